@@ -0,0 +1,316 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/AlecAivazis/survey/v2"
+	"github.com/dokulabs/doku-cli/internal/catalog"
+	"github.com/dokulabs/doku-cli/internal/config"
+	"github.com/dokulabs/doku-cli/internal/docker"
+	"github.com/dokulabs/doku-cli/internal/envfile"
+	"github.com/dokulabs/doku-cli/internal/manifest"
+	"github.com/dokulabs/doku-cli/internal/project"
+	"github.com/dokulabs/doku-cli/internal/service"
+	"github.com/dokulabs/doku-cli/internal/workspace"
+	"github.com/dokulabs/doku-cli/pkg/types"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+var (
+	applyFile   string
+	applyPrune  bool
+	applyDryRun bool
+	applyYes    bool
+)
+
+var applyCmd = &cobra.Command{
+	Use:   "apply",
+	Short: "Reconcile services and projects with a doku.yaml manifest",
+	Long: `Reconcile local services and projects with a declarative doku.yaml manifest.
+
+Services that are missing are installed, services whose version, ports or
+environment have drifted are updated, and missing projects are added and run.
+With --prune, instances and projects not declared in the manifest are removed
+(volumes are kept).
+
+A manifest with a workspace: key is applied to that workspace only, and
+selects it for every command run in its directory.
+
+Example doku.yaml:
+
+  workspace: work
+  services:
+    postgres:
+      version: "16"
+      env:
+        POSTGRES_PASSWORD: secret
+      ports: ["5432"]
+    cache:
+      service: redis
+      internal: true
+
+  projects:
+    api:
+      path: ./api
+      port: 3000
+      depends: [postgres]
+
+Examples:
+  doku apply                    # Apply ./doku.yaml
+  doku apply -f team.yaml       # Apply a specific manifest
+  doku apply --dry-run          # Show the plan without changing anything
+  doku apply --prune --yes      # Also remove undeclared services`,
+	Args: cobra.NoArgs,
+	RunE: runApply,
+}
+
+func init() {
+	rootCmd.AddCommand(applyCmd)
+
+	applyCmd.Flags().StringVarP(&applyFile, "file", "f", manifest.DefaultFileName, "Path to the manifest file")
+	applyCmd.Flags().BoolVar(&applyPrune, "prune", false, "Remove services and projects not declared in the manifest")
+	applyCmd.Flags().BoolVar(&applyDryRun, "dry-run", false, "Show the plan without applying it")
+	applyCmd.Flags().BoolVarP(&applyYes, "yes", "y", false, "Skip confirmation prompt")
+}
+
+func runApply(cmd *cobra.Command, args []string) error {
+	m, err := manifest.Load(applyFile)
+	if err != nil {
+		return err
+	}
+	if m.Workspace != "" && m.Workspace != workspace.DisplayName() {
+		return fmt.Errorf("%s is for workspace '%s', but '%s' is selected. Apply it with: doku --workspace %s apply -f %s",
+			applyFile, m.Workspace, workspace.DisplayName(), m.Workspace, applyFile)
+	}
+
+	// Initialize config manager
+	cfgMgr, err := initConfigManager()
+	if err != nil {
+		if err == types.ErrNotInitialized {
+			return nil
+		}
+		return err
+	}
+
+	cfg, err := cfgMgr.Get()
+	if err != nil {
+		return fmt.Errorf("failed to get config: %w", err)
+	}
+
+	envMgr := envfile.NewManager(cfgMgr.GetDokuDir())
+	loadEnv := func(name string) map[string]string {
+		if proj, exists := cfg.Projects[name]; exists {
+			return proj.Environment
+		}
+		if env, err := envMgr.Load(envMgr.GetServiceEnvPath(name, "")); err == nil {
+			return env
+		}
+		if instance, exists := cfg.Instances[name]; exists {
+			return instance.Environment
+		}
+		return nil
+	}
+
+	actions := manifest.BuildPlan(m, cfg, loadEnv, manifest.PlanOptions{Prune: applyPrune})
+
+	fmt.Println()
+	color.Cyan("Apply Plan (%s)", applyFile)
+	fmt.Println()
+
+	if len(actions) == 0 {
+		color.Green("✓ Everything is up to date")
+		return nil
+	}
+
+	for _, action := range actions {
+		fmt.Printf("  %s %-16s %s\n", formatApplyAction(action.Type), action.Name,
+			color.New(color.Faint).Sprint(action.Reason))
+	}
+	fmt.Println()
+
+	if applyDryRun {
+		color.Cyan("Dry run complete. No changes were made.")
+		return nil
+	}
+
+	// Confirmation
+	if !applyYes {
+		confirm := false
+		prompt := &survey.Confirm{
+			Message: fmt.Sprintf("Apply %d change(s)?", len(actions)),
+			Default: true,
+		}
+		if err := survey.AskOne(prompt, &confirm); err != nil {
+			return err
+		}
+
+		if !confirm {
+			color.Yellow("Apply cancelled")
+			return nil
+		}
+		fmt.Println()
+	}
+
+	// Initialize Docker client
+	dockerClient, err := initDockerClient()
+	if err != nil {
+		return err
+	}
+	defer dockerClient.Close()
+
+	catalogMgr := catalog.NewManager(cfgMgr.GetCatalogDir())
+	if !catalogMgr.CatalogExists() {
+		return fmt.Errorf("catalog not found. Run 'doku catalog update' first")
+	}
+
+	failed := 0
+	for _, action := range actions {
+		color.Cyan("→ %s %s", action.Type, action.Name)
+		if err := applyAction(action, m, dockerClient, cfgMgr, catalogMgr); err != nil {
+			color.Red("✗ %s %s: %v", action.Type, action.Name, err)
+			failed++
+			continue
+		}
+		color.Green("✓ %s %s", action.Type, action.Name)
+		fmt.Println()
+	}
+
+	fmt.Println()
+	if failed > 0 {
+		return fmt.Errorf("%d of %d change(s) failed", failed, len(actions))
+	}
+
+	color.Green("✓ Applied %d change(s)", len(actions))
+	return nil
+}
+
+// applyAction performs a single reconciliation step
+func applyAction(action manifest.Action, m *manifest.Manifest, dockerClient *docker.Client, cfgMgr *config.Manager, catalogMgr *catalog.Manager) error {
+	serviceMgr := getServiceManager(dockerClient, cfgMgr)
+
+	switch action.Type {
+	case manifest.ActionInstall, manifest.ActionReinstall:
+		entry := m.Services[action.Name]
+		ports, err := manifest.ParsePorts(entry.Ports)
+		if err != nil {
+			return err
+		}
+
+		installer, err := service.NewInstaller(dockerClient, cfgMgr, catalogMgr)
+		if err != nil {
+			return fmt.Errorf("failed to create installer: %w", err)
+		}
+
+		_, err = installer.Install(service.InstallOptions{
+			ServiceName:       entry.Service,
+			Version:           entry.Version,
+			InstanceName:      action.Name,
+			Environment:       entry.Env,
+			MemoryLimit:       entry.Memory,
+			CPULimit:          entry.CPU,
+			PortMappings:      ports,
+			Internal:          entry.Internal,
+			AutoInstallDeps:   true,
+			Replace:           action.Type == manifest.ActionReinstall,
+			ReuseExistingData: true,
+		})
+		return err
+
+	case manifest.ActionUpgrade:
+		entry := m.Services[action.Name]
+		spec, err := catalogMgr.GetServiceVersion(entry.Service, entry.Version)
+		if err != nil {
+			return fmt.Errorf("failed to get version spec: %w", err)
+		}
+		spec = spec.ForArch(dockerClient.Arch())
+
+		if err := dockerClient.ImagePull(spec.Image); err != nil {
+			return fmt.Errorf("failed to pull image: %w", err)
+		}
+
+		if err := serviceMgr.RecreateWithImage(action.Name, spec.Image); err != nil {
+			return err
+		}
+
+		instance, err := cfgMgr.GetInstance(action.Name)
+		if err != nil {
+			return err
+		}
+		instance.Version = entry.Version
+		return cfgMgr.UpdateInstance(action.Name, instance)
+
+	case manifest.ActionUpdateEnv:
+		entry := m.Services[action.Name]
+		envMgr := envfile.NewManager(cfgMgr.GetDokuDir())
+		if err := envfile.UpdateEnvFile(envMgr.GetServiceEnvPath(action.Name, ""), entry.Env); err != nil {
+			return fmt.Errorf("failed to update environment file: %w", err)
+		}
+		return serviceMgr.Recreate(action.Name)
+
+	case manifest.ActionRemove:
+		return serviceMgr.Remove(action.Name, true, false)
+
+	case manifest.ActionAddProject, manifest.ActionUpdateProject:
+		entry := m.Projects[action.Name]
+		projectMgr, err := project.NewManager(dockerClient, cfgMgr)
+		if err != nil {
+			return fmt.Errorf("failed to initialize project manager: %w", err)
+		}
+
+		// Keep existing variables that the manifest doesn't mention
+		env := make(map[string]string)
+		links := entry.Links
+		if existing, err := projectMgr.Get(action.Name); err == nil {
+			env = envfile.MergeEnv(existing.Environment)
+			if links == nil {
+				links = existing.Links
+			}
+		}
+		env = envfile.MergeEnv(env, entry.Env)
+
+		if _, err := projectMgr.Add(project.AddOptions{
+			ProjectPath:  m.ProjectPath(entry),
+			Name:         action.Name,
+			Dockerfile:   entry.Dockerfile,
+			Port:         entry.Port,
+			Ports:        entry.Ports,
+			Environment:  env,
+			Dependencies: entry.Depends,
+			Internal:     entry.Internal,
+			Links:        links,
+			Replace:      action.Type == manifest.ActionUpdateProject,
+		}); err != nil {
+			return err
+		}
+
+		return projectMgr.Run(project.RunOptions{
+			Name:        action.Name,
+			Build:       true,
+			InstallDeps: true,
+			Detach:      true,
+		})
+
+	case manifest.ActionRemoveProject:
+		projectMgr, err := project.NewManager(dockerClient, cfgMgr)
+		if err != nil {
+			return fmt.Errorf("failed to initialize project manager: %w", err)
+		}
+		return projectMgr.Remove(action.Name, false)
+
+	default:
+		return fmt.Errorf("unknown action: %s", action.Type)
+	}
+}
+
+// formatApplyAction returns a colored symbol and label for a plan action
+func formatApplyAction(actionType manifest.ActionType) string {
+	switch actionType {
+	case manifest.ActionInstall, manifest.ActionAddProject:
+		return color.GreenString("+ %-14s", actionType)
+	case manifest.ActionRemove, manifest.ActionRemoveProject:
+		return color.RedString("- %-14s", actionType)
+	default:
+		return color.YellowString("~ %-14s", actionType)
+	}
+}
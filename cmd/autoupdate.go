@@ -0,0 +1,399 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"sort"
+	"text/tabwriter"
+	"time"
+
+	"github.com/dokulabs/doku-cli/internal/service"
+	"github.com/dokulabs/doku-cli/pkg/types"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+var (
+	autoUpdateWindow   string
+	autoUpdateInterval time.Duration
+	autoUpdateNow      bool
+	autoUpdateWatch    bool
+)
+
+// autoUpdateWatchInterval is how often 'doku autoupdate run --watch' looks
+// for instances that are due
+const autoUpdateWatchInterval = 5 * time.Minute
+
+var autoUpdateCmd = &cobra.Command{
+	Use:   "autoupdate",
+	Short: "Keep selected services on the latest image of their tag",
+	Long: `Opt services in to automatic image updates.
+
+An auto-updated service's image tag is pulled again on each check. When the
+tag points to a new image, the container is recreated with it, keeping its
+volumes and environment, and the update is recorded. Use it for low-risk
+tools such as dozzle or adminer; 'doku rollback' restores the previous image.
+
+Checks happen when 'doku autoupdate run' runs. Schedule it with cron, or
+keep it running with --watch.
+
+Examples:
+  doku autoupdate enable dozzle adminer --window 02:00-05:00
+  doku autoupdate status
+  doku autoupdate run                 # Update services that are due
+  doku autoupdate run dozzle --now    # Check now, ignoring window and interval
+  doku autoupdate history dozzle
+  doku autoupdate disable adminer
+
+Cron (every 30 minutes):
+  */30 * * * * doku autoupdate run`,
+}
+
+var autoUpdateEnableCmd = &cobra.Command{
+	Use:   "enable <service>...",
+	Short: "Turn on automatic image updates for services",
+	Args:  cobra.MinimumNArgs(1),
+	RunE:  runAutoUpdateEnable,
+}
+
+var autoUpdateDisableCmd = &cobra.Command{
+	Use:   "disable <service>...",
+	Short: "Turn off automatic image updates for services",
+	Args:  cobra.MinimumNArgs(1),
+	RunE:  runAutoUpdateDisable,
+}
+
+var autoUpdateStatusCmd = &cobra.Command{
+	Use:     "status",
+	Short:   "Show services with automatic image updates",
+	Aliases: []string{"list", "ls"},
+	Args:    cobra.NoArgs,
+	RunE:    runAutoUpdateStatus,
+}
+
+var autoUpdateRunCmd = &cobra.Command{
+	Use:   "run [service]...",
+	Short: "Check auto-updated services and apply new images",
+	Long: `Check the auto-updated services that are due, or the given ones, and
+recreate those whose image tag points to a new image.
+
+A service is due when its interval has passed since the last check and the
+current time is in its window. --now checks regardless.`,
+	RunE: runAutoUpdateRun,
+}
+
+var autoUpdateHistoryCmd = &cobra.Command{
+	Use:   "history [service]",
+	Short: "Show the image updates that were applied",
+	Args:  cobra.MaximumNArgs(1),
+	RunE:  runAutoUpdateHistory,
+}
+
+func init() {
+	rootCmd.AddCommand(autoUpdateCmd)
+
+	autoUpdateCmd.AddCommand(autoUpdateEnableCmd)
+	autoUpdateCmd.AddCommand(autoUpdateDisableCmd)
+	autoUpdateCmd.AddCommand(autoUpdateStatusCmd)
+	autoUpdateCmd.AddCommand(autoUpdateRunCmd)
+	autoUpdateCmd.AddCommand(autoUpdateHistoryCmd)
+
+	autoUpdateEnableCmd.Flags().StringVar(&autoUpdateWindow, "window", "", "Daily time window for updates, e.g. 02:00-05:00 (default: any time)")
+	autoUpdateEnableCmd.Flags().DurationVar(&autoUpdateInterval, "interval", service.DefaultAutoUpdateInterval, "Minimum time between checks")
+	autoUpdateRunCmd.Flags().BoolVar(&autoUpdateNow, "now", false, "Check now, ignoring windows and intervals")
+	autoUpdateRunCmd.Flags().BoolVar(&autoUpdateWatch, "watch", false, "Keep running and check every few minutes")
+}
+
+func runAutoUpdateEnable(cmd *cobra.Command, args []string) error {
+	if autoUpdateWindow != "" {
+		if _, _, err := service.ParseWindow(autoUpdateWindow); err != nil {
+			return err
+		}
+	}
+	if autoUpdateInterval <= 0 {
+		return fmt.Errorf("--interval must be positive")
+	}
+
+	cfgMgr, err := initConfigManager()
+	if err != nil {
+		if err == types.ErrNotInitialized {
+			return nil
+		}
+		return err
+	}
+
+	names, err := cfgMgr.ResolveNames(args)
+	if err != nil {
+		return err
+	}
+
+	for _, name := range names {
+		instance, err := cfgMgr.GetInstance(name)
+		if err != nil {
+			return fmt.Errorf("service '%s' not found", name)
+		}
+		if instance.IsMultiContainer || instance.ServiceType == "custom-project" {
+			return fmt.Errorf("auto-update is only supported for single-container catalog services; '%s' is not one", name)
+		}
+
+		policy := instance.AutoUpdate
+		if policy == nil {
+			policy = &types.AutoUpdateConfig{}
+		}
+		policy.Window = autoUpdateWindow
+		policy.Interval = autoUpdateInterval
+		instance.AutoUpdate = policy
+
+		if err := cfgMgr.UpdateInstance(name, instance); err != nil {
+			return err
+		}
+		color.Green("✓ Auto-update enabled for %s (%s)", name, describeAutoUpdate(policy))
+	}
+
+	fmt.Println()
+	fmt.Println("Updates are applied when 'doku autoupdate run' runs. Schedule it with cron:")
+	color.New(color.Faint).Println("  */30 * * * * doku autoupdate run")
+	return nil
+}
+
+func runAutoUpdateDisable(cmd *cobra.Command, args []string) error {
+	cfgMgr, err := initConfigManager()
+	if err != nil {
+		if err == types.ErrNotInitialized {
+			return nil
+		}
+		return err
+	}
+
+	names, err := cfgMgr.ResolveNames(args)
+	if err != nil {
+		return err
+	}
+
+	for _, name := range names {
+		instance, err := cfgMgr.GetInstance(name)
+		if err != nil {
+			return fmt.Errorf("service '%s' not found", name)
+		}
+		if instance.AutoUpdate == nil {
+			color.Yellow("⚠️  Auto-update is not enabled for %s", name)
+			continue
+		}
+
+		instance.AutoUpdate = nil
+		if err := cfgMgr.UpdateInstance(name, instance); err != nil {
+			return err
+		}
+		color.Green("✓ Auto-update disabled for %s", name)
+	}
+	return nil
+}
+
+func runAutoUpdateStatus(cmd *cobra.Command, args []string) error {
+	cfgMgr, err := initConfigManager()
+	if err != nil {
+		if err == types.ErrNotInitialized {
+			return nil
+		}
+		return err
+	}
+
+	instances := autoUpdatedInstances(cfgMgr.ListInstances)
+	if len(instances) == 0 {
+		color.Yellow("No services have auto-update enabled")
+		fmt.Println()
+		fmt.Println("Enable it with: doku autoupdate enable <service>")
+		return nil
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
+	fmt.Fprintln(w, "SERVICE\tPOLICY\tLAST CHECK\tLAST UPDATE")
+	for _, instance := range instances {
+		policy := instance.AutoUpdate
+		lastCheck, lastUpdate := "never", "never"
+		if !policy.LastCheck.IsZero() {
+			lastCheck = formatTime(policy.LastCheck)
+		}
+		if n := len(policy.History); n > 0 {
+			lastUpdate = formatTime(policy.History[n-1].UpdatedAt)
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", instance.Name, describeAutoUpdate(policy), lastCheck, lastUpdate)
+	}
+	w.Flush()
+
+	return nil
+}
+
+func runAutoUpdateRun(cmd *cobra.Command, args []string) error {
+	cfgMgr, err := initConfigManager()
+	if err != nil {
+		if err == types.ErrNotInitialized {
+			return nil
+		}
+		return err
+	}
+
+	dockerClient, err := initDockerClient()
+	if err != nil {
+		return err
+	}
+	defer dockerClient.Close()
+
+	serviceMgr := getServiceManager(dockerClient, cfgMgr)
+
+	names, err := cfgMgr.ResolveNames(args)
+	if err != nil {
+		return err
+	}
+
+	for {
+		// Reload, as other doku commands may have changed the config since
+		if _, err := cfgMgr.Load(); err != nil {
+			if !autoUpdateWatch {
+				return err
+			}
+			color.Red("✗ %v", err)
+			time.Sleep(autoUpdateWatchInterval)
+			continue
+		}
+
+		var targets []*types.Instance
+		now := time.Now()
+		for _, instance := range autoUpdatedInstances(cfgMgr.ListInstances) {
+			if len(names) > 0 && !slices.Contains(names, instance.Name) {
+				continue
+			}
+			if autoUpdateNow || service.AutoUpdateDue(instance, now) {
+				targets = append(targets, instance)
+			}
+		}
+		for _, name := range names {
+			instance, err := cfgMgr.GetInstance(name)
+			if err == nil && instance.AutoUpdate == nil {
+				color.Yellow("⚠️  Auto-update is not enabled for %s", name)
+			}
+		}
+
+		for _, instance := range targets {
+			record, err := serviceMgr.AutoUpdate(instance.Name)
+			switch {
+			case err != nil:
+				color.Red("✗ %s: %v", instance.Name, err)
+			case record == nil:
+				fmt.Printf("%s is up to date\n", instance.Name)
+			default:
+				color.Green("✓ Updated %s: %s → %s", instance.Name, shortImageID(record.OldImageID), shortImageID(record.NewImageID))
+			}
+		}
+
+		if !autoUpdateWatch {
+			if len(targets) == 0 && !autoUpdateNow {
+				fmt.Println("No services are due for an update check")
+			}
+			return nil
+		}
+		autoUpdateNow = false
+		time.Sleep(autoUpdateWatchInterval)
+	}
+}
+
+func runAutoUpdateHistory(cmd *cobra.Command, args []string) error {
+	cfgMgr, err := initConfigManager()
+	if err != nil {
+		if err == types.ErrNotInitialized {
+			return nil
+		}
+		return err
+	}
+
+	type entry struct {
+		instance string
+		record   types.AutoUpdateRecord
+	}
+	var entries []entry
+
+	instances := autoUpdatedInstances(cfgMgr.ListInstances)
+	if len(args) == 1 {
+		instance, err := cfgMgr.GetInstance(args[0])
+		if err != nil {
+			return fmt.Errorf("service '%s' not found", args[0])
+		}
+		instances = []*types.Instance{instance}
+	}
+	for _, instance := range instances {
+		if instance.AutoUpdate == nil {
+			continue
+		}
+		for _, record := range instance.AutoUpdate.History {
+			entries = append(entries, entry{instance.Name, record})
+		}
+	}
+
+	if len(entries) == 0 {
+		color.Yellow("No automatic updates recorded")
+		return nil
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].record.UpdatedAt.After(entries[j].record.UpdatedAt)
+	})
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
+	fmt.Fprintln(w, "WHEN\tSERVICE\tIMAGE\tCHANGE")
+	for _, e := range entries {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s → %s\n",
+			e.record.UpdatedAt.Format("2006-01-02 15:04"),
+			e.instance,
+			e.record.Image,
+			shortImageID(e.record.OldImageID),
+			shortImageID(e.record.NewImageID))
+	}
+	w.Flush()
+
+	return nil
+}
+
+// autoUpdatedInstances returns the instances with auto-update enabled,
+// sorted by name
+func autoUpdatedInstances(list func() ([]*types.Instance, error)) []*types.Instance {
+	all, err := list()
+	if err != nil {
+		return nil
+	}
+
+	var instances []*types.Instance
+	for _, instance := range all {
+		if instance.AutoUpdate != nil {
+			instances = append(instances, instance)
+		}
+	}
+	sort.Slice(instances, func(i, j int) bool {
+		return instances[i].Name < instances[j].Name
+	})
+	return instances
+}
+
+// describeAutoUpdate summarizes a policy, e.g. "every 24h, 02:00-05:00"
+func describeAutoUpdate(policy *types.AutoUpdateConfig) string {
+	interval := policy.Interval
+	if interval <= 0 {
+		interval = service.DefaultAutoUpdateInterval
+	}
+	window := "any time"
+	if policy.Window != "" {
+		window = policy.Window
+	}
+	return fmt.Sprintf("every %s, %s", interval, window)
+}
+
+// shortImageID returns the first 12 characters of an image ID's digest
+func shortImageID(id string) string {
+	if len(id) > 7 && id[:7] == "sha256:" {
+		id = id[7:]
+	}
+	if len(id) > 12 {
+		return id[:12]
+	}
+	return id
+}
@@ -0,0 +1,242 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/dokulabs/doku-cli/internal/config"
+	"github.com/dokulabs/doku-cli/internal/project"
+	"github.com/dokulabs/doku-cli/internal/service"
+	"github.com/dokulabs/doku-cli/pkg/types"
+	"github.com/fatih/color"
+)
+
+// bulkAction describes a start, stop or restart applied to several services
+type bulkAction struct {
+	verb    string // e.g. "Starting"
+	done    string // e.g. "Started"
+	reverse bool   // Handle dependents before their dependencies
+
+	// selects reports whether an instance (with refreshed status) needs the action
+	selects func(instance *types.Instance) bool
+
+	service func(serviceMgr *service.Manager, name string) error
+	project func(projectMgr *project.Manager, name string) error
+}
+
+var (
+	bulkStart = bulkAction{
+		verb: "Starting",
+		done: "Started",
+		selects: func(instance *types.Instance) bool {
+			return instance.Status != types.StatusRunning
+		},
+		service: (*service.Manager).Start,
+		project: (*project.Manager).Start,
+	}
+
+	bulkStop = bulkAction{
+		verb:    "Stopping",
+		done:    "Stopped",
+		reverse: true,
+		selects: func(instance *types.Instance) bool {
+			return instance.Status == types.StatusRunning
+		},
+		service: (*service.Manager).Stop,
+		project: (*project.Manager).Stop,
+	}
+
+	bulkRestart = bulkAction{
+		verb: "Restarting",
+		done: "Restarted",
+		selects: func(instance *types.Instance) bool {
+			return instance.Status == types.StatusRunning
+		},
+		service: (*service.Manager).Restart,
+		project: (*project.Manager).Restart,
+	}
+)
+
+// bulkTarget selects the services a bulk action applies to. The zero value
+// selects all services.
+type bulkTarget struct {
+	serviceType string // --service <type>
+	group       string // @group argument
+}
+
+// describe names the selection for messages, e.g. "@backend services"
+func (t bulkTarget) describe() string {
+	switch {
+	case t.group != "":
+		return fmt.Sprintf("services in %s%s", config.GroupPrefix, t.group)
+	case t.serviceType != "":
+		return t.serviceType + " services"
+	default:
+		return "services"
+	}
+}
+
+// runBulkAction applies an action to the selected services concurrently and
+// in dependency order, then prints a summary
+func runBulkAction(action bulkAction, target bulkTarget) error {
+	cfgMgr, err := initConfigManager()
+	if err != nil {
+		if err == types.ErrNotInitialized {
+			return nil
+		}
+		return err
+	}
+
+	dockerClient, err := initDockerClient()
+	if err != nil {
+		return err
+	}
+	defer dockerClient.Close()
+
+	serviceMgr := getServiceManager(dockerClient, cfgMgr)
+	projectMgr, err := project.NewManager(dockerClient, cfgMgr)
+	if err != nil {
+		return fmt.Errorf("failed to initialize project manager: %w", err)
+	}
+
+	instances, err := serviceMgr.List()
+	if err != nil {
+		return fmt.Errorf("failed to list instances: %w", err)
+	}
+
+	var members map[string]bool
+	if target.group != "" {
+		names, err := cfgMgr.GetGroup(target.group)
+		if err != nil {
+			return err
+		}
+		installed := make(map[string]bool, len(instances))
+		for _, instance := range instances {
+			installed[instance.Name] = true
+		}
+		members = make(map[string]bool, len(names))
+		for _, name := range names {
+			members[name] = true
+			if !installed[name] {
+				color.Yellow("⚠️  %s is in %s%s but not installed", name, config.GroupPrefix, target.group)
+			}
+		}
+	}
+
+	// Refresh statuses from Docker so the selection matches reality
+	ctx := context.Background()
+	var wg sync.WaitGroup
+	for _, instance := range instances {
+		wg.Add(1)
+		go func(inst *types.Instance) {
+			defer wg.Done()
+			updateInstanceStatus(ctx, dockerClient, inst)
+		}(instance)
+	}
+	wg.Wait()
+
+	var names []string
+	projects := make(map[string]bool)
+	deps := make(map[string][]string)
+	for _, instance := range instances {
+		if target.serviceType != "" && instance.ServiceType != target.serviceType {
+			continue
+		}
+		if members != nil && !members[instance.Name] {
+			continue
+		}
+		if !action.selects(instance) {
+			continue
+		}
+		names = append(names, instance.Name)
+		deps[instance.Name] = instance.Dependencies
+		if instance.ServiceType == "custom-project" {
+			projects[instance.Name] = true
+		}
+	}
+	sort.Strings(names)
+
+	if len(names) == 0 {
+		color.Yellow("No %s need %s", target.describe(), lowerFirst(action.verb))
+		return nil
+	}
+
+	fmt.Printf("%s %d service(s)...\n", action.verb, len(names))
+	fmt.Println()
+
+	results := service.RunBulk(names, service.BulkOptions{
+		Dependencies: deps,
+		Reverse:      action.reverse,
+	}, func(name string) error {
+		if projects[name] {
+			return action.project(projectMgr, name)
+		}
+		return action.service(serviceMgr, name)
+	})
+
+	var succeeded, skipped []string
+	var failed []service.BulkResult
+	for _, result := range results {
+		switch {
+		case result.Err == nil:
+			succeeded = append(succeeded, result.Name)
+		case errors.Is(result.Err, types.ErrAlreadyRunning), errors.Is(result.Err, types.ErrAlreadyStopped):
+			skipped = append(skipped, result.Name)
+		default:
+			failed = append(failed, result)
+		}
+	}
+
+	fmt.Println()
+	if len(succeeded) > 0 {
+		color.Green("✓ %s %d service(s)", action.done, len(succeeded))
+		for _, name := range succeeded {
+			fmt.Printf("  • %s\n", name)
+		}
+	}
+	if len(skipped) > 0 {
+		color.Yellow("⚠️  Skipped %d service(s) already in the desired state", len(skipped))
+		for _, name := range skipped {
+			fmt.Printf("  • %s\n", name)
+		}
+	}
+	if len(failed) > 0 {
+		color.Red("✗ Failed %d service(s)", len(failed))
+		for _, result := range failed {
+			fmt.Printf("  • %s: %v\n", result.Name, result.Err)
+		}
+		return fmt.Errorf("%d of %d service(s) failed", len(failed), len(names))
+	}
+
+	return nil
+}
+
+// lowerFirst lowercases the first letter of an ASCII word
+func lowerFirst(s string) string {
+	if s == "" || s[0] < 'A' || s[0] > 'Z' {
+		return s
+	}
+	return string(s[0]+'a'-'A') + s[1:]
+}
+
+// parseBulkArgs returns the bulk target of a start, stop or restart, or nil
+// when it applies to the single service named in args
+func parseBulkArgs(args []string, all bool, serviceType string) (*bulkTarget, error) {
+	flags := all || serviceType != ""
+	if flags && len(args) > 0 {
+		return nil, fmt.Errorf("cannot combine a service name or group with --all or --service")
+	}
+	if flags {
+		return &bulkTarget{serviceType: serviceType}, nil
+	}
+	if len(args) == 0 {
+		return nil, fmt.Errorf("specify a service name, @group, --all or --service <type>")
+	}
+	if group, ok := config.ParseGroupRef(args[0]); ok {
+		return &bulkTarget{group: group}, nil
+	}
+	return nil, nil
+}
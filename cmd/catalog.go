@@ -15,10 +15,14 @@ import (
 )
 
 var (
-	catalogCategory string
-	catalogSearch   string
-	catalogVerbose  bool
-	catalogSource   string // URL, branch, or tag for catalog update
+	catalogCategory  string
+	catalogSearch    string
+	catalogVerbose   bool
+	catalogTag       string
+	catalogInstalled bool
+	catalogSource    string // URL, branch, or tag for catalog update
+	catalogStrict    bool   // Fail validation on warnings too
+	catalogUnpin     bool   // Update a pinned catalog
 )
 
 var catalogCmd = &cobra.Command{
@@ -36,11 +40,19 @@ var catalogListCmd = &cobra.Command{
 }
 
 var catalogSearchCmd = &cobra.Command{
-	Use:   "search <query>",
+	Use:   "search [query]",
 	Short: "Search for services",
-	Long:  `Search for services by name, description, or tags`,
-	Args:  cobra.MinimumNArgs(1),
-	RunE:  runCatalogSearch,
+	Long: `Search for services by name, tags, category, or description.
+
+Results are ordered by relevance: name matches first, then tag matches,
+then category and description matches. Every word of the query must match.
+
+Examples:
+  doku catalog search postgres              # Search by name
+  doku catalog search queue --tag messaging # Combine query and tag filter
+  doku catalog search -c database           # All databases
+  doku catalog search --installed           # Catalog services you have installed`,
+	RunE: runCatalogSearch,
 }
 
 var catalogUpdateCmd = &cobra.Command{
@@ -61,7 +73,12 @@ By default, downloads from the main branch. You can specify a different source:
 
 You can also use the DOKU_CATALOG_SOURCE environment variable:
   export DOKU_CATALOG_SOURCE=develop
-  doku catalog update`,
+  doku catalog update
+
+Catalog sources registered with 'doku catalog add-source' are updated too.
+
+A catalog pinned with 'doku catalog pin' isn't updated, unless --unpin is
+passed to remove the pin.`,
 	RunE: runCatalogUpdate,
 }
 
@@ -73,6 +90,28 @@ var catalogShowCmd = &cobra.Command{
 	RunE:  runCatalogShow,
 }
 
+var catalogValidateCmd = &cobra.Command{
+	Use:   "validate [catalog-dir]",
+	Short: "Check a catalog for mistakes",
+	Long: `Check a hierarchical catalog directory (catalog.yaml, services/...) and
+report problems by file and line, for catalog authors.
+
+Besides files that don't parse and missing fields, it finds host ports
+mapped twice within a version, multi-container services without a clear
+primary container, invalid memory and CPU strings, healthcheck durations
+that don't parse, dependencies that aren't in the catalog and fields that
+are ignored because they are misspelled.
+
+Without a directory, the local catalog (~/.doku/catalog) is checked.
+
+Examples:
+  doku catalog validate                  # Check the local catalog
+  doku catalog validate ./doku-catalog   # Check a catalog checkout
+  doku catalog validate . --strict       # Fail on warnings too, e.g. in CI`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runCatalogValidate,
+}
+
 func init() {
 	rootCmd.AddCommand(catalogCmd)
 
@@ -81,16 +120,27 @@ func init() {
 	catalogCmd.AddCommand(catalogSearchCmd)
 	catalogCmd.AddCommand(catalogUpdateCmd)
 	catalogCmd.AddCommand(catalogShowCmd)
+	catalogCmd.AddCommand(catalogValidateCmd)
 
 	// Flags for list command
 	catalogListCmd.Flags().StringVarP(&catalogCategory, "category", "c", "", "Filter by category")
 	catalogListCmd.Flags().BoolVarP(&catalogVerbose, "verbose", "v", false, "Show detailed information")
 
+	// Flags for search command
+	catalogSearchCmd.Flags().StringVarP(&catalogCategory, "category", "c", "", "Filter by category")
+	catalogSearchCmd.Flags().StringVarP(&catalogTag, "tag", "t", "", "Filter by tag")
+	catalogSearchCmd.Flags().BoolVar(&catalogInstalled, "installed", false, "Only show services with installed instances")
+	catalogSearchCmd.Flags().BoolVarP(&catalogVerbose, "verbose", "v", false, "Show detailed information")
+
 	// Flags for show command
 	catalogShowCmd.Flags().BoolVarP(&catalogVerbose, "verbose", "v", false, "Show all versions")
 
+	// Flags for validate command
+	catalogValidateCmd.Flags().BoolVar(&catalogStrict, "strict", false, "Treat warnings as errors")
+
 	// Flags for update command
 	catalogUpdateCmd.Flags().StringVarP(&catalogSource, "source", "s", "", "Catalog source (branch name, tag name, or full URL)")
+	catalogUpdateCmd.Flags().BoolVar(&catalogUnpin, "unpin", false, "Remove the pin set with 'doku catalog pin' and update")
 }
 
 func runCatalogList(cmd *cobra.Command, args []string) error {
@@ -152,6 +202,9 @@ func runCatalogList(cmd *cobra.Command, args []string) error {
 
 func runCatalogSearch(cmd *cobra.Command, args []string) error {
 	query := strings.Join(args, " ")
+	if query == "" && catalogCategory == "" && catalogTag == "" && !catalogInstalled {
+		return fmt.Errorf("provide a search query or a filter (--category, --tag, --installed)")
+	}
 
 	// Get config manager
 	cfgMgr, err := config.New()
@@ -169,12 +222,39 @@ func runCatalogSearch(cmd *cobra.Command, args []string) error {
 	}
 
 	// Search services
-	services, err := catalogMgr.SearchServices(query)
+	services, err := catalogMgr.SearchServices(query, catalog.SearchOptions{
+		Category: catalogCategory,
+		Tag:      catalogTag,
+	})
 	if err != nil {
 		return fmt.Errorf("failed to search services: %w", err)
 	}
 
-	color.Cyan("Search results for '%s':\n", query)
+	// Cross-reference with installed instances
+	installed := make(map[string][]string)
+	if cfgMgr.IsInitialized() {
+		if cfg, err := cfgMgr.Get(); err == nil {
+			for name, instance := range cfg.Instances {
+				installed[instance.ServiceType] = append(installed[instance.ServiceType], name)
+			}
+		}
+	}
+
+	if catalogInstalled {
+		filtered := make([]*types.CatalogService, 0, len(services))
+		for _, service := range services {
+			if len(installed[service.Name]) > 0 {
+				filtered = append(filtered, service)
+			}
+		}
+		services = filtered
+	}
+
+	if query != "" {
+		color.Cyan("Search results for '%s':\n", query)
+	} else {
+		color.Cyan("Search results:\n")
+	}
 
 	if len(services) == 0 {
 		fmt.Println("No services found.")
@@ -183,7 +263,11 @@ func runCatalogSearch(cmd *cobra.Command, args []string) error {
 
 	// Display services
 	for _, service := range services {
-		displayService(service, false)
+		displayService(service, catalogVerbose)
+		if names := installed[service.Name]; len(names) > 0 {
+			sort.Strings(names)
+			fmt.Printf("  %s %s\n", color.GreenString("Installed:"), strings.Join(names, ", "))
+		}
 	}
 
 	fmt.Printf("\nFound: %d service(s)\n", len(services))
@@ -197,6 +281,29 @@ func runCatalogUpdate(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to create config manager: %w", err)
 	}
 
+	// A pinned catalog stays at its tag, so that installs are the same on
+	// every machine of the team
+	if cfgMgr.IsInitialized() {
+		cfg, err := cfgMgr.Get()
+		if err != nil {
+			return fmt.Errorf("failed to get config: %w", err)
+		}
+		if pin := cfg.Preferences.CatalogPin; pin != "" {
+			if !catalogUnpin {
+				color.Yellow("Catalog is pinned to %s: not updating", pin)
+				fmt.Println("Update it anyway, removing the pin, with: doku catalog update --unpin")
+				return nil
+			}
+			if err := cfgMgr.Update(func(c *types.Config) error {
+				c.Preferences.CatalogPin = ""
+				return nil
+			}); err != nil {
+				return fmt.Errorf("failed to remove the catalog pin: %w", err)
+			}
+			color.Green("✓ Catalog unpinned from %s", pin)
+		}
+	}
+
 	// Create catalog manager
 	catalogMgr := catalog.NewManager(cfgMgr.GetCatalogDir())
 
@@ -217,6 +324,12 @@ func runCatalogUpdate(cmd *cobra.Command, args []string) error {
 	// Check if local catalog exists
 	hasLocalCatalog := catalogMgr.CatalogExists()
 
+	// Keep the current catalog to show what the update changes
+	var previous *types.ServiceCatalog
+	if hasLocalCatalog {
+		previous, _ = catalogMgr.LoadCatalog()
+	}
+
 	fmt.Println("Updating service catalog...")
 
 	// Fetch catalog
@@ -247,6 +360,8 @@ func runCatalogUpdate(cmd *cobra.Command, args []string) error {
 			color.New(color.Faint).Println("   • Install services: doku install <service>")
 			color.New(color.Faint).Println("   • The catalog will auto-update once GitHub releases are published")
 
+			updateCatalogSources(catalogMgr)
+			showCatalogChanges(cfgMgr, catalogMgr, previous)
 			return nil
 		}
 
@@ -257,6 +372,7 @@ func runCatalogUpdate(cmd *cobra.Command, args []string) error {
 	// Validate catalog
 	if err := catalogMgr.ValidateCatalog(); err != nil {
 		color.Red("⚠️  Catalog validation failed: %v", err)
+		fmt.Println("   Run 'doku catalog validate' for details")
 		return nil
 	}
 
@@ -280,9 +396,110 @@ func runCatalogUpdate(cmd *cobra.Command, args []string) error {
 	services, _ := catalogMgr.ListServices()
 	fmt.Printf("  Services: %d\n", len(services))
 
+	updateCatalogSources(catalogMgr)
+	showCatalogChanges(cfgMgr, catalogMgr, previous)
 	return nil
 }
 
+// showCatalogChanges prints what changed since the catalog before the
+// update, if there was one
+func showCatalogChanges(cfgMgr *config.Manager, catalogMgr *catalog.Manager, previous *types.ServiceCatalog) {
+	if previous == nil {
+		return
+	}
+	if current, err := catalogMgr.LoadCatalog(); err == nil {
+		printCatalogDiff(cfgMgr, catalogMgr, catalog.DiffCatalogs(previous, current))
+	}
+}
+
+// printCatalogDiff shows what an update changed, and warns about installed
+// instances whose version was removed or deprecated
+func printCatalogDiff(cfgMgr *config.Manager, catalogMgr *catalog.Manager, diff *catalog.Diff) {
+	fmt.Println()
+	if diff.Empty() {
+		fmt.Println("No changes since the previous catalog")
+		return
+	}
+
+	fmt.Println("Changes since the previous catalog:")
+	for _, name := range diff.AddedServices {
+		fmt.Printf("  %s %s (new service)\n", color.GreenString("+"), name)
+	}
+	for _, name := range diff.RemovedServices {
+		fmt.Printf("  %s %s (removed)\n", color.RedString("-"), name)
+	}
+	for _, name := range mapKeys(diff.AddedVersions) {
+		fmt.Printf("  %s %s %s\n", color.GreenString("+"), name, strings.Join(diff.AddedVersions[name], ", "))
+	}
+	for _, name := range mapKeys(diff.RemovedVersions) {
+		fmt.Printf("  %s %s %s\n", color.RedString("-"), name, strings.Join(diff.RemovedVersions[name], ", "))
+	}
+	for _, change := range diff.ChangedImages {
+		where := change.Service + " " + change.Version
+		if change.Container != "" {
+			where += " (" + change.Container + ")"
+		}
+		fmt.Printf("  %s %s: %s → %s\n", color.YellowString("~"), where, change.From, change.To)
+	}
+	for _, dep := range diff.Deprecated {
+		fmt.Printf("  %s %s %s deprecated: %s\n", color.YellowString("!"), dep.Service, dep.Version, dep.Reason)
+	}
+
+	if !cfgMgr.IsInitialized() {
+		return
+	}
+	instances, err := cfgMgr.ListInstances()
+	if err != nil {
+		return
+	}
+	sort.Slice(instances, func(i, j int) bool { return instances[i].Name < instances[j].Name })
+
+	var warned bool
+	warn := func(format string, args ...interface{}) {
+		if !warned {
+			fmt.Println()
+			warned = true
+		}
+		color.Yellow("⚠️  "+format, args...)
+	}
+	for _, instance := range instances {
+		if instance.ServiceType == "custom-project" {
+			continue
+		}
+		name, err := catalogMgr.ResolveName(instance.ServiceType)
+		if err != nil {
+			continue
+		}
+		if diff.VersionRemoved(name, instance.Version) {
+			warn("%s runs %s %s, which was removed from the catalog: it can't be reinstalled. Upgrade it with: doku service upgrade %s",
+				instance.Name, instance.ServiceType, instance.Version, instance.Name)
+			continue
+		}
+		for _, dep := range diff.Deprecated {
+			if dep.Service == name && dep.Version == instance.Version {
+				warn("%s runs %s %s, which is now deprecated: %s", instance.Name, instance.ServiceType, instance.Version, dep.Reason)
+			}
+		}
+	}
+}
+
+// updateCatalogSources downloads the catalogs of the registered sources
+// again. A source that fails keeps its previous catalog.
+func updateCatalogSources(catalogMgr *catalog.Manager) {
+	sources, err := catalogMgr.ListSources()
+	if err != nil {
+		color.Yellow("⚠️  %v", err)
+		return
+	}
+	for _, source := range sources {
+		if err := catalogMgr.FetchSource(source.Name); err != nil {
+			color.Yellow("⚠️  Could not update catalog source %s: %v", source.Name, err)
+			continue
+		}
+		color.Green("✓ Catalog source %s updated", source.Name)
+	}
+}
+
 func runCatalogShow(cmd *cobra.Command, args []string) error {
 	serviceName := args[0]
 
@@ -456,6 +673,9 @@ func displayServiceDetails(service *types.CatalogService, showAllVersions bool)
 		for _, version := range versions {
 			spec := service.Versions[version]
 			fmt.Printf("\n  %s\n", color.CyanString(version))
+			if spec.Deprecated != "" {
+				fmt.Printf("    %s\n", color.YellowString("Deprecated: %s", spec.Deprecated))
+			}
 			fmt.Printf("    Image: %s\n", spec.Image)
 			if spec.Description != "" {
 				fmt.Printf("    Description: %s\n", spec.Description)
@@ -500,3 +720,44 @@ func buildCatalogURL(source string) string {
 	// Otherwise, treat as a branch name
 	return fmt.Sprintf("https://github.com/dokulabs/doku-catalog/archive/refs/heads/%s.tar.gz", source)
 }
+
+func runCatalogValidate(cmd *cobra.Command, args []string) error {
+	var dir string
+	if len(args) > 0 {
+		dir = args[0]
+	} else {
+		cfgMgr, err := config.New()
+		if err != nil {
+			return fmt.Errorf("failed to create config manager: %w", err)
+		}
+		dir = cfgMgr.GetCatalogDir()
+	}
+
+	issues, err := catalog.Validate(dir)
+	if err != nil {
+		return err
+	}
+
+	errorCount, warningCount := 0, 0
+	for _, issue := range issues {
+		if issue.Warning {
+			warningCount++
+			fmt.Printf("%s %s\n", color.YellowString("warning:"), issue)
+		} else {
+			errorCount++
+			fmt.Printf("%s %s\n", color.RedString("error:"), issue)
+		}
+	}
+
+	if len(issues) == 0 {
+		color.Green("✓ Catalog is valid: %s", dir)
+		return nil
+	}
+
+	fmt.Println()
+	fmt.Printf("%d error(s), %d warning(s)\n", errorCount, warningCount)
+	if errorCount > 0 || (catalogStrict && warningCount > 0) {
+		return fmt.Errorf("catalog validation failed")
+	}
+	return nil
+}
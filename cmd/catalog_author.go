@@ -0,0 +1,154 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/dokulabs/doku-cli/internal/catalog"
+	"github.com/dokulabs/doku-cli/internal/config"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+var (
+	catalogInitCategory string
+	catalogInitImage    string
+	catalogInitVersion  string
+	catalogInitPort     int
+	catalogInitCatalog  string
+	catalogInitForce    bool
+)
+
+var catalogInitCmd = &cobra.Command{
+	Use:   "init <name>",
+	Short: "Generate the skeleton of a new service",
+	Long: `Generate the skeleton of a service definition, with a version, its ports,
+volumes, environment, configuration options, resources and healthcheck, to
+fill in.
+
+By default it writes <name>.yaml in the current directory, a single file
+to add with 'doku catalog add'. With --catalog, it writes the service into a
+checkout of a catalog such as doku-catalog, under
+services/<category>/<name>/, to contribute it.
+
+Check it with 'doku catalog lint' as you edit it.
+
+Examples:
+  doku catalog init internal-api --image registry.example.com/api:1.4 --port 8080
+  doku catalog init meilisearch --category search --catalog ~/src/doku-catalog`,
+	Args: cobra.ExactArgs(1),
+	RunE: runCatalogInit,
+}
+
+var catalogLintCmd = &cobra.Command{
+	Use:   "lint <path>",
+	Short: "Check a service definition or catalog for mistakes",
+	Long: `Check a service definition file (see 'doku catalog add') or a catalog
+directory: everything 'doku catalog validate' checks, and also that the
+files follow the schema: ports in range, known protocols, configuration
+options whose type, choices, pattern and default agree, container paths for
+volumes, pinned image tags, and the description and category shown by
+'doku catalog'.
+
+Issues are reported by file and line. The command fails on errors, and with
+--strict on warnings too, to use it in CI.
+
+Examples:
+  doku catalog lint ./internal-api.yaml
+  doku catalog lint ~/src/doku-catalog --strict`,
+	Args: cobra.ExactArgs(1),
+	RunE: runCatalogLint,
+}
+
+func init() {
+	catalogCmd.AddCommand(catalogInitCmd)
+	catalogCmd.AddCommand(catalogLintCmd)
+
+	catalogInitCmd.Flags().StringVarP(&catalogInitCategory, "category", "c", "other", "Category of the service")
+	catalogInitCmd.Flags().StringVar(&catalogInitImage, "image", "", "Image of the first version (default example/<name>:<version>)")
+	catalogInitCmd.Flags().StringVar(&catalogInitVersion, "version", "1.0", "First version")
+	catalogInitCmd.Flags().IntVarP(&catalogInitPort, "port", "p", 8080, "Port of the service")
+	catalogInitCmd.Flags().StringVar(&catalogInitCatalog, "catalog", "", "Write into this catalog checkout instead of a single file")
+	catalogInitCmd.Flags().BoolVarP(&catalogInitForce, "force", "f", false, "Overwrite existing files")
+
+	catalogLintCmd.Flags().BoolVar(&catalogStrict, "strict", false, "Treat warnings as errors")
+}
+
+func runCatalogInit(cmd *cobra.Command, args []string) error {
+	dir, inCatalog := ".", catalogInitCatalog != ""
+	if inCatalog {
+		dir = catalogInitCatalog
+	}
+
+	files, err := catalog.Scaffold(dir, catalog.ScaffoldData{
+		Name:     args[0],
+		Category: catalogInitCategory,
+		Image:    catalogInitImage,
+		Version:  catalogInitVersion,
+		Port:     catalogInitPort,
+	}, inCatalog, catalogInitForce)
+	if err != nil {
+		return err
+	}
+
+	color.Green("✓ Generated the skeleton of %s", args[0])
+	for _, file := range files {
+		fmt.Printf("  %s\n", file)
+	}
+	fmt.Println()
+	color.Cyan("Next steps:")
+	fmt.Println("  Fill in the TODOs and remove what the service doesn't need, then:")
+	if inCatalog {
+		fmt.Printf("    doku catalog lint %s\n", dir)
+	} else {
+		fmt.Printf("    doku catalog lint %s\n", files[0])
+		fmt.Printf("    doku catalog add %s\n", files[0])
+		fmt.Printf("    doku install %s\n", args[0])
+	}
+	return nil
+}
+
+func runCatalogLint(cmd *cobra.Command, args []string) error {
+	path := args[0]
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+
+	var issues []catalog.Issue
+	if info.IsDir() {
+		issues, err = catalog.Lint(path)
+	} else {
+		cfgMgr, cfgErr := config.New()
+		if cfgErr != nil {
+			return fmt.Errorf("failed to create config manager: %w", cfgErr)
+		}
+		_, issues, err = catalog.NewManager(cfgMgr.GetCatalogDir()).LintLocalService(path)
+	}
+	if err != nil {
+		return err
+	}
+
+	errorCount, warningCount := 0, 0
+	for _, issue := range issues {
+		if issue.Warning {
+			warningCount++
+			fmt.Printf("%s %s\n", color.YellowString("warning:"), issue)
+		} else {
+			errorCount++
+			fmt.Printf("%s %s\n", color.RedString("error:"), issue)
+		}
+	}
+
+	if len(issues) == 0 {
+		color.Green("✓ No issues found: %s", path)
+		return nil
+	}
+
+	fmt.Println()
+	fmt.Printf("%d error(s), %d warning(s)\n", errorCount, warningCount)
+	if errorCount > 0 || (catalogStrict && warningCount > 0) {
+		return fmt.Errorf("lint failed")
+	}
+	return nil
+}
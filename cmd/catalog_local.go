@@ -0,0 +1,128 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/dokulabs/doku-cli/internal/catalog"
+	"github.com/dokulabs/doku-cli/internal/config"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+var catalogAddCheck bool
+
+var catalogAddCmd = &cobra.Command{
+	Use:   "add <file>",
+	Short: "Add a service definition of your own",
+	Long: `Add a service you wrote to the catalog of this machine, so that it is
+installed like the catalog's services: behind Traefik, with its
+dependencies and env file, without forking doku-catalog.
+
+The file holds the fields of a catalog service.yaml, and its versions with
+the fields of their config.yaml:
+
+  name: internal-api
+  description: Our internal API
+  category: internal
+  versions:
+    "1.4":
+      image: registry.example.com/internal-api:1.4
+      port: 8080
+      dependencies_v2:
+        - name: postgres
+          version: "16"
+
+It is validated like a catalog (see 'doku catalog validate') before it is
+added. Local services take precedence over every catalog source; they are
+also found as local/<name>. Adding a file with the name of a local service
+replaces it.
+
+Examples:
+  doku catalog add ./internal-api.yaml
+  doku catalog add ./internal-api.yaml --check
+  doku install internal-api`,
+	Args: cobra.ExactArgs(1),
+	RunE: runCatalogAdd,
+}
+
+var catalogRemoveCmd = &cobra.Command{
+	Use:   "remove <name>",
+	Short: "Remove a service definition added with 'catalog add'",
+	Long: `Remove a local service definition. Installed instances keep running,
+but can't be upgraded or reinstalled until it is added again.
+
+Examples:
+  doku catalog remove internal-api`,
+	Args: cobra.ExactArgs(1),
+	RunE: runCatalogRemove,
+}
+
+func init() {
+	catalogCmd.AddCommand(catalogAddCmd)
+	catalogCmd.AddCommand(catalogRemoveCmd)
+
+	catalogAddCmd.Flags().BoolVar(&catalogAddCheck, "check", false, "Only validate the file")
+	catalogAddCmd.Flags().BoolVar(&catalogStrict, "strict", false, "Treat warnings as errors")
+}
+
+func runCatalogAdd(cmd *cobra.Command, args []string) error {
+	path := args[0]
+
+	cfgMgr, err := config.New()
+	if err != nil {
+		return fmt.Errorf("failed to create config manager: %w", err)
+	}
+	catalogMgr := catalog.NewManager(cfgMgr.GetCatalogDir())
+
+	def, issues, err := catalogMgr.CheckLocalService(path)
+	if err != nil {
+		return err
+	}
+
+	errorCount, warningCount := 0, 0
+	for _, issue := range issues {
+		if issue.Warning {
+			warningCount++
+			fmt.Printf("%s %s\n", color.YellowString("warning:"), issue)
+		} else {
+			errorCount++
+			fmt.Printf("%s %s\n", color.RedString("error:"), issue)
+		}
+	}
+	if len(issues) > 0 {
+		fmt.Println()
+		fmt.Printf("%d error(s), %d warning(s)\n", errorCount, warningCount)
+	}
+	if errorCount > 0 || (catalogStrict && warningCount > 0) {
+		return fmt.Errorf("service definition is invalid")
+	}
+
+	if catalogAddCheck {
+		color.Green("✓ Service definition is valid: %s", path)
+		return nil
+	}
+
+	if _, err := catalogMgr.AddLocalService(path); err != nil {
+		return err
+	}
+	color.Green("✓ Local service %s added (%d version(s))", def.Name, len(def.Versions))
+	fmt.Printf("Install it with: doku install %s\n", def.Name)
+	return nil
+}
+
+func runCatalogRemove(cmd *cobra.Command, args []string) error {
+	name := strings.TrimPrefix(args[0], catalog.LocalSource+"/")
+
+	cfgMgr, err := config.New()
+	if err != nil {
+		return fmt.Errorf("failed to create config manager: %w", err)
+	}
+	catalogMgr := catalog.NewManager(cfgMgr.GetCatalogDir())
+
+	if err := catalogMgr.RemoveLocalService(name); err != nil {
+		return err
+	}
+	color.Green("✓ Local service %s removed", name)
+	return nil
+}
@@ -0,0 +1,274 @@
+package cmd
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/dokulabs/doku-cli/internal/catalog"
+	"github.com/dokulabs/doku-cli/internal/config"
+	"github.com/dokulabs/doku-cli/internal/dependencies"
+	"github.com/dokulabs/doku-cli/internal/docker"
+	"github.com/dokulabs/doku-cli/internal/offline"
+	"github.com/dokulabs/doku-cli/internal/service"
+	"github.com/dokulabs/doku-cli/internal/traefik"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+	"io"
+)
+
+var catalogBundleImages bool
+
+var catalogBundleCmd = &cobra.Command{
+	Use:   "bundle <file.tar.gz> [service[:version]...]",
+	Short: "Pack the catalog and images for machines without network access",
+	Long: `Pack the catalog, with its other sources and local services, into one
+archive to use Doku on a machine that can't reach GitHub or image
+registries, such as a locked-down laptop.
+
+With --images, the images of the named services and of their dependencies
+are pulled if needed and saved into the archive too, with the Traefik
+image. Without services, those of the installed instances are included.
+Images are saved for this machine's architecture.
+
+Load the archive on the other machine with 'doku catalog load'.
+
+Examples:
+  doku catalog bundle doku-offline.tar.gz
+  doku catalog bundle doku-offline.tar.gz postgres:16 redis --images
+  doku catalog bundle doku-offline.tar.gz --images`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: runCatalogBundle,
+}
+
+var catalogLoadCmd = &cobra.Command{
+	Use:   "load <file.tar.gz>",
+	Short: "Use a catalog and images packed by 'catalog bundle'",
+	Long: `Replace the catalog with the one of an archive written by 'doku catalog
+bundle', and load its images into Docker. Services whose images were
+loaded are then installed without network access: the installer uses
+cached images instead of pulling them.
+
+Load it before 'doku init' on a new machine, so that init finds the
+catalog and the Traefik image.
+
+Examples:
+  doku catalog load doku-offline.tar.gz
+  doku init
+  doku install postgres:16`,
+	Args: cobra.ExactArgs(1),
+	RunE: runCatalogLoad,
+}
+
+func init() {
+	catalogCmd.AddCommand(catalogBundleCmd)
+	catalogCmd.AddCommand(catalogLoadCmd)
+
+	catalogBundleCmd.Flags().BoolVar(&catalogBundleImages, "images", false, "Include the images of the services and their dependencies")
+}
+
+func runCatalogBundle(cmd *cobra.Command, args []string) error {
+	output, services := args[0], args[1:]
+	if len(services) > 0 && !catalogBundleImages {
+		return fmt.Errorf("services name the images to include: add --images")
+	}
+
+	cfgMgr, err := config.New()
+	if err != nil {
+		return fmt.Errorf("failed to create config manager: %w", err)
+	}
+	catalogMgr := catalog.NewManager(cfgMgr.GetCatalogDir())
+	if !catalogMgr.CatalogExists() {
+		return fmt.Errorf("no catalog to bundle. Run 'doku catalog update' first")
+	}
+
+	opts := offline.BundleOptions{
+		DokuDir:     cfgMgr.GetDokuDir(),
+		OutputPath:  output,
+		DokuVersion: version,
+	}
+	if catalogVersion, err := catalogMgr.GetCatalogVersion(); err == nil {
+		opts.CatalogVersion = catalogVersion
+	}
+
+	if catalogBundleImages {
+		dockerClient, err := initDockerClient()
+		if err != nil {
+			return err
+		}
+		defer dockerClient.Close()
+
+		if len(services) == 0 {
+			if services, err = installedServices(cfgMgr); err != nil {
+				return err
+			}
+			if len(services) == 0 {
+				return fmt.Errorf("no services are installed: name the services whose images to include")
+			}
+		}
+
+		opts.Arch = dockerClient.Arch()
+		opts.Services, opts.Images, err = bundleImages(catalogMgr, cfgMgr, services, opts.Arch)
+		if err != nil {
+			return err
+		}
+		for _, image := range opts.Images {
+			if exists, err := dockerClient.ImageExists(image); err != nil {
+				return err
+			} else if !exists {
+				fmt.Printf("Pulling image %s...\n", image)
+				if err := dockerClient.ImagePullQuiet(image); err != nil {
+					return fmt.Errorf("failed to pull %s: %w", image, err)
+				}
+			}
+		}
+		fmt.Printf("Saving %d image(s)...\n", len(opts.Images))
+		opts.SaveImages = dockerClient.ImageSave
+	}
+
+	manifest, err := offline.Bundle(opts)
+	if err != nil {
+		return err
+	}
+
+	color.Green("✓ Offline bundle written to %s", output)
+	fmt.Println()
+	fmt.Println("Included:")
+	fmt.Printf("  • Catalog %s\n", manifest.CatalogVersion)
+	for _, name := range manifest.Services {
+		fmt.Printf("  • %s\n", name)
+	}
+	if len(manifest.Images) > 0 {
+		fmt.Printf("  • %d image(s) for linux/%s\n", len(manifest.Images), manifest.Arch)
+	}
+	fmt.Println()
+	fmt.Printf("Load it on the other machine with: doku catalog load %s\n", output)
+	return nil
+}
+
+// installedServices returns the services of the installed instances, as
+// name:version
+func installedServices(cfgMgr *config.Manager) ([]string, error) {
+	if !cfgMgr.IsInitialized() {
+		return nil, nil
+	}
+	instances, err := cfgMgr.ListInstances()
+	if err != nil {
+		return nil, err
+	}
+	seen := make(map[string]bool)
+	var services []string
+	for _, instance := range instances {
+		if instance.ServiceType == "custom-project" {
+			continue
+		}
+		name := instance.ServiceType
+		if instance.Version != "" {
+			name += ":" + instance.Version
+		}
+		if !seen[name] {
+			seen[name] = true
+			services = append(services, name)
+		}
+	}
+	sort.Strings(services)
+	return services, nil
+}
+
+// bundleImages returns the services to bundle with their dependencies, as
+// name:version, and the images they run on arch, with Traefik's
+func bundleImages(catalogMgr *catalog.Manager, cfgMgr *config.Manager, services []string, arch string) ([]string, []string, error) {
+	resolver := dependencies.NewResolver(catalogMgr, cfgMgr)
+
+	var resolved []string
+	traefikImage := traefik.TraefikImage
+	if cfg, err := cfgMgr.Get(); err == nil {
+		traefikImage = traefik.Image(cfg.Traefik.Version)
+	}
+	images := []string{traefikImage}
+	seen := map[string]bool{traefikImage: true}
+	for _, arg := range services {
+		name, version, _ := strings.Cut(arg, ":")
+		result, err := resolver.Resolve(name, version)
+		if err != nil {
+			return nil, nil, err
+		}
+		for _, node := range result.InstallOrder {
+			qualified := node.ServiceName + ":" + node.Version
+			if seen[qualified] {
+				continue
+			}
+			seen[qualified] = true
+			resolved = append(resolved, qualified)
+
+			spec, err := catalogMgr.GetServiceVersion(node.ServiceName, node.Version)
+			if err != nil {
+				return nil, nil, err
+			}
+			for _, image := range service.SpecImages(spec.ForArch(arch)) {
+				if !seen[image] {
+					seen[image] = true
+					images = append(images, image)
+				}
+			}
+		}
+	}
+	return resolved, images, nil
+}
+
+func runCatalogLoad(cmd *cobra.Command, args []string) error {
+	path := args[0]
+
+	manifest, err := offline.ReadManifest(path)
+	if err != nil {
+		return err
+	}
+
+	cfgMgr, err := config.New()
+	if err != nil {
+		return fmt.Errorf("failed to create config manager: %w", err)
+	}
+
+	var dockerClient *docker.Client
+	if len(manifest.Images) > 0 {
+		dockerClient, err = initDockerClient()
+		if err != nil {
+			return err
+		}
+		defer dockerClient.Close()
+		if arch := dockerClient.Arch(); manifest.Arch != "" && manifest.Arch != arch {
+			color.Yellow("⚠️  The bundle's images are for linux/%s, and this machine runs linux/%s: they will run under emulation", manifest.Arch, arch)
+		}
+		fmt.Printf("Loading %d image(s)...\n", len(manifest.Images))
+	}
+
+	loadImages := func(r io.Reader) error {
+		return dockerClient.ImageLoad(r)
+	}
+	if _, err := offline.Load(path, cfgMgr.GetDokuDir(), loadImages); err != nil {
+		return err
+	}
+
+	catalogMgr := catalog.NewManager(cfgMgr.GetCatalogDir())
+	catalogMgr.InvalidateCache()
+	if cfgMgr.IsInitialized() && manifest.CatalogVersion != "" {
+		cfgMgr.UpdateCatalogVersion(manifest.CatalogVersion)
+	}
+
+	color.Green("✓ Catalog %s loaded", manifest.CatalogVersion)
+	if len(manifest.Images) > 0 {
+		color.Green("✓ %d image(s) loaded", len(manifest.Images))
+	}
+	if len(manifest.Services) > 0 {
+		fmt.Println()
+		fmt.Println("Ready to install without network access:")
+		for _, name := range manifest.Services {
+			fmt.Printf("  doku install %s\n", name)
+		}
+	}
+	if !cfgMgr.IsInitialized() {
+		fmt.Println()
+		fmt.Println("Set up Doku with: doku init")
+	}
+	return nil
+}
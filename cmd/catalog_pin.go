@@ -0,0 +1,89 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/dokulabs/doku-cli/internal/catalog"
+	"github.com/dokulabs/doku-cli/pkg/types"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+var catalogPinCmd = &cobra.Command{
+	Use:   "pin [tag]",
+	Short: "Lock the catalog to a tag",
+	Long: `Download the catalog at a tag of doku-catalog and lock it there:
+'doku catalog update' then leaves it alone, so that services install the
+same on every machine of a team. The pin belongs to the workspace.
+
+Without a tag, shows the current pin. Remove it with
+'doku catalog update --unpin'.
+
+Examples:
+  doku catalog pin v1.4.0
+  doku catalog pin
+  doku catalog update --unpin`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runCatalogPin,
+}
+
+func init() {
+	catalogCmd.AddCommand(catalogPinCmd)
+}
+
+func runCatalogPin(cmd *cobra.Command, args []string) error {
+	cfgMgr, err := initConfigManager()
+	if err != nil {
+		return err
+	}
+	cfg, err := cfgMgr.Get()
+	if err != nil {
+		return fmt.Errorf("failed to get config: %w", err)
+	}
+
+	if len(args) == 0 {
+		if cfg.Preferences.CatalogPin == "" {
+			fmt.Println("Catalog is not pinned")
+		} else {
+			fmt.Printf("Catalog is pinned to %s\n", cfg.Preferences.CatalogPin)
+		}
+		return nil
+	}
+	tag := args[0]
+
+	catalogMgr := catalog.NewManager(cfgMgr.GetCatalogDir())
+	catalogMgr.SetCatalogURL(buildCatalogURL(tag))
+
+	var previous *types.ServiceCatalog
+	if catalogMgr.CatalogExists() {
+		previous, _ = catalogMgr.LoadCatalog()
+	}
+
+	fmt.Printf("Downloading catalog %s...\n", tag)
+	if err := catalogMgr.FetchCatalog(); err != nil {
+		return fmt.Errorf("failed to download catalog %s: %w", tag, err)
+	}
+	if err := catalogMgr.ValidateCatalog(); err != nil {
+		color.Yellow("⚠️  Catalog validation failed: %v", err)
+	}
+
+	catalogVersion, _ := catalogMgr.GetCatalogVersion()
+	if err := cfgMgr.Update(func(c *types.Config) error {
+		c.Preferences.CatalogPin = tag
+		if catalogVersion != "" {
+			c.Preferences.CatalogVersion = catalogVersion
+		}
+		return nil
+	}); err != nil {
+		return fmt.Errorf("failed to save the catalog pin: %w", err)
+	}
+
+	color.Green("✓ Catalog pinned to %s", tag)
+	if catalogVersion != "" {
+		fmt.Printf("  Version: %s\n", catalogVersion)
+	}
+	showCatalogChanges(cfgMgr, catalogMgr, previous)
+	fmt.Println()
+	fmt.Println("'doku catalog update' keeps this catalog until you run 'doku catalog update --unpin'")
+	return nil
+}
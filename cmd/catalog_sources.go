@@ -0,0 +1,185 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/dokulabs/doku-cli/internal/catalog"
+	"github.com/dokulabs/doku-cli/internal/config"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+var catalogSourcePriority int
+
+var catalogAddSourceCmd = &cobra.Command{
+	Use:   "add-source <name> <url>",
+	Short: "Register another catalog source",
+	Long: `Register a catalog besides the official one, e.g. your company's
+catalog of internal services, and download it. The URL is a .tar.gz of a
+hierarchical catalog (catalog.yaml, services/...), over HTTP(S) or as a
+local path.
+
+Its services are installed as <name>/<service>. A service is also found
+by its name alone when no other source has it, or when its source has a
+higher priority (--priority) than the official catalog's 0: that lets a
+source replace official services, e.g. with hardened images.
+
+Adding a source that is already registered changes its URL and priority.
+
+Examples:
+  doku catalog add-source mycompany https://example.com/catalog.tar.gz
+  doku install mycompany/internal-api
+  doku catalog add-source hardened ./hardened.tar.gz --priority 10`,
+	Args: cobra.ExactArgs(2),
+	RunE: runCatalogAddSource,
+}
+
+var catalogRemoveSourceCmd = &cobra.Command{
+	Use:   "remove-source <name>",
+	Short: "Unregister a catalog source",
+	Long: `Unregister a catalog source and delete its downloaded catalog.
+Installed services of the source keep running, but can't be upgraded or
+reinstalled until it is added again.
+
+Examples:
+  doku catalog remove-source mycompany`,
+	Args: cobra.ExactArgs(1),
+	RunE: runCatalogRemoveSource,
+}
+
+var catalogSourcesCmd = &cobra.Command{
+	Use:   "sources",
+	Short: "List catalog sources",
+	Long: `List the catalog sources, highest priority first, with the number of
+services each has.
+
+Examples:
+  doku catalog sources`,
+	Args: cobra.NoArgs,
+	RunE: runCatalogSources,
+}
+
+func init() {
+	catalogCmd.AddCommand(catalogAddSourceCmd)
+	catalogCmd.AddCommand(catalogRemoveSourceCmd)
+	catalogCmd.AddCommand(catalogSourcesCmd)
+
+	catalogAddSourceCmd.Flags().IntVar(&catalogSourcePriority, "priority", 0, "Priority of the source's services over those of the same name (official catalog: 0)")
+}
+
+func runCatalogAddSource(cmd *cobra.Command, args []string) error {
+	name, url := args[0], args[1]
+
+	cfgMgr, err := config.New()
+	if err != nil {
+		return fmt.Errorf("failed to create config manager: %w", err)
+	}
+	catalogMgr := catalog.NewManager(cfgMgr.GetCatalogDir())
+
+	if err := catalogMgr.AddSource(catalog.Source{Name: name, URL: url, Priority: catalogSourcePriority}); err != nil {
+		return err
+	}
+
+	fmt.Printf("Downloading catalog %s...\n", name)
+	if err := catalogMgr.FetchSource(name); err != nil {
+		color.Yellow("⚠️  Source registered, but its catalog could not be downloaded: %v", err)
+		fmt.Println("   Retry with: doku catalog update")
+		return nil
+	}
+
+	count := 0
+	if sourceCatalog, err := catalogMgr.LoadSource(catalog.Source{Name: name, URL: url}); err == nil {
+		count = len(sourceCatalog.Services)
+	}
+	color.Green("✓ Catalog source %s added (%d service(s))", name, count)
+	fmt.Printf("Install its services with: doku install %s/<service>\n", name)
+	return nil
+}
+
+func runCatalogRemoveSource(cmd *cobra.Command, args []string) error {
+	name := args[0]
+
+	cfgMgr, err := config.New()
+	if err != nil {
+		return fmt.Errorf("failed to create config manager: %w", err)
+	}
+	catalogMgr := catalog.NewManager(cfgMgr.GetCatalogDir())
+
+	if err := catalogMgr.RemoveSource(name); err != nil {
+		return err
+	}
+	color.Green("✓ Catalog source %s removed", name)
+
+	if cfgMgr.IsInitialized() {
+		if cfg, err := cfgMgr.Get(); err == nil {
+			var using []string
+			for instanceName, instance := range cfg.Instances {
+				if source, _ := catalog.SplitName(instance.ServiceType); source == name {
+					using = append(using, instanceName)
+				}
+			}
+			if len(using) > 0 {
+				sort.Strings(using)
+				color.Yellow("⚠️  Installed from it: %s. They can't be upgraded until the source is added again.", strings.Join(using, ", "))
+			}
+		}
+	}
+	return nil
+}
+
+func runCatalogSources(cmd *cobra.Command, args []string) error {
+	cfgMgr, err := config.New()
+	if err != nil {
+		return fmt.Errorf("failed to create config manager: %w", err)
+	}
+	catalogMgr := catalog.NewManager(cfgMgr.GetCatalogDir())
+
+	sources, err := catalogMgr.ListSources()
+	if err != nil {
+		return err
+	}
+
+	countServices := func(load func() (int, error)) string {
+		count, err := load()
+		if err != nil {
+			return color.YellowString("not downloaded")
+		}
+		return fmt.Sprintf("%d", count)
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "NAME\tPRIORITY\tSERVICES\tURL")
+	official := countServices(func() (int, error) {
+		sourceCatalog, err := catalog.NewManager(cfgMgr.GetCatalogDir()).LoadCatalog()
+		if err != nil {
+			return 0, err
+		}
+		// Without the services of the other sources
+		count := 0
+		for name := range sourceCatalog.Services {
+			if source, _ := catalog.SplitName(name); source == "" {
+				count++
+			}
+		}
+		return count, nil
+	})
+	if local, err := catalogMgr.ListLocalServices(); err == nil && len(local) > 0 {
+		fmt.Fprintf(w, "%s\t%d\t%d\t%s\n", catalog.LocalSource, catalog.LocalPriority, len(local), "(doku catalog add)")
+	}
+	fmt.Fprintf(w, "%s\t0\t%s\t%s\n", catalog.OfficialSource, official, catalog.DefaultCatalogURL)
+	for _, source := range sources {
+		count := countServices(func() (int, error) {
+			sourceCatalog, err := catalogMgr.LoadSource(source)
+			if err != nil {
+				return 0, err
+			}
+			return len(sourceCatalog.Services), nil
+		})
+		fmt.Fprintf(w, "%s\t%d\t%s\t%s\n", source.Name, source.Priority, count, source.URL)
+	}
+	return w.Flush()
+}
@@ -0,0 +1,406 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/dokulabs/doku-cli/internal/certs"
+	"github.com/dokulabs/doku-cli/internal/config"
+	"github.com/dokulabs/doku-cli/internal/docker"
+	"github.com/dokulabs/doku-cli/internal/domain"
+	"github.com/dokulabs/doku-cli/internal/proxy"
+	"github.com/dokulabs/doku-cli/internal/readonly"
+	"github.com/dokulabs/doku-cli/internal/traefik"
+	"github.com/dokulabs/doku-cli/pkg/types"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// noAutoRenew lists the commands that don't renew certificates due for
+// renewal first
+var noAutoRenew = map[string]bool{
+	"certs":      true,
+	"completion": true,
+	"config":     true,
+	"help":       true,
+	"init":       true,
+	"self":       true,
+	"uninstall":  true,
+	"upgrade":    true,
+	"version":    true,
+}
+
+var certsDays int
+
+var certsCmd = &cobra.Command{
+	Use:   "certs",
+	Short: "Check and renew the certificates of the services' hostnames",
+	Long: `Check and renew the certificates Traefik serves for the hostnames of Doku
+and its services, with https.
+
+Doku renews the certificates it issues, with mkcert or an imported CA, when
+they expire within 30 days: before any command, and with 'doku certs renew'.
+Change the number of days, or turn automatic renewal off:
+
+  doku config set certificates.renewdays 60
+  doku config set certificates.renewdays off
+
+Provided certificates can't be renewed by Doku: replace them before they
+expire. 'doku doctor' reports those that are due.
+
+Examples:
+  doku certs status              # Expiry of every certificate
+  doku certs renew               # Renew those due for renewal
+  doku certs renew --days 90     # Renew those expiring within 90 days
+  doku certs renew doku.local    # Renew one now`,
+}
+
+var certsStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Show when the certificates expire",
+	Args:  cobra.NoArgs,
+	RunE:  runCertsStatus,
+}
+
+var certsRenewCmd = &cobra.Command{
+	Use:   "renew [domain...]",
+	Short: "Renew the certificates due for renewal, or those given",
+	Long: `Issue the certificates due for renewal again, or those of the domains given
+whatever their expiry, and reload Traefik so it serves them.`,
+	RunE: runCertsRenew,
+}
+
+func init() {
+	rootCmd.AddCommand(certsCmd)
+	certsCmd.AddCommand(certsStatusCmd)
+	certsCmd.AddCommand(certsRenewCmd)
+
+	certsStatusCmd.Flags().IntVar(&certsDays, "days", 0, "Mark the certificates expiring within this many days (default: certificates.renewdays, or 30)")
+	certsRenewCmd.Flags().IntVar(&certsDays, "days", 0, "Renew the certificates expiring within this many days (default: certificates.renewdays, or 30)")
+}
+
+// certsConfig returns the config manager and configuration when Traefik
+// serves certificates, or nil ones after saying why not
+func certsConfig() (*config.Manager, *types.Config, error) {
+	cfgMgr, err := initConfigManager()
+	if err != nil {
+		if err == types.ErrNotInitialized {
+			return nil, nil, nil
+		}
+		return nil, nil, err
+	}
+	cfg, err := cfgMgr.Get()
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get config: %w", err)
+	}
+
+	if cfg.Preferences.Protocol != "https" {
+		fmt.Println("Doku serves the services over http, so it has no certificates.")
+		fmt.Println("Switch to https with 'doku init --protocol https'")
+		return nil, nil, nil
+	}
+	if proxy.Backend(cfg.Preferences) == proxy.Caddy {
+		fmt.Println("Caddy issues and renews its own certificates.")
+		return nil, nil, nil
+	}
+	return cfgMgr, cfg, nil
+}
+
+// renewalDays returns the window of the --days flag, or the configured one
+func renewalDays(cmd *cobra.Command, cfg *types.Config) (int, error) {
+	if cmd.Flags().Changed("days") {
+		if certsDays < 0 {
+			return 0, fmt.Errorf("--days must be 0 or more")
+		}
+		return certsDays, nil
+	}
+	return certs.RenewalWindow(cfg.Certificates), nil
+}
+
+func runCertsStatus(cmd *cobra.Command, args []string) error {
+	cfgMgr, cfg, err := certsConfig()
+	if err != nil || cfgMgr == nil {
+		return err
+	}
+	days, err := renewalDays(cmd, cfg)
+	if err != nil {
+		return err
+	}
+
+	list, err := certs.List(cfgMgr.GetCertsDir())
+	if err != nil {
+		return err
+	}
+
+	printCertificatesSource(cfg.Certificates)
+	if certs.RenewDays(cfg.Certificates) < 0 {
+		fmt.Println("Automatic renewal: off")
+	} else if certs.Source(cfg.Certificates) != certs.SourceProvided {
+		fmt.Printf("Automatic renewal: %d days before they expire\n", certs.RenewDays(cfg.Certificates))
+	}
+	fmt.Println()
+
+	if len(list) == 0 {
+		color.Yellow("⚠️  No certificates in %s", cfgMgr.GetCertsDir())
+		return nil
+	}
+
+	now := time.Now()
+	due := 0
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
+	fmt.Fprintln(w, "DOMAIN\tEXPIRES\tDAYS LEFT\tCOVERS\tSTATUS")
+	for _, cert := range list {
+		if cert.Err != nil {
+			fmt.Fprintf(w, "%s\t-\t-\t-\t%s\n", cert.Name, color.RedString("unreadable: %v", cert.Err))
+			due++
+			continue
+		}
+
+		status := color.GreenString("valid")
+		switch {
+		case cert.Expired(now):
+			status = color.RedString("expired")
+			due++
+		case cert.Due(now, days):
+			status = color.YellowString("due for renewal")
+			due++
+		}
+		fmt.Fprintf(w, "%s\t%s\t%d\t%s\t%s\n", cert.Name, cert.NotAfter.Format("2006-01-02"), cert.DaysLeft(now), strings.Join(cert.Domains, ", "), status)
+	}
+	w.Flush()
+
+	if due > 0 {
+		fmt.Println()
+		if certs.Source(cfg.Certificates) == certs.SourceProvided {
+			fmt.Printf("Replace them in %s\n", cfgMgr.GetCertsDir())
+		} else {
+			fmt.Println("Renew them with 'doku certs renew'")
+		}
+	}
+	return nil
+}
+
+// printCertificatesSource says where the certificates come from
+func printCertificatesSource(c types.CertificatesConfig) {
+	switch certs.Source(c) {
+	case certs.SourceCA:
+		ca, _, err := certs.LoadCA(c.CACert, c.CAKey)
+		if err != nil {
+			color.Yellow("⚠️  Issued from the CA %s, which can't be used: %v", c.CACert, err)
+			return
+		}
+		fmt.Printf("Issued from: %s, which expires on %s\n", ca.Subject.CommonName, ca.NotAfter.Format("2006-01-02"))
+	case certs.SourceProvided:
+		fmt.Println("Issued elsewhere and provided, so Doku can't renew them")
+	default:
+		fmt.Println("Issued by: mkcert's local CA")
+	}
+}
+
+func runCertsRenew(cmd *cobra.Command, args []string) error {
+	cfgMgr, cfg, err := certsConfig()
+	if err != nil || cfgMgr == nil {
+		return err
+	}
+	days, err := renewalDays(cmd, cfg)
+	if err != nil {
+		return err
+	}
+
+	list, err := certs.List(cfgMgr.GetCertsDir())
+	if err != nil {
+		return err
+	}
+
+	var names []string
+	if len(args) > 0 {
+		for _, name := range args {
+			if !slices.ContainsFunc(list, func(cert certs.Certificate) bool { return cert.Name == name }) {
+				return fmt.Errorf("no certificate of %s in %s", name, cfgMgr.GetCertsDir())
+			}
+		}
+		names = args
+	} else {
+		now := time.Now()
+		for _, cert := range list {
+			if cert.Err != nil || cert.Due(now, days) {
+				names = append(names, cert.Name)
+			}
+		}
+		if len(names) == 0 {
+			color.Green("✓ No certificate expires within %d days", days)
+			return nil
+		}
+	}
+
+	failed := 0
+	renewed := 0
+	for _, name := range names {
+		if err := renewCertificate(cfgMgr, cfg, name, days); err != nil {
+			color.Red("✗ %s: %v", name, err)
+			failed++
+			continue
+		}
+		renewed++
+	}
+
+	if renewed > 0 {
+		if err := reloadCertificates(cfgMgr, cfg); err != nil {
+			return err
+		}
+		printSuccess(fmt.Sprintf("Renewed %d certificate(s); Traefik serves them now", renewed))
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d certificate(s) could not be renewed", failed)
+	}
+	return nil
+}
+
+// renewalManager returns the manager issuing a certificate again, for the
+// names it has, e.g. a service's extra SANs
+func renewalManager(cfgMgr *config.Manager, cfg *types.Config, cert certs.Certificate) *certs.Manager {
+	return certs.NewManager(cfgMgr.GetCertsDir(), cert.Name).Configure(cfg.Certificates).SetHosts(cert.Domains)
+}
+
+// renewCertificate issues the certificate of a domain again, unless it
+// can't be renewed days before it expires. The new one overwrites the old
+// only once issued.
+func renewCertificate(cfgMgr *config.Manager, cfg *types.Config, name string, days int) error {
+	certMgr := renewalManager(cfgMgr, cfg, certs.Read(cfgMgr.GetCertsDir(), name))
+	if err := certMgr.CanRenew(days); err != nil {
+		return err
+	}
+	return certMgr.GenerateCertificates()
+}
+
+// reloadCertificates rewrites Traefik's dynamic configuration, which it
+// watches, so it loads the certificates again without restarting
+func reloadCertificates(cfgMgr *config.Manager, cfg *types.Config) error {
+	traefikMgr := traefik.NewManager(nil, cfgMgr.GetTraefikDir(), cfgMgr.GetCertsDir(), cfg.Preferences.Domain, cfg.Preferences.Protocol)
+	if err := traefikMgr.GenerateDynamicConfig(); err != nil {
+		return fmt.Errorf("failed to reload Traefik: %w", err)
+	}
+	return nil
+}
+
+// serviceCertificateName returns the name of a service's own certificate,
+// the one with its TLS SANs: its hostname under the main domain
+func serviceCertificateName(subdomain string, prefs types.PreferencesConfig) string {
+	return subdomain + "." + domain.BaseDomains(prefs)[0]
+}
+
+// validateTLSSANs checks a service can get its own certificate with the
+// given SANs: Traefik serves Doku's certificates, over https
+func validateTLSSANs(cfg *types.Config, sans []string) error {
+	if cfg.Preferences.Protocol != "https" {
+		return fmt.Errorf("--tls-san needs https: Doku serves the services over http")
+	}
+	if proxy.Backend(cfg.Preferences) == proxy.Caddy {
+		return fmt.Errorf("--tls-san needs Traefik: Caddy issues its own certificates")
+	}
+	for _, san := range sans {
+		if err := certs.ValidateSAN(san); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// issueServiceCertificate issues a service's own certificate, for its
+// hostname and TLS SANs, and has Traefik serve it. Traefik picks it over
+// the domain's wildcard certificate for the names it has.
+func issueServiceCertificate(cfgMgr *config.Manager, cfg *types.Config, subdomain string, sans []string) error {
+	name := serviceCertificateName(subdomain, cfg.Preferences)
+	hosts := []string{name}
+	for _, san := range sans {
+		if !slices.Contains(hosts, san) {
+			hosts = append(hosts, san)
+		}
+	}
+
+	certMgr := certs.NewManager(cfgMgr.GetCertsDir(), name).Configure(cfg.Certificates).SetHosts(hosts)
+	if certMgr.NeedsMkcert() && !certMgr.IsMkcertInstalled() {
+		return fmt.Errorf("mkcert is not installed; it is needed to generate the certificate of %s", name)
+	}
+	if err := certMgr.GenerateCertificates(); err != nil {
+		return err
+	}
+	return reloadCertificates(cfgMgr, cfg)
+}
+
+// removeServiceCertificate removes a service's own certificate, so Traefik
+// stops serving it
+func removeServiceCertificate(cfgMgr *config.Manager, cfg *types.Config, instance *types.Instance) error {
+	certMgr := certs.NewManager(cfgMgr.GetCertsDir(), serviceCertificateName(domain.InstanceSubdomain(instance), cfg.Preferences))
+	for _, path := range []string{certMgr.GetCertificatePath(), certMgr.GetKeyPath()} {
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to remove %s: %w", path, err)
+		}
+	}
+	return reloadCertificates(cfgMgr, cfg)
+}
+
+// autoRenewCertificates renews the certificates Doku issued that are due
+// for renewal, before commands. Like the automatic resync, it's skipped for
+// commands that don't need it, in scripts and in read-only mode, and
+// certificates it can't renew are left to 'doku doctor'.
+func autoRenewCertificates(cmd *cobra.Command) {
+	if readonly.Enabled() || viper.GetBool("quiet") || !docker.IsTerminal(os.Stdout) {
+		return
+	}
+
+	top := cmd
+	for top.HasParent() && top.Parent().HasParent() {
+		top = top.Parent()
+	}
+	if !top.HasParent() || noAutoRenew[top.Name()] || strings.HasPrefix(top.Name(), "__") {
+		return
+	}
+
+	cfgMgr, err := config.New()
+	if err != nil || !cfgMgr.IsInitialized() {
+		return
+	}
+	cfg, err := cfgMgr.Get()
+	if err != nil || cfg.Preferences.Protocol != "https" || proxy.Backend(cfg.Preferences) != proxy.Traefik {
+		return
+	}
+	days := certs.RenewDays(cfg.Certificates)
+	if days < 0 || certs.Source(cfg.Certificates) == certs.SourceProvided {
+		return
+	}
+
+	list, err := certs.List(cfgMgr.GetCertsDir())
+	if err != nil {
+		return
+	}
+	now := time.Now()
+	renewed := 0
+	for _, cert := range list {
+		if !cert.Due(now, days) {
+			continue
+		}
+		certMgr := renewalManager(cfgMgr, cfg, cert)
+		if certMgr.CanRenew(days) != nil {
+			continue
+		}
+
+		color.New(color.Faint).Fprintf(os.Stderr, "The certificate of %s expires in %d day(s), renewing it:\n", cert.Name, cert.DaysLeft(now))
+		if err := certMgr.GenerateCertificates(); err != nil {
+			color.New(color.FgYellow).Fprintf(os.Stderr, "⚠️  Failed to renew the certificate of %s: %v\n", cert.Name, err)
+			continue
+		}
+		renewed++
+	}
+	if renewed == 0 {
+		return
+	}
+	if err := reloadCertificates(cfgMgr, cfg); err != nil {
+		color.New(color.FgYellow).Fprintf(os.Stderr, "⚠️  %v\n", err)
+	}
+}
@@ -0,0 +1,172 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	dockerContainer "github.com/docker/docker/api/types/container"
+	"github.com/dokulabs/doku-cli/internal/docker"
+	"github.com/dokulabs/doku-cli/pkg/types"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+var (
+	changesContainer      string
+	changesSummary        bool
+	changesDepth          int
+	changesIncludeVolumes bool
+)
+
+var changesCmd = &cobra.Command{
+	Use:   "changes <service>",
+	Short: "Show files a service wrote outside its volumes",
+	Long: `Show the filesystem changes a service's container made on top of its image.
+
+Changes under mounted volumes are hidden by default, so the output lists data
+that will be lost when the container is recreated. Directories that collect
+many added files are good candidates for volumes in the catalog spec.
+
+Change types:
+  A  Added
+  C  Changed
+  D  Deleted
+
+Examples:
+  doku changes postgres                 # List changed files
+  doku changes postgres --summary       # Group changes by directory
+  doku changes postgres -s --depth 2    # Group by top two path segments
+  doku changes signoz -c clickhouse     # One container of a multi-container service`,
+	Args: cobra.ExactArgs(1),
+	RunE: runChanges,
+}
+
+func init() {
+	rootCmd.AddCommand(changesCmd)
+
+	changesCmd.Flags().StringVarP(&changesContainer, "container", "c", "", "Specific container name (for multi-container services)")
+	changesCmd.Flags().BoolVarP(&changesSummary, "summary", "s", false, "Group changes by directory")
+	changesCmd.Flags().IntVar(&changesDepth, "depth", 3, "Number of path segments to group by with --summary")
+	changesCmd.Flags().BoolVar(&changesIncludeVolumes, "include-volumes", false, "Include changes at volume mount points")
+}
+
+func runChanges(cmd *cobra.Command, args []string) error {
+	instanceName := args[0]
+
+	// Initialize config manager
+	cfgMgr, err := initConfigManager()
+	if err != nil {
+		if err == types.ErrNotInitialized {
+			return nil
+		}
+		return err
+	}
+
+	// Initialize Docker client
+	dockerClient, err := initDockerClient()
+	if err != nil {
+		return err
+	}
+	defer dockerClient.Close()
+
+	serviceMgr := getServiceManager(dockerClient, cfgMgr)
+	instance, err := serviceMgr.Get(instanceName)
+	if err != nil {
+		return fmt.Errorf("service '%s' not found. Use 'doku list' to see installed services", instanceName)
+	}
+
+	// Collect the containers to inspect
+	containers := map[string]string{}
+	var order []string
+	if instance.IsMultiContainer {
+		for _, c := range instance.Containers {
+			if changesContainer != "" && c.Name != changesContainer {
+				continue
+			}
+			containers[c.Name] = c.FullName
+			order = append(order, c.Name)
+		}
+		if len(order) == 0 {
+			return fmt.Errorf("container '%s' not found in service '%s'", changesContainer, instanceName)
+		}
+	} else {
+		if changesContainer != "" {
+			return fmt.Errorf("--container is only supported for multi-container services")
+		}
+		containers[instanceName] = instance.ContainerName
+		order = append(order, instanceName)
+	}
+
+	for _, name := range order {
+		if err := showContainerChanges(dockerClient, name, containers[name], len(order) > 1); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// showContainerChanges prints the filesystem changes of a single container
+func showContainerChanges(dockerClient *docker.Client, name, containerName string, showHeader bool) error {
+	changes, err := dockerClient.ContainerDiff(containerName)
+	if err != nil {
+		return fmt.Errorf("failed to get changes for %s: %w", name, err)
+	}
+
+	var mountPoints []string
+	if !changesIncludeVolumes {
+		info, err := dockerClient.ContainerInspect(containerName)
+		if err != nil {
+			return fmt.Errorf("failed to inspect container: %w", err)
+		}
+		for _, mp := range info.Mounts {
+			mountPoints = append(mountPoints, mp.Destination)
+		}
+		changes = docker.FilterChanges(changes, mountPoints)
+	}
+
+	if showHeader {
+		fmt.Println()
+		color.Cyan("%s (%s)", name, containerName)
+	}
+
+	if len(changes) == 0 {
+		color.Green("✓ No changes outside volumes")
+		return nil
+	}
+
+	if changesSummary {
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
+		fmt.Fprintln(w, "PATH\tADDED\tCHANGED\tDELETED")
+		for _, summary := range docker.SummarizeChanges(changes, changesDepth) {
+			fmt.Fprintf(w, "%s\t%d\t%d\t%d\n", summary.Path, summary.Added, summary.Modified, summary.Deleted)
+		}
+		w.Flush()
+	} else {
+		for _, change := range changes {
+			fmt.Printf("%s %s\n", formatChangeKind(change.Kind), change.Path)
+		}
+	}
+
+	fmt.Println()
+	color.New(color.Faint).Printf("%d change(s)", len(changes))
+	if len(mountPoints) > 0 {
+		color.New(color.Faint).Printf(", excluding %d volume mount(s)", len(mountPoints))
+	}
+	fmt.Println()
+
+	return nil
+}
+
+// formatChangeKind returns a colored single-letter change type
+func formatChangeKind(kind dockerContainer.ChangeType) string {
+	switch kind {
+	case dockerContainer.ChangeAdd:
+		return color.GreenString("A")
+	case dockerContainer.ChangeDelete:
+		return color.RedString("D")
+	default:
+		return color.YellowString("C")
+	}
+}
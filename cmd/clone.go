@@ -0,0 +1,120 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/dokulabs/doku-cli/internal/catalog"
+	"github.com/dokulabs/doku-cli/internal/config"
+	"github.com/dokulabs/doku-cli/internal/service"
+	"github.com/dokulabs/doku-cli/pkg/types"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+var (
+	clonePorts []string
+	cloneLive  bool
+)
+
+var cloneCmd = &cobra.Command{
+	Use:   "clone <service> <new-name>",
+	Short: "Copy an installed service and its data under a new name",
+	Long: `Install a copy of a service with the same version, resources, labels and
+environment, then copy the data in its volumes into the copy. Use it to try
+migrations or experiments against realistic data without touching the
+original.
+
+The source is stopped while its volumes are copied, so databases are copied
+in a consistent state, and started again afterwards. --live copies without
+stopping it; that is faster but may copy files in the middle of a write.
+
+Host ports are not copied, as they would conflict with the source's; give
+the copy its own with --port. Custom projects can't be cloned.
+
+Examples:
+  doku clone postgres-16 postgres-16-test
+  doku clone postgres postgres-copy --port 5433:5432
+  doku clone redis redis-scratch --live`,
+	Args: cobra.ExactArgs(2),
+	RunE: runClone,
+}
+
+func init() {
+	rootCmd.AddCommand(cloneCmd)
+
+	cloneCmd.Flags().StringSliceVarP(&clonePorts, "port", "p", []string{}, "Port mappings for the copy (host:container or port)")
+	cloneCmd.Flags().BoolVar(&cloneLive, "live", false, "Copy the data without stopping the source")
+}
+
+func runClone(cmd *cobra.Command, args []string) error {
+	source, target := args[0], args[1]
+
+	cfgMgr, err := initConfigManager()
+	if err != nil {
+		if err == types.ErrNotInitialized {
+			return nil
+		}
+		return err
+	}
+
+	if err := config.ValidateInstanceName(target); err != nil {
+		return err
+	}
+
+	instance, err := cfgMgr.GetInstance(source)
+	if err != nil {
+		if _, projErr := cfgMgr.GetProject(source); projErr == nil {
+			return fmt.Errorf("'%s' is a custom project; add the project again under another name instead", source)
+		}
+		return fmt.Errorf("service '%s' not found. Use 'doku list' to see installed services", source)
+	}
+
+	portMappings, err := parsePortMappings(clonePorts, 0)
+	if err != nil {
+		return err
+	}
+
+	dockerClient, err := initDockerClient()
+	if err != nil {
+		return err
+	}
+	defer dockerClient.Close()
+
+	catalogMgr := catalog.NewManager(cfgMgr.GetCatalogDir())
+	installer, err := service.NewInstaller(dockerClient, cfgMgr, catalogMgr)
+	if err != nil {
+		return fmt.Errorf("failed to create installer: %w", err)
+	}
+
+	fmt.Println()
+	color.Cyan("Cloning %s (%s %s) to %s...", instance.Name, instance.ServiceType, instance.Version, target)
+	fmt.Println()
+
+	clone, err := installer.Clone(service.CloneOptions{
+		Source:       source,
+		Target:       target,
+		PortMappings: portMappings,
+		Live:         cloneLive,
+	})
+	if err != nil {
+		if clone != nil {
+			color.Yellow("⚠️  %s was installed but its data was not fully copied", target)
+			fmt.Printf("   Remove it with: doku remove %s\n", target)
+		}
+		return err
+	}
+
+	fmt.Println()
+	color.Green("✓ Cloned %s to %s", source, target)
+	if clone.URL != "" {
+		fmt.Printf("  URL: %s\n", clone.URL)
+	}
+	if clone.ConnectionString != "" {
+		fmt.Printf("  Connection: %s\n", clone.ConnectionString)
+	}
+	fmt.Println()
+	color.New(color.Faint).Printf("The copy has the same credentials as %s. See them with 'doku env %s --show-values'.\n", source, target)
+	fmt.Println()
+
+	return nil
+}
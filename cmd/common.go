@@ -3,6 +3,7 @@ package cmd
 import (
 	"fmt"
 
+	"github.com/dokulabs/doku-cli/internal/caddy"
 	"github.com/dokulabs/doku-cli/internal/config"
 	"github.com/dokulabs/doku-cli/internal/docker"
 	"github.com/dokulabs/doku-cli/internal/service"
@@ -41,6 +42,12 @@ func getServiceManager(dockerClient *docker.Client, cfgMgr *config.Manager) *ser
 	return service.NewManager(dockerClient, cfgMgr)
 }
 
+// traefikDashboardURL returns the URL of the Traefik dashboard
+func traefikDashboardURL(cfg *types.Config) string {
+	httpPort, httpsPort := config.TraefikPorts(cfg.Traefik)
+	return config.ServiceURL(cfg.Preferences.Protocol, "traefik."+cfg.Preferences.Domain, httpPort, httpsPort)
+}
+
 // TraefikAction represents an action to perform on Traefik
 type TraefikAction string
 
@@ -51,26 +58,43 @@ const (
 	TraefikActionInfo    TraefikAction = "info"
 )
 
-// handleTraefikCommand handles Traefik-specific commands (start, stop, restart)
-// Returns true if the instance was Traefik and was handled, false otherwise
+// handleTraefikCommand handles commands (start, stop, restart) on the reverse
+// proxy, Traefik or Caddy
+// Returns true if the instance was the proxy and was handled, false otherwise
 func handleTraefikCommand(instanceName string, action TraefikAction, dockerClient *docker.Client, cfgMgr *config.Manager) (handled bool, err error) {
-	// Check if this is a Traefik command
-	if instanceName != "traefik" && instanceName != "doku-traefik" {
+	// Check if this is a proxy command
+	containerName, ok := proxyContainerName(instanceName)
+	if !ok {
 		return false, nil
 	}
-
-	containerName := constants.TraefikContainerName
+	name, short := "Traefik", "traefik"
+	if containerName == caddy.ContainerName() {
+		name, short = "Caddy", "caddy"
+	}
 
 	// Check if container exists
 	exists, err := dockerClient.ContainerExists(containerName)
 	if err != nil || !exists {
-		return true, fmt.Errorf("Traefik container not found. Run 'doku init' first")
+		return true, fmt.Errorf("%s container not found. Run 'doku init' first", name)
 	}
 
 	// Get container info
 	containerInfo, err := dockerClient.ContainerInspect(containerName)
 	if err != nil {
-		return true, fmt.Errorf("failed to inspect Traefik container: %w", err)
+		return true, fmt.Errorf("failed to inspect %s container: %w", name, err)
+	}
+
+	// printDashboard prints the URL of Traefik's dashboard; Caddy has none
+	printDashboard := func() error {
+		if short != "traefik" {
+			return nil
+		}
+		cfg, err := cfgMgr.Get()
+		if err != nil {
+			return fmt.Errorf("failed to get configuration: %w", err)
+		}
+		fmt.Printf("Dashboard: %s\n", traefikDashboardURL(cfg))
+		return nil
 	}
 
 	// Perform action
@@ -78,64 +102,49 @@ func handleTraefikCommand(instanceName string, action TraefikAction, dockerClien
 	case TraefikActionStart:
 		// Check if already running
 		if containerInfo.State.Running {
-			color.Yellow("⚠️  Traefik is already running")
-			cfg, err := cfgMgr.Get()
-			if err != nil {
-				return true, fmt.Errorf("failed to get configuration: %w", err)
-			}
-			fmt.Printf("Dashboard: %s://traefik.%s\n", cfg.Preferences.Protocol, cfg.Preferences.Domain)
-			return true, nil
+			color.Yellow("⚠️  %s is already running", name)
+			return true, printDashboard()
 		}
 
-		fmt.Println("Starting Traefik...")
+		fmt.Printf("Starting %s...\n", name)
 		if err := dockerClient.ContainerStart(containerInfo.ID); err != nil {
-			return true, fmt.Errorf("failed to start Traefik: %w", err)
+			return true, fmt.Errorf("failed to start %s: %w", name, err)
 		}
 
-		color.Green("✓ Traefik started successfully")
-		cfg, err := cfgMgr.Get()
-		if err != nil {
-			return true, fmt.Errorf("failed to get configuration: %w", err)
-		}
-		fmt.Printf("Dashboard: %s://traefik.%s\n", cfg.Preferences.Protocol, cfg.Preferences.Domain)
-		return true, nil
+		color.Green("✓ %s started successfully", name)
+		return true, printDashboard()
 
 	case TraefikActionStop:
 		// Check if already stopped
 		if !containerInfo.State.Running {
-			color.Yellow("⚠️  Traefik is already stopped")
+			color.Yellow("⚠️  %s is already stopped", name)
 			return true, nil
 		}
 
-		color.Yellow("⚠️  Warning: Stopping Traefik will make all services inaccessible")
-		fmt.Println("Stopping Traefik...")
+		color.Yellow("⚠️  Warning: Stopping %s will make all services inaccessible", name)
+		fmt.Printf("Stopping %s...\n", name)
 
 		timeout := constants.DefaultContainerTimeout
 		if err := dockerClient.ContainerStop(containerName, &timeout); err != nil {
-			return true, fmt.Errorf("failed to stop Traefik: %w", err)
+			return true, fmt.Errorf("failed to stop %s: %w", name, err)
 		}
 
-		color.Green("✓ Traefik stopped successfully")
-		color.New(color.Faint).Println("Use 'doku start traefik' to start it again")
+		color.Green("✓ %s stopped successfully", name)
+		color.New(color.Faint).Printf("Use 'doku start %s' to start it again\n", short)
 		return true, nil
 
 	case TraefikActionRestart:
-		fmt.Println("Restarting Traefik...")
+		fmt.Printf("Restarting %s...\n", name)
 
 		timeout := constants.DefaultContainerTimeout
 		if err := dockerClient.ContainerRestart(containerName, &timeout); err != nil {
-			return true, fmt.Errorf("failed to restart Traefik: %w", err)
+			return true, fmt.Errorf("failed to restart %s: %w", name, err)
 		}
 
-		color.Green("✓ Traefik restarted successfully")
-		cfg, err := cfgMgr.Get()
-		if err != nil {
-			return true, fmt.Errorf("failed to get configuration: %w", err)
-		}
-		fmt.Printf("Dashboard: %s://traefik.%s\n", cfg.Preferences.Protocol, cfg.Preferences.Domain)
-		return true, nil
+		color.Green("✓ %s restarted successfully", name)
+		return true, printDashboard()
 
 	default:
-		return true, fmt.Errorf("unknown Traefik action: %s", action)
+		return true, fmt.Errorf("unknown %s action: %s", name, action)
 	}
 }
@@ -0,0 +1,265 @@
+package cmd
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/dokulabs/doku-cli/internal/catalog"
+	"github.com/dokulabs/doku-cli/internal/config"
+	"github.com/dokulabs/doku-cli/internal/workspace"
+	"github.com/dokulabs/doku-cli/pkg/types"
+	"github.com/spf13/cobra"
+)
+
+// completionFunc completes the arguments of a command
+type completionFunc func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective)
+
+// Shell completion of instance, group, project and catalog names. It only
+// reads the config and the catalog, without connecting to Docker, so it
+// stays fast.
+func init() {
+	for _, cmd := range []*cobra.Command{
+		backupCmd, changesCmd, cloneCmd, envCmd, envEditCmd, envHistoryCmd, envRollbackCmd,
+		envRefreshCmd, envSetCmd, envUnsetCmd, execCmd, infoCmd, profileApplyCmd,
+		removeCmd, renameCmd, rollbackCmd, scaleCmd, serviceUpgradeCmd, updateCmd,
+		backupListCmd, healthCmd, statsCmd, autoUpdateHistoryCmd, exposeCmd, shareCmd,
+		trafficCmd, limitsSetCmd,
+	} {
+		cmd.ValidArgsFunction = completeInstances(1, false)
+	}
+	for _, cmd := range []*cobra.Command{startCmd, stopCmd, restartCmd, logsCmd} {
+		cmd.ValidArgsFunction = completeInstances(1, true)
+	}
+	for _, cmd := range []*cobra.Command{statusCmd, exportCmd, autoUpdateEnableCmd, autoUpdateDisableCmd, autoUpdateRunCmd} {
+		cmd.ValidArgsFunction = completeInstances(0, false)
+	}
+
+	for _, cmd := range []*cobra.Command{installCmd, dependsCmd} {
+		cmd.ValidArgsFunction = completeCatalogServices(true)
+	}
+	for _, cmd := range []*cobra.Command{catalogShowCmd, profileShowCmd, profileCreateCmd, profileDeleteCmd} {
+		cmd.ValidArgsFunction = completeCatalogServices(false)
+	}
+
+	for _, cmd := range []*cobra.Command{projectBuildCmd, projectDevCmd, projectRemoveCmd, projectRunCmd, projectLinksCmd} {
+		cmd.ValidArgsFunction = completeProjects(1)
+	}
+	relinkCmd.ValidArgsFunction = completeProjects(0)
+	linkCmd.ValidArgsFunction = completeLink
+	docsCmd.ValidArgsFunction = completeDocs
+	openCmd.ValidArgsFunction = func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		completions, directive := completeInstances(1, false)(cmd, args, toComplete)
+		if len(args) == 0 {
+			completions = append(completions, "traefik\tTraefik dashboard")
+		}
+		return completions, directive
+	}
+
+	groupRemoveCmd.ValidArgsFunction = completeNames(func(cfg *types.Config) []string {
+		return mapKeys(cfg.Groups)
+	})
+	groupSetCmd.ValidArgsFunction = func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) == 0 {
+			return completeNames(func(cfg *types.Config) []string { return mapKeys(cfg.Groups) })(cmd, args, toComplete)
+		}
+		return completeInstances(0, false)(cmd, args, toComplete)
+	}
+	for _, cmd := range []*cobra.Command{webhookRemoveCmd, webhookTestCmd} {
+		cmd.ValidArgsFunction = completeNames(func(cfg *types.Config) []string {
+			names := make([]string, 0, len(cfg.Webhooks))
+			for _, hook := range cfg.Webhooks {
+				names = append(names, hook.Name)
+			}
+			return names
+		})
+	}
+}
+
+// completionConfig returns the config, or nil when Doku isn't initialized
+func completionConfig() *types.Config {
+	// Completion doesn't run the root's hooks, which select the workspace
+	name, _, err := resolveWorkspace()
+	if err != nil || workspace.Set(name) != nil {
+		return nil
+	}
+	cfgMgr, err := config.New()
+	if err != nil || !cfgMgr.IsInitialized() {
+		return nil
+	}
+	cfg, err := cfgMgr.Get()
+	if err != nil {
+		return nil
+	}
+	return cfg
+}
+
+// completeInstances completes installed instances, described by service,
+// version and status, up to max arguments (0 = any). Instances already
+// given are left out. With groups, "@group" names are offered too.
+func completeInstances(max int, groups bool) completionFunc {
+	return func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if max > 0 && len(args) >= max {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+		cfg := completionConfig()
+		if cfg == nil {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+
+		completions := instanceCompletions(cfg, args, nil)
+		if groups {
+			for _, name := range mapKeys(cfg.Groups) {
+				completions = append(completions, fmt.Sprintf("%s%s\tgroup: %s", config.GroupPrefix, name, strings.Join(cfg.Groups[name], ", ")))
+			}
+		}
+		return completions, cobra.ShellCompDirectiveNoFileComp
+	}
+}
+
+// completeInstancesOf completes the one instance argument with the
+// instances of the given catalog services
+func completeInstancesOf(serviceTypes ...string) completionFunc {
+	return func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) > 0 {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+		cfg := completionConfig()
+		if cfg == nil {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+		return instanceCompletions(cfg, nil, serviceTypes), cobra.ShellCompDirectiveNoFileComp
+	}
+}
+
+// instanceCompletions returns the instances not in exclude, of the given
+// services (nil = all), sorted
+func instanceCompletions(cfg *types.Config, exclude, serviceTypes []string) []string {
+	var completions []string
+	for _, name := range mapKeys(cfg.Instances) {
+		instance := cfg.Instances[name]
+		if slices.Contains(exclude, name) {
+			continue
+		}
+		if serviceTypes != nil && !slices.Contains(serviceTypes, instance.ServiceType) {
+			continue
+		}
+		description := instance.ServiceType
+		if instance.Version != "" {
+			description += " " + instance.Version
+		}
+		if instance.Status != "" {
+			description += ", " + string(instance.Status)
+		}
+		completions = append(completions, name+"\t"+description)
+	}
+	return completions
+}
+
+// completeCatalogServices completes the one catalog service argument,
+// described by its versions. With versions, "service:" completes to the
+// versions of the service.
+func completeCatalogServices(versions bool) completionFunc {
+	return func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) > 0 {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+		cfgMgr, err := config.New()
+		if err != nil || !cfgMgr.IsInitialized() {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+		services, err := catalog.NewManager(cfgMgr.GetCatalogDir()).ListServices()
+		if err != nil {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+
+		if name, _, found := strings.Cut(toComplete, ":"); found && versions {
+			for _, svc := range services {
+				if svc.Name != name {
+					continue
+				}
+				var completions []string
+				for _, version := range catalog.SortedVersions(svc) {
+					completions = append(completions, name+":"+version)
+				}
+				return completions, cobra.ShellCompDirectiveNoFileComp
+			}
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+
+		completions := make([]string, 0, len(services))
+		for _, svc := range services {
+			description := svc.Description
+			if versions := catalog.SortedVersions(svc); len(versions) > 0 {
+				description = fmt.Sprintf("%s (%s)", description, strings.Join(versions, ", "))
+			}
+			completions = append(completions, svc.Name+"\t"+description)
+		}
+		sort.Strings(completions)
+		return completions, cobra.ShellCompDirectiveNoFileComp
+	}
+}
+
+// completeProjects completes registered projects, up to max arguments
+// (0 = any)
+func completeProjects(max int) completionFunc {
+	return func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if max > 0 && len(args) >= max {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+		return completeNames(func(cfg *types.Config) []string {
+			var names []string
+			for _, name := range mapKeys(cfg.Projects) {
+				if !slices.Contains(args, name) {
+					names = append(names, name)
+				}
+			}
+			return names
+		})(cmd, nil, toComplete)
+	}
+}
+
+// completeLink completes 'doku link <project> <service>...'
+func completeLink(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) == 0 {
+		return completeProjects(1)(cmd, args, toComplete)
+	}
+	return completeInstances(0, false)(cmd, args, toComplete)
+}
+
+// completeDocs completes 'doku docs <service | project>'
+func completeDocs(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) > 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	instances, _ := completeInstances(1, false)(cmd, args, toComplete)
+	projects, _ := completeProjects(1)(cmd, args, toComplete)
+	return append(instances, projects...), cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeNames completes the one argument with names read from the config
+func completeNames(names func(cfg *types.Config) []string) completionFunc {
+	return func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) > 0 {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+		cfg := completionConfig()
+		if cfg == nil {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+		completions := names(cfg)
+		sort.Strings(completions)
+		return completions, cobra.ShellCompDirectiveNoFileComp
+	}
+}
+
+// mapKeys returns the keys of a map, sorted
+func mapKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,175 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+
+	"github.com/AlecAivazis/survey/v2"
+	"github.com/dokulabs/doku-cli/internal/completion"
+	"github.com/dokulabs/doku-cli/internal/config"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+var (
+	completionShell  string
+	completionYes    bool
+	completionDryRun bool
+)
+
+var completionInstallCmd = &cobra.Command{
+	Use:   "install",
+	Short: "Install shell completion for your shell",
+	Long: `Install the completion script for your shell and load it from your
+shell profile, so there is nothing to copy by hand.
+
+The shell is detected from $SHELL (PowerShell on Windows). The files that
+will change are shown before anything is written. Undo with
+'doku completion uninstall'.
+
+Examples:
+  doku completion install              # Detect the shell
+  doku completion install --shell zsh
+  doku completion install --dry-run    # Only show what would change`,
+	Args: cobra.NoArgs,
+	RunE: runCompletionInstall,
+}
+
+var completionUninstallCmd = &cobra.Command{
+	Use:   "uninstall",
+	Short: "Remove shell completion installed by 'doku completion install'",
+	Args:  cobra.NoArgs,
+	RunE:  runCompletionUninstall,
+}
+
+// addCompletionInstall adds install and uninstall to cobra's completion
+// command, which cobra only creates once all commands are registered
+func addCompletionInstall() {
+	rootCmd.InitDefaultCompletionCmd()
+
+	for _, cmd := range rootCmd.Commands() {
+		if cmd.Name() != "completion" {
+			continue
+		}
+		cmd.AddCommand(completionInstallCmd)
+		cmd.AddCommand(completionUninstallCmd)
+
+		for _, sub := range []*cobra.Command{completionInstallCmd, completionUninstallCmd} {
+			sub.Flags().StringVar(&completionShell, "shell", "", "Shell to use: bash, zsh, fish or powershell (default: detected)")
+		}
+		completionInstallCmd.Flags().BoolVarP(&completionYes, "yes", "y", false, "Skip confirmation prompt")
+		completionInstallCmd.Flags().BoolVar(&completionDryRun, "dry-run", false, "Show what would change without writing anything")
+		return
+	}
+}
+
+// completionPlan returns the installation plan for --shell or the detected shell
+func completionPlan() (*completion.Plan, error) {
+	shell, err := completion.DetectShell()
+	if completionShell != "" {
+		shell, err = completion.ParseShell(completionShell)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get home directory: %w", err)
+	}
+	cfgMgr, err := config.New()
+	if err != nil {
+		return nil, err
+	}
+
+	return completion.NewPlan(shell, home, cfgMgr.GetDokuDir())
+}
+
+func runCompletionInstall(cmd *cobra.Command, args []string) error {
+	plan, err := completionPlan()
+	if err != nil {
+		return err
+	}
+
+	var script bytes.Buffer
+	switch plan.Shell {
+	case completion.Bash:
+		err = rootCmd.GenBashCompletionV2(&script, true)
+	case completion.Zsh:
+		err = rootCmd.GenZshCompletion(&script)
+	case completion.Fish:
+		err = rootCmd.GenFishCompletion(&script, true)
+	case completion.PowerShell:
+		err = rootCmd.GenPowerShellCompletionWithDesc(&script)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to generate %s completion: %w", plan.Shell, err)
+	}
+
+	// Preview
+	fmt.Printf("Installing %s completion:\n", plan.Shell)
+	fmt.Println()
+	fmt.Printf("  • Write %s\n", plan.ScriptPath)
+	if plan.ProfilePath != "" {
+		fmt.Printf("  • Add to %s:\n", plan.ProfilePath)
+		for _, line := range plan.ProfileLines {
+			color.New(color.Faint).Printf("      %s\n", line)
+		}
+	}
+	fmt.Println()
+
+	if completionDryRun {
+		color.Cyan("Dry run complete. No changes were made.")
+		return nil
+	}
+
+	if !completionYes {
+		confirm := false
+		prompt := &survey.Confirm{
+			Message: "Install completion?",
+			Default: true,
+		}
+		if err := survey.AskOne(prompt, &confirm); err != nil {
+			return err
+		}
+		if !confirm {
+			color.Yellow("Installation cancelled")
+			return nil
+		}
+	}
+
+	if err := plan.Install(script.Bytes()); err != nil {
+		return err
+	}
+
+	color.Green("✓ Installed %s completion", plan.Shell)
+	if plan.ProfilePath != "" {
+		fmt.Printf("Open a new shell, or run: source %s\n", plan.ProfilePath)
+	} else {
+		fmt.Println("Open a new shell to use it")
+	}
+	fmt.Println("Undo with: doku completion uninstall")
+
+	return nil
+}
+
+func runCompletionUninstall(cmd *cobra.Command, args []string) error {
+	plan, err := completionPlan()
+	if err != nil {
+		return err
+	}
+
+	removed, err := plan.Uninstall()
+	if err != nil {
+		return err
+	}
+
+	if !removed {
+		color.Yellow("%s completion is not installed", plan.Shell)
+		return nil
+	}
+
+	color.Green("✓ Removed %s completion", plan.Shell)
+	return nil
+}
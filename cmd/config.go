@@ -2,9 +2,14 @@ package cmd
 
 import (
 	"fmt"
+	"strconv"
 	"strings"
 
 	"github.com/dokulabs/doku-cli/internal/config"
+	"github.com/dokulabs/doku-cli/internal/dns"
+	"github.com/dokulabs/doku-cli/internal/docker"
+	"github.com/dokulabs/doku-cli/internal/monitoring"
+	"github.com/dokulabs/doku-cli/internal/proxy"
 	"github.com/dokulabs/doku-cli/pkg/types"
 	"github.com/fatih/color"
 	"github.com/spf13/cobra"
@@ -49,7 +54,18 @@ Use dot notation to access nested values.
 Examples:
   doku config set monitoring.dsn https://...
   doku config set monitoring.enabled true
-  doku config set preferences.domain mydomain.local`,
+  doku config set preferences.domain mydomain.local
+  doku config set preferences.context work   # Name this setup's /etc/hosts section
+  doku config set preferences.skipupdatecheck true  # Don't check for new releases
+  doku config set traefik.httpport 8080      # Serve HTTP on another host port
+  doku config set traefik.httpsport 8443     # Serve HTTPS on another host port
+  doku config set preferences.labels.com.corp.team payments  # Label every service
+  doku config set preferences.labels.com.corp.team ""        # Remove the label
+  doku config set preferences.mirror artifactory.acme.com/docker-remote  # Pull images through a mirror
+  doku config set preferences.mirrors.ghcr.io artifactory.acme.com/ghcr-remote  # Mirror of one registry
+  doku config set preferences.mirrors.quay.io direct         # Pull from quay.io itself
+  doku config set preferences.logs.maxsize 50m               # Rotate container logs at 50MB
+  doku config set preferences.logs.maxfile 5                 # Keep 5 log files per container`,
 	Args: cobra.ExactArgs(2),
 	RunE: runConfigSet,
 }
@@ -89,6 +105,24 @@ func runConfigGet(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to get config: %w", err)
 	}
 
+	// Label keys and registry hosts contain dots, so they can't be looked up by path
+	if labelKey, ok := strings.CutPrefix(key, "preferences.labels."); ok {
+		value, exists := cfg.Preferences.Labels[labelKey]
+		if !exists {
+			return fmt.Errorf("key not found: %s", key)
+		}
+		fmt.Println(value)
+		return nil
+	}
+	if registry, ok := strings.CutPrefix(key, "preferences.mirrors."); ok {
+		value, exists := cfg.Preferences.Mirrors[docker.NormalizeRegistry(registry)]
+		if !exists {
+			return fmt.Errorf("key not found: %s", key)
+		}
+		fmt.Println(value)
+		return nil
+	}
+
 	// Get value by key
 	value, err := getConfigValue(cfg, key)
 	if err != nil {
@@ -122,6 +156,9 @@ func runConfigSet(cmd *cobra.Command, args []string) error {
 	}
 
 	color.Green("✓ Configuration updated: %s = %s", key, value)
+	if strings.HasPrefix(key, "preferences.logs.") {
+		fmt.Println("Containers get it when created or recreated, e.g. with 'doku restart <service> --recreate'")
+	}
 	return nil
 }
 
@@ -196,6 +233,13 @@ func getConfigValue(cfg interface{}, key string) (string, error) {
 func setConfigValue(cfgMgr *config.Manager, key, value string) error {
 	parts := strings.Split(key, ".")
 
+	if labelKey, ok := strings.CutPrefix(key, "preferences.labels."); ok {
+		return setDefaultLabel(cfgMgr, labelKey, value)
+	}
+	if registry, ok := strings.CutPrefix(key, "preferences.mirrors."); ok {
+		return setRegistryMirror(cfgMgr, registry, value)
+	}
+
 	// Special handling for known keys
 	switch key {
 	case "monitoring.tool":
@@ -215,6 +259,73 @@ func setConfigValue(cfgMgr *config.Manager, key, value string) error {
 			c.Preferences.Domain = value
 			return nil
 		})
+	case "preferences.context":
+		return setDNSContext(cfgMgr, value)
+	case "preferences.mirror":
+		if value == docker.MirrorDirect {
+			return fmt.Errorf("%s is only for preferences.mirrors.<registry>; use \"\" to pull without a mirror", value)
+		}
+		if err := docker.ValidateMirror(value); err != nil {
+			return err
+		}
+		return cfgMgr.Update(func(c *types.Config) error {
+			c.Preferences.Mirror = value
+			return nil
+		})
+	case "traefik.httpport", "traefik.httpsport":
+		return setTraefikPort(cfgMgr, key, value)
+	case "preferences.bindip":
+		return setBindIP(cfgMgr, value)
+	case "preferences.proxy":
+		return fmt.Errorf("the proxy is chosen at setup: run 'doku init --proxy %s'", value)
+	case "limits.memory", "limits.cpu":
+		return fmt.Errorf("the default limits are applied to the services when set: run 'doku limits defaults --%s %s'", strings.TrimPrefix(key, "limits."), value)
+	case "limits.disabled":
+		return fmt.Errorf("limits are lifted and restored on the services: run 'doku limits disable' or 'doku limits enable'")
+	case "preferences.engine":
+		return fmt.Errorf("the container engine is chosen at setup: run 'doku init --engine %s'", value)
+	case "traefik.version":
+		return fmt.Errorf("traefik is recreated to change its version: run 'doku traefik upgrade %s'", value)
+	case "preferences.skipupdatecheck":
+		skip, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("%s must be true or false", key)
+		}
+		return cfgMgr.Update(func(c *types.Config) error {
+			c.Preferences.SkipUpdateCheck = skip
+			return nil
+		})
+	case "preferences.logs.maxsize":
+		if err := monitoring.ValidateLogRotation(types.LogRotation{MaxSize: value}); err != nil {
+			return err
+		}
+		return cfgMgr.Update(func(c *types.Config) error {
+			c.Preferences.Logs.MaxSize = value
+			return nil
+		})
+	case "preferences.logs.maxfile":
+		files, err := strconv.Atoi(value)
+		if err != nil || files < 1 {
+			return fmt.Errorf("%s must be a number of files, at least 1", key)
+		}
+		return cfgMgr.Update(func(c *types.Config) error {
+			c.Preferences.Logs.MaxFile = files
+			return nil
+		})
+	case "certificates.renewdays":
+		// "off" is easier to type than a negative number, which looks like
+		// a flag
+		if value == "off" {
+			value = "-1"
+		}
+		days, err := strconv.Atoi(value)
+		if err != nil {
+			return fmt.Errorf("%s must be a number of days, or off to not renew automatically", key)
+		}
+		return cfgMgr.Update(func(c *types.Config) error {
+			c.Certificates.RenewDays = days
+			return nil
+		})
 	case "preferences.protocol":
 		if value != "http" && value != "https" {
 			return fmt.Errorf("protocol must be 'http' or 'https'")
@@ -231,6 +342,205 @@ func setConfigValue(cfgMgr *config.Manager, key, value string) error {
 	}
 }
 
+// setDefaultLabel sets a label added to every service installed from now
+// on. An empty value removes it.
+func setDefaultLabel(cfgMgr *config.Manager, key, value string) error {
+	if err := docker.ValidateExtraLabel(key); err != nil {
+		return err
+	}
+
+	return cfgMgr.Update(func(c *types.Config) error {
+		if value == "" {
+			delete(c.Preferences.Labels, key)
+			return nil
+		}
+		if c.Preferences.Labels == nil {
+			c.Preferences.Labels = make(map[string]string)
+		}
+		c.Preferences.Labels[key] = value
+		return nil
+	})
+}
+
+// setRegistryMirror sets the mirror a registry's images are pulled from,
+// overriding preferences.mirror. An empty value removes the override.
+func setRegistryMirror(cfgMgr *config.Manager, registry, value string) error {
+	if err := docker.ValidateMirror(value); err != nil {
+		return err
+	}
+	registry = docker.NormalizeRegistry(registry)
+
+	return cfgMgr.Update(func(c *types.Config) error {
+		if value == "" {
+			delete(c.Preferences.Mirrors, registry)
+			return nil
+		}
+		if c.Preferences.Mirrors == nil {
+			c.Preferences.Mirrors = make(map[string]string)
+		}
+		c.Preferences.Mirrors[registry] = value
+		return nil
+	})
+}
+
+// setDNSContext renames the context and moves the existing hosts file
+// entries to the new context's section
+func setDNSContext(cfgMgr *config.Manager, value string) error {
+	if err := dns.ValidateContext(value); err != nil {
+		return err
+	}
+
+	cfg, err := cfgMgr.Get()
+	if err != nil {
+		return err
+	}
+
+	if cfg.Preferences.DNSSetup == "hosts" {
+		dnsMgr := dns.NewManagerForContext(cfg.Preferences.Context)
+		if err := dnsMgr.RenameContext(value); err != nil {
+			return fmt.Errorf("failed to move hosts file entries: %w", err)
+		}
+	}
+
+	return cfgMgr.Update(func(c *types.Config) error {
+		c.Preferences.Context = value
+		return nil
+	})
+}
+
+// setTraefikPort changes the host port of one of Traefik's entrypoints. The
+// URLs stored for services and projects are updated to the new port, and
+// Traefik is recreated, since a container's port bindings can't change.
+func setTraefikPort(cfgMgr *config.Manager, key, value string) error {
+	port, err := strconv.Atoi(value)
+	if err != nil {
+		return fmt.Errorf("invalid port: %s", value)
+	}
+
+	httpPort, httpsPort := cfgMgr.GetTraefikPorts()
+	if key == "traefik.httpport" {
+		httpPort = port
+	} else {
+		httpsPort = port
+	}
+	if err := cfgMgr.SetTraefikPorts(httpPort, httpsPort); err != nil {
+		return err
+	}
+
+	if err := cfgMgr.Update(func(c *types.Config) error {
+		c.Traefik.DashboardURL = config.WithTraefikPorts(c.Traefik.DashboardURL, httpPort, httpsPort)
+		c.Monitoring.URL = config.WithTraefikPorts(c.Monitoring.URL, httpPort, httpsPort)
+		for _, instance := range c.Instances {
+			if instance.ConnectionString == instance.URL {
+				instance.ConnectionString = config.WithTraefikPorts(instance.ConnectionString, httpPort, httpsPort)
+			}
+			instance.URL = config.WithTraefikPorts(instance.URL, httpPort, httpsPort)
+		}
+		for _, project := range c.Projects {
+			project.URL = config.WithTraefikPorts(project.URL, httpPort, httpsPort)
+		}
+		return nil
+	}); err != nil {
+		return fmt.Errorf("failed to update URLs: %w", err)
+	}
+
+	return recreateProxy(cfgMgr, "with the new ports")
+}
+
+// setBindIP changes the address the hostnames resolve to and ports are
+// published on. The hosts file entries or dnsmasq, and the proxy, move to
+// it; containers of services with published ports do when recreated.
+func setBindIP(cfgMgr *config.Manager, value string) error {
+	if err := config.ValidateBindIP(value); err != nil {
+		return err
+	}
+	if err := cfgMgr.Update(func(c *types.Config) error {
+		c.Preferences.BindIP = value
+		return nil
+	}); err != nil {
+		return err
+	}
+	cfg, err := cfgMgr.Get()
+	if err != nil {
+		return err
+	}
+	warnUnassignedBindIP(value)
+
+	switch cfg.Preferences.DNSSetup {
+	case "hosts":
+		dnsMgr := dns.NewManagerForContext(cfg.Preferences.Context).SetIP(config.HostsIP(cfg.Preferences))
+		if err := dnsMgr.Readdress(); err != nil {
+			return fmt.Errorf("failed to update hosts file entries: %w", err)
+		}
+	case "dnsmasq":
+		dockerClient, err := initDockerClient()
+		if err != nil {
+			return err
+		}
+		defer dockerClient.Close()
+		if err := setupWildcardDNS(dockerClient, cfg); err != nil {
+			return err
+		}
+	}
+
+	if err := recreateProxy(cfgMgr, "on "+config.PublishIP(cfg.Preferences)); err != nil {
+		return err
+	}
+
+	var published []string
+	for _, name := range mapKeys(cfg.Instances) {
+		if instance := cfg.Instances[name]; len(instance.Network.PortMappings) > 0 || instance.Network.HostPort > 0 {
+			published = append(published, name)
+		}
+	}
+	if len(published) > 0 {
+		color.Yellow("⚠️  %s keep their ports on the old address until recreated, e.g. with 'doku restart %s --recreate'", strings.Join(published, ", "), published[0])
+	}
+	return nil
+}
+
+// recreateProxy replaces the proxy's container with one using the current
+// configuration, saying why, e.g. "with the new ports". It only rewrites
+// the configuration if the proxy isn't installed.
+func recreateProxy(cfgMgr *config.Manager, reason string) error {
+	cfg, err := cfgMgr.Get()
+	if err != nil {
+		return err
+	}
+
+	dockerClient, err := initDockerClient()
+	if err != nil {
+		return err
+	}
+	defer dockerClient.Close()
+
+	reverseProxy := proxy.New(dockerClient, cfgMgr, cfg)
+
+	exists, err := dockerClient.ContainerExists(reverseProxy.ContainerName())
+	if err != nil {
+		return fmt.Errorf("failed to check %s container: %w", reverseProxy.Name(), err)
+	}
+	if !exists {
+		return reverseProxy.WriteConfig()
+	}
+
+	fmt.Printf("Recreating %s %s...\n", reverseProxy.Name(), reason)
+	networkMgr := docker.NewNetworkManager(dockerClient)
+	networkMgr.DisconnectContainer(cfg.Network.Name, reverseProxy.ContainerName(), true)
+
+	if err := reverseProxy.RemoveContainer(); err != nil {
+		return fmt.Errorf("failed to remove %s: %w", reverseProxy.Name(), err)
+	}
+	if err := reverseProxy.Setup(); err != nil {
+		return fmt.Errorf("failed to set up %s: %w", reverseProxy.Name(), err)
+	}
+	if err := networkMgr.ConnectContainer(cfg.Network.Name, reverseProxy.ContainerName()); err != nil {
+		return fmt.Errorf("failed to connect %s to network: %w", reverseProxy.Name(), err)
+	}
+
+	return nil
+}
+
 // setNestedValue sets a nested value in a struct
 func setNestedValue(obj interface{}, parts []string, value string) error {
 	if len(parts) == 0 {
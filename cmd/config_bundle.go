@@ -0,0 +1,366 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/AlecAivazis/survey/v2"
+	"github.com/dokulabs/doku-cli/internal/catalog"
+	"github.com/dokulabs/doku-cli/internal/certs"
+	"github.com/dokulabs/doku-cli/internal/config"
+	"github.com/dokulabs/doku-cli/internal/docker"
+	"github.com/dokulabs/doku-cli/internal/domain"
+	"github.com/dokulabs/doku-cli/internal/migration"
+	"github.com/dokulabs/doku-cli/internal/proxy"
+	"github.com/dokulabs/doku-cli/internal/service"
+	"github.com/dokulabs/doku-cli/internal/workspace"
+	"github.com/dokulabs/doku-cli/pkg/types"
+	"github.com/fatih/color"
+)
+
+// isBundlePath reports whether a path names a bundle for moving Doku to
+// another machine, rather than a YAML or JSON export
+func isBundlePath(path string) bool {
+	return strings.HasSuffix(path, ".tar.gz") || strings.HasSuffix(path, ".tgz")
+}
+
+// runConfigExportBundle writes the configuration, env files and profiles
+// to a bundle
+func runConfigExportBundle(path string) error {
+	cfgMgr, err := config.New()
+	if err != nil {
+		return fmt.Errorf("failed to create config manager: %w", err)
+	}
+	if !cfgMgr.IsInitialized() {
+		color.Yellow("Doku is not initialized. Run 'doku init' first.")
+		return nil
+	}
+
+	cfg, err := cfgMgr.Get()
+	if err != nil {
+		return fmt.Errorf("failed to get config: %w", err)
+	}
+
+	manifest, err := migration.Export(migration.ExportOptions{
+		DokuDir:     cfgMgr.GetDokuDir(),
+		OutputPath:  path,
+		DokuVersion: version,
+		Workspace:   workspace.Current(),
+	})
+	if err != nil {
+		return err
+	}
+
+	color.Green("✓ Bundle written to %s", path)
+	fmt.Println()
+	fmt.Println("Included:")
+	fmt.Printf("  • %d file(s): configuration, env files and profiles\n", len(manifest.Files))
+	for _, cert := range manifest.Certificates {
+		fmt.Printf("  • Certificate details for %s (regenerated on import)\n", strings.Join(cert.Domains, ", "))
+	}
+	fmt.Println()
+	color.Yellow("⚠️  The bundle contains the services' passwords: keep it private.")
+	if cfg.Preferences.EncryptSecrets {
+		fmt.Println("Its values are encrypted: set DOKU_SECRET_KEY on the other machine to the key")
+		fmt.Println("printed by 'doku config encrypt --print-key'.")
+	}
+	fmt.Printf("Restore it on the other machine with: doku config import %s --recreate\n", filepath.Base(path))
+	return nil
+}
+
+// runConfigImportBundle restores a bundle, and with --recreate sets up the
+// network, certificates and Traefik and recreates the services
+func runConfigImportBundle(path string) error {
+	manifest, err := migration.ReadManifest(path)
+	if err != nil {
+		return err
+	}
+	if manifest.Workspace != workspace.Current() {
+		from := manifest.Workspace
+		if from == "" {
+			from = "default"
+		}
+		return fmt.Errorf("the bundle is of workspace '%s'. Import it with: doku --workspace %s config import %s",
+			from, from, path)
+	}
+
+	cfgMgr, err := config.New()
+	if err != nil {
+		return fmt.Errorf("failed to create config manager: %w", err)
+	}
+	exists := cfgMgr.IsInitialized()
+	if exists && !importOverwrite {
+		return fmt.Errorf("Doku is already set up in %s. Replace its configuration with --overwrite", cfgMgr.GetDokuDir())
+	}
+
+	fmt.Println()
+	color.Cyan("Bundle Import")
+	fmt.Println()
+	fmt.Printf("Source: %s\n", path)
+	fmt.Printf("Exported at: %s", manifest.CreatedAt.Format("2006-01-02 15:04:05"))
+	if manifest.DokuVersion != "" {
+		fmt.Printf(" by Doku %s", manifest.DokuVersion)
+	}
+	fmt.Println()
+	fmt.Printf("Files: %d (configuration, env files and profiles)\n", len(manifest.Files))
+	for _, cert := range manifest.Certificates {
+		fmt.Printf("Certificate: %s (regenerated)\n", strings.Join(cert.Domains, ", "))
+	}
+	if exists {
+		color.Yellow("The configuration in %s will be replaced", cfgMgr.GetDokuDir())
+	}
+	fmt.Println()
+
+	if importDryRun {
+		color.Cyan("Dry run complete. No changes were made.")
+		return nil
+	}
+
+	if !importYes {
+		confirm := false
+		prompt := &survey.Confirm{
+			Message: "Import the bundle?",
+			Default: true,
+		}
+		if err := survey.AskOne(prompt, &confirm); err != nil {
+			return err
+		}
+		if !confirm {
+			color.Yellow("Import cancelled")
+			return nil
+		}
+		fmt.Println()
+	}
+
+	if exists {
+		configPath := filepath.Join(cfgMgr.GetDokuDir(), config.ConfigFileName)
+		if data, err := os.ReadFile(configPath); err == nil {
+			if err := os.WriteFile(configPath+".before-import", data, 0600); err != nil {
+				return fmt.Errorf("failed to back up the configuration: %w", err)
+			}
+		}
+	}
+
+	if _, err := migration.Import(path, cfgMgr.GetDokuDir()); err != nil {
+		return err
+	}
+
+	// Read the imported configuration, and point its machine-specific
+	// paths at this machine
+	cfgMgr, err = config.New()
+	if err != nil {
+		return fmt.Errorf("failed to create config manager: %w", err)
+	}
+	if err := cfgMgr.Initialize(); err != nil {
+		return err
+	}
+	if err := cfgMgr.Update(func(c *types.Config) error {
+		switch certs.Source(c.Certificates) {
+		case certs.SourceCA:
+			// The bundle leaves the CA out, like the certificates
+			c.Certificates.CACert = filepath.Join(cfgMgr.GetCADir(), "ca.pem")
+			c.Certificates.CAKey = filepath.Join(cfgMgr.GetCADir(), "ca-key.pem")
+		case certs.SourceMkcert:
+			c.Certificates.CACert = filepath.Join(cfgMgr.GetCertsDir(), "rootCA.pem")
+			c.Certificates.CAKey = filepath.Join(cfgMgr.GetCertsDir(), "rootCA-key.pem")
+		}
+		c.Certificates.CertsDir = cfgMgr.GetCertsDir()
+		c.Traefik.Status = types.StatusUnknown
+		return nil
+	}); err != nil {
+		return fmt.Errorf("failed to update the imported configuration: %w", err)
+	}
+	cfg, err := cfgMgr.Get()
+	if err != nil {
+		return fmt.Errorf("failed to get config: %w", err)
+	}
+
+	color.Green("✓ Imported %d file(s) into %s", len(manifest.Files), cfgMgr.GetDokuDir())
+	fmt.Println()
+
+	if !importRecreate {
+		color.Cyan("Next steps:")
+		fmt.Println("  Set up Traefik and recreate the services:")
+		fmt.Printf("    doku config import %s --recreate --overwrite\n", path)
+		if cfg.Preferences.DNSSetup == "hosts" {
+			fmt.Println("  Restore the hosts file entries:")
+			fmt.Println("    doku doctor --fix")
+		}
+		fmt.Println()
+		return nil
+	}
+
+	return recreateImportedSetup(cfgMgr, cfg)
+}
+
+// recreateImportedSetup sets up an imported configuration like doku init
+// does, then pulls the services' images and creates their containers. Their
+// env files are those of the bundle, so passwords are kept.
+func recreateImportedSetup(cfgMgr *config.Manager, cfg *types.Config) error {
+	dockerClient, err := initDockerClient()
+	if err != nil {
+		return err
+	}
+	defer dockerClient.Close()
+
+	mainDomain, protocol := cfg.Preferences.Domain, cfg.Preferences.Protocol
+
+	// Caddy issues the certificates from its own CA
+	if protocol == "https" && proxy.Backend(cfg.Preferences) == proxy.Traefik {
+		color.Cyan("Generating certificates for %s...", mainDomain)
+		certMgr := certs.NewManager(cfgMgr.GetCertsDir(), mainDomain).Configure(cfg.Certificates)
+		switch certs.Source(cfg.Certificates) {
+		case certs.SourceMkcert:
+			if !certMgr.IsMkcertInstalled() {
+				return fmt.Errorf("mkcert is not installed; it is needed to generate certificates for %s. Install it and run the import again", mainDomain)
+			}
+			if err := certMgr.InstallCA(); err != nil {
+				return fmt.Errorf("failed to install CA: %w", err)
+			}
+		case certs.SourceCA:
+			if _, _, err := certs.LoadCA(cfg.Certificates.CACert, cfg.Certificates.CAKey); err != nil {
+				return fmt.Errorf("the setup issues its certificates from a CA, which bundles leave out: %w. Import it with 'doku init --ca-cert <cert> --ca-key <key>'", err)
+			}
+		}
+		if err := certMgr.GenerateCertificates(); err != nil {
+			return fmt.Errorf("failed to generate certificates: %w", err)
+		}
+		if err := ensureCertificates(cfgMgr, dockerClient, cfg, extraCertificateDomains(cfg)); err != nil {
+			return err
+		}
+		for _, name := range mapKeys(cfg.Instances) {
+			instance := cfg.Instances[name]
+			if len(instance.TLSSANs) == 0 {
+				continue
+			}
+			if err := issueServiceCertificate(cfgMgr, cfg, domain.InstanceSubdomain(instance), instance.TLSSANs); err != nil {
+				return err
+			}
+		}
+		color.Green("✓ Certificates generated")
+	}
+
+	networkMgr := docker.NewNetworkManager(dockerClient)
+	if err := networkMgr.EnsureDokuNetwork(docker.NetworkName(), cfg.Network.Subnet, cfg.Network.Gateway); err != nil {
+		return fmt.Errorf("failed to create network: %w", err)
+	}
+	color.Green("✓ Network %s ready", docker.NetworkName())
+
+	// Traefik only gets the hash of the dashboard's password, which isn't
+	// in the bundle
+	if auth := cfg.Traefik.DashboardAuth; auth != nil && proxy.Backend(cfg.Preferences) == proxy.Traefik {
+		if err := dashboardTraefik(cfgMgr, cfg).SetDashboardAuth(auth.User, auth.Password); err != nil {
+			return err
+		}
+	}
+
+	reverseProxy := proxy.New(dockerClient, cfgMgr, cfg)
+	if exists, err := dockerClient.ContainerExists(reverseProxy.ContainerName()); err != nil {
+		return fmt.Errorf("failed to check %s container: %w", reverseProxy.Name(), err)
+	} else if !exists {
+		if err := reverseProxy.Setup(); err != nil {
+			return fmt.Errorf("failed to setup %s: %w", reverseProxy.Name(), err)
+		}
+		if err := networkMgr.ConnectContainer(docker.NetworkName(), reverseProxy.ContainerName()); err != nil {
+			return fmt.Errorf("failed to connect %s to network: %w", reverseProxy.Name(), err)
+		}
+	}
+	if err := cfgMgr.Update(func(c *types.Config) error {
+		c.Traefik.DashboardURL = reverseProxy.GetDashboardURL()
+		c.Traefik.Status = types.StatusRunning
+		return nil
+	}); err != nil {
+		return fmt.Errorf("failed to update %s status: %w", reverseProxy.Name(), err)
+	}
+	color.Green("✓ %s running", reverseProxy.Name())
+
+	if cfg.Preferences.DNSSetup == "dnsmasq" {
+		if err := setupWildcardDNS(dockerClient, cfg); err != nil {
+			return err
+		}
+		color.Green("✓ *.%s resolves through dnsmasq", mainDomain)
+	}
+
+	catalogMgr := catalog.NewManager(cfgMgr.GetCatalogDir())
+	if !catalogMgr.CatalogExists() {
+		color.Cyan("Downloading service catalog...")
+		if err := catalogMgr.FetchCatalog(); err != nil {
+			return fmt.Errorf("failed to download the catalog: %w", err)
+		}
+		if catalogVersion, err := catalogMgr.GetCatalogVersion(); err == nil && catalogVersion != "" {
+			cfgMgr.UpdateCatalogVersion(catalogVersion)
+		}
+	}
+	fmt.Println()
+
+	installer, err := service.NewInstaller(dockerClient, cfgMgr, catalogMgr)
+	if err != nil {
+		return fmt.Errorf("failed to create installer: %w", err)
+	}
+
+	var failed []string
+	for _, name := range importOrder(cfg.Instances) {
+		instance := cfg.Instances[name]
+		if instance.ServiceType == "custom-project" {
+			continue
+		}
+
+		color.Cyan("Recreating %s...", name)
+		// The record is replaced by the installation, which keeps the
+		// restored env file
+		if err := cfgMgr.RemoveInstance(name); err != nil {
+			return err
+		}
+		_, err := installer.Install(service.InstallOptions{
+			ServiceName:       instance.ServiceType,
+			Version:           instance.Version,
+			InstanceName:      name,
+			MemoryLimit:       instance.Resources.MemoryLimit,
+			CPULimit:          instance.Resources.CPULimit,
+			PortMappings:      instance.Network.PortMappings,
+			Internal:          !instance.Traefik.Enabled,
+			HostNetwork:       instance.UsesHostNetwork(),
+			Labels:            instance.Labels,
+			Logs:              instance.Logs,
+			Middlewares:       instance.Middlewares,
+			Domains:           instance.Domains,
+			TLSSANs:           instance.TLSSANs,
+			SkipDependencies:  true,
+			ReuseExistingData: true,
+		})
+		if err != nil {
+			color.Yellow("⚠️  %s: %v", name, err)
+			failed = append(failed, name)
+			// Keep the record, to retry later
+			cfgMgr.AddInstance(instance)
+			continue
+		}
+		color.Green("✓ %s", name)
+	}
+	fmt.Println()
+
+	color.Green("✓ Doku is set up from the bundle")
+	if len(failed) > 0 {
+		color.Yellow("Some services could not be recreated: %s. Retry with 'doku install' after fixing the problem.", strings.Join(failed, ", "))
+	}
+	if len(cfg.Projects) > 0 {
+		fmt.Println("Projects are built from their source: once it is on this machine, run them with 'doku project run <name>'.")
+	}
+	if cfg.Preferences.DNSSetup == "hosts" {
+		fmt.Println("Restore the hosts file entries with: doku doctor --fix")
+	}
+	return nil
+}
+
+// importOrder returns the instances to recreate, those installed as
+// dependencies first
+func importOrder(instances map[string]*types.Instance) []string {
+	names := mapKeys(instances)
+	sort.SliceStable(names, func(i, j int) bool {
+		return len(instances[names[i]].DependencyOf) > 0 && len(instances[names[j]].DependencyOf) == 0
+	})
+	return names
+}
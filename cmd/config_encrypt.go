@@ -0,0 +1,159 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/dokulabs/doku-cli/internal/config"
+	"github.com/dokulabs/doku-cli/internal/envfile"
+	"github.com/dokulabs/doku-cli/internal/secrets"
+	"github.com/dokulabs/doku-cli/pkg/types"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+var encryptPrintKey bool
+
+var configEncryptCmd = &cobra.Command{
+	Use:   "encrypt",
+	Short: "Encrypt env files and secrets at rest",
+	Long: `Turn on encryption at rest: the values of the env files, and the
+sensitive fields of the configuration (the services' environment and
+connection strings, the monitoring credentials and webhook URLs), are
+encrypted on disk with a key kept in the OS keychain. They are decrypted
+in memory only, when Doku reads them, e.g. to create a container.
+
+Existing plaintext is encrypted, the env files' backups included. The key
+is created on first use. On machines without a keychain, such as CI
+runners, set it in DOKU_SECRET_KEY instead.
+
+--print-key prints the key, to set DOKU_SECRET_KEY on another machine,
+e.g. to read a bundle exported with encryption on.
+
+Examples:
+  doku config encrypt               # Encrypt existing plaintext
+  doku config encrypt --print-key   # Print the key
+  doku config decrypt               # Turn encryption off`,
+	Args: cobra.NoArgs,
+	RunE: runConfigEncrypt,
+}
+
+var configDecryptCmd = &cobra.Command{
+	Use:   "decrypt",
+	Short: "Turn encryption at rest off",
+	Long: `Turn encryption at rest off, writing the env files and the
+configuration in plaintext again. The key is kept in the OS keychain.
+
+Examples:
+  doku config decrypt`,
+	Args: cobra.NoArgs,
+	RunE: runConfigDecrypt,
+}
+
+func init() {
+	configCmd.AddCommand(configEncryptCmd)
+	configCmd.AddCommand(configDecryptCmd)
+
+	configEncryptCmd.Flags().BoolVar(&encryptPrintKey, "print-key", false, "Print the encryption key instead")
+}
+
+func runConfigEncrypt(cmd *cobra.Command, args []string) error {
+	if encryptPrintKey {
+		key, err := secrets.ExportKey()
+		if err != nil {
+			return fmt.Errorf("%w: turn encryption on with 'doku config encrypt'", err)
+		}
+		fmt.Println(key)
+		return nil
+	}
+
+	cfgMgr, err := initConfigManager()
+	if err != nil {
+		if err == types.ErrNotInitialized {
+			return nil
+		}
+		return err
+	}
+	// Read the configuration before the key may be created, since it may
+	// hold values encrypted with another one
+	if _, err := cfgMgr.Get(); err != nil {
+		return fmt.Errorf("failed to get config: %w", err)
+	}
+
+	if _, err := secrets.EnsureKey(); err != nil {
+		return err
+	}
+	color.Green("✓ Encryption key ready")
+
+	count, err := setEncryption(cfgMgr, true)
+	if err != nil {
+		return err
+	}
+	color.Green("✓ Configuration encrypted")
+	color.Green("✓ %d env file(s) encrypted, with their backups", count)
+
+	beforeImport := filepath.Join(cfgMgr.GetDokuDir(), config.ConfigFileName+".before-import")
+	if _, err := os.Stat(beforeImport); err == nil {
+		color.Yellow("⚠️  %s holds a plaintext copy of the configuration: delete it once it isn't needed", beforeImport)
+	}
+	fmt.Println()
+	fmt.Println("Values are decrypted in memory only, when Doku reads them. 'doku env edit' is")
+	fmt.Println("off while they are encrypted: use 'doku env set' and 'doku env unset'.")
+	return nil
+}
+
+func runConfigDecrypt(cmd *cobra.Command, args []string) error {
+	cfgMgr, err := initConfigManager()
+	if err != nil {
+		if err == types.ErrNotInitialized {
+			return nil
+		}
+		return err
+	}
+	cfg, err := cfgMgr.Get()
+	if err != nil {
+		return fmt.Errorf("failed to get config: %w", err)
+	}
+	if !cfg.Preferences.EncryptSecrets {
+		color.Yellow("Encryption at rest is not on")
+	}
+
+	count, err := setEncryption(cfgMgr, false)
+	if err != nil {
+		return err
+	}
+	color.Green("✓ Configuration and %d env file(s) written in plaintext", count)
+	return nil
+}
+
+// setEncryption turns encryption at rest on or off, and writes the
+// configuration and the env files again accordingly. It returns the number
+// of env files written.
+func setEncryption(cfgMgr *config.Manager, on bool) (int, error) {
+	if err := cfgMgr.Update(func(c *types.Config) error {
+		c.Preferences.EncryptSecrets = on
+		return nil
+	}); err != nil {
+		return 0, fmt.Errorf("failed to update the configuration: %w", err)
+	}
+
+	envMgr := envfile.NewManager(cfgMgr.GetDokuDir())
+	serviceFiles, err := envMgr.ListServiceEnvFiles()
+	if err != nil {
+		return 0, err
+	}
+	projectFiles, err := envMgr.ListProjectEnvFiles()
+	if err != nil {
+		return 0, err
+	}
+
+	count := 0
+	for _, path := range append(serviceFiles, projectFiles...) {
+		if err := envfile.Reseal(path); err != nil {
+			return count, err
+		}
+		count++
+	}
+	return count, nil
+}
@@ -55,10 +55,15 @@ var (
 )
 
 var configExportCmd = &cobra.Command{
-	Use:   "export",
+	Use:   "export [bundle.tar.gz]",
 	Short: "Export Doku configuration to a file",
 	Long: `Export the current Doku configuration to a file.
 
+Given a .tar.gz path, export writes a bundle to move Doku to another
+machine instead: the configuration, env files and profiles, along with
+the details of the certificates, which are generated again on import.
+The bundle contains the services' passwords.
+
 The exported file can be used to:
   - Backup configuration settings
   - Share configuration with team members
@@ -71,7 +76,9 @@ Examples:
   doku config export                      # Export to stdout (YAML)
   doku config export -o config.yaml       # Export to file
   doku config export --format json        # Export as JSON
-  doku config export --include-env        # Include environment variables`,
+  doku config export --include-env        # Include environment variables
+  doku config export bundle.tar.gz        # Bundle for another machine`,
+	Args: cobra.MaximumNArgs(1),
 	RunE: runConfigExport,
 }
 
@@ -85,6 +92,16 @@ func init() {
 }
 
 func runConfigExport(cmd *cobra.Command, args []string) error {
+	if len(args) > 0 {
+		if !isBundlePath(args[0]) {
+			return fmt.Errorf("a bundle must be a .tar.gz file. Export YAML or JSON with: doku config export -o %s", args[0])
+		}
+		return runConfigExportBundle(args[0])
+	}
+	if isBundlePath(exportOutput) {
+		return runConfigExportBundle(exportOutput)
+	}
+
 	// Create config manager
 	cfgMgr, err := config.New()
 	if err != nil {
@@ -20,6 +20,7 @@ var (
 	importOverwrite bool
 	importDryRun    bool
 	importYes       bool
+	importRecreate  bool
 )
 
 var configImportCmd = &cobra.Command{
@@ -35,11 +36,18 @@ This will:
 Note: This does not recreate containers. Use 'doku install' or 'doku deploy'
 to create services based on the imported configuration.
 
+A .tar.gz bundle written by 'doku config export bundle.tar.gz' restores the
+configuration, env files and profiles of another machine, on a machine
+where Doku isn't set up yet (or with --overwrite). With --recreate, import
+then sets up certificates, the network and Traefik, pulls the services'
+images and recreates their containers, with the same passwords.
+
 Examples:
   doku config import config.yaml           # Import from YAML file
   doku config import config.json           # Import from JSON file
   doku config import config.yaml --dry-run # Preview changes without applying
-  doku config import config.yaml --overwrite # Overwrite existing config`,
+  doku config import config.yaml --overwrite # Overwrite existing config
+  doku config import bundle.tar.gz --recreate # Move Doku from another machine`,
 	Args: cobra.ExactArgs(1),
 	RunE: runConfigImport,
 }
@@ -50,10 +58,14 @@ func init() {
 	configImportCmd.Flags().BoolVar(&importOverwrite, "overwrite", false, "Overwrite existing configuration completely")
 	configImportCmd.Flags().BoolVar(&importDryRun, "dry-run", false, "Preview changes without applying")
 	configImportCmd.Flags().BoolVarP(&importYes, "yes", "y", false, "Skip confirmation prompt")
+	configImportCmd.Flags().BoolVar(&importRecreate, "recreate", false, "Pull images and recreate containers after importing a bundle")
 }
 
 func runConfigImport(cmd *cobra.Command, args []string) error {
 	importFile = args[0]
+	if isBundlePath(importFile) {
+		return runConfigImportBundle(importFile)
+	}
 
 	// Read input file
 	data, err := os.ReadFile(importFile)
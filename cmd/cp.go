@@ -0,0 +1,136 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/dokulabs/doku-cli/internal/service"
+	"github.com/dokulabs/doku-cli/pkg/types"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+var cpContainer string
+
+var cpCmd = &cobra.Command{
+	Use:   "cp <src> <dest>",
+	Short: "Copy files between the host and a service container",
+	Long: `Copy files or directories between the host and a service container.
+
+One of the paths is a service path written as <service>:<path>, the other
+a local path. The same rules as docker cp apply: a destination ending in /
+must be an existing directory, and copying a directory into an existing
+directory puts it inside. Containers don't need to be running.
+
+Examples:
+  doku cp dump.sql postgres:/tmp/                  # Copy a file into postgres
+  doku cp postgres:/var/lib/postgresql/data/pg_hba.conf .
+  doku cp ./config myapp:/app/config               # Copy a directory
+  doku cp -c frontend signoz:/etc/nginx/nginx.conf .`,
+	Args: cobra.ExactArgs(2),
+	RunE: runCp,
+}
+
+func init() {
+	rootCmd.AddCommand(cpCmd)
+
+	cpCmd.Flags().StringVarP(&cpContainer, "container", "c", "", "Container name (for multi-container services)")
+}
+
+func runCp(cmd *cobra.Command, args []string) error {
+	srcInstance, srcPath := splitCopyArg(args[0])
+	dstInstance, dstPath := splitCopyArg(args[1])
+
+	switch {
+	case srcInstance != "" && dstInstance != "":
+		return fmt.Errorf("copying between two services is not supported; copy to the host first")
+	case srcInstance == "" && dstInstance == "":
+		return fmt.Errorf("one of the paths must be a service path, e.g. postgres:/tmp/")
+	case (srcInstance != "" && srcPath == "") || (dstInstance != "" && dstPath == ""):
+		return fmt.Errorf("specify a path inside the container, e.g. postgres:/tmp/")
+	}
+
+	cfgMgr, err := initConfigManager()
+	if err != nil {
+		if err == types.ErrNotInitialized {
+			return nil
+		}
+		return err
+	}
+
+	dockerClient, err := initDockerClient()
+	if err != nil {
+		return err
+	}
+	defer dockerClient.Close()
+
+	serviceMgr := getServiceManager(dockerClient, cfgMgr)
+	ctx := context.Background()
+
+	if srcInstance != "" {
+		containerName, err := copyContainerName(serviceMgr, srcInstance)
+		if err != nil {
+			return err
+		}
+		if err := dockerClient.CopyFromContainer(ctx, containerName, srcPath, dstPath); err != nil {
+			return err
+		}
+		color.Green("✓ Copied %s to %s", args[0], dstPath)
+		return nil
+	}
+
+	containerName, err := copyContainerName(serviceMgr, dstInstance)
+	if err != nil {
+		return err
+	}
+	if err := dockerClient.CopyToContainer(ctx, containerName, srcPath, dstPath); err != nil {
+		return err
+	}
+	color.Green("✓ Copied %s to %s", srcPath, args[1])
+	return nil
+}
+
+// splitCopyArg splits a <service>:<path> argument. Local paths, including
+// absolute Windows paths and relative paths containing a colon such as
+// ./a:b, come back with an empty service.
+func splitCopyArg(arg string) (instance, path string) {
+	if filepath.IsAbs(arg) {
+		return "", arg
+	}
+	instance, path, ok := strings.Cut(arg, ":")
+	if !ok || instance == "" || strings.HasPrefix(instance, ".") {
+		return "", arg
+	}
+	return instance, path
+}
+
+// copyContainerName returns the container to copy from or to for an
+// instance, honouring --container for multi-container services
+func copyContainerName(serviceMgr *service.Manager, name string) (string, error) {
+	if proxyContainer, ok := proxyContainerName(name); ok {
+		return proxyContainer, nil
+	}
+
+	instance, err := serviceMgr.Get(name)
+	if err != nil {
+		return "", fmt.Errorf("service '%s' not found", name)
+	}
+
+	if !instance.IsMultiContainer {
+		return instance.ContainerName, nil
+	}
+
+	var names []string
+	for _, c := range instance.Containers {
+		if c.Name == cpContainer {
+			return c.FullName, nil
+		}
+		names = append(names, c.Name)
+	}
+	if cpContainer == "" {
+		return "", fmt.Errorf("'%s' is a multi-container service; pick one with --container: %s", name, strings.Join(names, ", "))
+	}
+	return "", fmt.Errorf("container '%s' not found in service '%s'", cpContainer, name)
+}
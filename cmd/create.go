@@ -0,0 +1,208 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"text/tabwriter"
+
+	"github.com/dokulabs/doku-cli/internal/config"
+	"github.com/dokulabs/doku-cli/internal/dns"
+	"github.com/dokulabs/doku-cli/internal/docker"
+	"github.com/dokulabs/doku-cli/internal/project"
+	"github.com/dokulabs/doku-cli/internal/scaffold"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+var (
+	createDir      string
+	createPort     int
+	createInternal bool
+	createNoRun    bool
+	createForce    bool
+)
+
+var createCmd = &cobra.Command{
+	Use:   "create [template] [name]",
+	Short: "Create a new project from a template",
+	Long: `Create a new project from a built-in template.
+
+Doku writes a minimal app, a Dockerfile and a doku.yaml to a new directory,
+registers the directory as a project, then builds and starts it at
+https://<name>.<domain>.
+
+Templates:
+  node-express   Node.js API with Express
+  go-api         Go HTTP API using the standard library
+  django         Django app served by Gunicorn
+  rails          Minimal Ruby on Rails app served by Puma
+  static-site    Static HTML site served by nginx
+
+The name defaults to the template name, and the directory to ./<name>.
+
+Examples:
+  doku create                          # List templates
+  doku create node-express api         # Create ./api and run it
+  doku create go-api --dir ~/src/svc   # Choose the directory
+  doku create django blog --port 9000  # Listen on another port
+  doku create static-site --no-run     # Only write and register the project`,
+	Args: cobra.MaximumNArgs(2),
+	RunE: runCreate,
+}
+
+func init() {
+	rootCmd.AddCommand(createCmd)
+
+	createCmd.Flags().StringVar(&createDir, "dir", "", "Directory for the project (default: ./<name>)")
+	createCmd.Flags().IntVarP(&createPort, "port", "p", 0, "Port the app listens on (default: the template's)")
+	createCmd.Flags().BoolVar(&createInternal, "internal", false, "Internal only (no Traefik/HTTPS)")
+	createCmd.Flags().BoolVar(&createNoRun, "no-run", false, "Write and register the project without building or starting it")
+	createCmd.Flags().BoolVar(&createForce, "force", false, "Overwrite files that already exist in the directory")
+}
+
+func runCreate(cmd *cobra.Command, args []string) error {
+	if len(args) == 0 {
+		printCreateTemplates()
+		return nil
+	}
+
+	tmpl, err := scaffold.Get(args[0])
+	if err != nil {
+		return err
+	}
+
+	name := tmpl.Name
+	if len(args) > 1 {
+		name = args[1]
+	} else if createDir != "" {
+		name = filepath.Base(filepath.Clean(createDir))
+	}
+	if err := config.ValidateInstanceName(name); err != nil {
+		return err
+	}
+
+	cfgMgr, err := config.New()
+	if err != nil {
+		return fmt.Errorf("failed to create config manager: %w", err)
+	}
+
+	if !cfgMgr.IsInitialized() {
+		color.Yellow("⚠️  Doku is not initialized. Run 'doku init' first.")
+		return nil
+	}
+
+	if cfgMgr.HasInstance(name) {
+		return fmt.Errorf("'%s' is already installed; choose another name", name)
+	}
+
+	dir := createDir
+	if dir == "" {
+		dir = name
+	}
+	dir, err = filepath.Abs(dir)
+	if err != nil {
+		return fmt.Errorf("invalid directory: %w", err)
+	}
+
+	dockerClient, err := docker.NewClient()
+	if err != nil {
+		return fmt.Errorf("failed to create Docker client: %w", err)
+	}
+	defer dockerClient.Close()
+
+	projectMgr, err := project.NewManager(dockerClient, cfgMgr)
+	if err != nil {
+		return fmt.Errorf("failed to create project manager: %w", err)
+	}
+
+	port := createPort
+	if port == 0 {
+		port = tmpl.Port
+	}
+
+	fmt.Println()
+	color.Cyan("Creating %s from the %s template...", name, tmpl.Name)
+
+	files, err := scaffold.Generate(tmpl, dir, scaffold.Data{Name: name, Port: port}, createForce)
+	if err != nil {
+		return err
+	}
+	for _, file := range files {
+		fmt.Printf("  %s %s\n", color.GreenString("+"), filepath.Join(dir, file))
+	}
+	fmt.Println()
+
+	proj, err := projectMgr.Add(project.AddOptions{
+		ProjectPath: dir,
+		Name:        name,
+		Port:        port,
+		Internal:    createInternal,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to add project: %w", err)
+	}
+	color.Green("✓ Project %s registered", name)
+
+	if createNoRun {
+		fmt.Println()
+		color.Cyan("Next steps:")
+		fmt.Printf("  doku project run %s --build\n", name)
+		fmt.Println()
+		return nil
+	}
+
+	fmt.Println()
+	color.Cyan("Building and starting %s...", name)
+	if err := projectMgr.Run(project.RunOptions{
+		Name:   name,
+		Build:  true,
+		Detach: true,
+	}); err != nil {
+		color.Yellow("⚠️  The project was created but failed to start. Fix the error and run:")
+		fmt.Printf("   doku project run %s --build\n", name)
+		return fmt.Errorf("failed to run %s: %w", name, err)
+	}
+
+	if proj.URL != "" {
+		subdomain := config.URLHost(proj.URL)
+
+		dnsMgr := dns.NewManagerForContext(cfgMgr.GetContext()).SetIP(cfgMgr.GetHostsIP())
+		if err := dnsMgr.AddSingleEntry(dnsMgr.IP(), subdomain); err != nil {
+			color.Yellow("⚠️  Warning: Failed to add DNS entry: %v", err)
+			color.Yellow("   You may need to manually add: %s %s to /etc/hosts", dnsMgr.IP(), subdomain)
+		}
+	}
+
+	fmt.Println()
+	color.Green("✓ %s is running", name)
+	if proj.URL != "" {
+		fmt.Printf("  URL: %s\n", proj.URL)
+	}
+	fmt.Println()
+	color.Cyan("Next steps:")
+	fmt.Printf("  cd %s\n", dir)
+	fmt.Printf("  doku logs %s -f               # Follow the logs\n", name)
+	fmt.Printf("  doku project run %s --build   # Rebuild after editing\n", name)
+	fmt.Println()
+
+	return nil
+}
+
+// printCreateTemplates lists the built-in templates
+func printCreateTemplates() {
+	fmt.Println()
+	color.New(color.Bold, color.FgCyan).Println("Project templates")
+	fmt.Println()
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "TEMPLATE\tPORT\tDESCRIPTION")
+	for _, t := range scaffold.Templates() {
+		fmt.Fprintf(w, "%s\t%d\t%s\n", t.Name, t.Port, t.Description)
+	}
+	w.Flush()
+
+	fmt.Println()
+	color.New(color.Faint).Println("Tip: Use 'doku create <template> <name>' to create a project")
+	fmt.Println()
+}
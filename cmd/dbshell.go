@@ -0,0 +1,168 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/dokulabs/doku-cli/internal/dbshell"
+	"github.com/dokulabs/doku-cli/internal/docker"
+	"github.com/dokulabs/doku-cli/internal/envfile"
+	"github.com/dokulabs/doku-cli/internal/service"
+	"github.com/dokulabs/doku-cli/pkg/types"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	for _, client := range dbshell.Clients {
+		rootCmd.AddCommand(newDBShellCmd(client))
+	}
+}
+
+// newDBShellCmd creates the command for a database CLI, e.g. doku psql
+func newDBShellCmd(client *dbshell.Client) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   fmt.Sprintf("%s [instance] [-- %s args...]", client.Name, client.Name),
+		Short: fmt.Sprintf("Open %s connected to a %s instance", client.Name, client.Description),
+		Long: fmt.Sprintf(`Run %[1]s inside a %[2]s container, logged in with the
+credentials from the instance's env file.
+
+The instance can be left out when only one %[2]s instance is installed.
+Arguments after -- are passed on to %[1]s.
+
+Examples:
+  %-24[3]s # The only %[2]s instance
+  %-24[4]s # A specific instance
+  %[5]s`,
+			client.Name, client.Description,
+			"doku "+client.Name,
+			"doku "+client.Name+" "+client.ServiceTypes[0],
+			"doku "+client.Name+" "+client.ServiceTypes[0]+" -- "+dbShellExampleArgs(client.Name)),
+		ValidArgsFunction: completeInstancesOf(client.ServiceTypes...),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runDBShell(client, args)
+		},
+	}
+
+	// Everything after the instance name belongs to the client
+	cmd.Flags().SetInterspersed(false)
+
+	return cmd
+}
+
+// dbShellExampleArgs returns example client arguments for the help text
+func dbShellExampleArgs(name string) string {
+	switch name {
+	case "psql":
+		return `-c "select version()"`
+	case "mysql":
+		return `-e "select version()"`
+	default:
+		return "info server"
+	}
+}
+
+func runDBShell(client *dbshell.Client, args []string) error {
+	var instanceName string
+	if len(args) > 0 && args[0] != "--" && !strings.HasPrefix(args[0], "-") {
+		instanceName, args = args[0], args[1:]
+	}
+	if len(args) > 0 && args[0] == "--" {
+		args = args[1:]
+	}
+
+	cfgMgr, err := initConfigManager()
+	if err != nil {
+		if err == types.ErrNotInitialized {
+			return nil
+		}
+		return err
+	}
+
+	dockerClient, err := initDockerClient()
+	if err != nil {
+		return err
+	}
+	defer dockerClient.Close()
+
+	serviceMgr := getServiceManager(dockerClient, cfgMgr)
+
+	instance, err := findDBInstance(serviceMgr, client, instanceName)
+	if err != nil {
+		return err
+	}
+
+	if instance.IsMultiContainer {
+		return fmt.Errorf("'%s' is a multi-container service; use 'doku exec --container <name> %s' instead", instance.Name, instance.Name)
+	}
+
+	info, err := dockerClient.ContainerInspect(instance.ContainerName)
+	if err != nil {
+		return fmt.Errorf("failed to inspect container: %w", err)
+	}
+	if info.ContainerJSONBase == nil || info.State == nil || !info.State.Running {
+		return fmt.Errorf("'%s' is not running. Start it first with: doku start %s", instance.Name, instance.Name)
+	}
+
+	// The env file is the source of truth, the config is the fallback for
+	// instances installed before env files existed
+	envMgr := envfile.NewManager(cfgMgr.GetDokuDir())
+	fileEnv, _ := envMgr.Load(envMgr.GetServiceEnvPath(instance.Name, ""))
+	env := envfile.MergeEnv(instance.Environment, fileEnv)
+
+	command := client.Command(instance.ServiceType, env, args)
+
+	return execWithExitCode(context.Background(), dockerClient, docker.ExecOptions{
+		Container:   instance.ContainerName,
+		Command:     command.Args,
+		Env:         command.Env,
+		Interactive: true,
+		TTY:         docker.IsTerminal(os.Stdin),
+		Stdin:       os.Stdin,
+		Stdout:      os.Stdout,
+		Stderr:      os.Stderr,
+	})
+}
+
+// findDBInstance returns the named instance, or the only installed instance
+// the client supports when no name is given
+func findDBInstance(serviceMgr *service.Manager, client *dbshell.Client, name string) (*types.Instance, error) {
+	if name != "" {
+		instance, err := serviceMgr.Get(name)
+		if err != nil {
+			return nil, fmt.Errorf("instance '%s' not found. Use 'doku list' to see installed services", name)
+		}
+		if !client.Supports(instance.ServiceType) {
+			return nil, fmt.Errorf("'%s' is a %s instance; %s works with %s", name, instance.ServiceType, client.Name, strings.Join(client.ServiceTypes, ", "))
+		}
+		return instance, nil
+	}
+
+	instances, err := serviceMgr.List()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list instances: %w", err)
+	}
+
+	var candidates []*types.Instance
+	for _, instance := range instances {
+		if client.Supports(instance.ServiceType) {
+			candidates = append(candidates, instance)
+		}
+	}
+
+	switch len(candidates) {
+	case 0:
+		return nil, fmt.Errorf("no %s instance installed. Install one with: doku install %s", client.Description, client.ServiceTypes[0])
+	case 1:
+		return candidates[0], nil
+	}
+
+	names := make([]string, len(candidates))
+	for idx, instance := range candidates {
+		names[idx] = instance.Name
+	}
+	sort.Strings(names)
+	return nil, fmt.Errorf("several %s instances installed, pick one: %s", client.Description, strings.Join(names, ", "))
+}
@@ -0,0 +1,100 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/dokulabs/doku-cli/internal/docker"
+	"github.com/dokulabs/doku-cli/pkg/types"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+var diskCmd = &cobra.Command{
+	Use:   "disk",
+	Short: "Show the disk space used by services",
+	Long: `Show the disk space each instance and project takes up: the images its
+containers run, their writable layers, its volumes and its container logs.
+Like 'docker system df', scoped to Doku's resources.
+
+Image sizes include layers shared with other images, so an image used by
+several instances counts for each; the total counts it once. Logs show "-"
+when Docker keeps them where Doku can't read them, e.g. in the VM of
+Docker Desktop.
+
+Examples:
+  doku disk`,
+	Args: cobra.NoArgs,
+	RunE: runDisk,
+}
+
+func init() {
+	rootCmd.AddCommand(diskCmd)
+}
+
+func runDisk(cmd *cobra.Command, args []string) error {
+	cfgMgr, err := initConfigManager()
+	if err != nil {
+		if err == types.ErrNotInitialized {
+			return nil
+		}
+		return err
+	}
+	cfg, err := cfgMgr.Get()
+	if err != nil {
+		return err
+	}
+
+	dockerClient, err := initDockerClient()
+	if err != nil {
+		return err
+	}
+	defer dockerClient.Close()
+
+	ctx := context.Background()
+	names := append(mapKeys(cfg.Instances), mapKeys(cfg.Projects)...)
+	report, err := dockerClient.DiskUsage(ctx, names)
+	if err != nil {
+		return err
+	}
+
+	if len(report.Owners) == 0 {
+		color.Yellow("No services found")
+		return nil
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
+	fmt.Fprintln(w, "NAME\tIMAGES\tCONTAINERS\tVOLUMES\tLOGS\tTOTAL")
+	for _, usage := range report.Owners {
+		printDiskUsage(w, usage.Owner, usage)
+	}
+	printDiskUsage(w, "TOTAL", report.Total)
+	w.Flush()
+
+	// Only worth a mention when there's something to reclaim
+	unused, err := dockerClient.UnusedImages(ctx, keptImages(cfg, true))
+	if err == nil && len(unused) > 0 {
+		var size int64
+		for _, img := range unused {
+			size += img.Size
+		}
+		fmt.Println()
+		fmt.Printf("%d image(s) of old versions no instance uses take up %s\n", len(unused), formatBytes(size))
+		fmt.Println("Remove them with: doku image prune")
+	}
+	return nil
+}
+
+// printDiskUsage prints a row of 'doku disk'
+func printDiskUsage(w *tabwriter.Writer, name string, usage docker.DiskUsage) {
+	logs := "-"
+	total := usage.Images + usage.Containers + usage.Volumes
+	if usage.Logs >= 0 {
+		logs = formatBytes(usage.Logs)
+		total += usage.Logs
+	}
+	fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n", name, formatBytes(usage.Images), formatBytes(usage.Containers),
+		formatBytes(usage.Volumes), logs, formatBytes(total))
+}
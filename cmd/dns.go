@@ -0,0 +1,499 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"runtime"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/dokulabs/doku-cli/internal/config"
+	"github.com/dokulabs/doku-cli/internal/dns"
+	"github.com/dokulabs/doku-cli/internal/dnsmasq"
+	"github.com/dokulabs/doku-cli/internal/docker"
+	"github.com/dokulabs/doku-cli/internal/domain"
+	"github.com/dokulabs/doku-cli/internal/service"
+	"github.com/dokulabs/doku-cli/pkg/types"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+// dnsSetupMethods are the ways Doku can make the domains resolve
+var dnsSetupMethods = []string{"hosts", "dnsmasq", "manual"}
+
+var dnsRepairDryRun bool
+
+var dnsCmd = &cobra.Command{
+	Use:   "dns",
+	Short: "Manage the hostnames of the services",
+	Long: `Manage how the services' hostnames resolve to this machine: the entries
+Doku keeps in the hosts file, or the dnsmasq container of
+'doku init --dns dnsmasq'.
+
+Examples:
+  doku dns list                     # Doku's hosts file entries, and those left over
+  doku dns verify                   # Check every hostname resolves here
+  doku dns repair                   # Regenerate the entries from the services
+  doku dns repair --dry-run         # Show what repair would change
+  doku dns add api.myproject.test   # Add an entry
+  doku dns remove api.myproject.test`,
+}
+
+var dnsListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List Doku's hosts file entries",
+	Long: `List the entries Doku keeps in the hosts file and what each is for. Entries
+no service or project uses any more, e.g. of a removed service, are marked
+unused; those the services need but lack are listed as missing.`,
+	Args: cobra.NoArgs,
+	RunE: runDNSList,
+}
+
+var dnsAddCmd = &cobra.Command{
+	Use:   "add <hostname>",
+	Short: "Add a hosts file entry resolving to this machine",
+	Long: `Add a hosts file entry resolving a hostname to this machine, among Doku's
+own. 'doku dns repair' removes it again unless a service uses it.`,
+	Args: cobra.ExactArgs(1),
+	RunE: runDNSAdd,
+}
+
+var dnsRemoveCmd = &cobra.Command{
+	Use:   "remove <hostname>",
+	Short: "Remove one of Doku's hosts file entries",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runDNSRemove,
+}
+
+var dnsVerifyCmd = &cobra.Command{
+	Use:   "verify [hostname...]",
+	Short: "Check the services' hostnames resolve to this machine",
+	Long: `Look up every hostname of Doku and its services, or those given, the way
+other programs do, and check they resolve to this machine. Fails if any
+doesn't.`,
+	RunE: runDNSVerify,
+}
+
+var dnsRepairCmd = &cobra.Command{
+	Use:   "repair",
+	Short: "Regenerate Doku's DNS setup from the services",
+	Long: `Regenerate Doku's DNS setup from the services installed now.
+
+With hosts file entries, Doku's section is rewritten with an entry for each
+hostname of Doku and its services: missing ones are added, and those no
+service uses any more, including those added with 'doku dns add', removed.
+Entries of other workspaces are kept. With dnsmasq, its container and
+resolvers are set up again.`,
+	Args: cobra.NoArgs,
+	RunE: runDNSRepair,
+}
+
+func init() {
+	rootCmd.AddCommand(dnsCmd)
+	dnsCmd.AddCommand(dnsListCmd)
+	dnsCmd.AddCommand(dnsAddCmd)
+	dnsCmd.AddCommand(dnsRemoveCmd)
+	dnsCmd.AddCommand(dnsVerifyCmd)
+	dnsCmd.AddCommand(dnsRepairCmd)
+
+	dnsRepairCmd.Flags().BoolVar(&dnsRepairDryRun, "dry-run", false, "Show what would change without changing anything")
+}
+
+// dnsConfig returns the config manager and configuration, or nil ones when
+// Doku isn't initialized
+func dnsConfig() (*config.Manager, *types.Config, error) {
+	cfgMgr, err := initConfigManager()
+	if err != nil {
+		if err == types.ErrNotInitialized {
+			return nil, nil, nil
+		}
+		return nil, nil, err
+	}
+	cfg, err := cfgMgr.Get()
+	if err != nil {
+		return nil, nil, err
+	}
+	return cfgMgr, cfg, nil
+}
+
+func runDNSList(cmd *cobra.Command, args []string) error {
+	cfgMgr, cfg, err := dnsConfig()
+	if err != nil || cfgMgr == nil {
+		return err
+	}
+
+	dnsMgr := dns.NewManagerForContext(cfgMgr.GetContext())
+	printDNSSetup(cfg, dnsMgr)
+	fmt.Println()
+
+	entries, err := dnsMgr.Entries()
+	if err != nil {
+		return err
+	}
+	expected := service.HostsEntries(cfg)
+	owners := make(map[string]string, len(expected))
+	for _, entry := range expected {
+		owners[entry.Hostname] = entry.For
+	}
+
+	present := make(map[string]bool, len(entries))
+	unused := 0
+	if len(entries) == 0 {
+		fmt.Printf("No Doku entries in %s\n", dnsMgr.GetHostsFilePath())
+	} else {
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
+		fmt.Fprintln(w, "HOSTNAME\tIP\tFOR")
+		for _, entry := range entries {
+			present[entry.Hostname] = true
+			owner := owners[entry.Hostname]
+			if owner == "" {
+				owner = color.YellowString("unused")
+				unused++
+			}
+			fmt.Fprintf(w, "%s\t%s\t%s\n", entry.Hostname, entry.IP, owner)
+		}
+		w.Flush()
+	}
+
+	if cfg.Preferences.DNSSetup != "hosts" {
+		return nil
+	}
+	var missing []string
+	for _, entry := range expected {
+		if !present[entry.Hostname] {
+			missing = append(missing, fmt.Sprintf("%s (%s)", entry.Hostname, entry.For))
+		}
+	}
+	if len(missing) > 0 {
+		fmt.Println()
+		color.Yellow("⚠️  Missing: %s", strings.Join(missing, ", "))
+	}
+	if len(missing) > 0 || unused > 0 {
+		color.New(color.Faint).Println("Fix the entries with 'doku dns repair'")
+	}
+	return nil
+}
+
+// printDNSSetup prints how the hostnames resolve in cfg's setup
+func printDNSSetup(cfg *types.Config, dnsMgr *dns.Manager) {
+	switch cfg.Preferences.DNSSetup {
+	case "hosts":
+		fmt.Printf("DNS: hosts file entries (%s), resolving to %s\n", dnsMgr.GetHostsFilePath(), config.HostsIP(cfg.Preferences))
+	case "dnsmasq":
+		var wildcards []string
+		for _, name := range domain.BaseDomains(cfg.Preferences) {
+			wildcards = append(wildcards, "*."+name)
+		}
+		fmt.Printf("DNS: dnsmasq on 127.0.0.1:%d, answering for %s\n", dnsmasq.Port(cfg.Preferences.DNSPort), strings.Join(wildcards, ", "))
+	case "manual":
+		fmt.Println("DNS: manual, Doku doesn't change the hosts file")
+	default:
+		fmt.Println("DNS: not set up")
+	}
+}
+
+func runDNSAdd(cmd *cobra.Command, args []string) error {
+	hostname := args[0]
+
+	cfgMgr, _, err := dnsConfig()
+	if err != nil || cfgMgr == nil {
+		return err
+	}
+	if err := domain.Validate(hostname); err != nil {
+		return err
+	}
+	if !strings.Contains(hostname, ".") {
+		return fmt.Errorf("invalid hostname '%s': expected a full hostname, e.g. api.myproject.test", hostname)
+	}
+
+	dnsMgr := dns.NewManagerForContext(cfgMgr.GetContext()).SetIP(cfgMgr.GetHostsIP())
+	if has, err := dnsMgr.HasHostname(hostname); err != nil {
+		return err
+	} else if has {
+		color.Green("✓ %s already has an entry", hostname)
+		return nil
+	}
+	if err := dnsMgr.AddHostname(hostname); err != nil {
+		return err
+	}
+	color.Green("✓ %s resolves to %s", hostname, dnsMgr.IP())
+	return nil
+}
+
+func runDNSRemove(cmd *cobra.Command, args []string) error {
+	hostname := args[0]
+
+	cfgMgr, cfg, err := dnsConfig()
+	if err != nil || cfgMgr == nil {
+		return err
+	}
+
+	dnsMgr := dns.NewManagerForContext(cfgMgr.GetContext())
+	if has, err := dnsMgr.HasHostname(hostname); err != nil {
+		return err
+	} else if !has {
+		return fmt.Errorf("%s has no Doku entry. Use 'doku dns list' to see them", hostname)
+	}
+	if err := dnsMgr.RemoveSingleEntry(hostname); err != nil {
+		return err
+	}
+	color.Green("✓ Removed %s", hostname)
+
+	for _, entry := range service.HostsEntries(cfg) {
+		if entry.Hostname == hostname {
+			color.Yellow("⚠️  %s is a hostname of %s, which no longer resolves there; 'doku dns repair' adds it back", hostname, entry.For)
+		}
+	}
+	return nil
+}
+
+func runDNSVerify(cmd *cobra.Command, args []string) error {
+	cfgMgr, cfg, err := dnsConfig()
+	if err != nil || cfgMgr == nil {
+		return err
+	}
+
+	owners := make(map[string]string)
+	hostnames := args
+	if len(hostnames) == 0 {
+		for _, entry := range service.HostsEntries(cfg) {
+			hostnames = append(hostnames, entry.Hostname)
+			owners[entry.Hostname] = entry.For
+		}
+	}
+
+	failed := 0
+	for _, hostname := range hostnames {
+		label := hostname
+		if owner := owners[hostname]; owner != "" {
+			label += color.New(color.Faint).Sprintf(" (%s)", owner)
+		}
+
+		addrs, err := lookupHost(hostname)
+		switch {
+		case err != nil:
+			fmt.Printf("%s %s: doesn't resolve\n", color.RedString("✗"), label)
+			failed++
+		case !resolvesLocally(addrs, cfg.Preferences.BindIP):
+			fmt.Printf("%s %s: resolves to %s, not this machine\n", color.RedString("✗"), label, strings.Join(addrs, ", "))
+			failed++
+		default:
+			fmt.Printf("%s %s → %s\n", color.GreenString("✓"), label, strings.Join(addrs, ", "))
+		}
+	}
+
+	if failed > 0 {
+		hint := "fix them with 'doku dns repair'"
+		if cfg.Preferences.DNSSetup != "hosts" && cfg.Preferences.DNSSetup != "dnsmasq" {
+			hint = "add them to your DNS or hosts file"
+		}
+		return fmt.Errorf("%d of %d hostnames don't resolve to this machine: %s", failed, len(hostnames), hint)
+	}
+	return nil
+}
+
+// lookupHost resolves a hostname as the system does, giving up after a
+// few seconds
+func lookupHost(hostname string) ([]string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+	return net.DefaultResolver.LookupHost(ctx, hostname)
+}
+
+// resolvesLocally reports whether any of the addresses is the bind IP, or
+// with none, this machine's loopback address
+func resolvesLocally(addrs []string, bindIP string) bool {
+	for _, addr := range addrs {
+		ip := net.ParseIP(addr)
+		if ip == nil {
+			continue
+		}
+		if bindIP == "" && ip.IsLoopback() || ip.Equal(net.ParseIP(bindIP)) {
+			return true
+		}
+	}
+	return false
+}
+
+func runDNSRepair(cmd *cobra.Command, args []string) error {
+	cfgMgr, cfg, err := dnsConfig()
+	if err != nil || cfgMgr == nil {
+		return err
+	}
+
+	switch cfg.Preferences.DNSSetup {
+	case "dnsmasq":
+		if dnsRepairDryRun {
+			fmt.Printf("Would recreate %s and the resolvers of %s\n", dnsmasq.ContainerName(), strings.Join(domain.BaseDomains(cfg.Preferences), ", "))
+			return nil
+		}
+		dockerClient, err := initDockerClient()
+		if err != nil {
+			return err
+		}
+		defer dockerClient.Close()
+		if err := setupWildcardDNS(dockerClient, cfg); err != nil {
+			return err
+		}
+		color.Green("✓ dnsmasq answers for %s", strings.Join(domain.BaseDomains(cfg.Preferences), ", "))
+		return nil
+	case "hosts":
+		return repairHostsEntries(cfgMgr, cfg)
+	default:
+		return fmt.Errorf("Doku doesn't manage DNS in this setup; let it with 'doku init --dns hosts' or 'doku init --dns dnsmasq'")
+	}
+}
+
+// repairHostsEntries rewrites Doku's section of the hosts file with the
+// entries the services need
+func repairHostsEntries(cfgMgr *config.Manager, cfg *types.Config) error {
+	dnsMgr := dns.NewManagerForContext(cfgMgr.GetContext()).SetIP(config.HostsIP(cfg.Preferences))
+	entries, err := dnsMgr.Entries()
+	if err != nil {
+		return err
+	}
+	present := make(map[string]string, len(entries))
+	for _, entry := range entries {
+		present[entry.Hostname] = entry.IP
+	}
+
+	expected := service.HostsEntries(cfg)
+	hostnames := make([]string, 0, len(expected))
+	wanted := make(map[string]bool, len(expected))
+	changed := false
+	for _, entry := range expected {
+		hostnames = append(hostnames, entry.Hostname)
+		wanted[entry.Hostname] = true
+		switch ip := present[entry.Hostname]; ip {
+		case dnsMgr.IP():
+		case "":
+			fmt.Printf("%s %s (%s)\n", color.GreenString("+"), entry.Hostname, entry.For)
+			changed = true
+		default:
+			fmt.Printf("%s %s: %s → %s\n", color.YellowString("~"), entry.Hostname, ip, dnsMgr.IP())
+			changed = true
+		}
+	}
+	for _, entry := range entries {
+		if !wanted[entry.Hostname] {
+			fmt.Printf("%s %s\n", color.RedString("-"), entry.Hostname)
+			changed = true
+		}
+	}
+
+	if !changed {
+		color.Green("✓ The hosts file entries are up to date")
+		return nil
+	}
+	if dnsRepairDryRun {
+		color.New(color.Faint).Println("Dry run: nothing was changed")
+		return nil
+	}
+	if err := dnsMgr.SetHostnames(hostnames); err != nil {
+		return err
+	}
+	color.Green("✓ Regenerated Doku's entries in %s", dnsMgr.GetHostsFilePath())
+	return nil
+}
+
+// warnUnassignedBindIP warns when no interface of this machine has the bind
+// IP, which ports then can't be published on. Linux answers on all of
+// 127.0.0.0/8; macOS needs an alias for each loopback address but
+// 127.0.0.1.
+func warnUnassignedBindIP(ip string) {
+	bindIP := net.ParseIP(ip)
+	if bindIP == nil {
+		return
+	}
+	addrs, err := net.InterfaceAddrs()
+	if err != nil {
+		return
+	}
+	for _, addr := range addrs {
+		ipNet, ok := addr.(*net.IPNet)
+		if !ok {
+			continue
+		}
+		if ipNet.IP.Equal(bindIP) || runtime.GOOS == "linux" && ipNet.IP.IsLoopback() && ipNet.Contains(bindIP) {
+			return
+		}
+	}
+
+	color.Yellow("⚠️  No network interface of this machine has %s", ip)
+	if bindIP.IsLoopback() && runtime.GOOS == "darwin" {
+		color.New(color.Faint).Printf("Add it with: sudo ifconfig lo0 alias %s up\n", ip)
+	}
+}
+
+// wildcardResolver returns the resolver manager sending queries to the
+// dnsmasq container of cfg
+func wildcardResolver(cfg *types.Config) *dns.ResolverManager {
+	return dns.NewResolverManager().SetPort(dnsmasq.Port(cfg.Preferences.DNSPort))
+}
+
+// setupWildcardDNS recreates the dnsmasq container answering for the base
+// domains, and sends their queries to it. Where the system can't be set
+// up, it prints how to do it by hand.
+func setupWildcardDNS(dockerClient *docker.Client, cfg *types.Config) error {
+	domains := domain.BaseDomains(cfg.Preferences)
+	port := dnsmasq.Port(cfg.Preferences.DNSPort)
+
+	dnsmasqMgr := dnsmasq.NewManager(dockerClient, domains).
+		SetPort(port).
+		SetAddress(cfg.Preferences.BindIP).
+		SetLogRotation(cfg.Preferences.Logs)
+	if err := dnsmasqMgr.Setup(); err != nil {
+		return fmt.Errorf("%w; pick another port with 'doku init --dns-port'", err)
+	}
+
+	resolverMgr := wildcardResolver(cfg)
+	if !resolverMgr.Supported() {
+		color.Yellow("⚠️  Doku can't send queries to dnsmasq on this system")
+		fmt.Printf("Point these domains at the DNS server 127.0.0.1, port %d:\n", port)
+		for _, name := range domains {
+			color.Cyan("  %s", name)
+		}
+		return nil
+	}
+	for _, name := range domains {
+		if err := resolverMgr.SetupResolver(name); err != nil {
+			return fmt.Errorf("failed to set up the resolver for %s: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// updateWildcardDNS makes dnsmasq answer for the base domains as
+// configured now. Failures are reported and skipped.
+func updateWildcardDNS(cfgMgr *config.Manager, dockerClient *docker.Client) {
+	cfg, err := cfgMgr.Get()
+	if err == nil {
+		err = setupWildcardDNS(dockerClient, cfg)
+	}
+	if err != nil {
+		color.Yellow("⚠️  Failed to update dnsmasq: %v", err)
+	}
+}
+
+// removeWildcardDNS removes the dnsmasq container and the resolvers of the
+// base domains, left by a setup in dnsmasq mode
+func removeWildcardDNS(dockerClient *docker.Client, cfg *types.Config) {
+	if err := dnsmasq.NewManager(dockerClient, nil).RemoveContainer(); err != nil {
+		color.Yellow("⚠️  Failed to remove %s: %v", dnsmasq.ContainerName(), err)
+	}
+	for _, name := range domain.BaseDomains(cfg.Preferences) {
+		if wildcardResolver(cfg).HasResolver(name) {
+			removeWildcardResolver(cfg, name)
+		}
+	}
+}
+
+// removeWildcardResolver stops sending a domain's queries to dnsmasq
+func removeWildcardResolver(cfg *types.Config, name string) {
+	if err := wildcardResolver(cfg).RemoveResolver(name); err != nil {
+		color.Yellow("⚠️  Failed to remove the resolver for %s: %v", name, err)
+	}
+}
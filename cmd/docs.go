@@ -0,0 +1,141 @@
+package cmd
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/dokulabs/doku-cli/internal/catalog"
+	"github.com/dokulabs/doku-cli/internal/config"
+	"github.com/dokulabs/doku-cli/pkg/types"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+var docsPrint bool
+
+var docsCmd = &cobra.Command{
+	Use:   "docs <service> [link]",
+	Short: "Open the documentation of a service or project",
+	Long: `Open the documentation of an installed service in the browser, as listed
+in the catalog, or its homepage when the catalog has no documentation link.
+
+Projects open the links registered with 'doku project links' or in
+doku.yaml: the one named docs, or the only one. Name another link to open
+it instead. --print prints the URL instead of opening it.
+
+Examples:
+  doku docs postgres             # PostgreSQL documentation
+  doku docs postgres repository  # Its source repository
+  doku docs api                  # The project's docs link
+  doku docs api swagger --print  # Print another link of the project`,
+	Args: cobra.RangeArgs(1, 2),
+	RunE: runDocs,
+}
+
+func init() {
+	rootCmd.AddCommand(docsCmd)
+
+	docsCmd.Flags().BoolVar(&docsPrint, "print", false, "Print the URL instead of opening it")
+}
+
+// namedLink is a link of a service or project shown by 'doku info'
+type namedLink struct {
+	Name, URL string
+}
+
+// instanceLinks returns the links of an installed service from the
+// catalog, documentation first, or nil if the catalog has none
+func instanceLinks(cfgMgr *config.Manager, instance *types.Instance) []namedLink {
+	catalogMgr := catalog.NewManager(cfgMgr.GetCatalogDir())
+	svc, err := catalogMgr.GetService(instance.ServiceType)
+	if err != nil || svc.Links == nil {
+		return nil
+	}
+
+	var links []namedLink
+	for _, link := range []namedLink{
+		{"documentation", svc.Links.Documentation},
+		{"homepage", svc.Links.Homepage},
+		{"repository", svc.Links.Repository},
+	} {
+		if link.URL != "" {
+			links = append(links, link)
+		}
+	}
+	return links
+}
+
+// projectLinks returns the links registered for a project, docs first and
+// then by name
+func projectLinks(proj *types.Project) []namedLink {
+	links := make([]namedLink, 0, len(proj.Links))
+	for name, url := range proj.Links {
+		links = append(links, namedLink{name, url})
+	}
+	sort.Slice(links, func(i, j int) bool {
+		if (links[i].Name == "docs") != (links[j].Name == "docs") {
+			return links[i].Name == "docs"
+		}
+		return links[i].Name < links[j].Name
+	})
+	return links
+}
+
+func runDocs(cmd *cobra.Command, args []string) error {
+	name := args[0]
+
+	cfgMgr, err := initConfigManager()
+	if err != nil {
+		if err == types.ErrNotInitialized {
+			return nil
+		}
+		return err
+	}
+
+	var links []namedLink
+	var hint string
+	if instance, err := cfgMgr.GetInstance(name); err == nil {
+		links = instanceLinks(cfgMgr, instance)
+		hint = fmt.Sprintf("The catalog has no links for %s", instance.ServiceType)
+	} else if proj, projErr := cfgMgr.GetProject(name); projErr == nil {
+		links = projectLinks(proj)
+		hint = fmt.Sprintf("%s has no links. Add one with: doku project links %s docs=<url>", name, name)
+	} else {
+		return fmt.Errorf("service '%s' not found. Use 'doku list' to see installed services", name)
+	}
+
+	if len(links) == 0 {
+		return fmt.Errorf("%s", hint)
+	}
+
+	link := links[0]
+	if len(args) > 1 {
+		found := false
+		for _, l := range links {
+			if l.Name == args[1] {
+				link, found = l, true
+				break
+			}
+		}
+		if !found {
+			names := make([]string, 0, len(links))
+			for _, l := range links {
+				names = append(names, l.Name)
+			}
+			return fmt.Errorf("%s has no link named %s (links: %s)", name, args[1], strings.Join(names, ", "))
+		}
+	}
+
+	if docsPrint {
+		fmt.Println(link.URL)
+		return nil
+	}
+
+	color.Cyan("Opening %s %s: %s", name, link.Name, link.URL)
+	if err := openBrowser(link.URL); err != nil {
+		color.Yellow("⚠️  Failed to open the browser: %v", err)
+		fmt.Printf("   Open %s manually\n", link.URL)
+	}
+	return nil
+}
@@ -0,0 +1,197 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/dokulabs/doku-cli/internal/certs"
+	"github.com/dokulabs/doku-cli/internal/config"
+	"github.com/dokulabs/doku-cli/internal/dns"
+	"github.com/dokulabs/doku-cli/internal/docker"
+	"github.com/dokulabs/doku-cli/internal/doctor"
+	"github.com/dokulabs/doku-cli/internal/proxy"
+	"github.com/dokulabs/doku-cli/internal/service"
+	"github.com/dokulabs/doku-cli/pkg/types"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+var doctorFix bool
+
+var doctorCmd = &cobra.Command{
+	Use:   "doctor",
+	Short: "Find and repair drift between Doku and Docker",
+	Long: `Check that what runs in Docker matches the Doku configuration:
+
+  • the reverse proxy (Traefik or Caddy) is running
+  • every instance's containers still exist
+  • containers are connected to doku-network
+  • routed containers have their Traefik labels
+  • service hostnames are in the hosts file (when Doku manages it)
+  • dnsmasq is running (with 'doku init --dns dnsmasq')
+  • the certificates Traefik serves aren't expired or due for renewal
+
+With --fix, doctor repairs what it finds: it starts the proxy, reconnects
+containers to doku-network, recreates containers without Traefik labels,
+adds missing hosts entries, recreates dnsmasq, renews certificates and
+removes the records of instances whose containers no longer exist.
+
+Examples:
+  doku doctor          # Report drift
+  doku doctor --fix    # Report and repair drift`,
+	Args: cobra.NoArgs,
+	RunE: runDoctor,
+}
+
+func init() {
+	rootCmd.AddCommand(doctorCmd)
+
+	doctorCmd.Flags().BoolVar(&doctorFix, "fix", false, "Repair the drift found")
+}
+
+func runDoctor(cmd *cobra.Command, args []string) error {
+	cfgMgr, err := initConfigManager()
+	if err != nil {
+		if err == types.ErrNotInitialized {
+			return nil
+		}
+		return err
+	}
+
+	cfg, err := cfgMgr.Get()
+	if err != nil {
+		return fmt.Errorf("failed to get config: %w", err)
+	}
+
+	dockerClient, err := initDockerClient()
+	if err != nil {
+		return err
+	}
+	defer dockerClient.Close()
+
+	dnsMgr := dns.NewManagerForContext(cfgMgr.GetContext()).SetIP(cfgMgr.GetHostsIP())
+	state, err := doctor.Observe(dockerClient, cfg, dnsMgr, cfgMgr.GetCertsDir())
+	if err != nil {
+		return fmt.Errorf("failed to inspect Docker: %w", err)
+	}
+
+	issues := doctor.Diagnose(cfg, state)
+	if len(issues) == 0 {
+		color.Green("✓ No drift found")
+		return nil
+	}
+
+	color.Yellow("Found %d issue(s):", len(issues))
+	fmt.Println()
+	for _, issue := range issues {
+		label := "Doku"
+		if issue.Instance != "" {
+			label = issue.Instance
+		}
+		fmt.Printf("  %s: %s\n", color.CyanString(label), issue.Message)
+		color.New(color.Faint).Printf("    fix: %s\n", issue.Fix())
+	}
+	fmt.Println()
+
+	if !doctorFix {
+		fmt.Println("Run 'doku doctor --fix' to repair")
+		return nil
+	}
+
+	color.Cyan("Repairing...")
+	serviceMgr := getServiceManager(dockerClient, cfgMgr)
+	reconnected := make(map[string]bool)
+	failed := 0
+	renewed := false
+	for _, issue := range issues {
+		if issue.Kind == doctor.KindNetwork {
+			// Reconnect handles all of an instance's containers at once
+			if reconnected[issue.Instance] {
+				continue
+			}
+			reconnected[issue.Instance] = true
+		}
+
+		if err := repairIssue(issue, cfgMgr, cfg, dockerClient, serviceMgr, dnsMgr); err != nil {
+			color.Red("✗ %s: %v", issue.Fix(), err)
+			failed++
+			continue
+		}
+		color.Green("✓ %s", issue.Fix())
+		if issue.Kind == doctor.KindCertificate {
+			renewed = true
+		}
+	}
+	if renewed {
+		if err := reloadCertificates(cfgMgr, cfg); err != nil {
+			color.Red("✗ %v", err)
+			failed++
+		}
+	}
+	fmt.Println()
+
+	if failed > 0 {
+		return fmt.Errorf("%d issue(s) could not be repaired", failed)
+	}
+	color.Green("✓ All issues repaired")
+	return nil
+}
+
+// repairIssue repairs one issue found by doctor.Diagnose
+func repairIssue(
+	issue doctor.Issue,
+	cfgMgr *config.Manager,
+	cfg *types.Config,
+	dockerClient *docker.Client,
+	serviceMgr *service.Manager,
+	dnsMgr *dns.Manager,
+) error {
+	prefs := cfg.Preferences
+
+	switch issue.Kind {
+	case doctor.KindTraefik:
+		return proxy.New(dockerClient, cfgMgr, cfg).EnsureRunning()
+
+	case doctor.KindMissing:
+		instance := cfg.Instances[issue.Instance]
+		if err := cfgMgr.RemoveInstance(issue.Instance); err != nil {
+			return err
+		}
+		if prefs.DNSSetup == "hosts" && instance.URL != "" {
+			if err := dnsMgr.RemoveSingleEntry(doctor.Subdomain(instance) + "." + prefs.Domain); err != nil {
+				color.Yellow("⚠️  Failed to remove hosts entry: %v", err)
+			}
+		}
+		return nil
+
+	case doctor.KindNetwork:
+		_, err := serviceMgr.Reconnect(issue.Instance)
+		return err
+
+	case doctor.KindLabels:
+		instance := cfg.Instances[issue.Instance]
+		status, _ := serviceMgr.GetStatus(issue.Instance)
+		relabel := func(labels map[string]string) {
+			for key, value := range doctor.TraefikLabels(instance, prefs) {
+				labels[key] = value
+			}
+		}
+		if err := serviceMgr.RecreateWithLabels(issue.Instance, relabel); err != nil {
+			return err
+		}
+		if status != types.StatusRunning {
+			return serviceMgr.Stop(issue.Instance)
+		}
+		return nil
+
+	case doctor.KindHosts:
+		return dnsMgr.AddServiceDomain(doctor.Subdomain(cfg.Instances[issue.Instance]), prefs.Domain)
+
+	case doctor.KindDNS:
+		return setupWildcardDNS(dockerClient, cfg)
+
+	case doctor.KindCertificate:
+		return renewCertificate(cfgMgr, cfg, issue.Certificate, certs.RenewalWindow(cfg.Certificates))
+	}
+
+	return fmt.Errorf("unknown issue kind %q", issue.Kind)
+}
@@ -0,0 +1,613 @@
+package cmd
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/AlecAivazis/survey/v2"
+	"github.com/dokulabs/doku-cli/internal/certs"
+	"github.com/dokulabs/doku-cli/internal/config"
+	"github.com/dokulabs/doku-cli/internal/dns"
+	"github.com/dokulabs/doku-cli/internal/docker"
+	"github.com/dokulabs/doku-cli/internal/doctor"
+	"github.com/dokulabs/doku-cli/internal/domain"
+	"github.com/dokulabs/doku-cli/internal/envfile"
+	"github.com/dokulabs/doku-cli/internal/project"
+	"github.com/dokulabs/doku-cli/internal/proxy"
+	"github.com/dokulabs/doku-cli/internal/service"
+	"github.com/dokulabs/doku-cli/internal/traefik"
+	"github.com/dokulabs/doku-cli/pkg/types"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+var (
+	domainMigrateDryRun bool
+	domainMigrateYes    bool
+)
+
+var domainCmd = &cobra.Command{
+	Use:   "domain",
+	Short: "Manage the Doku domains",
+	Long: `Manage the base domains services are exposed under (e.g. doku.local).
+
+Services get a URL under the main domain, and are also reachable under the
+other base domains: postgres.doku.local and postgres.myproject.test.`,
+}
+
+var domainListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List the base domains and the services' own domains",
+	Args:  cobra.NoArgs,
+	RunE:  runDomainList,
+}
+
+var domainAddCmd = &cobra.Command{
+	Use:   "add <domain>",
+	Short: "Make services reachable under another base domain",
+	Long: `Add a base domain services are also reachable under, on top of the main
+one: with myproject.test, postgres.doku.local is also postgres.myproject.test.
+
+A certificate is generated for it (HTTPS only) and hosts entries added for
+the services. The services Traefik routes to are recreated to pick up the
+new hostnames.
+
+Examples:
+  doku domain add myproject.test`,
+	Args: cobra.ExactArgs(1),
+	RunE: runDomainAdd,
+}
+
+var domainRemoveCmd = &cobra.Command{
+	Use:   "remove <domain>",
+	Short: "Stop serving services under a base domain",
+	Long: `Remove a base domain added with 'doku domain add'. The services Traefik
+routes to are recreated without its hostnames. The main domain can't be
+removed: move to another one with 'doku domain migrate'.
+
+Examples:
+  doku domain remove myproject.test`,
+	Args: cobra.ExactArgs(1),
+	RunE: runDomainRemove,
+}
+
+var domainMigrateCmd = &cobra.Command{
+	Use:   "migrate <old-domain> <new-domain>",
+	Short: "Move all services to a new domain",
+	Long: `Move Doku and all installed services from one domain to another.
+
+The migration:
+  • generates certificates for the new domain (HTTPS only)
+  • regenerates the Traefik configuration and restarts Traefik
+  • updates instance and project URLs in the configuration
+  • rewrites env vars that reference the old domain
+  • updates /etc/hosts entries (and the macOS resolver, if set up)
+  • recreates containers so their Traefik routes use the new domain
+
+The plan is always shown before anything is changed.
+
+Examples:
+  # Show what would change
+  doku domain migrate doku.local dev.test --dry-run
+
+  # Migrate
+  doku domain migrate doku.local dev.test`,
+	Args: cobra.ExactArgs(2),
+	RunE: runDomainMigrate,
+}
+
+func init() {
+	rootCmd.AddCommand(domainCmd)
+	domainCmd.AddCommand(domainMigrateCmd)
+	domainCmd.AddCommand(domainListCmd)
+	domainCmd.AddCommand(domainAddCmd)
+	domainCmd.AddCommand(domainRemoveCmd)
+
+	domainMigrateCmd.Flags().BoolVar(&domainMigrateDryRun, "dry-run", false, "Show the migration plan without changing anything")
+	domainMigrateCmd.Flags().BoolVarP(&domainMigrateYes, "yes", "y", false, "Skip confirmation prompt")
+}
+
+func runDomainMigrate(cmd *cobra.Command, args []string) error {
+	oldDomain, newDomain := args[0], args[1]
+
+	cfgMgr, err := config.New()
+	if err != nil {
+		return fmt.Errorf("failed to create config manager: %w", err)
+	}
+
+	if !cfgMgr.IsInitialized() {
+		color.Yellow("⚠️  Doku is not initialized. Run 'doku init' first.")
+		return nil
+	}
+
+	cfg, err := cfgMgr.Get()
+	if err != nil {
+		return fmt.Errorf("failed to get config: %w", err)
+	}
+
+	envMgr := envfile.NewManager(cfgMgr.GetDokuDir())
+	plan, err := domain.NewPlan(cfg, envMgr, oldDomain, newDomain)
+	if err != nil {
+		return err
+	}
+
+	printDomainPlan(plan, cfg)
+
+	if domainMigrateDryRun {
+		color.New(color.Faint).Println("Dry run: nothing was changed")
+		return nil
+	}
+
+	if !domainMigrateYes {
+		confirm := false
+		prompt := &survey.Confirm{
+			Message: fmt.Sprintf("Migrate from %s to %s?", oldDomain, newDomain),
+			Default: false,
+		}
+		if err := survey.AskOne(prompt, &confirm); err != nil {
+			return err
+		}
+		if !confirm {
+			color.Yellow("Migration cancelled")
+			return nil
+		}
+		fmt.Println()
+	}
+
+	dockerClient, err := docker.NewClient()
+	if err != nil {
+		return fmt.Errorf("failed to create Docker client: %w", err)
+	}
+	defer dockerClient.Close()
+
+	protocol := cfg.Preferences.Protocol
+	// The proxy as configured for the new domain
+	next := *cfg
+	next.Preferences.Domain = newDomain
+	reverseProxy := proxy.New(dockerClient, cfgMgr, &next)
+
+	// Step 1: certificates, which Caddy issues itself
+	if protocol == "https" && proxy.Backend(cfg.Preferences) == proxy.Traefik {
+		color.Cyan("Generating certificates for %s...", newDomain)
+		certMgr := certs.NewManager(cfgMgr.GetCertsDir(), newDomain).Configure(cfg.Certificates)
+		if certMgr.NeedsMkcert() && !certMgr.IsMkcertInstalled() {
+			return fmt.Errorf("mkcert is not installed; it is needed to generate certificates for %s", newDomain)
+		}
+		if !certMgr.CertificatesExist() {
+			if err := certMgr.GenerateCertificates(); err != nil {
+				return err
+			}
+		}
+		fmt.Println()
+	}
+
+	// Step 2: the reverse proxy
+	color.Cyan("Updating %s configuration...", reverseProxy.Name())
+	if err := reverseProxy.WriteConfig(); err != nil {
+		return err
+	}
+	if running, _ := reverseProxy.IsRunning(); running {
+		if err := reverseProxy.RestartContainer(); err != nil {
+			color.Yellow("⚠️  Failed to restart %s: %v", reverseProxy.Name(), err)
+		}
+	}
+	fmt.Println()
+
+	// Step 3: configuration and env files
+	color.Cyan("Updating configuration...")
+	if err := cfgMgr.Update(func(c *types.Config) error {
+		c.Preferences.Domain = newDomain
+		c.Traefik.DashboardURL = domain.Replace(c.Traefik.DashboardURL, oldDomain, newDomain)
+		c.Monitoring.URL = domain.Replace(c.Monitoring.URL, oldDomain, newDomain)
+
+		for _, instance := range c.Instances {
+			instance.URL = domain.Replace(instance.URL, oldDomain, newDomain)
+			for key, value := range instance.Environment {
+				instance.Environment[key] = domain.Replace(value, oldDomain, newDomain)
+			}
+		}
+		for _, proj := range c.Projects {
+			proj.URL = domain.Replace(proj.URL, oldDomain, newDomain)
+			for key, value := range proj.Environment {
+				proj.Environment[key] = domain.Replace(value, oldDomain, newDomain)
+			}
+		}
+		return nil
+	}); err != nil {
+		return fmt.Errorf("failed to update config: %w", err)
+	}
+
+	for _, target := range plan.Targets {
+		if err := target.ApplyEnv(envMgr); err != nil {
+			return err
+		}
+	}
+	color.Green("✓ Configuration updated")
+	fmt.Println()
+
+	// Step 4: DNS
+	if cfg.Preferences.DNSSetup == "hosts" {
+		color.Cyan("Updating DNS entries...")
+		migrateDNSEntries(plan, cfg.Preferences)
+		fmt.Println()
+	}
+	if cfg.Preferences.DNSSetup == "dnsmasq" {
+		color.Cyan("Updating dnsmasq...")
+		removeWildcardResolver(cfg, oldDomain)
+		if err := setupWildcardDNS(dockerClient, &next); err != nil {
+			color.Yellow("⚠️  Failed to update dnsmasq: %v", err)
+		} else {
+			color.Green("✓ *.%s resolves through dnsmasq", newDomain)
+		}
+		fmt.Println()
+	}
+
+	// Step 5: containers
+	var failed []string
+	if len(plan.Targets) > 0 {
+		color.Cyan("Recreating containers...")
+
+		serviceMgr := service.NewManager(dockerClient, cfgMgr)
+		projectMgr, err := project.NewManager(dockerClient, cfgMgr)
+		if err != nil {
+			return fmt.Errorf("failed to create project manager: %w", err)
+		}
+
+		for _, target := range plan.Targets {
+			if err := recreateForDomain(serviceMgr, projectMgr, target, oldDomain, newDomain); err != nil {
+				color.Yellow("⚠️  %s: %v", target.Name, err)
+				failed = append(failed, target.Name)
+				continue
+			}
+			color.Green("✓ %s", target.Name)
+		}
+		fmt.Println()
+	}
+
+	color.Green("✓ Migrated from %s to %s", oldDomain, newDomain)
+	if len(failed) > 0 {
+		color.Yellow("Some containers could not be recreated. Retry with 'doku restart <name>' after fixing the problem.")
+	}
+	fmt.Printf("Certificates for %s are kept in %s and can be removed manually.\n", oldDomain, cfgMgr.GetCertsDir())
+
+	return nil
+}
+
+// printDomainPlan prints what a migration will change
+func printDomainPlan(plan *domain.Plan, cfg *types.Config) {
+	prefs := cfg.Preferences
+	fmt.Println()
+	color.Cyan("Domain migration: %s → %s", plan.OldDomain, plan.NewDomain)
+	fmt.Println()
+
+	fmt.Println("Doku:")
+	if proxy.Backend(prefs) == proxy.Caddy {
+		fmt.Println("  • Regenerate Caddy config")
+	} else {
+		if prefs.Protocol == "https" {
+			fmt.Printf("  • Generate certificates for %s and *.%s\n", plan.NewDomain, plan.NewDomain)
+		}
+		httpPort, httpsPort := config.TraefikPorts(cfg.Traefik)
+		fmt.Printf("  • Regenerate Traefik config (dashboard: %s)\n", config.ServiceURL(prefs.Protocol, "traefik."+plan.NewDomain, httpPort, httpsPort))
+	}
+	if prefs.DNSSetup == "hosts" {
+		fmt.Println("  • Rewrite /etc/hosts entries")
+	}
+	if prefs.DNSSetup == "dnsmasq" {
+		fmt.Printf("  • Answer for *.%s with dnsmasq\n", plan.NewDomain)
+	}
+	fmt.Println()
+
+	if len(plan.Targets) == 0 {
+		fmt.Println("No services or projects reference the old domain.")
+		fmt.Println()
+		return
+	}
+
+	fmt.Println("Services and projects:")
+	for _, target := range plan.Targets {
+		kind := "service"
+		if target.IsProject {
+			kind = "project"
+		}
+		fmt.Printf("  %s (%s)\n", color.CyanString(target.Name), kind)
+		if target.OldURL != target.NewURL {
+			fmt.Printf("    URL: %s → %s\n", target.OldURL, target.NewURL)
+		}
+		for _, change := range target.EnvChanges {
+			oldValue, newValue := change.OldValue, change.NewValue
+			if isSensitiveKey(change.Key) {
+				oldValue, newValue = maskValue(oldValue), maskValue(newValue)
+			}
+			fmt.Printf("    %s: %s → %s\n", change.Key, oldValue, newValue)
+		}
+		if target.Running {
+			fmt.Println("    Container will be recreated")
+		} else {
+			fmt.Println("    Container will be recreated and left stopped")
+		}
+	}
+	fmt.Println()
+}
+
+// migrateDNSEntries moves the Doku hosts entries and macOS resolver to the new domain
+func migrateDNSEntries(plan *domain.Plan, prefs types.PreferencesConfig) {
+	dnsMgr := dns.NewManagerForContext(prefs.Context).SetIP(config.HostsIP(prefs))
+
+	if current, err := dnsMgr.GetDokuDomain(); err == nil && current == plan.OldDomain {
+		if err := dnsMgr.UpdateDokuDomain(plan.NewDomain); err != nil {
+			color.Yellow("⚠️  Failed to update hosts entries: %v", err)
+		}
+	}
+
+	hosts := [][2]string{{"traefik." + plan.OldDomain, "traefik." + plan.NewDomain}}
+	for _, target := range plan.Targets {
+		oldHost, newHost := target.Hostnames()
+		if oldHost != "" && oldHost != newHost {
+			hosts = append(hosts, [2]string{oldHost, newHost})
+		}
+	}
+	for _, pair := range hosts {
+		if err := dnsMgr.RemoveSingleEntry(pair[0]); err != nil {
+			color.Yellow("⚠️  Failed to remove %s from hosts file: %v", pair[0], err)
+		}
+		if err := dnsMgr.AddSingleEntry(dnsMgr.IP(), pair[1]); err != nil {
+			color.Yellow("⚠️  Failed to add %s to hosts file: %v", pair[1], err)
+		}
+	}
+
+	resolverMgr := dns.NewResolverManager()
+	if resolverMgr.IsMacOS() && resolverMgr.HasResolver(plan.OldDomain) {
+		if err := resolverMgr.RemoveResolver(plan.OldDomain); err != nil {
+			color.Yellow("⚠️  Failed to remove resolver for %s: %v", plan.OldDomain, err)
+		}
+		if err := resolverMgr.SetupResolver(plan.NewDomain); err != nil {
+			color.Yellow("⚠️  Failed to set up resolver for %s: %v", plan.NewDomain, err)
+		}
+	}
+
+	color.Green("✓ DNS entries updated")
+}
+
+// recreateForDomain recreates the containers of a target so they pick up the
+// new env and Traefik rules. Stopped targets are stopped again afterwards.
+func recreateForDomain(serviceMgr *service.Manager, projectMgr *project.Manager, target *domain.Target, oldDomain, newDomain string) error {
+	if target.IsProject {
+		if !target.Running {
+			// Projects get their labels from the config when they next run
+			return nil
+		}
+		return projectMgr.Run(project.RunOptions{Name: target.Name, Detach: true})
+	}
+
+	relabel := func(labels map[string]string) {
+		for key, value := range labels {
+			labels[key] = domain.Replace(value, oldDomain, newDomain)
+		}
+	}
+	if err := serviceMgr.RecreateWithLabels(target.Name, relabel); err != nil {
+		return err
+	}
+
+	if !target.Running {
+		return serviceMgr.Stop(target.Name)
+	}
+	return nil
+}
+
+func runDomainList(cmd *cobra.Command, args []string) error {
+	cfgMgr, err := initConfigManager()
+	if err != nil {
+		if err == types.ErrNotInitialized {
+			return nil
+		}
+		return err
+	}
+	cfg, err := cfgMgr.Get()
+	if err != nil {
+		return err
+	}
+
+	for n, base := range domain.BaseDomains(cfg.Preferences) {
+		if n == 0 {
+			fmt.Printf("%s %s\n", base, color.New(color.Faint).Sprint("(main)"))
+		} else {
+			fmt.Println(base)
+		}
+	}
+
+	var owned []string
+	for _, name := range mapKeys(cfg.Instances) {
+		if len(cfg.Instances[name].Domains) > 0 {
+			owned = append(owned, name)
+		}
+	}
+	if len(owned) > 0 {
+		fmt.Println()
+		color.New(color.Bold).Println("Service domains")
+		for _, name := range owned {
+			fmt.Printf("  %s: %s\n", name, strings.Join(cfg.Instances[name].Domains, ", "))
+		}
+	}
+	return nil
+}
+
+func runDomainAdd(cmd *cobra.Command, args []string) error {
+	name := args[0]
+
+	cfgMgr, err := initConfigManager()
+	if err != nil {
+		if err == types.ErrNotInitialized {
+			return nil
+		}
+		return err
+	}
+	cfg, err := cfgMgr.Get()
+	if err != nil {
+		return err
+	}
+
+	if err := domain.Validate(name); err != nil {
+		return err
+	}
+	if slices.Contains(domain.BaseDomains(cfg.Preferences), name) {
+		return fmt.Errorf("'%s' is already a base domain", name)
+	}
+	for instanceName, instance := range cfg.Instances {
+		if slices.Contains(instance.Domains, name) {
+			return fmt.Errorf("'%s' is a domain of '%s'", name, instanceName)
+		}
+	}
+
+	dockerClient, err := initDockerClient()
+	if err != nil {
+		return err
+	}
+	defer dockerClient.Close()
+
+	if err := ensureCertificates(cfgMgr, dockerClient, cfg, []string{name}); err != nil {
+		return err
+	}
+
+	if err := cfgMgr.Update(func(c *types.Config) error {
+		c.Preferences.Domains = append(c.Preferences.Domains, name)
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	if cfg.Preferences.DNSSetup == "hosts" {
+		dnsMgr := dns.NewManagerForContext(cfg.Preferences.Context).SetIP(config.HostsIP(cfg.Preferences))
+		for _, instanceName := range mapKeys(cfg.Instances) {
+			instance := cfg.Instances[instanceName]
+			if !service.IsRouted(instance) {
+				continue
+			}
+			if err := dnsMgr.AddServiceDomain(doctor.Subdomain(instance), name); err != nil {
+				color.Yellow("⚠️  Failed to add %s.%s to hosts file: %v", doctor.Subdomain(instance), name, err)
+			}
+		}
+	}
+	if cfg.Preferences.DNSSetup == "dnsmasq" {
+		updateWildcardDNS(cfgMgr, dockerClient)
+	}
+
+	updateHostRules(service.NewManager(dockerClient, cfgMgr), cfg)
+
+	color.Green("✓ Services are also reachable under %s", name)
+	return nil
+}
+
+func runDomainRemove(cmd *cobra.Command, args []string) error {
+	name := args[0]
+
+	cfgMgr, err := initConfigManager()
+	if err != nil {
+		if err == types.ErrNotInitialized {
+			return nil
+		}
+		return err
+	}
+	cfg, err := cfgMgr.Get()
+	if err != nil {
+		return err
+	}
+
+	if name == domain.BaseDomains(cfg.Preferences)[0] {
+		return fmt.Errorf("'%s' is the main domain; move to another one with 'doku domain migrate'", name)
+	}
+	if !slices.Contains(cfg.Preferences.Domains, name) {
+		return fmt.Errorf("'%s' is not a base domain. Use 'doku domain list' to see them", name)
+	}
+
+	dockerClient, err := initDockerClient()
+	if err != nil {
+		return err
+	}
+	defer dockerClient.Close()
+
+	if err := cfgMgr.Update(func(c *types.Config) error {
+		c.Preferences.Domains = slices.DeleteFunc(c.Preferences.Domains, func(d string) bool { return d == name })
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	if cfg.Preferences.DNSSetup == "hosts" {
+		dnsMgr := dns.NewManagerForContext(cfg.Preferences.Context)
+		for _, instanceName := range mapKeys(cfg.Instances) {
+			instance := cfg.Instances[instanceName]
+			if !service.IsRouted(instance) {
+				continue
+			}
+			host := doctor.Subdomain(instance) + "." + name
+			if err := dnsMgr.RemoveSingleEntry(host); err != nil {
+				color.Yellow("⚠️  Failed to remove %s from hosts file: %v", host, err)
+			}
+		}
+	}
+	if cfg.Preferences.DNSSetup == "dnsmasq" {
+		removeWildcardResolver(cfg, name)
+		updateWildcardDNS(cfgMgr, dockerClient)
+	}
+
+	updateHostRules(service.NewManager(dockerClient, cfgMgr), cfg)
+
+	color.Green("✓ Services are no longer reachable under %s", name)
+	return nil
+}
+
+// updateHostRules recreates the services Traefik routes to so their rules
+// match the base domains. Failures are reported and skipped.
+func updateHostRules(serviceMgr *service.Manager, cfg *types.Config) {
+	for _, name := range mapKeys(cfg.Instances) {
+		if !service.IsRouted(cfg.Instances[name]) {
+			continue
+		}
+		fmt.Printf("Recreating %s...\n", name)
+		if err := serviceMgr.UpdateHostRules(name); err != nil {
+			color.Yellow("⚠️  Failed to update the routes of %s: %v", name, err)
+		}
+	}
+}
+
+// extraCertificateDomains returns the domains other than the main one
+// that need certificates: the other base domains and the services' own
+func extraCertificateDomains(cfg *types.Config) []string {
+	names := domain.BaseDomains(cfg.Preferences)[1:]
+	for _, name := range mapKeys(cfg.Instances) {
+		names = append(names, cfg.Instances[name].Domains...)
+	}
+	return names
+}
+
+// ensureCertificates generates the certificates of the given domains that
+// have none and has Traefik serve them. Only HTTPS setups use certificates,
+// and Caddy issues its own.
+func ensureCertificates(cfgMgr *config.Manager, dockerClient *docker.Client, cfg *types.Config, names []string) error {
+	if cfg.Preferences.Protocol != "https" || proxy.Backend(cfg.Preferences) == proxy.Caddy {
+		return nil
+	}
+
+	generated := false
+	for _, name := range names {
+		certMgr := certs.NewManager(cfgMgr.GetCertsDir(), name).Configure(cfg.Certificates)
+		if certMgr.CertificatesExist() {
+			continue
+		}
+		if certMgr.NeedsMkcert() && !certMgr.IsMkcertInstalled() {
+			return fmt.Errorf("mkcert is not installed; it is needed to generate certificates for %s", name)
+		}
+		if err := certMgr.GenerateCertificates(); err != nil {
+			return err
+		}
+		generated = true
+	}
+	if !generated {
+		return nil
+	}
+
+	traefikMgr := traefik.NewManager(dockerClient, cfgMgr.GetTraefikDir(), cfgMgr.GetCertsDir(), cfg.Preferences.Domain, cfg.Preferences.Protocol)
+	return traefikMgr.GenerateDynamicConfig()
+}
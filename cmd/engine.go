@@ -0,0 +1,20 @@
+package cmd
+
+import (
+	"github.com/dokulabs/doku-cli/internal/config"
+	"github.com/dokulabs/doku-cli/internal/docker"
+)
+
+// useEngine makes Docker clients talk to the container engine chosen at
+// setup, Docker or Podman. Without one, or Doku set up, it's detected.
+func useEngine() {
+	cfgMgr, err := config.New()
+	if err != nil || !cfgMgr.IsInitialized() {
+		return
+	}
+	cfg, err := cfgMgr.Get()
+	if err != nil {
+		return
+	}
+	docker.SetEngine(cfg.Preferences.Engine)
+}
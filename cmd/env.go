@@ -2,13 +2,18 @@ package cmd
 
 import (
 	"fmt"
+	"os"
 	"sort"
 	"strings"
 
+	"github.com/AlecAivazis/survey/v2"
+	"github.com/dokulabs/doku-cli/internal/catalog"
 	"github.com/dokulabs/doku-cli/internal/config"
 	"github.com/dokulabs/doku-cli/internal/docker"
 	"github.com/dokulabs/doku-cli/internal/envfile"
+	"github.com/dokulabs/doku-cli/internal/project"
 	"github.com/dokulabs/doku-cli/internal/service"
+	"github.com/dokulabs/doku-cli/pkg/types"
 	"github.com/fatih/color"
 	"github.com/spf13/cobra"
 )
@@ -198,3 +203,115 @@ func displayEnvironmentVariables(serviceName string, env map[string]string, show
 		fmt.Println()
 	}
 }
+
+// applyEnvChanges offers to recreate a service whose env file changed, when
+// the running container doesn't have the new values of keys yet. With
+// restart it recreates without asking; it never asks when stdin isn't a
+// terminal.
+func applyEnvChanges(dockerClient *docker.Client, cfgMgr *config.Manager, serviceMgr *service.Manager, instance *types.Instance, env map[string]string, keys []string, restart bool) error {
+	pending, err := serviceMgr.PendingEnvChanges(instance.Name, env, keys)
+	if err != nil {
+		// The container can't be compared, so assume everything changed
+		pending = keys
+	}
+
+	if len(pending) == 0 {
+		color.Green("✓ The running container already uses these values")
+		fmt.Println()
+		return nil
+	}
+
+	recreate := restart
+	if !recreate && docker.IsTerminal(os.Stdin) {
+		fmt.Printf("Not applied to the running container yet: %s\n", strings.Join(pending, ", "))
+		fmt.Println()
+		prompt := &survey.Confirm{
+			Message: fmt.Sprintf("Recreate %s now so the changes take effect?", instance.Name),
+			Default: true,
+		}
+		if err := survey.AskOne(prompt, &recreate); err != nil {
+			return err
+		}
+		fmt.Println()
+	}
+
+	if !recreate {
+		color.Yellow("⚠️  Changes saved but not applied. Recreate the container to apply them:")
+		fmt.Printf("   doku restart %s --recreate\n", instance.Name)
+		fmt.Println()
+		return nil
+	}
+
+	color.Cyan("Recreating container to apply changes...")
+	if err := recreateWithEnv(dockerClient, cfgMgr, serviceMgr, instance); err != nil {
+		return err
+	}
+	color.Green("✓ Service recreated with new environment")
+	fmt.Println()
+	offerRelink(dockerClient, cfgMgr, serviceMgr, instance.Name)
+	return nil
+}
+
+// checkEnvOptions validates the values about to be set, and the keys about
+// to be unset, against the configuration options of the service's catalog
+// spec. Variables the catalog doesn't define, custom projects and services
+// whose spec can't be found aren't checked.
+func checkEnvOptions(cfgMgr *config.Manager, instance *types.Instance, set map[string]string, unset []string) error {
+	if instance.ServiceType == "custom-project" {
+		return nil
+	}
+	catalogMgr := catalog.NewManager(cfgMgr.GetCatalogDir())
+	spec, err := catalogMgr.GetServiceVersion(instance.ServiceType, instance.Version)
+	if err != nil {
+		return nil
+	}
+
+	var problems []string
+	keys := make([]string, 0, len(set))
+	for key := range set {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		if opt := catalog.FindOption(spec, key); opt != nil {
+			if err := catalog.ValidateOption(*opt, set[key]); err != nil {
+				problems = append(problems, err.Error())
+			}
+		}
+	}
+	for _, key := range unset {
+		if opt := catalog.FindOption(spec, key); opt != nil && opt.Required {
+			problems = append(problems, fmt.Sprintf("%s is required by %s and can't be unset", key, instance.ServiceType))
+		}
+	}
+
+	if len(problems) == 0 {
+		return nil
+	}
+	return fmt.Errorf("invalid values for %s %s:\n  %s\nUse --force to go ahead anyway", instance.ServiceType, instance.Version, strings.Join(problems, "\n  "))
+}
+
+// recreateWithEnv recreates a service or custom project from its env file
+func recreateWithEnv(dockerClient *docker.Client, cfgMgr *config.Manager, serviceMgr *service.Manager, instance *types.Instance) error {
+	if instance.ServiceType != "custom-project" {
+		if err := serviceMgr.Recreate(instance.Name); err != nil {
+			return fmt.Errorf("failed to recreate service: %w", err)
+		}
+		return nil
+	}
+
+	projectMgr, err := project.NewManager(dockerClient, cfgMgr)
+	if err != nil {
+		return fmt.Errorf("failed to create project manager: %w", err)
+	}
+
+	// Run removes the existing container and creates a new one
+	if err := projectMgr.Run(project.RunOptions{
+		Name:   instance.Name,
+		Build:  false,
+		Detach: true,
+	}); err != nil {
+		return fmt.Errorf("failed to recreate container: %w", err)
+	}
+	return nil
+}
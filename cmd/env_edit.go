@@ -169,26 +169,8 @@ func runEnvEdit(cmd *cobra.Command, args []string) error {
 		color.Cyan("Recreating container to apply environment changes...")
 		fmt.Println()
 
-		if isCustomProject {
-			// For custom projects, use project manager
-			projectMgr, err := project.NewManager(dockerClient, cfgMgr)
-			if err != nil {
-				return fmt.Errorf("failed to create project manager: %w", err)
-			}
-
-			runOpts := project.RunOptions{
-				Name:   serviceName,
-				Build:  false, // Don't rebuild image
-				Detach: true,
-			}
-			if err := projectMgr.Run(runOpts); err != nil {
-				return fmt.Errorf("failed to recreate container: %w", err)
-			}
-		} else {
-			// For catalog services, use service manager
-			if err := serviceMgr.Recreate(serviceName); err != nil {
-				return fmt.Errorf("failed to recreate container: %w", err)
-			}
+		if err := recreateWithEnv(dockerClient, cfgMgr, serviceMgr, instance); err != nil {
+			return err
 		}
 
 		fmt.Println()
@@ -197,8 +179,8 @@ func runEnvEdit(cmd *cobra.Command, args []string) error {
 	} else {
 		fmt.Println()
 		color.Yellow("⚠️  Changes saved but not applied.")
-		color.Yellow("    To apply changes, restart the service:")
-		color.Yellow("    doku restart %s", serviceName)
+		color.Yellow("    To apply changes, recreate the service:")
+		color.Yellow("    doku restart %s --recreate", serviceName)
 		fmt.Println()
 	}
 
@@ -0,0 +1,186 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"text/tabwriter"
+
+	"github.com/dokulabs/doku-cli/internal/config"
+	"github.com/dokulabs/doku-cli/internal/docker"
+	"github.com/dokulabs/doku-cli/internal/envfile"
+	"github.com/dokulabs/doku-cli/internal/service"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+var (
+	envRollbackTo      string
+	envRollbackRestart bool
+)
+
+var envHistoryCmd = &cobra.Command{
+	Use:   "history <service>",
+	Short: "List backups of a service's env file",
+	Long: `List the saved versions of a service's env file.
+
+Every time Doku writes an env file it first backs up the previous version
+under ~/.doku/services/.history/<service>/ (or ~/.doku/projects/.history/
+for custom projects). The last ` + fmt.Sprint(envfile.MaxHistory) + ` versions are kept.
+
+Examples:
+  doku env history postgres
+  doku env rollback postgres --to 20261018-101530.123`,
+	Args: cobra.ExactArgs(1),
+	RunE: runEnvHistory,
+}
+
+var envRollbackCmd = &cobra.Command{
+	Use:   "rollback <service> --to <timestamp>",
+	Short: "Restore a service's env file from a backup",
+	Long: `Restore a service's env file to a version listed by 'doku env history'.
+
+The current env file is backed up first, so a rollback can be undone with
+another rollback. A unique prefix of the timestamp is enough.
+
+Examples:
+  doku env rollback postgres --to 20261018-101530.123
+  doku env rollback postgres --to 20261018-1015
+  doku env rollback postgres --to 20261018-1015 --restart`,
+	Args: cobra.ExactArgs(1),
+	RunE: runEnvRollback,
+}
+
+func init() {
+	envCmd.AddCommand(envHistoryCmd)
+	envCmd.AddCommand(envRollbackCmd)
+
+	envRollbackCmd.Flags().StringVar(&envRollbackTo, "to", "", "Timestamp of the backup to restore (see 'doku env history')")
+	envRollbackCmd.Flags().BoolVarP(&envRollbackRestart, "restart", "r", false, "Recreate the service without asking so the changes take effect")
+	envRollbackCmd.MarkFlagRequired("to")
+}
+
+func runEnvHistory(cmd *cobra.Command, args []string) error {
+	instanceName := args[0]
+
+	cfgMgr, err := config.New()
+	if err != nil {
+		return fmt.Errorf("failed to create config manager: %w", err)
+	}
+
+	if !cfgMgr.IsInitialized() {
+		color.Yellow("⚠️  Doku is not initialized. Run 'doku init' first.")
+		return nil
+	}
+
+	instance, err := cfgMgr.GetInstance(instanceName)
+	if err != nil {
+		return fmt.Errorf("service '%s' not found. Use 'doku list' to see installed services", instanceName)
+	}
+
+	envPath := instanceEnvPath(cfgMgr, instance)
+	backups, err := envfile.History(envPath)
+	if err != nil {
+		return err
+	}
+
+	fmt.Println()
+	if len(backups) == 0 {
+		color.Yellow("No backups of %s yet", envPath)
+		fmt.Println()
+		return nil
+	}
+
+	color.New(color.Bold, color.FgCyan).Printf("Env file history for %s\n", instance.Name)
+	fmt.Printf("File: %s\n", envPath)
+	fmt.Println()
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "TIMESTAMP\tSAVED\tVARIABLES")
+	for _, b := range backups {
+		count := "?"
+		if env, err := envfile.LoadEnvFile(b.Path); err == nil {
+			count = fmt.Sprint(len(env))
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\n", b.Timestamp, formatTime(b.Time), count)
+	}
+	w.Flush()
+
+	fmt.Println()
+	color.New(color.Faint).Printf("Tip: Use 'doku env rollback %s --to <timestamp>' to restore a version\n", instance.Name)
+	fmt.Println()
+	return nil
+}
+
+func runEnvRollback(cmd *cobra.Command, args []string) error {
+	instanceName := args[0]
+
+	cfgMgr, err := config.New()
+	if err != nil {
+		return fmt.Errorf("failed to create config manager: %w", err)
+	}
+
+	if !cfgMgr.IsInitialized() {
+		color.Yellow("⚠️  Doku is not initialized. Run 'doku init' first.")
+		return nil
+	}
+
+	dockerClient, err := docker.NewClient()
+	if err != nil {
+		return fmt.Errorf("failed to create Docker client: %w", err)
+	}
+	defer dockerClient.Close()
+
+	serviceMgr := service.NewManager(dockerClient, cfgMgr)
+
+	instance, err := serviceMgr.Get(instanceName)
+	if err != nil {
+		return fmt.Errorf("service '%s' not found", instanceName)
+	}
+
+	envPath := instanceEnvPath(cfgMgr, instance)
+	backup, err := envfile.FindBackup(envPath, envRollbackTo)
+	if err != nil {
+		return fmt.Errorf("%w. Use 'doku env history %s' to list backups", err, instance.Name)
+	}
+
+	current, _ := envfile.LoadEnvFile(envPath)
+	if err := envfile.Rollback(envPath, backup); err != nil {
+		return err
+	}
+	restored, err := envfile.LoadEnvFile(envPath)
+	if err != nil {
+		return fmt.Errorf("failed to load restored env file: %w", err)
+	}
+
+	fmt.Println()
+	color.Green("✓ Restored %s from %s", envPath, backup.Timestamp)
+	fmt.Println()
+
+	changed := changedEnvKeys(current, restored)
+	if len(changed) == 0 {
+		color.New(color.Faint).Println("The backup has the same values as the current env file")
+		fmt.Println()
+		return nil
+	}
+
+	return applyEnvChanges(dockerClient, cfgMgr, serviceMgr, instance, restored, changed, envRollbackRestart)
+}
+
+// changedEnvKeys returns the keys added, removed or changed between two
+// versions of an env file
+func changedEnvKeys(before, after map[string]string) []string {
+	var keys []string
+	for k, v := range after {
+		if old, ok := before[k]; !ok || old != v {
+			keys = append(keys, k)
+		}
+	}
+	for k := range before {
+		if _, ok := after[k]; !ok {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -2,6 +2,7 @@ package cmd
 
 import (
 	"fmt"
+	"sort"
 	"strings"
 
 	"github.com/dokulabs/doku-cli/internal/config"
@@ -14,6 +15,7 @@ import (
 
 var (
 	envSetRestart bool
+	envSetForce   bool
 )
 
 var envSetCmd = &cobra.Command{
@@ -24,8 +26,13 @@ var envSetCmd = &cobra.Command{
 Environment variables are saved to the service's env file:
   ~/.doku/services/<service>.env
 
-The service will need to be restarted for changes to take effect.
-Use --restart to automatically restart the service.
+Values of variables the service's catalog entry defines as configuration
+options are checked against their type, choices and validation pattern
+first; --force saves them anyway.
+
+The new values only take effect once the container is recreated. Doku
+compares them with the running container and offers to recreate it;
+--recreate recreates it without asking.
 
 Examples:
   # Set a single environment variable
@@ -34,15 +41,17 @@ Examples:
   # Set multiple environment variables
   doku env set frontend API_URL=https://api.example.com NODE_ENV=production
 
-  # Set and automatically restart
-  doku env set redis REDIS_PASSWORD=secret --restart`,
+  # Set and recreate the container without asking
+  doku env set postgres POSTGRES_MAX_CONNECTIONS=200 SHARED_BUFFERS=512MB --recreate`,
 	Args: cobra.MinimumNArgs(2),
 	RunE: runEnvSet,
 }
 
 func init() {
 	envCmd.AddCommand(envSetCmd)
-	envSetCmd.Flags().BoolVarP(&envSetRestart, "restart", "r", false, "Restart service after setting variables")
+	envSetCmd.Flags().BoolVar(&envSetRestart, "recreate", false, "Recreate the service without asking so the changes take effect")
+	envSetCmd.Flags().BoolVarP(&envSetRestart, "restart", "r", false, "Same as --recreate")
+	envSetCmd.Flags().BoolVar(&envSetForce, "force", false, "Save values that fail the catalog's validation")
 }
 
 func runEnvSet(cmd *cobra.Command, args []string) error {
@@ -56,6 +65,9 @@ func runEnvSet(cmd *cobra.Command, args []string) error {
 		if len(parts) != 2 {
 			return fmt.Errorf("invalid environment variable format: %s (use KEY=VALUE)", envVar)
 		}
+		if parts[0] == "" {
+			return fmt.Errorf("invalid environment variable format: %s (use KEY=VALUE)", envVar)
+		}
 		envMap[parts[0]] = parts[1]
 	}
 
@@ -87,6 +99,12 @@ func runEnvSet(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("service '%s' not found. Use 'doku list' to see installed services", instanceName)
 	}
 
+	if !envSetForce {
+		if err := checkEnvOptions(cfgMgr, instance, envMap, nil); err != nil {
+			return err
+		}
+	}
+
 	isCustomProject := instance.ServiceType == "custom-project"
 
 	// Get env file path
@@ -126,19 +144,16 @@ func runEnvSet(cmd *cobra.Command, args []string) error {
 	color.Green("✓ Environment variables saved to %s", envPath)
 	fmt.Println()
 
-	// Restart if requested
-	if envSetRestart {
-		color.Cyan("Recreating container to apply changes...")
-		if err := serviceMgr.Recreate(instanceName); err != nil {
-			return fmt.Errorf("failed to recreate service: %w", err)
-		}
-		color.Green("✓ Service recreated with new environment")
-		fmt.Println()
-	} else {
-		color.Yellow("⚠️  Note: Service needs to be restarted for changes to take effect")
-		fmt.Printf("   Run: doku restart %s\n", instanceName)
-		fmt.Println()
+	env, err := envMgr.Load(envPath)
+	if err != nil {
+		return fmt.Errorf("failed to load environment file: %w", err)
+	}
+
+	keys := make([]string, 0, len(envMap))
+	for key := range envMap {
+		keys = append(keys, key)
 	}
+	sort.Strings(keys)
 
-	return nil
+	return applyEnvChanges(dockerClient, cfgMgr, serviceMgr, instance, env, keys, envSetRestart)
 }
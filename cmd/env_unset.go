@@ -13,6 +13,7 @@ import (
 
 var (
 	envUnsetRestart bool
+	envUnsetForce   bool
 )
 
 var envUnsetCmd = &cobra.Command{
@@ -23,7 +24,11 @@ var envUnsetCmd = &cobra.Command{
 The env file is located at:
   ~/.doku/services/<service>.env
 
-The service will need to be restarted for changes to take effect.
+Variables the service's catalog entry marks as required can't be unset
+unless you pass --force.
+
+The change only takes effect once the container is recreated. Doku
+offers to recreate it; --recreate recreates it without asking.
 
 Examples:
   # Unset a single environment variable
@@ -32,15 +37,17 @@ Examples:
   # Unset multiple environment variables
   doku env unset frontend API_URL NODE_ENV
 
-  # Unset and automatically restart
-  doku env unset redis REDIS_PASSWORD --restart`,
+  # Unset and recreate the container without asking
+  doku env unset redis REDIS_PASSWORD --recreate`,
 	Args: cobra.MinimumNArgs(2),
 	RunE: runEnvUnset,
 }
 
 func init() {
 	envCmd.AddCommand(envUnsetCmd)
-	envUnsetCmd.Flags().BoolVarP(&envUnsetRestart, "restart", "r", false, "Restart service after unsetting variables")
+	envUnsetCmd.Flags().BoolVar(&envUnsetRestart, "recreate", false, "Recreate the service without asking so the changes take effect")
+	envUnsetCmd.Flags().BoolVarP(&envUnsetRestart, "restart", "r", false, "Same as --recreate")
+	envUnsetCmd.Flags().BoolVar(&envUnsetForce, "force", false, "Unset variables the catalog marks as required")
 }
 
 func runEnvUnset(cmd *cobra.Command, args []string) error {
@@ -75,6 +82,12 @@ func runEnvUnset(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("service '%s' not found", instanceName)
 	}
 
+	if !envUnsetForce {
+		if err := checkEnvOptions(cfgMgr, instance, nil, keys); err != nil {
+			return err
+		}
+	}
+
 	isCustomProject := instance.ServiceType == "custom-project"
 
 	// Get env file path
@@ -97,18 +110,18 @@ func runEnvUnset(cmd *cobra.Command, args []string) error {
 	fmt.Println()
 
 	// Remove keys
-	removedCount := 0
+	var removed []string
 	for _, key := range keys {
 		if _, exists := existingEnv[key]; exists {
 			fmt.Printf("  %s %s\n", color.RedString("✗"), key)
 			delete(existingEnv, key)
-			removedCount++
+			removed = append(removed, key)
 		} else {
 			fmt.Printf("  %s %s (not found)\n", color.YellowString("⚠"), key)
 		}
 	}
 
-	if removedCount == 0 {
+	if len(removed) == 0 {
 		fmt.Println()
 		color.Yellow("No environment variables were removed")
 		return nil
@@ -120,22 +133,8 @@ func runEnvUnset(cmd *cobra.Command, args []string) error {
 	}
 
 	fmt.Println()
-	color.Green("✓ Removed %d environment variable(s) from %s", removedCount, envPath)
+	color.Green("✓ Removed %d environment variable(s) from %s", len(removed), envPath)
 	fmt.Println()
 
-	// Restart if requested
-	if envUnsetRestart {
-		color.Cyan("Recreating container to apply changes...")
-		if err := serviceMgr.Recreate(instanceName); err != nil {
-			return fmt.Errorf("failed to recreate service: %w", err)
-		}
-		color.Green("✓ Service recreated")
-		fmt.Println()
-	} else {
-		color.Yellow("⚠️  Note: Service needs to be restarted for changes to take effect")
-		fmt.Printf("   Run: doku restart %s\n", instanceName)
-		fmt.Println()
-	}
-
-	return nil
+	return applyEnvChanges(dockerClient, cfgMgr, serviceMgr, instance, existingEnv, removed, envUnsetRestart)
 }
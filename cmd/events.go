@@ -0,0 +1,123 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/dokulabs/doku-cli/internal/events"
+	"github.com/dokulabs/doku-cli/pkg/types"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+var eventsWatchInterval time.Duration
+
+var eventsCmd = &cobra.Command{
+	Use:   "events",
+	Short: "Watch service events",
+	Long: `Watch service events: services being installed, started, stopped or
+removed, and failing.
+
+Events are sent to the webhooks set up with 'doku webhook'.`,
+}
+
+var eventsWatchCmd = &cobra.Command{
+	Use:   "watch",
+	Short: "Print events and notify webhooks as services fail",
+	Long: `Follow Docker events, printing service events as they happen and sending
+them to the webhooks: services that crash, are killed for running out of
+memory, stop unexpectedly or keep restarting (crash-loop) raise a fail
+event.
+
+Keep it running in a terminal, or start it with your session, to be
+notified of failures of services running in the background.
+
+Examples:
+  doku events watch
+  doku events watch --interval 10s`,
+	Args: cobra.NoArgs,
+	RunE: runEventsWatch,
+}
+
+func init() {
+	rootCmd.AddCommand(eventsCmd)
+	eventsCmd.AddCommand(eventsWatchCmd)
+
+	eventsWatchCmd.Flags().DurationVar(&eventsWatchInterval, "interval", 30*time.Second, "How often to check for crash loops")
+}
+
+func runEventsWatch(cmd *cobra.Command, args []string) error {
+	if eventsWatchInterval < time.Second {
+		return fmt.Errorf("--interval must be at least 1s")
+	}
+
+	cfgMgr, err := initConfigManager()
+	if err != nil {
+		if err == types.ErrNotInitialized {
+			return nil
+		}
+		return err
+	}
+
+	dockerClient, err := initDockerClient()
+	if err != nil {
+		return err
+	}
+	defer dockerClient.Close()
+
+	serviceMgr := getServiceManager(dockerClient, cfgMgr)
+
+	events.Subscribe(printEvent)
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	color.Cyan("Watching services. Press Ctrl+C to stop")
+
+	go serviceMgr.Watch(ctx, nil)
+
+	ticker := time.NewTicker(eventsWatchInterval)
+	defer ticker.Stop()
+
+	// Crash loops are reported once, until the service stops restarting
+	looping := make(map[string]bool)
+	for {
+		restarting, err := serviceMgr.Restarting(ctx)
+		if err != nil && ctx.Err() == nil {
+			color.Red("✗ %v", err)
+		}
+		current := make(map[string]bool, len(restarting))
+		for _, name := range restarting {
+			current[name] = true
+			if !looping[name] {
+				events.Emit(events.New(events.TypeFail, name, "keeps restarting (crash loop)"))
+			}
+		}
+		if err == nil {
+			looping = current
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// printEvent prints an event as a line of the watch output
+func printEvent(e events.Event) {
+	line := fmt.Sprintf("%s  %-8s %s: %s", e.Time.Format("15:04:05"), e.Type, e.Service, e.Message)
+	switch e.Type {
+	case events.TypeFail:
+		color.Red("%s", line)
+	case events.TypeStop, events.TypeRemove:
+		color.Yellow("%s", line)
+	default:
+		color.Green("%s", line)
+	}
+}
@@ -2,6 +2,7 @@ package cmd
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
 
@@ -21,21 +22,26 @@ var (
 )
 
 var execCmd = &cobra.Command{
-	Use:   "exec <service> [command...]",
+	Use:   "exec [flags] <service> [--] [command...]",
 	Short: "Execute a command in a running service container",
 	Long: `Execute a command inside a running service container.
 
 If no command is specified, starts an interactive shell (bash or sh).
 
+Flags go before the service name; everything after it is the command, so
+the command's own flags don't need quoting. A TTY is allocated when stdin
+is a terminal; the terminal is switched to raw mode and resizes are passed
+on to the container. The command's exit code becomes doku's exit code.
+
 Examples:
-  doku exec postgres                    # Open shell in postgres container
-  doku exec postgres psql -U postgres   # Run psql command
-  doku exec redis redis-cli             # Run redis-cli
-  doku exec myapp -- npm run migrate    # Run npm command (use -- for args)
-  doku exec myapp -u root bash          # Run as root user`,
-	Args:               cobra.MinimumNArgs(1),
-	DisableFlagParsing: false,
-	RunE:               runExec,
+  doku exec postgres                       # Open shell in postgres container
+  doku exec postgres -- psql -U postgres   # Run psql command
+  doku exec redis redis-cli                # Run redis-cli
+  doku exec myapp -- npm run migrate       # Run npm command
+  doku exec -u root myapp bash             # Run as root user
+  doku exec -i=true -t=false postgres -- psql -U postgres < dump.sql`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: runExec,
 }
 
 func init() {
@@ -43,7 +49,10 @@ func init() {
 
 	execCmd.Flags().StringVarP(&execContainer, "container", "c", "", "Container name (for multi-container services)")
 	execCmd.Flags().BoolVarP(&execInteractive, "interactive", "i", true, "Keep STDIN open")
-	execCmd.Flags().BoolVarP(&execTTY, "tty", "t", true, "Allocate a pseudo-TTY")
+	execCmd.Flags().BoolVarP(&execTTY, "tty", "t", true, "Allocate a pseudo-TTY (default: when stdin is a terminal)")
+
+	// Stop parsing flags at the service name so the command keeps its own flags
+	execCmd.Flags().SetInterspersed(false)
 	execCmd.Flags().StringVarP(&execUser, "user", "u", "", "Username or UID")
 	execCmd.Flags().StringVarP(&execWorkdir, "workdir", "w", "", "Working directory inside the container")
 }
@@ -53,6 +62,9 @@ func runExec(cmd *cobra.Command, args []string) error {
 
 	// Determine the command to run
 	var execCommand []string
+	if len(args) > 1 && args[1] == "--" {
+		args = append(args[:1], args[2:]...)
+	}
 	if len(args) > 1 {
 		execCommand = args[1:]
 	} else {
@@ -80,8 +92,8 @@ func runExec(cmd *cobra.Command, args []string) error {
 
 	// Handle Traefik specially
 	var containerName string
-	if instanceName == "traefik" || instanceName == "doku-traefik" {
-		containerName = "doku-traefik"
+	if proxyContainer, ok := proxyContainerName(instanceName); ok {
+		containerName = proxyContainer
 	} else {
 		// Get service instance
 		serviceMgr := service.NewManager(dockerClient, cfgMgr)
@@ -133,13 +145,19 @@ func runExec(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("container is not running. Start it first with: doku start %s", instanceName)
 	}
 
+	// A TTY on a pipe mangles the data, so only default to one on a terminal
+	tty := execTTY
+	if !cmd.Flags().Changed("tty") {
+		tty = docker.IsTerminal(os.Stdin)
+	}
+
 	// Execute command
 	ctx := context.Background()
 	execOpts := docker.ExecOptions{
 		Container:   containerName,
 		Command:     execCommand,
 		Interactive: execInteractive,
-		TTY:         execTTY,
+		TTY:         tty,
 		User:        execUser,
 		WorkDir:     execWorkdir,
 		Stdin:       os.Stdin,
@@ -147,5 +165,18 @@ func runExec(cmd *cobra.Command, args []string) error {
 		Stderr:      os.Stderr,
 	}
 
-	return dockerClient.Exec(ctx, execOpts)
+	return execWithExitCode(ctx, dockerClient, execOpts)
+}
+
+// execWithExitCode runs an exec and exits with the command's exit code when
+// it fails, like running the command locally would
+func execWithExitCode(ctx context.Context, dockerClient *docker.Client, opts docker.ExecOptions) error {
+	err := dockerClient.Exec(ctx, opts)
+
+	var exitErr *docker.ExecExitError
+	if errors.As(err, &exitErr) {
+		dockerClient.Close()
+		os.Exit(exitErr.Code)
+	}
+	return err
 }
@@ -0,0 +1,259 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/dokulabs/doku-cli/internal/compose"
+	"github.com/dokulabs/doku-cli/internal/docker"
+	"github.com/dokulabs/doku-cli/internal/traefik"
+	"github.com/dokulabs/doku-cli/pkg/types"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+var (
+	composeOutput      string
+	composeName        string
+	composeNoTraefik   bool
+	composeMaskSecrets bool
+)
+
+var exportCmd = &cobra.Command{
+	Use:   "export [service...]",
+	Short: "Export services as a docker-compose.yml",
+	Long: `Export installed services as a docker-compose file.
+
+The compose file is generated from the running containers, so it includes
+the exact images, environment, ports, volumes, network aliases and Traefik
+labels doku configured. Multi-container services become one compose service
+per container, and custom projects are exported with a build section.
+
+The output can be shared with teammates who don't use doku:
+  docker compose -f docker-compose.yml up -d
+
+Note: environment variables usually contain passwords. Use --mask-secrets
+before sharing the file outside your machine.
+
+Examples:
+  doku export                              # Print all services to stdout
+  doku export -o docker-compose.yml        # Write to a file
+  doku export postgres redis               # Export selected services only
+  doku export --no-traefik                 # Skip the Traefik proxy
+  doku export --mask-secrets               # Replace secret values`,
+	RunE: runExport,
+}
+
+func init() {
+	rootCmd.AddCommand(exportCmd)
+
+	exportCmd.Flags().StringVarP(&composeOutput, "output", "o", "", "Output file (default: stdout)")
+	exportCmd.Flags().StringVar(&composeName, "name", "doku", "Compose project name")
+	exportCmd.Flags().BoolVar(&composeNoTraefik, "no-traefik", false, "Do not include the Traefik proxy")
+	exportCmd.Flags().BoolVar(&composeMaskSecrets, "mask-secrets", false, "Replace sensitive environment values with placeholders")
+}
+
+func runExport(cmd *cobra.Command, args []string) error {
+	// Initialize config manager
+	cfgMgr, err := initConfigManager()
+	if err != nil {
+		if err == types.ErrNotInitialized {
+			return nil
+		}
+		return err
+	}
+
+	// Initialize Docker client
+	dockerClient, err := initDockerClient()
+	if err != nil {
+		return err
+	}
+	defer dockerClient.Close()
+
+	serviceMgr := getServiceManager(dockerClient, cfgMgr)
+	instances, err := serviceMgr.List()
+	if err != nil {
+		return fmt.Errorf("failed to list services: %w", err)
+	}
+
+	// Filter to the requested services
+	if len(args) > 0 {
+		byName := make(map[string]*types.Instance, len(instances))
+		for _, instance := range instances {
+			byName[instance.Name] = instance
+		}
+
+		var selected []*types.Instance
+		for _, name := range args {
+			instance, ok := byName[name]
+			if !ok {
+				return fmt.Errorf("service '%s' not found. Use 'doku list' to see installed services", name)
+			}
+			selected = append(selected, instance)
+		}
+		instances = selected
+	}
+
+	outputDir := "."
+	if composeOutput != "" {
+		outputDir = filepath.Dir(composeOutput)
+	}
+
+	file := compose.NewFile(composeName)
+	var warnings []string
+
+	// Compose service names of each instance, used to resolve depends_on
+	serviceNames := make(map[string][]string)
+
+	for _, instance := range instances {
+		// Compose-based projects are exported like multi-container services below
+		if instance.ServiceType == "custom-project" && !instance.IsMultiContainer {
+			project, err := cfgMgr.GetProject(instance.Name)
+			if err != nil {
+				warnings = append(warnings, fmt.Sprintf("skipped project %s: %v", instance.Name, err))
+				continue
+			}
+
+			svc, err := exportContainer(dockerClient, file, instance.ContainerName)
+			if err != nil {
+				warnings = append(warnings, fmt.Sprintf("skipped project %s: %v", instance.Name, err))
+				continue
+			}
+
+			// Teammates don't have the locally built image, so build from source
+			buildContext := project.Path
+			if rel, err := filepath.Rel(outputDir, project.Path); err == nil {
+				buildContext = rel
+			}
+			svc.Build = &compose.Build{Context: buildContext, Dockerfile: project.Dockerfile}
+
+			file.Services[instance.Name] = svc
+			serviceNames[instance.Name] = []string{instance.Name}
+			continue
+		}
+
+		if !instance.IsMultiContainer {
+			svc, err := exportContainer(dockerClient, file, instance.ContainerName)
+			if err != nil {
+				warnings = append(warnings, fmt.Sprintf("skipped %s: %v", instance.Name, err))
+				continue
+			}
+
+			file.Services[instance.Name] = svc
+			serviceNames[instance.Name] = []string{instance.Name}
+			continue
+		}
+
+		// Multi-container: one compose service per container
+		var primary string
+		var others []string
+		for _, c := range instance.Containers {
+			svc, err := exportContainer(dockerClient, file, c.FullName)
+			if err != nil {
+				warnings = append(warnings, fmt.Sprintf("skipped %s/%s: %v", instance.Name, c.Name, err))
+				continue
+			}
+
+			name := fmt.Sprintf("%s-%s", instance.Name, c.Name)
+			file.Services[name] = svc
+			serviceNames[instance.Name] = append(serviceNames[instance.Name], name)
+			if c.Primary {
+				primary = name
+			} else {
+				others = append(others, name)
+			}
+		}
+
+		// The primary container starts after the rest of the service
+		if primary != "" {
+			file.Services[primary].DependsOn = append(file.Services[primary].DependsOn, others...)
+		}
+	}
+
+	// Resolve dependencies between instances
+	for _, instance := range instances {
+		var deps []string
+		for _, dep := range instance.Dependencies {
+			deps = append(deps, serviceNames[dep]...)
+		}
+		if len(deps) == 0 {
+			continue
+		}
+
+		for _, name := range serviceNames[instance.Name] {
+			file.Services[name].DependsOn = append(file.Services[name].DependsOn, deps...)
+		}
+	}
+
+	// Include the Traefik proxy so the *.doku.local routes keep working
+	if !composeNoTraefik {
+		svc, err := exportContainer(dockerClient, file, traefik.ContainerName())
+		if err != nil {
+			warnings = append(warnings, fmt.Sprintf("skipped Traefik: %v", err))
+		} else {
+			file.Services["traefik"] = svc
+			for _, volume := range svc.Volumes {
+				if strings.HasPrefix(volume, "/") {
+					warnings = append(warnings, "Traefik mounts config and certificates from host paths; copy them along with the compose file")
+					break
+				}
+			}
+		}
+	}
+
+	if len(file.Services) == 0 {
+		color.Yellow("No services to export")
+		return nil
+	}
+
+	if composeMaskSecrets {
+		for _, svc := range file.Services {
+			for key := range svc.Environment {
+				if isSensitiveKey(key) {
+					svc.Environment[key] = "CHANGE_ME"
+				}
+			}
+		}
+	}
+
+	if composeOutput == "" {
+		data, err := file.Marshal()
+		if err != nil {
+			return err
+		}
+		fmt.Print(string(data))
+	} else {
+		if err := file.WriteFile(composeOutput); err != nil {
+			return err
+		}
+		color.Green("✓ Exported %d compose service(s) to %s", len(file.Services), composeOutput)
+		if !composeMaskSecrets {
+			color.Yellow("⚠️  The file contains passwords and other secrets from your services")
+		}
+	}
+
+	// Keep stdout clean for piping
+	for _, warning := range warnings {
+		fmt.Fprintln(os.Stderr, color.YellowString("⚠️  %s", warning))
+	}
+
+	return nil
+}
+
+// exportContainer converts a running container into a compose service
+func exportContainer(dockerClient *docker.Client, file *compose.File, containerName string) (*compose.Service, error) {
+	info, err := dockerClient.ContainerInspect(containerName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to inspect container: %w", err)
+	}
+
+	// Image defaults are left out to keep the file readable
+	imageInfo, _, err := dockerClient.ImageInspectWithRaw(info.Image)
+	if err != nil {
+		return file.ServiceFromContainer(info, nil), nil
+	}
+
+	return file.ServiceFromContainer(info, imageInfo.Config), nil
+}
@@ -0,0 +1,414 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+
+	"github.com/dokulabs/doku-cli/internal/caddy"
+	"github.com/dokulabs/doku-cli/internal/certs"
+	"github.com/dokulabs/doku-cli/internal/config"
+	"github.com/dokulabs/doku-cli/internal/docker"
+	"github.com/dokulabs/doku-cli/internal/domain"
+	"github.com/dokulabs/doku-cli/internal/lan"
+	"github.com/dokulabs/doku-cli/internal/proxy"
+	"github.com/dokulabs/doku-cli/internal/service"
+	"github.com/dokulabs/doku-cli/pkg/types"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+var (
+	exposeBasicAuth      []string
+	exposeAllowIPs       []string
+	exposeRateLimit      int
+	exposeRateBurst      int
+	exposeHeaders        []string
+	exposeRequestHeaders []string
+	exposeClear          bool
+	exposeLAN            bool
+	exposeLANIP          string
+	exposeMDNS           bool
+)
+
+var exposeCmd = &cobra.Command{
+	Use:   "expose <service>",
+	Short: "Put basic auth, an IP allow list, a rate limit or headers in front of a service",
+	Long: `Attach Traefik middlewares to a service's route: requests to its URL go
+through them before reaching it.
+
+  --basic-auth       ask for a user and password (the password is hashed)
+  --allow-ip         only let IPs or CIDR ranges through
+  --rate-limit       limit each client to a number of requests per second
+  --header           add a header to the responses
+  --request-header   add a header to the requests
+
+Each kind of flag given replaces that kind's settings and keeps the others;
+--clear removes them all. The service is recreated to apply them, and they
+are kept across upgrades and recreates. Without flags, the current settings
+are shown.
+
+--lan lets phones and other devices of your network reach the service
+through Traefik, at <service>.<LAN IP>.nip.io: nip.io is a public DNS
+service resolving such names to the IP in them. With --mdns, it's also
+reachable at <service>.local, announced over mDNS while the command runs;
+run it again to announce it again. --lan=false stops exposing it.
+
+Examples:
+  doku expose api --basic-auth admin:s3cret
+  doku expose api --allow-ip 192.168.1.0/24 --allow-ip 10.0.0.5
+  doku expose api --rate-limit 100 --rate-burst 50
+  doku expose api --header X-Frame-Options:DENY
+  doku expose api --clear
+  doku expose api --lan
+  doku expose api --lan --mdns    # Announce api.local until Ctrl+C
+  doku expose api --lan=false
+  doku expose api                 # Show the current settings`,
+	Args: cobra.ExactArgs(1),
+	RunE: runExpose,
+}
+
+func init() {
+	rootCmd.AddCommand(exposeCmd)
+
+	exposeCmd.Flags().StringArrayVar(&exposeBasicAuth, "basic-auth", []string{}, "User allowed in (user:password). Can be specified multiple times")
+	exposeCmd.Flags().StringSliceVar(&exposeAllowIPs, "allow-ip", []string{}, "IP or CIDR range allowed in. Can be specified multiple times")
+	exposeCmd.Flags().IntVar(&exposeRateLimit, "rate-limit", 0, "Requests per second allowed per client, on average")
+	exposeCmd.Flags().IntVar(&exposeRateBurst, "rate-burst", 0, "Requests allowed at once above the rate limit")
+	exposeCmd.Flags().StringArrayVar(&exposeHeaders, "header", []string{}, "Response header to add (Name:Value). Can be specified multiple times")
+	exposeCmd.Flags().StringArrayVar(&exposeRequestHeaders, "request-header", []string{}, "Request header to add (Name:Value). Can be specified multiple times")
+	exposeCmd.Flags().BoolVar(&exposeClear, "clear", false, "Remove all middlewares")
+	exposeCmd.Flags().BoolVar(&exposeLAN, "lan", false, "Let the other devices of the network reach the service (--lan=false to stop)")
+	exposeCmd.Flags().StringVar(&exposeLANIP, "lan-ip", "", "This machine's IP on the network (default: detected)")
+	exposeCmd.Flags().BoolVar(&exposeMDNS, "mdns", false, "Also announce <service>.local over mDNS, until Ctrl+C")
+}
+
+func runExpose(cmd *cobra.Command, args []string) error {
+	name := args[0]
+
+	cfgMgr, err := initConfigManager()
+	if err != nil {
+		if err == types.ErrNotInitialized {
+			return nil
+		}
+		return err
+	}
+
+	instance, err := cfgMgr.GetInstance(name)
+	if err != nil {
+		return fmt.Errorf("service '%s' not found. Use 'doku list' to see installed services", name)
+	}
+
+	flags := cmd.Flags()
+	changed := false
+	for _, flag := range []string{"basic-auth", "allow-ip", "rate-limit", "rate-burst", "header", "request-header"} {
+		changed = changed || flags.Changed(flag)
+	}
+	lanChanged := flags.Changed("lan") || flags.Changed("lan-ip") || flags.Changed("mdns")
+	if exposeClear && changed {
+		return fmt.Errorf("--clear can't be combined with other flags")
+	}
+	if !exposeClear && !changed && !lanChanged {
+		printMiddlewares(instance)
+		printLAN(cfgMgr, instance)
+		return nil
+	}
+	if lanChanged && !exposeLAN && (flags.Changed("lan-ip") || exposeMDNS) {
+		return fmt.Errorf("--lan-ip and --mdns go with --lan")
+	}
+
+	cfg, err := cfgMgr.Get()
+	if err != nil {
+		return err
+	}
+	if proxy.Backend(cfg.Preferences) == proxy.Caddy {
+		if len(exposeBasicAuth) > 0 {
+			return fmt.Errorf("--basic-auth needs the Traefik proxy: Caddy can't check its passwords")
+		}
+		if exposeRateLimit > 0 {
+			color.Yellow("⚠️  Caddy has no rate limiter: the rate limit only applies under the Traefik proxy")
+		}
+	}
+
+	dockerClient, err := initDockerClient()
+	if err != nil {
+		return err
+	}
+	defer dockerClient.Close()
+
+	if exposeClear || changed {
+		var mw *types.Middlewares
+		if !exposeClear {
+			mw, err = exposeMiddlewares(cmd, instance.Middlewares)
+			if err != nil {
+				return err
+			}
+		}
+
+		if err := service.NewManager(dockerClient, cfgMgr).SetMiddlewares(name, mw); err != nil {
+			return err
+		}
+
+		if mw.IsEmpty() {
+			color.Green("✓ Removed the middlewares of %s", name)
+		} else {
+			color.Green("✓ Updated the middlewares of %s", name)
+			instance.Middlewares = mw
+			printMiddlewares(instance)
+		}
+	}
+
+	if lanChanged {
+		return exposeOnLAN(cfgMgr, dockerClient, instance)
+	}
+	return nil
+}
+
+// exposeOnLAN exposes an instance to the other devices of the network, or
+// stops exposing it with --lan=false, and with --mdns announces its .local
+// hostname until Ctrl+C
+func exposeOnLAN(cfgMgr *config.Manager, dockerClient *docker.Client, instance *types.Instance) error {
+	name := instance.Name
+	serviceMgr := service.NewManager(dockerClient, cfgMgr)
+
+	if !exposeLAN {
+		if instance.LAN == nil {
+			fmt.Printf("%s isn't exposed to the network\n", name)
+			return nil
+		}
+		if err := serviceMgr.SetLAN(name, nil); err != nil {
+			return err
+		}
+		color.Green("✓ %s is no longer exposed to the network", name)
+		return nil
+	}
+
+	var ip net.IP
+	var err error
+	if exposeLANIP != "" {
+		ip, err = lan.ParseIP(exposeLANIP)
+	} else {
+		ip, err = lan.DetectIP()
+	}
+	if err != nil {
+		return err
+	}
+
+	if !service.IsRouted(instance) {
+		return printDirectLANAddresses(instance, ip)
+	}
+
+	cfg, err := cfgMgr.Get()
+	if err != nil {
+		return err
+	}
+	exposure := &types.LANExposure{IP: ip.String(), MDNS: exposeMDNS}
+	hosts := domain.LANHostnames(domain.InstanceSubdomain(instance), exposure)
+	if err := ensureCertificates(cfgMgr, dockerClient, cfg, hosts); err != nil {
+		return err
+	}
+
+	if instance.LAN == nil || *instance.LAN != *exposure {
+		if err := serviceMgr.SetLAN(name, exposure); err != nil {
+			return err
+		}
+		color.Green("✓ %s is exposed to the network", name)
+		instance.LAN = exposure
+	}
+	printLAN(cfgMgr, instance)
+	if cfg.Preferences.Protocol == "https" && certs.Source(cfg.Certificates) != certs.SourceProvided {
+		caCert := cfg.Certificates.CACert
+		if proxy.Backend(cfg.Preferences) == proxy.Caddy {
+			caCert = caddy.NewManager(dockerClient, cfgMgr.GetCaddyDir(), cfg.Preferences.Protocol).CACertPath()
+		}
+		color.Yellow("⚠️  Devices that don't trust Doku's CA (%s) will warn about the certificate", caCert)
+	}
+
+	if !exposure.MDNS {
+		return nil
+	}
+	// The routes are otherwise synced once the command ends, after Ctrl+C
+	if err := proxy.SyncRoutes(dockerClient, cfgMgr); err != nil {
+		return err
+	}
+	local := hosts[len(hosts)-1]
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	fmt.Printf("Announcing %s over mDNS. Press Ctrl+C to stop\n", local)
+	if err := lan.Announce(ctx, []string{local}, ip); err != nil {
+		return err
+	}
+	fmt.Println()
+	color.Green("✓ Stopped announcing %s", local)
+	return nil
+}
+
+// printDirectLANAddresses prints where the ports of an instance Traefik
+// doesn't route to are reachable from the network: they're published on
+// every interface
+func printDirectLANAddresses(instance *types.Instance, ip net.IP) error {
+	switch {
+	case instance.UsesHostNetwork():
+		fmt.Printf("%s uses the host's network: its ports are reachable on the network at %s\n", instance.Name, ip)
+	case len(instance.Network.PortMappings) > 0:
+		fmt.Printf("%s isn't routed through Traefik; its published ports are reachable on the network at:\n", instance.Name)
+		for _, containerPort := range mapKeys(instance.Network.PortMappings) {
+			fmt.Printf("  %s:%s (→ %s)\n", ip, instance.Network.PortMappings[containerPort], containerPort)
+		}
+	default:
+		return fmt.Errorf("'%s' isn't routed through Traefik and publishes no port; reinstall it with --port to reach it from the network", instance.Name)
+	}
+	return nil
+}
+
+// printLAN prints the URLs an instance exposed to the network is reachable
+// at from other devices
+func printLAN(cfgMgr *config.Manager, instance *types.Instance) {
+	if instance.LAN == nil {
+		return
+	}
+	cfg, err := cfgMgr.Get()
+	if err != nil {
+		return
+	}
+	httpPort, httpsPort := cfgMgr.GetTraefikPorts()
+	fmt.Println("Reachable from the network at:")
+	for _, host := range domain.LANHostnames(domain.InstanceSubdomain(instance), instance.LAN) {
+		fmt.Printf("  %s\n", config.ServiceURL(cfg.Preferences.Protocol, host, httpPort, httpsPort))
+	}
+}
+
+// exposeMiddlewares returns current with the kinds of middlewares whose
+// flags were given replaced
+func exposeMiddlewares(cmd *cobra.Command, current *types.Middlewares) (*types.Middlewares, error) {
+	mw := &types.Middlewares{}
+	if current != nil {
+		*mw = *current
+	}
+	flags := cmd.Flags()
+
+	if flags.Changed("basic-auth") {
+		mw.BasicAuth = nil
+		for _, credentials := range exposeBasicAuth {
+			if credentials == "" {
+				continue
+			}
+			entry, err := docker.BasicAuthUser(credentials)
+			if err != nil {
+				return nil, err
+			}
+			mw.BasicAuth = append(mw.BasicAuth, entry)
+		}
+	}
+
+	if flags.Changed("allow-ip") {
+		mw.IPAllowList = nil
+		for _, source := range exposeAllowIPs {
+			if source = strings.TrimSpace(source); source != "" {
+				mw.IPAllowList = append(mw.IPAllowList, source)
+			}
+		}
+	}
+
+	if flags.Changed("rate-limit") || flags.Changed("rate-burst") {
+		limit := types.RateLimit{}
+		if mw.RateLimit != nil {
+			limit = *mw.RateLimit
+		}
+		if flags.Changed("rate-limit") {
+			limit.Average = exposeRateLimit
+		}
+		if flags.Changed("rate-burst") {
+			limit.Burst = exposeRateBurst
+		}
+		mw.RateLimit = &limit
+		// A rate limit of 0 turns it off
+		if limit.Average == 0 {
+			mw.RateLimit = nil
+		}
+	}
+
+	for _, header := range []struct {
+		flag   string
+		values []string
+		target *map[string]string
+	}{
+		{"header", exposeHeaders, &mw.Headers},
+		{"request-header", exposeRequestHeaders, &mw.RequestHeaders},
+	} {
+		if !flags.Changed(header.flag) {
+			continue
+		}
+		*header.target = nil
+		for _, value := range header.values {
+			if value == "" {
+				continue
+			}
+			key, val, ok := strings.Cut(value, ":")
+			if !ok {
+				return nil, fmt.Errorf("invalid --%s: %s (expected Name:Value)", header.flag, value)
+			}
+			if *header.target == nil {
+				*header.target = make(map[string]string)
+			}
+			(*header.target)[strings.TrimSpace(key)] = strings.TrimSpace(val)
+		}
+	}
+
+	if err := docker.ValidateMiddlewares(mw); err != nil {
+		return nil, err
+	}
+	return mw, nil
+}
+
+// printMiddlewares prints the middlewares in front of an instance
+func printMiddlewares(instance *types.Instance) {
+	mw := instance.Middlewares
+	if mw.IsEmpty() {
+		fmt.Printf("No middlewares in front of %s\n", instance.Name)
+		return
+	}
+
+	fmt.Println()
+	printMiddlewareSettings(mw)
+	fmt.Println()
+}
+
+// printMiddlewareSettings prints the settings of middlewares, one per line
+func printMiddlewareSettings(mw *types.Middlewares) {
+	if len(mw.BasicAuth) > 0 {
+		users := make([]string, 0, len(mw.BasicAuth))
+		for _, entry := range mw.BasicAuth {
+			user, _, _ := strings.Cut(entry, ":")
+			users = append(users, user)
+		}
+		fmt.Printf("  Basic auth:      %s\n", strings.Join(users, ", "))
+	}
+	if len(mw.IPAllowList) > 0 {
+		fmt.Printf("  Allowed IPs:     %s\n", strings.Join(mw.IPAllowList, ", "))
+	}
+	if mw.RateLimit != nil {
+		fmt.Printf("  Rate limit:      %d req/s", mw.RateLimit.Average)
+		if mw.RateLimit.Burst > 0 {
+			fmt.Printf(", bursts of %d", mw.RateLimit.Burst)
+		}
+		fmt.Println()
+	}
+	printHeaders("Response headers", mw.Headers)
+	printHeaders("Request headers", mw.RequestHeaders)
+}
+
+// printHeaders prints the headers a middleware adds, sorted
+func printHeaders(title string, headers map[string]string) {
+	if len(headers) == 0 {
+		return
+	}
+	fmt.Printf("  %s:\n", title)
+	for _, name := range mapKeys(headers) {
+		fmt.Printf("    %s: %s\n", name, headers[name])
+	}
+}
@@ -0,0 +1,155 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/dokulabs/doku-cli/internal/config"
+	"github.com/dokulabs/doku-cli/pkg/types"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+var groupCmd = &cobra.Command{
+	Use:   "group",
+	Short: "Manage named groups of services",
+	Long: `Manage named groups of services and projects.
+
+A group can be used wherever a command takes @group instead of a single
+service, to operate on all its members at once.
+
+Examples:
+  doku group set backend postgres redis api   # Define a group
+  doku group list                             # Show all groups
+  doku start @backend                         # Start the group
+  doku logs @backend -f                       # Follow logs of the group
+  doku group remove backend                   # Delete the group`,
+}
+
+var groupSetCmd = &cobra.Command{
+	Use:   "set <group> <service>...",
+	Short: "Create or replace a group",
+	Long: `Create a group, or replace the members of an existing one.
+
+Members must be installed services or projects.
+
+Example:
+  doku group set backend postgres redis api`,
+	Args: cobra.MinimumNArgs(2),
+	RunE: runGroupSet,
+}
+
+var groupListCmd = &cobra.Command{
+	Use:     "list",
+	Short:   "List groups and their members",
+	Aliases: []string{"ls"},
+	Args:    cobra.NoArgs,
+	RunE:    runGroupList,
+}
+
+var groupRemoveCmd = &cobra.Command{
+	Use:     "remove <group>",
+	Short:   "Delete a group",
+	Long:    `Delete a group. Its members are not affected.`,
+	Aliases: []string{"rm"},
+	Args:    cobra.ExactArgs(1),
+	RunE:    runGroupRemove,
+}
+
+func init() {
+	rootCmd.AddCommand(groupCmd)
+
+	groupCmd.AddCommand(groupSetCmd)
+	groupCmd.AddCommand(groupListCmd)
+	groupCmd.AddCommand(groupRemoveCmd)
+}
+
+func runGroupSet(cmd *cobra.Command, args []string) error {
+	name := strings.TrimPrefix(args[0], config.GroupPrefix)
+	members := args[1:]
+
+	cfgMgr, err := initConfigManager()
+	if err != nil {
+		if err == types.ErrNotInitialized {
+			return nil
+		}
+		return err
+	}
+
+	var missing []string
+	for _, member := range members {
+		if cfgMgr.HasInstance(member) {
+			continue
+		}
+		if _, err := cfgMgr.GetProject(member); err == nil {
+			continue
+		}
+		missing = append(missing, member)
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("not installed: %s. Use 'doku list' to see installed services", strings.Join(missing, ", "))
+	}
+
+	if err := cfgMgr.SetGroup(name, members); err != nil {
+		return err
+	}
+
+	color.Green("✓ Group %s%s: %s", config.GroupPrefix, name, strings.Join(members, ", "))
+	return nil
+}
+
+func runGroupList(cmd *cobra.Command, args []string) error {
+	cfgMgr, err := initConfigManager()
+	if err != nil {
+		if err == types.ErrNotInitialized {
+			return nil
+		}
+		return err
+	}
+
+	names, err := cfgMgr.ListGroups()
+	if err != nil {
+		return err
+	}
+
+	if len(names) == 0 {
+		color.Yellow("No groups defined")
+		fmt.Println()
+		fmt.Println("Create one with: doku group set <group> <service>...")
+		return nil
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
+	fmt.Fprintln(w, "GROUP\tMEMBERS")
+	for _, name := range names {
+		members, err := cfgMgr.GetGroup(name)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(w, "%s%s\t%s\n", config.GroupPrefix, name, strings.Join(members, ", "))
+	}
+	w.Flush()
+
+	return nil
+}
+
+func runGroupRemove(cmd *cobra.Command, args []string) error {
+	name := strings.TrimPrefix(args[0], config.GroupPrefix)
+
+	cfgMgr, err := initConfigManager()
+	if err != nil {
+		if err == types.ErrNotInitialized {
+			return nil
+		}
+		return err
+	}
+
+	if err := cfgMgr.RemoveGroup(name); err != nil {
+		return err
+	}
+
+	color.Green("✓ Group %s%s removed", config.GroupPrefix, name)
+	return nil
+}
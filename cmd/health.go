@@ -317,9 +317,11 @@ func getServiceHealth(serviceMgr *service.Manager, dockerClient *docker.Client,
 
 	// Check network connectivity
 	networkMgr := docker.NewNetworkManager(dockerClient)
-	connected, _ := networkMgr.IsContainerConnected("doku-network", instance.ContainerName)
-	if connected {
-		health.NetworkStatus = color.GreenString("connected to doku-network")
+	connected, _ := networkMgr.IsContainerConnected(docker.NetworkName(), instance.ContainerName)
+	if instance.UsesHostNetwork() {
+		health.NetworkStatus = color.CyanString("host network")
+	} else if connected {
+		health.NetworkStatus = color.GreenString("connected to %s", docker.NetworkName())
 	} else {
 		health.NetworkStatus = color.YellowString("not connected")
 	}
@@ -0,0 +1,166 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/AlecAivazis/survey/v2"
+	"github.com/docker/docker/api/types/image"
+	"github.com/dokulabs/doku-cli/pkg/types"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+var (
+	imagePruneDryRun   bool
+	imagePruneYes      bool
+	imagePruneRollback bool
+)
+
+var imageCmd = &cobra.Command{
+	Use:   "image",
+	Short: "Manage the images of services",
+}
+
+var imagePruneCmd = &cobra.Command{
+	Use:   "prune",
+	Short: "Remove images left behind by upgrades",
+	Long: `Remove the images of old versions that no instance uses any more.
+
+Upgrades, 'doku restart --latest' and auto-updates leave the image the
+instance ran before behind. Prune removes the images of the repositories
+Doku's services run that no container, Doku's or not, uses.
+
+The image 'doku rollback' would go back to is kept unless --rollback is
+given.
+
+Examples:
+  doku image prune --dry-run    # List the images that would be removed
+  doku image prune              # Remove them, after confirming
+  doku image prune --rollback   # Remove the images rollback needs too`,
+	Args: cobra.NoArgs,
+	RunE: runImagePrune,
+}
+
+func init() {
+	rootCmd.AddCommand(imageCmd)
+	imageCmd.AddCommand(imagePruneCmd)
+
+	imagePruneCmd.Flags().BoolVar(&imagePruneDryRun, "dry-run", false, "List the images that would be removed without removing them")
+	imagePruneCmd.Flags().BoolVarP(&imagePruneYes, "yes", "y", false, "Skip confirmation prompt")
+	imagePruneCmd.Flags().BoolVar(&imagePruneRollback, "rollback", false, "Also remove the images rollback would restore")
+}
+
+func runImagePrune(cmd *cobra.Command, args []string) error {
+	cfgMgr, err := initConfigManager()
+	if err != nil {
+		if err == types.ErrNotInitialized {
+			return nil
+		}
+		return err
+	}
+	cfg, err := cfgMgr.Get()
+	if err != nil {
+		return err
+	}
+
+	dockerClient, err := initDockerClient()
+	if err != nil {
+		return err
+	}
+	defer dockerClient.Close()
+
+	unused, err := dockerClient.UnusedImages(context.Background(), keptImages(cfg, !imagePruneRollback))
+	if err != nil {
+		return err
+	}
+	if len(unused) == 0 {
+		color.Green("✓ No unused images")
+		return nil
+	}
+
+	var reclaimable int64
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
+	fmt.Fprintln(w, "IMAGE\tID\tSIZE")
+	for _, img := range unused {
+		fmt.Fprintf(w, "%s\t%s\t%s\n", imageDisplayName(img), shortImageID(img.ID), formatBytes(img.Size))
+		reclaimable += img.Size
+	}
+	w.Flush()
+	fmt.Println()
+
+	if imagePruneDryRun {
+		fmt.Printf("%d unused image(s), %s would be reclaimed\n", len(unused), formatBytes(reclaimable))
+		return nil
+	}
+
+	if !imagePruneYes {
+		confirm := false
+		prompt := &survey.Confirm{
+			Message: fmt.Sprintf("Remove %d image(s) (%s)?", len(unused), formatBytes(reclaimable)),
+			Default: false,
+		}
+		if err := survey.AskOne(prompt, &confirm); err != nil {
+			return fmt.Errorf("confirmation failed: %w", err)
+		}
+		if !confirm {
+			color.Yellow("Prune cancelled")
+			return nil
+		}
+	}
+
+	var removed int
+	var reclaimed int64
+	for _, img := range unused {
+		// No container uses the image, so forcing only removes all its
+		// tags at once
+		if err := dockerClient.ImageRemove(img.ID, true); err != nil {
+			color.Yellow("⚠️  Could not remove %s: %v", imageDisplayName(img), err)
+			continue
+		}
+		removed++
+		reclaimed += img.Size
+	}
+
+	color.Green("✓ Removed %d image(s), reclaimed %s", removed, formatBytes(reclaimed))
+	return nil
+}
+
+// keptImages returns the images the instances are pinned to and, with
+// rollback, those 'doku rollback' would restore
+func keptImages(cfg *types.Config, rollback bool) []string {
+	var keep []string
+	for _, instance := range cfg.Instances {
+		if instance.ImageDigest != "" {
+			keep = append(keep, instance.ImageDigest)
+		}
+		for _, c := range instance.Containers {
+			keep = append(keep, c.Image)
+			if c.ImageDigest != "" {
+				keep = append(keep, c.ImageDigest)
+			}
+		}
+		if rollback && instance.Previous != nil && instance.Previous.Image != "" {
+			keep = append(keep, instance.Previous.Image)
+		}
+	}
+	return keep
+}
+
+// imageDisplayName returns the name to show an image by: its first tag, or
+// else its first digest, or else its ID
+func imageDisplayName(img image.Summary) string {
+	for _, tag := range img.RepoTags {
+		if tag != "<none>:<none>" {
+			return tag
+		}
+	}
+	for _, digest := range img.RepoDigests {
+		if digest != "<none>@<none>" {
+			return digest
+		}
+	}
+	return shortImageID(img.ID)
+}
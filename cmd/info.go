@@ -8,7 +8,9 @@ import (
 	dockerTypes "github.com/docker/docker/api/types"
 	"github.com/dokulabs/doku-cli/internal/config"
 	"github.com/dokulabs/doku-cli/internal/docker"
+	"github.com/dokulabs/doku-cli/internal/domain"
 	"github.com/dokulabs/doku-cli/internal/service"
+	"github.com/dokulabs/doku-cli/internal/traefik"
 	"github.com/dokulabs/doku-cli/pkg/types"
 	"github.com/fatih/color"
 	"github.com/spf13/cobra"
@@ -67,7 +69,7 @@ func runInfo(cmd *cobra.Command, args []string) error {
 	defer dockerClient.Close()
 
 	// Special handling for Traefik
-	if instanceName == "traefik" || instanceName == "doku-traefik" {
+	if instanceName == "traefik" || instanceName == traefik.ContainerName() {
 		return displayTraefikInfo(cfg, dockerClient)
 	}
 
@@ -90,8 +92,18 @@ func runInfo(cmd *cobra.Command, args []string) error {
 	// Update status
 	updateStatus(instance, containerInfo)
 
+	// Links from the catalog, or registered for a project
+	var links []namedLink
+	if instance.ServiceType == "custom-project" {
+		if proj, err := cfgMgr.GetProject(instanceName); err == nil {
+			links = projectLinks(proj)
+		}
+	} else {
+		links = instanceLinks(cfgMgr, instance)
+	}
+
 	// Display information
-	displayServiceInfo(instance, cfg, containerInfo, infoShowEnv)
+	displayServiceInfo(instance, cfg, containerInfo, links, infoShowEnv)
 
 	return nil
 }
@@ -115,7 +127,7 @@ func updateStatus(instance *types.Instance, containerInfo dockerTypes.ContainerJ
 	}
 }
 
-func displayServiceInfo(instance *types.Instance, cfg *types.Config, containerInfo dockerTypes.ContainerJSON, showEnv bool) {
+func displayServiceInfo(instance *types.Instance, cfg *types.Config, containerInfo dockerTypes.ContainerJSON, links []namedLink, showEnv bool) {
 	// Header
 	fmt.Println()
 	statusIcon := getInfoStatusIcon(instance.Status)
@@ -127,6 +139,11 @@ func displayServiceInfo(instance *types.Instance, cfg *types.Config, containerIn
 	fmt.Println(strings.Repeat("=", len(instance.Name)+20))
 	fmt.Println()
 
+	if stoppedUnexpectedly(instance) {
+		color.Yellow("⚠️  Stopped, but not with 'doku stop'. Start it again with: doku resync")
+		fmt.Println()
+	}
+
 	// Basic Information
 	color.New(color.Bold).Println("Service Information")
 	fmt.Printf("  Type: %s\n", color.CyanString(instance.ServiceType))
@@ -134,6 +151,17 @@ func displayServiceInfo(instance *types.Instance, cfg *types.Config, containerIn
 		fmt.Printf("  Version: %s\n", instance.Version)
 	}
 	fmt.Printf("  Container: %s\n", instance.ContainerName)
+	if instance.ImageDigest != "" {
+		fmt.Printf("  Image Digest: %s\n", instance.ImageDigest)
+	}
+	for _, c := range instance.Containers {
+		if c.ImageDigest != "" {
+			fmt.Printf("  Image Digest (%s): %s\n", c.Name, c.ImageDigest)
+		}
+	}
+	if instance.ReplicaCount() > 1 {
+		fmt.Printf("  Replicas: %d\n", instance.ReplicaCount())
+	}
 	fmt.Printf("  Created: %s\n", instance.CreatedAt.Format("2006-01-02 15:04:05"))
 	if instance.Status == types.StatusRunning && containerInfo.State != nil {
 		fmt.Printf("  Uptime: %s\n", formatUptime(containerInfo.State.StartedAt))
@@ -142,10 +170,21 @@ func displayServiceInfo(instance *types.Instance, cfg *types.Config, containerIn
 
 	// Access Information
 	color.New(color.Bold).Println("Access")
-	if instance.Traefik.Enabled {
+	if instance.UsesHostNetwork() {
+		fmt.Printf("  Type: %s\n", color.CyanString("Host network (no Traefik routing)"))
+		if instance.Network.InternalPort > 0 {
+			fmt.Printf("  Address: localhost:%d\n", instance.Network.InternalPort)
+		}
+	} else if instance.Traefik.Enabled {
 		fmt.Printf("  URL: %s\n", color.GreenString(instance.URL))
 		fmt.Printf("  Protocol: %s\n", instance.Traefik.Protocol)
 		fmt.Printf("  Subdomain: %s.%s\n", instance.Traefik.Subdomain, cfg.Preferences.Domain)
+		if hosts := domain.InstanceHostnames(instance, cfg.Preferences); len(hosts) > 1 {
+			fmt.Printf("  Also at: %s\n", strings.Join(hosts[1:], ", "))
+		}
+		if len(instance.TLSSANs) > 0 {
+			fmt.Printf("  TLS SANs: %s\n", strings.Join(instance.TLSSANs, ", "))
+		}
 	} else {
 		fmt.Printf("  Type: %s\n", color.YellowString("Internal only"))
 		if instance.Network.InternalPort > 0 {
@@ -175,7 +214,7 @@ func displayServiceInfo(instance *types.Instance, cfg *types.Config, containerIn
 	if len(instance.Network.PortMappings) > 0 {
 		fmt.Println("  Port Mappings:")
 		for containerPort, hostPort := range instance.Network.PortMappings {
-			fmt.Printf("    localhost:%s → container:%s\n", hostPort, containerPort)
+			fmt.Printf("    %s:%s → container:%s\n", config.LocalHost(cfg.Preferences), hostPort, containerPort)
 		}
 	} else if instance.Network.HostPort > 0 {
 		// Backward compatibility with old single port format
@@ -183,19 +222,30 @@ func displayServiceInfo(instance *types.Instance, cfg *types.Config, containerIn
 	}
 	fmt.Println()
 
+	// Middlewares in front of the route
+	if !instance.Middlewares.IsEmpty() {
+		color.New(color.Bold).Println("Middlewares")
+		printMiddlewareSettings(instance.Middlewares)
+		fmt.Println()
+	}
+
 	// Resource Information
 	color.New(color.Bold).Println("Resources")
-	if instance.Resources.MemoryLimit != "" {
-		fmt.Printf("  Memory Limit: %s\n", instance.Resources.MemoryLimit)
+	memory, cpu := service.EffectiveLimits(cfg.Limits, instance.Resources)
+	if memory != "" {
+		fmt.Printf("  Memory Limit: %s\n", describeLimit(memory, instance.Resources.MemoryLimit))
 	} else {
 		fmt.Printf("  Memory Limit: %s\n", color.New(color.Faint).Sprint("unlimited"))
 	}
 
-	if instance.Resources.CPULimit != "" {
-		fmt.Printf("  CPU Limit: %s\n", instance.Resources.CPULimit)
+	if cpu != "" {
+		fmt.Printf("  CPU Limit: %s\n", describeLimit(cpu, instance.Resources.CPULimit))
 	} else {
 		fmt.Printf("  CPU Limit: %s\n", color.New(color.Faint).Sprint("unlimited"))
 	}
+	if cfg.Limits.Disabled {
+		color.New(color.Faint).Println("  Limits are disabled ('doku limits enable')")
+	}
 	fmt.Println()
 
 	// Volume Information
@@ -207,6 +257,16 @@ func displayServiceInfo(instance *types.Instance, cfg *types.Config, containerIn
 		fmt.Println()
 	}
 
+	// Links
+	if len(links) > 0 {
+		color.New(color.Bold).Println("Links")
+		for _, link := range links {
+			fmt.Printf("  %s: %s\n", link.Name, link.URL)
+		}
+		color.New(color.Faint).Printf("  Open with: doku docs %s [link]\n", instance.Name)
+		fmt.Println()
+	}
+
 	// Environment Variables
 	if showEnv && len(instance.Environment) > 0 {
 		color.New(color.Bold).Println("Environment Variables")
@@ -339,7 +399,7 @@ func formatUptime(startedAt string) string {
 }
 
 func displayTraefikInfo(cfg *types.Config, dockerClient *docker.Client) error {
-	containerName := "doku-traefik"
+	containerName := traefik.ContainerName()
 
 	// Get container info from Docker
 	containerInfo, err := dockerClient.ContainerInspect(containerName)
@@ -372,6 +432,16 @@ func displayTraefikInfo(cfg *types.Config, dockerClient *docker.Client) error {
 	color.New(color.Bold).Println("Service Information")
 	fmt.Printf("  Type: %s\n", color.CyanString("Traefik Reverse Proxy"))
 	fmt.Printf("  Container: %s\n", containerName)
+	if containerInfo.Config != nil {
+		running := traefik.VersionOf(containerInfo.Config.Image)
+		if running == "" {
+			running = containerInfo.Config.Image
+		}
+		fmt.Printf("  Version: %s\n", running)
+		if pinned := traefik.Image(cfg.Traefik.Version); containerInfo.Config.Image != pinned {
+			color.Yellow("  ⚠️  Configured for %s: run 'doku traefik upgrade %s'", pinned, traefik.VersionOf(pinned))
+		}
+	}
 	if status == types.StatusRunning && containerInfo.State != nil {
 		fmt.Printf("  Uptime: %s\n", formatUptime(containerInfo.State.StartedAt))
 	}
@@ -379,17 +449,17 @@ func displayTraefikInfo(cfg *types.Config, dockerClient *docker.Client) error {
 
 	// Access Information
 	color.New(color.Bold).Println("Access")
-	dashboardURL := fmt.Sprintf("%s://traefik.%s", cfg.Preferences.Protocol, cfg.Preferences.Domain)
-	fmt.Printf("  Dashboard: %s\n", color.GreenString(dashboardURL))
-	fmt.Printf("  HTTP Port: %d\n", cfg.Traefik.HTTPPort)
+	httpPort, httpsPort := config.TraefikPorts(cfg.Traefik)
+	fmt.Printf("  Dashboard: %s\n", color.GreenString(traefikDashboardURL(cfg)))
+	fmt.Printf("  HTTP Port: %d\n", httpPort)
 	if cfg.Preferences.Protocol == "https" {
-		fmt.Printf("  HTTPS Port: %d\n", cfg.Traefik.HTTPSPort)
+		fmt.Printf("  HTTPS Port: %d\n", httpsPort)
 	}
 	fmt.Println()
 
 	// Network Information
 	color.New(color.Bold).Println("Network")
-	fmt.Printf("  Network: %s\n", "doku-network")
+	fmt.Printf("  Network: %s\n", docker.NetworkName())
 	fmt.Printf("  Role: %s\n", "Gateway for all services")
 	fmt.Println()
 
@@ -402,6 +472,7 @@ func displayTraefikInfo(cfg *types.Config, dockerClient *docker.Client) error {
 	} else {
 		fmt.Println("  Start:   " + color.CyanString("doku start traefik"))
 	}
+	fmt.Println("  Upgrade: " + color.CyanString("doku traefik upgrade"))
 	fmt.Println("  Reinit:  " + color.CyanString("doku init"))
 	fmt.Println()
 
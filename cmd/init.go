@@ -2,26 +2,53 @@ package cmd
 
 import (
 	"fmt"
+	"runtime"
+	"slices"
 	"strings"
 	"time"
 
 	"github.com/AlecAivazis/survey/v2"
+	"github.com/dokulabs/doku-cli/internal/caddy"
 	"github.com/dokulabs/doku-cli/internal/catalog"
 	"github.com/dokulabs/doku-cli/internal/certs"
 	"github.com/dokulabs/doku-cli/internal/config"
 	"github.com/dokulabs/doku-cli/internal/dns"
+	"github.com/dokulabs/doku-cli/internal/dnsmasq"
 	"github.com/dokulabs/doku-cli/internal/docker"
+	"github.com/dokulabs/doku-cli/internal/proxy"
 	"github.com/dokulabs/doku-cli/internal/service"
-	"github.com/dokulabs/doku-cli/internal/traefik"
+	"github.com/dokulabs/doku-cli/internal/workspace"
 	"github.com/dokulabs/doku-cli/pkg/types"
 	"github.com/fatih/color"
 	"github.com/spf13/cobra"
 )
 
 var (
-	initDomain   string
-	initProtocol string
-	initSkipDNS  bool
+	initDomain    string
+	initProtocol  string
+	initSkipDNS   bool
+	initHTTPPort  int
+	initHTTPSPort int
+	initProxy     string
+	initDNS       string
+	initDNSPort   int
+	initBindIP    string
+	initEngine    string
+
+	// A company's CA to issue the certificates from instead of mkcert's,
+	// or a wildcard certificate issued elsewhere
+	initCACert  string
+	initCAKey   string
+	initCert    string
+	initCertKey string
+
+	// initDashboardAuth protects the Traefik dashboard with a generated
+	// password
+	initDashboardAuth bool
+
+	// The network of a new workspace, which can't overlap with the others
+	initNetworkSubnet  string
+	initNetworkGateway string
 )
 
 var initCmd = &cobra.Command{
@@ -33,7 +60,52 @@ var initCmd = &cobra.Command{
   • Configuring DNS (*.doku.local)
   • Creating Docker network
   • Installing Traefik reverse proxy
-  • Downloading service catalog`,
+  • Downloading service catalog
+
+Traefik listens on ports 80 and 443. Use --http-port and --https-port where
+those are taken or need root; URLs then include the port.
+
+With --proxy caddy, Caddy routes the services instead of Traefik, from a
+Caddyfile Doku keeps up to date. It serves HTTPS with certificates from its
+own local CA, so mkcert isn't needed. Basic auth and rate limits set with
+'doku expose' need Traefik.
+
+With --dns dnsmasq, a dnsmasq container answers for *.doku.local, and the
+system sends it the queries for the domain (/etc/resolver on macOS,
+systemd-resolved on Linux): every subdomain resolves, with no hosts file
+entry per service.
+
+With --bind-ip, the hostnames resolve to that address and Traefik and the
+services' ports are published on it alone, instead of 127.0.0.1 and every
+address: e.g. a loopback alias, so setups don't collide on ports, or the
+address of a WSL2 VM that Windows reaches it at.
+
+Where mkcert's CA isn't allowed, --ca-cert and --ca-key import a CA, e.g.
+your company's, that Doku issues the certificates from instead. --cert and
+--cert-key serve a wildcard certificate issued elsewhere for the domain
+(*.domain); Doku then can't issue certificates for other domains. Either
+skips mkcert entirely.
+
+With --dashboard-auth, the Traefik dashboard asks for a user and a generated
+password. Manage them later with 'doku traefik auth'.
+
+Doku runs on Podman as well as Docker, through its Docker-compatible API.
+The engine is detected: Docker's socket, else Podman's (that of the user or
+root on Linux, of 'podman machine' on macOS and Windows). --engine chooses
+it, and DOKU_ENGINE overrides it for a command. On macOS and Windows, use a
+rootful machine ('podman machine set --rootful') for Traefik to watch it.
+
+Examples:
+  doku init
+  doku init --domain dev.local --protocol https
+  doku init --http-port 8080 --https-port 8443
+  doku init --proxy caddy
+  doku init --dns dnsmasq
+  doku init --bind-ip 127.0.0.2
+  doku init --ca-cert acme-dev-ca.pem --ca-key acme-dev-ca-key.pem
+  doku init --domain dev.acme.com --cert wildcard.pem --cert-key wildcard-key.pem
+  doku init --dashboard-auth
+  doku init --engine podman`,
 	RunE: runInit,
 }
 
@@ -43,11 +115,165 @@ func init() {
 	initCmd.Flags().StringVar(&initDomain, "domain", "doku.local", "Domain to use for services")
 	initCmd.Flags().StringVar(&initProtocol, "protocol", "", "Protocol (http or https)")
 	initCmd.Flags().BoolVar(&initSkipDNS, "skip-dns", false, "Skip DNS/hosts file configuration")
+	initCmd.Flags().IntVar(&initHTTPPort, "http-port", config.DefaultHTTPPort, "Host port for HTTP traffic")
+	initCmd.Flags().IntVar(&initHTTPSPort, "https-port", config.DefaultHTTPSPort, "Host port for HTTPS traffic")
+	initCmd.Flags().StringVar(&initProxy, "proxy", proxy.Traefik, "Reverse proxy (traefik or caddy)")
+	initCmd.Flags().StringVar(&initDNS, "dns", "", "DNS setup: hosts, dnsmasq or manual (default: ask)")
+	initCmd.Flags().IntVar(&initDNSPort, "dns-port", dnsmasq.DefaultPort, "Host port of the dnsmasq container (--dns dnsmasq)")
+	initCmd.Flags().StringVar(&initEngine, "engine", "", "Container engine: docker or podman (default: detected)")
+	initCmd.Flags().StringVar(&initBindIP, "bind-ip", "", "Address hosts entries point at and ports are published on, e.g. 127.0.0.2 (default 127.0.0.1 and every address)")
+	initCmd.Flags().StringVar(&initCACert, "ca-cert", "", "CA certificate to issue the certificates from instead of mkcert (with --ca-key)")
+	initCmd.Flags().StringVar(&initCAKey, "ca-key", "", "Private key of --ca-cert")
+	initCmd.Flags().StringVar(&initCert, "cert", "", "Wildcard certificate issued elsewhere to serve, for the domain and *.domain (with --cert-key)")
+	initCmd.Flags().StringVar(&initCertKey, "cert-key", "", "Private key of --cert")
+	initCmd.Flags().BoolVar(&initDashboardAuth, "dashboard-auth", false, "Protect the Traefik dashboard with a generated password")
+}
+
+// validateInitCertificates checks the flags importing a CA or a certificate
+func validateInitCertificates() error {
+	if (initCACert == "") != (initCAKey == "") {
+		return fmt.Errorf("--ca-cert and --ca-key go together")
+	}
+	if (initCert == "") != (initCertKey == "") {
+		return fmt.Errorf("--cert and --cert-key go together")
+	}
+	if initCACert == "" && initCert == "" {
+		return nil
+	}
+	if initCACert != "" && initCert != "" {
+		return fmt.Errorf("use either --ca-cert to issue the certificates or --cert to serve one")
+	}
+	if initProxy == proxy.Caddy {
+		return fmt.Errorf("Caddy issues its own certificates: --ca-cert and --cert need Traefik")
+	}
+	if initProtocol == "http" {
+		return fmt.Errorf("certificates are only served with --protocol https")
+	}
+	return nil
+}
+
+// setupInitCertificates sets up the certificate of the domain: from the CA
+// or certificate of the flags, else where the configuration says, mkcert by
+// default
+func setupInitCertificates(cfgMgr *config.Manager) error {
+	switch {
+	case initCACert != "":
+		imported, err := certs.ImportCA(initCACert, initCAKey, cfgMgr.GetCADir())
+		if err != nil {
+			return err
+		}
+		if err := cfgMgr.Update(func(c *types.Config) error {
+			c.Certificates.Source = imported.Source
+			c.Certificates.CACert = imported.CACert
+			c.Certificates.CAKey = imported.CAKey
+			return nil
+		}); err != nil {
+			return fmt.Errorf("failed to save the CA: %w", err)
+		}
+		printSuccess(fmt.Sprintf("CA imported to %s", imported.CACert))
+	case initCert != "":
+		if err := certs.NewManager(cfgMgr.GetCertsDir(), initDomain).ImportCertificate(initCert, initCertKey); err != nil {
+			return err
+		}
+		if err := cfgMgr.Update(func(c *types.Config) error {
+			c.Certificates.Source = certs.SourceProvided
+			return nil
+		}); err != nil {
+			return fmt.Errorf("failed to save the certificate source: %w", err)
+		}
+		printSuccess(fmt.Sprintf("Certificate for %s and *.%s imported", initDomain, initDomain))
+	}
+
+	cfg, err := cfgMgr.Get()
+	if err != nil {
+		return fmt.Errorf("failed to get configuration: %w", err)
+	}
+	certMgr := certs.NewManager(cfgMgr.GetCertsDir(), initDomain).Configure(cfg.Certificates)
+
+	switch certs.Source(cfg.Certificates) {
+	case certs.SourceProvided:
+		if !certMgr.CertificatesExist() {
+			return fmt.Errorf("the certificates are provided, and %s has none: import one with --cert and --cert-key", initDomain)
+		}
+		if !certs.IsTrusted(certMgr.GetCertificatePath()) {
+			color.Yellow("⚠️  This machine doesn't trust the issuer of %s: browsers will warn about it", certMgr.GetCertificatePath())
+		}
+		return nil
+	case certs.SourceCA:
+		if !certs.IsTrusted(cfg.Certificates.CACert) {
+			color.Yellow("⚠️  This machine doesn't trust the CA %s: add it to the system trust store, or browsers will warn about the certificates", cfg.Certificates.CACert)
+		}
+	default:
+		// Check if mkcert is installed
+		if !certMgr.IsMkcertInstalled() {
+			fmt.Println("⚠️  mkcert not found, attempting to install...")
+			if err := certMgr.InstallMkcert(); err != nil {
+				color.Yellow("⚠️  Could not install mkcert automatically")
+				color.Yellow("Please install mkcert manually: https://github.com/FiloSottile/mkcert")
+				return fmt.Errorf("mkcert installation required")
+			}
+			printSuccess("mkcert installed")
+		}
+
+		// Install CA
+		if err := certMgr.InstallCA(); err != nil {
+			return fmt.Errorf("failed to install CA: %w", err)
+		}
+		printSuccess("CA certificate installed to system trust store")
+	}
+
+	// Generate certificates
+	if err := certMgr.GenerateCertificates(); err != nil {
+		return fmt.Errorf("failed to generate certificates: %w", err)
+	}
+	printSuccess(fmt.Sprintf("SSL certificates generated for %s and *.%s", initDomain, initDomain))
+	return nil
 }
 
 func runInit(cmd *cobra.Command, args []string) error {
 	printHeader("Welcome to Doku Setup")
 
+	// A workspace's services default to a subdomain of their own
+	if !cmd.Flags().Changed("domain") && initDomain != "" {
+		initDomain = workspace.Domain(initDomain)
+	}
+
+	for _, port := range []int{initHTTPPort, initHTTPSPort} {
+		if err := config.ValidatePort(port); err != nil {
+			return err
+		}
+	}
+	if initHTTPPort == initHTTPSPort {
+		return fmt.Errorf("--http-port and --https-port must differ")
+	}
+	if err := proxy.Validate(initProxy); err != nil {
+		return err
+	}
+	if initDNS != "" && !slices.Contains(dnsSetupMethods, initDNS) {
+		return fmt.Errorf("invalid --dns '%s': use one of %s", initDNS, strings.Join(dnsSetupMethods, ", "))
+	}
+	if err := config.ValidatePort(initDNSPort); err != nil {
+		return err
+	}
+	if err := config.ValidateBindIP(initBindIP); err != nil {
+		return err
+	}
+	if initEngine != "" {
+		if err := docker.ValidateEngine(initEngine); err != nil {
+			return err
+		}
+		docker.SetEngine(initEngine)
+	}
+	if err := validateInitCertificates(); err != nil {
+		return err
+	}
+	if (initCACert != "" || initCert != "") && initProtocol == "" {
+		initProtocol = "https" // Certificates are only served over HTTPS
+	}
+	if initDashboardAuth && initProxy != proxy.Traefik {
+		return fmt.Errorf("--dashboard-auth protects Traefik's dashboard; Caddy has none")
+	}
+
 	// Create config manager
 	cfgMgr, err := config.New()
 	if err != nil {
@@ -79,15 +305,23 @@ func runInit(cmd *cobra.Command, args []string) error {
 	}
 	defer dockerClient.Close()
 
+	engine := dockerClient.Engine()
 	if err := dockerClient.Ping(); err != nil {
-		return fmt.Errorf("Docker daemon is not running: %w", err)
+		if engine.Name() == docker.EnginePodman {
+			return fmt.Errorf("Podman is not running (%s): %w", engine.StartHint(), err)
+		}
+		return fmt.Errorf("Docker daemon is not running (%s): %w", engine.StartHint(), err)
 	}
 
 	version, err := dockerClient.Version()
 	if err != nil {
 		return fmt.Errorf("failed to get Docker version: %w", err)
 	}
-	printSuccess(fmt.Sprintf("Docker detected (version %s)", version.Version))
+	if engine.Name() == docker.EnginePodman {
+		printSuccess(fmt.Sprintf("Podman detected (version %s)", version.Version))
+	} else {
+		printSuccess(fmt.Sprintf("Docker detected (version %s)", version.Version))
+	}
 
 	// Step 2: Prompt for preferences
 	printStep(2, "Configuration")
@@ -126,6 +360,18 @@ func runInit(cmd *cobra.Command, args []string) error {
 	}
 
 	printSuccess(fmt.Sprintf("Protocol: %s, Domain: %s", initProtocol, initDomain))
+	if initHTTPPort != config.DefaultHTTPPort || initHTTPSPort != config.DefaultHTTPSPort {
+		printSuccess(fmt.Sprintf("Ports: %d (HTTP), %d (HTTPS)", initHTTPPort, initHTTPSPort))
+	}
+	if initBindIP != "" {
+		printSuccess(fmt.Sprintf("Bind IP: %s", initBindIP))
+		warnUnassignedBindIP(initBindIP)
+	}
+	for _, port := range []int{initHTTPPort, initHTTPSPort} {
+		if warning := engine.PortWarning(port); warning != "" {
+			color.Yellow("⚠️  %s", warning)
+		}
+	}
 
 	// Step 2.5: Monitoring tool selection
 	fmt.Println()
@@ -179,40 +425,43 @@ func runInit(cmd *cobra.Command, args []string) error {
 	if err := cfgMgr.SetProtocol(initProtocol); err != nil {
 		return fmt.Errorf("failed to set protocol: %w", err)
 	}
+	if err := cfgMgr.SetTraefikPorts(initHTTPPort, initHTTPSPort); err != nil {
+		return fmt.Errorf("failed to set Traefik ports: %w", err)
+	}
+	if err := cfgMgr.Update(func(c *types.Config) error {
+		c.Preferences.Proxy = initProxy
+		c.Preferences.BindIP = initBindIP
+		if cmd.Flags().Changed("engine") {
+			c.Preferences.Engine = initEngine
+		}
+		return nil
+	}); err != nil {
+		return fmt.Errorf("failed to set proxy: %w", err)
+	}
 	if err := cfgMgr.SetMonitoringTool(monitoringTool); err != nil {
 		return fmt.Errorf("failed to set monitoring tool: %w", err)
 	}
+	if initNetworkSubnet != "" {
+		if err := cfgMgr.Update(func(c *types.Config) error {
+			c.Network.Subnet = initNetworkSubnet
+			c.Network.Gateway = initNetworkGateway
+			return nil
+		}); err != nil {
+			return fmt.Errorf("failed to set network: %w", err)
+		}
+	}
 
 	printSuccess(fmt.Sprintf("Configuration saved to %s", cfgMgr.GetDokuDir()))
 
 	// Step 4: Setup SSL certificates (if HTTPS)
-	if initProtocol == "https" {
+	if initProtocol == "https" && initProxy == proxy.Caddy {
 		printStep(4, "Setting up SSL certificates")
-
-		certMgr := certs.NewManager(cfgMgr.GetCertsDir(), initDomain)
-
-		// Check if mkcert is installed
-		if !certMgr.IsMkcertInstalled() {
-			fmt.Println("⚠️  mkcert not found, attempting to install...")
-			if err := certMgr.InstallMkcert(); err != nil {
-				color.Yellow("⚠️  Could not install mkcert automatically")
-				color.Yellow("Please install mkcert manually: https://github.com/FiloSottile/mkcert")
-				return fmt.Errorf("mkcert installation required")
-			}
-			printSuccess("mkcert installed")
-		}
-
-		// Install CA
-		if err := certMgr.InstallCA(); err != nil {
-			return fmt.Errorf("failed to install CA: %w", err)
-		}
-		printSuccess("CA certificate installed to system trust store")
-
-		// Generate certificates
-		if err := certMgr.GenerateCertificates(); err != nil {
-			return fmt.Errorf("failed to generate certificates: %w", err)
+		printSuccess("Caddy will issue the certificates from its own local CA")
+	} else if initProtocol == "https" {
+		printStep(4, "Setting up SSL certificates")
+		if err := setupInitCertificates(cfgMgr); err != nil {
+			return err
 		}
-		printSuccess(fmt.Sprintf("SSL certificates generated for %s and *.%s", initDomain, initDomain))
 	}
 
 	// Step 5: Configure DNS
@@ -223,22 +472,41 @@ func runInit(cmd *cobra.Command, args []string) error {
 			printStep(4, "Configuring DNS")
 		}
 
-		dnsMgr := dns.NewManager()
-
-		dnsMethod := ""
-		dnsPrompt := &survey.Select{
-			Message: "DNS setup method:",
-			Options: []string{
-				"Automatic (/etc/hosts modification)",
-				"Manual (I'll configure DNS myself)",
-			},
-			Default: "Automatic (/etc/hosts modification)",
+		dnsMgr := dns.NewManagerForContext(cfgMgr.GetContext()).SetIP(cfgMgr.GetHostsIP())
+
+		dnsMethod := initDNS
+		if dnsMethod == "" {
+			dnsChoice := ""
+			dnsPrompt := &survey.Select{
+				Message: "DNS setup method:",
+				Options: []string{
+					"Automatic (/etc/hosts modification)",
+					"Wildcard (dnsmasq container, no entry per service)",
+					"Manual (I'll configure DNS myself)",
+				},
+				Default: "Automatic (/etc/hosts modification)",
+			}
+			if err := survey.AskOne(dnsPrompt, &dnsChoice); err != nil {
+				return fmt.Errorf("failed to get DNS setup selection: %w", err)
+			}
+			switch {
+			case strings.HasPrefix(dnsChoice, "Automatic"):
+				dnsMethod = "hosts"
+			case strings.HasPrefix(dnsChoice, "Wildcard"):
+				dnsMethod = "dnsmasq"
+			default:
+				dnsMethod = "manual"
+			}
 		}
-		if err := survey.AskOne(dnsPrompt, &dnsMethod); err != nil {
-			return fmt.Errorf("failed to get DNS setup selection: %w", err)
+
+		// Moving away from an earlier setup's dnsmasq
+		if dnsMethod != "dnsmasq" {
+			if cfg, err := cfgMgr.Get(); err == nil && cfg.Preferences.DNSSetup == "dnsmasq" {
+				removeWildcardDNS(dockerClient, cfg)
+			}
 		}
 
-		if dnsMethod == "Automatic (/etc/hosts modification)" {
+		if dnsMethod == "hosts" {
 			fmt.Println("⚠️  This requires administrator privileges")
 
 			if err := dnsMgr.AddDokuDomain(initDomain); err != nil {
@@ -248,16 +516,22 @@ func runInit(cmd *cobra.Command, args []string) error {
 				fmt.Println()
 				color.New(color.Bold).Printf("Add entries to %s:\n", dnsMgr.GetHostsFilePath())
 				fmt.Println()
-				fmt.Printf("  sudo sh -c \"cat >> %s << 'EOF'\n", dnsMgr.GetHostsFilePath())
+				if runtime.GOOS == "windows" {
+					fmt.Println("  Open Notepad as administrator, open the file and add:")
+				} else {
+					fmt.Printf("  sudo sh -c \"cat >> %s << 'EOF'\n", dnsMgr.GetHostsFilePath())
+				}
 				fmt.Println("# Doku local development")
-				color.Cyan("127.0.0.1 %s", initDomain)
-				color.Cyan("127.0.0.1 traefik.%s", initDomain)
+				color.Cyan("%s %s", dnsMgr.IP(), initDomain)
+				color.Cyan("%s traefik.%s", dnsMgr.IP(), initDomain)
 				if monitoringTool == "dozzle" {
-					color.Cyan("127.0.0.1 dozzle.%s", initDomain)
+					color.Cyan("%s dozzle.%s", dnsMgr.IP(), initDomain)
 				}
 				color.Cyan("# Add more entries as you install services:")
-				color.Cyan("# 127.0.0.1 <service>.%s", initDomain)
-				fmt.Println("EOF\"")
+				color.Cyan("# %s <service>.%s", dnsMgr.IP(), initDomain)
+				if runtime.GOOS != "windows" {
+					fmt.Println("EOF\"")
+				}
 				fmt.Println()
 				color.New(color.Faint).Println("Note: You'll need to add an entry for each service you install.")
 				color.New(color.Faint).Println("You can continue for now and set up DNS later.")
@@ -290,6 +564,31 @@ func runInit(cmd *cobra.Command, args []string) error {
 			}); err != nil {
 				return fmt.Errorf("failed to update DNS setup method: %w", err)
 			}
+		} else if dnsMethod == "dnsmasq" {
+			fmt.Println("⚠️  This requires administrator privileges")
+
+			if err := cfgMgr.Update(func(c *types.Config) error {
+				c.Preferences.DNSSetup = "dnsmasq"
+				c.Preferences.DNSPort = initDNSPort
+				return nil
+			}); err != nil {
+				return fmt.Errorf("failed to update DNS setup method: %w", err)
+			}
+			cfg, err := cfgMgr.Get()
+			if err != nil {
+				return fmt.Errorf("failed to get configuration: %w", err)
+			}
+			if err := setupWildcardDNS(dockerClient, cfg); err != nil {
+				return err
+			}
+			printSuccess(fmt.Sprintf("*.%s resolves through dnsmasq", initDomain))
+
+			// Entries of an earlier setup would shadow the wildcard
+			if has, err := dnsMgr.HasDokuEntries(); err == nil && has {
+				if err := dnsMgr.RemoveDokuEntries(); err != nil {
+					color.Yellow("⚠️  Failed to remove the entries of %s: %v", dnsMgr.GetHostsFilePath(), err)
+				}
+			}
 		} else {
 			printSuccess("Skipping automatic DNS setup")
 			fmt.Println()
@@ -297,14 +596,14 @@ func runInit(cmd *cobra.Command, args []string) error {
 			fmt.Println()
 			color.New(color.Bold).Printf("Add these entries to your DNS or %s:\n", dnsMgr.GetHostsFilePath())
 			fmt.Println()
-			color.Cyan("127.0.0.1 %s", initDomain)
-			color.Cyan("127.0.0.1 traefik.%s", initDomain)
+			color.Cyan("%s %s", dnsMgr.IP(), initDomain)
+			color.Cyan("%s traefik.%s", dnsMgr.IP(), initDomain)
 			if monitoringTool == "dozzle" {
-				color.Cyan("127.0.0.1 dozzle.%s", initDomain)
+				color.Cyan("%s dozzle.%s", dnsMgr.IP(), initDomain)
 			}
 			fmt.Println()
 			color.New(color.Faint).Println("Note: When you install services, you'll need to manually add DNS entries:")
-			color.New(color.Faint).Printf("      127.0.0.1 <service>.%s\n", initDomain)
+			color.New(color.Faint).Printf("      %s <service>.%s\n", dnsMgr.IP(), initDomain)
 			fmt.Println()
 
 			// Update config with DNS setup method
@@ -327,38 +626,41 @@ func runInit(cmd *cobra.Command, args []string) error {
 	}
 	printStep(stepNum, "Setting up Docker network")
 
+	cfg, err := cfgMgr.Get()
+	if err != nil {
+		return fmt.Errorf("failed to get configuration: %w", err)
+	}
+
 	networkMgr := docker.NewNetworkManager(dockerClient)
-	if err := networkMgr.EnsureDokuNetwork("doku-network", "172.20.0.0/16", "172.20.0.1"); err != nil {
+	if err := networkMgr.EnsureDokuNetwork(docker.NetworkName(), cfg.Network.Subnet, cfg.Network.Gateway); err != nil {
 		return fmt.Errorf("failed to create network: %w", err)
 	}
 
-	printSuccess("Docker network 'doku-network' created")
+	printSuccess(fmt.Sprintf("Docker network '%s' created", docker.NetworkName()))
 
-	// Step 7: Setup Traefik
+	// Step 7: Setup the reverse proxy
 	stepNum++
-	printStep(stepNum, "Installing Traefik reverse proxy")
-
-	traefikMgr := traefik.NewManager(
-		dockerClient,
-		cfgMgr.GetTraefikDir(),
-		cfgMgr.GetCertsDir(),
-		initDomain,
-		initProtocol,
-	)
-
-	// Check if Traefik container already exists
-	traefikExists, err := dockerClient.ContainerExists(traefik.TraefikContainerName)
+	reverseProxy := proxy.New(dockerClient, cfgMgr, cfg)
+	printStep(stepNum, fmt.Sprintf("Installing %s reverse proxy", reverseProxy.Name()))
+
+	// The other backend, left by an earlier setup, holds the ports
+	if err := removeOtherProxy(dockerClient, networkMgr, reverseProxy); err != nil {
+		return err
+	}
+
+	// Check if the proxy container already exists
+	proxyExists, err := dockerClient.ContainerExists(reverseProxy.ContainerName())
 	if err != nil {
-		return fmt.Errorf("failed to check Traefik container: %w", err)
+		return fmt.Errorf("failed to check %s container: %w", reverseProxy.Name(), err)
 	}
 
 	// If exists, ask user what to do
-	if traefikExists {
-		color.Yellow("⚠️  Traefik container already exists")
+	recreate := true
+	if proxyExists {
+		color.Yellow("⚠️  %s container already exists", reverseProxy.Name())
 
-		recreate := false
 		recreatePrompt := &survey.Confirm{
-			Message: "Do you want to remove and recreate Traefik? (Recommended for clean setup)",
+			Message: fmt.Sprintf("Do you want to remove and recreate %s? (Recommended for clean setup)", reverseProxy.Name()),
 			Default: true,
 		}
 		if err := survey.AskOne(recreatePrompt, &recreate); err != nil {
@@ -366,100 +668,92 @@ func runInit(cmd *cobra.Command, args []string) error {
 		}
 
 		if recreate {
-			fmt.Println("Removing existing Traefik container...")
+			fmt.Printf("Removing existing %s container...\n", reverseProxy.Name())
 
 			// Disconnect from network first
-			networkMgr.DisconnectContainer("doku-network", traefik.TraefikContainerName, true)
+			networkMgr.DisconnectContainer(docker.NetworkName(), reverseProxy.ContainerName(), true)
 
 			// Remove container
-			if err := traefikMgr.RemoveContainer(); err != nil {
-				return fmt.Errorf("failed to remove existing Traefik: %w", err)
-			}
-
-			// Setup Traefik (create and start)
-			if err := traefikMgr.Setup(); err != nil {
-				return fmt.Errorf("failed to setup Traefik: %w", err)
-			}
-
-			// Connect Traefik to doku-network
-			if err := networkMgr.ConnectContainer("doku-network", traefik.TraefikContainerName); err != nil {
-				return fmt.Errorf("failed to connect Traefik to network: %w", err)
-			}
-
-			dashboardURL := traefikMgr.GetDashboardURL()
-			printSuccess(fmt.Sprintf("Traefik installed and running"))
-			printSuccess(fmt.Sprintf("Dashboard: %s", dashboardURL))
-
-			// Update config with Traefik status
-			if err := cfgMgr.Update(func(c *types.Config) error {
-				c.Traefik.DashboardURL = dashboardURL
-				c.Traefik.Status = "running"
-				return nil
-			}); err != nil {
-				return fmt.Errorf("failed to update Traefik status: %w", err)
-			}
-		} else {
-			// Use existing Traefik - just ensure it's running
-			isRunning, err := traefikMgr.IsRunning()
-			if err != nil {
-				return fmt.Errorf("failed to check Traefik status: %w", err)
-			}
-
-			if !isRunning {
-				fmt.Println("Starting existing Traefik container...")
-				containerInfo, _ := dockerClient.ContainerInspect(traefik.TraefikContainerName)
-				if err := dockerClient.ContainerStart(containerInfo.ID); err != nil {
-					return fmt.Errorf("failed to start existing Traefik: %w", err)
-				}
+			if err := reverseProxy.RemoveContainer(); err != nil {
+				return fmt.Errorf("failed to remove existing %s: %w", reverseProxy.Name(), err)
 			}
+		}
+	}
 
-			printSuccess("Using existing Traefik container")
+	if recreate {
+		// Setup the proxy (create and start)
+		if err := reverseProxy.Setup(); err != nil {
+			return fmt.Errorf("failed to setup %s: %w", reverseProxy.Name(), err)
+		}
 
-			// Update config with Traefik status
-			dashboardURL := traefikMgr.GetDashboardURL()
-			if err := cfgMgr.Update(func(c *types.Config) error {
-				c.Traefik.DashboardURL = dashboardURL
-				c.Traefik.Status = "running"
-				return nil
-			}); err != nil {
-				return fmt.Errorf("failed to update Traefik status: %w", err)
-			}
+		// Connect the proxy to doku-network
+		if err := networkMgr.ConnectContainer(docker.NetworkName(), reverseProxy.ContainerName()); err != nil {
+			return fmt.Errorf("failed to connect %s to network: %w", reverseProxy.Name(), err)
 		}
+
+		printSuccess(fmt.Sprintf("%s installed and running", reverseProxy.Name()))
 	} else {
-		// No existing Traefik, create fresh
-		// Setup Traefik (create and start)
-		if err := traefikMgr.Setup(); err != nil {
-			return fmt.Errorf("failed to setup Traefik: %w", err)
+		// Use the existing proxy - just ensure it's running
+		isRunning, err := reverseProxy.IsRunning()
+		if err != nil {
+			return fmt.Errorf("failed to check %s status: %w", reverseProxy.Name(), err)
 		}
 
-		// Connect Traefik to doku-network
-		if err := networkMgr.ConnectContainer("doku-network", traefik.TraefikContainerName); err != nil {
-			return fmt.Errorf("failed to connect Traefik to network: %w", err)
+		if !isRunning {
+			fmt.Printf("Starting existing %s container...\n", reverseProxy.Name())
+			if err := dockerClient.ContainerStart(reverseProxy.ContainerName()); err != nil {
+				return fmt.Errorf("failed to start existing %s: %w", reverseProxy.Name(), err)
+			}
 		}
 
-		dashboardURL := traefikMgr.GetDashboardURL()
-		printSuccess(fmt.Sprintf("Traefik installed and running"))
+		printSuccess(fmt.Sprintf("Using existing %s container", reverseProxy.Name()))
+	}
+
+	dashboardURL := reverseProxy.GetDashboardURL()
+	if dashboardURL != "" {
 		printSuccess(fmt.Sprintf("Dashboard: %s", dashboardURL))
+	}
 
-		// Update config with Traefik status
-		if err := cfgMgr.Update(func(c *types.Config) error {
-			c.Traefik.DashboardURL = dashboardURL
-			c.Traefik.Status = "running"
-			return nil
-		}); err != nil {
-			return fmt.Errorf("failed to update Traefik status: %w", err)
+	// Update config with the proxy's status
+	if err := cfgMgr.Update(func(c *types.Config) error {
+		c.Traefik.DashboardURL = dashboardURL
+		c.Traefik.Status = "running"
+		return nil
+	}); err != nil {
+		return fmt.Errorf("failed to update %s status: %w", reverseProxy.Name(), err)
+	}
+
+	if initDashboardAuth {
+		cfg, err := cfgMgr.Get()
+		if err != nil {
+			return err
+		}
+		if cfg.Traefik.DashboardAuth != nil {
+			printSuccess("The dashboard already asks for a password")
+		} else if err := setDashboardAuth(cfgMgr, cfg, dashboardAuthUser, ""); err != nil {
+			return fmt.Errorf("failed to protect the dashboard: %w", err)
 		}
 	}
+
 	// Step 8: Download catalog
 	stepNum++
 	printStep(stepNum, "Downloading service catalog")
 
 	catalogMgr := catalog.NewManager(cfgMgr.GetCatalogDir())
+	if cfg, err := cfgMgr.Get(); err == nil && cfg.Preferences.CatalogPin != "" {
+		catalogMgr.SetCatalogURL(buildCatalogURL(cfg.Preferences.CatalogPin))
+	}
 
 	// Try to fetch catalog
 	if err := catalogMgr.FetchCatalog(); err != nil {
 		color.Yellow("⚠️  Could not download catalog from GitHub: %v", err)
-		color.Yellow("Catalog will be available after running: doku catalog update")
+		if version, verr := catalogMgr.GetCatalogVersion(); verr == nil && catalogMgr.CatalogExists() {
+			// e.g. loaded from an offline bundle
+			printSuccess(fmt.Sprintf("Using the catalog on disk (version: %s)", version))
+			cfgMgr.UpdateCatalogVersion(version)
+		} else {
+			color.Yellow("Catalog will be available after running: doku catalog update")
+		}
 	} else {
 		// Validate catalog
 		if err := catalogMgr.ValidateCatalog(); err != nil {
@@ -501,9 +795,12 @@ func runInit(cmd *cobra.Command, args []string) error {
 
 	fmt.Println()
 	color.Green("✓ Docker: Running")
-	color.Green("✓ Network: doku-network created")
-	color.Green("✓ Traefik: Running")
-	if initProtocol == "https" {
+	color.Green("✓ Network: %s created", docker.NetworkName())
+	color.Green("✓ %s: Running", reverseProxy.Name())
+	if caddyMgr, ok := reverseProxy.(*caddy.Manager); ok && initProtocol == "https" {
+		color.Green("✓ SSL: Caddy's local CA")
+		color.Yellow("⚠️  Trust its certificate to open services without warnings: %s", caddyMgr.CACertPath())
+	} else if initProtocol == "https" {
 		color.Green("✓ SSL: Certificates installed")
 	}
 	if !initSkipDNS {
@@ -522,9 +819,12 @@ func runInit(cmd *cobra.Command, args []string) error {
 
 	fmt.Println()
 	color.Cyan("Next steps:")
+	fmt.Println("  • Try an example stack: doku quickstart")
 	fmt.Println("  • Browse catalog: doku catalog")
 	fmt.Println("  • Install a service: doku install <service>")
-	fmt.Println(fmt.Sprintf("  • View Traefik dashboard: %s", traefikMgr.GetDashboardURL()))
+	if dashboardURL != "" {
+		fmt.Println(fmt.Sprintf("  • View Traefik dashboard: %s", dashboardURL))
+	}
 	if monitoringTool != "none" {
 		monitoringURL := getMonitoringURL(cfgMgr, monitoringTool, initProtocol, initDomain)
 		fmt.Println(fmt.Sprintf("  • View monitoring dashboard: %s", monitoringURL))
@@ -578,8 +878,8 @@ func getMonitoringDisplayName(tool string) string {
 
 // getMonitoringURL returns the dashboard URL for a monitoring tool
 func getMonitoringURL(cfgMgr *config.Manager, tool, protocol, domain string) string {
-	instanceName := tool
-	return fmt.Sprintf("%s://%s.%s", protocol, instanceName, domain)
+	httpPort, httpsPort := cfgMgr.GetTraefikPorts()
+	return config.ServiceURL(protocol, tool+"."+domain, httpPort, httpsPort)
 }
 
 // installMonitoringTool installs the selected monitoring tool
@@ -612,7 +912,7 @@ func installMonitoringTool(dockerClient *docker.Client, cfgMgr *config.Manager,
 	time.Sleep(3 * time.Second)
 
 	// Configure monitoring in the config
-	monitoringURL := fmt.Sprintf("%s://%s.%s", protocol, tool, domain)
+	monitoringURL := getMonitoringURL(cfgMgr, tool, protocol, domain)
 
 	switch tool {
 	case "dozzle":
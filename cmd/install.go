@@ -8,6 +8,7 @@ import (
 	"regexp"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/AlecAivazis/survey/v2"
 	"github.com/dokulabs/doku-cli/internal/catalog"
@@ -30,14 +31,26 @@ var (
 	installPorts              []string
 	installYes                bool
 	installInternal           bool
+	installHostNetwork        bool
 	installSkipDeps           bool
 	installDisableAutoInstall bool   // When true, prompts before installing dependencies
 	installPath               string // Path to custom project with Dockerfile
 	installBuild              bool   // Force rebuild even if cached image exists
+	installBuildArgs          []string
+	installTarget             string // Dockerfile stage to build
+	installHealthTimeout      time.Duration
+	installDefaultPasswords   bool
+	installLabels             []string
+	installInteractive        bool // Pick services from a multi-select
+	installDepInstances       []string
+	installLogMaxSize         string
+	installLogMaxFile         int
+	installDomains            []string
+	installTLSSANs            []string
 )
 
 var installCmd = &cobra.Command{
-	Use:   "install <service>[:<version>]",
+	Use:   "install [<service>[:<version>]]",
 	Short: "Install a service from the catalog",
 	Long: `Install and start a service from the catalog or custom project.
 
@@ -47,18 +60,28 @@ Examples:
   doku install postgres:16       # Install PostgreSQL 16
   doku install redis --name cache  # Install with custom name
   doku install mysql --env MYSQL_ROOT_PASSWORD=secret
+  doku install postgres --default-passwords  # Keep the catalog's passwords
+  doku install postgres --label com.corp.team=payments  # Extra Docker label
+  doku install kafka --log-max-size 50m --log-max-file 5  # Keep more logs
   doku install postgres --memory 2g --cpu 1.0
+  doku install api --domain api.myproject.test  # Also reachable at its own hostname
   doku install postgres --port 5432  # Map single port
   doku install rabbitmq --port 5672 --port 15672  # Map multiple ports
   doku install rabbitmq --port 5673:5672 --port 15673:15672  # Map to different host ports
   doku install user-service --internal  # Install as internal (no external access)
+  doku install avahi --host-network     # Share the host's network stack (no Traefik URL)
+  doku install app --dep-instance postgres=postgres-16  # Use an installed dependency
+
+  # Pick several services from the catalog
+  doku install --interactive
 
   # Custom projects with Dockerfile
   doku install frontend --path=./frontend  # Install from custom Dockerfile
   doku install api --path=./api --internal  # Install as internal service
   doku install worker --path=./worker --env QUEUE_URL=redis://redis:6379
-  doku install ui --path=./ui --build  # Force rebuild even if cached image exists`,
-	Args: cobra.ExactArgs(1),
+  doku install ui --path=./ui --build  # Force rebuild even if cached image exists
+  doku install api --path=./api --target prod --build-arg GO_VERSION=1.23  # Build a stage with build args`,
+	Args: cobra.MaximumNArgs(1),
 	RunE: runInstall,
 }
 
@@ -73,19 +96,59 @@ func init() {
 	installCmd.Flags().StringSliceVarP(&installPorts, "port", "p", []string{}, "Port mappings (host:container or port). Can be specified multiple times")
 	installCmd.Flags().BoolVarP(&installYes, "yes", "y", false, "Skip confirmation prompts")
 	installCmd.Flags().BoolVar(&installInternal, "internal", false, "Install as internal service (no Traefik exposure)")
+	installCmd.Flags().BoolVar(&installHostNetwork, "host-network", false, "Run on the host's network stack instead of doku-network (no Traefik routing)")
 	installCmd.Flags().BoolVar(&installSkipDeps, "skip-deps", false, "Skip dependency resolution and installation")
 	installCmd.Flags().BoolVar(&installDisableAutoInstall, "no-auto-install-deps", false, "Prompt before installing dependencies (interactive mode)")
 	installCmd.Flags().StringVar(&installPath, "path", "", "Path to custom project with Dockerfile")
 	installCmd.Flags().BoolVar(&installBuild, "build", false, "Force rebuild even if cached image exists")
+	installCmd.Flags().StringArrayVar(&installBuildArgs, "build-arg", []string{}, "Build argument for --path projects (KEY=VALUE). Can be specified multiple times")
+	installCmd.Flags().StringVar(&installTarget, "target", "", "Dockerfile stage to build for --path projects")
+	installCmd.Flags().BoolVarP(&installInteractive, "interactive", "i", false, "Select several services to install from the catalog")
+	installCmd.Flags().DurationVar(&installHealthTimeout, "health-timeout", service.DefaultHealthTimeout, "How long to wait for each container to become healthy before starting its dependents")
+	installCmd.Flags().BoolVar(&installDefaultPasswords, "default-passwords", false, "Keep the catalog's default passwords instead of generating random ones")
+	installCmd.Flags().StringArrayVar(&installLabels, "label", []string{}, "Extra Docker label for the service's containers (KEY=VALUE). Can be specified multiple times")
+	installCmd.Flags().StringVar(&installLogMaxSize, "log-max-size", "", "Size a log file grows to before it's rotated (e.g. 10m, 1g; default from preferences.logs.maxsize)")
+	installCmd.Flags().IntVar(&installLogMaxFile, "log-max-file", 0, "Number of log files kept (default from preferences.logs.maxfile)")
+	installCmd.Flags().StringSliceVar(&installDomains, "domain", []string{}, "Hostname the service is also reachable at (e.g. api.myproject.test). Can be specified multiple times")
+	installCmd.Flags().StringArrayVar(&installTLSSANs, "tls-san", []string{}, "Hostname or IP address the service's own certificate also covers, for clients that verify it (e.g. api.internal, 10.0.0.5). Can be specified multiple times")
+	installCmd.Flags().StringArrayVar(&installDepInstances, "dep-instance", []string{}, "Use an installed instance for a dependency instead of installing one (SERVICE=INSTANCE). Can be specified multiple times")
 }
 
 func runInstall(cmd *cobra.Command, args []string) error {
+	if installInteractive {
+		if len(args) > 0 {
+			return fmt.Errorf("--interactive does not take a service name")
+		}
+		return runInteractiveInstall()
+	}
+
+	if len(args) == 0 {
+		return fmt.Errorf("requires a service name (or use --interactive)")
+	}
 	serviceSpec := args[0]
 
 	// Check if --path is provided (custom project installation)
 	if installPath != "" {
+		if len(installLabels) > 0 {
+			return fmt.Errorf("--label is not supported with --path")
+		}
+		if installLogRotation() != nil {
+			return fmt.Errorf("--log-max-size and --log-max-file are not supported with --path")
+		}
+		if installHostNetwork {
+			return fmt.Errorf("--host-network is not supported with --path")
+		}
+		if len(installDomains) > 0 {
+			return fmt.Errorf("--domain is not supported with --path")
+		}
+		if len(installTLSSANs) > 0 {
+			return fmt.Errorf("--tls-san is not supported with --path")
+		}
 		return installCustomProject(serviceSpec)
 	}
+	if len(installBuildArgs) > 0 || installTarget != "" {
+		return fmt.Errorf("--build-arg and --target require --path")
+	}
 
 	// Parse service:version
 	parts := strings.SplitN(serviceSpec, ":", 2)
@@ -121,6 +184,12 @@ func runInstall(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("version not found: %w", err)
 	}
 
+	hostNetwork := installHostNetwork || spec.HostNetwork
+	if hostNetwork && spec.IsMultiContainer() {
+		return fmt.Errorf("host networking is only supported for single-container services")
+	}
+	routed := !hostNetwork && (spec.Protocol == "http" || spec.Protocol == "https")
+
 	// Determine actual version
 	actualVersion := version
 	if actualVersion == "" || actualVersion == "latest" {
@@ -172,6 +241,15 @@ func runInstall(cmd *cobra.Command, args []string) error {
 	}
 	fmt.Println()
 
+	labels, err := parseLabels(installLabels)
+	if err != nil {
+		return err
+	}
+	depInstances, err := parseDependencyInstances(installDepInstances)
+	if err != nil {
+		return err
+	}
+
 	// Parse environment variables
 	envOverrides := make(map[string]string)
 	for _, env := range installEnv {
@@ -234,6 +312,14 @@ func runInstall(cmd *cobra.Command, args []string) error {
 
 	// Get config for URL
 	cfg, _ := cfgMgr.Get()
+	if len(installTLSSANs) > 0 {
+		if !routed || installInternal {
+			return fmt.Errorf("--tls-san needs a service Traefik routes to over HTTP: leave out --internal and --host-network")
+		}
+		if err := validateTLSSANs(cfg, installTLSSANs); err != nil {
+			return err
+		}
+	}
 	protocol := cfg.Preferences.Protocol
 	if protocol == "" {
 		protocol = "https"
@@ -244,7 +330,7 @@ func runInstall(cmd *cobra.Command, args []string) error {
 	}
 
 	// Allow user to customize domain
-	if !installYes && (spec.Protocol == "http" || spec.Protocol == "https") {
+	if !installYes && routed {
 		fmt.Println()
 		domainPrompt := &survey.Input{
 			Message: "Domain for this service:",
@@ -256,8 +342,17 @@ func runInstall(cmd *cobra.Command, args []string) error {
 		}
 	}
 
-	if spec.Protocol == "http" || spec.Protocol == "https" {
-		fmt.Printf("URL: %s://%s.%s\n", protocol, instanceName, domain)
+	if hostNetwork {
+		fmt.Println("Network: host (no Traefik routing)")
+	} else if routed {
+		httpPort, httpsPort := config.TraefikPorts(cfg.Traefik)
+		fmt.Printf("URL: %s\n", config.ServiceURL(protocol, instanceName+"."+domain, httpPort, httpsPort))
+		for _, host := range installDomains {
+			fmt.Printf("     %s\n", config.ServiceURL(protocol, host, httpPort, httpsPort))
+		}
+		if len(installTLSSANs) > 0 {
+			fmt.Printf("Certificate: %s, %s\n", serviceCertificateName(instanceName, cfg.Preferences), strings.Join(installTLSSANs, ", "))
+		}
 	}
 	fmt.Println()
 
@@ -286,6 +381,20 @@ func runInstall(cmd *cobra.Command, args []string) error {
 	}
 	defer dockerClient.Close()
 
+	// Traefik needs certificates for the service's own domains
+	if len(installDomains) > 0 {
+		if err := ensureCertificates(cfgMgr, dockerClient, cfg, installDomains); err != nil {
+			return err
+		}
+	}
+
+	// The service's own certificate, with its extra SANs
+	if len(installTLSSANs) > 0 {
+		if err := issueServiceCertificate(cfgMgr, cfg, instanceName, installTLSSANs); err != nil {
+			return err
+		}
+	}
+
 	// Create installer
 	installer, err := service.NewInstaller(dockerClient, cfgMgr, catalogMgr)
 	if err != nil {
@@ -303,8 +412,18 @@ func runInstall(cmd *cobra.Command, args []string) error {
 		Volumes:          volumeMounts,
 		PortMappings:     portMappings,
 		Internal:         installInternal,
+		HostNetwork:      installHostNetwork,
 		SkipDependencies: installSkipDeps,
 		AutoInstallDeps:  !installDisableAutoInstall,
+		HealthTimeout:    installHealthTimeout,
+		DefaultPasswords: installDefaultPasswords,
+		Labels:           labels,
+		Logs:             installLogRotation(),
+		Domains:          installDomains,
+		TLSSANs:          installTLSSANs,
+
+		DependencyInstances: depInstances,
+		ChooseDependencies:  !installYes && docker.IsTerminal(os.Stdin),
 	}
 
 	instance, err := installer.Install(opts)
@@ -317,12 +436,15 @@ func runInstall(cmd *cobra.Command, args []string) error {
 	color.Green("✓ Successfully installed %s", instance.Name)
 	fmt.Println()
 
+	// A reinstall may have changed the credentials or port linked projects use
+	offerRelink(dockerClient, cfgMgr, service.NewManager(dockerClient, cfgMgr), instance.Name)
+
 	// Show DNS setup message for manual mode
-	if cfg.Preferences.DNSSetup == "manual" && (spec.Protocol == "http" || spec.Protocol == "https") {
+	if cfg.Preferences.DNSSetup == "manual" && routed {
 		color.New(color.Bold, color.FgYellow).Println("📝 Manual DNS Setup Required:")
 		fmt.Println()
-		fmt.Printf("Add this entry to your DNS or /etc/hosts:\n")
-		color.Cyan("  127.0.0.1 %s.%s", instance.Name, domain)
+		fmt.Printf("Add this entry to your DNS or %s:\n", dns.NewManager().GetHostsFilePath())
+		color.Cyan("  %s %s.%s", config.HostsIP(cfg.Preferences), instance.Name, domain)
 		fmt.Println()
 		fmt.Println()
 	}
@@ -341,7 +463,13 @@ func runInstall(cmd *cobra.Command, args []string) error {
 	}
 
 	// Show connection information
-	if spec.Protocol == "http" || spec.Protocol == "https" {
+	if instance.UsesHostNetwork() {
+		color.Cyan("Access your service:")
+		if instance.Network.InternalPort > 0 {
+			fmt.Printf("  On the host: localhost:%d\n", instance.Network.InternalPort)
+		}
+		color.New(color.Faint).Println("  (host network: no Traefik URL)")
+	} else if spec.Protocol == "http" || spec.Protocol == "https" {
 		color.Cyan("Access your service:")
 		fmt.Printf("  URL: %s\n", instance.URL)
 		if cfg.Preferences.DNSSetup == "manual" {
@@ -356,7 +484,7 @@ func runInstall(cmd *cobra.Command, args []string) error {
 	// Show admin port if available
 	// For services with admin ports (like RabbitMQ), the main URL routes to the admin/management UI
 	// The AMQP/protocol port is accessed via direct connection
-	if spec.AdminPort > 0 {
+	if spec.AdminPort > 0 && instance.URL != "" {
 		fmt.Printf("  Management UI: %s (port %d)\n", instance.URL, spec.AdminPort)
 	}
 
@@ -444,6 +572,15 @@ func getMonitoringToolName(tool string) string {
 	}
 }
 
+// installLogRotation returns the rotation of the logs given with
+// --log-max-size and --log-max-file, or nil to use the preferences'
+func installLogRotation() *types.LogRotation {
+	if installLogMaxSize == "" && installLogMaxFile == 0 {
+		return nil
+	}
+	return &types.LogRotation{MaxSize: installLogMaxSize, MaxFile: installLogMaxFile}
+}
+
 // parsePortMappings parses port mapping strings into a map[containerPort]hostPort
 // Supports formats:
 //   - "5432"         -> maps container port 5432 to host port 5432
@@ -520,6 +657,11 @@ func installCustomProject(serviceName string) error {
 		return fmt.Errorf("failed to create project manager: %w", err)
 	}
 
+	buildArgs, err := parseBuildArgs(installBuildArgs)
+	if err != nil {
+		return err
+	}
+
 	// Parse environment variables
 	envOverrides := make(map[string]string)
 
@@ -642,12 +784,16 @@ func installCustomProject(serviceName string) error {
 	} else {
 		fmt.Println("Mode: Public (Traefik enabled)")
 		if mainPort > 0 {
-			fmt.Printf("URL: %s://%s\n", protocol, fullSubdomain)
+			httpPort, httpsPort := config.TraefikPorts(cfg.Traefik)
+			fmt.Printf("URL: %s\n", config.ServiceURL(protocol, fullSubdomain, httpPort, httpsPort))
 		}
 	}
 	if mainPort > 0 {
 		fmt.Printf("Port: %d\n", mainPort)
 	}
+	if installTarget != "" {
+		fmt.Printf("Target stage: %s\n", installTarget)
+	}
 	fmt.Println()
 
 	// Check if project already exists
@@ -718,6 +864,8 @@ func installCustomProject(serviceName string) error {
 		Domain:      fullSubdomain,
 		Internal:    installInternal,
 		Replace:     replaceExisting,
+		BuildArgs:   buildArgs,
+		Target:      installTarget,
 	}
 
 	proj, err := projectMgr.Add(addOpts)
@@ -741,11 +889,19 @@ func installCustomProject(serviceName string) error {
 		}
 
 		// Build the Docker image
-		// Pass env vars as both build args (for Next.js, etc.) and runtime env vars
+		// Pass env vars as both build args (for Next.js, etc.) and runtime env vars;
+		// --build-arg values take precedence
+		allBuildArgs := make(map[string]string, len(envOverrides)+len(buildArgs))
+		for key, value := range envOverrides {
+			allBuildArgs[key] = value
+		}
+		for key, value := range buildArgs {
+			allBuildArgs[key] = value
+		}
 		buildOpts := project.BuildOptions{
 			Name:      instanceName,
 			NoCache:   installBuild, // Skip cache if --build flag
-			BuildArgs: envOverrides, // Pass all env vars as build args for frameworks that need them at build time
+			BuildArgs: allBuildArgs, // Pass all env vars as build args for frameworks that need them at build time
 		}
 
 		if err := projectMgr.Build(buildOpts); err != nil {
@@ -774,14 +930,12 @@ func installCustomProject(serviceName string) error {
 	// Add DNS entry for external services
 	if !installInternal && proj.URL != "" {
 		// Extract subdomain from URL (e.g., "https://ui.doku.local" -> "ui.doku.local")
-		subdomain := strings.TrimPrefix(proj.URL, protocol+"://")
-		subdomain = strings.TrimPrefix(subdomain, "https://")
-		subdomain = strings.TrimPrefix(subdomain, "http://")
+		subdomain := config.URLHost(proj.URL)
 
-		dnsMgr := dns.NewManager()
-		if err := dnsMgr.AddSingleEntry("127.0.0.1", subdomain); err != nil {
+		dnsMgr := dns.NewManagerForContext(cfgMgr.GetContext()).SetIP(cfgMgr.GetHostsIP())
+		if err := dnsMgr.AddSingleEntry(dnsMgr.IP(), subdomain); err != nil {
 			color.Yellow("⚠️  Warning: Failed to add DNS entry: %v", err)
-			color.Yellow("   You may need to manually add: 127.0.0.1 %s to /etc/hosts", subdomain)
+			color.Yellow("   You may need to manually add: %s %s to /etc/hosts", dnsMgr.IP(), subdomain)
 		}
 	}
 
@@ -0,0 +1,289 @@
+package cmd
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/AlecAivazis/survey/v2"
+	"github.com/dokulabs/doku-cli/internal/catalog"
+	"github.com/dokulabs/doku-cli/internal/config"
+	"github.com/dokulabs/doku-cli/internal/docker"
+	"github.com/dokulabs/doku-cli/internal/service"
+	"github.com/dokulabs/doku-cli/pkg/types"
+	"github.com/fatih/color"
+)
+
+// interactiveSelection is a catalog service picked in interactive install
+type interactiveSelection struct {
+	service  *types.CatalogService
+	version  string
+	spec     *types.ServiceSpec
+	internal bool
+	env      map[string]string
+}
+
+// runInteractiveInstall lets the user pick several catalog services, asks a
+// few quick questions for each and installs them in dependency order
+func runInteractiveInstall() error {
+	labels, err := parseLabels(installLabels)
+	if err != nil {
+		return err
+	}
+
+	cfgMgr, err := config.New()
+	if err != nil {
+		return fmt.Errorf("failed to create config manager: %w", err)
+	}
+
+	catalogMgr := catalog.NewManager(cfgMgr.GetCatalogDir())
+	if !catalogMgr.CatalogExists() {
+		color.Yellow("⚠️  Catalog not found. Please run 'doku catalog update' first.")
+		return nil
+	}
+
+	services, err := catalogMgr.ListServices()
+	if err != nil {
+		return fmt.Errorf("failed to load catalog: %w", err)
+	}
+	sort.Slice(services, func(i, j int) bool {
+		if services[i].Category != services[j].Category {
+			return services[i].Category < services[j].Category
+		}
+		return services[i].Name < services[j].Name
+	})
+
+	// Build the multi-select; typing filters the list
+	options := make([]string, 0, len(services))
+	byOption := make(map[string]*types.CatalogService, len(services))
+	for _, svc := range services {
+		option := fmt.Sprintf("%s [%s] - %s", svc.Name, svc.Category, svc.Description)
+		if cfgMgr.HasInstance(svc.Name) {
+			option += " (installed)"
+		}
+		options = append(options, option)
+		byOption[option] = svc
+	}
+
+	var picked []string
+	prompt := &survey.MultiSelect{
+		Message:  "Select services to install:",
+		Options:  options,
+		PageSize: 15,
+		Help:     "Type to filter, space to select, enter to confirm",
+	}
+	if err := survey.AskOne(prompt, &picked); err != nil {
+		return err
+	}
+
+	if len(picked) == 0 {
+		color.Yellow("No services selected")
+		return nil
+	}
+
+	// Collect quick options for each selected service
+	selections := make([]*interactiveSelection, 0, len(picked))
+	for _, option := range picked {
+		svc := byOption[option]
+
+		fmt.Println()
+		color.Cyan("%s %s", svc.Icon, svc.Name)
+
+		selection, err := promptSelectionOptions(catalogMgr, svc)
+		if err != nil {
+			return err
+		}
+		selections = append(selections, selection)
+	}
+
+	ordered := orderSelections(selections)
+
+	// Summary and confirmation
+	fmt.Println()
+	color.Cyan("Installation plan:")
+	for idx, selection := range ordered {
+		access := "exposed"
+		if selection.internal {
+			access = "internal"
+		}
+		fmt.Printf("  %d. %s %s (%s)\n", idx+1, selection.service.Name, selection.version, access)
+	}
+	fmt.Println()
+
+	if !installYes {
+		confirm := false
+		confirmPrompt := &survey.Confirm{
+			Message: fmt.Sprintf("Install %d service(s)?", len(ordered)),
+			Default: true,
+		}
+		if err := survey.AskOne(confirmPrompt, &confirm); err != nil {
+			return err
+		}
+		if !confirm {
+			color.Yellow("Installation cancelled")
+			return nil
+		}
+	}
+
+	dockerClient, err := docker.NewClient()
+	if err != nil {
+		return fmt.Errorf("failed to create Docker client: %w", err)
+	}
+	defer dockerClient.Close()
+
+	installer, err := service.NewInstaller(dockerClient, cfgMgr, catalogMgr)
+	if err != nil {
+		return fmt.Errorf("failed to create installer: %w", err)
+	}
+
+	var installed, skipped, failed []string
+	for _, selection := range ordered {
+		name := selection.service.Name
+
+		// A selected service may already have been installed as a dependency
+		if cfgMgr.HasInstance(name) {
+			color.Yellow("⚠️  %s is already installed, skipping", name)
+			skipped = append(skipped, name)
+			continue
+		}
+
+		fmt.Println()
+		color.Cyan("Installing %s %s...", name, selection.version)
+
+		_, err := installer.Install(service.InstallOptions{
+			ServiceName:      name,
+			Version:          selection.version,
+			Environment:      selection.env,
+			Internal:         selection.internal,
+			SkipDependencies: installSkipDeps,
+			AutoInstallDeps:  true,
+			HealthTimeout:    installHealthTimeout,
+			DefaultPasswords: installDefaultPasswords,
+			Labels:           labels,
+			Logs:             installLogRotation(),
+		})
+		if err != nil {
+			color.Red("✗ Failed to install %s: %v", name, err)
+			failed = append(failed, name)
+			continue
+		}
+
+		color.Green("✓ Successfully installed %s", name)
+		installed = append(installed, name)
+	}
+
+	fmt.Println()
+	if len(installed) > 0 {
+		color.Green("✓ Installed: %s", strings.Join(installed, ", "))
+	}
+	if len(skipped) > 0 {
+		color.Yellow("Skipped: %s", strings.Join(skipped, ", "))
+	}
+	if len(failed) > 0 {
+		return fmt.Errorf("failed to install: %s", strings.Join(failed, ", "))
+	}
+
+	fmt.Println()
+	fmt.Println("Run 'doku list' to see your services")
+
+	return nil
+}
+
+// promptSelectionOptions asks for the version, access and required
+// configuration of a selected service
+func promptSelectionOptions(catalogMgr *catalog.Manager, svc *types.CatalogService) (*interactiveSelection, error) {
+	versions := catalog.SortedVersions(svc)
+	if len(versions) == 0 {
+		return nil, fmt.Errorf("service '%s' has no versions", svc.Name)
+	}
+
+	version := versions[0]
+	if len(versions) > 1 {
+		versionPrompt := &survey.Select{
+			Message: "Version:",
+			Options: versions,
+			Default: version,
+		}
+		if err := survey.AskOne(versionPrompt, &version); err != nil {
+			return nil, err
+		}
+	}
+
+	spec, err := catalogMgr.GetServiceVersion(svc.Name, version)
+	if err != nil {
+		return nil, err
+	}
+
+	selection := &interactiveSelection{
+		service: svc,
+		version: version,
+		spec:    spec,
+		env:     make(map[string]string),
+	}
+
+	if spec.Protocol == "http" || spec.Protocol == "https" {
+		expose := true
+		exposePrompt := &survey.Confirm{
+			Message: "Expose through Traefik?",
+			Default: !installInternal,
+		}
+		if err := survey.AskOne(exposePrompt, &expose); err != nil {
+			return nil, err
+		}
+		selection.internal = !expose
+	} else {
+		selection.internal = installInternal
+	}
+
+	// Only ask for required options; everything else keeps its default
+	if spec.Configuration != nil {
+		for _, opt := range spec.Configuration.Options {
+			if !opt.Required {
+				continue
+			}
+			value, err := promptForOption(opt)
+			if err != nil {
+				return nil, err
+			}
+			if value != "" {
+				selection.env[opt.EnvVar] = value
+			}
+		}
+	}
+
+	return selection, nil
+}
+
+// orderSelections orders selected services so that each one comes after the
+// selected services it depends on. Selection order is kept otherwise.
+func orderSelections(selections []*interactiveSelection) []*interactiveSelection {
+	byName := make(map[string]*interactiveSelection, len(selections))
+	for _, selection := range selections {
+		byName[selection.service.Name] = selection
+	}
+
+	ordered := make([]*interactiveSelection, 0, len(selections))
+	visited := make(map[string]bool, len(selections))
+
+	var visit func(selection *interactiveSelection)
+	visit = func(selection *interactiveSelection) {
+		name := selection.service.Name
+		if visited[name] {
+			return
+		}
+		visited[name] = true
+
+		for _, dep := range selection.spec.Dependencies {
+			if depSelection, ok := byName[dep.Name]; ok {
+				visit(depSelection)
+			}
+		}
+		ordered = append(ordered, selection)
+	}
+
+	for _, selection := range selections {
+		visit(selection)
+	}
+
+	return ordered
+}
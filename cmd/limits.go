@@ -0,0 +1,318 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/dokulabs/doku-cli/internal/config"
+	"github.com/dokulabs/doku-cli/internal/docker"
+	"github.com/dokulabs/doku-cli/internal/service"
+	"github.com/dokulabs/doku-cli/pkg/types"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+var (
+	limitsMemory string
+	limitsCPU    string
+)
+
+var limitsCmd = &cobra.Command{
+	Use:   "limits",
+	Short: "Manage the memory and CPU limits of services",
+	Long: `Show and change the memory and CPU limits services run with.
+
+A service runs with the limits given at install (--memory, --cpu), else the
+catalog's, else the defaults: set them so a forgotten service can't take up
+all of the machine's memory. Changes apply to the containers in place,
+without recreating them.
+
+'doku limits disable' lifts every limit, e.g. for a benchmark, until 'doku
+limits enable' restores them. Docker can't lift a memory limit in place: it's
+raised to the machine's memory instead.
+
+A multi-container service's containers all get its limits, which replace the
+catalog's per container.
+
+Examples:
+  doku limits                                   # Show the limits
+  doku limits defaults --memory 2g --cpu 2      # Limit services without limits of their own
+  doku limits set clickhouse --memory 4g
+  doku limits set clickhouse --memory none      # Back to the default
+  doku limits disable
+  doku limits enable`,
+	Args: cobra.NoArgs,
+	RunE: runLimitsShow,
+}
+
+var limitsSetCmd = &cobra.Command{
+	Use:   "set <service>",
+	Short: "Change the limits of a service without recreating it",
+	Long: `Change the memory and CPU limits of a service, its replicas included,
+without recreating it. "none" removes the service's own limit, so it runs
+with the default.`,
+	Args: cobra.ExactArgs(1),
+	RunE: runLimitsSet,
+}
+
+var limitsDefaultsCmd = &cobra.Command{
+	Use:   "defaults",
+	Short: "Set the limits of services without limits of their own",
+	Long: `Set the memory and CPU limits of services installed without any, now and
+from now on. "none" removes a default. Without flags, show the defaults.`,
+	Args: cobra.NoArgs,
+	RunE: runLimitsDefaults,
+}
+
+var limitsEnableCmd = &cobra.Command{
+	Use:   "enable",
+	Short: "Run services with their limits again",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runLimitsToggle(false)
+	},
+}
+
+var limitsDisableCmd = &cobra.Command{
+	Use:   "disable",
+	Short: "Lift the limits of every service until they are enabled again",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runLimitsToggle(true)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(limitsCmd)
+
+	limitsCmd.AddCommand(limitsSetCmd)
+	limitsCmd.AddCommand(limitsDefaultsCmd)
+	limitsCmd.AddCommand(limitsEnableCmd)
+	limitsCmd.AddCommand(limitsDisableCmd)
+
+	for _, cmd := range []*cobra.Command{limitsSetCmd, limitsDefaultsCmd} {
+		cmd.Flags().StringVar(&limitsMemory, "memory", "", "Memory limit, e.g. 512m or 2g (none to remove it)")
+		cmd.Flags().StringVar(&limitsCPU, "cpu", "", "CPU limit in cores, e.g. 0.5 or 2 (none to remove it)")
+	}
+}
+
+// limitFlags returns the limits of the --memory and --cpu flags, keeping
+// the current ones where a flag isn't given; "none" is empty
+func limitFlags(cmd *cobra.Command, memory, cpu string) (string, string, error) {
+	if !cmd.Flags().Changed("memory") && !cmd.Flags().Changed("cpu") {
+		return "", "", fmt.Errorf("give --memory, --cpu or both")
+	}
+	if cmd.Flags().Changed("memory") {
+		memory = limitsMemory
+		if memory == "none" {
+			memory = ""
+		} else if _, err := docker.ParseMemoryString(memory); err != nil {
+			return "", "", fmt.Errorf("invalid --memory: %w", err)
+		}
+	}
+	if cmd.Flags().Changed("cpu") {
+		cpu = limitsCPU
+		if cpu == "none" {
+			cpu = ""
+		} else if _, _, err := docker.ParseCPUString(cpu); err != nil {
+			return "", "", fmt.Errorf("invalid --cpu: %w", err)
+		}
+	}
+	return memory, cpu, nil
+}
+
+func runLimitsShow(cmd *cobra.Command, args []string) error {
+	cfgMgr, err := initConfigManager()
+	if err != nil {
+		if err == types.ErrNotInitialized {
+			return nil
+		}
+		return err
+	}
+	cfg, err := cfgMgr.Get()
+	if err != nil {
+		return fmt.Errorf("failed to get config: %w", err)
+	}
+
+	printLimitDefaults(cfg.Limits)
+	if len(cfg.Instances) == 0 {
+		return nil
+	}
+	fmt.Println()
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
+	fmt.Fprintln(w, "SERVICE\tMEMORY\tCPU")
+	for _, name := range mapKeys(cfg.Instances) {
+		own := cfg.Instances[name].Resources
+		memory, cpu := service.EffectiveLimits(cfg.Limits, own)
+		fmt.Fprintf(w, "%s\t%s\t%s\n", name, describeLimit(memory, own.MemoryLimit), describeLimit(cpu, own.CPULimit))
+	}
+	return w.Flush()
+}
+
+// printLimitDefaults prints the default limits, and whether limits apply
+func printLimitDefaults(limits types.LimitsConfig) {
+	unset := color.New(color.Faint).Sprint("none")
+	memory, cpu := unset, unset
+	if limits.Memory != "" {
+		memory = limits.Memory
+	}
+	if limits.CPU != "" {
+		cpu = limits.CPU
+	}
+	fmt.Printf("Defaults: memory %s, CPU %s\n", memory, cpu)
+	if limits.Disabled {
+		color.Yellow("⚠️  Limits are disabled: services run without any. Run 'doku limits enable' to restore them.")
+	}
+}
+
+// describeLimit shows a limit a service runs with, and whether it's the
+// default
+func describeLimit(limit, own string) string {
+	switch {
+	case limit == "":
+		return "unlimited"
+	case own == "":
+		return limit + " (default)"
+	}
+	return limit
+}
+
+func runLimitsSet(cmd *cobra.Command, args []string) error {
+	cfgMgr, err := initConfigManager()
+	if err != nil {
+		if err == types.ErrNotInitialized {
+			return nil
+		}
+		return err
+	}
+	name := args[0]
+	instance, err := cfgMgr.GetInstance(name)
+	if err != nil {
+		return fmt.Errorf("service '%s' not found", name)
+	}
+	memory, cpu, err := limitFlags(cmd, instance.Resources.MemoryLimit, instance.Resources.CPULimit)
+	if err != nil {
+		return err
+	}
+
+	dockerClient, err := initDockerClient()
+	if err != nil {
+		return err
+	}
+	defer dockerClient.Close()
+
+	if err := getServiceManager(dockerClient, cfgMgr).SetLimits(name, memory, cpu); err != nil {
+		return err
+	}
+
+	cfg, err := cfgMgr.Get()
+	if err != nil {
+		return fmt.Errorf("failed to get config: %w", err)
+	}
+	effectiveMemory, effectiveCPU := service.EffectiveLimits(cfg.Limits, types.ResourceConfig{MemoryLimit: memory, CPULimit: cpu})
+	color.Green("✓ %s limited to memory %s, CPU %s", name, describeLimit(effectiveMemory, memory), describeLimit(effectiveCPU, cpu))
+	if cfg.Limits.Disabled {
+		color.Yellow("⚠️  Limits are disabled: they apply once you run 'doku limits enable'")
+	}
+	return nil
+}
+
+func runLimitsDefaults(cmd *cobra.Command, args []string) error {
+	cfgMgr, err := initConfigManager()
+	if err != nil {
+		if err == types.ErrNotInitialized {
+			return nil
+		}
+		return err
+	}
+	cfg, err := cfgMgr.Get()
+	if err != nil {
+		return fmt.Errorf("failed to get config: %w", err)
+	}
+	if !cmd.Flags().Changed("memory") && !cmd.Flags().Changed("cpu") {
+		printLimitDefaults(cfg.Limits)
+		return nil
+	}
+
+	memory, cpu, err := limitFlags(cmd, cfg.Limits.Memory, cfg.Limits.CPU)
+	if err != nil {
+		return err
+	}
+	if err := cfgMgr.Update(func(c *types.Config) error {
+		c.Limits.Memory = memory
+		c.Limits.CPU = cpu
+		return nil
+	}); err != nil {
+		return fmt.Errorf("failed to save the default limits: %w", err)
+	}
+	color.Green("✓ Default limits saved")
+
+	// Services with limits of their own for both keep them
+	return applyLimits(cfgMgr, func(instance *types.Instance) bool {
+		return instance.Resources.MemoryLimit == "" || instance.Resources.CPULimit == ""
+	})
+}
+
+func runLimitsToggle(disable bool) error {
+	cfgMgr, err := initConfigManager()
+	if err != nil {
+		if err == types.ErrNotInitialized {
+			return nil
+		}
+		return err
+	}
+	if err := cfgMgr.Update(func(c *types.Config) error {
+		c.Limits.Disabled = disable
+		return nil
+	}); err != nil {
+		return fmt.Errorf("failed to save limits: %w", err)
+	}
+	if disable {
+		color.Green("✓ Limits disabled")
+	} else {
+		color.Green("✓ Limits enabled")
+	}
+	return applyLimits(cfgMgr, func(*types.Instance) bool { return true })
+}
+
+// applyLimits updates the containers of the services affected to the limits
+// they should run with, reporting those that couldn't be
+func applyLimits(cfgMgr *config.Manager, affected func(instance *types.Instance) bool) error {
+	cfg, err := cfgMgr.Get()
+	if err != nil {
+		return fmt.Errorf("failed to get config: %w", err)
+	}
+	var names []string
+	for _, name := range mapKeys(cfg.Instances) {
+		if affected(cfg.Instances[name]) {
+			names = append(names, name)
+		}
+	}
+	if len(names) == 0 {
+		return nil
+	}
+
+	dockerClient, err := initDockerClient()
+	if err != nil {
+		return err
+	}
+	defer dockerClient.Close()
+	serviceMgr := getServiceManager(dockerClient, cfgMgr)
+
+	failed := 0
+	for _, name := range names {
+		if err := serviceMgr.ApplyLimits(cfg.Instances[name]); err != nil {
+			color.Yellow("⚠️  %s: %v", name, err)
+			failed++
+			continue
+		}
+		memory, cpu := service.EffectiveLimits(cfg.Limits, cfg.Instances[name].Resources)
+		fmt.Printf("  %s: memory %s, CPU %s\n", name, describeLimit(memory, cfg.Instances[name].Resources.MemoryLimit), describeLimit(cpu, cfg.Instances[name].Resources.CPULimit))
+	}
+	if failed > 0 {
+		return fmt.Errorf("failed to update the limits of %d service(s)", failed)
+	}
+	return nil
+}
@@ -0,0 +1,120 @@
+package cmd
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/dokulabs/doku-cli/internal/config"
+	"github.com/dokulabs/doku-cli/internal/envfile"
+	"github.com/dokulabs/doku-cli/pkg/types"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+var (
+	linkPrefix  string
+	linkRestart bool
+)
+
+var linkCmd = &cobra.Command{
+	Use:   "link <project> <service>...",
+	Short: "Inject a service's connection details into a project",
+	Long: `Add the connection details of installed services to the env file of a
+custom project or service, then offer to recreate it.
+
+Each linked service adds <PREFIX>_HOST, _PORT, _USER, _PASSWORD, _DATABASE
+and _URL, when the service has them. The prefix defaults to the service
+name in upper case.
+
+Examples:
+  doku link myapp postgres                 # POSTGRES_HOST, POSTGRES_URL, ...
+  doku link myapp postgres redis
+  doku link myapp postgres --prefix DB     # DB_HOST, DB_URL, ...
+  doku link myapp postgres --restart       # Recreate without asking`,
+	Args: cobra.MinimumNArgs(2),
+	RunE: runLink,
+}
+
+func init() {
+	rootCmd.AddCommand(linkCmd)
+
+	linkCmd.Flags().StringVar(&linkPrefix, "prefix", "", "Prefix of the injected variables (default: service name in upper case)")
+	linkCmd.Flags().BoolVarP(&linkRestart, "restart", "r", false, "Recreate the project without asking so the variables take effect")
+}
+
+func runLink(cmd *cobra.Command, args []string) error {
+	instanceName := args[0]
+	serviceNames := args[1:]
+
+	if linkPrefix != "" && len(serviceNames) > 1 {
+		return fmt.Errorf("--prefix can only be used when linking a single service")
+	}
+
+	cfgMgr, err := initConfigManager()
+	if err != nil {
+		if err == types.ErrNotInitialized {
+			return nil
+		}
+		return err
+	}
+
+	dockerClient, err := initDockerClient()
+	if err != nil {
+		return err
+	}
+	defer dockerClient.Close()
+
+	serviceMgr := getServiceManager(dockerClient, cfgMgr)
+
+	instance, err := serviceMgr.Get(instanceName)
+	if err != nil {
+		return fmt.Errorf("'%s' not found. Use 'doku list' to see installed services and projects", instanceName)
+	}
+
+	fmt.Println()
+	var keys []string
+	for _, serviceName := range serviceNames {
+		env, err := serviceMgr.Link(instanceName, serviceName, linkPrefix)
+		if err != nil {
+			return err
+		}
+
+		color.Cyan("Linked %s to %s", serviceName, instanceName)
+		serviceKeys := make([]string, 0, len(env))
+		for key := range env {
+			serviceKeys = append(serviceKeys, key)
+		}
+		sort.Strings(serviceKeys)
+		for _, key := range serviceKeys {
+			value := env[key]
+			// Connection URLs carry the password too
+			if isSensitiveKey(key) || strings.HasSuffix(key, "_URL") {
+				value = maskValue(value)
+			}
+			fmt.Printf("  %s = %s\n", color.GreenString(key), value)
+		}
+		fmt.Println()
+		keys = append(keys, serviceKeys...)
+	}
+
+	envPath := instanceEnvPath(cfgMgr, instance)
+	color.Green("✓ Environment variables saved to %s", envPath)
+	fmt.Println()
+
+	env, err := envfile.LoadEnvFile(envPath)
+	if err != nil {
+		return fmt.Errorf("failed to load environment file: %w", err)
+	}
+
+	return applyEnvChanges(dockerClient, cfgMgr, serviceMgr, instance, env, keys, linkRestart)
+}
+
+// instanceEnvPath returns the env file of a service or custom project
+func instanceEnvPath(cfgMgr *config.Manager, instance *types.Instance) string {
+	envMgr := envfile.NewManager(cfgMgr.GetDokuDir())
+	if instance.ServiceType == "custom-project" {
+		return envMgr.GetProjectEnvPath(instance.Name)
+	}
+	return envMgr.GetServiceEnvPath(instance.Name, "")
+}
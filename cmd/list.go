@@ -4,8 +4,10 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"os/signal"
 	"strings"
 	"sync"
+	"syscall"
 	"text/tabwriter"
 	"time"
 
@@ -18,16 +20,30 @@ import (
 )
 
 var (
-	listAll     bool
-	listService string
-	listVerbose bool
-	listHealth  bool
+	listAll      bool
+	listService  string
+	listVerbose  bool
+	listHealth   bool
+	listStats    bool
+	listWatch    bool
+	listInterval time.Duration
 )
 
+// listStatsTimeout bounds how long a single container's stats sample may take
+const listStatsTimeout = 3 * time.Second
+
 var listCmd = &cobra.Command{
-	Use:     "list",
-	Short:   "List all installed services",
-	Long:    "List all installed services with their status, versions, and access URLs",
+	Use:   "list",
+	Short: "List all installed services",
+	Long: `List all installed services with their status, versions, and access URLs.
+
+Examples:
+  doku list                 # Running services
+  doku list --all           # Include stopped services
+  doku list --stats         # Add CPU and memory usage columns
+  doku list --health        # Add health check status
+  doku list --watch         # Refresh until Ctrl+C, like 'watch docker ps'
+  doku list -w --interval 10s`,
 	Aliases: []string{"ls"},
 	RunE:    runList,
 }
@@ -37,8 +53,12 @@ func init() {
 
 	listCmd.Flags().BoolVarP(&listAll, "all", "a", false, "Show all instances including stopped")
 	listCmd.Flags().StringVarP(&listService, "service", "s", "", "Filter by service type")
+	_ = listCmd.RegisterFlagCompletionFunc("service", completeCatalogServices(false))
 	listCmd.Flags().BoolVarP(&listVerbose, "verbose", "v", false, "Show detailed information")
 	listCmd.Flags().BoolVar(&listHealth, "health", false, "Show health check status")
+	listCmd.Flags().BoolVar(&listStats, "stats", false, "Show CPU and memory usage of running services")
+	listCmd.Flags().BoolVarP(&listWatch, "watch", "w", false, "Refresh continuously, and as soon as a service changes")
+	listCmd.Flags().DurationVar(&listInterval, "interval", 2*time.Second, "Refresh interval for --watch")
 }
 
 func runList(cmd *cobra.Command, args []string) error {
@@ -64,6 +84,56 @@ func runList(cmd *cobra.Command, args []string) error {
 	// Create service manager
 	serviceMgr := service.NewManager(dockerClient, cfgMgr)
 
+	if listWatch {
+		return watchList(cfgMgr, dockerClient, serviceMgr)
+	}
+	return renderList(context.Background(), cfgMgr, dockerClient, serviceMgr)
+}
+
+// watchList redraws the list every --interval, and as soon as Docker
+// reports a service starting, stopping, crashing or changing health
+func watchList(cfgMgr *config.Manager, dockerClient *docker.Client, serviceMgr *service.Manager) error {
+	if listInterval < statusMinInterval {
+		listInterval = statusMinInterval
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	changed := make(chan struct{}, 1)
+	go serviceMgr.Watch(ctx, func(service.StatusChange) {
+		select {
+		case changed <- struct{}{}:
+		default:
+		}
+	})
+
+	ticker := time.NewTicker(listInterval)
+	defer ticker.Stop()
+
+	for {
+		// Clear the screen and redraw from the top
+		fmt.Print("\033[H\033[2J")
+		color.New(color.Bold).Printf("Doku services  %s\n", time.Now().Format("15:04:05"))
+		color.New(color.Faint).Printf("Refreshing every %s, press Ctrl+C to quit\n", listInterval)
+
+		if err := renderList(ctx, cfgMgr, dockerClient, serviceMgr); err != nil && ctx.Err() == nil {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			fmt.Println()
+			return nil
+		case <-ticker.C:
+		case <-changed:
+		}
+	}
+}
+
+// renderList shows the instances selected by the flags, with their status
+// refreshed from Docker
+func renderList(ctx context.Context, cfgMgr *config.Manager, dockerClient *docker.Client, serviceMgr *service.Manager) error {
 	// Get all instances
 	instances, err := serviceMgr.List()
 	if err != nil {
@@ -95,7 +165,6 @@ func runList(cmd *cobra.Command, args []string) error {
 	}
 
 	// Update instance statuses from Docker in parallel
-	ctx := context.Background()
 	var wg sync.WaitGroup
 
 	for _, instance := range filteredInstances {
@@ -114,8 +183,21 @@ func runList(cmd *cobra.Command, args []string) error {
 		updateHealthStatus(ctx, dockerClient, filteredInstances)
 	}
 
+	// Sample resource usage if requested
+	if listStats {
+		updateResourceUsage(ctx, dockerClient, filteredInstances)
+	}
+
 	// Display instances
-	displayInstances(filteredInstances, cfg.Preferences.Protocol, cfg.Preferences.Domain, listVerbose, listHealth)
+	displayInstances(filteredInstances, cfg.Preferences.Protocol, cfg.Preferences.Domain, config.LocalHost(cfg.Preferences), listVerbose, listHealth, listStats)
+
+	for _, instance := range filteredInstances {
+		if instance.Status == types.StatusMissing {
+			color.Yellow("⚠️  Some containers were removed outside Doku. Run 'doku sync' to recreate them.")
+			fmt.Println()
+			break
+		}
+	}
 
 	return nil
 }
@@ -129,8 +211,9 @@ func filterInstances(instances []*types.Instance, serviceFilter string, showAll
 			continue
 		}
 
-		// Filter by status (if not showing all)
-		if !showAll && instance.Status != types.StatusRunning {
+		// Filter by status (if not showing all). Missing instances are
+		// always shown, as they need attention.
+		if !showAll && instance.Status != types.StatusRunning && instance.Status != types.StatusMissing {
 			continue
 		}
 
@@ -151,6 +234,9 @@ func updateInstanceStatus(ctx context.Context, dockerClient *docker.Client, inst
 	containerInfo, err := dockerClient.ContainerInspect(instance.ContainerName)
 	if err != nil {
 		instance.Status = types.StatusUnknown
+		if exists, err := dockerClient.ContainerExists(instance.ContainerName); err == nil && !exists {
+			instance.Status = types.StatusMissing
+		}
 		return
 	}
 
@@ -167,8 +253,8 @@ func updateInstanceStatus(ctx context.Context, dockerClient *docker.Client, inst
 		instance.Status = types.StatusStopped
 	}
 
-	// Note: Resource usage (CPU/Memory stats) is not currently displayed in list output
-	// The updateResourceUsage function has been removed to improve performance
+	// Note: Resource usage (CPU/Memory stats) is only sampled with --stats,
+	// see updateResourceUsage
 }
 
 // updateMultiContainerStatus updates status for multi-container services in parallel
@@ -176,6 +262,7 @@ func updateMultiContainerStatus(ctx context.Context, dockerClient *docker.Client
 	runningCount := 0
 	stoppedCount := 0
 	failedCount := 0
+	missingCount := 0
 
 	// Use mutex to safely update counters from goroutines
 	var mu sync.Mutex
@@ -189,6 +276,12 @@ func updateMultiContainerStatus(ctx context.Context, dockerClient *docker.Client
 			containerInfo, err := dockerClient.ContainerInspect(container.ContainerID)
 			if err != nil {
 				container.Status = "unknown"
+				if exists, err := dockerClient.ContainerExists(container.FullName); err == nil && !exists {
+					container.Status = "missing"
+					mu.Lock()
+					missingCount++
+					mu.Unlock()
+				}
 				return
 			}
 
@@ -212,7 +305,9 @@ func updateMultiContainerStatus(ctx context.Context, dockerClient *docker.Client
 	wg.Wait()
 
 	// Determine overall status
-	if failedCount > 0 {
+	if missingCount > 0 {
+		instance.Status = types.StatusMissing
+	} else if failedCount > 0 {
 		instance.Status = types.StatusFailed
 	} else if runningCount == len(instance.Containers) {
 		instance.Status = types.StatusRunning
@@ -270,9 +365,97 @@ func checkInstanceHealth(ctx context.Context, dockerClient *docker.Client, insta
 	}
 }
 
-func displayInstances(instances []*types.Instance, protocol, domain string, verbose, showHealth bool) {
+// updateResourceUsage samples CPU and memory usage of running instances in
+// parallel. Each container sample is bounded by listStatsTimeout so a slow
+// daemon never blocks the listing; usage is left empty when sampling fails.
+func updateResourceUsage(ctx context.Context, dockerClient *docker.Client, instances []*types.Instance) {
+	var wg sync.WaitGroup
+
+	for _, instance := range instances {
+		if instance.Status != types.StatusRunning {
+			continue
+		}
+
+		wg.Add(1)
+		go func(inst *types.Instance) {
+			defer wg.Done()
+			sampleInstanceUsage(ctx, dockerClient, inst)
+		}(instance)
+	}
+
+	wg.Wait()
+}
+
+// sampleInstanceUsage sums the resource usage of all running containers of an instance
+func sampleInstanceUsage(ctx context.Context, dockerClient *docker.Client, instance *types.Instance) {
+	stats := sampleInstanceStats(ctx, dockerClient, instance)
+	if stats == nil {
+		instance.Resources.CPUUsage = ""
+		instance.Resources.MemoryUsage = ""
+		return
+	}
+
+	instance.Resources.CPUUsage = fmt.Sprintf("%.1f%%", stats.CPUPercent)
+	instance.Resources.MemoryUsage = formatBytes(int64(stats.MemoryUsage))
+}
+
+// sampleInstanceStats samples all running containers of an instance in
+// parallel and sums their usage. The memory limit is the largest container
+// limit. Returns nil when no container could be sampled.
+func sampleInstanceStats(ctx context.Context, dockerClient *docker.Client, instance *types.Instance) *docker.ContainerStatsResult {
+	var containers []string
+	if instance.IsMultiContainer {
+		for _, c := range instance.Containers {
+			if c.Status == "running" {
+				containers = append(containers, c.FullName)
+			}
+		}
+	} else {
+		containers = append(containers, instance.ContainerName)
+	}
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	total := &docker.ContainerStatsResult{}
+	sampled := 0
+
+	for _, name := range containers {
+		wg.Add(1)
+		go func(containerName string) {
+			defer wg.Done()
+
+			statsCtx, cancel := context.WithTimeout(ctx, listStatsTimeout)
+			defer cancel()
+
+			stats, err := dockerClient.ContainerStats(statsCtx, containerName)
+			if err != nil {
+				return
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+			total.CPUPercent += stats.CPUPercent
+			total.MemoryUsage += stats.MemoryUsage
+			total.NetworkRx += stats.NetworkRx
+			total.NetworkTx += stats.NetworkTx
+			if stats.MemoryLimit > total.MemoryLimit {
+				total.MemoryLimit = stats.MemoryLimit
+			}
+			sampled++
+		}(name)
+	}
+
+	wg.Wait()
+
+	if sampled == 0 {
+		return nil
+	}
+	return total
+}
+
+func displayInstances(instances []*types.Instance, protocol, domain, localHost string, verbose, showHealth, showStats bool) {
 	if verbose {
-		displayInstancesVerbose(instances, protocol, domain, showHealth)
+		displayInstancesVerbose(instances, protocol, domain, localHost, showHealth, showStats)
 		return
 	}
 
@@ -282,26 +465,15 @@ func displayInstances(instances []*types.Instance, protocol, domain string, verb
 	w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
 
 	// Print header - plain text without colors for proper alignment
+	header := []string{"NAME", "SERVICE", "VERSION", "STATUS"}
 	if showHealth {
-		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%s\n",
-			"NAME",
-			"SERVICE",
-			"VERSION",
-			"STATUS",
-			"HEALTH",
-			"PORTS",
-			"URL",
-		)
-	} else {
-		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n",
-			"NAME",
-			"SERVICE",
-			"VERSION",
-			"STATUS",
-			"PORTS",
-			"URL",
-		)
+		header = append(header, "HEALTH")
+	}
+	if showStats {
+		header = append(header, "CPU", "MEM")
 	}
+	header = append(header, "PORTS", "URL")
+	fmt.Fprintln(w, strings.Join(header, "\t"))
 
 	// Print each instance
 	for _, instance := range instances {
@@ -324,6 +496,9 @@ func displayInstances(instances []*types.Instance, protocol, domain string, verb
 
 		// Format status (plain text to fix alignment)
 		status := formatStatusTextForTable(instance.Status)
+		if stoppedUnexpectedly(instance) {
+			status += " (unexpected)"
+		}
 
 		// Format health
 		health := formatHealthForTable(instance.HealthStatus)
@@ -337,26 +512,15 @@ func displayInstances(instances []*types.Instance, protocol, domain string, verb
 			url = "-"
 		}
 
+		row := []string{name, serviceType, version, status}
 		if showHealth {
-			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%s\n",
-				name,
-				serviceType,
-				version,
-				status,
-				health,
-				ports,
-				url,
-			)
-		} else {
-			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n",
-				name,
-				serviceType,
-				version,
-				status,
-				ports,
-				url,
-			)
+			row = append(row, health)
+		}
+		if showStats {
+			row = append(row, formatUsageForTable(instance.Resources.CPUUsage), formatUsageForTable(instance.Resources.MemoryUsage))
 		}
+		row = append(row, ports, url)
+		fmt.Fprintln(w, strings.Join(row, "\t"))
 	}
 
 	w.Flush()
@@ -365,6 +529,13 @@ func displayInstances(instances []*types.Instance, protocol, domain string, verb
 	fmt.Println()
 }
 
+func formatUsageForTable(usage string) string {
+	if usage == "" || usage == "N/A" {
+		return "-"
+	}
+	return usage
+}
+
 func formatHealthForTable(health string) string {
 	switch health {
 	case "healthy":
@@ -380,7 +551,7 @@ func formatHealthForTable(health string) string {
 	}
 }
 
-func displayInstancesVerbose(instances []*types.Instance, protocol, domain string, showHealth bool) {
+func displayInstancesVerbose(instances []*types.Instance, protocol, domain, localHost string, showHealth, showStats bool) {
 	fmt.Println()
 	color.New(color.Bold, color.FgCyan).Println("📋 Installed Services")
 	fmt.Println()
@@ -390,7 +561,13 @@ func displayInstancesVerbose(instances []*types.Instance, protocol, domain strin
 			fmt.Println()
 		}
 
-		displayInstance(instance, protocol, domain, true)
+		displayInstance(instance, protocol, domain, localHost, true)
+
+		// Usage sampled with --stats, unless shown next to both limits
+		res := instance.Resources
+		if showStats && (res.MemoryLimit == "" || res.CPULimit == "") {
+			fmt.Printf("  Usage: %s CPU, %s memory\n", formatUsageForTable(res.CPUUsage), formatUsageForTable(res.MemoryUsage))
+		}
 	}
 
 	fmt.Println()
@@ -398,7 +575,7 @@ func displayInstancesVerbose(instances []*types.Instance, protocol, domain strin
 	fmt.Println()
 }
 
-func displayInstance(instance *types.Instance, protocol, domain string, verbose bool) {
+func displayInstance(instance *types.Instance, protocol, domain, localHost string, verbose bool) {
 	// Status indicator
 	statusColor := getStatusColor(instance.Status)
 	statusIcon := getStatusIcon(instance.Status)
@@ -428,6 +605,14 @@ func displayInstance(instance *types.Instance, protocol, domain string, verbose
 		}
 	}
 
+	if instance.ReplicaCount() > 1 {
+		fmt.Printf("  Replicas: %d\n", instance.ReplicaCount())
+	}
+
+	if instance.UsesHostNetwork() {
+		fmt.Printf("  Network: %s\n", color.CyanString("host (no Traefik routing)"))
+	}
+
 	// URL (if Traefik enabled)
 	if instance.Traefik.Enabled && instance.URL != "" {
 		fmt.Printf("  URL: %s\n", color.GreenString(instance.URL))
@@ -474,7 +659,11 @@ func displayInstance(instance *types.Instance, protocol, domain string, verbose
 
 	// Access instructions (if running)
 	if instance.Status == types.StatusRunning && !verbose {
-		if instance.Traefik.Enabled {
+		if instance.UsesHostNetwork() {
+			if instance.Network.InternalPort > 0 {
+				fmt.Printf("  Access: %s\n", color.New(color.Faint).Sprintf("On the host at localhost:%d", instance.Network.InternalPort))
+			}
+		} else if instance.Traefik.Enabled {
 			fmt.Printf("  Access: %s\n", color.New(color.Faint).Sprintf("Open %s in your browser", instance.URL))
 		} else if instance.Network.InternalPort > 0 {
 			fmt.Printf("  Access: %s\n", color.New(color.Faint).Sprintf("Internal only (port %d)", instance.Network.InternalPort))
@@ -484,11 +673,11 @@ func displayInstance(instance *types.Instance, protocol, domain string, verbose
 	// Show host port mappings
 	if len(instance.Network.PortMappings) > 0 {
 		for containerPort, hostPort := range instance.Network.PortMappings {
-			fmt.Printf("  Port: localhost:%s → container:%s\n", hostPort, containerPort)
+			fmt.Printf("  Port: %s:%s → container:%s\n", localHost, hostPort, containerPort)
 		}
 	} else if instance.Network.HostPort > 0 {
 		// Backward compatibility with old single port format
-		fmt.Printf("  Port: localhost:%d → container:%d\n", instance.Network.HostPort, instance.Network.InternalPort)
+		fmt.Printf("  Port: %s:%d → container:%d\n", localHost, instance.Network.HostPort, instance.Network.InternalPort)
 	}
 }
 
@@ -498,7 +687,7 @@ func getStatusColor(status types.ServiceStatus) func(format string, a ...interfa
 		return color.Green
 	case types.StatusStopped:
 		return color.Yellow
-	case types.StatusFailed:
+	case types.StatusFailed, types.StatusMissing:
 		return color.Red
 	default:
 		return func(format string, a ...interface{}) {
@@ -513,7 +702,7 @@ func getStatusIcon(status types.ServiceStatus) string {
 		return color.GreenString("●")
 	case types.StatusStopped:
 		return color.YellowString("○")
-	case types.StatusFailed:
+	case types.StatusFailed, types.StatusMissing:
 		return color.RedString("✗")
 	default:
 		return color.New(color.Faint).Sprint("?")
@@ -543,7 +732,7 @@ func getContainerStatusSymbol(status string) string {
 		return color.GreenString("●")
 	case "stopped":
 		return color.YellowString("○")
-	case "failed":
+	case "failed", "missing":
 		return color.RedString("✗")
 	default:
 		return color.New(color.Faint).Sprint("?")
@@ -558,11 +747,20 @@ func formatStatusForTable(status types.ServiceStatus) string {
 		return color.YellowString("Exited")
 	case types.StatusFailed:
 		return color.RedString("Failed")
+	case types.StatusMissing:
+		return color.RedString("Missing")
 	default:
 		return color.New(color.Faint).Sprint("Unknown")
 	}
 }
 
+// stoppedUnexpectedly reports whether an instance that should be running
+// isn't, e.g. because it crashed, as opposed to being stopped with doku stop
+func stoppedUnexpectedly(instance *types.Instance) bool {
+	return instance.DesiredState == types.StatusRunning &&
+		(instance.Status == types.StatusStopped || instance.Status == types.StatusFailed)
+}
+
 func formatStatusTextForTable(status types.ServiceStatus) string {
 	switch status {
 	case types.StatusRunning:
@@ -571,12 +769,21 @@ func formatStatusTextForTable(status types.ServiceStatus) string {
 		return "Exited"
 	case types.StatusFailed:
 		return "Failed"
+	case types.StatusMissing:
+		return "Missing"
 	default:
 		return "Unknown"
 	}
 }
 
 func formatPortsForTable(instance *types.Instance) string {
+	if instance.UsesHostNetwork() {
+		if instance.Network.InternalPort > 0 {
+			return fmt.Sprintf("host:%d", instance.Network.InternalPort)
+		}
+		return "host"
+	}
+
 	ports := []string{}
 
 	// Collect port mappings
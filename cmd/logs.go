@@ -1,13 +1,16 @@
 package cmd
 
 import (
+	"bytes"
 	"fmt"
 	"io"
 	"os"
 	"os/signal"
 	"strings"
+	"sync"
 	"syscall"
 
+	"github.com/docker/docker/pkg/stdcopy"
 	"github.com/dokulabs/doku-cli/internal/config"
 	"github.com/dokulabs/doku-cli/internal/docker"
 	"github.com/dokulabs/doku-cli/internal/service"
@@ -23,10 +26,11 @@ var (
 	logsContainer  string
 	logsAll        bool
 	logsSince      string
+	logsTruncate   bool
 )
 
 var logsCmd = &cobra.Command{
-	Use:   "logs <service>",
+	Use:   "logs <service | @group>",
 	Short: "View logs from a service",
 	Long: `View logs from a service instance.
 
@@ -38,7 +42,13 @@ Examples:
   doku logs postgres-main --tail 50        # Show last 50 lines
   doku logs postgres-main --since 1h       # Logs from last hour
   doku logs postgres-main --since 30m      # Logs from last 30 minutes
-  doku logs postgres-main -f --tail 20     # Follow, starting with last 20 lines`,
+  doku logs postgres-main -f --tail 20     # Follow, starting with last 20 lines
+  doku logs @backend -f                    # Follow all services of a group
+  doku logs postgres-main --truncate       # Clear the logs
+
+Docker rotates the logs of Doku's containers: up to 3 files of 10MB each
+unless 'doku config set preferences.logs.maxsize/maxfile' or the install
+flags --log-max-size and --log-max-file say otherwise.`,
 	Args: cobra.ExactArgs(1),
 	RunE: runLogs,
 }
@@ -52,6 +62,7 @@ func init() {
 	logsCmd.Flags().StringVarP(&logsContainer, "container", "c", "", "Specific container name (for multi-container services)")
 	logsCmd.Flags().BoolVarP(&logsAll, "all", "a", false, "Show logs from all containers (multi-container only)")
 	logsCmd.Flags().StringVar(&logsSince, "since", "", "Show logs since timestamp (e.g. 1h, 30m, 2h30m)")
+	logsCmd.Flags().BoolVar(&logsTruncate, "truncate", false, "Clear the logs instead of showing them")
 }
 
 func runLogs(cmd *cobra.Command, args []string) error {
@@ -76,21 +87,32 @@ func runLogs(cmd *cobra.Command, args []string) error {
 	}
 	defer dockerClient.Close()
 
+	if group, ok := config.ParseGroupRef(instanceName); ok {
+		if logsTruncate {
+			return fmt.Errorf("--truncate takes a service, not a group")
+		}
+		return runGroupLogs(cfgMgr, dockerClient, group, logsFollow)
+	}
+
+	if logsTruncate {
+		return truncateLogs(cfgMgr, dockerClient, instanceName)
+	}
+
 	// Special handling for Traefik
 	var containerName string
-	var isTraefik bool
+	var isProxy bool
 
-	if instanceName == "traefik" || instanceName == "doku-traefik" {
-		containerName = "doku-traefik"
-		isTraefik = true
+	if proxyContainer, ok := proxyContainerName(instanceName); ok {
+		containerName = proxyContainer
+		isProxy = true
 
-		// Check if Traefik container exists
+		// Check if the proxy container exists
 		exists, err := dockerClient.ContainerExists(containerName)
 		if err != nil {
-			return fmt.Errorf("failed to check Traefik container: %w", err)
+			return fmt.Errorf("failed to check %s container: %w", containerName, err)
 		}
 		if !exists {
-			return fmt.Errorf("Traefik container not found. Run 'doku init' first")
+			return fmt.Errorf("%s container not found. Run 'doku init' first", containerName)
 		}
 	} else {
 		// Regular service - get from service manager
@@ -130,8 +152,8 @@ func runLogs(cmd *cobra.Command, args []string) error {
 	defer logsReader.Close()
 
 	// Show info about what we're viewing
-	if isTraefik && logsFollow {
-		color.New(color.Faint).Println("Viewing Traefik logs (Press Ctrl+C to stop)...")
+	if isProxy && logsFollow {
+		color.New(color.Faint).Printf("Viewing %s logs (Press Ctrl+C to stop)...\n", containerName)
 		fmt.Println()
 	}
 
@@ -262,6 +284,33 @@ func handleMultiContainerLogs(dockerClient *docker.Client, instance *types.Insta
 	return nil
 }
 
+// truncateLogs clears the logs of a service's containers, or of Traefik
+func truncateLogs(cfgMgr *config.Manager, dockerClient *docker.Client, instanceName string) error {
+	if proxyContainer, ok := proxyContainerName(instanceName); ok {
+		if err := dockerClient.TruncateLogs(proxyContainer); err != nil {
+			return err
+		}
+		color.Green("✓ Cleared the logs of %s", proxyContainer)
+		return nil
+	}
+
+	if !cfgMgr.HasInstance(instanceName) {
+		return fmt.Errorf("service '%s' not found. Use 'doku list' to see installed services", instanceName)
+	}
+
+	serviceMgr := service.NewManager(dockerClient, cfgMgr)
+	count, err := serviceMgr.TruncateLogs(instanceName, logsContainer)
+	if err != nil {
+		return err
+	}
+	if count == 1 {
+		color.Green("✓ Cleared the logs of %s", instanceName)
+	} else {
+		color.Green("✓ Cleared the logs of %s (%d containers)", instanceName, count)
+	}
+	return nil
+}
+
 // getContainerNames returns a comma-separated list of container names
 func getContainerNames(containers []types.ContainerInfo) string {
 	names := make([]string, len(containers))
@@ -270,3 +319,130 @@ func getContainerNames(containers []types.ContainerInfo) string {
 	}
 	return strings.Join(names, ", ")
 }
+
+// groupLogSource is one container whose logs are shown for a group
+type groupLogSource struct {
+	label       string
+	containerID string
+}
+
+// runGroupLogs shows the logs of all members of a group, with each line
+// prefixed by the service (and container) it came from
+func runGroupLogs(cfgMgr *config.Manager, dockerClient *docker.Client, group string, follow bool) error {
+	members, err := cfgMgr.GetGroup(group)
+	if err != nil {
+		return err
+	}
+
+	serviceMgr := service.NewManager(dockerClient, cfgMgr)
+
+	var sources []groupLogSource
+	for _, name := range members {
+		instance, err := serviceMgr.Get(name)
+		if err != nil {
+			color.Yellow("⚠️  %s is in %s%s but not installed", name, config.GroupPrefix, group)
+			continue
+		}
+		if instance.IsMultiContainer {
+			for _, c := range instance.Containers {
+				sources = append(sources, groupLogSource{label: name + "/" + c.Name, containerID: c.ContainerID})
+			}
+			continue
+		}
+		sources = append(sources, groupLogSource{label: name, containerID: instance.ContainerName})
+	}
+
+	if len(sources) == 0 {
+		color.Yellow("No services to show logs for in %s%s", config.GroupPrefix, group)
+		return nil
+	}
+
+	width := 0
+	for _, source := range sources {
+		if len(source.label) > width {
+			width = len(source.label)
+		}
+	}
+
+	if follow {
+		color.New(color.Faint).Printf("Viewing logs of %s%s (Press Ctrl+C to stop)...\n", config.GroupPrefix, group)
+		fmt.Println()
+
+		sigChan := make(chan os.Signal, 1)
+		signal.Notify(sigChan, os.Interrupt, syscall.SIGTERM)
+
+		go func() {
+			<-sigChan
+			fmt.Println()
+			color.New(color.Faint).Println("Log streaming stopped")
+			os.Exit(0)
+		}()
+	}
+
+	colors := []color.Attribute{color.FgCyan, color.FgGreen, color.FgYellow, color.FgMagenta, color.FgBlue, color.FgRed}
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+
+	for idx, source := range sources {
+		prefix := color.New(colors[idx%len(colors)]).Sprintf("%-*s | ", width, source.label)
+
+		wg.Add(1)
+		go func(source groupLogSource, prefix string) {
+			defer wg.Done()
+
+			logsReader, err := dockerClient.ContainerLogs(source.containerID, follow)
+			if err != nil {
+				mu.Lock()
+				color.Yellow("⚠️  Failed to get logs from %s: %v", source.label, err)
+				mu.Unlock()
+				return
+			}
+			defer logsReader.Close()
+
+			out := &prefixWriter{mu: &mu, prefix: prefix}
+			if _, err := stdcopy.StdCopy(out, out, logsReader); err != nil && err != io.EOF {
+				mu.Lock()
+				color.Yellow("⚠️  Error reading logs from %s: %v", source.label, err)
+				mu.Unlock()
+			}
+			out.Flush()
+		}(source, prefix)
+	}
+
+	wg.Wait()
+	return nil
+}
+
+// prefixWriter writes complete lines to stdout with a prefix, holding a
+// shared lock so lines of concurrent writers don't interleave
+type prefixWriter struct {
+	mu     *sync.Mutex
+	prefix string
+	buf    []byte
+}
+
+func (w *prefixWriter) Write(p []byte) (int, error) {
+	w.buf = append(w.buf, p...)
+	for {
+		idx := bytes.IndexByte(w.buf, '\n')
+		if idx < 0 {
+			break
+		}
+		w.mu.Lock()
+		fmt.Fprintf(os.Stdout, "%s%s", w.prefix, w.buf[:idx+1])
+		w.mu.Unlock()
+		w.buf = w.buf[idx+1:]
+	}
+	return len(p), nil
+}
+
+// Flush writes a trailing line without newline
+func (w *prefixWriter) Flush() {
+	if len(w.buf) == 0 {
+		return
+	}
+	w.mu.Lock()
+	fmt.Fprintf(os.Stdout, "%s%s\n", w.prefix, w.buf)
+	w.mu.Unlock()
+	w.buf = nil
+}
@@ -290,7 +290,7 @@ func runNetworkConnections(cmd *cobra.Command, args []string) error {
 	for name, instance := range cfg.Instances {
 		hostname := instance.ContainerName
 		if hostname == "" {
-			hostname = fmt.Sprintf("doku-%s", name)
+			hostname = docker.GenerateContainerName(name)
 		}
 
 		port := instance.Network.InternalPort
@@ -325,7 +325,7 @@ func runNetworkConnections(cmd *cobra.Command, args []string) error {
 	for name, project := range cfg.Projects {
 		hostname := project.ContainerName
 		if hostname == "" {
-			hostname = fmt.Sprintf("doku-%s", name)
+			hostname = docker.GenerateContainerName(name)
 		}
 
 		portStr := "-"
@@ -361,7 +361,7 @@ func runNetworkConnections(cmd *cobra.Command, args []string) error {
 	for name, instance := range cfg.Instances {
 		hostname := instance.ContainerName
 		if hostname == "" {
-			hostname = fmt.Sprintf("doku-%s", name)
+			hostname = docker.GenerateContainerName(name)
 		}
 
 		port := instance.Network.InternalPort
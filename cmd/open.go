@@ -0,0 +1,96 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/dokulabs/doku-cli/internal/traefik"
+	"github.com/dokulabs/doku-cli/pkg/types"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+var openPrint bool
+
+var openCmd = &cobra.Command{
+	Use:   "open <service>",
+	Short: "Open the URL of a service in the browser",
+	Long: `Open the URL of an installed service in the default browser, once its
+container is running. 'doku open traefik' opens the Traefik dashboard.
+Monitoring tools installed by 'doku init', such as Dozzle, are services
+too.
+
+--print prints the URL instead of opening it.
+
+Examples:
+  doku open grafana            # https://grafana.doku.local
+  doku open traefik            # The Traefik dashboard
+  doku open dozzle             # Container logs in Dozzle
+  doku open rabbitmq --print   # Print the URL`,
+	Args: cobra.ExactArgs(1),
+	RunE: runOpen,
+}
+
+func init() {
+	rootCmd.AddCommand(openCmd)
+
+	openCmd.Flags().BoolVar(&openPrint, "print", false, "Print the URL instead of opening it")
+}
+
+func runOpen(cmd *cobra.Command, args []string) error {
+	name := args[0]
+
+	cfgMgr, err := initConfigManager()
+	if err != nil {
+		if err == types.ErrNotInitialized {
+			return nil
+		}
+		return err
+	}
+
+	var url, containerName, startHint string
+	if instance, err := cfgMgr.GetInstance(name); err == nil {
+		if instance.URL == "" {
+			return fmt.Errorf("%s has no URL: it isn't exposed through Traefik. See how to connect with: doku info %s", name, name)
+		}
+		url, containerName = instance.URL, instance.ContainerName
+		if primary := instance.GetPrimaryContainer(); primary != nil {
+			containerName = primary.FullName
+		}
+		startHint = "doku start " + name
+	} else if name == "traefik" || name == traefik.ContainerName() {
+		cfg, err := cfgMgr.Get()
+		if err != nil {
+			return fmt.Errorf("failed to get configuration: %w", err)
+		}
+		name, url, containerName = "Traefik", traefikDashboardURL(cfg), traefik.ContainerName()
+		startHint = "doku start traefik"
+	} else {
+		return fmt.Errorf("service '%s' not found. Use 'doku list' to see installed services", name)
+	}
+
+	dockerClient, err := initDockerClient()
+	if err != nil {
+		return err
+	}
+	defer dockerClient.Close()
+
+	containerInfo, err := dockerClient.ContainerInspect(containerName)
+	if err != nil {
+		return fmt.Errorf("failed to inspect %s: %w", name, err)
+	}
+	if containerInfo.State == nil || !containerInfo.State.Running {
+		return fmt.Errorf("%s is not running. Start it with: %s", name, startHint)
+	}
+
+	if openPrint {
+		fmt.Println(url)
+		return nil
+	}
+
+	color.Cyan("Opening %s: %s", name, url)
+	if err := openBrowser(url); err != nil {
+		color.Yellow("⚠️  Failed to open the browser: %v", err)
+		fmt.Printf("   Open %s manually\n", url)
+	}
+	return nil
+}
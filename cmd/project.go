@@ -18,6 +18,9 @@ Examples:
   # Add a project
   doku project add ./my-app --name myapp --port 8080
 
+  # Import a docker-compose.yml as a project
+  doku project import ./docker-compose.yml
+
   # Build a project
   doku project build myapp
 
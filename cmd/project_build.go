@@ -12,9 +12,14 @@ import (
 )
 
 var (
-	projectBuildNoCache bool
-	projectBuildPull    bool
-	projectBuildTag     string
+	projectBuildNoCache  bool
+	projectBuildPull     bool
+	projectBuildTag      string
+	projectBuildProgress string
+	projectBuildArgs     []string
+	projectBuildTarget   string
+	projectBuildSecrets  []string
+	projectBuildSSH      []string
 )
 
 // projectBuildCmd represents the project build command
@@ -37,7 +42,18 @@ Examples:
   doku project build myapp --pull
 
   # Build with custom tag
-  doku project build myapp --tag myapp:v1.0.0`,
+  doku project build myapp --tag myapp:v1.0.0
+
+  # Build the prod stage of a multi-stage Dockerfile
+  doku project build myapp --target prod --build-arg NODE_ENV=production
+
+  # Install private dependencies with a secret and SSH forwarding
+  doku project build api --secret id=npmrc,src=~/.npmrc --ssh default
+
+  # Show the full BuildKit output
+  doku project build myapp --progress plain
+
+The output of the last build is kept in ~/.doku/projects/{name}/build.log.`,
 	Args: cobra.ExactArgs(1),
 	RunE: projectBuildRun,
 }
@@ -48,11 +64,24 @@ func init() {
 	projectBuildCmd.Flags().BoolVar(&projectBuildNoCache, "no-cache", false, "Build without using cache")
 	projectBuildCmd.Flags().BoolVar(&projectBuildPull, "pull", false, "Pull base image before building")
 	projectBuildCmd.Flags().StringVarP(&projectBuildTag, "tag", "t", "", "Custom tag for the image")
+	projectBuildCmd.Flags().StringArrayVar(&projectBuildArgs, "build-arg", nil, "Build argument (KEY=VALUE). Can be specified multiple times")
+	projectBuildCmd.Flags().StringVar(&projectBuildTarget, "target", "", "Dockerfile stage to build")
+	projectBuildCmd.Flags().StringArrayVar(&projectBuildSecrets, "secret", nil, "BuildKit secret (id=ID,src=FILE or id=ID,env=VAR). Can be specified multiple times")
+	projectBuildCmd.Flags().StringArrayVar(&projectBuildSSH, "ssh", nil, "SSH agent or keys to forward (default or ID=PATH). Can be specified multiple times")
+	projectBuildCmd.Flags().StringVar(&projectBuildProgress, "progress", project.ProgressAuto, "Progress output: auto, plain or tty")
 }
 
 func projectBuildRun(cmd *cobra.Command, args []string) error {
 	projectName := args[0]
 
+	if err := project.ValidateProgress(projectBuildProgress); err != nil {
+		return err
+	}
+	flagBuildArgs, err := parseBuildArgs(projectBuildArgs)
+	if err != nil {
+		return err
+	}
+
 	// Initialize Docker client
 	dockerClient, err := docker.NewClient()
 	if err != nil {
@@ -112,6 +141,15 @@ func projectBuildRun(cmd *cobra.Command, args []string) error {
 		}
 	}
 
+	// Then the project's saved build args, and those given on the command
+	// line, which override the others
+	for key, value := range proj.BuildArgs {
+		buildArgs[key] = value
+	}
+	for key, value := range flagBuildArgs {
+		buildArgs[key] = value
+	}
+
 	// Build project
 	opts := project.BuildOptions{
 		Name:      projectName,
@@ -119,6 +157,10 @@ func projectBuildRun(cmd *cobra.Command, args []string) error {
 		Pull:      projectBuildPull,
 		Tag:       projectBuildTag,
 		BuildArgs: buildArgs,
+		Target:    projectBuildTarget,
+		Secrets:   projectBuildSecrets,
+		SSH:       projectBuildSSH,
+		Progress:  projectBuildProgress,
 	}
 
 	if err := projectMgr.Build(opts); err != nil {
@@ -0,0 +1,138 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/dokulabs/doku-cli/internal/config"
+	"github.com/dokulabs/doku-cli/internal/docker"
+	"github.com/dokulabs/doku-cli/internal/project"
+	"github.com/dokulabs/doku-cli/pkg/types"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+var (
+	projectDevMounts  []string
+	projectDevCommand string
+	projectDevOff     bool
+	projectDevBuild   bool
+)
+
+// projectDevCmd represents the project dev command
+var projectDevCmd = &cobra.Command{
+	Use:   "dev [project-name]",
+	Short: "Run a project with its sources mounted for hot reload",
+	Long: `Run a project in dev mode: source directories are bind-mounted into the
+container and a dev command replaces the image's, so framework hot reload
+picks up changes without rebuilding the image.
+
+Relative mount sources are relative to the project directory. Dev mode is
+saved, so later runs and recreates keep it until you turn it off. Running
+'doku project dev' again without flags reuses the saved settings; --mount
+and --cmd replace them.
+
+Examples:
+  # Mount the sources and run the dev server
+  doku project dev myapp --mount ./src:/app/src --cmd "npm run dev"
+
+  # Several mounts, one read-only
+  doku project dev myapp --mount ./src:/app/src --mount ./config:/app/config:ro
+
+  # Run again with the saved settings
+  doku project dev myapp
+
+  # Back to the built image
+  doku project dev myapp --off`,
+	Args: cobra.ExactArgs(1),
+	RunE: projectDevRun,
+}
+
+func init() {
+	projectCmd.AddCommand(projectDevCmd)
+
+	projectDevCmd.Flags().StringArrayVarP(&projectDevMounts, "mount", "m", nil, "Bind-mount a source directory (SOURCE:TARGET[:ro], can be repeated)")
+	projectDevCmd.Flags().StringVar(&projectDevCommand, "cmd", "", "Command to run instead of the image's, e.g. \"npm run dev\"")
+	projectDevCmd.Flags().BoolVar(&projectDevOff, "off", false, "Turn dev mode off and run the built image")
+	projectDevCmd.Flags().BoolVar(&projectDevBuild, "build", false, "Build the image before running")
+}
+
+func projectDevRun(cmd *cobra.Command, args []string) error {
+	projectName := args[0]
+
+	if projectDevOff && (len(projectDevMounts) > 0 || projectDevCommand != "") {
+		return fmt.Errorf("--off cannot be combined with --mount or --cmd")
+	}
+
+	// Initialize Docker client
+	dockerClient, err := docker.NewClient()
+	if err != nil {
+		return fmt.Errorf("failed to create Docker client: %w", err)
+	}
+	defer dockerClient.Close()
+
+	// Initialize config manager
+	cfgMgr, err := config.New()
+	if err != nil {
+		return fmt.Errorf("failed to initialize config: %w", err)
+	}
+
+	// Initialize project manager
+	projectMgr, err := project.NewManager(dockerClient, cfgMgr)
+	if err != nil {
+		return fmt.Errorf("failed to initialize project manager: %w", err)
+	}
+
+	proj, err := projectMgr.Get(projectName)
+	if err != nil {
+		return fmt.Errorf("project '%s' not found. Add it first with: doku project add", projectName)
+	}
+
+	var dev *types.DevConfig
+	if !projectDevOff {
+		dev = &types.DevConfig{}
+		if proj.Dev != nil {
+			*dev = *proj.Dev
+		}
+		if len(projectDevMounts) > 0 {
+			dev.Mounts = nil
+			for _, spec := range projectDevMounts {
+				m, err := project.ParseDevMount(spec, proj.Path)
+				if err != nil {
+					return err
+				}
+				dev.Mounts = append(dev.Mounts, m)
+			}
+		}
+		if cmd.Flags().Changed("cmd") {
+			dev.Command = projectDevCommand
+		}
+		if len(dev.Mounts) == 0 && dev.Command == "" {
+			return fmt.Errorf("specify what to mount, e.g. --mount ./src:/app/src")
+		}
+	} else if proj.Dev == nil {
+		color.Yellow("⚠️  Dev mode is not on for %s", projectName)
+		return nil
+	}
+
+	if err := projectMgr.SetDev(projectName, dev); err != nil {
+		return err
+	}
+
+	fmt.Println()
+	if dev != nil {
+		color.Cyan("→ Running project in dev mode: %s", projectName)
+	} else {
+		color.Cyan("→ Turning dev mode off: %s", projectName)
+	}
+
+	if err := projectMgr.Run(project.RunOptions{
+		Name:   projectName,
+		Build:  projectDevBuild,
+		Detach: true,
+	}); err != nil {
+		color.Red("\n✗ Failed to run project: %v\n", err)
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,153 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/dokulabs/doku-cli/internal/config"
+	"github.com/dokulabs/doku-cli/internal/dns"
+	"github.com/dokulabs/doku-cli/internal/docker"
+	"github.com/dokulabs/doku-cli/internal/project"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+var (
+	projectImportName     string
+	projectImportExpose   string
+	projectImportPort     int
+	projectImportInternal bool
+	projectImportBuild    bool
+	projectImportReplace  bool
+	projectImportNoStart  bool
+)
+
+// projectImportCmd represents the project import command
+var projectImportCmd = &cobra.Command{
+	Use:   "import <docker-compose.yml>",
+	Short: "Import a docker-compose.yml as a project",
+	Long: `Import an existing docker-compose.yml as a multi-container project.
+
+Each compose service becomes a container on the Doku network, named
+doku-<project>-<service>. Services can still reach each other by their
+compose service names. Images with a build section are built locally.
+
+One service is routed through Traefik at https://<project>.<domain>.
+By default this is the first service (alphabetically) that publishes
+ports; use --expose to pick another one.
+
+Variables in the compose file (${VAR}, ${VAR:-default}) are read from the
+environment and from a .env file next to the compose file.
+
+Not supported: compose networks (all services join the Doku network),
+healthcheck conditions in depends_on, profiles and deploy sections.
+
+Examples:
+  # Import and start a compose stack
+  doku project import ./docker-compose.yml
+
+  # Route a specific service through Traefik
+  doku project import ./docker-compose.yml --name shop --expose web --port 3000
+
+  # Register without starting
+  doku project import ./docker-compose.yml --no-start`,
+	Args: cobra.ExactArgs(1),
+	RunE: projectImportRun,
+}
+
+func init() {
+	projectCmd.AddCommand(projectImportCmd)
+
+	projectImportCmd.Flags().StringVarP(&projectImportName, "name", "n", "", "Project name (defaults to compose name or directory name)")
+	projectImportCmd.Flags().StringVar(&projectImportExpose, "expose", "", "Compose service to route through Traefik")
+	projectImportCmd.Flags().IntVarP(&projectImportPort, "port", "p", 0, "Container port of the exposed service")
+	projectImportCmd.Flags().BoolVar(&projectImportInternal, "internal", false, "Internal only (no Traefik/HTTPS)")
+	projectImportCmd.Flags().BoolVar(&projectImportBuild, "build", false, "Rebuild images even if they exist")
+	projectImportCmd.Flags().BoolVar(&projectImportReplace, "replace", false, "Replace an existing project with the same name")
+	projectImportCmd.Flags().BoolVar(&projectImportNoStart, "no-start", false, "Register the project without starting it")
+}
+
+func projectImportRun(cmd *cobra.Command, args []string) error {
+	composePath := args[0]
+
+	// Initialize Docker client
+	dockerClient, err := docker.NewClient()
+	if err != nil {
+		return fmt.Errorf("failed to create Docker client: %w", err)
+	}
+	defer dockerClient.Close()
+
+	// Initialize config manager
+	cfgMgr, err := config.New()
+	if err != nil {
+		return fmt.Errorf("failed to initialize config: %w", err)
+	}
+
+	// Initialize project manager
+	projectMgr, err := project.NewManager(dockerClient, cfgMgr)
+	if err != nil {
+		return fmt.Errorf("failed to initialize project manager: %w", err)
+	}
+
+	proj, err := projectMgr.Import(project.ImportOptions{
+		ComposePath: composePath,
+		Name:        projectImportName,
+		Expose:      projectImportExpose,
+		Port:        projectImportPort,
+		Internal:    projectImportInternal,
+		Replace:     projectImportReplace,
+	})
+	if err != nil {
+		return err
+	}
+
+	green := color.New(color.FgGreen)
+	cyan := color.New(color.FgCyan)
+	yellow := color.New(color.FgYellow)
+
+	fmt.Println()
+	green.Println("✓ Project imported successfully")
+	fmt.Println()
+
+	fmt.Println("Project Details:")
+	cyan.Printf("  Name: %s\n", proj.Name)
+	cyan.Printf("  Compose file: %s\n", composePath)
+	for _, c := range proj.Containers {
+		line := fmt.Sprintf("  • %s (%s)", c.Name, c.Image)
+		if c.Primary {
+			line += " [exposed]"
+		}
+		fmt.Println(line)
+	}
+	if proj.URL != "" {
+		cyan.Printf("  URL: %s\n", proj.URL)
+	}
+	fmt.Println()
+
+	if projectImportNoStart {
+		yellow.Println("Next steps:")
+		fmt.Printf("  doku project run %s\n", proj.Name)
+		fmt.Println()
+		return nil
+	}
+
+	if err := projectMgr.Run(project.RunOptions{
+		Name:   proj.Name,
+		Build:  projectImportBuild,
+		Detach: true,
+	}); err != nil {
+		return fmt.Errorf("failed to start project: %w", err)
+	}
+
+	// Add DNS entry for the exposed service
+	if proj.URL != "" {
+		subdomain := config.URLHost(proj.URL)
+
+		dnsMgr := dns.NewManagerForContext(cfgMgr.GetContext()).SetIP(cfgMgr.GetHostsIP())
+		if err := dnsMgr.AddSingleEntry(dnsMgr.IP(), subdomain); err != nil {
+			color.Yellow("⚠️  Warning: Failed to add DNS entry: %v", err)
+			color.Yellow("   You may need to manually add: %s %s to /etc/hosts", dnsMgr.IP(), subdomain)
+		}
+	}
+
+	return nil
+}
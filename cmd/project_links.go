@@ -0,0 +1,101 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/dokulabs/doku-cli/internal/project"
+	"github.com/dokulabs/doku-cli/pkg/types"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+var projectLinksRemove []string
+
+// projectLinksCmd represents the project links command
+var projectLinksCmd = &cobra.Command{
+	Use:   "links <project> [NAME=URL...]",
+	Short: "List or register a project's links",
+	Long: `Register named links of a project, such as its API docs, admin page or
+issue tracker. 'doku info' shows them and 'doku docs' opens them; the link
+named docs is opened by default.
+
+Without NAME=URL arguments the links are listed. Links can also be declared
+under links: in a project's doku.yaml entry.
+
+Examples:
+  # List the links
+  doku project links api
+
+  # Register links
+  doku project links api docs=https://api.doku.local/docs admin=https://api.doku.local/admin
+
+  # Remove one
+  doku project links api --remove admin`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: projectLinksRun,
+}
+
+func init() {
+	projectCmd.AddCommand(projectLinksCmd)
+
+	projectLinksCmd.Flags().StringSliceVar(&projectLinksRemove, "remove", nil, "Names of links to remove")
+}
+
+func projectLinksRun(cmd *cobra.Command, args []string) error {
+	projectName := args[0]
+
+	set := make(map[string]string)
+	for _, arg := range args[1:] {
+		parts := strings.SplitN(arg, "=", 2)
+		if len(parts) != 2 {
+			return fmt.Errorf("invalid link format: %s (use NAME=URL)", arg)
+		}
+		set[parts[0]] = parts[1]
+	}
+
+	cfgMgr, err := initConfigManager()
+	if err != nil {
+		if err == types.ErrNotInitialized {
+			return nil
+		}
+		return err
+	}
+
+	proj, err := cfgMgr.GetProject(projectName)
+	if err != nil {
+		return fmt.Errorf("project '%s' not found", projectName)
+	}
+
+	if len(set) > 0 || len(projectLinksRemove) > 0 {
+		dockerClient, err := initDockerClient()
+		if err != nil {
+			return err
+		}
+		defer dockerClient.Close()
+
+		projectMgr, err := project.NewManager(dockerClient, cfgMgr)
+		if err != nil {
+			return fmt.Errorf("failed to initialize project manager: %w", err)
+		}
+		links, err := projectMgr.SetLinks(projectName, set, projectLinksRemove)
+		if err != nil {
+			return err
+		}
+		proj.Links = links
+		color.Green("✓ Links of %s updated", projectName)
+	}
+
+	links := projectLinks(proj)
+	if len(links) == 0 {
+		fmt.Printf("%s has no links. Add one with: doku project links %s docs=<url>\n", projectName, projectName)
+		return nil
+	}
+
+	fmt.Println()
+	for _, link := range links {
+		fmt.Printf("  %-14s %s\n", link.Name, link.URL)
+	}
+	fmt.Println()
+	return nil
+}
@@ -0,0 +1,73 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/dokulabs/doku-cli/internal/caddy"
+	"github.com/dokulabs/doku-cli/internal/config"
+	"github.com/dokulabs/doku-cli/internal/docker"
+	"github.com/dokulabs/doku-cli/internal/proxy"
+	"github.com/dokulabs/doku-cli/internal/readonly"
+	"github.com/dokulabs/doku-cli/internal/traefik"
+	"github.com/fatih/color"
+)
+
+// syncProxyRoutes brings Caddy's routes up to date with the containers
+// after a command, since any of them may have created, relabeled or
+// removed some. Traefik follows the containers itself.
+func syncProxyRoutes() {
+	if readonly.Enabled() {
+		return
+	}
+	cfgMgr, err := config.New()
+	if err != nil || !cfgMgr.IsInitialized() {
+		return
+	}
+	cfg, err := cfgMgr.Get()
+	if err != nil || proxy.Backend(cfg.Preferences) != proxy.Caddy {
+		return
+	}
+	dockerClient, err := docker.NewClient()
+	if err != nil {
+		return
+	}
+	defer dockerClient.Close()
+
+	if err := proxy.New(dockerClient, cfgMgr, cfg).SyncRoutes(); err != nil {
+		color.New(color.Faint).Fprintf(os.Stderr, "Failed to update Caddy's routes: %v\n", err)
+	}
+}
+
+// proxyContainerName returns the container of the proxy a name given to
+// logs, exec and the like stands for: "traefik", "caddy" or their
+// container's name
+func proxyContainerName(name string) (string, bool) {
+	switch name {
+	case "traefik", traefik.ContainerName():
+		return traefik.ContainerName(), true
+	case "caddy", caddy.ContainerName():
+		return caddy.ContainerName(), true
+	}
+	return "", false
+}
+
+// removeOtherProxy removes the container of the backend not chosen, left
+// by an earlier setup: it would hold the ports
+func removeOtherProxy(dockerClient *docker.Client, networkMgr *docker.NetworkManager, chosen proxy.Proxy) error {
+	for _, name := range []string{traefik.ContainerName(), caddy.ContainerName()} {
+		if name == chosen.ContainerName() {
+			continue
+		}
+		exists, err := dockerClient.ContainerExists(name)
+		if err != nil || !exists {
+			continue
+		}
+		fmt.Printf("Removing %s, replaced by %s...\n", name, chosen.Name())
+		networkMgr.DisconnectContainer(docker.NetworkName(), name, true)
+		if err := dockerClient.ContainerRemove(name, true); err != nil {
+			return fmt.Errorf("failed to remove %s: %w", name, err)
+		}
+	}
+	return nil
+}
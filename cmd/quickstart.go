@@ -0,0 +1,364 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/AlecAivazis/survey/v2"
+	"github.com/dokulabs/doku-cli/internal/catalog"
+	"github.com/dokulabs/doku-cli/internal/config"
+	"github.com/dokulabs/doku-cli/internal/dns"
+	"github.com/dokulabs/doku-cli/internal/docker"
+	"github.com/dokulabs/doku-cli/internal/project"
+	"github.com/dokulabs/doku-cli/internal/service"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+var (
+	quickstartDir     string
+	quickstartYes     bool
+	quickstartSkipApp bool
+)
+
+// Names used by the example stack
+const (
+	quickstartAppName  = "quickstart-app"
+	quickstartAppPort  = 8000
+	quickstartPostgres = "postgres"
+	quickstartRedis    = "redis"
+)
+
+// quickstartDockerfile builds the sample web app
+const quickstartDockerfile = `FROM python:3.12-alpine
+WORKDIR /app
+COPY app.py .
+EXPOSE 8000
+CMD ["python", "-u", "app.py"]
+`
+
+// quickstartApp is a dependency-free web app that shows its environment and
+// checks that it can reach postgres and redis over the Doku network
+const quickstartApp = `import os
+import socket
+from http.server import BaseHTTPRequestHandler, HTTPServer
+
+SERVICES = [
+    ("postgres", os.environ.get("POSTGRES_HOST", "postgres"), int(os.environ.get("POSTGRES_PORT", "5432"))),
+    ("redis", os.environ.get("REDIS_HOST", "redis"), int(os.environ.get("REDIS_PORT", "6379"))),
+]
+
+
+def reachable(host, port):
+    try:
+        with socket.create_connection((host, port), timeout=2):
+            return True
+    except OSError:
+        return False
+
+
+class Handler(BaseHTTPRequestHandler):
+    def do_GET(self):
+        rows = ""
+        for name, host, port in SERVICES:
+            status = "reachable" if reachable(host, port) else "unreachable"
+            rows += f"<tr><td>{name}</td><td>{host}:{port}</td><td>{status}</td></tr>"
+            print(f"{name} at {host}:{port} is {status}")
+
+        body = f"""<!doctype html>
+<html><head><title>Doku quickstart</title></head>
+<body style="font-family: sans-serif; max-width: 40em; margin: 3em auto">
+<h1>Hello from Doku</h1>
+<p>This app runs in a container on the Doku network and reaches other
+services by their instance name.</p>
+<table border="1" cellpadding="6">
+<tr><th>Service</th><th>Address</th><th>Status</th></tr>
+{rows}
+</table>
+<p>DATABASE_URL = <code>{os.environ.get("DATABASE_URL", "")}</code></p>
+</body></html>"""
+
+        data = body.encode()
+        self.send_response(200)
+        self.send_header("Content-Type", "text/html; charset=utf-8")
+        self.send_header("Content-Length", str(len(data)))
+        self.end_headers()
+        self.wfile.write(data)
+
+
+if __name__ == "__main__":
+    print("Listening on port 8000")
+    HTTPServer(("0.0.0.0", 8000), Handler).serve_forever()
+`
+
+var quickstartCmd = &cobra.Command{
+	Use:   "quickstart",
+	Short: "Install an example stack to get to know Doku",
+	Long: `Install a small example stack: PostgreSQL, Redis and a sample web app
+built from a Dockerfile. The web app connects to both services over the Doku
+network and shows the result in the browser.
+
+When it's done, quickstart walks through the commands to inspect, configure
+and remove the stack.
+
+Examples:
+  doku quickstart                   # Install the example stack
+  doku quickstart --yes             # Don't ask for confirmation
+  doku quickstart --skip-app        # Only install postgres and redis
+  doku quickstart --dir ./example   # Write the sample app to ./example`,
+	RunE: runQuickstart,
+}
+
+func init() {
+	rootCmd.AddCommand(quickstartCmd)
+
+	quickstartCmd.Flags().StringVar(&quickstartDir, "dir", "", "Directory for the sample app (default: ~/.doku/projects/quickstart-app)")
+	quickstartCmd.Flags().BoolVarP(&quickstartYes, "yes", "y", false, "Skip confirmation prompts")
+	quickstartCmd.Flags().BoolVar(&quickstartSkipApp, "skip-app", false, "Only install the example services, not the sample app")
+}
+
+func runQuickstart(cmd *cobra.Command, args []string) error {
+	cfgMgr, err := config.New()
+	if err != nil {
+		return fmt.Errorf("failed to create config manager: %w", err)
+	}
+
+	if !cfgMgr.IsInitialized() {
+		color.Yellow("⚠️  Doku is not initialized. Run 'doku init' first.")
+		return nil
+	}
+
+	catalogMgr := catalog.NewManager(cfgMgr.GetCatalogDir())
+	if !catalogMgr.CatalogExists() {
+		color.Yellow("⚠️  Catalog not found. Please run 'doku catalog update' first.")
+		return nil
+	}
+
+	cfg, err := cfgMgr.Get()
+	if err != nil {
+		return fmt.Errorf("failed to get config: %w", err)
+	}
+
+	protocol := cfg.Preferences.Protocol
+	if protocol == "" {
+		protocol = "https"
+	}
+	domain := cfg.Preferences.Domain
+	if domain == "" {
+		domain = "doku.local"
+	}
+
+	appDir := quickstartDir
+	if appDir == "" {
+		appDir = filepath.Join(cfgMgr.GetProjectsDir(), quickstartAppName)
+	}
+
+	fmt.Println()
+	color.Cyan("🚀 Doku quickstart")
+	fmt.Println()
+	fmt.Println("This installs a small example stack:")
+	fmt.Println("  • postgres        - a PostgreSQL database (internal)")
+	fmt.Println("  • redis           - a Redis cache (internal)")
+	if !quickstartSkipApp {
+		httpPort, httpsPort := cfgMgr.GetTraefikPorts()
+		fmt.Printf("  • %s  - a sample web app at %s\n", quickstartAppName, config.ServiceURL(protocol, quickstartAppName+"."+domain, httpPort, httpsPort))
+		fmt.Printf("    (source written to %s)\n", appDir)
+	}
+	fmt.Println()
+
+	if !quickstartYes {
+		confirm := false
+		prompt := &survey.Confirm{
+			Message: "Install the example stack?",
+			Default: true,
+		}
+		if err := survey.AskOne(prompt, &confirm); err != nil {
+			return err
+		}
+		if !confirm {
+			color.Yellow("Quickstart cancelled")
+			return nil
+		}
+		fmt.Println()
+	}
+
+	dockerClient, err := docker.NewClient()
+	if err != nil {
+		return fmt.Errorf("failed to create Docker client: %w", err)
+	}
+	defer dockerClient.Close()
+
+	installer, err := service.NewInstaller(dockerClient, cfgMgr, catalogMgr)
+	if err != nil {
+		return fmt.Errorf("failed to create installer: %w", err)
+	}
+
+	// Step 1 and 2: services from the catalog
+	steps := 3
+	if quickstartSkipApp {
+		steps = 2
+	}
+	for idx, name := range []string{quickstartPostgres, quickstartRedis} {
+		color.Cyan("Step %d/%d: Installing %s...", idx+1, steps, name)
+
+		if cfgMgr.HasInstance(name) {
+			fmt.Printf("%s is already installed, reusing it\n", name)
+			fmt.Println()
+			continue
+		}
+
+		if _, err := installer.Install(service.InstallOptions{
+			ServiceName:     name,
+			InstanceName:    name,
+			Internal:        true,
+			AutoInstallDeps: true,
+		}); err != nil {
+			return fmt.Errorf("failed to install %s: %w", name, err)
+		}
+		color.Green("✓ %s installed", name)
+		fmt.Println()
+	}
+
+	appURL := ""
+	if !quickstartSkipApp {
+		color.Cyan("Step 3/3: Building and starting %s...", quickstartAppName)
+
+		appURL, err = installQuickstartApp(dockerClient, cfgMgr, appDir)
+		if err != nil {
+			return err
+		}
+		color.Green("✓ %s started", quickstartAppName)
+		fmt.Println()
+	}
+
+	printQuickstartTour(appURL, appDir)
+
+	return nil
+}
+
+// installQuickstartApp writes the sample app, adds it as a project, builds
+// and runs it. Returns the app URL.
+func installQuickstartApp(dockerClient *docker.Client, cfgMgr *config.Manager, appDir string) (string, error) {
+	if err := writeQuickstartApp(appDir); err != nil {
+		return "", err
+	}
+
+	projectMgr, err := project.NewManager(dockerClient, cfgMgr)
+	if err != nil {
+		return "", fmt.Errorf("failed to create project manager: %w", err)
+	}
+
+	// Replace an earlier quickstart app instead of failing on the name
+	existing, _ := projectMgr.Get(quickstartAppName)
+
+	proj, err := projectMgr.Add(project.AddOptions{
+		ProjectPath: appDir,
+		Name:        quickstartAppName,
+		Port:        quickstartAppPort,
+		Environment: quickstartAppEnv(cfgMgr),
+		Replace:     existing != nil,
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to add %s: %w", quickstartAppName, err)
+	}
+
+	if err := projectMgr.Run(project.RunOptions{
+		Name:   quickstartAppName,
+		Build:  true,
+		Detach: true,
+	}); err != nil {
+		return "", fmt.Errorf("failed to run %s: %w", quickstartAppName, err)
+	}
+
+	if proj.URL != "" {
+		subdomain := config.URLHost(proj.URL)
+
+		dnsMgr := dns.NewManagerForContext(cfgMgr.GetContext()).SetIP(cfgMgr.GetHostsIP())
+		if err := dnsMgr.AddSingleEntry(dnsMgr.IP(), subdomain); err != nil {
+			color.Yellow("⚠️  Warning: Failed to add DNS entry: %v", err)
+			color.Yellow("   You may need to manually add: %s %s to /etc/hosts", dnsMgr.IP(), subdomain)
+		}
+	}
+
+	return proj.URL, nil
+}
+
+// writeQuickstartApp writes the sample app source to dir
+func writeQuickstartApp(dir string) error {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("failed to create %s: %w", dir, err)
+	}
+
+	files := map[string]string{
+		"Dockerfile": quickstartDockerfile,
+		"app.py":     quickstartApp,
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			return fmt.Errorf("failed to write %s: %w", name, err)
+		}
+	}
+
+	return nil
+}
+
+// quickstartAppEnv points the sample app at the example services, using the
+// postgres credentials the instance was installed with
+func quickstartAppEnv(cfgMgr *config.Manager) map[string]string {
+	user, password, database := "postgres", "postgres", "postgres"
+	if instance, err := cfgMgr.GetInstance(quickstartPostgres); err == nil {
+		if v := instance.Environment["POSTGRES_USER"]; v != "" {
+			user = v
+		}
+		if v := instance.Environment["POSTGRES_PASSWORD"]; v != "" {
+			password = v
+		}
+		if v := instance.Environment["POSTGRES_DB"]; v != "" {
+			database = v
+		}
+	}
+
+	return map[string]string{
+		"POSTGRES_HOST": quickstartPostgres,
+		"POSTGRES_PORT": "5432",
+		"REDIS_HOST":    quickstartRedis,
+		"REDIS_PORT":    "6379",
+		"DATABASE_URL":  fmt.Sprintf("postgres://%s:%s@%s:5432/%s", user, password, quickstartPostgres, database),
+		"REDIS_URL":     fmt.Sprintf("redis://%s:6379", quickstartRedis),
+	}
+}
+
+// printQuickstartTour prints the URLs and a short tour of everyday commands
+func printQuickstartTour(appURL, appDir string) {
+	color.Green("✓ Example stack is ready")
+	fmt.Println()
+
+	if appURL != "" {
+		color.Cyan("Open the sample app:")
+		fmt.Printf("  %s\n", appURL)
+		fmt.Println()
+	}
+
+	color.Cyan("Take a tour:")
+	fmt.Println("  doku list                       # See what's running")
+	fmt.Println("  doku status                     # CPU, memory and network usage")
+	fmt.Println("  doku info postgres              # Connection details")
+	fmt.Println("  doku env postgres               # Environment variables")
+	if appURL != "" {
+		fmt.Printf("  doku logs %s -f       # Follow the app logs (reload the page)\n", quickstartAppName)
+		fmt.Printf("  doku env set %s GREETING=hi  # Change configuration\n", quickstartAppName)
+		fmt.Println()
+		fmt.Printf("Edit %s and run 'doku project run %s --build' to see your changes.\n",
+			filepath.Join(appDir, "app.py"), quickstartAppName)
+	}
+	fmt.Println()
+
+	color.Cyan("Clean up when you're done:")
+	if appURL != "" {
+		fmt.Printf("  doku remove %s\n", quickstartAppName)
+	}
+	fmt.Println("  doku remove redis")
+	fmt.Println("  doku remove postgres")
+	fmt.Println()
+}
@@ -0,0 +1,239 @@
+package cmd
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/dokulabs/doku-cli/internal/service"
+	"github.com/dokulabs/doku-cli/pkg/types"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+var quietHoursWatch bool
+
+// quietHoursWatchInterval is how often 'doku quiethours run --watch' checks
+// whether quiet hours began or ended
+const quietHoursWatchInterval = time.Minute
+
+var quietHoursCmd = &cobra.Command{
+	Use:     "quiethours",
+	Aliases: []string{"quiet-hours"},
+	Short:   "Stop services outside work hours",
+	Long: `Stop services during a daily quiet window, e.g. at night, and start them
+again when it ends, to keep battery and fans in check.
+
+Set the window with 'doku stop --schedule'. Services are stopped and
+started when 'doku quiethours run' runs: schedule it with cron, or keep it
+running with --watch. Only the services quiet hours stopped are started
+again, and a service started by hand during quiet hours keeps running.
+
+Examples:
+  doku stop --schedule 19:00-09:00 @tools @observability
+  doku quiethours status
+  doku quiethours run --watch
+  doku stop --schedule off
+
+Cron (every 5 minutes):
+  */5 * * * * doku quiethours run`,
+}
+
+var quietHoursStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Show the quiet hours schedule",
+	Args:  cobra.NoArgs,
+	RunE:  runQuietHoursStatus,
+}
+
+var quietHoursRunCmd = &cobra.Command{
+	Use:   "run",
+	Short: "Stop or start services as quiet hours begin or end",
+	Args:  cobra.NoArgs,
+	RunE:  runQuietHoursRun,
+}
+
+func init() {
+	rootCmd.AddCommand(quietHoursCmd)
+
+	quietHoursCmd.AddCommand(quietHoursStatusCmd)
+	quietHoursCmd.AddCommand(quietHoursRunCmd)
+
+	quietHoursRunCmd.Flags().BoolVar(&quietHoursWatch, "watch", false, "Keep running and check every minute")
+}
+
+// runStopSchedule sets or turns off quiet hours for 'doku stop --schedule'
+func runStopSchedule(window string, targets []string) error {
+	cfgMgr, err := initConfigManager()
+	if err != nil {
+		if err == types.ErrNotInitialized {
+			return nil
+		}
+		return err
+	}
+
+	if window == "off" {
+		if len(targets) > 0 {
+			return fmt.Errorf("--schedule off does not take services")
+		}
+		var stopped []string
+		if err := cfgMgr.Update(func(c *types.Config) error {
+			if c.QuietHours != nil {
+				stopped = c.QuietHours.Stopped
+			}
+			c.QuietHours = nil
+			return nil
+		}); err != nil {
+			return err
+		}
+		color.Green("✓ Quiet hours turned off")
+		if len(stopped) > 0 {
+			color.Yellow("⚠️  Still stopped for quiet hours: %s", strings.Join(stopped, ", "))
+			fmt.Printf("   Start them with: doku start %s\n", strings.Join(stopped, " "))
+		}
+		return nil
+	}
+
+	if _, _, err := service.ParseWindow(window); err != nil {
+		return err
+	}
+	// Fail now rather than at the next run if a group doesn't exist
+	if _, err := cfgMgr.ResolveNames(targets); err != nil {
+		return err
+	}
+
+	if err := cfgMgr.Update(func(c *types.Config) error {
+		if c.QuietHours == nil {
+			c.QuietHours = &types.QuietHoursConfig{}
+		}
+		c.QuietHours.Window = window
+		c.QuietHours.Targets = targets
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	stopAt, startAt, _ := strings.Cut(window, "-")
+	color.Green("✓ Quiet hours set: services stop at %s and start again at %s", stopAt, startAt)
+	fmt.Printf("  Services: %s\n", describeQuietHoursTargets(targets))
+	fmt.Println()
+	color.New(color.Faint).Println("Quiet hours apply when 'doku quiethours run' runs. Schedule it with cron:")
+	color.New(color.Faint).Println("  */5 * * * * doku quiethours run")
+	color.New(color.Faint).Println("or keep 'doku quiethours run --watch' running.")
+	return nil
+}
+
+func runQuietHoursStatus(cmd *cobra.Command, args []string) error {
+	cfgMgr, err := initConfigManager()
+	if err != nil {
+		if err == types.ErrNotInitialized {
+			return nil
+		}
+		return err
+	}
+
+	cfg, err := cfgMgr.Get()
+	if err != nil {
+		return err
+	}
+	quiet := cfg.QuietHours
+	if quiet == nil {
+		color.Yellow("Quiet hours are not set")
+		fmt.Println()
+		fmt.Println("Set them with: doku stop --schedule 19:00-09:00 [service | @group]...")
+		return nil
+	}
+
+	inWindow, err := service.InWindow(quiet.Window, time.Now())
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Window:    %s\n", quiet.Window)
+	fmt.Printf("Services:  %s\n", describeQuietHoursTargets(quiet.Targets))
+	if inWindow {
+		fmt.Printf("Now:       %s\n", color.YellowString("quiet hours"))
+	} else {
+		fmt.Printf("Now:       %s\n", color.GreenString("work hours"))
+	}
+	if !quiet.LastStop.IsZero() {
+		fmt.Printf("Last stop: %s\n", formatTime(quiet.LastStop))
+	}
+	if len(quiet.Stopped) > 0 {
+		fmt.Printf("Stopped:   %s\n", strings.Join(quiet.Stopped, ", "))
+	}
+	return nil
+}
+
+func runQuietHoursRun(cmd *cobra.Command, args []string) error {
+	cfgMgr, err := initConfigManager()
+	if err != nil {
+		if err == types.ErrNotInitialized {
+			return nil
+		}
+		return err
+	}
+
+	dockerClient, err := initDockerClient()
+	if err != nil {
+		return err
+	}
+	defer dockerClient.Close()
+
+	serviceMgr := getServiceManager(dockerClient, cfgMgr)
+
+	for {
+		// Reload, as other doku commands may have changed the config since
+		if _, err := cfgMgr.Load(); err != nil {
+			if !quietHoursWatch {
+				return err
+			}
+			color.Red("✗ %v", err)
+			time.Sleep(quietHoursWatchInterval)
+			continue
+		}
+
+		result, err := serviceMgr.RunQuietHours(time.Now())
+		if err != nil {
+			if !quietHoursWatch {
+				return err
+			}
+			color.Red("✗ %v", err)
+		} else {
+			printQuietHoursResult(result)
+		}
+
+		if !quietHoursWatch {
+			return nil
+		}
+		time.Sleep(quietHoursWatchInterval)
+	}
+}
+
+// printQuietHoursResult lists what a quiet hours run changed
+func printQuietHoursResult(result *service.QuietHoursResult) {
+	if len(result.Stopped) > 0 {
+		color.Green("✓ Quiet hours began, stopped: %s", strings.Join(result.Stopped, ", "))
+	}
+	if len(result.Started) > 0 {
+		color.Green("✓ Quiet hours ended, started: %s", strings.Join(result.Started, ", "))
+	}
+
+	failed := make([]string, 0, len(result.Failed))
+	for name := range result.Failed {
+		failed = append(failed, name)
+	}
+	sort.Strings(failed)
+	for _, name := range failed {
+		color.Red("✗ %s: %v", name, result.Failed[name])
+	}
+}
+
+// describeQuietHoursTargets describes the services quiet hours apply to
+func describeQuietHoursTargets(targets []string) string {
+	if len(targets) == 0 {
+		return "all services"
+	}
+	return strings.Join(targets, ", ")
+}
@@ -0,0 +1,224 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/AlecAivazis/survey/v2"
+	"github.com/docker/docker/api/types/registry"
+	"github.com/dokulabs/doku-cli/internal/config"
+	"github.com/dokulabs/doku-cli/internal/docker"
+	"github.com/dokulabs/doku-cli/pkg/types"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+var (
+	registryUsername      string
+	registryPasswordStdin bool
+)
+
+var registryCmd = &cobra.Command{
+	Use:   "registry",
+	Short: "Manage logins to private image registries",
+	Long: `Manage logins to private image registries.
+
+Images are pulled, and projects built, with the credentials of their
+registry: the login saved with 'doku registry login', or else Docker's own
+from ~/.docker/config.json and its credential helpers, so registries you
+logged in to with 'docker login' work without doing anything.
+
+Passwords are saved in the Doku configuration, encrypted if
+'doku config encrypt' is on.
+
+Examples:
+  doku registry login ghcr.io -u octocat
+  echo $GITHUB_TOKEN | doku registry login ghcr.io -u octocat --password-stdin
+  doku registry list
+  doku registry logout ghcr.io`,
+}
+
+var registryLoginCmd = &cobra.Command{
+	Use:   "login <registry>",
+	Short: "Save the credentials for a registry",
+	Long: `Check credentials against a registry and save them for pulls and builds.
+The registry is a host such as ghcr.io; use docker.io for Docker Hub.
+
+Examples:
+  doku registry login ghcr.io -u octocat
+  echo $TOKEN | doku registry login registry.example.com:5000 -u ci --password-stdin`,
+	Args: cobra.ExactArgs(1),
+	RunE: runRegistryLogin,
+}
+
+var registryLogoutCmd = &cobra.Command{
+	Use:   "logout <registry>",
+	Short: "Delete the credentials saved for a registry",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runRegistryLogout,
+}
+
+var registryListCmd = &cobra.Command{
+	Use:     "list",
+	Short:   "List registry logins",
+	Aliases: []string{"ls"},
+	Args:    cobra.NoArgs,
+	RunE:    runRegistryList,
+}
+
+func init() {
+	rootCmd.AddCommand(registryCmd)
+
+	registryCmd.AddCommand(registryLoginCmd)
+	registryCmd.AddCommand(registryLogoutCmd)
+	registryCmd.AddCommand(registryListCmd)
+
+	registryLoginCmd.Flags().StringVarP(&registryUsername, "username", "u", "", "Username")
+	registryLoginCmd.Flags().BoolVar(&registryPasswordStdin, "password-stdin", false, "Read the password or token from stdin")
+}
+
+func runRegistryLogin(cmd *cobra.Command, args []string) error {
+	server := docker.NormalizeRegistry(args[0])
+
+	cfgMgr, err := initConfigManager()
+	if err != nil {
+		if err == types.ErrNotInitialized {
+			return nil
+		}
+		return err
+	}
+
+	login := types.RegistryLogin{Server: server, Username: registryUsername}
+	if login.Username == "" {
+		if registryPasswordStdin || !docker.IsTerminal(os.Stdin) {
+			return fmt.Errorf("a username is required: use --username")
+		}
+		if err := survey.AskOne(&survey.Input{Message: "Username:"}, &login.Username); err != nil {
+			return err
+		}
+	}
+
+	if registryPasswordStdin {
+		login.Password, err = readPasswordStdin(os.Stdin)
+		if err != nil {
+			return err
+		}
+	} else {
+		if !docker.IsTerminal(os.Stdin) {
+			return fmt.Errorf("no terminal to ask for the password: use --password-stdin")
+		}
+		if err := survey.AskOne(&survey.Password{Message: "Password:"}, &login.Password); err != nil {
+			return err
+		}
+	}
+
+	dockerClient, err := initDockerClient()
+	if err != nil {
+		return err
+	}
+	defer dockerClient.Close()
+
+	if err := dockerClient.RegistryLogin(registry.AuthConfig{
+		Username:      login.Username,
+		Password:      login.Password,
+		ServerAddress: server,
+	}); err != nil {
+		return err
+	}
+
+	if err := cfgMgr.SetRegistryLogin(login); err != nil {
+		return err
+	}
+
+	color.Green("✓ Logged in to %s as %s", server, login.Username)
+	return nil
+}
+
+// readPasswordStdin reads a password given on stdin, without its trailing
+// newline
+func readPasswordStdin(r io.Reader) (string, error) {
+	line, err := bufio.NewReader(r).ReadString('\n')
+	if err != nil && err != io.EOF {
+		return "", fmt.Errorf("failed to read the password from stdin: %w", err)
+	}
+	password := strings.TrimRight(line, "\r\n")
+	if password == "" {
+		return "", fmt.Errorf("no password given on stdin")
+	}
+	return password, nil
+}
+
+func runRegistryLogout(cmd *cobra.Command, args []string) error {
+	server := docker.NormalizeRegistry(args[0])
+
+	cfgMgr, err := initConfigManager()
+	if err != nil {
+		if err == types.ErrNotInitialized {
+			return nil
+		}
+		return err
+	}
+
+	if err := cfgMgr.RemoveRegistryLogin(server); err != nil {
+		return err
+	}
+
+	color.Green("✓ Logged out of %s", server)
+	return nil
+}
+
+func runRegistryList(cmd *cobra.Command, args []string) error {
+	cfgMgr, err := initConfigManager()
+	if err != nil {
+		if err == types.ErrNotInitialized {
+			return nil
+		}
+		return err
+	}
+
+	logins, err := cfgMgr.ListRegistryLogins()
+	if err != nil {
+		return err
+	}
+
+	if len(logins) == 0 {
+		color.Yellow("No registry logins saved")
+		fmt.Println()
+		fmt.Println("Registries you logged in to with 'docker login' are used too.")
+		fmt.Println("Add one with: doku registry login <registry> -u <username>")
+		return nil
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
+	fmt.Fprintln(w, "REGISTRY\tUSERNAME")
+	for _, login := range logins {
+		fmt.Fprintf(w, "%s\t%s\n", login.Server, login.Username)
+	}
+	w.Flush()
+
+	return nil
+}
+
+// useRegistries makes the logins saved with 'doku registry login' and the
+// configured mirrors available to pulls and builds. Doku not being set up
+// isn't an error here.
+func useRegistries() {
+	cfgMgr, err := config.New()
+	if err != nil || !cfgMgr.IsInitialized() {
+		return
+	}
+	cfg, err := cfgMgr.Get()
+	if err != nil {
+		return
+	}
+	if len(cfg.Registries) > 0 {
+		docker.SetRegistryLogins(cfg.Registries)
+	}
+	if cfg.Preferences.Mirror != "" || len(cfg.Preferences.Mirrors) > 0 {
+		docker.SetRegistryMirrors(cfg.Preferences.Mirror, cfg.Preferences.Mirrors)
+	}
+}
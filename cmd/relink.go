@@ -0,0 +1,163 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/AlecAivazis/survey/v2"
+	"github.com/dokulabs/doku-cli/internal/config"
+	"github.com/dokulabs/doku-cli/internal/docker"
+	"github.com/dokulabs/doku-cli/internal/envfile"
+	"github.com/dokulabs/doku-cli/internal/service"
+	"github.com/dokulabs/doku-cli/pkg/types"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+var (
+	relinkAll     bool
+	relinkRestart bool
+)
+
+var relinkCmd = &cobra.Command{
+	Use:   "relink [project]...",
+	Short: "Refresh the variables injected by 'doku link'",
+	Long: `Update the connection variables that 'doku link' injected, so they match
+the linked services again after those were reinstalled with new credentials
+or a new port, then offer to recreate the projects that changed.
+
+Examples:
+  doku relink myapp             # Refresh myapp's links
+  doku relink --all             # Refresh every linked project
+  doku relink --all --restart   # ...and recreate them without asking`,
+	RunE: runRelink,
+}
+
+func init() {
+	rootCmd.AddCommand(relinkCmd)
+
+	relinkCmd.Flags().BoolVar(&relinkAll, "all", false, "Refresh every project and service that has links")
+	relinkCmd.Flags().BoolVarP(&relinkRestart, "restart", "r", false, "Recreate changed projects without asking")
+}
+
+func runRelink(cmd *cobra.Command, args []string) error {
+	if relinkAll == (len(args) > 0) {
+		return fmt.Errorf("specify projects to refresh or use --all")
+	}
+
+	cfgMgr, err := initConfigManager()
+	if err != nil {
+		if err == types.ErrNotInitialized {
+			return nil
+		}
+		return err
+	}
+
+	dockerClient, err := initDockerClient()
+	if err != nil {
+		return err
+	}
+	defer dockerClient.Close()
+
+	serviceMgr := getServiceManager(dockerClient, cfgMgr)
+
+	names := args
+	if relinkAll {
+		instances, err := cfgMgr.ListInstances()
+		if err != nil {
+			return err
+		}
+		for _, instance := range instances {
+			if len(instance.Links) > 0 {
+				names = append(names, instance.Name)
+			}
+		}
+		if len(names) == 0 {
+			color.Yellow("No projects are linked to services. Link one with: doku link <project> <service>")
+			return nil
+		}
+	}
+
+	return relinkInstances(dockerClient, cfgMgr, serviceMgr, names, relinkRestart)
+}
+
+// relinkInstances refreshes the links of instances and offers to recreate
+// those whose variables changed. Errors for one instance don't stop the
+// others.
+func relinkInstances(dockerClient *docker.Client, cfgMgr *config.Manager, serviceMgr *service.Manager, names []string, restart bool) error {
+	failed := 0
+	fmt.Println()
+	for _, name := range names {
+		instance, err := serviceMgr.Get(name)
+		if err != nil {
+			color.Red("✗ %s: not found", name)
+			failed++
+			continue
+		}
+		if len(instance.Links) == 0 {
+			color.Yellow("⚠️  %s has no links", name)
+			continue
+		}
+
+		changed, err := serviceMgr.Relink(name)
+		if err != nil {
+			color.Red("✗ %s: %v", name, err)
+			failed++
+			continue
+		}
+		if len(changed) == 0 {
+			fmt.Printf("%s is up to date\n", name)
+			continue
+		}
+
+		color.Green("✓ Refreshed %s: %s", name, strings.Join(changed, ", "))
+		env, err := envfile.LoadEnvFile(instanceEnvPath(cfgMgr, instance))
+		if err != nil {
+			color.Red("✗ %s: %v", name, err)
+			failed++
+			continue
+		}
+		if err := applyEnvChanges(dockerClient, cfgMgr, serviceMgr, instance, env, changed, restart); err != nil {
+			color.Red("✗ %s: %v", name, err)
+			failed++
+		}
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d of %d could not be refreshed", failed, len(names))
+	}
+	return nil
+}
+
+// offerRelink is called after a service was recreated or reinstalled. If
+// projects linked to it now have stale variables, it offers to refresh and
+// recreate them, or says how to do it when stdin isn't a terminal.
+func offerRelink(dockerClient *docker.Client, cfgMgr *config.Manager, serviceMgr *service.Manager, serviceName string) {
+	stale, err := serviceMgr.StaleLinks(serviceName)
+	if err != nil || len(stale) == 0 {
+		return
+	}
+
+	color.Yellow("⚠️  Linked to %s with outdated connection variables: %s", serviceName, strings.Join(stale, ", "))
+	if !docker.IsTerminal(os.Stdin) {
+		fmt.Printf("   Refresh them with: doku relink %s\n", strings.Join(stale, " "))
+		fmt.Println()
+		return
+	}
+
+	refresh := false
+	prompt := &survey.Confirm{
+		Message: "Refresh their variables and recreate them now?",
+		Default: true,
+	}
+	if err := survey.AskOne(prompt, &refresh); err != nil || !refresh {
+		fmt.Printf("   Refresh them later with: doku relink %s\n", strings.Join(stale, " "))
+		fmt.Println()
+		return
+	}
+
+	if err := relinkInstances(dockerClient, cfgMgr, serviceMgr, stale, true); err != nil {
+		color.Yellow("⚠️  %v", err)
+	}
+}
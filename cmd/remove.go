@@ -1,6 +1,7 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
 	"strings"
 
@@ -14,8 +15,10 @@ import (
 )
 
 var (
-	removeForce bool
-	removeYes   bool
+	removeForce    bool
+	removeYes      bool
+	removeWithDeps bool
+	removeKeepDeps bool
 )
 
 var removeCmd = &cobra.Command{
@@ -35,6 +38,10 @@ Data is preserved for safety:
 After removal, manual cleanup instructions will be shown if you want to
 permanently delete the data.
 
+Dependencies installed along with the service (e.g. zookeeper for kafka)
+are offered for removal too once no other service or project needs them.
+Use --with-deps to remove them without asking, or --keep-deps to keep them.
+
 Use --yes to skip confirmation prompt.
 Use --force to force removal even if container is running.`,
 	Args:    cobra.ExactArgs(1),
@@ -47,11 +54,17 @@ func init() {
 
 	removeCmd.Flags().BoolVarP(&removeForce, "force", "f", false, "Force removal (even if running)")
 	removeCmd.Flags().BoolVarP(&removeYes, "yes", "y", false, "Skip confirmation prompt")
+	removeCmd.Flags().BoolVar(&removeWithDeps, "with-deps", false, "Also remove dependencies installed with it that nothing else needs")
+	removeCmd.Flags().BoolVar(&removeKeepDeps, "keep-deps", false, "Keep the dependencies installed with it")
 }
 
 func runRemove(cmd *cobra.Command, args []string) error {
 	instanceName := args[0]
 
+	if removeWithDeps && removeKeepDeps {
+		return fmt.Errorf("--with-deps and --keep-deps can't be used together")
+	}
+
 	// Create config manager
 	cfgMgr, err := config.New()
 	if err != nil {
@@ -71,19 +84,19 @@ func runRemove(cmd *cobra.Command, args []string) error {
 	}
 	defer dockerClient.Close()
 
-	// Prevent removal of Traefik (system component)
-	if instanceName == "traefik" || instanceName == "doku-traefik" {
+	// Prevent removal of the reverse proxy (system component)
+	if proxyContainer, ok := proxyContainerName(instanceName); ok {
 		fmt.Println()
-		color.Red("✗ Cannot remove Traefik")
+		color.Red("✗ Cannot remove %s", proxyContainer)
 		fmt.Println()
-		color.Yellow("Traefik is a core system component required for all services.")
+		color.Yellow("The reverse proxy is a core system component required for all services.")
 		fmt.Println()
-		color.New(color.Bold).Println("To remove Traefik along with all Doku components:")
+		color.New(color.Bold).Println("To remove it along with all Doku components:")
 		fmt.Printf("  %s\n", color.CyanString("doku uninstall"))
 		fmt.Println()
 		color.New(color.Faint).Println("This will remove:")
 		color.New(color.Faint).Println("  • All services")
-		color.New(color.Faint).Println("  • Traefik reverse proxy")
+		color.New(color.Faint).Println("  • The reverse proxy")
 		color.New(color.Faint).Println("  • Docker network")
 		color.New(color.Faint).Println("  • SSL certificates")
 		color.New(color.Faint).Println("  • All configuration")
@@ -128,25 +141,9 @@ func runRemove(cmd *cobra.Command, args []string) error {
 	}
 
 	// Get volume names from Docker
-	if instance.IsMultiContainer {
-		for _, container := range instance.Containers {
-			containerInfo, err := dockerClient.ContainerInspect(container.FullName)
-			if err == nil {
-				for _, mount := range containerInfo.Mounts {
-					if mount.Type == "volume" && strings.HasPrefix(mount.Name, "doku-") {
-						volumeNames = append(volumeNames, mount.Name)
-					}
-				}
-			}
-		}
-	} else {
-		containerInfo, err := dockerClient.ContainerInspect(instance.ContainerName)
-		if err == nil {
-			for _, mount := range containerInfo.Mounts {
-				if mount.Type == "volume" && strings.HasPrefix(mount.Name, "doku-") {
-					volumeNames = append(volumeNames, mount.Name)
-				}
-			}
+	if volumes, err := dockerClient.ListInstanceVolumes(context.Background(), instanceName); err == nil {
+		for _, vol := range volumes {
+			volumeNames = append(volumeNames, vol.Name)
 		}
 	}
 
@@ -182,7 +179,17 @@ func runRemove(cmd *cobra.Command, args []string) error {
 	}
 
 	// Show dependencies
-	if len(instance.Dependencies) > 0 {
+	var orphans []string
+	if instance.ServiceType != "custom-project" {
+		orphans, err = serviceMgr.OrphanedDependencies(instanceName)
+		if err != nil {
+			return fmt.Errorf("failed to check dependencies: %w", err)
+		}
+	}
+	if len(orphans) > 0 {
+		fmt.Printf("Dependencies installed with it and no longer needed: %s\n", strings.Join(orphans, ", "))
+		fmt.Println()
+	} else if len(instance.Dependencies) > 0 {
 		color.New(color.Faint).Printf("Dependencies (%s) will NOT be removed\n", strings.Join(instance.Dependencies, ", "))
 		fmt.Println()
 	}
@@ -213,11 +220,26 @@ func runRemove(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to remove service: %w", err)
 	}
 
+	// Its own certificate, with its TLS SANs, goes with it
+	if len(instance.TLSSANs) > 0 {
+		if cfg, err := cfgMgr.Get(); err == nil {
+			if err := removeServiceCertificate(cfgMgr, cfg, instance); err != nil {
+				color.Yellow("⚠️  Failed to remove the certificate of %s: %v", instanceName, err)
+			}
+		}
+	}
+
 	// Success message
 	fmt.Println()
 	color.Green("✓ Service '%s' removed successfully", instanceName)
 	fmt.Println()
 
+	if len(orphans) > 0 {
+		if err := removeOrphanedDependencies(cfgMgr, serviceMgr, orphans); err != nil {
+			return err
+		}
+	}
+
 	// Show cleanup instructions if there's data to clean up
 	if len(volumeNames) > 0 || len(envFilePaths) > 0 {
 		color.Yellow("Data preserved for safety. To permanently delete:")
@@ -246,3 +268,55 @@ func runRemove(cmd *cobra.Command, args []string) error {
 
 	return nil
 }
+
+// removeOrphanedDependencies removes the dependencies left unneeded by a
+// removed service, dependents first, once confirmed (or with --with-deps).
+// Their data is preserved like the service's.
+func removeOrphanedDependencies(cfgMgr *config.Manager, serviceMgr *service.Manager, orphans []string) error {
+	remove := removeWithDeps
+	if !remove && !removeKeepDeps && !removeYes {
+		prompt := &survey.Confirm{
+			Message: fmt.Sprintf("Also remove its unused dependencies (%s)? (data will be preserved)", strings.Join(orphans, ", ")),
+			Default: false,
+		}
+		if err := survey.AskOne(prompt, &remove); err != nil {
+			return fmt.Errorf("confirmation failed: %w", err)
+		}
+	}
+
+	if !remove {
+		color.New(color.Faint).Println("Unused dependencies kept. To remove them:")
+		for _, name := range orphans {
+			color.New(color.Faint).Printf("  doku remove %s\n", name)
+		}
+		fmt.Println()
+		return nil
+	}
+
+	deps := make(map[string][]string, len(orphans))
+	for _, name := range orphans {
+		if dep, err := cfgMgr.GetInstance(name); err == nil {
+			deps[name] = dep.Dependencies
+		}
+	}
+
+	results := service.RunBulk(orphans, service.BulkOptions{
+		Workers:      1,
+		Dependencies: deps,
+		Reverse:      true,
+	}, func(name string) error {
+		fmt.Printf("Removing %s...\n", color.CyanString(name))
+		return serviceMgr.Remove(name, removeForce, false)
+	})
+
+	fmt.Println()
+	for _, result := range results {
+		if result.Err != nil {
+			color.Red("✗ Failed to remove %s: %v", result.Name, result.Err)
+		} else {
+			color.Green("✓ Dependency '%s' removed", result.Name)
+		}
+	}
+	fmt.Println()
+	return nil
+}
@@ -0,0 +1,134 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/dokulabs/doku-cli/internal/config"
+	"github.com/dokulabs/doku-cli/internal/dns"
+	"github.com/dokulabs/doku-cli/internal/domain"
+	"github.com/dokulabs/doku-cli/internal/service"
+	"github.com/dokulabs/doku-cli/pkg/types"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+var renameCmd = &cobra.Command{
+	Use:   "rename <service> <new-name>",
+	Short: "Rename an installed service",
+	Long: `Give an installed service a new name, keeping its data and settings.
+
+Docker can't rename labels or volumes, so the containers are recreated
+under the new name and the data in the service's volumes is copied into
+new volumes; the old ones are removed once the renamed service is in
+place. The service is stopped meanwhile and started again if it was
+running.
+
+The Traefik route, URL, network aliases, env files and hosts entry follow
+the new name, as do groups, links and ${name.FIELD} references in other
+services' env files. Services that referenced the old name must be
+recreated to pick up the change; doku lists them when it's done.
+
+Custom projects can't be renamed.
+
+Examples:
+  doku rename postgres-16 db
+  doku rename redis cache`,
+	Args: cobra.ExactArgs(2),
+	RunE: runRename,
+}
+
+func init() {
+	rootCmd.AddCommand(renameCmd)
+}
+
+func runRename(cmd *cobra.Command, args []string) error {
+	oldName, newName := args[0], args[1]
+
+	cfgMgr, err := initConfigManager()
+	if err != nil {
+		if err == types.ErrNotInitialized {
+			return nil
+		}
+		return err
+	}
+
+	if oldName == newName {
+		return fmt.Errorf("'%s' already has that name", oldName)
+	}
+	if err := config.ValidateInstanceName(newName); err != nil {
+		return err
+	}
+
+	instance, err := cfgMgr.GetInstance(oldName)
+	if err != nil {
+		if _, projErr := cfgMgr.GetProject(oldName); projErr == nil {
+			return fmt.Errorf("'%s' is a custom project and can't be renamed", oldName)
+		}
+		return fmt.Errorf("service '%s' not found. Use 'doku list' to see installed services", oldName)
+	}
+	if cfgMgr.HasInstance(newName) {
+		return fmt.Errorf("'%s' is already installed; choose another name", newName)
+	}
+
+	cfg, err := cfgMgr.Get()
+	if err != nil {
+		return err
+	}
+
+	dockerClient, err := initDockerClient()
+	if err != nil {
+		return err
+	}
+	defer dockerClient.Close()
+
+	fmt.Println()
+	color.Cyan("Renaming %s to %s...", oldName, newName)
+	fmt.Println()
+
+	oldURL := instance.URL
+	serviceMgr := service.NewManager(dockerClient, cfgMgr)
+	renamed, dependents, err := serviceMgr.Rename(oldName, newName)
+	if err != nil {
+		return err
+	}
+
+	if cfg.Preferences.DNSSetup == "hosts" && renamed.URL != "" {
+		dnsMgr := dns.NewManagerForContext(cfg.Preferences.Context).SetIP(config.HostsIP(cfg.Preferences))
+		if oldHost := config.URLHost(oldURL); oldHost != "" {
+			if err := dnsMgr.RemoveSingleEntry(oldHost); err != nil {
+				color.Yellow("⚠️  Failed to remove %s from hosts file: %v", oldHost, err)
+			}
+		}
+		for _, base := range domain.BaseDomains(cfg.Preferences)[1:] {
+			if err := dnsMgr.RemoveSingleEntry(oldName + "." + base); err != nil {
+				color.Yellow("⚠️  Failed to remove %s.%s from hosts file: %v", oldName, base, err)
+			}
+		}
+		for _, base := range domain.BaseDomains(cfg.Preferences) {
+			if err := dnsMgr.AddServiceDomain(newName, base); err != nil {
+				color.Yellow("⚠️  Failed to add DNS entry: %v", err)
+				color.Yellow("   You may need to manually add: %s %s.%s to /etc/hosts", dnsMgr.IP(), newName, base)
+			}
+		}
+	}
+
+	fmt.Println()
+	color.Green("✓ Renamed %s to %s", oldName, newName)
+	if renamed.URL != "" {
+		fmt.Printf("  URL: %s\n", renamed.URL)
+	}
+	if renamed.ConnectionString != "" {
+		fmt.Printf("  Connection: %s\n", renamed.ConnectionString)
+	}
+
+	if len(dependents) > 0 {
+		fmt.Println()
+		color.Yellow("⚠️  These services referred to %s; recreate them to use the new name:", oldName)
+		for _, name := range dependents {
+			fmt.Printf("   doku restart %s --recreate\n", name)
+		}
+	}
+	fmt.Println()
+
+	return nil
+}
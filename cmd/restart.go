@@ -2,7 +2,9 @@ package cmd
 
 import (
 	"fmt"
+	"os"
 	"strings"
+	"time"
 
 	"github.com/dokulabs/doku-cli/internal/catalog"
 	"github.com/dokulabs/doku-cli/internal/config"
@@ -15,21 +17,32 @@ import (
 )
 
 var (
-	restartPort    int
-	restartRunInit bool
-	restartEnv     []string
+	restartAll         bool
+	restartServiceType string
+	restartPort        int
+	restartRunInit     bool
+	restartEnv         []string
+	restartRecreate    bool
+	restartLatest      bool
 )
 
 var restartCmd = &cobra.Command{
-	Use:   "restart <service>",
+	Use:   "restart [service | @group]",
 	Short: "Restart a service",
 	Long: `Restart a service instance.
 
-The service will be stopped and then started again. Environment variables are
-always loaded from the service's env file (~/.doku/services/<service>.env).
+The service will be stopped and then started again. A plain restart keeps the
+existing container, so changes to the service's env file
+(~/.doku/services/<service>.env) only take effect with --recreate:
+  doku restart postgres --recreate
 
 You can update environment variables while restarting:
-  doku restart postgres --env POSTGRES_PASSWORD=newpass
+  doku restart postgres --env POSTGRES_PASSWORD=newpass --recreate
+
+Recreating reuses the image the service was installed with, pinned by
+digest, even if its tag has since moved. Use --latest to pull the tag again
+and move to the image it now points to:
+  doku restart postgres --latest
 
 You can also change the port mapping when restarting:
   doku restart postgres --port 5432   # Add or change port mapping
@@ -37,22 +50,52 @@ You can also change the port mapping when restarting:
 
 For multi-container services with init containers (e.g., database migrations),
 use the --run-init flag to run init containers before restarting:
-  doku restart signoz --run-init      # Run migrations before restart`,
-	Args: cobra.ExactArgs(1),
+  doku restart signoz --run-init      # Run migrations before restart
+
+Restart several services at once:
+  doku restart --all                  # All running services
+  doku restart --service postgres     # All running postgres instances
+  doku restart @backend               # All running services of a group`,
+	Args: cobra.MaximumNArgs(1),
 	RunE: runRestart,
 }
 
 func init() {
 	rootCmd.AddCommand(restartCmd)
 
+	restartCmd.Flags().BoolVarP(&restartAll, "all", "a", false, "Restart all running services")
+	restartCmd.Flags().StringVarP(&restartServiceType, "service", "s", "", "Restart all running services of this type")
+	_ = restartCmd.RegisterFlagCompletionFunc("service", completeCatalogServices(false))
+
 	restartCmd.Flags().IntVarP(&restartPort, "port", "p", -1, "Change host port mapping (0 to remove, -1 to keep current)")
 	restartCmd.Flags().BoolVar(&restartRunInit, "run-init", false, "Run init containers before restarting (for multi-container services)")
 	restartCmd.Flags().StringSliceVarP(&restartEnv, "env", "e", []string{}, "Update environment variables (KEY=VALUE), saved to env file")
+	restartCmd.Flags().BoolVar(&restartRecreate, "recreate", false, "Recreate the container(s) so env file and label changes take effect")
+	restartCmd.Flags().BoolVar(&restartLatest, "latest", false, "Pull the image tag again and recreate with the image it now points to")
 }
 
 func runRestart(cmd *cobra.Command, args []string) error {
+	if target, err := parseBulkArgs(args, restartAll, restartServiceType); target != nil || err != nil {
+		if err != nil {
+			return err
+		}
+		if restartPort != -1 || restartRunInit || restartRecreate || restartLatest || len(restartEnv) > 0 {
+			return fmt.Errorf("--port, --env, --run-init, --recreate and --latest can only be used with a single service")
+		}
+		return runBulkAction(bulkRestart, *target)
+	}
+
 	instanceName := args[0]
 
+	// Moving to the latest image recreates the containers anyway
+	if restartLatest {
+		restartRecreate = true
+	}
+
+	if restartRecreate && (restartPort != -1 || restartRunInit) {
+		return fmt.Errorf("--recreate and --latest cannot be combined with --port or --run-init")
+	}
+
 	// Initialize config manager
 	cfgMgr, err := initConfigManager()
 	if err != nil {
@@ -89,7 +132,10 @@ func runRestart(cmd *cobra.Command, args []string) error {
 		if restartRunInit {
 			return fmt.Errorf("--run-init is not supported for custom projects")
 		}
-		return restartProject(instanceName, dockerClient, cfgMgr, restartEnv)
+		if restartLatest {
+			return fmt.Errorf("--latest is not supported for custom projects")
+		}
+		return restartProject(instanceName, dockerClient, cfgMgr, restartEnv, restartRecreate)
 	}
 
 	// Initialize catalog manager if --run-init is requested
@@ -124,8 +170,31 @@ func runRestart(cmd *cobra.Command, args []string) error {
 
 	fmt.Printf("Restarting %s...\n", color.CyanString(instanceName))
 
-	// Check if port flag was provided
-	if restartPort != -1 {
+	// Check if recreate or port flag was provided
+	if restartLatest {
+		// Re-resolving the tag also picks up env file and label changes
+		if err := serviceMgr.RecreateLatest(instanceName); err != nil {
+			return fmt.Errorf("failed to recreate service: %w", err)
+		}
+		// Update instance reference
+		instance, err = serviceMgr.Get(instanceName)
+		if err != nil {
+			return fmt.Errorf("failed to get updated instance: %w", err)
+		}
+		if instance.ImageDigest != "" {
+			color.Green("✓ Pinned %s", instance.ImageDigest)
+		}
+	} else if restartRecreate {
+		// Recreate picks up env file and label changes
+		if err := serviceMgr.Recreate(instanceName); err != nil {
+			return fmt.Errorf("failed to recreate service: %w", err)
+		}
+		// Update instance reference
+		instance, err = serviceMgr.Get(instanceName)
+		if err != nil {
+			return fmt.Errorf("failed to get updated instance: %w", err)
+		}
+	} else if restartPort != -1 {
 		// Port change requested - need to recreate container
 		if restartPort != instance.Network.HostPort {
 			fmt.Printf("Changing port mapping: %d → %d\n", instance.Network.HostPort, restartPort)
@@ -152,6 +221,15 @@ func runRestart(cmd *cobra.Command, args []string) error {
 
 	// Success message
 	color.Green("✓ Service restarted successfully")
+	if restartRecreate {
+		offerRelink(dockerClient, cfgMgr, serviceMgr, instanceName)
+	}
+
+	// A plain restart keeps the old container, so env file edits are not applied
+	if !restartRecreate && envChangedSinceCreate(dockerClient, cfgMgr, instance) {
+		color.Yellow("⚠️  The env file changed after the container was created; changes are not applied")
+		color.New(color.Faint).Printf("Use 'doku restart %s --recreate' to apply them\n", instanceName)
+	}
 
 	// Show access information
 	fmt.Println()
@@ -168,11 +246,11 @@ func runRestart(cmd *cobra.Command, args []string) error {
 	if len(instance.Network.PortMappings) > 0 {
 		fmt.Println("Port mappings:")
 		for containerPort, hostPort := range instance.Network.PortMappings {
-			fmt.Printf("  localhost:%s → container:%s\n", hostPort, containerPort)
+			fmt.Printf("  %s:%s → container:%s\n", cfgMgr.GetLocalHost(), hostPort, containerPort)
 		}
 	} else if instance.Network.HostPort > 0 {
 		// Backward compatibility with old single port format
-		fmt.Printf("Host port: localhost:%d → container:%d\n", instance.Network.HostPort, instance.Network.InternalPort)
+		fmt.Printf("Host port: %s:%d → container:%d\n", cfgMgr.GetLocalHost(), instance.Network.HostPort, instance.Network.InternalPort)
 	}
 
 	// Show helpful commands
@@ -183,7 +261,7 @@ func runRestart(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
-func restartProject(projectName string, dockerClient *docker.Client, cfgMgr *config.Manager, envFlags []string) error {
+func restartProject(projectName string, dockerClient *docker.Client, cfgMgr *config.Manager, envFlags []string, recreate bool) error {
 	projectMgr, err := project.NewManager(dockerClient, cfgMgr)
 	if err != nil {
 		return fmt.Errorf("failed to initialize project manager: %w", err)
@@ -216,16 +294,29 @@ func restartProject(projectName string, dockerClient *docker.Client, cfgMgr *con
 		color.Green("✓ Updated environment file")
 	}
 
-	fmt.Printf("Restarting %s...\n", color.CyanString(projectName))
+	if recreate {
+		fmt.Printf("Recreating %s...\n", color.CyanString(projectName))
 
-	// Stop the project
-	if err := projectMgr.Stop(projectName); err != nil {
-		return fmt.Errorf("failed to stop project: %w", err)
-	}
+		// Run removes the existing container and creates a new one
+		if err := projectMgr.Run(project.RunOptions{
+			Name:   projectName,
+			Build:  false,
+			Detach: true,
+		}); err != nil {
+			return fmt.Errorf("failed to recreate project: %w", err)
+		}
+	} else {
+		fmt.Printf("Restarting %s...\n", color.CyanString(projectName))
+
+		// Stop the project
+		if err := projectMgr.Stop(projectName); err != nil {
+			return fmt.Errorf("failed to stop project: %w", err)
+		}
 
-	// Start the project
-	if err := projectMgr.Start(projectName); err != nil {
-		return fmt.Errorf("failed to start project: %w", err)
+		// Start the project
+		if err := projectMgr.Start(projectName); err != nil {
+			return fmt.Errorf("failed to start project: %w", err)
+		}
 	}
 
 	// Success message
@@ -248,3 +339,42 @@ func restartProject(projectName string, dockerClient *docker.Client, cfgMgr *con
 
 	return nil
 }
+
+// envChangedSinceCreate reports whether any of the instance's env files was
+// modified after its container was created
+func envChangedSinceCreate(dockerClient *docker.Client, cfgMgr *config.Manager, instance *types.Instance) bool {
+	envMgr := envfile.NewManager(cfgMgr.GetDokuDir())
+
+	// Map container names to their env files
+	envPaths := map[string]string{}
+	if instance.IsMultiContainer {
+		for _, c := range instance.Containers {
+			envPaths[c.FullName] = envMgr.GetServiceEnvPath(instance.Name, c.Name)
+		}
+	} else {
+		envPaths[instance.ContainerName] = envMgr.GetServiceEnvPath(instance.Name, "")
+	}
+
+	for containerName, envPath := range envPaths {
+		stat, err := os.Stat(envPath)
+		if err != nil {
+			continue
+		}
+
+		info, err := dockerClient.ContainerInspect(containerName)
+		if err != nil {
+			continue
+		}
+
+		created, err := time.Parse(time.RFC3339Nano, info.Created)
+		if err != nil {
+			continue
+		}
+
+		if stat.ModTime().After(created) {
+			return true
+		}
+	}
+
+	return false
+}
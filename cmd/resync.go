@@ -0,0 +1,172 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dokulabs/doku-cli/internal/config"
+	"github.com/dokulabs/doku-cli/internal/docker"
+	"github.com/dokulabs/doku-cli/internal/readonly"
+	"github.com/dokulabs/doku-cli/internal/service"
+	"github.com/dokulabs/doku-cli/pkg/types"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+// noResyncEnvVar turns off the automatic resync before commands
+const noResyncEnvVar = "DOKU_NO_RESYNC"
+
+// autoResyncTimeout bounds the automatic resync so a slow or stopped
+// Docker daemon doesn't hold up the command
+const autoResyncTimeout = 3 * time.Second
+
+// noAutoResync lists the commands that don't resync first: those that
+// don't need Docker and those that start or stop instances themselves
+var noAutoResync = map[string]bool{
+	"completion": true,
+	"config":     true,
+	"help":       true,
+	"init":       true,
+	"quiethours": true,
+	"remove":     true,
+	"restart":    true,
+	"resync":     true,
+	"self":       true,
+	"start":      true,
+	"stop":       true,
+	"sync":       true,
+	"uninstall":  true,
+	"upgrade":    true,
+	"version":    true,
+	"workspace":  true,
+}
+
+var resyncNoStart bool
+
+var resyncCmd = &cobra.Command{
+	Use:   "resync",
+	Short: "Bring service statuses back in line with Docker",
+	Long: `Refresh the recorded status of every service from Docker and start the
+services that should be running but aren't, e.g. after Docker Desktop
+restarted and only some containers came back.
+
+Doku does this automatically before most commands; set ` + noResyncEnvVar + `=1
+to turn that off.
+
+Examples:
+  doku resync              # Refresh statuses and start stopped services
+  doku resync --no-start   # Only refresh statuses`,
+	Args: cobra.NoArgs,
+	RunE: runResync,
+}
+
+func init() {
+	rootCmd.AddCommand(resyncCmd)
+
+	resyncCmd.Flags().BoolVar(&resyncNoStart, "no-start", false, "Only refresh statuses; don't start services")
+}
+
+func runResync(cmd *cobra.Command, args []string) error {
+	cfgMgr, err := initConfigManager()
+	if err != nil {
+		if err == types.ErrNotInitialized {
+			return nil
+		}
+		return err
+	}
+
+	dockerClient, err := initDockerClient()
+	if err != nil {
+		return err
+	}
+	defer dockerClient.Close()
+
+	serviceMgr := getServiceManager(dockerClient, cfgMgr)
+
+	result, err := serviceMgr.Resync(context.Background(), !resyncNoStart)
+	if err != nil {
+		return fmt.Errorf("failed to resync: %w", err)
+	}
+
+	if !result.Changed() {
+		color.Green("✓ All service statuses match Docker")
+		return nil
+	}
+
+	printResyncResult(result, os.Stdout)
+	if len(result.Failed) > 0 {
+		return fmt.Errorf("%d service(s) could not be started", len(result.Failed))
+	}
+	return nil
+}
+
+// autoResync runs a resync before a command, so statuses recorded before a
+// Docker daemon restart don't mislead it. It only reports what it did, on
+// stderr, and fails silently: the command itself reports Docker problems.
+func autoResync(cmd *cobra.Command) {
+	if off, err := strconv.ParseBool(os.Getenv(noResyncEnvVar)); err == nil && off {
+		return
+	}
+	if readonly.Enabled() {
+		return
+	}
+
+	top := cmd
+	for top.HasParent() && top.Parent().HasParent() {
+		top = top.Parent()
+	}
+	if !top.HasParent() || noAutoResync[top.Name()] || strings.HasPrefix(top.Name(), "__") {
+		return
+	}
+
+	cfgMgr, err := config.New()
+	if err != nil || !cfgMgr.IsInitialized() {
+		return
+	}
+	dockerClient, err := docker.NewClient()
+	if err != nil {
+		return
+	}
+	defer dockerClient.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), autoResyncTimeout)
+	defer cancel()
+
+	result, err := service.NewManager(dockerClient, cfgMgr).Resync(ctx, true)
+	if err != nil || (len(result.Started) == 0 && len(result.Failed) == 0) {
+		return
+	}
+
+	color.New(color.Faint).Fprintln(os.Stderr, "Some services had stopped, probably because Docker restarted:")
+	printResyncResult(&service.ResyncResult{Started: result.Started, Failed: result.Failed}, os.Stderr)
+}
+
+// printResyncResult lists what a resync changed
+func printResyncResult(result *service.ResyncResult, w io.Writer) {
+	if len(result.Started) > 0 {
+		fmt.Fprintln(w, color.GreenString("✓ Started: %s", strings.Join(result.Started, ", ")))
+	}
+	if len(result.Refreshed) > 0 {
+		fmt.Fprintln(w, color.GreenString("✓ Status refreshed: %s", strings.Join(result.Refreshed, ", ")))
+	}
+
+	failed := make([]string, 0, len(result.Failed))
+	for name := range result.Failed {
+		failed = append(failed, name)
+	}
+	sort.Strings(failed)
+	for _, name := range failed {
+		fmt.Fprintln(w, color.RedString("✗ Failed to start %s: %v", name, result.Failed[name]))
+	}
+
+	if len(result.Missing) > 0 {
+		fmt.Fprintln(w, color.YellowString("⚠️  Containers missing: %s", strings.Join(result.Missing, ", ")))
+		fmt.Fprintln(w, "   Recreate them with 'doku sync' or remove them with 'doku remove <service>'")
+	}
+}
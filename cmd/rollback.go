@@ -0,0 +1,135 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/AlecAivazis/survey/v2"
+	"github.com/dokulabs/doku-cli/pkg/types"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+var rollbackYes bool
+
+var rollbackCmd = &cobra.Command{
+	Use:   "rollback <service>",
+	Short: "Restore a service to the image it ran before its last upgrade",
+	Long: `Restore a service to the image, version and environment it ran before
+its container was last recreated with a different image (e.g. by
+'doku service upgrade').
+
+Volumes are preserved. The replaced state is kept, so running rollback a
+second time returns to the version you rolled back from.
+
+Examples:
+  doku rollback postgres          # Roll back postgres to its previous image
+  doku rollback postgres --yes    # Skip confirmation`,
+	Args: cobra.ExactArgs(1),
+	RunE: runRollback,
+}
+
+func init() {
+	rootCmd.AddCommand(rollbackCmd)
+
+	rollbackCmd.Flags().BoolVarP(&rollbackYes, "yes", "y", false, "Skip confirmation prompt")
+}
+
+func runRollback(cmd *cobra.Command, args []string) error {
+	instanceName := args[0]
+
+	// Initialize config manager
+	cfgMgr, err := initConfigManager()
+	if err != nil {
+		if err == types.ErrNotInitialized {
+			return nil
+		}
+		return err
+	}
+
+	// Initialize Docker client
+	dockerClient, err := initDockerClient()
+	if err != nil {
+		return err
+	}
+	defer dockerClient.Close()
+
+	serviceMgr := getServiceManager(dockerClient, cfgMgr)
+
+	instance, err := serviceMgr.Get(instanceName)
+	if err != nil {
+		return fmt.Errorf("service '%s' not found", instanceName)
+	}
+
+	if instance.ServiceType == "custom-project" {
+		return fmt.Errorf("rollback is not supported for custom projects")
+	}
+
+	if instance.Previous == nil {
+		color.Yellow("⚠️  No previous version recorded for '%s'", instanceName)
+		color.New(color.Faint).Println("A snapshot is taken each time the service is upgraded")
+		return nil
+	}
+
+	previous := instance.Previous
+
+	// Show rollback plan
+	fmt.Println()
+	color.Cyan("Rollback Plan for '%s'", instanceName)
+	fmt.Println()
+	fmt.Printf("  Current version:  %s\n", color.YellowString(instance.Version))
+	fmt.Printf("  Restore version:  %s\n", color.GreenString(previous.Version))
+	fmt.Printf("  Restore image:    %s\n", previous.Image)
+	fmt.Printf("  Environment vars: %d\n", len(previous.Environment))
+	fmt.Printf("  Recorded:         %s\n", formatTime(previous.CapturedAt))
+	fmt.Println()
+
+	// Confirmation
+	if !rollbackYes {
+		confirm := false
+		prompt := &survey.Confirm{
+			Message: "Proceed with rollback?",
+			Default: true,
+		}
+		if err := survey.AskOne(prompt, &confirm); err != nil {
+			return err
+		}
+
+		if !confirm {
+			color.Yellow("Rollback cancelled")
+			return nil
+		}
+		fmt.Println()
+	}
+
+	// Make sure the previous image is still available locally
+	exists, err := dockerClient.ImageExists(previous.Image)
+	if err != nil || !exists {
+		color.Cyan("Pulling image: %s", previous.Image)
+		if err := dockerClient.ImagePull(previous.Image); err != nil {
+			return fmt.Errorf("failed to pull image: %w", err)
+		}
+		color.Green("✓ Image pulled")
+		fmt.Println()
+	}
+
+	color.Cyan("Recreating container with previous version...")
+	if err := serviceMgr.Rollback(instanceName); err != nil {
+		return fmt.Errorf("rollback failed: %w", err)
+	}
+	color.Green("✓ Container recreated")
+	fmt.Println()
+
+	color.Green("Rollback complete!")
+	fmt.Println()
+	fmt.Printf("  %s restored to version %s\n",
+		color.CyanString(instanceName),
+		color.GreenString(previous.Version))
+	fmt.Println()
+
+	color.New(color.Faint).Println("Verify the service:")
+	color.New(color.Faint).Printf("  doku health %s\n", instanceName)
+	color.New(color.Faint).Printf("  doku logs %s\n", instanceName)
+	fmt.Println()
+
+	return nil
+}
@@ -3,16 +3,25 @@ package cmd
 import (
 	"fmt"
 	"os"
+	"time"
 
+	"github.com/dokulabs/doku-cli/internal/docker"
+	"github.com/dokulabs/doku-cli/internal/readonly"
+	"github.com/dokulabs/doku-cli/internal/timing"
+	"github.com/dokulabs/doku-cli/internal/workspace"
+	"github.com/fatih/color"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 )
 
 var (
-	cfgFile string
-	version string
-	commit  string
-	date    string
+	cfgFile       string
+	readOnly      bool
+	profiling     bool
+	workspaceName string
+	version       string
+	commit        string
+	date          string
 )
 
 // rootCmd represents the base command
@@ -30,11 +39,42 @@ It provides:
   • Resource management (CPU/Memory limits)
 
 Get started with: doku init`,
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		if err := selectWorkspace(cmd); err != nil {
+			return err
+		}
+		if readOnly || readonly.EnabledByEnv() {
+			readonly.Enable(true)
+			color.New(color.Faint).Fprintln(os.Stderr, "Read-only mode: Docker and Doku files won't be changed")
+		}
+		if profiling || timing.EnabledByEnv() {
+			timing.Enable(true)
+		}
+		if viper.GetBool("quiet") {
+			docker.SetQuietPulls(true)
+		}
+		subscribeWebhooks()
+		useEngine()
+		useRegistries()
+		startUpdateCheck(cmd)
+		autoResync(cmd)
+		autoRenewCertificates(cmd)
+		return nil
+	},
+	PersistentPostRun: func(cmd *cobra.Command, args []string) {
+		syncProxyRoutes()
+	},
 }
 
 // Execute adds all child commands to the root command and sets flags appropriately.
 func Execute() error {
-	return rootCmd.Execute()
+	addCompletionInstall()
+
+	start := time.Now()
+	cmd, err := rootCmd.ExecuteC()
+	reportTiming(cmd, time.Since(start))
+	finishUpdateCheck()
+	return err
 }
 
 func init() {
@@ -44,6 +84,9 @@ func init() {
 	rootCmd.PersistentFlags().StringVar(&cfgFile, "config", "", "config file (default is $HOME/.doku/config.toml)")
 	rootCmd.PersistentFlags().BoolP("verbose", "v", false, "verbose output")
 	rootCmd.PersistentFlags().BoolP("quiet", "q", false, "quiet mode (minimal output)")
+	rootCmd.PersistentFlags().BoolVar(&profiling, "profile", false, "report where the command spent its time (also "+timing.EnvVar+"=1)")
+	rootCmd.PersistentFlags().StringVar(&workspaceName, "workspace", "", "workspace to work in (also "+workspace.EnvVar+"; default is the default workspace)")
+	rootCmd.PersistentFlags().BoolVar(&readOnly, "read-only", false, "refuse any change to Docker or Doku files (also "+readonly.EnvVar+"=1)")
 
 	// Bind flags to viper
 	viper.BindPFlag("verbose", rootCmd.PersistentFlags().Lookup("verbose"))
@@ -0,0 +1,92 @@
+package cmd
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/dokulabs/doku-cli/internal/project"
+	"github.com/dokulabs/doku-cli/internal/service"
+	"github.com/dokulabs/doku-cli/pkg/types"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+var scaleCmd = &cobra.Command{
+	Use:   "scale <service> <replicas>",
+	Short: "Run several replicas of a service behind Traefik",
+	Long: `Run a number of identical containers for a stateless service or custom
+project, the original included. The replicas share its Traefik route, so
+requests to its URL are balanced across them, and its network name on
+doku-network.
+
+Replicas don't get the host ports of the original, which only it can bind.
+Services that keep data in volumes, multi-container services, compose
+projects and services on the host network can't be scaled.
+
+Replicas follow the original: they start, stop and are removed with it, and
+are replaced when it is recreated, e.g. after 'doku env set'.
+
+Examples:
+  doku scale api 3      # Run three containers
+  doku scale api 1      # Back to a single container`,
+	Args: cobra.ExactArgs(2),
+	RunE: runScale,
+}
+
+func init() {
+	rootCmd.AddCommand(scaleCmd)
+}
+
+func runScale(cmd *cobra.Command, args []string) error {
+	name := args[0]
+	replicas, err := strconv.Atoi(args[1])
+	if err != nil || replicas < 1 {
+		return fmt.Errorf("invalid number of replicas: %s (use a number from 1)", args[1])
+	}
+
+	cfgMgr, err := initConfigManager()
+	if err != nil {
+		if err == types.ErrNotInitialized {
+			return nil
+		}
+		return err
+	}
+
+	dockerClient, err := initDockerClient()
+	if err != nil {
+		return err
+	}
+	defer dockerClient.Close()
+
+	var url string
+	if instance, err := cfgMgr.GetInstance(name); err == nil {
+		if err := service.NewManager(dockerClient, cfgMgr).Scale(name, replicas); err != nil {
+			return err
+		}
+		url = instance.URL
+	} else if proj, projErr := cfgMgr.GetProject(name); projErr == nil {
+		projectMgr, err := project.NewManager(dockerClient, cfgMgr)
+		if err != nil {
+			return fmt.Errorf("failed to create project manager: %w", err)
+		}
+		if err := projectMgr.Scale(name, replicas); err != nil {
+			return err
+		}
+		url = proj.URL
+	} else {
+		return fmt.Errorf("service '%s' not found. Use 'doku list' to see installed services", name)
+	}
+
+	fmt.Println()
+	if replicas == 1 {
+		color.Green("✓ %s runs a single container", name)
+	} else {
+		color.Green("✓ %s runs %d replicas", name, replicas)
+		if url != "" {
+			fmt.Printf("  Requests to %s are balanced across them\n", url)
+		}
+	}
+	fmt.Println()
+
+	return nil
+}
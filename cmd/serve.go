@@ -0,0 +1,197 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"os/signal"
+	"runtime"
+	"strconv"
+	"syscall"
+
+	"github.com/dokulabs/doku-cli/internal/api"
+	"github.com/dokulabs/doku-cli/internal/catalog"
+	"github.com/dokulabs/doku-cli/internal/config"
+	"github.com/dokulabs/doku-cli/internal/dns"
+	"github.com/dokulabs/doku-cli/internal/docker"
+	"github.com/dokulabs/doku-cli/internal/traefik"
+	"github.com/dokulabs/doku-cli/pkg/types"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+// serveTokenEnvVar holds the API token when --token isn't given
+const serveTokenEnvVar = "DOKU_API_TOKEN"
+
+var (
+	serveListen    string
+	serveToken     string
+	serveDashboard bool
+)
+
+var serveCmd = &cobra.Command{
+	Use:   "serve",
+	Short: "Serve the Doku API over HTTP",
+	Long: `Run Doku as a daemon exposing a local HTTP API, so editors and GUIs can
+list, install, start, stop and read the logs of services without shelling
+out to the CLI.
+
+Endpoints (JSON):
+  GET    /v1/health
+  GET    /v1/services                   List services
+  POST   /v1/services                   Install {"service", "version", "name", "environment"}
+  GET    /v1/services/{name}            Show a service
+  DELETE /v1/services/{name}            Remove a service (?volumes=true to delete its data)
+  POST   /v1/services/{name}/start      Also /stop and /restart
+  GET    /v1/services/{name}/logs       Logs as plain text (?follow=true, ?container=)
+  GET    /v1/catalog                    List catalog services
+  GET    /v1/catalog/{name}             Show a catalog service
+  GET    /v1/projects                   List projects
+  GET    /v1/projects/{name}            Show a project
+  POST   /v1/projects/{name}/start      Also /stop and /restart
+
+Requests must send "Authorization: Bearer <token>", with the token of
+--token (or ` + serveTokenEnvVar + `), else a random one printed at start. Requests
+for other hosts than the address listened on (and dashboard.<domain> with
+--dashboard) are rejected, so that web pages can't reach the API.
+
+The web dashboard, listing services with their status, logs and
+start/stop/restart/remove actions, is served at the root of the address.
+With --dashboard, Traefik also routes dashboard.<domain> to it while
+'doku serve' runs. Traefik reaches it through host.docker.internal: on
+Linux, listen on an address containers can reach, e.g. 0.0.0.0.
+
+Examples:
+  doku serve
+  doku serve --listen 127.0.0.1:7070 --token s3cret
+  doku serve --dashboard                   # https://dashboard.doku.local`,
+	Args: cobra.NoArgs,
+	RunE: runServe,
+}
+
+func init() {
+	rootCmd.AddCommand(serveCmd)
+
+	serveCmd.Flags().StringVar(&serveListen, "listen", "127.0.0.1:7070", "Address to listen on")
+	serveCmd.Flags().StringVar(&serveToken, "token", "", "Token clients must send (default $"+serveTokenEnvVar+", else a random one)")
+	serveCmd.Flags().BoolVar(&serveDashboard, "dashboard", false, "Route dashboard.<domain> to the web dashboard through Traefik")
+}
+
+func runServe(cmd *cobra.Command, args []string) error {
+	cfgMgr, err := initConfigManager()
+	if err != nil {
+		if err == types.ErrNotInitialized {
+			return nil
+		}
+		return err
+	}
+
+	dockerClient, err := initDockerClient()
+	if err != nil {
+		return err
+	}
+	defer dockerClient.Close()
+
+	token := serveToken
+	if token == "" {
+		token = os.Getenv(serveTokenEnvVar)
+	}
+	generated := token == ""
+	if generated {
+		if token, err = api.NewToken(); err != nil {
+			return err
+		}
+	}
+
+	server := api.NewServer(dockerClient, cfgMgr, catalog.NewManager(cfgMgr.GetCatalogDir())).SetToken(token)
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	color.Green("✓ Doku API listening on http://%s", serveListen)
+	if generated {
+		// The dashboard keeps the token of the link, which the browser
+		// doesn't send
+		fmt.Printf("  Token: %s\n", token)
+		fmt.Printf("  Dashboard: http://%s/#token=%s\n", serveListen, token)
+	} else {
+		fmt.Printf("  Dashboard: http://%s/\n", serveListen)
+	}
+
+	if serveDashboard {
+		cleanup, err := routeDokuDashboard(cfgMgr, dockerClient, serveListen)
+		if err != nil {
+			return err
+		}
+		defer cleanup()
+
+		domain, err := cfgMgr.GetDomain()
+		if err != nil {
+			return fmt.Errorf("failed to get domain: %w", err)
+		}
+		server.AllowHosts("dashboard." + domain)
+	}
+	fmt.Println("Press Ctrl+C to stop")
+
+	if err := server.Serve(ctx, serveListen); err != nil {
+		return fmt.Errorf("API server failed: %w", err)
+	}
+	return nil
+}
+
+// isLoopback reports whether addr only listens on the loopback interface
+func isLoopback(addr string) bool {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return false
+	}
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+// routeDokuDashboard makes Traefik route dashboard.<domain> to the
+// dashboard served on addr, and returns a function removing the route
+func routeDokuDashboard(cfgMgr *config.Manager, dockerClient *docker.Client, addr string) (func(), error) {
+	_, portStr, err := net.SplitHostPort(addr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid listen address %s: %w", addr, err)
+	}
+	port, err := strconv.Atoi(portStr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid listen port %s", portStr)
+	}
+
+	cfg, err := cfgMgr.Get()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get config: %w", err)
+	}
+	prefs := cfg.Preferences
+
+	traefikMgr := traefik.NewManager(dockerClient, cfgMgr.GetTraefikDir(), cfgMgr.GetCertsDir(), prefs.Domain, prefs.Protocol).
+		SetPorts(cfgMgr.GetTraefikPorts())
+	if err := traefikMgr.SetDokuDashboard(port).GenerateDynamicConfig(); err != nil {
+		return nil, fmt.Errorf("failed to route the dashboard: %w", err)
+	}
+
+	if prefs.DNSSetup == "hosts" {
+		dnsMgr := dns.NewManagerForContext(cfgMgr.GetContext()).SetIP(cfgMgr.GetHostsIP())
+		if err := dnsMgr.AddServiceDomain("dashboard", prefs.Domain); err != nil {
+			color.Yellow("⚠️  Failed to add dashboard.%s to the hosts file: %v", prefs.Domain, err)
+		}
+	}
+	if !isLoopback(addr) || runtime.GOOS != "linux" {
+		fmt.Printf("  Dashboard: %s\n", traefikMgr.GetDokuDashboardURL())
+	} else {
+		color.Yellow("⚠️  Traefik can't reach %s on Linux; listen on 0.0.0.0 for %s", addr, traefikMgr.GetDokuDashboardURL())
+	}
+
+	return func() {
+		if err := traefikMgr.SetDokuDashboard(0).GenerateDynamicConfig(); err != nil {
+			color.Yellow("⚠️  Failed to remove the dashboard route: %v", err)
+		}
+	}, nil
+}
@@ -196,6 +196,7 @@ func runServiceUpgrade(cmd *cobra.Command, args []string) error {
 	if err != nil {
 		return fmt.Errorf("failed to get target version spec: %w", err)
 	}
+	targetSpec = targetSpec.ForArch(dockerClient.Arch())
 
 	// Step 1: Pull new image
 	color.Cyan("Pulling new image: %s", targetSpec.Image)
@@ -219,6 +220,7 @@ func runServiceUpgrade(cmd *cobra.Command, args []string) error {
 
 	// Recreate the container with the new image
 	if err := serviceMgr.RecreateWithImage(instanceName, targetSpec.Image); err != nil {
+		color.New(color.Faint).Printf("Restore the previous version with: doku rollback %s\n", instanceName)
 		return fmt.Errorf("failed to recreate container: %w", err)
 	}
 
@@ -243,6 +245,8 @@ func runServiceUpgrade(cmd *cobra.Command, args []string) error {
 	color.New(color.Faint).Printf("  doku health %s\n", instanceName)
 	color.New(color.Faint).Printf("  doku logs %s\n", instanceName)
 	fmt.Println()
+	color.New(color.Faint).Printf("If the new version misbehaves, run 'doku rollback %s'\n", instanceName)
+	fmt.Println()
 
 	return nil
 }
@@ -0,0 +1,126 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/dokulabs/doku-cli/internal/config"
+	"github.com/dokulabs/doku-cli/internal/proxy"
+	"github.com/dokulabs/doku-cli/internal/service"
+	"github.com/dokulabs/doku-cli/internal/tunnel"
+	"github.com/dokulabs/doku-cli/pkg/types"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+var shareCmd = &cobra.Command{
+	Use:   "share <service>",
+	Short: "Share a service on the internet through a tunnel",
+	Long: `Give a service or project a public URL until Ctrl+C.
+
+A Cloudflare quick tunnel client (cloudflared) runs as a container on
+doku-network and forwards requests from a random *.trycloudflare.com URL
+to Traefik, as if they were made to the service's own URL. The middlewares
+set with 'doku expose', like basic auth, apply to them.
+
+Anyone with the URL can reach the service while it's shared: consider
+'doku expose <service> --basic-auth user:password' first.
+
+Examples:
+  doku share api
+  doku share frontend`,
+	Args: cobra.ExactArgs(1),
+	RunE: runShare,
+}
+
+func init() {
+	rootCmd.AddCommand(shareCmd)
+}
+
+func runShare(cmd *cobra.Command, args []string) error {
+	name := args[0]
+
+	cfgMgr, err := initConfigManager()
+	if err != nil {
+		if err == types.ErrNotInitialized {
+			return nil
+		}
+		return err
+	}
+	cfg, err := cfgMgr.Get()
+	if err != nil {
+		return err
+	}
+
+	var url string
+	protected := false
+	if instance, ok := cfg.Instances[name]; ok {
+		if !service.IsRouted(instance) {
+			return fmt.Errorf("'%s' has no URL to share: Traefik doesn't route to it", name)
+		}
+		url = instance.URL
+		protected = instance.Middlewares != nil && len(instance.Middlewares.BasicAuth) > 0
+	} else if proj, ok := cfg.Projects[name]; ok {
+		if proj.URL == "" {
+			return fmt.Errorf("'%s' has no URL to share", name)
+		}
+		url = proj.URL
+	} else {
+		return fmt.Errorf("service '%s' not found. Use 'doku list' to see installed services", name)
+	}
+
+	dockerClient, err := initDockerClient()
+	if err != nil {
+		return err
+	}
+	defer dockerClient.Close()
+
+	protocol := cfg.Preferences.Protocol
+	reverseProxy := proxy.New(dockerClient, cfgMgr, cfg)
+	if running, err := reverseProxy.IsRunning(); err != nil || !running {
+		return fmt.Errorf("%s is not running; start it with 'doku start %s'", reverseProxy.Name(), proxy.Backend(cfg.Preferences))
+	}
+
+	// Requests reach the proxy inside doku-network, on the ports it
+	// listens on
+	httpPort, httpsPort := cfgMgr.GetTraefikPorts()
+	origin := fmt.Sprintf("http://%s:%d", reverseProxy.ContainerName(), httpPort)
+	if protocol == "https" {
+		origin = fmt.Sprintf("https://%s:%d", reverseProxy.ContainerName(), httpsPort)
+	}
+
+	fmt.Printf("Opening a tunnel to %s...\n", url)
+	t, err := tunnel.Start(dockerClient, tunnel.Options{Name: name, Host: config.URLHost(url), Origin: origin})
+	if err != nil {
+		return err
+	}
+	defer t.Close()
+
+	fmt.Println()
+	color.Green("✓ %s is shared at %s", name, t.URL)
+	if !protected {
+		color.Yellow("⚠️  Anyone with the URL can reach %s", name)
+	}
+	fmt.Println("Press Ctrl+C to stop sharing")
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	exited := make(chan error, 1)
+	go func() { exited <- t.Wait() }()
+
+	select {
+	case <-ctx.Done():
+		fmt.Println()
+		color.Green("✓ Stopped sharing %s", name)
+		return nil
+	case err := <-exited:
+		if err == nil {
+			err = fmt.Errorf("exited")
+		}
+		return fmt.Errorf("the tunnel stopped: %w", err)
+	}
+}
@@ -12,22 +12,44 @@ import (
 	"github.com/spf13/cobra"
 )
 
+var (
+	startAll         bool
+	startServiceType string
+)
+
 var startCmd = &cobra.Command{
-	Use:   "start <service>",
+	Use:   "start [service | @group]",
 	Short: "Start a stopped service",
 	Long: `Start a stopped service instance.
 
 The service will be started using its existing configuration.
-All settings (environment variables, volumes, network) remain the same.`,
-	Args: cobra.ExactArgs(1),
+All settings (environment variables, volumes, network) remain the same.
+
+Examples:
+  doku start postgres           # Start one service
+  doku start --all              # Start all stopped services
+  doku start --service postgres # Start all postgres instances
+  doku start @backend           # Start the stopped services of a group (see 'doku group')`,
+	Args: cobra.MaximumNArgs(1),
 	RunE: runStart,
 }
 
 func init() {
 	rootCmd.AddCommand(startCmd)
+
+	startCmd.Flags().BoolVarP(&startAll, "all", "a", false, "Start all stopped services")
+	startCmd.Flags().StringVarP(&startServiceType, "service", "s", "", "Start all stopped services of this type")
+	_ = startCmd.RegisterFlagCompletionFunc("service", completeCatalogServices(false))
 }
 
 func runStart(cmd *cobra.Command, args []string) error {
+	if target, err := parseBulkArgs(args, startAll, startServiceType); target != nil || err != nil {
+		if err != nil {
+			return err
+		}
+		return runBulkAction(bulkStart, *target)
+	}
+
 	instanceName := args[0]
 
 	// Initialize config manager
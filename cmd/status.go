@@ -0,0 +1,260 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"sort"
+	"strings"
+	"sync"
+	"syscall"
+	"text/tabwriter"
+	"time"
+
+	"github.com/dokulabs/doku-cli/internal/config"
+	"github.com/dokulabs/doku-cli/internal/docker"
+	"github.com/dokulabs/doku-cli/internal/service"
+	"github.com/dokulabs/doku-cli/pkg/types"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+var (
+	statusWatch    bool
+	statusInterval time.Duration
+	statusAll      bool
+)
+
+// statusMinInterval keeps --watch from hammering the Docker daemon
+const statusMinInterval = time.Second
+
+var statusCmd = &cobra.Command{
+	Use:   "status [instance...]",
+	Short: "Show resource usage of running services",
+	Long: `Show CPU, memory and network usage of running services.
+
+Usage of multi-container services is summed over their containers.
+With --watch the view refreshes until you press Ctrl+C, and right away
+when a service starts, stops or crashes.
+
+Examples:
+  doku status                     # One-off snapshot
+  doku status --watch             # Refresh every 3 seconds
+  doku status -w --interval 10s   # Refresh every 10 seconds
+  doku status postgres redis      # Only these instances`,
+	RunE: runStatus,
+}
+
+func init() {
+	rootCmd.AddCommand(statusCmd)
+
+	statusCmd.Flags().BoolVarP(&statusWatch, "watch", "w", false, "Refresh continuously")
+	statusCmd.Flags().DurationVar(&statusInterval, "interval", 3*time.Second, "Refresh interval for --watch")
+	statusCmd.Flags().BoolVarP(&statusAll, "all", "a", false, "Include stopped services")
+}
+
+// statusRow is the sampled usage of a single instance
+type statusRow struct {
+	instance *types.Instance
+	stats    *docker.ContainerStatsResult
+}
+
+func runStatus(cmd *cobra.Command, args []string) error {
+	cfgMgr, err := config.New()
+	if err != nil {
+		return fmt.Errorf("failed to create config manager: %w", err)
+	}
+
+	if !cfgMgr.IsInitialized() {
+		color.Yellow("⚠️  Doku is not initialized. Run 'doku init' first.")
+		return nil
+	}
+
+	dockerClient, err := docker.NewClient()
+	if err != nil {
+		return fmt.Errorf("failed to create Docker client: %w", err)
+	}
+	defer dockerClient.Close()
+
+	serviceMgr := service.NewManager(dockerClient, cfgMgr)
+
+	if !statusWatch {
+		rows, err := sampleStatus(context.Background(), dockerClient, serviceMgr, args, true)
+		if err != nil {
+			return err
+		}
+		if len(rows) == 0 {
+			fmt.Println()
+			color.Yellow("No running services found")
+			fmt.Println()
+			return nil
+		}
+		fmt.Println()
+		displayStatus(rows)
+		fmt.Println()
+		return nil
+	}
+
+	if statusInterval < statusMinInterval {
+		statusInterval = statusMinInterval
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	// Follow Docker events for statuses, and redraw as soon as one changes
+	changed := make(chan struct{}, 1)
+	go serviceMgr.Watch(ctx, func(service.StatusChange) {
+		select {
+		case changed <- struct{}{}:
+		default:
+		}
+	})
+
+	ticker := time.NewTicker(statusInterval)
+	defer ticker.Stop()
+
+	for {
+		rows, err := sampleStatus(ctx, dockerClient, serviceMgr, args, false)
+		if err != nil && ctx.Err() == nil {
+			return err
+		}
+
+		// Clear the screen and redraw from the top
+		fmt.Print("\033[H\033[2J")
+		color.New(color.Bold).Printf("Doku status  %s\n", time.Now().Format("15:04:05"))
+		color.New(color.Faint).Printf("Refreshing every %s, press Ctrl+C to quit\n\n", statusInterval)
+
+		if len(rows) == 0 {
+			color.Yellow("No running services found")
+		} else {
+			displayStatus(rows)
+		}
+
+		select {
+		case <-ctx.Done():
+			fmt.Println()
+			return nil
+		case <-ticker.C:
+		case <-changed:
+		}
+	}
+}
+
+// sampleStatus lists instances and samples the usage of running ones in
+// parallel. With refresh, their state is first inspected from Docker;
+// otherwise the recorded one is used, e.g. as Watch keeps it up to date.
+func sampleStatus(ctx context.Context, dockerClient *docker.Client, serviceMgr *service.Manager, names []string, refresh bool) ([]statusRow, error) {
+	instances, err := serviceMgr.List()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list instances: %w", err)
+	}
+
+	if len(names) > 0 {
+		wanted := make(map[string]bool, len(names))
+		for _, name := range names {
+			wanted[name] = true
+		}
+		filtered := make([]*types.Instance, 0, len(names))
+		for _, instance := range instances {
+			if wanted[instance.Name] {
+				filtered = append(filtered, instance)
+				delete(wanted, instance.Name)
+			}
+		}
+		for name := range wanted {
+			return nil, fmt.Errorf("instance '%s' not found", name)
+		}
+		instances = filtered
+	}
+
+	var wg sync.WaitGroup
+	if refresh {
+		for _, instance := range instances {
+			wg.Add(1)
+			go func(inst *types.Instance) {
+				defer wg.Done()
+				updateInstanceStatus(ctx, dockerClient, inst)
+			}(instance)
+		}
+		wg.Wait()
+	}
+
+	rows := make([]statusRow, 0, len(instances))
+	for _, instance := range instances {
+		if instance.Status == types.StatusRunning || statusAll {
+			rows = append(rows, statusRow{instance: instance})
+		}
+	}
+
+	for idx := range rows {
+		if rows[idx].instance.Status != types.StatusRunning {
+			continue
+		}
+		wg.Add(1)
+		go func(row *statusRow) {
+			defer wg.Done()
+			row.stats = sampleInstanceStats(ctx, dockerClient, row.instance)
+		}(&rows[idx])
+	}
+	wg.Wait()
+
+	sort.Slice(rows, func(i, j int) bool {
+		return rows[i].instance.Name < rows[j].instance.Name
+	})
+
+	return rows, nil
+}
+
+// displayStatus renders the usage table with a total line
+func displayStatus(rows []statusRow) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
+	fmt.Fprintln(w, strings.Join([]string{"NAME", "STATUS", "CPU %", "MEM USAGE / LIMIT", "MEM %", "NET I/O"}, "\t"))
+
+	total := &docker.ContainerStatsResult{}
+	for _, row := range rows {
+		status := formatStatusTextForTable(row.instance.Status)
+		if row.stats == nil {
+			fmt.Fprintf(w, "%s\t%s\t-\t-\t-\t-\n", row.instance.Name, status)
+			continue
+		}
+
+		stats := row.stats
+		total.CPUPercent += stats.CPUPercent
+		total.MemoryUsage += stats.MemoryUsage
+		total.NetworkRx += stats.NetworkRx
+		total.NetworkTx += stats.NetworkTx
+
+		fmt.Fprintf(w, "%s\t%s\t%.1f%%\t%s / %s\t%s\t%s / %s\n",
+			row.instance.Name,
+			status,
+			stats.CPUPercent,
+			formatBytes(int64(stats.MemoryUsage)),
+			formatBytes(int64(stats.MemoryLimit)),
+			formatMemoryPercent(stats.MemoryUsage, stats.MemoryLimit),
+			formatBytes(int64(stats.NetworkRx)),
+			formatBytes(int64(stats.NetworkTx)),
+		)
+	}
+
+	if len(rows) > 1 {
+		fmt.Fprintf(w, "%s\t\t%.1f%%\t%s\t\t%s / %s\n",
+			"TOTAL",
+			total.CPUPercent,
+			formatBytes(int64(total.MemoryUsage)),
+			formatBytes(int64(total.NetworkRx)),
+			formatBytes(int64(total.NetworkTx)),
+		)
+	}
+
+	w.Flush()
+}
+
+// formatMemoryPercent formats memory usage as a percentage of the limit
+func formatMemoryPercent(usage, limit uint64) string {
+	if limit == 0 {
+		return "-"
+	}
+	return fmt.Sprintf("%.1f%%", float64(usage)/float64(limit)*100)
+}
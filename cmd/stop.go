@@ -12,22 +12,63 @@ import (
 	"github.com/spf13/cobra"
 )
 
+var (
+	stopAll         bool
+	stopServiceType string
+	stopSchedule    string
+)
+
 var stopCmd = &cobra.Command{
-	Use:   "stop <service>",
+	Use:   "stop [service | @group]",
 	Short: "Stop a running service",
 	Long: `Stop a running service instance.
 
 The service container will be stopped but not removed.
-All data in volumes is preserved and the service can be restarted.`,
-	Args: cobra.ExactArgs(1),
+All data in volumes is preserved and the service can be restarted.
+
+Examples:
+  doku stop postgres           # Stop one service
+  doku stop --all              # Stop all running services
+  doku stop --service postgres # Stop all postgres instances
+  doku stop @backend           # Stop the running services of a group (see 'doku group')
+
+Quiet hours (see 'doku quiethours'):
+  doku stop --schedule 19:00-09:00 @tools @observability  # Stop groups outside work hours
+  doku stop --schedule 19:00-09:00                        # Stop all services outside work hours
+  doku stop --schedule off                                # Turn quiet hours off`,
+	Args: func(cmd *cobra.Command, args []string) error {
+		if stopSchedule != "" {
+			return nil
+		}
+		return cobra.MaximumNArgs(1)(cmd, args)
+	},
 	RunE: runStop,
 }
 
 func init() {
 	rootCmd.AddCommand(stopCmd)
+
+	stopCmd.Flags().BoolVarP(&stopAll, "all", "a", false, "Stop all running services")
+	stopCmd.Flags().StringVarP(&stopServiceType, "service", "s", "", "Stop all running services of this type")
+	_ = stopCmd.RegisterFlagCompletionFunc("service", completeCatalogServices(false))
+	stopCmd.Flags().StringVar(&stopSchedule, "schedule", "", "Stop services daily in a quiet window, e.g. 19:00-09:00 (\"off\" to turn off)")
 }
 
 func runStop(cmd *cobra.Command, args []string) error {
+	if stopSchedule != "" {
+		if stopAll || stopServiceType != "" {
+			return fmt.Errorf("--schedule cannot be combined with --all or --service; list services or @groups instead")
+		}
+		return runStopSchedule(stopSchedule, args)
+	}
+
+	if target, err := parseBulkArgs(args, stopAll, stopServiceType); target != nil || err != nil {
+		if err != nil {
+			return err
+		}
+		return runBulkAction(bulkStop, *target)
+	}
+
 	instanceName := args[0]
 
 	// Initialize config manager
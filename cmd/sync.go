@@ -0,0 +1,129 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/AlecAivazis/survey/v2"
+	"github.com/dokulabs/doku-cli/internal/catalog"
+	"github.com/dokulabs/doku-cli/internal/docker"
+	"github.com/dokulabs/doku-cli/internal/service"
+	"github.com/dokulabs/doku-cli/pkg/types"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+var syncYes bool
+
+var syncCmd = &cobra.Command{
+	Use:   "sync",
+	Short: "Reconcile Doku with Docker and recreate missing containers",
+	Long: `Bring Doku's records back in line with Docker, like 'doku resync', and
+offer to recreate the services whose containers were removed outside Doku,
+e.g. with 'docker rm'. Those are marked as missing in 'doku list'.
+
+A missing service is recreated from its record: the same version, ports,
+volumes, resources and labels. Its env file and volumes are reused, so it
+keeps its credentials and data.
+
+Examples:
+  doku sync         # Ask before recreating each missing service
+  doku sync --yes   # Recreate all missing services`,
+	Args: cobra.NoArgs,
+	RunE: runSync,
+}
+
+func init() {
+	rootCmd.AddCommand(syncCmd)
+
+	syncCmd.Flags().BoolVarP(&syncYes, "yes", "y", false, "Recreate missing services without asking")
+}
+
+func runSync(cmd *cobra.Command, args []string) error {
+	cfgMgr, err := initConfigManager()
+	if err != nil {
+		if err == types.ErrNotInitialized {
+			return nil
+		}
+		return err
+	}
+
+	dockerClient, err := initDockerClient()
+	if err != nil {
+		return err
+	}
+	defer dockerClient.Close()
+
+	serviceMgr := getServiceManager(dockerClient, cfgMgr)
+	result, err := serviceMgr.Resync(context.Background(), true)
+	if err != nil {
+		return fmt.Errorf("failed to resync: %w", err)
+	}
+
+	missing := result.Missing
+	result.Missing = nil
+	if result.Changed() {
+		printResyncResult(result, os.Stdout)
+	}
+	if len(missing) == 0 {
+		color.Green("✓ All services match Docker")
+		return nil
+	}
+
+	color.Yellow("⚠️  The containers of %d service(s) were removed outside Doku", len(missing))
+	fmt.Println()
+
+	interactive := docker.IsTerminal(os.Stdin)
+	var recreate []string
+	for _, name := range missing {
+		if syncYes {
+			recreate = append(recreate, name)
+			continue
+		}
+		if !interactive {
+			fmt.Printf("  %s\n", name)
+			continue
+		}
+		confirm := false
+		prompt := &survey.Confirm{
+			Message: fmt.Sprintf("Recreate %s?", name),
+			Default: true,
+		}
+		if err := survey.AskOne(prompt, &confirm); err != nil {
+			return err
+		}
+		if confirm {
+			recreate = append(recreate, name)
+		}
+	}
+
+	if len(recreate) == 0 {
+		fmt.Println()
+		fmt.Println("Recreate them with 'doku sync --yes' or remove them with 'doku remove <service>'")
+		return nil
+	}
+
+	catalogMgr := catalog.NewManager(cfgMgr.GetCatalogDir())
+	installer, err := service.NewInstaller(dockerClient, cfgMgr, catalogMgr)
+	if err != nil {
+		return fmt.Errorf("failed to create installer: %w", err)
+	}
+
+	failed := 0
+	for _, name := range recreate {
+		fmt.Println()
+		color.Cyan("Recreating %s...", name)
+		if _, err := installer.RecreateMissing(name); err != nil {
+			color.Red("✗ %s: %v", name, err)
+			failed++
+			continue
+		}
+		color.Green("✓ %s recreated", name)
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d service(s) could not be recreated", failed)
+	}
+	return nil
+}
@@ -0,0 +1,96 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/dokulabs/doku-cli/internal/timing"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// slowestSpans is how many individual calls the profile report lists
+const slowestSpans = 10
+
+// fastPathBudgets are the latency budgets of commands people run all the
+// time. A command that exceeds its budget prints a hint, so regressions are
+// noticed without anyone having to profile.
+var fastPathBudgets = map[string]time.Duration{
+	"doku version":      300 * time.Millisecond,
+	"doku config get":   300 * time.Millisecond,
+	"doku group list":   300 * time.Millisecond,
+	"doku env":          time.Second,
+	"doku info":         time.Second,
+	"doku list":         1500 * time.Millisecond,
+	"doku status":       1500 * time.Millisecond,
+	"doku project list": 1500 * time.Millisecond,
+}
+
+// reportTiming prints the profile when --profile is on, or a hint when a
+// fast-path command ran over its budget
+func reportTiming(cmd *cobra.Command, elapsed time.Duration) {
+	if cmd == nil {
+		return
+	}
+
+	if timing.Enabled() {
+		printProfile(os.Stderr, cmd.CommandPath(), elapsed, timing.Summarize(), timing.Spans())
+		return
+	}
+
+	budget, ok := fastPathBudgets[cmd.CommandPath()]
+	if !ok || elapsed <= budget || viper.GetBool("quiet") {
+		return
+	}
+	color.New(color.Faint).Fprintf(os.Stderr,
+		"%s took %s (budget %s). Run it with --profile to see where the time went.\n",
+		cmd.CommandPath(), formatElapsed(elapsed), formatElapsed(budget))
+}
+
+// printProfile writes the per-category totals and the slowest calls
+func printProfile(out io.Writer, command string, elapsed time.Duration, summary []timing.Summary, spans []timing.Span) {
+	fmt.Fprintln(out)
+	color.New(color.Bold).Fprintf(out, "Profile: %s took %s\n", command, formatElapsed(elapsed))
+
+	if len(spans) == 0 {
+		fmt.Fprintln(out, "  No Docker, catalog, config or network calls recorded")
+		return
+	}
+
+	fmt.Fprintln(out)
+	w := tabwriter.NewWriter(out, 0, 0, 3, ' ', 0)
+	fmt.Fprintln(w, "  CATEGORY\tCALLS\tTIME")
+	for _, s := range summary {
+		fmt.Fprintf(w, "  %s\t%d\t%s\n", s.Category, s.Calls, formatElapsed(s.Total))
+	}
+	w.Flush()
+
+	fmt.Fprintln(out)
+	fmt.Fprintln(out, "  Slowest calls:")
+	w = tabwriter.NewWriter(out, 0, 0, 3, ' ', 0)
+	for idx, span := range spans {
+		if idx == slowestSpans {
+			break
+		}
+		fmt.Fprintf(w, "    %s\t%s\t%s\n", formatElapsed(span.Duration), span.Category, span.Name)
+	}
+	w.Flush()
+
+	color.New(color.Faint).Fprintln(out, "\n  Calls made in parallel overlap, so times can add up to more than the total")
+}
+
+// formatElapsed rounds a duration for display, e.g. 1.24s or 35ms
+func formatElapsed(d time.Duration) string {
+	switch {
+	case d >= time.Second:
+		return d.Round(10 * time.Millisecond).String()
+	case d >= time.Millisecond:
+		return d.Round(time.Millisecond).String()
+	default:
+		return d.Round(time.Microsecond).String()
+	}
+}
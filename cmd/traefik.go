@@ -0,0 +1,292 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/dokulabs/doku-cli/internal/config"
+	"github.com/dokulabs/doku-cli/internal/proxy"
+	"github.com/dokulabs/doku-cli/internal/service"
+	"github.com/dokulabs/doku-cli/internal/traefik"
+	"github.com/dokulabs/doku-cli/pkg/types"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+// dashboardAuthUser is the user of the dashboard unless --user says otherwise
+const dashboardAuthUser = "admin"
+
+var (
+	traefikAuthUser     string
+	traefikAuthPassword string
+	traefikAuthShow     bool
+)
+
+var traefikCmd = &cobra.Command{
+	Use:   "traefik",
+	Short: "Manage Traefik",
+	Long: `Manage Traefik, the reverse proxy in front of the services.
+
+Start, stop and restart it like a service: 'doku start traefik'.`,
+}
+
+var traefikAuthCmd = &cobra.Command{
+	Use:   "auth",
+	Short: "Show whether the Traefik dashboard asks for a password",
+	Long: `Show whether the Traefik dashboard asks for a password, and who it lets in.
+
+The dashboard shows every route and the containers behind them, and anyone
+who can reach Traefik can open it. Protect it with basic auth: Doku
+generates a password, keeps it in its configuration (encrypted when
+preferences.encryptsecrets is on) and gives Traefik only its hash.
+
+Examples:
+  doku traefik auth                    # Show the settings
+  doku traefik auth --show-password    # Also show the password
+  doku traefik auth enable             # Protect the dashboard
+  doku traefik auth enable --user ops --password s3cret
+  doku traefik auth rotate             # Generate a new password
+  doku traefik auth disable            # Open the dashboard again`,
+	Args: cobra.NoArgs,
+	RunE: runTraefikAuth,
+}
+
+var traefikUpgradeCmd = &cobra.Command{
+	Use:   "upgrade [version]",
+	Short: "Move Traefik to another release",
+	Long: `Recreate Traefik on another 2.x release, by default the newest one Doku is
+tested with (` + traefik.RecommendedVersion + `), and pin it: later setups use it too. Its
+configuration, certificates, dashboard password and access log are kept.
+
+The new image is pulled before the running Traefik is replaced, so a failed
+pull leaves it as it was.
+
+Examples:
+  doku traefik upgrade           # To ` + traefik.RecommendedVersion + `
+  doku traefik upgrade 2.11.3    # To a given release
+  doku traefik upgrade ` + traefik.TraefikVersion + `      # Back to Doku's default`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runTraefikUpgrade,
+}
+
+var traefikAuthEnableCmd = &cobra.Command{
+	Use:   "enable",
+	Short: "Protect the Traefik dashboard with basic auth",
+	Args:  cobra.NoArgs,
+	RunE:  runTraefikAuthEnable,
+}
+
+var traefikAuthRotateCmd = &cobra.Command{
+	Use:   "rotate",
+	Short: "Replace the Traefik dashboard's password",
+	Args:  cobra.NoArgs,
+	RunE:  runTraefikAuthRotate,
+}
+
+var traefikAuthDisableCmd = &cobra.Command{
+	Use:   "disable",
+	Short: "Open the Traefik dashboard without a password",
+	Args:  cobra.NoArgs,
+	RunE:  runTraefikAuthDisable,
+}
+
+func init() {
+	rootCmd.AddCommand(traefikCmd)
+
+	traefikCmd.AddCommand(traefikAuthCmd)
+	traefikCmd.AddCommand(traefikUpgradeCmd)
+	traefikAuthCmd.AddCommand(traefikAuthEnableCmd)
+	traefikAuthCmd.AddCommand(traefikAuthRotateCmd)
+	traefikAuthCmd.AddCommand(traefikAuthDisableCmd)
+
+	traefikAuthCmd.Flags().BoolVar(&traefikAuthShow, "show-password", false, "Show the password")
+	traefikAuthEnableCmd.Flags().StringVar(&traefikAuthUser, "user", dashboardAuthUser, "User allowed in")
+	traefikAuthEnableCmd.Flags().StringVar(&traefikAuthPassword, "password", "", "Password (default: generated)")
+	traefikAuthRotateCmd.Flags().StringVar(&traefikAuthPassword, "password", "", "New password (default: generated)")
+}
+
+// traefikSetupConfig returns the config manager and configuration of a
+// Traefik setup, or nil ones when Doku isn't initialized
+func traefikSetupConfig() (*config.Manager, *types.Config, error) {
+	cfgMgr, err := initConfigManager()
+	if err != nil {
+		if err == types.ErrNotInitialized {
+			return nil, nil, nil
+		}
+		return nil, nil, err
+	}
+	cfg, err := cfgMgr.Get()
+	if err != nil {
+		return nil, nil, err
+	}
+	if proxy.Backend(cfg.Preferences) != proxy.Traefik {
+		return nil, nil, fmt.Errorf("this setup's proxy is Caddy, not Traefik")
+	}
+	return cfgMgr, cfg, nil
+}
+
+// dashboardTraefik returns a Traefik manager writing the configuration of
+// cfg; the dashboard's auth needs no container
+func dashboardTraefik(cfgMgr *config.Manager, cfg *types.Config) *traefik.Manager {
+	return traefik.NewManager(nil, cfgMgr.GetTraefikDir(), cfgMgr.GetCertsDir(), cfg.Preferences.Domain, cfg.Preferences.Protocol).
+		SetPorts(cfgMgr.GetTraefikPorts())
+}
+
+func runTraefikAuth(cmd *cobra.Command, args []string) error {
+	cfgMgr, cfg, err := traefikSetupConfig()
+	if err != nil || cfgMgr == nil {
+		return err
+	}
+
+	fmt.Printf("Dashboard: %s\n", traefikDashboardURL(cfg))
+	auth := cfg.Traefik.DashboardAuth
+	if auth == nil {
+		color.Yellow("⚠️  Open to anyone who can reach Traefik. Protect it with 'doku traefik auth enable'")
+		return nil
+	}
+	fmt.Printf("User:      %s\n", auth.User)
+	if traefikAuthShow {
+		fmt.Printf("Password:  %s\n", auth.Password)
+	} else {
+		color.New(color.Faint).Println("Password:  hidden (--show-password to show it)")
+	}
+	if !dashboardTraefik(cfgMgr, cfg).DashboardAuthEnabled() {
+		color.Yellow("⚠️  Traefik's configuration lacks the user: run 'doku traefik auth rotate'")
+	}
+	return nil
+}
+
+func runTraefikAuthEnable(cmd *cobra.Command, args []string) error {
+	cfgMgr, cfg, err := traefikSetupConfig()
+	if err != nil || cfgMgr == nil {
+		return err
+	}
+	if cfg.Traefik.DashboardAuth != nil {
+		return fmt.Errorf("the dashboard is already protected; change its password with 'doku traefik auth rotate'")
+	}
+	return setDashboardAuth(cfgMgr, cfg, traefikAuthUser, traefikAuthPassword)
+}
+
+func runTraefikAuthRotate(cmd *cobra.Command, args []string) error {
+	cfgMgr, cfg, err := traefikSetupConfig()
+	if err != nil || cfgMgr == nil {
+		return err
+	}
+	auth := cfg.Traefik.DashboardAuth
+	if auth == nil {
+		return fmt.Errorf("the dashboard isn't protected; protect it with 'doku traefik auth enable'")
+	}
+	return setDashboardAuth(cfgMgr, cfg, auth.User, traefikAuthPassword)
+}
+
+func runTraefikAuthDisable(cmd *cobra.Command, args []string) error {
+	cfgMgr, cfg, err := traefikSetupConfig()
+	if err != nil || cfgMgr == nil {
+		return err
+	}
+	if err := dashboardTraefik(cfgMgr, cfg).RemoveDashboardAuth(); err != nil {
+		return err
+	}
+	if err := cfgMgr.Update(func(c *types.Config) error {
+		c.Traefik.DashboardAuth = nil
+		return nil
+	}); err != nil {
+		return err
+	}
+	color.Green("✓ The Traefik dashboard no longer asks for a password")
+	return nil
+}
+
+func runTraefikUpgrade(cmd *cobra.Command, args []string) error {
+	cfgMgr, cfg, err := traefikSetupConfig()
+	if err != nil || cfgMgr == nil {
+		return err
+	}
+
+	version := traefik.RecommendedVersion
+	if len(args) > 0 {
+		version = args[0]
+	}
+	version, err = traefik.NormalizeVersion(version)
+	if err != nil {
+		return err
+	}
+	image := traefik.Image(version)
+
+	dockerClient, err := initDockerClient()
+	if err != nil {
+		return err
+	}
+	defer dockerClient.Close()
+
+	current := ""
+	if info, err := dockerClient.ContainerInspect(traefik.ContainerName()); err == nil && info.Config != nil {
+		current = info.Config.Image
+	}
+	if current == image {
+		if cfg.Traefik.Version != version {
+			if err := cfgMgr.Update(func(c *types.Config) error {
+				c.Traefik.Version = version
+				return nil
+			}); err != nil {
+				return err
+			}
+		}
+		color.Green("✓ Traefik already runs %s", version)
+		return nil
+	}
+
+	if current != "" {
+		if cached, err := dockerClient.ImageExists(image); err != nil || !cached {
+			fmt.Printf("Pulling %s...\n", image)
+			if err := dockerClient.ImagePull(image); err != nil {
+				return fmt.Errorf("failed to pull %s, Traefik is left as it was: %w", image, err)
+			}
+		}
+	}
+
+	if err := cfgMgr.Update(func(c *types.Config) error {
+		c.Traefik.Version = version
+		return nil
+	}); err != nil {
+		return err
+	}
+	if err := recreateProxy(cfgMgr, "on "+image); err != nil {
+		return err
+	}
+
+	if from := traefik.VersionOf(current); from != "" {
+		color.Green("✓ Traefik moved from %s to %s", from, version)
+	} else {
+		color.Green("✓ Traefik pinned to %s", version)
+	}
+	return nil
+}
+
+// setDashboardAuth protects the dashboard, letting user in with password or
+// a generated one, and prints the credentials
+func setDashboardAuth(cfgMgr *config.Manager, cfg *types.Config, user, password string) error {
+	if password == "" {
+		generated, err := service.GeneratePassword(service.GeneratedPasswordLength)
+		if err != nil {
+			return err
+		}
+		password = generated
+	}
+
+	if err := dashboardTraefik(cfgMgr, cfg).SetDashboardAuth(user, password); err != nil {
+		return err
+	}
+	if err := cfgMgr.Update(func(c *types.Config) error {
+		c.Traefik.DashboardAuth = &types.DashboardAuth{User: user, Password: password}
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	color.Green("✓ The Traefik dashboard asks for a password")
+	fmt.Printf("  URL:      %s\n", traefikDashboardURL(cfg))
+	fmt.Printf("  User:     %s\n", user)
+	fmt.Printf("  Password: %s\n", password)
+	color.New(color.Faint).Println("  Show them again with 'doku traefik auth --show-password'")
+	return nil
+}
@@ -0,0 +1,217 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"text/tabwriter"
+	"time"
+
+	"github.com/dokulabs/doku-cli/internal/docker"
+	"github.com/dokulabs/doku-cli/internal/proxy"
+	"github.com/dokulabs/doku-cli/internal/traefik"
+	"github.com/dokulabs/doku-cli/internal/traffic"
+	"github.com/dokulabs/doku-cli/internal/workspace"
+	"github.com/dokulabs/doku-cli/pkg/types"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+var (
+	trafficFollow bool
+	trafficTail   int
+	trafficSince  string
+	trafficClear  bool
+)
+
+var trafficCmd = &cobra.Command{
+	Use:   "traffic [service]",
+	Short: "Show the requests Traefik routed",
+	Long: `Show the requests Traefik routed to the services, from its access log:
+the latest ones, then per service the number of requests, their status
+codes and their latency percentiles. Useful to see whether a webhook
+reached a service, and what it answered.
+
+Traefik writes the log to ~/.doku/traefik/logs/access.log. Traefik set up
+before Doku wrote it there needs recreating: run 'doku init' again.
+
+Examples:
+  doku traffic                   # Latest requests and summary of all services
+  doku traffic api               # Only api's
+  doku traffic api -f            # Stream api's requests as they come
+  doku traffic --since 15m       # Requests of the last 15 minutes
+  doku traffic --tail 0          # Summary only
+  doku traffic --clear           # Empty the access log`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runTraffic,
+}
+
+func init() {
+	rootCmd.AddCommand(trafficCmd)
+
+	trafficCmd.Flags().BoolVarP(&trafficFollow, "follow", "f", false, "Stream requests as they come")
+	trafficCmd.Flags().IntVar(&trafficTail, "tail", 20, "Number of latest requests to show")
+	trafficCmd.Flags().StringVar(&trafficSince, "since", "", "Only requests since then (e.g. 1h, 30m, 2h30m)")
+	trafficCmd.Flags().BoolVar(&trafficClear, "clear", false, "Empty the access log")
+}
+
+func runTraffic(cmd *cobra.Command, args []string) error {
+	cfgMgr, err := initConfigManager()
+	if err != nil {
+		if err == types.ErrNotInitialized {
+			return nil
+		}
+		return err
+	}
+	cfg, err := cfgMgr.Get()
+	if err != nil {
+		return err
+	}
+	if proxy.Backend(cfg.Preferences) != proxy.Traefik {
+		return fmt.Errorf("the access log needs the Traefik proxy: run 'doku init --proxy traefik'")
+	}
+
+	if trafficClear {
+		if len(args) > 0 || trafficFollow || cmd.Flags().Changed("since") {
+			return fmt.Errorf("--clear can't be combined with a service or other flags")
+		}
+		dockerClient, err := initDockerClient()
+		if err != nil {
+			return err
+		}
+		defer dockerClient.Close()
+		traefikMgr := traefik.NewManager(dockerClient, cfgMgr.GetTraefikDir(), cfgMgr.GetCertsDir(), cfg.Preferences.Domain, cfg.Preferences.Protocol)
+		if err := traefikMgr.ClearAccessLog(); err != nil {
+			return err
+		}
+		color.Green("✓ Cleared Traefik's access log")
+		return nil
+	}
+
+	keep, err := trafficFilter(cfg, args)
+	if err != nil {
+		return err
+	}
+
+	path := traefik.AccessLogPath(cfgMgr.GetTraefikDir())
+	info, err := os.Stat(path)
+	if os.IsNotExist(err) {
+		return fmt.Errorf("no access log at %s: Traefik set up before Doku wrote one needs recreating, run 'doku init' again", path)
+	}
+	if err != nil {
+		return err
+	}
+
+	entries, err := traffic.Read(path, keep)
+	if err != nil {
+		return fmt.Errorf("failed to read the access log: %w", err)
+	}
+
+	if trafficTail > 0 {
+		latest := entries
+		if len(latest) > trafficTail {
+			latest = latest[len(latest)-trafficTail:]
+		}
+		for _, e := range latest {
+			printTrafficEntry(e)
+		}
+	}
+
+	if trafficFollow {
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+		color.New(color.Faint).Println("Waiting for requests. Press Ctrl+C to stop")
+		return traffic.Follow(ctx, path, info.Size(), func(e traffic.Entry) {
+			if keep(e) {
+				printTrafficEntry(e)
+			}
+		})
+	}
+
+	if len(entries) == 0 {
+		fmt.Println("No requests yet")
+		return nil
+	}
+	if trafficTail > 0 {
+		fmt.Println()
+	}
+	printTrafficSummary(traffic.Summarize(entries))
+	return nil
+}
+
+// trafficFilter returns what selects the requests to show: those of the
+// service or project given, since --since
+func trafficFilter(cfg *types.Config, args []string) (func(traffic.Entry) bool, error) {
+	var since time.Time
+	if trafficSince != "" {
+		d, err := time.ParseDuration(trafficSince)
+		if err != nil {
+			return nil, fmt.Errorf("invalid --since '%s': %w", trafficSince, err)
+		}
+		since = time.Now().Add(-d)
+	}
+
+	var routers map[string]bool
+	if len(args) > 0 {
+		name := args[0]
+		switch {
+		case cfg.Instances[name] != nil:
+			// Single-container services route by container name,
+			// multi-container ones by instance name
+			routers = map[string]bool{docker.GenerateContainerName(name): true, name: true}
+		case cfg.Projects[name] != nil:
+			routers = map[string]bool{name: true}
+		default:
+			return nil, fmt.Errorf("service '%s' not found. Use 'doku list' to see installed services", name)
+		}
+	}
+
+	return func(e traffic.Entry) bool {
+		if routers != nil && !routers[e.Router] {
+			return false
+		}
+		return since.IsZero() || !e.Time.Before(since)
+	}, nil
+}
+
+// trafficRouterName returns the service a router is named after
+func trafficRouterName(router string) string {
+	if router == "" {
+		return "(unrouted)"
+	}
+	return strings.TrimPrefix(router, workspace.Prefix())
+}
+
+// printTrafficEntry prints a request, e.g.
+// 10:00:01  POST  api.doku.local/webhooks/github  500  300ms  api
+func printTrafficEntry(e traffic.Entry) {
+	status := fmt.Sprintf("%d", e.Status)
+	switch {
+	case e.Status >= 500:
+		status = color.RedString(status)
+	case e.Status >= 400:
+		status = color.YellowString(status)
+	case e.Status >= 200 && e.Status < 300:
+		status = color.GreenString(status)
+	}
+	fmt.Printf("%s  %-6s %s%s  %s  %s  %s\n",
+		e.Time.Local().Format("15:04:05"), e.Method, e.Host, e.Path, status,
+		formatElapsed(e.Duration), color.New(color.Faint).Sprint(trafficRouterName(e.Router)))
+}
+
+// printTrafficSummary prints the requests per service with their status
+// classes and latency percentiles
+func printTrafficSummary(summaries []traffic.Summary) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
+	fmt.Fprintln(w, "SERVICE\tREQUESTS\t2XX\t3XX\t4XX\t5XX\tP50\tP95\tP99")
+	for _, s := range summaries {
+		fmt.Fprintf(w, "%s\t%d\t%d\t%d\t%d\t%d\t%s\t%s\t%s\n",
+			trafficRouterName(s.Router), s.Requests,
+			s.Statuses[2], s.Statuses[3], s.Statuses[4], s.Statuses[5],
+			formatElapsed(s.P50), formatElapsed(s.P95), formatElapsed(s.P99))
+	}
+	w.Flush()
+}
@@ -0,0 +1,60 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/dokulabs/doku-cli/internal/catalog"
+	"github.com/dokulabs/doku-cli/internal/docker"
+	"github.com/dokulabs/doku-cli/internal/tui"
+	"github.com/dokulabs/doku-cli/pkg/types"
+	"github.com/spf13/cobra"
+)
+
+var uiCmd = &cobra.Command{
+	Use:   "ui",
+	Short: "Manage services from an interactive terminal UI",
+	Long: `Open a keyboard-driven terminal UI listing services with the logs and
+resource usage of the selected one, and a catalog browser to install new
+services.
+
+Keys:
+  ↑/↓ j/k   Select
+  s x r     Start, stop or restart the selected service
+  d         Remove the selected service (keeps data volumes)
+  tab       Switch between services and catalog
+  enter     Choose a version of a catalog service and install it
+  ?         Help
+  q         Quit
+
+Example:
+  doku ui`,
+	Args: cobra.NoArgs,
+	RunE: runUI,
+}
+
+func init() {
+	rootCmd.AddCommand(uiCmd)
+}
+
+func runUI(cmd *cobra.Command, args []string) error {
+	if !docker.IsTerminal(os.Stdin) || !docker.IsTerminal(os.Stdout) {
+		return fmt.Errorf("doku ui needs a terminal; use 'doku list' and friends in scripts")
+	}
+
+	cfgMgr, err := initConfigManager()
+	if err != nil {
+		if err == types.ErrNotInitialized {
+			return nil
+		}
+		return err
+	}
+
+	dockerClient, err := initDockerClient()
+	if err != nil {
+		return err
+	}
+	defer dockerClient.Close()
+
+	return tui.Run(dockerClient, cfgMgr, catalog.NewManager(cfgMgr.GetCatalogDir()))
+}
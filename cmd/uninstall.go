@@ -11,6 +11,7 @@ import (
 	"github.com/AlecAivazis/survey/v2"
 	"github.com/dokulabs/doku-cli/internal/config"
 	"github.com/dokulabs/doku-cli/internal/docker"
+	"github.com/dokulabs/doku-cli/internal/readonly"
 	"github.com/fatih/color"
 	"github.com/spf13/cobra"
 )
@@ -57,6 +58,10 @@ func init() {
 }
 
 func runUninstall(cmd *cobra.Command, args []string) error {
+	if err := readonly.Check("uninstall Doku"); err != nil {
+		return err
+	}
+
 	ctx := context.Background()
 
 	// Colors
@@ -157,19 +162,14 @@ func runUninstall(cmd *cobra.Command, args []string) error {
 	if dockerClient != nil {
 		containersRemoved := 0
 
-		// Get all containers with name starting with "doku-"
-		allContainers, err := dockerClient.ListContainers(ctx)
+		// Get all containers created by Doku
+		allContainers, err := dockerClient.ListManagedContainers(ctx)
 		if err != nil {
 			fmt.Printf("  %s Failed to list containers: %v\n", red("✗"), err)
 		} else {
 			for _, container := range allContainers {
 				name := strings.TrimPrefix(container.Names[0], "/")
 
-				// Only process containers with "doku-" prefix
-				if !strings.HasPrefix(name, "doku-") {
-					continue
-				}
-
 				if err := dockerClient.StopContainer(ctx, container.ID); err != nil {
 					fmt.Printf("  %s Failed to stop %s: %v\n", red("✗"), name, err)
 				} else {
@@ -193,15 +193,12 @@ func runUninstall(cmd *cobra.Command, args []string) error {
 	// Step 2: List Docker volumes (but don't remove them)
 	fmt.Printf("\n%s Checking Docker volumes (preserving data)...\n", cyan("→"))
 	if dockerClient != nil {
-		volumes, err := dockerClient.ListVolumes(ctx)
+		volumes, err := dockerClient.ListManagedVolumes(ctx)
 		if err != nil {
 			fmt.Printf("  %s Failed to list volumes: %v\n", red("✗"), err)
 		} else {
 			for _, volume := range volumes {
-				// Only count volumes with "doku-" prefix
-				if strings.HasPrefix(volume.Name, "doku-") {
-					preservedVolumes = append(preservedVolumes, volume.Name)
-				}
+				preservedVolumes = append(preservedVolumes, volume.Name)
 			}
 			if len(preservedVolumes) > 0 {
 				fmt.Printf("  %s Preserved %d Docker volume(s) with your data\n", green("✓"), len(preservedVolumes))
@@ -212,7 +209,7 @@ func runUninstall(cmd *cobra.Command, args []string) error {
 	// Step 3: Remove Docker network
 	fmt.Printf("\n%s Removing Docker network...\n", cyan("→"))
 	if dockerClient != nil {
-		networkName := "doku-network"
+		networkName := docker.NetworkName()
 		if err := dockerClient.RemoveNetwork(ctx, networkName); err != nil {
 			if !strings.Contains(err.Error(), "not found") {
 				fmt.Printf("  %s Failed to remove network: %v\n", red("✗"), err)
@@ -291,6 +288,17 @@ func runUninstall(cmd *cobra.Command, args []string) error {
 			}
 		}
 
+		// Remove caddy directory, with Caddy's CA
+		caddyDir := cfgMgr.GetCaddyDir()
+		if _, err := os.Stat(caddyDir); err == nil {
+			if err := os.RemoveAll(caddyDir); err != nil {
+				fmt.Printf("  %s Failed to remove %s: %v\n", red("✗"), caddyDir, err)
+			} else {
+				fmt.Printf("  %s Removed %s\n", green("✓"), caddyDir)
+				cleaned = append(cleaned, "Caddy configuration")
+			}
+		}
+
 		// Remove catalog directory
 		catalogDir := filepath.Join(dokuDir, "catalog")
 		if _, err := os.Stat(catalogDir); err == nil {
@@ -432,6 +440,8 @@ func runUninstall(cmd *cobra.Command, args []string) error {
 		fmt.Printf("   %s\n", cyan("sudo rm -f /etc/resolver/doku.local"))
 	case "linux":
 		fmt.Printf("   %s\n", cyan("sudo sed -i '/doku.local/d' /etc/hosts"))
+		fmt.Println("   If using dnsmasq:")
+		fmt.Printf("   %s\n", cyan("sudo rm -f /etc/systemd/resolved.conf.d/doku-*.conf && sudo systemctl restart systemd-resolved"))
 	case "windows":
 		fmt.Printf("   %s\n", cyan(`notepad C:\Windows\System32\drivers\etc\hosts`))
 		fmt.Println("   Then manually remove lines containing 'doku.local'")
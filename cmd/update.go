@@ -120,6 +120,7 @@ func updateSingleService(instanceName string, cfgMgr *config.Manager, catalogMgr
 	if err != nil {
 		return fmt.Errorf("version '%s' not found for service '%s'", targetVersion, instance.ServiceType)
 	}
+	targetSpec = targetSpec.ForArch(dockerClient.Arch())
 
 	// Check if already on target version
 	if instance.Version == targetVersion {
@@ -207,6 +208,12 @@ func updateSingleService(instanceName string, cfgMgr *config.Manager, catalogMgr
 		Version:      targetVersion,
 		InstanceName: instanceName,
 		Environment:  instance.Environment,
+		Labels:       instance.Labels,
+		Logs:         instance.Logs,
+		Middlewares:  instance.Middlewares,
+		Domains:      instance.Domains,
+		TLSSANs:      instance.TLSSANs,
+		LAN:          instance.LAN,
 		MemoryLimit:  instance.Resources.MemoryLimit,
 		CPULimit:     instance.Resources.CPULimit,
 		Volumes:      instance.Volumes,
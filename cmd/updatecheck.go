@@ -0,0 +1,119 @@
+package cmd
+
+import (
+	"context"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/dokulabs/doku-cli/internal/config"
+	"github.com/dokulabs/doku-cli/internal/docker"
+	"github.com/dokulabs/doku-cli/internal/readonly"
+	"github.com/dokulabs/doku-cli/internal/updatecheck"
+	"github.com/dokulabs/doku-cli/pkg/types"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// updateCheckTimeout bounds the daily check for new releases. It runs
+// while the command does, so it only holds up commands that finish sooner.
+const updateCheckTimeout = 2 * time.Second
+
+// noUpdateCheck lists the commands that don't check for new releases
+var noUpdateCheck = map[string]bool{
+	"completion": true,
+	"help":       true,
+	"self":       true,
+	"upgrade":    true,
+}
+
+// pendingUpdateCheck is a check for new releases running alongside the
+// command
+type pendingUpdateCheck struct {
+	cancel   context.CancelFunc
+	done     chan struct{}
+	previous *types.UpdateCheckState
+	state    *types.UpdateCheckState
+}
+
+var updateCheck *pendingUpdateCheck
+
+// startUpdateCheck checks for new releases of Doku and the catalog in the
+// background, at most once a day. Like the automatic resync, it's skipped
+// for commands that don't need it, in scripts and in read-only mode.
+func startUpdateCheck(cmd *cobra.Command) {
+	if updatecheck.DisabledByEnv() || readonly.Enabled() || viper.GetBool("quiet") || !docker.IsTerminal(os.Stderr) {
+		return
+	}
+
+	top := cmd
+	for top.HasParent() && top.Parent().HasParent() {
+		top = top.Parent()
+	}
+	if !top.HasParent() || noUpdateCheck[top.Name()] || strings.HasPrefix(top.Name(), "__") {
+		return
+	}
+
+	cfgMgr, err := config.New()
+	if err != nil || !cfgMgr.IsInitialized() {
+		return
+	}
+	cfg, err := cfgMgr.Get()
+	if err != nil || cfg.Preferences.SkipUpdateCheck || !updatecheck.Due(cfg.UpdateCheck, time.Now()) {
+		return
+	}
+
+	checker := updatecheck.New()
+	if os.Getenv("DOKU_CATALOG_SOURCE") != "" || cfg.Preferences.CatalogPin != "" {
+		// A custom catalog has its own versions, and a pinned one isn't
+		// updated
+		checker.CatalogURL = ""
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), updateCheckTimeout)
+	check := &pendingUpdateCheck{
+		cancel:   cancel,
+		done:     make(chan struct{}),
+		previous: cfg.UpdateCheck,
+	}
+	go func() {
+		defer close(check.done)
+		// Failures are recorded as checked too, so being offline doesn't
+		// mean a check before every command
+		check.state, _ = checker.Check(ctx, check.previous)
+	}()
+	updateCheck = check
+}
+
+// finishUpdateCheck waits for the check started with the command, saves its
+// result and prints a line for each newer release
+func finishUpdateCheck() {
+	check := updateCheck
+	if check == nil {
+		return
+	}
+	defer check.cancel()
+
+	// The check gives up at its timeout
+	<-check.done
+
+	// The config is saved now rather than during the check, so that it
+	// doesn't overwrite changes the command made meanwhile
+	cfgMgr, err := config.New()
+	if err != nil || !cfgMgr.IsInitialized() || check.state == nil {
+		return
+	}
+	var catalogVersion string
+	if err := cfgMgr.Update(func(c *types.Config) error {
+		c.UpdateCheck = check.state
+		catalogVersion = c.Preferences.CatalogVersion
+		return nil
+	}); err != nil {
+		return
+	}
+
+	for _, notice := range updatecheck.Notices(check.state, version, catalogVersion) {
+		color.New(color.Faint).Fprintln(os.Stderr, notice)
+	}
+}
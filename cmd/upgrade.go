@@ -11,6 +11,8 @@ import (
 	"strings"
 
 	"github.com/AlecAivazis/survey/v2"
+	"github.com/dokulabs/doku-cli/internal/readonly"
+	"github.com/dokulabs/doku-cli/internal/timing"
 	"github.com/fatih/color"
 	"github.com/spf13/cobra"
 )
@@ -57,6 +59,10 @@ func init() {
 }
 
 func runUpgrade(cmd *cobra.Command, args []string) error {
+	if err := readonly.Check("upgrade Doku"); err != nil {
+		return err
+	}
+
 	fmt.Println()
 	color.New(color.Bold, color.FgCyan).Println("Doku Self-Upgrade")
 	fmt.Println()
@@ -237,6 +243,7 @@ func getLatestRelease(includePrerelease bool) (*GitHubRelease, error) {
 		apiURL = githubAPIURL
 	}
 
+	defer timing.Track(timing.Network, "fetch release info")()
 	resp, err := http.Get(apiURL)
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch release info: %w", err)
@@ -271,6 +278,7 @@ func getLatestRelease(includePrerelease bool) (*GitHubRelease, error) {
 }
 
 func downloadBinary(url string) (string, error) {
+	defer timing.Track(timing.Network, "download binary")()
 	resp, err := http.Get(url)
 	if err != nil {
 		return "", err
@@ -1,6 +1,11 @@
 package cmd
 
-import "strings"
+import (
+	"fmt"
+	"strings"
+
+	"github.com/dokulabs/doku-cli/internal/docker"
+)
 
 // isSensitiveKey checks if a key contains sensitive information
 func isSensitiveKey(key string) bool {
@@ -25,3 +30,58 @@ func maskValue(value string) string {
 	}
 	return value[:2] + strings.Repeat("*", len(value)-4) + value[len(value)-2:]
 }
+
+// parseLabels parses KEY=VALUE label flags, rejecting labels Doku sets itself
+func parseLabels(values []string) (map[string]string, error) {
+	if len(values) == 0 {
+		return nil, nil
+	}
+
+	labels := make(map[string]string, len(values))
+	for _, value := range values {
+		key, val, ok := strings.Cut(value, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid label format: %s (use KEY=VALUE)", value)
+		}
+		if err := docker.ValidateExtraLabel(key); err != nil {
+			return nil, err
+		}
+		labels[key] = val
+	}
+	return labels, nil
+}
+
+// parseDependencyInstances parses SERVICE=INSTANCE flags binding
+// dependencies to installed instances
+func parseDependencyInstances(values []string) (map[string]string, error) {
+	if len(values) == 0 {
+		return nil, nil
+	}
+
+	bound := make(map[string]string, len(values))
+	for _, value := range values {
+		dep, instance, ok := strings.Cut(value, "=")
+		if !ok || dep == "" || instance == "" {
+			return nil, fmt.Errorf("invalid dependency instance: %s (use SERVICE=INSTANCE, e.g. postgres=postgres-16)", value)
+		}
+		bound[dep] = instance
+	}
+	return bound, nil
+}
+
+// parseBuildArgs parses KEY=VALUE build argument flags
+func parseBuildArgs(values []string) (map[string]string, error) {
+	if len(values) == 0 {
+		return nil, nil
+	}
+
+	args := make(map[string]string, len(values))
+	for _, value := range values {
+		key, val, ok := strings.Cut(value, "=")
+		if !ok || key == "" {
+			return nil, fmt.Errorf("invalid build arg format: %s (use KEY=VALUE)", value)
+		}
+		args[key] = val
+	}
+	return args, nil
+}
@@ -0,0 +1,219 @@
+package cmd
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/dokulabs/doku-cli/internal/config"
+	"github.com/dokulabs/doku-cli/internal/events"
+	"github.com/dokulabs/doku-cli/pkg/types"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+var (
+	webhookFormat string
+	webhookEvents []string
+)
+
+var webhookCmd = &cobra.Command{
+	Use:   "webhook",
+	Short: "Manage webhooks notified of service events",
+	Long: `Manage webhooks that service events are POSTed to.
+
+Events are emitted when services are installed, started, stopped or
+removed, and when they fail: crash, keep restarting or can't be started.
+Failures of services running in the background are noticed by any doku
+command, or as they happen by 'doku events watch'.
+
+Formats:
+  json    The event: {"type", "service", "message", "time"}
+  slack   A message for a Slack incoming webhook
+
+Examples:
+  doku webhook add alerts https://hooks.slack.com/services/... --format slack --events fail
+  doku webhook add ci http://localhost:9000/doku
+  doku webhook list
+  doku webhook test alerts
+  doku webhook remove ci`,
+}
+
+var webhookAddCmd = &cobra.Command{
+	Use:   "add <name> <url>",
+	Short: "Add or replace a webhook",
+	Long: `Add a webhook, or replace the one of the same name.
+
+Example:
+  doku webhook add alerts https://hooks.slack.com/services/... --format slack --events fail,stop`,
+	Args: cobra.ExactArgs(2),
+	RunE: runWebhookAdd,
+}
+
+var webhookListCmd = &cobra.Command{
+	Use:     "list",
+	Short:   "List webhooks",
+	Aliases: []string{"ls"},
+	Args:    cobra.NoArgs,
+	RunE:    runWebhookList,
+}
+
+var webhookRemoveCmd = &cobra.Command{
+	Use:     "remove <name>",
+	Short:   "Delete a webhook",
+	Aliases: []string{"rm"},
+	Args:    cobra.ExactArgs(1),
+	RunE:    runWebhookRemove,
+}
+
+var webhookTestCmd = &cobra.Command{
+	Use:   "test <name>",
+	Short: "Send a test event to a webhook",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runWebhookTest,
+}
+
+func init() {
+	rootCmd.AddCommand(webhookCmd)
+
+	webhookCmd.AddCommand(webhookAddCmd)
+	webhookCmd.AddCommand(webhookListCmd)
+	webhookCmd.AddCommand(webhookRemoveCmd)
+	webhookCmd.AddCommand(webhookTestCmd)
+
+	webhookAddCmd.Flags().StringVar(&webhookFormat, "format", events.FormatJSON, "Payload format (json, slack)")
+	webhookAddCmd.Flags().StringSliceVar(&webhookEvents, "events", nil, "Event types to send: install, start, stop, remove, fail (default all)")
+}
+
+func runWebhookAdd(cmd *cobra.Command, args []string) error {
+	if webhookFormat != events.FormatJSON && webhookFormat != events.FormatSlack {
+		return fmt.Errorf("unknown format '%s' (expected json or slack)", webhookFormat)
+	}
+	for _, name := range webhookEvents {
+		if _, err := events.ParseType(name); err != nil {
+			return err
+		}
+	}
+
+	cfgMgr, err := initConfigManager()
+	if err != nil {
+		if err == types.ErrNotInitialized {
+			return nil
+		}
+		return err
+	}
+
+	hook := types.WebhookConfig{
+		Name:   args[0],
+		URL:    args[1],
+		Format: webhookFormat,
+		Events: webhookEvents,
+	}
+	if err := cfgMgr.SetWebhook(hook); err != nil {
+		return err
+	}
+
+	color.Green("✓ Webhook %s: %s events to %s", hook.Name, describeWebhookEvents(hook.Events), hook.URL)
+	fmt.Printf("Try it with: doku webhook test %s\n", hook.Name)
+	return nil
+}
+
+func runWebhookList(cmd *cobra.Command, args []string) error {
+	cfgMgr, err := initConfigManager()
+	if err != nil {
+		if err == types.ErrNotInitialized {
+			return nil
+		}
+		return err
+	}
+
+	hooks, err := cfgMgr.ListWebhooks()
+	if err != nil {
+		return err
+	}
+
+	if len(hooks) == 0 {
+		color.Yellow("No webhooks defined")
+		fmt.Println()
+		fmt.Println("Add one with: doku webhook add <name> <url>")
+		return nil
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
+	fmt.Fprintln(w, "NAME\tFORMAT\tEVENTS\tURL")
+	for _, hook := range hooks {
+		format := hook.Format
+		if format == "" {
+			format = events.FormatJSON
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", hook.Name, format, describeWebhookEvents(hook.Events), hook.URL)
+	}
+	w.Flush()
+
+	return nil
+}
+
+func runWebhookRemove(cmd *cobra.Command, args []string) error {
+	cfgMgr, err := initConfigManager()
+	if err != nil {
+		if err == types.ErrNotInitialized {
+			return nil
+		}
+		return err
+	}
+
+	if err := cfgMgr.RemoveWebhook(args[0]); err != nil {
+		return err
+	}
+
+	color.Green("✓ Webhook %s removed", args[0])
+	return nil
+}
+
+func runWebhookTest(cmd *cobra.Command, args []string) error {
+	cfgMgr, err := initConfigManager()
+	if err != nil {
+		if err == types.ErrNotInitialized {
+			return nil
+		}
+		return err
+	}
+
+	hook, err := cfgMgr.GetWebhook(args[0])
+	if err != nil {
+		return err
+	}
+
+	e := events.New(events.TypeFail, "test", "test notification from Doku")
+	if err := events.Send(&http.Client{Timeout: 10 * time.Second}, hook, e); err != nil {
+		return fmt.Errorf("webhook %s failed: %w", hook.Name, err)
+	}
+
+	color.Green("✓ Test event sent to %s", hook.URL)
+	return nil
+}
+
+// describeWebhookEvents describes the event types a webhook is sent
+func describeWebhookEvents(names []string) string {
+	if len(names) == 0 {
+		return "all"
+	}
+	return strings.Join(names, ",")
+}
+
+// subscribeWebhooks sends the events emitted by the command to the
+// configured webhooks. Doku not being set up isn't an error here.
+func subscribeWebhooks() {
+	cfgMgr, err := config.New()
+	if err != nil || !cfgMgr.IsInitialized() {
+		return
+	}
+	cfg, err := cfgMgr.Get()
+	if err != nil || len(cfg.Webhooks) == 0 {
+		return
+	}
+	events.Subscribe(events.WebhookHandler(cfg.Webhooks))
+}
@@ -0,0 +1,471 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/AlecAivazis/survey/v2"
+	"github.com/dokulabs/doku-cli/internal/caddy"
+	"github.com/dokulabs/doku-cli/internal/config"
+	"github.com/dokulabs/doku-cli/internal/dns"
+	"github.com/dokulabs/doku-cli/internal/docker"
+	"github.com/dokulabs/doku-cli/internal/proxy"
+	"github.com/dokulabs/doku-cli/internal/traefik"
+	"github.com/dokulabs/doku-cli/internal/workspace"
+	"github.com/dokulabs/doku-cli/pkg/types"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+var (
+	workspaceDomain    string
+	workspaceProtocol  string
+	workspaceSkipDNS   bool
+	workspaceHTTPPort  int
+	workspaceHTTPSPort int
+	workspaceBindIP    string
+	workspaceRemoveYes bool
+)
+
+var workspaceCmd = &cobra.Command{
+	Use:   "workspace",
+	Short: "Manage isolated workspaces",
+	Long: `Manage workspaces: separate Doku setups side by side, e.g. one per client.
+
+Each workspace has its own configuration directory, Docker network,
+container and volume names, domain (*.<workspace>.doku.local) and Traefik
+with its own ports, so its services never see those of another workspace.
+The setup in ~/.doku is the default workspace.
+
+Select a workspace with --workspace or the ` + workspace.EnvVar + ` variable. In a
+project directory, a .doku/workspace file holding the workspace name, or
+the workspace: key of a doku.yaml manifest, selects it for the commands
+run in that directory and below, like direnv scopes environments.
+
+Examples:
+  doku workspace create work                 # Set up the "work" workspace
+  doku --workspace work install postgres     # https://postgres.work.doku.local:8444
+  DOKU_WORKSPACE=work doku list              # Services of the workspace
+  doku workspace use work                    # Use it in the current directory
+  doku workspace current                     # Show the workspace in use
+  doku workspace list                        # All workspaces
+  doku workspace remove work                 # Delete the workspace`,
+}
+
+var workspaceCreateCmd = &cobra.Command{
+	Use:   "create <name>",
+	Short: "Create and initialize a workspace",
+	Long: `Create a workspace and initialize it like 'doku init' does.
+
+The workspace gets its own Docker network and Traefik. Its domain defaults
+to <name>.doku.local and its Traefik ports to free ones, e.g. 8001 and
+8444, since the default workspace's Traefik uses 80 and 443.
+
+With --bind-ip, its hostnames resolve to that address, and its Traefik and
+services publish their ports on it alone. "auto" picks a loopback alias of
+its own, e.g. 127.0.0.2. Once the default workspace is bound to 127.0.0.1
+too ('doku config set preferences.bindip 127.0.0.1'), the workspaces can
+publish the same ports, e.g. --http-port 80 --https-port 443.
+
+Examples:
+  doku workspace create work
+  doku workspace create acme --domain acme.local --protocol http
+  doku workspace create lab --http-port 9080 --https-port 9443
+  doku workspace create client --bind-ip auto`,
+	Args: cobra.ExactArgs(1),
+	RunE: runWorkspaceCreate,
+}
+
+var workspaceUseCmd = &cobra.Command{
+	Use:   "use <name>",
+	Short: "Select a workspace for the current directory",
+	Long: `Select a workspace for the commands run in the current directory and
+below, by writing its name to .doku/workspace. Commit the file to share the
+selection with the project. 'doku workspace use default' selects the default
+workspace, e.g. in a subdirectory of a project using another one.
+
+--workspace and ` + workspace.EnvVar + ` still take precedence.`,
+	Args: cobra.ExactArgs(1),
+	RunE: runWorkspaceUse,
+}
+
+var workspaceCurrentCmd = &cobra.Command{
+	Use:   "current",
+	Short: "Show the workspace in use and what selected it",
+	Args:  cobra.NoArgs,
+	RunE:  runWorkspaceCurrent,
+}
+
+var workspaceListCmd = &cobra.Command{
+	Use:     "list",
+	Short:   "List workspaces",
+	Aliases: []string{"ls"},
+	Args:    cobra.NoArgs,
+	RunE:    runWorkspaceList,
+}
+
+var workspaceRemoveCmd = &cobra.Command{
+	Use:   "remove <name>",
+	Short: "Delete a workspace",
+	Long: `Delete a workspace: its reverse proxy, Docker network, hosts file entries
+or dnsmasq, configuration and certificates.
+
+A workspace with services can't be removed: remove them first, or run
+'doku --workspace <name> uninstall'.`,
+	Aliases: []string{"rm"},
+	Args:    cobra.ExactArgs(1),
+	RunE:    runWorkspaceRemove,
+}
+
+func init() {
+	rootCmd.AddCommand(workspaceCmd)
+
+	workspaceCmd.AddCommand(workspaceCreateCmd)
+	workspaceCmd.AddCommand(workspaceUseCmd)
+	workspaceCmd.AddCommand(workspaceCurrentCmd)
+	workspaceCmd.AddCommand(workspaceListCmd)
+	workspaceCmd.AddCommand(workspaceRemoveCmd)
+
+	workspaceCreateCmd.Flags().StringVar(&workspaceDomain, "domain", "", "Domain to use for services (default <name>.doku.local)")
+	workspaceCreateCmd.Flags().StringVar(&workspaceProtocol, "protocol", "", "Protocol (http or https)")
+	workspaceCreateCmd.Flags().BoolVar(&workspaceSkipDNS, "skip-dns", false, "Skip DNS/hosts file configuration")
+	workspaceCreateCmd.Flags().IntVar(&workspaceHTTPPort, "http-port", 0, "Host port for HTTP traffic (default a free one)")
+	workspaceCreateCmd.Flags().IntVar(&workspaceHTTPSPort, "https-port", 0, "Host port for HTTPS traffic (default a free one)")
+	workspaceCreateCmd.Flags().StringVar(&workspaceBindIP, "bind-ip", "", "Address the workspace binds to, or auto for a loopback alias of its own")
+
+	workspaceRemoveCmd.Flags().BoolVarP(&workspaceRemoveYes, "yes", "y", false, "Skip confirmation prompt")
+
+	workspaceRemoveCmd.ValidArgsFunction = completeWorkspaces
+	workspaceUseCmd.ValidArgsFunction = completeWorkspaces
+	_ = rootCmd.RegisterFlagCompletionFunc("workspace", completeWorkspaces)
+}
+
+// workspaceSource tells what selected the workspace: the flag, the
+// environment or a marker file
+var workspaceSource string
+
+// resolveWorkspace returns the workspace selected by --workspace, the
+// environment or the current directory, in that order, and what selected it
+func resolveWorkspace() (name, source string, err error) {
+	if workspaceName != "" {
+		return workspaceName, "--workspace", nil
+	}
+	if name := os.Getenv(workspace.EnvVar); name != "" {
+		return name, workspace.EnvVar, nil
+	}
+
+	cwd, err := os.Getwd()
+	if err != nil {
+		return "", "", nil
+	}
+	baseDir, err := config.BaseDir()
+	if err != nil {
+		return "", "", err
+	}
+	return workspace.Detect(cwd, baseDir)
+}
+
+// selectWorkspace selects the workspace of --workspace, the environment or
+// the current directory for the rest of the command. A workspace must have
+// been created first, except for the commands that manage workspaces.
+func selectWorkspace(cmd *cobra.Command) error {
+	name, source, err := resolveWorkspace()
+	if err != nil {
+		return err
+	}
+	if err := workspace.Set(name); err != nil {
+		return err
+	}
+	workspaceSource = source
+	if workspace.Current() == "" {
+		return nil
+	}
+
+	top := cmd
+	for top.HasParent() && top.Parent().HasParent() {
+		top = top.Parent()
+	}
+	switch top.Name() {
+	case "workspace", "completion", "help", "version":
+		return nil
+	}
+
+	baseDir, err := config.BaseDir()
+	if err != nil {
+		return err
+	}
+	if _, err := os.Stat(workspace.Path(baseDir, workspace.Current())); os.IsNotExist(err) {
+		return fmt.Errorf("workspace '%s' (selected by %s) doesn't exist. Create it with: doku workspace create %s",
+			workspace.Current(), workspaceSource, workspace.Current())
+	}
+	return nil
+}
+
+func runWorkspaceCreate(cmd *cobra.Command, args []string) error {
+	name := args[0]
+	if err := workspace.Validate(name); err != nil {
+		return err
+	}
+
+	baseDir, err := config.BaseDir()
+	if err != nil {
+		return err
+	}
+	if _, err := os.Stat(workspace.Path(baseDir, name)); err == nil {
+		return fmt.Errorf("workspace '%s' already exists. Reinitialize it with: doku --workspace %s init", name, name)
+	}
+	if workspaceBindIP != "auto" {
+		if err := config.ValidateBindIP(workspaceBindIP); err != nil {
+			return err
+		}
+	}
+
+	// The network must not overlap with any other Docker network, nor
+	// with those of the workspaces not set up yet
+	dockerClient, err := docker.NewClient()
+	if err != nil {
+		return fmt.Errorf("Docker is not available: %w", err)
+	}
+	networks, err := dockerClient.NetworkList()
+	dockerClient.Close()
+	if err != nil {
+		return err
+	}
+	var used []string
+	for _, n := range networks {
+		for _, ipam := range n.IPAM.Config {
+			used = append(used, ipam.Subnet)
+		}
+	}
+	others, err := workspace.List(baseDir)
+	if err != nil {
+		return err
+	}
+	for _, other := range others {
+		if cfg := workspaceConfig(baseDir, other); cfg != nil {
+			used = append(used, cfg.Network.Subnet)
+		}
+	}
+
+	slot, err := workspace.FreeSlot(used)
+	if err != nil {
+		return err
+	}
+	if err := workspace.Set(name); err != nil {
+		return err
+	}
+
+	// Initialize the workspace as doku init would, with its own network
+	// and ports
+	initDomain, initProtocol, initSkipDNS = workspaceDomain, workspaceProtocol, workspaceSkipDNS
+	if initDomain == "" {
+		initDomain = config.DefaultDomain // runInit prefixes it with the workspace name
+	}
+	initHTTPPort, initHTTPSPort = workspace.Ports(slot)
+	initBindIP = workspaceBindIP
+	if initBindIP == "auto" {
+		initBindIP = workspace.LoopbackIP(slot)
+	}
+	if workspaceHTTPPort != 0 {
+		initHTTPPort = workspaceHTTPPort
+	}
+	if workspaceHTTPSPort != 0 {
+		initHTTPSPort = workspaceHTTPSPort
+	}
+	initNetworkSubnet, initNetworkGateway = workspace.Subnet(slot)
+	if err := runInit(cmd, args); err != nil {
+		return err
+	}
+
+	fmt.Println()
+	color.Cyan("Use the workspace with:")
+	fmt.Printf("  doku --workspace %s install <service>\n", name)
+	fmt.Printf("  export %s=%s\n", workspace.EnvVar, name)
+	return nil
+}
+
+func runWorkspaceUse(cmd *cobra.Command, args []string) error {
+	name := args[0]
+	if name != "default" {
+		baseDir, err := config.BaseDir()
+		if err != nil {
+			return err
+		}
+		if err := workspace.Validate(name); err != nil {
+			return err
+		}
+		if _, err := os.Stat(workspace.Path(baseDir, name)); os.IsNotExist(err) {
+			return fmt.Errorf("workspace '%s' doesn't exist. Create it with: doku workspace create %s", name, name)
+		}
+	}
+
+	cwd, err := os.Getwd()
+	if err != nil {
+		return err
+	}
+	path, err := workspace.WriteMarker(cwd, name)
+	if err != nil {
+		return err
+	}
+
+	color.Green("✓ Commands run in %s now use workspace %s", cwd, name)
+	color.New(color.Faint).Printf("  Written to %s\n", path)
+	if os.Getenv(workspace.EnvVar) != "" {
+		color.Yellow("⚠️  %s is set and takes precedence over the file", workspace.EnvVar)
+	}
+	return nil
+}
+
+func runWorkspaceCurrent(cmd *cobra.Command, args []string) error {
+	if workspaceSource == "" {
+		fmt.Println(workspace.DisplayName())
+		return nil
+	}
+	fmt.Printf("%s (selected by %s)\n", workspace.DisplayName(), workspaceSource)
+	return nil
+}
+
+func runWorkspaceList(cmd *cobra.Command, args []string) error {
+	baseDir, err := config.BaseDir()
+	if err != nil {
+		return err
+	}
+	names, err := workspace.List(baseDir)
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
+	fmt.Fprintln(w, "WORKSPACE\tDOMAIN\tPORTS\tSERVICES")
+	for _, name := range append([]string{""}, names...) {
+		marker := "  "
+		if name == workspace.Current() {
+			marker = "* "
+		}
+		display := name
+		if display == "" {
+			display = "default"
+		}
+
+		cfg := workspaceConfig(baseDir, name)
+		if cfg == nil {
+			fmt.Fprintf(w, "%s%s\t-\t-\tnot initialized\n", marker, display)
+			continue
+		}
+		fmt.Fprintf(w, "%s%s\t%s\t%d, %d\t%d\n", marker, display, cfg.Preferences.Domain,
+			cfg.Traefik.HTTPPort, cfg.Traefik.HTTPSPort, len(cfg.Instances))
+	}
+	w.Flush()
+
+	return nil
+}
+
+func runWorkspaceRemove(cmd *cobra.Command, args []string) error {
+	name := args[0]
+	if err := workspace.Validate(name); err != nil {
+		return err
+	}
+
+	baseDir, err := config.BaseDir()
+	if err != nil {
+		return err
+	}
+	dir := workspace.Path(baseDir, name)
+	if _, err := os.Stat(dir); os.IsNotExist(err) {
+		return fmt.Errorf("workspace '%s' doesn't exist. Use 'doku workspace list' to see workspaces", name)
+	}
+
+	cfg := workspaceConfig(baseDir, name)
+	if cfg != nil && len(cfg.Instances) > 0 {
+		return fmt.Errorf("workspace '%s' has %d service(s). Remove them first, or run: doku --workspace %s uninstall",
+			name, len(cfg.Instances), name)
+	}
+
+	if !workspaceRemoveYes {
+		confirm := false
+		prompt := &survey.Confirm{
+			Message: fmt.Sprintf("Delete workspace %s and its configuration in %s?", name, dir),
+			Default: false,
+		}
+		if err := survey.AskOne(prompt, &confirm); err != nil {
+			return fmt.Errorf("failed to get user confirmation: %w", err)
+		}
+		if !confirm {
+			color.Yellow("⚠️  Removal cancelled")
+			return nil
+		}
+	}
+
+	// The Docker resources are named after the workspace
+	if err := workspace.Set(name); err != nil {
+		return err
+	}
+
+	if dockerClient, err := docker.NewClient(); err != nil {
+		color.Yellow("⚠️  Docker is not available, the workspace's reverse proxy and network are left: %v", err)
+	} else {
+		defer dockerClient.Close()
+
+		for _, reverseProxy := range []proxy.Proxy{
+			traefik.NewManager(dockerClient, "", "", "", ""),
+			caddy.NewManager(dockerClient, "", ""),
+		} {
+			if err := reverseProxy.RemoveContainer(); err != nil {
+				color.Yellow("⚠️  Failed to remove %s: %v", reverseProxy.ContainerName(), err)
+			}
+		}
+		if cfg != nil && cfg.Preferences.DNSSetup == "dnsmasq" {
+			removeWildcardDNS(dockerClient, cfg)
+		}
+		if err := dockerClient.RemoveNetwork(cmd.Context(), docker.NetworkName()); err != nil && !strings.Contains(err.Error(), "not found") {
+			color.Yellow("⚠️  Failed to remove network %s: %v", docker.NetworkName(), err)
+		}
+	}
+
+	if cfg != nil && cfg.Preferences.DNSSetup == "hosts" {
+		dnsMgr := dns.NewManagerForContext(cfg.Preferences.Context)
+		if err := dnsMgr.RemoveDokuEntries(); err != nil {
+			color.Yellow("⚠️  Failed to remove the hosts file entries of %s: %v", name, err)
+		}
+	}
+
+	if err := os.RemoveAll(dir); err != nil {
+		return fmt.Errorf("failed to remove %s: %w", dir, err)
+	}
+
+	color.Green("✓ Workspace %s removed", name)
+	return nil
+}
+
+// completeWorkspaces completes the names of the created workspaces
+func completeWorkspaces(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) > 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	baseDir, err := config.BaseDir()
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	names, _ := workspace.List(baseDir)
+	return names, cobra.ShellCompDirectiveNoFileComp
+}
+
+// workspaceConfig reads the configuration of a workspace ("" = the default
+// one), nil if it isn't initialized
+func workspaceConfig(baseDir, name string) *types.Config {
+	dir := baseDir
+	if name != "" {
+		dir = workspace.Path(baseDir, name)
+	}
+	cfgMgr, err := config.NewWithCustomPath(dir)
+	if err != nil || !cfgMgr.IsInitialized() {
+		return nil
+	}
+	cfg, err := cfgMgr.Get()
+	if err != nil {
+		return nil
+	}
+	return cfg
+}
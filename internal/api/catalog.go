@@ -0,0 +1,32 @@
+package api
+
+import (
+	"net/http"
+	"sort"
+)
+
+func (s *Server) handleListCatalog(w http.ResponseWriter, r *http.Request) {
+	services, err := s.catalogMgr.ListServices()
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	sort.Slice(services, func(i, j int) bool {
+		return services[i].Name < services[j].Name
+	})
+
+	entries := make([]CatalogService, 0, len(services))
+	for _, service := range services {
+		entries = append(entries, catalogServiceFrom(service))
+	}
+	writeJSON(w, http.StatusOK, entries)
+}
+
+func (s *Server) handleGetCatalogService(w http.ResponseWriter, r *http.Request) {
+	service, err := s.catalogMgr.GetService(r.PathValue("name"))
+	if err != nil {
+		writeError(w, http.StatusNotFound, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, catalogServiceFrom(service))
+}
@@ -0,0 +1,18 @@
+package api
+
+import (
+	_ "embed"
+	"net/http"
+)
+
+// dashboardPage is the web dashboard: a single page listing the services,
+// with their logs and start/stop/restart/remove actions, using the API
+//
+//go:embed dashboard/index.html
+var dashboardPage []byte
+
+func (s *Server) handleDashboard(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Header().Set("Content-Security-Policy", "default-src 'self'; style-src 'unsafe-inline'; script-src 'unsafe-inline'")
+	_, _ = w.Write(dashboardPage)
+}
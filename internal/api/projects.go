@@ -0,0 +1,92 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"path"
+	"sort"
+)
+
+func (s *Server) handleListProjects(w http.ResponseWriter, r *http.Request) {
+	list, err := s.configMgr.ListProjects()
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	projectMgr, err := s.projectManager()
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].Name < list[j].Name
+	})
+
+	projects := make([]Project, 0, len(list))
+	for _, project := range list {
+		p := projectFrom(project)
+		if status, err := projectMgr.GetStatus(project.Name); err == nil {
+			p.Status = string(status)
+		}
+		projects = append(projects, p)
+	}
+	writeJSON(w, http.StatusOK, projects)
+}
+
+func (s *Server) handleGetProject(w http.ResponseWriter, r *http.Request) {
+	name := r.PathValue("name")
+	project, err := s.configMgr.GetProject(name)
+	if err != nil {
+		writeError(w, http.StatusNotFound, fmt.Errorf("project '%s' not found", name))
+		return
+	}
+
+	p := projectFrom(project)
+	if projectMgr, err := s.projectManager(); err == nil {
+		if status, err := projectMgr.GetStatus(name); err == nil {
+			p.Status = string(status)
+		}
+	}
+	writeJSON(w, http.StatusOK, p)
+}
+
+// handleProjectAction starts, stops or restarts a project, after the last
+// element of the path
+func (s *Server) handleProjectAction(w http.ResponseWriter, r *http.Request) {
+	name := r.PathValue("name")
+	if _, err := s.configMgr.GetProject(name); err != nil {
+		writeError(w, http.StatusNotFound, fmt.Errorf("project '%s' not found", name))
+		return
+	}
+
+	if !s.lock(w) {
+		return
+	}
+	defer s.mu.Unlock()
+
+	projectMgr, err := s.projectManager()
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	switch path.Base(r.URL.Path) {
+	case "start":
+		err = projectMgr.Start(name)
+	case "stop":
+		err = projectMgr.Stop(name)
+	case "restart":
+		err = projectMgr.Restart(name)
+	}
+	if err != nil {
+		writeError(w, errorStatus(err), err)
+		return
+	}
+
+	project, err := s.configMgr.GetProject(name)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, projectFrom(project))
+}
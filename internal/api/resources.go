@@ -0,0 +1,117 @@
+package api
+
+import (
+	"time"
+
+	"github.com/dokulabs/doku-cli/internal/catalog"
+	"github.com/dokulabs/doku-cli/pkg/types"
+)
+
+// Service is an installed service instance. Environment variables aren't
+// included, as they hold credentials.
+type Service struct {
+	Name         string      `json:"name"`
+	Service      string      `json:"service"`
+	Version      string      `json:"version"`
+	Status       string      `json:"status"`
+	DesiredState string      `json:"desired_state,omitempty"`
+	URL          string      `json:"url,omitempty"`
+	Containers   []Container `json:"containers"`
+	Dependencies []string    `json:"dependencies,omitempty"`
+	CreatedAt    time.Time   `json:"created_at"`
+}
+
+// Container is one of the containers of a service or project
+type Container struct {
+	Name    string `json:"name"`
+	Primary bool   `json:"primary,omitempty"`
+	Image   string `json:"image,omitempty"`
+	Status  string `json:"status,omitempty"`
+}
+
+// CatalogService is a service of the catalog
+type CatalogService struct {
+	Name        string   `json:"name"`
+	Description string   `json:"description"`
+	Category    string   `json:"category"`
+	Icon        string   `json:"icon,omitempty"`
+	Tags        []string `json:"tags,omitempty"`
+	Versions    []string `json:"versions"` // Newest first
+}
+
+// Project is a project built and run by Doku
+type Project struct {
+	Name       string      `json:"name"`
+	Path       string      `json:"path"`
+	Status     string      `json:"status"`
+	URL        string      `json:"url,omitempty"`
+	Port       int         `json:"port,omitempty"`
+	Containers []Container `json:"containers"`
+	CreatedAt  time.Time   `json:"created_at"`
+}
+
+// InstallRequest is the body of POST /v1/services
+type InstallRequest struct {
+	Service     string            `json:"service"`
+	Version     string            `json:"version,omitempty"`
+	Name        string            `json:"name,omitempty"`
+	Environment map[string]string `json:"environment,omitempty"`
+	MemoryLimit string            `json:"memory_limit,omitempty"`
+	CPULimit    string            `json:"cpu_limit,omitempty"`
+	Internal    bool              `json:"internal,omitempty"`
+}
+
+func serviceFromInstance(instance *types.Instance) Service {
+	svc := Service{
+		Name:         instance.Name,
+		Service:      instance.ServiceType,
+		Version:      instance.Version,
+		Status:       string(instance.Status),
+		DesiredState: string(instance.DesiredState),
+		URL:          instance.URL,
+		Dependencies: instance.Dependencies,
+		CreatedAt:    instance.CreatedAt,
+	}
+	if instance.IsMultiContainer {
+		svc.Containers = containersFromInfo(instance.Containers)
+	} else {
+		svc.Containers = []Container{{Name: instance.ContainerName, Primary: true, Status: string(instance.Status)}}
+	}
+	return svc
+}
+
+func catalogServiceFrom(service *types.CatalogService) CatalogService {
+	return CatalogService{
+		Name:        service.Name,
+		Description: service.Description,
+		Category:    service.Category,
+		Icon:        service.Icon,
+		Tags:        service.Tags,
+		Versions:    catalog.SortedVersions(service),
+	}
+}
+
+func projectFrom(project *types.Project) Project {
+	p := Project{
+		Name:      project.Name,
+		Path:      project.Path,
+		Status:    string(project.Status),
+		URL:       project.URL,
+		Port:      project.Port,
+		CreatedAt: project.CreatedAt,
+	}
+	if project.IsCompose() {
+		p.Containers = containersFromInfo(project.Containers)
+	} else {
+		p.Containers = []Container{{Name: project.ContainerName, Primary: true, Status: string(project.Status)}}
+	}
+	return p
+}
+
+func containersFromInfo(infos []types.ContainerInfo) []Container {
+	containers := make([]Container, 0, len(infos))
+	for _, c := range infos {
+		containers = append(containers, Container{Name: c.FullName, Primary: c.Primary, Image: c.Image, Status: c.Status})
+	}
+	return containers
+}
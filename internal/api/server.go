@@ -0,0 +1,271 @@
+// Package api serves the service manager, installer, catalog and project
+// manager over a local HTTP API ('doku serve'), so editors and GUIs can
+// drive Doku without shelling out to the CLI. Requests and responses are
+// JSON; errors are {"error": "..."} with a matching status code. The web
+// dashboard, built on the API, is served at /.
+package api
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/dokulabs/doku-cli/internal/catalog"
+	"github.com/dokulabs/doku-cli/internal/config"
+	"github.com/dokulabs/doku-cli/internal/docker"
+	"github.com/dokulabs/doku-cli/internal/project"
+	"github.com/dokulabs/doku-cli/internal/service"
+	"github.com/dokulabs/doku-cli/pkg/types"
+)
+
+// shutdownTimeout bounds how long Serve waits for requests in flight
+const shutdownTimeout = 10 * time.Second
+
+// Server handles the API requests
+type Server struct {
+	dockerClient *docker.Client
+	configMgr    *config.Manager
+	catalogMgr   *catalog.Manager
+	token        string
+	hosts        map[string]bool // Host headers accepted, any when empty
+
+	// mu serializes the requests that change services or projects, as
+	// the CLI commands behind them expect to run one at a time
+	mu sync.Mutex
+
+	// watching is set while Serve follows Docker events, which keep the
+	// recorded statuses up to date
+	watching atomic.Bool
+}
+
+// NewServer creates an API server
+func NewServer(dockerClient *docker.Client, configMgr *config.Manager, catalogMgr *catalog.Manager) *Server {
+	return &Server{
+		dockerClient: dockerClient,
+		configMgr:    configMgr,
+		catalogMgr:   catalogMgr,
+	}
+}
+
+// SetToken requires requests to carry "Authorization: Bearer <token>"
+// (empty = no authentication)
+func (s *Server) SetToken(token string) *Server {
+	s.token = token
+	return s
+}
+
+// NewToken returns a random token, for when 'doku serve' isn't given one
+func NewToken() (string, error) {
+	b := make([]byte, 24)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate a token: %w", err)
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// AllowHosts accepts requests for the given hosts, "host:port" or "host"
+// for any port, and rejects those for others once one is set. Serve allows
+// the address it listens on.
+func (s *Server) AllowHosts(hosts ...string) *Server {
+	if s.hosts == nil {
+		s.hosts = make(map[string]bool)
+	}
+	for _, host := range hosts {
+		s.hosts[strings.ToLower(host)] = true
+	}
+	return s
+}
+
+// allowedHost reports whether a request's Host header is one the API is
+// served at. Pages of other sites can't reach the API through names of
+// theirs resolving to the machine (DNS rebinding), which they control.
+func (s *Server) allowedHost(host string) bool {
+	if len(s.hosts) == 0 {
+		return true
+	}
+	host = strings.ToLower(host)
+	if s.hosts[host] {
+		return true
+	}
+	name, _, err := net.SplitHostPort(host)
+	if err != nil {
+		name = host
+	}
+	return s.hosts[name]
+}
+
+// listenHosts returns the hosts the API listening on addr is reached at:
+// the address, localhost, and the machine's addresses when it listens on
+// all of them
+func listenHosts(addr string) []string {
+	host, port, err := net.SplitHostPort(addr)
+	if err != nil {
+		return nil
+	}
+	hosts := []string{addr, net.JoinHostPort("localhost", port), net.JoinHostPort("127.0.0.1", port), net.JoinHostPort("::1", port)}
+	if ip := net.ParseIP(host); host == "" || (ip != nil && ip.IsUnspecified()) {
+		if addrs, err := net.InterfaceAddrs(); err == nil {
+			for _, a := range addrs {
+				if ipNet, ok := a.(*net.IPNet); ok {
+					hosts = append(hosts, net.JoinHostPort(ipNet.IP.String(), port))
+				}
+			}
+		}
+	}
+	return hosts
+}
+
+// Handler returns the HTTP handler of the API
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("GET /{$}", s.handleDashboard)
+	mux.HandleFunc("GET /v1/health", s.handleHealth)
+
+	mux.HandleFunc("GET /v1/services", s.handleListServices)
+	mux.HandleFunc("POST /v1/services", s.handleInstall)
+	mux.HandleFunc("GET /v1/services/{name}", s.handleGetService)
+	mux.HandleFunc("DELETE /v1/services/{name}", s.handleRemoveService)
+	mux.HandleFunc("POST /v1/services/{name}/start", s.handleServiceAction)
+	mux.HandleFunc("POST /v1/services/{name}/stop", s.handleServiceAction)
+	mux.HandleFunc("POST /v1/services/{name}/restart", s.handleServiceAction)
+	mux.HandleFunc("GET /v1/services/{name}/logs", s.handleServiceLogs)
+
+	mux.HandleFunc("GET /v1/catalog", s.handleListCatalog)
+	mux.HandleFunc("GET /v1/catalog/{name}", s.handleGetCatalogService)
+
+	mux.HandleFunc("GET /v1/projects", s.handleListProjects)
+	mux.HandleFunc("GET /v1/projects/{name}", s.handleGetProject)
+	mux.HandleFunc("POST /v1/projects/{name}/start", s.handleProjectAction)
+	mux.HandleFunc("POST /v1/projects/{name}/stop", s.handleProjectAction)
+	mux.HandleFunc("POST /v1/projects/{name}/restart", s.handleProjectAction)
+
+	return s.authenticate(mux)
+}
+
+// Serve serves the API on addr until ctx is done. Statuses are kept up to
+// date from Docker events meanwhile.
+func (s *Server) Serve(ctx context.Context, addr string) error {
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+
+	s.AllowHosts(listenHosts(addr)...)
+	s.AllowHosts(listenHosts(listener.Addr().String())...)
+
+	go s.serviceManager().Watch(ctx, nil)
+	s.watching.Store(true)
+	defer s.watching.Store(false)
+
+	srv := &http.Server{
+		Handler:           s.Handler(),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- srv.Serve(listener)
+	}()
+
+	select {
+	case err := <-errCh:
+		return err
+	case <-ctx.Done():
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+		defer cancel()
+		return srv.Shutdown(shutdownCtx)
+	}
+}
+
+// authenticate rejects API requests without the token, when one is set.
+// The dashboard page holds no data and asks for the token itself. Requests
+// for hosts the API isn't served at, and changes requested by pages of
+// other sites, are always rejected, as browsers send them without asking,
+// token or not.
+func (s *Server) authenticate(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !s.allowedHost(r.Host) {
+			writeError(w, http.StatusForbidden, fmt.Errorf("unknown host %q", r.Host))
+			return
+		}
+		if origin := r.Header.Get("Origin"); origin != "" && r.Method != http.MethodGet {
+			if u, err := url.Parse(origin); err != nil || u.Host != r.Host {
+				writeError(w, http.StatusForbidden, errors.New("cross-origin requests are not allowed"))
+				return
+			}
+		}
+		if s.token != "" && r.URL.Path != "/" {
+			given, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+			if !ok || subtle.ConstantTimeCompare([]byte(given), []byte(s.token)) != 1 {
+				writeError(w, http.StatusUnauthorized, errors.New("missing or invalid token"))
+				return
+			}
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// lock serializes a request changing services or projects. The config is
+// reloaded first, so that saving it doesn't undo the changes other doku
+// commands made since.
+func (s *Server) lock(w http.ResponseWriter) bool {
+	s.mu.Lock()
+	if _, err := s.configMgr.Load(); err != nil {
+		s.mu.Unlock()
+		writeError(w, http.StatusInternalServerError, err)
+		return false
+	}
+	return true
+}
+
+func (s *Server) serviceManager() *service.Manager {
+	return service.NewManager(s.dockerClient, s.configMgr)
+}
+
+func (s *Server) projectManager() (*project.Manager, error) {
+	return project.NewManager(s.dockerClient, s.configMgr)
+}
+
+func (s *Server) handleHealth(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]string{"status": "ok"})
+}
+
+// writeJSON writes v as the JSON response body
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+// writeError writes err as a JSON error response
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, map[string]string{"error": err.Error()})
+}
+
+// errorStatus maps the errors of the managers to HTTP status codes
+func errorStatus(err error) int {
+	switch {
+	case errors.Is(err, types.ErrServiceNotFound), errors.Is(err, types.ErrProjectNotFound),
+		errors.Is(err, types.ErrVersionNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, types.ErrAlreadyRunning), errors.Is(err, types.ErrAlreadyStopped),
+		errors.Is(err, types.ErrProjectExists):
+		return http.StatusConflict
+	case errors.Is(err, types.ErrReadOnly):
+		return http.StatusForbidden
+	default:
+		return http.StatusInternalServerError
+	}
+}
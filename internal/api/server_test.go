@@ -0,0 +1,205 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/dokulabs/doku-cli/internal/catalog"
+	"github.com/dokulabs/doku-cli/internal/config"
+	"github.com/dokulabs/doku-cli/pkg/types"
+)
+
+func newTestServer(t *testing.T) *Server {
+	t.Helper()
+	cfgMgr, err := config.NewWithCustomPath(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewWithCustomPath failed: %v", err)
+	}
+	if err := cfgMgr.Initialize(); err != nil {
+		t.Fatalf("Initialize failed: %v", err)
+	}
+	return NewServer(nil, cfgMgr, catalog.NewManager(cfgMgr.GetCatalogDir()))
+}
+
+func request(t *testing.T, handler http.Handler, method, target, body, token string) *httptest.ResponseRecorder {
+	t.Helper()
+	req := httptest.NewRequest(method, target, strings.NewReader(body))
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestAuthentication(t *testing.T) {
+	handler := newTestServer(t).SetToken("secret").Handler()
+
+	tests := []struct {
+		token string
+		want  int
+	}{
+		{"", http.StatusUnauthorized},
+		{"wrong", http.StatusUnauthorized},
+		{"secret", http.StatusOK},
+	}
+	for _, tt := range tests {
+		if rec := request(t, handler, "GET", "/v1/health", "", tt.token); rec.Code != tt.want {
+			t.Errorf("token %q: status = %d, expected %d", tt.token, rec.Code, tt.want)
+		}
+	}
+}
+
+func TestCrossOrigin(t *testing.T) {
+	handler := newTestServer(t).Handler()
+
+	tests := []struct {
+		method, origin string
+		want           int
+	}{
+		{"POST", "http://evil.example", http.StatusForbidden},
+		{"POST", "http://example.com", http.StatusNotFound}, // Same origin: reaches the handler
+		{"POST", "", http.StatusNotFound},
+		{"GET", "http://evil.example", http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest(tt.method, "/v1/services/nope/stop", nil)
+		if tt.method == "GET" {
+			req = httptest.NewRequest(tt.method, "/v1/services/nope", nil)
+		}
+		if tt.origin != "" {
+			req.Header.Set("Origin", tt.origin)
+		}
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		if rec.Code != tt.want {
+			t.Errorf("%s with Origin %q: status = %d, expected %d", tt.method, tt.origin, rec.Code, tt.want)
+		}
+	}
+}
+
+func TestForeignHost(t *testing.T) {
+	handler := newTestServer(t).SetToken("secret").AllowHosts(listenHosts("127.0.0.1:7070")...).AllowHosts("dashboard.doku.local").Handler()
+
+	tests := []struct {
+		host string
+		want int
+	}{
+		{"127.0.0.1:7070", http.StatusOK},
+		{"localhost:7070", http.StatusOK},
+		{"dashboard.doku.local", http.StatusOK},
+		{"dashboard.doku.local:8443", http.StatusOK},
+		{"evil.example:7070", http.StatusForbidden}, // Resolves to 127.0.0.1 (DNS rebinding)
+		{"localhost:8080", http.StatusForbidden},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest("GET", "/v1/health", nil)
+		req.Host = tt.host
+		req.Header.Set("Authorization", "Bearer secret")
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		if rec.Code != tt.want {
+			t.Errorf("Host %q: status = %d, expected %d", tt.host, rec.Code, tt.want)
+		}
+	}
+}
+
+func TestDashboard(t *testing.T) {
+	handler := newTestServer(t).SetToken("secret").Handler()
+
+	rec := request(t, handler, "GET", "/", "", "")
+	if rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), "<title>Doku</title>") {
+		t.Errorf("GET / = %d, expected the dashboard page", rec.Code)
+	}
+	if rec := request(t, handler, "GET", "/nope", "", "secret"); rec.Code != http.StatusNotFound {
+		t.Errorf("GET /nope = %d, expected 404", rec.Code)
+	}
+}
+
+func TestErrorResponses(t *testing.T) {
+	handler := newTestServer(t).Handler()
+
+	tests := []struct {
+		method, target, body string
+		want                 int
+	}{
+		{"GET", "/v1/services/nope", "", http.StatusNotFound},
+		{"POST", "/v1/services/nope/start", "", http.StatusNotFound},
+		{"DELETE", "/v1/services/nope", "", http.StatusNotFound},
+		{"GET", "/v1/services/nope/logs", "", http.StatusNotFound},
+		{"GET", "/v1/projects/nope", "", http.StatusNotFound},
+		{"POST", "/v1/projects/nope/stop", "", http.StatusNotFound},
+		{"GET", "/v1/catalog/nope", "", http.StatusNotFound},
+		{"POST", "/v1/services", "{", http.StatusBadRequest},
+		{"POST", "/v1/services", `{"version": "16"}`, http.StatusBadRequest},
+		{"POST", "/v1/services", `{"service": "nope"}`, http.StatusNotFound},
+		{"PUT", "/v1/services/nope", "", http.StatusMethodNotAllowed},
+	}
+	for _, tt := range tests {
+		rec := request(t, handler, tt.method, tt.target, tt.body, "")
+		if rec.Code != tt.want {
+			t.Errorf("%s %s: status = %d, expected %d", tt.method, tt.target, rec.Code, tt.want)
+			continue
+		}
+		if tt.want == http.StatusMethodNotAllowed {
+			continue
+		}
+		var body map[string]string
+		if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil || body["error"] == "" {
+			t.Errorf("%s %s: body = %s, expected a JSON error", tt.method, tt.target, rec.Body.String())
+		}
+	}
+}
+
+func TestLockReloadsConfig(t *testing.T) {
+	s := newTestServer(t)
+	if _, err := s.configMgr.Get(); err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+
+	// Another doku command changes the config meanwhile
+	other, err := config.NewWithCustomPath(s.configMgr.GetDokuDir())
+	if err != nil {
+		t.Fatalf("NewWithCustomPath failed: %v", err)
+	}
+	if err := other.SetDomain("other.local"); err != nil {
+		t.Fatalf("SetDomain failed: %v", err)
+	}
+
+	if !s.lock(httptest.NewRecorder()) {
+		t.Fatal("lock failed")
+	}
+	defer s.mu.Unlock()
+	if domain, _ := s.configMgr.GetDomain(); domain != "other.local" {
+		t.Errorf("domain = %q, expected the change of the other command to be loaded", domain)
+	}
+}
+
+func TestLogsContainer(t *testing.T) {
+	instance := &types.Instance{
+		Name:             "signoz",
+		IsMultiContainer: true,
+		Containers: []types.ContainerInfo{
+			{Name: "query", FullName: "doku-signoz-query"},
+			{Name: "frontend", FullName: "doku-signoz-frontend", Primary: true},
+		},
+	}
+
+	tests := map[string]string{
+		"":                  "doku-signoz-frontend",
+		"query":             "doku-signoz-query",
+		"doku-signoz-query": "doku-signoz-query",
+	}
+	for name, expected := range tests {
+		got, err := logsContainer(instance, name)
+		if err != nil || got != expected {
+			t.Errorf("logsContainer(%q) = %q, %v; expected %q", name, got, err, expected)
+		}
+	}
+	if _, err := logsContainer(instance, "nope"); err == nil {
+		t.Error("logsContainer(\"nope\") should fail")
+	}
+}
@@ -0,0 +1,238 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"path"
+	"sort"
+	"strconv"
+
+	"github.com/docker/docker/pkg/stdcopy"
+	"github.com/dokulabs/doku-cli/internal/service"
+	"github.com/dokulabs/doku-cli/pkg/types"
+)
+
+func (s *Server) handleListServices(w http.ResponseWriter, r *http.Request) {
+	// Statuses are refreshed from Docker when it answers, unless Docker
+	// events already keep them up to date; otherwise the recorded ones are
+	// listed
+	if !s.watching.Load() {
+		s.mu.Lock()
+		if _, err := s.configMgr.Load(); err == nil {
+			_, _ = s.serviceManager().Resync(r.Context(), false)
+		}
+		s.mu.Unlock()
+	}
+
+	instances, err := s.configMgr.ListInstances()
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	sort.Slice(instances, func(i, j int) bool {
+		return instances[i].Name < instances[j].Name
+	})
+
+	services := make([]Service, 0, len(instances))
+	for _, instance := range instances {
+		services = append(services, serviceFromInstance(instance))
+	}
+	writeJSON(w, http.StatusOK, services)
+}
+
+func (s *Server) handleGetService(w http.ResponseWriter, r *http.Request) {
+	instance, ok := s.instance(w, r.PathValue("name"))
+	if !ok {
+		return
+	}
+	svc := serviceFromInstance(instance)
+	if status, err := s.serviceManager().GetStatus(instance.Name); err == nil {
+		svc.Status = string(status)
+	}
+	writeJSON(w, http.StatusOK, svc)
+}
+
+func (s *Server) handleInstall(w http.ResponseWriter, r *http.Request) {
+	var req InstallRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("invalid request body: %w", err))
+		return
+	}
+	if req.Service == "" {
+		writeError(w, http.StatusBadRequest, errors.New("service is required"))
+		return
+	}
+	if _, err := s.catalogMgr.GetService(req.Service); err != nil {
+		writeError(w, http.StatusNotFound, err)
+		return
+	}
+
+	if !s.lock(w) {
+		return
+	}
+	defer s.mu.Unlock()
+
+	// The installer would ask on the terminal whether to replace it
+	if req.Name != "" && s.configMgr.HasInstance(req.Name) {
+		writeError(w, http.StatusConflict, fmt.Errorf("instance '%s' already exists", req.Name))
+		return
+	}
+
+	installer, err := service.NewInstaller(s.dockerClient, s.configMgr, s.catalogMgr)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	instance, err := installer.Install(service.InstallOptions{
+		ServiceName:       req.Service,
+		Version:           req.Version,
+		InstanceName:      req.Name,
+		Environment:       req.Environment,
+		MemoryLimit:       req.MemoryLimit,
+		CPULimit:          req.CPULimit,
+		Internal:          req.Internal,
+		AutoInstallDeps:   true,
+		ReuseExistingData: true,
+	})
+	if err != nil {
+		writeError(w, errorStatus(err), err)
+		return
+	}
+	writeJSON(w, http.StatusCreated, serviceFromInstance(instance))
+}
+
+func (s *Server) handleRemoveService(w http.ResponseWriter, r *http.Request) {
+	instance, ok := s.instance(w, r.PathValue("name"))
+	if !ok {
+		return
+	}
+	removeVolumes, _ := strconv.ParseBool(r.URL.Query().Get("volumes"))
+
+	if !s.lock(w) {
+		return
+	}
+	defer s.mu.Unlock()
+
+	if err := s.serviceManager().Remove(instance.Name, false, removeVolumes); err != nil {
+		writeError(w, errorStatus(err), err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// handleServiceAction starts, stops or restarts a service, after the last
+// element of the path
+func (s *Server) handleServiceAction(w http.ResponseWriter, r *http.Request) {
+	instance, ok := s.instance(w, r.PathValue("name"))
+	if !ok {
+		return
+	}
+
+	if !s.lock(w) {
+		return
+	}
+	defer s.mu.Unlock()
+
+	serviceMgr := s.serviceManager()
+	var err error
+	switch path.Base(r.URL.Path) {
+	case "start":
+		err = serviceMgr.Start(instance.Name)
+	case "stop":
+		err = serviceMgr.Stop(instance.Name)
+	case "restart":
+		err = serviceMgr.Restart(instance.Name)
+	}
+	if err != nil {
+		writeError(w, errorStatus(err), err)
+		return
+	}
+
+	instance, err = s.configMgr.GetInstance(instance.Name)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, serviceFromInstance(instance))
+}
+
+// handleServiceLogs streams the logs of a service's container as plain
+// text: the primary one, or the one named by ?container= for
+// multi-container services. ?follow=true keeps streaming new lines.
+func (s *Server) handleServiceLogs(w http.ResponseWriter, r *http.Request) {
+	instance, ok := s.instance(w, r.PathValue("name"))
+	if !ok {
+		return
+	}
+	follow, _ := strconv.ParseBool(r.URL.Query().Get("follow"))
+
+	containerName, err := logsContainer(instance, r.URL.Query().Get("container"))
+	if err != nil {
+		writeError(w, http.StatusNotFound, err)
+		return
+	}
+
+	logs, err := s.dockerClient.ContainerLogs(containerName, follow)
+	if err != nil {
+		writeError(w, http.StatusBadGateway, err)
+		return
+	}
+	defer logs.Close()
+
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	out := &flushWriter{w: w}
+	if flusher, ok := w.(http.Flusher); ok {
+		out.flusher = flusher
+	}
+
+	// Closing the logs stops the copy when the client goes away
+	go func() {
+		<-r.Context().Done()
+		logs.Close()
+	}()
+	_, _ = stdcopy.StdCopy(out, out, logs)
+}
+
+// logsContainer returns the container whose logs to show
+func logsContainer(instance *types.Instance, name string) (string, error) {
+	if !instance.IsMultiContainer {
+		return instance.ContainerName, nil
+	}
+	for _, c := range instance.Containers {
+		if (name == "" && c.Primary) || (name != "" && (c.Name == name || c.FullName == name)) {
+			return c.FullName, nil
+		}
+	}
+	if name == "" && len(instance.Containers) > 0 {
+		return instance.Containers[0].FullName, nil
+	}
+	return "", fmt.Errorf("container '%s' not found in %s", name, instance.Name)
+}
+
+// instance returns an installed instance, or writes a 404 response
+func (s *Server) instance(w http.ResponseWriter, name string) (*types.Instance, bool) {
+	instance, err := s.configMgr.GetInstance(name)
+	if err != nil {
+		writeError(w, http.StatusNotFound, fmt.Errorf("service '%s' not found", name))
+		return nil, false
+	}
+	return instance, true
+}
+
+// flushWriter flushes each write, so streamed logs arrive as they come
+type flushWriter struct {
+	w       io.Writer
+	flusher http.Flusher
+}
+
+func (f *flushWriter) Write(p []byte) (int, error) {
+	n, err := f.w.Write(p)
+	if f.flusher != nil {
+		f.flusher.Flush()
+	}
+	return n, err
+}
@@ -14,6 +14,7 @@ import (
 	"github.com/dokulabs/doku-cli/internal/config"
 	"github.com/dokulabs/doku-cli/internal/docker"
 	"github.com/dokulabs/doku-cli/internal/envfile"
+	"github.com/dokulabs/doku-cli/internal/readonly"
 	"github.com/dokulabs/doku-cli/pkg/types"
 )
 
@@ -58,6 +59,10 @@ type BackupInfo struct {
 
 // Backup creates a backup of a service instance
 func (m *Manager) Backup(opts BackupOptions) (*BackupInfo, error) {
+	if err := readonly.Check("back up " + opts.InstanceName); err != nil {
+		return nil, err
+	}
+
 	ctx := context.Background()
 
 	// Ensure backup directory exists
@@ -143,8 +148,7 @@ func (m *Manager) Backup(opts BackupOptions) (*BackupInfo, error) {
 
 	// Backup volumes
 	if opts.IncludeVolumes {
-		volumePrefix := fmt.Sprintf("doku-%s-", opts.InstanceName)
-		volumes, err := m.dockerClient.ListVolumesByPrefix(ctx, volumePrefix)
+		volumes, err := m.dockerClient.ListInstanceVolumes(ctx, opts.InstanceName)
 		if err != nil {
 			return nil, fmt.Errorf("failed to list volumes: %w", err)
 		}
@@ -9,6 +9,8 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+
+	"github.com/dokulabs/doku-cli/internal/readonly"
 )
 
 // RestoreOptions holds options for restore operation
@@ -30,6 +32,10 @@ type RestoreResult struct {
 
 // Restore restores a backup to the system
 func (m *Manager) Restore(opts RestoreOptions) (*RestoreResult, error) {
+	if err := readonly.Check("restore a backup"); err != nil {
+		return nil, err
+	}
+
 	// Open backup file
 	file, err := os.Open(opts.BackupPath)
 	if err != nil {
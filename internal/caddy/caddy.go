@@ -0,0 +1,272 @@
+// Package caddy runs Caddy as Doku's reverse proxy instead of Traefik. It
+// routes requests from a Caddyfile generated from the containers' Traefik
+// labels, and serves HTTPS with certificates from its own local CA.
+package caddy
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/mount"
+	"github.com/docker/docker/api/types/network"
+	"github.com/docker/go-connections/nat"
+	"github.com/dokulabs/doku-cli/internal/config"
+	"github.com/dokulabs/doku-cli/internal/docker"
+	"github.com/dokulabs/doku-cli/internal/monitoring"
+	"github.com/dokulabs/doku-cli/internal/workspace"
+	"github.com/dokulabs/doku-cli/pkg/types"
+)
+
+// Image is the Caddy image
+const Image = "caddy:2"
+
+// ContainerName returns the name of the Caddy container of the selected
+// workspace, doku-caddy in the default one
+func ContainerName() string {
+	return workspace.Prefix() + "caddy"
+}
+
+// Manager handles Caddy setup and configuration
+type Manager struct {
+	dockerClient *docker.Client
+	configDir    string // Holds the Caddyfile, and Caddy's data (its CA) in data/
+	protocol     string
+	httpPort     int
+	httpsPort    int
+	hostIP       string // Host address the ports are published on
+
+	// logRotation is the rotation of the container's logs
+	logRotation types.LogRotation
+}
+
+// NewManager creates a new Caddy manager
+func NewManager(dockerClient *docker.Client, configDir, protocol string) *Manager {
+	return &Manager{
+		dockerClient: dockerClient,
+		configDir:    configDir,
+		protocol:     protocol,
+		httpPort:     config.DefaultHTTPPort,
+		httpsPort:    config.DefaultHTTPSPort,
+		hostIP:       "0.0.0.0",
+	}
+}
+
+// SetPorts sets the host ports Caddy serves HTTP and HTTPS on
+func (m *Manager) SetPorts(httpPort, httpsPort int) *Manager {
+	m.httpPort = httpPort
+	m.httpsPort = httpsPort
+	return m
+}
+
+// SetHostIP sets the host address Caddy's ports are published on
+func (m *Manager) SetHostIP(ip string) *Manager {
+	m.hostIP = ip
+	return m
+}
+
+// SetLogRotation sets the rotation of the logs of the container created
+// from now on
+func (m *Manager) SetLogRotation(rotation types.LogRotation) *Manager {
+	m.logRotation = rotation
+	return m
+}
+
+// Name returns the name of the proxy
+func (m *Manager) Name() string {
+	return "Caddy"
+}
+
+// ContainerName returns the name of the Caddy container
+func (m *Manager) ContainerName() string {
+	return ContainerName()
+}
+
+// GetDashboardURL returns "": Caddy has no dashboard
+func (m *Manager) GetDashboardURL() string {
+	return ""
+}
+
+// CACertPath returns the certificate of Caddy's local CA, which browsers
+// must trust to open the services over HTTPS without warnings. Caddy
+// creates it on its first start.
+func (m *Manager) CACertPath() string {
+	return filepath.Join(m.configDir, "data", "caddy", "pki", "authorities", "local", "root.crt")
+}
+
+// caddyfilePath returns the path of the generated Caddyfile
+func (m *Manager) caddyfilePath() string {
+	return filepath.Join(m.configDir, "Caddyfile")
+}
+
+// WriteConfig writes the Caddyfile
+func (m *Manager) WriteConfig() error {
+	return m.SyncRoutes()
+}
+
+// Setup writes the Caddyfile and starts the Caddy container
+func (m *Manager) Setup() error {
+	if err := m.WriteConfig(); err != nil {
+		return err
+	}
+	if err := m.StartContainer(); err != nil {
+		return fmt.Errorf("failed to start Caddy container: %w", err)
+	}
+	return nil
+}
+
+// SyncRoutes regenerates the Caddyfile from the labels of the workspace's
+// containers. Caddy watches it and reloads when it changes.
+func (m *Manager) SyncRoutes() error {
+	list, err := m.dockerClient.ListContainersByLabel(context.Background(), "traefik.enable", "true")
+	if err != nil {
+		return err
+	}
+	containers := make([]Container, 0, len(list))
+	for _, ctr := range list {
+		if !workspace.Owns(ctr.Labels) || len(ctr.Names) == 0 {
+			continue
+		}
+		containers = append(containers, Container{
+			Name:   strings.TrimPrefix(ctr.Names[0], "/"),
+			Labels: ctr.Labels,
+		})
+	}
+
+	content := []byte(Render(RoutesFromContainers(containers), Options{
+		Protocol:  m.protocol,
+		HTTPPort:  m.httpPort,
+		HTTPSPort: m.httpsPort,
+	}))
+	if current, err := os.ReadFile(m.caddyfilePath()); err == nil && bytes.Equal(current, content) {
+		return nil
+	}
+
+	if err := os.MkdirAll(filepath.Join(m.configDir, "data"), 0755); err != nil {
+		return fmt.Errorf("failed to create Caddy directory: %w", err)
+	}
+	// Replace the file at once, so Caddy never reads half of it
+	tmp := m.caddyfilePath() + ".tmp"
+	if err := os.WriteFile(tmp, content, 0644); err != nil {
+		return fmt.Errorf("failed to write Caddyfile: %w", err)
+	}
+	if err := os.Rename(tmp, m.caddyfilePath()); err != nil {
+		return fmt.Errorf("failed to write Caddyfile: %w", err)
+	}
+	return nil
+}
+
+// StartContainer starts the Caddy container, creating it if needed
+func (m *Manager) StartContainer() error {
+	exists, err := m.dockerClient.ContainerExists(ContainerName())
+	if err != nil {
+		return err
+	}
+	if exists {
+		return m.RestartContainer()
+	}
+
+	if cached, err := m.dockerClient.ImageExists(Image); err != nil || !cached {
+		fmt.Printf("Pulling Caddy image %s...\n", Image)
+		if err := m.dockerClient.ImagePull(Image); err != nil {
+			return fmt.Errorf("failed to pull Caddy image: %w", err)
+		}
+	}
+
+	bindings := nat.PortMap{}
+	exposed := nat.PortSet{}
+	for _, port := range []int{m.httpPort, m.httpsPort} {
+		// Caddy listens on the host's ports, like Traefik's entrypoints,
+		// so its redirects to HTTPS point at the right port
+		spec := nat.Port(fmt.Sprintf("%d/tcp", port))
+		bindings[spec] = []nat.PortBinding{{HostIP: m.hostIP, HostPort: strconv.Itoa(port)}}
+		exposed[spec] = struct{}{}
+	}
+
+	containerConfig := &container.Config{
+		Image:        Image,
+		Cmd:          []string{"caddy", "run", "--config", "/etc/caddy/Caddyfile", "--adapter", "caddyfile", "--watch"},
+		ExposedPorts: exposed,
+		Labels:       docker.ComponentLabels("caddy"),
+	}
+	hostConfig := &container.HostConfig{
+		RestartPolicy: container.RestartPolicy{Name: "unless-stopped"},
+		Mounts: []mount.Mount{
+			// The directory rather than the file, so replacing the
+			// Caddyfile is seen inside the container
+			{Type: mount.TypeBind, Source: m.configDir, Target: "/etc/caddy", ReadOnly: true},
+			{Type: mount.TypeBind, Source: filepath.Join(m.configDir, "data"), Target: "/data"},
+		},
+		PortBindings: bindings,
+		ExtraHosts:   m.dockerClient.Engine().ExtraHosts(),
+		LogConfig:    *monitoring.GetDockerLoggingConfig(nil, m.logRotation),
+	}
+	// Connected to doku-network afterwards, like Traefik
+	containerID, err := m.dockerClient.ContainerCreate(containerConfig, hostConfig, &network.NetworkingConfig{}, ContainerName())
+	if err != nil {
+		return fmt.Errorf("failed to create Caddy container: %w", err)
+	}
+	if err := m.dockerClient.ContainerStart(containerID); err != nil {
+		return fmt.Errorf("failed to start Caddy container: %w", err)
+	}
+	return nil
+}
+
+// StopContainer stops the Caddy container
+func (m *Manager) StopContainer() error {
+	timeout := 10
+	return m.dockerClient.ContainerStop(ContainerName(), &timeout)
+}
+
+// RestartContainer restarts the Caddy container
+func (m *Manager) RestartContainer() error {
+	timeout := 10
+	return m.dockerClient.ContainerRestart(ContainerName(), &timeout)
+}
+
+// RemoveContainer removes the Caddy container. Its data, with its CA, is
+// kept.
+func (m *Manager) RemoveContainer() error {
+	exists, err := m.dockerClient.ContainerExists(ContainerName())
+	if err != nil || !exists {
+		return err
+	}
+	return m.dockerClient.ContainerRemove(ContainerName(), true)
+}
+
+// IsRunning checks if the Caddy container is running
+func (m *Manager) IsRunning() (bool, error) {
+	exists, err := m.dockerClient.ContainerExists(ContainerName())
+	if err != nil || !exists {
+		return false, err
+	}
+	info, err := m.dockerClient.ContainerInspect(ContainerName())
+	if err != nil {
+		return false, err
+	}
+	return info.State.Running, nil
+}
+
+// EnsureRunning starts Caddy if it isn't running, setting it up if its
+// container is gone
+func (m *Manager) EnsureRunning() error {
+	if running, err := m.IsRunning(); err != nil || running {
+		return err
+	}
+	exists, err := m.dockerClient.ContainerExists(ContainerName())
+	if err != nil {
+		return err
+	}
+	if exists {
+		if err := m.SyncRoutes(); err != nil {
+			return err
+		}
+		return m.dockerClient.ContainerStart(ContainerName())
+	}
+	return m.Setup()
+}
@@ -0,0 +1,203 @@
+package caddy
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// hostMatcher matches the hostnames of a Traefik rule, e.g.
+// Host(`api.doku.local`) || Host(`api.example.test`)
+var hostMatcher = regexp.MustCompile("Host\\(`([^`]+)`\\)")
+
+// Container is a container Traefik's labels route requests to
+type Container struct {
+	Name   string
+	Labels map[string]string
+}
+
+// Route is a site of the Caddyfile: requests for Hosts go to Upstreams
+// through the middlewares set with 'doku expose'
+type Route struct {
+	Name           string   // Traefik router, e.g. doku-api
+	Hosts          []string // e.g. api.doku.local
+	Upstreams      []string // Containers with their port, e.g. doku-api:8080
+	BasicAuth      bool     // Requests must authenticate, which Caddy can't check here
+	AllowIPs       []string
+	RateLimited    bool // Caddy has no rate limiter of its own, so it isn't applied
+	Headers        map[string]string
+	RequestHeaders map[string]string
+}
+
+// RoutesFromContainers turns the Traefik labels of containers into Caddy
+// routes, so that everything labeling a container for Traefik (installs,
+// projects, 'doku expose', domains) routes the same way under Caddy.
+// Replicas sharing a router are load-balanced. Routes are sorted by name.
+func RoutesFromContainers(containers []Container) []Route {
+	routes := make(map[string]*Route)
+	for _, ctr := range containers {
+		labels := ctr.Labels
+		if labels["traefik.enable"] != "true" {
+			continue
+		}
+		for key, rule := range labels {
+			rest, ok := strings.CutPrefix(key, "traefik.http.routers.")
+			if !ok {
+				continue
+			}
+			router, ok := strings.CutSuffix(rest, ".rule")
+			if !ok {
+				continue
+			}
+
+			// Routers to Traefik's own services, like its dashboard
+			service := labels["traefik.http.routers."+router+".service"]
+			if strings.Contains(service, "@") {
+				continue
+			}
+			if service == "" {
+				service = router
+			}
+			port := labels["traefik.http.services."+service+".loadbalancer.server.port"]
+			if port == "" {
+				continue
+			}
+
+			route, ok := routes[router]
+			if !ok {
+				route = &Route{Name: router}
+				for _, match := range hostMatcher.FindAllStringSubmatch(rule, -1) {
+					route.Hosts = append(route.Hosts, match[1])
+				}
+				applyMiddlewares(route, labels, router)
+				routes[router] = route
+			}
+			route.Upstreams = append(route.Upstreams, ctr.Name+":"+port)
+		}
+	}
+
+	names := make([]string, 0, len(routes))
+	for name, route := range routes {
+		if len(route.Hosts) > 0 {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	result := make([]Route, 0, len(names))
+	for _, name := range names {
+		route := routes[name]
+		sort.Strings(route.Upstreams)
+		result = append(result, *route)
+	}
+	return result
+}
+
+// applyMiddlewares reads the middlewares attached to a router into route
+func applyMiddlewares(route *Route, labels map[string]string, router string) {
+	for _, name := range strings.Split(labels["traefik.http.routers."+router+".middlewares"], ",") {
+		name = strings.TrimSuffix(strings.TrimSpace(name), "@docker")
+		if name == "" {
+			continue
+		}
+		prefix := "traefik.http.middlewares." + name + "."
+		for key, value := range labels {
+			setting, ok := strings.CutPrefix(key, prefix)
+			if !ok {
+				continue
+			}
+			switch {
+			case setting == "basicauth.users":
+				route.BasicAuth = true
+			case setting == "ipwhitelist.sourcerange" || setting == "ipallowlist.sourcerange":
+				for _, source := range strings.Split(value, ",") {
+					if source = strings.TrimSpace(source); source != "" {
+						route.AllowIPs = append(route.AllowIPs, source)
+					}
+				}
+			case strings.HasPrefix(setting, "ratelimit."):
+				route.RateLimited = true
+			case strings.HasPrefix(setting, "headers.customresponseheaders."):
+				if route.Headers == nil {
+					route.Headers = make(map[string]string)
+				}
+				route.Headers[strings.TrimPrefix(setting, "headers.customresponseheaders.")] = value
+			case strings.HasPrefix(setting, "headers.customrequestheaders."):
+				if route.RequestHeaders == nil {
+					route.RequestHeaders = make(map[string]string)
+				}
+				route.RequestHeaders[strings.TrimPrefix(setting, "headers.customrequestheaders.")] = value
+			}
+		}
+	}
+}
+
+// Options are the global settings of the Caddyfile
+type Options struct {
+	Protocol  string // http or https
+	HTTPPort  int
+	HTTPSPort int
+}
+
+// Render returns the Caddyfile serving routes. Over HTTPS, Caddy issues
+// the certificates from its own local CA.
+func Render(routes []Route, opts Options) string {
+	var b strings.Builder
+	b.WriteString("# Caddy configuration for Doku, generated from the Traefik labels of the\n")
+	b.WriteString("# containers: changes are overwritten\n\n")
+
+	b.WriteString("{\n")
+	fmt.Fprintf(&b, "\thttp_port %d\n", opts.HTTPPort)
+	fmt.Fprintf(&b, "\thttps_port %d\n", opts.HTTPSPort)
+	if opts.Protocol == "https" {
+		b.WriteString("\tlocal_certs\n")
+	} else {
+		b.WriteString("\tauto_https off\n")
+	}
+	b.WriteString("}\n")
+
+	for _, route := range routes {
+		sites := make([]string, 0, len(route.Hosts))
+		for _, host := range route.Hosts {
+			if opts.Protocol != "https" {
+				host = "http://" + host
+			}
+			sites = append(sites, host)
+		}
+
+		fmt.Fprintf(&b, "\n# %s\n", route.Name)
+		fmt.Fprintf(&b, "%s {\n", strings.Join(sites, ", "))
+		if len(route.AllowIPs) > 0 {
+			fmt.Fprintf(&b, "\t@denied not remote_ip %s\n", strings.Join(route.AllowIPs, " "))
+			b.WriteString("\trespond @denied 403\n")
+		}
+		if route.BasicAuth {
+			// The users' passwords are hashed with Apache's MD5 scheme,
+			// which Caddy doesn't take: refuse rather than let anyone in
+			b.WriteString("\trespond \"Basic auth set with 'doku expose' needs the Traefik proxy\" 403\n")
+		}
+		if route.RateLimited {
+			b.WriteString("\t# The rate limit set with 'doku expose' needs the Traefik proxy\n")
+		}
+		for _, name := range sortedKeys(route.Headers) {
+			fmt.Fprintf(&b, "\theader %s %q\n", name, route.Headers[name])
+		}
+		for _, name := range sortedKeys(route.RequestHeaders) {
+			fmt.Fprintf(&b, "\trequest_header %s %q\n", name, route.RequestHeaders[name])
+		}
+		fmt.Fprintf(&b, "\treverse_proxy %s\n", strings.Join(route.Upstreams, " "))
+		b.WriteString("}\n")
+	}
+	return b.String()
+}
+
+// sortedKeys returns the keys of m, sorted
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
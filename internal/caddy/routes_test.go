@@ -0,0 +1,92 @@
+package caddy
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestRoutesFromContainers(t *testing.T) {
+	api := map[string]string{
+		"traefik.enable":                                                                          "true",
+		"traefik.http.routers.doku-api.rule":                                                      "Host(`api.doku.local`) || Host(`api.example.test`)",
+		"traefik.http.services.doku-api.loadbalancer.server.port":                                 "8080",
+		"traefik.http.routers.doku-api.middlewares":                                               "doku-api-allowlist@docker,doku-api-headers@docker",
+		"traefik.http.middlewares.doku-api-allowlist.ipwhitelist.sourcerange":                     "10.0.0.0/8,192.168.1.5",
+		"traefik.http.middlewares.doku-api-headers.headers.customresponseheaders.X-Frame-Options": "DENY",
+		"traefik.http.middlewares.doku-api-headers.headers.customrequestheaders.X-Env":            "dev",
+	}
+	containers := []Container{
+		{Name: "doku-api-2", Labels: api},
+		{Name: "doku-api", Labels: api},
+		{Name: "doku-postgres", Labels: map[string]string{"traefik.enable": "false"}},
+		{Name: "doku-web", Labels: map[string]string{
+			"traefik.enable":                                           "true",
+			"traefik.http.routers.web.rule":                            "Host(`web.doku.local`)",
+			"traefik.http.routers.web.service":                         "web-svc",
+			"traefik.http.services.web-svc.loadbalancer.server.port":   "3000",
+			"traefik.http.routers.web.middlewares":                     "web-auth@docker,web-ratelimit@docker",
+			"traefik.http.middlewares.web-auth.basicauth.users":        "admin:$apr1$abc$def",
+			"traefik.http.middlewares.web-ratelimit.ratelimit.average": "10",
+		}},
+		{Name: "doku-traefik", Labels: map[string]string{
+			"traefik.enable":                    "true",
+			"traefik.http.routers.dash.rule":    "Host(`traefik.doku.local`)",
+			"traefik.http.routers.dash.service": "api@internal",
+		}},
+	}
+
+	want := []Route{
+		{
+			Name:           "doku-api",
+			Hosts:          []string{"api.doku.local", "api.example.test"},
+			Upstreams:      []string{"doku-api-2:8080", "doku-api:8080"},
+			AllowIPs:       []string{"10.0.0.0/8", "192.168.1.5"},
+			Headers:        map[string]string{"X-Frame-Options": "DENY"},
+			RequestHeaders: map[string]string{"X-Env": "dev"},
+		},
+		{
+			Name:        "web",
+			Hosts:       []string{"web.doku.local"},
+			Upstreams:   []string{"doku-web:3000"},
+			BasicAuth:   true,
+			RateLimited: true,
+		},
+	}
+	if got := RoutesFromContainers(containers); !reflect.DeepEqual(got, want) {
+		t.Errorf("RoutesFromContainers() =\n%+v\nwant\n%+v", got, want)
+	}
+}
+
+func TestRender(t *testing.T) {
+	routes := []Route{{
+		Name:      "doku-api",
+		Hosts:     []string{"api.doku.local", "api.example.test"},
+		Upstreams: []string{"doku-api:8080"},
+		AllowIPs:  []string{"10.0.0.0/8"},
+		BasicAuth: true,
+		Headers:   map[string]string{"X-Frame-Options": "DENY"},
+	}}
+
+	https := Render(routes, Options{Protocol: "https", HTTPPort: 80, HTTPSPort: 8443})
+	for _, line := range []string{
+		"\thttps_port 8443\n",
+		"\tlocal_certs\n",
+		"api.doku.local, api.example.test {\n",
+		"\t@denied not remote_ip 10.0.0.0/8\n",
+		"\trespond \"Basic auth set with 'doku expose' needs the Traefik proxy\" 403\n",
+		"\theader X-Frame-Options \"DENY\"\n",
+		"\treverse_proxy doku-api:8080\n",
+	} {
+		if !strings.Contains(https, line) {
+			t.Errorf("Render() over HTTPS is missing %q:\n%s", line, https)
+		}
+	}
+
+	http := Render(routes, Options{Protocol: "http", HTTPPort: 8080, HTTPSPort: 8443})
+	for _, line := range []string{"\tauto_https off\n", "http://api.doku.local, http://api.example.test {\n"} {
+		if !strings.Contains(http, line) {
+			t.Errorf("Render() over HTTP is missing %q:\n%s", line, http)
+		}
+	}
+}
@@ -0,0 +1,169 @@
+package catalog
+
+import (
+	"crypto/sha256"
+	"encoding/gob"
+	"encoding/hex"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/dokulabs/doku-cli/internal/readonly"
+	"github.com/dokulabs/doku-cli/internal/timing"
+	"github.com/dokulabs/doku-cli/pkg/types"
+)
+
+// IndexFileName is the on-disk cache of the parsed catalog, kept in the
+// catalog directory so that replacing the catalog also drops the index
+const IndexFileName = ".index.gob"
+
+// indexFormat is bumped whenever the cached types change shape
+const indexFormat = 1
+
+// catalogIndex is the content of the index file
+type catalogIndex struct {
+	Format   int
+	Revision string
+	Catalog  *types.ServiceCatalog
+}
+
+var (
+	// memCache holds the parsed catalog per catalog directory for the rest
+	// of the process
+	memMu    sync.Mutex
+	memCache = make(map[string]*types.ServiceCatalog)
+)
+
+// loadCached returns the parsed catalog from memory, then from the index
+// file if it matches the catalog revision, and only parses the YAML files
+// when neither is usable
+func (m *Manager) loadCached() (*types.ServiceCatalog, error) {
+	memMu.Lock()
+	defer memMu.Unlock()
+
+	if catalog, ok := memCache[m.catalogDir]; ok {
+		return catalog, nil
+	}
+
+	rev, err := revision(m.catalogDir)
+	if err == nil {
+		if catalog := m.readIndex(rev); catalog != nil {
+			memCache[m.catalogDir] = catalog
+			return catalog, nil
+		}
+	}
+
+	done := timing.Track(timing.Catalog, "parse catalog")
+	catalog, loadErr := NewHierarchicalLoader(m.catalogDir).Load()
+	done()
+	if loadErr != nil {
+		return nil, loadErr
+	}
+
+	memCache[m.catalogDir] = catalog
+	if err == nil {
+		// The index only saves time, a failure to write it is not an error
+		_ = m.writeIndex(rev, catalog)
+	}
+
+	return catalog, nil
+}
+
+// InvalidateCache drops the cached catalog, in memory and on disk
+func (m *Manager) InvalidateCache() {
+	memMu.Lock()
+	delete(memCache, m.catalogDir)
+	memMu.Unlock()
+
+	if readonly.Enabled() {
+		return
+	}
+	os.Remove(m.indexPath())
+}
+
+func (m *Manager) indexPath() string {
+	return filepath.Join(m.catalogDir, IndexFileName)
+}
+
+// readIndex returns the catalog stored in the index file, or nil when it is
+// missing, unreadable or for another revision
+func (m *Manager) readIndex(rev string) *types.ServiceCatalog {
+	defer timing.Track(timing.Catalog, "read catalog index")()
+
+	f, err := os.Open(m.indexPath())
+	if err != nil {
+		return nil
+	}
+	defer f.Close()
+
+	var index catalogIndex
+	if err := gob.NewDecoder(f).Decode(&index); err != nil {
+		return nil
+	}
+	if index.Format != indexFormat || index.Revision != rev || index.Catalog == nil {
+		return nil
+	}
+	if index.Catalog.Services == nil {
+		index.Catalog.Services = make(map[string]*types.CatalogService)
+	}
+
+	return index.Catalog
+}
+
+// writeIndex stores the parsed catalog for later processes
+func (m *Manager) writeIndex(rev string, catalog *types.ServiceCatalog) error {
+	if readonly.Enabled() {
+		return nil
+	}
+
+	tmpFile := m.indexPath() + ".tmp"
+	f, err := os.Create(tmpFile)
+	if err != nil {
+		return fmt.Errorf("failed to create catalog index: %w", err)
+	}
+
+	index := catalogIndex{Format: indexFormat, Revision: rev, Catalog: catalog}
+	if err := gob.NewEncoder(f).Encode(&index); err != nil {
+		f.Close()
+		os.Remove(tmpFile)
+		return fmt.Errorf("failed to encode catalog index: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		os.Remove(tmpFile)
+		return fmt.Errorf("failed to write catalog index: %w", err)
+	}
+
+	return os.Rename(tmpFile, m.indexPath())
+}
+
+// revision fingerprints the catalog from the path, size and modification
+// time of its files. Walking the tree is much cheaper than parsing it, and
+// any edit, update or replacement of the catalog changes the revision.
+func revision(catalogDir string) (string, error) {
+	defer timing.Track(timing.Catalog, "stat catalog")()
+
+	hash := sha256.New()
+	err := filepath.WalkDir(catalogDir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if entry.IsDir() || entry.Name() == IndexFileName || entry.Name() == IndexFileName+".tmp" {
+			return nil
+		}
+
+		info, err := entry.Info()
+		if err != nil {
+			return err
+		}
+		rel, _ := filepath.Rel(catalogDir, path)
+		fmt.Fprintf(hash, "%s\x00%d\x00%d\n", filepath.ToSlash(rel), info.Size(), info.ModTime().UnixNano())
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
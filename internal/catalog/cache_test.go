@@ -0,0 +1,141 @@
+package catalog
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/dokulabs/doku-cli/internal/readonly"
+)
+
+// writeTestCatalog creates a catalog with a single postgres:16 service
+func writeTestCatalog(t *testing.T, image string) string {
+	t.Helper()
+
+	dir := t.TempDir()
+	versionDir := filepath.Join(dir, "services", "database", "postgres", "versions", "16")
+	if err := os.MkdirAll(versionDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	files := map[string]string{
+		filepath.Join(dir, CatalogFileName):                                    "version: \"1.0\"\n",
+		filepath.Join(dir, "services", "database", "postgres", "service.yaml"): "name: postgres\ncategory: database\n",
+		filepath.Join(versionDir, "config.yaml"):                               "image: " + image + "\nport: 5432\n",
+	}
+	for path, content := range files {
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	return dir
+}
+
+// forgetMemCache drops the in-memory cache, as if a new process started
+func forgetMemCache(dir string) {
+	memMu.Lock()
+	delete(memCache, dir)
+	memMu.Unlock()
+}
+
+func TestLoadCatalogCachesInMemory(t *testing.T) {
+	dir := writeTestCatalog(t, "postgres:16")
+	defer forgetMemCache(dir)
+	mgr := NewManager(dir)
+
+	first, err := mgr.LoadCatalog()
+	if err != nil {
+		t.Fatalf("LoadCatalog() error = %v", err)
+	}
+	second, err := mgr.LoadCatalog()
+	if err != nil {
+		t.Fatalf("LoadCatalog() error = %v", err)
+	}
+	if first != second {
+		t.Error("expected the second load to return the cached catalog")
+	}
+}
+
+func TestLoadCatalogUsesIndex(t *testing.T) {
+	dir := writeTestCatalog(t, "postgres:16")
+	defer forgetMemCache(dir)
+	mgr := NewManager(dir)
+
+	if _, err := mgr.LoadCatalog(); err != nil {
+		t.Fatalf("LoadCatalog() error = %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, IndexFileName)); err != nil {
+		t.Fatalf("expected the index to be written: %v", err)
+	}
+
+	// Break the YAML so a load can only succeed through the index
+	configPath := filepath.Join(dir, "services", "database", "postgres", "versions", "16", "config.yaml")
+	info, err := os.Stat(configPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(configPath, []byte("image: [broken\nport: 5432\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	// Same size and time, so the revision does not change
+	if err := os.Truncate(configPath, info.Size()); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chtimes(configPath, info.ModTime(), info.ModTime()); err != nil {
+		t.Fatal(err)
+	}
+
+	forgetMemCache(dir)
+	spec, err := mgr.GetServiceVersion("postgres", "16")
+	if err != nil {
+		t.Fatalf("GetServiceVersion() error = %v", err)
+	}
+	if spec.Image != "postgres:16" || spec.Port != 5432 {
+		t.Errorf("unexpected spec from index: %+v", spec)
+	}
+}
+
+func TestLoadCatalogIndexInvalidatedByEdit(t *testing.T) {
+	dir := writeTestCatalog(t, "postgres:16")
+	defer forgetMemCache(dir)
+	mgr := NewManager(dir)
+
+	if _, err := mgr.LoadCatalog(); err != nil {
+		t.Fatalf("LoadCatalog() error = %v", err)
+	}
+
+	configPath := filepath.Join(dir, "services", "database", "postgres", "versions", "16", "config.yaml")
+	if err := os.WriteFile(configPath, []byte("image: postgres:16.4\nport: 5432\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	later := time.Now().Add(time.Minute)
+	if err := os.Chtimes(configPath, later, later); err != nil {
+		t.Fatal(err)
+	}
+
+	forgetMemCache(dir)
+	spec, err := mgr.GetServiceVersion("postgres", "16")
+	if err != nil {
+		t.Fatalf("GetServiceVersion() error = %v", err)
+	}
+	if spec.Image != "postgres:16.4" {
+		t.Errorf("expected the edited image, got %s", spec.Image)
+	}
+}
+
+func TestLoadCatalogReadOnlySkipsIndex(t *testing.T) {
+	readonly.Enable(true)
+	defer readonly.Enable(false)
+
+	dir := writeTestCatalog(t, "postgres:16")
+	defer forgetMemCache(dir)
+
+	if _, err := NewManager(dir).LoadCatalog(); err != nil {
+		t.Fatalf("LoadCatalog() error = %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, IndexFileName)); !os.IsNotExist(err) {
+		t.Errorf("expected no index in read-only mode, got %v", err)
+	}
+}
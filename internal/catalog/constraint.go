@@ -0,0 +1,251 @@
+package catalog
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/dokulabs/doku-cli/pkg/types"
+)
+
+// versionPattern matches the numeric versions that constraints apply to,
+// such as "16", "7.2", "v1.2.3" or "1.2.3-beta"
+var versionPattern = regexp.MustCompile(`^v?\d+(\.\d+)*(-[0-9A-Za-z.-]+)?$`)
+
+// Constraint is a version range such as ">=15 <17", "^7.0", "~7.2" or
+// "16.x". Space-separated comparisons must all hold; alternatives are
+// separated by "||".
+type Constraint struct {
+	raw          string
+	alternatives [][]comparison
+}
+
+// comparison is a single operator and version, e.g. ">=15"
+type comparison struct {
+	op      string
+	version string
+}
+
+// IsConstraint reports whether a version is a range rather than an exact
+// version or "latest"
+func IsConstraint(version string) bool {
+	if strings.ContainsAny(version, "<>=^~*| ") {
+		return true
+	}
+	for _, part := range strings.Split(strings.TrimPrefix(version, "v"), ".") {
+		if part == "x" || part == "X" {
+			return true
+		}
+	}
+	return false
+}
+
+// ParseConstraint parses a version range. Supported forms are comparisons
+// (=, !=, >, >=, <, <=), caret ranges (^7.0 allows 7.x), tilde ranges
+// (~7.2 allows 7.2.x), wildcards (16.x, *) and bare versions.
+func ParseConstraint(s string) (*Constraint, error) {
+	c := &Constraint{raw: strings.TrimSpace(s)}
+	if c.raw == "" {
+		return nil, fmt.Errorf("empty version constraint")
+	}
+
+	for _, alternative := range strings.Split(c.raw, "||") {
+		var comparisons []comparison
+		terms := strings.Fields(alternative)
+		for i := 0; i < len(terms); i++ {
+			term := terms[i]
+			// Allow a space after the operator, as in ">= 15"
+			if strings.Trim(term, "<>=!^~") == "" && i+1 < len(terms) {
+				i++
+				term += terms[i]
+			}
+			parsed, err := parseTerm(term)
+			if err != nil {
+				return nil, fmt.Errorf("invalid version constraint '%s': %w", c.raw, err)
+			}
+			comparisons = append(comparisons, parsed...)
+		}
+		if len(comparisons) == 0 {
+			return nil, fmt.Errorf("invalid version constraint '%s': empty alternative", c.raw)
+		}
+		c.alternatives = append(c.alternatives, comparisons)
+	}
+
+	return c, nil
+}
+
+// parseTerm expands one term of a constraint into comparisons
+func parseTerm(term string) ([]comparison, error) {
+	if term == "*" || term == "x" || term == "X" {
+		return []comparison{{">=", "0"}}, nil
+	}
+
+	op := ""
+	for _, prefix := range []string{">=", "<=", "!=", ">", "<", "=", "^", "~"} {
+		if strings.HasPrefix(term, prefix) {
+			op = prefix
+			break
+		}
+	}
+	version := strings.TrimPrefix(strings.TrimPrefix(term, op), "v")
+
+	parts := strings.Split(version, ".")
+	wildcard := len(parts)
+	for i, part := range parts {
+		if part == "x" || part == "X" || part == "*" {
+			wildcard = i
+			break
+		}
+	}
+	if wildcard < len(parts) {
+		if op != "" && op != "=" {
+			return nil, fmt.Errorf("wildcard '%s' can't follow %s", term, op)
+		}
+		if wildcard == 0 {
+			return []comparison{{">=", "0"}}, nil
+		}
+		return prefixRange(parts[:wildcard])
+	}
+
+	if !versionPattern.MatchString(version) {
+		return nil, fmt.Errorf("'%s' is not a version", term)
+	}
+
+	switch op {
+	case "^":
+		// Allow changes that keep the first non-zero part
+		numbers := strings.Split(strings.SplitN(version, "-", 2)[0], ".")
+		keep := 1
+		for keep < len(numbers) && numbers[keep-1] == "0" {
+			keep++
+		}
+		upper, err := nextVersion(numbers[:keep])
+		if err != nil {
+			return nil, err
+		}
+		return []comparison{{">=", version}, {"<", upper}}, nil
+	case "~":
+		// Allow patch changes, or minor ones if only a major is given
+		numbers := strings.Split(strings.SplitN(version, "-", 2)[0], ".")
+		keep := 2
+		if len(numbers) < keep {
+			keep = len(numbers)
+		}
+		upper, err := nextVersion(numbers[:keep])
+		if err != nil {
+			return nil, err
+		}
+		return []comparison{{">=", version}, {"<", upper}}, nil
+	case "":
+		return []comparison{{"=", version}}, nil
+	default:
+		return []comparison{{op, version}}, nil
+	}
+}
+
+// prefixRange returns the comparisons matching every version starting with
+// the given parts, e.g. 16 for "16.x"
+func prefixRange(parts []string) ([]comparison, error) {
+	for _, part := range parts {
+		if _, err := strconv.Atoi(part); err != nil {
+			return nil, fmt.Errorf("'%s' is not a version", strings.Join(parts, "."))
+		}
+	}
+	upper, err := nextVersion(parts)
+	if err != nil {
+		return nil, err
+	}
+	return []comparison{{">=", strings.Join(parts, ".")}, {"<", upper}}, nil
+}
+
+// nextVersion increments the last of the given version parts
+func nextVersion(parts []string) (string, error) {
+	last, err := strconv.Atoi(parts[len(parts)-1])
+	if err != nil {
+		return "", fmt.Errorf("'%s' is not a version", strings.Join(parts, "."))
+	}
+	next := append(append([]string{}, parts[:len(parts)-1]...), strconv.Itoa(last+1))
+	return strings.Join(next, "."), nil
+}
+
+// Matches reports whether a version satisfies the constraint. Versions that
+// aren't numeric, such as "latest", never do.
+func (c *Constraint) Matches(version string) bool {
+	if !versionPattern.MatchString(version) {
+		return false
+	}
+	for _, comparisons := range c.alternatives {
+		if matchesAll(comparisons, version) {
+			return true
+		}
+	}
+	return false
+}
+
+func matchesAll(comparisons []comparison, version string) bool {
+	for _, cmp := range comparisons {
+		result := CompareVersions(version, cmp.version)
+		var ok bool
+		switch cmp.op {
+		case "=":
+			ok = result == 0
+		case "!=":
+			ok = result != 0
+		case ">":
+			ok = result > 0
+		case ">=":
+			ok = result >= 0
+		case "<":
+			ok = result < 0
+		case "<=":
+			ok = result <= 0
+		}
+		if !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// String returns the constraint as written
+func (c *Constraint) String() string {
+	return c.raw
+}
+
+// MatchingVersions returns the versions of a service satisfying a
+// constraint, newest first
+func MatchingVersions(service *types.CatalogService, constraint *Constraint) []string {
+	var matching []string
+	for _, version := range SortedVersions(service) {
+		if constraint.Matches(version) {
+			matching = append(matching, version)
+		}
+	}
+	return matching
+}
+
+// ResolveVersion returns the catalog version of a service to install for a
+// version: the newest one satisfying a range such as "^7.0", or the version
+// itself otherwise
+func (m *Manager) ResolveVersion(serviceName, version string) (string, error) {
+	if !IsConstraint(version) {
+		return version, nil
+	}
+
+	constraint, err := ParseConstraint(version)
+	if err != nil {
+		return "", err
+	}
+	service, err := m.GetService(serviceName)
+	if err != nil {
+		return "", err
+	}
+
+	matching := MatchingVersions(service, constraint)
+	if len(matching) == 0 {
+		return "", fmt.Errorf("no version of '%s' satisfies '%s' (available: %s)",
+			serviceName, version, strings.Join(SortedVersions(service), ", "))
+	}
+	return matching[0], nil
+}
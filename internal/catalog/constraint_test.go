@@ -0,0 +1,98 @@
+package catalog
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/dokulabs/doku-cli/pkg/types"
+)
+
+func TestIsConstraint(t *testing.T) {
+	tests := map[string]bool{
+		"16":        false,
+		"7.2.1":     false,
+		"latest":    false,
+		"":          false,
+		">=15 <17":  true,
+		"^7.0":      true,
+		"~7.2":      true,
+		"16.x":      true,
+		"*":         true,
+		"15 || 17":  true,
+		"!=16":      true,
+		"v1.2.x":    true,
+		"1.2.3-rc1": false,
+	}
+
+	for version, expected := range tests {
+		if got := IsConstraint(version); got != expected {
+			t.Errorf("IsConstraint(%q) = %v, expected %v", version, got, expected)
+		}
+	}
+}
+
+func TestConstraintMatches(t *testing.T) {
+	tests := []struct {
+		constraint string
+		matches    []string
+		rejects    []string
+	}{
+		{">=15 <17", []string{"15", "16", "16.4"}, []string{"14", "17", "17.0"}},
+		{">= 15", []string{"15", "18"}, []string{"14.9"}},
+		{"^7.0", []string{"7.0", "7", "7.4.1"}, []string{"6.9", "8"}},
+		{"^0.2.1", []string{"0.2.1", "0.2.9"}, []string{"0.3.0", "0.2.0"}},
+		{"~7.2", []string{"7.2", "7.2.5"}, []string{"7.3", "7.1"}},
+		{"~7", []string{"7.0", "7.9"}, []string{"8"}},
+		{"16.x", []string{"16", "16.2"}, []string{"15", "17"}},
+		{"*", []string{"1", "16.2"}, []string{"latest"}},
+		{"15 || >=17", []string{"15", "17", "18"}, []string{"16"}},
+		{"!=16", []string{"15", "17"}, []string{"16"}},
+		{"v16.x", []string{"v16.1", "16.1"}, []string{"v17"}},
+	}
+
+	for _, tt := range tests {
+		c, err := ParseConstraint(tt.constraint)
+		if err != nil {
+			t.Errorf("ParseConstraint(%q) failed: %v", tt.constraint, err)
+			continue
+		}
+		for _, version := range tt.matches {
+			if !c.Matches(version) {
+				t.Errorf("%q should match %q", tt.constraint, version)
+			}
+		}
+		for _, version := range tt.rejects {
+			if c.Matches(version) {
+				t.Errorf("%q should not match %q", tt.constraint, version)
+			}
+		}
+	}
+}
+
+func TestParseConstraintInvalid(t *testing.T) {
+	for _, constraint := range []string{"", ">=", ">=abc", "^x", ">16.x", "15 ||", "a.x"} {
+		if _, err := ParseConstraint(constraint); err == nil {
+			t.Errorf("ParseConstraint(%q) should fail", constraint)
+		}
+	}
+}
+
+func TestMatchingVersions(t *testing.T) {
+	service := &types.CatalogService{
+		Versions: map[string]*types.ServiceSpec{
+			"14":     {},
+			"15":     {},
+			"16":     {},
+			"17":     {},
+			"latest": {},
+		},
+	}
+
+	c, err := ParseConstraint(">=15 <17")
+	if err != nil {
+		t.Fatalf("ParseConstraint failed: %v", err)
+	}
+	if got := MatchingVersions(service, c); !reflect.DeepEqual(got, []string{"16", "15"}) {
+		t.Errorf("MatchingVersions = %v, expected [16 15]", got)
+	}
+}
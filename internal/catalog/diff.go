@@ -0,0 +1,124 @@
+package catalog
+
+import (
+	"sort"
+
+	"github.com/dokulabs/doku-cli/pkg/types"
+)
+
+// ImageChange is a version, or a container of it, whose image changed
+type ImageChange struct {
+	Service   string
+	Version   string
+	Container string // Empty for single-container services
+	From      string
+	To        string
+}
+
+// Deprecation is a version deprecated upstream
+type Deprecation struct {
+	Service string
+	Version string
+	Reason  string
+}
+
+// Diff is what changed between two catalogs, sorted by service and version
+type Diff struct {
+	AddedServices   []string
+	RemovedServices []string
+	AddedVersions   map[string][]string // Service → versions
+	RemovedVersions map[string][]string // Service → versions
+	ChangedImages   []ImageChange
+	Deprecated      []Deprecation // Versions deprecated since the old catalog
+}
+
+// Empty reports whether the catalogs are the same for users
+func (d *Diff) Empty() bool {
+	return len(d.AddedServices) == 0 && len(d.RemovedServices) == 0 && len(d.AddedVersions) == 0 &&
+		len(d.RemovedVersions) == 0 && len(d.ChangedImages) == 0 && len(d.Deprecated) == 0
+}
+
+// VersionRemoved reports whether a version of a service, or the service,
+// was removed
+func (d *Diff) VersionRemoved(service, version string) bool {
+	for _, name := range d.RemovedServices {
+		if name == service {
+			return true
+		}
+	}
+	for _, v := range d.RemovedVersions[service] {
+		if v == version {
+			return true
+		}
+	}
+	return false
+}
+
+// DiffCatalogs compares an old catalog with a new one
+func DiffCatalogs(old, new *types.ServiceCatalog) *Diff {
+	d := &Diff{
+		AddedVersions:   make(map[string][]string),
+		RemovedVersions: make(map[string][]string),
+	}
+
+	for _, name := range sortedKeys(new.Services) {
+		newService := new.Services[name]
+		oldService, ok := old.Services[name]
+		if !ok {
+			d.AddedServices = append(d.AddedServices, name)
+			continue
+		}
+
+		for _, version := range sortedKeys(newService.Versions) {
+			newSpec := newService.Versions[version]
+			oldSpec, ok := oldService.Versions[version]
+			if !ok {
+				d.AddedVersions[name] = append(d.AddedVersions[name], version)
+				continue
+			}
+			d.ChangedImages = append(d.ChangedImages, imageChanges(name, version, oldSpec, newSpec)...)
+			if newSpec.Deprecated != "" && oldSpec.Deprecated == "" {
+				d.Deprecated = append(d.Deprecated, Deprecation{Service: name, Version: version, Reason: newSpec.Deprecated})
+			}
+		}
+		for _, version := range sortedKeys(oldService.Versions) {
+			if _, ok := newService.Versions[version]; !ok {
+				d.RemovedVersions[name] = append(d.RemovedVersions[name], version)
+			}
+		}
+	}
+	for _, name := range sortedKeys(old.Services) {
+		if _, ok := new.Services[name]; !ok {
+			d.RemovedServices = append(d.RemovedServices, name)
+		}
+	}
+	return d
+}
+
+// imageChanges compares the images of two specs of a version, containers
+// by name
+func imageChanges(service, version string, old, new *types.ServiceSpec) []ImageChange {
+	var changes []ImageChange
+	if old.Image != new.Image && old.Image != "" && new.Image != "" {
+		changes = append(changes, ImageChange{Service: service, Version: version, From: old.Image, To: new.Image})
+	}
+	oldImages := make(map[string]string, len(old.Containers))
+	for _, c := range old.Containers {
+		oldImages[c.Name] = c.Image
+	}
+	for _, c := range new.Containers {
+		if from, ok := oldImages[c.Name]; ok && from != c.Image {
+			changes = append(changes, ImageChange{Service: service, Version: version, Container: c.Name, From: from, To: c.Image})
+		}
+	}
+	return changes
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
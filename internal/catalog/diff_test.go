@@ -0,0 +1,64 @@
+package catalog
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/dokulabs/doku-cli/pkg/types"
+)
+
+func TestDiffCatalogs(t *testing.T) {
+	old := &types.ServiceCatalog{Services: map[string]*types.CatalogService{
+		"postgres": {Versions: map[string]*types.ServiceSpec{
+			"15": {Image: "postgres:15.4"},
+			"16": {Image: "postgres:16.1"},
+		}},
+		"memcached": {Versions: map[string]*types.ServiceSpec{"1": {Image: "memcached:1"}}},
+		"signoz": {Versions: map[string]*types.ServiceSpec{"1": {Containers: []types.ContainerSpec{
+			{Name: "query-service", Image: "signoz/query-service:0.40"},
+			{Name: "frontend", Image: "signoz/frontend:0.40"},
+		}}}},
+	}}
+	new := &types.ServiceCatalog{Services: map[string]*types.CatalogService{
+		"postgres": {Versions: map[string]*types.ServiceSpec{
+			"16": {Image: "postgres:16.2", Deprecated: "use 17"},
+			"17": {Image: "postgres:17.0"},
+		}},
+		"redis": {Versions: map[string]*types.ServiceSpec{"7": {Image: "redis:7"}}},
+		"signoz": {Versions: map[string]*types.ServiceSpec{"1": {Containers: []types.ContainerSpec{
+			{Name: "query-service", Image: "signoz/query-service:0.41"},
+			{Name: "frontend", Image: "signoz/frontend:0.40"},
+		}}}},
+	}}
+
+	d := DiffCatalogs(old, new)
+	if !reflect.DeepEqual(d.AddedServices, []string{"redis"}) {
+		t.Errorf("AddedServices = %v", d.AddedServices)
+	}
+	if !reflect.DeepEqual(d.RemovedServices, []string{"memcached"}) {
+		t.Errorf("RemovedServices = %v", d.RemovedServices)
+	}
+	if !reflect.DeepEqual(d.AddedVersions, map[string][]string{"postgres": {"17"}}) {
+		t.Errorf("AddedVersions = %v", d.AddedVersions)
+	}
+	if !reflect.DeepEqual(d.RemovedVersions, map[string][]string{"postgres": {"15"}}) {
+		t.Errorf("RemovedVersions = %v", d.RemovedVersions)
+	}
+	wantImages := []ImageChange{
+		{Service: "postgres", Version: "16", From: "postgres:16.1", To: "postgres:16.2"},
+		{Service: "signoz", Version: "1", Container: "query-service", From: "signoz/query-service:0.40", To: "signoz/query-service:0.41"},
+	}
+	if !reflect.DeepEqual(d.ChangedImages, wantImages) {
+		t.Errorf("ChangedImages = %v, want %v", d.ChangedImages, wantImages)
+	}
+	if !reflect.DeepEqual(d.Deprecated, []Deprecation{{Service: "postgres", Version: "16", Reason: "use 17"}}) {
+		t.Errorf("Deprecated = %v", d.Deprecated)
+	}
+
+	if !d.VersionRemoved("postgres", "15") || !d.VersionRemoved("memcached", "1") || d.VersionRemoved("postgres", "16") {
+		t.Error("VersionRemoved() is wrong")
+	}
+	if d.Empty() || !DiffCatalogs(new, new).Empty() {
+		t.Error("Empty() is wrong")
+	}
+}
@@ -0,0 +1,181 @@
+package catalog
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/dokulabs/doku-cli/internal/docker"
+	"github.com/dokulabs/doku-cli/pkg/types"
+)
+
+// envNameRe matches an environment variable name
+var envNameRe = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// knownProtocols are the protocols the installer knows: http and https are
+// routed by Traefik, the others only get port mappings
+var knownProtocols = map[string]bool{"": true, "http": true, "https": true, "tcp": true, "udp": true, "grpc": true}
+
+// optionTypes are the types of configuration options, "" being a string
+var optionTypes = map[string]bool{"": true, "string": true, "int": true, "bool": true, "select": true}
+
+// Lint checks a hierarchical catalog directory like Validate, and also that
+// its files follow the schema: ports in range, known protocols, configuration
+// options that can be set, container paths for volumes, and the metadata
+// shown by 'doku catalog'.
+func Lint(dir string) ([]Issue, error) {
+	return validate(dir, true)
+}
+
+// lintMetadata checks the fields of a service.yaml shown to users
+func (v *catalogValidator) lintMetadata(path, description, category string) {
+	if description == "" {
+		v.warnf(path, 0, "description is missing")
+	} else if strings.HasPrefix(description, "TODO") {
+		v.warnf(path, 0, "description is still the skeleton's placeholder")
+	}
+	if category == "" {
+		v.warnf(path, 0, "category is missing")
+	}
+}
+
+// lintSpec checks a version's fields against the schema
+func (v *catalogValidator) lintSpec(path string, node *yaml.Node, spec *types.ServiceSpec) {
+	for key, port := range map[string]int{"port": spec.Port, "admin_port": spec.AdminPort} {
+		if port < 0 || port > 65535 {
+			v.errorf(path, keyLine(node, key), "%s %d is not between 1 and 65535", key, port)
+		}
+	}
+	if !knownProtocols[spec.Protocol] {
+		v.warnf(path, keyLine(node, "protocol"), "unknown protocol '%s': use http, https, tcp, udp or grpc", spec.Protocol)
+	}
+	if spec.Image != "" {
+		v.lintImage(path, keyLine(node, "image"), spec.Image)
+	}
+	v.lintEnvironment(path, node, spec.Environment, "environment")
+	v.lintVolumes(path, node, spec.Volumes, "volumes")
+	v.lintResources(path, node, spec.Resources, "resources")
+	if spec.Healthcheck != nil && spec.Healthcheck.Retries < 0 {
+		v.errorf(path, keyLine(node, "healthcheck", "retries"), "healthcheck retries can't be negative")
+	}
+	for i, c := range spec.Containers {
+		idx := strconv.Itoa(i)
+		if c.Image != "" {
+			v.lintImage(path, keyLine(node, "containers", idx, "image"), c.Image)
+		}
+		v.lintEnvironment(path, node, c.Environment, "containers", idx, "environment")
+		v.lintVolumes(path, node, c.Volumes, "containers", idx, "volumes")
+		v.lintResources(path, node, c.Resources, "containers", idx, "resources")
+	}
+
+	if spec.Configuration == nil {
+		return
+	}
+	envVars := make(map[string]bool)
+	for i, opt := range spec.Configuration.Options {
+		linePath := []string{"configuration", "options", strconv.Itoa(i)}
+		line := keyLine(node, linePath...)
+		if opt.Name == "" {
+			v.errorf(path, line, "option has no name")
+		}
+		switch {
+		case opt.EnvVar == "":
+			v.errorf(path, line, "option '%s' has no env_var", opt.Name)
+		case !envNameRe.MatchString(opt.EnvVar):
+			v.errorf(path, keyLine(node, append(linePath, "env_var")...), "option '%s': invalid env_var '%s'", opt.Name, opt.EnvVar)
+		case envVars[opt.EnvVar]:
+			v.errorf(path, keyLine(node, append(linePath, "env_var")...), "option '%s': %s is set by another option", opt.Name, opt.EnvVar)
+		}
+		envVars[opt.EnvVar] = true
+
+		if !optionTypes[opt.Type] {
+			v.errorf(path, keyLine(node, append(linePath, "type")...), "option '%s': unknown type '%s': use string, int, bool or select", opt.Name, opt.Type)
+			continue
+		}
+		if opt.Type == "select" && len(opt.Options) == 0 {
+			v.errorf(path, line, "option '%s' is a select without options", opt.Name)
+			continue
+		}
+		if opt.Validation != "" {
+			if _, err := regexp.Compile(opt.Validation); err != nil {
+				v.errorf(path, keyLine(node, append(linePath, "validation")...), "option '%s': invalid validation pattern: %v", opt.Name, err)
+				continue
+			}
+		}
+		if opt.Default != "" {
+			if err := ValidateOption(opt, opt.Default); err != nil {
+				v.errorf(path, keyLine(node, append(linePath, "default")...), "option '%s': default: %v", opt.Name, err)
+			}
+		}
+	}
+}
+
+// lintImage warns about images that change under the user
+func (v *catalogValidator) lintImage(path string, line int, image string) {
+	if strings.Contains(image, "@") {
+		return
+	}
+	tag := ""
+	if i := strings.LastIndex(image, ":"); i > strings.LastIndex(image, "/") {
+		tag = image[i+1:]
+	}
+	if tag == "" || tag == "latest" {
+		v.warnf(path, line, "image %s has no pinned tag, so the version may change on each pull", image)
+	}
+}
+
+func (v *catalogValidator) lintEnvironment(path string, node *yaml.Node, env map[string]string, linePath ...string) {
+	for name := range env {
+		if !envNameRe.MatchString(name) {
+			v.errorf(path, keyLine(node, append(linePath, name)...), "invalid environment variable name '%s'", name)
+		}
+	}
+}
+
+// lintVolumes checks volumes: a container path for a named volume, or
+// source:target[:ro] for a bind mount
+func (v *catalogValidator) lintVolumes(path string, node *yaml.Node, volumes []string, linePath ...string) {
+	for i, volume := range volumes {
+		target := volume
+		if parts := strings.Split(volume, ":"); len(parts) > 1 {
+			target = parts[1]
+			if len(parts) > 3 || (len(parts) == 3 && parts[2] != "ro" && parts[2] != "rw") {
+				v.errorf(path, keyLine(node, append(linePath, strconv.Itoa(i))...), "invalid volume %q: use /path or source:/path[:ro]", volume)
+				continue
+			}
+		}
+		if !strings.HasPrefix(target, "/") {
+			v.errorf(path, keyLine(node, append(linePath, strconv.Itoa(i))...), "volume %q: the container path must be absolute", volume)
+		}
+	}
+}
+
+// lintResources checks that minimums don't exceed maximums
+func (v *catalogValidator) lintResources(path string, node *yaml.Node, res *types.ResourceRequirements, linePath ...string) {
+	if res == nil {
+		return
+	}
+	if res.MemoryMin != "" && res.MemoryMax != "" {
+		min, errMin := docker.ParseMemoryString(res.MemoryMin)
+		max, errMax := docker.ParseMemoryString(res.MemoryMax)
+		if errMin == nil && errMax == nil && min > max {
+			v.errorf(path, keyLine(node, linePath...), "memory minimum %s is more than the maximum %s", res.MemoryMin, res.MemoryMax)
+		}
+	}
+	if res.CPUMin != "" && res.CPUMax != "" {
+		min, errMin := strconv.ParseFloat(res.CPUMin, 64)
+		max, errMax := strconv.ParseFloat(res.CPUMax, 64)
+		if errMin == nil && errMax == nil && min > max {
+			v.errorf(path, keyLine(node, linePath...), "CPU minimum %s is more than the maximum %s", res.CPUMin, res.CPUMax)
+		}
+	}
+}
+
+// lintName checks a service ID, the directory name it is installed by
+func (v *catalogValidator) lintName(path, id string) {
+	if !sourceNameRe.MatchString(id) {
+		v.warnf(path, 0, "service '%s' can't be installed by name: use lowercase letters, digits and dashes", id)
+	}
+}
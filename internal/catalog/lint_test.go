@@ -0,0 +1,124 @@
+package catalog
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLint(t *testing.T) {
+	dir := writeCatalog(t, map[string]string{
+		"catalog.yaml":                       "version: \"1.0\"\n",
+		"services/search/Meili/service.yaml": "name: Meilisearch\n",
+		"services/search/Meili/versions/1/config.yaml": `image: getmeili/meilisearch
+port: 70000
+protocol: htp
+volumes:
+  - data
+environment:
+  MEILI-ENV: development
+resources:
+  memorymin: 1g
+  memorymax: 512m
+configuration:
+  options:
+    - name: env
+      type: select
+      env_var: MEILI_ENV
+      default: staging
+      options: [development, production]
+    - name: key
+      type: secret
+      env_var: MEILI_ENV
+`,
+	})
+
+	// Validate doesn't check the schema
+	issues, err := Validate(dir)
+	if err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+	if len(issues) != 0 {
+		t.Errorf("Validate() = %v, want no issues", issues)
+	}
+
+	issues, err = Lint(dir)
+	if err != nil {
+		t.Fatalf("Lint() error = %v", err)
+	}
+	want := []string{
+		"service.yaml: description is missing",
+		"service.yaml: category is missing",
+		"service 'Meili' can't be installed by name",
+		"config.yaml:1: image getmeili/meilisearch has no pinned tag",
+		"config.yaml:2: port 70000 is not between 1 and 65535",
+		"config.yaml:3: unknown protocol 'htp'",
+		"config.yaml:5: volume \"data\": the container path must be absolute",
+		"config.yaml:7: invalid environment variable name 'MEILI-ENV'",
+		"config.yaml:8: memory minimum 1g is more than the maximum 512m",
+		"config.yaml:16: option 'env': default: MEILI_ENV must be one of development, production",
+		"config.yaml:20: option 'key': MEILI_ENV is set by another option",
+	}
+	for _, w := range want {
+		found := false
+		for _, issue := range issues {
+			if strings.Contains(issue.String(), w) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			t.Errorf("Lint() missing issue %q in:\n%v", w, issues)
+		}
+	}
+}
+
+func TestScaffold(t *testing.T) {
+	dir := t.TempDir()
+	data := ScaffoldData{Name: "internal-api", Category: "internal", Image: "corp/api:1.4", Version: "1.4", Port: 9000}
+
+	files, err := Scaffold(dir, data, false, false)
+	if err != nil {
+		t.Fatalf("Scaffold() error = %v", err)
+	}
+	if len(files) != 1 || files[0] != "internal-api.yaml" {
+		t.Fatalf("Scaffold() = %v, want [internal-api.yaml]", files)
+	}
+	if _, err := Scaffold(dir, data, false, false); err == nil {
+		t.Error("Scaffold() overwrote an existing file without force")
+	}
+
+	mgr := NewManager(filepath.Join(dir, "catalog"))
+	def, issues, err := mgr.LintLocalService(filepath.Join(dir, files[0]))
+	if err != nil {
+		t.Fatalf("LintLocalService() error = %v", err)
+	}
+	// Only the placeholder description is left to fill in
+	if len(issues) != 1 || !strings.Contains(issues[0].Message, "placeholder") {
+		t.Errorf("LintLocalService() of the skeleton = %v, want the placeholder warning", issues)
+	}
+	version := def.Versions["1.4"]
+	if spec := version.spec(); spec.Image != "corp/api:1.4" || spec.Port != 9000 || spec.Resources == nil || spec.Resources.MemoryMax != "512m" {
+		t.Errorf("skeleton version = %+v", spec)
+	}
+
+	catalogDir := writeCatalog(t, map[string]string{"catalog.yaml": "version: \"1.0\"\n"})
+	files, err = Scaffold(catalogDir, data, true, false)
+	if err != nil {
+		t.Fatalf("Scaffold() in a catalog error = %v", err)
+	}
+	if len(files) != 2 {
+		t.Fatalf("Scaffold() in a catalog = %v, want service.yaml and config.yaml", files)
+	}
+	issues, err = Lint(catalogDir)
+	if err != nil {
+		t.Fatalf("Lint() error = %v", err)
+	}
+	if len(issues) != 1 || !strings.Contains(issues[0].Message, "placeholder") {
+		t.Errorf("Lint() of the skeleton = %v, want the placeholder warning", issues)
+	}
+
+	if _, err := Scaffold(dir, ScaffoldData{Name: "Bad Name"}, false, false); err == nil {
+		t.Error("Scaffold() accepted an invalid name")
+	}
+}
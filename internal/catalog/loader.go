@@ -172,6 +172,11 @@ func (l *HierarchicalLoader) loadVersionSpec(versionDir string) (*types.ServiceS
 		return nil, fmt.Errorf("failed to parse version config: %w", err)
 	}
 
+	return config.spec(), nil
+}
+
+// spec converts a version config.yaml to a service spec
+func (config *VersionConfig) spec() *types.ServiceSpec {
 	spec := &types.ServiceSpec{
 		Image:          config.Image,
 		Description:    config.Description,
@@ -182,6 +187,8 @@ func (l *HierarchicalLoader) loadVersionSpec(versionDir string) (*types.ServiceS
 		Volumes:        config.Volumes,
 		Command:        config.Command,
 		Environment:    config.Environment,
+		HostNetwork:    config.HostNetwork,
+		Deprecated:     config.Deprecated,
 		Containers:     config.Containers,
 		InitContainers: config.InitContainers,
 	}
@@ -217,7 +224,7 @@ func (l *HierarchicalLoader) loadVersionSpec(versionDir string) (*types.ServiceS
 		spec.Configuration = config.Configuration
 	}
 
-	return spec, nil
+	return spec
 }
 
 // CatalogMetadata represents the root catalog.yaml structure
@@ -261,6 +268,8 @@ type VersionConfig struct {
 	Healthcheck   *types.Healthcheck          `yaml:"healthcheck,omitempty"`
 	Resources     *types.ResourceRequirements `yaml:"resources,omitempty"`
 	Configuration *types.ServiceConfiguration `yaml:"configuration,omitempty"`
+	HostNetwork   bool                        `yaml:"host_network,omitempty"`
+	Deprecated    string                      `yaml:"deprecated,omitempty"`
 
 	// Multi-container support (NEW)
 	Containers     []types.ContainerSpec `yaml:"containers,omitempty"`
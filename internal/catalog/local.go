@@ -0,0 +1,266 @@
+package catalog
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/dokulabs/doku-cli/internal/readonly"
+)
+
+const (
+	// LocalSource names the source of the service definitions added with
+	// AddLocalService, kept in ~/.doku/catalogs/local
+	LocalSource = "local"
+
+	// LocalPriority puts local definitions before every catalog: they are
+	// written by the user for this machine
+	LocalPriority = 1000
+
+	// localCategoryDir holds the local definitions in the local catalog
+	localCategoryDir = "local"
+)
+
+// LocalDefinition is a service definition written by a user in a single
+// file: the fields of a catalog's service.yaml, and its versions' config.yaml
+// under versions
+type LocalDefinition struct {
+	Name        string                   `yaml:"name"`
+	Description string                   `yaml:"description"`
+	Category    string                   `yaml:"category"`
+	Icon        string                   `yaml:"icon"`
+	Tags        []string                 `yaml:"tags"`
+	Links       *ServiceLinks            `yaml:"links,omitempty"`
+	Versions    map[string]VersionConfig `yaml:"versions"`
+}
+
+// localSource returns the source of the local definitions, or false when
+// none was added
+func (m *Manager) localSource() (Source, bool) {
+	if m.sourcesDir == "" {
+		return Source{}, false
+	}
+	source := Source{Name: LocalSource, Priority: LocalPriority}
+	if !m.sourceManager(source).CatalogExists() {
+		return Source{}, false
+	}
+	return source, true
+}
+
+// allSources returns the registered sources and the local definitions,
+// highest priority first
+func (m *Manager) allSources() ([]Source, error) {
+	sources, err := m.ListSources()
+	if err != nil {
+		return nil, err
+	}
+	if local, ok := m.localSource(); ok {
+		sources = append([]Source{local}, sources...)
+	}
+	return sources, nil
+}
+
+// CheckLocalService validates a service definition file like a catalog's
+// services, its dependencies resolved against the catalog. It returns the
+// definition and the issues found, by line of the file.
+func (m *Manager) CheckLocalService(path string) (*LocalDefinition, []Issue, error) {
+	return m.checkLocalService(path, false)
+}
+
+// LintLocalService checks a service definition file like CheckLocalService,
+// and also against the schema like Lint
+func (m *Manager) LintLocalService(path string) (*LocalDefinition, []Issue, error) {
+	return m.checkLocalService(path, true)
+}
+
+func (m *Manager) checkLocalService(path string, lint bool) (*LocalDefinition, []Issue, error) {
+	if _, err := os.Stat(path); err != nil {
+		return nil, nil, err
+	}
+
+	v := &catalogValidator{dir: filepath.Dir(path), services: make(map[string]map[string]*versionFile), lint: lint}
+	var def LocalDefinition
+	node := v.decode(path, &def)
+	if node == nil {
+		return nil, v.issues, nil
+	}
+
+	if def.Name == "" {
+		v.errorf(path, 0, "name is missing")
+	} else if !sourceNameRe.MatchString(def.Name) {
+		v.errorf(path, keyLine(node, "name"), "invalid name %q: use lowercase letters, digits and dashes", def.Name)
+	}
+	if len(def.Versions) == 0 {
+		v.errorf(path, keyLine(node, "versions"), "service has no versions")
+	}
+	if lint {
+		v.lintMetadata(path, def.Description, def.Category)
+	}
+
+	// Dependencies may be any service of the catalog
+	if m.CatalogExists() {
+		if catalog, err := m.LoadCatalog(); err == nil {
+			for name, service := range catalog.Services {
+				versions := make(map[string]*versionFile, len(service.Versions))
+				for version, spec := range service.Versions {
+					versions[version] = &versionFile{spec: spec}
+				}
+				v.services[name] = versions
+			}
+			// Services of other sources can be named without their source
+			for name, versions := range v.services {
+				if base := BaseName(name); v.services[base] == nil {
+					v.services[base] = versions
+				}
+			}
+		}
+	}
+	versions := make(map[string]*versionFile, len(def.Versions))
+	for version, config := range def.Versions {
+		config := config
+		versions[version] = &versionFile{path: path, node: valueNode(node, "versions", version), spec: config.spec()}
+	}
+	v.services[def.Name] = versions
+
+	names := make([]string, 0, len(versions))
+	for version := range versions {
+		names = append(names, version)
+	}
+	sort.Strings(names)
+	for _, version := range names {
+		v.checkSpec(def.Name, versions[version])
+	}
+
+	sort.SliceStable(v.issues, func(i, j int) bool {
+		return v.issues[i].Line < v.issues[j].Line
+	})
+	return &def, v.issues, nil
+}
+
+// AddLocalService adds a service definition checked by CheckLocalService to
+// the local definitions, replacing one of the same name. Its file is
+// written in the catalog layout, so that it is installed like a catalog
+// service.
+func (m *Manager) AddLocalService(path string) (*LocalDefinition, error) {
+	if err := readonly.Check("add local service " + path); err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var node yaml.Node
+	if err := yaml.Unmarshal(data, &node); err != nil {
+		return nil, fmt.Errorf("invalid YAML in %s: %w", path, err)
+	}
+	var def LocalDefinition
+	if err := node.Decode(&def); err != nil {
+		return nil, fmt.Errorf("invalid service definition %s: %w", path, err)
+	}
+
+	localDir := m.SourceDir(LocalSource)
+	serviceDir := filepath.Join(localDir, "services", localCategoryDir, def.Name)
+	if err := os.RemoveAll(serviceDir); err != nil {
+		return nil, fmt.Errorf("failed to replace %s: %w", def.Name, err)
+	}
+
+	files := map[string]interface{}{
+		filepath.Join(localDir, CatalogFileName): CatalogMetadata{Version: LocalSource, Format: "hierarchical"},
+		filepath.Join(serviceDir, "service.yaml"): ServiceMetadata{
+			Name:        def.Name,
+			Description: def.Description,
+			Category:    def.Category,
+			Icon:        def.Icon,
+			Tags:        def.Tags,
+			Links:       def.Links,
+		},
+	}
+	for version := range def.Versions {
+		// The version's node keeps the author's comments and layout
+		files[filepath.Join(serviceDir, "versions", version, "config.yaml")] = valueNode(&node, "versions", version)
+	}
+	for file, content := range files {
+		out, err := yaml.Marshal(content)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode %s: %w", file, err)
+		}
+		if err := os.MkdirAll(filepath.Dir(file), 0755); err != nil {
+			return nil, fmt.Errorf("failed to create %s: %w", filepath.Dir(file), err)
+		}
+		if err := os.WriteFile(file, out, 0644); err != nil {
+			return nil, fmt.Errorf("failed to write %s: %w", file, err)
+		}
+	}
+
+	m.sourceManager(Source{Name: LocalSource}).InvalidateCache()
+	return &def, nil
+}
+
+// RemoveLocalService removes a local service definition
+func (m *Manager) RemoveLocalService(name string) error {
+	if err := readonly.Check("remove local service " + name); err != nil {
+		return err
+	}
+
+	serviceDir := filepath.Join(m.SourceDir(LocalSource), "services", localCategoryDir, name)
+	if _, err := os.Stat(serviceDir); err != nil {
+		return fmt.Errorf("local service '%s' not found", name)
+	}
+	if err := os.RemoveAll(serviceDir); err != nil {
+		return fmt.Errorf("failed to remove %s: %w", name, err)
+	}
+	m.sourceManager(Source{Name: LocalSource}).InvalidateCache()
+
+	// A catalog without services doesn't load
+	if names, err := m.ListLocalServices(); err == nil && len(names) == 0 {
+		return os.RemoveAll(m.SourceDir(LocalSource))
+	}
+	return nil
+}
+
+// ListLocalServices returns the names of the local service definitions
+func (m *Manager) ListLocalServices() ([]string, error) {
+	entries, err := os.ReadDir(filepath.Join(m.SourceDir(LocalSource), "services", localCategoryDir))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var names []string
+	for _, entry := range entries {
+		if entry.IsDir() {
+			names = append(names, entry.Name())
+		}
+	}
+	return names, nil
+}
+
+// valueNode returns the value of a path of mapping keys in a YAML document,
+// or nil
+func valueNode(node *yaml.Node, path ...string) *yaml.Node {
+	if node.Kind == yaml.DocumentNode && len(node.Content) > 0 {
+		node = node.Content[0]
+	}
+	for _, key := range path {
+		if node.Kind != yaml.MappingNode {
+			return nil
+		}
+		var next *yaml.Node
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			if node.Content[i].Value == key {
+				next = node.Content[i+1]
+				break
+			}
+		}
+		if next == nil {
+			return nil
+		}
+		node = next
+	}
+	return node
+}
@@ -0,0 +1,93 @@
+package catalog
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLocalService(t *testing.T) {
+	dokuDir := t.TempDir()
+	catalogDir := filepath.Join(dokuDir, "catalog")
+	writeSourceCatalog(t, catalogDir, map[string]string{"postgres": "postgres:16"})
+	defer forgetMemCache(catalogDir)
+	mgr := NewManager(catalogDir)
+	defer forgetMemCache(mgr.SourceDir(LocalSource))
+
+	bad := filepath.Join(dokuDir, "bad.yaml")
+	if err := os.WriteFile(bad, []byte(`name: internal-api
+versions:
+  "1":
+    image: corp/api:1
+    colour: blue
+    dependencies: [nosuch]
+`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	_, issues, err := mgr.CheckLocalService(bad)
+	if err != nil {
+		t.Fatalf("CheckLocalService() error = %v", err)
+	}
+	var warned, failed bool
+	for _, issue := range issues {
+		if issue.Warning && issue.Line == 5 && strings.Contains(issue.Message, "colour") {
+			warned = true
+		}
+		if !issue.Warning && strings.Contains(issue.Message, "nosuch") {
+			failed = true
+		}
+	}
+	if !warned || !failed {
+		t.Errorf("CheckLocalService() issues = %v, want an unknown field warning on line 5 and a missing dependency error", issues)
+	}
+
+	good := filepath.Join(dokuDir, "internal-api.yaml")
+	if err := os.WriteFile(good, []byte(`name: internal-api
+description: Our API
+versions:
+  "1":
+    image: corp/api:1
+    port: 8080
+    dependencies: [postgres]
+`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	def, issues, err := mgr.CheckLocalService(good)
+	if err != nil || len(issues) > 0 {
+		t.Fatalf("CheckLocalService() = %v, %v, want no issues", issues, err)
+	}
+	if _, err := mgr.AddLocalService(good); err != nil {
+		t.Fatalf("AddLocalService() error = %v", err)
+	}
+
+	for _, name := range []string{def.Name, "local/" + def.Name} {
+		spec, err := mgr.GetServiceVersion(name, "")
+		if err != nil {
+			t.Errorf("GetServiceVersion(%s) error = %v", name, err)
+			continue
+		}
+		if spec.Image != "corp/api:1" || spec.Port != 8080 {
+			t.Errorf("GetServiceVersion(%s) = %s:%d, want corp/api:1:8080", name, spec.Image, spec.Port)
+		}
+	}
+	if names, _ := mgr.ListLocalServices(); len(names) != 1 || names[0] != def.Name {
+		t.Errorf("ListLocalServices() = %v, want [%s]", names, def.Name)
+	}
+	if err := ValidateSourceName(LocalSource); err == nil {
+		t.Error("ValidateSourceName() accepted the local definitions' name")
+	}
+
+	if err := mgr.RemoveLocalService(def.Name); err != nil {
+		t.Fatalf("RemoveLocalService() error = %v", err)
+	}
+	if _, err := mgr.GetServiceVersion(def.Name, ""); err == nil {
+		t.Error("GetServiceVersion() found a removed local service")
+	}
+	if _, err := mgr.ListServices(); err != nil {
+		t.Errorf("ListServices() after removing the last local service error = %v", err)
+	}
+	if err := mgr.RemoveLocalService(def.Name); err == nil {
+		t.Error("RemoveLocalService() of a missing service succeeded")
+	}
+}
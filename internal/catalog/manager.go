@@ -12,6 +12,8 @@ import (
 	"strconv"
 	"strings"
 
+	"github.com/dokulabs/doku-cli/internal/readonly"
+	"github.com/dokulabs/doku-cli/internal/timing"
 	"github.com/dokulabs/doku-cli/pkg/types"
 )
 
@@ -26,6 +28,7 @@ const (
 type Manager struct {
 	catalogDir string
 	catalogURL string
+	sourcesDir string // Registered catalog sources ("" for a source's own manager)
 }
 
 // NewManager creates a new catalog manager
@@ -33,6 +36,7 @@ func NewManager(catalogDir string) *Manager {
 	return &Manager{
 		catalogDir: catalogDir,
 		catalogURL: DefaultCatalogURL,
+		sourcesDir: filepath.Join(filepath.Dir(catalogDir), SourcesDirName),
 	}
 }
 
@@ -53,21 +57,22 @@ func (m *Manager) GetCatalogDir() string {
 
 // FetchCatalog downloads and extracts the hierarchical catalog
 func (m *Manager) FetchCatalog() error {
+	if err := readonly.Check("update the catalog"); err != nil {
+		return err
+	}
+
 	// Ensure catalog directory exists
 	if err := os.MkdirAll(m.catalogDir, 0755); err != nil {
 		return fmt.Errorf("failed to create catalog directory: %w", err)
 	}
 
 	// Download catalog tarball
-	resp, err := http.Get(m.catalogURL)
+	defer timing.Track(timing.Network, "download catalog")()
+	body, err := openCatalogURL(m.catalogURL)
 	if err != nil {
-		return fmt.Errorf("failed to download catalog: %w", err)
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("failed to download catalog: HTTP %d", resp.StatusCode)
+		return err
 	}
+	defer body.Close()
 
 	// Create temporary directory for extraction
 	tmpDir := m.catalogDir + ".tmp"
@@ -79,7 +84,7 @@ func (m *Manager) FetchCatalog() error {
 	}
 
 	// Extract tar.gz
-	if err := extractTarGz(resp.Body, tmpDir); err != nil {
+	if err := extractTarGz(body, tmpDir); err != nil {
 		os.RemoveAll(tmpDir)
 		return fmt.Errorf("failed to extract catalog: %w", err)
 	}
@@ -93,10 +98,36 @@ func (m *Manager) FetchCatalog() error {
 	if err := os.Rename(tmpDir, m.catalogDir); err != nil {
 		return fmt.Errorf("failed to update catalog: %w", err)
 	}
+	m.InvalidateCache()
 
 	return nil
 }
 
+// openCatalogURL opens a catalog tarball from an HTTP(S) URL, or from a
+// local path for catalogs shared on a file server
+func openCatalogURL(url string) (io.ReadCloser, error) {
+	if path, ok := strings.CutPrefix(url, "file://"); ok || !strings.Contains(url, "://") {
+		if !ok {
+			path = url
+		}
+		file, err := os.Open(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open catalog: %w", err)
+		}
+		return file, nil
+	}
+
+	resp, err := http.Get(url)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download catalog: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, fmt.Errorf("failed to download catalog: HTTP %d", resp.StatusCode)
+	}
+	return resp.Body, nil
+}
+
 // extractTarGz extracts a tar.gz archive to the specified directory
 // Strips the top-level directory from GitHub tarballs (e.g., doku-catalog-main/)
 func extractTarGz(r io.Reader, destDir string) error {
@@ -180,7 +211,8 @@ func extractTarGz(r io.Reader, destDir string) error {
 	return nil
 }
 
-// LoadCatalog loads and parses the catalog from hierarchical structure
+// LoadCatalog loads and parses the catalog from hierarchical structure. The
+// result is shared with other callers and must not be modified.
 func (m *Manager) LoadCatalog() (*types.ServiceCatalog, error) {
 	catalogPath := m.GetCatalogPath()
 
@@ -189,24 +221,36 @@ func (m *Manager) LoadCatalog() (*types.ServiceCatalog, error) {
 		return nil, fmt.Errorf("catalog not found, please run 'doku catalog update'")
 	}
 
-	// Use hierarchical loader
-	loader := NewHierarchicalLoader(m.catalogDir)
-	catalog, err := loader.Load()
+	// Parsing the hierarchical catalog is slow, so it is cached
+	catalog, err := m.loadCached()
 	if err != nil {
 		return nil, fmt.Errorf("failed to load catalog: %w", err)
 	}
 
-	return catalog, nil
+	sources, err := m.allSources()
+	if err != nil {
+		return nil, err
+	}
+	if len(sources) == 0 {
+		return catalog, nil
+	}
+	return m.mergeSources(catalog, sources)
 }
 
-// GetService retrieves a specific service from the catalog
+// GetService retrieves a specific service from the catalog. Services of
+// other sources than the official catalog are named <source>/<service>, or
+// by their name alone if it resolves to them (see Source).
 func (m *Manager) GetService(serviceName string) (*types.CatalogService, error) {
 	catalog, err := m.LoadCatalog()
 	if err != nil {
 		return nil, err
 	}
+	sources, err := m.allSources()
+	if err != nil {
+		return nil, err
+	}
 
-	service, exists := catalog.Services[serviceName]
+	service, exists := catalog.Services[resolveName(catalog, sources, serviceName)]
 	if !exists {
 		return nil, fmt.Errorf("service '%s' not found in catalog", serviceName)
 	}
@@ -236,11 +280,15 @@ func (m *Manager) GetServiceVersion(serviceName, version string) (*types.Service
 
 // getLatestVersion returns the latest version of a service using semantic versioning
 func (m *Manager) getLatestVersion(service *types.CatalogService) string {
-	if len(service.Versions) == 0 {
+	versions := SortedVersions(service)
+	if len(versions) == 0 {
 		return ""
 	}
+	return versions[0]
+}
 
-	// Collect all versions into a slice
+// SortedVersions returns the versions of a service, newest first
+func SortedVersions(service *types.CatalogService) []string {
 	versions := make([]string, 0, len(service.Versions))
 	for version := range service.Versions {
 		versions = append(versions, version)
@@ -248,17 +296,16 @@ func (m *Manager) getLatestVersion(service *types.CatalogService) string {
 
 	// Sort versions using semantic versioning comparison
 	sort.Slice(versions, func(i, j int) bool {
-		return compareVersions(versions[i], versions[j]) < 0
+		return CompareVersions(versions[i], versions[j]) > 0
 	})
 
-	// Return the last (highest) version
-	return versions[len(versions)-1]
+	return versions
 }
 
-// compareVersions compares two version strings
+// CompareVersions compares two version strings
 // Returns: -1 if v1 < v2, 0 if v1 == v2, 1 if v1 > v2
 // Handles versions like "15", "16.1", "v1.2.3", "1.2.3-beta"
-func compareVersions(v1, v2 string) int {
+func CompareVersions(v1, v2 string) int {
 	// Normalize versions by removing 'v' prefix
 	v1 = strings.TrimPrefix(v1, "v")
 	v2 = strings.TrimPrefix(v2, "v")
@@ -362,36 +409,18 @@ func (m *Manager) ListServicesByCategory(category string) ([]*types.CatalogServi
 	return filtered, nil
 }
 
-// SearchServices searches for services by name, description, or tags
-func (m *Manager) SearchServices(query string) ([]*types.CatalogService, error) {
+// SearchServices searches for services by name, tags, category, or description,
+// ordered by relevance
+func (m *Manager) SearchServices(query string, opts SearchOptions) ([]*types.CatalogService, error) {
 	allServices, err := m.ListServices()
 	if err != nil {
 		return nil, err
 	}
 
-	query = strings.ToLower(query)
-	results := make([]*types.CatalogService, 0)
-
-	for _, service := range allServices {
-		// Search in name
-		if strings.Contains(strings.ToLower(service.Name), query) {
-			results = append(results, service)
-			continue
-		}
-
-		// Search in description
-		if strings.Contains(strings.ToLower(service.Description), query) {
-			results = append(results, service)
-			continue
-		}
-
-		// Search in tags
-		for _, tag := range service.Tags {
-			if strings.Contains(strings.ToLower(tag), query) {
-				results = append(results, service)
-				break
-			}
-		}
+	ranked := RankServices(allServices, query, opts)
+	results := make([]*types.CatalogService, 0, len(ranked))
+	for _, result := range ranked {
+		results = append(results, result.Service)
 	}
 
 	return results, nil
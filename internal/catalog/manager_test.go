@@ -0,0 +1,31 @@
+package catalog
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/dokulabs/doku-cli/pkg/types"
+)
+
+func TestSortedVersions(t *testing.T) {
+	service := &types.CatalogService{
+		Name: "postgres",
+		Versions: map[string]*types.ServiceSpec{
+			"14":        {},
+			"16":        {},
+			"16.1":      {},
+			"17.0-beta": {},
+			"9.6":       {},
+		},
+	}
+
+	want := []string{"17.0-beta", "16.1", "16", "14", "9.6"}
+	if got := SortedVersions(service); !reflect.DeepEqual(got, want) {
+		t.Errorf("SortedVersions() = %v, want %v", got, want)
+	}
+
+	m := &Manager{}
+	if got := m.getLatestVersion(service); got != "17.0-beta" {
+		t.Errorf("getLatestVersion() = %q, want %q", got, "17.0-beta")
+	}
+}
@@ -0,0 +1,69 @@
+package catalog
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/dokulabs/doku-cli/pkg/types"
+)
+
+// FindOption returns the configuration option of a spec set through the
+// environment variable envVar, or nil if the spec doesn't define one
+func FindOption(spec *types.ServiceSpec, envVar string) *types.ConfigOption {
+	if spec == nil || spec.Configuration == nil {
+		return nil
+	}
+	for i := range spec.Configuration.Options {
+		if spec.Configuration.Options[i].EnvVar == envVar {
+			return &spec.Configuration.Options[i]
+		}
+	}
+	return nil
+}
+
+// ValidateOption checks a value against the type, choices and validation
+// pattern of a configuration option
+func ValidateOption(opt types.ConfigOption, value string) error {
+	if value == "" {
+		if opt.Required {
+			return fmt.Errorf("%s is required", opt.EnvVar)
+		}
+		return nil
+	}
+
+	switch opt.Type {
+	case "int":
+		if _, err := strconv.Atoi(value); err != nil {
+			return fmt.Errorf("%s must be a whole number, got %q", opt.EnvVar, value)
+		}
+	case "bool":
+		if _, err := strconv.ParseBool(value); err != nil {
+			return fmt.Errorf("%s must be true or false, got %q", opt.EnvVar, value)
+		}
+	case "select":
+		valid := false
+		for _, choice := range opt.Options {
+			if choice == value {
+				valid = true
+				break
+			}
+		}
+		if !valid {
+			return fmt.Errorf("%s must be one of %s, got %q", opt.EnvVar, strings.Join(opt.Options, ", "), value)
+		}
+	}
+
+	if opt.Validation != "" {
+		re, err := regexp.Compile(opt.Validation)
+		if err != nil {
+			return fmt.Errorf("%s has an invalid validation pattern in the catalog: %w", opt.EnvVar, err)
+		}
+		if !re.MatchString(value) {
+			return fmt.Errorf("%s doesn't match %s, got %q", opt.EnvVar, opt.Validation, value)
+		}
+	}
+
+	return nil
+}
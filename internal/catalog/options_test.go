@@ -0,0 +1,57 @@
+package catalog
+
+import (
+	"testing"
+
+	"github.com/dokulabs/doku-cli/pkg/types"
+)
+
+func TestFindOption(t *testing.T) {
+	spec := &types.ServiceSpec{
+		Configuration: &types.ServiceConfiguration{
+			Options: []types.ConfigOption{
+				{Name: "max_connections", EnvVar: "POSTGRES_MAX_CONNECTIONS", Type: "int"},
+			},
+		},
+	}
+
+	if opt := FindOption(spec, "POSTGRES_MAX_CONNECTIONS"); opt == nil || opt.Name != "max_connections" {
+		t.Errorf("FindOption() = %v, want max_connections", opt)
+	}
+	if opt := FindOption(spec, "SHARED_BUFFERS"); opt != nil {
+		t.Errorf("FindOption() = %v, want nil", opt)
+	}
+	if opt := FindOption(&types.ServiceSpec{}, "POSTGRES_MAX_CONNECTIONS"); opt != nil {
+		t.Errorf("FindOption() without configuration = %v, want nil", opt)
+	}
+}
+
+func TestValidateOption(t *testing.T) {
+	tests := []struct {
+		name    string
+		opt     types.ConfigOption
+		value   string
+		wantErr bool
+	}{
+		{"int", types.ConfigOption{EnvVar: "N", Type: "int"}, "200", false},
+		{"int not a number", types.ConfigOption{EnvVar: "N", Type: "int"}, "lots", true},
+		{"bool", types.ConfigOption{EnvVar: "B", Type: "bool"}, "false", false},
+		{"bool invalid", types.ConfigOption{EnvVar: "B", Type: "bool"}, "maybe", true},
+		{"select", types.ConfigOption{EnvVar: "S", Type: "select", Options: []string{"debug", "info"}}, "info", false},
+		{"select invalid", types.ConfigOption{EnvVar: "S", Type: "select", Options: []string{"debug", "info"}}, "trace", true},
+		{"pattern", types.ConfigOption{EnvVar: "P", Type: "string", Validation: `^\d+MB$`}, "512MB", false},
+		{"pattern mismatch", types.ConfigOption{EnvVar: "P", Type: "string", Validation: `^\d+MB$`}, "512", true},
+		{"bad pattern", types.ConfigOption{EnvVar: "P", Type: "string", Validation: `(`}, "x", true},
+		{"required empty", types.ConfigOption{EnvVar: "R", Required: true}, "", true},
+		{"optional empty", types.ConfigOption{EnvVar: "O", Type: "int"}, "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateOption(tt.opt, tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidateOption(%q) error = %v, wantErr %v", tt.value, err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,184 @@
+package catalog
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"text/template"
+)
+
+// ScaffoldData fills in a new service's skeleton
+type ScaffoldData struct {
+	Name     string // Service ID, e.g. internal-api
+	Category string
+	Image    string // Image of the first version, with its tag
+	Version  string
+	Port     int
+}
+
+// serviceTemplate is a service.yaml
+const serviceTemplate = `name: {{.Name}}
+description: TODO describe {{.Name}}
+category: {{.Category}}
+icon: "📦"
+tags: []
+latest_version: "{{.Version}}"
+available_versions:
+  - "{{.Version}}"
+links:
+  homepage: ""
+  documentation: ""
+  repository: ""
+`
+
+// versionTemplate is a version's config.yaml, indented by indent
+const versionTemplate = `{{.Indent}}# Image of this version, with a pinned tag
+{{.Indent}}image: {{.Image}}
+{{.Indent}}# Port routed by Traefik to https://{{.Name}}.<domain>
+{{.Indent}}port: {{.Port}}
+{{.Indent}}protocol: http
+{{.Indent}}# Other ports to map on the host, e.g. "9090:9090"
+{{.Indent}}ports: []
+{{.Indent}}# Container paths kept in a named volume
+{{.Indent}}volumes:
+{{.Indent}}  - /data
+{{.Indent}}environment:
+{{.Indent}}  LOG_LEVEL: info
+{{.Indent}}resources:
+{{.Indent}}  memorymin: 128m
+{{.Indent}}  memorymax: 512m
+{{.Indent}}  cpumin: "0.25"
+{{.Indent}}  cpumax: "1.0"
+{{.Indent}}healthcheck:
+{{.Indent}}  test: ["CMD-SHELL", "wget -q --spider http://localhost:{{.Port}}/ || exit 1"]
+{{.Indent}}  interval: 30s
+{{.Indent}}  timeout: 5s
+{{.Indent}}  retries: 3
+{{.Indent}}# Options asked for at install, and set with --env
+{{.Indent}}configuration:
+{{.Indent}}  options:
+{{.Indent}}    - name: log_level
+{{.Indent}}      description: Log level
+{{.Indent}}      type: select
+{{.Indent}}      default: info
+{{.Indent}}      env_var: LOG_LEVEL
+{{.Indent}}      options: [debug, info, warn, error]
+{{.Indent}}# Services installed first, e.g.
+{{.Indent}}# dependencies_v2:
+{{.Indent}}#   - name: postgres
+{{.Indent}}#     version: ">=15"
+`
+
+// localTemplate is a single-file definition for AddLocalService
+const localTemplate = `name: {{.Name}}
+description: TODO describe {{.Name}}
+category: {{.Category}}
+tags: []
+versions:
+  "{{.Version}}":
+{{.VersionConfig}}`
+
+// Scaffold writes the skeleton of a new service and returns the paths
+// written, relative to dir. With catalog set, dir is a catalog checkout and
+// the service is written in its layout; otherwise a single <name>.yaml is
+// written for 'doku catalog add'. It refuses to overwrite existing files
+// unless force is set.
+func Scaffold(dir string, data ScaffoldData, catalog, force bool) ([]string, error) {
+	if !sourceNameRe.MatchString(data.Name) {
+		return nil, fmt.Errorf("invalid service name %q: use lowercase letters, digits and dashes", data.Name)
+	}
+	if data.Category == "" {
+		data.Category = "other"
+	}
+	if data.Version == "" {
+		data.Version = "1.0"
+	}
+	if data.Image == "" {
+		data.Image = fmt.Sprintf("example/%s:%s", data.Name, data.Version)
+	}
+	if data.Port == 0 {
+		data.Port = 8080
+	}
+
+	files, err := renderScaffold(data, catalog)
+	if err != nil {
+		return nil, err
+	}
+
+	paths := make([]string, 0, len(files))
+	for path := range files {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	if !force {
+		for _, path := range paths {
+			if _, err := os.Stat(filepath.Join(dir, filepath.FromSlash(path))); err == nil {
+				return nil, fmt.Errorf("%s already exists (use --force to overwrite)", filepath.Join(dir, path))
+			}
+		}
+	}
+
+	for _, path := range paths {
+		target := filepath.Join(dir, filepath.FromSlash(path))
+		if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+			return nil, fmt.Errorf("failed to create %s: %w", filepath.Dir(target), err)
+		}
+		if err := os.WriteFile(target, files[path], 0644); err != nil {
+			return nil, fmt.Errorf("failed to write %s: %w", path, err)
+		}
+	}
+
+	return paths, nil
+}
+
+// renderScaffold fills in the skeleton's files, keyed by slash path
+func renderScaffold(data ScaffoldData, catalog bool) (map[string][]byte, error) {
+	execute := func(name, content string, data interface{}) ([]byte, error) {
+		tmpl, err := template.New(name).Parse(content)
+		if err != nil {
+			return nil, err
+		}
+		var buf bytes.Buffer
+		if err := tmpl.Execute(&buf, data); err != nil {
+			return nil, fmt.Errorf("%s: %w", name, err)
+		}
+		return buf.Bytes(), nil
+	}
+
+	type versionData struct {
+		ScaffoldData
+		Indent string
+	}
+
+	if catalog {
+		serviceDir := "services/" + data.Category + "/" + data.Name
+		service, err := execute("service.yaml", serviceTemplate, data)
+		if err != nil {
+			return nil, err
+		}
+		version, err := execute("config.yaml", versionTemplate, versionData{ScaffoldData: data})
+		if err != nil {
+			return nil, err
+		}
+		return map[string][]byte{
+			serviceDir + "/service.yaml":                              service,
+			serviceDir + "/versions/" + data.Version + "/config.yaml": version,
+		}, nil
+	}
+
+	version, err := execute("config.yaml", versionTemplate, versionData{ScaffoldData: data, Indent: "    "})
+	if err != nil {
+		return nil, err
+	}
+	local, err := execute(data.Name+".yaml", localTemplate, struct {
+		ScaffoldData
+		VersionConfig string
+	}{data, string(version)})
+	if err != nil {
+		return nil, err
+	}
+	return map[string][]byte{data.Name + ".yaml": local}, nil
+}
@@ -0,0 +1,121 @@
+package catalog
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/dokulabs/doku-cli/pkg/types"
+)
+
+// Relevance scores for a single search term. Name matches rank above tag
+// matches, which rank above description matches.
+const (
+	scoreNameExact    = 100
+	scoreNamePrefix   = 80
+	scoreNameContains = 60
+	scoreTagExact     = 40
+	scoreTagContains  = 30
+	scoreCategory     = 20
+	scoreDescription  = 10
+)
+
+// SearchOptions narrows search results
+type SearchOptions struct {
+	Category string // Only services in this category
+	Tag      string // Only services with this tag
+}
+
+// SearchResult is a service with its relevance score
+type SearchResult struct {
+	Service *types.CatalogService
+	Score   int
+}
+
+// RankServices filters services by the options and orders them by relevance
+// to the query. Every whitespace-separated term of the query must match the
+// name, a tag, the category or the description. An empty query matches all
+// services, ordered by name.
+func RankServices(services []*types.CatalogService, query string, opts SearchOptions) []SearchResult {
+	terms := strings.Fields(strings.ToLower(query))
+	results := make([]SearchResult, 0)
+
+	for _, service := range services {
+		if opts.Category != "" && !strings.EqualFold(service.Category, opts.Category) {
+			continue
+		}
+		if opts.Tag != "" && !hasTag(service, opts.Tag) {
+			continue
+		}
+
+		total := 0
+		matched := true
+		for _, term := range terms {
+			score := scoreTerm(service, term)
+			if score == 0 {
+				matched = false
+				break
+			}
+			total += score
+		}
+
+		if matched {
+			results = append(results, SearchResult{Service: service, Score: total})
+		}
+	}
+
+	sort.SliceStable(results, func(i, j int) bool {
+		if results[i].Score != results[j].Score {
+			return results[i].Score > results[j].Score
+		}
+		return results[i].Service.Name < results[j].Service.Name
+	})
+
+	return results
+}
+
+// scoreTerm returns the best score of a single lowercase term for a service
+func scoreTerm(service *types.CatalogService, term string) int {
+	name := strings.ToLower(service.Name)
+	switch {
+	case name == term:
+		return scoreNameExact
+	case strings.HasPrefix(name, term):
+		return scoreNamePrefix
+	case strings.Contains(name, term):
+		return scoreNameContains
+	}
+
+	best := 0
+	for _, tag := range service.Tags {
+		tag = strings.ToLower(tag)
+		if tag == term {
+			return scoreTagExact
+		}
+		if strings.Contains(tag, term) {
+			best = scoreTagContains
+		}
+	}
+	if best > 0 {
+		return best
+	}
+
+	if strings.Contains(strings.ToLower(service.Category), term) {
+		return scoreCategory
+	}
+
+	if strings.Contains(strings.ToLower(service.Description), term) {
+		return scoreDescription
+	}
+
+	return 0
+}
+
+// hasTag reports whether a service has the given tag (case-insensitive)
+func hasTag(service *types.CatalogService, tag string) bool {
+	for _, t := range service.Tags {
+		if strings.EqualFold(t, tag) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,68 @@
+package catalog
+
+import (
+	"testing"
+
+	"github.com/dokulabs/doku-cli/pkg/types"
+)
+
+func testServices() []*types.CatalogService {
+	return []*types.CatalogService{
+		{Name: "postgres", Category: "database", Tags: []string{"sql", "relational"}, Description: "PostgreSQL database"},
+		{Name: "pgadmin", Category: "tools", Tags: []string{"postgres", "admin"}, Description: "Web UI for PostgreSQL"},
+		{Name: "timescale", Category: "database", Tags: []string{"sql", "timeseries"}, Description: "Time-series database built on postgres"},
+		{Name: "redis", Category: "cache", Tags: []string{"cache", "key-value"}, Description: "In-memory data store"},
+		{Name: "rabbitmq", Category: "queue", Tags: []string{"messaging", "amqp"}, Description: "Message broker"},
+	}
+}
+
+func TestRankServices(t *testing.T) {
+	results := RankServices(testServices(), "postgres", SearchOptions{})
+
+	expected := []string{"postgres", "pgadmin", "timescale"}
+	if len(results) != len(expected) {
+		t.Fatalf("Expected %d results, got %d", len(expected), len(results))
+	}
+
+	// Name match ranks above tag match, which ranks above description match
+	for i, name := range expected {
+		if results[i].Service.Name != name {
+			t.Errorf("Result %d: expected %s, got %s", i, name, results[i].Service.Name)
+		}
+	}
+}
+
+func TestRankServicesAllTermsMustMatch(t *testing.T) {
+	results := RankServices(testServices(), "sql time", SearchOptions{})
+	if len(results) != 1 || results[0].Service.Name != "timescale" {
+		t.Errorf("Expected only timescale, got %v", results)
+	}
+}
+
+func TestRankServicesFilters(t *testing.T) {
+	tests := []struct {
+		name     string
+		query    string
+		opts     SearchOptions
+		expected []string
+	}{
+		{"category", "", SearchOptions{Category: "Database"}, []string{"postgres", "timescale"}},
+		{"tag", "", SearchOptions{Tag: "sql"}, []string{"postgres", "timescale"}},
+		{"query and tag", "time", SearchOptions{Tag: "sql"}, []string{"timescale"}},
+		{"no match", "", SearchOptions{Category: "queue", Tag: "sql"}, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			results := RankServices(testServices(), tt.query, tt.opts)
+			if len(results) != len(tt.expected) {
+				t.Fatalf("Expected %v, got %d results", tt.expected, len(results))
+			}
+			for i, name := range tt.expected {
+				if results[i].Service.Name != name {
+					t.Errorf("Result %d: expected %s, got %s", i, name, results[i].Service.Name)
+				}
+			}
+		})
+	}
+}
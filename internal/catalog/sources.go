@@ -0,0 +1,277 @@
+package catalog
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/dokulabs/doku-cli/internal/readonly"
+	"github.com/dokulabs/doku-cli/pkg/types"
+)
+
+const (
+	// OfficialSource names the official catalog, whose services have no
+	// namespace
+	OfficialSource = "doku"
+
+	// SourcesDirName is the directory, next to the official catalog, that
+	// holds the other sources: ~/.doku/catalogs/<name>
+	SourcesDirName = "catalogs"
+
+	// sourcesFileName lists the registered sources, in SourcesDirName
+	sourcesFileName = "sources.yaml"
+)
+
+var sourceNameRe = regexp.MustCompile(`^[a-z0-9]([a-z0-9-]*[a-z0-9])?$`)
+
+// Source is a catalog registered besides the official one. Its services
+// are installed as <source>/<service>, and by their name alone when no
+// source of higher priority has a service of that name; the official
+// catalog has priority 0 and wins ties.
+type Source struct {
+	Name     string `yaml:"name"`
+	URL      string `yaml:"url"`
+	Priority int    `yaml:"priority,omitempty"`
+}
+
+// ValidateSourceName checks that a name can be used for a catalog source
+func ValidateSourceName(name string) error {
+	if name == OfficialSource {
+		return fmt.Errorf("'%s' is the official catalog", OfficialSource)
+	}
+	if name == LocalSource {
+		return fmt.Errorf("'%s' holds the services added with 'doku catalog add'", LocalSource)
+	}
+	if !sourceNameRe.MatchString(name) {
+		return fmt.Errorf("invalid source name %q: use lowercase letters, digits and dashes", name)
+	}
+	return nil
+}
+
+// SplitName splits a service name into its source and the name within it,
+// e.g. "mycompany/internal-api" into "mycompany" and "internal-api". The
+// source is empty for a name without one.
+func SplitName(name string) (source, service string) {
+	if i := strings.Index(name, "/"); i >= 0 {
+		return name[:i], name[i+1:]
+	}
+	return "", name
+}
+
+// BaseName returns a service name without its source, e.g. for instance
+// names and network aliases
+func BaseName(name string) string {
+	_, service := SplitName(name)
+	return service
+}
+
+func (m *Manager) sourcesFile() string {
+	return filepath.Join(m.sourcesDir, sourcesFileName)
+}
+
+// SourceDir returns the directory a source's catalog is extracted to
+func (m *Manager) SourceDir(name string) string {
+	return filepath.Join(m.sourcesDir, name)
+}
+
+// ListSources returns the registered sources, highest priority first
+func (m *Manager) ListSources() ([]Source, error) {
+	if m.sourcesDir == "" {
+		return nil, nil
+	}
+
+	data, err := os.ReadFile(m.sourcesFile())
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read catalog sources: %w", err)
+	}
+
+	var sources []Source
+	if err := yaml.Unmarshal(data, &sources); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", m.sourcesFile(), err)
+	}
+	sort.SliceStable(sources, func(i, j int) bool {
+		if sources[i].Priority != sources[j].Priority {
+			return sources[i].Priority > sources[j].Priority
+		}
+		return sources[i].Name < sources[j].Name
+	})
+	return sources, nil
+}
+
+// AddSource registers a source, or updates the URL and priority of one
+// registered before. Its catalog is downloaded by FetchSource.
+func (m *Manager) AddSource(source Source) error {
+	if err := readonly.Check("add catalog source " + source.Name); err != nil {
+		return err
+	}
+	if err := ValidateSourceName(source.Name); err != nil {
+		return err
+	}
+	if source.URL == "" {
+		return fmt.Errorf("source %s has no URL", source.Name)
+	}
+
+	sources, err := m.ListSources()
+	if err != nil {
+		return err
+	}
+	replaced := false
+	for i := range sources {
+		if sources[i].Name == source.Name {
+			sources[i] = source
+			replaced = true
+		}
+	}
+	if !replaced {
+		sources = append(sources, source)
+	}
+	return m.saveSources(sources)
+}
+
+// RemoveSource unregisters a source and deletes its catalog
+func (m *Manager) RemoveSource(name string) error {
+	if err := readonly.Check("remove catalog source " + name); err != nil {
+		return err
+	}
+
+	sources, err := m.ListSources()
+	if err != nil {
+		return err
+	}
+	kept := sources[:0]
+	for _, source := range sources {
+		if source.Name != name {
+			kept = append(kept, source)
+		}
+	}
+	if len(kept) == len(sources) {
+		return fmt.Errorf("catalog source '%s' not found", name)
+	}
+	if err := m.saveSources(kept); err != nil {
+		return err
+	}
+
+	sourceMgr := &Manager{catalogDir: m.SourceDir(name)}
+	sourceMgr.InvalidateCache()
+	return os.RemoveAll(sourceMgr.catalogDir)
+}
+
+func (m *Manager) saveSources(sources []Source) error {
+	if err := os.MkdirAll(m.sourcesDir, 0755); err != nil {
+		return fmt.Errorf("failed to create %s: %w", m.sourcesDir, err)
+	}
+	data, err := yaml.Marshal(sources)
+	if err != nil {
+		return fmt.Errorf("failed to encode catalog sources: %w", err)
+	}
+	if err := os.WriteFile(m.sourcesFile(), data, 0644); err != nil {
+		return fmt.Errorf("failed to save catalog sources: %w", err)
+	}
+	return nil
+}
+
+// sourceManager returns a manager for a source's own catalog
+func (m *Manager) sourceManager(source Source) *Manager {
+	return &Manager{
+		catalogDir: m.SourceDir(source.Name),
+		catalogURL: source.URL,
+	}
+}
+
+// FetchSource downloads the catalog of a registered source
+func (m *Manager) FetchSource(name string) error {
+	sources, err := m.ListSources()
+	if err != nil {
+		return err
+	}
+	for _, source := range sources {
+		if source.Name == name {
+			return m.sourceManager(source).FetchCatalog()
+		}
+	}
+	return fmt.Errorf("catalog source '%s' not found", name)
+}
+
+// LoadSource loads the catalog of a registered source by itself
+func (m *Manager) LoadSource(source Source) (*types.ServiceCatalog, error) {
+	return m.sourceManager(source).LoadCatalog()
+}
+
+// mergeSources adds the services of the registered sources to the official
+// catalog, as <source>/<service>. Sources not downloaded yet are skipped.
+func (m *Manager) mergeSources(official *types.ServiceCatalog, sources []Source) (*types.ServiceCatalog, error) {
+	merged := &types.ServiceCatalog{
+		Version:  official.Version,
+		Services: make(map[string]*types.CatalogService, len(official.Services)),
+	}
+	for name, service := range official.Services {
+		merged.Services[name] = service
+	}
+
+	for _, source := range sources {
+		sourceMgr := m.sourceManager(source)
+		if !sourceMgr.CatalogExists() {
+			continue
+		}
+		catalog, err := sourceMgr.LoadCatalog()
+		if err != nil {
+			return nil, fmt.Errorf("catalog source %s: %w", source.Name, err)
+		}
+		for name, service := range catalog.Services {
+			namespaced := *service
+			namespaced.Name = source.Name + "/" + name
+			merged.Services[namespaced.Name] = &namespaced
+		}
+	}
+	return merged, nil
+}
+
+// ResolveName returns the name of a service in the catalog with its source,
+// e.g. mycompany/internal-api for internal-api when that source provides it
+func (m *Manager) ResolveName(serviceName string) (string, error) {
+	catalog, err := m.LoadCatalog()
+	if err != nil {
+		return "", err
+	}
+	sources, err := m.allSources()
+	if err != nil {
+		return "", err
+	}
+	return resolveName(catalog, sources, serviceName), nil
+}
+
+// resolveName returns the key of a service in the merged catalog. A name
+// without a source is looked up in every source, and the one with the
+// highest priority that has it wins; the official catalog wins ties.
+func resolveName(catalog *types.ServiceCatalog, sources []Source, name string) string {
+	source, service := SplitName(name)
+	if source == OfficialSource {
+		return service
+	}
+	if source != "" {
+		return name
+	}
+
+	_, official := catalog.Services[name]
+	// Sources are sorted by priority, so the first one that has the service
+	// is the best one
+	for _, source := range sources {
+		qualified := source.Name + "/" + name
+		if _, ok := catalog.Services[qualified]; !ok {
+			continue
+		}
+		if !official || source.Priority > 0 {
+			return qualified
+		}
+		break
+	}
+	return name
+}
@@ -0,0 +1,106 @@
+package catalog
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// writeSourceCatalog writes a source's catalog with a service per image
+func writeSourceCatalog(t *testing.T, dir string, images map[string]string) {
+	t.Helper()
+
+	files := map[string]string{filepath.Join(dir, CatalogFileName): "version: \"1.0\"\n"}
+	for name, image := range images {
+		serviceDir := filepath.Join(dir, "services", "misc", name)
+		files[filepath.Join(serviceDir, "service.yaml")] = "name: " + name + "\n"
+		files[filepath.Join(serviceDir, "versions", "1", "config.yaml")] = "image: " + image + "\nport: 80\n"
+	}
+	for path, content := range files {
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestSources(t *testing.T) {
+	dokuDir := t.TempDir()
+	catalogDir := filepath.Join(dokuDir, "catalog")
+	writeSourceCatalog(t, catalogDir, map[string]string{"postgres": "postgres:16"})
+	defer forgetMemCache(catalogDir)
+
+	mgr := NewManager(catalogDir)
+	if err := mgr.AddSource(Source{Name: OfficialSource, URL: "x"}); err == nil {
+		t.Error("AddSource() accepted the official catalog's name")
+	}
+	if err := mgr.AddSource(Source{Name: "mycompany", URL: "https://example.com/catalog.tar.gz"}); err != nil {
+		t.Fatalf("AddSource() error = %v", err)
+	}
+	sourceDir := mgr.SourceDir("mycompany")
+	writeSourceCatalog(t, sourceDir, map[string]string{"postgres": "corp/postgres:16", "internal-api": "corp/api:1"})
+	defer forgetMemCache(sourceDir)
+
+	services, err := mgr.ListServices()
+	if err != nil {
+		t.Fatalf("ListServices() error = %v", err)
+	}
+	if len(services) != 3 {
+		t.Errorf("ListServices() returned %d services, want 3", len(services))
+	}
+
+	tests := []struct {
+		name  string
+		image string
+	}{
+		{"postgres", "postgres:16"},                // The official catalog wins ties
+		{"doku/postgres", "postgres:16"},           // Explicitly official
+		{"mycompany/postgres", "corp/postgres:16"}, // Namespaced
+		{"internal-api", "corp/api:1"},             // Only in the source
+	}
+	for _, tt := range tests {
+		spec, err := mgr.GetServiceVersion(tt.name, "")
+		if err != nil {
+			t.Errorf("GetServiceVersion(%s) error = %v", tt.name, err)
+			continue
+		}
+		if spec.Image != tt.image {
+			t.Errorf("GetServiceVersion(%s) image = %s, want %s", tt.name, spec.Image, tt.image)
+		}
+	}
+
+	// A higher priority replaces the official service
+	if err := mgr.AddSource(Source{Name: "mycompany", URL: "https://example.com/catalog.tar.gz", Priority: 10}); err != nil {
+		t.Fatalf("AddSource() error = %v", err)
+	}
+	if spec, err := mgr.GetServiceVersion("postgres", ""); err != nil || spec.Image != "corp/postgres:16" {
+		t.Errorf("GetServiceVersion(postgres) with priority 10 = %v, %v, want corp/postgres:16", spec, err)
+	}
+
+	if err := mgr.RemoveSource("mycompany"); err != nil {
+		t.Fatalf("RemoveSource() error = %v", err)
+	}
+	if _, err := os.Stat(sourceDir); !os.IsNotExist(err) {
+		t.Error("RemoveSource() kept the source's catalog")
+	}
+	if _, err := mgr.GetService("internal-api"); err == nil {
+		t.Error("GetService() found a service of a removed source")
+	}
+	if err := mgr.RemoveSource("mycompany"); err == nil {
+		t.Error("RemoveSource() of an unknown source succeeded")
+	}
+}
+
+func TestSplitName(t *testing.T) {
+	if source, service := SplitName("mycompany/internal-api"); source != "mycompany" || service != "internal-api" {
+		t.Errorf("SplitName() = %q, %q", source, service)
+	}
+	if source, service := SplitName("postgres"); source != "" || service != "postgres" {
+		t.Errorf("SplitName() = %q, %q", source, service)
+	}
+	if got := BaseName("mycompany/internal-api"); got != "internal-api" {
+		t.Errorf("BaseName() = %q", got)
+	}
+}
@@ -0,0 +1,493 @@
+package catalog
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/dokulabs/doku-cli/internal/docker"
+	"github.com/dokulabs/doku-cli/pkg/types"
+)
+
+// Issue is a problem found in a catalog file
+type Issue struct {
+	File    string // Path relative to the catalog directory
+	Line    int    // Line in File, 0 when not known
+	Message string
+	Warning bool // Warnings don't stop the catalog from loading
+}
+
+func (i Issue) String() string {
+	if i.Line > 0 {
+		return fmt.Sprintf("%s:%d: %s", i.File, i.Line, i.Message)
+	}
+	return fmt.Sprintf("%s: %s", i.File, i.Message)
+}
+
+// yamlLineRe finds the line number in YAML parse errors
+var yamlLineRe = regexp.MustCompile(`line (\d+)`)
+
+// unknownFieldRe matches the strict decoding error for an unknown field
+var unknownFieldRe = regexp.MustCompile(`^line (\d+): field (\S+) not found`)
+
+// versionFile is a loaded version config.yaml
+type versionFile struct {
+	path string
+	node *yaml.Node
+	spec *types.ServiceSpec
+}
+
+// catalogValidator collects the issues of a hierarchical catalog
+type catalogValidator struct {
+	dir      string
+	issues   []Issue
+	services map[string]map[string]*versionFile // Service ID → version → config
+	lint     bool                               // Also check the schema, see Lint
+}
+
+// Validate checks a hierarchical catalog directory for authors: files that
+// don't parse, missing fields, port conflicts within a spec, multi-container
+// services without a clear primary container, invalid resource strings and
+// dependencies that don't resolve. Issues are sorted by file and line.
+func Validate(dir string) ([]Issue, error) {
+	return validate(dir, false)
+}
+
+func validate(dir string, lint bool) ([]Issue, error) {
+	if _, err := os.Stat(filepath.Join(dir, "catalog.yaml")); err != nil {
+		return nil, fmt.Errorf("not a catalog directory (no catalog.yaml): %s", dir)
+	}
+
+	v := &catalogValidator{dir: dir, services: make(map[string]map[string]*versionFile), lint: lint}
+	v.checkMetadata()
+	v.loadServices()
+	for id, versions := range v.services {
+		for _, file := range versions {
+			v.checkSpec(id, file)
+		}
+	}
+
+	sort.SliceStable(v.issues, func(i, j int) bool {
+		if v.issues[i].File != v.issues[j].File {
+			return v.issues[i].File < v.issues[j].File
+		}
+		return v.issues[i].Line < v.issues[j].Line
+	})
+	return v.issues, nil
+}
+
+func (v *catalogValidator) errorf(path string, line int, format string, args ...interface{}) {
+	v.issues = append(v.issues, Issue{File: v.rel(path), Line: line, Message: fmt.Sprintf(format, args...)})
+}
+
+func (v *catalogValidator) warnf(path string, line int, format string, args ...interface{}) {
+	v.issues = append(v.issues, Issue{File: v.rel(path), Line: line, Message: fmt.Sprintf(format, args...), Warning: true})
+}
+
+func (v *catalogValidator) rel(path string) string {
+	if rel, err := filepath.Rel(v.dir, path); err == nil {
+		return filepath.ToSlash(rel)
+	}
+	return path
+}
+
+// decode parses a YAML file into out, keeping its node tree for line
+// numbers. Problems are recorded as issues and reported by returning nil.
+func (v *catalogValidator) decode(path string, out interface{}) *yaml.Node {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		v.errorf(path, 0, "%v", err)
+		return nil
+	}
+
+	var node yaml.Node
+	if err := yaml.Unmarshal(data, &node); err != nil {
+		v.errorf(path, yamlErrorLine(err.Error()), "invalid YAML: %s", strings.TrimPrefix(err.Error(), "yaml: "))
+		return nil
+	}
+	var typeErr *yaml.TypeError
+	if err := node.Decode(out); errors.As(err, &typeErr) {
+		for _, msg := range typeErr.Errors {
+			line := yamlErrorLine(msg)
+			v.errorf(path, line, "%s", strings.TrimPrefix(msg, fmt.Sprintf("line %d: ", line)))
+		}
+		return nil
+	} else if err != nil {
+		v.errorf(path, yamlErrorLine(err.Error()), "%s", strings.TrimPrefix(err.Error(), "yaml: "))
+		return nil
+	}
+
+	// Unknown fields are ignored when loading, which hides typos
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(reflect.New(reflect.TypeOf(out).Elem()).Interface()); errors.As(err, &typeErr) {
+		for _, msg := range typeErr.Errors {
+			if m := unknownFieldRe.FindStringSubmatch(msg); m != nil {
+				line, _ := strconv.Atoi(m[1])
+				v.warnf(path, line, "unknown field '%s' is ignored", m[2])
+			}
+		}
+	}
+	return &node
+}
+
+func (v *catalogValidator) checkMetadata() {
+	path := filepath.Join(v.dir, "catalog.yaml")
+	var metadata CatalogMetadata
+	node := v.decode(path, &metadata)
+	if node == nil {
+		return
+	}
+	if metadata.Version == "" {
+		v.errorf(path, 0, "version is missing")
+	}
+}
+
+// loadServices reads every service.yaml and version config.yaml
+func (v *catalogValidator) loadServices() {
+	servicesDir := filepath.Join(v.dir, "services")
+	categories, err := os.ReadDir(servicesDir)
+	if err != nil {
+		v.errorf(servicesDir, 0, "services directory not found")
+		return
+	}
+
+	for _, category := range categories {
+		if !category.IsDir() {
+			continue
+		}
+		entries, err := os.ReadDir(filepath.Join(servicesDir, category.Name()))
+		if err != nil {
+			continue
+		}
+		for _, entry := range entries {
+			if entry.IsDir() {
+				v.loadService(entry.Name(), filepath.Join(servicesDir, category.Name(), entry.Name()))
+			}
+		}
+	}
+
+	if len(v.services) == 0 {
+		v.errorf(servicesDir, 0, "catalog contains no services")
+	}
+}
+
+func (v *catalogValidator) loadService(id, serviceDir string) {
+	servicePath := filepath.Join(serviceDir, "service.yaml")
+	var metadata ServiceMetadata
+	node := v.decode(servicePath, &metadata)
+	if node == nil {
+		return
+	}
+	if metadata.Name == "" {
+		v.errorf(servicePath, 0, "name is missing")
+	}
+	if v.lint {
+		v.lintName(servicePath, id)
+		v.lintMetadata(servicePath, metadata.Description, metadata.Category)
+	}
+
+	versions := make(map[string]*versionFile)
+	v.services[id] = versions
+
+	versionsDir := filepath.Join(serviceDir, "versions")
+	entries, err := os.ReadDir(versionsDir)
+	if err != nil {
+		v.errorf(servicePath, 0, "service has no versions directory")
+		return
+	}
+	found := 0
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		found++
+		path := filepath.Join(versionsDir, entry.Name(), "config.yaml")
+		var config VersionConfig
+		versionNode := v.decode(path, &config)
+		if versionNode == nil {
+			continue
+		}
+		versions[entry.Name()] = &versionFile{path: path, node: versionNode, spec: config.spec()}
+	}
+
+	if found == 0 {
+		v.errorf(servicePath, 0, "service has no versions")
+	}
+	if metadata.LatestVersion != "" && versions[metadata.LatestVersion] == nil {
+		v.errorf(servicePath, keyLine(node, "latest_version"), "latest_version '%s' has no versions/%s/config.yaml", metadata.LatestVersion, metadata.LatestVersion)
+	}
+	for i, version := range metadata.AvailableVersions {
+		if versions[version] == nil {
+			v.warnf(servicePath, keyLine(node, "available_versions", strconv.Itoa(i)), "available version '%s' has no versions/%s/config.yaml", version, version)
+		}
+	}
+}
+
+// checkSpec checks one version of a service
+func (v *catalogValidator) checkSpec(id string, file *versionFile) {
+	spec, path, node := file.spec, file.path, file.node
+
+	switch {
+	case spec.Image == "" && len(spec.Containers) == 0:
+		v.errorf(path, 0, "either image or containers is required")
+	case spec.Image != "" && len(spec.Containers) > 0:
+		v.errorf(path, keyLine(node, "containers"), "image and containers cannot both be set")
+	case spec.Image != "" && spec.Port == 0:
+		v.errorf(path, keyLine(node, "image"), "port is required")
+	}
+
+	if spec.HostNetwork {
+		if len(spec.Containers) > 0 {
+			v.errorf(path, keyLine(node, "host_network"), "host_network is only supported for single-container services")
+		}
+		if len(spec.Ports) > 0 {
+			v.warnf(path, keyLine(node, "ports"), "ports are ignored with host_network: the container uses the host's ports")
+		}
+	}
+
+	// Host ports mapped more than once in this spec
+	hostPorts := make(map[int]string)
+	checkPorts := func(ports []string, where string, linePath ...string) {
+		for i, mapping := range ports {
+			line := keyLine(node, append(linePath, strconv.Itoa(i))...)
+			host, err := hostPort(mapping)
+			if err != nil {
+				v.errorf(path, line, "%s: %v", where, err)
+				continue
+			}
+			if host == 0 {
+				continue
+			}
+			if other, ok := hostPorts[host]; ok {
+				v.errorf(path, line, "%s: host port %d is already mapped by %s", where, host, other)
+				continue
+			}
+			hostPorts[host] = where
+		}
+	}
+	checkPorts(spec.Ports, "ports", "ports")
+
+	v.checkResources(path, node, spec.Resources, "resources")
+	v.checkHealthcheck(path, node, spec.Healthcheck, "healthcheck")
+
+	names := make(map[string]bool)
+	primaries := 0
+	for i, c := range spec.Containers {
+		idx := strconv.Itoa(i)
+		line := keyLine(node, "containers", idx)
+		switch {
+		case c.Name == "":
+			v.errorf(path, line, "container has no name")
+		case names[c.Name]:
+			v.errorf(path, line, "duplicate container name '%s'", c.Name)
+		}
+		names[c.Name] = true
+		if c.Image == "" {
+			v.errorf(path, line, "container '%s' has no image", c.Name)
+		}
+		if c.Primary {
+			primaries++
+		}
+		checkPorts(c.Ports, fmt.Sprintf("container '%s'", c.Name), "containers", idx, "ports")
+		v.checkResources(path, node, c.Resources, "containers", idx, "resources")
+		v.checkHealthcheck(path, node, c.Healthcheck, "containers", idx, "healthcheck")
+	}
+	for _, c := range spec.InitContainers {
+		names[c.Name] = true
+	}
+
+	if len(spec.Containers) > 1 {
+		switch {
+		case primaries > 1:
+			v.errorf(path, keyLine(node, "containers"), "%d containers are marked primary; only one can be", primaries)
+		case primaries == 0:
+			v.warnf(path, keyLine(node, "containers"), "no container is marked primary: true, so '%s' is used", spec.Containers[0].Name)
+		}
+	}
+
+	// Dependencies on other services and between containers
+	for i, dep := range spec.Dependencies {
+		line := keyLine(node, "dependencies_v2", strconv.Itoa(i))
+		if line == 0 {
+			line = keyLine(node, "dependencies", strconv.Itoa(i))
+		}
+		versions, ok := v.services[dep.Name]
+		switch {
+		case dep.Name == id:
+			v.errorf(path, line, "service depends on itself")
+		case !ok:
+			v.errorf(path, line, "dependency '%s' is not in the catalog", dep.Name)
+		case IsConstraint(dep.Version):
+			v.checkConstraint(path, line, dep, versions)
+		case dep.Version != "" && dep.Version != "latest" && versions[dep.Version] == nil:
+			v.errorf(path, line, "dependency '%s' has no version '%s'", dep.Name, dep.Version)
+		}
+	}
+	for i, c := range spec.Containers {
+		for j, dep := range c.DependsOn {
+			if !names[dep] && v.services[dep] == nil {
+				v.errorf(path, keyLine(node, "containers", strconv.Itoa(i), "depends_on", strconv.Itoa(j)),
+					"container '%s' depends on '%s', which is neither a container of this service nor a catalog service", c.Name, dep)
+			}
+		}
+	}
+	for i, c := range spec.InitContainers {
+		for j, dep := range c.DependsOn {
+			if !names[dep] && v.services[dep] == nil {
+				v.errorf(path, keyLine(node, "init_containers", strconv.Itoa(i), "depends_on", strconv.Itoa(j)),
+					"init container '%s' depends on '%s', which is neither a container of this service nor a catalog service", c.Name, dep)
+			}
+		}
+	}
+
+	if v.lint {
+		v.lintSpec(path, node, spec)
+	}
+}
+
+// checkConstraint checks that a dependency's version range parses and is
+// satisfied by a version of the dependency
+func (v *catalogValidator) checkConstraint(path string, line int, dep types.DependencySpec, versions map[string]*versionFile) {
+	constraint, err := ParseConstraint(dep.Version)
+	if err != nil {
+		v.errorf(path, line, "dependency '%s': %v", dep.Name, err)
+		return
+	}
+	for version := range versions {
+		if constraint.Matches(version) {
+			return
+		}
+	}
+	v.errorf(path, line, "no version of dependency '%s' satisfies '%s'", dep.Name, dep.Version)
+}
+
+// checkResources checks memory and CPU strings such as "512m" and "0.5"
+func (v *catalogValidator) checkResources(path string, node *yaml.Node, res *types.ResourceRequirements, linePath ...string) {
+	if res == nil {
+		return
+	}
+	line := keyLine(node, linePath...)
+	for _, mem := range []string{res.MemoryMin, res.MemoryMax} {
+		if mem == "" {
+			continue
+		}
+		if _, err := docker.ParseMemoryString(mem); err != nil {
+			v.errorf(path, line, "invalid memory %q: %v", mem, err)
+		}
+	}
+	for _, cpu := range []string{res.CPUMin, res.CPUMax} {
+		if cpu == "" {
+			continue
+		}
+		if _, _, err := docker.ParseCPUString(cpu); err != nil {
+			v.errorf(path, line, "invalid CPU %q: %v", cpu, err)
+		}
+	}
+}
+
+// checkHealthcheck checks a healthcheck's durations such as "30s"
+func (v *catalogValidator) checkHealthcheck(path string, node *yaml.Node, hc *types.Healthcheck, linePath ...string) {
+	if hc == nil {
+		return
+	}
+	if len(hc.Test) == 0 {
+		v.errorf(path, keyLine(node, linePath...), "healthcheck has no test")
+	}
+	for key, value := range map[string]string{"interval": hc.Interval, "timeout": hc.Timeout, "start_period": hc.Start} {
+		if value == "" {
+			continue
+		}
+		if _, err := time.ParseDuration(value); err != nil {
+			v.errorf(path, keyLine(node, append(linePath, key)...), "healthcheck %s %q is not a duration such as 30s", key, value)
+		}
+	}
+}
+
+// hostPort returns the host side of a port mapping such as "9000:9000" or
+// "127.0.0.1:9000:9000/tcp", or 0 for a container port only or a range
+func hostPort(mapping string) (int, error) {
+	spec, _, _ := strings.Cut(mapping, "/")
+	parts := strings.Split(spec, ":")
+
+	var host string
+	switch len(parts) {
+	case 1:
+	case 2:
+		host = parts[0]
+	case 3:
+		host = parts[1]
+	default:
+		return 0, fmt.Errorf("invalid port mapping %q", mapping)
+	}
+	if strings.Contains(spec, "-") {
+		return 0, nil
+	}
+	if _, err := strconv.Atoi(parts[len(parts)-1]); err != nil {
+		return 0, fmt.Errorf("invalid port mapping %q", mapping)
+	}
+	if host == "" {
+		return 0, nil
+	}
+	port, err := strconv.Atoi(host)
+	if err != nil || port <= 0 || port > 65535 {
+		return 0, fmt.Errorf("invalid host port in %q", mapping)
+	}
+	return port, nil
+}
+
+// keyLine returns the line of a value in a YAML document, following a path
+// of mapping keys and sequence indexes. It stops at the deepest node found,
+// so a missing key gives the line of its parent; 0 if nothing matches.
+func keyLine(node *yaml.Node, path ...string) int {
+	if node == nil {
+		return 0
+	}
+	if node.Kind == yaml.DocumentNode && len(node.Content) > 0 {
+		node = node.Content[0]
+	}
+
+	line := 0
+	for _, key := range path {
+		var next *yaml.Node
+		switch node.Kind {
+		case yaml.MappingNode:
+			for i := 0; i+1 < len(node.Content); i += 2 {
+				if node.Content[i].Value == key {
+					line = node.Content[i].Line
+					next = node.Content[i+1]
+					break
+				}
+			}
+		case yaml.SequenceNode:
+			if i, err := strconv.Atoi(key); err == nil && i < len(node.Content) {
+				next = node.Content[i]
+				line = next.Line
+			}
+		}
+		if next == nil {
+			break
+		}
+		node = next
+	}
+	return line
+}
+
+// yamlErrorLine returns the line number in a YAML error message, or 0
+func yamlErrorLine(msg string) int {
+	if m := yamlLineRe.FindStringSubmatch(msg); m != nil {
+		line, _ := strconv.Atoi(m[1])
+		return line
+	}
+	return 0
+}
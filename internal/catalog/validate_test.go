@@ -0,0 +1,166 @@
+package catalog
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// writeCatalog writes files, keyed by path, into a temporary catalog
+func writeCatalog(t *testing.T, files map[string]string) string {
+	t.Helper()
+	dir := t.TempDir()
+	for name, content := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+func TestValidate(t *testing.T) {
+	dir := writeCatalog(t, map[string]string{
+		"catalog.yaml": "version: \"1.0\"\n",
+		"services/database/postgres/service.yaml": "name: PostgreSQL\nlatest_version: \"16\"\n",
+		"services/database/postgres/versions/16/config.yaml": `image: postgres:16
+port: 5432
+ports:
+  - "5432:5432"
+  - "5432:5433"
+resources:
+  memorymax: lots
+  memory_limit: 1g
+`,
+		"services/observability/signoz/service.yaml": "name: SigNoz\nlatest_version: \"1\"\n",
+		"services/observability/signoz/versions/1/config.yaml": `port: 3301
+containers:
+  - name: frontend
+    image: signoz/frontend
+    ports: ["3301:3301"]
+  - name: query
+    image: signoz/query
+    depends_on: [clickhouse]
+    healthcheck:
+      test: ["CMD", "true"]
+      interval: often
+dependencies_v2:
+  - name: postgres
+    version: "15"
+  - name: zookeeper
+  - name: postgres
+    version: ">=17"
+`,
+		"services/cache/redis/service.yaml":           "name: Redis\nlatest_version: \"8\"\n",
+		"services/cache/redis/versions/7/config.yaml": "image: redis:7\nport: 6379\nport: 6380\n",
+	})
+
+	issues, err := Validate(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got []string
+	for _, issue := range issues {
+		got = append(got, issue.String())
+	}
+	all := strings.Join(got, "\n")
+
+	for _, want := range []string{
+		"services/cache/redis/service.yaml:2: latest_version '8' has no versions/8/config.yaml",
+		"services/cache/redis/versions/7/config.yaml:3: mapping key \"port\" already defined at line 2",
+		"services/database/postgres/versions/16/config.yaml:5: ports: host port 5432 is already mapped by ports",
+		"services/database/postgres/versions/16/config.yaml:6: invalid memory \"lots\"",
+		"services/database/postgres/versions/16/config.yaml:8: unknown field 'memory_limit' is ignored",
+		"services/observability/signoz/versions/1/config.yaml:2: no container is marked primary: true, so 'frontend' is used",
+		"services/observability/signoz/versions/1/config.yaml:8: container 'query' depends on 'clickhouse'",
+		"services/observability/signoz/versions/1/config.yaml:11: healthcheck interval \"often\" is not a duration",
+		"services/observability/signoz/versions/1/config.yaml:13: dependency 'postgres' has no version '15'",
+		"services/observability/signoz/versions/1/config.yaml:15: dependency 'zookeeper' is not in the catalog",
+		"services/observability/signoz/versions/1/config.yaml:16: no version of dependency 'postgres' satisfies '>=17'",
+	} {
+		if !strings.Contains(all, want) {
+			t.Errorf("missing issue %q in:\n%s", want, all)
+		}
+	}
+	if len(got) != 11 {
+		t.Errorf("got %d issues, want 11:\n%s", len(got), all)
+	}
+}
+
+func TestValidateHostNetwork(t *testing.T) {
+	dir := writeCatalog(t, map[string]string{
+		"catalog.yaml":                        "version: \"1.0\"\n",
+		"services/network/avahi/service.yaml": "name: Avahi\nlatest_version: \"1\"\n",
+		"services/network/avahi/versions/1/config.yaml": `image: avahi:1
+port: 5353
+host_network: true
+ports:
+  - "5353:5353"
+`,
+		"services/network/scanner/service.yaml": "name: Scanner\nlatest_version: \"1\"\n",
+		"services/network/scanner/versions/1/config.yaml": `port: 8080
+host_network: true
+containers:
+  - name: web
+    image: scanner/web
+`,
+	})
+
+	issues, err := Validate(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got []string
+	for _, issue := range issues {
+		got = append(got, issue.String())
+	}
+	all := strings.Join(got, "\n")
+
+	for _, want := range []string{
+		"services/network/avahi/versions/1/config.yaml:4: ports are ignored with host_network",
+		"services/network/scanner/versions/1/config.yaml:2: host_network is only supported for single-container services",
+	} {
+		if !strings.Contains(all, want) {
+			t.Errorf("missing issue %q in:\n%s", want, all)
+		}
+	}
+	if len(got) != 2 {
+		t.Errorf("got %d issues, want 2:\n%s", len(got), all)
+	}
+}
+
+func TestValidateNotACatalog(t *testing.T) {
+	if _, err := Validate(t.TempDir()); err == nil {
+		t.Error("Validate() should fail without catalog.yaml")
+	}
+}
+
+func TestHostPort(t *testing.T) {
+	tests := []struct {
+		mapping string
+		want    int
+	}{
+		{"9000", 0},
+		{"9000:9000", 9000},
+		{"9001:9000/udp", 9001},
+		{"127.0.0.1:8080:80", 8080},
+		{"8000-8010:8000-8010", 0},
+	}
+	for _, tt := range tests {
+		got, err := hostPort(tt.mapping)
+		if err != nil || got != tt.want {
+			t.Errorf("hostPort(%q) = %d, %v, want %d", tt.mapping, got, err, tt.want)
+		}
+	}
+	for _, mapping := range []string{"web:80", "80:http", "70000:80"} {
+		if _, err := hostPort(mapping); err == nil {
+			t.Errorf("hostPort(%q) should fail", mapping)
+		}
+	}
+}
@@ -0,0 +1,366 @@
+package certs
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/dokulabs/doku-cli/internal/domain"
+	"github.com/dokulabs/doku-cli/internal/readonly"
+	"github.com/dokulabs/doku-cli/pkg/types"
+)
+
+// Where the certificates come from
+const (
+	// SourceMkcert issues them with mkcert and its local CA
+	SourceMkcert = "mkcert"
+	// SourceCA issues them from an imported CA, e.g. a company's
+	SourceCA = "ca"
+	// SourceProvided serves certificates issued elsewhere and imported
+	SourceProvided = "provided"
+)
+
+// leafValidity is how long a certificate issued from an imported CA is
+// valid, like mkcert's
+const leafValidity = 825 * 24 * time.Hour
+
+// Source returns where the certificates of a configuration come from
+func Source(c types.CertificatesConfig) string {
+	if c.Source == "" {
+		return SourceMkcert
+	}
+	return c.Source
+}
+
+// Configure sets where the manager's certificates come from
+func (m *Manager) Configure(c types.CertificatesConfig) *Manager {
+	m.source = Source(c)
+	m.caCert = c.CACert
+	m.caKey = c.CAKey
+	return m
+}
+
+// SetHosts sets the hostnames and IP addresses the manager's certificate
+// covers, e.g. a service's hostname and extra names its clients connect
+// to, instead of the domain and its subdomains. The certificate is still
+// named after the domain.
+func (m *Manager) SetHosts(hosts []string) *Manager {
+	m.hosts = hosts
+	return m
+}
+
+// names returns the hostnames and IP addresses the certificate covers
+func (m *Manager) names() []string {
+	if len(m.hosts) > 0 {
+		return m.hosts
+	}
+	return []string{m.domain, "*." + m.domain}
+}
+
+// ValidateSAN checks a name can be added to a certificate: an IP address
+// or a hostname, without wildcard
+func ValidateSAN(name string) error {
+	if net.ParseIP(name) != nil {
+		return nil
+	}
+	if err := domain.Validate(name); err != nil {
+		return fmt.Errorf("invalid SAN '%s': expected a hostname or an IP address", name)
+	}
+	return nil
+}
+
+// NeedsMkcert reports whether the manager issues certificates with mkcert
+func (m *Manager) NeedsMkcert() bool {
+	return m.source == "" || m.source == SourceMkcert
+}
+
+// LoadCA reads a CA certificate and its private key, checking that they
+// belong together and that the certificate may sign others
+func LoadCA(certPath, keyPath string) (*x509.Certificate, crypto.Signer, error) {
+	chain, err := readCertificates(certPath)
+	if err != nil {
+		return nil, nil, err
+	}
+	ca := chain[0]
+	if !ca.IsCA || (ca.KeyUsage != 0 && ca.KeyUsage&x509.KeyUsageCertSign == 0) {
+		return nil, nil, fmt.Errorf("%s is not a CA certificate: it can't sign others", certPath)
+	}
+	if time.Now().After(ca.NotAfter) {
+		return nil, nil, fmt.Errorf("the CA certificate %s expired on %s", certPath, ca.NotAfter.Format("2006-01-02"))
+	}
+
+	key, err := readPrivateKey(keyPath)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !publicKeysMatch(ca.PublicKey, key.Public()) {
+		return nil, nil, fmt.Errorf("the key %s doesn't belong to the certificate %s", keyPath, certPath)
+	}
+	return ca, key, nil
+}
+
+// ImportCA copies a CA certificate and key into dir as ca.pem and
+// ca-key.pem, and returns the configuration issuing from them
+func ImportCA(certPath, keyPath, dir string) (types.CertificatesConfig, error) {
+	if err := readonly.Check("import the CA " + certPath); err != nil {
+		return types.CertificatesConfig{}, err
+	}
+	if _, _, err := LoadCA(certPath, keyPath); err != nil {
+		return types.CertificatesConfig{}, err
+	}
+
+	c := types.CertificatesConfig{
+		Source: SourceCA,
+		CACert: filepath.Join(dir, "ca.pem"),
+		CAKey:  filepath.Join(dir, "ca-key.pem"),
+	}
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return types.CertificatesConfig{}, fmt.Errorf("failed to create %s: %w", dir, err)
+	}
+	if err := copyFile(certPath, c.CACert, 0644); err != nil {
+		return types.CertificatesConfig{}, err
+	}
+	if err := copyFile(keyPath, c.CAKey, 0600); err != nil {
+		return types.CertificatesConfig{}, err
+	}
+	return c, nil
+}
+
+// IsTrusted reports whether this machine trusts the certificates a CA
+// issues. A company's CA usually is, installed by its IT.
+func IsTrusted(caCertPath string) bool {
+	chain, err := readCertificates(caCertPath)
+	if err != nil {
+		return false
+	}
+	roots, err := x509.SystemCertPool()
+	if err != nil {
+		return false
+	}
+	intermediates := x509.NewCertPool()
+	for _, cert := range chain[1:] {
+		intermediates.AddCert(cert)
+	}
+	_, err = chain[0].Verify(x509.VerifyOptions{
+		Roots:         roots,
+		Intermediates: intermediates,
+		KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageAny},
+	})
+	return err == nil
+}
+
+// issueFromCA writes the certificate of the domain and its subdomains,
+// signed by the imported CA
+func (m *Manager) issueFromCA() error {
+	ca, caKey, err := LoadCA(m.caCert, m.caKey)
+	if err != nil {
+		return fmt.Errorf("failed to load the CA: %w", err)
+	}
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return fmt.Errorf("failed to generate key: %w", err)
+	}
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return fmt.Errorf("failed to generate serial number: %w", err)
+	}
+
+	now := time.Now()
+	notAfter := now.Add(leafValidity)
+	if notAfter.After(ca.NotAfter) {
+		notAfter = ca.NotAfter
+	}
+	template := &x509.Certificate{
+		SerialNumber: serial,
+		Subject: pkix.Name{
+			Organization: []string{"Doku development certificate"},
+			CommonName:   m.domain,
+		},
+		NotBefore:   now.Add(-time.Hour),
+		NotAfter:    notAfter,
+		KeyUsage:    x509.KeyUsageDigitalSignature,
+		ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+	for _, name := range m.names() {
+		if ip := net.ParseIP(name); ip != nil {
+			template.IPAddresses = append(template.IPAddresses, ip)
+		} else {
+			template.DNSNames = append(template.DNSNames, name)
+		}
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, ca, &key.PublicKey, caKey)
+	if err != nil {
+		return fmt.Errorf("failed to sign the certificate of %s: %w", m.domain, err)
+	}
+	keyDER, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		return fmt.Errorf("failed to encode key: %w", err)
+	}
+
+	// The CA follows the certificate, so clients that only trust the root
+	// above an intermediate CA get the whole chain
+	certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+	certPEM = append(certPEM, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: ca.Raw})...)
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: keyDER})
+
+	fmt.Printf("Issuing SSL certificates for %s from %s...\n", joinNames(m.names()), ca.Subject.CommonName)
+	return m.writeCertificate(certPEM, keyPEM)
+}
+
+// ImportCertificate installs a certificate issued elsewhere, and its key, as
+// the domain's. It must cover the domain's subdomains, e.g. as *.domain.
+func (m *Manager) ImportCertificate(certPath, keyPath string) error {
+	if err := readonly.Check("import the certificate " + certPath); err != nil {
+		return err
+	}
+
+	chain, err := readCertificates(certPath)
+	if err != nil {
+		return err
+	}
+	leaf := chain[0]
+	if err := leaf.VerifyHostname("doku." + m.domain); err != nil {
+		return fmt.Errorf("%s doesn't cover the subdomains of %s: it needs *.%s", certPath, m.domain, m.domain)
+	}
+	if time.Now().After(leaf.NotAfter) {
+		return fmt.Errorf("the certificate %s expired on %s", certPath, leaf.NotAfter.Format("2006-01-02"))
+	}
+	key, err := readPrivateKey(keyPath)
+	if err != nil {
+		return err
+	}
+	if !publicKeysMatch(leaf.PublicKey, key.Public()) {
+		return fmt.Errorf("the key %s doesn't belong to the certificate %s", keyPath, certPath)
+	}
+
+	certPEM, err := os.ReadFile(certPath)
+	if err != nil {
+		return fmt.Errorf("failed to read certificate: %w", err)
+	}
+	keyPEM, err := os.ReadFile(keyPath)
+	if err != nil {
+		return fmt.Errorf("failed to read key: %w", err)
+	}
+	return m.writeCertificate(certPEM, keyPEM)
+}
+
+// writeCertificate writes the domain's certificate and key to the certs
+// directory
+func (m *Manager) writeCertificate(certPEM, keyPEM []byte) error {
+	if err := os.MkdirAll(m.certsDir, 0755); err != nil {
+		return fmt.Errorf("failed to create certs directory: %w", err)
+	}
+	if err := os.WriteFile(m.GetCertificatePath(), certPEM, 0644); err != nil {
+		return fmt.Errorf("failed to write certificate: %w", err)
+	}
+	if err := os.WriteFile(m.GetKeyPath(), keyPEM, 0600); err != nil {
+		return fmt.Errorf("failed to write key: %w", err)
+	}
+	return nil
+}
+
+// readCertificates reads the certificates of a PEM file, the first one
+// being the leaf or CA and the others its chain
+func readCertificates(path string) ([]*x509.Certificate, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read certificate: %w", err)
+	}
+
+	var chain []*x509.Certificate
+	for {
+		var block *pem.Block
+		block, data = pem.Decode(data)
+		if block == nil {
+			break
+		}
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse certificate %s: %w", path, err)
+		}
+		chain = append(chain, cert)
+	}
+	if len(chain) == 0 {
+		return nil, fmt.Errorf("no PEM certificate in %s", path)
+	}
+	return chain, nil
+}
+
+// readPrivateKey reads an unencrypted PKCS#8, PKCS#1 or EC private key
+func readPrivateKey(path string) (crypto.Signer, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read key: %w", err)
+	}
+
+	for {
+		var block *pem.Block
+		block, data = pem.Decode(data)
+		if block == nil {
+			return nil, fmt.Errorf("no PEM private key in %s", path)
+		}
+
+		var key any
+		switch block.Type {
+		case "PRIVATE KEY":
+			key, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+		case "RSA PRIVATE KEY":
+			key, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+		case "EC PRIVATE KEY":
+			key, err = x509.ParseECPrivateKey(block.Bytes)
+		case "ENCRYPTED PRIVATE KEY":
+			return nil, fmt.Errorf("the key %s is encrypted: decrypt it first, e.g. with openssl pkey -in %s -out key.pem", path, path)
+		default:
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse key %s: %w", path, err)
+		}
+		signer, ok := key.(crypto.Signer)
+		if !ok {
+			return nil, fmt.Errorf("unsupported key type in %s", path)
+		}
+		return signer, nil
+	}
+}
+
+// publicKeysMatch reports whether two public keys are the same
+func publicKeysMatch(a, b crypto.PublicKey) bool {
+	key, ok := a.(interface{ Equal(crypto.PublicKey) bool })
+	return ok && key.Equal(b)
+}
+
+// joinNames joins names for a message, e.g. "a, b and c"
+func joinNames(names []string) string {
+	if len(names) < 2 {
+		return strings.Join(names, "")
+	}
+	return strings.Join(names[:len(names)-1], ", ") + " and " + names[len(names)-1]
+}
+
+// copyFile copies a file, giving the copy mode
+func copyFile(src, dest string, mode os.FileMode) error {
+	data, err := os.ReadFile(src)
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", src, err)
+	}
+	if err := os.WriteFile(dest, data, mode); err != nil {
+		return fmt.Errorf("failed to write %s: %w", dest, err)
+	}
+	return nil
+}
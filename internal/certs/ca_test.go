@@ -0,0 +1,219 @@
+package certs
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dokulabs/doku-cli/pkg/types"
+)
+
+// writeCertificate creates a certificate and its key in dir, signed by
+// parent (self-signed if nil), and returns their paths
+func writeCertificate(t *testing.T, dir, name string, template *x509.Certificate, parent *x509.Certificate, parentKey *ecdsa.PrivateKey) (string, string, *x509.Certificate, *ecdsa.PrivateKey) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if parent == nil {
+		parent, parentKey = template, key
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, parent, &key.PublicKey, parentKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	certPath := filepath.Join(dir, name+".pem")
+	keyPath := filepath.Join(dir, name+"-key.pem")
+	if err := os.WriteFile(certPath, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(keyPath, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0600); err != nil {
+		t.Fatal(err)
+	}
+	return certPath, keyPath, cert, key
+}
+
+func caTemplate() *x509.Certificate {
+	return &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "Acme Dev CA"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(365 * 24 * time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	}
+}
+
+func leafTemplate(names ...string) *x509.Certificate {
+	return &x509.Certificate{
+		SerialNumber: big.NewInt(2),
+		DNSNames:     names,
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(30 * 24 * time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+}
+
+func TestLoadCA(t *testing.T) {
+	dir := t.TempDir()
+	caCert, caKey, ca, key := writeCertificate(t, dir, "ca", caTemplate(), nil, nil)
+	_, otherKey, _, _ := writeCertificate(t, dir, "other", caTemplate(), nil, nil)
+	leafCert, leafKey, _, _ := writeCertificate(t, dir, "leaf", leafTemplate("doku.local"), ca, key)
+
+	if _, _, err := LoadCA(caCert, caKey); err != nil {
+		t.Errorf("LoadCA() error = %v", err)
+	}
+	if _, _, err := LoadCA(caCert, otherKey); err == nil {
+		t.Error("LoadCA() should fail with another CA's key")
+	}
+	if _, _, err := LoadCA(leafCert, leafKey); err == nil {
+		t.Error("LoadCA() should fail with a certificate that isn't a CA")
+	}
+}
+
+func TestIssueFromCA(t *testing.T) {
+	dir := t.TempDir()
+	caCert, caKey, ca, _ := writeCertificate(t, dir, "ca", caTemplate(), nil, nil)
+
+	config, err := ImportCA(caCert, caKey, filepath.Join(dir, "imported"))
+	if err != nil {
+		t.Fatalf("ImportCA() error = %v", err)
+	}
+	if config.Source != SourceCA {
+		t.Errorf("ImportCA() source = %q, want %q", config.Source, SourceCA)
+	}
+	if info, err := os.Stat(config.CAKey); err != nil || info.Mode().Perm() != 0600 {
+		t.Errorf("the imported key should be private, got %v, %v", info, err)
+	}
+
+	mgr := NewManager(filepath.Join(dir, "certs"), "doku.local").Configure(config)
+	if mgr.NeedsMkcert() {
+		t.Error("NeedsMkcert() should be false with an imported CA")
+	}
+	if err := mgr.GenerateCertificates(); err != nil {
+		t.Fatalf("GenerateCertificates() error = %v", err)
+	}
+
+	chain, err := readCertificates(mgr.GetCertificatePath())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(chain) != 2 {
+		t.Errorf("the certificate should be followed by the CA, got %d certificates", len(chain))
+	}
+	roots := x509.NewCertPool()
+	roots.AddCert(ca)
+	for _, host := range []string{"doku.local", "postgres.doku.local"} {
+		if _, err := chain[0].Verify(x509.VerifyOptions{DNSName: host, Roots: roots}); err != nil {
+			t.Errorf("the certificate should be valid for %s: %v", host, err)
+		}
+	}
+	if chain[0].NotAfter.After(ca.NotAfter) {
+		t.Errorf("the certificate shouldn't outlive the CA: %s > %s", chain[0].NotAfter, ca.NotAfter)
+	}
+}
+
+func TestIssueWithHosts(t *testing.T) {
+	dir := t.TempDir()
+	caCert, caKey, ca, _ := writeCertificate(t, dir, "ca", caTemplate(), nil, nil)
+	config := types.CertificatesConfig{Source: SourceCA, CACert: caCert, CAKey: caKey}
+
+	certsDir := filepath.Join(dir, "certs")
+	mgr := NewManager(certsDir, "api.doku.local").Configure(config).SetHosts([]string{"api.doku.local", "api.internal", "10.0.0.5"})
+	if err := mgr.GenerateCertificates(); err != nil {
+		t.Fatalf("GenerateCertificates() error = %v", err)
+	}
+
+	chain, err := readCertificates(mgr.GetCertificatePath())
+	if err != nil {
+		t.Fatal(err)
+	}
+	roots := x509.NewCertPool()
+	roots.AddCert(ca)
+	for _, host := range []string{"api.doku.local", "api.internal", "10.0.0.5"} {
+		if _, err := chain[0].Verify(x509.VerifyOptions{DNSName: host, Roots: roots}); err != nil {
+			t.Errorf("the certificate should be valid for %s: %v", host, err)
+		}
+	}
+	if err := chain[0].VerifyHostname("postgres.doku.local"); err == nil {
+		t.Error("the certificate shouldn't cover the domain's other subdomains")
+	}
+
+	// Renewing it from what it covers keeps the SANs
+	cert := Read(certsDir, "api.doku.local")
+	if want := []string{"api.doku.local", "api.internal", "10.0.0.5"}; strings.Join(cert.Domains, " ") != strings.Join(want, " ") {
+		t.Errorf("Read() domains = %v, want %v", cert.Domains, want)
+	}
+}
+
+func TestValidateSAN(t *testing.T) {
+	for _, name := range []string{"api.internal", "api", "10.0.0.5", "::1"} {
+		if err := ValidateSAN(name); err != nil {
+			t.Errorf("ValidateSAN(%q) error = %v", name, err)
+		}
+	}
+	for _, name := range []string{"*.doku.local", "", "api..internal", "api internal"} {
+		if err := ValidateSAN(name); err == nil {
+			t.Errorf("ValidateSAN(%q) should fail", name)
+		}
+	}
+}
+
+func TestImportCertificate(t *testing.T) {
+	dir := t.TempDir()
+	_, _, ca, caKey := writeCertificate(t, dir, "ca", caTemplate(), nil, nil)
+	wildcard, wildcardKey, _, _ := writeCertificate(t, dir, "wildcard", leafTemplate("dev.acme.com", "*.dev.acme.com"), ca, caKey)
+	single, singleKey, _, _ := writeCertificate(t, dir, "single", leafTemplate("dev.acme.com"), ca, caKey)
+
+	mgr := NewManager(filepath.Join(dir, "certs"), "dev.acme.com").Configure(types.CertificatesConfig{Source: SourceProvided})
+	if err := mgr.ImportCertificate(single, singleKey); err == nil {
+		t.Error("ImportCertificate() should fail without the wildcard")
+	}
+	if err := mgr.ImportCertificate(wildcard, singleKey); err == nil {
+		t.Error("ImportCertificate() should fail with another certificate's key")
+	}
+	if err := mgr.ImportCertificate(wildcard, wildcardKey); err != nil {
+		t.Fatalf("ImportCertificate() error = %v", err)
+	}
+	if !mgr.CertificatesExist() {
+		t.Error("the certificate should be installed as the domain's")
+	}
+
+	if err := mgr.GenerateCertificates(); err == nil {
+		t.Error("GenerateCertificates() should fail with provided certificates")
+	}
+	if err := mgr.RegenerateCertificates(); err == nil || !mgr.CertificatesExist() {
+		t.Error("RegenerateCertificates() should fail and keep the provided certificate")
+	}
+}
+
+func TestSource(t *testing.T) {
+	if got := Source(types.CertificatesConfig{}); got != SourceMkcert {
+		t.Errorf("Source() = %q, want %q", got, SourceMkcert)
+	}
+	if !NewManager("", "doku.local").NeedsMkcert() {
+		t.Error("NeedsMkcert() should default to true")
+	}
+}
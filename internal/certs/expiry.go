@@ -0,0 +1,131 @@
+package certs
+
+import (
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/dokulabs/doku-cli/pkg/types"
+)
+
+// DefaultRenewDays is how many days before they expire certificates are
+// renewed, unless configured otherwise
+const DefaultRenewDays = 30
+
+// RenewDays returns how many days before they expire the certificates of a
+// configuration are renewed automatically, or -1 if they aren't
+func RenewDays(c types.CertificatesConfig) int {
+	switch {
+	case c.RenewDays == 0:
+		return DefaultRenewDays
+	case c.RenewDays < 0:
+		return -1
+	}
+	return c.RenewDays
+}
+
+// RenewalWindow returns how many days before they expire the certificates
+// of a configuration are due for renewal, whether renewed automatically or
+// not
+func RenewalWindow(c types.CertificatesConfig) int {
+	if days := RenewDays(c); days >= 0 {
+		return days
+	}
+	return DefaultRenewDays
+}
+
+// Certificate is a certificate in the certs directory, named after the
+// domain it was issued for
+type Certificate struct {
+	Name     string
+	Path     string
+	Domains  []string // The hostnames and IP addresses it covers
+	NotAfter time.Time
+	Err      error // Why the certificate couldn't be read, if it couldn't
+}
+
+// DaysLeft returns the number of whole days until the certificate expires,
+// negative once it has
+func (c Certificate) DaysLeft(now time.Time) int {
+	return int(math.Floor(c.NotAfter.Sub(now).Hours() / 24))
+}
+
+// Expired reports whether the certificate has expired
+func (c Certificate) Expired(now time.Time) bool {
+	return !now.Before(c.NotAfter)
+}
+
+// Due reports whether the certificate expires within days, and so should be
+// renewed
+func (c Certificate) Due(now time.Time, days int) bool {
+	return c.Err == nil && days >= 0 && c.NotAfter.Before(now.Add(time.Duration(days)*24*time.Hour))
+}
+
+// List returns the certificates in certsDir that have their key next to
+// them, as Traefik serves them, sorted by name
+func List(certsDir string) ([]Certificate, error) {
+	entries, err := os.ReadDir(certsDir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", certsDir, err)
+	}
+
+	var list []Certificate
+	for _, entry := range entries {
+		name, ok := strings.CutSuffix(entry.Name(), ".pem")
+		if !ok || entry.IsDir() || strings.HasSuffix(name, "-key") {
+			continue
+		}
+		if _, err := os.Stat(filepath.Join(certsDir, name+"-key.pem")); err != nil {
+			continue
+		}
+		list = append(list, Read(certsDir, name))
+	}
+	return list, nil
+}
+
+// Read returns the certificate of certsDir named after a domain. Err tells
+// why it couldn't be read, if it couldn't.
+func Read(certsDir, name string) Certificate {
+	cert := Certificate{Name: name, Path: filepath.Join(certsDir, name+".pem")}
+	chain, err := readCertificates(cert.Path)
+	if err != nil {
+		cert.Err = err
+		return cert
+	}
+	cert.Domains = chain[0].DNSNames
+	for _, ip := range chain[0].IPAddresses {
+		cert.Domains = append(cert.Domains, ip.String())
+	}
+	cert.NotAfter = chain[0].NotAfter
+	return cert
+}
+
+// CanRenew returns why the manager can't renew its certificates days before
+// they expire, or nil if it can
+func (m *Manager) CanRenew(days int) error {
+	switch m.source {
+	case SourceProvided:
+		return fmt.Errorf("the certificates are provided, so Doku can't renew the one of %s: replace %s and %s", m.domain, m.GetCertificatePath(), m.GetKeyPath())
+	case SourceCA:
+		ca, _, err := LoadCA(m.caCert, m.caKey)
+		if err != nil {
+			return fmt.Errorf("failed to load the CA: %w", err)
+		}
+		// The certificates it issues expire with it, so renewing them wouldn't
+		// take them out of the window
+		if ca.NotAfter.Before(time.Now().Add(time.Duration(days) * 24 * time.Hour)) {
+			return fmt.Errorf("the CA expires on %s, and the certificates it issues with it: import a new one with 'doku init --ca-cert <file> --ca-key <file>'", ca.NotAfter.Format("2006-01-02"))
+		}
+		return nil
+	}
+	if !m.IsMkcertInstalled() {
+		return fmt.Errorf("mkcert is not installed; it is needed to renew the certificate of %s", m.domain)
+	}
+	return nil
+}
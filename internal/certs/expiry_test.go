@@ -0,0 +1,83 @@
+package certs
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/dokulabs/doku-cli/pkg/types"
+)
+
+func TestList(t *testing.T) {
+	dir := t.TempDir()
+	_, _, ca, caKey := writeCertificate(t, dir, "ca", caTemplate(), nil, nil)
+	writeCertificate(t, dir, "doku.local", leafTemplate("doku.local", "*.doku.local"), ca, caKey)
+	if err := os.Remove(filepath.Join(dir, "ca-key.pem")); err != nil {
+		t.Fatal(err)
+	}
+
+	list, err := List(dir)
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	if len(list) != 1 || list[0].Name != "doku.local" {
+		t.Fatalf("List() = %+v, want only the certificate with a key", list)
+	}
+	if list[0].Err != nil || len(list[0].Domains) != 2 {
+		t.Errorf("List() = %+v, want the domains of doku.local", list[0])
+	}
+
+	if list, err := List(filepath.Join(dir, "missing")); err != nil || list != nil {
+		t.Errorf("List() of a missing directory = %v, %v, want nothing", list, err)
+	}
+}
+
+func TestDue(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	cert := Certificate{Name: "doku.local", NotAfter: now.Add(10 * 24 * time.Hour)}
+
+	if got := cert.DaysLeft(now); got != 10 {
+		t.Errorf("DaysLeft() = %d, want 10", got)
+	}
+	if !cert.Due(now, 30) || cert.Due(now, 5) || cert.Due(now, -1) {
+		t.Error("Due() should only be true within the renewal window")
+	}
+	if cert.Expired(now) || !cert.Expired(cert.NotAfter) {
+		t.Error("Expired() should be true from NotAfter")
+	}
+	if got := cert.DaysLeft(now.Add(11 * 24 * time.Hour)); got != -1 {
+		t.Errorf("DaysLeft() after expiry = %d, want -1", got)
+	}
+}
+
+func TestRenewDays(t *testing.T) {
+	tests := map[int]int{0: DefaultRenewDays, 14: 14, -5: -1}
+	for configured, want := range tests {
+		if got := RenewDays(types.CertificatesConfig{RenewDays: configured}); got != want {
+			t.Errorf("RenewDays(%d) = %d, want %d", configured, got, want)
+		}
+	}
+	if got := RenewalWindow(types.CertificatesConfig{RenewDays: -1}); got != DefaultRenewDays {
+		t.Errorf("RenewalWindow() = %d, want %d without automatic renewal", got, DefaultRenewDays)
+	}
+}
+
+func TestCanRenew(t *testing.T) {
+	dir := t.TempDir()
+	caCert, caKey, _, _ := writeCertificate(t, dir, "ca", caTemplate(), nil, nil)
+	config := types.CertificatesConfig{Source: SourceCA, CACert: caCert, CAKey: caKey}
+
+	mgr := NewManager(dir, "doku.local").Configure(config)
+	if err := mgr.CanRenew(30); err != nil {
+		t.Errorf("CanRenew() error = %v", err)
+	}
+	if err := mgr.CanRenew(400); err == nil {
+		t.Error("CanRenew() should fail when the CA expires within the window")
+	}
+
+	provided := NewManager(dir, "doku.local").Configure(types.CertificatesConfig{Source: SourceProvided})
+	if err := provided.CanRenew(30); err == nil {
+		t.Error("CanRenew() should fail with provided certificates")
+	}
+}
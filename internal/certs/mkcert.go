@@ -7,12 +7,25 @@ import (
 	"path/filepath"
 	"runtime"
 	"strings"
+
+	"github.com/dokulabs/doku-cli/internal/readonly"
 )
 
-// Manager handles certificate generation with mkcert
+// Manager handles certificate generation with mkcert, or from an imported
+// CA (see Configure)
 type Manager struct {
 	certsDir string
 	domain   string
+
+	// source is where the certificates come from, and caCert and caKey
+	// the CA issuing them with SourceCA
+	source string
+	caCert string
+	caKey  string
+
+	// hosts are the hostnames and IP addresses the certificate covers,
+	// instead of the domain and its subdomains (see SetHosts)
+	hosts []string
 }
 
 // NewManager creates a new certificate manager
@@ -31,6 +44,10 @@ func (m *Manager) IsMkcertInstalled() bool {
 
 // InstallMkcert attempts to install mkcert
 func (m *Manager) InstallMkcert() error {
+	if err := readonly.Check("install mkcert"); err != nil {
+		return err
+	}
+
 	if m.IsMkcertInstalled() {
 		return nil
 	}
@@ -190,6 +207,10 @@ func (m *Manager) installMkcertWindows() error {
 
 // InstallCA installs the mkcert root CA into system trust stores
 func (m *Manager) InstallCA() error {
+	if err := readonly.Check("install the local CA"); err != nil {
+		return err
+	}
+
 	if !m.IsMkcertInstalled() {
 		return fmt.Errorf("mkcert is not installed")
 	}
@@ -208,6 +229,24 @@ func (m *Manager) InstallCA() error {
 
 // GenerateCertificates generates SSL certificates for the domain
 func (m *Manager) GenerateCertificates() error {
+	if err := readonly.Check("generate certificates"); err != nil {
+		return err
+	}
+
+	switch m.source {
+	case SourceCA:
+		if err := m.issueFromCA(); err != nil {
+			return err
+		}
+		fmt.Printf("✓ Certificates issued:\n")
+		fmt.Printf("  - Certificate: %s\n", m.GetCertificatePath())
+		fmt.Printf("  - Key: %s\n", m.GetKeyPath())
+		return nil
+	case SourceProvided:
+		return fmt.Errorf("the certificates are provided, so Doku can't issue one for %s: copy a certificate for it and *.%s to %s, and its key to %s",
+			m.domain, m.domain, m.GetCertificatePath(), m.GetKeyPath())
+	}
+
 	if !m.IsMkcertInstalled() {
 		return fmt.Errorf("mkcert is not installed")
 	}
@@ -220,15 +259,11 @@ func (m *Manager) GenerateCertificates() error {
 	certFile := filepath.Join(m.certsDir, fmt.Sprintf("%s.pem", m.domain))
 	keyFile := filepath.Join(m.certsDir, fmt.Sprintf("%s-key.pem", m.domain))
 
-	// Generate certificate for domain and wildcard
-	fmt.Printf("Generating SSL certificates for %s and *.%s...\n", m.domain, m.domain)
+	// Generate certificate for domain and wildcard, or the hosts set
+	fmt.Printf("Generating SSL certificates for %s...\n", joinNames(m.names()))
 
-	cmd := exec.Command("mkcert",
-		"-cert-file", certFile,
-		"-key-file", keyFile,
-		m.domain,
-		fmt.Sprintf("*.%s", m.domain),
-	)
+	args := append([]string{"-cert-file", certFile, "-key-file", keyFile}, m.names()...)
+	cmd := exec.Command("mkcert", args...)
 	cmd.Stdout = os.Stdout
 	cmd.Stderr = os.Stderr
 
@@ -282,6 +317,13 @@ func (m *Manager) CertificatesExist() bool {
 
 // RegenerateCertificates removes old certificates and generates new ones
 func (m *Manager) RegenerateCertificates() error {
+	if err := readonly.Check("regenerate certificates"); err != nil {
+		return err
+	}
+	if m.source == SourceProvided {
+		return fmt.Errorf("the certificates are provided, so Doku can't regenerate the one of %s: replace %s and %s", m.domain, m.GetCertificatePath(), m.GetKeyPath())
+	}
+
 	// Remove old certificates if they exist
 	if m.CertificatesExist() {
 		certPath := m.GetCertificatePath()
@@ -301,6 +343,10 @@ func (m *Manager) RegenerateCertificates() error {
 
 // UninstallCA removes the mkcert CA from system trust stores
 func (m *Manager) UninstallCA() error {
+	if err := readonly.Check("uninstall the local CA"); err != nil {
+		return err
+	}
+
 	if !m.IsMkcertInstalled() {
 		return fmt.Errorf("mkcert is not installed")
 	}
@@ -6,6 +6,8 @@ import (
 	"fmt"
 	"os"
 	"time"
+
+	"github.com/dokulabs/doku-cli/internal/readonly"
 )
 
 // ValidateCertificate validates a certificate file
@@ -105,6 +107,10 @@ func GetCertificateDomains(certPath string) ([]string, error) {
 
 // CopyCertificates copies certificate files to a destination directory
 func CopyCertificates(certPath, keyPath, destDir string) error {
+	if err := readonly.Check("copy certificates to " + destDir); err != nil {
+		return err
+	}
+
 	// Ensure destination directory exists
 	if err := os.MkdirAll(destDir, 0755); err != nil {
 		return fmt.Errorf("failed to create destination directory: %w", err)
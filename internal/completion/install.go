@@ -0,0 +1,246 @@
+// Package completion installs shell completion scripts and hooks them into
+// the user's shell profile, and takes them out again.
+package completion
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/dokulabs/doku-cli/internal/readonly"
+)
+
+// Shell is a shell completion can be installed for
+type Shell string
+
+const (
+	Bash       Shell = "bash"
+	Zsh        Shell = "zsh"
+	Fish       Shell = "fish"
+	PowerShell Shell = "powershell"
+)
+
+// Shells lists the supported shells
+var Shells = []Shell{Bash, Zsh, Fish, PowerShell}
+
+// Markers around the lines Doku adds to a shell profile
+const (
+	blockStart = "# >>> doku completion >>>"
+	blockEnd   = "# <<< doku completion <<<"
+)
+
+// ParseShell returns the shell for a name such as "zsh" or "/bin/zsh"
+func ParseShell(name string) (Shell, error) {
+	base := strings.TrimSuffix(strings.ToLower(filepath.Base(name)), ".exe")
+	switch base {
+	case "bash":
+		return Bash, nil
+	case "zsh":
+		return Zsh, nil
+	case "fish":
+		return Fish, nil
+	case "powershell", "pwsh":
+		return PowerShell, nil
+	}
+	return "", fmt.Errorf("unsupported shell %q (supported: bash, zsh, fish, powershell)", name)
+}
+
+// DetectShell returns the user's shell from $SHELL, or PowerShell on Windows
+func DetectShell() (Shell, error) {
+	if shell := os.Getenv("SHELL"); shell != "" {
+		return ParseShell(shell)
+	}
+	if runtime.GOOS == "windows" {
+		return PowerShell, nil
+	}
+	return "", fmt.Errorf("could not detect your shell; pass it with --shell")
+}
+
+// Plan describes where the completion script of a shell goes and which
+// lines load it from the shell profile
+type Plan struct {
+	Shell        Shell
+	ScriptPath   string
+	ProfilePath  string   // Empty when the shell loads the script by itself
+	ProfileLines []string // Lines added to the profile between markers
+}
+
+// NewPlan returns the installation plan for a shell. dokuDir holds scripts
+// that the shell does not pick up by itself.
+func NewPlan(shell Shell, home, dokuDir string) (*Plan, error) {
+	scriptDir := filepath.Join(dokuDir, "completion")
+
+	switch shell {
+	case Bash:
+		script := filepath.Join(scriptDir, "doku.bash")
+		return &Plan{
+			Shell:        shell,
+			ScriptPath:   script,
+			ProfilePath:  filepath.Join(home, ".bashrc"),
+			ProfileLines: []string{fmt.Sprintf("[ -f %q ] && source %q", script, script)},
+		}, nil
+
+	case Zsh:
+		// Sourcing after compinit works with frameworks like oh-my-zsh, which
+		// run compinit before the end of .zshrc
+		script := filepath.Join(scriptDir, "_doku")
+		return &Plan{
+			Shell:       shell,
+			ScriptPath:  script,
+			ProfilePath: filepath.Join(home, ".zshrc"),
+			ProfileLines: []string{
+				"(( $+functions[compdef] )) || { autoload -U compinit && compinit }",
+				fmt.Sprintf("[ -f %q ] && source %q", script, script),
+			},
+		}, nil
+
+	case Fish:
+		// Fish loads completions from this directory on demand
+		configDir := os.Getenv("XDG_CONFIG_HOME")
+		if configDir == "" {
+			configDir = filepath.Join(home, ".config")
+		}
+		return &Plan{
+			Shell:      shell,
+			ScriptPath: filepath.Join(configDir, "fish", "completions", "doku.fish"),
+		}, nil
+
+	case PowerShell:
+		script := filepath.Join(scriptDir, "doku.ps1")
+		return &Plan{
+			Shell:        shell,
+			ScriptPath:   script,
+			ProfilePath:  powerShellProfile(home),
+			ProfileLines: []string{fmt.Sprintf("if (Test-Path '%s') { . '%s' }", script, script)},
+		}, nil
+	}
+
+	return nil, fmt.Errorf("unsupported shell %q", shell)
+}
+
+// powerShellProfile returns the current user's PowerShell profile
+func powerShellProfile(home string) string {
+	if runtime.GOOS == "windows" {
+		return filepath.Join(home, "Documents", "PowerShell", "Microsoft.PowerShell_profile.ps1")
+	}
+	return filepath.Join(home, ".config", "powershell", "Microsoft.PowerShell_profile.ps1")
+}
+
+// Install writes the completion script and adds the loading lines to the
+// profile, replacing those of an earlier installation
+func (p *Plan) Install(script []byte) error {
+	if err := readonly.Check("install shell completion"); err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(p.ScriptPath), 0755); err != nil {
+		return fmt.Errorf("failed to create completion directory: %w", err)
+	}
+	if err := os.WriteFile(p.ScriptPath, script, 0644); err != nil {
+		return fmt.Errorf("failed to write completion script: %w", err)
+	}
+
+	if p.ProfilePath == "" {
+		return nil
+	}
+
+	content, err := readProfile(p.ProfilePath)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(p.ProfilePath), 0755); err != nil {
+		return fmt.Errorf("failed to create profile directory: %w", err)
+	}
+	if err := os.WriteFile(p.ProfilePath, []byte(AddBlock(content, p.ProfileLines)), 0644); err != nil {
+		return fmt.Errorf("failed to update %s: %w", p.ProfilePath, err)
+	}
+
+	return nil
+}
+
+// Uninstall removes the completion script and the profile lines. It reports
+// whether anything was installed.
+func (p *Plan) Uninstall() (bool, error) {
+	if err := readonly.Check("uninstall shell completion"); err != nil {
+		return false, err
+	}
+
+	removed := false
+	if err := os.Remove(p.ScriptPath); err == nil {
+		removed = true
+	} else if !os.IsNotExist(err) {
+		return false, fmt.Errorf("failed to remove completion script: %w", err)
+	}
+
+	if p.ProfilePath == "" {
+		return removed, nil
+	}
+
+	content, err := readProfile(p.ProfilePath)
+	if err != nil {
+		return removed, err
+	}
+	updated := RemoveBlock(content)
+	if updated == content {
+		return removed, nil
+	}
+	if err := os.WriteFile(p.ProfilePath, []byte(updated), 0644); err != nil {
+		return removed, fmt.Errorf("failed to update %s: %w", p.ProfilePath, err)
+	}
+
+	return true, nil
+}
+
+// Installed reports whether the completion script is in place
+func (p *Plan) Installed() bool {
+	_, err := os.Stat(p.ScriptPath)
+	return err == nil
+}
+
+func readProfile(path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return "", fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	return string(data), nil
+}
+
+// AddBlock returns profile content with lines between Doku's markers at the
+// end, replacing an existing block
+func AddBlock(content string, lines []string) string {
+	content = RemoveBlock(content)
+	if content != "" && !strings.HasSuffix(content, "\n") {
+		content += "\n"
+	}
+
+	var b strings.Builder
+	b.WriteString(content)
+	b.WriteString(blockStart + "\n")
+	for _, line := range lines {
+		b.WriteString(line + "\n")
+	}
+	b.WriteString(blockEnd + "\n")
+	return b.String()
+}
+
+// RemoveBlock returns profile content without Doku's block
+func RemoveBlock(content string) string {
+	lines := strings.SplitAfter(content, "\n")
+
+	var b strings.Builder
+	inBlock := false
+	for _, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case trimmed == blockStart:
+			inBlock = true
+		case trimmed == blockEnd && inBlock:
+			inBlock = false
+		case !inBlock:
+			b.WriteString(line)
+		}
+	}
+	return b.String()
+}
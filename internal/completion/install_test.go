@@ -0,0 +1,112 @@
+package completion
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestParseShell(t *testing.T) {
+	tests := map[string]Shell{
+		"/bin/bash":     Bash,
+		"/usr/bin/zsh":  Zsh,
+		"fish":          Fish,
+		"pwsh.exe":      PowerShell,
+		"PowerShell":    PowerShell,
+		"/opt/bin/bash": Bash,
+	}
+	for name, want := range tests {
+		got, err := ParseShell(name)
+		if err != nil || got != want {
+			t.Errorf("ParseShell(%q) = %q, %v; want %q", name, got, err, want)
+		}
+	}
+
+	if _, err := ParseShell("tcsh"); err == nil {
+		t.Error("expected an error for an unsupported shell")
+	}
+}
+
+func TestAddBlock(t *testing.T) {
+	content := "export PATH=$HOME/bin:$PATH"
+
+	once := AddBlock(content, []string{"source a"})
+	if !strings.HasPrefix(once, content+"\n") {
+		t.Errorf("existing content should be kept, got:\n%s", once)
+	}
+	if strings.Count(once, blockStart) != 1 || !strings.Contains(once, "source a\n") {
+		t.Errorf("expected one block, got:\n%s", once)
+	}
+
+	twice := AddBlock(once, []string{"source b"})
+	if strings.Count(twice, blockStart) != 1 || strings.Contains(twice, "source a") {
+		t.Errorf("expected the block to be replaced, got:\n%s", twice)
+	}
+
+	if removed := RemoveBlock(twice); removed != content+"\n" {
+		t.Errorf("RemoveBlock() = %q, want %q", removed, content+"\n")
+	}
+}
+
+func TestRemoveBlockKeepsSurroundingLines(t *testing.T) {
+	content := "a\n" + blockStart + "\nsource x\n" + blockEnd + "\nb\n"
+	if got := RemoveBlock(content); got != "a\nb\n" {
+		t.Errorf("RemoveBlock() = %q", got)
+	}
+}
+
+func TestPlanInstallUninstall(t *testing.T) {
+	home := t.TempDir()
+	profile := filepath.Join(home, ".zshrc")
+	if err := os.WriteFile(profile, []byte("alias ll='ls -l'\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	plan, err := NewPlan(Zsh, home, filepath.Join(home, ".doku"))
+	if err != nil {
+		t.Fatalf("NewPlan() error = %v", err)
+	}
+
+	if err := plan.Install([]byte("#compdef doku\n")); err != nil {
+		t.Fatalf("Install() error = %v", err)
+	}
+	if !plan.Installed() {
+		t.Error("expected the script to be installed")
+	}
+	data, _ := os.ReadFile(profile)
+	if !strings.Contains(string(data), plan.ScriptPath) {
+		t.Errorf("expected the profile to load the script, got:\n%s", data)
+	}
+
+	removed, err := plan.Uninstall()
+	if err != nil || !removed {
+		t.Fatalf("Uninstall() = %v, %v", removed, err)
+	}
+	data, _ = os.ReadFile(profile)
+	if string(data) != "alias ll='ls -l'\n" {
+		t.Errorf("expected the profile to be restored, got:\n%s", data)
+	}
+
+	removed, err = plan.Uninstall()
+	if err != nil || removed {
+		t.Errorf("second Uninstall() = %v, %v; want false, nil", removed, err)
+	}
+}
+
+func TestFishPlanHasNoProfile(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", "")
+	home := t.TempDir()
+
+	plan, err := NewPlan(Fish, home, filepath.Join(home, ".doku"))
+	if err != nil {
+		t.Fatalf("NewPlan() error = %v", err)
+	}
+	if plan.ProfilePath != "" {
+		t.Errorf("fish loads completions by itself, got profile %s", plan.ProfilePath)
+	}
+	want := filepath.Join(home, ".config", "fish", "completions", "doku.fish")
+	if plan.ScriptPath != want {
+		t.Errorf("ScriptPath = %s, want %s", plan.ScriptPath, want)
+	}
+}
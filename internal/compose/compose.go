@@ -0,0 +1,113 @@
+package compose
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"sort"
+
+	"github.com/dokulabs/doku-cli/internal/readonly"
+	"gopkg.in/yaml.v3"
+)
+
+// File represents a docker-compose.yml document
+type File struct {
+	Name     string              `yaml:"name,omitempty"`
+	Services map[string]*Service `yaml:"services"`
+	Networks map[string]*Network `yaml:"networks,omitempty"`
+	Volumes  map[string]*Volume  `yaml:"volumes,omitempty"`
+}
+
+// Service represents a single compose service
+type Service struct {
+	Image         string            `yaml:"image,omitempty"`
+	Build         *Build            `yaml:"build,omitempty"`
+	ContainerName string            `yaml:"container_name,omitempty"`
+	Command       ShellCommand      `yaml:"command,omitempty"`
+	Entrypoint    ShellCommand      `yaml:"entrypoint,omitempty"`
+	WorkingDir    string            `yaml:"working_dir,omitempty"`
+	User          string            `yaml:"user,omitempty"`
+	Environment   MappingWithEquals `yaml:"environment,omitempty"`
+	EnvFile       StringOrList      `yaml:"env_file,omitempty"`
+	Ports         PortList          `yaml:"ports,omitempty"`
+	Volumes       VolumeList        `yaml:"volumes,omitempty"`
+	Labels        MappingWithEquals `yaml:"labels,omitempty"`
+	Networks      ServiceNetworks   `yaml:"networks,omitempty"`
+	DependsOn     DependsOn         `yaml:"depends_on,omitempty"`
+	Restart       string            `yaml:"restart,omitempty"`
+	MemLimit      string            `yaml:"mem_limit,omitempty"`
+	CPUs          string            `yaml:"cpus,omitempty"`
+}
+
+// Build holds the build settings of a service
+type Build struct {
+	Context    string `yaml:"context"`
+	Dockerfile string `yaml:"dockerfile,omitempty"`
+}
+
+// ServiceNetwork holds per-network settings of a service
+type ServiceNetwork struct {
+	Aliases []string `yaml:"aliases,omitempty"`
+}
+
+// Network represents a top-level compose network
+type Network struct {
+	Name     string `yaml:"name,omitempty"`
+	External bool   `yaml:"external,omitempty"`
+}
+
+// Volume represents a top-level compose volume
+type Volume struct {
+	Name     string `yaml:"name,omitempty"`
+	External bool   `yaml:"external,omitempty"`
+}
+
+// NewFile creates an empty compose file
+func NewFile(name string) *File {
+	return &File{
+		Name:     name,
+		Services: make(map[string]*Service),
+		Networks: make(map[string]*Network),
+		Volumes:  make(map[string]*Volume),
+	}
+}
+
+// ServiceNames returns the service names in sorted order
+func (f *File) ServiceNames() []string {
+	names := make([]string, 0, len(f.Services))
+	for name := range f.Services {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Marshal encodes the compose file as YAML
+func (f *File) Marshal() ([]byte, error) {
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(f); err != nil {
+		return nil, fmt.Errorf("failed to encode compose file: %w", err)
+	}
+	if err := encoder.Close(); err != nil {
+		return nil, fmt.Errorf("failed to encode compose file: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// WriteFile writes the compose file to disk
+func (f *File) WriteFile(path string) error {
+	if err := readonly.Check("write " + path); err != nil {
+		return err
+	}
+
+	data, err := f.Marshal()
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write compose file: %w", err)
+	}
+	return nil
+}
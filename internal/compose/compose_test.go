@@ -0,0 +1,327 @@
+package compose
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/mount"
+	"github.com/docker/docker/api/types/network"
+	"github.com/docker/go-connections/nat"
+	"gopkg.in/yaml.v3"
+)
+
+func TestFormatPortBindings(t *testing.T) {
+	bindings := nat.PortMap{
+		"80/tcp":   {{HostIP: "0.0.0.0", HostPort: "8080"}},
+		"5432/tcp": {{HostIP: "127.0.0.1", HostPort: "5432"}},
+		"53/udp":   {{HostPort: "53"}},
+	}
+
+	got := FormatPortBindings(bindings)
+	expected := []string{"127.0.0.1:5432:5432", "53:53/udp", "8080:80"}
+
+	if strings.Join(got, ",") != strings.Join(expected, ",") {
+		t.Errorf("Expected %v, got %v", expected, got)
+	}
+}
+
+func TestServiceFromContainer(t *testing.T) {
+	info := container.InspectResponse{
+		ContainerJSONBase: &container.ContainerJSONBase{
+			ID: "abcdef1234567890",
+			HostConfig: &container.HostConfig{
+				PortBindings:  nat.PortMap{"5432/tcp": {{HostPort: "5432"}}},
+				RestartPolicy: container.RestartPolicy{Name: container.RestartPolicyUnlessStopped},
+				Resources:     container.Resources{Memory: 512 * 1024 * 1024, NanoCPUs: 1500000000},
+			},
+		},
+		Config: &container.Config{
+			Image:  "postgres:16",
+			Env:    []string{"PATH=/usr/bin", "POSTGRES_PASSWORD=secret"},
+			Cmd:    []string{"postgres"},
+			Labels: map[string]string{"maintainer": "postgres", "doku.managed": "true"},
+		},
+		Mounts: []container.MountPoint{
+			{Type: mount.TypeVolume, Name: "doku-postgres-data", Destination: "/var/lib/postgresql/data", RW: true},
+			{Type: mount.TypeBind, Source: "/home/me/init.sql", Destination: "/docker-entrypoint-initdb.d/init.sql"},
+		},
+		NetworkSettings: &container.NetworkSettings{
+			Networks: map[string]*network.EndpointSettings{
+				"doku-network": {Aliases: []string{"postgres", "abcdef123456"}},
+			},
+		},
+	}
+	imageConfig := &container.Config{
+		Env:    []string{"PATH=/usr/bin"},
+		Cmd:    []string{"postgres"},
+		Labels: map[string]string{"maintainer": "postgres"},
+	}
+
+	f := NewFile("doku")
+	svc := f.ServiceFromContainer(info, imageConfig)
+
+	if svc.Image != "postgres:16" {
+		t.Errorf("Expected image postgres:16, got %s", svc.Image)
+	}
+
+	if len(svc.Environment) != 1 || svc.Environment["POSTGRES_PASSWORD"] != "secret" {
+		t.Errorf("Expected only POSTGRES_PASSWORD in environment, got %v", svc.Environment)
+	}
+
+	if len(svc.Labels) != 1 || svc.Labels["doku.managed"] != "true" {
+		t.Errorf("Expected image labels to be dropped, got %v", svc.Labels)
+	}
+
+	if svc.Command != nil {
+		t.Errorf("Expected command inherited from image to be omitted, got %v", svc.Command)
+	}
+
+	if svc.Restart != "unless-stopped" || svc.MemLimit != "536870912" || svc.CPUs != "1.5" {
+		t.Errorf("Unexpected restart/limits: %s %s %s", svc.Restart, svc.MemLimit, svc.CPUs)
+	}
+
+	expectedVolumes := []string{
+		"doku-postgres-data:/var/lib/postgresql/data",
+		"/home/me/init.sql:/docker-entrypoint-initdb.d/init.sql:ro",
+	}
+	if strings.Join(svc.Volumes, ",") != strings.Join(expectedVolumes, ",") {
+		t.Errorf("Expected volumes %v, got %v", expectedVolumes, svc.Volumes)
+	}
+
+	if f.Volumes["doku-postgres-data"] == nil {
+		t.Error("Expected named volume to be declared at top level")
+	}
+
+	aliases := svc.Networks["doku-network"].Aliases
+	if len(aliases) != 1 || aliases[0] != "postgres" {
+		t.Errorf("Expected short ID alias to be dropped, got %v", aliases)
+	}
+
+	if f.Networks["doku-network"] == nil {
+		t.Error("Expected network to be declared at top level")
+	}
+}
+
+func TestExportRoundTrip(t *testing.T) {
+	auth := "traefik.http.middlewares.doku-web-auth.basicauth.users"
+	info := container.InspectResponse{
+		ContainerJSONBase: &container.ContainerJSONBase{ID: "abcdef1234567890"},
+		Config: &container.Config{
+			Image:  "nginx:1.27",
+			Env:    []string{"PASSWORD=pa$$word$1", "PLAIN=${HOME}"},
+			Cmd:    []string{"sh", "-c", "echo $PASSWORD"},
+			Labels: map[string]string{auth: "admin:$apr1$xyz$hash"},
+		},
+	}
+
+	f := NewFile("doku")
+	f.Services["web"] = f.ServiceFromContainer(info, nil)
+	data, err := f.Marshal()
+	if err != nil {
+		t.Fatalf("Failed to marshal: %v", err)
+	}
+
+	parsed, err := Parse(data, map[string]string{"HOME": "/root", "PASSWORD": "leaked"})
+	if err != nil {
+		t.Fatalf("Failed to parse exported file: %v", err)
+	}
+	web := parsed.Services["web"]
+	if web.Labels[auth] != "admin:$apr1$xyz$hash" {
+		t.Errorf("Expected the basicauth label to be kept, got %q", web.Labels[auth])
+	}
+	if web.Environment["PASSWORD"] != "pa$$word$1" || web.Environment["PLAIN"] != "${HOME}" {
+		t.Errorf("Expected the environment to be kept, got %v", web.Environment)
+	}
+	if strings.Join(web.Command, "|") != "sh|-c|echo $PASSWORD" {
+		t.Errorf("Expected the command to be kept, got %q", web.Command)
+	}
+}
+
+func TestMarshal(t *testing.T) {
+	f := NewFile("doku")
+	f.Services["redis"] = &Service{Image: "redis:7", Ports: []string{"6379:6379"}}
+
+	data, err := f.Marshal()
+	if err != nil {
+		t.Fatalf("Failed to marshal: %v", err)
+	}
+
+	var decoded map[string]interface{}
+	if err := yaml.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("Generated YAML is invalid: %v", err)
+	}
+
+	if _, ok := decoded["volumes"]; ok {
+		t.Error("Expected empty volumes to be omitted")
+	}
+
+	if !strings.Contains(string(data), "image: redis:7") {
+		t.Errorf("Expected image in output, got:\n%s", data)
+	}
+}
+
+const testComposeFile = `
+name: shop
+services:
+  web:
+    build: ./web
+    command: npm run "start server"
+    environment:
+      - NODE_ENV=production
+      - DATABASE_URL=postgres://db:5432/${DB_NAME:-shop}
+    ports:
+      - 3000
+      - target: 9229
+        published: 9229
+    depends_on:
+      db:
+        condition: service_healthy
+  db:
+    image: postgres:${PG_VERSION}
+    environment:
+      POSTGRES_PASSWORD: secret
+      EMPTY:
+    volumes:
+      - data:/var/lib/postgresql/data
+      - ./init.sql:/docker-entrypoint-initdb.d/init.sql:ro
+    networks: [backend]
+volumes:
+  data:
+`
+
+func TestParse(t *testing.T) {
+	f, err := Parse([]byte(testComposeFile), map[string]string{"PG_VERSION": "16"})
+	if err != nil {
+		t.Fatalf("Failed to parse compose file: %v", err)
+	}
+
+	if f.Name != "shop" || len(f.Services) != 2 {
+		t.Fatalf("Unexpected file: name=%s services=%d", f.Name, len(f.Services))
+	}
+
+	web := f.Services["web"]
+	if web.Build == nil || web.Build.Context != "./web" {
+		t.Errorf("Expected build context ./web, got %+v", web.Build)
+	}
+
+	if strings.Join(web.Command, "|") != "npm|run|start server" {
+		t.Errorf("Unexpected command: %q", web.Command)
+	}
+
+	if web.Environment["DATABASE_URL"] != "postgres://db:5432/shop" {
+		t.Errorf("Expected default to be interpolated, got %s", web.Environment["DATABASE_URL"])
+	}
+
+	if strings.Join(web.Ports, ",") != "3000,9229:9229" {
+		t.Errorf("Unexpected ports: %v", web.Ports)
+	}
+
+	if len(web.DependsOn) != 1 || web.DependsOn[0] != "db" {
+		t.Errorf("Expected depends_on [db], got %v", web.DependsOn)
+	}
+
+	db := f.Services["db"]
+	if db.Image != "postgres:16" {
+		t.Errorf("Expected image postgres:16, got %s", db.Image)
+	}
+
+	if value, ok := db.Environment["EMPTY"]; !ok || value != "" {
+		t.Errorf("Expected empty variable to be kept, got %q (%v)", value, ok)
+	}
+
+	if _, ok := db.Networks["backend"]; !ok {
+		t.Errorf("Expected network list to be parsed, got %v", db.Networks)
+	}
+
+	order, err := f.StartOrder()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if strings.Join(order, ",") != "db,web" {
+		t.Errorf("Expected db to start before web, got %v", order)
+	}
+}
+
+func TestParseInvalid(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+	}{
+		{"no services", "services: {}\n"},
+		{"no image", "services:\n  web:\n    ports: [\"80\"]\n"},
+		{"unknown dependency", "services:\n  web:\n    image: nginx\n    depends_on: [db]\n"},
+		{"circular dependency", "services:\n  a:\n    image: x\n    depends_on: [b]\n  b:\n    image: x\n    depends_on: [a]\n"},
+		{"required variable", "services:\n  web:\n    image: ${IMAGE:?set IMAGE}\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Parse([]byte(tt.content), nil); err == nil {
+				t.Error("Expected error, got nil")
+			}
+		})
+	}
+}
+
+func TestParseInterpolatesValues(t *testing.T) {
+	content := `# Set ${UNSET:?x} to change nothing
+services:
+  web:
+    image: ${IMAGE}
+    environment:
+      GREETING: ${GREETING}
+      LITERAL: '$$HOME'
+    volumes:
+      - type: bind
+        source: ./data
+        target: /data
+        read_only: ${READ_ONLY}
+`
+	vars := map[string]string{"IMAGE": "nginx", "GREETING": "a #b: c\nd", "READ_ONLY": "true"}
+	f, err := Parse([]byte(content), vars)
+	if err != nil {
+		t.Fatalf("Failed to parse compose file: %v", err)
+	}
+
+	web := f.Services["web"]
+	if web.Image != "nginx" {
+		t.Errorf("Expected image nginx, got %q", web.Image)
+	}
+	if web.Environment["GREETING"] != "a #b: c\nd" {
+		t.Errorf("Expected the value to be kept whole, got %q", web.Environment["GREETING"])
+	}
+	if web.Environment["LITERAL"] != "$HOME" {
+		t.Errorf("Expected $$ to be unescaped, got %q", web.Environment["LITERAL"])
+	}
+	if len(web.Volumes) != 1 || !strings.HasSuffix(web.Volumes[0], ":ro") {
+		t.Errorf("Expected an interpolated read_only to be a boolean, got %v", web.Volumes)
+	}
+}
+
+func TestInterpolate(t *testing.T) {
+	vars := map[string]string{"A": "1", "EMPTY": ""}
+
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"$A-${A}", "1-1"},
+		{"${EMPTY:-x}", "x"},
+		{"${EMPTY-x}", ""},
+		{"${MISSING-x}", "x"},
+		{"$$A", "$A"},
+		{"cost: 5$", "cost: 5$"},
+	}
+
+	for _, tt := range tests {
+		got, err := Interpolate(tt.input, vars)
+		if err != nil {
+			t.Errorf("Interpolate(%q) returned error: %v", tt.input, err)
+			continue
+		}
+		if got != tt.expected {
+			t.Errorf("Interpolate(%q) = %q, expected %q", tt.input, got, tt.expected)
+		}
+	}
+}
@@ -0,0 +1,217 @@
+package compose
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	dockerTypes "github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/mount"
+	"github.com/docker/go-connections/nat"
+)
+
+// ServiceFromContainer converts an inspected container into a compose service.
+// imageConfig is the config of the container's image; environment variables,
+// labels, command and entrypoint inherited unchanged from the image are omitted.
+// Named volumes used by the service are added to the file's top-level volumes.
+// Every "$" is written as "$$", so that values aren't interpolated on import.
+func (f *File) ServiceFromContainer(info dockerTypes.ContainerJSON, imageConfig *container.Config) *Service {
+	if info.Config == nil {
+		info.Config = &container.Config{}
+	}
+	if imageConfig == nil {
+		imageConfig = &container.Config{}
+	}
+	if info.ContainerJSONBase == nil {
+		info.ContainerJSONBase = &container.ContainerJSONBase{}
+	}
+
+	svc := &Service{
+		Image:       escapeDollars(info.Config.Image),
+		WorkingDir:  escapeDollars(differentString(info.Config.WorkingDir, imageConfig.WorkingDir)),
+		User:        escapeDollars(differentString(info.Config.User, imageConfig.User)),
+		Environment: escapeValues(envWithoutDefaults(info.Config.Env, imageConfig.Env)),
+		Labels:      escapeValues(labelsWithoutDefaults(info.Config.Labels, imageConfig.Labels)),
+	}
+
+	if !equalStrings(info.Config.Cmd, imageConfig.Cmd) {
+		svc.Command = ShellCommand(escapeAll(info.Config.Cmd))
+	}
+	if !equalStrings(info.Config.Entrypoint, imageConfig.Entrypoint) {
+		svc.Entrypoint = ShellCommand(escapeAll(info.Config.Entrypoint))
+	}
+
+	if info.HostConfig != nil {
+		svc.Ports = FormatPortBindings(info.HostConfig.PortBindings)
+		if info.HostConfig.RestartPolicy.Name != "" && info.HostConfig.RestartPolicy.Name != "no" {
+			svc.Restart = string(info.HostConfig.RestartPolicy.Name)
+		}
+		if info.HostConfig.Memory > 0 {
+			svc.MemLimit = strconv.FormatInt(info.HostConfig.Memory, 10)
+		}
+		if info.HostConfig.NanoCPUs > 0 {
+			svc.CPUs = strconv.FormatFloat(float64(info.HostConfig.NanoCPUs)/1e9, 'f', -1, 64)
+		}
+	}
+
+	for _, mp := range info.Mounts {
+		suffix := ""
+		if !mp.RW {
+			suffix = ":ro"
+		}
+
+		switch mp.Type {
+		case mount.TypeVolume:
+			if mp.Name == "" {
+				continue
+			}
+			svc.Volumes = append(svc.Volumes, fmt.Sprintf("%s:%s%s", mp.Name, mp.Destination, suffix))
+			// Keep the Docker volume name so existing data is reused on this host
+			f.Volumes[mp.Name] = &Volume{Name: mp.Name}
+		case mount.TypeBind:
+			svc.Volumes = append(svc.Volumes, escapeDollars(fmt.Sprintf("%s:%s%s", mp.Source, mp.Destination, suffix)))
+		}
+	}
+
+	if info.NetworkSettings != nil {
+		for name, endpoint := range info.NetworkSettings.Networks {
+			if name == "bridge" || name == "host" || name == "none" {
+				continue
+			}
+
+			var aliases []string
+			if endpoint != nil {
+				for _, alias := range endpoint.Aliases {
+					// Skip the short container ID Docker adds on its own
+					if !strings.HasPrefix(info.ID, alias) {
+						aliases = append(aliases, escapeDollars(alias))
+					}
+				}
+			}
+
+			if svc.Networks == nil {
+				svc.Networks = make(ServiceNetworks)
+			}
+			svc.Networks[name] = &ServiceNetwork{Aliases: aliases}
+			f.Networks[name] = &Network{Name: name}
+		}
+	}
+
+	return svc
+}
+
+// FormatPortBindings renders Docker port bindings in compose short syntax
+// ("8080:80", "127.0.0.1:5432:5432", "53:53/udp")
+func FormatPortBindings(bindings nat.PortMap) []string {
+	var ports []string
+	for port, hostBindings := range bindings {
+		containerPort := port.Port()
+		if port.Proto() != "tcp" {
+			containerPort += "/" + port.Proto()
+		}
+
+		for _, binding := range hostBindings {
+			if binding.HostPort == "" {
+				ports = append(ports, containerPort)
+				continue
+			}
+
+			hostPart := binding.HostPort
+			if binding.HostIP != "" && binding.HostIP != "0.0.0.0" && binding.HostIP != "::" {
+				hostPart = binding.HostIP + ":" + hostPart
+			}
+			ports = append(ports, hostPart+":"+containerPort)
+		}
+	}
+
+	sort.Strings(ports)
+	return ports
+}
+
+// envWithoutDefaults converts an env slice to a map, dropping entries that
+// are identical to the image defaults
+func envWithoutDefaults(env, imageEnv []string) map[string]string {
+	defaults := make(map[string]bool, len(imageEnv))
+	for _, e := range imageEnv {
+		defaults[e] = true
+	}
+
+	result := make(map[string]string)
+	for _, e := range env {
+		if defaults[e] {
+			continue
+		}
+		parts := strings.SplitN(e, "=", 2)
+		if len(parts) == 2 {
+			result[parts[0]] = parts[1]
+		}
+	}
+
+	if len(result) == 0 {
+		return nil
+	}
+	return result
+}
+
+// labelsWithoutDefaults drops labels inherited unchanged from the image
+func labelsWithoutDefaults(labels, imageLabels map[string]string) map[string]string {
+	result := make(map[string]string)
+	for key, value := range labels {
+		if imageValue, ok := imageLabels[key]; ok && imageValue == value {
+			continue
+		}
+		result[key] = value
+	}
+
+	if len(result) == 0 {
+		return nil
+	}
+	return result
+}
+
+// escapeDollars escapes "$" as "$$", which Interpolate turns back into "$"
+func escapeDollars(s string) string {
+	return strings.ReplaceAll(s, "$", "$$")
+}
+
+// escapeAll escapes the "$" of every string of list
+func escapeAll(list []string) []string {
+	if list == nil {
+		return nil
+	}
+	escaped := make([]string, len(list))
+	for i, s := range list {
+		escaped[i] = escapeDollars(s)
+	}
+	return escaped
+}
+
+// escapeValues escapes the "$" of the values of m, whose keys aren't
+// interpolated
+func escapeValues(m map[string]string) map[string]string {
+	for key, value := range m {
+		m[key] = escapeDollars(value)
+	}
+	return m
+}
+
+func differentString(value, imageValue string) string {
+	if value == imageValue {
+		return ""
+	}
+	return value
+}
+
+func equalStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
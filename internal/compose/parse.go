@@ -0,0 +1,521 @@
+package compose
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/dokulabs/doku-cli/internal/envfile"
+	"gopkg.in/yaml.v3"
+)
+
+// ShellCommand is a command given either as a string or as a list
+type ShellCommand []string
+
+// UnmarshalYAML accepts "npm start" as well as ["npm", "start"]
+func (c *ShellCommand) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.ScalarNode {
+		*c = splitCommand(node.Value)
+		return nil
+	}
+
+	var list []string
+	if err := node.Decode(&list); err != nil {
+		return err
+	}
+	*c = list
+	return nil
+}
+
+// StringOrList is a value given either as a single string or as a list
+type StringOrList []string
+
+// UnmarshalYAML accepts a single string or a list of strings
+func (l *StringOrList) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.ScalarNode {
+		*l = []string{node.Value}
+		return nil
+	}
+
+	var list []string
+	if err := node.Decode(&list); err != nil {
+		return err
+	}
+	*l = list
+	return nil
+}
+
+// MappingWithEquals is a mapping given either as a map or as a list of KEY=VALUE
+type MappingWithEquals map[string]string
+
+// UnmarshalYAML accepts {KEY: value} as well as ["KEY=value"]
+func (m *MappingWithEquals) UnmarshalYAML(node *yaml.Node) error {
+	result := make(map[string]string)
+
+	switch node.Kind {
+	case yaml.SequenceNode:
+		for _, item := range node.Content {
+			parts := strings.SplitN(item.Value, "=", 2)
+			if len(parts) == 2 {
+				result[parts[0]] = parts[1]
+			} else {
+				result[parts[0]] = ""
+			}
+		}
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			value := node.Content[i+1]
+			if value.Tag == "!!null" {
+				result[node.Content[i].Value] = ""
+			} else {
+				result[node.Content[i].Value] = value.Value
+			}
+		}
+	default:
+		return fmt.Errorf("line %d: expected a map or a list", node.Line)
+	}
+
+	*m = result
+	return nil
+}
+
+// PortList holds port mappings in compose short syntax
+type PortList []string
+
+// UnmarshalYAML accepts short syntax ("8080:80", 5432) and long syntax mappings
+func (p *PortList) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind != yaml.SequenceNode {
+		return fmt.Errorf("line %d: ports must be a list", node.Line)
+	}
+
+	var ports []string
+	for _, item := range node.Content {
+		if item.Kind == yaml.ScalarNode {
+			ports = append(ports, item.Value)
+			continue
+		}
+
+		var long struct {
+			Target    string `yaml:"target"`
+			Published string `yaml:"published"`
+			HostIP    string `yaml:"host_ip"`
+			Protocol  string `yaml:"protocol"`
+		}
+		if err := item.Decode(&long); err != nil {
+			return err
+		}
+		if long.Target == "" {
+			return fmt.Errorf("line %d: port is missing a target", item.Line)
+		}
+
+		port := long.Target
+		if long.Published != "" {
+			port = long.Published + ":" + port
+			if long.HostIP != "" {
+				port = long.HostIP + ":" + port
+			}
+		}
+		if long.Protocol != "" && long.Protocol != "tcp" {
+			port += "/" + long.Protocol
+		}
+		ports = append(ports, port)
+	}
+
+	*p = ports
+	return nil
+}
+
+// VolumeList holds service volumes in compose short syntax
+type VolumeList []string
+
+// UnmarshalYAML accepts short syntax ("data:/var/lib/data:ro") and long syntax mappings
+func (v *VolumeList) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind != yaml.SequenceNode {
+		return fmt.Errorf("line %d: volumes must be a list", node.Line)
+	}
+
+	var volumes []string
+	for _, item := range node.Content {
+		if item.Kind == yaml.ScalarNode {
+			volumes = append(volumes, item.Value)
+			continue
+		}
+
+		var long struct {
+			Type     string `yaml:"type"`
+			Source   string `yaml:"source"`
+			Target   string `yaml:"target"`
+			ReadOnly bool   `yaml:"read_only"`
+		}
+		if err := item.Decode(&long); err != nil {
+			return err
+		}
+		if long.Target == "" {
+			return fmt.Errorf("line %d: volume is missing a target", item.Line)
+		}
+		if long.Type != "" && long.Type != "volume" && long.Type != "bind" {
+			return fmt.Errorf("line %d: volume type '%s' is not supported", item.Line, long.Type)
+		}
+
+		volume := long.Target
+		if long.Source != "" {
+			volume = long.Source + ":" + volume
+		}
+		if long.ReadOnly {
+			volume += ":ro"
+		}
+		volumes = append(volumes, volume)
+	}
+
+	*v = volumes
+	return nil
+}
+
+// ServiceNetworks holds the networks a service joins
+type ServiceNetworks map[string]*ServiceNetwork
+
+// UnmarshalYAML accepts a list of network names as well as a map
+func (n *ServiceNetworks) UnmarshalYAML(node *yaml.Node) error {
+	result := make(map[string]*ServiceNetwork)
+
+	if node.Kind == yaml.SequenceNode {
+		for _, item := range node.Content {
+			result[item.Value] = nil
+		}
+		*n = result
+		return nil
+	}
+
+	var networks map[string]*ServiceNetwork
+	if err := node.Decode(&networks); err != nil {
+		return err
+	}
+	for name, network := range networks {
+		result[name] = network
+	}
+	*n = result
+	return nil
+}
+
+// DependsOn holds the services a service depends on
+type DependsOn []string
+
+// UnmarshalYAML accepts a list of service names as well as the long syntax
+// map (conditions are ignored)
+func (d *DependsOn) UnmarshalYAML(node *yaml.Node) error {
+	var deps []string
+
+	switch node.Kind {
+	case yaml.SequenceNode:
+		for _, item := range node.Content {
+			deps = append(deps, item.Value)
+		}
+	case yaml.MappingNode:
+		for i := 0; i < len(node.Content); i += 2 {
+			deps = append(deps, node.Content[i].Value)
+		}
+	default:
+		return fmt.Errorf("line %d: depends_on must be a list or a map", node.Line)
+	}
+
+	sort.Strings(deps)
+	*d = deps
+	return nil
+}
+
+// UnmarshalYAML accepts a build context path as well as a build mapping
+func (b *Build) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.ScalarNode {
+		b.Context = node.Value
+		return nil
+	}
+
+	type plain Build
+	return node.Decode((*plain)(b))
+}
+
+// Load reads and parses a compose file. Variables are interpolated from the
+// process environment and an optional .env file next to the compose file.
+func Load(path string) (*File, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read compose file: %w", err)
+	}
+
+	vars := make(map[string]string)
+	dotEnv := filepath.Join(filepath.Dir(path), ".env")
+	if _, err := os.Stat(dotEnv); err == nil {
+		loaded, err := envfile.LoadEnvFile(dotEnv)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", dotEnv, err)
+		}
+		vars = loaded
+	}
+	for _, e := range os.Environ() {
+		parts := strings.SplitN(e, "=", 2)
+		vars[parts[0]] = parts[1]
+	}
+
+	return Parse(data, vars)
+}
+
+// Parse parses compose file content, interpolating variables from vars.
+// Variables are interpolated in the values once parsed, so that neither
+// comments nor values holding YAML syntax, like "a #b", change the file.
+func Parse(data []byte, vars map[string]string) (*File, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("failed to parse compose file: %w", err)
+	}
+	if err := interpolateNode(&doc, vars); err != nil {
+		return nil, err
+	}
+
+	var f File
+	if err := doc.Decode(&f); err != nil {
+		return nil, fmt.Errorf("failed to parse compose file: %w", err)
+	}
+
+	if err := f.Validate(); err != nil {
+		return nil, err
+	}
+
+	return &f, nil
+}
+
+// Validate checks that the compose file can be run
+func (f *File) Validate() error {
+	if len(f.Services) == 0 {
+		return fmt.Errorf("compose file has no services")
+	}
+
+	for name, svc := range f.Services {
+		if svc == nil {
+			return fmt.Errorf("service '%s' is empty", name)
+		}
+		if svc.Image == "" && svc.Build == nil {
+			return fmt.Errorf("service '%s' needs an image or a build section", name)
+		}
+		for _, dep := range svc.DependsOn {
+			if _, ok := f.Services[dep]; !ok {
+				return fmt.Errorf("service '%s' depends on unknown service '%s'", name, dep)
+			}
+		}
+	}
+
+	_, err := f.StartOrder()
+	return err
+}
+
+// StartOrder returns the service names ordered so that every service comes
+// after the services it depends on
+func (f *File) StartOrder() ([]string, error) {
+	const (
+		unvisited = iota
+		visiting
+		done
+	)
+
+	state := make(map[string]int, len(f.Services))
+	var order []string
+
+	var visit func(name string) error
+	visit = func(name string) error {
+		switch state[name] {
+		case visiting:
+			return fmt.Errorf("circular dependency involving service '%s'", name)
+		case done:
+			return nil
+		}
+
+		state[name] = visiting
+		if svc := f.Services[name]; svc != nil {
+			for _, dep := range svc.DependsOn {
+				if err := visit(dep); err != nil {
+					return err
+				}
+			}
+		}
+		state[name] = done
+		order = append(order, name)
+		return nil
+	}
+
+	for _, name := range f.ServiceNames() {
+		if err := visit(name); err != nil {
+			return nil, err
+		}
+	}
+
+	return order, nil
+}
+
+// Interpolate substitutes $VAR, ${VAR}, ${VAR:-default}, ${VAR-default},
+// ${VAR:?error} and ${VAR?error} in content; $$ is a literal dollar sign
+func Interpolate(content string, vars map[string]string) (string, error) {
+	var out strings.Builder
+
+	for i := 0; i < len(content); i++ {
+		c := content[i]
+		if c != '$' || i+1 >= len(content) {
+			out.WriteByte(c)
+			continue
+		}
+
+		next := content[i+1]
+		switch {
+		case next == '$':
+			out.WriteByte('$')
+			i++
+
+		case next == '{':
+			end := strings.IndexByte(content[i+2:], '}')
+			if end < 0 {
+				return "", fmt.Errorf("unterminated variable at offset %d", i)
+			}
+			expr := content[i+2 : i+2+end]
+			value, err := expandVariable(expr, vars)
+			if err != nil {
+				return "", err
+			}
+			out.WriteString(value)
+			i += 2 + end
+
+		case isVariableChar(next, true):
+			j := i + 1
+			for j < len(content) && isVariableChar(content[j], false) {
+				j++
+			}
+			out.WriteString(vars[content[i+1:j]])
+			i = j - 1
+
+		default:
+			out.WriteByte(c)
+		}
+	}
+
+	return out.String(), nil
+}
+
+// interpolateNode interpolates the scalar values under node, in place.
+// Mapping keys and the targets of aliases, interpolated where anchored, are
+// left as they are.
+func interpolateNode(node *yaml.Node, vars map[string]string) error {
+	switch node.Kind {
+	case yaml.ScalarNode:
+		value, err := Interpolate(node.Value, vars)
+		if err != nil {
+			return err
+		}
+		if value != node.Value {
+			node.Value = value
+			if node.Style&(yaml.TaggedStyle|yaml.SingleQuotedStyle|yaml.DoubleQuotedStyle|yaml.LiteralStyle|yaml.FoldedStyle) == 0 {
+				// Resolved again, so that "${PORT}" can be a number
+				node.Tag = ""
+			}
+		}
+	case yaml.MappingNode:
+		for i := 1; i < len(node.Content); i += 2 {
+			if err := interpolateNode(node.Content[i], vars); err != nil {
+				return err
+			}
+		}
+	case yaml.DocumentNode, yaml.SequenceNode:
+		for _, child := range node.Content {
+			if err := interpolateNode(child, vars); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// expandVariable resolves the expression inside ${...}
+func expandVariable(expr string, vars map[string]string) (string, error) {
+	n := 0
+	for n < len(expr) && isVariableChar(expr[n], n == 0) {
+		n++
+	}
+	if n == 0 {
+		return "", fmt.Errorf("invalid variable '${%s}'", expr)
+	}
+
+	name, rest := expr[:n], expr[n:]
+	value, set := vars[name]
+	if rest == "" {
+		return value, nil
+	}
+
+	var op string
+	for _, candidate := range []string{":-", ":?", "-", "?"} {
+		if strings.HasPrefix(rest, candidate) {
+			op = candidate
+			break
+		}
+	}
+	if op == "" {
+		return "", fmt.Errorf("invalid variable '${%s}'", expr)
+	}
+
+	arg := rest[len(op):]
+	missing := !set || (strings.HasPrefix(op, ":") && value == "")
+	if !missing {
+		return value, nil
+	}
+
+	if strings.HasSuffix(op, "?") {
+		if arg == "" {
+			arg = "not set"
+		}
+		return "", fmt.Errorf("required variable %s: %s", name, arg)
+	}
+	return arg, nil
+}
+
+func isVariableChar(c byte, first bool) bool {
+	if c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') {
+		return true
+	}
+	return !first && c >= '0' && c <= '9'
+}
+
+// splitCommand splits a command string into words, honouring single and
+// double quotes
+func splitCommand(command string) []string {
+	var words []string
+	var current strings.Builder
+	var quote byte
+	inWord := false
+
+	for i := 0; i < len(command); i++ {
+		c := command[i]
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			} else {
+				current.WriteByte(c)
+			}
+		case c == '\'' || c == '"':
+			quote = c
+			inWord = true
+		case c == ' ' || c == '\t' || c == '\n':
+			if inWord {
+				words = append(words, current.String())
+				current.Reset()
+				inWord = false
+			}
+		default:
+			current.WriteByte(c)
+			inWord = true
+		}
+	}
+
+	if inWord {
+		words = append(words, current.String())
+	}
+
+	return words
+}
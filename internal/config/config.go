@@ -4,9 +4,14 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"sync"
 	"time"
 
 	"github.com/BurntSushi/toml"
+	"github.com/dokulabs/doku-cli/internal/readonly"
+	"github.com/dokulabs/doku-cli/internal/secrets"
+	"github.com/dokulabs/doku-cli/internal/timing"
+	"github.com/dokulabs/doku-cli/internal/workspace"
 	"github.com/dokulabs/doku-cli/pkg/types"
 )
 
@@ -17,21 +22,26 @@ const (
 	DokuDirName     = ".doku"
 )
 
-// Manager handles configuration operations
+// Manager handles configuration operations. It is safe for concurrent use:
+// writes through Update are serialized, and the instance and project
+// accessors return copies, which callers change freely and save with
+// UpdateInstance or EditInstance. The configuration Get returns is shared:
+// it must only be changed through Update.
 type Manager struct {
 	configPath string
 	dokuDir    string
 	config     *types.Config
+	mu         sync.RWMutex
 }
 
-// New creates a new configuration manager
+// New creates a new configuration manager for the selected workspace
 func New() (*Manager, error) {
-	homeDir, err := os.UserHomeDir()
+	baseDir, err := BaseDir()
 	if err != nil {
-		return nil, fmt.Errorf("failed to get home directory: %w", err)
+		return nil, err
 	}
 
-	dokuDir := filepath.Join(homeDir, DokuDirName)
+	dokuDir := workspace.Dir(baseDir)
 	configPath := filepath.Join(dokuDir, ConfigFileName)
 
 	return &Manager{
@@ -40,6 +50,16 @@ func New() (*Manager, error) {
 	}, nil
 }
 
+// BaseDir returns the Doku directory, ~/.doku, which is the default
+// workspace's and holds the other workspaces
+func BaseDir() (string, error) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get home directory: %w", err)
+	}
+	return filepath.Join(homeDir, DokuDirName), nil
+}
+
 // NewWithCustomPath creates a new configuration manager with a custom doku directory path
 // This is primarily used for testing purposes
 func NewWithCustomPath(dokuDir string) (*Manager, error) {
@@ -53,6 +73,10 @@ func NewWithCustomPath(dokuDir string) (*Manager, error) {
 
 // Initialize creates the Doku directory and default configuration
 func (m *Manager) Initialize() error {
+	if err := readonly.Check("initialize Doku"); err != nil {
+		return err
+	}
+
 	// Create .doku directory if it doesn't exist
 	if err := os.MkdirAll(m.dokuDir, 0755); err != nil {
 		return fmt.Errorf("failed to create doku directory: %w", err)
@@ -91,6 +115,14 @@ func (m *Manager) CreateDefault() error {
 
 // Load reads the configuration from disk
 func (m *Manager) Load() (*types.Config, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.load()
+}
+
+func (m *Manager) load() (*types.Config, error) {
+	defer timing.Track(timing.Config, "load "+filepath.Base(m.configPath))()
+
 	if !m.Exists() {
 		return nil, fmt.Errorf("config file does not exist: %s", m.configPath)
 	}
@@ -99,6 +131,10 @@ func (m *Manager) Load() (*types.Config, error) {
 	if _, err := toml.DecodeFile(m.configPath, &config); err != nil {
 		return nil, fmt.Errorf("failed to decode config file: %w", err)
 	}
+	if err := openConfig(&config); err != nil {
+		return nil, err
+	}
+	secrets.Enable(config.Preferences.EncryptSecrets)
 
 	// Initialize maps if nil
 	if config.Instances == nil {
@@ -114,11 +150,33 @@ func (m *Manager) Load() (*types.Config, error) {
 
 // Save writes the configuration to disk
 func (m *Manager) Save(config *types.Config) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.save(config)
+}
+
+func (m *Manager) save(config *types.Config) error {
+	if err := readonly.Check("write the Doku configuration"); err != nil {
+		return err
+	}
+	defer timing.Track(timing.Config, "save "+filepath.Base(m.configPath))()
+
 	// Ensure directory exists
 	if err := os.MkdirAll(filepath.Dir(m.configPath), 0755); err != nil {
 		return fmt.Errorf("failed to create config directory: %w", err)
 	}
 
+	// Sensitive fields are only encrypted on disk
+	secrets.Enable(config.Preferences.EncryptSecrets)
+	onDisk := config
+	if config.Preferences.EncryptSecrets {
+		sealed, err := sealConfig(config)
+		if err != nil {
+			return err
+		}
+		onDisk = sealed
+	}
+
 	// Create temporary file
 	tmpFile := m.configPath + ".tmp"
 	f, err := os.Create(tmpFile)
@@ -128,7 +186,7 @@ func (m *Manager) Save(config *types.Config) error {
 
 	// Encode config to TOML
 	encoder := toml.NewEncoder(f)
-	if err := encoder.Encode(config); err != nil {
+	if err := encoder.Encode(onDisk); err != nil {
 		f.Close()
 		os.Remove(tmpFile)
 		return fmt.Errorf("failed to encode config: %w", err)
@@ -149,17 +207,38 @@ func (m *Manager) Save(config *types.Config) error {
 	return nil
 }
 
-// Get returns the current configuration (loads if not cached)
+// Get returns the current configuration (loads if not cached). It is the
+// cached configuration itself: change it through Update, and read its
+// instances through GetInstance and ListInstances where it may be updated
+// concurrently.
 func (m *Manager) Get() (*types.Config, error) {
+	m.mu.RLock()
+	config := m.config
+	m.mu.RUnlock()
+	if config != nil {
+		return config, nil
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.get()
+}
+
+// get returns the cached configuration, loading it if needed. The caller
+// must hold the write lock.
+func (m *Manager) get() (*types.Config, error) {
 	if m.config != nil {
 		return m.config, nil
 	}
-	return m.Load()
+	return m.load()
 }
 
 // Update updates specific fields in the configuration
 func (m *Manager) Update(updateFn func(*types.Config) error) error {
-	config, err := m.Get()
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	config, err := m.get()
 	if err != nil {
 		return err
 	}
@@ -168,7 +247,7 @@ func (m *Manager) Update(updateFn func(*types.Config) error) error {
 		return err
 	}
 
-	return m.Save(config)
+	return m.save(config)
 }
 
 // GetDokuDir returns the path to the .doku directory
@@ -186,11 +265,22 @@ func (m *Manager) GetTraefikDir() string {
 	return filepath.Join(m.dokuDir, "traefik")
 }
 
+// GetCaddyDir returns the path to the Caddy directory
+func (m *Manager) GetCaddyDir() string {
+	return filepath.Join(m.dokuDir, "caddy")
+}
+
 // GetCertsDir returns the path to the certs directory
 func (m *Manager) GetCertsDir() string {
 	return filepath.Join(m.dokuDir, "certs")
 }
 
+// GetCADir returns the path to the directory of an imported CA, which
+// issues the certificates instead of mkcert
+func (m *Manager) GetCADir() string {
+	return filepath.Join(m.dokuDir, "ca")
+}
+
 // GetServicesDir returns the path to the services directory
 func (m *Manager) GetServicesDir() string {
 	return filepath.Join(m.dokuDir, "services")
@@ -221,13 +311,14 @@ func (m *Manager) SetProtocol(protocol string) error {
 	})
 }
 
-// AddInstance adds a new service instance to the configuration
+// AddInstance adds a new service instance to the configuration. A copy is
+// stored, so the caller can keep changing instance.
 func (m *Manager) AddInstance(instance *types.Instance) error {
 	return m.Update(func(c *types.Config) error {
 		if c.Instances == nil {
 			c.Instances = make(map[string]*types.Instance)
 		}
-		c.Instances[instance.Name] = instance
+		c.Instances[instance.Name] = deepCopy(instance)
 		return nil
 	})
 }
@@ -236,35 +327,56 @@ func (m *Manager) AddInstance(instance *types.Instance) error {
 func (m *Manager) RemoveInstance(name string) error {
 	return m.Update(func(c *types.Config) error {
 		delete(c.Instances, name)
+		removeGroupMember(c, name)
+		removeDependent(c, name)
 		return nil
 	})
 }
 
-// GetInstance retrieves a service instance by name
+// removeDependent drops a removed instance from the instances installed as
+// its dependencies
+func removeDependent(c *types.Config, name string) {
+	for _, instance := range c.Instances {
+		kept := instance.DependencyOf[:0]
+		for _, dependent := range instance.DependencyOf {
+			if dependent != name {
+				kept = append(kept, dependent)
+			}
+		}
+		if len(kept) == 0 {
+			kept = nil
+		}
+		instance.DependencyOf = kept
+	}
+}
+
+// GetInstance returns a copy of a service instance
 func (m *Manager) GetInstance(name string) (*types.Instance, error) {
-	config, err := m.Get()
-	if err != nil {
+	if _, err := m.Get(); err != nil {
 		return nil, err
 	}
 
-	instance, exists := config.Instances[name]
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	instance, exists := m.config.Instances[name]
 	if !exists {
 		return nil, fmt.Errorf("instance not found: %s", name)
 	}
 
-	return instance, nil
+	return deepCopy(instance), nil
 }
 
-// ListInstances returns all service instances
+// ListInstances returns copies of all service instances
 func (m *Manager) ListInstances() ([]*types.Instance, error) {
-	config, err := m.Get()
-	if err != nil {
+	if _, err := m.Get(); err != nil {
 		return nil, err
 	}
 
-	instances := make([]*types.Instance, 0, len(config.Instances))
-	for _, instance := range config.Instances {
-		instances = append(instances, instance)
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	instances := make([]*types.Instance, 0, len(m.config.Instances))
+	for _, instance := range m.config.Instances {
+		instances = append(instances, deepCopy(instance))
 	}
 
 	return instances, nil
@@ -277,28 +389,45 @@ func (m *Manager) HasInstance(name string) bool {
 		return false
 	}
 
+	m.mu.RLock()
 	_, exists := config.Instances[name]
+	m.mu.RUnlock()
 	return exists
 }
 
-// UpdateInstance updates an existing instance
+// UpdateInstance replaces an existing instance with a copy of instance
 func (m *Manager) UpdateInstance(name string, instance *types.Instance) error {
 	return m.Update(func(c *types.Config) error {
 		if _, exists := c.Instances[name]; !exists {
 			return fmt.Errorf("instance not found: %s", name)
 		}
-		c.Instances[name] = instance
+		c.Instances[name] = deepCopy(instance)
+		return nil
+	})
+}
+
+// EditInstance changes an existing instance with edit, under the lock of
+// Update, and saves it. Unlike UpdateInstance, it keeps what others changed
+// in the instance since it was read.
+func (m *Manager) EditInstance(name string, edit func(instance *types.Instance)) error {
+	return m.Update(func(c *types.Config) error {
+		instance, exists := c.Instances[name]
+		if !exists {
+			return fmt.Errorf("instance not found: %s", name)
+		}
+		edit(instance)
 		return nil
 	})
 }
 
-// AddProject adds a new project to the configuration
+// AddProject adds a new project to the configuration. A copy is stored, so
+// the caller can keep changing project.
 func (m *Manager) AddProject(project *types.Project) error {
 	return m.Update(func(c *types.Config) error {
 		if c.Projects == nil {
 			c.Projects = make(map[string]*types.Project)
 		}
-		c.Projects[project.Name] = project
+		c.Projects[project.Name] = deepCopy(project)
 		return nil
 	})
 }
@@ -307,23 +436,41 @@ func (m *Manager) AddProject(project *types.Project) error {
 func (m *Manager) RemoveProject(name string) error {
 	return m.Update(func(c *types.Config) error {
 		delete(c.Projects, name)
+		removeGroupMember(c, name)
 		return nil
 	})
 }
 
-// GetProject retrieves a project by name
+// GetProject returns a copy of a project
 func (m *Manager) GetProject(name string) (*types.Project, error) {
-	config, err := m.Get()
-	if err != nil {
+	if _, err := m.Get(); err != nil {
 		return nil, err
 	}
 
-	project, exists := config.Projects[name]
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	project, exists := m.config.Projects[name]
 	if !exists {
 		return nil, fmt.Errorf("project not found: %s", name)
 	}
 
-	return project, nil
+	return deepCopy(project), nil
+}
+
+// ListProjects returns copies of all projects
+func (m *Manager) ListProjects() ([]*types.Project, error) {
+	if _, err := m.Get(); err != nil {
+		return nil, err
+	}
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	projects := make([]*types.Project, 0, len(m.config.Projects))
+	for _, project := range m.config.Projects {
+		projects = append(projects, deepCopy(project))
+	}
+
+	return projects, nil
 }
 
 // UpdateCatalogVersion updates the catalog version and timestamp
@@ -340,22 +487,23 @@ func (m *Manager) getDefaultConfig() *types.Config {
 	return &types.Config{
 		Preferences: types.PreferencesConfig{
 			Protocol:       DefaultProtocol,
-			Domain:         DefaultDomain,
+			Domain:         workspace.Domain(DefaultDomain),
 			CatalogVersion: "",
 			LastUpdate:     time.Now(),
 			DNSSetup:       "none",
+			Context:        workspace.Current(),
 		},
 		Network: types.NetworkGlobalConfig{
-			Name:    "doku-network",
+			Name:    workspace.NetworkName(),
 			Subnet:  "172.20.0.0/16",
 			Gateway: "172.20.0.1",
 		},
 		Traefik: types.TraefikGlobalConfig{
-			ContainerName:    "doku-traefik",
+			ContainerName:    workspace.TraefikContainerName(),
 			Status:           types.StatusUnknown,
 			DashboardEnabled: true,
-			HTTPPort:         80,
-			HTTPSPort:        443,
+			HTTPPort:         DefaultHTTPPort,
+			HTTPSPort:        DefaultHTTPSPort,
 			DashboardURL:     "",
 		},
 		Certificates: types.CertificatesConfig{
@@ -400,6 +548,16 @@ func (m *Manager) GetDomain() (string, error) {
 	return config.Preferences.Domain, nil
 }
 
+// GetContext returns the context name used to mark this setup's hosts file
+// entries, or "" for the default context
+func (m *Manager) GetContext() string {
+	config, err := m.Get()
+	if err != nil {
+		return ""
+	}
+	return config.Preferences.Context
+}
+
 // GetProtocol returns the configured protocol
 func (m *Manager) GetProtocol() (string, error) {
 	config, err := m.Get()
@@ -409,6 +567,66 @@ func (m *Manager) GetProtocol() (string, error) {
 	return config.Preferences.Protocol, nil
 }
 
+// GetTraefikPorts returns the host ports of Traefik's HTTP and HTTPS
+// entrypoints
+func (m *Manager) GetTraefikPorts() (httpPort, httpsPort int) {
+	config, err := m.Get()
+	if err != nil {
+		return DefaultHTTPPort, DefaultHTTPSPort
+	}
+	return TraefikPorts(config.Traefik)
+}
+
+// GetHostsIP returns the address the services' hostnames resolve to, see
+// HostsIP
+func (m *Manager) GetHostsIP() string {
+	config, err := m.Get()
+	if err != nil {
+		return DefaultHostsIP
+	}
+	return HostsIP(config.Preferences)
+}
+
+// GetPublishIP returns the host address ports are published on, see
+// PublishIP
+func (m *Manager) GetPublishIP() string {
+	config, err := m.Get()
+	if err != nil {
+		return PublishIP(types.PreferencesConfig{})
+	}
+	return PublishIP(config.Preferences)
+}
+
+// GetLocalHost returns the host published ports are reached at, see
+// LocalHost
+func (m *Manager) GetLocalHost() string {
+	config, err := m.Get()
+	if err != nil {
+		return LocalHost(types.PreferencesConfig{})
+	}
+	return LocalHost(config.Preferences)
+}
+
+// SetTraefikPorts sets the host ports of Traefik's HTTP and HTTPS
+// entrypoints
+func (m *Manager) SetTraefikPorts(httpPort, httpsPort int) error {
+	if err := ValidatePort(httpPort); err != nil {
+		return err
+	}
+	if err := ValidatePort(httpsPort); err != nil {
+		return err
+	}
+	if httpPort == httpsPort {
+		return fmt.Errorf("HTTP and HTTPS ports must differ")
+	}
+
+	return m.Update(func(c *types.Config) error {
+		c.Traefik.HTTPPort = httpPort
+		c.Traefik.HTTPSPort = httpsPort
+		return nil
+	})
+}
+
 // SetMonitoringTool sets the monitoring tool preference
 func (m *Manager) SetMonitoringTool(tool string) error {
 	validTools := map[string]bool{"dozzle": true, "none": true}
@@ -451,11 +669,13 @@ func (m *Manager) ConfigureMonitoring(tool, url, dsn string) error {
 	})
 }
 
-// GetMonitoringConfig returns the monitoring configuration
+// GetMonitoringConfig returns a copy of the monitoring configuration
 func (m *Manager) GetMonitoringConfig() (*types.MonitoringConfig, error) {
-	config, err := m.Get()
-	if err != nil {
+	if _, err := m.Get(); err != nil {
 		return nil, err
 	}
-	return &config.Monitoring, nil
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return deepCopy(&m.config.Monitoring), nil
 }
@@ -1,11 +1,14 @@
 package config
 
 import (
+	"errors"
 	"os"
 	"path/filepath"
+	"sync"
 	"testing"
 	"time"
 
+	"github.com/dokulabs/doku-cli/internal/readonly"
 	"github.com/dokulabs/doku-cli/pkg/types"
 )
 
@@ -152,6 +155,55 @@ func TestAddAndGetInstance(t *testing.T) {
 	}
 }
 
+func TestInstanceSnapshotPersists(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	mgr := &Manager{
+		dokuDir:    filepath.Join(tmpDir, ".doku"),
+		configPath: filepath.Join(tmpDir, ".doku", "config.toml"),
+	}
+
+	if err := mgr.Initialize(); err != nil {
+		t.Fatalf("Failed to initialize: %v", err)
+	}
+
+	instance := &types.Instance{
+		Name:          "postgres",
+		ServiceType:   "postgres",
+		Version:       "17",
+		ContainerName: "doku-postgres",
+		Previous: &types.InstanceSnapshot{
+			Image:       "postgres:16",
+			Version:     "16",
+			Environment: map[string]string{"POSTGRES_PASSWORD": "secret"},
+			CapturedAt:  time.Now(),
+		},
+	}
+
+	if err := mgr.AddInstance(instance); err != nil {
+		t.Fatalf("Failed to add instance: %v", err)
+	}
+
+	// Reload from disk rather than the cached config
+	loaded, err := mgr.Load()
+	if err != nil {
+		t.Fatalf("Failed to load config: %v", err)
+	}
+
+	previous := loaded.Instances["postgres"].Previous
+	if previous == nil {
+		t.Fatal("Expected rollback snapshot to be persisted")
+	}
+
+	if previous.Image != "postgres:16" || previous.Version != "16" {
+		t.Errorf("Unexpected snapshot: image=%s version=%s", previous.Image, previous.Version)
+	}
+
+	if previous.Environment["POSTGRES_PASSWORD"] != "secret" {
+		t.Errorf("Expected snapshot environment to be persisted, got %v", previous.Environment)
+	}
+}
+
 func TestListInstances(t *testing.T) {
 	tmpDir := t.TempDir()
 
@@ -228,6 +280,41 @@ func TestRemoveInstance(t *testing.T) {
 	}
 }
 
+func TestRemoveInstanceUpdatesDependencyOf(t *testing.T) {
+	mgr := newTestManager(t)
+
+	instances := []*types.Instance{
+		{Name: "signoz"},
+		{Name: "grafana"},
+		{Name: "clickhouse", DependencyOf: []string{"signoz", "grafana"}},
+		{Name: "zookeeper", DependencyOf: []string{"signoz"}},
+	}
+	for _, instance := range instances {
+		if err := mgr.AddInstance(instance); err != nil {
+			t.Fatalf("AddInstance failed: %v", err)
+		}
+	}
+
+	if err := mgr.RemoveInstance("signoz"); err != nil {
+		t.Fatalf("RemoveInstance failed: %v", err)
+	}
+
+	clickhouse, err := mgr.GetInstance("clickhouse")
+	if err != nil {
+		t.Fatalf("GetInstance failed: %v", err)
+	}
+	if len(clickhouse.DependencyOf) != 1 || clickhouse.DependencyOf[0] != "grafana" {
+		t.Errorf("clickhouse.DependencyOf = %v, expected [grafana]", clickhouse.DependencyOf)
+	}
+	zookeeper, err := mgr.GetInstance("zookeeper")
+	if err != nil {
+		t.Fatalf("GetInstance failed: %v", err)
+	}
+	if zookeeper.DependencyOf != nil {
+		t.Errorf("zookeeper.DependencyOf = %v, expected nil", zookeeper.DependencyOf)
+	}
+}
+
 func TestSetDomain(t *testing.T) {
 	tmpDir := t.TempDir()
 
@@ -293,3 +380,115 @@ func TestSetProtocol(t *testing.T) {
 		t.Error("Expected error for invalid protocol, got nil")
 	}
 }
+
+func TestSaveReadOnly(t *testing.T) {
+	mgr := newTestManager(t)
+
+	readonly.Enable(true)
+	defer readonly.Enable(false)
+
+	err := mgr.SetDomain("readonly.local")
+	if !errors.Is(err, types.ErrReadOnly) {
+		t.Fatalf("SetDomain() in read-only mode = %v, expected ErrReadOnly", err)
+	}
+
+	reloaded := &Manager{dokuDir: mgr.dokuDir, configPath: mgr.configPath}
+	domain, err := reloaded.GetDomain()
+	if err != nil {
+		t.Fatalf("Failed to get domain: %v", err)
+	}
+	if domain == "readonly.local" {
+		t.Error("config should not be written in read-only mode")
+	}
+}
+
+func TestGetInstanceReturnsCopy(t *testing.T) {
+	mgr := newTestManager(t)
+
+	if err := mgr.AddInstance(&types.Instance{
+		Name:        "redis",
+		Status:      types.StatusRunning,
+		Environment: map[string]string{"A": "1"},
+	}); err != nil {
+		t.Fatalf("Failed to add instance: %v", err)
+	}
+
+	instance, err := mgr.GetInstance("redis")
+	if err != nil {
+		t.Fatalf("Failed to get instance: %v", err)
+	}
+	instance.Status = types.StatusStopped
+	instance.Environment["A"] = "2"
+
+	stored, err := mgr.GetInstance("redis")
+	if err != nil {
+		t.Fatalf("Failed to get instance: %v", err)
+	}
+	if stored.Status != types.StatusRunning || stored.Environment["A"] != "1" {
+		t.Errorf("changing a returned instance changed the stored one: %+v", stored)
+	}
+}
+
+func TestConcurrentInstanceUpdates(t *testing.T) {
+	mgr := newTestManager(t)
+
+	if err := mgr.AddInstance(&types.Instance{Name: "redis", Status: types.StatusRunning}); err != nil {
+		t.Fatalf("Failed to add instance: %v", err)
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 10; j++ {
+				if i%2 == 0 {
+					instance, err := mgr.GetInstance("redis")
+					if err != nil {
+						t.Errorf("Failed to get instance: %v", err)
+						return
+					}
+					instance.UpdatedAt = time.Now()
+					if err := mgr.UpdateInstance("redis", instance); err != nil {
+						t.Errorf("Failed to update instance: %v", err)
+					}
+					continue
+				}
+				if err := mgr.EditInstance("redis", func(instance *types.Instance) {
+					instance.Status = types.StatusStopped
+				}); err != nil {
+					t.Errorf("Failed to edit instance: %v", err)
+				}
+				if _, err := mgr.ListInstances(); err != nil {
+					t.Errorf("Failed to list instances: %v", err)
+				}
+			}
+		}(i)
+	}
+	wg.Wait()
+}
+
+func TestListProjectsReturnsCopies(t *testing.T) {
+	mgr := newTestManager(t)
+
+	if err := mgr.AddProject(&types.Project{Name: "shop", Status: types.StatusRunning}); err != nil {
+		t.Fatalf("Failed to add project: %v", err)
+	}
+
+	projects, err := mgr.ListProjects()
+	if err != nil {
+		t.Fatalf("Failed to list projects: %v", err)
+	}
+	if len(projects) != 1 || projects[0].Name != "shop" {
+		t.Fatalf("ListProjects() = %+v, expected the shop project", projects)
+	}
+	projects[0].Status = types.StatusStopped
+
+	stored, err := mgr.GetProject("shop")
+	if err != nil {
+		t.Fatalf("Failed to get project: %v", err)
+	}
+	if stored.Status != types.StatusRunning {
+		t.Errorf("changing a listed project changed the stored one: %+v", stored)
+	}
+}
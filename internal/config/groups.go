@@ -0,0 +1,155 @@
+package config
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/dokulabs/doku-cli/pkg/types"
+)
+
+// GroupPrefix marks a command argument as a group reference, e.g. "@backend"
+const GroupPrefix = "@"
+
+// ParseGroupRef returns the group name of an "@group" argument
+func ParseGroupRef(arg string) (string, bool) {
+	if !strings.HasPrefix(arg, GroupPrefix) {
+		return "", false
+	}
+	return strings.TrimPrefix(arg, GroupPrefix), true
+}
+
+// ValidateGroupName checks that a group name is usable in "@group" arguments
+func ValidateGroupName(name string) error {
+	if name == "" {
+		return fmt.Errorf("group name cannot be empty")
+	}
+	for _, c := range name {
+		if !(c == '-' || c == '_' || (c >= 'a' && c <= 'z') || (c >= '0' && c <= '9')) {
+			return fmt.Errorf("invalid group name: %s (use lowercase letters, numbers, '-' and '_')", name)
+		}
+	}
+	return nil
+}
+
+// SetGroup creates or replaces a group. Duplicate members are dropped.
+func (m *Manager) SetGroup(name string, members []string) error {
+	if err := ValidateGroupName(name); err != nil {
+		return err
+	}
+
+	unique := make([]string, 0, len(members))
+	seen := make(map[string]bool, len(members))
+	for _, member := range members {
+		if member == "" || seen[member] {
+			continue
+		}
+		seen[member] = true
+		unique = append(unique, member)
+	}
+	if len(unique) == 0 {
+		return fmt.Errorf("group '%s' needs at least one member", name)
+	}
+
+	return m.Update(func(c *types.Config) error {
+		if c.Groups == nil {
+			c.Groups = make(map[string][]string)
+		}
+		c.Groups[name] = unique
+		return nil
+	})
+}
+
+// GetGroup returns the members of a group
+func (m *Manager) GetGroup(name string) ([]string, error) {
+	config, err := m.Get()
+	if err != nil {
+		return nil, err
+	}
+
+	m.mu.RLock()
+	members, exists := config.Groups[name]
+	m.mu.RUnlock()
+	if !exists {
+		return nil, fmt.Errorf("group not found: %s", name)
+	}
+
+	return append([]string(nil), members...), nil
+}
+
+// RemoveGroup deletes a group. Its members are not affected.
+func (m *Manager) RemoveGroup(name string) error {
+	return m.Update(func(c *types.Config) error {
+		if _, exists := c.Groups[name]; !exists {
+			return fmt.Errorf("group not found: %s", name)
+		}
+		delete(c.Groups, name)
+		return nil
+	})
+}
+
+// ListGroups returns the names of all groups, sorted
+func (m *Manager) ListGroups() ([]string, error) {
+	config, err := m.Get()
+	if err != nil {
+		return nil, err
+	}
+
+	m.mu.RLock()
+	names := make([]string, 0, len(config.Groups))
+	for name := range config.Groups {
+		names = append(names, name)
+	}
+	m.mu.RUnlock()
+
+	sort.Strings(names)
+	return names, nil
+}
+
+// ResolveNames expands "@group" arguments to their members, keeping the
+// order of the arguments and dropping duplicates
+func (m *Manager) ResolveNames(args []string) ([]string, error) {
+	var names []string
+	seen := make(map[string]bool)
+	add := func(name string) {
+		if !seen[name] {
+			seen[name] = true
+			names = append(names, name)
+		}
+	}
+
+	for _, arg := range args {
+		group, ok := ParseGroupRef(arg)
+		if !ok {
+			add(arg)
+			continue
+		}
+		members, err := m.GetGroup(group)
+		if err != nil {
+			return nil, err
+		}
+		for _, member := range members {
+			add(member)
+		}
+	}
+
+	return names, nil
+}
+
+// removeGroupMember drops a removed instance or project from all groups.
+// Groups left without members are deleted.
+func removeGroupMember(c *types.Config, name string) {
+	for group, members := range c.Groups {
+		kept := members[:0]
+		for _, member := range members {
+			if member != name {
+				kept = append(kept, member)
+			}
+		}
+		if len(kept) == 0 {
+			delete(c.Groups, group)
+		} else {
+			c.Groups[group] = kept
+		}
+	}
+}
@@ -0,0 +1,135 @@
+package config
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/dokulabs/doku-cli/pkg/types"
+)
+
+func newTestManager(t *testing.T) *Manager {
+	t.Helper()
+
+	tmpDir := t.TempDir()
+	mgr := &Manager{
+		dokuDir:    filepath.Join(tmpDir, ".doku"),
+		configPath: filepath.Join(tmpDir, ".doku", "config.toml"),
+	}
+	if err := mgr.Initialize(); err != nil {
+		t.Fatalf("Failed to initialize: %v", err)
+	}
+	return mgr
+}
+
+func TestSetAndGetGroup(t *testing.T) {
+	mgr := newTestManager(t)
+
+	if err := mgr.SetGroup("backend", []string{"postgres", "redis", "postgres", "api"}); err != nil {
+		t.Fatalf("SetGroup failed: %v", err)
+	}
+
+	// Reload from disk to check the group is persisted
+	reloaded := &Manager{dokuDir: mgr.dokuDir, configPath: mgr.configPath}
+	members, err := reloaded.GetGroup("backend")
+	if err != nil {
+		t.Fatalf("GetGroup failed: %v", err)
+	}
+
+	expected := []string{"postgres", "redis", "api"}
+	if !reflect.DeepEqual(members, expected) {
+		t.Errorf("GetGroup() = %v, expected %v", members, expected)
+	}
+
+	if _, err := mgr.GetGroup("frontend"); err == nil {
+		t.Error("GetGroup should fail for unknown groups")
+	}
+}
+
+func TestSetGroupInvalid(t *testing.T) {
+	mgr := newTestManager(t)
+
+	if err := mgr.SetGroup("Back End", []string{"postgres"}); err == nil {
+		t.Error("SetGroup should reject invalid names")
+	}
+	if err := mgr.SetGroup("backend", nil); err == nil {
+		t.Error("SetGroup should reject groups without members")
+	}
+}
+
+func TestRemoveGroup(t *testing.T) {
+	mgr := newTestManager(t)
+
+	if err := mgr.SetGroup("backend", []string{"postgres"}); err != nil {
+		t.Fatalf("SetGroup failed: %v", err)
+	}
+	if err := mgr.SetGroup("cache", []string{"redis"}); err != nil {
+		t.Fatalf("SetGroup failed: %v", err)
+	}
+	if err := mgr.RemoveGroup("backend"); err != nil {
+		t.Fatalf("RemoveGroup failed: %v", err)
+	}
+	if err := mgr.RemoveGroup("backend"); err == nil {
+		t.Error("RemoveGroup should fail for unknown groups")
+	}
+
+	names, err := mgr.ListGroups()
+	if err != nil {
+		t.Fatalf("ListGroups failed: %v", err)
+	}
+	if !reflect.DeepEqual(names, []string{"cache"}) {
+		t.Errorf("ListGroups() = %v, expected [cache]", names)
+	}
+}
+
+func TestResolveNames(t *testing.T) {
+	mgr := newTestManager(t)
+
+	if err := mgr.SetGroup("backend", []string{"postgres", "redis"}); err != nil {
+		t.Fatalf("SetGroup failed: %v", err)
+	}
+
+	names, err := mgr.ResolveNames([]string{"api", "@backend", "redis"})
+	if err != nil {
+		t.Fatalf("ResolveNames failed: %v", err)
+	}
+	expected := []string{"api", "postgres", "redis"}
+	if !reflect.DeepEqual(names, expected) {
+		t.Errorf("ResolveNames() = %v, expected %v", names, expected)
+	}
+
+	if _, err := mgr.ResolveNames([]string{"@missing"}); err == nil {
+		t.Error("ResolveNames should fail for unknown groups")
+	}
+}
+
+func TestRemoveInstanceUpdatesGroups(t *testing.T) {
+	mgr := newTestManager(t)
+
+	for _, name := range []string{"postgres", "redis"} {
+		if err := mgr.AddInstance(&types.Instance{Name: name}); err != nil {
+			t.Fatalf("AddInstance failed: %v", err)
+		}
+	}
+	if err := mgr.SetGroup("backend", []string{"postgres", "redis"}); err != nil {
+		t.Fatalf("SetGroup failed: %v", err)
+	}
+	if err := mgr.SetGroup("db", []string{"postgres"}); err != nil {
+		t.Fatalf("SetGroup failed: %v", err)
+	}
+
+	if err := mgr.RemoveInstance("postgres"); err != nil {
+		t.Fatalf("RemoveInstance failed: %v", err)
+	}
+
+	members, err := mgr.GetGroup("backend")
+	if err != nil {
+		t.Fatalf("GetGroup failed: %v", err)
+	}
+	if !reflect.DeepEqual(members, []string{"redis"}) {
+		t.Errorf("backend members = %v, expected [redis]", members)
+	}
+	if _, err := mgr.GetGroup("db"); err == nil {
+		t.Error("empty group should be deleted")
+	}
+}
@@ -100,7 +100,8 @@ func (p *Preferences) BuildServiceURL(instanceName string, useHTTPS bool) (strin
 		protocol = "https"
 	}
 
-	return fmt.Sprintf("%s://%s.%s", protocol, instanceName, domain), nil
+	httpPort, httpsPort := p.manager.GetTraefikPorts()
+	return ServiceURL(protocol, instanceName+"."+domain, httpPort, httpsPort), nil
 }
 
 // BuildInternalHostname constructs the internal Docker hostname for a service
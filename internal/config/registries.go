@@ -0,0 +1,60 @@
+package config
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/dokulabs/doku-cli/pkg/types"
+)
+
+// SetRegistryLogin saves the credentials for a registry, replacing those
+// saved for it before. The server is stored as given, so callers should
+// normalize it first.
+func (m *Manager) SetRegistryLogin(login types.RegistryLogin) error {
+	if login.Server == "" || strings.ContainsAny(login.Server, " /") {
+		return fmt.Errorf("invalid registry: %q (expected a host such as ghcr.io)", login.Server)
+	}
+	if login.Username == "" || login.Password == "" {
+		return fmt.Errorf("a username and password are required to log in to %s", login.Server)
+	}
+
+	return m.Update(func(c *types.Config) error {
+		for i := range c.Registries {
+			if c.Registries[i].Server == login.Server {
+				c.Registries[i] = login
+				return nil
+			}
+		}
+		c.Registries = append(c.Registries, login)
+		return nil
+	})
+}
+
+// RemoveRegistryLogin deletes the credentials saved for a registry
+func (m *Manager) RemoveRegistryLogin(server string) error {
+	return m.Update(func(c *types.Config) error {
+		for i, login := range c.Registries {
+			if login.Server == server {
+				c.Registries = append(c.Registries[:i], c.Registries[i+1:]...)
+				return nil
+			}
+		}
+		return fmt.Errorf("not logged in to %s", server)
+	})
+}
+
+// ListRegistryLogins returns the saved registry logins, sorted by server
+func (m *Manager) ListRegistryLogins() ([]types.RegistryLogin, error) {
+	config, err := m.Get()
+	if err != nil {
+		return nil, err
+	}
+
+	m.mu.RLock()
+	logins := append([]types.RegistryLogin(nil), config.Registries...)
+	m.mu.RUnlock()
+
+	sort.Slice(logins, func(i, j int) bool { return logins[i].Server < logins[j].Server })
+	return logins, nil
+}
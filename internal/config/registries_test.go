@@ -0,0 +1,54 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/dokulabs/doku-cli/pkg/types"
+)
+
+func TestRegistryLogins(t *testing.T) {
+	mgr := newTestManager(t)
+
+	for _, login := range []types.RegistryLogin{
+		{Server: "ghcr.io", Username: "octocat", Password: "old-token"},
+		{Server: "docker.io", Username: "dev", Password: "secret"},
+		{Server: "ghcr.io", Username: "octocat", Password: "new-token"},
+	} {
+		if err := mgr.SetRegistryLogin(login); err != nil {
+			t.Fatalf("SetRegistryLogin failed: %v", err)
+		}
+	}
+
+	// Reload from disk to check the logins are persisted and replaced
+	reloaded := &Manager{dokuDir: mgr.dokuDir, configPath: mgr.configPath}
+	logins, err := reloaded.ListRegistryLogins()
+	if err != nil {
+		t.Fatalf("ListRegistryLogins failed: %v", err)
+	}
+	if len(logins) != 2 || logins[0].Server != "docker.io" || logins[1].Password != "new-token" {
+		t.Errorf("ListRegistryLogins() = %+v, expected docker.io and the replaced ghcr.io", logins)
+	}
+
+	if err := reloaded.RemoveRegistryLogin("docker.io"); err != nil {
+		t.Fatalf("RemoveRegistryLogin failed: %v", err)
+	}
+	if err := reloaded.RemoveRegistryLogin("docker.io"); err == nil {
+		t.Error("RemoveRegistryLogin should fail for registries not logged in to")
+	}
+}
+
+func TestSetRegistryLoginInvalid(t *testing.T) {
+	mgr := newTestManager(t)
+
+	tests := []types.RegistryLogin{
+		{Server: "", Username: "dev", Password: "secret"},
+		{Server: "ghcr.io/acme", Username: "dev", Password: "secret"},
+		{Server: "ghcr.io", Username: "", Password: "secret"},
+		{Server: "ghcr.io", Username: "dev", Password: ""},
+	}
+	for _, login := range tests {
+		if err := mgr.SetRegistryLogin(login); err == nil {
+			t.Errorf("SetRegistryLogin(%+v) should fail", login)
+		}
+	}
+}
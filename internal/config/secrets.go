@@ -0,0 +1,124 @@
+package config
+
+import (
+	"fmt"
+
+	"github.com/dokulabs/doku-cli/internal/secrets"
+	"github.com/dokulabs/doku-cli/pkg/types"
+)
+
+// sealConfig returns a copy of the configuration to write to disk, its
+// sensitive fields encrypted: the services' and projects' environment and
+// connection strings, the monitoring credentials, the webhook URLs, the
+// registry passwords and the Traefik dashboard's password. The
+// configuration itself is left in plaintext, since it is the one in memory.
+func sealConfig(c *types.Config) (*types.Config, error) {
+	sealed := *c
+	if err := transformConfig(&sealed, secrets.Encrypt, secrets.EncryptMap, true); err != nil {
+		return nil, fmt.Errorf("failed to encrypt the configuration: %w", err)
+	}
+	return &sealed, nil
+}
+
+// openConfig decrypts the sensitive fields of a configuration read from
+// disk, in place
+func openConfig(c *types.Config) error {
+	if err := transformConfig(c, secrets.Decrypt, secrets.DecryptMap, false); err != nil {
+		return fmt.Errorf("failed to decrypt the configuration: %w", err)
+	}
+	return nil
+}
+
+// transformConfig applies fn to the sensitive fields of a configuration,
+// and mapFn to its sensitive maps.
+// With copyOnWrite, the instances, projects, webhooks and registry logins
+// holding them are copied first, so that those c shares with another
+// configuration aren't changed.
+func transformConfig(c *types.Config, fn func(string) (string, error), mapFn func(map[string]string) (map[string]string, error), copyOnWrite bool) error {
+	var err error
+	if c.Monitoring.DSN, err = fn(c.Monitoring.DSN); err != nil {
+		return fmt.Errorf("monitoring DSN: %w", err)
+	}
+	if c.Monitoring.APIKey, err = fn(c.Monitoring.APIKey); err != nil {
+		return fmt.Errorf("monitoring API key: %w", err)
+	}
+
+	if auth := c.Traefik.DashboardAuth; auth != nil {
+		if copyOnWrite {
+			copied := *auth
+			auth = &copied
+			c.Traefik.DashboardAuth = auth
+		}
+		if auth.Password, err = fn(auth.Password); err != nil {
+			return fmt.Errorf("traefik dashboard password: %w", err)
+		}
+	}
+
+	if copyOnWrite {
+		c.Webhooks = append([]types.WebhookConfig(nil), c.Webhooks...)
+	}
+	for i := range c.Webhooks {
+		if c.Webhooks[i].URL, err = fn(c.Webhooks[i].URL); err != nil {
+			return fmt.Errorf("webhook %s: %w", c.Webhooks[i].Name, err)
+		}
+	}
+
+	if copyOnWrite {
+		c.Registries = append([]types.RegistryLogin(nil), c.Registries...)
+	}
+	for i := range c.Registries {
+		if c.Registries[i].Password, err = fn(c.Registries[i].Password); err != nil {
+			return fmt.Errorf("registry %s: %w", c.Registries[i].Server, err)
+		}
+	}
+
+	instances := c.Instances
+	if copyOnWrite && instances != nil {
+		c.Instances = make(map[string]*types.Instance, len(instances))
+	}
+	for name, instance := range instances {
+		if copyOnWrite {
+			copied := *instance
+			if instance.Previous != nil {
+				previous := *instance.Previous
+				copied.Previous = &previous
+			}
+			copied.Links = append([]types.Link(nil), instance.Links...)
+			instance = &copied
+			c.Instances[name] = instance
+		}
+
+		if instance.Environment, err = mapFn(instance.Environment); err != nil {
+			return fmt.Errorf("instance %s: %w", name, err)
+		}
+		if instance.ConnectionString, err = fn(instance.ConnectionString); err != nil {
+			return fmt.Errorf("instance %s: %w", name, err)
+		}
+		if instance.Previous != nil {
+			if instance.Previous.Environment, err = mapFn(instance.Previous.Environment); err != nil {
+				return fmt.Errorf("instance %s: %w", name, err)
+			}
+		}
+		for i := range instance.Links {
+			if instance.Links[i].Env, err = mapFn(instance.Links[i].Env); err != nil {
+				return fmt.Errorf("instance %s: %w", name, err)
+			}
+		}
+	}
+
+	projects := c.Projects
+	if copyOnWrite && projects != nil {
+		c.Projects = make(map[string]*types.Project, len(projects))
+	}
+	for name, project := range projects {
+		if copyOnWrite {
+			copied := *project
+			project = &copied
+			c.Projects[name] = project
+		}
+		if project.Environment, err = mapFn(project.Environment); err != nil {
+			return fmt.Errorf("project %s: %w", name, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,141 @@
+package config
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+
+	"github.com/dokulabs/doku-cli/pkg/types"
+)
+
+// Default host ports of Traefik's entrypoints
+const (
+	DefaultHTTPPort  = 80
+	DefaultHTTPSPort = 443
+)
+
+// TraefikPorts returns the host ports of Traefik's HTTP and HTTPS
+// entrypoints, falling back to the defaults when unset
+func TraefikPorts(t types.TraefikGlobalConfig) (httpPort, httpsPort int) {
+	httpPort, httpsPort = t.HTTPPort, t.HTTPSPort
+	if httpPort == 0 {
+		httpPort = DefaultHTTPPort
+	}
+	if httpsPort == 0 {
+		httpsPort = DefaultHTTPSPort
+	}
+	return httpPort, httpsPort
+}
+
+// DefaultHostsIP is what the services' hostnames resolve to unless a bind
+// IP is configured
+const DefaultHostsIP = "127.0.0.1"
+
+// HostsIP returns the address the services' hostnames resolve to: the bind
+// IP, or the loopback address
+func HostsIP(prefs types.PreferencesConfig) string {
+	if prefs.BindIP == "" {
+		return DefaultHostsIP
+	}
+	return prefs.BindIP
+}
+
+// PublishIP returns the host address the proxy's and services' ports are
+// published on: the bind IP, or every address
+func PublishIP(prefs types.PreferencesConfig) string {
+	if prefs.BindIP == "" {
+		return "0.0.0.0"
+	}
+	return prefs.BindIP
+}
+
+// LocalHost returns the host a published port is reached at, for messages:
+// the bind IP, or localhost
+func LocalHost(prefs types.PreferencesConfig) string {
+	if prefs.BindIP == "" {
+		return "localhost"
+	}
+	return prefs.BindIP
+}
+
+// ValidateBindIP checks that ip can be both the target of hosts entries and
+// the address ports are published on. "" unsets it.
+func ValidateBindIP(ip string) error {
+	if ip == "" {
+		return nil
+	}
+	parsed := net.ParseIP(ip)
+	if parsed == nil || parsed.To4() == nil {
+		return fmt.Errorf("invalid bind IP '%s': expected an IPv4 address, e.g. 127.0.0.2", ip)
+	}
+	if parsed.IsUnspecified() || parsed.IsMulticast() || parsed.Equal(net.IPv4bcast) {
+		return fmt.Errorf("invalid bind IP '%s': hostnames can't resolve to it", ip)
+	}
+	return nil
+}
+
+// ValidatePort checks that port is a usable TCP port
+func ValidatePort(port int) error {
+	if port < 1 || port > 65535 {
+		return fmt.Errorf("invalid port %d: must be between 1 and 65535", port)
+	}
+	return nil
+}
+
+// ServiceURL returns the URL Traefik serves host at. The port is only
+// included when it isn't the protocol's default.
+func ServiceURL(protocol, host string, httpPort, httpsPort int) string {
+	port, defaultPort := httpPort, DefaultHTTPPort
+	if protocol == "https" {
+		port, defaultPort = httpsPort, DefaultHTTPSPort
+	}
+	if port == 0 || port == defaultPort {
+		return fmt.Sprintf("%s://%s", protocol, host)
+	}
+	return fmt.Sprintf("%s://%s:%d", protocol, host, port)
+}
+
+// WithTraefikPorts points an http(s) URL at the given entrypoint ports,
+// keeping its path. Other URLs are returned unchanged.
+func WithTraefikPorts(rawURL string, httpPort, httpsPort int) string {
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Host == "" || (u.Scheme != "http" && u.Scheme != "https") {
+		return rawURL
+	}
+	base, err := url.Parse(ServiceURL(u.Scheme, u.Hostname(), httpPort, httpsPort))
+	if err != nil {
+		return rawURL
+	}
+	u.Host = base.Host
+	return u.String()
+}
+
+// URLHost returns the host name of a service URL, without scheme or port
+// (e.g. "https://api.doku.local:8443" -> "api.doku.local")
+func URLHost(rawURL string) string {
+	if u, err := url.Parse(rawURL); err == nil && u.Host != "" {
+		return u.Hostname()
+	}
+	host := strings.TrimPrefix(strings.TrimPrefix(rawURL, "https://"), "http://")
+	return strings.SplitN(host, "/", 2)[0]
+}
+
+// ValidateLink checks a named link of a project: a short name without
+// spaces or '=' and an http(s) URL with a host
+func ValidateLink(name, rawURL string) error {
+	if name == "" || strings.ContainsAny(name, " \t=") {
+		return fmt.Errorf("invalid link name %q: use a short name like docs or api-docs", name)
+	}
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return fmt.Errorf("invalid URL for link %s: %w", name, err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return fmt.Errorf("invalid URL for link %s: %q must start with http:// or https://", name, rawURL)
+	}
+	if u.Host == "" {
+		return fmt.Errorf("invalid URL for link %s: missing host", name)
+	}
+	return nil
+}
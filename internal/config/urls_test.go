@@ -0,0 +1,139 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/dokulabs/doku-cli/pkg/types"
+)
+
+func TestTraefikPorts(t *testing.T) {
+	httpPort, httpsPort := TraefikPorts(types.TraefikGlobalConfig{})
+	if httpPort != 80 || httpsPort != 443 {
+		t.Errorf("TraefikPorts(unset) = %d, %d, want 80, 443", httpPort, httpsPort)
+	}
+
+	httpPort, httpsPort = TraefikPorts(types.TraefikGlobalConfig{HTTPPort: 8080, HTTPSPort: 8443})
+	if httpPort != 8080 || httpsPort != 8443 {
+		t.Errorf("TraefikPorts(8080, 8443) = %d, %d", httpPort, httpsPort)
+	}
+}
+
+func TestServiceURL(t *testing.T) {
+	tests := []struct {
+		protocol            string
+		httpPort, httpsPort int
+		want                string
+	}{
+		{"https", 80, 443, "https://api.doku.local"},
+		{"http", 80, 443, "http://api.doku.local"},
+		{"https", 8080, 8443, "https://api.doku.local:8443"},
+		{"http", 8080, 8443, "http://api.doku.local:8080"},
+		{"https", 8080, 443, "https://api.doku.local"},
+		{"https", 0, 0, "https://api.doku.local"},
+	}
+
+	for _, tt := range tests {
+		if got := ServiceURL(tt.protocol, "api.doku.local", tt.httpPort, tt.httpsPort); got != tt.want {
+			t.Errorf("ServiceURL(%s, %d, %d) = %q, want %q", tt.protocol, tt.httpPort, tt.httpsPort, got, tt.want)
+		}
+	}
+}
+
+func TestWithTraefikPorts(t *testing.T) {
+	tests := []struct {
+		url                 string
+		httpPort, httpsPort int
+		want                string
+	}{
+		{"https://api.doku.local", 8080, 8443, "https://api.doku.local:8443"},
+		{"https://api.doku.local:8443/admin", 80, 443, "https://api.doku.local/admin"},
+		{"http://api.doku.local:8080", 8000, 443, "http://api.doku.local:8000"},
+		{"postgresql://user@postgres.doku.local:5432/db", 8080, 8443, "postgresql://user@postgres.doku.local:5432/db"},
+		{"", 8080, 8443, ""},
+	}
+
+	for _, tt := range tests {
+		if got := WithTraefikPorts(tt.url, tt.httpPort, tt.httpsPort); got != tt.want {
+			t.Errorf("WithTraefikPorts(%q) = %q, want %q", tt.url, got, tt.want)
+		}
+	}
+}
+
+func TestURLHost(t *testing.T) {
+	tests := map[string]string{
+		"https://api.doku.local":         "api.doku.local",
+		"https://api.doku.local:8443":    "api.doku.local",
+		"http://ui.doku.local:8080/path": "ui.doku.local",
+		"api.doku.local":                 "api.doku.local",
+	}
+
+	for url, want := range tests {
+		if got := URLHost(url); got != want {
+			t.Errorf("URLHost(%q) = %q, want %q", url, got, want)
+		}
+	}
+}
+
+func TestValidatePort(t *testing.T) {
+	for _, port := range []int{1, 80, 8443, 65535} {
+		if err := ValidatePort(port); err != nil {
+			t.Errorf("ValidatePort(%d) = %v", port, err)
+		}
+	}
+	for _, port := range []int{0, -1, 65536} {
+		if err := ValidatePort(port); err == nil {
+			t.Errorf("ValidatePort(%d) should fail", port)
+		}
+	}
+}
+
+func TestBindIP(t *testing.T) {
+	unset := types.PreferencesConfig{}
+	if HostsIP(unset) != "127.0.0.1" || PublishIP(unset) != "0.0.0.0" || LocalHost(unset) != "localhost" {
+		t.Errorf("unset bind IP = %s, %s, %s", HostsIP(unset), PublishIP(unset), LocalHost(unset))
+	}
+
+	set := types.PreferencesConfig{BindIP: "127.0.0.2"}
+	if HostsIP(set) != "127.0.0.2" || PublishIP(set) != "127.0.0.2" || LocalHost(set) != "127.0.0.2" {
+		t.Errorf("bind IP 127.0.0.2 = %s, %s, %s", HostsIP(set), PublishIP(set), LocalHost(set))
+	}
+}
+
+func TestValidateBindIP(t *testing.T) {
+	for _, ip := range []string{"", "127.0.0.2", "172.28.160.1"} {
+		if err := ValidateBindIP(ip); err != nil {
+			t.Errorf("ValidateBindIP(%q) = %v", ip, err)
+		}
+	}
+	for _, ip := range []string{"localhost", "0.0.0.0", "::1", "224.0.0.1", "127.0.0"} {
+		if err := ValidateBindIP(ip); err == nil {
+			t.Errorf("ValidateBindIP(%q) should fail", ip)
+		}
+	}
+}
+
+func TestValidateLink(t *testing.T) {
+	valid := map[string]string{
+		"docs":     "https://api.doku.local/docs",
+		"api-docs": "http://localhost:8080/swagger",
+	}
+	for name, url := range valid {
+		if err := ValidateLink(name, url); err != nil {
+			t.Errorf("ValidateLink(%q, %q) = %v", name, url, err)
+		}
+	}
+
+	invalid := [][2]string{
+		{"", "https://example.com"},
+		{"my docs", "https://example.com"},
+		{"a=b", "https://example.com"},
+		{"docs", "file:///etc/passwd"},
+		{"docs", "example.com/docs"},
+		{"docs", "https://"},
+	}
+	for _, link := range invalid {
+		if err := ValidateLink(link[0], link[1]); err == nil {
+			t.Errorf("ValidateLink(%q, %q) should fail", link[0], link[1])
+		}
+	}
+}
@@ -0,0 +1,76 @@
+package config
+
+import (
+	"fmt"
+	"net/url"
+	"sort"
+
+	"github.com/dokulabs/doku-cli/pkg/types"
+)
+
+// SetWebhook adds a webhook, or replaces the one of the same name
+func (m *Manager) SetWebhook(hook types.WebhookConfig) error {
+	if err := ValidateGroupName(hook.Name); err != nil {
+		return fmt.Errorf("invalid webhook name: %s (use lowercase letters, numbers, '-' and '_')", hook.Name)
+	}
+	u, err := url.Parse(hook.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("invalid webhook URL: %s (expected http:// or https://)", hook.URL)
+	}
+
+	return m.Update(func(c *types.Config) error {
+		for i := range c.Webhooks {
+			if c.Webhooks[i].Name == hook.Name {
+				c.Webhooks[i] = hook
+				return nil
+			}
+		}
+		c.Webhooks = append(c.Webhooks, hook)
+		return nil
+	})
+}
+
+// GetWebhook returns a webhook by name
+func (m *Manager) GetWebhook(name string) (types.WebhookConfig, error) {
+	config, err := m.Get()
+	if err != nil {
+		return types.WebhookConfig{}, err
+	}
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	for _, hook := range config.Webhooks {
+		if hook.Name == name {
+			return hook, nil
+		}
+	}
+	return types.WebhookConfig{}, fmt.Errorf("webhook not found: %s", name)
+}
+
+// RemoveWebhook deletes a webhook
+func (m *Manager) RemoveWebhook(name string) error {
+	return m.Update(func(c *types.Config) error {
+		for i, hook := range c.Webhooks {
+			if hook.Name == name {
+				c.Webhooks = append(c.Webhooks[:i], c.Webhooks[i+1:]...)
+				return nil
+			}
+		}
+		return fmt.Errorf("webhook not found: %s", name)
+	})
+}
+
+// ListWebhooks returns all webhooks, sorted by name
+func (m *Manager) ListWebhooks() ([]types.WebhookConfig, error) {
+	config, err := m.Get()
+	if err != nil {
+		return nil, err
+	}
+
+	m.mu.RLock()
+	hooks := append([]types.WebhookConfig(nil), config.Webhooks...)
+	m.mu.RUnlock()
+
+	sort.Slice(hooks, func(i, j int) bool { return hooks[i].Name < hooks[j].Name })
+	return hooks, nil
+}
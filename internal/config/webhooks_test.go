@@ -0,0 +1,73 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/dokulabs/doku-cli/pkg/types"
+)
+
+func TestSetAndGetWebhook(t *testing.T) {
+	mgr := newTestManager(t)
+
+	hook := types.WebhookConfig{Name: "slack", URL: "https://hooks.slack.com/services/x", Format: "slack", Events: []string{"fail"}}
+	if err := mgr.SetWebhook(hook); err != nil {
+		t.Fatalf("SetWebhook failed: %v", err)
+	}
+	hook.Events = nil
+	if err := mgr.SetWebhook(hook); err != nil {
+		t.Fatalf("SetWebhook failed: %v", err)
+	}
+
+	// Reload from disk to check the webhook is persisted and was replaced
+	reloaded := &Manager{dokuDir: mgr.dokuDir, configPath: mgr.configPath}
+	hooks, err := reloaded.ListWebhooks()
+	if err != nil {
+		t.Fatalf("ListWebhooks failed: %v", err)
+	}
+	if len(hooks) != 1 || hooks[0].Name != "slack" || len(hooks[0].Events) != 0 {
+		t.Errorf("ListWebhooks() = %+v, expected the replaced webhook", hooks)
+	}
+
+	if _, err := reloaded.GetWebhook("ci"); err == nil {
+		t.Error("GetWebhook should fail for unknown webhooks")
+	}
+}
+
+func TestSetWebhookInvalid(t *testing.T) {
+	mgr := newTestManager(t)
+
+	tests := []types.WebhookConfig{
+		{Name: "My Hook", URL: "https://example.com"},
+		{Name: "ci", URL: "ftp://example.com"},
+		{Name: "ci", URL: "example.com/hook"},
+	}
+	for _, hook := range tests {
+		if err := mgr.SetWebhook(hook); err == nil {
+			t.Errorf("SetWebhook(%+v) should fail", hook)
+		}
+	}
+}
+
+func TestRemoveWebhook(t *testing.T) {
+	mgr := newTestManager(t)
+
+	for _, name := range []string{"slack", "ci"} {
+		if err := mgr.SetWebhook(types.WebhookConfig{Name: name, URL: "http://localhost:9000/" + name}); err != nil {
+			t.Fatalf("SetWebhook failed: %v", err)
+		}
+	}
+	if err := mgr.RemoveWebhook("slack"); err != nil {
+		t.Fatalf("RemoveWebhook failed: %v", err)
+	}
+	if err := mgr.RemoveWebhook("slack"); err == nil {
+		t.Error("RemoveWebhook should fail for unknown webhooks")
+	}
+
+	hooks, err := mgr.ListWebhooks()
+	if err != nil {
+		t.Fatalf("ListWebhooks failed: %v", err)
+	}
+	if len(hooks) != 1 || hooks[0].Name != "ci" {
+		t.Errorf("ListWebhooks() = %+v, expected [ci]", hooks)
+	}
+}
@@ -0,0 +1,118 @@
+// Package dbshell builds command lines for database CLIs that run inside a
+// service container, with credentials taken from the instance environment.
+package dbshell
+
+// Client is a database CLI and the services it connects to
+type Client struct {
+	Name         string   // Command name, e.g. "psql"
+	Description  string   // What the client connects to
+	ServiceTypes []string // Catalog service types the client works with
+
+	build func(serviceType string, env map[string]string, args []string) *Command
+}
+
+// Command is a command line to exec in the container, with extra env vars
+// that carry credentials so they don't show up in the process list
+type Command struct {
+	Args []string
+	Env  []string
+}
+
+// Clients lists the supported database CLIs
+var Clients = []*Client{
+	{
+		Name:         "psql",
+		Description:  "PostgreSQL",
+		ServiceTypes: []string{"postgres", "postgresql"},
+		build:        buildPsql,
+	},
+	{
+		Name:         "mysql",
+		Description:  "MySQL or MariaDB",
+		ServiceTypes: []string{"mysql", "mariadb"},
+		build:        buildMySQL,
+	},
+	{
+		Name:         "redis-cli",
+		Description:  "Redis",
+		ServiceTypes: []string{"redis"},
+		build:        buildRedisCLI,
+	},
+}
+
+// Supports reports whether the client can connect to a service type
+func (c *Client) Supports(serviceType string) bool {
+	for _, t := range c.ServiceTypes {
+		if t == serviceType {
+			return true
+		}
+	}
+	return false
+}
+
+// Command returns the command line connecting the client to an instance of
+// serviceType with the given environment. args are passed on to the client.
+func (c *Client) Command(serviceType string, env map[string]string, args []string) *Command {
+	return c.build(serviceType, env, args)
+}
+
+func buildPsql(serviceType string, env map[string]string, args []string) *Command {
+	user := firstSet(env, "POSTGRES_USER")
+	if user == "" {
+		user = "postgres"
+	}
+	database := firstSet(env, "POSTGRES_DB")
+	if database == "" {
+		database = user
+	}
+
+	cmd := &Command{Args: append([]string{"psql", "-U", user, "-d", database}, args...)}
+	if password := firstSet(env, "POSTGRES_PASSWORD"); password != "" {
+		cmd.Env = append(cmd.Env, "PGPASSWORD="+password)
+	}
+	return cmd
+}
+
+func buildMySQL(serviceType string, env map[string]string, args []string) *Command {
+	// MariaDB 11 images no longer ship the mysql binary
+	binary := "mysql"
+	if serviceType == "mariadb" {
+		binary = "mariadb"
+	}
+
+	// Prefer the application user, falling back to root
+	user := firstSet(env, "MYSQL_USER", "MARIADB_USER")
+	password := firstSet(env, "MYSQL_PASSWORD", "MARIADB_PASSWORD")
+	if user == "" || password == "" {
+		user = "root"
+		password = firstSet(env, "MYSQL_ROOT_PASSWORD", "MARIADB_ROOT_PASSWORD")
+	}
+
+	cmd := &Command{Args: []string{binary, "-u", user}}
+	if database := firstSet(env, "MYSQL_DATABASE", "MARIADB_DATABASE"); database != "" {
+		cmd.Args = append(cmd.Args, database)
+	}
+	cmd.Args = append(cmd.Args, args...)
+	if password != "" {
+		cmd.Env = append(cmd.Env, "MYSQL_PWD="+password)
+	}
+	return cmd
+}
+
+func buildRedisCLI(serviceType string, env map[string]string, args []string) *Command {
+	cmd := &Command{Args: append([]string{"redis-cli"}, args...)}
+	if password := firstSet(env, "REDIS_PASSWORD"); password != "" {
+		cmd.Env = append(cmd.Env, "REDISCLI_AUTH="+password)
+	}
+	return cmd
+}
+
+// firstSet returns the value of the first key that is set in env
+func firstSet(env map[string]string, keys ...string) string {
+	for _, key := range keys {
+		if value := env[key]; value != "" {
+			return value
+		}
+	}
+	return ""
+}
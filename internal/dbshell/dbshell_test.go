@@ -0,0 +1,115 @@
+package dbshell
+
+import (
+	"reflect"
+	"testing"
+)
+
+func client(t *testing.T, name string) *Client {
+	t.Helper()
+	for _, c := range Clients {
+		if c.Name == name {
+			return c
+		}
+	}
+	t.Fatalf("client %s not found", name)
+	return nil
+}
+
+func TestPsqlCommand(t *testing.T) {
+	psql := client(t, "psql")
+
+	cmd := psql.Command("postgres", map[string]string{
+		"POSTGRES_USER":     "app",
+		"POSTGRES_PASSWORD": "secret",
+		"POSTGRES_DB":       "appdb",
+	}, []string{"-c", "select 1"})
+
+	expectedArgs := []string{"psql", "-U", "app", "-d", "appdb", "-c", "select 1"}
+	if !reflect.DeepEqual(cmd.Args, expectedArgs) {
+		t.Errorf("Args = %v, expected %v", cmd.Args, expectedArgs)
+	}
+	if !reflect.DeepEqual(cmd.Env, []string{"PGPASSWORD=secret"}) {
+		t.Errorf("Env = %v, expected [PGPASSWORD=secret]", cmd.Env)
+	}
+
+	// Defaults of the official image
+	cmd = psql.Command("postgres", nil, nil)
+	if !reflect.DeepEqual(cmd.Args, []string{"psql", "-U", "postgres", "-d", "postgres"}) {
+		t.Errorf("default Args = %v", cmd.Args)
+	}
+	if len(cmd.Env) != 0 {
+		t.Errorf("default Env = %v, expected none", cmd.Env)
+	}
+}
+
+func TestMySQLCommand(t *testing.T) {
+	mysql := client(t, "mysql")
+
+	tests := []struct {
+		name         string
+		serviceType  string
+		env          map[string]string
+		expectedArgs []string
+		expectedEnv  []string
+	}{
+		{
+			name:         "root",
+			serviceType:  "mysql",
+			env:          map[string]string{"MYSQL_ROOT_PASSWORD": "rootpw", "MYSQL_DATABASE": "shop"},
+			expectedArgs: []string{"mysql", "-u", "root", "shop"},
+			expectedEnv:  []string{"MYSQL_PWD=rootpw"},
+		},
+		{
+			name:        "application user",
+			serviceType: "mysql",
+			env: map[string]string{
+				"MYSQL_ROOT_PASSWORD": "rootpw",
+				"MYSQL_USER":          "shop",
+				"MYSQL_PASSWORD":      "shoppw",
+			},
+			expectedArgs: []string{"mysql", "-u", "shop"},
+			expectedEnv:  []string{"MYSQL_PWD=shoppw"},
+		},
+		{
+			name:         "mariadb",
+			serviceType:  "mariadb",
+			env:          map[string]string{"MARIADB_ROOT_PASSWORD": "rootpw"},
+			expectedArgs: []string{"mariadb", "-u", "root"},
+			expectedEnv:  []string{"MYSQL_PWD=rootpw"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd := mysql.Command(tt.serviceType, tt.env, nil)
+			if !reflect.DeepEqual(cmd.Args, tt.expectedArgs) {
+				t.Errorf("Args = %v, expected %v", cmd.Args, tt.expectedArgs)
+			}
+			if !reflect.DeepEqual(cmd.Env, tt.expectedEnv) {
+				t.Errorf("Env = %v, expected %v", cmd.Env, tt.expectedEnv)
+			}
+		})
+	}
+}
+
+func TestRedisCLICommand(t *testing.T) {
+	redis := client(t, "redis-cli")
+
+	cmd := redis.Command("redis", map[string]string{"REDIS_PASSWORD": "secret"}, []string{"ping"})
+	if !reflect.DeepEqual(cmd.Args, []string{"redis-cli", "ping"}) {
+		t.Errorf("Args = %v", cmd.Args)
+	}
+	if !reflect.DeepEqual(cmd.Env, []string{"REDISCLI_AUTH=secret"}) {
+		t.Errorf("Env = %v", cmd.Env)
+	}
+}
+
+func TestSupports(t *testing.T) {
+	if !client(t, "mysql").Supports("mariadb") {
+		t.Error("mysql should support mariadb")
+	}
+	if client(t, "psql").Supports("redis") {
+		t.Error("psql should not support redis")
+	}
+}
@@ -5,6 +5,7 @@ import (
 
 	"github.com/dokulabs/doku-cli/internal/catalog"
 	"github.com/dokulabs/doku-cli/internal/config"
+	"github.com/dokulabs/doku-cli/pkg/types"
 )
 
 // Resolver handles dependency resolution and installation order calculation
@@ -35,6 +36,7 @@ type DependencyNode struct {
 	Required    bool              // Is this dependency required
 	Environment map[string]string // Environment variable overrides
 	IsInstalled bool              // Whether already installed
+	Instance    string            // Installed instance providing the service, if any
 	Depth       int               // Depth in dependency tree (0 = root)
 }
 
@@ -46,8 +48,14 @@ func (r *Resolver) Resolve(serviceName, version string) (*ResolutionResult, erro
 		version = "latest"
 	}
 
+	// Resolve a version range to the newest matching catalog version
+	version, err := r.catalogMgr.ResolveVersion(serviceName, version)
+	if err != nil {
+		return nil, err
+	}
+
 	// Get service spec to check if it exists
-	_, err := r.catalogMgr.GetServiceVersion(serviceName, version)
+	_, err = r.catalogMgr.GetServiceVersion(serviceName, version)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get service spec for %s: %w", serviceName, err)
 	}
@@ -96,6 +104,11 @@ func (r *Resolver) buildDependencyGraph(
 		if depth < existing.Depth {
 			existing.Depth = depth
 		}
+		if catalog.IsConstraint(version) {
+			if constraint, err := catalog.ParseConstraint(version); err == nil && !constraint.Matches(existing.Version) {
+				return fmt.Errorf("conflicting versions of %s: %s doesn't satisfy %s", serviceName, existing.Version, version)
+			}
+		}
 		return nil
 	}
 
@@ -103,21 +116,26 @@ func (r *Resolver) buildDependencyGraph(
 	visiting[serviceName] = true
 	defer delete(visiting, serviceName)
 
+	// Resolve a version range to an installed instance satisfying it, or to
+	// the newest matching catalog version
+	instanceName, version, err := r.resolveVersion(serviceName, version)
+	if err != nil {
+		return err
+	}
+
 	// Get service spec
 	spec, err := r.catalogMgr.GetServiceVersion(serviceName, version)
 	if err != nil {
 		return fmt.Errorf("failed to get spec for %s@%s: %w", serviceName, version, err)
 	}
 
-	// Check if already installed
-	isInstalled := r.configMgr.HasInstance(serviceName)
-
 	// Create node
 	node := &DependencyNode{
 		ServiceName: serviceName,
 		Version:     version,
 		Required:    true,
-		IsInstalled: isInstalled,
+		IsInstalled: instanceName != "",
+		Instance:    instanceName,
 		Depth:       depth,
 	}
 	nodes[serviceName] = node
@@ -163,6 +181,54 @@ func (r *Resolver) buildDependencyGraph(
 	return nil
 }
 
+// resolveVersion returns the installed instance providing a service at a
+// version, if any, and the version to use. Instances are found by the
+// service's name; for version ranges, any instance of the service whose
+// version satisfies the range is reused, the newest first.
+func (r *Resolver) resolveVersion(serviceName, version string) (string, string, error) {
+	if !catalog.IsConstraint(version) {
+		if r.configMgr.HasInstance(serviceName) {
+			return serviceName, version, nil
+		}
+		return "", version, nil
+	}
+
+	constraint, err := catalog.ParseConstraint(version)
+	if err != nil {
+		return "", "", fmt.Errorf("dependency %s: %w", serviceName, err)
+	}
+
+	instances, err := r.configMgr.ListInstances()
+	if err != nil {
+		return "", "", err
+	}
+	var reuse *types.Instance
+	for _, instance := range instances {
+		if instance.ServiceType != serviceName || !constraint.Matches(instance.Version) {
+			continue
+		}
+		if reuse == nil || catalog.CompareVersions(instance.Version, reuse.Version) > 0 ||
+			(instance.Version == reuse.Version && instance.Name < reuse.Name) {
+			reuse = instance
+		}
+	}
+	if reuse != nil {
+		return reuse.Name, reuse.Version, nil
+	}
+
+	// A new instance is named after the service, so one of another version
+	// would be in the way
+	if existing, err := r.configMgr.GetInstance(serviceName); err == nil {
+		return "", "", fmt.Errorf("%s is installed at version %s, which doesn't satisfy %s", serviceName, existing.Version, version)
+	}
+
+	resolved, err := r.catalogMgr.ResolveVersion(serviceName, version)
+	if err != nil {
+		return "", "", err
+	}
+	return "", resolved, nil
+}
+
 // topologicalSort performs topological sort using DFS
 // Returns nodes in the order they should be installed (dependencies first)
 func (r *Resolver) topologicalSort(
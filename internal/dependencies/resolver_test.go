@@ -341,6 +341,95 @@ func TestResolveWithInstalledDependency(t *testing.T) {
 	}
 }
 
+// createRangedServices adds a service with several versions and one that
+// depends on a range of them
+func createRangedServices(t *testing.T, tmpDir string) {
+	servicesDir := filepath.Join(tmpDir, "catalog", "services")
+	for _, version := range []string{"15", "16", "17"} {
+		createMockService(t, servicesDir, "database", "pg", version, `
+image: pg:`+version+`
+port: 5432
+`)
+	}
+	createMockService(t, servicesDir, "app", "app", "latest", `
+image: app:latest
+port: 8080
+dependencies_v2:
+  - name: pg
+    version: ">=15 <17"
+    required: true
+`)
+}
+
+// TestResolveVersionRange tests that a range resolves to the newest matching version
+func TestResolveVersionRange(t *testing.T) {
+	resolver, tmpDir, cleanup := setupTestEnvironment(t)
+	defer cleanup()
+	createRangedServices(t, tmpDir)
+
+	result, err := resolver.Resolve("app", "latest")
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	pg := result.AllNodes["pg"]
+	if pg == nil {
+		t.Fatal("Expected pg in the graph")
+	}
+	if pg.Version != "16" {
+		t.Errorf("Expected pg version 16, got %s", pg.Version)
+	}
+	if pg.IsInstalled {
+		t.Error("Expected pg to not be marked as installed")
+	}
+}
+
+// TestResolveVersionRangeReusesInstance tests that an installed instance
+// satisfying a range is reused, whatever its name
+func TestResolveVersionRangeReusesInstance(t *testing.T) {
+	resolver, tmpDir, cleanup := setupTestEnvironment(t)
+	defer cleanup()
+	createRangedServices(t, tmpDir)
+
+	for _, instance := range []*types.Instance{
+		{Name: "pg-15", ServiceType: "pg", Version: "15"},
+		{Name: "pg-17", ServiceType: "pg", Version: "17"},
+	} {
+		if err := resolver.configMgr.AddInstance(instance); err != nil {
+			t.Fatalf("Failed to add instance: %v", err)
+		}
+	}
+
+	result, err := resolver.Resolve("app", "latest")
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	pg := result.AllNodes["pg"]
+	if !pg.IsInstalled || pg.Instance != "pg-15" || pg.Version != "15" {
+		t.Errorf("Expected pg-15 (15) to be reused, got %+v", *pg)
+	}
+	if missing := resolver.GetMissingDependencies(result); len(missing) != 1 || missing[0].ServiceName != "app" {
+		t.Errorf("Expected only app to be missing, got: %v", missing)
+	}
+}
+
+// TestResolveVersionRangeConflict tests that an instance named after the
+// service at a version outside the range is reported
+func TestResolveVersionRangeConflict(t *testing.T) {
+	resolver, tmpDir, cleanup := setupTestEnvironment(t)
+	defer cleanup()
+	createRangedServices(t, tmpDir)
+
+	if err := resolver.configMgr.AddInstance(&types.Instance{Name: "pg", ServiceType: "pg", Version: "17"}); err != nil {
+		t.Fatalf("Failed to add instance: %v", err)
+	}
+
+	if _, err := resolver.Resolve("app", "latest"); err == nil || !contains(err.Error(), "doesn't satisfy") {
+		t.Errorf("Expected a version conflict, got: %v", err)
+	}
+}
+
 // TestGetDependencyTree tests dependency tree generation
 func TestGetDependencyTree(t *testing.T) {
 	resolver, _, cleanup := setupTestEnvironment(t)
@@ -0,0 +1,34 @@
+package dns
+
+import (
+	"encoding/base64"
+	"fmt"
+	"strings"
+	"unicode/utf16"
+)
+
+// psQuote quotes a string as a PowerShell literal, in which only single
+// quotes need escaping
+func psQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+}
+
+// encodePowerShell encodes a script for powershell -EncodedCommand, which
+// takes base64 of UTF-16LE and spares quoting it inside another command
+func encodePowerShell(script string) string {
+	units := utf16.Encode([]rune(script))
+	buf := make([]byte, 0, len(units)*2)
+	for _, u := range units {
+		buf = append(buf, byte(u), byte(u>>8))
+	}
+	return base64.StdEncoding.EncodeToString(buf)
+}
+
+// elevatedCopyScript returns the PowerShell script copying src over dest
+// from an elevated PowerShell, and exiting with its exit code
+func elevatedCopyScript(src, dest string) string {
+	copyScript := fmt.Sprintf("Copy-Item -LiteralPath %s -Destination %s -Force -ErrorAction Stop", psQuote(src), psQuote(dest))
+	return "$p = Start-Process -FilePath powershell -Verb RunAs -Wait -PassThru -WindowStyle Hidden " +
+		"-ArgumentList '-NoProfile','-NonInteractive','-EncodedCommand','" + encodePowerShell(copyScript) + "'; " +
+		"exit $p.ExitCode"
+}
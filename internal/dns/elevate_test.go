@@ -0,0 +1,40 @@
+package dns
+
+import (
+	"encoding/base64"
+	"strings"
+	"testing"
+)
+
+// TestPSQuote tests quoting PowerShell literals
+func TestPSQuote(t *testing.T) {
+	if got := psQuote(`C:\Users\O'Brien\hosts`); got != `'C:\Users\O''Brien\hosts'` {
+		t.Errorf("psQuote() = %s", got)
+	}
+}
+
+// TestEncodePowerShell tests encoding scripts as UTF-16LE base64
+func TestEncodePowerShell(t *testing.T) {
+	decoded, err := base64.StdEncoding.DecodeString(encodePowerShell("dir"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(decoded) != "d\x00i\x00r\x00" {
+		t.Errorf("encodePowerShell(dir) decodes to %q", decoded)
+	}
+}
+
+// TestElevatedCopyScript tests the script copying the hosts file from an
+// elevated PowerShell
+func TestElevatedCopyScript(t *testing.T) {
+	script := elevatedCopyScript(`C:\Temp\doku-hosts-1`, `C:\Windows\System32\drivers\etc\hosts`)
+	for _, want := range []string{"-Verb RunAs", "-Wait", "exit $p.ExitCode"} {
+		if !strings.Contains(script, want) {
+			t.Errorf("script lacks %q: %s", want, script)
+		}
+	}
+	copyScript := "Copy-Item -LiteralPath 'C:\\Temp\\doku-hosts-1' -Destination 'C:\\Windows\\System32\\drivers\\etc\\hosts' -Force -ErrorAction Stop"
+	if !strings.Contains(script, encodePowerShell(copyScript)) {
+		t.Errorf("script doesn't run the encoded copy: %s", script)
+	}
+}
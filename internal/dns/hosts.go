@@ -5,8 +5,10 @@ import (
 	"fmt"
 	"os"
 	"os/exec"
-	"runtime"
+	"slices"
 	"strings"
+
+	"github.com/dokulabs/doku-cli/internal/readonly"
 )
 
 const (
@@ -15,25 +17,96 @@ const (
 	DokuEnd    = "# doku-managed-end"
 )
 
-// Manager handles DNS and hosts file management
+// Manager handles DNS and hosts file management. Each manager owns the
+// managed section of one context, so several Doku setups can share a hosts
+// file without touching each other's entries.
 type Manager struct {
 	hostsFile string
+	context   string
+	ip        string // "" for 127.0.0.1
 }
 
-// NewManager creates a new DNS manager
+// NewManager creates a new DNS manager for the default (unnamed) context
 func NewManager() *Manager {
-	hostsFile := getHostsFilePath()
+	return NewManagerForContext("")
+}
+
+// NewManagerForContext creates a DNS manager whose hosts file entries are
+// marked with the context name, e.g. "# doku-managed-start [work]"
+func NewManagerForContext(context string) *Manager {
 	return &Manager{
-		hostsFile: hostsFile,
+		hostsFile: getHostsFilePath(),
+		context:   context,
+	}
+}
+
+// SetIP sets the address the entries added from now on resolve to, e.g. a
+// loopback alias of the workspace; "" keeps 127.0.0.1
+func (m *Manager) SetIP(ip string) *Manager {
+	m.ip = ip
+	return m
+}
+
+// IP returns the address the manager's entries resolve to
+func (m *Manager) IP() string {
+	if m.ip == "" {
+		return "127.0.0.1"
+	}
+	return m.ip
+}
+
+// ValidateContext checks that a context name can be used in hosts file markers
+func ValidateContext(context string) error {
+	for _, c := range context {
+		if !(c == '-' || c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9')) {
+			return fmt.Errorf("invalid character '%c' in context '%s': use letters, digits, '-' and '_'", c, context)
+		}
+	}
+	return nil
+}
+
+// Context returns the context whose entries the manager handles
+func (m *Manager) Context() string {
+	return m.context
+}
+
+// startMarker returns the line that opens the context's managed section
+func (m *Manager) startMarker() string {
+	if m.context == "" {
+		return DokuStart
 	}
+	return fmt.Sprintf("%s [%s]", DokuStart, m.context)
 }
 
-// getHostsFilePath returns the hosts file path based on OS
-func getHostsFilePath() string {
-	if runtime.GOOS == "windows" {
-		return "C:\\Windows\\System32\\drivers\\etc\\hosts"
+// endMarker returns the line that closes the context's managed section
+func (m *Manager) endMarker() string {
+	if m.context == "" {
+		return DokuEnd
 	}
-	return "/etc/hosts"
+	return fmt.Sprintf("%s [%s]", DokuEnd, m.context)
+}
+
+// entryMarker returns the comment appended to each entry of the context
+func (m *Manager) entryMarker() string {
+	if m.context == "" {
+		return DokuMarker
+	}
+	return fmt.Sprintf("# doku-managed [%s] - do not edit", m.context)
+}
+
+// isStart and isEnd compare whole lines, since the unnamed markers are a
+// prefix of the named ones
+func (m *Manager) isStart(line string) bool {
+	return strings.TrimSpace(line) == m.startMarker()
+}
+
+func (m *Manager) isEnd(line string) bool {
+	return strings.TrimSpace(line) == m.endMarker()
+}
+
+// ownsEntry reports whether a line is an entry of the manager's context
+func (m *Manager) ownsEntry(line string) bool {
+	return strings.Contains(line, m.entryMarker())
 }
 
 // AddDokuDomain adds Doku domain entries to the hosts file
@@ -91,15 +164,15 @@ func (m *Manager) RemoveDokuEntries() error {
 	inDokuSection := false
 
 	for _, line := range lines {
-		if strings.Contains(line, DokuStart) {
+		if m.isStart(line) {
 			inDokuSection = true
 			continue
 		}
-		if strings.Contains(line, DokuEnd) {
+		if m.isEnd(line) {
 			inDokuSection = false
 			continue
 		}
-		if !inDokuSection && !strings.Contains(line, DokuMarker) {
+		if !inDokuSection && !m.ownsEntry(line) {
 			newLines = append(newLines, line)
 		}
 	}
@@ -126,8 +199,15 @@ func (m *Manager) HasDokuEntries() (bool, error) {
 		return false, fmt.Errorf("failed to read hosts file: %w", err)
 	}
 
-	return strings.Contains(string(content), DokuMarker) ||
-		strings.Contains(string(content), DokuStart), nil
+	if strings.Contains(string(content), m.entryMarker()) {
+		return true, nil
+	}
+	for _, line := range strings.Split(string(content), "\n") {
+		if m.isStart(line) {
+			return true, nil
+		}
+	}
+	return false, nil
 }
 
 // GetDokuDomain returns the currently configured Doku domain from hosts file
@@ -141,11 +221,11 @@ func (m *Manager) GetDokuDomain() (string, error) {
 	inDokuSection := false
 
 	for _, line := range lines {
-		if strings.Contains(line, DokuStart) {
+		if m.isStart(line) {
 			inDokuSection = true
 			continue
 		}
-		if strings.Contains(line, DokuEnd) {
+		if m.isEnd(line) {
 			break
 		}
 
@@ -168,31 +248,114 @@ func (m *Manager) GetDokuDomain() (string, error) {
 
 // generateHostsEntries generates hosts file entries for the domain
 func (m *Manager) generateHostsEntries(domain string) string {
-	entries := fmt.Sprintf("%s\n", DokuStart)
-	entries += fmt.Sprintf("127.0.0.1 %s %s\n", domain, DokuMarker)
-	entries += fmt.Sprintf("%s\n", DokuEnd)
+	return m.generateSection([]string{domain})
+}
+
+// generateSection generates the context's section with an entry for each
+// hostname
+func (m *Manager) generateSection(hostnames []string) string {
+	entries := fmt.Sprintf("%s\n", m.startMarker())
+	for _, hostname := range hostnames {
+		entries += fmt.Sprintf("%s %s %s\n", m.IP(), hostname, m.entryMarker())
+	}
+	entries += fmt.Sprintf("%s\n", m.endMarker())
 	return entries
 }
 
-// AddServiceDomain adds a single service DNS entry to the hosts file
-// For example: rabbitmq.doku.local -> 127.0.0.1
-func (m *Manager) AddServiceDomain(serviceName, baseDomain string) error {
-	subdomain := fmt.Sprintf("%s.%s", serviceName, baseDomain)
+// Entry is a hostname in the hosts file and the IP it resolves to
+type Entry struct {
+	IP       string
+	Hostname string
+}
 
-	// Check if entry already exists
+// Entries returns the manager's entries, those of its section and the
+// standalone ones, in the order of the file
+func (m *Manager) Entries() ([]Entry, error) {
+	content, err := os.ReadFile(m.hostsFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read hosts file: %w", err)
+	}
+
+	var entries []Entry
+	inDokuSection := false
+	for _, line := range strings.Split(string(content), "\n") {
+		switch {
+		case m.isStart(line):
+			inDokuSection = true
+			continue
+		case m.isEnd(line):
+			inDokuSection = false
+			continue
+		case !inDokuSection && !m.ownsEntry(line):
+			continue
+		}
+
+		line, _, _ = strings.Cut(line, "#")
+		fields := strings.Fields(line)
+		if len(fields) < 2 {
+			continue
+		}
+		for _, hostname := range fields[1:] {
+			entries = append(entries, Entry{IP: fields[0], Hostname: hostname})
+		}
+	}
+	return entries, nil
+}
+
+// SetHostnames replaces the manager's entries with a section holding one
+// for each hostname, the first being the Doku domain. The file is written
+// once.
+func (m *Manager) SetHostnames(hostnames []string) error {
 	content, err := os.ReadFile(m.hostsFile)
 	if err != nil {
 		return fmt.Errorf("failed to read hosts file: %w", err)
 	}
 
-	// Check if this subdomain already exists (in doku-managed section or as standalone)
-	scanner := bufio.NewScanner(strings.NewReader(string(content)))
-	for scanner.Scan() {
-		line := scanner.Text()
-		if strings.Contains(line, DokuMarker) && strings.Contains(line, subdomain) {
-			// Entry already exists
-			return nil
+	var kept []string
+	inDokuSection := false
+	for _, line := range strings.Split(strings.ReplaceAll(string(content), "\r\n", "\n"), "\n") {
+		if m.isStart(line) {
+			inDokuSection = true
+			continue
+		}
+		if m.isEnd(line) {
+			inDokuSection = false
+			continue
 		}
+		if !inDokuSection && !m.ownsEntry(line) {
+			kept = append(kept, line)
+		}
+	}
+
+	updatedContent := strings.TrimRight(strings.Join(kept, "\n"), "\n")
+	if updatedContent != "" {
+		updatedContent += "\n\n"
+	}
+	updatedContent += m.generateSection(hostnames)
+	return m.writeHostsFile(updatedContent)
+}
+
+// AddServiceDomain adds a single service DNS entry to the hosts file
+// For example: rabbitmq.doku.local -> 127.0.0.1, or the manager's IP
+func (m *Manager) AddServiceDomain(serviceName, baseDomain string) error {
+	return m.AddHostname(fmt.Sprintf("%s.%s", serviceName, baseDomain))
+}
+
+// AddHostname adds an entry for a hostname to the Doku section of the hosts
+// file, e.g. api.myproject.test -> 127.0.0.1
+func (m *Manager) AddHostname(subdomain string) error {
+	// Check if this subdomain already exists (in doku-managed section or as standalone)
+	exists, err := m.HasHostname(subdomain)
+	if err != nil {
+		return err
+	}
+	if exists {
+		return nil
+	}
+
+	content, err := os.ReadFile(m.hostsFile)
+	if err != nil {
+		return fmt.Errorf("failed to read hosts file: %w", err)
 	}
 
 	// Add new entry inside the doku-managed section
@@ -204,10 +367,10 @@ func (m *Manager) AddServiceDomain(serviceName, baseDomain string) error {
 		newLines = append(newLines, line)
 
 		// Add the new service entry just before the DokuEnd marker
-		if !added && strings.Contains(line, DokuEnd) {
+		if !added && m.isEnd(line) {
 			// Insert before the DokuEnd line
 			newLines = newLines[:len(newLines)-1] // Remove the DokuEnd we just added
-			newLines = append(newLines, fmt.Sprintf("127.0.0.1 %s %s", subdomain, DokuMarker))
+			newLines = append(newLines, fmt.Sprintf("%s %s %s", m.IP(), subdomain, m.entryMarker()))
 			newLines = append(newLines, line) // Add back the DokuEnd
 			added = true
 		}
@@ -215,15 +378,49 @@ func (m *Manager) AddServiceDomain(serviceName, baseDomain string) error {
 
 	// If DokuEnd marker doesn't exist (no doku-managed section), add as standalone entry
 	if !added {
-		newLines = append(newLines, fmt.Sprintf("127.0.0.1 %s %s", subdomain, DokuMarker))
+		newLines = append(newLines, fmt.Sprintf("%s %s %s", m.IP(), subdomain, m.entryMarker()))
 	}
 
 	updatedContent := strings.Join(newLines, "\n")
 	return m.writeHostsFile(updatedContent)
 }
 
-// writeHostsFile writes content to the hosts file (requires sudo on Unix)
+// HasServiceDomain reports whether the hosts file has the manager's entry
+// for a service's subdomain, e.g. postgres.doku.local
+func (m *Manager) HasServiceDomain(serviceName, baseDomain string) (bool, error) {
+	return m.HasHostname(fmt.Sprintf("%s.%s", serviceName, baseDomain))
+}
+
+// HasHostname reports whether the hosts file has the manager's entry for a
+// hostname
+func (m *Manager) HasHostname(subdomain string) (bool, error) {
+	content, err := os.ReadFile(m.hostsFile)
+	if err != nil {
+		return false, fmt.Errorf("failed to read hosts file: %w", err)
+	}
+
+	for _, line := range strings.Split(string(content), "\n") {
+		if !m.ownsEntry(line) {
+			continue
+		}
+		for _, field := range strings.Fields(line) {
+			if field == subdomain {
+				return true, nil
+			}
+		}
+	}
+	return false, nil
+}
+
+// writeHostsFile writes content to the hosts file, as administrator if
+// needed (sudo on Unix, a UAC prompt on Windows). The file keeps its line
+// endings: Windows' uses CRLF.
 func (m *Manager) writeHostsFile(content string) error {
+	if err := readonly.Check("update " + m.hostsFile); err != nil {
+		return err
+	}
+	content = withLineEndings(content, m.lineEnding())
+
 	// Create temporary file
 	tmpFile, err := os.CreateTemp("", "doku-hosts-*")
 	if err != nil {
@@ -238,19 +435,14 @@ func (m *Manager) writeHostsFile(content string) error {
 	}
 	tmpFile.Close()
 
-	// Copy temp file to hosts file (may require sudo)
-	if runtime.GOOS == "windows" {
-		// On Windows, try direct write
-		return os.WriteFile(m.hostsFile, []byte(content), 0644)
-	}
-
-	// On Unix, use sudo to copy
-	return m.copyWithSudo(tmpFile.Name(), m.hostsFile)
+	// Copy temp file to hosts file (may require administrator privileges)
+	return m.copyPrivileged(tmpFile.Name(), m.hostsFile)
 }
 
-// copyWithSudo copies a file using sudo (Unix only)
-func (m *Manager) copyWithSudo(src, dest string) error {
-	// Try without sudo first
+// copyPrivileged copies a file, as administrator if the user can't write
+// the destination
+func (m *Manager) copyPrivileged(src, dest string) error {
+	// Try without elevation first
 	srcContent, err := os.ReadFile(src)
 	if err != nil {
 		return err
@@ -261,22 +453,42 @@ func (m *Manager) copyWithSudo(src, dest string) error {
 		return nil
 	}
 
-	// If that fails, we need sudo
 	fmt.Println()
-	fmt.Println("⚠️  Updating /etc/hosts requires administrator privileges")
-	fmt.Println("📝 Please enter your password when prompted...")
+	fmt.Printf("⚠️  Updating %s requires administrator privileges\n", dest)
+	fmt.Println(elevationPrompt)
 	fmt.Println()
 
-	// Execute sudo cp with proper argument separation (not string parsing)
-	// This is safe from shell injection as arguments are passed directly
-	if err := executeCommandArgs("sudo", "cp", src, dest); err != nil {
-		return fmt.Errorf("failed to update hosts file with sudo: %w", err)
+	if err := elevatedCopy(src, dest); err != nil {
+		return fmt.Errorf("failed to update hosts file as administrator: %w", err)
 	}
 
 	fmt.Println("✓ Hosts file updated successfully")
 	return nil
 }
 
+// lineEnding returns the line ending the hosts file uses, the platform's
+// if it has no lines yet
+func (m *Manager) lineEnding() string {
+	content, err := os.ReadFile(m.hostsFile)
+	switch {
+	case err != nil || !strings.Contains(string(content), "\n"):
+		return defaultLineEnding
+	case strings.Contains(string(content), "\r\n"):
+		return "\r\n"
+	default:
+		return "\n"
+	}
+}
+
+// withLineEndings returns content with every line ending made eol
+func withLineEndings(content, eol string) string {
+	content = strings.ReplaceAll(content, "\r\n", "\n")
+	if eol == "\n" {
+		return content
+	}
+	return strings.ReplaceAll(content, "\n", eol)
+}
+
 // VerifyDNSResolution verifies that DNS resolution works for the domain
 func (m *Manager) VerifyDNSResolution(domain string) error {
 	// This is a basic check - in a real implementation,
@@ -296,6 +508,10 @@ func (m *Manager) VerifyDNSResolution(domain string) error {
 
 // BackupHostsFile creates a backup of the hosts file
 func (m *Manager) BackupHostsFile() (string, error) {
+	if err := readonly.Check("back up " + m.hostsFile); err != nil {
+		return "", err
+	}
+
 	content, err := os.ReadFile(m.hostsFile)
 	if err != nil {
 		return "", fmt.Errorf("failed to read hosts file: %w", err)
@@ -331,11 +547,11 @@ func (m *Manager) ListDokuEntries() ([]string, error) {
 	inDokuSection := false
 
 	for _, line := range lines {
-		if strings.Contains(line, DokuStart) {
+		if m.isStart(line) {
 			inDokuSection = true
 			continue
 		}
-		if strings.Contains(line, DokuEnd) {
+		if m.isEnd(line) {
 			break
 		}
 
@@ -433,7 +649,7 @@ func (m *Manager) AddSingleEntry(ip, hostname string) error {
 	}
 
 	// Add new entry
-	entry := fmt.Sprintf("\n%s %s %s\n", ip, hostname, DokuMarker)
+	entry := fmt.Sprintf("\n%s %s %s\n", ip, hostname, m.entryMarker())
 	updatedContent := string(content) + entry
 
 	return m.writeHostsFile(updatedContent)
@@ -450,7 +666,7 @@ func (m *Manager) RemoveSingleEntry(hostname string) error {
 	var newLines []string
 
 	for _, line := range lines {
-		if !strings.Contains(line, hostname) || !strings.Contains(line, DokuMarker) {
+		if !m.ownsEntry(line) || !slices.Contains(strings.Fields(line), hostname) {
 			newLines = append(newLines, line)
 		}
 	}
@@ -458,3 +674,71 @@ func (m *Manager) RemoveSingleEntry(hostname string) error {
 	updatedContent := strings.Join(newLines, "\n")
 	return m.writeHostsFile(updatedContent)
 }
+
+// RenameContext moves the manager's entries to another context by rewriting
+// their markers. Entries of other contexts are left alone.
+func (m *Manager) RenameContext(context string) error {
+	if err := ValidateContext(context); err != nil {
+		return err
+	}
+	if context == m.context {
+		return nil
+	}
+
+	content, err := os.ReadFile(m.hostsFile)
+	if err != nil {
+		return fmt.Errorf("failed to read hosts file: %w", err)
+	}
+
+	target := &Manager{hostsFile: m.hostsFile, context: context}
+	lines := strings.Split(string(content), "\n")
+	changed := false
+
+	for idx, line := range lines {
+		switch {
+		case m.isStart(line):
+			lines[idx] = target.startMarker()
+		case m.isEnd(line):
+			lines[idx] = target.endMarker()
+		case m.ownsEntry(line):
+			lines[idx] = strings.Replace(line, m.entryMarker(), target.entryMarker(), 1)
+		default:
+			continue
+		}
+		changed = true
+	}
+
+	m.context = context
+	if !changed {
+		return nil
+	}
+	return m.writeHostsFile(strings.Join(lines, "\n"))
+}
+
+// Readdress points the manager's entries at its IP, e.g. after the bind IP
+// changed. Entries of other contexts are left alone.
+func (m *Manager) Readdress() error {
+	content, err := os.ReadFile(m.hostsFile)
+	if err != nil {
+		return fmt.Errorf("failed to read hosts file: %w", err)
+	}
+
+	lines := strings.Split(string(content), "\n")
+	changed := false
+	for idx, line := range lines {
+		if !m.ownsEntry(line) {
+			continue
+		}
+		fields := strings.Fields(line)
+		if len(fields) < 2 || fields[0] == m.IP() {
+			continue
+		}
+		lines[idx] = strings.Replace(line, fields[0], m.IP(), 1)
+		changed = true
+	}
+
+	if !changed {
+		return nil
+	}
+	return m.writeHostsFile(strings.Join(lines, "\n"))
+}
@@ -306,6 +306,41 @@ func TestAddServiceDomainIdempotent(t *testing.T) {
 	}
 }
 
+// TestHasServiceDomain tests looking up a service's entry
+func TestHasServiceDomain(t *testing.T) {
+	initialContent := `127.0.0.1 localhost
+127.0.0.1 grafana.doku.local
+# doku-managed-start
+127.0.0.1 doku.local # doku-managed - do not edit
+127.0.0.1 myredis.doku.local # doku-managed - do not edit
+# doku-managed-end
+`
+	manager, _, cleanup := createTestManager(t, initialContent)
+	defer cleanup()
+
+	tests := map[string]bool{
+		"myredis": true,
+		"redis":   false, // Only a longer name matches
+		"grafana": false, // Not managed by Doku
+	}
+	for service, expected := range tests {
+		got, err := manager.HasServiceDomain(service, "doku.local")
+		if err != nil {
+			t.Fatalf("HasServiceDomain failed: %v", err)
+		}
+		if got != expected {
+			t.Errorf("HasServiceDomain(%q) = %v, expected %v", service, got, expected)
+		}
+	}
+
+	if err := manager.AddServiceDomain("redis", "doku.local"); err != nil {
+		t.Fatalf("AddServiceDomain failed: %v", err)
+	}
+	if got, _ := manager.HasServiceDomain("redis", "doku.local"); !got {
+		t.Error("HasServiceDomain should find the added entry")
+	}
+}
+
 // TestListDokuEntries tests listing Doku entries
 func TestListDokuEntries(t *testing.T) {
 	initialContent := `127.0.0.1 localhost
@@ -557,3 +592,380 @@ func TestConstants(t *testing.T) {
 		t.Error("DokuEnd should not be empty")
 	}
 }
+
+// sharedHostsContent has the sections of two contexts plus a standalone entry of each
+const sharedHostsContent = `127.0.0.1 localhost
+# doku-managed-start
+127.0.0.1 doku.local # doku-managed - do not edit
+127.0.0.1 api.doku.local # doku-managed - do not edit
+# doku-managed-end
+# doku-managed-start [work]
+127.0.0.1 work.local # doku-managed [work] - do not edit
+127.0.0.1 api.work.local # doku-managed [work] - do not edit
+# doku-managed-end [work]
+127.0.0.1 app.doku.local # doku-managed - do not edit
+127.0.0.1 app.work.local # doku-managed [work] - do not edit
+`
+
+// TestContextSectionsAreIsolated tests that managers only touch their own context
+func TestContextSectionsAreIsolated(t *testing.T) {
+	manager, hostsFile, cleanup := createTestManager(t, sharedHostsContent)
+	defer cleanup()
+	work := &Manager{hostsFile: hostsFile, context: "work"}
+
+	if domain, err := manager.GetDokuDomain(); err != nil || domain != "doku.local" {
+		t.Errorf("default GetDokuDomain() = %q, %v, expected doku.local", domain, err)
+	}
+	if domain, err := work.GetDokuDomain(); err != nil || domain != "work.local" {
+		t.Errorf("work GetDokuDomain() = %q, %v, expected work.local", domain, err)
+	}
+
+	entries, err := work.ListDokuEntries()
+	if err != nil {
+		t.Fatalf("ListDokuEntries failed: %v", err)
+	}
+	if len(entries) != 2 || !strings.Contains(entries[1], "api.work.local") {
+		t.Errorf("work ListDokuEntries() = %v", entries)
+	}
+
+	if err := work.RemoveDokuEntries(); err != nil {
+		t.Fatalf("RemoveDokuEntries failed: %v", err)
+	}
+
+	content, err := os.ReadFile(hostsFile)
+	if err != nil {
+		t.Fatalf("Failed to read hosts file: %v", err)
+	}
+	contentStr := string(content)
+
+	if strings.Contains(contentStr, "[work]") {
+		t.Errorf("work entries should be removed, got:\n%s", contentStr)
+	}
+	for _, keep := range []string{"# doku-managed-start\n", "api.doku.local", "app.doku.local", "# doku-managed-end\n"} {
+		if !strings.Contains(contentStr, keep) {
+			t.Errorf("default context entry %q should be kept, got:\n%s", keep, contentStr)
+		}
+	}
+
+	has, err := work.HasDokuEntries()
+	if err != nil || has {
+		t.Errorf("work HasDokuEntries() = %v, %v, expected false", has, err)
+	}
+	has, err = manager.HasDokuEntries()
+	if err != nil || !has {
+		t.Errorf("default HasDokuEntries() = %v, %v, expected true", has, err)
+	}
+}
+
+// TestAddServiceDomainContext tests that entries go into the context's own section
+func TestAddServiceDomainContext(t *testing.T) {
+	_, hostsFile, cleanup := createTestManager(t, sharedHostsContent)
+	defer cleanup()
+	work := &Manager{hostsFile: hostsFile, context: "work"}
+
+	if err := work.AddServiceDomain("redis", "work.local"); err != nil {
+		t.Fatalf("AddServiceDomain failed: %v", err)
+	}
+
+	content, err := os.ReadFile(hostsFile)
+	if err != nil {
+		t.Fatalf("Failed to read hosts file: %v", err)
+	}
+
+	expected := "127.0.0.1 redis.work.local # doku-managed [work] - do not edit\n# doku-managed-end [work]"
+	if !strings.Contains(string(content), expected) {
+		t.Errorf("entry should be added before the work end marker, got:\n%s", content)
+	}
+}
+
+// TestRemoveSingleEntryContext tests that single entries of other contexts are kept
+func TestRemoveSingleEntryContext(t *testing.T) {
+	initialContent := `127.0.0.1 app.local # doku-managed - do not edit
+127.0.0.1 app.local # doku-managed [work] - do not edit
+`
+	manager, hostsFile, cleanup := createTestManager(t, initialContent)
+	defer cleanup()
+
+	if err := manager.RemoveSingleEntry("app.local"); err != nil {
+		t.Fatalf("RemoveSingleEntry failed: %v", err)
+	}
+
+	content, err := os.ReadFile(hostsFile)
+	if err != nil {
+		t.Fatalf("Failed to read hosts file: %v", err)
+	}
+	if string(content) != "127.0.0.1 app.local # doku-managed [work] - do not edit\n" {
+		t.Errorf("unexpected hosts file:\n%s", content)
+	}
+}
+
+// TestRenameContext tests moving entries from one context to another
+func TestRenameContext(t *testing.T) {
+	manager, hostsFile, cleanup := createTestManager(t, sharedHostsContent)
+	defer cleanup()
+
+	if err := manager.RenameContext("personal"); err != nil {
+		t.Fatalf("RenameContext failed: %v", err)
+	}
+	if manager.Context() != "personal" {
+		t.Errorf("Context() = %q, expected personal", manager.Context())
+	}
+
+	content, err := os.ReadFile(hostsFile)
+	if err != nil {
+		t.Fatalf("Failed to read hosts file: %v", err)
+	}
+	contentStr := string(content)
+
+	for _, expected := range []string{
+		"# doku-managed-start [personal]\n127.0.0.1 doku.local # doku-managed [personal] - do not edit",
+		"# doku-managed-end [personal]\n",
+		"127.0.0.1 app.doku.local # doku-managed [personal] - do not edit",
+		"# doku-managed-start [work]\n127.0.0.1 work.local # doku-managed [work] - do not edit",
+	} {
+		if !strings.Contains(contentStr, expected) {
+			t.Errorf("hosts file should contain %q, got:\n%s", expected, contentStr)
+		}
+	}
+	if strings.Contains(contentStr, DokuMarker) {
+		t.Errorf("no unnamed entries should be left, got:\n%s", contentStr)
+	}
+
+	if err := manager.RenameContext("bad name"); err == nil {
+		t.Error("RenameContext should reject invalid context names")
+	}
+}
+
+// TestValidateContext tests context name validation
+func TestValidateContext(t *testing.T) {
+	for _, name := range []string{"", "work", "client-a", "team_2"} {
+		if err := ValidateContext(name); err != nil {
+			t.Errorf("ValidateContext(%q) returned error: %v", name, err)
+		}
+	}
+	for _, name := range []string{"my work", "a]b", "x#y"} {
+		if err := ValidateContext(name); err == nil {
+			t.Errorf("ValidateContext(%q) should return an error", name)
+		}
+	}
+}
+
+// crlfHostsContent is a Windows hosts file with a Doku section
+const crlfHostsContent = "127.0.0.1 localhost\r\n" +
+	"# doku-managed-start\r\n" +
+	"127.0.0.1 doku.local # doku-managed - do not edit\r\n" +
+	"# doku-managed-end\r\n"
+
+// TestCRLFPreserved tests that edits keep a CRLF hosts file's line endings
+func TestCRLFPreserved(t *testing.T) {
+	manager, hostsFile, cleanup := createTestManager(t, crlfHostsContent)
+	defer cleanup()
+
+	if err := manager.AddServiceDomain("redis", "doku.local"); err != nil {
+		t.Fatalf("AddServiceDomain failed: %v", err)
+	}
+	if err := manager.AddSingleEntry("127.0.0.1", "api.myproject.test"); err != nil {
+		t.Fatalf("AddSingleEntry failed: %v", err)
+	}
+
+	content, err := os.ReadFile(hostsFile)
+	if err != nil {
+		t.Fatalf("Failed to read hosts file: %v", err)
+	}
+	contentStr := string(content)
+	if strings.Count(contentStr, "\n") != strings.Count(contentStr, "\r\n") {
+		t.Errorf("hosts file has bare LF line endings:\n%q", contentStr)
+	}
+	expected := "127.0.0.1 redis.doku.local # doku-managed - do not edit\r\n# doku-managed-end\r\n"
+	if !strings.Contains(contentStr, expected) {
+		t.Errorf("entry should be added before the end marker, got:\n%q", contentStr)
+	}
+
+	if err := manager.RemoveDokuEntries(); err != nil {
+		t.Fatalf("RemoveDokuEntries failed: %v", err)
+	}
+	content, _ = os.ReadFile(hostsFile)
+	contentStr = string(content)
+	if !strings.HasPrefix(contentStr, "127.0.0.1 localhost\r\n") || strings.Contains(contentStr, "doku") {
+		t.Errorf("unexpected hosts file after removal:\n%q", contentStr)
+	}
+	if strings.Count(contentStr, "\n") != strings.Count(contentStr, "\r\n") {
+		t.Errorf("hosts file has bare LF line endings after removal:\n%q", contentStr)
+	}
+}
+
+// TestCRLFDomain tests reading the Doku domain of a CRLF hosts file
+func TestCRLFDomain(t *testing.T) {
+	manager, _, cleanup := createTestManager(t, crlfHostsContent)
+	defer cleanup()
+
+	if domain, err := manager.GetDokuDomain(); err != nil || domain != "doku.local" {
+		t.Errorf("GetDokuDomain() = %q, %v, expected doku.local", domain, err)
+	}
+	if has, err := manager.HasServiceDomain("redis", "doku.local"); err != nil || has {
+		t.Errorf("HasServiceDomain(redis) = %v, %v, expected false", has, err)
+	}
+}
+
+// TestWithLineEndings tests converting line endings
+func TestWithLineEndings(t *testing.T) {
+	mixed := "a\r\nb\nc\n"
+	if got := withLineEndings(mixed, "\r\n"); got != "a\r\nb\r\nc\r\n" {
+		t.Errorf("withLineEndings(CRLF) = %q", got)
+	}
+	if got := withLineEndings(mixed, "\n"); got != "a\nb\nc\n" {
+		t.Errorf("withLineEndings(LF) = %q", got)
+	}
+}
+
+// TestLineEnding tests detecting the hosts file's line endings
+func TestLineEnding(t *testing.T) {
+	tests := map[string]string{
+		"127.0.0.1 localhost\r\n": "\r\n",
+		"127.0.0.1 localhost\n":   "\n",
+		"":                        defaultLineEnding,
+	}
+	for content, expected := range tests {
+		manager, _, cleanup := createTestManager(t, content)
+		if got := manager.lineEnding(); got != expected {
+			t.Errorf("lineEnding() of %q = %q, expected %q", content, got, expected)
+		}
+		cleanup()
+	}
+}
+
+// TestEntries tests listing the manager's entries
+func TestEntries(t *testing.T) {
+	manager, _, cleanup := createTestManager(t, sharedHostsContent)
+	defer cleanup()
+
+	entries, err := manager.Entries()
+	if err != nil {
+		t.Fatalf("Entries failed: %v", err)
+	}
+	expected := []Entry{
+		{IP: "127.0.0.1", Hostname: "doku.local"},
+		{IP: "127.0.0.1", Hostname: "api.doku.local"},
+		{IP: "127.0.0.1", Hostname: "app.doku.local"},
+	}
+	if len(entries) != len(expected) {
+		t.Fatalf("Entries() = %v, expected %v", entries, expected)
+	}
+	for i := range expected {
+		if entries[i] != expected[i] {
+			t.Errorf("Entries()[%d] = %v, expected %v", i, entries[i], expected[i])
+		}
+	}
+}
+
+// TestSetHostnames tests regenerating the manager's section
+func TestSetHostnames(t *testing.T) {
+	manager, hostsFile, cleanup := createTestManager(t, sharedHostsContent)
+	defer cleanup()
+
+	if err := manager.SetHostnames([]string{"doku.local", "redis.doku.local"}); err != nil {
+		t.Fatalf("SetHostnames failed: %v", err)
+	}
+
+	content, err := os.ReadFile(hostsFile)
+	if err != nil {
+		t.Fatalf("Failed to read hosts file: %v", err)
+	}
+	contentStr := string(content)
+	for _, gone := range []string{"api.doku.local", "app.doku.local"} {
+		if strings.Contains(contentStr, gone) {
+			t.Errorf("%s should be removed, got:\n%s", gone, contentStr)
+		}
+	}
+	expected := "# doku-managed-start\n127.0.0.1 doku.local # doku-managed - do not edit\n" +
+		"127.0.0.1 redis.doku.local # doku-managed - do not edit\n# doku-managed-end\n"
+	if !strings.HasSuffix(contentStr, expected) {
+		t.Errorf("section should be regenerated at the end, got:\n%s", contentStr)
+	}
+	if strings.Count(contentStr, "[work]") != 5 {
+		t.Errorf("work entries should be kept, got:\n%s", contentStr)
+	}
+	if domain, err := manager.GetDokuDomain(); err != nil || domain != "doku.local" {
+		t.Errorf("GetDokuDomain() = %q, %v, expected doku.local", domain, err)
+	}
+}
+
+// TestRemoveSingleEntryExact tests that only the exact hostname is removed
+func TestRemoveSingleEntryExact(t *testing.T) {
+	initialContent := `127.0.0.1 api.doku.local # doku-managed - do not edit
+127.0.0.1 myapi.doku.local # doku-managed - do not edit
+`
+	manager, hostsFile, cleanup := createTestManager(t, initialContent)
+	defer cleanup()
+
+	if err := manager.RemoveSingleEntry("api.doku.local"); err != nil {
+		t.Fatalf("RemoveSingleEntry failed: %v", err)
+	}
+
+	content, err := os.ReadFile(hostsFile)
+	if err != nil {
+		t.Fatalf("Failed to read hosts file: %v", err)
+	}
+	if string(content) != "127.0.0.1 myapi.doku.local # doku-managed - do not edit\n" {
+		t.Errorf("unexpected hosts file:\n%s", content)
+	}
+}
+
+func TestSetIP(t *testing.T) {
+	manager, hostsFile, cleanup := createTestManager(t, "127.0.0.1 localhost\n")
+	defer cleanup()
+
+	if manager.SetIP("").IP() != "127.0.0.1" {
+		t.Errorf("SetIP(\"\") should keep 127.0.0.1, got %s", manager.IP())
+	}
+	manager.SetIP("127.0.0.2")
+	if err := manager.SetHostnames([]string{"doku.local"}); err != nil {
+		t.Fatalf("SetHostnames failed: %v", err)
+	}
+	if err := manager.AddHostname("redis.doku.local"); err != nil {
+		t.Fatalf("AddHostname failed: %v", err)
+	}
+
+	entries, err := manager.Entries()
+	if err != nil {
+		t.Fatalf("Entries failed: %v", err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("Entries() = %v, expected 2", entries)
+	}
+	for _, entry := range entries {
+		if entry.IP != "127.0.0.2" {
+			t.Errorf("%s resolves to %s, expected 127.0.0.2", entry.Hostname, entry.IP)
+		}
+	}
+	content, _ := os.ReadFile(hostsFile)
+	if !strings.HasPrefix(string(content), "127.0.0.1 localhost\n") {
+		t.Errorf("other entries should be kept, got:\n%s", content)
+	}
+}
+
+func TestReaddress(t *testing.T) {
+	manager, hostsFile, cleanup := createTestManager(t, sharedHostsContent)
+	defer cleanup()
+
+	if err := manager.SetIP("127.0.0.2").Readdress(); err != nil {
+		t.Fatalf("Readdress failed: %v", err)
+	}
+
+	entries, err := manager.Entries()
+	if err != nil {
+		t.Fatalf("Entries failed: %v", err)
+	}
+	if len(entries) == 0 {
+		t.Fatal("Entries() should not be empty")
+	}
+	for _, entry := range entries {
+		if entry.IP != "127.0.0.2" {
+			t.Errorf("%s resolves to %s, expected 127.0.0.2", entry.Hostname, entry.IP)
+		}
+	}
+	content, _ := os.ReadFile(hostsFile)
+	if !strings.Contains(string(content), "127.0.0.1 work.local #") || !strings.HasPrefix(string(content), "127.0.0.1 localhost\n") {
+		t.Errorf("entries of other contexts should be kept, got:\n%s", content)
+	}
+}
@@ -0,0 +1,20 @@
+//go:build !windows
+
+package dns
+
+// defaultLineEnding ends the lines of a new hosts file
+const defaultLineEnding = "\n"
+
+// elevationPrompt tells the user how they'll be asked for privileges
+const elevationPrompt = "📝 Please enter your password when prompted..."
+
+// getHostsFilePath returns the hosts file path
+func getHostsFilePath() string {
+	return "/etc/hosts"
+}
+
+// elevatedCopy copies src over dest with sudo. The arguments are passed
+// directly, not through a shell, so paths can't inject commands.
+func elevatedCopy(src, dest string) error {
+	return executeCommandArgs("sudo", "cp", src, dest)
+}
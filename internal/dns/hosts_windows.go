@@ -0,0 +1,31 @@
+//go:build windows
+
+package dns
+
+import (
+	"os"
+	"path/filepath"
+)
+
+// defaultLineEnding ends the lines of a new hosts file
+const defaultLineEnding = "\r\n"
+
+// elevationPrompt tells the user how they'll be asked for privileges
+const elevationPrompt = "📝 Please allow the change in the User Account Control prompt..."
+
+// getHostsFilePath returns the hosts file path, under the Windows
+// directory, which isn't always C:\Windows
+func getHostsFilePath() string {
+	root := os.Getenv("SystemRoot")
+	if root == "" {
+		root = `C:\Windows`
+	}
+	return filepath.Join(root, "System32", "drivers", "etc", "hosts")
+}
+
+// elevatedCopy copies src over dest from an elevated PowerShell, which
+// Windows asks the user to allow with a UAC prompt. Declining it fails
+// the copy.
+func elevatedCopy(src, dest string) error {
+	return executeCommandArgs("powershell", "-NoProfile", "-NonInteractive", "-Command", elevatedCopyScript(src, dest))
+}
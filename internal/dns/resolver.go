@@ -1,75 +1,134 @@
 package dns
 
 import (
+	"errors"
 	"fmt"
 	"os"
+	"os/exec"
 	"path/filepath"
 	"runtime"
+
+	"github.com/dokulabs/doku-cli/internal/readonly"
 )
 
-// ResolverManager handles macOS /etc/resolver configuration
+// ResolverManager sends the queries for a domain to a local DNS server:
+// with an /etc/resolver file on macOS, and a systemd-resolved drop-in on
+// Linux
 type ResolverManager struct {
 	resolverDir string
+	resolvedDir string
+	port        int
 }
 
 // NewResolverManager creates a new resolver manager
 func NewResolverManager() *ResolverManager {
 	return &ResolverManager{
 		resolverDir: "/etc/resolver",
+		resolvedDir: "/etc/systemd/resolved.conf.d",
+		port:        53,
 	}
 }
 
+// SetPort sets the port of the local DNS server
+func (rm *ResolverManager) SetPort(port int) *ResolverManager {
+	rm.port = port
+	return rm
+}
+
 // IsMacOS checks if the system is macOS
 func (rm *ResolverManager) IsMacOS() bool {
 	return runtime.GOOS == "darwin"
 }
 
-// SetupResolver creates a resolver configuration for the domain (macOS only)
-func (rm *ResolverManager) SetupResolver(domain string) error {
-	if !rm.IsMacOS() {
-		return fmt.Errorf("resolver setup is only supported on macOS")
+// usesResolved checks if the system resolves names with systemd-resolved
+func (rm *ResolverManager) usesResolved() bool {
+	if runtime.GOOS != "linux" {
+		return false
 	}
+	_, err := os.Stat("/run/systemd/resolve")
+	return err == nil
+}
 
-	// Ensure resolver directory exists
-	if err := os.MkdirAll(rm.resolverDir, 0755); err != nil {
-		return fmt.Errorf("failed to create resolver directory: %w", err)
-	}
+// Supported reports whether the system can send a domain's queries to a
+// local DNS server
+func (rm *ResolverManager) Supported() bool {
+	return rm.IsMacOS() || rm.usesResolved()
+}
 
-	// Create resolver file
-	resolverFile := filepath.Join(rm.resolverDir, domain)
+// macOSResolverConfig returns the /etc/resolver file pointing at the local
+// DNS server on port
+func macOSResolverConfig(port int) string {
 	content := "nameserver 127.0.0.1\n"
+	if port != 53 {
+		content += fmt.Sprintf("port %d\n", port)
+	}
+	return content
+}
 
-	// Write resolver configuration
-	if err := os.WriteFile(resolverFile, []byte(content), 0644); err != nil {
-		return fmt.Errorf("failed to write resolver file: %w", err)
+// resolvedConfig returns the systemd-resolved drop-in sending the queries
+// for domain, and only those, to the local DNS server on port
+func resolvedConfig(domain string, port int) string {
+	server := "127.0.0.1"
+	if port != 53 {
+		server = fmt.Sprintf("127.0.0.1:%d", port)
 	}
+	return fmt.Sprintf("[Resolve]\nDNS=%s\nDomains=~%s\n", server, domain)
+}
 
-	return nil
+// SetupResolver sends the queries for the domain and its subdomains to the
+// local DNS server
+func (rm *ResolverManager) SetupResolver(domain string) error {
+	if err := readonly.Check("set up the resolver for " + domain); err != nil {
+		return err
+	}
+
+	switch {
+	case rm.IsMacOS():
+		return writeSystemFile(rm.GetResolverPath(domain), macOSResolverConfig(rm.port))
+	case rm.usesResolved():
+		if err := writeSystemFile(rm.GetResolverPath(domain), resolvedConfig(domain, rm.port)); err != nil {
+			return err
+		}
+		return restartResolved()
+	default:
+		return fmt.Errorf("resolver setup is only supported on macOS and on Linux with systemd-resolved")
+	}
 }
 
 // RemoveResolver removes the resolver configuration for the domain
 func (rm *ResolverManager) RemoveResolver(domain string) error {
-	if !rm.IsMacOS() {
-		return nil
+	if err := readonly.Check("remove the resolver for " + domain); err != nil {
+		return err
 	}
 
-	resolverFile := filepath.Join(rm.resolverDir, domain)
+	if !rm.HasResolver(domain) {
+		return nil
+	}
 
+	resolverFile := rm.GetResolverPath(domain)
 	if err := os.Remove(resolverFile); err != nil && !os.IsNotExist(err) {
-		return fmt.Errorf("failed to remove resolver file: %w", err)
+		if !errors.Is(err, os.ErrPermission) {
+			return fmt.Errorf("failed to remove resolver file: %w", err)
+		}
+		printPrivilegesNote(resolverFile)
+		if err := executeCommandArgs("sudo", "rm", "-f", resolverFile); err != nil {
+			return fmt.Errorf("failed to remove resolver file with sudo: %w", err)
+		}
 	}
 
+	if rm.usesResolved() {
+		return restartResolved()
+	}
 	return nil
 }
 
 // HasResolver checks if a resolver configuration exists for the domain
 func (rm *ResolverManager) HasResolver(domain string) bool {
-	if !rm.IsMacOS() {
+	if !rm.Supported() {
 		return false
 	}
 
-	resolverFile := filepath.Join(rm.resolverDir, domain)
-	_, err := os.Stat(resolverFile)
+	_, err := os.Stat(rm.GetResolverPath(domain))
 	return err == nil
 }
 
@@ -99,5 +158,60 @@ func (rm *ResolverManager) ListResolvers() ([]string, error) {
 
 // GetResolverPath returns the path to the resolver file for a domain
 func (rm *ResolverManager) GetResolverPath(domain string) string {
-	return filepath.Join(rm.resolverDir, domain)
+	if rm.IsMacOS() {
+		return filepath.Join(rm.resolverDir, domain)
+	}
+	return filepath.Join(rm.resolvedDir, "doku-"+domain+".conf")
+}
+
+// writeSystemFile writes a file under /etc, with sudo if needed
+func writeSystemFile(path, content string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err == nil {
+		if err := os.WriteFile(path, []byte(content), 0644); err == nil {
+			return nil
+		}
+	}
+
+	tmpFile, err := os.CreateTemp("", "doku-resolver-*")
+	if err != nil {
+		return fmt.Errorf("failed to create temp file: %w", err)
+	}
+	defer os.Remove(tmpFile.Name())
+	if _, err := tmpFile.WriteString(content); err != nil {
+		tmpFile.Close()
+		return fmt.Errorf("failed to write temp file: %w", err)
+	}
+	tmpFile.Close()
+
+	printPrivilegesNote(path)
+	if err := executeCommandArgs("sudo", "mkdir", "-p", filepath.Dir(path)); err != nil {
+		return fmt.Errorf("failed to create %s with sudo: %w", filepath.Dir(path), err)
+	}
+	if err := executeCommandArgs("sudo", "cp", tmpFile.Name(), path); err != nil {
+		return fmt.Errorf("failed to write %s with sudo: %w", path, err)
+	}
+	// sudo cp keeps the temp file's mode, which only its owner can read
+	if err := executeCommandArgs("sudo", "chmod", "644", path); err != nil {
+		return fmt.Errorf("failed to make %s readable: %w", path, err)
+	}
+	return nil
+}
+
+// restartResolved makes systemd-resolved load its drop-ins again
+func restartResolved() error {
+	if err := exec.Command("systemctl", "restart", "systemd-resolved").Run(); err == nil {
+		return nil
+	}
+	if err := executeCommandArgs("sudo", "systemctl", "restart", "systemd-resolved"); err != nil {
+		return fmt.Errorf("failed to restart systemd-resolved: %w", err)
+	}
+	return nil
+}
+
+// printPrivilegesNote tells the user why sudo asks for their password
+func printPrivilegesNote(path string) {
+	fmt.Println()
+	fmt.Printf("⚠️  Updating %s requires administrator privileges\n", path)
+	fmt.Println("📝 Please enter your password when prompted...")
+	fmt.Println()
 }
@@ -0,0 +1,23 @@
+package dns
+
+import "testing"
+
+// TestMacOSResolverConfig tests the /etc/resolver file content
+func TestMacOSResolverConfig(t *testing.T) {
+	if got := macOSResolverConfig(53); got != "nameserver 127.0.0.1\n" {
+		t.Errorf("macOSResolverConfig(53) = %q", got)
+	}
+	if got := macOSResolverConfig(5354); got != "nameserver 127.0.0.1\nport 5354\n" {
+		t.Errorf("macOSResolverConfig(5354) = %q", got)
+	}
+}
+
+// TestResolvedConfig tests the systemd-resolved drop-in content
+func TestResolvedConfig(t *testing.T) {
+	if got := resolvedConfig("doku.local", 53); got != "[Resolve]\nDNS=127.0.0.1\nDomains=~doku.local\n" {
+		t.Errorf("resolvedConfig(doku.local, 53) = %q", got)
+	}
+	if got := resolvedConfig("doku.local", 5354); got != "[Resolve]\nDNS=127.0.0.1:5354\nDomains=~doku.local\n" {
+		t.Errorf("resolvedConfig(doku.local, 5354) = %q", got)
+	}
+}
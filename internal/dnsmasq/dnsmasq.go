@@ -0,0 +1,166 @@
+// Package dnsmasq runs the DNS server of Doku's wildcard DNS mode: a
+// dnsmasq container answering every name under Doku's domains with the
+// local address, so services resolve without a hosts file entry each. The
+// OS sends it only the queries for those domains, see dns.ResolverManager.
+package dnsmasq
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/network"
+	"github.com/docker/go-connections/nat"
+	"github.com/dokulabs/doku-cli/internal/docker"
+	"github.com/dokulabs/doku-cli/internal/monitoring"
+	"github.com/dokulabs/doku-cli/internal/workspace"
+	"github.com/dokulabs/doku-cli/pkg/types"
+)
+
+// Image is the dnsmasq image
+const Image = "4km3/dnsmasq:2.90-r3"
+
+// DefaultPort is the host port dnsmasq answers on unless configured
+// otherwise
+const DefaultPort = 53
+
+// Address is what every name under Doku's domains resolves to unless the
+// manager is given another, e.g. the bind IP
+const Address = "127.0.0.1"
+
+// ContainerName returns the name of the dnsmasq container of the selected
+// workspace, doku-dnsmasq in the default one
+func ContainerName() string {
+	return workspace.Prefix() + "dnsmasq"
+}
+
+// Port returns the host port dnsmasq answers on for a configured one,
+// DefaultPort for 0
+func Port(port int) int {
+	if port == 0 {
+		return DefaultPort
+	}
+	return port
+}
+
+// Args returns the arguments dnsmasq runs with to answer for domains and
+// their subdomains with address. It knows no other names and forwards
+// nothing.
+func Args(domains []string, address string) []string {
+	args := []string{"--keep-in-foreground", "--no-resolv", "--no-hosts", "--log-facility=-"}
+	for _, domain := range domains {
+		args = append(args, fmt.Sprintf("--address=/%s/%s", domain, address))
+	}
+	return args
+}
+
+// Manager handles the dnsmasq container
+type Manager struct {
+	dockerClient *docker.Client
+	domains      []string
+	address      string
+	port         int
+
+	// logRotation is the rotation of the container's logs
+	logRotation types.LogRotation
+}
+
+// NewManager creates a dnsmasq manager answering for domains
+func NewManager(dockerClient *docker.Client, domains []string) *Manager {
+	return &Manager{
+		dockerClient: dockerClient,
+		domains:      domains,
+		address:      Address,
+		port:         DefaultPort,
+	}
+}
+
+// SetPort sets the host port dnsmasq answers on, DefaultPort for 0
+func (m *Manager) SetPort(port int) *Manager {
+	m.port = Port(port)
+	return m
+}
+
+// SetAddress sets what the names resolve to, Address for ""
+func (m *Manager) SetAddress(address string) *Manager {
+	if address == "" {
+		address = Address
+	}
+	m.address = address
+	return m
+}
+
+// SetLogRotation sets the rotation of the logs of the container created
+// from now on
+func (m *Manager) SetLogRotation(rotation types.LogRotation) *Manager {
+	m.logRotation = rotation
+	return m
+}
+
+// Setup creates and starts the dnsmasq container, replacing the existing
+// one: its arguments hold the domains, which may have changed
+func (m *Manager) Setup() error {
+	if err := m.RemoveContainer(); err != nil {
+		return err
+	}
+
+	if cached, err := m.dockerClient.ImageExists(Image); err != nil || !cached {
+		fmt.Printf("Pulling dnsmasq image %s...\n", Image)
+		if err := m.dockerClient.ImagePull(Image); err != nil {
+			return fmt.Errorf("failed to pull dnsmasq image: %w", err)
+		}
+	}
+
+	bindings := nat.PortMap{}
+	exposed := nat.PortSet{}
+	for _, proto := range []string{"udp", "tcp"} {
+		// Only on the loopback address: it answers for Doku's domains
+		// alone, and must not become the LAN's resolver
+		spec := nat.Port("53/" + proto)
+		bindings[spec] = []nat.PortBinding{{HostIP: "127.0.0.1", HostPort: strconv.Itoa(m.port)}}
+		exposed[spec] = struct{}{}
+	}
+
+	containerConfig := &container.Config{
+		Image:        Image,
+		Entrypoint:   []string{"dnsmasq"},
+		Cmd:          Args(m.domains, m.address),
+		ExposedPorts: exposed,
+		Labels:       docker.ComponentLabels("dnsmasq"),
+	}
+	hostConfig := &container.HostConfig{
+		RestartPolicy: container.RestartPolicy{Name: "unless-stopped"},
+		PortBindings:  bindings,
+		LogConfig:     *monitoring.GetDockerLoggingConfig(nil, m.logRotation),
+	}
+	containerID, err := m.dockerClient.ContainerCreate(containerConfig, hostConfig, &network.NetworkingConfig{}, ContainerName())
+	if err != nil {
+		return fmt.Errorf("failed to create dnsmasq container: %w", err)
+	}
+	if err := m.dockerClient.ContainerStart(containerID); err != nil {
+		return fmt.Errorf("failed to start dnsmasq container on port %d: %w", m.port, err)
+	}
+	return nil
+}
+
+// RemoveContainer removes the dnsmasq container
+func (m *Manager) RemoveContainer() error {
+	exists, err := m.dockerClient.ContainerExists(ContainerName())
+	if err != nil || !exists {
+		return err
+	}
+	return m.dockerClient.ContainerRemove(ContainerName(), true)
+}
+
+// IsRunning checks if the dnsmasq container is running
+func (m *Manager) IsRunning() (bool, error) {
+	exists, err := m.dockerClient.ContainerExists(ContainerName())
+	if err != nil || !exists {
+		return false, err
+	}
+	info, err := m.dockerClient.ContainerInspect(ContainerName())
+	if err != nil {
+		return false, err
+	}
+	return info.State.Running, nil
+}
@@ -0,0 +1,31 @@
+package dnsmasq
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestArgs(t *testing.T) {
+	args := Args([]string{"doku.local", "myproject.test"}, Address)
+	for _, want := range []string{"--no-resolv", "--address=/doku.local/127.0.0.1", "--address=/myproject.test/127.0.0.1"} {
+		if !slices.Contains(args, want) {
+			t.Errorf("Args() = %v, lacks %s", args, want)
+		}
+	}
+
+	args = Args([]string{"doku.local"}, "127.0.0.2")
+	for _, want := range []string{"--address=/doku.local/127.0.0.2"} {
+		if !slices.Contains(args, want) {
+			t.Errorf("Args() = %v, lacks %s", args, want)
+		}
+	}
+}
+
+func TestPort(t *testing.T) {
+	if got := Port(0); got != DefaultPort {
+		t.Errorf("Port(0) = %d, want %d", got, DefaultPort)
+	}
+	if got := Port(5354); got != 5354 {
+		t.Errorf("Port(5354) = %d, want 5354", got)
+	}
+}
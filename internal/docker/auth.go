@@ -0,0 +1,285 @@
+package docker
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/distribution/reference"
+	"github.com/docker/docker/api/types/registry"
+	"github.com/dokulabs/doku-cli/pkg/types"
+)
+
+const (
+	// DockerHub is the registry of images without a registry host
+	DockerHub = "docker.io"
+
+	// dockerHubServer is the name Docker's credential stores know Docker
+	// Hub by
+	dockerHubServer = "https://index.docker.io/v1/"
+)
+
+var (
+	loginsMu sync.RWMutex
+
+	// registryLogins are the logins saved with 'doku registry login',
+	// keyed by registry host. They take precedence over Docker's own.
+	registryLogins map[string]types.RegistryLogin
+)
+
+// SetRegistryLogins sets the logins saved with 'doku registry login' that
+// pulls and builds use for the rest of the process
+func SetRegistryLogins(logins []types.RegistryLogin) {
+	loginsMu.Lock()
+	defer loginsMu.Unlock()
+
+	registryLogins = make(map[string]types.RegistryLogin, len(logins))
+	for _, login := range logins {
+		registryLogins[NormalizeRegistry(login.Server)] = login
+	}
+}
+
+// NormalizeRegistry returns the host of a registry address, e.g.
+// "https://ghcr.io/v2/" -> "ghcr.io". Docker Hub's addresses all become
+// "docker.io".
+func NormalizeRegistry(server string) string {
+	host := strings.ToLower(server)
+	host = strings.TrimPrefix(host, "https://")
+	host = strings.TrimPrefix(host, "http://")
+	host, _, _ = strings.Cut(host, "/")
+
+	switch host {
+	case "index.docker.io", "registry-1.docker.io", "registry.hub.docker.com":
+		return DockerHub
+	}
+	return host
+}
+
+// RegistryHost returns the registry an image is pulled from, e.g.
+// "ghcr.io/acme/api:1.0" -> "ghcr.io", "postgres:16" -> "docker.io"
+func RegistryHost(imageName string) string {
+	named, err := reference.ParseNormalizedNamed(imageName)
+	if err != nil {
+		return DockerHub
+	}
+	return NormalizeRegistry(reference.Domain(named))
+}
+
+// registryAuth returns the encoded credentials to pull imageName with, or
+// "" to pull it anonymously
+func registryAuth(imageName string) (string, error) {
+	auth, ok, err := RegistryCredentials(RegistryHost(imageName))
+	if err != nil || !ok {
+		return "", err
+	}
+	return registry.EncodeAuthConfig(auth)
+}
+
+// RegistryCredentials returns the credentials for a registry host: the
+// login saved with 'doku registry login', or else Docker's, from its
+// config.json and credential helpers. ok is false when there are none.
+func RegistryCredentials(host string) (auth registry.AuthConfig, ok bool, err error) {
+	host = NormalizeRegistry(host)
+
+	loginsMu.RLock()
+	login, found := registryLogins[host]
+	loginsMu.RUnlock()
+	if found {
+		return registry.AuthConfig{
+			Username:      login.Username,
+			Password:      login.Password,
+			ServerAddress: serverAddress(host),
+		}, true, nil
+	}
+
+	cfg, err := loadDockerConfig()
+	if err != nil {
+		return registry.AuthConfig{}, false, err
+	}
+	return cfg.credentials(host)
+}
+
+// allRegistryAuths returns the credentials for every registry known, keyed
+// by server address, for builds whose base images may come from any of
+// them. Registries whose credentials can't be read are left out.
+func allRegistryAuths() map[string]registry.AuthConfig {
+	hosts := make(map[string]bool)
+	if cfg, err := loadDockerConfig(); err == nil {
+		for _, host := range cfg.hosts() {
+			hosts[host] = true
+		}
+	}
+	loginsMu.RLock()
+	for host := range registryLogins {
+		hosts[host] = true
+	}
+	loginsMu.RUnlock()
+
+	auths := make(map[string]registry.AuthConfig, len(hosts))
+	for host := range hosts {
+		if auth, ok, err := RegistryCredentials(host); err == nil && ok {
+			auths[serverAddress(host)] = auth
+		}
+	}
+	return auths
+}
+
+// serverAddress returns the address Docker knows a registry host by
+func serverAddress(host string) string {
+	if host == DockerHub {
+		return dockerHubServer
+	}
+	return host
+}
+
+// dockerConfig is the part of Docker's config.json holding credentials
+type dockerConfig struct {
+	Auths       map[string]dockerAuth `json:"auths"`
+	CredsStore  string                `json:"credsStore"`
+	CredHelpers map[string]string     `json:"credHelpers"`
+}
+
+// dockerAuth is an entry of config.json's auths
+type dockerAuth struct {
+	Auth          string `json:"auth"`
+	IdentityToken string `json:"identitytoken"`
+}
+
+// dockerConfigPath returns the path of Docker's config.json: in
+// $DOCKER_CONFIG, or else in ~/.docker
+func dockerConfigPath() string {
+	if dir := os.Getenv("DOCKER_CONFIG"); dir != "" {
+		return filepath.Join(dir, "config.json")
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, ".docker", "config.json")
+}
+
+// loadDockerConfig reads Docker's config.json. A missing file has no
+// credentials.
+func loadDockerConfig() (*dockerConfig, error) {
+	cfg := &dockerConfig{}
+	path := dockerConfigPath()
+	if path == "" {
+		return cfg, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return cfg, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read Docker config: %w", err)
+	}
+	if err := json.Unmarshal(data, cfg); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	return cfg, nil
+}
+
+// hosts returns the registries config.json has credentials for, except
+// those only a credential store knows
+func (c *dockerConfig) hosts() []string {
+	var hosts []string
+	for server := range c.Auths {
+		hosts = append(hosts, NormalizeRegistry(server))
+	}
+	for server := range c.CredHelpers {
+		hosts = append(hosts, NormalizeRegistry(server))
+	}
+	return hosts
+}
+
+// credentials returns the credentials for a registry host: from its
+// credential helper, or else the store for all registries, or else the
+// auths of config.json, the order Docker uses
+func (c *dockerConfig) credentials(host string) (registry.AuthConfig, bool, error) {
+	for server, helper := range c.CredHelpers {
+		if NormalizeRegistry(server) == host {
+			return helperCredentials(helper, host)
+		}
+	}
+	if c.CredsStore != "" {
+		auth, ok, err := helperCredentials(c.CredsStore, host)
+		if err != nil || ok {
+			return auth, ok, err
+		}
+	}
+
+	for server, entry := range c.Auths {
+		if NormalizeRegistry(server) != host {
+			continue
+		}
+		auth := registry.AuthConfig{
+			ServerAddress: serverAddress(host),
+			IdentityToken: entry.IdentityToken,
+		}
+		if entry.Auth != "" {
+			decoded, err := base64.StdEncoding.DecodeString(entry.Auth)
+			if err != nil {
+				return registry.AuthConfig{}, false, fmt.Errorf("invalid credentials for %s in Docker config: %w", server, err)
+			}
+			username, password, found := strings.Cut(string(decoded), ":")
+			if !found {
+				return registry.AuthConfig{}, false, fmt.Errorf("invalid credentials for %s in Docker config", server)
+			}
+			auth.Username = username
+			auth.Password = password
+		}
+		if auth.Username == "" && auth.IdentityToken == "" {
+			continue
+		}
+		return auth, true, nil
+	}
+	return registry.AuthConfig{}, false, nil
+}
+
+// helperCredentials asks a Docker credential helper, e.g. "desktop" for
+// docker-credential-desktop, for the credentials of a registry host
+func helperCredentials(helper, host string) (registry.AuthConfig, bool, error) {
+	server := serverAddress(host)
+	cmd := exec.Command("docker-credential-"+helper, "get")
+	cmd.Stdin = strings.NewReader(server)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		// A helper that isn't installed has nothing to give
+		if errors.Is(err, exec.ErrNotFound) {
+			return registry.AuthConfig{}, false, nil
+		}
+		// Helpers report a registry they have nothing for on stdout
+		if strings.Contains(stdout.String()+stderr.String(), "credentials not found") {
+			return registry.AuthConfig{}, false, nil
+		}
+		return registry.AuthConfig{}, false, fmt.Errorf("credential helper docker-credential-%s failed: %w", helper, err)
+	}
+
+	var creds struct {
+		Username string
+		Secret   string
+	}
+	if err := json.Unmarshal(stdout.Bytes(), &creds); err != nil {
+		return registry.AuthConfig{}, false, fmt.Errorf("credential helper docker-credential-%s: %w", helper, err)
+	}
+
+	auth := registry.AuthConfig{ServerAddress: server}
+	if creds.Username == "<token>" {
+		auth.IdentityToken = creds.Secret
+	} else {
+		auth.Username = creds.Username
+		auth.Password = creds.Secret
+	}
+	return auth, true, nil
+}
@@ -0,0 +1,129 @@
+package docker
+
+import (
+	"encoding/base64"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/dokulabs/doku-cli/pkg/types"
+)
+
+func TestRegistryHost(t *testing.T) {
+	tests := []struct {
+		image    string
+		expected string
+	}{
+		{"postgres:16", "docker.io"},
+		{"grafana/grafana:latest", "docker.io"},
+		{"docker.io/library/redis", "docker.io"},
+		{"ghcr.io/acme/api:1.0", "ghcr.io"},
+		{"registry.example.com:5000/team/app", "registry.example.com:5000"},
+		{"localhost/app:dev", "localhost"},
+	}
+
+	for _, tt := range tests {
+		if got := RegistryHost(tt.image); got != tt.expected {
+			t.Errorf("RegistryHost(%q) = %q, expected %q", tt.image, got, tt.expected)
+		}
+	}
+}
+
+func TestNormalizeRegistry(t *testing.T) {
+	tests := map[string]string{
+		"https://index.docker.io/v1/": "docker.io",
+		"registry-1.docker.io":        "docker.io",
+		"https://GHCR.io/v2/":         "ghcr.io",
+		"registry.example.com:5000":   "registry.example.com:5000",
+	}
+
+	for server, expected := range tests {
+		if got := NormalizeRegistry(server); got != expected {
+			t.Errorf("NormalizeRegistry(%q) = %q, expected %q", server, got, expected)
+		}
+	}
+}
+
+func writeDockerConfig(t *testing.T, content string) {
+	t.Helper()
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "config.json"), []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("DOCKER_CONFIG", dir)
+}
+
+func TestRegistryCredentials(t *testing.T) {
+	auth := base64.StdEncoding.EncodeToString([]byte("dev:hub-password"))
+	writeDockerConfig(t, `{"auths": {
+		"https://index.docker.io/v1/": {"auth": "`+auth+`"},
+		"quay.io": {}
+	}}`)
+	defer SetRegistryLogins(nil)
+
+	creds, ok, err := RegistryCredentials("docker.io")
+	if err != nil || !ok {
+		t.Fatalf("RegistryCredentials(docker.io) = %v, %v", ok, err)
+	}
+	if creds.Username != "dev" || creds.Password != "hub-password" || creds.ServerAddress != dockerHubServer {
+		t.Errorf("RegistryCredentials(docker.io) = %+v", creds)
+	}
+
+	// Entries without credentials, and unknown registries, have none
+	for _, host := range []string{"quay.io", "ghcr.io"} {
+		if _, ok, err := RegistryCredentials(host); ok || err != nil {
+			t.Errorf("RegistryCredentials(%s) = %v, %v, expected no credentials", host, ok, err)
+		}
+	}
+
+	// Doku's own logins take precedence
+	SetRegistryLogins([]types.RegistryLogin{{Server: "docker.io", Username: "doku", Password: "doku-token"}})
+	creds, ok, err = RegistryCredentials("index.docker.io")
+	if err != nil || !ok || creds.Username != "doku" || creds.Password != "doku-token" {
+		t.Errorf("RegistryCredentials() = %+v, %v, %v, expected the doku login", creds, ok, err)
+	}
+
+	auths := allRegistryAuths()
+	if len(auths) != 1 || auths[dockerHubServer].Username != "doku" {
+		t.Errorf("allRegistryAuths() = %+v", auths)
+	}
+}
+
+func TestRegistryCredentialsHelper(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the fake credential helper is a shell script")
+	}
+
+	bin := t.TempDir()
+	helper := `#!/bin/sh
+read server
+case "$server" in
+  ghcr.io) echo '{"ServerURL": "ghcr.io", "Username": "octocat", "Secret": "gh-token"}' ;;
+  quay.io) echo '{"ServerURL": "quay.io", "Username": "<token>", "Secret": "identity"}' ;;
+  *) echo "credentials not found in native keychain"; exit 1 ;;
+esac
+`
+	if err := os.WriteFile(filepath.Join(bin, "docker-credential-fake"), []byte(helper), 0755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", bin+string(os.PathListSeparator)+os.Getenv("PATH"))
+	writeDockerConfig(t, `{"credsStore": "fake", "credHelpers": {"registry.example.com": "missing"}}`)
+
+	creds, ok, err := RegistryCredentials("ghcr.io")
+	if err != nil || !ok || creds.Username != "octocat" || creds.Password != "gh-token" {
+		t.Errorf("RegistryCredentials(ghcr.io) = %+v, %v, %v", creds, ok, err)
+	}
+
+	creds, ok, err = RegistryCredentials("quay.io")
+	if err != nil || !ok || creds.IdentityToken != "identity" || creds.Username != "" {
+		t.Errorf("RegistryCredentials(quay.io) = %+v, %v, %v, expected an identity token", creds, ok, err)
+	}
+
+	// Registries the helper doesn't know, or whose helper isn't installed
+	for _, host := range []string{"docker.io", "registry.example.com"} {
+		if _, ok, err := RegistryCredentials(host); ok || err != nil {
+			t.Errorf("RegistryCredentials(%s) = %v, %v, expected no credentials", host, ok, err)
+		}
+	}
+}
@@ -5,7 +5,10 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"net/http"
 	"os"
+	"runtime"
+	"strconv"
 	"strings"
 
 	"github.com/docker/docker/api/types"
@@ -14,14 +17,22 @@ import (
 	"github.com/docker/docker/api/types/image"
 	"github.com/docker/docker/api/types/network"
 	networkTypes "github.com/docker/docker/api/types/network"
+	"github.com/docker/docker/api/types/registry"
 	"github.com/docker/docker/api/types/volume"
 	"github.com/docker/docker/client"
+	"github.com/docker/docker/pkg/jsonmessage"
+	"github.com/docker/docker/pkg/stdcopy"
+	"github.com/dokulabs/doku-cli/internal/readonly"
+	"github.com/dokulabs/doku-cli/internal/timing"
+	"github.com/dokulabs/doku-cli/internal/workspace"
+	"golang.org/x/term"
 )
 
 // Client wraps the Docker SDK client
 type Client struct {
-	cli *client.Client
-	ctx context.Context
+	cli    *client.Client
+	ctx    context.Context
+	engine Engine
 }
 
 // NewClient creates a new Docker client with BuildKit enabled
@@ -30,21 +41,59 @@ func NewClient() (*Client, error) {
 	// This must be done before any Docker operations
 	os.Setenv("DOCKER_BUILDKIT", "1")
 
+	// Connects to Podman's socket when it runs instead of Docker, unless
+	// DOCKER_HOST says where to
+	engine := CurrentEngine()
+	opts := []client.Opt{client.FromEnv}
+	if host := engine.Host(); host != "" && os.Getenv("DOCKER_HOST") == "" {
+		opts = append(opts, client.WithHost(host))
+	}
+
 	// Create client with BuildKit support
-	cli, err := client.NewClientWithOpts(
-		client.FromEnv,
+	cli, err := client.NewClientWithOpts(append(opts,
 		client.WithAPIVersionNegotiation(),
-	)
+		withTiming(),
+	)...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create Docker client: %w", err)
 	}
 
 	return &Client{
-		cli: cli,
-		ctx: context.Background(),
+		cli:    cli,
+		ctx:    context.Background(),
+		engine: engine,
 	}, nil
 }
 
+// Engine returns the container engine the client talks to, or the one Doku
+// uses without a client
+func (c *Client) Engine() Engine {
+	if c == nil || c.engine == nil {
+		return CurrentEngine()
+	}
+	return c.engine
+}
+
+// withTiming records every Docker API call when profiling is on. It must
+// come after the options that configure the transport.
+func withTiming() client.Opt {
+	return func(c *client.Client) error {
+		if !timing.Enabled() {
+			return nil
+		}
+		httpClient := c.HTTPClient()
+		// The SDK picks https from the transport's TLS config, which it
+		// can't see through the wrapper
+		if tr, ok := httpClient.Transport.(*http.Transport); ok && tr.TLSClientConfig != nil {
+			if err := client.WithScheme("https")(c); err != nil {
+				return err
+			}
+		}
+		httpClient.Transport = timing.Transport(timing.Docker, httpClient.Transport)
+		return client.WithHTTPClient(httpClient)(c)
+	}
+}
+
 // Close closes the Docker client connection
 func (c *Client) Close() error {
 	if c.cli != nil {
@@ -80,6 +129,10 @@ func (c *Client) IsDockerAvailable() bool {
 
 // ContainerCreate creates a new container
 func (c *Client) ContainerCreate(config *container.Config, hostConfig *container.HostConfig, networkingConfig *networkTypes.NetworkingConfig, containerName string) (string, error) {
+	if err := readonly.Check("create container " + containerName); err != nil {
+		return "", err
+	}
+
 	resp, err := c.cli.ContainerCreate(c.ctx, config, hostConfig, networkingConfig, nil, containerName)
 	if err != nil {
 		return "", fmt.Errorf("failed to create container: %w", err)
@@ -90,6 +143,10 @@ func (c *Client) ContainerCreate(config *container.Config, hostConfig *container
 
 // ContainerStart starts a container
 func (c *Client) ContainerStart(containerID string) error {
+	if err := readonly.Check("start container " + containerID); err != nil {
+		return err
+	}
+
 	if err := c.cli.ContainerStart(c.ctx, containerID, container.StartOptions{}); err != nil {
 		return fmt.Errorf("failed to start container: %w", err)
 	}
@@ -98,6 +155,10 @@ func (c *Client) ContainerStart(containerID string) error {
 
 // ContainerStop stops a container
 func (c *Client) ContainerStop(containerID string, timeout *int) error {
+	if err := readonly.Check("stop container " + containerID); err != nil {
+		return err
+	}
+
 	options := container.StopOptions{}
 	if timeout != nil {
 		options.Timeout = timeout
@@ -111,6 +172,10 @@ func (c *Client) ContainerStop(containerID string, timeout *int) error {
 
 // ContainerRemove removes a container
 func (c *Client) ContainerRemove(containerID string, force bool) error {
+	if err := readonly.Check("remove container " + containerID); err != nil {
+		return err
+	}
+
 	options := container.RemoveOptions{
 		Force: force,
 	}
@@ -123,6 +188,10 @@ func (c *Client) ContainerRemove(containerID string, force bool) error {
 
 // ContainerRestart restarts a container
 func (c *Client) ContainerRestart(containerID string, timeout *int) error {
+	if err := readonly.Check("restart container " + containerID); err != nil {
+		return err
+	}
+
 	options := container.StopOptions{}
 	if timeout != nil {
 		options.Timeout = timeout
@@ -172,6 +241,22 @@ func (c *Client) ContainerLogs(containerID string, follow bool) (io.ReadCloser,
 	return logs, nil
 }
 
+// ContainerLogsTail retrieves the last lines of a container's logs,
+// without timestamps
+func (c *Client) ContainerLogsTail(ctx context.Context, containerID string, lines int) (io.ReadCloser, error) {
+	options := container.LogsOptions{
+		ShowStdout: true,
+		ShowStderr: true,
+		Tail:       strconv.Itoa(lines),
+	}
+
+	logs, err := c.cli.ContainerLogs(ctx, containerID, options)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get container logs: %w", err)
+	}
+	return logs, nil
+}
+
 // ContainerStats returns resource usage statistics for a container
 func (c *Client) ContainerStats(ctx context.Context, containerID string) (*ContainerStatsResult, error) {
 	stats, err := c.cli.ContainerStats(ctx, containerID, false)
@@ -192,6 +277,12 @@ func (c *Client) ContainerStats(ctx context.Context, containerID string) (*Conta
 		MemoryLimit: statsJSON.MemoryStats.Limit,
 	}
 
+	// Sum traffic over all network interfaces
+	for _, network := range statsJSON.Networks {
+		result.NetworkRx += network.RxBytes
+		result.NetworkTx += network.TxBytes
+	}
+
 	// Calculate CPU percentage
 	cpuDelta := float64(statsJSON.CPUStats.CPUUsage.TotalUsage - statsJSON.PreCPUStats.CPUUsage.TotalUsage)
 	systemDelta := float64(statsJSON.CPUStats.SystemUsage - statsJSON.PreCPUStats.SystemUsage)
@@ -207,6 +298,8 @@ type ContainerStatsResult struct {
 	CPUPercent  float64
 	MemoryUsage uint64
 	MemoryLimit uint64
+	NetworkRx   uint64 // Bytes received since the container started
+	NetworkTx   uint64 // Bytes sent since the container started
 }
 
 // ContainerExists checks if a container exists
@@ -227,19 +320,161 @@ func (c *Client) ContainerExists(containerName string) (bool, error) {
 	return false, nil
 }
 
+// ContainerDiff returns the filesystem changes a container made on top of its image
+func (c *Client) ContainerDiff(containerID string) ([]container.FilesystemChange, error) {
+	changes, err := c.cli.ContainerDiff(c.ctx, containerID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get container changes: %w", err)
+	}
+	return changes, nil
+}
+
 // Image Operations
 
-// ImagePull pulls an image from a registry
+// ImagePull pulls an image from a registry, showing its progress unless
+// pulls are quiet
 func (c *Client) ImagePull(imageName string) error {
-	out, err := c.cli.ImagePull(c.ctx, imageName, image.PullOptions{})
+	if quietPulls.Load() {
+		return c.ImagePullQuiet(imageName)
+	}
+	if err := readonly.Check("pull image " + imageName); err != nil {
+		return err
+	}
+
+	return c.pull(imageName, func(out io.Reader) error {
+		return renderPull(out, os.Stdout, IsTerminal(os.Stdout))
+	})
+}
+
+// pull pulls an image with the credentials of its registry, if any,
+// passing the progress stream to display. An image pulled through a mirror
+// is tagged with its own name, unless it's pinned by digest.
+func (c *Client) pull(imageName string, display func(io.Reader) error) error {
+	ref := MirrorImage(imageName)
+	auth, err := registryAuth(ref)
+	if err != nil {
+		return fmt.Errorf("failed to read registry credentials: %w", err)
+	}
+
+	out, err := c.cli.ImagePull(c.ctx, ref, image.PullOptions{RegistryAuth: auth})
 	if err != nil {
 		return fmt.Errorf("failed to pull image: %w", err)
 	}
 	defer out.Close()
+	if err := display(out); err != nil {
+		return err
+	}
+
+	if ref == imageName || IsDigestRef(imageName) {
+		return nil
+	}
+	return c.ImageTag(ref, imageName)
+}
+
+// RegistryLogin checks credentials against a registry, like docker login
+func (c *Client) RegistryLogin(auth registry.AuthConfig) error {
+	if _, err := c.cli.RegistryLogin(c.ctx, auth); err != nil {
+		return fmt.Errorf("login to %s failed: %w", auth.ServerAddress, err)
+	}
+	return nil
+}
+
+// Arch returns the architecture of the Docker daemon in Go's naming (amd64,
+// arm64), falling back to the client's when the daemon doesn't say
+func (c *Client) Arch() string {
+	version, err := c.cli.ServerVersion(c.ctx)
+	if err != nil || version.Arch == "" {
+		return runtime.GOARCH
+	}
+	return version.Arch
+}
+
+// ImageHasArch reports whether the registry has a build of an image for
+// linux/arch. Images whose manifest doesn't list platforms are assumed to
+// match. Checking needs the registry, so callers should treat an error as
+// "unknown".
+func (c *Client) ImageHasArch(imageName, arch string) (bool, error) {
+	ref := MirrorImage(imageName)
+	auth, err := registryAuth(ref)
+	if err != nil {
+		return false, fmt.Errorf("failed to read registry credentials: %w", err)
+	}
+	inspect, err := c.cli.DistributionInspect(c.ctx, ref, auth)
+	if err != nil {
+		return false, fmt.Errorf("failed to inspect image manifest: %w", err)
+	}
+	if len(inspect.Platforms) == 0 {
+		return true, nil
+	}
+	for _, platform := range inspect.Platforms {
+		if platform.OS == "linux" && platform.Architecture == arch {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// ImagePullQuiet pulls an image without printing progress
+func (c *Client) ImagePullQuiet(imageName string) error {
+	if err := readonly.Check("pull image " + imageName); err != nil {
+		return err
+	}
+
+	// Decoding the stream surfaces errors reported mid-pull
+	return c.pull(imageName, func(out io.Reader) error {
+		return jsonmessage.DisplayJSONMessagesStream(out, io.Discard, 0, false, nil)
+	})
+}
+
+// ImageSave writes local images to w as a tar archive, like docker save
+func (c *Client) ImageSave(imageNames []string, w io.Writer) error {
+	out, err := c.cli.ImageSave(c.ctx, imageNames)
+	if err != nil {
+		return fmt.Errorf("failed to save images: %w", err)
+	}
+	defer out.Close()
+
+	if _, err := io.Copy(w, out); err != nil {
+		return fmt.Errorf("failed to save images: %w", err)
+	}
+	return nil
+}
+
+// ImageLoad loads images from a tar archive written by ImageSave, like
+// docker load
+func (c *Client) ImageLoad(r io.Reader) error {
+	if err := readonly.Check("load images"); err != nil {
+		return err
+	}
+
+	resp, err := c.cli.ImageLoad(c.ctx, r, client.ImageLoadWithQuiet(true))
+	if err != nil {
+		return fmt.Errorf("failed to load images: %w", err)
+	}
+	defer resp.Body.Close()
+
+	// Decoding the stream surfaces errors reported mid-load
+	return jsonmessage.DisplayJSONMessagesStream(resp.Body, io.Discard, 0, false, nil)
+}
 
-	// Copy output to stdout to show pull progress
-	_, err = io.Copy(os.Stdout, out)
-	return err
+// ImageID returns the ID of a local image
+func (c *Client) ImageID(imageName string) (string, error) {
+	inspect, _, err := c.cli.ImageInspectWithRaw(c.ctx, imageName)
+	if err != nil {
+		return "", fmt.Errorf("failed to inspect image: %w", err)
+	}
+	return inspect.ID, nil
+}
+
+// ImageDigest returns the reference pinning a local image by digest, e.g.
+// "postgres@sha256:...", or "" for an image with no repo digest, such as
+// one built locally and never pushed
+func (c *Client) ImageDigest(imageName string) (string, error) {
+	inspect, _, err := c.cli.ImageInspectWithRaw(c.ctx, imageName)
+	if err != nil {
+		return "", fmt.Errorf("failed to inspect image: %w", err)
+	}
+	return DigestRef(imageName, inspect.RepoDigests), nil
 }
 
 // ImageList lists available images
@@ -253,6 +488,10 @@ func (c *Client) ImageList() ([]image.Summary, error) {
 
 // ImageRemove removes an image
 func (c *Client) ImageRemove(imageID string, force bool) error {
+	if err := readonly.Check("remove image " + imageID); err != nil {
+		return err
+	}
+
 	options := image.RemoveOptions{
 		Force: force,
 	}
@@ -264,25 +503,75 @@ func (c *Client) ImageRemove(imageID string, force bool) error {
 	return nil
 }
 
-// ImageExists checks if an image exists locally
+// ImageExists checks if an image exists locally, by tag, digest or image ID.
+// An image pinned by digest may also have been pulled through a mirror.
 func (c *Client) ImageExists(imageName string) (bool, error) {
+	exists, err := c.imageExists(imageName)
+	if exists || err != nil || !IsDigestRef(imageName) {
+		return exists, err
+	}
+	if mirrored := MirrorImage(imageName); mirrored != imageName {
+		return c.imageExists(mirrored)
+	}
+	return false, nil
+}
+
+// LocalImage returns the name to use a local image by: imageName, or for
+// one pinned by digest and pulled through a mirror, which can't be tagged
+// with its own name, the mirror's reference
+func (c *Client) LocalImage(imageName string) string {
+	if !IsDigestRef(imageName) {
+		return imageName
+	}
+	mirrored := MirrorImage(imageName)
+	if mirrored == imageName {
+		return imageName
+	}
+	if exists, err := c.imageExists(imageName); err == nil && !exists {
+		if exists, err := c.imageExists(mirrored); err == nil && exists {
+			return mirrored
+		}
+	}
+	return imageName
+}
+
+// imageExists checks if an image exists locally under exactly imageName
+func (c *Client) imageExists(imageName string) (bool, error) {
 	images, err := c.ImageList()
 	if err != nil {
 		return false, err
 	}
 
 	for _, img := range images {
+		if img.ID == imageName {
+			return true, nil
+		}
 		for _, tag := range img.RepoTags {
 			if tag == imageName {
 				return true, nil
 			}
 		}
+		for _, digest := range img.RepoDigests {
+			if digest == imageName {
+				return true, nil
+			}
+		}
 	}
 	return false, nil
 }
 
-// ImageBuild builds a Docker image
+// ImageBuild builds a Docker image. Unless options name the registries to
+// log in to, base images are pulled with the credentials of every registry
+// known.
 func (c *Client) ImageBuild(buildContext io.Reader, options types.ImageBuildOptions) (types.ImageBuildResponse, error) {
+	if err := readonly.Check("build image"); err != nil {
+		return types.ImageBuildResponse{}, err
+	}
+
+	if options.AuthConfigs == nil {
+		options.AuthConfigs = allRegistryAuths()
+	}
+
 	response, err := c.cli.ImageBuild(c.ctx, buildContext, options)
 	if err != nil {
 		return types.ImageBuildResponse{}, fmt.Errorf("failed to build image: %w", err)
@@ -292,6 +581,10 @@ func (c *Client) ImageBuild(buildContext io.Reader, options types.ImageBuildOpti
 
 // ImageTag tags an image
 func (c *Client) ImageTag(source, target string) error {
+	if err := readonly.Check("tag image " + target); err != nil {
+		return err
+	}
+
 	if err := c.cli.ImageTag(c.ctx, source, target); err != nil {
 		return fmt.Errorf("failed to tag image: %w", err)
 	}
@@ -311,6 +604,10 @@ func (c *Client) ImageInspectWithRaw(imageID string) (types.ImageInspect, []byte
 
 // VolumeCreate creates a new volume
 func (c *Client) VolumeCreate(volumeName string, labels map[string]string) (*volume.Volume, error) {
+	if err := readonly.Check("create volume " + volumeName); err != nil {
+		return nil, err
+	}
+
 	vol, err := c.cli.VolumeCreate(c.ctx, volume.CreateOptions{
 		Name:   volumeName,
 		Labels: labels,
@@ -323,6 +620,10 @@ func (c *Client) VolumeCreate(volumeName string, labels map[string]string) (*vol
 
 // VolumeRemove removes a volume
 func (c *Client) VolumeRemove(volumeName string, force bool) error {
+	if err := readonly.Check("remove volume " + volumeName); err != nil {
+		return err
+	}
+
 	if err := c.cli.VolumeRemove(c.ctx, volumeName, force); err != nil {
 		return fmt.Errorf("failed to remove volume: %w", err)
 	}
@@ -368,6 +669,10 @@ func (c *Client) VolumeExists(volumeName string) (bool, error) {
 
 // NetworkCreate creates a new network
 func (c *Client) NetworkCreate(networkName string, options network.CreateOptions) (string, error) {
+	if err := readonly.Check("create network " + networkName); err != nil {
+		return "", err
+	}
+
 	resp, err := c.cli.NetworkCreate(c.ctx, networkName, options)
 	if err != nil {
 		return "", fmt.Errorf("failed to create network: %w", err)
@@ -377,6 +682,10 @@ func (c *Client) NetworkCreate(networkName string, options network.CreateOptions
 
 // NetworkRemove removes a network
 func (c *Client) NetworkRemove(networkID string) error {
+	if err := readonly.Check("remove network " + networkID); err != nil {
+		return err
+	}
+
 	if err := c.cli.NetworkRemove(c.ctx, networkID); err != nil {
 		return fmt.Errorf("failed to remove network: %w", err)
 	}
@@ -418,6 +727,10 @@ func (c *Client) NetworkExists(networkName string) (bool, error) {
 
 // NetworkConnect connects a container to a network
 func (c *Client) NetworkConnect(networkID, containerID string) error {
+	if err := readonly.Check("connect container " + containerID + " to a network"); err != nil {
+		return err
+	}
+
 	if err := c.cli.NetworkConnect(c.ctx, networkID, containerID, nil); err != nil {
 		return fmt.Errorf("failed to connect container to network: %w", err)
 	}
@@ -426,6 +739,10 @@ func (c *Client) NetworkConnect(networkID, containerID string) error {
 
 // NetworkConnectWithAliases connects a container to a network with custom aliases
 func (c *Client) NetworkConnectWithAliases(networkID, containerID string, aliases []string) error {
+	if err := readonly.Check("connect container " + containerID + " to a network"); err != nil {
+		return err
+	}
+
 	endpointSettings := &networkTypes.EndpointSettings{
 		Aliases: aliases,
 	}
@@ -438,6 +755,10 @@ func (c *Client) NetworkConnectWithAliases(networkID, containerID string, aliase
 
 // NetworkDisconnect disconnects a container from a network
 func (c *Client) NetworkDisconnect(networkID, containerID string, force bool) error {
+	if err := readonly.Check("disconnect container " + containerID + " from a network"); err != nil {
+		return err
+	}
+
 	if err := c.cli.NetworkDisconnect(c.ctx, networkID, containerID, force); err != nil {
 		return fmt.Errorf("failed to disconnect container from network: %w", err)
 	}
@@ -529,36 +850,144 @@ func (c *Client) ListVolumesByPrefix(ctx context.Context, prefix string) ([]*vol
 	return matchedVolumes, nil
 }
 
+// ListManagedContainers lists the containers created by Doku: those labelled
+// as managed, and unlabelled ones from older versions named with the doku-
+// prefix
+func (c *Client) ListManagedContainers(ctx context.Context) ([]types.Container, error) {
+	containers, err := c.ListContainers(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var managed []types.Container
+	for _, ctr := range containers {
+		if IsDokuContainer(ctr.Labels) || (!isLabelled(ctr.Labels) && hasLegacyName(ctr.Names)) {
+			managed = append(managed, ctr)
+		}
+	}
+	return managed, nil
+}
+
+// ListManagedVolumes lists the volumes created by Doku, by label and, for
+// volumes from older versions, by the doku- name prefix
+func (c *Client) ListManagedVolumes(ctx context.Context) ([]*volume.Volume, error) {
+	volumes, err := c.ListVolumes(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var managed []*volume.Volume
+	for _, vol := range volumes {
+		if IsDokuContainer(vol.Labels) || (!isLabelled(vol.Labels) && hasLegacyName([]string{vol.Name})) {
+			managed = append(managed, vol)
+		}
+	}
+	return managed, nil
+}
+
+// ListInstanceVolumes lists the volumes of an instance or project. Labelled
+// volumes are matched on their instance label; unlabelled ones from older
+// versions on the doku-<instance>- name prefix.
+func (c *Client) ListInstanceVolumes(ctx context.Context, instanceName string) ([]*volume.Volume, error) {
+	volumes, err := c.ListVolumes(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	legacyPrefix := LegacyNamePrefix + instanceName + "-"
+	var matched []*volume.Volume
+	for _, vol := range volumes {
+		if owner, ok := vol.Labels[LabelInstance]; ok {
+			if owner == instanceName && workspace.Owns(vol.Labels) {
+				matched = append(matched, vol)
+			}
+			continue
+		}
+		if !isLabelled(vol.Labels) && workspace.Current() == "" && strings.HasPrefix(vol.Name, legacyPrefix) {
+			matched = append(matched, vol)
+		}
+	}
+	return matched, nil
+}
+
+// ListManagedImages lists the images Doku built
+func (c *Client) ListManagedImages(ctx context.Context) ([]image.Summary, error) {
+	filterArgs := filters.NewArgs()
+	filterArgs.Add("label", LabelManaged+"=true")
+
+	images, err := c.cli.ImageList(ctx, image.ListOptions{Filters: filterArgs})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list images: %w", err)
+	}
+	return images, nil
+}
+
+// hasLegacyName reports whether any of a container's names has the doku-
+// prefix. Unlabelled resources predate workspaces, so they only belong to
+// the default one.
+func hasLegacyName(names []string) bool {
+	if workspace.Current() != "" {
+		return false
+	}
+	for _, name := range names {
+		if strings.HasPrefix(strings.TrimPrefix(name, "/"), LegacyNamePrefix) {
+			return true
+		}
+	}
+	return false
+}
+
 // StopContainer stops a container by name or ID
 func (c *Client) StopContainer(ctx context.Context, containerID string) error {
+	if err := readonly.Check("stop container " + containerID); err != nil {
+		return err
+	}
+
 	timeout := 10 // 10 seconds timeout
 	return c.ContainerStop(containerID, &timeout)
 }
 
 // RemoveContainer removes a container by name or ID
 func (c *Client) RemoveContainer(ctx context.Context, containerID string) error {
+	if err := readonly.Check("remove container " + containerID); err != nil {
+		return err
+	}
+
 	return c.ContainerRemove(containerID, true)
 }
 
 // RemoveVolume removes a volume by name
 func (c *Client) RemoveVolume(ctx context.Context, volumeName string) error {
+	if err := readonly.Check("remove volume " + volumeName); err != nil {
+		return err
+	}
+
 	return c.VolumeRemove(volumeName, true)
 }
 
 // RemoveNetwork removes a network by name or ID
 func (c *Client) RemoveNetwork(ctx context.Context, networkID string) error {
+	if err := readonly.Check("remove network " + networkID); err != nil {
+		return err
+	}
+
 	return c.NetworkRemove(networkID)
 }
 
 // RunContainer creates and starts a container in one step (for init containers)
 func (c *Client) RunContainer(image, name string, cmd, env []string, network string, autoRemove bool) (string, error) {
+	if err := readonly.Check("run container " + name); err != nil {
+		return "", err
+	}
+
 	ctx := context.Background()
 
 	// Create container config
 	config := &container.Config{
-		Image: image,
-		Cmd:   cmd,
-		Env:   env,
+		Image:  image,
+		Cmd:    cmd,
+		Env:    env,
+		Labels: ManagedLabels(),
 	}
 
 	// Create host config
@@ -632,13 +1061,29 @@ type ExecOptions struct {
 	TTY         bool
 	User        string
 	WorkDir     string
+	Env         []string // Extra environment variables (KEY=VALUE)
 	Stdin       io.Reader
 	Stdout      io.Writer
 	Stderr      io.Writer
 }
 
-// Exec executes a command inside a running container
+// ExecExitError reports a command that ran but exited with a non-zero code
+type ExecExitError struct {
+	Code int
+}
+
+func (e *ExecExitError) Error() string {
+	return fmt.Sprintf("command exited with code %d", e.Code)
+}
+
+// Exec executes a command inside a running container. With a TTY and a
+// terminal on stdin, the local terminal is put in raw mode and the exec is
+// resized along with it.
 func (c *Client) Exec(ctx context.Context, opts ExecOptions) error {
+	if err := readonly.Check("run a command in container " + opts.Container); err != nil {
+		return err
+	}
+
 	// Create exec configuration
 	execConfig := container.ExecOptions{
 		AttachStdin:  opts.Interactive,
@@ -646,6 +1091,7 @@ func (c *Client) Exec(ctx context.Context, opts ExecOptions) error {
 		AttachStderr: true,
 		Tty:          opts.TTY,
 		Cmd:          opts.Command,
+		Env:          opts.Env,
 		User:         opts.User,
 		WorkingDir:   opts.WorkDir,
 	}
@@ -665,19 +1111,42 @@ func (c *Client) Exec(ctx context.Context, opts ExecOptions) error {
 	}
 	defer resp.Close()
 
+	// Raw mode and resizing only make sense when a terminal drives the exec
+	if fd, ok := terminalFd(opts.Stdin); ok && opts.TTY && opts.Interactive {
+		state, err := term.MakeRaw(fd)
+		if err != nil {
+			return fmt.Errorf("failed to set terminal to raw mode: %w", err)
+		}
+		defer term.Restore(fd, state)
+
+		resizeCtx, cancel := context.WithCancel(ctx)
+		defer cancel()
+		c.resizeExec(resizeCtx, execID.ID, fd)
+		go watchTerminalResize(resizeCtx, fd, func() {
+			c.resizeExec(resizeCtx, execID.ID, fd)
+		})
+	}
+
 	// Handle I/O
 	errCh := make(chan error, 1)
 
-	// Copy output
+	// Copy output. Without a TTY, stdout and stderr are multiplexed.
 	go func() {
-		_, err := io.Copy(opts.Stdout, resp.Reader)
+		var err error
+		if opts.TTY {
+			_, err = io.Copy(opts.Stdout, resp.Reader)
+		} else {
+			_, err = stdcopy.StdCopy(opts.Stdout, opts.Stderr, resp.Reader)
+		}
 		errCh <- err
 	}()
 
-	// Copy input if interactive
+	// Copy input if interactive, closing the write side on EOF so commands
+	// reading piped input finish
 	if opts.Interactive && opts.Stdin != nil {
 		go func() {
 			io.Copy(resp.Conn, opts.Stdin)
+			resp.CloseWrite()
 		}()
 	}
 
@@ -693,8 +1162,36 @@ func (c *Client) Exec(ctx context.Context, opts ExecOptions) error {
 	}
 
 	if inspectResp.ExitCode != 0 {
-		return fmt.Errorf("command exited with code %d", inspectResp.ExitCode)
+		return &ExecExitError{Code: inspectResp.ExitCode}
 	}
 
 	return nil
 }
+
+// resizeExec sets the exec's TTY size to the size of the local terminal
+func (c *Client) resizeExec(ctx context.Context, execID string, fd int) {
+	width, height, err := term.GetSize(fd)
+	if err != nil || width == 0 || height == 0 {
+		return
+	}
+	c.cli.ContainerExecResize(ctx, execID, container.ResizeOptions{
+		Height: uint(height),
+		Width:  uint(width),
+	})
+}
+
+// terminalFd returns the file descriptor of r if it is a terminal
+func terminalFd(r io.Reader) (int, bool) {
+	f, ok := r.(*os.File)
+	if !ok {
+		return 0, false
+	}
+	fd := int(f.Fd())
+	return fd, term.IsTerminal(fd)
+}
+
+// IsTerminal reports whether r is a terminal
+func IsTerminal(r io.Reader) bool {
+	_, ok := terminalFd(r)
+	return ok
+}
@@ -2,7 +2,10 @@ package docker
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"os"
+	"strings"
 	"testing"
 
 	"github.com/docker/docker/api/types/network"
@@ -368,6 +371,48 @@ func TestListVolumesByLabel(t *testing.T) {
 	}
 }
 
+// TestListInstanceVolumes tests that volumes are matched on their instance
+// label, and unlabelled ones on their name
+func TestListInstanceVolumes(t *testing.T) {
+	client := skipIfNoDocker(t)
+	if client == nil {
+		return
+	}
+	defer client.Close()
+
+	ctx := context.Background()
+	labelled := fmt.Sprintf("doku-test-labels-%d", os.Getpid())
+	legacy := "doku-" + labelled + "-legacy"
+	other := labelled + "-other"
+
+	if _, err := client.VolumeCreate(labelled, InstanceLabels(labelled)); err != nil {
+		t.Fatalf("VolumeCreate failed: %v", err)
+	}
+	defer client.VolumeRemove(labelled, true)
+	if _, err := client.VolumeCreate(legacy, nil); err != nil {
+		t.Fatalf("VolumeCreate failed: %v", err)
+	}
+	defer client.VolumeRemove(legacy, true)
+	// Named like the instance but labelled for another one
+	if _, err := client.VolumeCreate("doku-"+labelled+"-x", InstanceLabels(other)); err != nil {
+		t.Fatalf("VolumeCreate failed: %v", err)
+	}
+	defer client.VolumeRemove("doku-"+labelled+"-x", true)
+
+	volumes, err := client.ListInstanceVolumes(ctx, labelled)
+	if err != nil {
+		t.Fatalf("ListInstanceVolumes failed: %v", err)
+	}
+
+	names := map[string]bool{}
+	for _, vol := range volumes {
+		names[vol.Name] = true
+	}
+	if len(names) != 2 || !names[labelled] || !names[legacy] {
+		t.Errorf("expected %s and %s, got %v", labelled, legacy, names)
+	}
+}
+
 // Integration tests that create/modify Docker resources
 // These tests create actual Docker resources and clean them up
 
@@ -487,3 +532,36 @@ func TestNetworkCreateAndRemove(t *testing.T) {
 		t.Error("Removed network should not exist")
 	}
 }
+
+// TestExecExitError tests that exit codes survive error wrapping
+func TestExecExitError(t *testing.T) {
+	err := fmt.Errorf("exec failed: %w", &ExecExitError{Code: 3})
+
+	var exitErr *ExecExitError
+	if !errors.As(err, &exitErr) {
+		t.Fatal("errors.As should find the ExecExitError")
+	}
+	if exitErr.Code != 3 {
+		t.Errorf("Code = %d, expected 3", exitErr.Code)
+	}
+	if exitErr.Error() != "command exited with code 3" {
+		t.Errorf("Error() = %q", exitErr.Error())
+	}
+}
+
+// TestIsTerminal tests that pipes and plain readers aren't terminals
+func TestIsTerminal(t *testing.T) {
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("Failed to create pipe: %v", err)
+	}
+	defer r.Close()
+	defer w.Close()
+
+	if IsTerminal(r) {
+		t.Error("a pipe should not be a terminal")
+	}
+	if IsTerminal(strings.NewReader("input")) {
+		t.Error("a strings.Reader should not be a terminal")
+	}
+}
@@ -0,0 +1,86 @@
+package docker
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path"
+
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/pkg/archive"
+	"github.com/dokulabs/doku-cli/internal/readonly"
+)
+
+// CopyToContainer copies a file or directory from the host into a container,
+// following the same rules as docker cp: a destination ending in / must be
+// an existing directory, and a directory source is copied into an existing
+// directory destination.
+func (c *Client) CopyToContainer(ctx context.Context, containerID, srcPath, dstPath string) error {
+	if err := readonly.Check("copy files into container " + containerID); err != nil {
+		return err
+	}
+
+	// Resolve the destination, following a symlink like docker cp does
+	dstInfo := archive.CopyInfo{Path: dstPath}
+	dstStat, err := c.cli.ContainerStatPath(ctx, containerID, dstPath)
+	if err == nil && dstStat.Mode&os.ModeSymlink != 0 {
+		target := dstStat.LinkTarget
+		if !path.IsAbs(target) {
+			dstParent, _ := archive.SplitPathDirEntry(dstPath)
+			target = path.Join(dstParent, target)
+		}
+		dstInfo.Path = target
+		dstStat, err = c.cli.ContainerStatPath(ctx, containerID, target)
+	}
+	// A missing destination is fine as long as its parent exists, which the
+	// daemon checks
+	if err == nil {
+		dstInfo.Exists, dstInfo.IsDir = true, dstStat.Mode.IsDir()
+	}
+
+	srcInfo, err := archive.CopyInfoSourcePath(srcPath, false)
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", srcPath, err)
+	}
+
+	srcArchive, err := archive.TarResource(srcInfo)
+	if err != nil {
+		return fmt.Errorf("failed to archive %s: %w", srcPath, err)
+	}
+	defer srcArchive.Close()
+
+	dstDir, content, err := archive.PrepareArchiveCopy(srcArchive, srcInfo, dstInfo)
+	if err != nil {
+		return fmt.Errorf("failed to prepare copy: %w", err)
+	}
+	defer content.Close()
+
+	err = c.cli.CopyToContainer(ctx, containerID, dstDir, content, container.CopyToContainerOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to copy to container: %w", err)
+	}
+
+	return nil
+}
+
+// CopyFromContainer copies a file or directory from a container to the
+// host, following the same rules as docker cp
+func (c *Client) CopyFromContainer(ctx context.Context, containerID, srcPath, dstPath string) error {
+	content, stat, err := c.cli.CopyFromContainer(ctx, containerID, srcPath)
+	if err != nil {
+		return fmt.Errorf("failed to copy from container: %w", err)
+	}
+	defer content.Close()
+
+	srcInfo := archive.CopyInfo{
+		Path:   srcPath,
+		Exists: true,
+		IsDir:  stat.Mode.IsDir(),
+	}
+
+	if err := archive.CopyTo(content, srcInfo, dstPath); err != nil {
+		return fmt.Errorf("failed to write %s: %w", dstPath, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,266 @@
+package docker
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/distribution/reference"
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/image"
+)
+
+// DiskUsage is the disk space taken up by the resources of an instance,
+// project or Doku component
+type DiskUsage struct {
+	Owner      string
+	Images     int64 // Images its containers run, layers shared with other images included
+	Containers int64 // Writable layers of its containers
+	Volumes    int64
+	Logs       int64 // -1 when the log files can't be read, e.g. with Docker in a VM
+}
+
+// DiskReport is the disk usage of Doku's resources, per owner
+type DiskReport struct {
+	Owners []DiskUsage
+	Total  DiskUsage // Images used by several owners counted once
+}
+
+// DiskUsage reports the disk space Doku's containers, their images and
+// volumes take up, like 'docker system df' scoped to Doku's resources.
+// instances are the names of the instances and projects, to attribute
+// resources of older versions, which only have names, to.
+func (c *Client) DiskUsage(ctx context.Context, instances []string) (DiskReport, error) {
+	du, err := c.cli.DiskUsage(ctx, types.DiskUsageOptions{})
+	if err != nil {
+		return DiskReport{}, fmt.Errorf("failed to get disk usage: %w", err)
+	}
+	return summarizeDiskUsage(du, instances, c.logSize), nil
+}
+
+// logSize returns the size of a container's log file, or -1 when it can't
+// be read
+func (c *Client) logSize(containerID string) int64 {
+	inspect, err := c.cli.ContainerInspect(c.ctx, containerID)
+	if err != nil || inspect.LogPath == "" {
+		return -1
+	}
+	info, err := os.Stat(inspect.LogPath)
+	if err != nil {
+		return -1
+	}
+	return info.Size()
+}
+
+// summarizeDiskUsage sums the disk usage of Doku's resources per owner,
+// sorted by owner
+func summarizeDiskUsage(du types.DiskUsage, instances []string, logSize func(containerID string) int64) DiskReport {
+	imageSizes := make(map[string]int64, len(du.Images))
+	for _, img := range du.Images {
+		imageSizes[img.ID] = img.Size
+	}
+
+	owners := make(map[string]*DiskUsage)
+	ownerOf := func(name string) *DiskUsage {
+		usage, ok := owners[name]
+		if !ok {
+			usage = &DiskUsage{Owner: name}
+			owners[name] = usage
+		}
+		return usage
+	}
+
+	ownerImages := make(map[string]map[string]bool)
+	allImages := make(map[string]bool)
+	var total DiskUsage
+	for _, ctr := range du.Containers {
+		if !isManaged(ctr.Labels, ctr.Names) {
+			continue
+		}
+		name := resourceOwner(ctr.Labels, ctr.Names, instances)
+		usage := ownerOf(name)
+
+		usage.Containers += ctr.SizeRw
+		total.Containers += ctr.SizeRw
+
+		if ownerImages[name] == nil {
+			ownerImages[name] = make(map[string]bool)
+		}
+		if !ownerImages[name][ctr.ImageID] {
+			ownerImages[name][ctr.ImageID] = true
+			usage.Images += imageSizes[ctr.ImageID]
+		}
+		if !allImages[ctr.ImageID] {
+			allImages[ctr.ImageID] = true
+			total.Images += imageSizes[ctr.ImageID]
+		}
+
+		size := logSize(ctr.ID)
+		if size < 0 || usage.Logs < 0 {
+			usage.Logs = -1
+		} else {
+			usage.Logs += size
+		}
+		if size < 0 || total.Logs < 0 {
+			total.Logs = -1
+		} else {
+			total.Logs += size
+		}
+	}
+
+	for _, vol := range du.Volumes {
+		if !isManaged(vol.Labels, []string{vol.Name}) {
+			continue
+		}
+		// The size is -1 when the daemon couldn't compute it
+		if vol.UsageData == nil || vol.UsageData.Size < 0 {
+			ownerOf(resourceOwner(vol.Labels, []string{vol.Name}, instances))
+			continue
+		}
+		ownerOf(resourceOwner(vol.Labels, []string{vol.Name}, instances)).Volumes += vol.UsageData.Size
+		total.Volumes += vol.UsageData.Size
+	}
+
+	report := DiskReport{Total: total}
+	for _, usage := range owners {
+		report.Owners = append(report.Owners, *usage)
+	}
+	sort.Slice(report.Owners, func(i, j int) bool {
+		return report.Owners[i].Owner < report.Owners[j].Owner
+	})
+	return report
+}
+
+// isManaged reports whether a container or volume was created by Doku, in
+// the current workspace
+func isManaged(labels map[string]string, names []string) bool {
+	return IsDokuContainer(labels) || (!isLabelled(labels) && hasLegacyName(names))
+}
+
+// resourceOwner returns the instance, project or Doku component a resource
+// belongs to: from its labels, or for resources of older versions, from a
+// doku-<instance> or doku-<instance>-<container> name
+func resourceOwner(labels map[string]string, names []string, instances []string) string {
+	if owner := labels[LabelInstance]; owner != "" {
+		return owner
+	}
+	if component := labels[LabelComponent]; component != "" {
+		return component
+	}
+
+	name := strings.TrimPrefix(strings.TrimPrefix(firstName(names), "/"), LegacyNamePrefix)
+	owner := ""
+	for _, instance := range instances {
+		if (name == instance || strings.HasPrefix(name, instance+"-")) && len(instance) > len(owner) {
+			owner = instance
+		}
+	}
+	if owner == "" {
+		return name
+	}
+	return owner
+}
+
+// firstName returns the first of a resource's names, or ""
+func firstName(names []string) string {
+	if len(names) == 0 {
+		return ""
+	}
+	return names[0]
+}
+
+// UnusedImages returns the local images left behind by image changes, such
+// as upgrades: images of the repositories of keep and of Doku's containers
+// that neither keep nor any container, Doku's or not, uses. keep lists
+// references or IDs of images that must stay, e.g. those rollback restores.
+func (c *Client) UnusedImages(ctx context.Context, keep []string) ([]image.Summary, error) {
+	images, err := c.ImageList()
+	if err != nil {
+		return nil, err
+	}
+	containers, err := c.ListContainers(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	repos := append([]string(nil), keep...)
+	inUse := append([]string(nil), keep...)
+	for _, ref := range keep {
+		if mirrored := MirrorImage(ref); mirrored != ref {
+			inUse = append(inUse, mirrored)
+		}
+	}
+	for _, ctr := range containers {
+		inUse = append(inUse, ctr.ImageID, ctr.Image)
+		if isManaged(ctr.Labels, ctr.Names) {
+			repos = append(repos, ctr.Image)
+		}
+	}
+	return unusedImages(images, repos, inUse), nil
+}
+
+// unusedImages returns the images of the repositories of repos that no
+// reference or ID of inUse names
+func unusedImages(images []image.Summary, repos []string, inUse []string) []image.Summary {
+	repoSet := make(map[string]bool)
+	for _, ref := range repos {
+		if repo := repositoryName(ref); repo != "" {
+			repoSet[repo] = true
+		}
+	}
+	used := make(map[string]bool)
+	for _, ref := range inUse {
+		used[ref] = true
+		used[normalizeRef(ref)] = true
+	}
+
+	var unused []image.Summary
+	for _, img := range images {
+		if used[img.ID] {
+			continue
+		}
+		refs := append(append([]string(nil), img.RepoTags...), img.RepoDigests...)
+		inRepo, isUsed := false, false
+		for _, ref := range refs {
+			if repoSet[repositoryName(ref)] {
+				inRepo = true
+			}
+			if used[normalizeRef(ref)] {
+				isUsed = true
+			}
+		}
+		if inRepo && !isUsed {
+			unused = append(unused, img)
+		}
+	}
+	return unused
+}
+
+// repositoryName returns the repository of an image reference, e.g.
+// "postgres:16" -> "docker.io/library/postgres", or "" for an image ID
+func repositoryName(ref string) string {
+	if ref == "" || strings.HasPrefix(ref, "sha256:") {
+		return ""
+	}
+	named, err := reference.ParseNormalizedNamed(ref)
+	if err != nil {
+		return ""
+	}
+	return named.Name()
+}
+
+// normalizeRef returns the full form of an image reference, e.g.
+// "postgres" -> "docker.io/library/postgres:latest", so that references
+// to the same image compare equal
+func normalizeRef(ref string) string {
+	if ref == "" || strings.HasPrefix(ref, "sha256:") {
+		return ref
+	}
+	named, err := reference.ParseNormalizedNamed(ref)
+	if err != nil {
+		return ref
+	}
+	return reference.TagNameOnly(named).String()
+}
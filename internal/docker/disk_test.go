@@ -0,0 +1,117 @@
+package docker
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/image"
+	"github.com/docker/docker/api/types/volume"
+)
+
+func TestSummarizeDiskUsage(t *testing.T) {
+	du := types.DiskUsage{
+		Images: []*image.Summary{
+			{ID: "sha256:pg", Size: 400},
+			{ID: "sha256:redis", Size: 100},
+			{ID: "sha256:traefik", Size: 150},
+		},
+		Containers: []*container.Summary{
+			{ID: "c1", ImageID: "sha256:pg", SizeRw: 10, Labels: InstanceLabels("db")},
+			{ID: "c2", ImageID: "sha256:pg", SizeRw: 5, Labels: InstanceLabels("db")},
+			{ID: "c3", ImageID: "sha256:pg", SizeRw: 1, Labels: InstanceLabels("analytics")},
+			{ID: "c4", ImageID: "sha256:traefik", SizeRw: 2, Labels: ComponentLabels("traefik")},
+			// Created by an older version: attributed by name
+			{ID: "c5", ImageID: "sha256:redis", SizeRw: 3, Names: []string{"/doku-cache-redis"}},
+			// Not Doku's
+			{ID: "c6", ImageID: "sha256:redis", SizeRw: 99, Names: []string{"/other"}},
+		},
+		Volumes: []*volume.Volume{
+			{Name: "doku-db-data", Labels: InstanceLabels("db"), UsageData: &volume.UsageData{Size: 1000}},
+			{Name: "doku-cache-data", UsageData: &volume.UsageData{Size: 50}},
+			{Name: "doku-analytics-data", Labels: InstanceLabels("analytics"), UsageData: &volume.UsageData{Size: -1}},
+			{Name: "other", UsageData: &volume.UsageData{Size: 77}},
+		},
+	}
+	logs := map[string]int64{"c1": 20, "c2": 30, "c3": 1, "c4": -1, "c5": 4}
+	report := summarizeDiskUsage(du, []string{"db", "analytics", "cache"}, func(id string) int64 { return logs[id] })
+
+	want := []DiskUsage{
+		{Owner: "analytics", Images: 400, Containers: 1, Logs: 1},
+		{Owner: "cache", Images: 100, Containers: 3, Volumes: 50, Logs: 4},
+		{Owner: "db", Images: 400, Containers: 15, Volumes: 1000, Logs: 50},
+		{Owner: "traefik", Images: 150, Containers: 2, Logs: -1},
+	}
+	if len(report.Owners) != len(want) {
+		t.Fatalf("owners = %+v, want %+v", report.Owners, want)
+	}
+	for n := range want {
+		if report.Owners[n] != want[n] {
+			t.Errorf("owner %d = %+v, want %+v", n, report.Owners[n], want[n])
+		}
+	}
+
+	// The postgres image is counted once, and one unreadable log makes the
+	// total unknown
+	total := DiskUsage{Images: 650, Containers: 21, Volumes: 1050, Logs: -1}
+	if report.Total != total {
+		t.Errorf("total = %+v, want %+v", report.Total, total)
+	}
+}
+
+func TestResourceOwner(t *testing.T) {
+	instances := []string{"api", "api-db"}
+	tests := []struct {
+		labels map[string]string
+		names  []string
+		want   string
+	}{
+		{InstanceLabels("web"), []string{"/doku-api"}, "web"},
+		{ComponentLabels("traefik"), []string{"/doku-traefik"}, "traefik"},
+		{nil, []string{"/doku-api"}, "api"},
+		{nil, []string{"/doku-api-worker"}, "api"},
+		{nil, []string{"/doku-api-db-primary"}, "api-db"},
+		{nil, []string{"doku-unknown"}, "unknown"},
+	}
+	for _, tt := range tests {
+		if got := resourceOwner(tt.labels, tt.names, instances); got != tt.want {
+			t.Errorf("resourceOwner(%v, %v) = %q, want %q", tt.labels, tt.names, got, tt.want)
+		}
+	}
+}
+
+func TestUnusedImages(t *testing.T) {
+	oldDigest := "sha256:" + strings.Repeat("a", 64)
+	pinnedDigest := "sha256:" + strings.Repeat("b", 64)
+	images := []image.Summary{
+		{ID: "sha256:pg16", RepoTags: []string{"postgres:16"}},
+		{ID: "sha256:pg15", RepoTags: []string{"postgres:15"}},
+		{ID: "sha256:pg14", RepoTags: []string{"postgres:14"}},
+		// The old image of a moved tag
+		{ID: "sha256:pgold", RepoDigests: []string{"postgres@" + oldDigest}},
+		// Used by a container that isn't Doku's
+		{ID: "sha256:pg13", RepoTags: []string{"postgres:13"}},
+		// Pinned by digest
+		{ID: "sha256:redis", RepoDigests: []string{"redis@" + pinnedDigest}},
+		{ID: "sha256:redisold", RepoTags: []string{"redis:6"}},
+		// A repository Doku doesn't use
+		{ID: "sha256:nginx", RepoTags: []string{"nginx:latest"}},
+	}
+	repos := []string{"postgres:16", "docker.io/library/redis@" + pinnedDigest}
+	inUse := []string{"sha256:pg16", "docker.io/library/postgres:14", "postgres:13", "redis@" + pinnedDigest}
+
+	var got []string
+	for _, img := range unusedImages(images, repos, inUse) {
+		got = append(got, img.ID)
+	}
+	want := []string{"sha256:pg15", "sha256:pgold", "sha256:redisold"}
+	if len(got) != len(want) {
+		t.Fatalf("unusedImages() = %v, want %v", got, want)
+	}
+	for n := range want {
+		if got[n] != want[n] {
+			t.Errorf("unusedImages()[%d] = %s, want %s", n, got[n], want[n])
+		}
+	}
+}
@@ -0,0 +1,254 @@
+package docker
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/mount"
+)
+
+// Engines Doku runs containers with. Both are driven through the Docker
+// API, which Podman serves too.
+const (
+	EngineDocker = "docker"
+	EnginePodman = "podman"
+)
+
+// engineEnvVar chooses the engine, over the preferences
+const engineEnvVar = "DOKU_ENGINE"
+
+// dockerSocket is where Docker serves its API on Linux and macOS, and
+// where containers watching the others find it
+const dockerSocket = "/var/run/docker.sock"
+
+// podmanMachineSocket is where a rootful Podman machine serves the API,
+// inside the machine
+const podmanMachineSocket = "/run/podman/podman.sock"
+
+// Engine is the container engine behind the Docker API: what differs
+// between Docker and Podman when Doku creates containers
+type Engine interface {
+	// Name is EngineDocker or EnginePodman
+	Name() string
+
+	// Host returns the address of the engine's API, "" for the Docker
+	// SDK's default
+	Host() string
+
+	// SocketPath returns the path of the API socket where the containers
+	// run, e.g. inside Podman's machine, for Traefik to watch them
+	SocketPath() string
+
+	// SocketSecurityOpt returns the security options letting a container
+	// use the API socket
+	SocketSecurityOpt() []string
+
+	// ExtraHosts returns the hosts entries a container needs to reach the
+	// machine as host.docker.internal
+	ExtraHosts() []string
+
+	// PortWarning says why the engine may fail to publish a port, "" if
+	// it shouldn't
+	PortWarning(port int) string
+
+	// StartHint tells how to start the engine when it doesn't answer
+	StartHint() string
+}
+
+var (
+	engineMu   sync.Mutex
+	engineName string // Configured with SetEngine, "" to detect it
+)
+
+// SetEngine sets the engine from the preferences, "" to detect it.
+// DOKU_ENGINE overrides it.
+func SetEngine(name string) {
+	engineMu.Lock()
+	defer engineMu.Unlock()
+	engineName = name
+}
+
+// ValidateEngine checks an engine is one Doku supports
+func ValidateEngine(name string) error {
+	if name != EngineDocker && name != EnginePodman {
+		return fmt.Errorf("invalid engine '%s': expected %s or %s", name, EngineDocker, EnginePodman)
+	}
+	return nil
+}
+
+// CurrentEngine returns the engine Doku uses: DOKU_ENGINE's, the
+// configured one, or the one detected
+func CurrentEngine() Engine {
+	engineMu.Lock()
+	name := engineName
+	engineMu.Unlock()
+	if env := os.Getenv(engineEnvVar); env != "" {
+		name = env
+	}
+	return newEngine(name, hostEnv{getenv: os.Getenv, exists: fileExists, goos: runtime.GOOS})
+}
+
+// UseEngineSocket points the bind mounts of Docker's socket, which the
+// catalog's services watching containers ask for, at the engine's
+func UseEngineSocket(engine Engine, hostConfig *container.HostConfig) {
+	mounted := false
+	for idx, m := range hostConfig.Mounts {
+		if m.Type == mount.TypeBind && m.Source == dockerSocket {
+			hostConfig.Mounts[idx].Source = engine.SocketPath()
+			mounted = true
+		}
+	}
+	if mounted {
+		hostConfig.SecurityOpt = append(hostConfig.SecurityOpt, engine.SocketSecurityOpt()...)
+	}
+}
+
+// hostEnv is what engine detection looks at, so tests can fake it
+type hostEnv struct {
+	getenv func(string) string
+	exists func(string) bool
+	goos   string
+}
+
+// newEngine returns the named engine, or detects it: DOCKER_HOST or
+// Docker's socket mean Docker, Podman's socket Podman
+func newEngine(name string, env hostEnv) Engine {
+	switch name {
+	case EngineDocker:
+		return dockerEngine{}
+	case EnginePodman:
+		return podmanEngine{host: podmanHost(env), goos: env.goos}
+	}
+
+	if host := env.getenv("DOCKER_HOST"); host != "" {
+		if strings.Contains(host, "podman") {
+			return podmanEngine{goos: env.goos}
+		}
+		return dockerEngine{}
+	}
+	if env.goos == "windows" || env.exists(dockerSocket) {
+		return dockerEngine{}
+	}
+	if host := podmanHost(env); host != "" && env.exists(strings.TrimPrefix(host, "unix://")) {
+		return podmanEngine{host: host, goos: env.goos}
+	}
+	return dockerEngine{}
+}
+
+// dockerEngine is Docker Engine or Docker Desktop
+type dockerEngine struct{}
+
+func (dockerEngine) Name() string                { return EngineDocker }
+func (dockerEngine) Host() string                { return "" }
+func (dockerEngine) SocketPath() string          { return dockerSocket }
+func (dockerEngine) SocketSecurityOpt() []string { return nil }
+func (dockerEngine) PortWarning(int) string      { return "" }
+
+func (dockerEngine) ExtraHosts() []string {
+	// Lets Linux hosts reach processes on the host, as Docker Desktop does
+	return []string{"host.docker.internal:host-gateway"}
+}
+
+func (dockerEngine) StartHint() string {
+	return "start Docker Desktop, or the Docker service"
+}
+
+// podmanEngine is Podman through its Docker-compatible API: on Linux its
+// socket, rootless or not, elsewhere that of its machine
+type podmanEngine struct {
+	host string // "" when DOCKER_HOST points at it
+	goos string
+}
+
+func (e podmanEngine) Name() string { return EnginePodman }
+func (e podmanEngine) Host() string { return e.host }
+
+func (e podmanEngine) SocketPath() string {
+	if e.goos != "linux" {
+		return podmanMachineSocket
+	}
+	if path, ok := strings.CutPrefix(e.host, "unix://"); ok {
+		return path
+	}
+	return podmanMachineSocket
+}
+
+// SocketSecurityOpt turns SELinux labeling off for the container, which
+// could otherwise not open the socket on Fedora and RHEL
+func (e podmanEngine) SocketSecurityOpt() []string {
+	return []string{"label=disable"}
+}
+
+// ExtraHosts is empty: Podman adds host.docker.internal itself, and older
+// versions reject host-gateway
+func (e podmanEngine) ExtraHosts() []string {
+	return nil
+}
+
+// PortWarning tells rootless Podman on Linux can't publish the ports below
+// net.ipv4.ip_unprivileged_port_start
+func (e podmanEngine) PortWarning(port int) string {
+	if e.goos != "linux" || os.Geteuid() == 0 || strings.HasPrefix(e.SocketPath(), "/run/podman/") {
+		return ""
+	}
+	data, err := os.ReadFile("/proc/sys/net/ipv4/ip_unprivileged_port_start")
+	if err != nil {
+		return ""
+	}
+	start, err := strconv.Atoi(strings.TrimSpace(string(data)))
+	if err != nil || port >= start {
+		return ""
+	}
+	return fmt.Sprintf("rootless Podman can't publish port %d, below %d: run 'sudo sysctl net.ipv4.ip_unprivileged_port_start=%d', or use other ports with 'doku init --http-port 8080 --https-port 8443'", port, start, port)
+}
+
+func (e podmanEngine) StartHint() string {
+	if e.goos == "linux" {
+		return "start Podman's socket with 'systemctl --user enable --now podman.socket'"
+	}
+	return "start Podman's machine with 'podman machine start'"
+}
+
+// podmanHost returns the address of Podman's API: CONTAINER_HOST, the
+// socket of the user or of root on Linux, or that of the machine
+func podmanHost(env hostEnv) string {
+	if host := env.getenv("CONTAINER_HOST"); host != "" {
+		return host
+	}
+
+	switch env.goos {
+	case "linux":
+		if dir := env.getenv("XDG_RUNTIME_DIR"); dir != "" {
+			if path := filepath.Join(dir, "podman", "podman.sock"); env.exists(path) {
+				return "unix://" + path
+			}
+		}
+		return "unix://" + podmanMachineSocket
+	case "windows":
+		return "npipe:////./pipe/podman-machine-default"
+	}
+
+	// The machine's socket is forwarded to one on the host, whose path
+	// the machine knows
+	out, err := exec.Command("podman", "machine", "inspect", "--format", "{{.ConnectionInfo.PodmanSocket.Path}}").Output()
+	if err != nil {
+		return ""
+	}
+	path := strings.TrimSpace(strings.SplitN(string(out), "\n", 2)[0])
+	if path == "" || path == "<no value>" {
+		return ""
+	}
+	return "unix://" + path
+}
+
+func fileExists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
@@ -0,0 +1,88 @@
+package docker
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/mount"
+)
+
+// fakeHost returns a host with the given environment and files
+func fakeHost(goos string, env map[string]string, files ...string) hostEnv {
+	return hostEnv{
+		getenv: func(key string) string { return env[key] },
+		exists: func(path string) bool {
+			for _, file := range files {
+				if file == path {
+					return true
+				}
+			}
+			return false
+		},
+		goos: goos,
+	}
+}
+
+func TestNewEngine(t *testing.T) {
+	tests := []struct {
+		name       string
+		configured string
+		host       hostEnv
+		want       string
+		wantHost   string
+		wantSocket string
+	}{
+		{"docker socket", "", fakeHost("linux", nil, dockerSocket, "/run/user/1000/podman/podman.sock"), EngineDocker, "", dockerSocket},
+		{"rootless podman", "", fakeHost("linux", map[string]string{"XDG_RUNTIME_DIR": "/run/user/1000"}, "/run/user/1000/podman/podman.sock"), EnginePodman, "unix:///run/user/1000/podman/podman.sock", "/run/user/1000/podman/podman.sock"},
+		{"rootful podman", "", fakeHost("linux", nil, podmanMachineSocket), EnginePodman, "unix://" + podmanMachineSocket, podmanMachineSocket},
+		{"DOCKER_HOST", "", fakeHost("linux", map[string]string{"DOCKER_HOST": "tcp://10.0.0.5:2375"}, podmanMachineSocket), EngineDocker, "", dockerSocket},
+		{"DOCKER_HOST at podman", "", fakeHost("linux", map[string]string{"DOCKER_HOST": "unix:///run/podman/podman.sock"}), EnginePodman, "", podmanMachineSocket},
+		{"CONTAINER_HOST", "podman", fakeHost("darwin", map[string]string{"CONTAINER_HOST": "unix:///tmp/podman.sock"}), EnginePodman, "unix:///tmp/podman.sock", podmanMachineSocket},
+		{"nothing", "", fakeHost("linux", nil), EngineDocker, "", dockerSocket},
+		{"configured", "docker", fakeHost("linux", nil, podmanMachineSocket), EngineDocker, "", dockerSocket},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			engine := newEngine(tt.configured, tt.host)
+			if engine.Name() != tt.want || engine.Host() != tt.wantHost || engine.SocketPath() != tt.wantSocket {
+				t.Errorf("newEngine() = %s at %q, socket %q, want %s at %q, socket %q", engine.Name(), engine.Host(), engine.SocketPath(), tt.want, tt.wantHost, tt.wantSocket)
+			}
+		})
+	}
+}
+
+func TestValidateEngine(t *testing.T) {
+	for _, name := range []string{EngineDocker, EnginePodman} {
+		if err := ValidateEngine(name); err != nil {
+			t.Errorf("ValidateEngine(%q) error = %v", name, err)
+		}
+	}
+	if err := ValidateEngine("containerd"); err == nil {
+		t.Error("ValidateEngine() should fail for an unknown engine")
+	}
+}
+
+func TestUseEngineSocket(t *testing.T) {
+	engine := podmanEngine{host: "unix:///run/user/1000/podman/podman.sock", goos: "linux"}
+	hostConfig := &container.HostConfig{Mounts: []mount.Mount{
+		{Type: mount.TypeBind, Source: dockerSocket, Target: dockerSocket, ReadOnly: true},
+		{Type: mount.TypeVolume, Source: "dozzle-data", Target: "/data"},
+	}}
+	UseEngineSocket(engine, hostConfig)
+	if got := hostConfig.Mounts[0].Source; got != "/run/user/1000/podman/podman.sock" {
+		t.Errorf("socket mounted from %q, want Podman's", got)
+	}
+	if hostConfig.Mounts[1].Source != "dozzle-data" {
+		t.Error("other mounts should be left alone")
+	}
+	if !reflect.DeepEqual(hostConfig.SecurityOpt, []string{"label=disable"}) {
+		t.Errorf("SecurityOpt = %v, want SELinux labeling off", hostConfig.SecurityOpt)
+	}
+
+	hostConfig = &container.HostConfig{Mounts: []mount.Mount{{Type: mount.TypeBind, Source: dockerSocket, Target: dockerSocket}}}
+	UseEngineSocket(dockerEngine{}, hostConfig)
+	if hostConfig.Mounts[0].Source != dockerSocket || hostConfig.SecurityOpt != nil {
+		t.Errorf("Docker's socket should be mounted as is, got %+v", hostConfig)
+	}
+}
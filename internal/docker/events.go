@@ -0,0 +1,84 @@
+package docker
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/docker/docker/api/types/events"
+	"github.com/docker/docker/api/types/filters"
+)
+
+// ContainerEvent is a change of state of a Doku container reported by Docker
+type ContainerEvent struct {
+	ID       string
+	Name     string
+	Action   string // e.g. "start", "die", "oom", "restart" or "health_status"
+	ExitCode int    // Exit code of "die" events
+	Health   string // Health of "health_status" events, e.g. "unhealthy"
+	Time     time.Time
+}
+
+// containerEventActions are the container events changing their state
+var containerEventActions = []events.Action{
+	events.ActionStart, events.ActionRestart, events.ActionStop, events.ActionKill,
+	events.ActionDie, events.ActionOOM, events.ActionDestroy, events.ActionPause,
+	events.ActionUnPause, events.ActionHealthStatus,
+}
+
+// ContainerEvents streams the state changes of Doku's containers until ctx
+// is done. The error channel receives a value when the stream breaks,
+// e.g. because the Docker daemon stopped.
+func (c *Client) ContainerEvents(ctx context.Context) (<-chan ContainerEvent, <-chan error) {
+	filterArgs := filters.NewArgs(filters.Arg("type", string(events.ContainerEventType)))
+	for _, action := range containerEventActions {
+		filterArgs.Add("event", string(action))
+	}
+
+	messages, errs := c.cli.Events(ctx, events.ListOptions{Filters: filterArgs})
+
+	out := make(chan ContainerEvent)
+	go func() {
+		defer close(out)
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case msg := <-messages:
+				e, ok := containerEvent(msg)
+				if !ok {
+					continue
+				}
+				select {
+				case out <- e:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+	return out, errs
+}
+
+// containerEvent converts the event of a Doku container
+func containerEvent(msg events.Message) (ContainerEvent, bool) {
+	attrs := msg.Actor.Attributes
+	name := attrs["name"]
+	if !IsDokuContainer(attrs) && (isLabelled(attrs) || !hasLegacyName([]string{name})) {
+		return ContainerEvent{}, false
+	}
+
+	exitCode, _ := strconv.Atoi(attrs["exitCode"])
+	// Health checks report as e.g. "health_status: healthy"
+	action, health, _ := strings.Cut(string(msg.Action), ": ")
+
+	return ContainerEvent{
+		ID:       msg.Actor.ID,
+		Name:     name,
+		Action:   action,
+		ExitCode: exitCode,
+		Health:   health,
+		Time:     time.Unix(0, msg.TimeNano),
+	}, true
+}
@@ -0,0 +1,42 @@
+package docker
+
+import (
+	"testing"
+
+	"github.com/docker/docker/api/types/events"
+)
+
+func TestContainerEvent(t *testing.T) {
+	msg := events.Message{
+		Type:   events.ContainerEventType,
+		Action: events.ActionDie,
+		Actor: events.Actor{
+			ID:         "abc123",
+			Attributes: map[string]string{"name": "postgres-16", LabelManaged: "true", "exitCode": "137"},
+		},
+		TimeNano: 1_700_000_000_000_000_000,
+	}
+
+	e, ok := containerEvent(msg)
+	if !ok {
+		t.Fatal("containerEvent should accept Doku containers")
+	}
+	if e.ID != "abc123" || e.Name != "postgres-16" || e.Action != "die" || e.ExitCode != 137 || e.Time.Unix() != 1_700_000_000 {
+		t.Errorf("containerEvent() = %+v", e)
+	}
+
+	msg.Action = events.ActionHealthStatusUnhealthy
+	if e, _ := containerEvent(msg); e.Action != "health_status" || e.Health != "unhealthy" {
+		t.Errorf("containerEvent() = %+v, expected an unhealthy health_status event", e)
+	}
+
+	msg.Actor.Attributes = map[string]string{"name": "other"}
+	if _, ok := containerEvent(msg); ok {
+		t.Error("containerEvent should skip containers not managed by Doku")
+	}
+
+	msg.Actor.Attributes = map[string]string{"name": LegacyNamePrefix + "redis"}
+	if _, ok := containerEvent(msg); !ok {
+		t.Error("containerEvent should accept legacy Doku containers")
+	}
+}
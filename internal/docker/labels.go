@@ -2,8 +2,82 @@ package docker
 
 import (
 	"fmt"
+	"strings"
+
+	"github.com/dokulabs/doku-cli/internal/workspace"
 )
 
+// Labels Doku puts on the containers, volumes, networks and images it
+// creates, so they can be found with label filters whatever their names
+const (
+	LabelManaged   = "doku.managed"   // "true" on every Doku resource
+	LabelInstance  = "doku.instance"  // Instance or project the resource belongs to
+	LabelComponent = "doku.component" // Doku infrastructure, e.g. "traefik"
+
+	// LabelLegacyManagedBy marks resources created by older versions
+	LabelLegacyManagedBy = "managed-by"
+
+	// LegacyNamePrefix is how resources were recognised before labels
+	LegacyNamePrefix = "doku-"
+)
+
+// ManagedLabels returns the labels of a resource Doku created, with the
+// workspace it belongs to
+func ManagedLabels() map[string]string {
+	return MergeLabels(map[string]string{
+		LabelManaged:         "true",
+		LabelLegacyManagedBy: "doku",
+	}, workspace.Labels())
+}
+
+// InstanceLabels returns the labels of a resource belonging to an instance
+// or project
+func InstanceLabels(instanceName string) map[string]string {
+	labels := ManagedLabels()
+	labels[LabelInstance] = instanceName
+	return labels
+}
+
+// ComponentLabels returns the labels of Doku's own infrastructure
+func ComponentLabels(component string) map[string]string {
+	labels := ManagedLabels()
+	labels[LabelComponent] = component
+	return labels
+}
+
+// reservedLabelPrefixes are label namespaces Doku manages itself
+var reservedLabelPrefixes = []string{"doku.", "traefik."}
+
+// ValidateExtraLabel checks that a user-supplied label doesn't clash with
+// the labels Doku sets itself
+func ValidateExtraLabel(key string) error {
+	if key == "" {
+		return fmt.Errorf("label key cannot be empty")
+	}
+	if strings.ContainsAny(key, " \t=") {
+		return fmt.Errorf("invalid label key %q", key)
+	}
+	if key == LabelLegacyManagedBy {
+		return fmt.Errorf("label %q is reserved for Doku", key)
+	}
+	for _, prefix := range reservedLabelPrefixes {
+		if strings.HasPrefix(key, prefix) {
+			return fmt.Errorf("label %q is reserved for Doku (%s*)", key, prefix)
+		}
+	}
+	return nil
+}
+
+// ValidateExtraLabels checks every key of labels with ValidateExtraLabel
+func ValidateExtraLabels(labels map[string]string) error {
+	for key := range labels {
+		if err := ValidateExtraLabel(key); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // TraefikLabels holds Traefik routing configuration
 type TraefikLabels struct {
 	Enabled     bool
@@ -76,7 +150,7 @@ func GenerateTraefikLabels(config TraefikLabels) map[string]string {
 	labels[fmt.Sprintf("%s.loadbalancer.server.port", servicePrefix)] = fmt.Sprintf("%d", config.Port)
 
 	// Docker network (Traefik will use this network)
-	labels["traefik.docker.network"] = "doku-network"
+	labels["traefik.docker.network"] = NetworkName()
 
 	return labels
 }
@@ -136,12 +210,10 @@ func GenerateTraefikLabelsForDashboard(domain string, useTLS bool) map[string]st
 
 // GenerateDokuManagedLabels generates common labels for Doku-managed containers
 func GenerateDokuManagedLabels(instanceName, serviceType, version string) map[string]string {
-	return map[string]string{
-		"managed-by":           "doku",
-		"doku.instance":        instanceName,
+	return MergeLabels(InstanceLabels(instanceName), map[string]string{
 		"doku.service.type":    serviceType,
 		"doku.service.version": version,
-	}
+	})
 }
 
 // MergeLabels merges multiple label maps into one
@@ -0,0 +1,93 @@
+package docker
+
+import (
+	"testing"
+
+	"github.com/dokulabs/doku-cli/internal/workspace"
+)
+
+func TestInstanceLabels(t *testing.T) {
+	labels := InstanceLabels("postgres-16")
+
+	if labels[LabelManaged] != "true" {
+		t.Errorf("expected %s=true, got %q", LabelManaged, labels[LabelManaged])
+	}
+	if labels[LabelInstance] != "postgres-16" {
+		t.Errorf("expected %s=postgres-16, got %q", LabelInstance, labels[LabelInstance])
+	}
+	if !IsDokuContainer(labels) {
+		t.Error("expected instance labels to mark the resource as managed")
+	}
+	if ExtractInstanceName(labels) != "postgres-16" {
+		t.Errorf("ExtractInstanceName() = %q", ExtractInstanceName(labels))
+	}
+}
+
+func TestIsDokuContainer(t *testing.T) {
+	tests := []struct {
+		name   string
+		labels map[string]string
+		want   bool
+	}{
+		{"managed", map[string]string{LabelManaged: "true"}, true},
+		{"legacy", map[string]string{LabelLegacyManagedBy: "doku"}, true},
+		{"component", ComponentLabels("traefik"), true},
+		{"other", map[string]string{LabelLegacyManagedBy: "compose"}, false},
+		{"none", nil, false},
+	}
+
+	for _, tt := range tests {
+		if got := IsDokuContainer(tt.labels); got != tt.want {
+			t.Errorf("%s: IsDokuContainer() = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestIsDokuContainerWorkspace(t *testing.T) {
+	t.Cleanup(func() { workspace.Set("") })
+
+	workspace.Set("work")
+	labels := InstanceLabels("postgres")
+	if labels[workspace.Label] != "work" || !IsDokuContainer(labels) {
+		t.Errorf("expected the workspace to own its instances, labels = %v", labels)
+	}
+	if IsDokuContainer(map[string]string{LabelManaged: "true"}) {
+		t.Error("expected a workspace not to own the default workspace's containers")
+	}
+	if GenerateContainerName("postgres") != "doku-work-postgres" {
+		t.Errorf("GenerateContainerName() = %s", GenerateContainerName("postgres"))
+	}
+	if hasLegacyName([]string{"/doku-postgres"}) {
+		t.Error("expected legacy names to belong to the default workspace only")
+	}
+
+	workspace.Set("")
+	if IsDokuContainer(labels) {
+		t.Error("expected the default workspace not to own another workspace's containers")
+	}
+}
+
+func TestHasLegacyName(t *testing.T) {
+	if !hasLegacyName([]string{"/doku-postgres"}) {
+		t.Error("expected /doku-postgres to match")
+	}
+	if hasLegacyName([]string{"/my-postgres"}) {
+		t.Error("expected /my-postgres not to match")
+	}
+}
+
+func TestValidateExtraLabel(t *testing.T) {
+	valid := []string{"com.corp.team", "backup.enable", "team"}
+	for _, key := range valid {
+		if err := ValidateExtraLabel(key); err != nil {
+			t.Errorf("ValidateExtraLabel(%q) = %v, want nil", key, err)
+		}
+	}
+
+	invalid := []string{"", "doku.instance", "traefik.enable", LabelLegacyManagedBy, "has space", "a=b"}
+	for _, key := range invalid {
+		if err := ValidateExtraLabel(key); err == nil {
+			t.Errorf("ValidateExtraLabel(%q) = nil, want an error", key)
+		}
+	}
+}
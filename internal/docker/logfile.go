@@ -0,0 +1,46 @@
+package docker
+
+import (
+	"fmt"
+	"path"
+
+	"github.com/docker/docker/api/types/mount"
+	"github.com/dokulabs/doku-cli/internal/readonly"
+)
+
+// TruncateLogs empties the log file Docker keeps of a container and
+// deletes its rotated files. The files live where the daemon runs, which
+// may be a VM, so a throwaway container does the work.
+func (c *Client) TruncateLogs(containerID string) error {
+	if err := readonly.Check("truncate the logs of " + containerID); err != nil {
+		return err
+	}
+
+	info, err := c.ContainerInspect(containerID)
+	if err != nil {
+		return err
+	}
+	driver := ""
+	if info.HostConfig != nil {
+		driver = info.HostConfig.LogConfig.Type
+	}
+	if driver != "json-file" {
+		return fmt.Errorf("the logs of %s use the %s driver: only json-file logs can be truncated", containerID, driver)
+	}
+	if info.LogPath == "" {
+		return fmt.Errorf("Docker reports no log file for %s", containerID)
+	}
+
+	if err := c.runHelper("doku-logs-truncate", truncateLogsScript(path.Base(info.LogPath)), []mount.Mount{
+		{Type: mount.TypeBind, Source: path.Dir(info.LogPath), Target: "/logs"},
+	}); err != nil {
+		return fmt.Errorf("failed to truncate the logs of %s: %w", containerID, err)
+	}
+	return nil
+}
+
+// truncateLogsScript empties the log file named file in /logs, rather than
+// deleting it as Docker keeps it open, and deletes its rotated files
+func truncateLogsScript(file string) string {
+	return fmt.Sprintf(`: > "/logs/%s" && rm -f "/logs/%s".*`, file, file)
+}
@@ -0,0 +1,214 @@
+package docker
+
+import (
+	"crypto/md5"
+	"crypto/rand"
+	"fmt"
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/dokulabs/doku-cli/pkg/types"
+)
+
+// Kinds of the middlewares Doku attaches to a router, named
+// <router>-<kind> so they can be told from other routers' and replaced
+var middlewareKinds = []string{"auth", "allowlist", "ratelimit", "headers"}
+
+// ApplyMiddlewares replaces the middlewares of the HTTP routers in a
+// container's labels with mw's. Containers without a router are left
+// alone; a nil mw removes the middlewares.
+func ApplyMiddlewares(labels map[string]string, mw *types.Middlewares) {
+	for _, router := range httpRouters(labels) {
+		delete(labels, "traefik.http.routers."+router+".middlewares")
+		for key := range labels {
+			for _, kind := range middlewareKinds {
+				if strings.HasPrefix(key, "traefik.http.middlewares."+router+"-"+kind+".") {
+					delete(labels, key)
+				}
+			}
+		}
+
+		for key, value := range middlewareLabels(router, mw) {
+			labels[key] = value
+		}
+	}
+}
+
+// httpRouters returns the names of the HTTP routers a container's labels
+// define, sorted
+func httpRouters(labels map[string]string) []string {
+	var routers []string
+	for key := range labels {
+		rest, ok := strings.CutPrefix(key, "traefik.http.routers.")
+		if !ok {
+			continue
+		}
+		if router, ok := strings.CutSuffix(rest, ".rule"); ok {
+			routers = append(routers, router)
+		}
+	}
+	sort.Strings(routers)
+	return routers
+}
+
+// middlewareLabels returns the labels defining mw's middlewares and
+// attaching them to a router
+func middlewareLabels(router string, mw *types.Middlewares) map[string]string {
+	labels := make(map[string]string)
+	if mw.IsEmpty() {
+		return labels
+	}
+
+	var names []string
+	add := func(kind string) string {
+		name := router + "-" + kind
+		names = append(names, name+"@docker")
+		return "traefik.http.middlewares." + name
+	}
+
+	// Clients not let through don't get to try passwords
+	if len(mw.IPAllowList) > 0 {
+		// Traefik v2's name for it; v3 calls it ipallowlist
+		labels[add("allowlist")+".ipwhitelist.sourcerange"] = strings.Join(mw.IPAllowList, ",")
+	}
+	if mw.RateLimit != nil {
+		prefix := add("ratelimit") + ".ratelimit"
+		labels[prefix+".average"] = strconv.Itoa(mw.RateLimit.Average)
+		if mw.RateLimit.Burst > 0 {
+			labels[prefix+".burst"] = strconv.Itoa(mw.RateLimit.Burst)
+		}
+	}
+	if len(mw.BasicAuth) > 0 {
+		labels[add("auth")+".basicauth.users"] = strings.Join(mw.BasicAuth, ",")
+	}
+	if len(mw.Headers) > 0 || len(mw.RequestHeaders) > 0 {
+		prefix := add("headers") + ".headers"
+		for name, value := range mw.Headers {
+			labels[prefix+".customresponseheaders."+name] = value
+		}
+		for name, value := range mw.RequestHeaders {
+			labels[prefix+".customrequestheaders."+name] = value
+		}
+	}
+
+	labels["traefik.http.routers."+router+".middlewares"] = strings.Join(names, ",")
+	return labels
+}
+
+// ValidateMiddlewares checks mw's settings are ones Traefik accepts
+func ValidateMiddlewares(mw *types.Middlewares) error {
+	if mw == nil {
+		return nil
+	}
+	for _, entry := range mw.BasicAuth {
+		user, hash, ok := strings.Cut(entry, ":")
+		if !ok || user == "" || hash == "" {
+			return fmt.Errorf("invalid basic auth user: %q (expected user:password)", entry)
+		}
+	}
+	for _, source := range mw.IPAllowList {
+		if net.ParseIP(source) != nil {
+			continue
+		}
+		if _, _, err := net.ParseCIDR(source); err != nil {
+			return fmt.Errorf("invalid IP or CIDR range: %s", source)
+		}
+	}
+	if mw.RateLimit != nil && (mw.RateLimit.Average < 1 || mw.RateLimit.Burst < 0) {
+		return fmt.Errorf("invalid rate limit: %d requests per second, bursts of %d", mw.RateLimit.Average, mw.RateLimit.Burst)
+	}
+	for _, headers := range []map[string]string{mw.Headers, mw.RequestHeaders} {
+		for name := range headers {
+			if name == "" || strings.ContainsAny(name, " :.\t") {
+				return fmt.Errorf("invalid header name: %q", name)
+			}
+		}
+	}
+	return nil
+}
+
+// BasicAuthUser returns the htpasswd entry of a "user:password" pair, the
+// password hashed with Apache's MD5 scheme, which Traefik understands.
+// Passwords already hashed are kept.
+func BasicAuthUser(credentials string) (string, error) {
+	user, password, ok := strings.Cut(credentials, ":")
+	if !ok || user == "" || password == "" {
+		return "", fmt.Errorf("invalid basic auth credentials (expected user:password)")
+	}
+	for _, prefix := range []string{"$apr1$", "$2y$", "$2a$", "$2b$", "{SHA}"} {
+		if strings.HasPrefix(password, prefix) {
+			return credentials, nil
+		}
+	}
+
+	salt := make([]byte, 8)
+	if _, err := rand.Read(salt); err != nil {
+		return "", fmt.Errorf("failed to generate salt: %w", err)
+	}
+	for n := range salt {
+		salt[n] = apr1Alphabet[int(salt[n])%len(apr1Alphabet)]
+	}
+	return user + ":" + apr1(password, string(salt)), nil
+}
+
+// apr1Alphabet is the base64 variant of crypt(3) hashes
+const apr1Alphabet = "./0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz"
+
+// apr1 hashes a password with Apache's variant of the MD5 crypt scheme,
+// "$apr1$<salt>$<hash>"
+func apr1(password, salt string) string {
+	const magic = "$apr1$"
+
+	alt := md5.Sum([]byte(password + salt + password))
+	h := md5.New()
+	h.Write([]byte(password + magic + salt))
+	for n := len(password); n > 0; n -= 16 {
+		h.Write(alt[:min(16, n)])
+	}
+	for n := len(password); n > 0; n >>= 1 {
+		if n&1 == 1 {
+			h.Write([]byte{0})
+		} else {
+			h.Write([]byte{password[0]})
+		}
+	}
+	sum := h.Sum(nil)
+
+	// 1000 rounds, to slow down guessing
+	for round := 0; round < 1000; round++ {
+		h := md5.New()
+		if round&1 == 1 {
+			h.Write([]byte(password))
+		} else {
+			h.Write(sum)
+		}
+		if round%3 != 0 {
+			h.Write([]byte(salt))
+		}
+		if round%7 != 0 {
+			h.Write([]byte(password))
+		}
+		if round&1 == 1 {
+			h.Write(sum)
+		} else {
+			h.Write([]byte(password))
+		}
+		sum = h.Sum(nil)
+	}
+
+	var out strings.Builder
+	encode := func(v uint, chars int) {
+		for ; chars > 0; chars-- {
+			out.WriteByte(apr1Alphabet[v&0x3f])
+			v >>= 6
+		}
+	}
+	for _, group := range [][3]int{{0, 6, 12}, {1, 7, 13}, {2, 8, 14}, {3, 9, 15}, {4, 10, 5}} {
+		encode(uint(sum[group[0]])<<16|uint(sum[group[1]])<<8|uint(sum[group[2]]), 4)
+	}
+	encode(uint(sum[11]), 2)
+
+	return magic + salt + "$" + out.String()
+}
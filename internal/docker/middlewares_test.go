@@ -0,0 +1,122 @@
+package docker
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/dokulabs/doku-cli/pkg/types"
+)
+
+func TestApplyMiddlewares(t *testing.T) {
+	labels := map[string]string{
+		"traefik.enable":                                    "true",
+		"traefik.http.routers.api.rule":                     "Host(`api.doku.local`)",
+		"traefik.http.middlewares.api-auth.basicauth.users": "old:hash",
+		"traefik.http.routers.api.middlewares":              "api-auth@docker",
+	}
+	mw := &types.Middlewares{
+		IPAllowList: []string{"10.0.0.0/8"},
+		RateLimit:   &types.RateLimit{Average: 100, Burst: 50},
+		Headers:     map[string]string{"X-Frame-Options": "DENY"},
+	}
+	ApplyMiddlewares(labels, mw)
+
+	want := map[string]string{
+		"traefik.http.middlewares.api-allowlist.ipwhitelist.sourcerange":                     "10.0.0.0/8",
+		"traefik.http.middlewares.api-ratelimit.ratelimit.average":                           "100",
+		"traefik.http.middlewares.api-ratelimit.ratelimit.burst":                             "50",
+		"traefik.http.middlewares.api-headers.headers.customresponseheaders.X-Frame-Options": "DENY",
+		"traefik.http.routers.api.middlewares":                                               "api-allowlist@docker,api-ratelimit@docker,api-headers@docker",
+	}
+	for key, value := range want {
+		if labels[key] != value {
+			t.Errorf("labels[%q] = %q, want %q", key, labels[key], value)
+		}
+	}
+	if _, ok := labels["traefik.http.middlewares.api-auth.basicauth.users"]; ok {
+		t.Error("ApplyMiddlewares() kept the old basic auth middleware")
+	}
+
+	ApplyMiddlewares(labels, nil)
+	for key := range labels {
+		if strings.HasPrefix(key, "traefik.http.middlewares.") || strings.HasSuffix(key, ".middlewares") {
+			t.Errorf("ApplyMiddlewares(nil) kept %s", key)
+		}
+	}
+	if labels["traefik.http.routers.api.rule"] == "" || labels["traefik.enable"] != "true" {
+		t.Error("ApplyMiddlewares(nil) removed the router")
+	}
+}
+
+func TestApplyMiddlewaresWithoutRouter(t *testing.T) {
+	labels := map[string]string{"traefik.enable": "false"}
+	ApplyMiddlewares(labels, &types.Middlewares{BasicAuth: []string{"admin:hash"}})
+	if len(labels) != 1 {
+		t.Errorf("ApplyMiddlewares() = %v, want the labels unchanged", labels)
+	}
+}
+
+func TestValidateMiddlewares(t *testing.T) {
+	valid := []*types.Middlewares{
+		nil,
+		{BasicAuth: []string{"admin:$apr1$xy$94JxxwMvxqQwA3Iid6OL9."}},
+		{IPAllowList: []string{"192.168.1.10", "10.0.0.0/8", "::1"}},
+		{RateLimit: &types.RateLimit{Average: 10}},
+		{Headers: map[string]string{"X-Frame-Options": "DENY"}},
+	}
+	for _, mw := range valid {
+		if err := ValidateMiddlewares(mw); err != nil {
+			t.Errorf("ValidateMiddlewares(%+v) = %v", mw, err)
+		}
+	}
+
+	invalid := []*types.Middlewares{
+		{BasicAuth: []string{"admin"}},
+		{IPAllowList: []string{"10.0.0.0/33"}},
+		{IPAllowList: []string{"example.com"}},
+		{RateLimit: &types.RateLimit{Average: 0}},
+		{RequestHeaders: map[string]string{"X Bad": "1"}},
+	}
+	for _, mw := range invalid {
+		if err := ValidateMiddlewares(mw); err == nil {
+			t.Errorf("ValidateMiddlewares(%+v) accepted", mw)
+		}
+	}
+}
+
+func TestApr1(t *testing.T) {
+	tests := []struct{ password, salt, want string }{
+		{"password", "abcdefgh", "$apr1$abcdefgh$FBwExRW4dCc8aL.OvjpIE1"},
+		{"secret", "xy", "$apr1$xy$94JxxwMvxqQwA3Iid6OL9."},
+	}
+	for _, tt := range tests {
+		if got := apr1(tt.password, tt.salt); got != tt.want {
+			t.Errorf("apr1(%q, %q) = %q, want %q", tt.password, tt.salt, got, tt.want)
+		}
+	}
+}
+
+func TestBasicAuthUser(t *testing.T) {
+	entry, err := BasicAuthUser("admin:s3cret:with:colons")
+	if err != nil {
+		t.Fatal(err)
+	}
+	user, hash, _ := strings.Cut(entry, ":")
+	if user != "admin" || !strings.HasPrefix(hash, "$apr1$") {
+		t.Fatalf("BasicAuthUser() = %q", entry)
+	}
+	salt := strings.Split(hash, "$")[2]
+	if apr1("s3cret:with:colons", salt) != hash {
+		t.Errorf("BasicAuthUser() = %q, doesn't match the password", entry)
+	}
+
+	hashed := "admin:$apr1$xy$94JxxwMvxqQwA3Iid6OL9."
+	if got, _ := BasicAuthUser(hashed); got != hashed {
+		t.Errorf("BasicAuthUser(%q) = %q, want it kept", hashed, got)
+	}
+	for _, bad := range []string{"admin", ":pass", "admin:"} {
+		if _, err := BasicAuthUser(bad); err == nil {
+			t.Errorf("BasicAuthUser(%q) accepted", bad)
+		}
+	}
+}
@@ -0,0 +1,97 @@
+package docker
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/distribution/reference"
+)
+
+// MirrorDirect, as a registry's mirror, pulls its images from the registry
+// itself even when a mirror is set for all registries
+const MirrorDirect = "direct"
+
+var (
+	mirrorsMu sync.RWMutex
+
+	// defaultMirror is the pull-through mirror images of every registry
+	// are pulled from, e.g. "artifactory.acme.com/docker-remote"
+	defaultMirror string
+
+	// registryMirrors override defaultMirror, keyed by registry host
+	registryMirrors map[string]string
+)
+
+// SetRegistryMirrors sets the pull-through mirror images are pulled from
+// for the rest of the process: mirror for every registry, overridden by
+// overrides, keyed by registry host. An empty mirror and no overrides pull
+// from the registries themselves.
+func SetRegistryMirrors(mirror string, overrides map[string]string) {
+	mirrorsMu.Lock()
+	defer mirrorsMu.Unlock()
+
+	defaultMirror = mirror
+	registryMirrors = make(map[string]string, len(overrides))
+	for host, m := range overrides {
+		registryMirrors[NormalizeRegistry(host)] = m
+	}
+}
+
+// ValidateMirror checks a mirror is a registry host, optionally followed by
+// a path, e.g. "artifactory.acme.com/docker-remote", or MirrorDirect
+func ValidateMirror(mirror string) error {
+	if mirror == "" || mirror == MirrorDirect {
+		return nil
+	}
+	if strings.Contains(mirror, "://") {
+		return fmt.Errorf("invalid mirror: %s (leave out the scheme, e.g. artifactory.acme.com/docker-remote)", mirror)
+	}
+
+	host, _, _ := strings.Cut(mirror, "/")
+	if !strings.ContainsAny(host, ".:") && host != "localhost" {
+		return fmt.Errorf("invalid mirror: %s (expected a registry host, e.g. artifactory.acme.com/docker-remote)", mirror)
+	}
+	if _, err := reference.ParseNormalizedNamed(strings.TrimSuffix(mirror, "/") + "/image"); err != nil {
+		return fmt.Errorf("invalid mirror: %s", mirror)
+	}
+	return nil
+}
+
+// MirrorImage returns the reference imageName is pulled by: the same image
+// in its registry's mirror, or imageName when the registry has none
+func MirrorImage(imageName string) string {
+	mirrorsMu.RLock()
+	defer mirrorsMu.RUnlock()
+	return mirrorRef(imageName, defaultMirror, registryMirrors)
+}
+
+// mirrorRef rewrites imageName to its registry's mirror, e.g. "postgres:16"
+// with the mirror "artifactory.acme.com/docker-remote" ->
+// "artifactory.acme.com/docker-remote/library/postgres:16". Images already
+// in the mirror are left alone.
+func mirrorRef(imageName, mirror string, overrides map[string]string) string {
+	named, err := reference.ParseNormalizedNamed(imageName)
+	if err != nil {
+		return imageName
+	}
+
+	host := NormalizeRegistry(reference.Domain(named))
+	if override, ok := overrides[host]; ok {
+		mirror = override
+	}
+	mirror = strings.TrimSuffix(mirror, "/")
+	if mirror == "" || mirror == MirrorDirect || strings.HasPrefix(imageName, mirror+"/") {
+		return imageName
+	}
+
+	ref := mirror + "/" + reference.Path(named)
+	named = reference.TagNameOnly(named)
+	if tagged, ok := named.(reference.Tagged); ok {
+		ref += ":" + tagged.Tag()
+	}
+	if digested, ok := named.(reference.Digested); ok {
+		ref += "@" + digested.Digest().String()
+	}
+	return ref
+}
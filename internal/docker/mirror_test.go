@@ -0,0 +1,58 @@
+package docker
+
+import "testing"
+
+const testDigest = "0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef"
+
+func TestMirrorRef(t *testing.T) {
+	const mirror = "artifactory.acme.com/docker-remote"
+	overrides := map[string]string{
+		"ghcr.io": "artifactory.acme.com/ghcr-remote/",
+		"quay.io": MirrorDirect,
+	}
+
+	tests := []struct {
+		image    string
+		expected string
+	}{
+		{"postgres:16", mirror + "/library/postgres:16"},
+		{"postgres", mirror + "/library/postgres:latest"},
+		{"grafana/grafana:11.0.0", mirror + "/grafana/grafana:11.0.0"},
+		{"docker.io/library/redis:7", mirror + "/library/redis:7"},
+		{"postgres@sha256:" + testDigest, mirror + "/library/postgres@sha256:" + testDigest},
+		{"ghcr.io/acme/api:1.0", "artifactory.acme.com/ghcr-remote/acme/api:1.0"},
+		{"quay.io/prometheus/node-exporter:v1.8.0", "quay.io/prometheus/node-exporter:v1.8.0"},
+		{mirror + "/library/postgres:16", mirror + "/library/postgres:16"},
+		{"Not An Image", "Not An Image"},
+	}
+
+	for _, tt := range tests {
+		if got := mirrorRef(tt.image, mirror, overrides); got != tt.expected {
+			t.Errorf("mirrorRef(%q) = %q, expected %q", tt.image, got, tt.expected)
+		}
+	}
+
+	// Without a mirror only the overrides apply
+	if got := mirrorRef("postgres:16", "", overrides); got != "postgres:16" {
+		t.Errorf("mirrorRef() without a mirror = %q, expected the image itself", got)
+	}
+	if got := mirrorRef("ghcr.io/acme/api:1.0", "", overrides); got != "artifactory.acme.com/ghcr-remote/acme/api:1.0" {
+		t.Errorf("mirrorRef() = %q, expected the ghcr.io override", got)
+	}
+}
+
+func TestValidateMirror(t *testing.T) {
+	valid := []string{"", MirrorDirect, "artifactory.acme.com/docker-remote", "mirror.local:5000", "localhost:5000/hub"}
+	for _, mirror := range valid {
+		if err := ValidateMirror(mirror); err != nil {
+			t.Errorf("ValidateMirror(%q) error = %v", mirror, err)
+		}
+	}
+
+	invalid := []string{"https://artifactory.acme.com", "docker-remote", "artifactory.acme.com/Docker Remote"}
+	for _, mirror := range invalid {
+		if err := ValidateMirror(mirror); err == nil {
+			t.Errorf("ValidateMirror(%q) should fail", mirror)
+		}
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 
 	"github.com/docker/docker/api/types/network"
+	"github.com/dokulabs/doku-cli/internal/workspace"
 )
 
 const (
@@ -12,6 +13,12 @@ const (
 	DefaultNetworkGateway = "172.20.0.1"
 )
 
+// NetworkName returns the name of the Doku network of the selected
+// workspace, DefaultNetworkName in the default one
+func NetworkName() string {
+	return workspace.NetworkName()
+}
+
 // NetworkManager manages Docker networks for Doku
 type NetworkManager struct {
 	client *Client
@@ -28,7 +35,7 @@ func NewNetworkManager(client *Client) *NetworkManager {
 func (nm *NetworkManager) EnsureDokuNetwork(networkName, subnet, gateway string) error {
 	// Use defaults if not provided
 	if networkName == "" {
-		networkName = DefaultNetworkName
+		networkName = NetworkName()
 	}
 	if subnet == "" {
 		subnet = DefaultNetworkSubnet
@@ -72,10 +79,9 @@ func (nm *NetworkManager) CreateBridgeNetwork(name, subnet, gateway string) (str
 		Driver:     "bridge",
 		IPAM:       ipam,
 		EnableIPv6: &enableIPv6,
-		Labels: map[string]string{
-			"managed-by":   "doku",
+		Labels: MergeLabels(ManagedLabels(), map[string]string{
 			"doku.network": "true",
-		},
+		}),
 	}
 
 	networkID, err := nm.client.NetworkCreate(name, options)
@@ -135,7 +141,7 @@ func (nm *NetworkManager) DisconnectContainer(networkName, containerID string, f
 // RemoveDokuNetwork removes the Doku network
 func (nm *NetworkManager) RemoveDokuNetwork(networkName string) error {
 	if networkName == "" {
-		networkName = DefaultNetworkName
+		networkName = NetworkName()
 	}
 
 	network, err := nm.GetNetworkByName(networkName)
@@ -166,7 +172,7 @@ func (nm *NetworkManager) ListDokuNetworks() ([]network.Inspect, error) {
 	for _, network := range allNetworks {
 		// Check if network is managed by Doku
 		if labels := network.Labels; labels != nil {
-			if managedBy, ok := labels["managed-by"]; ok && managedBy == "doku" {
+			if IsDokuContainer(labels) {
 				dokuNetworks = append(dokuNetworks, network)
 			}
 		}
@@ -0,0 +1,172 @@
+package docker
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/docker/docker/pkg/jsonmessage"
+	"github.com/docker/go-units"
+)
+
+const (
+	// progressBarWidth is the number of characters of a layer's bar
+	progressBarWidth = 30
+
+	// progressRedraw is how often a terminal is redrawn while layers only
+	// make progress; a layer changing phase redraws at once
+	progressRedraw = 100 * time.Millisecond
+)
+
+var quietPulls atomic.Bool
+
+// SetQuietPulls turns off the progress of image pulls for the rest of the
+// process, e.g. for scripts
+func SetQuietPulls(quiet bool) {
+	quietPulls.Store(quiet)
+}
+
+// layerProgress is the state of one layer of a pull
+type layerProgress struct {
+	id      string
+	status  string // Phase, e.g. "Downloading" or "Pull complete"
+	current int64
+	total   int64
+}
+
+// pullProgress renders the progress stream of an image pull: on a terminal
+// a line per layer, with a bar while it downloads or extracts, redrawn in
+// place; otherwise a line each time a layer moves to another phase
+type pullProgress struct {
+	out      io.Writer
+	terminal bool
+
+	layers []*layerProgress // In the order they appeared
+	byID   map[string]*layerProgress
+
+	drawn    int // Lines drawn by the last redraw
+	lastDraw time.Time
+}
+
+// renderPull reads the progress stream of a pull from in and renders it to
+// out. It returns the error the stream reports, if any.
+func renderPull(in io.Reader, out io.Writer, terminal bool) error {
+	p := &pullProgress{
+		out:      out,
+		terminal: terminal,
+		byID:     make(map[string]*layerProgress),
+	}
+
+	dec := json.NewDecoder(in)
+	for {
+		var msg jsonmessage.JSONMessage
+		if err := dec.Decode(&msg); err != nil {
+			if err == io.EOF {
+				break
+			}
+			return err
+		}
+		if msg.Error != nil {
+			p.redraw()
+			return msg.Error
+		}
+		p.update(msg)
+	}
+
+	p.redraw()
+	return nil
+}
+
+// update applies a message of the stream
+func (p *pullProgress) update(msg jsonmessage.JSONMessage) {
+	// Messages about the image rather than a layer: "Pulling from
+	// library/postgres" (its ID is the tag), "Digest: ..." and "Status: ..."
+	if msg.ID == "" || strings.HasPrefix(msg.Status, "Pulling from") {
+		p.println(strings.TrimSpace(msg.Status))
+		return
+	}
+
+	layer, ok := p.byID[msg.ID]
+	if !ok {
+		layer = &layerProgress{id: msg.ID}
+		p.byID[msg.ID] = layer
+		p.layers = append(p.layers, layer)
+	}
+
+	changed := layer.status != msg.Status
+	layer.status = msg.Status
+	layer.current, layer.total = 0, 0
+	if msg.Progress != nil {
+		layer.current, layer.total = msg.Progress.Current, msg.Progress.Total
+	}
+
+	if !p.terminal {
+		if changed {
+			fmt.Fprintf(p.out, "%s: %s\n", layer.id, layer.status)
+		}
+		return
+	}
+	if changed || time.Since(p.lastDraw) >= progressRedraw {
+		p.redraw()
+	}
+}
+
+// println prints a line above the layers
+func (p *pullProgress) println(line string) {
+	if line == "" {
+		return
+	}
+	if !p.terminal {
+		fmt.Fprintln(p.out, line)
+		return
+	}
+
+	// Lines about the image come before or after the layers: start over
+	// below them
+	p.redraw()
+	p.layers = nil
+	p.byID = make(map[string]*layerProgress)
+	p.drawn = 0
+	fmt.Fprintln(p.out, line)
+}
+
+// redraw draws the layers over the lines drawn last time
+func (p *pullProgress) redraw() {
+	if !p.terminal || len(p.layers) == 0 {
+		return
+	}
+	if p.drawn > 0 {
+		fmt.Fprintf(p.out, "\x1b[%dA", p.drawn)
+	}
+	for _, layer := range p.layers {
+		fmt.Fprintf(p.out, "\r\x1b[2K%s\n", formatLayer(layer))
+	}
+	p.drawn = len(p.layers)
+	p.lastDraw = time.Now()
+}
+
+// formatLayer formats the line of a layer, e.g.
+// "a2abf6c4d29d  Downloading  [=====>      ]  12.1MB/45.6MB"
+func formatLayer(layer *layerProgress) string {
+	line := fmt.Sprintf("%s  %-17s", layer.id, layer.status)
+	if layer.total <= 0 {
+		return strings.TrimRight(line, " ")
+	}
+	return fmt.Sprintf("%s  %s  %s/%s", line, progressBar(layer.current, layer.total),
+		units.HumanSize(float64(layer.current)), units.HumanSize(float64(layer.total)))
+}
+
+// progressBar draws current out of total, e.g. "[=====>      ]"
+func progressBar(current, total int64) string {
+	filled := int(float64(progressBarWidth) * float64(current) / float64(total))
+	filled = max(0, min(filled, progressBarWidth))
+
+	bar := strings.Repeat("=", filled)
+	if filled < progressBarWidth {
+		bar += ">" + strings.Repeat(" ", progressBarWidth-filled-1)
+	}
+	return "[" + bar + "]"
+}
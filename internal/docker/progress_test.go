@@ -0,0 +1,88 @@
+package docker
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+const pullStream = `{"status":"Pulling from library/postgres","id":"16"}
+{"status":"Pulling fs layer","progressDetail":{},"id":"a2abf6c4d29d"}
+{"status":"Already exists","progressDetail":{},"id":"b5a9b1c2d3e4"}
+{"status":"Downloading","progressDetail":{"current":1024,"total":4096},"id":"a2abf6c4d29d"}
+{"status":"Downloading","progressDetail":{"current":4096,"total":4096},"id":"a2abf6c4d29d"}
+{"status":"Download complete","progressDetail":{},"id":"a2abf6c4d29d"}
+{"status":"Extracting","progressDetail":{"current":2048,"total":4096},"id":"a2abf6c4d29d"}
+{"status":"Pull complete","progressDetail":{},"id":"a2abf6c4d29d"}
+{"status":"Digest: sha256:0123"}
+{"status":"Status: Downloaded newer image for postgres:16"}
+`
+
+func TestRenderPullPlain(t *testing.T) {
+	var out bytes.Buffer
+	if err := renderPull(strings.NewReader(pullStream), &out, false); err != nil {
+		t.Fatalf("renderPull() error = %v", err)
+	}
+
+	// One line per phase, not per progress update
+	expected := `Pulling from library/postgres
+a2abf6c4d29d: Pulling fs layer
+b5a9b1c2d3e4: Already exists
+a2abf6c4d29d: Downloading
+a2abf6c4d29d: Download complete
+a2abf6c4d29d: Extracting
+a2abf6c4d29d: Pull complete
+Digest: sha256:0123
+Status: Downloaded newer image for postgres:16
+`
+	if out.String() != expected {
+		t.Errorf("renderPull() =\n%s\nexpected\n%s", out.String(), expected)
+	}
+}
+
+func TestRenderPullTerminal(t *testing.T) {
+	var out bytes.Buffer
+	if err := renderPull(strings.NewReader(pullStream), &out, true); err != nil {
+		t.Fatalf("renderPull() error = %v", err)
+	}
+
+	for _, want := range []string{
+		"a2abf6c4d29d  Downloading        [=======>                      ]  1.024kB/4.096kB",
+		"a2abf6c4d29d  Extracting         [===============>              ]  2.048kB/4.096kB",
+		"a2abf6c4d29d  Pull complete\n",
+		"b5a9b1c2d3e4  Already exists\n",
+		"Status: Downloaded newer image for postgres:16\n",
+	} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("renderPull() output is missing %q:\n%s", want, out.String())
+		}
+	}
+}
+
+func TestRenderPullError(t *testing.T) {
+	stream := `{"status":"Pulling from acme/api","id":"1.0"}
+{"errorDetail":{"message":"unauthorized: authentication required"},"error":"unauthorized: authentication required"}
+`
+	err := renderPull(strings.NewReader(stream), &bytes.Buffer{}, true)
+	if err == nil || !strings.Contains(err.Error(), "unauthorized") {
+		t.Errorf("renderPull() error = %v, expected the stream's error", err)
+	}
+}
+
+func TestProgressBar(t *testing.T) {
+	tests := []struct {
+		current, total int64
+		expected       string
+	}{
+		{0, 100, "[>" + strings.Repeat(" ", 29) + "]"},
+		{50, 100, "[" + strings.Repeat("=", 15) + ">" + strings.Repeat(" ", 14) + "]"},
+		{100, 100, "[" + strings.Repeat("=", 30) + "]"},
+		{150, 100, "[" + strings.Repeat("=", 30) + "]"},
+	}
+
+	for _, tt := range tests {
+		if got := progressBar(tt.current, tt.total); got != tt.expected {
+			t.Errorf("progressBar(%d, %d) = %q, expected %q", tt.current, tt.total, got, tt.expected)
+		}
+	}
+}
@@ -0,0 +1,208 @@
+package docker
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/docker/docker/pkg/jsonmessage"
+	"github.com/docker/go-units"
+	"github.com/dokulabs/doku-cli/internal/readonly"
+)
+
+// PullStatus is the progress of a pull summed over the image's layers
+type PullStatus struct {
+	Layers     int   // Layers of the image seen so far
+	Done       int   // Layers pulled or already present
+	Downloaded int64 // Bytes downloaded
+	Size       int64 // Bytes to download, as far as known
+}
+
+// pullSummary sums the progress stream of a pull per layer
+type pullSummary struct {
+	layers map[string]*layerProgress
+	order  []string
+}
+
+// update applies a message of the stream
+func (s *pullSummary) update(msg jsonmessage.JSONMessage) {
+	if msg.ID == "" || strings.HasPrefix(msg.Status, "Pulling from") {
+		return
+	}
+
+	layer, ok := s.layers[msg.ID]
+	if !ok {
+		layer = &layerProgress{id: msg.ID}
+		s.layers[msg.ID] = layer
+		s.order = append(s.order, msg.ID)
+	}
+	layer.status = msg.Status
+
+	// Only the download counts towards the bytes: once it's complete the
+	// layer keeps its size
+	switch msg.Status {
+	case "Downloading":
+		if msg.Progress != nil {
+			layer.current, layer.total = msg.Progress.Current, msg.Progress.Total
+		}
+	case "Verifying Checksum", "Download complete", "Extracting", "Pull complete":
+		layer.current = layer.total
+	}
+}
+
+// status returns the progress summed over the layers
+func (s *pullSummary) status() PullStatus {
+	var status PullStatus
+	for _, id := range s.order {
+		layer := s.layers[id]
+		status.Layers++
+		if layer.status == "Pull complete" || layer.status == "Already exists" {
+			status.Done++
+		}
+		status.Downloaded += layer.current
+		status.Size += layer.total
+	}
+	return status
+}
+
+// ImagePullStatus pulls an image like ImagePullQuiet, calling progress as
+// the pull advances
+func (c *Client) ImagePullStatus(imageName string, progress func(PullStatus)) error {
+	if err := readonly.Check("pull image " + imageName); err != nil {
+		return err
+	}
+
+	return c.pull(imageName, func(out io.Reader) error {
+		summary := &pullSummary{layers: make(map[string]*layerProgress)}
+		dec := json.NewDecoder(out)
+		for {
+			var msg jsonmessage.JSONMessage
+			if err := dec.Decode(&msg); err != nil {
+				if err == io.EOF {
+					return nil
+				}
+				return err
+			}
+			if msg.Error != nil {
+				return msg.Error
+			}
+			summary.update(msg)
+			progress(summary.status())
+		}
+	})
+}
+
+// imagePull is the state of one image of ImagePullAll
+type imagePull struct {
+	name   string
+	state  string // "waiting", "pulling", "done" or "failed"
+	status PullStatus
+}
+
+// ImagePullAll pulls images concurrently, at most workers at a time, with
+// a line of progress per image on a terminal. It returns the errors of all
+// the pulls that failed.
+func (c *Client) ImagePullAll(imageNames []string, workers int) error {
+	workers = max(1, workers)
+	quiet := quietPulls.Load()
+	terminal := !quiet && IsTerminal(os.Stdout)
+
+	pulls := make([]*imagePull, len(imageNames))
+	for n, name := range imageNames {
+		pulls[n] = &imagePull{name: name, state: "waiting"}
+	}
+
+	var (
+		mu       sync.Mutex
+		drawn    int
+		lastDraw time.Time
+	)
+	// redraw draws the lines of the images over the last ones; mu must be held
+	redraw := func(force bool) {
+		if !terminal || (!force && time.Since(lastDraw) < progressRedraw) {
+			return
+		}
+		if drawn > 0 {
+			fmt.Printf("\x1b[%dA", drawn)
+		}
+		for _, p := range pulls {
+			fmt.Printf("\r\x1b[2K%s\n", formatImagePull(p, imageNames))
+		}
+		drawn = len(pulls)
+		lastDraw = time.Now()
+	}
+	// report notes an image changing state
+	report := func(p *imagePull, state string) {
+		mu.Lock()
+		defer mu.Unlock()
+		p.state = state
+		if !quiet && !terminal && state != "waiting" {
+			fmt.Printf("  %s: %s\n", p.name, state)
+		}
+		redraw(true)
+	}
+
+	mu.Lock()
+	redraw(true)
+	mu.Unlock()
+
+	errs := make([]error, len(pulls))
+	sem := make(chan struct{}, workers)
+	var wg sync.WaitGroup
+	for n, p := range pulls {
+		wg.Add(1)
+		go func(n int, p *imagePull) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			report(p, "pulling")
+			err := c.ImagePullStatus(p.name, func(status PullStatus) {
+				mu.Lock()
+				defer mu.Unlock()
+				p.status = status
+				redraw(false)
+			})
+			if err != nil {
+				errs[n] = fmt.Errorf("%s: %w", p.name, err)
+				report(p, "failed")
+				return
+			}
+			report(p, "done")
+		}(n, p)
+	}
+	wg.Wait()
+
+	return errors.Join(errs...)
+}
+
+// formatImagePull formats the line of an image of ImagePullAll, e.g.
+// "  postgres:16   [=====>      ]  12.1MB/45.6MB  3/7 layers"
+func formatImagePull(p *imagePull, imageNames []string) string {
+	width := 0
+	for _, name := range imageNames {
+		width = max(width, len(name))
+	}
+	line := fmt.Sprintf("  %-*s  ", width, p.name)
+
+	switch p.state {
+	case "done":
+		return line + "✓ pulled"
+	case "failed":
+		return line + "✗ failed"
+	case "waiting":
+		return line + "waiting"
+	}
+
+	if p.status.Size <= 0 {
+		return line + "pulling"
+	}
+	return fmt.Sprintf("%s%s  %s/%s  %d/%d layers", line, progressBar(p.status.Downloaded, p.status.Size),
+		units.HumanSize(float64(p.status.Downloaded)), units.HumanSize(float64(p.status.Size)),
+		p.status.Done, p.status.Layers)
+}
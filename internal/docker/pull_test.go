@@ -0,0 +1,62 @@
+package docker
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/docker/docker/pkg/jsonmessage"
+)
+
+func TestPullSummary(t *testing.T) {
+	summary := &pullSummary{layers: make(map[string]*layerProgress)}
+	var statuses []PullStatus
+	dec := json.NewDecoder(strings.NewReader(pullStream))
+	for dec.More() {
+		var msg jsonmessage.JSONMessage
+		if err := dec.Decode(&msg); err != nil {
+			t.Fatal(err)
+		}
+		summary.update(msg)
+		statuses = append(statuses, summary.status())
+	}
+
+	// After the first progress update: 1KB of one 4KB layer, one layer present
+	downloading := statuses[3]
+	if downloading != (PullStatus{Layers: 2, Done: 1, Downloaded: 1024, Size: 4096}) {
+		t.Errorf("status while downloading = %+v", downloading)
+	}
+
+	// Extracting keeps the downloaded size
+	if extracting := statuses[6]; extracting.Downloaded != 4096 || extracting.Done != 1 {
+		t.Errorf("status while extracting = %+v", extracting)
+	}
+
+	if final := summary.status(); final != (PullStatus{Layers: 2, Done: 2, Downloaded: 4096, Size: 4096}) {
+		t.Errorf("final status = %+v", final)
+	}
+}
+
+func TestFormatImagePull(t *testing.T) {
+	names := []string{"postgres:16", "signoz/query-service:0.50"}
+
+	tests := []struct {
+		pull     imagePull
+		expected string
+	}{
+		{imagePull{name: "postgres:16", state: "waiting"}, "  postgres:16                waiting"},
+		{imagePull{name: "postgres:16", state: "pulling"}, "  postgres:16                pulling"},
+		{imagePull{name: "postgres:16", state: "done"}, "  postgres:16                ✓ pulled"},
+		{imagePull{name: "postgres:16", state: "failed"}, "  postgres:16                ✗ failed"},
+		{
+			imagePull{name: "postgres:16", state: "pulling", status: PullStatus{Layers: 4, Done: 1, Downloaded: 1000, Size: 2000}},
+			"  postgres:16                [===============>              ]  1kB/2kB  1/4 layers",
+		},
+	}
+
+	for _, tt := range tests {
+		if got := formatImagePull(&tt.pull, names); got != tt.expected {
+			t.Errorf("formatImagePull(%+v) =\n%q\nexpected\n%q", tt.pull, got, tt.expected)
+		}
+	}
+}
@@ -0,0 +1,214 @@
+package docker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/mount"
+	"github.com/docker/docker/api/types/network"
+	"github.com/dokulabs/doku-cli/internal/readonly"
+)
+
+// Labels of the extra containers 'doku scale' runs next to a service's
+// container. Replicas are numbered from 2; the original container is the
+// first.
+const (
+	LabelReplicaOf = "doku.replica.of" // Name of the container the replica copies
+	LabelReplica   = "doku.replica"    // Replica number
+)
+
+// ErrStatefulReplicas is returned when scaling a container that keeps data
+// in volumes, which its replicas would share
+var ErrStatefulReplicas = errors.New("the container stores data in volumes, so it can't be scaled")
+
+// ReplicaName returns the name of the nth replica of a container
+func ReplicaName(containerName string, n int) string {
+	return fmt.Sprintf("%s-%d", containerName, n)
+}
+
+// ListReplicas returns the replicas of a container, by replica number
+func (c *Client) ListReplicas(containerName string) ([]types.Container, error) {
+	replicas, err := c.ListContainersByLabel(context.Background(), LabelReplicaOf, containerName)
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(replicas, func(i, j int) bool {
+		return replicaNumber(replicas[i]) < replicaNumber(replicas[j])
+	})
+	return replicas, nil
+}
+
+// SyncReplicas makes count containers run the primary container's image
+// and configuration, the primary included: it creates the missing
+// replicas, starting them if the primary is running, and removes the extra
+// ones. Replicas have the primary's labels, so Traefik balances requests
+// across them, and its network aliases, but no host ports. recreate
+// replaces the existing replicas too, e.g. after the primary was recreated
+// with another image or environment.
+func (c *Client) SyncReplicas(primary string, count int, recreate bool) error {
+	if count < 1 {
+		return fmt.Errorf("replicas must be at least 1, got %d", count)
+	}
+
+	existing, err := c.ListReplicas(primary)
+	if err != nil {
+		return err
+	}
+	if count == 1 && len(existing) == 0 {
+		return nil
+	}
+	if err := readonly.Check(fmt.Sprintf("scale %s to %d", primary, count)); err != nil {
+		return err
+	}
+
+	have := make(map[int]bool, len(existing))
+	for _, replica := range existing {
+		n := replicaNumber(replica)
+		if n <= count && !recreate {
+			have[n] = true
+			continue
+		}
+		if err := c.ContainerRemove(replica.ID, true); err != nil {
+			return fmt.Errorf("failed to remove replica %d: %w", n, err)
+		}
+	}
+	if count == 1 {
+		return nil
+	}
+
+	info, err := c.ContainerInspect(primary)
+	if err != nil {
+		return fmt.Errorf("failed to inspect %s: %w", primary, err)
+	}
+	if err := CanReplicate(info); err != nil {
+		return err
+	}
+
+	for n := 2; n <= count; n++ {
+		if have[n] {
+			continue
+		}
+		id, err := c.createReplica(info, primary, n)
+		if err != nil {
+			return err
+		}
+		if info.State != nil && info.State.Running {
+			if err := c.ContainerStart(id); err != nil {
+				return fmt.Errorf("failed to start replica %d: %w", n, err)
+			}
+		}
+	}
+	return nil
+}
+
+// CanReplicate reports why a container can't be scaled, if it can't: it
+// mounts named volumes, or shares the host's network, where its replicas
+// would conflict over ports
+func CanReplicate(info types.ContainerJSON) error {
+	for _, mp := range info.Mounts {
+		if mp.Type == mount.TypeVolume {
+			return ErrStatefulReplicas
+		}
+	}
+	if info.HostConfig != nil && info.HostConfig.NetworkMode.IsHost() {
+		return errors.New("the container uses the host network, so its replicas would conflict over ports")
+	}
+	return nil
+}
+
+// createReplica creates the nth replica of the inspected container
+func (c *Client) createReplica(info types.ContainerJSON, primary string, n int) (string, error) {
+	config := *info.Config
+	config.Hostname = ""
+	config.Labels = MergeLabels(info.Config.Labels, map[string]string{
+		LabelReplicaOf: primary,
+		LabelReplica:   strconv.Itoa(n),
+	})
+
+	mounts := make([]mount.Mount, 0, len(info.Mounts))
+	for _, mp := range info.Mounts {
+		mounts = append(mounts, mount.Mount{
+			Type:     mp.Type,
+			Source:   mp.Source,
+			Target:   mp.Destination,
+			ReadOnly: !mp.RW,
+		})
+	}
+
+	hostConfig := &container.HostConfig{
+		RestartPolicy: info.HostConfig.RestartPolicy,
+		Mounts:        mounts,
+		LogConfig:     info.HostConfig.LogConfig,
+		Resources:     info.HostConfig.Resources,
+	}
+
+	endpoints := make(map[string]*network.EndpointSettings)
+	if info.NetworkSettings != nil {
+		for name, endpoint := range info.NetworkSettings.Networks {
+			var aliases []string
+			if endpoint != nil {
+				for _, alias := range endpoint.Aliases {
+					if !strings.HasPrefix(info.ID, alias) {
+						aliases = append(aliases, alias)
+					}
+				}
+			}
+			endpoints[name] = &network.EndpointSettings{Aliases: aliases}
+		}
+	}
+
+	name := ReplicaName(primary, n)
+	id, err := c.ContainerCreate(&config, hostConfig, &network.NetworkingConfig{EndpointsConfig: endpoints}, name)
+	if err != nil {
+		return "", fmt.Errorf("failed to create replica %s: %w", name, err)
+	}
+	return id, nil
+}
+
+// StartReplicas starts the replicas of a container
+func (c *Client) StartReplicas(containerName string) error {
+	return c.eachReplica(containerName, func(id string) error {
+		return c.ContainerStart(id)
+	})
+}
+
+// StopReplicas stops the replicas of a container
+func (c *Client) StopReplicas(containerName string) error {
+	timeout := 10
+	return c.eachReplica(containerName, func(id string) error {
+		return c.ContainerStop(id, &timeout)
+	})
+}
+
+// RemoveReplicas removes the replicas of a container
+func (c *Client) RemoveReplicas(containerName string) error {
+	return c.eachReplica(containerName, func(id string) error {
+		return c.ContainerRemove(id, true)
+	})
+}
+
+func (c *Client) eachReplica(containerName string, fn func(id string) error) error {
+	replicas, err := c.ListReplicas(containerName)
+	if err != nil {
+		return err
+	}
+	var errs []error
+	for _, replica := range replicas {
+		if err := fn(replica.ID); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// replicaNumber returns the number of a replica, 0 if it isn't one
+func replicaNumber(replica types.Container) int {
+	n, _ := strconv.Atoi(replica.Labels[LabelReplica])
+	return n
+}
@@ -0,0 +1,55 @@
+package docker
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/mount"
+)
+
+func TestReplicaName(t *testing.T) {
+	if got := ReplicaName("doku-api", 2); got != "doku-api-2" {
+		t.Errorf("ReplicaName() = %q, want doku-api-2", got)
+	}
+}
+
+func TestReplicaNumber(t *testing.T) {
+	replica := types.Container{Labels: map[string]string{LabelReplica: "3"}}
+	if got := replicaNumber(replica); got != 3 {
+		t.Errorf("replicaNumber() = %d, want 3", got)
+	}
+	if got := replicaNumber(types.Container{}); got != 0 {
+		t.Errorf("replicaNumber() without label = %d, want 0", got)
+	}
+}
+
+func TestCanReplicate(t *testing.T) {
+	stateless := types.ContainerJSON{
+		ContainerJSONBase: &types.ContainerJSONBase{HostConfig: &container.HostConfig{}},
+		Mounts: []types.MountPoint{
+			{Type: mount.TypeBind, Source: "/home/me/app", Destination: "/app"},
+		},
+	}
+	if err := CanReplicate(stateless); err != nil {
+		t.Errorf("CanReplicate() with a bind mount = %v, want nil", err)
+	}
+
+	stateful := types.ContainerJSON{
+		ContainerJSONBase: &types.ContainerJSONBase{HostConfig: &container.HostConfig{}},
+		Mounts: []types.MountPoint{
+			{Type: mount.TypeVolume, Name: "doku-pg-data", Destination: "/var/lib/postgresql/data"},
+		},
+	}
+	if err := CanReplicate(stateful); !errors.Is(err, ErrStatefulReplicas) {
+		t.Errorf("CanReplicate() with a volume = %v, want ErrStatefulReplicas", err)
+	}
+
+	host := types.ContainerJSON{
+		ContainerJSONBase: &types.ContainerJSONBase{HostConfig: &container.HostConfig{NetworkMode: "host"}},
+	}
+	if err := CanReplicate(host); err == nil {
+		t.Error("CanReplicate() on the host network = nil, want an error")
+	}
+}
@@ -0,0 +1,27 @@
+//go:build !windows
+
+package docker
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"syscall"
+)
+
+// watchTerminalResize calls resize whenever the terminal window changes size.
+// fd is unused: the size change is signalled by SIGWINCH.
+func watchTerminalResize(ctx context.Context, fd int, resize func()) {
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, syscall.SIGWINCH)
+	defer signal.Stop(sigCh)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-sigCh:
+			resize()
+		}
+	}
+}
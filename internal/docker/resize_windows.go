@@ -0,0 +1,32 @@
+//go:build windows
+
+package docker
+
+import (
+	"context"
+	"time"
+
+	"golang.org/x/term"
+)
+
+// watchTerminalResize polls the terminal size, as Windows has no SIGWINCH,
+// and calls resize when it changes
+func watchTerminalResize(ctx context.Context, fd int, resize func()) {
+	ticker := time.NewTicker(250 * time.Millisecond)
+	defer ticker.Stop()
+
+	width, height, _ := term.GetSize(fd)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			w, h, err := term.GetSize(fd)
+			if err != nil || (w == width && h == height) {
+				continue
+			}
+			width, height = w, h
+			resize()
+		}
+	}
+}
@@ -7,6 +7,7 @@ import (
 
 	"github.com/docker/docker/api/types/container"
 	"github.com/docker/go-connections/nat"
+	"github.com/dokulabs/doku-cli/internal/readonly"
 )
 
 // ResourceConfig holds resource limit configuration
@@ -47,6 +48,71 @@ func ApplyResourceLimits(hostConfig *container.HostConfig, config ResourceConfig
 	return nil
 }
 
+// UpdateLimits changes the memory and CPU limits of a container in place,
+// whether it runs or not. An empty limit lifts it.
+func (c *Client) UpdateLimits(containerID, memory, cpu string) error {
+	if err := readonly.Check("change the limits of container " + containerID); err != nil {
+		return err
+	}
+
+	info, err := c.cli.ContainerInspect(c.ctx, containerID)
+	if err != nil {
+		return fmt.Errorf("failed to inspect container: %w", err)
+	}
+	var memTotal int64
+	if memory == "" && info.HostConfig.Memory > 0 {
+		system, err := c.cli.Info(c.ctx)
+		if err != nil {
+			return fmt.Errorf("failed to get Docker info: %w", err)
+		}
+		memTotal = system.MemTotal
+	}
+
+	resources, err := limitResources(info.HostConfig.Resources, memory, cpu, memTotal)
+	if err != nil {
+		return err
+	}
+	if _, err := c.cli.ContainerUpdate(c.ctx, containerID, container.UpdateConfig{Resources: resources}); err != nil {
+		return fmt.Errorf("failed to update container limits: %w", err)
+	}
+	return nil
+}
+
+// limitResources returns the update of a container's current resources to
+// the given limits. Docker can't lift a memory limit in place, so it's
+// raised to memTotal, the machine's memory, instead.
+func limitResources(current container.Resources, memory, cpu string, memTotal int64) (container.Resources, error) {
+	var resources container.Resources
+
+	switch {
+	case memory != "":
+		memBytes, err := ParseMemoryString(memory)
+		if err != nil {
+			return resources, fmt.Errorf("invalid memory limit: %w", err)
+		}
+		// Swap as much as memory, as Docker gives containers by default
+		resources.Memory = memBytes
+		resources.MemorySwap = memBytes * 2
+	case current.Memory > 0:
+		resources.Memory = memTotal
+		resources.MemorySwap = -1
+	}
+
+	switch {
+	case cpu != "":
+		cpuQuota, cpuPeriod, err := ParseCPUString(cpu)
+		if err != nil {
+			return resources, fmt.Errorf("invalid CPU limit: %w", err)
+		}
+		resources.CPUQuota = cpuQuota
+		resources.CPUPeriod = cpuPeriod
+	case current.CPUQuota > 0:
+		resources.CPUQuota = -1
+	}
+
+	return resources, nil
+}
+
 // ParseMemoryString converts memory strings like "512m", "1g" to bytes
 func ParseMemoryString(mem string) (int64, error) {
 	if mem == "" {
@@ -0,0 +1,40 @@
+package docker
+
+import (
+	"testing"
+
+	"github.com/docker/docker/api/types/container"
+)
+
+func TestLimitResources(t *testing.T) {
+	limited := container.Resources{Memory: 512 << 20, CPUQuota: 50000, CPUPeriod: 100000}
+
+	got, err := limitResources(limited, "2g", "1.5", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Memory != 2<<30 || got.MemorySwap != 4<<30 || got.CPUQuota != 150000 || got.CPUPeriod != 100000 {
+		t.Errorf("limitResources() = %+v, want 2g of memory and 1.5 cores", got)
+	}
+
+	// Lifting the limits: memory can only be raised
+	got, err = limitResources(limited, "", "", 16<<30)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Memory != 16<<30 || got.MemorySwap != -1 || got.CPUQuota != -1 {
+		t.Errorf("limitResources() = %+v, want the machine's memory and no CPU quota", got)
+	}
+
+	// Nothing to lift
+	if got, err := limitResources(container.Resources{}, "", "", 16<<30); err != nil || got.Memory != 0 || got.CPUQuota != 0 {
+		t.Errorf("limitResources() of an unlimited container = %+v, %v, want no change", got, err)
+	}
+
+	if _, err := limitResources(limited, "lots", "", 0); err == nil {
+		t.Error("limitResources() should fail with an invalid memory limit")
+	}
+	if _, err := limitResources(limited, "", "-1", 0); err == nil {
+		t.Error("limitResources() should fail with an invalid CPU limit")
+	}
+}
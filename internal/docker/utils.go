@@ -2,19 +2,25 @@ package docker
 
 import (
 	"fmt"
+	"path"
+	"sort"
 	"strings"
+
+	"github.com/docker/docker/api/types/container"
+	"github.com/dokulabs/doku-cli/internal/workspace"
 )
 
-// GenerateContainerName generates a Docker container name with doku prefix
+// GenerateContainerName generates a Docker container name with the doku
+// prefix of the selected workspace
 func GenerateContainerName(instanceName string) string {
-	return fmt.Sprintf("doku-%s", instanceName)
+	return workspace.Prefix() + instanceName
 }
 
 // GenerateVolumeName generates a Docker volume name from a volume path
 // Sanitizes the path to create a valid Docker volume name
 func GenerateVolumeName(instanceName, volumePath string) string {
 	if volumePath == "" {
-		return fmt.Sprintf("doku-%s-data", instanceName)
+		return GenerateContainerName(instanceName) + "-data"
 	}
 
 	// Extract meaningful identifier from volume path
@@ -34,10 +40,10 @@ func GenerateVolumeName(instanceName, volumePath string) string {
 	// Join parts with hyphens
 	if len(parts) > 0 {
 		volumeType := joinParts(parts, "-")
-		return fmt.Sprintf("doku-%s-%s", instanceName, volumeType)
+		return fmt.Sprintf("%s-%s", GenerateContainerName(instanceName), volumeType)
 	}
 
-	return fmt.Sprintf("doku-%s-data", instanceName)
+	return GenerateContainerName(instanceName) + "-data"
 }
 
 // splitPath splits a file path into parts
@@ -159,9 +165,17 @@ func ContainerHasLabel(labels map[string]string, key, value string) bool {
 	return val == value
 }
 
-// IsDokuContainer checks if a container is managed by Doku
+// IsDokuContainer checks if a container is managed by Doku, in the
+// selected workspace
 func IsDokuContainer(labels map[string]string) bool {
-	return ContainerHasLabel(labels, "managed-by", "doku")
+	managed := ContainerHasLabel(labels, LabelManaged, "true") || ContainerHasLabel(labels, LabelLegacyManagedBy, "doku")
+	return managed && workspace.Owns(labels)
+}
+
+// isLabelled reports whether a resource has Doku's labels, even those of
+// another workspace, as opposed to resources of older versions
+func isLabelled(labels map[string]string) bool {
+	return ContainerHasLabel(labels, LabelManaged, "") || ContainerHasLabel(labels, LabelLegacyManagedBy, "doku")
 }
 
 // ExtractInstanceName extracts the Doku instance name from container labels
@@ -169,7 +183,7 @@ func ExtractInstanceName(labels map[string]string) string {
 	if labels == nil {
 		return ""
 	}
-	return labels["doku.instance"]
+	return labels[LabelInstance]
 }
 
 // CreateEnvVars converts a map to Docker environment variable format
@@ -227,3 +241,126 @@ func GetImageTag(image string) string {
 	}
 	return "latest"
 }
+
+// IsDigestRef reports whether an image reference is pinned by digest,
+// e.g. "postgres@sha256:..."
+func IsDigestRef(image string) bool {
+	return strings.Contains(image, "@")
+}
+
+// DigestRef returns the reference pinning image by digest from the image's
+// repo digests, e.g. "postgres:16" -> "postgres@sha256:...". The digest
+// of another repository is used when none matches, since it names the same
+// content, and for an image ID. It returns "" when there are no repo digests.
+func DigestRef(image string, repoDigests []string) string {
+	if len(repoDigests) == 0 {
+		return ""
+	}
+	if strings.HasPrefix(image, "sha256:") {
+		// An image ID has no repository of its own
+		return repoDigests[0]
+	}
+
+	// Strip the digest or tag; a colon before the last slash is a registry port
+	repo, _, _ := strings.Cut(image, "@")
+	if i := strings.LastIndex(repo, ":"); i > strings.LastIndex(repo, "/") {
+		repo = repo[:i]
+	}
+
+	for _, ref := range repoDigests {
+		if name, _, ok := strings.Cut(ref, "@"); ok && name == repo {
+			return ref
+		}
+	}
+	if _, digest, ok := strings.Cut(repoDigests[0], "@"); ok {
+		return repo + "@" + digest
+	}
+	return ""
+}
+
+// ChangeSummary counts filesystem changes under a directory
+type ChangeSummary struct {
+	Path     string
+	Added    int
+	Modified int
+	Deleted  int
+}
+
+// Total returns the number of changes in the summary
+func (s ChangeSummary) Total() int {
+	return s.Added + s.Modified + s.Deleted
+}
+
+// FilterChanges drops changes at or below any of the given paths (e.g. volume mount points)
+func FilterChanges(changes []container.FilesystemChange, excluded []string) []container.FilesystemChange {
+	filtered := make([]container.FilesystemChange, 0, len(changes))
+	for _, change := range changes {
+		skip := false
+		for _, dir := range excluded {
+			if IsPathWithin(change.Path, dir) {
+				skip = true
+				break
+			}
+		}
+		if !skip {
+			filtered = append(filtered, change)
+		}
+	}
+	return filtered
+}
+
+// SummarizeChanges groups changes by their directory truncated to depth path
+// segments, sorted by number of changes (most first)
+func SummarizeChanges(changes []container.FilesystemChange, depth int) []ChangeSummary {
+	if depth < 1 {
+		depth = 1
+	}
+
+	byDir := make(map[string]*ChangeSummary)
+	for _, change := range changes {
+		segments := strings.Split(strings.Trim(path.Clean(change.Path), "/"), "/")
+		if len(segments) > depth {
+			segments = segments[:depth]
+		}
+		dir := "/" + strings.Join(segments, "/")
+
+		summary, exists := byDir[dir]
+		if !exists {
+			summary = &ChangeSummary{Path: dir}
+			byDir[dir] = summary
+		}
+
+		switch change.Kind {
+		case container.ChangeAdd:
+			summary.Added++
+		case container.ChangeDelete:
+			summary.Deleted++
+		default:
+			summary.Modified++
+		}
+	}
+
+	summaries := make([]ChangeSummary, 0, len(byDir))
+	for _, summary := range byDir {
+		summaries = append(summaries, *summary)
+	}
+
+	sort.Slice(summaries, func(i, j int) bool {
+		if summaries[i].Total() != summaries[j].Total() {
+			return summaries[i].Total() > summaries[j].Total()
+		}
+		return summaries[i].Path < summaries[j].Path
+	})
+
+	return summaries
+}
+
+// IsPathWithin reports whether p is dir or a path below it
+func IsPathWithin(p, dir string) bool {
+	p = path.Clean(p)
+	dir = path.Clean(dir)
+	if dir == "/" {
+		return true
+	}
+	return p == dir || strings.HasPrefix(p, dir+"/")
+}
@@ -0,0 +1,99 @@
+package docker
+
+import (
+	"testing"
+
+	"github.com/docker/docker/api/types/container"
+)
+
+func TestIsPathWithin(t *testing.T) {
+	tests := []struct {
+		path     string
+		dir      string
+		expected bool
+	}{
+		{"/var/lib/postgresql/data", "/var/lib/postgresql/data", true},
+		{"/var/lib/postgresql/data/base", "/var/lib/postgresql/data", true},
+		{"/var/lib/postgresql/database", "/var/lib/postgresql/data", false},
+		{"/etc", "/", true},
+		{"/tmp/", "/tmp", true},
+	}
+
+	for _, tt := range tests {
+		if got := IsPathWithin(tt.path, tt.dir); got != tt.expected {
+			t.Errorf("IsPathWithin(%q, %q) = %v, expected %v", tt.path, tt.dir, got, tt.expected)
+		}
+	}
+}
+
+func TestFilterChanges(t *testing.T) {
+	changes := []container.FilesystemChange{
+		{Kind: container.ChangeAdd, Path: "/data"},
+		{Kind: container.ChangeAdd, Path: "/data/file"},
+		{Kind: container.ChangeModify, Path: "/etc"},
+		{Kind: container.ChangeAdd, Path: "/etc/app.conf"},
+	}
+
+	filtered := FilterChanges(changes, []string{"/data"})
+	if len(filtered) != 2 {
+		t.Fatalf("Expected 2 changes, got %d", len(filtered))
+	}
+
+	for _, change := range filtered {
+		if IsPathWithin(change.Path, "/data") {
+			t.Errorf("Change under volume was not filtered: %s", change.Path)
+		}
+	}
+}
+
+func TestSummarizeChanges(t *testing.T) {
+	changes := []container.FilesystemChange{
+		{Kind: container.ChangeAdd, Path: "/var/cache/app/a"},
+		{Kind: container.ChangeAdd, Path: "/var/cache/app/b"},
+		{Kind: container.ChangeDelete, Path: "/var/cache/old"},
+		{Kind: container.ChangeModify, Path: "/etc/hosts"},
+	}
+
+	summaries := SummarizeChanges(changes, 2)
+	if len(summaries) != 2 {
+		t.Fatalf("Expected 2 directories, got %d: %v", len(summaries), summaries)
+	}
+
+	first := summaries[0]
+	if first.Path != "/var/cache" || first.Added != 2 || first.Deleted != 1 {
+		t.Errorf("Unexpected first summary: %+v", first)
+	}
+
+	second := summaries[1]
+	if second.Path != "/etc/hosts" || second.Modified != 1 {
+		t.Errorf("Unexpected second summary: %+v", second)
+	}
+}
+
+func TestDigestRef(t *testing.T) {
+	digests := []string{
+		"registry.example.com:5000/postgres@sha256:aaa",
+		"postgres@sha256:bbb",
+	}
+
+	tests := []struct {
+		image       string
+		repoDigests []string
+		expected    string
+	}{
+		{"postgres:16", digests, "postgres@sha256:bbb"},
+		{"postgres", digests, "postgres@sha256:bbb"},
+		{"registry.example.com:5000/postgres:16", digests, "registry.example.com:5000/postgres@sha256:aaa"},
+		{"registry.example.com:5000/postgres", digests, "registry.example.com:5000/postgres@sha256:aaa"},
+		{"postgres@sha256:bbb", digests, "postgres@sha256:bbb"},
+		{"docker.io/library/postgres:16", digests, "docker.io/library/postgres@sha256:aaa"},
+		{"sha256:0123abcd", digests, "registry.example.com:5000/postgres@sha256:aaa"},
+		{"myapp:dev", nil, ""},
+	}
+
+	for _, tt := range tests {
+		if got := DigestRef(tt.image, tt.repoDigests); got != tt.expected {
+			t.Errorf("DigestRef(%q) = %q, expected %q", tt.image, got, tt.expected)
+		}
+	}
+}
@@ -0,0 +1,76 @@
+package docker
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/mount"
+	"github.com/dokulabs/doku-cli/internal/readonly"
+)
+
+// VolumeHelperImage is the small image used to work on volume contents
+const VolumeHelperImage = "alpine:latest"
+
+// copyVolumeScript empties the destination, then streams the source into it
+// with tar so ownership, permissions and links are kept
+const copyVolumeScript = `find /to -mindepth 1 -delete && tar -C /from -cf - . | tar -C /to -xpf -`
+
+// CopyVolume replaces the contents of the volume dst with those of src. It
+// runs tar in a throwaway container with both volumes mounted; the
+// containers using the volumes should be stopped so the copy is consistent.
+func (c *Client) CopyVolume(src, dst string) error {
+	if err := readonly.Check(fmt.Sprintf("copy volume %s to %s", src, dst)); err != nil {
+		return err
+	}
+
+	err := c.runHelper("doku-volume-copy", copyVolumeScript, []mount.Mount{
+		{Type: mount.TypeVolume, Source: src, Target: "/from", ReadOnly: true},
+		{Type: mount.TypeVolume, Source: dst, Target: "/to"},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to copy volume %s: %w", src, err)
+	}
+	return nil
+}
+
+// runHelper runs a shell script in a throwaway container of
+// VolumeHelperImage with mounts, and waits for it to finish. The error of
+// a failed script includes its output.
+func (c *Client) runHelper(namePrefix, script string, mounts []mount.Mount) error {
+	exists, err := c.ImageExists(VolumeHelperImage)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		if err := c.ImagePullQuiet(VolumeHelperImage); err != nil {
+			return err
+		}
+	}
+
+	name := fmt.Sprintf("%s-%d", namePrefix, time.Now().UnixNano())
+	config := &container.Config{
+		Image:  VolumeHelperImage,
+		Cmd:    []string{"sh", "-c", script},
+		Labels: ManagedLabels(),
+	}
+	hostConfig := &container.HostConfig{Mounts: mounts}
+
+	containerID, err := c.ContainerCreate(config, hostConfig, nil, name)
+	if err != nil {
+		return err
+	}
+	defer c.ContainerRemove(containerID, true)
+
+	if err := c.ContainerStart(containerID); err != nil {
+		return err
+	}
+	if err := c.WaitForContainer(containerID); err != nil {
+		if logs, logErr := c.GetContainerLogsString(containerID); logErr == nil && strings.TrimSpace(logs) != "" {
+			return fmt.Errorf("%w: %s", err, strings.TrimSpace(logs))
+		}
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,297 @@
+package doctor
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	dockertypes "github.com/docker/docker/api/types"
+	"github.com/dokulabs/doku-cli/internal/certs"
+	"github.com/dokulabs/doku-cli/internal/dns"
+	"github.com/dokulabs/doku-cli/internal/dnsmasq"
+	"github.com/dokulabs/doku-cli/internal/docker"
+	"github.com/dokulabs/doku-cli/internal/domain"
+	"github.com/dokulabs/doku-cli/internal/proxy"
+	"github.com/dokulabs/doku-cli/pkg/types"
+)
+
+// Kind is a kind of drift between the configuration and what runs
+type Kind string
+
+const (
+	KindTraefik Kind = "traefik" // The reverse proxy, Traefik or Caddy, isn't running
+	KindMissing Kind = "missing" // An instance's container no longer exists
+	KindNetwork Kind = "network" // A container isn't connected to doku-network
+	KindLabels  Kind = "labels"  // A container lacks its Traefik labels
+	KindHosts   Kind = "hosts"   // A service's hosts file entry is missing
+	KindDNS     Kind = "dns"     // The dnsmasq container answering for the domain isn't running
+
+	KindCertificate Kind = "certificate" // A certificate expired or is due for renewal
+)
+
+// Issue is a drift found by Diagnose
+type Issue struct {
+	Kind      Kind
+	Instance  string // Empty for the reverse proxy
+	Container string // Container concerned, if any
+	// Certificate concerned, if any, by the domain it's named after
+	Certificate string
+	Message     string
+}
+
+// Fix describes how 'doku doctor --fix' repairs the issue
+func (i Issue) Fix() string {
+	switch i.Kind {
+	case KindTraefik:
+		return "start the reverse proxy"
+	case KindMissing:
+		return fmt.Sprintf("remove the record of %s", i.Instance)
+	case KindNetwork:
+		return fmt.Sprintf("reconnect %s to %s", i.Container, docker.NetworkName())
+	case KindLabels:
+		return fmt.Sprintf("recreate %s with its Traefik labels", i.Instance)
+	case KindHosts:
+		return fmt.Sprintf("add %s to the hosts file", i.Instance)
+	case KindDNS:
+		return "recreate dnsmasq"
+	case KindCertificate:
+		return fmt.Sprintf("renew the certificate of %s", i.Certificate)
+	}
+	return ""
+}
+
+// Container is what Diagnose needs to know of a container
+type Container struct {
+	Labels   map[string]string
+	Networks []string
+}
+
+// State is what runs: the reverse proxy, dnsmasq, the containers by name,
+// when the hosts file is managed the service hostnames it has and the
+// certificates Traefik serves
+type State struct {
+	TraefikRunning bool
+	DNSRunning     bool // The dnsmasq container runs, with DNSSetup "dnsmasq"
+	Containers     map[string]*Container
+	Hosts          map[string]bool // nil if the hosts file isn't managed
+	Certificates   []certs.Certificate
+}
+
+// Observe collects the state Diagnose compares the configuration with
+func Observe(dockerClient *docker.Client, cfg *types.Config, dnsMgr *dns.Manager, certsDir string) (*State, error) {
+	containers, err := dockerClient.ContainerList(true)
+	if err != nil {
+		return nil, err
+	}
+
+	state := &State{Containers: containerMap(containers)}
+	for _, ctr := range containers {
+		if hasName(ctr, proxy.ContainerName(cfg.Preferences)) {
+			state.TraefikRunning = ctr.State == "running"
+		}
+		if hasName(ctr, dnsmasq.ContainerName()) {
+			state.DNSRunning = ctr.State == "running"
+		}
+	}
+
+	if cfg.Preferences.DNSSetup == "hosts" {
+		state.Hosts = make(map[string]bool)
+		for name, instance := range cfg.Instances {
+			if !routed(instance) {
+				continue
+			}
+			found, err := dnsMgr.HasServiceDomain(Subdomain(instance), cfg.Preferences.Domain)
+			if err != nil {
+				return nil, err
+			}
+			state.Hosts[name] = found
+		}
+	}
+
+	if servesCertificates(cfg.Preferences) {
+		if state.Certificates, err = certs.List(certsDir); err != nil {
+			return nil, err
+		}
+	}
+
+	return state, nil
+}
+
+// containerMap maps container names to their labels and networks
+func containerMap(containers []dockertypes.Container) map[string]*Container {
+	m := make(map[string]*Container, len(containers))
+	for _, ctr := range containers {
+		c := &Container{Labels: ctr.Labels}
+		if ctr.NetworkSettings != nil {
+			for network := range ctr.NetworkSettings.Networks {
+				c.Networks = append(c.Networks, network)
+			}
+			sort.Strings(c.Networks)
+		}
+		for _, name := range ctr.Names {
+			m[strings.TrimPrefix(name, "/")] = c
+		}
+	}
+	return m
+}
+
+func hasName(ctr dockertypes.Container, name string) bool {
+	for _, n := range ctr.Names {
+		if strings.TrimPrefix(n, "/") == name {
+			return true
+		}
+	}
+	return false
+}
+
+// Diagnose compares the instances in the configuration with what runs. An
+// instance whose containers are gone only reports that.
+func Diagnose(cfg *types.Config, state *State) []Issue {
+	var issues []Issue
+	if !state.TraefikRunning {
+		name := "Traefik"
+		if proxy.Backend(cfg.Preferences) == proxy.Caddy {
+			name = "Caddy"
+		}
+		issues = append(issues, Issue{Kind: KindTraefik, Message: name + " is not running, so no service URL works"})
+	}
+	if cfg.Preferences.DNSSetup == "dnsmasq" && !state.DNSRunning {
+		issues = append(issues, Issue{Kind: KindDNS, Message: fmt.Sprintf("dnsmasq is not running, so no *.%s name resolves", cfg.Preferences.Domain)})
+	}
+	if servesCertificates(cfg.Preferences) {
+		issues = append(issues, diagnoseCertificates(cfg.Certificates, state.Certificates, time.Now())...)
+	}
+
+	names := make([]string, 0, len(cfg.Instances))
+	for name := range cfg.Instances {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		instance := cfg.Instances[name]
+
+		var missing []string
+		for _, ctr := range containerNames(instance) {
+			if state.Containers[ctr] == nil {
+				missing = append(missing, ctr)
+			}
+		}
+		if len(missing) > 0 {
+			issues = append(issues, Issue{
+				Kind:     KindMissing,
+				Instance: name,
+				Message:  fmt.Sprintf("container %s no longer exists", strings.Join(missing, ", ")),
+			})
+			continue
+		}
+
+		if !instance.UsesHostNetwork() {
+			for _, ctr := range containerNames(instance) {
+				if !slices.Contains(state.Containers[ctr].Networks, docker.NetworkName()) {
+					issues = append(issues, Issue{
+						Kind:      KindNetwork,
+						Instance:  name,
+						Container: ctr,
+						Message:   fmt.Sprintf("%s is not connected to %s", ctr, docker.NetworkName()),
+					})
+				}
+			}
+		}
+
+		if !instance.IsMultiContainer && routed(instance) {
+			if _, ok := state.Containers[instance.ContainerName].Labels["traefik.enable"]; !ok {
+				issues = append(issues, Issue{
+					Kind:      KindLabels,
+					Instance:  name,
+					Container: instance.ContainerName,
+					Message:   fmt.Sprintf("%s has no Traefik labels, so %s doesn't route to it", instance.ContainerName, instance.URL),
+				})
+			}
+		}
+
+		if found, checked := state.Hosts[name]; checked && !found {
+			issues = append(issues, Issue{
+				Kind:     KindHosts,
+				Instance: name,
+				Message:  fmt.Sprintf("%s.%s is missing from the hosts file", Subdomain(instance), cfg.Preferences.Domain),
+			})
+		}
+	}
+
+	return issues
+}
+
+// servesCertificates reports whether Traefik serves the certificates of the
+// certs directory. Caddy issues its own.
+func servesCertificates(prefs types.PreferencesConfig) bool {
+	return prefs.Protocol == "https" && proxy.Backend(prefs) == proxy.Traefik
+}
+
+// diagnoseCertificates reports the certificates that expired or are due for
+// renewal, even when they aren't renewed automatically
+func diagnoseCertificates(c types.CertificatesConfig, list []certs.Certificate, now time.Time) []Issue {
+	days := certs.RenewalWindow(c)
+
+	var issues []Issue
+	for _, cert := range list {
+		issue := Issue{Kind: KindCertificate, Certificate: cert.Name}
+		switch {
+		case cert.Err != nil:
+			issue.Message = fmt.Sprintf("the certificate of %s can't be read: %v", cert.Name, cert.Err)
+		case cert.Expired(now):
+			issue.Message = fmt.Sprintf("the certificate of %s expired on %s, so browsers reject its hostnames", cert.Name, cert.NotAfter.Format("2006-01-02"))
+		case cert.Due(now, days):
+			issue.Message = fmt.Sprintf("the certificate of %s expires in %d day(s), on %s", cert.Name, cert.DaysLeft(now), cert.NotAfter.Format("2006-01-02"))
+		default:
+			continue
+		}
+		issues = append(issues, issue)
+	}
+	return issues
+}
+
+// TraefikLabels returns the labels routing an instance's hostnames to its
+// container, as the installer sets them
+func TraefikLabels(instance *types.Instance, prefs types.PreferencesConfig) map[string]string {
+	router := docker.GenerateContainerName(instance.Name)
+	labels := map[string]string{
+		"traefik.enable": "true",
+		fmt.Sprintf("traefik.http.routers.%s.rule", router):                      domain.HostRule(domain.InstanceHostnames(instance, prefs)),
+		fmt.Sprintf("traefik.http.routers.%s.entrypoints", router):               "web,websecure",
+		fmt.Sprintf("traefik.http.services.%s.loadbalancer.server.port", router): fmt.Sprintf("%d", instance.Traefik.Port),
+	}
+	if prefs.Protocol == "https" {
+		labels[fmt.Sprintf("traefik.http.routers.%s.tls", router)] = "true"
+	}
+	docker.ApplyMiddlewares(labels, instance.Middlewares)
+	return labels
+}
+
+// containerNames returns the names of an instance's containers
+func containerNames(instance *types.Instance) []string {
+	if !instance.IsMultiContainer {
+		return []string{instance.ContainerName}
+	}
+	names := make([]string, 0, len(instance.Containers))
+	for _, c := range instance.Containers {
+		names = append(names, c.FullName)
+	}
+	return names
+}
+
+// routed reports whether Traefik routes an HTTP URL to the instance
+func routed(instance *types.Instance) bool {
+	return instance.Traefik.Enabled && instance.URL != "" &&
+		(instance.Traefik.Protocol == "http" || instance.Traefik.Protocol == "https")
+}
+
+// Subdomain returns the subdomain of an instance's URL
+func Subdomain(instance *types.Instance) string {
+	if instance.Traefik.Subdomain != "" {
+		return instance.Traefik.Subdomain
+	}
+	return instance.Name
+}
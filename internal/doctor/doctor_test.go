@@ -0,0 +1,170 @@
+package doctor
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/dokulabs/doku-cli/internal/certs"
+	"github.com/dokulabs/doku-cli/pkg/types"
+)
+
+func webInstance(name string) *types.Instance {
+	return &types.Instance{
+		Name:          name,
+		ContainerName: "doku-" + name,
+		URL:           "http://" + name + ".doku.local",
+		Traefik:       types.TraefikInstanceConfig{Enabled: true, Subdomain: name, Port: 3000, Protocol: "http"},
+	}
+}
+
+func TestDiagnose(t *testing.T) {
+	redis := &types.Instance{
+		Name:          "redis",
+		ContainerName: "doku-redis",
+		Traefik:       types.TraefikInstanceConfig{Enabled: true, Port: 6379, Protocol: "tcp"},
+	}
+	scanner := &types.Instance{
+		Name:          "scanner",
+		ContainerName: "doku-scanner",
+		Network:       types.NetworkConfig{Mode: types.NetworkModeHost},
+	}
+	signoz := &types.Instance{
+		Name:             "signoz",
+		IsMultiContainer: true,
+		Containers: []types.ContainerInfo{
+			{Name: "frontend", FullName: "doku-signoz-frontend"},
+			{Name: "query", FullName: "doku-signoz-query"},
+		},
+	}
+
+	cfg := &types.Config{
+		Preferences: types.PreferencesConfig{Domain: "doku.local"},
+		Instances: map[string]*types.Instance{
+			"grafana": webInstance("grafana"),
+			"api":     webInstance("api"),
+			"redis":   redis,
+			"scanner": scanner,
+			"signoz":  signoz,
+		},
+	}
+	traefikLabels := map[string]string{"traefik.enable": "true"}
+	state := &State{
+		TraefikRunning: true,
+		Containers: map[string]*Container{
+			"doku-grafana":         {Labels: traefikLabels, Networks: []string{"doku-network"}},
+			"doku-api":             {Labels: map[string]string{}, Networks: []string{"bridge"}},
+			"doku-redis":           {Networks: []string{"doku-network"}},
+			"doku-scanner":         {Networks: []string{"host"}},
+			"doku-signoz-frontend": {Networks: []string{"doku-network"}},
+		},
+		Hosts: map[string]bool{"grafana": false, "api": true},
+	}
+
+	var got []string
+	for _, issue := range Diagnose(cfg, state) {
+		got = append(got, string(issue.Kind)+" "+issue.Instance+" "+issue.Container)
+	}
+	expected := []string{
+		"network api doku-api",
+		"labels api doku-api",
+		"hosts grafana ",
+		"missing signoz ",
+	}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("Diagnose = %q, expected %q", got, expected)
+	}
+}
+
+func TestDiagnoseTraefik(t *testing.T) {
+	issues := Diagnose(&types.Config{}, &State{})
+	if len(issues) != 1 || issues[0].Kind != KindTraefik {
+		t.Errorf("Diagnose = %v, expected a Traefik issue", issues)
+	}
+}
+
+func TestDiagnoseDNS(t *testing.T) {
+	cfg := &types.Config{Preferences: types.PreferencesConfig{Domain: "doku.local", DNSSetup: "dnsmasq"}}
+	issues := Diagnose(cfg, &State{TraefikRunning: true})
+	if len(issues) != 1 || issues[0].Kind != KindDNS {
+		t.Errorf("Diagnose = %v, expected a DNS issue", issues)
+	}
+	if issues := Diagnose(cfg, &State{TraefikRunning: true, DNSRunning: true}); len(issues) != 0 {
+		t.Errorf("Diagnose = %v, expected no issue", issues)
+	}
+}
+
+func TestDiagnoseCertificates(t *testing.T) {
+	now := time.Now()
+	state := &State{
+		TraefikRunning: true,
+		Certificates: []certs.Certificate{
+			{Name: "doku.local", NotAfter: now.Add(400 * 24 * time.Hour)},
+			{Name: "acme.test", NotAfter: now.Add(10 * 24 * time.Hour)},
+			{Name: "old.test", NotAfter: now.Add(-24 * time.Hour)},
+			{Name: "broken.test", Err: errors.New("no PEM certificate")},
+		},
+	}
+
+	cfg := &types.Config{Preferences: types.PreferencesConfig{Domain: "doku.local", Protocol: "https"}}
+	var got []string
+	for _, issue := range Diagnose(cfg, state) {
+		got = append(got, string(issue.Kind)+" "+issue.Certificate)
+	}
+	expected := []string{"certificate acme.test", "certificate old.test", "certificate broken.test"}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("Diagnose = %q, expected %q", got, expected)
+	}
+
+	// Without automatic renewal, they're still reported when due
+	cfg.Certificates.RenewDays = -1
+	if issues := Diagnose(cfg, state); len(issues) != 3 {
+		t.Errorf("Diagnose = %v, expected 3 certificate issues", issues)
+	}
+
+	// Caddy issues its own
+	cfg.Preferences.Proxy = "caddy"
+	if issues := Diagnose(cfg, state); len(issues) != 0 {
+		t.Errorf("Diagnose = %v, expected no issue with Caddy", issues)
+	}
+}
+
+func TestTraefikLabels(t *testing.T) {
+	labels := TraefikLabels(webInstance("api"), types.PreferencesConfig{Domain: "doku.local", Protocol: "https"})
+
+	expected := map[string]string{
+		"traefik.enable":                                          "true",
+		"traefik.http.routers.doku-api.rule":                      "Host(`api.doku.local`)",
+		"traefik.http.routers.doku-api.tls":                       "true",
+		"traefik.http.routers.doku-api.entrypoints":               "web,websecure",
+		"traefik.http.services.doku-api.loadbalancer.server.port": "3000",
+	}
+	if !reflect.DeepEqual(labels, expected) {
+		t.Errorf("TraefikLabels = %v, expected %v", labels, expected)
+	}
+}
+
+func TestTraefikLabelsWithMiddlewares(t *testing.T) {
+	instance := webInstance("api")
+	instance.Middlewares = &types.Middlewares{BasicAuth: []string{"admin:$apr1$xy$94JxxwMvxqQwA3Iid6OL9."}}
+	labels := TraefikLabels(instance, types.PreferencesConfig{Domain: "doku.local", Protocol: "http"})
+
+	if labels["traefik.http.routers.doku-api.middlewares"] != "doku-api-auth@docker" {
+		t.Errorf("TraefikLabels didn't attach the middlewares: %v", labels)
+	}
+	if labels["traefik.http.middlewares.doku-api-auth.basicauth.users"] != instance.Middlewares.BasicAuth[0] {
+		t.Errorf("TraefikLabels didn't define the basic auth middleware: %v", labels)
+	}
+}
+
+func TestTraefikLabelsWithDomains(t *testing.T) {
+	instance := webInstance("api")
+	instance.Domains = []string{"api.example.test"}
+	labels := TraefikLabels(instance, types.PreferencesConfig{Domain: "doku.local", Domains: []string{"myproject.test"}})
+
+	want := "Host(`api.doku.local`) || Host(`api.myproject.test`) || Host(`api.example.test`)"
+	if got := labels["traefik.http.routers.doku-api.rule"]; got != want {
+		t.Errorf("rule = %q, want %q", got, want)
+	}
+}
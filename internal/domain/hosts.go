@@ -0,0 +1,112 @@
+package domain
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/dokulabs/doku-cli/pkg/types"
+)
+
+// DefaultDomain is the base domain when none is configured
+const DefaultDomain = "doku.local"
+
+// BaseDomains returns the base domains services are reachable under: the
+// main one first, then the others configured
+func BaseDomains(prefs types.PreferencesConfig) []string {
+	main := prefs.Domain
+	if main == "" {
+		main = DefaultDomain
+	}
+	domains := []string{main}
+	for _, d := range prefs.Domains {
+		if !slices.Contains(domains, d) {
+			domains = append(domains, d)
+		}
+	}
+	return domains
+}
+
+// Hostnames returns the hostnames of an instance: its subdomain of each
+// base domain, then its own domains
+func Hostnames(subdomain string, baseDomains, custom []string) []string {
+	var hosts []string
+	for _, base := range baseDomains {
+		if host := subdomain + "." + base; !slices.Contains(hosts, host) {
+			hosts = append(hosts, host)
+		}
+	}
+	for _, host := range custom {
+		if !slices.Contains(hosts, host) {
+			hosts = append(hosts, host)
+		}
+	}
+	return hosts
+}
+
+// InstanceHostnames returns the hostnames of an instance under the
+// preferences' base domains, then those it's exposed to the network at
+func InstanceHostnames(instance *types.Instance, prefs types.PreferencesConfig) []string {
+	subdomain := InstanceSubdomain(instance)
+	custom := append(slices.Clone(instance.Domains), LANHostnames(subdomain, instance.LAN)...)
+	return Hostnames(subdomain, BaseDomains(prefs), custom)
+}
+
+// InstanceSubdomain returns the subdomain Traefik routes to an instance
+func InstanceSubdomain(instance *types.Instance) string {
+	if instance.Traefik.Subdomain != "" {
+		return instance.Traefik.Subdomain
+	}
+	return instance.Name
+}
+
+// LANDomain is a public wildcard DNS service resolving <anything>.<ip>.nip.io
+// to ip, so other devices reach the machine without DNS setup of their own
+const LANDomain = "nip.io"
+
+// LANHostnames returns the hostnames an instance exposed to the network is
+// reachable at from other devices: one resolving to the LAN IP, and
+// <subdomain>.local when it's announced over mDNS
+func LANHostnames(subdomain string, lan *types.LANExposure) []string {
+	if lan == nil || lan.IP == "" {
+		return nil
+	}
+	hosts := []string{fmt.Sprintf("%s.%s.%s", subdomain, lan.IP, LANDomain)}
+	if lan.MDNS {
+		hosts = append(hosts, subdomain+".local")
+	}
+	return hosts
+}
+
+// HostRule returns the Traefik rule matching requests to any of hosts
+func HostRule(hosts []string) string {
+	rules := make([]string, 0, len(hosts))
+	for _, host := range hosts {
+		rules = append(rules, fmt.Sprintf("Host(`%s`)", host))
+	}
+	return strings.Join(rules, " || ")
+}
+
+// ValidateHostnames checks the domains given to an instance are valid and
+// not used by another instance or under one of the base domains
+func ValidateHostnames(cfg *types.Config, instanceName string, hosts []string) error {
+	for _, host := range hosts {
+		if err := Validate(host); err != nil {
+			return err
+		}
+		if !strings.Contains(host, ".") {
+			return fmt.Errorf("invalid domain '%s': expected a full hostname, e.g. api.myproject.test", host)
+		}
+		for _, base := range BaseDomains(cfg.Preferences) {
+			if host == base {
+				return fmt.Errorf("'%s' is a base domain", host)
+			}
+		}
+		for name, instance := range cfg.Instances {
+			if name != instanceName && slices.Contains(instance.Domains, host) {
+				return fmt.Errorf("'%s' is already a domain of '%s'", host, name)
+			}
+		}
+	}
+	return nil
+}
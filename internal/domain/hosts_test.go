@@ -0,0 +1,95 @@
+package domain
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/dokulabs/doku-cli/pkg/types"
+)
+
+func TestBaseDomains(t *testing.T) {
+	prefs := types.PreferencesConfig{Domains: []string{"myproject.test", "doku.local", "myproject.test"}}
+	want := []string{"doku.local", "myproject.test"}
+	if got := BaseDomains(prefs); !reflect.DeepEqual(got, want) {
+		t.Errorf("BaseDomains() = %v, want %v", got, want)
+	}
+}
+
+func TestHostnames(t *testing.T) {
+	got := Hostnames("api", []string{"doku.local", "myproject.test"}, []string{"api.example.test", "api.doku.local"})
+	want := []string{"api.doku.local", "api.myproject.test", "api.example.test"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Hostnames() = %v, want %v", got, want)
+	}
+}
+
+func TestHostRule(t *testing.T) {
+	tests := []struct {
+		hosts []string
+		want  string
+	}{
+		{[]string{"api.doku.local"}, "Host(`api.doku.local`)"},
+		{[]string{"api.doku.local", "api.example.test"}, "Host(`api.doku.local`) || Host(`api.example.test`)"},
+	}
+	for _, tt := range tests {
+		if got := HostRule(tt.hosts); got != tt.want {
+			t.Errorf("HostRule(%v) = %q, want %q", tt.hosts, got, tt.want)
+		}
+	}
+}
+
+func TestValidateHostnames(t *testing.T) {
+	cfg := &types.Config{
+		Preferences: types.PreferencesConfig{Domain: "doku.local", Domains: []string{"myproject.test"}},
+		Instances: map[string]*types.Instance{
+			"web": {Name: "web", Domains: []string{"www.example.test"}},
+		},
+	}
+
+	if err := ValidateHostnames(cfg, "api", []string{"api.example.test"}); err != nil {
+		t.Errorf("ValidateHostnames() = %v", err)
+	}
+	if err := ValidateHostnames(cfg, "web", []string{"www.example.test"}); err != nil {
+		t.Errorf("ValidateHostnames() rejected the instance's own domain: %v", err)
+	}
+	for _, host := range []string{"www.example.test", "myproject.test", "localhost", "bad host.test"} {
+		if err := ValidateHostnames(cfg, "api", []string{host}); err == nil {
+			t.Errorf("ValidateHostnames(%q) accepted", host)
+		}
+	}
+}
+
+func TestInstanceHostnames(t *testing.T) {
+	instance := &types.Instance{
+		Name:    "api",
+		Traefik: types.TraefikInstanceConfig{Subdomain: "backend"},
+		Domains: []string{"api.example.test"},
+	}
+	got := InstanceHostnames(instance, types.PreferencesConfig{Domains: []string{"myproject.test"}})
+	want := []string{"backend.doku.local", "backend.myproject.test", "api.example.test"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("InstanceHostnames() = %v, want %v", got, want)
+	}
+}
+
+func TestLANHostnames(t *testing.T) {
+	tests := []struct {
+		lan  *types.LANExposure
+		want []string
+	}{
+		{nil, nil},
+		{&types.LANExposure{IP: "192.168.1.20"}, []string{"api.192.168.1.20.nip.io"}},
+		{&types.LANExposure{IP: "192.168.1.20", MDNS: true}, []string{"api.192.168.1.20.nip.io", "api.local"}},
+	}
+	for _, tt := range tests {
+		if got := LANHostnames("api", tt.lan); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("LANHostnames(%+v) = %v, want %v", tt.lan, got, tt.want)
+		}
+	}
+
+	instance := &types.Instance{Name: "api", LAN: &types.LANExposure{IP: "10.0.0.5"}}
+	want := []string{"api.doku.local", "api.10.0.0.5.nip.io"}
+	if got := InstanceHostnames(instance, types.PreferencesConfig{}); !reflect.DeepEqual(got, want) {
+		t.Errorf("InstanceHostnames() = %v, want %v", got, want)
+	}
+}
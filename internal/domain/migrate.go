@@ -0,0 +1,237 @@
+package domain
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/dokulabs/doku-cli/internal/envfile"
+	"github.com/dokulabs/doku-cli/pkg/types"
+)
+
+// EnvChange is an environment variable whose value references the old domain
+type EnvChange struct {
+	Path     string // Env file the variable lives in
+	Key      string
+	OldValue string
+	NewValue string
+}
+
+// Target is an instance or project affected by a domain migration
+type Target struct {
+	Name       string
+	IsProject  bool
+	Running    bool
+	OldURL     string
+	NewURL     string
+	EnvChanges []EnvChange
+}
+
+// Plan describes everything a domain migration changes
+type Plan struct {
+	OldDomain string
+	NewDomain string
+	Targets   []*Target
+}
+
+// NewPlan builds a migration plan from the current configuration and env files
+func NewPlan(cfg *types.Config, envMgr *envfile.Manager, oldDomain, newDomain string) (*Plan, error) {
+	if err := Validate(newDomain); err != nil {
+		return nil, err
+	}
+	if oldDomain == newDomain {
+		return nil, fmt.Errorf("old and new domain are the same")
+	}
+	if cfg.Preferences.Domain != oldDomain {
+		return nil, fmt.Errorf("current domain is '%s', not '%s'", cfg.Preferences.Domain, oldDomain)
+	}
+
+	plan := &Plan{OldDomain: oldDomain, NewDomain: newDomain}
+
+	for _, name := range sortedKeys(cfg.Instances) {
+		instance := cfg.Instances[name]
+		target := &Target{
+			Name:    name,
+			Running: instance.Status == types.StatusRunning,
+			OldURL:  instance.URL,
+			NewURL:  Replace(instance.URL, oldDomain, newDomain),
+		}
+
+		paths := []string{envMgr.GetServiceEnvPath(name, "")}
+		if instance.IsMultiContainer {
+			paths = paths[:0]
+			for _, c := range instance.Containers {
+				paths = append(paths, envMgr.GetServiceEnvPath(name, c.Name))
+			}
+		}
+		for _, path := range paths {
+			changes, err := envChanges(envMgr, path, oldDomain, newDomain)
+			if err != nil {
+				return nil, err
+			}
+			target.EnvChanges = append(target.EnvChanges, changes...)
+		}
+
+		if target.changed() {
+			plan.Targets = append(plan.Targets, target)
+		}
+	}
+
+	for _, name := range sortedKeys(cfg.Projects) {
+		project := cfg.Projects[name]
+		target := &Target{
+			Name:      name,
+			IsProject: true,
+			Running:   project.Status == types.StatusRunning,
+			OldURL:    project.URL,
+			NewURL:    Replace(project.URL, oldDomain, newDomain),
+		}
+
+		changes, err := envChanges(envMgr, envMgr.GetProjectEnvPath(name), oldDomain, newDomain)
+		if err != nil {
+			return nil, err
+		}
+		target.EnvChanges = changes
+
+		if target.changed() {
+			plan.Targets = append(plan.Targets, target)
+		}
+	}
+
+	return plan, nil
+}
+
+// changed reports whether the target references the old domain anywhere
+func (t *Target) changed() bool {
+	return t.OldURL != t.NewURL || len(t.EnvChanges) > 0
+}
+
+// Hostnames returns the old and new hostname of the target's URL, or empty
+// strings when it has no URL
+func (t *Target) Hostnames() (string, string) {
+	return hostname(t.OldURL), hostname(t.NewURL)
+}
+
+// ApplyEnv rewrites the env files of the target
+func (t *Target) ApplyEnv(envMgr *envfile.Manager) error {
+	byPath := make(map[string][]EnvChange)
+	for _, change := range t.EnvChanges {
+		byPath[change.Path] = append(byPath[change.Path], change)
+	}
+
+	for path, changes := range byPath {
+		env, err := envMgr.Load(path)
+		if err != nil {
+			return fmt.Errorf("failed to load %s: %w", path, err)
+		}
+		for _, change := range changes {
+			env[change.Key] = change.NewValue
+		}
+		if err := envMgr.Save(path, env); err != nil {
+			return fmt.Errorf("failed to save %s: %w", path, err)
+		}
+	}
+
+	return nil
+}
+
+// Replace replaces the old domain in value wherever it appears as a whole
+// domain name or as the suffix of a hostname, e.g. "api.doku.local" but not
+// "mydoku.local" or "doku.local.example.com"
+func Replace(value, oldDomain, newDomain string) string {
+	if oldDomain == "" || !strings.Contains(value, oldDomain) {
+		return value
+	}
+
+	var out strings.Builder
+	for i := 0; i < len(value); {
+		if strings.HasPrefix(value[i:], oldDomain) && boundaryBefore(value, i) && boundaryAfter(value, i+len(oldDomain)) {
+			out.WriteString(newDomain)
+			i += len(oldDomain)
+			continue
+		}
+		out.WriteByte(value[i])
+		i++
+	}
+
+	return out.String()
+}
+
+// Validate checks that a domain is a usable local domain name
+func Validate(name string) error {
+	if name == "" {
+		return fmt.Errorf("domain cannot be empty")
+	}
+	if strings.HasPrefix(name, ".") || strings.HasSuffix(name, ".") || strings.Contains(name, "..") {
+		return fmt.Errorf("invalid domain '%s'", name)
+	}
+	for i := 0; i < len(name); i++ {
+		if !isHostChar(name[i]) && name[i] != '.' {
+			return fmt.Errorf("invalid character '%c' in domain '%s'", name[i], name)
+		}
+	}
+	return nil
+}
+
+func envChanges(envMgr *envfile.Manager, path, oldDomain, newDomain string) ([]EnvChange, error) {
+	if !envMgr.Exists(path) {
+		return nil, nil
+	}
+
+	env, err := envMgr.Load(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load %s: %w", path, err)
+	}
+
+	var changes []EnvChange
+	for _, key := range sortedKeys(env) {
+		value := env[key]
+		if replaced := Replace(value, oldDomain, newDomain); replaced != value {
+			changes = append(changes, EnvChange{Path: path, Key: key, OldValue: value, NewValue: replaced})
+		}
+	}
+	return changes, nil
+}
+
+// boundaryBefore reports whether a domain may start at i: at the start of the
+// value, after a dot (subdomain) or after a character that can't be part of
+// a hostname
+func boundaryBefore(value string, i int) bool {
+	return i == 0 || value[i-1] == '.' || !isHostChar(value[i-1])
+}
+
+// boundaryAfter reports whether a domain may end at i: the next character
+// can't continue the hostname
+func boundaryAfter(value string, i int) bool {
+	if i >= len(value) {
+		return true
+	}
+	if value[i] == '.' {
+		return i+1 >= len(value) || !isHostChar(value[i+1])
+	}
+	return !isHostChar(value[i])
+}
+
+func isHostChar(c byte) bool {
+	return c == '-' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9')
+}
+
+// hostname strips the scheme and path from a URL
+func hostname(url string) string {
+	if i := strings.Index(url, "://"); i >= 0 {
+		url = url[i+3:]
+	}
+	if i := strings.IndexAny(url, "/:"); i >= 0 {
+		url = url[:i]
+	}
+	return url
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,121 @@
+package domain
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/dokulabs/doku-cli/internal/envfile"
+	"github.com/dokulabs/doku-cli/pkg/types"
+)
+
+func TestReplace(t *testing.T) {
+	tests := []struct {
+		value string
+		want  string
+	}{
+		{"doku.local", "dev.test"},
+		{"https://api.doku.local", "https://api.dev.test"},
+		{"Host(`api.doku.local`)", "Host(`api.dev.test`)"},
+		{"postgres://u:p@db.doku.local:5432/app", "postgres://u:p@db.dev.test:5432/app"},
+		{"a.doku.local,b.doku.local", "a.dev.test,b.dev.test"},
+		{"mydoku.local", "mydoku.local"},
+		{"doku.localhost", "doku.localhost"},
+		{"doku.local.example.com", "doku.local.example.com"},
+		{"Visit doku.local.", "Visit dev.test."},
+		{"no domain here", "no domain here"},
+	}
+
+	for _, tt := range tests {
+		if got := Replace(tt.value, "doku.local", "dev.test"); got != tt.want {
+			t.Errorf("Replace(%q) = %q, want %q", tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestValidate(t *testing.T) {
+	for _, valid := range []string{"doku.local", "dev.test", "my-app.internal"} {
+		if err := Validate(valid); err != nil {
+			t.Errorf("Validate(%q) error = %v", valid, err)
+		}
+	}
+	for _, invalid := range []string{"", ".local", "doku.", "doku..local", "doku local", "https://doku.local"} {
+		if err := Validate(invalid); err == nil {
+			t.Errorf("Validate(%q) should fail", invalid)
+		}
+	}
+}
+
+func TestNewPlan(t *testing.T) {
+	dokuDir := t.TempDir()
+	envMgr := envfile.NewManager(dokuDir)
+
+	writeEnv := func(path string, env map[string]string) {
+		t.Helper()
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := envMgr.Save(path, env); err != nil {
+			t.Fatal(err)
+		}
+	}
+	writeEnv(envMgr.GetServiceEnvPath("api", ""), map[string]string{
+		"PUBLIC_URL": "https://api.doku.local",
+		"PORT":       "8080",
+	})
+	writeEnv(envMgr.GetServiceEnvPath("redis", ""), map[string]string{"PORT": "6379"})
+
+	cfg := &types.Config{
+		Preferences: types.PreferencesConfig{Domain: "doku.local"},
+		Instances: map[string]*types.Instance{
+			"api":   {Name: "api", URL: "https://api.doku.local", Status: types.StatusRunning},
+			"redis": {Name: "redis", Status: types.StatusRunning},
+		},
+		Projects: map[string]*types.Project{
+			"web": {Name: "web", URL: "https://web.doku.local", Status: types.StatusStopped},
+		},
+	}
+
+	plan, err := NewPlan(cfg, envMgr, "doku.local", "dev.test")
+	if err != nil {
+		t.Fatalf("NewPlan() error = %v", err)
+	}
+
+	if len(plan.Targets) != 2 {
+		t.Fatalf("len(Targets) = %d, want 2", len(plan.Targets))
+	}
+
+	api := plan.Targets[0]
+	if api.Name != "api" || api.NewURL != "https://api.dev.test" {
+		t.Errorf("api target = %+v", api)
+	}
+	if len(api.EnvChanges) != 1 || api.EnvChanges[0].Key != "PUBLIC_URL" || api.EnvChanges[0].NewValue != "https://api.dev.test" {
+		t.Errorf("api env changes = %+v", api.EnvChanges)
+	}
+	if oldHost, newHost := api.Hostnames(); oldHost != "api.doku.local" || newHost != "api.dev.test" {
+		t.Errorf("Hostnames() = %q, %q", oldHost, newHost)
+	}
+
+	web := plan.Targets[1]
+	if !web.IsProject || web.Running || web.NewURL != "https://web.dev.test" {
+		t.Errorf("web target = %+v", web)
+	}
+
+	if err := api.ApplyEnv(envMgr); err != nil {
+		t.Fatalf("ApplyEnv() error = %v", err)
+	}
+	env, err := envMgr.Load(envMgr.GetServiceEnvPath("api", ""))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if env["PUBLIC_URL"] != "https://api.dev.test" || env["PORT"] != "8080" {
+		t.Errorf("env after ApplyEnv = %v", env)
+	}
+}
+
+func TestNewPlanWrongDomain(t *testing.T) {
+	cfg := &types.Config{Preferences: types.PreferencesConfig{Domain: "doku.local"}}
+	if _, err := NewPlan(cfg, envfile.NewManager(t.TempDir()), "other.local", "dev.test"); err == nil {
+		t.Error("NewPlan() with a domain that isn't current should fail")
+	}
+}
@@ -2,12 +2,16 @@ package envfile
 
 import (
 	"bufio"
+	"bytes"
 	"fmt"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"sort"
 	"strings"
+
+	"github.com/dokulabs/doku-cli/internal/readonly"
+	"github.com/dokulabs/doku-cli/internal/secrets"
 )
 
 const (
@@ -67,12 +71,46 @@ func (m *Manager) Exists(envPath string) bool {
 
 // Delete removes an env file
 func (m *Manager) Delete(envPath string) error {
+	if err := readonly.Check("delete " + envPath); err != nil {
+		return err
+	}
+
 	if !m.Exists(envPath) {
 		return nil
 	}
+	if err := Snapshot(envPath); err != nil {
+		return err
+	}
 	return os.Remove(envPath)
 }
 
+// Move renames an env file and its backups. A missing file has nothing to
+// move.
+func (m *Manager) Move(oldPath, newPath string) error {
+	if err := readonly.Check(fmt.Sprintf("move %s to %s", oldPath, newPath)); err != nil {
+		return err
+	}
+
+	if !m.Exists(oldPath) {
+		return nil
+	}
+	if m.Exists(newPath) {
+		return fmt.Errorf("%s already exists", newPath)
+	}
+	if err := os.Rename(oldPath, newPath); err != nil {
+		return err
+	}
+
+	oldHistory, newHistory := historyPath(oldPath), historyPath(newPath)
+	if _, err := os.Stat(oldHistory); err == nil {
+		if err := os.RemoveAll(newHistory); err != nil {
+			return err
+		}
+		return os.Rename(oldHistory, newHistory)
+	}
+	return nil
+}
+
 // FindEnvFilesByPrefix returns all env files that match a given instance prefix
 // This is useful for finding multi-container env files like <instance>-<container>.env
 func (m *Manager) FindEnvFilesByPrefix(instanceName string) []string {
@@ -144,6 +182,12 @@ func LoadEnvFile(filePath string) (map[string]string, error) {
 			}
 		}
 
+		// Encrypted values are only decrypted in memory
+		value, err = secrets.Decrypt(value)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %s: %w", filePath, key, err)
+		}
+
 		env[key] = value
 	}
 
@@ -154,14 +198,71 @@ func LoadEnvFile(filePath string) (map[string]string, error) {
 	return env, nil
 }
 
-// SaveEnvFile writes environment variables to an env file
+// SaveEnvFile writes environment variables to an env file. Their values
+// are encrypted when encryption at rest is on.
 func SaveEnvFile(filePath string, env map[string]string) error {
+	if err := readonly.Check("write " + filePath); err != nil {
+		return err
+	}
+
 	// Ensure directory exists
 	dir := filepath.Dir(filePath)
 	if err := os.MkdirAll(dir, 0755); err != nil {
 		return fmt.Errorf("failed to create directory: %w", err)
 	}
 
+	// Keep the current contents so a bad write can be rolled back
+	if err := Snapshot(filePath); err != nil {
+		return err
+	}
+
+	return writeEnvFile(filePath, env)
+}
+
+// Reseal writes an env file and its backups again, so that their values
+// are encrypted, or decrypted, according to the current mode
+func Reseal(filePath string) error {
+	if err := readonly.Check("write " + filePath); err != nil {
+		return err
+	}
+
+	backups, err := History(filePath)
+	if err != nil {
+		return err
+	}
+	paths := []string{filePath}
+	for _, backup := range backups {
+		paths = append(paths, backup.Path)
+	}
+
+	for _, path := range paths {
+		env, err := LoadEnvFile(path)
+		if err != nil {
+			return err
+		}
+		if err := writeEnvFile(path, env); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// IsEncrypted reports whether an env file has encrypted values
+func IsEncrypted(filePath string) bool {
+	data, err := os.ReadFile(filePath)
+	return err == nil && bytes.Contains(data, []byte("="+secrets.Prefix))
+}
+
+// writeEnvFile writes an env file without backing it up
+func writeEnvFile(filePath string, env map[string]string) error {
+	if secrets.Enabled() {
+		encrypted, err := secrets.EncryptMap(env)
+		if err != nil {
+			return fmt.Errorf("failed to encrypt %s: %w", filePath, err)
+		}
+		env = encrypted
+	}
+
 	// Create or truncate file
 	file, err := os.Create(filePath)
 	if err != nil {
@@ -266,17 +367,39 @@ func EnvSliceToMap(envSlice []string) map[string]string {
 
 // OpenInEditor opens the env file in the user's preferred editor
 func OpenInEditor(filePath string) error {
+	if err := readonly.Check("edit " + filePath); err != nil {
+		return err
+	}
+
+	if IsEncrypted(filePath) {
+		return fmt.Errorf("%s is encrypted: change its values with 'doku env set' and 'doku env unset'", filePath)
+	}
+
 	editor := getEditor()
 	if editor == "" {
 		return fmt.Errorf("no editor found. Set $EDITOR or $VISUAL environment variable")
 	}
 
+	before, err := os.ReadFile(filePath)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
 	cmd := exec.Command(editor, filePath)
 	cmd.Stdin = os.Stdin
 	cmd.Stdout = os.Stdout
 	cmd.Stderr = os.Stderr
 
-	return cmd.Run()
+	if err := cmd.Run(); err != nil {
+		return err
+	}
+
+	// Back up the previous contents only if the edit changed them
+	after, err := os.ReadFile(filePath)
+	if before != nil && (err != nil || !bytes.Equal(before, after)) {
+		return saveBackup(filePath, before)
+	}
+	return nil
 }
 
 // getEditor returns the user's preferred editor
@@ -0,0 +1,66 @@
+package envfile
+
+import (
+	"encoding/base64"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/dokulabs/doku-cli/internal/secrets"
+)
+
+func TestSaveEnvFileEncrypted(t *testing.T) {
+	t.Setenv(secrets.KeyEnvVar, base64.StdEncoding.EncodeToString([]byte(strings.Repeat("k", 32))))
+	path := filepath.Join(t.TempDir(), "services", "postgres.env")
+
+	// A plaintext file with a backup, from before encryption was turned on
+	for _, password := range []string{"first", "second"} {
+		if err := SaveEnvFile(path, map[string]string{"POSTGRES_PASSWORD": password}); err != nil {
+			t.Fatalf("SaveEnvFile() error = %v", err)
+		}
+	}
+	if IsEncrypted(path) {
+		t.Fatal("IsEncrypted() = true with encryption off")
+	}
+
+	secrets.Enable(true)
+	defer secrets.Enable(false)
+
+	if err := Reseal(path); err != nil {
+		t.Fatalf("Reseal() error = %v", err)
+	}
+	backups, _ := History(path)
+	for _, file := range []string{path, backups[0].Path} {
+		data, _ := os.ReadFile(file)
+		if strings.Contains(string(data), "first") || strings.Contains(string(data), "second") {
+			t.Errorf("%s has plaintext values after Reseal():\n%s", file, data)
+		}
+		if !strings.Contains(string(data), "POSTGRES_PASSWORD="+secrets.Prefix) {
+			t.Errorf("%s doesn't keep the variable names readable:\n%s", file, data)
+		}
+	}
+	if !IsEncrypted(path) {
+		t.Error("IsEncrypted() = false after Reseal()")
+	}
+
+	env, err := LoadEnvFile(path)
+	if err != nil {
+		t.Fatalf("LoadEnvFile() error = %v", err)
+	}
+	if env["POSTGRES_PASSWORD"] != "second" {
+		t.Errorf("LoadEnvFile() = %q, want second", env["POSTGRES_PASSWORD"])
+	}
+	if err := OpenInEditor(path); err == nil {
+		t.Error("OpenInEditor() of an encrypted file succeeded, expected an error")
+	}
+
+	// Turning encryption off writes plaintext again
+	secrets.Enable(false)
+	if err := Reseal(path); err != nil {
+		t.Fatalf("Reseal() error = %v", err)
+	}
+	if IsEncrypted(path) {
+		t.Error("IsEncrypted() = true after decrypting")
+	}
+}
@@ -0,0 +1,170 @@
+package envfile
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/dokulabs/doku-cli/internal/readonly"
+)
+
+const (
+	// HistoryDir is the subdirectory, next to the env files, that holds
+	// their backups: ~/.doku/services/.history/<instance>/<timestamp>.env
+	HistoryDir = ".history"
+	// MaxHistory is how many backups are kept per env file
+	MaxHistory = 10
+)
+
+// historyTimeFormat names backups so they sort by time
+const historyTimeFormat = "20060102-150405.000"
+
+// Backup is a saved version of an env file
+type Backup struct {
+	Timestamp string // Identifies the backup, e.g. 20261018-101530.123
+	Time      time.Time
+	Path      string
+}
+
+// historyPath returns the directory holding the backups of an env file
+func historyPath(filePath string) string {
+	name := strings.TrimSuffix(filepath.Base(filePath), ".env")
+	return filepath.Join(filepath.Dir(filePath), HistoryDir, name)
+}
+
+// Snapshot saves the current contents of an env file as a backup before it
+// is overwritten. A missing file has nothing to save.
+func Snapshot(filePath string) error {
+	content, err := os.ReadFile(filePath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return fmt.Errorf("failed to read env file: %w", err)
+	}
+	return saveBackup(filePath, content)
+}
+
+// saveBackup stores content as the newest backup of an env file and prunes
+// the oldest beyond MaxHistory. Content equal to the newest backup is not
+// stored again.
+func saveBackup(filePath string, content []byte) error {
+	backups, err := History(filePath)
+	if err != nil {
+		return err
+	}
+	if len(backups) > 0 {
+		if latest, err := os.ReadFile(backups[0].Path); err == nil && bytes.Equal(latest, content) {
+			return nil
+		}
+	}
+
+	dir := historyPath(filePath)
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return fmt.Errorf("failed to create history directory: %w", err)
+	}
+
+	// Writes within the same millisecond get the next free timestamp
+	now := time.Now()
+	path := filepath.Join(dir, now.Format(historyTimeFormat)+".env")
+	for fileExists(path) {
+		now = now.Add(time.Millisecond)
+		path = filepath.Join(dir, now.Format(historyTimeFormat)+".env")
+	}
+	if err := os.WriteFile(path, content, 0600); err != nil {
+		return fmt.Errorf("failed to back up env file: %w", err)
+	}
+
+	backups, err = History(filePath)
+	if err != nil {
+		return err
+	}
+	for _, old := range backups[min(len(backups), MaxHistory):] {
+		os.Remove(old.Path)
+	}
+	return nil
+}
+
+// History returns the backups of an env file, newest first
+func History(filePath string) ([]Backup, error) {
+	entries, err := os.ReadDir(historyPath(filePath))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read env file history: %w", err)
+	}
+
+	var backups []Backup
+	for _, entry := range entries {
+		timestamp, ok := strings.CutSuffix(entry.Name(), ".env")
+		if !ok || entry.IsDir() {
+			continue
+		}
+		t, err := time.ParseInLocation(historyTimeFormat, timestamp, time.Local)
+		if err != nil {
+			continue
+		}
+		backups = append(backups, Backup{
+			Timestamp: timestamp,
+			Time:      t,
+			Path:      filepath.Join(historyPath(filePath), entry.Name()),
+		})
+	}
+
+	sort.Slice(backups, func(i, j int) bool {
+		return backups[i].Timestamp > backups[j].Timestamp
+	})
+	return backups, nil
+}
+
+// FindBackup returns the backup of an env file identified by timestamp. A
+// unique prefix such as 20261018-1015 is enough.
+func FindBackup(filePath, timestamp string) (Backup, error) {
+	backups, err := History(filePath)
+	if err != nil {
+		return Backup{}, err
+	}
+
+	var matches []Backup
+	for _, b := range backups {
+		if b.Timestamp == timestamp {
+			return b, nil
+		}
+		if strings.HasPrefix(b.Timestamp, timestamp) {
+			matches = append(matches, b)
+		}
+	}
+
+	switch len(matches) {
+	case 0:
+		return Backup{}, fmt.Errorf("no backup at %s", timestamp)
+	case 1:
+		return matches[0], nil
+	}
+	return Backup{}, fmt.Errorf("%s matches %d backups; give more of the timestamp", timestamp, len(matches))
+}
+
+// Rollback restores an env file to a backup. The current contents are
+// backed up first, so a rollback can itself be rolled back.
+func Rollback(filePath string, backup Backup) error {
+	if err := readonly.Check("write " + filePath); err != nil {
+		return err
+	}
+
+	content, err := os.ReadFile(backup.Path)
+	if err != nil {
+		return fmt.Errorf("failed to read backup: %w", err)
+	}
+	if err := Snapshot(filePath); err != nil {
+		return err
+	}
+	if err := os.WriteFile(filePath, content, 0644); err != nil {
+		return fmt.Errorf("failed to restore env file: %w", err)
+	}
+	return nil
+}
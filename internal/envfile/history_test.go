@@ -0,0 +1,141 @@
+package envfile
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestSaveEnvFileKeepsHistory(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "services", "postgres.env")
+
+	for _, password := range []string{"first", "second", "third"} {
+		if err := SaveEnvFile(path, map[string]string{"POSTGRES_PASSWORD": password}); err != nil {
+			t.Fatalf("SaveEnvFile() error = %v", err)
+		}
+	}
+
+	backups, err := History(path)
+	if err != nil {
+		t.Fatalf("History() error = %v", err)
+	}
+	if len(backups) != 2 {
+		t.Fatalf("History() returned %d backups, want 2", len(backups))
+	}
+	if dir := filepath.Dir(backups[0].Path); dir != filepath.Join(filepath.Dir(path), HistoryDir, "postgres") {
+		t.Errorf("backup stored in %s", dir)
+	}
+
+	// Newest first: the backup taken before writing "third"
+	env, err := LoadEnvFile(backups[0].Path)
+	if err != nil {
+		t.Fatalf("LoadEnvFile() error = %v", err)
+	}
+	if env["POSTGRES_PASSWORD"] != "second" {
+		t.Errorf("newest backup has %q, want second", env["POSTGRES_PASSWORD"])
+	}
+}
+
+func TestHistoryPrunesOldBackups(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "redis.env")
+
+	for i := 0; i < MaxHistory+5; i++ {
+		if err := SaveEnvFile(path, map[string]string{"N": string(rune('a' + i))}); err != nil {
+			t.Fatalf("SaveEnvFile() error = %v", err)
+		}
+	}
+
+	backups, err := History(path)
+	if err != nil {
+		t.Fatalf("History() error = %v", err)
+	}
+	if len(backups) != MaxHistory {
+		t.Errorf("History() returned %d backups, want %d", len(backups), MaxHistory)
+	}
+}
+
+func TestRollback(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "api.env")
+
+	if err := SaveEnvFile(path, map[string]string{"API_KEY": "good"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := SaveEnvFile(path, map[string]string{"API_KEY": "bad"}); err != nil {
+		t.Fatal(err)
+	}
+
+	backups, err := History(path)
+	if err != nil || len(backups) != 1 {
+		t.Fatalf("History() = %v, %v; want one backup", backups, err)
+	}
+
+	backup, err := FindBackup(path, backups[0].Timestamp[:8])
+	if err != nil {
+		t.Fatalf("FindBackup() by prefix error = %v", err)
+	}
+	if err := Rollback(path, backup); err != nil {
+		t.Fatalf("Rollback() error = %v", err)
+	}
+
+	env, err := LoadEnvFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if env["API_KEY"] != "good" {
+		t.Errorf("API_KEY = %q after rollback, want good", env["API_KEY"])
+	}
+
+	// The rolled back contents were saved too
+	backups, _ = History(path)
+	if len(backups) != 2 {
+		t.Errorf("History() returned %d backups after rollback, want 2", len(backups))
+	}
+
+	if _, err := FindBackup(path, "19990101"); err == nil {
+		t.Error("FindBackup() with an unknown timestamp should fail")
+	}
+}
+
+func TestSnapshotMissingFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "missing.env")
+	if err := Snapshot(path); err != nil {
+		t.Fatalf("Snapshot() error = %v", err)
+	}
+	if _, err := os.Stat(historyPath(path)); !os.IsNotExist(err) {
+		t.Error("Snapshot() of a missing file should not create a history directory")
+	}
+}
+
+func TestMoveKeepsHistory(t *testing.T) {
+	dir := t.TempDir()
+	oldPath := filepath.Join(dir, "pg.env")
+	newPath := filepath.Join(dir, "db.env")
+
+	if err := SaveEnvFile(oldPath, map[string]string{"A": "1"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := SaveEnvFile(oldPath, map[string]string{"A": "2"}); err != nil {
+		t.Fatal(err)
+	}
+
+	m := NewManager(dir)
+	if err := m.Move(oldPath, newPath); err != nil {
+		t.Fatalf("Move() error = %v", err)
+	}
+
+	if m.Exists(oldPath) {
+		t.Error("Move() should remove the old file")
+	}
+	env, err := LoadEnvFile(newPath)
+	if err != nil || env["A"] != "2" {
+		t.Errorf("moved file = %v, %v; want A=2", env, err)
+	}
+	backups, err := History(newPath)
+	if err != nil || len(backups) != 1 {
+		t.Errorf("History() of the moved file = %d backups, %v; want 1", len(backups), err)
+	}
+
+	if err := m.Move(filepath.Join(dir, "missing.env"), newPath); err != nil {
+		t.Errorf("Move() of a missing file error = %v", err)
+	}
+}
@@ -0,0 +1,100 @@
+package envfile
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// referencePattern matches ${instance.FIELD}, e.g. ${postgres.PASSWORD}.
+// References without a dot, like ${HOME}, are left for the container, and
+// $${...} escapes a reference.
+var referencePattern = regexp.MustCompile(`\$?\$\{([A-Za-z0-9][A-Za-z0-9_-]*)\.([A-Za-z_][A-Za-z0-9_]*)\}`)
+
+// Resolver returns the value of a field of another instance, such as the
+// PASSWORD of postgres
+type Resolver func(instance, field string) (string, error)
+
+// Reference is an ${instance.FIELD} reference in an env value
+type Reference struct {
+	Instance string
+	Field    string
+}
+
+// HasReferences reports whether any value in env references another instance
+func HasReferences(env map[string]string) bool {
+	for _, value := range env {
+		if len(References(value)) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// References returns the instance references in an env value
+func References(value string) []Reference {
+	var refs []Reference
+	for _, m := range referencePattern.FindAllStringSubmatch(value, -1) {
+		if strings.HasPrefix(m[0], "$$") {
+			continue
+		}
+		refs = append(refs, Reference{Instance: m[1], Field: m[2]})
+	}
+	return refs
+}
+
+// RenameReferences returns a copy of env with its references to the
+// instance oldName pointing at newName instead, and whether any changed
+func RenameReferences(env map[string]string, oldName, newName string) (map[string]string, bool) {
+	result := make(map[string]string, len(env))
+	changed := false
+	for key, value := range env {
+		result[key] = referencePattern.ReplaceAllStringFunc(value, func(match string) string {
+			m := referencePattern.FindStringSubmatch(match)
+			if strings.HasPrefix(match, "$$") || m[1] != oldName {
+				return match
+			}
+			changed = true
+			return fmt.Sprintf("${%s.%s}", newName, m[2])
+		})
+	}
+	return result, changed
+}
+
+// Interpolate returns a copy of env with every ${instance.FIELD} reference
+// replaced by the value resolve returns. Values of other instances are used
+// as they are, without resolving their own references.
+func Interpolate(env map[string]string, resolve Resolver) (map[string]string, error) {
+	keys := make([]string, 0, len(env))
+	for key := range env {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	result := make(map[string]string, len(env))
+	for _, key := range keys {
+		var resolveErr error
+		result[key] = referencePattern.ReplaceAllStringFunc(env[key], func(match string) string {
+			if strings.HasPrefix(match, "$$") {
+				return match[1:]
+			}
+			if resolveErr != nil {
+				return match
+			}
+
+			m := referencePattern.FindStringSubmatch(match)
+			value, err := resolve(m[1], m[2])
+			if err != nil {
+				resolveErr = fmt.Errorf("%s: cannot resolve %s: %w", key, match, err)
+				return match
+			}
+			return value
+		})
+		if resolveErr != nil {
+			return nil, resolveErr
+		}
+	}
+
+	return result, nil
+}
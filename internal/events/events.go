@@ -0,0 +1,86 @@
+// Package events is the event bus of service lifecycle events: services
+// report being installed, started, stopped, removed or failing, and the
+// handlers subscribed, e.g. webhooks, are notified.
+package events
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Type is the kind of an event
+type Type string
+
+const (
+	TypeInstall Type = "install"
+	TypeStart   Type = "start"
+	TypeStop    Type = "stop"
+	TypeRemove  Type = "remove"
+	TypeFail    Type = "fail" // A service crashed, keeps restarting or couldn't start
+)
+
+// Types lists the event types
+var Types = []Type{TypeInstall, TypeStart, TypeStop, TypeRemove, TypeFail}
+
+// Event is something that happened to a service
+type Event struct {
+	Type    Type      `json:"type"`
+	Service string    `json:"service"`
+	Message string    `json:"message"`
+	Time    time.Time `json:"time"`
+}
+
+// New returns an event of the current time
+func New(eventType Type, service, format string, args ...any) Event {
+	return Event{
+		Type:    eventType,
+		Service: service,
+		Message: fmt.Sprintf(format, args...),
+		Time:    time.Now(),
+	}
+}
+
+// Handler is notified of events
+type Handler func(Event)
+
+var (
+	mu       sync.RWMutex
+	handlers []Handler
+)
+
+// Subscribe adds a handler notified of every event emitted afterwards
+func Subscribe(h Handler) {
+	mu.Lock()
+	defer mu.Unlock()
+	handlers = append(handlers, h)
+}
+
+// Reset removes all handlers
+func Reset() {
+	mu.Lock()
+	defer mu.Unlock()
+	handlers = nil
+}
+
+// Emit notifies the handlers of an event, in the order they subscribed.
+// It returns once they all have been notified.
+func Emit(e Event) {
+	mu.RLock()
+	subscribed := handlers
+	mu.RUnlock()
+
+	for _, h := range subscribed {
+		h(e)
+	}
+}
+
+// ParseType returns the event type named s
+func ParseType(s string) (Type, error) {
+	for _, t := range Types {
+		if string(t) == s {
+			return t, nil
+		}
+	}
+	return "", fmt.Errorf("unknown event type '%s' (expected install, start, stop, remove or fail)", s)
+}
@@ -0,0 +1,86 @@
+package events
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/dokulabs/doku-cli/pkg/types"
+)
+
+func TestEmit(t *testing.T) {
+	defer Reset()
+
+	var got []string
+	Subscribe(func(e Event) { got = append(got, "a:"+e.Service) })
+	Subscribe(func(e Event) { got = append(got, "b:"+e.Service) })
+
+	Emit(New(TypeStart, "postgres", "started"))
+	if len(got) != 2 || got[0] != "a:postgres" || got[1] != "b:postgres" {
+		t.Errorf("handlers notified %v, expected [a:postgres b:postgres]", got)
+	}
+}
+
+func TestPayload(t *testing.T) {
+	e := New(TypeFail, "postgres", "crashed")
+
+	body, err := Payload(FormatSlack, e)
+	if err != nil {
+		t.Fatalf("Payload failed: %v", err)
+	}
+	var slack map[string]string
+	if err := json.Unmarshal(body, &slack); err != nil || slack["text"] != ":red_circle: *postgres* crashed" {
+		t.Errorf("slack payload = %s", body)
+	}
+
+	body, err = Payload("", e)
+	if err != nil {
+		t.Fatalf("Payload failed: %v", err)
+	}
+	var decoded Event
+	if err := json.Unmarshal(body, &decoded); err != nil || decoded.Type != TypeFail || decoded.Service != "postgres" {
+		t.Errorf("json payload = %s", body)
+	}
+
+	if _, err := Payload("xml", e); err == nil {
+		t.Error("Payload with an unknown format should fail")
+	}
+}
+
+func TestWebhookHandler(t *testing.T) {
+	var received []Event
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		var e Event
+		if err := json.Unmarshal(body, &e); err != nil {
+			t.Errorf("invalid body %s", body)
+		}
+		received = append(received, e)
+	}))
+	defer srv.Close()
+
+	handler := WebhookHandler([]types.WebhookConfig{
+		{Name: "failures", URL: srv.URL, Events: []string{"fail"}},
+		{Name: "all", URL: srv.URL, Format: FormatJSON},
+	})
+	handler(New(TypeStart, "redis", "started"))
+	handler(New(TypeFail, "postgres", "crashed"))
+
+	if len(received) != 3 {
+		t.Fatalf("received %d events, expected 3", len(received))
+	}
+	if received[0].Service != "redis" || received[1].Service != "postgres" || received[2].Service != "postgres" {
+		t.Errorf("received %v", received)
+	}
+}
+
+func TestParseType(t *testing.T) {
+	if got, err := ParseType("fail"); err != nil || got != TypeFail {
+		t.Errorf("ParseType(fail) = %v, %v", got, err)
+	}
+	if _, err := ParseType("crash"); err == nil {
+		t.Error("ParseType(crash) should fail")
+	}
+}
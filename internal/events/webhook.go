@@ -0,0 +1,88 @@
+package events
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"slices"
+	"time"
+
+	"github.com/dokulabs/doku-cli/pkg/types"
+)
+
+// webhookTimeout bounds a webhook request, so a slow endpoint doesn't hold
+// up the command that emitted the event
+const webhookTimeout = 5 * time.Second
+
+// Webhook formats
+const (
+	FormatJSON  = "json"
+	FormatSlack = "slack"
+)
+
+// WebhookHandler returns a handler POSTing events to the webhooks that
+// want them. Failures are reported on stderr; they don't fail the command.
+func WebhookHandler(hooks []types.WebhookConfig) Handler {
+	client := &http.Client{Timeout: webhookTimeout}
+	return func(e Event) {
+		for _, hook := range hooks {
+			if !Wants(hook, e.Type) {
+				continue
+			}
+			if err := Send(client, hook, e); err != nil {
+				fmt.Fprintf(os.Stderr, "Warning: webhook %s: %v\n", hook.Name, err)
+			}
+		}
+	}
+}
+
+// Wants reports whether a webhook is sent events of a type
+func Wants(hook types.WebhookConfig, eventType Type) bool {
+	return len(hook.Events) == 0 || slices.Contains(hook.Events, string(eventType))
+}
+
+// Send POSTs an event to a webhook
+func Send(client *http.Client, hook types.WebhookConfig, e Event) error {
+	body, err := Payload(hook.Format, e)
+	if err != nil {
+		return err
+	}
+
+	resp, err := client.Post(hook.URL, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("%s answered %s", hook.URL, resp.Status)
+	}
+	return nil
+}
+
+// Payload returns the body POSTed for an event: the event itself, or a
+// message for Slack incoming webhooks
+func Payload(format string, e Event) ([]byte, error) {
+	switch format {
+	case "", FormatJSON:
+		return json.Marshal(e)
+	case FormatSlack:
+		return json.Marshal(map[string]string{"text": slackText(e)})
+	}
+	return nil, fmt.Errorf("unknown webhook format '%s'", format)
+}
+
+func slackText(e Event) string {
+	icon := map[Type]string{
+		TypeInstall: ":package:",
+		TypeStart:   ":large_green_circle:",
+		TypeStop:    ":white_circle:",
+		TypeRemove:  ":wastebasket:",
+		TypeFail:    ":red_circle:",
+	}[e.Type]
+	return fmt.Sprintf("%s *%s* %s", icon, e.Service, e.Message)
+}
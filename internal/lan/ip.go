@@ -0,0 +1,68 @@
+// Package lan exposes services to the other devices of the local network:
+// it finds the machine's address on it and announces hostnames over mDNS
+package lan
+
+import (
+	"fmt"
+	"net"
+	"strings"
+)
+
+// virtualInterfaces are prefixes of the names of interfaces that don't
+// lead to the local network: container bridges, VPNs and VMs
+var virtualInterfaces = []string{
+	"docker", "br-", "veth", "virbr", "cni", "podman", "flannel",
+	"tun", "utun", "tap", "tailscale", "zt", "wg", "vmnet", "vboxnet",
+}
+
+// DetectIP returns the machine's IPv4 address on the local network: the
+// first private address of an interface that is up and isn't a loopback,
+// a container bridge or a VPN
+func DetectIP() (net.IP, error) {
+	interfaces, err := net.Interfaces()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list network interfaces: %w", err)
+	}
+
+	for _, iface := range interfaces {
+		if iface.Flags&net.FlagUp == 0 || iface.Flags&net.FlagLoopback != 0 || isVirtual(iface.Name) {
+			continue
+		}
+		addrs, err := iface.Addrs()
+		if err != nil {
+			continue
+		}
+		for _, addr := range addrs {
+			ipNet, ok := addr.(*net.IPNet)
+			if !ok {
+				continue
+			}
+			if ip := ipNet.IP.To4(); ip != nil && ip.IsPrivate() {
+				return ip, nil
+			}
+		}
+	}
+	return nil, fmt.Errorf("no address on a local network found; give it with --lan-ip")
+}
+
+// isVirtual reports whether an interface is one of virtualInterfaces
+func isVirtual(name string) bool {
+	for _, prefix := range virtualInterfaces {
+		if strings.HasPrefix(name, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// ParseIP parses an IPv4 address given for the machine on the network
+func ParseIP(s string) (net.IP, error) {
+	ip := net.ParseIP(strings.TrimSpace(s)).To4()
+	if ip == nil {
+		return nil, fmt.Errorf("invalid LAN IP '%s': expected an IPv4 address, e.g. 192.168.1.20", s)
+	}
+	if ip.IsLoopback() || ip.IsUnspecified() {
+		return nil, fmt.Errorf("invalid LAN IP '%s': other devices can't reach it", s)
+	}
+	return ip, nil
+}
@@ -0,0 +1,98 @@
+package lan
+
+import (
+	"bytes"
+	"encoding/binary"
+	"net"
+	"reflect"
+	"testing"
+)
+
+func TestIsVirtual(t *testing.T) {
+	for name, want := range map[string]bool{
+		"eth0": false, "en0": false, "wlan0": false,
+		"docker0": true, "br-3f2a1b": true, "veth12ab": true, "utun3": true, "tailscale0": true,
+	} {
+		if got := isVirtual(name); got != want {
+			t.Errorf("isVirtual(%q) = %v, want %v", name, got, want)
+		}
+	}
+}
+
+func TestParseIP(t *testing.T) {
+	if ip, err := ParseIP(" 192.168.1.20 "); err != nil || ip.String() != "192.168.1.20" {
+		t.Errorf("ParseIP() = %v, %v", ip, err)
+	}
+	for _, s := range []string{"", "nope", "127.0.0.1", "0.0.0.0", "fe80::1"} {
+		if _, err := ParseIP(s); err == nil {
+			t.Errorf("ParseIP(%q) should fail", s)
+		}
+	}
+}
+
+func TestParseQuery(t *testing.T) {
+	// Two questions, the second naming api.local through a pointer to the
+	// "local" label of the first
+	msg := []byte{0x12, 0x34, 0, 0, 0, 2, 0, 0, 0, 0, 0, 0}
+	msg = appendName(msg, "web.local")
+	msg = binary.BigEndian.AppendUint16(msg, typeA)
+	msg = binary.BigEndian.AppendUint16(msg, classIN)
+	msg = append(msg, 3, 'a', 'p', 'i', 0xC0, 16)
+	msg = binary.BigEndian.AppendUint16(msg, typeANY)
+	msg = binary.BigEndian.AppendUint16(msg, classIN|cacheFlush)
+
+	id, questions, err := ParseQuery(msg)
+	if err != nil {
+		t.Fatalf("ParseQuery() error = %v", err)
+	}
+	want := []Question{
+		{Name: "web.local", Type: typeA},
+		{Name: "api.local", Type: typeANY, Unicast: true},
+	}
+	if id != 0x1234 || !reflect.DeepEqual(questions, want) {
+		t.Errorf("ParseQuery() = %#x, %+v, want 0x1234, %+v", id, questions, want)
+	}
+
+	response := append([]byte{0, 0, 0x84, 0}, msg[4:]...)
+	if _, _, err := ParseQuery(response); err == nil {
+		t.Error("ParseQuery() should reject responses")
+	}
+	if _, _, err := ParseQuery(msg[:20]); err == nil {
+		t.Error("ParseQuery() should reject truncated queries")
+	}
+
+	loop := []byte{0, 0, 0, 0, 0, 1, 0, 0, 0, 0, 0, 0, 0xC0, 12, 0, 1, 0, 1}
+	if _, _, err := ParseQuery(loop); err == nil {
+		t.Error("ParseQuery() should reject pointer loops")
+	}
+}
+
+func TestAnswer(t *testing.T) {
+	ip := net.ParseIP("192.168.1.20")
+
+	msg := Answer(0, "api.local", ip, false)
+	if count := binary.BigEndian.Uint16(msg[4:6]); count != 0 {
+		t.Errorf("questions = %d, want 0", count)
+	}
+	name, next, err := readName(msg, 12)
+	if err != nil || name != "api.local" {
+		t.Fatalf("answer name = %q, %v", name, err)
+	}
+	if class := binary.BigEndian.Uint16(msg[next+2 : next+4]); class != classIN|cacheFlush {
+		t.Errorf("class = %#x, want %#x", class, classIN|cacheFlush)
+	}
+	if got := msg[len(msg)-4:]; !bytes.Equal(got, ip.To4()) {
+		t.Errorf("address = %v, want %v", net.IP(got), ip)
+	}
+
+	msg = Answer(0x1234, "api.local", ip, true)
+	if id := binary.BigEndian.Uint16(msg[0:2]); id != 0x1234 {
+		t.Errorf("id = %#x, want 0x1234", id)
+	}
+	if count := binary.BigEndian.Uint16(msg[4:6]); count != 1 {
+		t.Errorf("questions = %d, want 1", count)
+	}
+	if class := binary.BigEndian.Uint16(msg[len(msg)-12 : len(msg)-10]); class != classIN {
+		t.Errorf("answer class = %#x, want %#x", class, classIN)
+	}
+}
@@ -0,0 +1,195 @@
+package lan
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+)
+
+const (
+	mdnsPort = 5353
+	mdnsTTL  = 120 // Seconds other devices cache an answer
+
+	typeA   = 1
+	typeANY = 255
+	classIN = 1
+
+	// cacheFlush marks an answer as replacing the cached ones, and in a
+	// question asks for a unicast answer
+	cacheFlush = 0x8000
+)
+
+// mdnsGroup is the multicast group mDNS queries and answers are sent to
+var mdnsGroup = &net.UDPAddr{IP: net.IPv4(224, 0, 0, 251), Port: mdnsPort}
+
+// Question is a question of a DNS query
+type Question struct {
+	Name    string // Without the trailing dot, e.g. api.local
+	Type    uint16
+	Unicast bool // The asker wants a unicast answer
+}
+
+// Announce announces that names resolve to ip, then answers the mDNS
+// queries for them until ctx is done
+func Announce(ctx context.Context, names []string, ip net.IP) error {
+	conn, err := net.ListenMulticastUDP("udp4", nil, mdnsGroup)
+	if err != nil {
+		return fmt.Errorf("failed to listen for mDNS queries: %w", err)
+	}
+	go func() {
+		<-ctx.Done()
+		conn.Close()
+	}()
+
+	wanted := make(map[string]bool, len(names))
+	for _, name := range names {
+		wanted[strings.ToLower(name)] = true
+		// Replace what other devices cached, e.g. an earlier address
+		if _, err := conn.WriteToUDP(Answer(0, name, ip, false), mdnsGroup); err != nil {
+			conn.Close()
+			return fmt.Errorf("failed to announce %s: %w", name, err)
+		}
+	}
+
+	buf := make([]byte, 9000)
+	for {
+		n, from, err := conn.ReadFromUDP(buf)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return fmt.Errorf("failed to read mDNS queries: %w", err)
+		}
+		id, questions, err := ParseQuery(buf[:n])
+		if err != nil {
+			continue
+		}
+		for _, q := range questions {
+			if !wanted[strings.ToLower(q.Name)] || (q.Type != typeA && q.Type != typeANY) {
+				continue
+			}
+			switch {
+			case from.Port != mdnsPort:
+				// A plain DNS resolver asking the group: it expects the
+				// answer back, with its query's ID and question
+				_, _ = conn.WriteToUDP(Answer(id, q.Name, ip, true), from)
+			case q.Unicast:
+				_, _ = conn.WriteToUDP(Answer(0, q.Name, ip, false), from)
+			default:
+				_, _ = conn.WriteToUDP(Answer(0, q.Name, ip, false), mdnsGroup)
+			}
+		}
+	}
+}
+
+// ParseQuery returns the ID and questions of a DNS query. Responses are
+// rejected.
+func ParseQuery(msg []byte) (uint16, []Question, error) {
+	if len(msg) < 12 {
+		return 0, nil, errors.New("message too short")
+	}
+	id := binary.BigEndian.Uint16(msg[0:2])
+	if binary.BigEndian.Uint16(msg[2:4])&0x8000 != 0 {
+		return 0, nil, errors.New("not a query")
+	}
+	count := int(binary.BigEndian.Uint16(msg[4:6]))
+
+	questions := make([]Question, 0, count)
+	offset := 12
+	for i := 0; i < count; i++ {
+		name, next, err := readName(msg, offset)
+		if err != nil {
+			return 0, nil, err
+		}
+		if next+4 > len(msg) {
+			return 0, nil, errors.New("question truncated")
+		}
+		class := binary.BigEndian.Uint16(msg[next+2 : next+4])
+		questions = append(questions, Question{
+			Name:    name,
+			Type:    binary.BigEndian.Uint16(msg[next : next+2]),
+			Unicast: class&cacheFlush != 0,
+		})
+		offset = next + 4
+	}
+	return id, questions, nil
+}
+
+// readName reads the name at offset, following compression pointers, and
+// returns it with the offset past it
+func readName(msg []byte, offset int) (string, int, error) {
+	var labels []string
+	end := -1
+	for jumps := 0; ; {
+		if offset >= len(msg) {
+			return "", 0, errors.New("name truncated")
+		}
+		length := int(msg[offset])
+		switch {
+		case length == 0:
+			if end < 0 {
+				end = offset + 1
+			}
+			return strings.Join(labels, "."), end, nil
+		case length&0xC0 == 0xC0:
+			if offset+1 >= len(msg) {
+				return "", 0, errors.New("name truncated")
+			}
+			if jumps++; jumps > 10 {
+				return "", 0, errors.New("name pointers loop")
+			}
+			if end < 0 {
+				end = offset + 2
+			}
+			offset = int(binary.BigEndian.Uint16(msg[offset:offset+2]) & 0x3FFF)
+		default:
+			if offset+1+length > len(msg) {
+				return "", 0, errors.New("name truncated")
+			}
+			labels = append(labels, string(msg[offset+1:offset+1+length]))
+			offset += 1 + length
+		}
+	}
+}
+
+// Answer returns an mDNS response saying name resolves to ip. Answers to
+// plain DNS resolvers carry their query's id and repeat its question, and
+// don't flush caches.
+func Answer(id uint16, name string, ip net.IP, question bool) []byte {
+	msg := binary.BigEndian.AppendUint16(nil, id)
+	msg = binary.BigEndian.AppendUint16(msg, 0x8400) // Authoritative response
+	questions := uint16(0)
+	if question {
+		questions = 1
+	}
+	msg = binary.BigEndian.AppendUint16(msg, questions)
+	msg = binary.BigEndian.AppendUint16(msg, 1) // One answer
+	msg = binary.BigEndian.AppendUint16(msg, 0)
+	msg = binary.BigEndian.AppendUint16(msg, 0)
+
+	class := uint16(classIN | cacheFlush)
+	if question {
+		msg = appendName(msg, name)
+		msg = binary.BigEndian.AppendUint16(msg, typeA)
+		msg = binary.BigEndian.AppendUint16(msg, classIN)
+		class = classIN
+	}
+	msg = appendName(msg, name)
+	msg = binary.BigEndian.AppendUint16(msg, typeA)
+	msg = binary.BigEndian.AppendUint16(msg, class)
+	msg = binary.BigEndian.AppendUint32(msg, mdnsTTL)
+	msg = binary.BigEndian.AppendUint16(msg, 4)
+	return append(msg, ip.To4()...)
+}
+
+// appendName appends name in DNS wire format
+func appendName(msg []byte, name string) []byte {
+	for _, label := range strings.Split(strings.TrimSuffix(name, "."), ".") {
+		msg = append(msg, byte(len(label)))
+		msg = append(msg, label...)
+	}
+	return append(msg, 0)
+}
@@ -0,0 +1,210 @@
+package manifest
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/dokulabs/doku-cli/internal/config"
+	"github.com/dokulabs/doku-cli/internal/workspace"
+	"gopkg.in/yaml.v3"
+)
+
+// DefaultFileName is the manifest file looked up by 'doku apply'
+const DefaultFileName = "doku.yaml"
+
+// Manifest describes the desired set of services and projects for a workspace
+type Manifest struct {
+	Workspace string                   `yaml:"workspace"` // Workspace the manifest applies to, also selected for commands run in its directory
+	Services  map[string]*ServiceEntry `yaml:"services"`
+	Projects  map[string]*ProjectEntry `yaml:"projects"`
+
+	// baseDir is the directory containing the manifest; relative project
+	// paths are resolved against it
+	baseDir string
+}
+
+// ServiceEntry describes a catalog service instance
+type ServiceEntry struct {
+	Service  string            `yaml:"service"` // Catalog service (defaults to the entry name)
+	Version  string            `yaml:"version"` // Version (empty or "latest" = any)
+	Env      map[string]string `yaml:"env"`     // Environment overrides
+	Ports    []string          `yaml:"ports"`   // Host port mappings ("5432" or "5433:5432")
+	Memory   string            `yaml:"memory"`
+	CPU      string            `yaml:"cpu"`
+	Internal bool              `yaml:"internal"`
+}
+
+// ProjectEntry describes a custom project built from a Dockerfile
+type ProjectEntry struct {
+	Path       string            `yaml:"path"`
+	Dockerfile string            `yaml:"dockerfile"`
+	Port       int               `yaml:"port"`
+	Ports      []string          `yaml:"ports"`
+	Env        map[string]string `yaml:"env"`
+	Depends    []string          `yaml:"depends"`
+	Internal   bool              `yaml:"internal"`
+	Links      map[string]string `yaml:"links"` // Named pages, e.g. docs: https://api.doku.local/docs
+}
+
+// Load reads and validates a manifest file
+func Load(path string) (*Manifest, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read manifest: %w", err)
+	}
+
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return nil, fmt.Errorf("invalid manifest path: %w", err)
+	}
+
+	m, err := Parse(data)
+	if err != nil {
+		return nil, err
+	}
+	m.baseDir = filepath.Dir(absPath)
+
+	return m, nil
+}
+
+// Parse decodes and validates manifest content
+func Parse(data []byte) (*Manifest, error) {
+	var m Manifest
+	if err := yaml.Unmarshal(data, &m); err != nil {
+		return nil, fmt.Errorf("failed to parse manifest: %w", err)
+	}
+
+	if m.Services == nil {
+		m.Services = make(map[string]*ServiceEntry)
+	}
+	if m.Projects == nil {
+		m.Projects = make(map[string]*ProjectEntry)
+	}
+
+	// Fill defaults for entries declared with an empty body
+	for name, svc := range m.Services {
+		if svc == nil {
+			svc = &ServiceEntry{}
+			m.Services[name] = svc
+		}
+		if svc.Service == "" {
+			svc.Service = name
+		}
+	}
+	for name, proj := range m.Projects {
+		if proj == nil {
+			proj = &ProjectEntry{}
+			m.Projects[name] = proj
+		}
+		if proj.Dockerfile == "" {
+			proj.Dockerfile = "Dockerfile"
+		}
+	}
+
+	if err := m.Validate(); err != nil {
+		return nil, err
+	}
+
+	return &m, nil
+}
+
+// Validate checks names, paths and port mappings
+func (m *Manifest) Validate() error {
+	if m.Workspace != "" && m.Workspace != "default" {
+		if err := workspace.Validate(m.Workspace); err != nil {
+			return err
+		}
+	}
+
+	for name, svc := range m.Services {
+		if err := config.ValidateInstanceName(name); err != nil {
+			return fmt.Errorf("service '%s': %w", name, err)
+		}
+		if _, err := ParsePorts(svc.Ports); err != nil {
+			return fmt.Errorf("service '%s': %w", name, err)
+		}
+	}
+
+	for name, proj := range m.Projects {
+		if _, exists := m.Services[name]; exists {
+			return fmt.Errorf("'%s' is declared as both a service and a project", name)
+		}
+		if err := config.ValidateInstanceName(name); err != nil {
+			return fmt.Errorf("project '%s': %w", name, err)
+		}
+		if proj.Path == "" {
+			return fmt.Errorf("project '%s': path is required", name)
+		}
+		if _, err := ParsePorts(proj.Ports); err != nil {
+			return fmt.Errorf("project '%s': %w", name, err)
+		}
+		for linkName, url := range proj.Links {
+			if err := config.ValidateLink(linkName, url); err != nil {
+				return fmt.Errorf("project '%s': %w", name, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// ProjectPath returns the absolute path of a project, resolved relative to the manifest
+func (m *Manifest) ProjectPath(proj *ProjectEntry) string {
+	if filepath.IsAbs(proj.Path) || m.baseDir == "" {
+		return filepath.Clean(proj.Path)
+	}
+	return filepath.Join(m.baseDir, proj.Path)
+}
+
+// ServiceNames returns the declared service names in sorted order
+func (m *Manifest) ServiceNames() []string {
+	names := make([]string, 0, len(m.Services))
+	for name := range m.Services {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ProjectNames returns the declared project names in sorted order
+func (m *Manifest) ProjectNames() []string {
+	names := make([]string, 0, len(m.Projects))
+	for name := range m.Projects {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ParsePorts converts "port" or "host:container" strings into a
+// containerPort -> hostPort map, matching the format used by 'doku install --port'
+func ParsePorts(ports []string) (map[string]string, error) {
+	if len(ports) == 0 {
+		return nil, nil
+	}
+
+	mappings := make(map[string]string)
+	for _, p := range ports {
+		parts := strings.Split(p, ":")
+		for _, part := range parts {
+			if _, err := strconv.Atoi(part); err != nil {
+				return nil, fmt.Errorf("invalid port mapping '%s'", p)
+			}
+		}
+
+		switch len(parts) {
+		case 1:
+			mappings[parts[0]] = parts[0]
+		case 2:
+			mappings[parts[1]] = parts[0]
+		default:
+			return nil, fmt.Errorf("invalid port mapping format '%s' (use 'port' or 'host:container')", p)
+		}
+	}
+
+	return mappings, nil
+}
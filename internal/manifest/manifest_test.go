@@ -0,0 +1,218 @@
+package manifest
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/dokulabs/doku-cli/pkg/types"
+)
+
+const testManifest = `
+services:
+  postgres:
+    version: "16"
+    env:
+      POSTGRES_PASSWORD: secret
+    ports: ["5432"]
+  cache:
+    service: redis
+projects:
+  api:
+    path: ./api
+    port: 3000
+    depends: [postgres]
+`
+
+func TestParse(t *testing.T) {
+	m, err := Parse([]byte(testManifest))
+	if err != nil {
+		t.Fatalf("Failed to parse manifest: %v", err)
+	}
+
+	if len(m.Services) != 2 {
+		t.Fatalf("Expected 2 services, got %d", len(m.Services))
+	}
+
+	if m.Services["postgres"].Service != "postgres" {
+		t.Errorf("Expected service to default to entry name, got '%s'", m.Services["postgres"].Service)
+	}
+
+	if m.Services["cache"].Service != "redis" {
+		t.Errorf("Expected service 'redis', got '%s'", m.Services["cache"].Service)
+	}
+
+	if m.Projects["api"].Dockerfile != "Dockerfile" {
+		t.Errorf("Expected default Dockerfile, got '%s'", m.Projects["api"].Dockerfile)
+	}
+}
+
+func TestParseInvalid(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+	}{
+		{"invalid instance name", "services:\n  Postgres: {}\n"},
+		{"reserved name", "services:\n  traefik: {}\n"},
+		{"invalid port", "services:\n  postgres:\n    ports: [\"abc\"]\n"},
+		{"project without path", "projects:\n  api:\n    port: 3000\n"},
+		{"duplicate name", "services:\n  api: {}\nprojects:\n  api:\n    path: ./api\n"},
+		{"invalid link", "projects:\n  api:\n    path: ./api\n    links:\n      docs: localhost/docs\n"},
+		{"invalid workspace", "workspace: Work\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Parse([]byte(tt.content)); err == nil {
+				t.Error("Expected error, got nil")
+			}
+		})
+	}
+}
+
+func TestLoadResolvesProjectPath(t *testing.T) {
+	tmpDir := t.TempDir()
+	path := filepath.Join(tmpDir, DefaultFileName)
+	if err := os.WriteFile(path, []byte(testManifest), 0644); err != nil {
+		t.Fatalf("Failed to write manifest: %v", err)
+	}
+
+	m, err := Load(path)
+	if err != nil {
+		t.Fatalf("Failed to load manifest: %v", err)
+	}
+
+	expected := filepath.Join(tmpDir, "api")
+	if got := m.ProjectPath(m.Projects["api"]); got != expected {
+		t.Errorf("Expected project path %s, got %s", expected, got)
+	}
+}
+
+func TestParsePorts(t *testing.T) {
+	mappings, err := ParsePorts([]string{"5432", "8081:8080"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if mappings["5432"] != "5432" {
+		t.Errorf("Expected 5432 -> 5432, got %s", mappings["5432"])
+	}
+
+	if mappings["8080"] != "8081" {
+		t.Errorf("Expected container 8080 -> host 8081, got %s", mappings["8080"])
+	}
+
+	if _, err := ParsePorts([]string{"1:2:3"}); err == nil {
+		t.Error("Expected error for invalid mapping")
+	}
+}
+
+func TestBuildPlan(t *testing.T) {
+	m, err := Parse([]byte(testManifest))
+	if err != nil {
+		t.Fatalf("Failed to parse manifest: %v", err)
+	}
+
+	cfg := &types.Config{
+		Instances: map[string]*types.Instance{
+			"postgres": {
+				Name:        "postgres",
+				ServiceType: "postgres",
+				Version:     "15",
+				Network:     types.NetworkConfig{PortMappings: map[string]string{"5432": "5432"}},
+			},
+			"mysql": {Name: "mysql", ServiceType: "mysql"},
+		},
+		Projects: map[string]*types.Project{},
+	}
+
+	env := map[string]map[string]string{
+		"postgres": {"POSTGRES_PASSWORD": "old"},
+	}
+	loadEnv := func(name string) map[string]string { return env[name] }
+
+	actions := BuildPlan(m, cfg, loadEnv, PlanOptions{})
+	expected := []Action{
+		{Type: ActionInstall, Name: "cache"},
+		{Type: ActionUpgrade, Name: "postgres"},
+		{Type: ActionUpdateEnv, Name: "postgres"},
+		{Type: ActionAddProject, Name: "api"},
+	}
+	assertActions(t, actions, expected)
+
+	// Prune removes undeclared instances only when requested
+	actions = BuildPlan(m, cfg, loadEnv, PlanOptions{Prune: true})
+	assertActions(t, actions, append(expected, Action{Type: ActionRemove, Name: "mysql"}))
+}
+
+func TestBuildPlanUpToDate(t *testing.T) {
+	m, err := Parse([]byte("services:\n  postgres:\n    version: \"16\"\n    env:\n      A: b\n"))
+	if err != nil {
+		t.Fatalf("Failed to parse manifest: %v", err)
+	}
+
+	cfg := &types.Config{
+		Instances: map[string]*types.Instance{
+			"postgres": {Name: "postgres", ServiceType: "postgres", Version: "16"},
+		},
+	}
+	loadEnv := func(name string) map[string]string { return map[string]string{"A": "b", "EXTRA": "kept"} }
+
+	if actions := BuildPlan(m, cfg, loadEnv, PlanOptions{Prune: true}); len(actions) != 0 {
+		t.Errorf("Expected no actions, got %v", actions)
+	}
+}
+
+func TestBuildPlanPortDrift(t *testing.T) {
+	m, err := Parse([]byte("services:\n  postgres:\n    ports: [\"5433:5432\"]\n"))
+	if err != nil {
+		t.Fatalf("Failed to parse manifest: %v", err)
+	}
+
+	cfg := &types.Config{
+		Instances: map[string]*types.Instance{
+			"postgres": {
+				Name:        "postgres",
+				ServiceType: "postgres",
+				Network:     types.NetworkConfig{PortMappings: map[string]string{"5432": "5432"}},
+			},
+		},
+	}
+	loadEnv := func(name string) map[string]string { return nil }
+
+	assertActions(t, BuildPlan(m, cfg, loadEnv, PlanOptions{}), []Action{{Type: ActionReinstall, Name: "postgres"}})
+}
+
+func TestBuildPlanLinkDrift(t *testing.T) {
+	m, err := Parse([]byte("projects:\n  api:\n    path: /src/api\n    links:\n      docs: https://api.doku.local/docs\n"))
+	if err != nil {
+		t.Fatalf("Failed to parse manifest: %v", err)
+	}
+
+	cfg := &types.Config{
+		Projects: map[string]*types.Project{
+			"api": {Name: "api", Path: "/src/api", Dockerfile: "Dockerfile"},
+		},
+	}
+	loadEnv := func(name string) map[string]string { return nil }
+
+	assertActions(t, BuildPlan(m, cfg, loadEnv, PlanOptions{}), []Action{{Type: ActionUpdateProject, Name: "api"}})
+
+	cfg.Projects["api"].Links = map[string]string{"docs": "https://api.doku.local/docs"}
+	assertActions(t, BuildPlan(m, cfg, loadEnv, PlanOptions{}), nil)
+}
+
+func assertActions(t *testing.T, got, expected []Action) {
+	t.Helper()
+
+	if len(got) != len(expected) {
+		t.Fatalf("Expected %d actions, got %d: %v", len(expected), len(got), got)
+	}
+
+	for i := range expected {
+		if got[i].Type != expected[i].Type || got[i].Name != expected[i].Name {
+			t.Errorf("Action %d: expected %s %s, got %s %s",
+				i, expected[i].Type, expected[i].Name, got[i].Type, got[i].Name)
+		}
+	}
+}
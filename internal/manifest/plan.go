@@ -0,0 +1,213 @@
+package manifest
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/dokulabs/doku-cli/pkg/types"
+)
+
+// ActionType identifies what 'doku apply' will do to reconcile an entry
+type ActionType string
+
+const (
+	ActionInstall       ActionType = "install"
+	ActionUpgrade       ActionType = "upgrade"
+	ActionUpdateEnv     ActionType = "update-env"
+	ActionReinstall     ActionType = "reinstall"
+	ActionRemove        ActionType = "remove"
+	ActionAddProject    ActionType = "add-project"
+	ActionUpdateProject ActionType = "update-project"
+	ActionRemoveProject ActionType = "remove-project"
+)
+
+// Action is a single reconciliation step
+type Action struct {
+	Type   ActionType
+	Name   string
+	Reason string
+}
+
+// EnvLoader returns the current environment of an instance or project
+type EnvLoader func(name string) map[string]string
+
+// PlanOptions controls how the plan is built
+type PlanOptions struct {
+	Prune bool // Remove instances and projects not declared in the manifest
+}
+
+// BuildPlan compares the manifest against the current configuration and
+// returns the actions needed to reconcile them. Services come first so
+// projects can depend on them; removals come last.
+func BuildPlan(m *Manifest, cfg *types.Config, loadEnv EnvLoader, opts PlanOptions) []Action {
+	var actions []Action
+
+	for _, name := range m.ServiceNames() {
+		entry := m.Services[name]
+		instance, exists := cfg.Instances[name]
+		if !exists {
+			actions = append(actions, Action{Type: ActionInstall, Name: name, Reason: describeService(entry)})
+			continue
+		}
+
+		if instance.ServiceType != entry.Service {
+			actions = append(actions, Action{
+				Type:   ActionReinstall,
+				Name:   name,
+				Reason: fmt.Sprintf("service %s → %s", instance.ServiceType, entry.Service),
+			})
+			continue
+		}
+
+		desiredPorts, _ := ParsePorts(entry.Ports)
+		if len(entry.Ports) > 0 && !mapsEqual(desiredPorts, instance.Network.PortMappings) {
+			actions = append(actions, Action{
+				Type:   ActionReinstall,
+				Name:   name,
+				Reason: fmt.Sprintf("ports %s → %s", formatPorts(instance.Network.PortMappings), formatPorts(desiredPorts)),
+			})
+			continue
+		}
+
+		if entry.Version != "" && entry.Version != "latest" && entry.Version != instance.Version {
+			actions = append(actions, Action{
+				Type:   ActionUpgrade,
+				Name:   name,
+				Reason: fmt.Sprintf("version %s → %s", instance.Version, entry.Version),
+			})
+		}
+
+		if keys := driftedKeys(entry.Env, loadEnv(name)); len(keys) > 0 {
+			actions = append(actions, Action{
+				Type:   ActionUpdateEnv,
+				Name:   name,
+				Reason: "env " + strings.Join(keys, ", "),
+			})
+		}
+	}
+
+	for _, name := range m.ProjectNames() {
+		entry := m.Projects[name]
+		project, exists := cfg.Projects[name]
+		if !exists {
+			actions = append(actions, Action{Type: ActionAddProject, Name: name, Reason: m.ProjectPath(entry)})
+			continue
+		}
+
+		var reasons []string
+		if project.Path != m.ProjectPath(entry) {
+			reasons = append(reasons, fmt.Sprintf("path %s → %s", project.Path, m.ProjectPath(entry)))
+		}
+		if project.Dockerfile != entry.Dockerfile {
+			reasons = append(reasons, fmt.Sprintf("dockerfile %s → %s", project.Dockerfile, entry.Dockerfile))
+		}
+		if project.Port != entry.Port {
+			reasons = append(reasons, fmt.Sprintf("port %d → %d", project.Port, entry.Port))
+		}
+		if keys := driftedKeys(entry.Env, loadEnv(name)); len(keys) > 0 {
+			reasons = append(reasons, "env "+strings.Join(keys, ", "))
+		}
+		if entry.Links != nil && !mapsEqual(entry.Links, project.Links) {
+			reasons = append(reasons, "links")
+		}
+		if len(reasons) > 0 {
+			actions = append(actions, Action{Type: ActionUpdateProject, Name: name, Reason: strings.Join(reasons, "; ")})
+		}
+	}
+
+	if opts.Prune {
+		actions = append(actions, pruneActions(m, cfg)...)
+	}
+
+	return actions
+}
+
+// pruneActions returns removals for undeclared instances and projects.
+// Instances that a declared service depends on are kept.
+func pruneActions(m *Manifest, cfg *types.Config) []Action {
+	var actions []Action
+
+	keep := make(map[string]bool)
+	for name := range m.Services {
+		keep[name] = true
+		if instance, exists := cfg.Instances[name]; exists {
+			for _, dep := range instance.Dependencies {
+				keep[dep] = true
+			}
+		}
+	}
+	for _, entry := range m.Projects {
+		for _, dep := range entry.Depends {
+			keep[strings.Split(dep, ":")[0]] = true
+		}
+	}
+
+	names := make([]string, 0, len(cfg.Instances))
+	for name := range cfg.Instances {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if !keep[name] {
+			actions = append(actions, Action{Type: ActionRemove, Name: name, Reason: "not declared in manifest"})
+		}
+	}
+
+	names = names[:0]
+	for name := range cfg.Projects {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if _, declared := m.Projects[name]; !declared {
+			actions = append(actions, Action{Type: ActionRemoveProject, Name: name, Reason: "not declared in manifest"})
+		}
+	}
+
+	return actions
+}
+
+// driftedKeys returns the desired keys whose current value differs
+func driftedKeys(desired, current map[string]string) []string {
+	var keys []string
+	for key, value := range desired {
+		if current[key] != value {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func describeService(entry *ServiceEntry) string {
+	if entry.Version == "" {
+		return entry.Service
+	}
+	return entry.Service + ":" + entry.Version
+}
+
+func mapsEqual(a, b map[string]string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for k, v := range a {
+		if b[k] != v {
+			return false
+		}
+	}
+	return true
+}
+
+// formatPorts renders containerPort -> hostPort mappings as host:container
+func formatPorts(mappings map[string]string) string {
+	if len(mappings) == 0 {
+		return "none"
+	}
+	parts := make([]string, 0, len(mappings))
+	for containerPort, hostPort := range mappings {
+		parts = append(parts, hostPort+":"+containerPort)
+	}
+	sort.Strings(parts)
+	return strings.Join(parts, ",")
+}
@@ -0,0 +1,323 @@
+// Package migration packs a Doku setup into a bundle, a .tar.gz file, to
+// move it to another machine: the configuration, the env files and the
+// profiles. Certificates are described but not included, since their keys
+// are tied to the machine's local CA; they are generated again on import.
+package migration
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/dokulabs/doku-cli/internal/certs"
+	"github.com/dokulabs/doku-cli/internal/config"
+	"github.com/dokulabs/doku-cli/internal/readonly"
+)
+
+const (
+	// FormatVersion is the version of the bundle layout
+	FormatVersion = "1"
+
+	// ManifestName is the bundle's description, first in the archive
+	ManifestName = "bundle.json"
+)
+
+// bundledDirs are the directories of the Doku directory a bundle includes.
+// The catalog, Traefik config and certificates are set up again on import,
+// and backups are left out for their size.
+var bundledDirs = []string{"services", "projects", "profiles"}
+
+// Manifest describes a bundle
+type Manifest struct {
+	FormatVersion string            `json:"format_version"`
+	CreatedAt     time.Time         `json:"created_at"`
+	DokuVersion   string            `json:"doku_version,omitempty"`
+	Workspace     string            `json:"workspace,omitempty"` // Workspace exported ("" = default)
+	Files         []string          `json:"files"`               // Paths relative to the Doku directory
+	Certificates  []CertificateInfo `json:"certificates,omitempty"`
+}
+
+// CertificateInfo describes a certificate of the exported setup
+type CertificateInfo struct {
+	File     string    `json:"file"`
+	Domains  []string  `json:"domains"`
+	NotAfter time.Time `json:"not_after"`
+}
+
+// ExportOptions holds options for Export
+type ExportOptions struct {
+	DokuDir     string // Doku directory of the exported workspace
+	OutputPath  string
+	DokuVersion string
+	Workspace   string
+}
+
+// Export writes a bundle of the Doku directory
+func Export(opts ExportOptions) (*Manifest, error) {
+	if _, err := os.Stat(filepath.Join(opts.DokuDir, config.ConfigFileName)); err != nil {
+		return nil, fmt.Errorf("no configuration to export in %s: %w", opts.DokuDir, err)
+	}
+
+	manifest := &Manifest{
+		FormatVersion: FormatVersion,
+		CreatedAt:     time.Now(),
+		DokuVersion:   opts.DokuVersion,
+		Workspace:     opts.Workspace,
+		Files:         []string{config.ConfigFileName},
+	}
+
+	for _, dir := range bundledDirs {
+		files, err := listFiles(opts.DokuDir, dir)
+		if err != nil {
+			return nil, err
+		}
+		manifest.Files = append(manifest.Files, files...)
+	}
+
+	manifest.Certificates = describeCertificates(filepath.Join(opts.DokuDir, "certs"))
+
+	if dir := filepath.Dir(opts.OutputPath); dir != "." {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return nil, fmt.Errorf("failed to create output directory: %w", err)
+		}
+	}
+	// Env files hold passwords, so the bundle is private
+	file, err := os.OpenFile(opts.OutputPath, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create bundle: %w", err)
+	}
+	defer file.Close()
+
+	gzWriter := gzip.NewWriter(file)
+	tarWriter := tar.NewWriter(gzWriter)
+
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode bundle manifest: %w", err)
+	}
+	if err := writeEntry(tarWriter, ManifestName, data, 0644); err != nil {
+		return nil, err
+	}
+
+	for _, rel := range manifest.Files {
+		path := filepath.Join(opts.DokuDir, filepath.FromSlash(rel))
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", path, err)
+		}
+		if err := writeEntry(tarWriter, rel, data, 0600); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tarWriter.Close(); err != nil {
+		return nil, fmt.Errorf("failed to write bundle: %w", err)
+	}
+	if err := gzWriter.Close(); err != nil {
+		return nil, fmt.Errorf("failed to write bundle: %w", err)
+	}
+	return manifest, nil
+}
+
+// ReadManifest reads the description of a bundle
+func ReadManifest(bundlePath string) (*Manifest, error) {
+	var manifest *Manifest
+	err := walkBundle(bundlePath, func(name string, r io.Reader) error {
+		if name != ManifestName {
+			return nil
+		}
+		manifest = &Manifest{}
+		if err := json.NewDecoder(r).Decode(manifest); err != nil {
+			return fmt.Errorf("failed to parse bundle manifest: %w", err)
+		}
+		return errStop
+	})
+	if err != nil {
+		return nil, err
+	}
+	if manifest == nil {
+		return nil, fmt.Errorf("%s is not a Doku bundle: it has no %s", bundlePath, ManifestName)
+	}
+	if manifest.FormatVersion != FormatVersion {
+		return nil, fmt.Errorf("unsupported bundle format %q: upgrade Doku to import it", manifest.FormatVersion)
+	}
+	return manifest, nil
+}
+
+// Import restores the files of a bundle into a Doku directory, replacing
+// those with the same name. It returns the bundle's manifest.
+func Import(bundlePath, dokuDir string) (*Manifest, error) {
+	if err := readonly.Check("import " + bundlePath); err != nil {
+		return nil, err
+	}
+
+	manifest, err := ReadManifest(bundlePath)
+	if err != nil {
+		return nil, err
+	}
+	listed := make(map[string]bool, len(manifest.Files))
+	for _, rel := range manifest.Files {
+		listed[rel] = true
+	}
+
+	err = walkBundle(bundlePath, func(name string, r io.Reader) error {
+		if name == ManifestName {
+			return nil
+		}
+		if !listed[name] || !isBundledPath(name) {
+			return fmt.Errorf("unexpected file in bundle: %s", name)
+		}
+
+		path := filepath.Join(dokuDir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			return fmt.Errorf("failed to create %s: %w", filepath.Dir(path), err)
+		}
+		file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0600)
+		if err != nil {
+			return fmt.Errorf("failed to restore %s: %w", path, err)
+		}
+		if _, err := io.Copy(file, r); err != nil {
+			file.Close()
+			return fmt.Errorf("failed to restore %s: %w", path, err)
+		}
+		return file.Close()
+	})
+	if err != nil {
+		return nil, err
+	}
+	return manifest, nil
+}
+
+// errStop ends walkBundle early
+var errStop = errors.New("stop")
+
+// walkBundle calls fn with each file of a bundle
+func walkBundle(bundlePath string, fn func(name string, r io.Reader) error) error {
+	file, err := os.Open(bundlePath)
+	if err != nil {
+		return fmt.Errorf("failed to open bundle: %w", err)
+	}
+	defer file.Close()
+
+	gzReader, err := gzip.NewReader(file)
+	if err != nil {
+		return fmt.Errorf("%s is not a Doku bundle: %w", bundlePath, err)
+	}
+	defer gzReader.Close()
+
+	tarReader := tar.NewReader(gzReader)
+	for {
+		header, err := tarReader.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read bundle: %w", err)
+		}
+		if header.Typeflag != tar.TypeReg {
+			continue
+		}
+		if err := fn(header.Name, tarReader); err != nil {
+			if err == errStop {
+				return nil
+			}
+			return err
+		}
+	}
+}
+
+// isBundledPath reports whether a path of a bundle is one Export writes,
+// so that a crafted bundle can't write outside the Doku directory
+func isBundledPath(name string) bool {
+	if name == config.ConfigFileName {
+		return true
+	}
+	if name != filepath.ToSlash(filepath.Clean(name)) || strings.HasPrefix(name, "/") || strings.Contains(name, "..") {
+		return false
+	}
+	for _, dir := range bundledDirs {
+		if strings.HasPrefix(name, dir+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// listFiles returns the regular files below a directory of the Doku
+// directory, relative to it with forward slashes
+func listFiles(dokuDir, dir string) ([]string, error) {
+	var files []string
+	err := filepath.WalkDir(filepath.Join(dokuDir, dir), func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) {
+				return nil
+			}
+			return err
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+		rel, err := filepath.Rel(dokuDir, path)
+		if err != nil {
+			return err
+		}
+		files = append(files, filepath.ToSlash(rel))
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list %s: %w", dir, err)
+	}
+	sort.Strings(files)
+	return files, nil
+}
+
+// describeCertificates returns the domains and expiry of the certificates
+// in a directory, skipping their keys
+func describeCertificates(certsDir string) []CertificateInfo {
+	entries, err := os.ReadDir(certsDir)
+	if err != nil {
+		return nil
+	}
+
+	var infos []CertificateInfo
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasSuffix(name, ".pem") || strings.HasSuffix(name, "-key.pem") {
+			continue
+		}
+		path := filepath.Join(certsDir, name)
+		domains, err := certs.GetCertificateDomains(path)
+		if err != nil {
+			continue
+		}
+		notAfter, _ := certs.GetCertificateExpiry(path)
+		infos = append(infos, CertificateInfo{File: name, Domains: domains, NotAfter: notAfter})
+	}
+	return infos
+}
+
+func writeEntry(tarWriter *tar.Writer, name string, data []byte, mode int64) error {
+	header := &tar.Header{
+		Name:     name,
+		Size:     int64(len(data)),
+		Mode:     mode,
+		ModTime:  time.Now(),
+		Typeflag: tar.TypeReg,
+	}
+	if err := tarWriter.WriteHeader(header); err != nil {
+		return fmt.Errorf("failed to write %s to bundle: %w", name, err)
+	}
+	if _, err := tarWriter.Write(data); err != nil {
+		return fmt.Errorf("failed to write %s to bundle: %w", name, err)
+	}
+	return nil
+}
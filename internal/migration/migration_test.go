@@ -0,0 +1,109 @@
+package migration
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestExportImport(t *testing.T) {
+	src := t.TempDir()
+	writeFile(t, filepath.Join(src, "config.toml"), "[Preferences]\nDomain = \"doku.local\"\n")
+	writeFile(t, filepath.Join(src, "services", "postgres.env"), "POSTGRES_PASSWORD=secret\n")
+	writeFile(t, filepath.Join(src, "projects", "api.env"), "PORT=3000\n")
+	writeFile(t, filepath.Join(src, "profiles", "postgres.toml"), "service = \"postgres\"\n")
+	writeFile(t, filepath.Join(src, "catalog", "catalog.yaml"), "version: 1\n")
+	writeFile(t, filepath.Join(src, "certs", "doku.local-key.pem"), "key\n")
+
+	bundle := filepath.Join(t.TempDir(), "bundle.tar.gz")
+	manifest, err := Export(ExportOptions{DokuDir: src, OutputPath: bundle, DokuVersion: "1.2.0", Workspace: "work"})
+	if err != nil {
+		t.Fatalf("Export() failed: %v", err)
+	}
+	want := []string{"config.toml", "services/postgres.env", "projects/api.env", "profiles/postgres.toml"}
+	if len(manifest.Files) != len(want) {
+		t.Fatalf("Files = %v, expected %v", manifest.Files, want)
+	}
+	for i, file := range want {
+		if manifest.Files[i] != file {
+			t.Errorf("Files[%d] = %s, expected %s", i, manifest.Files[i], file)
+		}
+	}
+
+	if info, err := os.Stat(bundle); err != nil || info.Mode().Perm() != 0600 {
+		t.Errorf("the bundle should be private: %v, %v", info.Mode(), err)
+	}
+
+	read, err := ReadManifest(bundle)
+	if err != nil || read.Workspace != "work" || read.DokuVersion != "1.2.0" {
+		t.Fatalf("ReadManifest() = %+v, %v", read, err)
+	}
+
+	dest := t.TempDir()
+	if _, err := Import(bundle, dest); err != nil {
+		t.Fatalf("Import() failed: %v", err)
+	}
+	for _, file := range want {
+		srcData, _ := os.ReadFile(filepath.Join(src, file))
+		destData, err := os.ReadFile(filepath.Join(dest, file))
+		if err != nil || string(destData) != string(srcData) {
+			t.Errorf("%s = %q, %v, expected %q", file, destData, err, srcData)
+		}
+	}
+	for _, file := range []string{"catalog/catalog.yaml", "certs/doku.local-key.pem"} {
+		if _, err := os.Stat(filepath.Join(dest, file)); err == nil {
+			t.Errorf("%s shouldn't be imported", file)
+		}
+	}
+}
+
+func TestExportWithoutConfig(t *testing.T) {
+	if _, err := Export(ExportOptions{DokuDir: t.TempDir(), OutputPath: filepath.Join(t.TempDir(), "b.tar.gz")}); err == nil {
+		t.Error("Export() should fail without a configuration")
+	}
+}
+
+func TestImportRejectsUnexpectedPaths(t *testing.T) {
+	bundle := filepath.Join(t.TempDir(), "evil.tar.gz")
+	file, err := os.Create(bundle)
+	if err != nil {
+		t.Fatal(err)
+	}
+	gzWriter := gzip.NewWriter(file)
+	tarWriter := tar.NewWriter(gzWriter)
+	writeEntry(tarWriter, ManifestName, []byte(`{"format_version": "1", "files": ["services/../../evil"]}`), 0644)
+	writeEntry(tarWriter, "services/../../evil", []byte("x"), 0644)
+	tarWriter.Close()
+	gzWriter.Close()
+	file.Close()
+
+	dest := filepath.Join(t.TempDir(), "doku")
+	if _, err := Import(bundle, dest); err == nil {
+		t.Error("Import() should reject a path outside the bundled directories")
+	}
+}
+
+func TestIsBundledPath(t *testing.T) {
+	for _, name := range []string{"config.toml", "services/postgres.env", "profiles/redis.toml"} {
+		if !isBundledPath(name) {
+			t.Errorf("isBundledPath(%q) = false", name)
+		}
+	}
+	for _, name := range []string{"catalog/catalog.yaml", "/etc/passwd", "services/../config", "services/./x", "traefik/traefik.yml"} {
+		if isBundledPath(name) {
+			t.Errorf("isBundledPath(%q) = true", name)
+		}
+	}
+}
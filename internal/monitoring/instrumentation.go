@@ -2,8 +2,10 @@ package monitoring
 
 import (
 	"fmt"
+	"strconv"
 
 	dockerTypes "github.com/docker/docker/api/types/container"
+	"github.com/docker/go-units"
 	"github.com/dokulabs/doku-cli/pkg/types"
 )
 
@@ -83,14 +85,23 @@ func getSentryEnv(serviceName string, config *types.MonitoringConfig) map[string
 	return env
 }
 
-// GetDockerLoggingConfig returns Docker logging driver configuration for monitoring
-func GetDockerLoggingConfig(monitoringConfig *types.MonitoringConfig) *dockerTypes.LogConfig {
+// Rotation of container logs when neither the preferences nor the instance
+// set one
+const (
+	DefaultLogMaxSize = "10m"
+	DefaultLogMaxFile = 3
+)
+
+// GetDockerLoggingConfig returns Docker logging driver configuration for
+// monitoring, with the logs rotated as rotation says
+func GetDockerLoggingConfig(monitoringConfig *types.MonitoringConfig, rotation types.LogRotation) *dockerTypes.LogConfig {
+	rotation = ResolveLogRotation(rotation, nil)
 	if monitoringConfig == nil || !monitoringConfig.Enabled || monitoringConfig.Tool == "none" {
 		return &dockerTypes.LogConfig{
 			Type: "json-file",
 			Config: map[string]string{
-				"max-size": "10m",
-				"max-file": "3",
+				"max-size": rotation.MaxSize,
+				"max-file": strconv.Itoa(rotation.MaxFile),
 			},
 		}
 	}
@@ -100,14 +111,69 @@ func GetDockerLoggingConfig(monitoringConfig *types.MonitoringConfig) *dockerTyp
 	return &dockerTypes.LogConfig{
 		Type: "json-file",
 		Config: map[string]string{
-			"max-size": "10m",
-			"max-file": "3",
+			"max-size": rotation.MaxSize,
+			"max-file": strconv.Itoa(rotation.MaxFile),
 			"labels":   "service,monitoring,managed-by",
 			"tag":      "{{.Name}}",
 		},
 	}
 }
 
+// ResolveLogRotation returns the rotation of an instance's logs: its
+// override, or else the preferences', field by field, or else the defaults
+func ResolveLogRotation(preferences types.LogRotation, override *types.LogRotation) types.LogRotation {
+	rotation := preferences
+	if override != nil {
+		if override.MaxSize != "" {
+			rotation.MaxSize = override.MaxSize
+		}
+		if override.MaxFile > 0 {
+			rotation.MaxFile = override.MaxFile
+		}
+	}
+	if rotation.MaxSize == "" {
+		rotation.MaxSize = DefaultLogMaxSize
+	}
+	if rotation.MaxFile <= 0 {
+		rotation.MaxFile = DefaultLogMaxFile
+	}
+	return rotation
+}
+
+// ApplyLogRotation returns a container's log configuration with its logs
+// rotated as rotation says, keeping its other options. Only the json-file
+// and local drivers rotate; other drivers are left alone.
+func ApplyLogRotation(logConfig dockerTypes.LogConfig, rotation types.LogRotation) dockerTypes.LogConfig {
+	if logConfig.Type != "json-file" && logConfig.Type != "local" {
+		return logConfig
+	}
+	rotation = ResolveLogRotation(rotation, nil)
+
+	options := make(map[string]string, len(logConfig.Config)+2)
+	for key, value := range logConfig.Config {
+		options[key] = value
+	}
+	options["max-size"] = rotation.MaxSize
+	options["max-file"] = strconv.Itoa(rotation.MaxFile)
+	logConfig.Config = options
+	return logConfig
+}
+
+// ValidateLogRotation checks a rotation's size is a size Docker takes,
+// e.g. "10m", and its file count isn't negative
+func ValidateLogRotation(rotation types.LogRotation) error {
+	if rotation.MaxSize != "" {
+		size, err := units.RAMInBytes(rotation.MaxSize)
+		if err != nil || size <= 0 {
+			return fmt.Errorf("invalid log size: %s (e.g. 10m or 1g)", rotation.MaxSize)
+		}
+	}
+	if rotation.MaxFile < 0 {
+		return fmt.Errorf("invalid number of log files: %d", rotation.MaxFile)
+	}
+	return nil
+}
+
 // GetServiceLabels returns Docker labels for monitoring
 func GetServiceLabels(serviceName string, monitoringConfig *types.MonitoringConfig) map[string]string {
 	labels := make(map[string]string)
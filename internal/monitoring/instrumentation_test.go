@@ -0,0 +1,59 @@
+package monitoring
+
+import (
+	"testing"
+
+	dockerTypes "github.com/docker/docker/api/types/container"
+	"github.com/dokulabs/doku-cli/pkg/types"
+)
+
+func TestResolveLogRotation(t *testing.T) {
+	tests := []struct {
+		name        string
+		preferences types.LogRotation
+		override    *types.LogRotation
+		want        types.LogRotation
+	}{
+		{"defaults", types.LogRotation{}, nil, types.LogRotation{MaxSize: "10m", MaxFile: 3}},
+		{"preferences", types.LogRotation{MaxSize: "50m", MaxFile: 5}, nil, types.LogRotation{MaxSize: "50m", MaxFile: 5}},
+		{"override", types.LogRotation{MaxSize: "50m"}, &types.LogRotation{MaxSize: "1g"}, types.LogRotation{MaxSize: "1g", MaxFile: 3}},
+		{"partial override", types.LogRotation{MaxSize: "50m", MaxFile: 5}, &types.LogRotation{MaxFile: 2}, types.LogRotation{MaxSize: "50m", MaxFile: 2}},
+	}
+	for _, tt := range tests {
+		if got := ResolveLogRotation(tt.preferences, tt.override); got != tt.want {
+			t.Errorf("%s: ResolveLogRotation() = %+v, want %+v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestApplyLogRotation(t *testing.T) {
+	logConfig := dockerTypes.LogConfig{
+		Type:   "json-file",
+		Config: map[string]string{"max-size": "10m", "max-file": "3", "tag": "{{.Name}}"},
+	}
+	got := ApplyLogRotation(logConfig, types.LogRotation{MaxSize: "50m"})
+	if got.Config["max-size"] != "50m" || got.Config["max-file"] != "3" || got.Config["tag"] != "{{.Name}}" {
+		t.Errorf("ApplyLogRotation() = %v", got.Config)
+	}
+	if logConfig.Config["max-size"] != "10m" {
+		t.Error("ApplyLogRotation() modified its argument")
+	}
+
+	syslog := dockerTypes.LogConfig{Type: "syslog"}
+	if got := ApplyLogRotation(syslog, types.LogRotation{MaxSize: "50m"}); got.Config != nil {
+		t.Errorf("ApplyLogRotation() changed a syslog config: %v", got.Config)
+	}
+}
+
+func TestValidateLogRotation(t *testing.T) {
+	for _, rotation := range []types.LogRotation{{}, {MaxSize: "10m"}, {MaxSize: "1g", MaxFile: 5}} {
+		if err := ValidateLogRotation(rotation); err != nil {
+			t.Errorf("ValidateLogRotation(%+v) = %v", rotation, err)
+		}
+	}
+	for _, rotation := range []types.LogRotation{{MaxSize: "big"}, {MaxSize: "0"}, {MaxFile: -1}} {
+		if err := ValidateLogRotation(rotation); err == nil {
+			t.Errorf("ValidateLogRotation(%+v) accepted", rotation)
+		}
+	}
+}
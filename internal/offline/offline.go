@@ -0,0 +1,360 @@
+// Package offline packs the service catalog and the images of services into
+// a bundle, a .tar.gz file, for machines without access to GitHub or image
+// registries. Loading a bundle puts its catalog in place and its images in
+// Docker's cache, where the installer finds them instead of pulling.
+package offline
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/dokulabs/doku-cli/internal/catalog"
+	"github.com/dokulabs/doku-cli/internal/readonly"
+)
+
+const (
+	// FormatVersion is the version of the bundle layout
+	FormatVersion = "1"
+
+	// ManifestName is the bundle's description, first in the archive
+	ManifestName = "offline.json"
+
+	// ImagesName is the docker save archive of the bundle's images
+	ImagesName = "images.tar"
+)
+
+// catalogDirs are the directories of the Doku directory a bundle includes:
+// the official catalog, and the other sources with the local services
+var catalogDirs = []string{"catalog", catalog.SourcesDirName}
+
+// Manifest describes a bundle
+type Manifest struct {
+	FormatVersion  string    `json:"format_version"`
+	CreatedAt      time.Time `json:"created_at"`
+	DokuVersion    string    `json:"doku_version,omitempty"`
+	CatalogVersion string    `json:"catalog_version,omitempty"`
+	Services       []string  `json:"services,omitempty"` // Services whose images are included, as name:version
+	Images         []string  `json:"images,omitempty"`
+	Arch           string    `json:"arch,omitempty"` // Architecture of the images
+}
+
+// BundleOptions holds options for Bundle
+type BundleOptions struct {
+	DokuDir        string // Doku directory, holding the catalog
+	OutputPath     string
+	DokuVersion    string
+	CatalogVersion string
+	Services       []string
+	Images         []string
+	Arch           string
+
+	// SaveImages writes Images to w as a tar archive, e.g. with docker save
+	SaveImages func(images []string, w io.Writer) error
+}
+
+// Bundle writes a bundle of the catalog and, when opts.Images is set, of
+// the images saved by opts.SaveImages
+func Bundle(opts BundleOptions) (*Manifest, error) {
+	if _, err := os.Stat(filepath.Join(opts.DokuDir, "catalog", catalog.CatalogFileName)); err != nil {
+		return nil, fmt.Errorf("no catalog to bundle in %s: run 'doku catalog update' first", opts.DokuDir)
+	}
+
+	manifest := &Manifest{
+		FormatVersion:  FormatVersion,
+		CreatedAt:      time.Now(),
+		DokuVersion:    opts.DokuVersion,
+		CatalogVersion: opts.CatalogVersion,
+		Services:       opts.Services,
+		Images:         opts.Images,
+	}
+	if len(opts.Images) > 0 {
+		manifest.Arch = opts.Arch
+	}
+
+	// docker save streams the images, but a tar entry needs its size first
+	var images *os.File
+	if len(opts.Images) > 0 {
+		var err error
+		images, err = os.CreateTemp("", "doku-images-*.tar")
+		if err != nil {
+			return nil, fmt.Errorf("failed to create temporary file: %w", err)
+		}
+		defer os.Remove(images.Name())
+		defer images.Close()
+		if err := opts.SaveImages(opts.Images, images); err != nil {
+			return nil, err
+		}
+		if _, err := images.Seek(0, io.SeekStart); err != nil {
+			return nil, fmt.Errorf("failed to read saved images: %w", err)
+		}
+	}
+
+	if dir := filepath.Dir(opts.OutputPath); dir != "." {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return nil, fmt.Errorf("failed to create output directory: %w", err)
+		}
+	}
+	file, err := os.Create(opts.OutputPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create bundle: %w", err)
+	}
+	defer file.Close()
+
+	gzWriter := gzip.NewWriter(file)
+	tarWriter := tar.NewWriter(gzWriter)
+
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode bundle manifest: %w", err)
+	}
+	if err := writeEntry(tarWriter, ManifestName, int64(len(data)), strings.NewReader(string(data))); err != nil {
+		return nil, err
+	}
+
+	for _, dir := range catalogDirs {
+		files, err := listFiles(opts.DokuDir, dir)
+		if err != nil {
+			return nil, err
+		}
+		for _, rel := range files {
+			// The index is a cache, built again on load
+			if filepath.Base(rel) == catalog.IndexFileName {
+				continue
+			}
+			if err := addFile(tarWriter, opts.DokuDir, rel); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if images != nil {
+		info, err := images.Stat()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read saved images: %w", err)
+		}
+		if err := writeEntry(tarWriter, ImagesName, info.Size(), images); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tarWriter.Close(); err != nil {
+		return nil, fmt.Errorf("failed to write bundle: %w", err)
+	}
+	if err := gzWriter.Close(); err != nil {
+		return nil, fmt.Errorf("failed to write bundle: %w", err)
+	}
+	return manifest, nil
+}
+
+// ReadManifest reads the description of a bundle
+func ReadManifest(bundlePath string) (*Manifest, error) {
+	var manifest *Manifest
+	err := walkBundle(bundlePath, func(name string, r io.Reader) error {
+		if name != ManifestName {
+			return nil
+		}
+		manifest = &Manifest{}
+		if err := json.NewDecoder(r).Decode(manifest); err != nil {
+			return fmt.Errorf("failed to parse bundle manifest: %w", err)
+		}
+		return errStop
+	})
+	if err != nil {
+		return nil, err
+	}
+	if manifest == nil {
+		return nil, fmt.Errorf("%s is not an offline bundle: it has no %s", bundlePath, ManifestName)
+	}
+	if manifest.FormatVersion != FormatVersion {
+		return nil, fmt.Errorf("unsupported bundle format %q: upgrade Doku to load it", manifest.FormatVersion)
+	}
+	return manifest, nil
+}
+
+// Load replaces the catalog of a Doku directory with that of a bundle, and
+// passes its images to loadImages, e.g. docker load. It returns the
+// bundle's manifest.
+func Load(bundlePath, dokuDir string, loadImages func(r io.Reader) error) (*Manifest, error) {
+	if err := readonly.Check("load " + bundlePath); err != nil {
+		return nil, err
+	}
+
+	manifest, err := ReadManifest(bundlePath)
+	if err != nil {
+		return nil, err
+	}
+
+	// The catalog is extracted next to the current one, which is only
+	// replaced once the bundle was read in full
+	if err := os.MkdirAll(dokuDir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create %s: %w", dokuDir, err)
+	}
+	tmpDir, err := os.MkdirTemp(dokuDir, ".offline-")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create temporary directory: %w", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	err = walkBundle(bundlePath, func(name string, r io.Reader) error {
+		switch {
+		case name == ManifestName:
+			return nil
+		case name == ImagesName:
+			return loadImages(r)
+		case !isCatalogPath(name):
+			return fmt.Errorf("unexpected file in bundle: %s", name)
+		}
+
+		path := filepath.Join(tmpDir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			return fmt.Errorf("failed to create %s: %w", filepath.Dir(path), err)
+		}
+		file, err := os.Create(path)
+		if err != nil {
+			return fmt.Errorf("failed to extract %s: %w", name, err)
+		}
+		if _, err := io.Copy(file, r); err != nil {
+			file.Close()
+			return fmt.Errorf("failed to extract %s: %w", name, err)
+		}
+		return file.Close()
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, dir := range catalogDirs {
+		extracted := filepath.Join(tmpDir, dir)
+		if _, err := os.Stat(extracted); err != nil {
+			continue
+		}
+		target := filepath.Join(dokuDir, dir)
+		if err := os.RemoveAll(target); err != nil {
+			return nil, fmt.Errorf("failed to remove %s: %w", target, err)
+		}
+		if err := os.Rename(extracted, target); err != nil {
+			return nil, fmt.Errorf("failed to move %s into place: %w", dir, err)
+		}
+	}
+	return manifest, nil
+}
+
+// errStop ends walkBundle early
+var errStop = errors.New("stop")
+
+// walkBundle calls fn with each file of a bundle
+func walkBundle(bundlePath string, fn func(name string, r io.Reader) error) error {
+	file, err := os.Open(bundlePath)
+	if err != nil {
+		return fmt.Errorf("failed to open bundle: %w", err)
+	}
+	defer file.Close()
+
+	gzReader, err := gzip.NewReader(file)
+	if err != nil {
+		return fmt.Errorf("%s is not an offline bundle: %w", bundlePath, err)
+	}
+	defer gzReader.Close()
+
+	tarReader := tar.NewReader(gzReader)
+	for {
+		header, err := tarReader.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read bundle: %w", err)
+		}
+		if header.Typeflag != tar.TypeReg {
+			continue
+		}
+		if err := fn(header.Name, tarReader); err != nil {
+			if err == errStop {
+				return nil
+			}
+			return err
+		}
+	}
+}
+
+// isCatalogPath reports whether a path of a bundle is in a catalog
+// directory, so that a crafted bundle can't write outside them
+func isCatalogPath(name string) bool {
+	if name != filepath.ToSlash(filepath.Clean(name)) || strings.HasPrefix(name, "/") || strings.Contains(name, "..") {
+		return false
+	}
+	for _, dir := range catalogDirs {
+		if strings.HasPrefix(name, dir+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// listFiles returns the regular files below a directory of the Doku
+// directory, relative to it with forward slashes
+func listFiles(dokuDir, dir string) ([]string, error) {
+	var files []string
+	err := filepath.WalkDir(filepath.Join(dokuDir, dir), func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) {
+				return nil
+			}
+			return err
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+		rel, err := filepath.Rel(dokuDir, path)
+		if err != nil {
+			return err
+		}
+		files = append(files, filepath.ToSlash(rel))
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list %s: %w", dir, err)
+	}
+	sort.Strings(files)
+	return files, nil
+}
+
+func addFile(tarWriter *tar.Writer, dokuDir, rel string) error {
+	file, err := os.Open(filepath.Join(dokuDir, filepath.FromSlash(rel)))
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", rel, err)
+	}
+	defer file.Close()
+	info, err := file.Stat()
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", rel, err)
+	}
+	return writeEntry(tarWriter, rel, info.Size(), file)
+}
+
+func writeEntry(tarWriter *tar.Writer, name string, size int64, r io.Reader) error {
+	header := &tar.Header{
+		Name:     name,
+		Size:     size,
+		Mode:     0644,
+		ModTime:  time.Now(),
+		Typeflag: tar.TypeReg,
+	}
+	if err := tarWriter.WriteHeader(header); err != nil {
+		return fmt.Errorf("failed to write %s to bundle: %w", name, err)
+	}
+	if _, err := io.Copy(tarWriter, r); err != nil {
+		return fmt.Errorf("failed to write %s to bundle: %w", name, err)
+	}
+	return nil
+}
@@ -0,0 +1,115 @@
+package offline
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/dokulabs/doku-cli/internal/catalog"
+)
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestBundleLoad(t *testing.T) {
+	src := t.TempDir()
+	writeFile(t, filepath.Join(src, "catalog", "catalog.yaml"), "version: \"2.0\"\n")
+	writeFile(t, filepath.Join(src, "catalog", "services", "database", "postgres", "service.yaml"), "name: postgres\n")
+	writeFile(t, filepath.Join(src, "catalog", catalog.IndexFileName), "cache")
+	writeFile(t, filepath.Join(src, "catalogs", "local", "catalog.yaml"), "version: local\n")
+	writeFile(t, filepath.Join(src, "config.toml"), "secret")
+
+	var saved []string
+	bundle := filepath.Join(t.TempDir(), "offline.tar.gz")
+	manifest, err := Bundle(BundleOptions{
+		DokuDir:        src,
+		OutputPath:     bundle,
+		CatalogVersion: "2.0",
+		Services:       []string{"postgres:16"},
+		Images:         []string{"postgres:16", "traefik:v2.10"},
+		Arch:           "arm64",
+		SaveImages: func(images []string, w io.Writer) error {
+			saved = images
+			_, err := io.WriteString(w, "IMAGES")
+			return err
+		},
+	})
+	if err != nil {
+		t.Fatalf("Bundle() error = %v", err)
+	}
+	if len(saved) != 2 || manifest.Arch != "arm64" {
+		t.Errorf("Bundle() saved %v for %s", saved, manifest.Arch)
+	}
+
+	read, err := ReadManifest(bundle)
+	if err != nil || read.CatalogVersion != "2.0" || len(read.Images) != 2 {
+		t.Fatalf("ReadManifest() = %+v, %v", read, err)
+	}
+
+	dest := t.TempDir()
+	writeFile(t, filepath.Join(dest, "catalog", "services", "old", "gone", "service.yaml"), "name: gone\n")
+	var loaded string
+	if _, err := Load(bundle, dest, func(r io.Reader) error {
+		data, err := io.ReadAll(r)
+		loaded = string(data)
+		return err
+	}); err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if loaded != "IMAGES" {
+		t.Errorf("Load() passed %q to loadImages, want the saved images", loaded)
+	}
+	for _, file := range []string{"catalog/catalog.yaml", "catalog/services/database/postgres/service.yaml", "catalogs/local/catalog.yaml"} {
+		if _, err := os.Stat(filepath.Join(dest, file)); err != nil {
+			t.Errorf("%s not loaded: %v", file, err)
+		}
+	}
+	for _, file := range []string{"catalog/services/old", "catalog/" + catalog.IndexFileName, "config.toml"} {
+		if _, err := os.Stat(filepath.Join(dest, file)); err == nil {
+			t.Errorf("%s should not be in the loaded catalog", file)
+		}
+	}
+}
+
+func TestBundleWithoutCatalog(t *testing.T) {
+	if _, err := Bundle(BundleOptions{DokuDir: t.TempDir(), OutputPath: filepath.Join(t.TempDir(), "x.tar.gz")}); err == nil {
+		t.Error("Bundle() without a catalog succeeded")
+	}
+}
+
+func TestLoadRejectsOtherPaths(t *testing.T) {
+	bundle := filepath.Join(t.TempDir(), "crafted.tar.gz")
+	file, err := os.Create(bundle)
+	if err != nil {
+		t.Fatal(err)
+	}
+	gzWriter := gzip.NewWriter(file)
+	tarWriter := tar.NewWriter(gzWriter)
+	for name, content := range map[string]string{
+		ManifestName: `{"format_version": "1"}`,
+		"../evil":    "x",
+	} {
+		if err := writeEntry(tarWriter, name, int64(len(content)), strings.NewReader(content)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	tarWriter.Close()
+	gzWriter.Close()
+	file.Close()
+
+	dest := t.TempDir()
+	if _, err := Load(bundle, dest, func(io.Reader) error { return nil }); err == nil {
+		t.Error("Load() accepted a file outside the catalog")
+	}
+}
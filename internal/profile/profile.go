@@ -6,6 +6,7 @@ import (
 	"path/filepath"
 
 	"github.com/BurntSushi/toml"
+	"github.com/dokulabs/doku-cli/internal/readonly"
 )
 
 // ProfileType represents the type of profile
@@ -83,6 +84,10 @@ func (m *Manager) GetProfilesDir() string {
 
 // EnsureProfilesDir creates the profiles directory if it doesn't exist
 func (m *Manager) EnsureProfilesDir() error {
+	if err := readonly.Check("create " + m.profilesDir); err != nil {
+		return err
+	}
+
 	return os.MkdirAll(m.profilesDir, 0755)
 }
 
@@ -104,6 +109,10 @@ func (m *Manager) GetServiceProfiles(serviceName string) (*ServiceProfiles, erro
 
 // SaveServiceProfiles saves profiles for a specific service
 func (m *Manager) SaveServiceProfiles(profiles *ServiceProfiles) error {
+	if err := readonly.Check("save profiles"); err != nil {
+		return err
+	}
+
 	if err := m.EnsureProfilesDir(); err != nil {
 		return fmt.Errorf("failed to create profiles directory: %w", err)
 	}
@@ -181,6 +190,10 @@ func (m *Manager) HasProfiles(serviceName string) bool {
 
 // DeleteProfiles removes profiles for a service
 func (m *Manager) DeleteProfiles(serviceName string) error {
+	if err := readonly.Check("delete profiles of " + serviceName); err != nil {
+		return err
+	}
+
 	profilePath := filepath.Join(m.profilesDir, serviceName+".toml")
 	if err := os.Remove(profilePath); err != nil && !os.IsNotExist(err) {
 		return fmt.Errorf("failed to delete profiles: %w", err)
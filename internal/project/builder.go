@@ -14,9 +14,16 @@ import (
 
 	"github.com/docker/docker/api/types"
 	"github.com/dokulabs/doku-cli/internal/docker"
+	"github.com/dokulabs/doku-cli/internal/readonly"
 	"github.com/fatih/color"
+	"github.com/moby/patternmatcher"
+	"github.com/moby/patternmatcher/ignorefile"
 )
 
+// DokuIgnoreFile lists paths to leave out of a project's build context, in
+// .dockerignore syntax, on top of the project's .dockerignore
+const DokuIgnoreFile = ".dokuignore"
+
 // Builder handles Docker build operations for projects
 type Builder struct {
 	docker *docker.Client
@@ -30,6 +37,12 @@ type DockerBuildOptions struct {
 	NoCache        bool               // Build without cache
 	Pull           bool               // Pull base image
 	BuildArgs      map[string]*string // Build arguments
+	Target         string             // Stage of a multi-stage Dockerfile to build
+	Labels         map[string]string  // Image labels
+	Secrets        []string           // BuildKit secrets, e.g. "id=npmrc,src=/home/me/.npmrc"
+	SSH            []string           // SSH agents or keys to forward, e.g. "default"
+	Progress       string             // Progress mode: auto, plain or tty
+	Log            io.Writer          // Receives the full build output, if set
 }
 
 // buildMessage represents a single build output line
@@ -46,23 +59,57 @@ func NewBuilder(dockerClient *docker.Client) *Builder {
 }
 
 // Build builds a Docker image from a Dockerfile
-// Uses docker buildx for BuildKit support with SSH forwarding
+// Uses docker buildx when available for BuildKit progress and SSH forwarding
 func (b *Builder) Build(opts DockerBuildOptions) (string, error) {
+	if err := readonly.Check("build an image from " + opts.ContextPath); err != nil {
+		return "", err
+	}
+
 	// Check if Dockerfile uses SSH mounts
 	usesSSH, err := b.dockerfileUsesSSH(opts.DockerfilePath)
 	if err != nil {
 		return "", err
 	}
 
-	// If Dockerfile uses SSH mounts, use buildx CLI for better BuildKit support
-	if usesSSH {
-		return b.buildWithBuildx(opts)
+	if opts.Target != "" {
+		if err := checkTarget(opts.DockerfilePath, opts.Target); err != nil {
+			return "", err
+		}
+	}
+
+	// Secrets and SSH forwarding need buildx; otherwise prefer it for its
+	// step progress
+	podman := b.docker.Engine().Name() == docker.EnginePodman
+	if len(opts.Secrets) > 0 || len(opts.SSH) > 0 {
+		if podman && !podmanAvailable() {
+			return "", fmt.Errorf("build secrets and SSH forwarding need the podman CLI; install it next to the Podman service")
+		}
+		if !podman && !buildxAvailable() {
+			return "", fmt.Errorf("build secrets and SSH forwarding need docker buildx; install the buildx plugin or update Docker Desktop")
+		}
+		return b.buildWithBuildx(opts, usesSSH)
+	}
+	// Podman builds with Buildah, whose progress the API streams as well,
+	// so its CLI is only needed for SSH
+	if usesSSH && podman || !podman && (usesSSH || buildxAvailable()) {
+		return b.buildWithBuildx(opts, usesSSH)
 	}
 
 	// Otherwise use standard SDK build
 	return b.buildWithSDK(opts)
 }
 
+// buildxAvailable checks if the docker CLI has the buildx plugin
+func buildxAvailable() bool {
+	return exec.Command("docker", "buildx", "version").Run() == nil
+}
+
+// podmanAvailable checks if the podman CLI is installed
+func podmanAvailable() bool {
+	_, err := exec.LookPath("podman")
+	return err == nil
+}
+
 // dockerfileUsesSSH checks if Dockerfile contains SSH mount directives
 func (b *Builder) dockerfileUsesSSH(dockerfilePath string) (bool, error) {
 	content, err := os.ReadFile(dockerfilePath)
@@ -72,10 +119,58 @@ func (b *Builder) dockerfileUsesSSH(dockerfilePath string) (bool, error) {
 	return strings.Contains(string(content), "--mount=type=ssh"), nil
 }
 
-// buildWithBuildx uses docker buildx CLI for builds requiring SSH
-func (b *Builder) buildWithBuildx(opts DockerBuildOptions) (string, error) {
+// checkTarget fails early if a Dockerfile has no stage named target
+func checkTarget(dockerfilePath, target string) error {
+	content, err := os.ReadFile(dockerfilePath)
+	if err != nil {
+		return err
+	}
+	stages := dockerfileStages(string(content))
+	for _, stage := range stages {
+		if strings.EqualFold(stage, target) {
+			return nil
+		}
+	}
+	if len(stages) == 0 {
+		return fmt.Errorf("target stage '%s' not found: the Dockerfile has no named stages", target)
+	}
+	return fmt.Errorf("target stage '%s' not found in the Dockerfile (stages: %s)", target, strings.Join(stages, ", "))
+}
+
+// dockerfileStages returns the names of a Dockerfile's stages, from its
+// "FROM <image> AS <name>" lines
+func dockerfileStages(content string) []string {
+	var stages []string
+	for _, line := range strings.Split(content, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) >= 4 && strings.EqualFold(fields[0], "FROM") {
+			// Skip flags such as --platform=...
+			rest := fields[1:]
+			for len(rest) > 0 && strings.HasPrefix(rest[0], "--") {
+				rest = rest[1:]
+			}
+			if len(rest) == 3 && strings.EqualFold(rest[1], "AS") {
+				stages = append(stages, rest[2])
+			}
+		}
+	}
+	return stages
+}
+
+// buildWithBuildx uses docker buildx CLI for BuildKit builds, streaming
+// their progress. With Podman, podman build takes the same flags.
+func (b *Builder) buildWithBuildx(opts DockerBuildOptions, withSSH bool) (string, error) {
+	podman := b.docker.Engine().Name() == docker.EnginePodman
+	builder := "BuildKit"
+	if podman {
+		builder = "Buildah"
+	}
 	cyan := color.New(color.FgCyan)
-	cyan.Println("→ Using BuildKit with SSH support")
+	if withSSH || len(opts.SSH) > 0 {
+		cyan.Printf("→ Using %s with SSH support\n", builder)
+	} else {
+		cyan.Printf("→ Using %s\n", builder)
+	}
 
 	// Validate Dockerfile
 	if err := b.ValidateDockerfile(opts.DockerfilePath); err != nil {
@@ -93,8 +188,40 @@ func (b *Builder) buildWithBuildx(opts DockerBuildOptions) (string, error) {
 		return "", fmt.Errorf("failed to resolve Dockerfile path: %w", err)
 	}
 
+	// buildx only knows .dockerignore, so with a .dokuignore the context is
+	// built here and sent as a tar, with the Dockerfile path inside it.
+	// podman build can't read one, but takes the patterns as an ignore file.
+	contextArg, dockerfileArg := absContextPath, absDockerfilePath
+	var contextTar io.ReadCloser
+	var ignoreFile string
+	if podman && FileExists(filepath.Join(absContextPath, DokuIgnoreFile)) {
+		ignoreFile, err = writeIgnoreFile(absContextPath)
+		if err != nil {
+			return "", err
+		}
+		defer os.Remove(ignoreFile)
+	} else if FileExists(filepath.Join(absContextPath, DokuIgnoreFile)) {
+		contextTar, err = b.createBuildContext(absContextPath, absDockerfilePath)
+		if err != nil {
+			return "", fmt.Errorf("failed to create build context: %w", err)
+		}
+		defer contextTar.Close()
+
+		contextArg = "-"
+		if dockerfileArg, err = filepath.Rel(absContextPath, absDockerfilePath); err != nil {
+			dockerfileArg = filepath.Base(absDockerfilePath)
+		}
+		dockerfileArg = filepath.ToSlash(dockerfileArg)
+	}
+
 	// Prepare buildx command
-	args := []string{"buildx", "build"}
+	cli, args := "docker", []string{"buildx", "build"}
+	if podman {
+		cli, args = "podman", []string{"build"}
+	}
+	if ignoreFile != "" {
+		args = append(args, "--ignorefile", ignoreFile)
+	}
 
 	// Add tags
 	for _, tag := range opts.Tags {
@@ -102,7 +229,7 @@ func (b *Builder) buildWithBuildx(opts DockerBuildOptions) (string, error) {
 	}
 
 	// Add dockerfile
-	args = append(args, "-f", absDockerfilePath)
+	args = append(args, "-f", dockerfileArg)
 
 	// Add build args
 	for k, v := range opts.BuildArgs {
@@ -111,8 +238,30 @@ func (b *Builder) buildWithBuildx(opts DockerBuildOptions) (string, error) {
 		}
 	}
 
-	// Add SSH forwarding
-	args = append(args, "--ssh", "default")
+	// Add labels
+	for k, v := range opts.Labels {
+		args = append(args, "--label", fmt.Sprintf("%s=%s", k, v))
+	}
+
+	// Add target stage
+	if opts.Target != "" {
+		args = append(args, "--target", opts.Target)
+	}
+
+	// Add SSH forwarding, of the default agent if the Dockerfile needs it
+	// and none was given
+	ssh := opts.SSH
+	if len(ssh) == 0 && withSSH {
+		ssh = []string{"default"}
+	}
+	for _, spec := range ssh {
+		args = append(args, "--ssh", spec)
+	}
+
+	// Add secrets
+	for _, spec := range opts.Secrets {
+		args = append(args, "--secret", spec)
+	}
 
 	// Add no-cache if requested
 	if opts.NoCache {
@@ -124,22 +273,45 @@ func (b *Builder) buildWithBuildx(opts DockerBuildOptions) (string, error) {
 		args = append(args, "--pull")
 	}
 
-	// Add load flag to load image into docker
-	args = append(args, "--load")
+	// Add load flag to load image into docker, and plain progress, which is
+	// line based so it can be logged and summarized. Podman builds into
+	// its store, with line based output already.
+	if !podman {
+		args = append(args, "--load", "--progress", "plain")
+	}
 
 	// Add context
-	args = append(args, absContextPath)
-
-	// Execute buildx
-	cmd := exec.Command("docker", args...)
-	output, err := cmd.CombinedOutput()
+	args = append(args, contextArg)
+
+	// Execute buildx, streaming its output
+	pr, pw := io.Pipe()
+	cmd := exec.Command(cli, args...)
+	if contextTar != nil {
+		cmd.Stdin = contextTar
+	}
+	cmd.Stdout = pw
+	cmd.Stderr = pw
+	if err := cmd.Start(); err != nil {
+		return "", fmt.Errorf("failed to start buildx: %w", err)
+	}
+	done := make(chan error, 1)
+	go func() {
+		err := cmd.Wait()
+		pw.Close()
+		done <- err
+	}()
 
 	fmt.Println()
-	fmt.Println(string(output))
-
-	if err != nil {
-		return "", fmt.Errorf("buildx failed: %w\nOutput: %s", err, string(output))
+	streamErr := streamBuildOutput(pr, opts)
+	io.Copy(io.Discard, pr) // Don't block buildx if streaming stopped early
+	if err := <-done; err != nil {
+		return "", fmt.Errorf("buildx failed: %w", err)
 	}
+	if streamErr != nil {
+		return "", fmt.Errorf("failed to read build output: %w", streamErr)
+	}
+	fmt.Println()
+	color.Green("✓ Build completed successfully")
 
 	// Extract image ID from tags
 	if len(opts.Tags) > 0 {
@@ -190,6 +362,8 @@ func (b *Builder) buildWithSDK(opts DockerBuildOptions) (string, error) {
 		Remove:     true,
 		PullParent: opts.Pull,
 		BuildArgs:  opts.BuildArgs,
+		Target:     opts.Target,
+		Labels:     opts.Labels,
 		// Note: BuildKit must be enabled in Docker daemon settings for SSH mounts
 		// Do NOT use Version: types.BuilderBuildKit as it causes parsing errors
 	}
@@ -207,7 +381,11 @@ func (b *Builder) buildWithSDK(opts DockerBuildOptions) (string, error) {
 	defer response.Body.Close()
 
 	// Parse and display build output
-	imageID, err := b.parseBuildOutput(response.Body)
+	log := opts.Log
+	if log == nil {
+		log = io.Discard
+	}
+	imageID, err := b.parseBuildOutput(response.Body, log)
 	if err != nil {
 		return "", err
 	}
@@ -245,17 +423,11 @@ func (b *Builder) createBuildContext(contextPath, dockerfilePath string) (io.Rea
 	tw := tar.NewWriter(buf)
 	defer tw.Close()
 
-	// Load .dockerignore patterns if file exists
-	dockerignorePath := filepath.Join(contextPath, ".dockerignore")
-	ignorePatterns := []string{}
-	if _, err := os.Stat(dockerignorePath); err == nil {
-		patterns, err := b.loadDockerignore(dockerignorePath)
-		if err != nil {
-			// Don't fail the build, just warn
-			fmt.Printf("Warning: Failed to load .dockerignore: %v\n", err)
-		} else {
-			ignorePatterns = patterns
-		}
+	// Load .dockerignore and .dokuignore patterns
+	ignore, err := loadIgnorePatterns(contextPath)
+	if err != nil {
+		// Don't fail the build, just warn
+		fmt.Printf("Warning: %v\n", err)
 	}
 
 	// Directories to skip during build context creation (fallback if no .dockerignore)
@@ -281,7 +453,8 @@ func (b *Builder) createBuildContext(contextPath, dockerfilePath string) (io.Rea
 	}
 
 	// Walk through project directory
-	err := filepath.Walk(contextPath, func(path string, info os.FileInfo, err error) error {
+	excluded := 0
+	err = filepath.Walk(contextPath, func(path string, info os.FileInfo, err error) error {
 		if err != nil {
 			return err
 		}
@@ -297,16 +470,29 @@ func (b *Builder) createBuildContext(contextPath, dockerfilePath string) (io.Rea
 			return nil
 		}
 
-		// Check against .dockerignore patterns if they exist
-		if len(ignorePatterns) > 0 && b.shouldIgnore(relPath, ignorePatterns) {
-			if info.IsDir() {
-				return filepath.SkipDir
+		// Check against the ignore patterns if there are any
+		if ignore != nil {
+			skip, err := ignore.MatchesOrParentMatches(filepath.ToSlash(relPath))
+			if err != nil {
+				return fmt.Errorf("invalid ignore pattern: %w", err)
+			}
+			if skip {
+				// A directory must still be walked if a "!" pattern may
+				// bring back some of its files
+				if info.IsDir() && !ignore.Exclusions() {
+					excluded++
+					return filepath.SkipDir
+				}
+				if !info.IsDir() {
+					excluded++
+				}
+				return nil
 			}
-			return nil
 		}
 
-		// Fallback: Skip common build/dependency directories if no .dockerignore
-		if len(ignorePatterns) == 0 && info.IsDir() && skipDirs[info.Name()] {
+		// Fallback: Skip common build/dependency directories if there are no ignore files
+		if ignore == nil && info.IsDir() && skipDirs[info.Name()] {
+			excluded++
 			return filepath.SkipDir
 		}
 
@@ -320,7 +506,7 @@ func (b *Builder) createBuildContext(contextPath, dockerfilePath string) (io.Rea
 		if err != nil {
 			return err
 		}
-		header.Name = relPath
+		header.Name = filepath.ToSlash(relPath)
 
 		// Use PAX format for better compatibility with long paths
 		header.Format = tar.FormatPAX
@@ -372,7 +558,7 @@ func (b *Builder) createBuildContext(contextPath, dockerfilePath string) (io.Rea
 
 	// Add Dockerfile to tar with its relative path
 	header := &tar.Header{
-		Name: relDockerfile,
+		Name: filepath.ToSlash(relDockerfile),
 		Mode: 0644,
 		Size: int64(len(dockerfileContent)),
 	}
@@ -383,11 +569,17 @@ func (b *Builder) createBuildContext(contextPath, dockerfilePath string) (io.Rea
 		return nil, fmt.Errorf("failed to write Dockerfile content: %w", err)
 	}
 
+	if err := tw.Close(); err != nil {
+		return nil, fmt.Errorf("failed to write tar archive: %w", err)
+	}
+	color.New(color.Faint).Printf("Build context: %s (%d paths excluded)\n", docker.FormatMemoryBytes(int64(buf.Len())), excluded)
+
 	return io.NopCloser(bytes.NewReader(buf.Bytes())), nil
 }
 
 // parseBuildOutput parses Docker build output and displays progress
-func (b *Builder) parseBuildOutput(reader io.Reader) (string, error) {
+// and copies it to log
+func (b *Builder) parseBuildOutput(reader io.Reader, log io.Writer) (string, error) {
 	decoder := json.NewDecoder(reader)
 	var imageID string
 
@@ -410,6 +602,7 @@ func (b *Builder) parseBuildOutput(reader io.Reader) (string, error) {
 
 		// Handle error messages
 		if msg.Error != "" {
+			fmt.Fprintln(log, msg.Error)
 			red.Printf("✗ Build failed: %s\n", msg.Error)
 
 			// Check for BuildKit-specific errors and provide helpful guidance
@@ -434,6 +627,7 @@ func (b *Builder) parseBuildOutput(reader io.Reader) (string, error) {
 
 		// Display stream output
 		if msg.Stream != "" {
+			fmt.Fprint(log, msg.Stream)
 			stream := strings.TrimSpace(msg.Stream)
 			if stream != "" {
 				// Highlight important messages
@@ -468,6 +662,31 @@ func (b *Builder) parseBuildOutput(reader io.Reader) (string, error) {
 	return imageID, nil
 }
 
+// streamBuildOutput copies buildx plain progress to the build log and
+// shows it either as is or summarized per step, depending on opts.Progress
+func streamBuildOutput(reader io.Reader, opts DockerBuildOptions) error {
+	var printer *stepPrinter
+	if resolveProgress(opts.Progress, os.Stdout) == ProgressTTY {
+		printer = newStepPrinter(os.Stdout)
+		defer printer.Close()
+	}
+
+	scanner := bufio.NewScanner(reader)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if opts.Log != nil {
+			fmt.Fprintln(opts.Log, line)
+		}
+		if printer != nil {
+			printer.Line(line)
+		} else {
+			fmt.Printf("  %s\n", line)
+		}
+	}
+	return scanner.Err()
+}
+
 // TagImage adds a tag to an image
 func (b *Builder) TagImage(imageID, tag string) error {
 	if err := b.docker.ImageTag(imageID, tag); err != nil {
@@ -487,64 +706,55 @@ func (b *Builder) GetImageInfo(imageTag string) (types.ImageInspect, error) {
 	return inspect, nil
 }
 
-// loadDockerignore loads patterns from a .dockerignore file
-func (b *Builder) loadDockerignore(dockerignorePath string) ([]string, error) {
-	file, err := os.Open(dockerignorePath)
-	if err != nil {
+// loadIgnorePatterns reads a project's .dockerignore and .dokuignore into
+// one matcher, or returns nil if it has neither. .dokuignore patterns come
+// last, so they can exclude more or bring files back with "!".
+func loadIgnorePatterns(contextPath string) (*patternmatcher.PatternMatcher, error) {
+	patterns, err := readIgnorePatterns(contextPath)
+	if err != nil || len(patterns) == 0 {
 		return nil, err
 	}
-	defer file.Close()
 
-	var patterns []string
-	scanner := bufio.NewScanner(file)
-	for scanner.Scan() {
-		line := strings.TrimSpace(scanner.Text())
-		// Skip empty lines and comments
-		if line == "" || strings.HasPrefix(line, "#") {
-			continue
-		}
-		patterns = append(patterns, line)
-	}
+	return patternmatcher.New(patterns)
+}
 
-	if err := scanner.Err(); err != nil {
-		return nil, err
+// writeIgnoreFile writes a project's .dockerignore and .dokuignore patterns
+// to a temporary file, for builders that take one, and returns its path
+func writeIgnoreFile(contextPath string) (string, error) {
+	patterns, err := readIgnorePatterns(contextPath)
+	if err != nil {
+		return "", err
 	}
-
-	return patterns, nil
+	file, err := os.CreateTemp("", "doku-ignore-*")
+	if err != nil {
+		return "", fmt.Errorf("failed to create ignore file: %w", err)
+	}
+	defer file.Close()
+	if _, err := file.WriteString(strings.Join(patterns, "\n") + "\n"); err != nil {
+		os.Remove(file.Name())
+		return "", fmt.Errorf("failed to write ignore file: %w", err)
+	}
+	return file.Name(), nil
 }
 
-// shouldIgnore checks if a path should be ignored based on .dockerignore patterns
-func (b *Builder) shouldIgnore(relPath string, patterns []string) bool {
-	// Normalize path separators
-	relPath = filepath.ToSlash(relPath)
-
-	for _, pattern := range patterns {
-		// Normalize pattern
-		pattern = filepath.ToSlash(pattern)
-
-		// Simple pattern matching (supports * and ** wildcards)
-		matched, err := filepath.Match(pattern, relPath)
-		if err == nil && matched {
-			return true
-		}
-
-		// Check if pattern matches a prefix (directory)
-		if strings.HasSuffix(pattern, "/") {
-			if strings.HasPrefix(relPath, pattern) {
-				return true
-			}
+// readIgnorePatterns returns a project's .dockerignore patterns followed by
+// its .dokuignore ones
+func readIgnorePatterns(contextPath string) ([]string, error) {
+	var patterns []string
+	for _, name := range []string{".dockerignore", DokuIgnoreFile} {
+		file, err := os.Open(filepath.Join(contextPath, name))
+		if os.IsNotExist(err) {
+			continue
 		}
-
-		// Check if it's a directory match
-		if strings.HasPrefix(relPath, pattern+"/") {
-			return true
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", name, err)
 		}
-
-		// Exact match
-		if relPath == pattern {
-			return true
+		filePatterns, err := ignorefile.ReadAll(file)
+		file.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", name, err)
 		}
+		patterns = append(patterns, filePatterns...)
 	}
-
-	return false
+	return patterns, nil
 }
@@ -0,0 +1,104 @@
+package project
+
+import (
+	"archive/tar"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestDockerfileStages(t *testing.T) {
+	content := `# syntax=docker/dockerfile:1
+FROM node:20 AS deps
+RUN npm ci
+
+from --platform=$BUILDPLATFORM node:20 as build
+COPY --from=deps /app/node_modules ./node_modules
+
+FROM nginx:alpine
+FROM build AS prod
+`
+	want := []string{"deps", "build", "prod"}
+	if got := dockerfileStages(content); !reflect.DeepEqual(got, want) {
+		t.Errorf("dockerfileStages() = %v, want %v", got, want)
+	}
+}
+
+func TestCreateBuildContextIgnoreFiles(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"Dockerfile":                     "FROM scratch\n",
+		".dockerignore":                  "*.log\n",
+		DokuIgnoreFile:                   "node_modules\ndocs/**\n!docs/api.md\n",
+		"main.go":                        "package main\n",
+		"debug.log":                      "log\n",
+		"node_modules/left-pad/index.js": "module.exports = 1\n",
+		"docs/guide.md":                  "guide\n",
+		"docs/api.md":                    "api\n",
+	}
+	for name, content := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	b := &Builder{}
+	context, err := b.createBuildContext(dir, filepath.Join(dir, "Dockerfile"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer context.Close()
+
+	got := make(map[string]bool)
+	tr := tar.NewReader(context)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		got[header.Name] = true
+	}
+
+	for _, name := range []string{"Dockerfile", "main.go", "docs/api.md"} {
+		if !got[name] {
+			t.Errorf("build context is missing %s", name)
+		}
+	}
+	for _, name := range []string{"debug.log", "node_modules/left-pad/index.js", "docs/guide.md"} {
+		if got[name] {
+			t.Errorf("build context should not contain %s", name)
+		}
+	}
+}
+
+func TestWriteIgnoreFile(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, ".dockerignore"), []byte("*.log\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, DokuIgnoreFile), []byte("node_modules\n!docs/api.md\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	path, err := writeIgnoreFile(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(path)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "*.log\nnode_modules\n!docs/api.md\n"; string(data) != want {
+		t.Errorf("writeIgnoreFile() wrote %q, want %q", data, want)
+	}
+}
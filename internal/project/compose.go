@@ -0,0 +1,623 @@
+package project
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/mount"
+	"github.com/docker/docker/api/types/network"
+	"github.com/docker/go-connections/nat"
+	"github.com/docker/go-units"
+	"github.com/dokulabs/doku-cli/internal/compose"
+	"github.com/dokulabs/doku-cli/internal/config"
+	"github.com/dokulabs/doku-cli/internal/dns"
+	"github.com/dokulabs/doku-cli/internal/docker"
+	"github.com/dokulabs/doku-cli/internal/envfile"
+	"github.com/dokulabs/doku-cli/internal/monitoring"
+	"github.com/dokulabs/doku-cli/internal/service"
+	"github.com/dokulabs/doku-cli/pkg/types"
+	"github.com/fatih/color"
+)
+
+// ImportOptions contains options for importing a compose file as a project
+type ImportOptions struct {
+	ComposePath string // Path to docker-compose.yml
+	Name        string // Project name (optional, defaults to compose name or directory name)
+	Expose      string // Compose service to route through Traefik (optional, defaults to first service with ports)
+	Port        int    // Container port of the exposed service (optional, defaults to its first port)
+	Internal    bool   // Internal only (no Traefik)
+	Replace     bool   // Replace existing project if it exists
+}
+
+// Import registers a compose file as a multi-container project
+func (m *Manager) Import(opts ImportOptions) (*types.Project, error) {
+	composePath, err := filepath.Abs(opts.ComposePath)
+	if err != nil {
+		return nil, fmt.Errorf("invalid compose file path: %w", err)
+	}
+
+	file, err := compose.Load(composePath)
+	if err != nil {
+		return nil, err
+	}
+
+	projectDir := filepath.Dir(composePath)
+
+	// Determine project name
+	projectName := opts.Name
+	if projectName == "" {
+		projectName = file.Name
+	}
+	if projectName == "" {
+		projectName = filepath.Base(projectDir)
+	}
+
+	if err := validateProjectName(projectName); err != nil {
+		return nil, err
+	}
+
+	// Check if project already exists
+	if _, err := m.Get(projectName); err == nil {
+		if !opts.Replace {
+			return nil, fmt.Errorf("project '%s' already exists", projectName)
+		}
+		if err := m.Remove(projectName, false); err != nil {
+			return nil, fmt.Errorf("failed to remove existing project: %w", err)
+		}
+		fmt.Printf("Removed existing project '%s'\n", projectName)
+	}
+
+	// Pick the service that gets the project URL
+	exposed, port, err := selectExposedService(file, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	url := ""
+	if exposed != "" {
+		cfg, err := m.configMgr.Get()
+		if err != nil {
+			return nil, err
+		}
+		httpPort, httpsPort := config.TraefikPorts(cfg.Traefik)
+		url = config.ServiceURL("https", projectName+"."+cfg.Preferences.Domain, httpPort, httpsPort)
+	}
+
+	order, err := file.StartOrder()
+	if err != nil {
+		return nil, err
+	}
+
+	containers := make([]types.ContainerInfo, 0, len(order))
+	for _, name := range order {
+		svc := file.Services[name]
+		containers = append(containers, types.ContainerInfo{
+			Name:      name,
+			FullName:  composeContainerName(projectName, name),
+			Primary:   name == exposed,
+			Status:    string(types.StatusStopped),
+			Image:     composeImage(projectName, name, svc),
+			Ports:     svc.Ports,
+			DependsOn: svc.DependsOn,
+		})
+	}
+
+	mainContainer := containers[0].FullName
+	if exposed != "" {
+		mainContainer = composeContainerName(projectName, exposed)
+	}
+
+	project := &types.Project{
+		Name:          projectName,
+		Path:          projectDir,
+		ComposeFile:   filepath.Base(composePath),
+		Status:        types.StatusStopped,
+		ContainerName: mainContainer,
+		URL:           url,
+		Port:          port,
+		CreatedAt:     time.Now(),
+		Environment:   make(map[string]string),
+		Containers:    containers,
+	}
+
+	if err := m.configMgr.AddProject(project); err != nil {
+		return nil, fmt.Errorf("failed to save project: %w", err)
+	}
+
+	return project, nil
+}
+
+// LoadComposeFile parses the compose file of a compose-based project
+func (m *Manager) LoadComposeFile(project *types.Project) (*compose.File, error) {
+	return compose.Load(filepath.Join(project.Path, project.ComposeFile))
+}
+
+// runCompose builds or pulls the images of a compose-based project and
+// (re)creates its containers in dependency order
+func (m *Manager) runCompose(project *types.Project, opts RunOptions) error {
+	file, err := m.LoadComposeFile(project)
+	if err != nil {
+		return err
+	}
+
+	order, err := file.StartOrder()
+	if err != nil {
+		return err
+	}
+
+	cfg, err := m.configMgr.Get()
+	if err != nil {
+		return fmt.Errorf("failed to get config: %w", err)
+	}
+
+	primary := ""
+	for _, c := range project.Containers {
+		if c.Primary {
+			primary = c.Name
+		}
+	}
+
+	containers := make([]types.ContainerInfo, 0, len(order))
+	for _, name := range order {
+		svc := file.Services[name]
+		imageTag := composeImage(project.Name, name, svc)
+
+		if err := m.ensureComposeImage(project, svc, imageTag, opts.Build); err != nil {
+			return fmt.Errorf("service %s: %w", name, err)
+		}
+
+		info := types.ContainerInfo{
+			Name:      name,
+			FullName:  composeContainerName(project.Name, name),
+			Primary:   name == primary,
+			Image:     imageTag,
+			Ports:     svc.Ports,
+			DependsOn: svc.DependsOn,
+		}
+
+		fmt.Printf("Starting %s...\n", name)
+		containerID, err := m.createComposeContainer(project, file, name, imageTag, cfg.Network.Name, info.Primary)
+		if err != nil {
+			return fmt.Errorf("service %s: %w", name, err)
+		}
+
+		if err := m.docker.ContainerStart(containerID); err != nil {
+			return fmt.Errorf("service %s: failed to start container: %w", name, err)
+		}
+
+		info.ContainerID = containerID
+		info.Status = string(types.StatusRunning)
+		containers = append(containers, info)
+	}
+
+	if err := m.configMgr.Update(func(c *types.Config) error {
+		if proj, exists := c.Projects[project.Name]; exists {
+			proj.Containers = containers
+			proj.Status = types.StatusRunning
+		}
+		return nil
+	}); err != nil {
+		return fmt.Errorf("failed to update project config: %w", err)
+	}
+
+	fmt.Println()
+	color.Green("✓ Project started successfully (%d containers)", len(containers))
+	fmt.Println()
+
+	if project.URL != "" {
+		fmt.Println("Access your project:")
+		color.Cyan("  URL: %s", project.URL)
+		fmt.Println()
+	}
+
+	return nil
+}
+
+// buildCompose rebuilds the images of compose services that have a build section
+func (m *Manager) buildCompose(project *types.Project) error {
+	file, err := m.LoadComposeFile(project)
+	if err != nil {
+		return err
+	}
+
+	for _, name := range file.ServiceNames() {
+		svc := file.Services[name]
+		if svc.Build == nil {
+			continue
+		}
+
+		fmt.Printf("Building %s...\n", name)
+		if err := m.ensureComposeImage(project, svc, composeImage(project.Name, name, svc), true); err != nil {
+			return fmt.Errorf("service %s: %w", name, err)
+		}
+	}
+
+	return nil
+}
+
+// ensureComposeImage builds or pulls the image of a compose service
+func (m *Manager) ensureComposeImage(project *types.Project, svc *compose.Service, imageTag string, rebuild bool) error {
+	if svc.Build == nil {
+		if m.imageExists(imageTag) {
+			return nil
+		}
+		fmt.Printf("Pulling %s...\n", imageTag)
+		return m.docker.ImagePull(imageTag)
+	}
+
+	if !rebuild && m.imageExists(imageTag) {
+		return nil
+	}
+
+	contextPath := svc.Build.Context
+	if !filepath.IsAbs(contextPath) {
+		contextPath = filepath.Join(project.Path, contextPath)
+	}
+
+	dockerfile := svc.Build.Dockerfile
+	if dockerfile == "" {
+		dockerfile = "Dockerfile"
+	}
+	if !filepath.IsAbs(dockerfile) {
+		dockerfile = filepath.Join(contextPath, dockerfile)
+	}
+
+	if err := m.builder.ValidateDockerfile(dockerfile); err != nil {
+		return err
+	}
+
+	_, err := m.builder.Build(DockerBuildOptions{
+		ContextPath:    contextPath,
+		DockerfilePath: dockerfile,
+		Tags:           []string{imageTag},
+		NoCache:        rebuild,
+		Labels:         docker.InstanceLabels(project.Name),
+	})
+	return err
+}
+
+// createComposeContainer creates the container of a compose service on the Doku network
+func (m *Manager) createComposeContainer(project *types.Project, file *compose.File, serviceName, imageTag, networkName string, primary bool) (string, error) {
+	svc := file.Services[serviceName]
+	containerName := composeContainerName(project.Name, serviceName)
+
+	// Environment: env_file entries first, then inline environment
+	env := make(map[string]string)
+	for _, envFile := range svc.EnvFile {
+		if !filepath.IsAbs(envFile) {
+			envFile = filepath.Join(project.Path, envFile)
+		}
+		values, err := envfile.LoadEnvFile(envFile)
+		if err != nil {
+			return "", fmt.Errorf("failed to read env file %s: %w", envFile, err)
+		}
+		env = envfile.MergeEnv(env, values)
+	}
+	env = envfile.MergeEnv(env, svc.Environment)
+	if envfile.HasReferences(env) {
+		resolved, err := envfile.Interpolate(env, service.EnvResolver(m.configMgr))
+		if err != nil {
+			return "", err
+		}
+		env = resolved
+	}
+
+	exposedPorts, portBindings, err := nat.ParsePortSpecs(svc.Ports)
+	if err != nil {
+		return "", fmt.Errorf("invalid ports: %w", err)
+	}
+
+	mounts, err := composeMounts(project, file, svc)
+	if err != nil {
+		return "", err
+	}
+
+	labels := docker.MergeLabels(svc.Labels, docker.InstanceLabels(project.Name))
+	labels["doku.type"] = "project"
+	labels["doku.name"] = project.Name
+	labels["doku.container"] = serviceName
+
+	if primary && project.URL != "" {
+		domain := config.URLHost(project.URL)
+
+		labels["traefik.enable"] = "true"
+		labels[fmt.Sprintf("traefik.http.routers.%s.rule", project.Name)] = fmt.Sprintf("Host(`%s`)", domain)
+		labels[fmt.Sprintf("traefik.http.routers.%s.entrypoints", project.Name)] = "websecure"
+		labels[fmt.Sprintf("traefik.http.routers.%s.tls", project.Name)] = "true"
+		labels[fmt.Sprintf("traefik.http.services.%s.loadbalancer.server.port", project.Name)] = fmt.Sprintf("%d", project.Port)
+	}
+
+	containerConfig := &container.Config{
+		Image:        imageTag,
+		Env:          envfile.EnvMapToSlice(env),
+		ExposedPorts: exposedPorts,
+		Labels:       labels,
+		Cmd:          []string(svc.Command),
+		Entrypoint:   []string(svc.Entrypoint),
+		WorkingDir:   svc.WorkingDir,
+		User:         svc.User,
+	}
+
+	restart := svc.Restart
+	if restart == "" {
+		restart = "unless-stopped"
+	}
+
+	hostConfig := &container.HostConfig{
+		PortBindings:  portBindings,
+		Mounts:        mounts,
+		RestartPolicy: container.RestartPolicy{Name: container.RestartPolicyMode(restart)},
+	}
+	if cfg, err := m.configMgr.Get(); err == nil {
+		hostConfig.LogConfig = *monitoring.GetDockerLoggingConfig(&cfg.Monitoring, cfg.Preferences.Logs)
+	}
+
+	if svc.MemLimit != "" {
+		memory, err := units.RAMInBytes(svc.MemLimit)
+		if err != nil {
+			return "", fmt.Errorf("invalid mem_limit: %w", err)
+		}
+		hostConfig.Resources.Memory = memory
+	}
+	if svc.CPUs != "" {
+		cpus, err := strconv.ParseFloat(svc.CPUs, 64)
+		if err != nil {
+			return "", fmt.Errorf("invalid cpus: %w", err)
+		}
+		hostConfig.Resources.NanoCPUs = int64(cpus * 1e9)
+	}
+
+	// Compose networks are flattened onto the Doku network; the service name
+	// stays resolvable so services can reach each other as in compose
+	networkConfig := &network.NetworkingConfig{
+		EndpointsConfig: map[string]*network.EndpointSettings{
+			networkName: {
+				Aliases: []string{serviceName, fmt.Sprintf("%s-%s", project.Name, serviceName)},
+			},
+		},
+	}
+
+	// Remove existing container if present
+	if err := m.docker.ContainerRemove(containerName, true); err != nil {
+		if !strings.Contains(err.Error(), "No such container") {
+			fmt.Printf("Warning: failed to remove existing container: %v\n", err)
+		}
+	}
+
+	containerID, err := m.docker.ContainerCreate(containerConfig, hostConfig, networkConfig, containerName)
+	if err != nil {
+		return "", fmt.Errorf("failed to create container: %w", err)
+	}
+
+	return containerID, nil
+}
+
+// startCompose starts the existing containers of a compose-based project
+func (m *Manager) startCompose(project *types.Project) error {
+	for _, c := range project.Containers {
+		exists, err := m.docker.ContainerExists(c.FullName)
+		if err != nil {
+			return fmt.Errorf("failed to check container: %w", err)
+		}
+		if !exists {
+			return fmt.Errorf("container %s not found, please run: doku project run %s", c.Name, project.Name)
+		}
+
+		if err := m.docker.ContainerStart(c.FullName); err != nil {
+			return fmt.Errorf("failed to start container %s: %w", c.Name, err)
+		}
+	}
+
+	return m.setComposeStatus(project.Name, types.StatusRunning)
+}
+
+// stopCompose stops the containers of a compose-based project in reverse start order
+func (m *Manager) stopCompose(project *types.Project) error {
+	timeout := 10
+	for i := len(project.Containers) - 1; i >= 0; i-- {
+		c := project.Containers[i]
+
+		exists, err := m.docker.ContainerExists(c.FullName)
+		if err != nil {
+			return fmt.Errorf("failed to check container: %w", err)
+		}
+		if !exists {
+			continue
+		}
+
+		if err := m.docker.ContainerStop(c.FullName, &timeout); err != nil {
+			return fmt.Errorf("failed to stop container %s: %w", c.Name, err)
+		}
+	}
+
+	return m.setComposeStatus(project.Name, types.StatusStopped)
+}
+
+// removeCompose removes the containers (and optionally built images) of a compose-based project
+func (m *Manager) removeCompose(project *types.Project, removeImage bool) error {
+	timeout := 10
+	for i := len(project.Containers) - 1; i >= 0; i-- {
+		c := project.Containers[i]
+
+		exists, err := m.docker.ContainerExists(c.FullName)
+		if err != nil {
+			return fmt.Errorf("failed to check container: %w", err)
+		}
+		if exists {
+			if err := m.docker.ContainerStop(c.FullName, &timeout); err != nil {
+				fmt.Printf("Warning: failed to stop container %s: %v\n", c.Name, err)
+			}
+			if err := m.docker.ContainerRemove(c.FullName, true); err != nil {
+				return fmt.Errorf("failed to remove container %s: %w", c.Name, err)
+			}
+		}
+
+		// Only images built for this project are removed
+		if removeImage && c.Image == composeBuildImage(project.Name, c.Name) {
+			if err := m.docker.ImageRemove(c.Image, true); err != nil {
+				fmt.Printf("Warning: failed to remove image: %v\n", err)
+			}
+		}
+	}
+
+	if project.URL != "" {
+		subdomain := config.URLHost(project.URL)
+
+		dnsMgr := dns.NewManagerForContext(m.configMgr.GetContext())
+		if err := dnsMgr.RemoveSingleEntry(subdomain); err != nil {
+			fmt.Printf("Warning: failed to remove DNS entry: %v\n", err)
+		}
+	}
+
+	return m.configMgr.RemoveProject(project.Name)
+}
+
+// setComposeStatus updates the status of a compose-based project and its containers
+func (m *Manager) setComposeStatus(name string, status types.ServiceStatus) error {
+	return m.configMgr.Update(func(c *types.Config) error {
+		if proj, exists := c.Projects[name]; exists {
+			proj.Status = status
+			for i := range proj.Containers {
+				proj.Containers[i].Status = string(status)
+			}
+		}
+		return nil
+	})
+}
+
+// selectExposedService returns the compose service that gets the project URL
+// and the container port Traefik routes to
+func selectExposedService(file *compose.File, opts ImportOptions) (string, int, error) {
+	if opts.Internal {
+		return "", 0, nil
+	}
+
+	name := opts.Expose
+	if name != "" {
+		if _, ok := file.Services[name]; !ok {
+			return "", 0, fmt.Errorf("service '%s' not found in compose file", name)
+		}
+	} else {
+		for _, candidate := range file.ServiceNames() {
+			if len(file.Services[candidate].Ports) > 0 {
+				name = candidate
+				break
+			}
+		}
+		if name == "" {
+			// Nothing is published, keep the project internal
+			return "", 0, nil
+		}
+	}
+
+	port := opts.Port
+	if port == 0 {
+		exposedPorts, _, err := nat.ParsePortSpecs(file.Services[name].Ports)
+		if err != nil {
+			return "", 0, fmt.Errorf("service '%s' has invalid ports: %w", name, err)
+		}
+		for p := range exposedPorts {
+			if p.Proto() == "tcp" && (port == 0 || p.Int() < port) {
+				port = p.Int()
+			}
+		}
+	}
+
+	if port == 0 {
+		return "", 0, fmt.Errorf("service '%s' has no ports, use --port to set the port to route to", name)
+	}
+
+	return name, port, nil
+}
+
+// composeMounts converts compose volume entries into Docker mounts
+func composeMounts(project *types.Project, file *compose.File, svc *compose.Service) ([]mount.Mount, error) {
+	var mounts []mount.Mount
+
+	for _, entry := range svc.Volumes {
+		parts := strings.Split(entry, ":")
+		if len(parts) == 1 {
+			// Anonymous volume
+			mounts = append(mounts, mount.Mount{
+				Type:          mount.TypeVolume,
+				Target:        parts[0],
+				VolumeOptions: &mount.VolumeOptions{Labels: docker.InstanceLabels(project.Name)},
+			})
+			continue
+		}
+
+		if len(parts) > 3 {
+			return nil, fmt.Errorf("invalid volume: %s", entry)
+		}
+
+		source, target := parts[0], parts[1]
+		readOnly := len(parts) == 3 && strings.Contains(parts[2], "ro")
+
+		if isBindSource(source) {
+			if strings.HasPrefix(source, "~") {
+				home, err := os.UserHomeDir()
+				if err != nil {
+					return nil, err
+				}
+				source = filepath.Join(home, source[1:])
+			} else if !filepath.IsAbs(source) {
+				source = filepath.Join(project.Path, source)
+			}
+
+			mounts = append(mounts, mount.Mount{Type: mount.TypeBind, Source: source, Target: target, ReadOnly: readOnly})
+			continue
+		}
+
+		// Named volumes are namespaced by project unless declared external or named
+		volumeName := fmt.Sprintf("%s-%s", docker.GenerateContainerName(project.Name), source)
+		external := false
+		if volume := file.Volumes[source]; volume != nil {
+			if volume.Name != "" {
+				volumeName = volume.Name
+			} else if volume.External {
+				volumeName = source
+			}
+			external = volume.External
+		}
+
+		volumeMount := mount.Mount{Type: mount.TypeVolume, Source: volumeName, Target: target, ReadOnly: readOnly}
+		if !external {
+			// Only applied when Docker creates the volume
+			volumeMount.VolumeOptions = &mount.VolumeOptions{Labels: docker.InstanceLabels(project.Name)}
+		}
+		mounts = append(mounts, volumeMount)
+	}
+
+	return mounts, nil
+}
+
+// isBindSource returns true if a volume source refers to a host path
+func isBindSource(source string) bool {
+	return strings.HasPrefix(source, "/") || strings.HasPrefix(source, ".") || strings.HasPrefix(source, "~")
+}
+
+// composeContainerName returns the container name of a compose service
+func composeContainerName(projectName, serviceName string) string {
+	return fmt.Sprintf("%s-%s", docker.GenerateContainerName(projectName), serviceName)
+}
+
+// composeBuildImage returns the image tag used for a built compose service
+func composeBuildImage(projectName, serviceName string) string {
+	return fmt.Sprintf("doku-project-%s-%s:latest", projectName, serviceName)
+}
+
+// composeImage returns the image a compose service runs
+func composeImage(projectName, serviceName string, svc *compose.Service) string {
+	if svc.Build != nil {
+		if svc.Image != "" {
+			return svc.Image
+		}
+		return composeBuildImage(projectName, serviceName)
+	}
+	return svc.Image
+}
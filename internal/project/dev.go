@@ -0,0 +1,82 @@
+package project
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/docker/docker/api/types/mount"
+	"github.com/dokulabs/doku-cli/pkg/types"
+)
+
+// ParseDevMount parses a mount such as "./src:/app/src" or "./src:/app/src:ro".
+// A relative source is relative to baseDir, the project directory.
+func ParseDevMount(spec, baseDir string) (types.DevMount, error) {
+	var m types.DevMount
+
+	rest := spec
+	switch {
+	case strings.HasSuffix(rest, ":ro"):
+		m.ReadOnly = true
+		rest = strings.TrimSuffix(rest, ":ro")
+	case strings.HasSuffix(rest, ":rw"):
+		rest = strings.TrimSuffix(rest, ":rw")
+	}
+
+	// Split at the last colon so Windows sources like C:\src keep theirs
+	idx := strings.LastIndex(rest, ":")
+	if idx <= 0 || idx == len(rest)-1 {
+		return m, fmt.Errorf("invalid mount %q (use SOURCE:TARGET, e.g. ./src:/app/src)", spec)
+	}
+	source, target := rest[:idx], rest[idx+1:]
+
+	if !strings.HasPrefix(target, "/") {
+		return m, fmt.Errorf("invalid mount %q: the container path must be absolute", spec)
+	}
+	if !filepath.IsAbs(source) {
+		source = filepath.Join(baseDir, source)
+	}
+
+	m.Source = filepath.Clean(source)
+	m.Target = target
+	return m, nil
+}
+
+// devMounts returns the bind mounts of a project's dev mode
+func devMounts(dev *types.DevConfig) ([]mount.Mount, error) {
+	mounts := make([]mount.Mount, 0, len(dev.Mounts))
+	for _, m := range dev.Mounts {
+		if _, err := os.Stat(m.Source); err != nil {
+			return nil, fmt.Errorf("dev mount source %s: %w", m.Source, err)
+		}
+		mounts = append(mounts, mount.Mount{
+			Type:     mount.TypeBind,
+			Source:   m.Source,
+			Target:   m.Target,
+			ReadOnly: m.ReadOnly,
+		})
+	}
+	return mounts, nil
+}
+
+// SetDev turns dev mode on with dev, or off with nil. It takes effect the
+// next time the project runs.
+func (m *Manager) SetDev(name string, dev *types.DevConfig) error {
+	project, err := m.Get(name)
+	if err != nil {
+		return err
+	}
+	if project.IsCompose() {
+		return fmt.Errorf("dev mode is not supported for compose projects; add bind mounts to the compose file instead")
+	}
+
+	return m.configMgr.Update(func(c *types.Config) error {
+		proj, exists := c.Projects[name]
+		if !exists {
+			return fmt.Errorf("project not found: %s", name)
+		}
+		proj.Dev = dev
+		return nil
+	})
+}
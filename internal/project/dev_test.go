@@ -0,0 +1,40 @@
+package project
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/dokulabs/doku-cli/pkg/types"
+)
+
+func TestParseDevMount(t *testing.T) {
+	base := filepath.FromSlash("/home/me/myapp")
+
+	tests := []struct {
+		spec string
+		want types.DevMount
+	}{
+		{"./src:/app/src", types.DevMount{Source: filepath.Join(base, "src"), Target: "/app/src"}},
+		{"src:/app/src:rw", types.DevMount{Source: filepath.Join(base, "src"), Target: "/app/src"}},
+		{"./config:/app/config:ro", types.DevMount{Source: filepath.Join(base, "config"), Target: "/app/config", ReadOnly: true}},
+		{"/abs/path:/data", types.DevMount{Source: filepath.Clean("/abs/path"), Target: "/data"}},
+	}
+	for _, tt := range tests {
+		got, err := ParseDevMount(tt.spec, base)
+		if err != nil {
+			t.Errorf("ParseDevMount(%q) error = %v", tt.spec, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ParseDevMount(%q) = %+v, want %+v", tt.spec, got, tt.want)
+		}
+	}
+}
+
+func TestParseDevMountInvalid(t *testing.T) {
+	for _, spec := range []string{"./src", ":/app", "./src:", "./src:app/src"} {
+		if _, err := ParseDevMount(spec, "/base"); err == nil {
+			t.Errorf("ParseDevMount(%q) should fail", spec)
+		}
+	}
+}
@@ -0,0 +1,44 @@
+package project
+
+import (
+	"fmt"
+
+	"github.com/dokulabs/doku-cli/internal/config"
+	"github.com/dokulabs/doku-cli/pkg/types"
+)
+
+// SetLinks adds or replaces the named links in set and removes those in
+// remove, returning the project's links afterwards
+func (m *Manager) SetLinks(name string, set map[string]string, remove []string) (map[string]string, error) {
+	for linkName, url := range set {
+		if err := config.ValidateLink(linkName, url); err != nil {
+			return nil, err
+		}
+	}
+
+	var links map[string]string
+	err := m.configMgr.Update(func(c *types.Config) error {
+		proj, exists := c.Projects[name]
+		if !exists {
+			return fmt.Errorf("project '%s' not found", name)
+		}
+		for _, linkName := range remove {
+			if _, ok := proj.Links[linkName]; !ok {
+				return fmt.Errorf("project '%s' has no link named %s", name, linkName)
+			}
+			delete(proj.Links, linkName)
+		}
+		if len(set) > 0 && proj.Links == nil {
+			proj.Links = make(map[string]string, len(set))
+		}
+		for linkName, url := range set {
+			proj.Links[linkName] = url
+		}
+		if len(proj.Links) == 0 {
+			proj.Links = nil
+		}
+		links = proj.Links
+		return nil
+	})
+	return links, err
+}
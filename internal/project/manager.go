@@ -33,6 +33,9 @@ type AddOptions struct {
 	Domain       string            // Custom domain (optional)
 	Internal     bool              // Internal only (no Traefik)
 	Replace      bool              // Replace existing project if it exists
+	BuildArgs    map[string]string // Build arguments saved for every build
+	Target       string            // Dockerfile stage to build (optional)
+	Links        map[string]string // Named pages of the project, e.g. docs
 }
 
 // BuildOptions contains options for building a project
@@ -42,6 +45,10 @@ type BuildOptions struct {
 	Pull      bool              // Pull base image before building
 	Tag       string            // Custom tag
 	BuildArgs map[string]string // Build arguments for ARG directives in Dockerfile (NOT runtime env vars)
+	Target    string            // Dockerfile stage to build (defaults to the project's)
+	Secrets   []string          // BuildKit secrets, e.g. "id=npmrc,src=~/.npmrc"
+	SSH       []string          // SSH agents or keys to forward, e.g. "default"
+	Progress  string            // Progress mode: auto, plain or tty
 }
 
 // RunOptions contains options for running a project
@@ -133,13 +140,13 @@ func (m *Manager) Add(opts AddOptions) (*types.Project, error) {
 	// Create URL if not internal
 	url := ""
 	if !opts.Internal && opts.Port > 0 {
-		if isFullSubdomain {
-			// Domain was explicitly provided as full subdomain
-			url = fmt.Sprintf("https://%s", domain)
-		} else {
+		host := domain
+		if !isFullSubdomain {
 			// Domain from config (base domain), construct full subdomain
-			url = fmt.Sprintf("https://%s.%s", projectName, domain)
+			host = fmt.Sprintf("%s.%s", projectName, domain)
 		}
+		httpPort, httpsPort := m.configMgr.GetTraefikPorts()
+		url = config.ServiceURL("https", host, httpPort, httpsPort)
 	}
 
 	// Create project object
@@ -148,12 +155,15 @@ func (m *Manager) Add(opts AddOptions) (*types.Project, error) {
 		Path:          absPath,
 		Dockerfile:    dockerfileRelPath, // Store relative path
 		Status:        types.StatusStopped,
-		ContainerName: fmt.Sprintf("doku-%s", projectName),
+		ContainerName: docker.GenerateContainerName(projectName),
 		URL:           url,
 		Port:          opts.Port,
 		CreatedAt:     time.Now(),
 		Dependencies:  opts.Dependencies,
 		Environment:   opts.Environment,
+		BuildArgs:     opts.BuildArgs,
+		Target:        opts.Target,
+		Links:         opts.Links,
 	}
 
 	// Add port mappings
@@ -204,6 +214,10 @@ func (m *Manager) Build(opts BuildOptions) error {
 		return err
 	}
 
+	if project.IsCompose() {
+		return m.buildCompose(project)
+	}
+
 	// Build absolute Dockerfile path for validation
 	// project.Dockerfile is now stored as relative path
 	dockerfileAbsPath := project.Dockerfile
@@ -222,11 +236,37 @@ func (m *Manager) Build(opts BuildOptions) error {
 		imageTag = fmt.Sprintf("doku-project-%s:latest", project.Name)
 	}
 
-	// Convert build args to Docker format (map[string]*string)
+	// Convert build args to Docker format (map[string]*string); the
+	// project's saved build args apply unless overridden for this build
 	dockerBuildArgs := make(map[string]*string)
-	for k, v := range opts.BuildArgs {
-		value := v
-		dockerBuildArgs[k] = &value
+	for _, args := range []map[string]string{project.BuildArgs, opts.BuildArgs} {
+		for k, v := range args {
+			value := v
+			dockerBuildArgs[k] = &value
+		}
+	}
+
+	target := opts.Target
+	if target == "" {
+		target = project.Target
+	}
+
+	// Check secrets and SSH specs, making their paths absolute
+	secrets := make([]string, 0, len(opts.Secrets))
+	for _, spec := range opts.Secrets {
+		secret, err := ParseBuildSecret(spec)
+		if err != nil {
+			return err
+		}
+		secrets = append(secrets, secret)
+	}
+	ssh := make([]string, 0, len(opts.SSH))
+	for _, spec := range opts.SSH {
+		s, err := ParseBuildSSH(spec)
+		if err != nil {
+			return err
+		}
+		ssh = append(ssh, s)
 	}
 
 	// Build options - pass absolute Dockerfile path to builder
@@ -237,10 +277,28 @@ func (m *Manager) Build(opts BuildOptions) error {
 		NoCache:        opts.NoCache,
 		Pull:           opts.Pull,
 		BuildArgs:      dockerBuildArgs,
+		Target:         target,
+		Labels:         docker.InstanceLabels(project.Name),
+		Secrets:        secrets,
+		SSH:            ssh,
+		Progress:       opts.Progress,
+	}
+
+	// Keep the output of the last build for debugging
+	logPath := m.BuildLogPath(project.Name)
+	if err := os.MkdirAll(filepath.Dir(logPath), 0755); err != nil {
+		return fmt.Errorf("failed to create build log directory: %w", err)
+	}
+	logFile, err := os.Create(logPath)
+	if err != nil {
+		return fmt.Errorf("failed to create build log: %w", err)
 	}
+	defer logFile.Close()
+	buildOpts.Log = logFile
 
 	// Execute build
 	imageID, err := m.builder.Build(buildOpts)
+	fmt.Printf("Build log: %s\n", logPath)
 	if err != nil {
 		return err
 	}
@@ -259,6 +317,11 @@ func (m *Manager) Build(opts BuildOptions) error {
 	return nil
 }
 
+// BuildLogPath returns where the output of a project's last build is kept
+func (m *Manager) BuildLogPath(name string) string {
+	return filepath.Join(m.configMgr.GetProjectsDir(), name, "build.log")
+}
+
 // Run runs a project
 func (m *Manager) Run(opts RunOptions) error {
 	project, err := m.Get(opts.Name)
@@ -266,6 +329,10 @@ func (m *Manager) Run(opts RunOptions) error {
 		return err
 	}
 
+	if project.IsCompose() {
+		return m.runCompose(project, opts)
+	}
+
 	// Build if requested or if no image exists
 	imageTag := fmt.Sprintf("doku-project-%s:latest", project.Name)
 	if opts.Build || !m.imageExists(imageTag) {
@@ -295,6 +362,15 @@ func (m *Manager) Run(opts RunOptions) error {
 		return err
 	}
 
+	// Replicas copy the container, so they are replaced along with it
+	if project.ReplicaCount() > 1 {
+		if proj, err := m.Get(project.Name); err == nil {
+			if err := m.docker.SyncReplicas(proj.ContainerName, proj.ReplicaCount(), true); err != nil {
+				fmt.Printf("Warning: failed to recreate the replicas of %s: %v\n", project.Name, err)
+			}
+		}
+	}
+
 	// Update status
 	return m.configMgr.Update(func(c *types.Config) error {
 		if proj, exists := c.Projects[project.Name]; exists {
@@ -311,6 +387,10 @@ func (m *Manager) Start(name string) error {
 		return err
 	}
 
+	if project.IsCompose() {
+		return m.startCompose(project)
+	}
+
 	// Check if container exists
 	exists, err := m.docker.ContainerExists(project.ContainerName)
 	if err != nil {
@@ -336,6 +416,11 @@ func (m *Manager) Start(name string) error {
 	if err := m.docker.ContainerStart(containerID); err != nil {
 		return fmt.Errorf("failed to start container: %w", err)
 	}
+	if project.ReplicaCount() > 1 {
+		if err := m.docker.StartReplicas(project.ContainerName); err != nil {
+			fmt.Printf("Warning: failed to start some replicas of %s: %v\n", name, err)
+		}
+	}
 
 	// Update status
 	return m.configMgr.Update(func(c *types.Config) error {
@@ -354,6 +439,10 @@ func (m *Manager) Stop(name string) error {
 		return err
 	}
 
+	if project.IsCompose() {
+		return m.stopCompose(project)
+	}
+
 	// Check if container exists
 	exists, err := m.docker.ContainerExists(project.ContainerName)
 	if err != nil {
@@ -379,6 +468,11 @@ func (m *Manager) Stop(name string) error {
 	if err := m.docker.ContainerStop(containerID, &timeout); err != nil {
 		return fmt.Errorf("failed to stop container: %w", err)
 	}
+	if project.ReplicaCount() > 1 {
+		if err := m.docker.StopReplicas(project.ContainerName); err != nil {
+			fmt.Printf("Warning: failed to stop some replicas of %s: %v\n", name, err)
+		}
+	}
 
 	// Update status
 	return m.configMgr.Update(func(c *types.Config) error {
@@ -396,6 +490,13 @@ func (m *Manager) Restart(name string) error {
 		return err
 	}
 
+	if project.IsCompose() {
+		if err := m.stopCompose(project); err != nil {
+			return err
+		}
+		return m.startCompose(project)
+	}
+
 	// Check if container exists
 	exists, err := m.docker.ContainerExists(project.ContainerName)
 	if err != nil {
@@ -421,6 +522,14 @@ func (m *Manager) Restart(name string) error {
 	if err := m.docker.ContainerRestart(containerID, &timeout); err != nil {
 		return fmt.Errorf("failed to restart container: %w", err)
 	}
+	if project.ReplicaCount() > 1 {
+		if err := m.docker.StopReplicas(project.ContainerName); err == nil {
+			err = m.docker.StartReplicas(project.ContainerName)
+		}
+		if err != nil {
+			fmt.Printf("Warning: failed to restart some replicas of %s: %v\n", name, err)
+		}
+	}
 
 	return nil
 }
@@ -432,6 +541,10 @@ func (m *Manager) Remove(name string, removeImage bool) error {
 		return err
 	}
 
+	if project.IsCompose() {
+		return m.removeCompose(project, removeImage)
+	}
+
 	// Check if container exists
 	exists, err := m.docker.ContainerExists(project.ContainerName)
 	if err != nil {
@@ -462,6 +575,9 @@ func (m *Manager) Remove(name string, removeImage bool) error {
 			}
 		}
 	}
+	if err := m.docker.RemoveReplicas(project.ContainerName); err != nil {
+		fmt.Printf("Warning: failed to remove replicas: %v\n", err)
+	}
 
 	// Remove image if requested
 	if removeImage {
@@ -475,16 +591,18 @@ func (m *Manager) Remove(name string, removeImage bool) error {
 	// Remove DNS entry if project has a URL
 	if project.URL != "" {
 		// Extract subdomain from URL (e.g., "https://ui.doku.local" -> "ui.doku.local")
-		subdomain := strings.TrimPrefix(project.URL, "https://")
-		subdomain = strings.TrimPrefix(subdomain, "http://")
+		subdomain := config.URLHost(project.URL)
 
-		dnsMgr := dns.NewManager()
+		dnsMgr := dns.NewManagerForContext(m.configMgr.GetContext())
 		if err := dnsMgr.RemoveSingleEntry(subdomain); err != nil {
 			// Only show warning, don't fail the removal
 			fmt.Printf("Warning: failed to remove DNS entry: %v\n", err)
 		}
 	}
 
+	// Remove the build log
+	os.Remove(m.BuildLogPath(project.Name))
+
 	// Remove from config
 	return m.configMgr.RemoveProject(name)
 }
@@ -0,0 +1,141 @@
+package project
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/dokulabs/doku-cli/internal/docker"
+	"github.com/fatih/color"
+)
+
+// Build progress modes
+const (
+	ProgressAuto  = "auto"  // tty on a terminal, plain otherwise
+	ProgressPlain = "plain" // BuildKit's full output
+	ProgressTTY   = "tty"   // One line per step, with its duration
+)
+
+// maxStepOutput is how many output lines of a step are kept to show when
+// it fails
+const maxStepOutput = 20
+
+// ValidateProgress checks a --progress value
+func ValidateProgress(mode string) error {
+	switch mode {
+	case "", ProgressAuto, ProgressPlain, ProgressTTY:
+		return nil
+	}
+	return fmt.Errorf("invalid progress mode %q (use auto, plain or tty)", mode)
+}
+
+// resolveProgress turns auto into plain or tty for out
+func resolveProgress(mode string, out *os.File) string {
+	if mode == ProgressPlain || mode == ProgressTTY {
+		return mode
+	}
+	if docker.IsTerminal(out) {
+		return ProgressTTY
+	}
+	return ProgressPlain
+}
+
+// buildStep is a step of a BuildKit build, e.g. "#5 [2/4] RUN npm ci"
+type buildStep struct {
+	name   string
+	output []string
+}
+
+// stepPrinter reads BuildKit's plain progress line by line and prints one
+// line per finished step with its duration, or CACHED for cache hits. The
+// output of a failing step is printed in full.
+type stepPrinter struct {
+	out     io.Writer
+	steps   map[string]*buildStep
+	current string // Step shown on the live status line
+}
+
+func newStepPrinter(out io.Writer) *stepPrinter {
+	return &stepPrinter{out: out, steps: make(map[string]*buildStep)}
+}
+
+// Line handles one line of plain progress output
+func (p *stepPrinter) Line(line string) {
+	id, rest, ok := strings.Cut(line, " ")
+	if !ok || !strings.HasPrefix(id, "#") {
+		return
+	}
+
+	step, seen := p.steps[id]
+	if !seen {
+		step = &buildStep{name: rest}
+		p.steps[id] = step
+		p.status(step)
+		return
+	}
+
+	switch {
+	case rest == "CACHED":
+		p.finish(step, color.New(color.Faint).Sprint("CACHED"))
+	case strings.HasPrefix(rest, "DONE "):
+		p.finish(step, color.New(color.Faint).Sprint(strings.TrimPrefix(rest, "DONE ")))
+	case strings.HasPrefix(rest, "ERROR"):
+		p.clearStatus()
+		fmt.Fprintf(p.out, "  %s %s\n", color.RedString("✗"), step.name)
+		for _, out := range step.output {
+			fmt.Fprintf(p.out, "    %s\n", out)
+		}
+		fmt.Fprintf(p.out, "    %s\n", color.RedString(rest))
+	default:
+		step.output = append(step.output, rest)
+		if len(step.output) > maxStepOutput {
+			step.output = step.output[len(step.output)-maxStepOutput:]
+		}
+	}
+}
+
+// Close ends the live status line
+func (p *stepPrinter) Close() {
+	p.clearStatus()
+}
+
+// status shows a step that started on the live status line. Internal steps
+// such as loading the Dockerfile are not shown.
+func (p *stepPrinter) status(step *buildStep) {
+	if isInternalStep(step.name) {
+		return
+	}
+	p.clearStatus()
+	fmt.Fprintf(p.out, "  %s %s", color.CyanString("→"), step.name)
+	p.current = step.name
+}
+
+// finish prints a finished step with a detail such as its duration
+func (p *stepPrinter) finish(step *buildStep, detail string) {
+	if isInternalStep(step.name) {
+		return
+	}
+	p.clearStatus()
+	fmt.Fprintf(p.out, "  %s %s  %s\n", color.GreenString("✓"), step.name, detail)
+}
+
+func (p *stepPrinter) clearStatus() {
+	if p.current != "" {
+		fmt.Fprint(p.out, "\r\033[K")
+		p.current = ""
+	}
+}
+
+// isInternalStep reports whether a step is BuildKit housekeeping rather
+// than a Dockerfile instruction
+func isInternalStep(name string) bool {
+	return strings.HasPrefix(name, "[internal]") ||
+		strings.HasPrefix(name, "building with") ||
+		strings.HasPrefix(name, "exporting") ||
+		strings.HasPrefix(name, "naming to") ||
+		strings.HasPrefix(name, "writing image") ||
+		strings.HasPrefix(name, "importing to") ||
+		strings.HasPrefix(name, "sending tarball") ||
+		strings.HasPrefix(name, "load ")
+}
@@ -0,0 +1,62 @@
+package project
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/fatih/color"
+)
+
+func TestStepPrinter(t *testing.T) {
+	color.NoColor = true
+
+	var out bytes.Buffer
+	p := newStepPrinter(&out)
+	for _, line := range []string{
+		"#1 [internal] load build definition from Dockerfile",
+		"#1 transferring dockerfile: 120B done",
+		"#1 DONE 0.0s",
+		"#2 [1/3] FROM docker.io/library/node:20",
+		"#2 CACHED",
+		"#3 [2/3] RUN npm ci",
+		"#3 0.512 added 120 packages",
+		"#3 DONE 3.2s",
+		"#4 [3/3] RUN npm run build",
+		"#4 0.301 Error: missing script: build",
+		"#4 ERROR: process \"/bin/sh -c npm run build\" did not complete successfully: exit code: 1",
+		"------",
+	} {
+		p.Line(line)
+	}
+	p.Close()
+
+	got := out.String()
+	for _, want := range []string{
+		"✓ [1/3] FROM docker.io/library/node:20  CACHED",
+		"✓ [2/3] RUN npm ci  3.2s",
+		"✗ [3/3] RUN npm run build",
+		"0.301 Error: missing script: build",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("output missing %q:\n%s", want, got)
+		}
+	}
+	if strings.Contains(got, "[internal]") {
+		t.Errorf("output shows internal steps:\n%s", got)
+	}
+	if strings.Contains(got, "added 120 packages") {
+		t.Errorf("output shows the output of a successful step:\n%s", got)
+	}
+}
+
+func TestValidateProgress(t *testing.T) {
+	for _, mode := range []string{"", ProgressAuto, ProgressPlain, ProgressTTY} {
+		if err := ValidateProgress(mode); err != nil {
+			t.Errorf("ValidateProgress(%q) error = %v", mode, err)
+		}
+	}
+	if err := ValidateProgress("fancy"); err == nil {
+		t.Error("ValidateProgress(\"fancy\") should fail")
+	}
+}
@@ -2,6 +2,7 @@ package project
 
 import (
 	"fmt"
+	"net/url"
 	"strings"
 
 	"github.com/AlecAivazis/survey/v2"
@@ -11,6 +12,8 @@ import (
 	"github.com/dokulabs/doku-cli/internal/catalog"
 	"github.com/dokulabs/doku-cli/internal/config"
 	"github.com/dokulabs/doku-cli/internal/docker"
+	"github.com/dokulabs/doku-cli/internal/envfile"
+	"github.com/dokulabs/doku-cli/internal/monitoring"
 	"github.com/dokulabs/doku-cli/internal/service"
 	"github.com/dokulabs/doku-cli/pkg/types"
 	"github.com/fatih/color"
@@ -70,7 +73,7 @@ func (r *Runner) Run(opts ContainerRunOptions) error {
 			// Internal or no URL - bind to host
 			portBindings[containerPort] = []nat.PortBinding{
 				{
-					HostIP:   "0.0.0.0",
+					HostIP:   config.PublishIP(cfg.Preferences),
 					HostPort: fmt.Sprintf("%d", opts.Project.Port),
 				},
 			}
@@ -88,7 +91,7 @@ func (r *Runner) Run(opts ContainerRunOptions) error {
 				exposedPorts[containerPort] = struct{}{}
 				portBindings[containerPort] = []nat.PortBinding{
 					{
-						HostIP:   "0.0.0.0",
+						HostIP:   config.PublishIP(cfg.Preferences),
 						HostPort: hostPort,
 					},
 				}
@@ -96,9 +99,18 @@ func (r *Runner) Run(opts ContainerRunOptions) error {
 		}
 	}
 
-	// Prepare environment variables
+	// Prepare environment variables, resolving references to other instances
+	projectEnv := opts.Project.Environment
+	if envfile.HasReferences(projectEnv) {
+		resolved, err := envfile.Interpolate(projectEnv, service.EnvResolver(r.configMgr))
+		if err != nil {
+			return err
+		}
+		projectEnv = resolved
+	}
+
 	env := []string{}
-	for key, value := range opts.Project.Environment {
+	for key, value := range projectEnv {
 		if !strings.HasPrefix(key, "DOKU_") { // Skip internal Doku variables
 			env = append(env, fmt.Sprintf("%s=%s", key, value))
 		}
@@ -110,16 +122,13 @@ func (r *Runner) Run(opts ContainerRunOptions) error {
 	}
 
 	// Prepare Traefik labels
-	labels := map[string]string{
-		"doku.managed": "true",
-		"doku.type":    "project",
-		"doku.name":    opts.Project.Name,
-	}
+	labels := docker.InstanceLabels(opts.Project.Name)
+	labels["doku.type"] = "project"
+	labels["doku.name"] = opts.Project.Name
 
 	// Add Traefik labels if project has a URL
 	if opts.Project.URL != "" {
-		domain := strings.TrimPrefix(opts.Project.URL, "https://")
-		domain = strings.TrimPrefix(domain, "http://")
+		domain := config.URLHost(opts.Project.URL)
 
 		labels["traefik.enable"] = "true"
 		labels[fmt.Sprintf("traefik.http.routers.%s.rule", opts.Project.Name)] = fmt.Sprintf("Host(`%s`)", domain)
@@ -142,6 +151,20 @@ func (r *Runner) Run(opts ContainerRunOptions) error {
 		RestartPolicy: container.RestartPolicy{
 			Name: "unless-stopped",
 		},
+		LogConfig: *monitoring.GetDockerLoggingConfig(&cfg.Monitoring, cfg.Preferences.Logs),
+	}
+
+	// Dev mode runs the mounted sources with the dev command
+	if dev := opts.Project.Dev; dev != nil {
+		mounts, err := devMounts(dev)
+		if err != nil {
+			return err
+		}
+		hostConfig.Mounts = mounts
+		if dev.Command != "" {
+			containerConfig.Cmd = []string{"sh", "-c", dev.Command}
+		}
+		labels["doku.dev"] = "true"
 	}
 
 	// Network config - connect to Doku network
@@ -195,6 +218,17 @@ func (r *Runner) Run(opts ContainerRunOptions) error {
 	green.Println("✓ Project started successfully")
 	fmt.Println()
 
+	if dev := opts.Project.Dev; dev != nil {
+		fmt.Println("Dev mode:")
+		for _, m := range dev.Mounts {
+			cyan.Printf("  %s → %s\n", m.Source, m.Target)
+		}
+		if dev.Command != "" {
+			cyan.Printf("  Command: %s\n", dev.Command)
+		}
+		fmt.Println()
+	}
+
 	if opts.Project.URL != "" {
 		fmt.Println("Access your project:")
 		cyan.Printf("  URL: %s\n", opts.Project.URL)
@@ -202,7 +236,7 @@ func (r *Runner) Run(opts ContainerRunOptions) error {
 		fmt.Println("Project is running:")
 		cyan.Printf("  Container: %s\n", opts.Project.ContainerName)
 		if opts.Project.Port > 0 {
-			cyan.Printf("  Port: http://localhost:%d\n", opts.Project.Port)
+			cyan.Printf("  Port: http://%s:%d\n", config.LocalHost(cfg.Preferences), opts.Project.Port)
 		}
 	}
 	fmt.Println()
@@ -330,14 +364,16 @@ func (r *Runner) injectDependencyEnvVars(project *types.Project, env *[]string)
 		// Inject connection string based on service type
 		switch instance.ServiceType {
 		case "postgres", "postgresql":
-			connStr := fmt.Sprintf("postgresql://postgres@%s.%s:5432", serviceName, cfg.Preferences.Domain)
+			userinfo := dependencyUserinfo(instance.Environment, "POSTGRES_USER", "postgres", "POSTGRES_PASSWORD")
+			connStr := fmt.Sprintf("postgresql://%s@%s.%s:5432", userinfo, serviceName, cfg.Preferences.Domain)
 			*env = append(*env, fmt.Sprintf("DATABASE_URL=%s", connStr))
 			*env = append(*env, fmt.Sprintf("POSTGRES_URL=%s", connStr))
 			*env = append(*env, fmt.Sprintf("DB_HOST=%s.%s", serviceName, cfg.Preferences.Domain))
 			*env = append(*env, fmt.Sprintf("DB_PORT=5432"))
 
 		case "mysql":
-			connStr := fmt.Sprintf("mysql://root@%s.%s:3306", serviceName, cfg.Preferences.Domain)
+			userinfo := dependencyUserinfo(instance.Environment, "", "root", "MYSQL_ROOT_PASSWORD")
+			connStr := fmt.Sprintf("mysql://%s@%s.%s:3306", userinfo, serviceName, cfg.Preferences.Domain)
 			*env = append(*env, fmt.Sprintf("DATABASE_URL=%s", connStr))
 			*env = append(*env, fmt.Sprintf("MYSQL_URL=%s", connStr))
 			*env = append(*env, fmt.Sprintf("DB_HOST=%s.%s", serviceName, cfg.Preferences.Domain))
@@ -345,12 +381,19 @@ func (r *Runner) injectDependencyEnvVars(project *types.Project, env *[]string)
 
 		case "redis":
 			connStr := fmt.Sprintf("redis://%s.%s:6379", serviceName, cfg.Preferences.Domain)
+			if password := instance.Environment["REDIS_PASSWORD"]; password != "" {
+				connStr = fmt.Sprintf("redis://%s@%s.%s:6379", url.UserPassword("", password), serviceName, cfg.Preferences.Domain)
+			}
 			*env = append(*env, fmt.Sprintf("REDIS_URL=%s", connStr))
 			*env = append(*env, fmt.Sprintf("REDIS_HOST=%s.%s", serviceName, cfg.Preferences.Domain))
 			*env = append(*env, fmt.Sprintf("REDIS_PORT=6379"))
 
 		case "rabbitmq":
-			connStr := fmt.Sprintf("amqp://guest:guest@%s.%s:5672", serviceName, cfg.Preferences.Domain)
+			userinfo := dependencyUserinfo(instance.Environment, "RABBITMQ_DEFAULT_USER", "guest", "RABBITMQ_DEFAULT_PASS")
+			if !strings.Contains(userinfo, ":") {
+				userinfo += ":guest"
+			}
+			connStr := fmt.Sprintf("amqp://%s@%s.%s:5672", userinfo, serviceName, cfg.Preferences.Domain)
 			*env = append(*env, fmt.Sprintf("RABBITMQ_URL=%s", connStr))
 			*env = append(*env, fmt.Sprintf("AMQP_URL=%s", connStr))
 			*env = append(*env, fmt.Sprintf("RABBITMQ_HOST=%s.%s", serviceName, cfg.Preferences.Domain))
@@ -365,6 +408,19 @@ func (r *Runner) injectDependencyEnvVars(project *types.Project, env *[]string)
 	return nil
 }
 
+// dependencyUserinfo returns the user and password part of a connection
+// URL from a dependency's environment, so generated passwords are included
+func dependencyUserinfo(env map[string]string, userKey, defaultUser, passwordKey string) string {
+	user := defaultUser
+	if v := env[userKey]; userKey != "" && v != "" {
+		user = v
+	}
+	if password := env[passwordKey]; password != "" {
+		return url.UserPassword(user, password).String()
+	}
+	return url.User(user).String()
+}
+
 // PromptInstallDependencies prompts user to install missing dependencies
 func (r *Runner) PromptInstallDependencies(project *types.Project) (bool, error) {
 	missing, err := r.checkDependencies(project.Dependencies)
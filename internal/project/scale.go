@@ -0,0 +1,45 @@
+package project
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/dokulabs/doku-cli/internal/docker"
+	"github.com/dokulabs/doku-cli/pkg/types"
+)
+
+// Scale runs replicas copies of a project's container, the original
+// included, behind the same Traefik route. Compose projects can't be
+// scaled.
+func (m *Manager) Scale(name string, replicas int) error {
+	project, err := m.Get(name)
+	if err != nil {
+		return err
+	}
+	if replicas < 1 {
+		return fmt.Errorf("replicas must be at least 1, got %d", replicas)
+	}
+	if project.IsCompose() {
+		return fmt.Errorf("'%s' is a compose project and can't be scaled", name)
+	}
+	if project.ContainerName == "" {
+		return fmt.Errorf("container not found, please run: doku project run %s", name)
+	}
+
+	if err := m.docker.SyncReplicas(project.ContainerName, replicas, false); err != nil {
+		if errors.Is(err, docker.ErrStatefulReplicas) {
+			return fmt.Errorf("'%s' can't be scaled: %w", name, err)
+		}
+		return err
+	}
+
+	return m.configMgr.Update(func(c *types.Config) error {
+		if proj, exists := c.Projects[name]; exists {
+			proj.Replicas = 0
+			if replicas > 1 {
+				proj.Replicas = replicas
+			}
+		}
+		return nil
+	})
+}
@@ -0,0 +1,97 @@
+package project
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ParseBuildSecret checks a BuildKit secret such as "id=npmrc,src=~/.npmrc"
+// or "id=token,env=GITHUB_TOKEN" and returns it with its file made
+// absolute, as docker buildx --secret expects it
+func ParseBuildSecret(spec string) (string, error) {
+	var id, src, env string
+	for _, field := range strings.Split(spec, ",") {
+		key, value, ok := strings.Cut(field, "=")
+		if !ok || value == "" {
+			return "", fmt.Errorf("invalid secret %q (use id=ID,src=FILE or id=ID,env=VAR)", spec)
+		}
+		switch key {
+		case "id":
+			id = value
+		case "src", "source":
+			src = value
+		case "env":
+			env = value
+		case "type":
+			if value != "file" && value != "env" {
+				return "", fmt.Errorf("invalid secret %q: type must be file or env", spec)
+			}
+		default:
+			return "", fmt.Errorf("invalid secret %q: unknown key %q", spec, key)
+		}
+	}
+
+	switch {
+	case id == "":
+		return "", fmt.Errorf("invalid secret %q: id is required", spec)
+	case src != "" && env != "":
+		return "", fmt.Errorf("invalid secret %q: use either src or env", spec)
+	case env != "":
+		if _, ok := os.LookupEnv(env); !ok {
+			return "", fmt.Errorf("secret '%s': environment variable %s is not set", id, env)
+		}
+		return fmt.Sprintf("id=%s,env=%s", id, env), nil
+	case src == "":
+		return "", fmt.Errorf("invalid secret %q: src or env is required", spec)
+	}
+
+	path, err := absPath(src)
+	if err != nil {
+		return "", err
+	}
+	if _, err := os.Stat(path); err != nil {
+		return "", fmt.Errorf("secret '%s': %w", id, err)
+	}
+	return fmt.Sprintf("id=%s,src=%s", id, path), nil
+}
+
+// ParseBuildSSH checks an SSH forwarding spec for docker buildx --ssh:
+// "default" for the SSH agent, or "ID=KEY[,KEY...]" for agent sockets or
+// private keys, whose paths are made absolute
+func ParseBuildSSH(spec string) (string, error) {
+	id, paths, ok := strings.Cut(spec, "=")
+	if id == "" {
+		return "", fmt.Errorf("invalid SSH spec %q (use default or ID=PATH)", spec)
+	}
+	if !ok {
+		return id, nil
+	}
+
+	var abs []string
+	for _, p := range strings.Split(paths, ",") {
+		path, err := absPath(p)
+		if err != nil {
+			return "", err
+		}
+		if _, err := os.Stat(path); err != nil {
+			return "", fmt.Errorf("SSH key for '%s': %w", id, err)
+		}
+		abs = append(abs, path)
+	}
+	return id + "=" + strings.Join(abs, ","), nil
+}
+
+// absPath makes a path given on the command line absolute, expanding a
+// leading ~ to the home directory
+func absPath(path string) (string, error) {
+	if path == "~" || strings.HasPrefix(path, "~/") || strings.HasPrefix(path, `~\`) {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", err
+		}
+		return filepath.Join(home, path[1:]), nil
+	}
+	return filepath.Abs(path)
+}
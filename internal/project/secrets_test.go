@@ -0,0 +1,70 @@
+package project
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestParseBuildSecret(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, ".npmrc")
+	if err := os.WriteFile(file, []byte("//registry.npmjs.org/:_authToken=x"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("DOKU_TEST_TOKEN", "secret")
+
+	tests := []struct {
+		spec string
+		want string
+	}{
+		{"id=npmrc,src=" + file, "id=npmrc,src=" + file},
+		{"id=npmrc,type=file,source=" + file, "id=npmrc,src=" + file},
+		{"id=token,env=DOKU_TEST_TOKEN", "id=token,env=DOKU_TEST_TOKEN"},
+	}
+	for _, tt := range tests {
+		got, err := ParseBuildSecret(tt.spec)
+		if err != nil {
+			t.Errorf("ParseBuildSecret(%q) error = %v", tt.spec, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ParseBuildSecret(%q) = %q, want %q", tt.spec, got, tt.want)
+		}
+	}
+
+	for _, spec := range []string{
+		"npmrc",
+		"src=" + file,
+		"id=npmrc",
+		"id=npmrc,src=" + filepath.Join(dir, "missing"),
+		"id=token,env=DOKU_TEST_UNSET_VARIABLE",
+		"id=npmrc,src=" + file + ",env=DOKU_TEST_TOKEN",
+		"id=npmrc,mode=0400",
+	} {
+		if _, err := ParseBuildSecret(spec); err == nil {
+			t.Errorf("ParseBuildSecret(%q) should fail", spec)
+		}
+	}
+}
+
+func TestParseBuildSSH(t *testing.T) {
+	dir := t.TempDir()
+	key := filepath.Join(dir, "id_ed25519")
+	if err := os.WriteFile(key, []byte("key"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	if got, err := ParseBuildSSH("default"); err != nil || got != "default" {
+		t.Errorf("ParseBuildSSH(default) = %q, %v", got, err)
+	}
+	if got, err := ParseBuildSSH("github=" + key); err != nil || got != "github="+key {
+		t.Errorf("ParseBuildSSH(github=key) = %q, %v", got, err)
+	}
+	if _, err := ParseBuildSSH("github=" + filepath.Join(dir, "missing")); err == nil {
+		t.Error("ParseBuildSSH() should fail for a missing key")
+	}
+	if _, err := ParseBuildSSH("=" + key); err == nil {
+		t.Error("ParseBuildSSH() should fail without an ID")
+	}
+}
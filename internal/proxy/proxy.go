@@ -0,0 +1,96 @@
+// Package proxy chooses the reverse proxy routing requests for the
+// services' hostnames to their containers: Traefik, or Caddy
+package proxy
+
+import (
+	"fmt"
+
+	"github.com/dokulabs/doku-cli/internal/caddy"
+	"github.com/dokulabs/doku-cli/internal/config"
+	"github.com/dokulabs/doku-cli/internal/docker"
+	"github.com/dokulabs/doku-cli/internal/traefik"
+	"github.com/dokulabs/doku-cli/pkg/types"
+)
+
+// Backends of the proxy
+const (
+	Traefik = "traefik"
+	Caddy   = "caddy"
+)
+
+// Proxy is the reverse proxy in front of the services. Both backends route
+// from the Traefik labels Doku gives the containers: Traefik reads them
+// itself, Caddy from a Caddyfile generated from them.
+type Proxy interface {
+	Name() string
+	ContainerName() string
+
+	// WriteConfig writes the proxy's configuration files
+	WriteConfig() error
+	// Setup writes the proxy's configuration and starts its container
+	Setup() error
+	EnsureRunning() error
+	IsRunning() (bool, error)
+	RestartContainer() error
+	RemoveContainer() error
+
+	// SyncRoutes brings the routes up to date with the containers' labels
+	SyncRoutes() error
+
+	// GetDashboardURL returns the URL of the proxy's dashboard, "" if it
+	// has none
+	GetDashboardURL() string
+}
+
+// Backend returns the proxy backend of the preferences
+func Backend(prefs types.PreferencesConfig) string {
+	if prefs.Proxy == "" {
+		return Traefik
+	}
+	return prefs.Proxy
+}
+
+// Validate checks a proxy backend is one Doku supports
+func Validate(backend string) error {
+	if backend != Traefik && backend != Caddy {
+		return fmt.Errorf("invalid proxy '%s': expected %s or %s", backend, Traefik, Caddy)
+	}
+	return nil
+}
+
+// New returns the proxy chosen in the configuration
+func New(dockerClient *docker.Client, cfgMgr *config.Manager, cfg *types.Config) Proxy {
+	prefs := cfg.Preferences
+	if Backend(prefs) == Caddy {
+		return caddy.NewManager(dockerClient, cfgMgr.GetCaddyDir(), prefs.Protocol).
+			SetPorts(cfgMgr.GetTraefikPorts()).
+			SetHostIP(config.PublishIP(prefs)).
+			SetLogRotation(prefs.Logs)
+	}
+	return traefik.NewManager(dockerClient, cfgMgr.GetTraefikDir(), cfgMgr.GetCertsDir(), prefs.Domain, prefs.Protocol).
+		SetPorts(cfgMgr.GetTraefikPorts()).
+		SetHostIP(config.PublishIP(prefs)).
+		SetVersion(cfg.Traefik.Version).
+		SetLogRotation(prefs.Logs)
+}
+
+// ContainerName returns the name of the container of the configured proxy
+func ContainerName(prefs types.PreferencesConfig) string {
+	if Backend(prefs) == Caddy {
+		return caddy.ContainerName()
+	}
+	return traefik.ContainerName()
+}
+
+// SyncRoutes brings the configured proxy's routes up to date with the
+// containers' labels, after containers were created or removed
+func SyncRoutes(dockerClient *docker.Client, cfgMgr *config.Manager) error {
+	cfg, err := cfgMgr.Get()
+	if err != nil {
+		return err
+	}
+	if Backend(cfg.Preferences) != Caddy {
+		return nil
+	}
+	return New(dockerClient, cfgMgr, cfg).SyncRoutes()
+}
@@ -0,0 +1,29 @@
+package proxy
+
+import (
+	"testing"
+
+	"github.com/dokulabs/doku-cli/pkg/types"
+)
+
+func TestBackend(t *testing.T) {
+	if got := Backend(types.PreferencesConfig{}); got != Traefik {
+		t.Errorf("Backend() = %q, want %q", got, Traefik)
+	}
+	if got := Backend(types.PreferencesConfig{Proxy: Caddy}); got != Caddy {
+		t.Errorf("Backend() = %q, want %q", got, Caddy)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	for _, backend := range []string{Traefik, Caddy} {
+		if err := Validate(backend); err != nil {
+			t.Errorf("Validate(%q) error = %v", backend, err)
+		}
+	}
+	for _, backend := range []string{"", "nginx"} {
+		if err := Validate(backend); err == nil {
+			t.Errorf("Validate(%q) should fail", backend)
+		}
+	}
+}
@@ -0,0 +1,44 @@
+// Package readonly implements read-only mode, in which Doku refuses to
+// change Docker resources or any of the files it manages. It lets a
+// terminal be handed to someone for troubleshooting without the risk of
+// accidental changes.
+package readonly
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"sync/atomic"
+
+	"github.com/dokulabs/doku-cli/pkg/types"
+)
+
+// EnvVar enables read-only mode when set to a true value
+const EnvVar = "DOKU_READ_ONLY"
+
+var enabled atomic.Bool
+
+// Enable turns read-only mode on or off for the rest of the process
+func Enable(on bool) {
+	enabled.Store(on)
+}
+
+// Enabled reports whether read-only mode is on
+func Enabled() bool {
+	return enabled.Load()
+}
+
+// EnabledByEnv reports whether the environment asks for read-only mode
+func EnabledByEnv() bool {
+	on, err := strconv.ParseBool(os.Getenv(EnvVar))
+	return err == nil && on
+}
+
+// Check returns an error wrapping types.ErrReadOnly when read-only mode is
+// on. action describes the refused change, e.g. "stop container postgres".
+func Check(action string) error {
+	if !enabled.Load() {
+		return nil
+	}
+	return fmt.Errorf("%w: refusing to %s", types.ErrReadOnly, action)
+}
@@ -0,0 +1,39 @@
+package readonly
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/dokulabs/doku-cli/pkg/types"
+)
+
+func TestCheck(t *testing.T) {
+	defer Enable(false)
+
+	if err := Check("stop container postgres"); err != nil {
+		t.Fatalf("Check() with read-only mode off = %v, expected nil", err)
+	}
+
+	Enable(true)
+	if !Enabled() {
+		t.Fatal("Enabled() = false after Enable(true)")
+	}
+
+	err := Check("stop container postgres")
+	if !errors.Is(err, types.ErrReadOnly) {
+		t.Fatalf("Check() = %v, expected ErrReadOnly", err)
+	}
+	if !strings.Contains(err.Error(), "stop container postgres") {
+		t.Errorf("error should name the refused action, got: %v", err)
+	}
+}
+
+func TestEnabledByEnv(t *testing.T) {
+	for value, expected := range map[string]bool{"": false, "0": false, "false": false, "1": true, "true": true, "yes": false} {
+		t.Setenv(EnvVar, value)
+		if got := EnabledByEnv(); got != expected {
+			t.Errorf("EnabledByEnv() with %s=%q = %v, expected %v", EnvVar, value, got, expected)
+		}
+	}
+}
@@ -0,0 +1,133 @@
+// Package scaffold generates new projects from built-in templates: the
+// source of a minimal app, a Dockerfile and a doku.yaml that registers it.
+package scaffold
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/template"
+
+	"github.com/dokulabs/doku-cli/internal/config"
+	"github.com/dokulabs/doku-cli/internal/manifest"
+)
+
+// Template is a built-in project template
+type Template struct {
+	Name        string
+	Description string
+	Port        int               // Port the app listens on
+	Files       map[string]string // Relative path to content
+}
+
+// Data fills in a template's files
+type Data struct {
+	Name string // Project name
+	Port int    // Port the app listens on
+}
+
+// Templates returns the built-in templates sorted by name
+func Templates() []*Template {
+	list := make([]*Template, 0, len(templates))
+	for _, t := range templates {
+		list = append(list, t)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].Name < list[j].Name
+	})
+	return list
+}
+
+// Get returns a built-in template by name
+func Get(name string) (*Template, error) {
+	for _, t := range templates {
+		if t.Name == name {
+			return t, nil
+		}
+	}
+
+	names := make([]string, 0, len(templates))
+	for _, t := range Templates() {
+		names = append(names, t.Name)
+	}
+	return nil, fmt.Errorf("unknown template %q (available: %s)", name, strings.Join(names, ", "))
+}
+
+// Generate writes the files of t and a doku.yaml to dir and returns the
+// paths written, relative to dir. It refuses to overwrite existing files
+// unless force is set.
+func Generate(t *Template, dir string, data Data, force bool) ([]string, error) {
+	if err := config.ValidateInstanceName(data.Name); err != nil {
+		return nil, err
+	}
+	if data.Port == 0 {
+		data.Port = t.Port
+	}
+
+	files, err := render(t, data)
+	if err != nil {
+		return nil, err
+	}
+
+	paths := make([]string, 0, len(files))
+	for path := range files {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	if !force {
+		for _, path := range paths {
+			if _, err := os.Stat(filepath.Join(dir, filepath.FromSlash(path))); err == nil {
+				return nil, fmt.Errorf("%s already exists (use --force to overwrite)", filepath.Join(dir, path))
+			}
+		}
+	}
+
+	for _, path := range paths {
+		target := filepath.Join(dir, filepath.FromSlash(path))
+		if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+			return nil, fmt.Errorf("failed to create %s: %w", filepath.Dir(target), err)
+		}
+		if err := os.WriteFile(target, files[path], 0644); err != nil {
+			return nil, fmt.Errorf("failed to write %s: %w", path, err)
+		}
+	}
+
+	return paths, nil
+}
+
+// manifestTemplate is the doku.yaml written with every template, so the
+// generated directory can be brought up again with 'doku apply'
+const manifestTemplate = `# Doku workspace for [[.Name]]
+# Run 'doku apply' in this directory to build and start it.
+projects:
+  [[.Name]]:
+    path: .
+    port: [[.Port]]
+`
+
+// render fills in the files of t, adding the doku.yaml
+func render(t *Template, data Data) (map[string][]byte, error) {
+	sources := map[string]string{manifest.DefaultFileName: manifestTemplate}
+	for path, content := range t.Files {
+		sources[path] = content
+	}
+
+	files := make(map[string][]byte, len(sources))
+	for path, content := range sources {
+		// [[ ]] delimiters leave the {{ }} of the generated apps alone
+		tmpl, err := template.New(path).Delims("[[", "]]").Parse(content)
+		if err != nil {
+			return nil, fmt.Errorf("template %s: %s: %w", t.Name, path, err)
+		}
+		var buf bytes.Buffer
+		if err := tmpl.Execute(&buf, data); err != nil {
+			return nil, fmt.Errorf("template %s: %s: %w", t.Name, path, err)
+		}
+		files[path] = buf.Bytes()
+	}
+	return files, nil
+}
@@ -0,0 +1,103 @@
+package scaffold
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/dokulabs/doku-cli/internal/manifest"
+)
+
+func TestGenerateAllTemplates(t *testing.T) {
+	for _, tmpl := range Templates() {
+		t.Run(tmpl.Name, func(t *testing.T) {
+			dir := t.TempDir()
+
+			paths, err := Generate(tmpl, dir, Data{Name: "my-app"}, false)
+			if err != nil {
+				t.Fatalf("Generate() error = %v", err)
+			}
+			if len(paths) != len(tmpl.Files)+1 {
+				t.Errorf("Generate() wrote %d files, want %d", len(paths), len(tmpl.Files)+1)
+			}
+
+			for _, path := range paths {
+				content, err := os.ReadFile(filepath.Join(dir, path))
+				if err != nil {
+					t.Fatalf("reading %s: %v", path, err)
+				}
+				if strings.Contains(string(content), "[[") {
+					t.Errorf("%s has unrendered placeholders", path)
+				}
+			}
+
+			m, err := manifest.Load(filepath.Join(dir, manifest.DefaultFileName))
+			if err != nil {
+				t.Fatalf("generated doku.yaml: %v", err)
+			}
+			proj := m.Projects["my-app"]
+			if proj == nil {
+				t.Fatal("doku.yaml does not declare my-app")
+			}
+			if proj.Port != tmpl.Port {
+				t.Errorf("doku.yaml port = %d, want %d", proj.Port, tmpl.Port)
+			}
+			if got := m.ProjectPath(proj); got != dir {
+				t.Errorf("doku.yaml project path = %s, want %s", got, dir)
+			}
+		})
+	}
+}
+
+func TestGeneratePort(t *testing.T) {
+	tmpl, err := Get("node-express")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dir := t.TempDir()
+	if _, err := Generate(tmpl, dir, Data{Name: "api", Port: 4000}, false); err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+
+	dockerfile, _ := os.ReadFile(filepath.Join(dir, "Dockerfile"))
+	if !strings.Contains(string(dockerfile), "EXPOSE 4000") {
+		t.Errorf("Dockerfile does not expose the chosen port:\n%s", dockerfile)
+	}
+}
+
+func TestGenerateExistingFiles(t *testing.T) {
+	tmpl, err := Get("static-site")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "Dockerfile"), []byte("FROM scratch\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := Generate(tmpl, dir, Data{Name: "site"}, false); err == nil {
+		t.Error("Generate() should refuse to overwrite an existing Dockerfile")
+	}
+	if _, err := Generate(tmpl, dir, Data{Name: "site"}, true); err != nil {
+		t.Errorf("Generate() with force error = %v", err)
+	}
+}
+
+func TestGenerateInvalidName(t *testing.T) {
+	tmpl, err := Get("go-api")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Generate(tmpl, t.TempDir(), Data{Name: "My App"}, false); err == nil {
+		t.Error("Generate() with an invalid name should fail")
+	}
+}
+
+func TestGetUnknown(t *testing.T) {
+	if _, err := Get("cobol"); err == nil || !strings.Contains(err.Error(), "node-express") {
+		t.Errorf("Get() error = %v, want a list of templates", err)
+	}
+}
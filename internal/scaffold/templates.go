@@ -0,0 +1,342 @@
+package scaffold
+
+// templates are the built-in templates, keyed by name. File contents use
+// [[ ]] for the project's Name and Port.
+var templates = map[string]*Template{
+	"node-express": {
+		Name:        "node-express",
+		Description: "Node.js API with Express",
+		Port:        3000,
+		Files: map[string]string{
+			"Dockerfile":    nodeExpressDockerfile,
+			".dockerignore": "node_modules\nnpm-debug.log\n",
+			"package.json":  nodeExpressPackage,
+			"server.js":     nodeExpressServer,
+		},
+	},
+	"go-api": {
+		Name:        "go-api",
+		Description: "Go HTTP API using the standard library",
+		Port:        8080,
+		Files: map[string]string{
+			"Dockerfile":    goAPIDockerfile,
+			".dockerignore": "bin\n",
+			"go.mod":        goAPIModule,
+			"main.go":       goAPIMain,
+		},
+	},
+	"django": {
+		Name:        "django",
+		Description: "Django app served by Gunicorn",
+		Port:        8000,
+		Files: map[string]string{
+			"Dockerfile":         djangoDockerfile,
+			".dockerignore":      "__pycache__\n*.pyc\n.venv\ndb.sqlite3\n",
+			"requirements.txt":   "Django>=5.0,<6.0\ngunicorn>=22.0\n",
+			"manage.py":          djangoManage,
+			"config/__init__.py": "",
+			"config/settings.py": djangoSettings,
+			"config/urls.py":     djangoURLs,
+			"config/wsgi.py":     djangoWSGI,
+		},
+	},
+	"rails": {
+		Name:        "rails",
+		Description: "Minimal Ruby on Rails app served by Puma",
+		Port:        3000,
+		Files: map[string]string{
+			"Dockerfile":    railsDockerfile,
+			".dockerignore": "log\ntmp\n.bundle\n",
+			"Gemfile":       railsGemfile,
+			"config.ru":     railsConfigRU,
+		},
+	},
+	"static-site": {
+		Name:        "static-site",
+		Description: "Static HTML site served by nginx",
+		Port:        80,
+		Files: map[string]string{
+			"Dockerfile":        staticDockerfile,
+			".dockerignore":     "Dockerfile\ndoku.yaml\n",
+			"public/index.html": staticIndex,
+			"public/style.css":  staticStyle,
+			"nginx.conf":        staticNginx,
+		},
+	},
+}
+
+const nodeExpressDockerfile = `FROM node:20-alpine
+WORKDIR /app
+COPY package*.json ./
+RUN npm install --omit=dev
+COPY . .
+ENV PORT=[[.Port]]
+EXPOSE [[.Port]]
+CMD ["node", "server.js"]
+`
+
+const nodeExpressPackage = `{
+  "name": "[[.Name]]",
+  "version": "0.1.0",
+  "private": true,
+  "main": "server.js",
+  "scripts": {
+    "start": "node server.js"
+  },
+  "dependencies": {
+    "express": "^4.19.2"
+  }
+}
+`
+
+const nodeExpressServer = `const express = require("express");
+
+const app = express();
+const port = process.env.PORT || [[.Port]];
+
+app.get("/", (req, res) => {
+  res.json({ name: "[[.Name]]", message: "Hello from Doku" });
+});
+
+app.get("/health", (req, res) => {
+  res.json({ status: "ok" });
+});
+
+app.listen(port, "0.0.0.0", () => {
+  console.log("[[.Name]] listening on port " + port);
+});
+`
+
+const goAPIDockerfile = `FROM golang:1.23-alpine AS build
+WORKDIR /src
+COPY go.mod ./
+RUN go mod download
+COPY . .
+RUN CGO_ENABLED=0 go build -o /bin/app .
+
+FROM alpine:3.20
+COPY --from=build /bin/app /usr/local/bin/app
+ENV PORT=[[.Port]]
+EXPOSE [[.Port]]
+CMD ["app"]
+`
+
+const goAPIModule = `module [[.Name]]
+
+go 1.23
+`
+
+const goAPIMain = `package main
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"os"
+)
+
+func main() {
+	port := os.Getenv("PORT")
+	if port == "" {
+		port = "[[.Port]]"
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, map[string]string{"name": "[[.Name]]", "message": "Hello from Doku"})
+	})
+	mux.HandleFunc("GET /health", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, map[string]string{"status": "ok"})
+	})
+
+	log.Printf("[[.Name]] listening on port %s", port)
+	log.Fatal(http.ListenAndServe(":"+port, mux))
+}
+
+func writeJSON(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(v)
+}
+`
+
+const djangoDockerfile = `FROM python:3.12-slim
+ENV PYTHONDONTWRITEBYTECODE=1 PYTHONUNBUFFERED=1
+WORKDIR /app
+COPY requirements.txt .
+RUN pip install --no-cache-dir -r requirements.txt
+COPY . .
+EXPOSE [[.Port]]
+CMD ["gunicorn", "config.wsgi:application", "--bind", "0.0.0.0:[[.Port]]"]
+`
+
+const djangoManage = `#!/usr/bin/env python
+import os
+import sys
+
+if __name__ == "__main__":
+    os.environ.setdefault("DJANGO_SETTINGS_MODULE", "config.settings")
+    from django.core.management import execute_from_command_line
+
+    execute_from_command_line(sys.argv)
+`
+
+const djangoSettings = `import os
+from pathlib import Path
+
+BASE_DIR = Path(__file__).resolve().parent.parent
+
+# Set DJANGO_SECRET_KEY with 'doku env set [[.Name]] DJANGO_SECRET_KEY=...'
+SECRET_KEY = os.environ.get("DJANGO_SECRET_KEY", "insecure-local-development-key")
+DEBUG = os.environ.get("DJANGO_DEBUG", "true").lower() == "true"
+ALLOWED_HOSTS = ["*"]
+CSRF_TRUSTED_ORIGINS = ["https://*.doku.local", "http://*.doku.local"]
+
+INSTALLED_APPS = [
+    "django.contrib.contenttypes",
+    "django.contrib.staticfiles",
+]
+
+MIDDLEWARE = [
+    "django.middleware.security.SecurityMiddleware",
+    "django.middleware.common.CommonMiddleware",
+]
+
+ROOT_URLCONF = "config.urls"
+WSGI_APPLICATION = "config.wsgi.application"
+
+DATABASES = {
+    "default": {
+        "ENGINE": "django.db.backends.sqlite3",
+        "NAME": BASE_DIR / "db.sqlite3",
+    }
+}
+
+STATIC_URL = "static/"
+DEFAULT_AUTO_FIELD = "django.db.models.BigAutoField"
+`
+
+const djangoURLs = `from django.http import JsonResponse
+from django.urls import path
+
+
+def index(request):
+    return JsonResponse({"name": "[[.Name]]", "message": "Hello from Doku"})
+
+
+def health(request):
+    return JsonResponse({"status": "ok"})
+
+
+urlpatterns = [
+    path("", index),
+    path("health", health),
+]
+`
+
+const djangoWSGI = `import os
+
+from django.core.wsgi import get_wsgi_application
+
+os.environ.setdefault("DJANGO_SETTINGS_MODULE", "config.settings")
+
+application = get_wsgi_application()
+`
+
+const railsDockerfile = `FROM ruby:3.3-slim
+RUN apt-get update && apt-get install -y --no-install-recommends build-essential \
+    && rm -rf /var/lib/apt/lists/*
+WORKDIR /app
+COPY Gemfile* ./
+RUN bundle install
+COPY . .
+EXPOSE [[.Port]]
+CMD ["bundle", "exec", "puma", "-b", "tcp://0.0.0.0:[[.Port]]", "config.ru"]
+`
+
+const railsGemfile = `source "https://rubygems.org"
+
+gem "rails", "~> 7.1"
+gem "puma", "~> 6.4"
+`
+
+const railsConfigRU = `# A single-file Rails app. Run 'rails new' for the full directory layout
+# once the app outgrows it.
+require "rails"
+require "action_controller/railtie"
+
+class App < Rails::Application
+  config.root = __dir__
+  config.eager_load = false
+  config.logger = Logger.new($stdout)
+  config.hosts.clear
+  config.secret_key_base = ENV.fetch("SECRET_KEY_BASE", "insecure-local-development-key")
+
+  routes.append do
+    root "home#index"
+    get "health" => "home#health"
+  end
+end
+
+class HomeController < ActionController::API
+  def index
+    render json: { name: "[[.Name]]", message: "Hello from Doku" }
+  end
+
+  def health
+    render json: { status: "ok" }
+  end
+end
+
+App.initialize!
+
+run App
+`
+
+const staticDockerfile = `FROM nginx:alpine
+COPY nginx.conf /etc/nginx/conf.d/default.conf
+COPY public/ /usr/share/nginx/html/
+EXPOSE [[.Port]]
+`
+
+const staticIndex = `<!doctype html>
+<html lang="en">
+<head>
+  <meta charset="utf-8">
+  <meta name="viewport" content="width=device-width, initial-scale=1">
+  <title>[[.Name]]</title>
+  <link rel="stylesheet" href="style.css">
+</head>
+<body>
+  <main>
+    <h1>[[.Name]]</h1>
+    <p>Hello from Doku. Edit <code>public/index.html</code> and run
+    <code>doku project run [[.Name]] --build</code> to see your changes.</p>
+  </main>
+</body>
+</html>
+`
+
+const staticStyle = `body {
+  font-family: system-ui, sans-serif;
+  margin: 0;
+  color: #1f2933;
+  background: #f5f7fa;
+}
+
+main {
+  max-width: 40em;
+  margin: 4em auto;
+  padding: 0 1em;
+}
+`
+
+const staticNginx = `server {
+    listen [[.Port]];
+    root /usr/share/nginx/html;
+    index index.html;
+
+    location / {
+        try_files $uri $uri/ =404;
+    }
+}
+`
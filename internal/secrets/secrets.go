@@ -0,0 +1,208 @@
+// Package secrets encrypts the values of env files and the sensitive fields
+// of the configuration at rest. Values are encrypted one by one with
+// AES-256-GCM, so that files keep their layout and the names of their
+// variables readable. The key is kept in the OS keychain (Keychain on
+// macOS, the Secret Service on Linux, the Credential Manager on Windows),
+// and values are decrypted in memory only, when Doku reads them.
+package secrets
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/zalando/go-keyring"
+)
+
+const (
+	// Prefix marks an encrypted value, e.g. enc:v1:3q2-7w...
+	Prefix = "enc:v1:"
+
+	// KeyEnvVar holds the key, base64-encoded, on machines without a
+	// keychain such as CI runners. It takes precedence over the keychain.
+	KeyEnvVar = "DOKU_SECRET_KEY"
+
+	// keyringService and keyringUser name the key in the keychain
+	keyringService = "doku"
+	keyringUser    = "encryption-key"
+
+	keySize = 32
+)
+
+// ErrNoKey is returned when encrypted values are read or written but no
+// key has been created
+var ErrNoKey = errors.New("no encryption key in the OS keychain or " + KeyEnvVar)
+
+var (
+	enabled atomic.Bool
+
+	keyMu     sync.Mutex
+	cachedKey []byte
+)
+
+// Enable turns encryption of written values on or off for the rest of the
+// process. Encrypted values are decrypted when read either way.
+func Enable(on bool) {
+	enabled.Store(on)
+}
+
+// Enabled reports whether written values are encrypted
+func Enabled() bool {
+	return enabled.Load()
+}
+
+// IsEncrypted reports whether a value was encrypted by Encrypt
+func IsEncrypted(value string) bool {
+	return strings.HasPrefix(value, Prefix)
+}
+
+// EnsureKey returns the key, creating one in the keychain if there is none
+// yet
+func EnsureKey() ([]byte, error) {
+	key, err := loadKey()
+	if err == nil || !errors.Is(err, ErrNoKey) {
+		return key, err
+	}
+
+	key = make([]byte, keySize)
+	if _, err := rand.Read(key); err != nil {
+		return nil, fmt.Errorf("failed to generate encryption key: %w", err)
+	}
+	if err := keyring.Set(keyringService, keyringUser, base64.StdEncoding.EncodeToString(key)); err != nil {
+		return nil, fmt.Errorf("failed to store the encryption key in the OS keychain: %w", err)
+	}
+
+	keyMu.Lock()
+	cachedKey = key
+	keyMu.Unlock()
+	return key, nil
+}
+
+// loadKey returns the key from KeyEnvVar or the keychain. It is read once
+// per process, since a keychain may ask the user for access.
+func loadKey() ([]byte, error) {
+	keyMu.Lock()
+	defer keyMu.Unlock()
+
+	if cachedKey != nil {
+		return cachedKey, nil
+	}
+
+	encoded := os.Getenv(KeyEnvVar)
+	source := KeyEnvVar
+	if encoded == "" {
+		var err error
+		encoded, err = keyring.Get(keyringService, keyringUser)
+		if err != nil {
+			if errors.Is(err, keyring.ErrNotFound) {
+				return nil, ErrNoKey
+			}
+			return nil, fmt.Errorf("failed to read the encryption key from the OS keychain: %w", err)
+		}
+		source = "the OS keychain"
+	}
+
+	key, err := base64.StdEncoding.DecodeString(strings.TrimSpace(encoded))
+	if err != nil || len(key) != keySize {
+		return nil, fmt.Errorf("invalid encryption key in %s: expected %d base64-encoded bytes", source, keySize)
+	}
+	cachedKey = key
+	return key, nil
+}
+
+// ExportKey returns the key, base64-encoded, to set KeyEnvVar on another
+// machine
+func ExportKey() (string, error) {
+	key, err := loadKey()
+	if err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(key), nil
+}
+
+// Encrypt encrypts a value. Empty and already encrypted values are
+// returned as they are.
+func Encrypt(value string) (string, error) {
+	if value == "" || IsEncrypted(value) {
+		return value, nil
+	}
+
+	gcm, err := newGCM()
+	if err != nil {
+		return "", err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", fmt.Errorf("failed to encrypt: %w", err)
+	}
+	sealed := gcm.Seal(nonce, nonce, []byte(value), nil)
+	return Prefix + base64.RawURLEncoding.EncodeToString(sealed), nil
+}
+
+// Decrypt decrypts a value encrypted by Encrypt. Other values are returned
+// as they are, so that plaintext left from before encryption was turned on
+// is still read.
+func Decrypt(value string) (string, error) {
+	encoded, ok := strings.CutPrefix(value, Prefix)
+	if !ok {
+		return value, nil
+	}
+
+	gcm, err := newGCM()
+	if err != nil {
+		return "", err
+	}
+	sealed, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil || len(sealed) < gcm.NonceSize() {
+		return "", fmt.Errorf("failed to decrypt: malformed value")
+	}
+	plain, err := gcm.Open(nil, sealed[:gcm.NonceSize()], sealed[gcm.NonceSize():], nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to decrypt: the value was encrypted with another key")
+	}
+	return string(plain), nil
+}
+
+// EncryptMap returns a copy of env with its values encrypted
+func EncryptMap(env map[string]string) (map[string]string, error) {
+	return transformMap(env, Encrypt)
+}
+
+// DecryptMap returns a copy of env with its values decrypted
+func DecryptMap(env map[string]string) (map[string]string, error) {
+	return transformMap(env, Decrypt)
+}
+
+func transformMap(env map[string]string, fn func(string) (string, error)) (map[string]string, error) {
+	if env == nil {
+		return nil, nil
+	}
+	result := make(map[string]string, len(env))
+	for k, v := range env {
+		transformed, err := fn(v)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", k, err)
+		}
+		result[k] = transformed
+	}
+	return result, nil
+}
+
+func newGCM() (cipher.AEAD, error) {
+	key, err := loadKey()
+	if err != nil {
+		return nil, err
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
@@ -0,0 +1,136 @@
+package secrets
+
+import (
+	"encoding/base64"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/zalando/go-keyring"
+)
+
+// useMockKeychain replaces the OS keychain and forgets the cached key
+func useMockKeychain(t *testing.T) {
+	t.Helper()
+	keyring.MockInit()
+	t.Setenv(KeyEnvVar, "")
+	cachedKey = nil
+	t.Cleanup(func() { cachedKey = nil })
+}
+
+func TestEncryptDecrypt(t *testing.T) {
+	useMockKeychain(t)
+	if _, err := EnsureKey(); err != nil {
+		t.Fatalf("EnsureKey() error = %v", err)
+	}
+
+	encrypted, err := Encrypt("s3cret=value")
+	if err != nil {
+		t.Fatalf("Encrypt() error = %v", err)
+	}
+	if !IsEncrypted(encrypted) || strings.Contains(encrypted, "s3cret") {
+		t.Errorf("Encrypt() = %q, expected an encrypted value", encrypted)
+	}
+	if again, _ := Encrypt("s3cret=value"); again == encrypted {
+		t.Error("Encrypt() returned the same value twice, expected a fresh nonce")
+	}
+
+	decrypted, err := Decrypt(encrypted)
+	if err != nil {
+		t.Fatalf("Decrypt() error = %v", err)
+	}
+	if decrypted != "s3cret=value" {
+		t.Errorf("Decrypt() = %q, expected %q", decrypted, "s3cret=value")
+	}
+
+	// Encrypted values aren't encrypted twice, and plaintext is read as is
+	if twice, _ := Encrypt(encrypted); twice != encrypted {
+		t.Error("Encrypt() of an encrypted value changed it")
+	}
+	if plain, _ := Decrypt("plain"); plain != "plain" {
+		t.Errorf("Decrypt(plain) = %q", plain)
+	}
+	if empty, _ := Encrypt(""); empty != "" {
+		t.Errorf("Encrypt(\"\") = %q, expected empty", empty)
+	}
+}
+
+func TestDecryptWithAnotherKey(t *testing.T) {
+	useMockKeychain(t)
+	t.Setenv(KeyEnvVar, base64.StdEncoding.EncodeToString(make([]byte, keySize)))
+
+	encrypted, err := Encrypt("value")
+	if err != nil {
+		t.Fatalf("Encrypt() error = %v", err)
+	}
+
+	cachedKey = nil
+	other := make([]byte, keySize)
+	other[0] = 1
+	t.Setenv(KeyEnvVar, base64.StdEncoding.EncodeToString(other))
+	if _, err := Decrypt(encrypted); err == nil {
+		t.Error("Decrypt() with another key succeeded, expected an error")
+	}
+}
+
+func TestNoKey(t *testing.T) {
+	useMockKeychain(t)
+
+	if _, err := Encrypt("value"); !errors.Is(err, ErrNoKey) {
+		t.Errorf("Encrypt() without a key error = %v, expected ErrNoKey", err)
+	}
+
+	key, err := EnsureKey()
+	if err != nil {
+		t.Fatalf("EnsureKey() error = %v", err)
+	}
+	stored, err := keyring.Get(keyringService, keyringUser)
+	if err != nil {
+		t.Fatalf("key not stored in the keychain: %v", err)
+	}
+	if stored != base64.StdEncoding.EncodeToString(key) {
+		t.Error("EnsureKey() stored a different key than it returned")
+	}
+}
+
+func TestInvalidKey(t *testing.T) {
+	useMockKeychain(t)
+	t.Setenv(KeyEnvVar, "too-short")
+
+	if _, err := Encrypt("value"); err == nil || !strings.Contains(err.Error(), KeyEnvVar) {
+		t.Errorf("Encrypt() with an invalid key error = %v, expected one naming %s", err, KeyEnvVar)
+	}
+}
+
+func TestEncryptMap(t *testing.T) {
+	useMockKeychain(t)
+	if _, err := EnsureKey(); err != nil {
+		t.Fatalf("EnsureKey() error = %v", err)
+	}
+
+	env := map[string]string{"USER": "admin", "PASSWORD": "hunter2"}
+	encrypted, err := EncryptMap(env)
+	if err != nil {
+		t.Fatalf("EncryptMap() error = %v", err)
+	}
+	if env["PASSWORD"] != "hunter2" {
+		t.Error("EncryptMap() changed its argument")
+	}
+	for k, v := range encrypted {
+		if !IsEncrypted(v) {
+			t.Errorf("%s = %q, expected an encrypted value", k, v)
+		}
+	}
+
+	decrypted, err := DecryptMap(encrypted)
+	if err != nil {
+		t.Fatalf("DecryptMap() error = %v", err)
+	}
+	if decrypted["USER"] != "admin" || decrypted["PASSWORD"] != "hunter2" {
+		t.Errorf("DecryptMap() = %v", decrypted)
+	}
+
+	if m, err := EncryptMap(nil); m != nil || err != nil {
+		t.Errorf("EncryptMap(nil) = %v, %v", m, err)
+	}
+}
@@ -0,0 +1,60 @@
+package service
+
+import (
+	"fmt"
+
+	"github.com/dokulabs/doku-cli/internal/docker"
+	"github.com/dokulabs/doku-cli/pkg/types"
+	"github.com/fatih/color"
+)
+
+// SpecImages returns the images a spec runs, without duplicates
+func SpecImages(spec *types.ServiceSpec) []string {
+	var images []string
+	seen := make(map[string]bool)
+	add := func(image string) {
+		if image != "" && !seen[image] {
+			seen[image] = true
+			images = append(images, image)
+		}
+	}
+
+	for _, c := range spec.InitContainers {
+		add(c.Image)
+	}
+	if spec.IsMultiContainer() {
+		for _, c := range spec.Containers {
+			add(c.Image)
+		}
+	} else {
+		add(spec.Image)
+	}
+
+	return images
+}
+
+// warnEmulatedImages warns about images that have no build for the
+// daemon's architecture before they are pulled, since they run under
+// emulation and both the pull and the service are much slower. Cached
+// images and images whose manifest can't be checked are skipped.
+func warnEmulatedImages(dockerClient *docker.Client, spec *types.ServiceSpec, arch string) {
+	var emulated []string
+	for _, image := range SpecImages(spec) {
+		if cached, err := dockerClient.ImageExists(image); err != nil || cached {
+			continue
+		}
+		if native, err := dockerClient.ImageHasArch(image, arch); err == nil && !native {
+			emulated = append(emulated, image)
+		}
+	}
+
+	if len(emulated) == 0 {
+		return
+	}
+
+	for _, image := range emulated {
+		color.Yellow("⚠️  %s has no linux/%s image and will run under emulation, which is much slower", image, arch)
+	}
+	color.New(color.Faint).Printf("A catalog spec can name a native image with: images.%s = \"<image>\"\n", arch)
+	fmt.Println()
+}
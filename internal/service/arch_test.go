@@ -0,0 +1,56 @@
+package service
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/dokulabs/doku-cli/pkg/types"
+)
+
+func TestSpecForArch(t *testing.T) {
+	spec := &types.ServiceSpec{
+		Image:  "clickhouse:24",
+		Images: map[string]string{"arm64": "clickhouse:24-arm64"},
+		InitContainers: []types.InitContainer{
+			{Name: "migrate", Image: "migrator:1", Images: map[string]string{"amd64": "migrator:1-amd64"}},
+		},
+	}
+
+	arm := spec.ForArch("arm64")
+	if arm.Image != "clickhouse:24-arm64" {
+		t.Errorf("Image = %s, want the arm64 override", arm.Image)
+	}
+	if arm.InitContainers[0].Image != "migrator:1" {
+		t.Errorf("init image = %s, want the default", arm.InitContainers[0].Image)
+	}
+	if spec.Image != "clickhouse:24" {
+		t.Error("ForArch must not modify the catalog spec")
+	}
+
+	amd := spec.ForArch("amd64")
+	if amd.Image != "clickhouse:24" || amd.InitContainers[0].Image != "migrator:1-amd64" {
+		t.Errorf("amd64 images = %s, %s", amd.Image, amd.InitContainers[0].Image)
+	}
+}
+
+func TestSpecImages(t *testing.T) {
+	spec := &types.ServiceSpec{
+		Image: "ignored:1",
+		Containers: []types.ContainerSpec{
+			{Name: "frontend", Image: "signoz/frontend:1"},
+			{Name: "query", Image: "signoz/query:1"},
+			{Name: "query-2", Image: "signoz/query:1"},
+		},
+		InitContainers: []types.InitContainer{{Name: "migrate", Image: "signoz/migrate:1"}},
+	}
+
+	want := []string{"signoz/migrate:1", "signoz/frontend:1", "signoz/query:1"}
+	if got := SpecImages(spec); !reflect.DeepEqual(got, want) {
+		t.Errorf("SpecImages() = %v, want %v", got, want)
+	}
+
+	single := &types.ServiceSpec{Image: "postgres:16"}
+	if got := SpecImages(single); !reflect.DeepEqual(got, []string{"postgres:16"}) {
+		t.Errorf("SpecImages() = %v", got)
+	}
+}
@@ -0,0 +1,146 @@
+package service
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/dokulabs/doku-cli/pkg/types"
+)
+
+// DefaultAutoUpdateInterval is how often an auto-updated image is checked
+const DefaultAutoUpdateInterval = 24 * time.Hour
+
+// maxAutoUpdateHistory is how many updates are kept per instance
+const maxAutoUpdateHistory = 20
+
+// ParseWindow parses a daily time window such as "02:00-05:00" into minutes
+// after midnight. A window may wrap midnight, e.g. "22:00-04:00".
+func ParseWindow(window string) (start, end int, err error) {
+	var startH, startM, endH, endM int
+	if _, err := fmt.Sscanf(window, "%d:%d-%d:%d", &startH, &startM, &endH, &endM); err != nil {
+		return 0, 0, fmt.Errorf("invalid window %q (use HH:MM-HH:MM, e.g. 02:00-05:00)", window)
+	}
+	for _, v := range []struct{ h, m int }{{startH, startM}, {endH, endM}} {
+		if v.h < 0 || v.h > 23 || v.m < 0 || v.m > 59 {
+			return 0, 0, fmt.Errorf("invalid window %q: times must be between 00:00 and 23:59", window)
+		}
+	}
+
+	start, end = startH*60+startM, endH*60+endM
+	if start == end {
+		return 0, 0, fmt.Errorf("invalid window %q: start and end are the same", window)
+	}
+	return start, end, nil
+}
+
+// InWindow reports whether t falls in a daily window. An empty window
+// allows any time.
+func InWindow(window string, t time.Time) (bool, error) {
+	if window == "" {
+		return true, nil
+	}
+	start, end, err := ParseWindow(window)
+	if err != nil {
+		return false, err
+	}
+
+	minute := t.Hour()*60 + t.Minute()
+	if start < end {
+		return minute >= start && minute < end, nil
+	}
+	return minute >= start || minute < end, nil
+}
+
+// AutoUpdateDue reports whether an instance's image should be checked at
+// now: auto-update is on, the interval has passed and now is in the window
+func AutoUpdateDue(instance *types.Instance, now time.Time) bool {
+	policy := instance.AutoUpdate
+	if policy == nil {
+		return false
+	}
+
+	interval := policy.Interval
+	if interval <= 0 {
+		interval = DefaultAutoUpdateInterval
+	}
+	if !policy.LastCheck.IsZero() && now.Sub(policy.LastCheck) < interval {
+		return false
+	}
+
+	inWindow, err := InWindow(policy.Window, now)
+	return err == nil && inWindow
+}
+
+// AutoUpdate pulls the image tag of an instance again and recreates its
+// container if the tag now points to a different image. It returns the
+// update made, or nil when the image is unchanged. The rollback snapshot
+// records the old image by ID, so 'doku rollback' restores it although the
+// tag has moved.
+func (m *Manager) AutoUpdate(instanceName string) (*types.AutoUpdateRecord, error) {
+	instance, err := m.configMgr.GetInstance(instanceName)
+	if err != nil {
+		return nil, fmt.Errorf("instance not found: %w", err)
+	}
+	if instance.IsMultiContainer {
+		return nil, fmt.Errorf("auto-update is not supported for multi-container services yet")
+	}
+
+	containerInfo, err := m.dockerClient.ContainerInspect(instance.ContainerName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to inspect container: %w", err)
+	}
+	imageRef := imageTag(containerInfo.Config)
+	oldID := containerInfo.Image
+
+	if err := m.dockerClient.ImagePullQuiet(imageRef); err != nil {
+		return nil, err
+	}
+	newID, err := m.dockerClient.ImageID(imageRef)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	if newID == oldID {
+		return nil, m.recordAutoUpdate(instanceName, now, nil)
+	}
+
+	if err := m.RecreateWithImage(instanceName, imageRef); err != nil {
+		return nil, err
+	}
+
+	record := &types.AutoUpdateRecord{
+		Image:      imageRef,
+		OldImageID: oldID,
+		NewImageID: newID,
+		UpdatedAt:  now,
+	}
+	return record, m.recordAutoUpdate(instanceName, now, record)
+}
+
+// recordAutoUpdate saves the time of a check and the update it made, if any
+func (m *Manager) recordAutoUpdate(instanceName string, checkedAt time.Time, record *types.AutoUpdateRecord) error {
+	instance, err := m.configMgr.GetInstance(instanceName)
+	if err != nil {
+		return err
+	}
+	if instance.AutoUpdate == nil {
+		instance.AutoUpdate = &types.AutoUpdateConfig{}
+	}
+
+	instance.AutoUpdate.LastCheck = checkedAt
+	if record != nil {
+		history := append(instance.AutoUpdate.History, *record)
+		if len(history) > maxAutoUpdateHistory {
+			history = history[len(history)-maxAutoUpdateHistory:]
+		}
+		instance.AutoUpdate.History = history
+
+		// Roll back to the exact image that was replaced
+		if instance.Previous != nil {
+			instance.Previous.Image = record.OldImageID
+		}
+	}
+
+	return m.configMgr.UpdateInstance(instanceName, instance)
+}
@@ -0,0 +1,66 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/dokulabs/doku-cli/pkg/types"
+)
+
+func TestInWindow(t *testing.T) {
+	at := func(h, m int) time.Time {
+		return time.Date(2024, 1, 1, h, m, 0, 0, time.Local)
+	}
+
+	tests := []struct {
+		window string
+		t      time.Time
+		want   bool
+	}{
+		{"", at(13, 0), true},
+		{"02:00-05:00", at(2, 0), true},
+		{"02:00-05:00", at(4, 59), true},
+		{"02:00-05:00", at(5, 0), false},
+		{"02:00-05:00", at(1, 59), false},
+		{"22:00-04:00", at(23, 30), true},
+		{"22:00-04:00", at(3, 0), true},
+		{"22:00-04:00", at(12, 0), false},
+	}
+	for _, tt := range tests {
+		got, err := InWindow(tt.window, tt.t)
+		if err != nil || got != tt.want {
+			t.Errorf("InWindow(%q, %s) = %v, %v; want %v", tt.window, tt.t.Format("15:04"), got, err, tt.want)
+		}
+	}
+}
+
+func TestParseWindowInvalid(t *testing.T) {
+	for _, window := range []string{"2am-5am", "02:00", "25:00-03:00", "02:60-03:00", "03:00-03:00"} {
+		if _, _, err := ParseWindow(window); err == nil {
+			t.Errorf("ParseWindow(%q) = nil error, want an error", window)
+		}
+	}
+}
+
+func TestAutoUpdateDue(t *testing.T) {
+	now := time.Date(2024, 1, 1, 3, 0, 0, 0, time.Local)
+
+	tests := []struct {
+		name   string
+		policy *types.AutoUpdateConfig
+		want   bool
+	}{
+		{"off", nil, false},
+		{"never checked", &types.AutoUpdateConfig{}, true},
+		{"checked recently", &types.AutoUpdateConfig{LastCheck: now.Add(-time.Hour)}, false},
+		{"interval passed", &types.AutoUpdateConfig{LastCheck: now.Add(-2 * time.Hour), Interval: time.Hour}, true},
+		{"outside window", &types.AutoUpdateConfig{Window: "22:00-23:00"}, false},
+		{"inside window", &types.AutoUpdateConfig{Window: "02:00-05:00"}, true},
+	}
+	for _, tt := range tests {
+		instance := &types.Instance{Name: "dozzle", AutoUpdate: tt.policy}
+		if got := AutoUpdateDue(instance, now); got != tt.want {
+			t.Errorf("%s: AutoUpdateDue() = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,159 @@
+package service
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/AlecAivazis/survey/v2"
+	"github.com/dokulabs/doku-cli/internal/catalog"
+	"github.com/dokulabs/doku-cli/internal/dependencies"
+	"github.com/dokulabs/doku-cli/pkg/types"
+	"github.com/fatih/color"
+)
+
+// bindDependencies binds missing dependencies to installed instances of
+// their service instead of installing new ones: the instances given in
+// opts.DependencyInstances, or the ones picked when opts.ChooseDependencies
+// is set. It returns the dependencies still to install, without the bound
+// ones and those only they need.
+func (i *Installer) bindDependencies(
+	opts InstallOptions,
+	result *dependencies.ResolutionResult,
+	missing []dependencies.DependencyNode,
+	resolved *resolvedDependencies,
+) ([]dependencies.DependencyNode, error) {
+	instances, err := i.configMgr.ListInstances()
+	if err != nil {
+		return nil, err
+	}
+
+	for serviceName, instanceName := range opts.DependencyInstances {
+		if _, ok := result.AllNodes[serviceName]; !ok || serviceName == opts.ServiceName {
+			return nil, fmt.Errorf("%s is not a dependency of %s", serviceName, opts.ServiceName)
+		}
+		instance, err := i.configMgr.GetInstance(instanceName)
+		if err != nil {
+			return nil, fmt.Errorf("instance '%s' not found", instanceName)
+		}
+		if instance.ServiceType != serviceName {
+			return nil, fmt.Errorf("'%s' is a %s instance, not %s", instanceName, instance.ServiceType, serviceName)
+		}
+	}
+
+	// Dependents come after their dependencies in the install order, so go
+	// backwards to skip what only a bound dependency needs
+	for idx := len(missing) - 1; idx >= 0; idx-- {
+		dep := missing[idx]
+		if dep.ServiceName == opts.ServiceName || !neededDependencies(result.Graph, opts.ServiceName, resolved.instances)[dep.ServiceName] {
+			continue
+		}
+
+		if instanceName, ok := opts.DependencyInstances[dep.ServiceName]; ok {
+			resolved.instances[dep.ServiceName] = instanceName
+			continue
+		}
+
+		candidates := instancesOf(instances, dep.ServiceName)
+		if len(candidates) == 0 {
+			continue
+		}
+		if !opts.ChooseDependencies {
+			color.New(color.Faint).Printf("%s is already installed as %s; bind to it with --dep-instance %s=%s\n",
+				dep.ServiceName, candidates[0].Name, dep.ServiceName, candidates[0].Name)
+			continue
+		}
+
+		options := make([]string, 0, len(candidates)+1)
+		for _, candidate := range candidates {
+			options = append(options, fmt.Sprintf("Use %s (%s)", candidate.Name, candidate.Version))
+		}
+		installNew := fmt.Sprintf("Install a new %s (%s)", dep.ServiceName, dep.Version)
+		options = append(options, installNew)
+
+		var choice int
+		prompt := &survey.Select{
+			Message: fmt.Sprintf("%s needs %s:", opts.ServiceName, dep.ServiceName),
+			Options: options,
+		}
+		if err := survey.AskOne(prompt, &choice); err != nil {
+			return nil, fmt.Errorf("selection failed: %w", err)
+		}
+		if choice < len(candidates) {
+			resolved.instances[dep.ServiceName] = candidates[choice].Name
+		}
+	}
+
+	needed := neededDependencies(result.Graph, opts.ServiceName, resolved.instances)
+	remaining := make([]dependencies.DependencyNode, 0, len(missing))
+	for _, dep := range missing {
+		if dep.ServiceName == opts.ServiceName || needed[dep.ServiceName] {
+			remaining = append(remaining, dep)
+		}
+	}
+	return remaining, nil
+}
+
+// neededDependencies returns the services root needs, directly or through
+// other dependencies, apart from the bound ones and what only they need
+func neededDependencies(graph map[string][]string, root string, bound map[string]string) map[string]bool {
+	needed := make(map[string]bool)
+	var visit func(name string)
+	visit = func(name string) {
+		for _, dep := range graph[name] {
+			if _, ok := bound[dep]; ok || needed[dep] {
+				continue
+			}
+			needed[dep] = true
+			visit(dep)
+		}
+	}
+	visit(root)
+	return needed
+}
+
+// instancesOf returns the instances of a catalog service, newest version
+// first
+func instancesOf(instances []*types.Instance, serviceName string) []*types.Instance {
+	var matching []*types.Instance
+	for _, instance := range instances {
+		if instance.ServiceType == serviceName {
+			matching = append(matching, instance)
+		}
+	}
+	sort.Slice(matching, func(a, b int) bool {
+		if cmp := catalog.CompareVersions(matching[a].Version, matching[b].Version); cmp != 0 {
+			return cmp > 0
+		}
+		return matching[a].Name < matching[b].Name
+	})
+	return matching
+}
+
+// bindLinks returns links injecting the connection variables of the
+// instances bound to dependencies, prefixed with the dependency's name
+// (e.g. POSTGRES_URL), and the variables themselves
+func (i *Installer) bindLinks(bound map[string]string) ([]types.Link, map[string]string, error) {
+	m := NewManager(i.dockerClient, i.configMgr)
+
+	names := make([]string, 0, len(bound))
+	for serviceName := range bound {
+		names = append(names, serviceName)
+	}
+	sort.Strings(names)
+
+	var links []types.Link
+	env := make(map[string]string)
+	for _, serviceName := range names {
+		link := types.Link{Service: bound[serviceName], Prefix: LinkPrefix(serviceName)}
+		linkEnv, err := m.linkEnv(link)
+		if err != nil {
+			return nil, nil, err
+		}
+		link.Env = linkEnv
+		links = append(links, link)
+		for key, value := range linkEnv {
+			env[key] = value
+		}
+	}
+	return links, env, nil
+}
@@ -0,0 +1,61 @@
+package service
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/dokulabs/doku-cli/pkg/types"
+)
+
+func TestNeededDependencies(t *testing.T) {
+	graph := map[string][]string{
+		"signoz":     {"clickhouse", "postgres"},
+		"clickhouse": {"zookeeper"},
+	}
+
+	tests := []struct {
+		name     string
+		bound    map[string]string
+		expected map[string]bool
+	}{
+		{
+			name:     "nothing bound",
+			expected: map[string]bool{"clickhouse": true, "postgres": true, "zookeeper": true},
+		},
+		{
+			name:     "bound dependency and what only it needs are left out",
+			bound:    map[string]string{"clickhouse": "clickhouse-24"},
+			expected: map[string]bool{"postgres": true},
+		},
+		{
+			name:     "leaf bound",
+			bound:    map[string]string{"postgres": "postgres-16"},
+			expected: map[string]bool{"clickhouse": true, "zookeeper": true},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := neededDependencies(graph, "signoz", tt.bound); !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("neededDependencies = %v, expected %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestInstancesOf(t *testing.T) {
+	instances := []*types.Instance{
+		{Name: "postgres-14", ServiceType: "postgres", Version: "14"},
+		{Name: "redis", ServiceType: "redis", Version: "7"},
+		{Name: "pg-b", ServiceType: "postgres", Version: "16"},
+		{Name: "pg-a", ServiceType: "postgres", Version: "16"},
+	}
+
+	var names []string
+	for _, instance := range instancesOf(instances, "postgres") {
+		names = append(names, instance.Name)
+	}
+	if expected := []string{"pg-a", "pg-b", "postgres-14"}; !reflect.DeepEqual(names, expected) {
+		t.Errorf("instancesOf = %v, expected %v", names, expected)
+	}
+}
@@ -0,0 +1,88 @@
+package service
+
+import (
+	"strings"
+	"sync"
+)
+
+// DefaultBulkWorkers is how many instances a bulk operation handles at once
+const DefaultBulkWorkers = 4
+
+// BulkOptions controls a bulk operation over several instances
+type BulkOptions struct {
+	Workers      int                 // Concurrent operations (0 = DefaultBulkWorkers)
+	Dependencies map[string][]string // Instances that must be handled before each instance
+	Reverse      bool                // Handle dependents before their dependencies (e.g. for stop)
+}
+
+// BulkResult is the outcome of a bulk operation for one instance
+type BulkResult struct {
+	Name string
+	Err  error
+}
+
+// RunBulk runs action for each name with a pool of workers. Instances are
+// handled in dependency waves: an instance only starts once the instances
+// it depends on are done (or, with Reverse, once its dependents are done).
+// Results are returned in the order of names.
+func RunBulk(names []string, opts BulkOptions, action func(name string) error) []BulkResult {
+	workers := opts.Workers
+	if workers <= 0 {
+		workers = DefaultBulkWorkers
+	}
+
+	results := make([]BulkResult, len(names))
+	for idx, name := range names {
+		results[idx].Name = name
+	}
+
+	levels, err := dependencyLevels(names, func(idx int) []string {
+		return dependencyNames(opts.Dependencies[names[idx]])
+	})
+	if err != nil {
+		// Fall back to a single wave; the order between instances is then undefined
+		all := make([]int, len(names))
+		for idx := range names {
+			all[idx] = idx
+		}
+		levels = [][]int{all}
+	}
+
+	if opts.Reverse {
+		for i, j := 0, len(levels)-1; i < j; i, j = i+1, j-1 {
+			levels[i], levels[j] = levels[j], levels[i]
+		}
+	}
+
+	for _, level := range levels {
+		jobs := make(chan int)
+		var wg sync.WaitGroup
+
+		for w := 0; w < workers && w < len(level); w++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for idx := range jobs {
+					results[idx].Err = action(names[idx])
+				}
+			}()
+		}
+
+		for _, idx := range level {
+			jobs <- idx
+		}
+		close(jobs)
+		wg.Wait()
+	}
+
+	return results
+}
+
+// dependencyNames strips version constraints such as "postgres:16"
+func dependencyNames(deps []string) []string {
+	names := make([]string, len(deps))
+	for idx, dep := range deps {
+		names[idx] = strings.SplitN(dep, ":", 2)[0]
+	}
+	return names
+}
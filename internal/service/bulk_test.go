@@ -0,0 +1,61 @@
+package service
+
+import (
+	"errors"
+	"sync"
+	"testing"
+)
+
+func TestRunBulk(t *testing.T) {
+	var mu sync.Mutex
+	var order []string
+
+	names := []string{"app", "postgres", "redis", "worker"}
+	deps := map[string][]string{
+		"app":    {"postgres:16", "redis"},
+		"worker": {"app"},
+	}
+
+	results := RunBulk(names, BulkOptions{Workers: 2, Dependencies: deps}, func(name string) error {
+		mu.Lock()
+		order = append(order, name)
+		mu.Unlock()
+		if name == "redis" {
+			return errors.New("boom")
+		}
+		return nil
+	})
+
+	position := make(map[string]int)
+	for idx, name := range order {
+		position[name] = idx
+	}
+	if position["app"] < position["postgres"] || position["app"] < position["redis"] || position["worker"] < position["app"] {
+		t.Errorf("dependencies not respected: %v", order)
+	}
+
+	for idx, result := range results {
+		if result.Name != names[idx] {
+			t.Errorf("results[%d].Name = %q, want %q", idx, result.Name, names[idx])
+		}
+		if (result.Err != nil) != (result.Name == "redis") {
+			t.Errorf("results[%d].Err = %v", idx, result.Err)
+		}
+	}
+}
+
+func TestRunBulkReverse(t *testing.T) {
+	var order []string
+
+	names := []string{"postgres", "app"}
+	deps := map[string][]string{"app": {"postgres"}}
+
+	RunBulk(names, BulkOptions{Workers: 1, Dependencies: deps, Reverse: true}, func(name string) error {
+		order = append(order, name)
+		return nil
+	})
+
+	if len(order) != 2 || order[0] != "app" || order[1] != "postgres" {
+		t.Errorf("order = %v, want [app postgres]", order)
+	}
+}
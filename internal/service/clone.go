@@ -0,0 +1,252 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/mount"
+	"github.com/dokulabs/doku-cli/internal/envfile"
+	"github.com/dokulabs/doku-cli/internal/monitoring"
+	"github.com/dokulabs/doku-cli/pkg/types"
+	"github.com/fatih/color"
+)
+
+// CloneOptions contains options for cloning an instance
+type CloneOptions struct {
+	Source       string            // Instance to clone
+	Target       string            // Name of the new instance
+	PortMappings map[string]string // Host ports for the clone (containerPort:hostPort)
+
+	// Live copies the volumes without stopping the source. Faster, but a
+	// database that is writing may leave an inconsistent copy.
+	Live bool
+}
+
+// volumePair is a volume of the source and the clone's volume at the same
+// mount point
+type volumePair struct {
+	Source, Target string
+}
+
+// Clone installs a copy of an instance under a new name: same service,
+// version, resources and labels, a copy of its env files and of the data in
+// its volumes. Host ports aren't copied, as they would conflict; bind
+// mounts aren't either, since both instances would share the host files.
+func (i *Installer) Clone(opts CloneOptions) (*types.Instance, error) {
+	source, err := i.configMgr.GetInstance(opts.Source)
+	if err != nil {
+		return nil, fmt.Errorf("service '%s' not found", opts.Source)
+	}
+	if i.configMgr.HasInstance(opts.Target) {
+		return nil, fmt.Errorf("instance '%s' already exists", opts.Target)
+	}
+
+	sourceContainers := instanceContainerNames(source)
+	if len(sourceContainers) == 0 {
+		return nil, fmt.Errorf("'%s' has no containers to clone", source.Name)
+	}
+	first, err := i.dockerClient.ContainerInspect(sourceContainers[0].full)
+	if err != nil {
+		return nil, fmt.Errorf("failed to inspect %s: %w", source.Name, err)
+	}
+	internal := first.Config != nil && first.Config.Labels["traefik.enable"] == "false"
+
+	if len(source.Volumes) > 0 {
+		color.Yellow("⚠️  The host directories mounted into %s are not mounted into the clone", source.Name)
+	}
+
+	// The env files go first: the installer reuses them, keeping the
+	// source's credentials, which its data was created with
+	if err := i.cloneEnvFiles(source, opts.Target); err != nil {
+		return nil, err
+	}
+
+	clone, err := i.Install(InstallOptions{
+		ServiceName:       source.ServiceType,
+		Version:           source.Version,
+		InstanceName:      opts.Target,
+		MemoryLimit:       source.Resources.MemoryLimit,
+		CPULimit:          source.Resources.CPULimit,
+		PortMappings:      opts.PortMappings,
+		Internal:          internal,
+		HostNetwork:       source.UsesHostNetwork(),
+		Labels:            source.Labels,
+		Logs:              source.Logs,
+		Middlewares:       source.Middlewares,
+		SkipDependencies:  true,
+		ReuseExistingData: true,
+	})
+	if err != nil {
+		envfile.NewManager(i.configMgr.GetDokuDir()).CleanupEnvFiles(opts.Target, true, containerShortNames(source))
+		return nil, err
+	}
+
+	pairs, err := i.pairVolumes(sourceContainers, instanceContainerNames(clone))
+	if err != nil {
+		return clone, err
+	}
+	if len(pairs) == 0 {
+		return clone, nil
+	}
+
+	return clone, i.copyVolumes(source, clone, pairs, opts.Live)
+}
+
+// copyVolumes stops the clone, and the source unless live, copies the
+// volumes and starts both again
+func (i *Installer) copyVolumes(source, clone *types.Instance, pairs []volumePair, live bool) error {
+	mgr := NewManager(i.dockerClient, i.configMgr)
+
+	fmt.Printf("Stopping %s to copy its data...\n", clone.Name)
+	if err := mgr.Stop(clone.Name); err != nil && !errors.Is(err, types.ErrAlreadyStopped) {
+		return fmt.Errorf("failed to stop %s: %w", clone.Name, err)
+	}
+
+	restartSource := false
+	if !live && source.Status == types.StatusRunning {
+		fmt.Printf("Stopping %s while its volumes are copied...\n", source.Name)
+		if err := mgr.Stop(source.Name); err != nil && !errors.Is(err, types.ErrAlreadyStopped) {
+			return fmt.Errorf("failed to stop %s: %w", source.Name, err)
+		}
+		restartSource = true
+	}
+
+	var copyErr error
+	for _, pair := range pairs {
+		fmt.Printf("Copying volume %s → %s...\n", pair.Source, pair.Target)
+		if err := i.dockerClient.CopyVolume(pair.Source, pair.Target); err != nil {
+			copyErr = err
+			break
+		}
+	}
+
+	if restartSource {
+		fmt.Printf("Starting %s again...\n", source.Name)
+		if err := mgr.Start(source.Name); err != nil && !errors.Is(err, types.ErrAlreadyRunning) {
+			color.Yellow("⚠️  Failed to start %s again: %v", source.Name, err)
+			color.Yellow("   Start it with: doku start %s", source.Name)
+		}
+	}
+
+	if copyErr != nil {
+		return fmt.Errorf("%w (the clone %s was left stopped)", copyErr, clone.Name)
+	}
+
+	fmt.Printf("Starting %s...\n", clone.Name)
+	if err := mgr.Start(clone.Name); err != nil && !errors.Is(err, types.ErrAlreadyRunning) {
+		return fmt.Errorf("failed to start %s: %w", clone.Name, err)
+	}
+	return nil
+}
+
+// cloneEnvFiles copies the env files of source to those of target,
+// pointing monitoring instrumentation at the new instance
+func (i *Installer) cloneEnvFiles(source *types.Instance, target string) error {
+	envMgr := envfile.NewManager(i.configMgr.GetDokuDir())
+
+	var monitoringEnv map[string]string
+	if cfg, err := i.configMgr.Get(); err == nil {
+		monitoringEnv = monitoring.GetInstrumentationEnv(target, &cfg.Monitoring)
+	}
+
+	containers := append([]string{""}, containerShortNames(source)...)
+	for _, name := range containers {
+		sourcePath := envMgr.GetServiceEnvPath(source.Name, name)
+		env, err := envMgr.Load(sourcePath)
+		if err != nil {
+			if os.IsNotExist(err) {
+				// Older single-container instances keep it in the config
+				if name != "" || len(source.Environment) == 0 {
+					continue
+				}
+				env = source.Environment
+			} else {
+				return fmt.Errorf("failed to read %s: %w", sourcePath, err)
+			}
+		}
+
+		env = envfile.MergeEnv(env, monitoringEnv)
+		if err := envMgr.Save(envMgr.GetServiceEnvPath(target, name), env); err != nil {
+			return fmt.Errorf("failed to write env file for %s: %w", target, err)
+		}
+	}
+	return nil
+}
+
+// pairVolumes matches the volumes of the source's containers with those the
+// clone's containers mount at the same paths
+func (i *Installer) pairVolumes(source, clone []containerName) ([]volumePair, error) {
+	cloneMounts := make(map[string][]container.MountPoint, len(clone))
+	for _, c := range clone {
+		info, err := i.dockerClient.ContainerInspect(c.full)
+		if err != nil {
+			return nil, fmt.Errorf("failed to inspect %s: %w", c.full, err)
+		}
+		cloneMounts[c.short] = info.Mounts
+	}
+
+	var pairs []volumePair
+	for _, c := range source {
+		info, err := i.dockerClient.ContainerInspect(c.full)
+		if err != nil {
+			return nil, fmt.Errorf("failed to inspect %s: %w", c.full, err)
+		}
+		pairs = append(pairs, matchVolumes(info.Mounts, cloneMounts[c.short])...)
+	}
+	return pairs, nil
+}
+
+// matchVolumes pairs the named volumes of two containers by mount point
+func matchVolumes(source, clone []container.MountPoint) []volumePair {
+	byDestination := make(map[string]string, len(clone))
+	for _, m := range clone {
+		if m.Type == mount.TypeVolume {
+			byDestination[m.Destination] = m.Name
+		}
+	}
+
+	var pairs []volumePair
+	for _, m := range source {
+		if m.Type != mount.TypeVolume {
+			continue
+		}
+		if target, ok := byDestination[m.Destination]; ok && target != m.Name {
+			pairs = append(pairs, volumePair{Source: m.Name, Target: target})
+		}
+	}
+	return pairs
+}
+
+// containerName is a container of an instance: its name in the service
+// spec (empty for single-container services) and in Docker
+type containerName struct {
+	short, full string
+}
+
+// instanceContainerNames returns the containers of an instance
+func instanceContainerNames(instance *types.Instance) []containerName {
+	if !instance.IsMultiContainer {
+		if instance.ContainerName == "" {
+			return nil
+		}
+		return []containerName{{full: instance.ContainerName}}
+	}
+
+	names := make([]containerName, 0, len(instance.Containers))
+	for _, c := range instance.Containers {
+		names = append(names, containerName{short: c.Name, full: c.FullName})
+	}
+	return names
+}
+
+// containerShortNames returns the spec names of a multi-container
+// instance's containers
+func containerShortNames(instance *types.Instance) []string {
+	names := make([]string, 0, len(instance.Containers))
+	for _, c := range instance.Containers {
+		names = append(names, c.Name)
+	}
+	return names
+}
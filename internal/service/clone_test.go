@@ -0,0 +1,50 @@
+package service
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/mount"
+	"github.com/dokulabs/doku-cli/pkg/types"
+)
+
+func TestMatchVolumes(t *testing.T) {
+	source := []container.MountPoint{
+		{Type: mount.TypeVolume, Name: "doku-pg-postgresql-data-0", Destination: "/var/lib/postgresql/data"},
+		{Type: mount.TypeBind, Source: "/home/me/init", Destination: "/docker-entrypoint-initdb.d"},
+		{Type: mount.TypeVolume, Name: "doku-pg-backups-1", Destination: "/backups"},
+	}
+	clone := []container.MountPoint{
+		{Type: mount.TypeVolume, Name: "doku-pg-test-postgresql-data-0", Destination: "/var/lib/postgresql/data"},
+		{Type: mount.TypeBind, Source: "/home/me/init", Destination: "/docker-entrypoint-initdb.d"},
+	}
+
+	got := matchVolumes(source, clone)
+	want := []volumePair{{Source: "doku-pg-postgresql-data-0", Target: "doku-pg-test-postgresql-data-0"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("matchVolumes() = %v, want %v", got, want)
+	}
+}
+
+func TestInstanceContainerNames(t *testing.T) {
+	single := &types.Instance{ContainerName: "doku-redis"}
+	if got := instanceContainerNames(single); !reflect.DeepEqual(got, []containerName{{full: "doku-redis"}}) {
+		t.Errorf("instanceContainerNames(single) = %v", got)
+	}
+
+	multi := &types.Instance{
+		IsMultiContainer: true,
+		Containers: []types.ContainerInfo{
+			{Name: "query", FullName: "doku-signoz-query"},
+			{Name: "frontend", FullName: "doku-signoz-frontend"},
+		},
+	}
+	want := []containerName{
+		{short: "query", full: "doku-signoz-query"},
+		{short: "frontend", full: "doku-signoz-frontend"},
+	}
+	if got := instanceContainerNames(multi); !reflect.DeepEqual(got, want) {
+		t.Errorf("instanceContainerNames(multi) = %v, want %v", got, want)
+	}
+}
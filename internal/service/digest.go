@@ -0,0 +1,109 @@
+package service
+
+import (
+	"fmt"
+
+	"github.com/docker/docker/api/types/container"
+	"github.com/dokulabs/doku-cli/internal/docker"
+	"github.com/dokulabs/doku-cli/pkg/types"
+)
+
+// imageDigest returns the reference pinning a local image by digest, or ""
+// when it can't be resolved. The instance is then simply not pinned.
+func imageDigest(dockerClient *docker.Client, image string) string {
+	digest, err := dockerClient.ImageDigest(image)
+	if err != nil {
+		return ""
+	}
+	return digest
+}
+
+// installedDigest returns the digest to record of an image installed:
+// pinned, the one it was pinned to, else the one image resolves to
+func installedDigest(dockerClient *docker.Client, pinned, image string) string {
+	if pinned != "" {
+		return pinned
+	}
+	return imageDigest(dockerClient, image)
+}
+
+// pinnedImage returns the image to recreate a container with: current, the
+// image it was created from, unless the tag now points to a different image
+// than digest, the one recorded at install. The digest is pulled again if
+// it was removed. An empty digest keeps current.
+func pinnedImage(dockerClient *docker.Client, digest, current string) (string, error) {
+	if digest == "" || digest == current {
+		return current, nil
+	}
+
+	pinned := dockerClient.LocalImage(digest)
+	pinnedID, err := dockerClient.ImageID(pinned)
+	if err != nil {
+		fmt.Printf("Pulling pinned image %s...\n", digest)
+		if err := dockerClient.ImagePullQuiet(digest); err != nil {
+			return "", fmt.Errorf("failed to pull pinned image: %w", err)
+		}
+		return dockerClient.LocalImage(digest), nil
+	}
+
+	if currentID, err := dockerClient.ImageID(current); err == nil && currentID == pinnedID {
+		return current, nil
+	}
+	return pinned, nil
+}
+
+// currentImage returns the image an instance runs, for a rollback snapshot:
+// its pinned digest if it has one, so rolling back restores that exact image
+func currentImage(instance *types.Instance, config *container.Config) string {
+	if instance.ImageDigest != "" {
+		return instance.ImageDigest
+	}
+	return config.Image
+}
+
+// imageTag returns the image reference a container's image was resolved
+// from. A container recreated from a pinned digest has the tag only in its
+// doku.version label.
+func imageTag(config *container.Config) string {
+	if docker.IsDigestRef(config.Image) && config.Labels["doku.version"] != "" {
+		return config.Labels["doku.version"]
+	}
+	return config.Image
+}
+
+// RecreateLatest pulls the tags an instance's images were installed from
+// and recreates its containers with the images they now point to, pinning
+// the new digests. A single-container instance can be rolled back to the
+// image it ran before.
+func (m *Manager) RecreateLatest(instanceName string) error {
+	instance, err := m.configMgr.GetInstance(instanceName)
+	if err != nil {
+		return fmt.Errorf("instance not found: %w", err)
+	}
+
+	if !instance.IsMultiContainer {
+		containerInfo, err := m.dockerClient.ContainerInspect(instance.ContainerName)
+		if err != nil {
+			return fmt.Errorf("failed to inspect container: %w", err)
+		}
+		tag := imageTag(containerInfo.Config)
+		fmt.Printf("Pulling image %s...\n", tag)
+		if err := m.dockerClient.ImagePull(tag); err != nil {
+			return fmt.Errorf("failed to pull image: %w", err)
+		}
+		return m.RecreateWithImage(instanceName, tag)
+	}
+
+	for i := range instance.Containers {
+		c := &instance.Containers[i]
+		fmt.Printf("Pulling image %s...\n", c.Image)
+		if err := m.dockerClient.ImagePull(c.Image); err != nil {
+			return fmt.Errorf("failed to pull image %s: %w", c.Image, err)
+		}
+		c.ImageDigest = imageDigest(m.dockerClient, c.Image)
+	}
+	if err := m.configMgr.UpdateInstance(instanceName, instance); err != nil {
+		return err
+	}
+	return m.recreateMultiContainerService(instance, nil)
+}
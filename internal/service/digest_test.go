@@ -0,0 +1,66 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/docker/docker/api/types/container"
+	"github.com/dokulabs/doku-cli/pkg/types"
+)
+
+func TestImageTag(t *testing.T) {
+	labels := map[string]string{"doku.version": "postgres:16"}
+
+	tests := []struct {
+		name     string
+		config   *container.Config
+		expected string
+	}{
+		{"tag", &container.Config{Image: "postgres:16", Labels: labels}, "postgres:16"},
+		{"pinned", &container.Config{Image: "postgres@sha256:abc", Labels: labels}, "postgres:16"},
+		{"pinned without label", &container.Config{Image: "postgres@sha256:abc"}, "postgres@sha256:abc"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := imageTag(tt.config); got != tt.expected {
+				t.Errorf("imageTag() = %q, expected %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestCurrentImage(t *testing.T) {
+	config := &container.Config{Image: "postgres:16"}
+
+	if got := currentImage(&types.Instance{}, config); got != "postgres:16" {
+		t.Errorf("currentImage() = %q, expected the container's image", got)
+	}
+
+	pinned := &types.Instance{ImageDigest: "postgres@sha256:abc"}
+	if got := currentImage(pinned, config); got != "postgres@sha256:abc" {
+		t.Errorf("currentImage() = %q, expected the pinned digest", got)
+	}
+}
+
+func TestContainerDigests(t *testing.T) {
+	instance := &types.Instance{
+		IsMultiContainer: true,
+		Containers: []types.ContainerInfo{
+			{Name: "frontend", ImageDigest: "signoz/frontend@sha256:aaa"},
+			{Name: "query"},
+		},
+	}
+
+	digests := containerDigests(instance)
+	if len(digests) != 1 || digests["frontend"] != "signoz/frontend@sha256:aaa" {
+		t.Errorf("containerDigests() = %v, expected the frontend's digest alone", digests)
+	}
+
+	// A pinned digest is recorded as it is, without asking Docker
+	if got := installedDigest(nil, "redis@sha256:bbb", "redis:7"); got != "redis@sha256:bbb" {
+		t.Errorf("installedDigest() = %q, expected the pinned digest", got)
+	}
+	if image, err := pinnedImage(nil, "", "redis:7"); err != nil || image != "redis:7" {
+		t.Errorf("pinnedImage() without a digest = %q, %v, expected the tag", image, err)
+	}
+}
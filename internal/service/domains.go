@@ -0,0 +1,110 @@
+package service
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/dokulabs/doku-cli/internal/config"
+	"github.com/dokulabs/doku-cli/internal/domain"
+	"github.com/dokulabs/doku-cli/pkg/types"
+)
+
+// UpdateHostRules recreates an instance's containers with a Host rule for
+// each of its hostnames: its subdomain of every base domain and its own
+// domains. Instances Traefik doesn't route to are left alone; stopped ones
+// are stopped again afterwards.
+func (m *Manager) UpdateHostRules(instanceName string) error {
+	cfg, err := m.configMgr.Get()
+	if err != nil {
+		return fmt.Errorf("failed to get config: %w", err)
+	}
+	instance, ok := cfg.Instances[instanceName]
+	if !ok {
+		return fmt.Errorf("instance not found: %s", instanceName)
+	}
+	if !IsRouted(instance) {
+		return nil
+	}
+
+	rule := domain.HostRule(domain.InstanceHostnames(instance, cfg.Preferences))
+	relabel := func(labels map[string]string) {
+		for key := range labels {
+			if strings.HasPrefix(key, "traefik.http.routers.") && strings.HasSuffix(key, ".rule") {
+				labels[key] = rule
+			}
+		}
+	}
+
+	wasRunning := instance.Status == types.StatusRunning
+	if err := m.RecreateWithLabels(instanceName, relabel); err != nil {
+		return err
+	}
+	if !wasRunning {
+		return m.Stop(instanceName)
+	}
+	return nil
+}
+
+// IsRouted reports whether Traefik routes requests for the instance's
+// hostnames to it
+func IsRouted(instance *types.Instance) bool {
+	return instance.Traefik.Enabled && instance.URL != "" && !instance.UsesHostNetwork()
+}
+
+// HostsEntry is a hostname the hosts file has an entry for when Doku
+// manages it, and what the hostname is for
+type HostsEntry struct {
+	Hostname string
+	For      string // An instance or project, or "doku", "traefik" or "dozzle"
+}
+
+// HostsEntries returns the entries the hosts file has when Doku manages it:
+// the main domain first, as the hosts manager expects, then the hostnames
+// of Traefik and Dozzle, and those of every routed instance and project
+func HostsEntries(cfg *types.Config) []HostsEntry {
+	bases := domain.BaseDomains(cfg.Preferences)
+	main := bases[0]
+
+	var entries []HostsEntry
+	seen := make(map[string]bool)
+	add := func(host, owner string) {
+		if host != "" && !seen[host] {
+			seen[host] = true
+			entries = append(entries, HostsEntry{Hostname: host, For: owner})
+		}
+	}
+
+	add(main, "doku")
+	add("traefik."+main, "traefik")
+	if cfg.Monitoring.Tool == "dozzle" {
+		add("dozzle."+main, "dozzle")
+	}
+
+	names := make([]string, 0, len(cfg.Instances))
+	for name := range cfg.Instances {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		instance := cfg.Instances[name]
+		if !IsRouted(instance) {
+			continue
+		}
+		for _, host := range domain.Hostnames(domain.InstanceSubdomain(instance), bases, instance.Domains) {
+			add(host, name)
+		}
+	}
+
+	names = names[:0]
+	for name := range cfg.Projects {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if url := cfg.Projects[name].URL; url != "" {
+			add(config.URLHost(url), name)
+		}
+	}
+	return entries
+}
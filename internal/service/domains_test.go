@@ -0,0 +1,51 @@
+package service
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/dokulabs/doku-cli/pkg/types"
+)
+
+func TestHostsEntries(t *testing.T) {
+	cfg := &types.Config{
+		Preferences: types.PreferencesConfig{Domain: "doku.local", Domains: []string{"myproject.test"}},
+		Monitoring:  types.MonitoringConfig{Tool: "dozzle"},
+		Instances: map[string]*types.Instance{
+			"api": {
+				Name:    "api",
+				URL:     "https://api.doku.local",
+				Domains: []string{"api.example.test"},
+				Traefik: types.TraefikInstanceConfig{Enabled: true},
+			},
+			"redis": {Name: "redis"},
+			"pg": {
+				Name:    "pg",
+				URL:     "https://postgres.doku.local",
+				Traefik: types.TraefikInstanceConfig{Enabled: true, Subdomain: "postgres"},
+			},
+		},
+		Projects: map[string]*types.Project{
+			"shop": {Name: "shop", URL: "https://shop.doku.local"},
+		},
+	}
+
+	var got []string
+	for _, entry := range HostsEntries(cfg) {
+		got = append(got, entry.Hostname+" "+entry.For)
+	}
+	expected := []string{
+		"doku.local doku",
+		"traefik.doku.local traefik",
+		"dozzle.doku.local dozzle",
+		"api.doku.local api",
+		"api.myproject.test api",
+		"api.example.test api",
+		"postgres.doku.local pg",
+		"postgres.myproject.test pg",
+		"shop.doku.local shop",
+	}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("HostsEntries() = %q, expected %q", got, expected)
+	}
+}
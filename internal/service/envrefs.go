@@ -0,0 +1,117 @@
+package service
+
+import (
+	"fmt"
+
+	"github.com/dokulabs/doku-cli/internal/config"
+	"github.com/dokulabs/doku-cli/internal/envfile"
+	"github.com/dokulabs/doku-cli/pkg/types"
+)
+
+// credentialKeys pairs the user and password variables of common images.
+// An empty UserKey means the image has a fixed user, DefaultUser.
+var credentialKeys = []struct {
+	UserKey     string
+	DefaultUser string
+	PasswordKey string
+}{
+	{"POSTGRES_USER", "postgres", "POSTGRES_PASSWORD"},
+	{"MYSQL_USER", "", "MYSQL_PASSWORD"},
+	{"", "root", "MYSQL_ROOT_PASSWORD"},
+	{"MARIADB_USER", "", "MARIADB_PASSWORD"},
+	{"", "root", "MARIADB_ROOT_PASSWORD"},
+	{"MONGO_INITDB_ROOT_USERNAME", "", "MONGO_INITDB_ROOT_PASSWORD"},
+	{"RABBITMQ_DEFAULT_USER", "guest", "RABBITMQ_DEFAULT_PASS"},
+	{"", "", "REDIS_PASSWORD"},
+}
+
+// databaseKeys name the default database of common images
+var databaseKeys = []string{"POSTGRES_DB", "MYSQL_DATABASE", "MARIADB_DATABASE", "MONGO_INITDB_DATABASE"}
+
+// EnvResolver resolves ${instance.FIELD} references in env files against
+// installed instances. FIELD is HOST, PORT or URL, one of USER, PASSWORD
+// and DATABASE, which map to the image's own variables (POSTGRES_USER,
+// MYSQL_ROOT_PASSWORD, ...), or any variable of the instance's env file.
+func EnvResolver(configMgr *config.Manager) envfile.Resolver {
+	envMgr := envfile.NewManager(configMgr.GetDokuDir())
+
+	return func(instanceName, field string) (string, error) {
+		instance, err := configMgr.GetInstance(instanceName)
+		if err != nil {
+			return "", fmt.Errorf("instance '%s' is not installed", instanceName)
+		}
+
+		fileEnv, _ := envMgr.Load(envMgr.GetServiceEnvPath(instanceName, ""))
+		env := envfile.MergeEnv(instance.Environment, fileEnv)
+
+		if value, ok := instanceField(instance, env, field); ok {
+			return value, nil
+		}
+		return "", fmt.Errorf("'%s' has no %s", instanceName, field)
+	}
+}
+
+// instanceField returns a field of an instance with env, its environment
+func instanceField(instance *types.Instance, env map[string]string, field string) (string, bool) {
+	switch field {
+	case "HOST":
+		return instance.Name, true
+	case "PORT":
+		if instance.Network.InternalPort == 0 {
+			return "", false
+		}
+		return fmt.Sprintf("%d", instance.Network.InternalPort), true
+	case "URL":
+		return instance.URL, instance.URL != ""
+	}
+
+	if value, ok := env[field]; ok {
+		return value, true
+	}
+
+	switch field {
+	case "USER", "USERNAME":
+		user, _, ok := credentials(env)
+		return user, ok && user != ""
+	case "PASSWORD":
+		_, password, ok := credentials(env)
+		return password, ok
+	case "DATABASE", "DB":
+		for _, key := range databaseKeys {
+			if value, ok := env[key]; ok {
+				return value, true
+			}
+		}
+	}
+
+	return "", false
+}
+
+// credentials returns the user and password of the first credential pair
+// set in env
+func credentials(env map[string]string) (user, password string, ok bool) {
+	for _, keys := range credentialKeys {
+		user := keys.DefaultUser
+		if keys.UserKey != "" {
+			if value, ok := env[keys.UserKey]; ok && value != "" {
+				user = value
+			} else if user == "" {
+				continue
+			}
+		}
+		if password, ok := env[keys.PasswordKey]; ok {
+			return user, password, true
+		}
+	}
+	return "", "", false
+}
+
+// resolveEnvRefs returns env with its references to other instances
+// resolved, ready to pass to a container. Env files keep the references,
+// so they are resolved again whenever a container is created.
+func resolveEnvRefs(configMgr *config.Manager, env map[string]string) (map[string]string, error) {
+	if !envfile.HasReferences(env) {
+		return env, nil
+	}
+	return envfile.Interpolate(env, EnvResolver(configMgr))
+}
@@ -0,0 +1,59 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/dokulabs/doku-cli/pkg/types"
+)
+
+func TestInstanceField(t *testing.T) {
+	postgres := &types.Instance{
+		Name:    "postgres",
+		URL:     "https://postgres.doku.local",
+		Network: types.NetworkConfig{InternalPort: 5432},
+	}
+	postgresEnv := map[string]string{"POSTGRES_PASSWORD": "s3cret", "POSTGRES_DB": "app"}
+
+	mysql := &types.Instance{Name: "mysql", Network: types.NetworkConfig{InternalPort: 3306}}
+	mysqlEnv := map[string]string{"MYSQL_ROOT_PASSWORD": "root-pw", "MYSQL_DATABASE": "shop"}
+
+	tests := []struct {
+		instance *types.Instance
+		env      map[string]string
+		field    string
+		want     string
+		ok       bool
+	}{
+		{postgres, postgresEnv, "HOST", "postgres", true},
+		{postgres, postgresEnv, "PORT", "5432", true},
+		{postgres, postgresEnv, "URL", "https://postgres.doku.local", true},
+		{postgres, postgresEnv, "USER", "postgres", true},
+		{postgres, postgresEnv, "PASSWORD", "s3cret", true},
+		{postgres, postgresEnv, "DATABASE", "app", true},
+		{postgres, postgresEnv, "POSTGRES_DB", "app", true},
+		{postgres, postgresEnv, "MISSING", "", false},
+		{mysql, mysqlEnv, "USER", "root", true},
+		{mysql, mysqlEnv, "PASSWORD", "root-pw", true},
+		{mysql, mysqlEnv, "DB", "shop", true},
+		{mysql, mysqlEnv, "URL", "", false},
+	}
+
+	for _, tt := range tests {
+		got, ok := instanceField(tt.instance, tt.env, tt.field)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("%s.%s = %q, %v; want %q, %v", tt.instance.Name, tt.field, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestCredentialsPrefersAppUser(t *testing.T) {
+	env := map[string]string{
+		"MYSQL_ROOT_PASSWORD": "root-pw",
+		"MYSQL_USER":          "app",
+		"MYSQL_PASSWORD":      "app-pw",
+	}
+	user, password, ok := credentials(env)
+	if !ok || user != "app" || password != "app-pw" {
+		t.Errorf("credentials() = %q, %q, %v; want app, app-pw, true", user, password, ok)
+	}
+}
@@ -0,0 +1,153 @@
+package service
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	dockerTypes "github.com/docker/docker/api/types/container"
+	"github.com/dokulabs/doku-cli/pkg/types"
+	"github.com/fatih/color"
+)
+
+// DefaultHealthTimeout is how long the installer waits for a container to
+// become healthy before giving up
+const DefaultHealthTimeout = 2 * time.Minute
+
+// healthPollInterval is how often container health is checked while waiting
+const healthPollInterval = 500 * time.Millisecond
+
+// noHealthcheckPause is how long to wait for containers without a healthcheck
+// before starting their dependents
+const noHealthcheckPause = 2 * time.Second
+
+var spinnerFrames = []string{"⠋", "⠙", "⠹", "⠸", "⠼", "⠴", "⠦", "⠧", "⠇", "⠏"}
+
+// healthConfig converts a catalog healthcheck into a Docker health config.
+// A test without a CMD, CMD-SHELL or NONE prefix is run with the shell.
+func healthConfig(hc *types.Healthcheck) (*dockerTypes.HealthConfig, error) {
+	if hc == nil || len(hc.Test) == 0 {
+		return nil, nil
+	}
+
+	config := &dockerTypes.HealthConfig{
+		Retries: hc.Retries,
+	}
+
+	switch hc.Test[0] {
+	case "CMD", "CMD-SHELL", "NONE":
+		config.Test = hc.Test
+	default:
+		config.Test = []string{"CMD-SHELL", strings.Join(hc.Test, " ")}
+	}
+
+	durations := []struct {
+		name  string
+		value string
+		dest  *time.Duration
+	}{
+		{"interval", hc.Interval, &config.Interval},
+		{"timeout", hc.Timeout, &config.Timeout},
+		{"start_period", hc.Start, &config.StartPeriod},
+	}
+	for _, d := range durations {
+		if d.value == "" {
+			continue
+		}
+		parsed, err := time.ParseDuration(d.value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid healthcheck %s '%s': %w", d.name, d.value, err)
+		}
+		*d.dest = parsed
+	}
+
+	return config, nil
+}
+
+// waitForHealthy waits until a started container reports healthy. Containers
+// without a healthcheck (in the catalog or the image) get a brief pause
+// instead. It fails if the container turns unhealthy, exits or does not
+// become healthy within the timeout.
+func (i *Installer) waitForHealthy(containerID, name string, timeout time.Duration) error {
+	if timeout <= 0 {
+		timeout = DefaultHealthTimeout
+	}
+
+	start := time.Now()
+	deadline := start.Add(timeout)
+	frame := 0
+	spinning := false
+
+	clear := func() {
+		if spinning {
+			fmt.Print("\r\033[K")
+		}
+	}
+
+	for {
+		info, err := i.dockerClient.ContainerInspect(containerID)
+		if err != nil {
+			clear()
+			return fmt.Errorf("failed to inspect %s: %w", name, err)
+		}
+
+		if info.ContainerJSONBase == nil || info.State == nil {
+			clear()
+			return fmt.Errorf("no state reported for %s", name)
+		}
+
+		state := info.State
+		if !state.Running && !state.Restarting {
+			clear()
+			return fmt.Errorf("%s exited with code %d", name, state.ExitCode)
+		}
+
+		if state.Health == nil {
+			clear()
+			time.Sleep(noHealthcheckPause)
+			return nil
+		}
+
+		switch state.Health.Status {
+		case "healthy":
+			clear()
+			color.Green("✓ %s is healthy (%s)", name, time.Since(start).Round(time.Second))
+			return nil
+		case "unhealthy":
+			clear()
+			return fmt.Errorf("%s is unhealthy%s", name, lastHealthOutput(state.Health))
+		}
+
+		if time.Now().After(deadline) {
+			clear()
+			return fmt.Errorf("timed out after %s waiting for %s to become healthy%s", timeout, name, lastHealthOutput(state.Health))
+		}
+
+		if color.NoColor {
+			// No terminal: print a single line instead of animating
+			if frame == 0 {
+				fmt.Printf("  Waiting for %s to become healthy...\n", name)
+			}
+		} else {
+			fmt.Printf("\r\033[K%s Waiting for %s to become healthy (%s)",
+				spinnerFrames[frame%len(spinnerFrames)], name, time.Since(start).Round(time.Second))
+			spinning = true
+		}
+		frame++
+
+		time.Sleep(healthPollInterval)
+	}
+}
+
+// lastHealthOutput formats the output of the most recent health probe
+func lastHealthOutput(health *dockerTypes.Health) string {
+	if health == nil || len(health.Log) == 0 {
+		return ""
+	}
+
+	output := strings.TrimSpace(health.Log[len(health.Log)-1].Output)
+	if output == "" {
+		return ""
+	}
+	return ": " + output
+}
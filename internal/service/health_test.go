@@ -0,0 +1,53 @@
+package service
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/dokulabs/doku-cli/pkg/types"
+)
+
+func TestHealthConfig(t *testing.T) {
+	config, err := healthConfig(nil)
+	if err != nil || config != nil {
+		t.Fatalf("healthConfig(nil) = %v, %v; want nil, nil", config, err)
+	}
+
+	config, err = healthConfig(&types.Healthcheck{
+		Test:     []string{"CMD", "pg_isready", "-U", "postgres"},
+		Interval: "10s",
+		Timeout:  "5s",
+		Retries:  3,
+		Start:    "30s",
+	})
+	if err != nil {
+		t.Fatalf("healthConfig() error = %v", err)
+	}
+	if want := []string{"CMD", "pg_isready", "-U", "postgres"}; !reflect.DeepEqual(config.Test, want) {
+		t.Errorf("Test = %v, want %v", config.Test, want)
+	}
+	if config.Interval != 10*time.Second || config.Timeout != 5*time.Second || config.StartPeriod != 30*time.Second {
+		t.Errorf("durations = %v/%v/%v, want 10s/5s/30s", config.Interval, config.Timeout, config.StartPeriod)
+	}
+	if config.Retries != 3 {
+		t.Errorf("Retries = %d, want 3", config.Retries)
+	}
+}
+
+func TestHealthConfigShellTest(t *testing.T) {
+	config, err := healthConfig(&types.Healthcheck{Test: []string{"curl", "-f", "http://localhost/health"}})
+	if err != nil {
+		t.Fatalf("healthConfig() error = %v", err)
+	}
+	want := []string{"CMD-SHELL", "curl -f http://localhost/health"}
+	if !reflect.DeepEqual(config.Test, want) {
+		t.Errorf("Test = %v, want %v", config.Test, want)
+	}
+}
+
+func TestHealthConfigInvalidDuration(t *testing.T) {
+	if _, err := healthConfig(&types.Healthcheck{Test: []string{"CMD", "true"}, Interval: "soon"}); err == nil {
+		t.Error("healthConfig() with invalid interval should fail")
+	}
+}
@@ -3,17 +3,22 @@ package service
 import (
 	"context"
 	"fmt"
+	"slices"
 	"strings"
+	"time"
 
 	dockerTypes "github.com/docker/docker/api/types/container"
 	"github.com/docker/docker/api/types/mount"
 	"github.com/docker/docker/api/types/network"
 	"github.com/docker/go-connections/nat"
 	"github.com/dokulabs/doku-cli/internal/catalog"
+	"github.com/dokulabs/doku-cli/internal/certs"
 	"github.com/dokulabs/doku-cli/internal/config"
 	"github.com/dokulabs/doku-cli/internal/dns"
 	"github.com/dokulabs/doku-cli/internal/docker"
+	"github.com/dokulabs/doku-cli/internal/domain"
 	"github.com/dokulabs/doku-cli/internal/envfile"
+	"github.com/dokulabs/doku-cli/internal/events"
 	"github.com/dokulabs/doku-cli/internal/monitoring"
 	"github.com/dokulabs/doku-cli/pkg/types"
 	"github.com/fatih/color"
@@ -24,8 +29,11 @@ type Installer struct {
 	dockerClient *docker.Client
 	configMgr    *config.Manager
 	catalogMgr   *catalog.Manager
-	domain       string
+	domain       string   // Main base domain, that of instances' URLs
+	domains      []string // All base domains, the main one first
 	protocol     string
+	httpPort     int // Host ports of Traefik's entrypoints
+	httpsPort    int
 }
 
 // NewInstaller creates a new service installer
@@ -36,22 +44,24 @@ func NewInstaller(dockerClient *docker.Client, configMgr *config.Manager, catalo
 		return nil, fmt.Errorf("failed to get config: %w", err)
 	}
 
-	domain := cfg.Preferences.Domain
-	if domain == "" {
-		domain = "doku.local"
-	}
+	domains := domain.BaseDomains(cfg.Preferences)
 
 	protocol := cfg.Preferences.Protocol
 	if protocol == "" {
 		protocol = "https"
 	}
 
+	httpPort, httpsPort := config.TraefikPorts(cfg.Traefik)
+
 	return &Installer{
 		dockerClient: dockerClient,
 		configMgr:    configMgr,
 		catalogMgr:   catalogMgr,
-		domain:       domain,
+		domain:       domains[0],
+		domains:      domains,
 		protocol:     protocol,
+		httpPort:     httpPort,
+		httpsPort:    httpsPort,
 	}, nil
 }
 
@@ -67,25 +77,138 @@ type InstallOptions struct {
 	PortMappings map[string]string // Port mappings (containerPort:hostPort as strings)
 	Internal     bool              // If true, don't expose via Traefik
 
+	// HostNetwork runs the container on the host's network stack instead
+	// of doku-network, for tools that need it (network scanners, mDNS).
+	// Traefik can't route to such containers. Services can also ask for it
+	// in their spec (host_network).
+	HostNetwork bool
+
+	// HealthTimeout is how long to wait for a container to become healthy
+	// before starting the containers that depend on it (0 = default)
+	HealthTimeout time.Duration
+
 	// Dependency management (Phase 3)
 	SkipDependencies bool // If true, skip dependency resolution
 	AutoInstallDeps  bool // If true, auto-install dependencies without prompting
 	IsDepend         bool // Internal: true if this is being installed as a dependency
 	Replace          bool // If true, replace existing instance without prompting
 
+	// DependencyInstances binds dependencies to installed instances of
+	// their service, e.g. postgres → postgres-16, instead of installing
+	// new ones. Their connection variables are injected into the service's
+	// environment.
+	DependencyInstances map[string]string
+
+	// ChooseDependencies offers the installed instances of a dependency's
+	// service to bind to, before installing a new one
+	ChooseDependencies bool
+
 	// Data reuse options
 	ReuseExistingData bool // If true, reuse existing volumes and env files
 	ForceCleanData    bool // If true, delete existing data without prompting
+
+	// DefaultPasswords keeps the catalog's default passwords instead of
+	// generating random ones
+	DefaultPasswords bool
+
+	// Labels are extra Docker labels for the service's containers, on top
+	// of the defaults in the config (preferences.labels)
+	Labels map[string]string
+
+	// Logs overrides the rotation of the containers' logs in the config
+	// (preferences.logs); nil keeps it
+	Logs *types.LogRotation
+
+	// Middlewares are the Traefik middlewares put in front of the
+	// service's router: basic auth, an IP allow list, a rate limit and
+	// headers
+	Middlewares *types.Middlewares
+
+	// Domains are hostnames the service is also reachable at, e.g.
+	// api.myproject.test
+	Domains []string
+
+	// TLSSANs are names the service's own certificate covers on top of its
+	// hostname, e.g. api.internal or 10.0.0.5
+	TLSSANs []string
+
+	// LAN exposes the service to the other devices of the network
+	LAN *types.LANExposure
+
+	// ImageDigest pins the image of a single-container service to a
+	// digest, e.g. the one recorded of an instance being recreated, rather
+	// than the image its tag points to now. ContainerDigests pins those of
+	// a multi-container service, by container name.
+	ImageDigest      string
+	ContainerDigests map[string]string
 }
 
 // Install installs a service from the catalog
 func (i *Installer) Install(opts InstallOptions) (*types.Instance, error) {
+	if err := docker.ValidateExtraLabels(opts.Labels); err != nil {
+		return nil, err
+	}
+	if opts.Logs != nil {
+		if err := monitoring.ValidateLogRotation(*opts.Logs); err != nil {
+			return nil, err
+		}
+	}
+	if err := docker.ValidateMiddlewares(opts.Middlewares); err != nil {
+		return nil, err
+	}
+	if len(opts.Domains) > 0 {
+		cfg, err := i.configMgr.Get()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get config: %w", err)
+		}
+		if err := domain.ValidateHostnames(cfg, opts.InstanceName, opts.Domains); err != nil {
+			return nil, err
+		}
+	}
+	for _, san := range opts.TLSSANs {
+		if err := certs.ValidateSAN(san); err != nil {
+			return nil, err
+		}
+	}
+
 	// Step 1: Resolve dependencies (Phase 3)
+	var deps *resolvedDependencies
 	if !opts.SkipDependencies && !opts.IsDepend {
-		if err := i.resolveDependencies(opts); err != nil {
+		resolved, err := i.resolveDependencies(opts)
+		if err != nil {
 			return nil, err
 		}
+		deps = resolved
+
+		// Connect to the installed instances reused for dependencies,
+		// unless overridden
+		if len(deps.env) > 0 {
+			opts.Environment = i.mergeEnvironment(deps.env, opts.Environment)
+		}
+	}
+
+	instance, err := i.install(opts)
+	if err != nil {
+		return nil, err
+	}
+	if deps != nil {
+		if err := i.recordDependencies(instance, deps); err != nil {
+			fmt.Printf("Warning: failed to record dependencies of %s: %v\n", instance.Name, err)
+		}
 	}
+	events.Emit(events.New(events.TypeInstall, instance.Name, "installed (%s %s)", instance.ServiceType, instance.Version))
+	return instance, nil
+}
+
+// install creates the instance of a service once its dependencies are in
+// place
+func (i *Installer) install(opts InstallOptions) (*types.Instance, error) {
+	// Resolve a version range such as "^16" to the newest matching version
+	resolvedVersion, err := i.catalogMgr.ResolveVersion(opts.ServiceName, opts.Version)
+	if err != nil {
+		return nil, err
+	}
+	opts.Version = resolvedVersion
 
 	// Get service spec from catalog
 	spec, err := i.catalogMgr.GetServiceVersion(opts.ServiceName, opts.Version)
@@ -110,10 +233,16 @@ func (i *Installer) Install(opts InstallOptions) (*types.Instance, error) {
 		}
 	}
 
+	// Use the images built for the daemon's architecture, and warn before
+	// pulling any that would be emulated
+	arch := i.dockerClient.Arch()
+	spec = spec.ForArch(arch)
+	warnEmulatedImages(i.dockerClient, spec, arch)
+
 	// Generate instance name if not provided
 	instanceName := opts.InstanceName
 	if instanceName == "" {
-		instanceName, err = i.generateInstanceName(opts.ServiceName, version)
+		instanceName, err = i.generateInstanceName(catalog.BaseName(opts.ServiceName), version)
 		if err != nil {
 			return nil, fmt.Errorf("failed to generate instance name: %w", err)
 		}
@@ -198,20 +327,50 @@ func (i *Installer) Install(opts InstallOptions) (*types.Instance, error) {
 		}
 	}
 
+	hostNetwork := opts.HostNetwork || spec.HostNetwork
+	if hostNetwork && spec.IsMultiContainer() {
+		return nil, fmt.Errorf("host networking is only supported for single-container services")
+	}
+	if len(opts.Domains) > 0 && (hostNetwork || opts.Internal) {
+		return nil, fmt.Errorf("domains need a service Traefik routes to: leave out --internal and --host-network")
+	}
+	if len(opts.TLSSANs) > 0 && (hostNetwork || opts.Internal) {
+		return nil, fmt.Errorf("TLS SANs need a service Traefik routes to: leave out --internal and --host-network")
+	}
+
 	// Step 3: Check if multi-container service (Phase 3)
 	if spec.IsMultiContainer() {
 		return i.installMultiContainer(opts, spec, instanceName, version, existingData)
 	}
 
 	// Single-container installation (existing logic)
-	// Merge environment variables: catalog defaults + user overrides
-	env := i.mergeEnvironment(spec.Environment, opts.Environment)
+	// Merge environment variables: catalog defaults + user overrides.
+	// Default passwords are replaced with random ones, except for
+	// dependencies, whose dependents are configured with the defaults.
+	defaults := spec.Environment
+	var generated []string
+	if !opts.DefaultPasswords && !opts.IsDepend {
+		defaults, generated, err = generatePasswords(spec.Environment, opts.Environment)
+		if err != nil {
+			return nil, err
+		}
+	}
+	env := i.mergeEnvironment(defaults, opts.Environment)
 
 	// If we have existing env data and user chose to reuse, merge with existing
 	if existingData != nil && len(existingData.EnvVars) > 0 {
 		// Existing values take precedence (user's data is preserved)
 		env = i.mergeWithExistingEnv(existingData.EnvVars, env)
 		color.Cyan("Merged %d existing environment variables", len(existingData.EnvVars))
+
+		// Passwords kept from the existing data were not generated
+		kept := generated[:0]
+		for _, key := range generated {
+			if _, existing := existingData.EnvVars[key]; !existing {
+				kept = append(kept, key)
+			}
+		}
+		generated = kept
 	}
 
 	// Add monitoring instrumentation environment variables
@@ -235,29 +394,56 @@ func (i *Installer) Install(opts InstallOptions) (*types.Instance, error) {
 	// Create container name
 	containerName := docker.GenerateContainerName(instanceName)
 
+	image, err := pinnedImage(i.dockerClient, opts.ImageDigest, spec.Image)
+	if err != nil {
+		return nil, err
+	}
+
 	// Check if image exists locally first
-	imageExists, err := i.dockerClient.ImageExists(spec.Image)
+	imageExists, err := i.dockerClient.ImageExists(image)
 	if err != nil {
 		return nil, fmt.Errorf("failed to check image existence: %w", err)
 	}
 
 	if imageExists {
-		fmt.Printf("Using cached image %s\n", spec.Image)
+		fmt.Printf("Using cached image %s\n", image)
 	} else {
 		// Pull image if not in cache
-		fmt.Printf("Pulling image %s...\n", spec.Image)
-		if err := i.dockerClient.ImagePull(spec.Image); err != nil {
+		fmt.Printf("Pulling image %s...\n", image)
+		if err := i.dockerClient.ImagePull(image); err != nil {
 			return nil, fmt.Errorf("failed to pull image: %w", err)
 		}
 	}
 
+	// The env file keeps references to other instances; the container
+	// gets their values
+	containerEnv, err := resolveEnvRefs(i.configMgr, env)
+	if err != nil {
+		return nil, err
+	}
+
+	// Containers on the host network use the host's ports directly and
+	// can't be routed to by Traefik
+	portMappings := opts.PortMappings
+	if hostNetwork {
+		printHostNetworkNotice(instanceName, spec.Port, len(portMappings) > 0)
+		portMappings = nil
+	}
+
 	// Create container configuration
 	containerConfig := &dockerTypes.Config{
-		Image:        spec.Image,
-		Env:          i.envMapToSlice(env),
-		Labels:       i.generateLabels(instanceName, service, spec, opts.Internal),
-		ExposedPorts: i.createExposedPorts(opts.PortMappings),
+		Image:        image,
+		Env:          i.envMapToSlice(containerEnv),
+		Labels:       docker.MergeLabels(i.extraLabels(opts.Labels), i.generateLabels(instanceName, service, spec, opts.Internal || hostNetwork, customHostnames(instanceName, opts))),
+		ExposedPorts: i.createExposedPorts(portMappings),
 	}
+	docker.ApplyMiddlewares(containerConfig.Labels, opts.Middlewares)
+
+	healthcheck, err := healthConfig(spec.Healthcheck)
+	if err != nil {
+		return nil, err
+	}
+	containerConfig.Healthcheck = healthcheck
 
 	// Set custom command if specified in the service spec
 	if len(spec.Command) > 0 {
@@ -270,18 +456,21 @@ func (i *Installer) Install(opts InstallOptions) (*types.Instance, error) {
 			Name: "unless-stopped",
 		},
 		Mounts:       i.createMounts(instanceName, spec, opts.Volumes),
-		LogConfig:    *monitoring.GetDockerLoggingConfig(&cfg.Monitoring),
-		PortBindings: i.createPortBindings(opts.PortMappings),
+		LogConfig:    *monitoring.GetDockerLoggingConfig(&cfg.Monitoring, monitoring.ResolveLogRotation(cfg.Preferences.Logs, opts.Logs)),
+		PortBindings: i.createPortBindings(portMappings),
 	}
 
-	// Apply resource limits
-	if err := i.applyResourceLimits(hostConfig, memoryLimit, cpuLimit); err != nil {
+	docker.UseEngineSocket(i.dockerClient.Engine(), hostConfig)
+
+	// Apply resource limits, the defaults without any
+	limitMemory, limitCPU := EffectiveLimits(cfg.Limits, types.ResourceConfig{MemoryLimit: memoryLimit, CPULimit: cpuLimit})
+	if err := i.applyResourceLimits(hostConfig, limitMemory, limitCPU); err != nil {
 		return nil, fmt.Errorf("failed to apply resource limits: %w", err)
 	}
 
 	// Build network aliases: service name and instance name
-	aliases := []string{opts.ServiceName}
-	if instanceName != opts.ServiceName {
+	aliases := []string{catalog.BaseName(opts.ServiceName)}
+	if instanceName != aliases[0] {
 		aliases = append(aliases, instanceName)
 	}
 
@@ -289,11 +478,17 @@ func (i *Installer) Install(opts InstallOptions) (*types.Instance, error) {
 	// This is more reliable than connecting after creation
 	networkConfig := &network.NetworkingConfig{
 		EndpointsConfig: map[string]*network.EndpointSettings{
-			"doku-network": {
+			docker.NetworkName(): {
 				Aliases: aliases,
 			},
 		},
 	}
+	networkName := docker.NetworkName()
+	if hostNetwork {
+		hostConfig.NetworkMode = dockerTypes.NetworkMode(types.NetworkModeHost)
+		networkConfig = nil
+		networkName = types.NetworkModeHost
+	}
 
 	// Create container with network config
 	fmt.Printf("Creating container %s...\n", instanceName)
@@ -314,23 +509,35 @@ func (i *Installer) Install(opts InstallOptions) (*types.Instance, error) {
 	fmt.Printf("Starting container...\n")
 	if err := i.dockerClient.ContainerStart(containerID); err != nil {
 		// Cleanup on failure
-		networkMgr.DisconnectContainer("doku-network", containerName, true)
+		networkMgr.DisconnectContainer(docker.NetworkName(), containerName, true)
 		i.dockerClient.ContainerRemove(containerName, true)
 		return nil, fmt.Errorf("failed to start container: %w", err)
 	}
 
 	// Build service URL
 	serviceURL := i.buildServiceURL(instanceName)
+	connectionString := i.buildConnectionString(instanceName, spec, env)
+	if hostNetwork {
+		serviceURL = ""
+		connectionString = ""
+		if spec.Port > 0 {
+			connectionString = fmt.Sprintf("localhost:%d", spec.Port)
+		}
+	}
 
 	// Save environment to env file
 	envMgr := envfile.NewManager(i.configMgr.GetDokuDir())
 	envPath := envMgr.GetServiceEnvPath(instanceName, "")
 	if err := envMgr.Save(envPath, env); err != nil {
 		// Cleanup on failure
-		networkMgr.DisconnectContainer("doku-network", containerName, true)
+		networkMgr.DisconnectContainer(docker.NetworkName(), containerName, true)
 		i.dockerClient.ContainerRemove(containerName, true)
 		return nil, fmt.Errorf("failed to save environment file: %w", err)
 	}
+	if len(generated) > 0 {
+		color.Cyan("Generated random passwords for %s", strings.Join(generated, ", "))
+		fmt.Printf("View them with: doku env %s --show-values\n", instanceName)
+	}
 
 	// Create instance record (Environment field kept for backward compatibility but not primary source)
 	instance := &types.Instance{
@@ -338,45 +545,76 @@ func (i *Installer) Install(opts InstallOptions) (*types.Instance, error) {
 		ServiceType:      opts.ServiceName,
 		Version:          version,
 		Status:           types.StatusRunning,
+		DesiredState:     types.StatusRunning,
 		ContainerName:    containerName,
 		ContainerID:      containerID, // Phase 3: Added for consistency
 		IsMultiContainer: false,       // Phase 3: Single-container
+		ImageDigest:      installedDigest(i.dockerClient, opts.ImageDigest, spec.Image),
 		URL:              serviceURL,
-		ConnectionString: i.buildConnectionString(instanceName, spec, env),
+		ConnectionString: connectionString,
 		Environment:      env, // Kept for backward compatibility during migration
+		Labels:           opts.Labels,
+		Logs:             opts.Logs,
+		Middlewares:      opts.Middlewares,
+		Domains:          opts.Domains,
+		TLSSANs:          opts.TLSSANs,
+		LAN:              opts.LAN,
 		Volumes:          opts.Volumes,
 		Resources: types.ResourceConfig{
 			MemoryLimit: memoryLimit,
 			CPULimit:    cpuLimit,
 		},
 		Network: types.NetworkConfig{
-			Name:         "doku-network",
+			Name:         networkName,
 			InternalPort: spec.Port,
-			PortMappings: opts.PortMappings,
+			PortMappings: portMappings,
 		},
 		Traefik: types.TraefikInstanceConfig{
-			Enabled:   true,
+			Enabled:   !hostNetwork,
 			Subdomain: instanceName,
 			Port:      spec.Port,
 			Protocol:  spec.Protocol,
 		},
 	}
+	if hostNetwork {
+		instance.Network.Mode = types.NetworkModeHost
+	}
 
 	// Save instance to config
 	if err := i.configMgr.AddInstance(instance); err != nil {
 		return nil, fmt.Errorf("failed to save instance: %w", err)
 	}
 
+	if hostNetwork {
+		return instance, nil
+	}
+
 	// Add DNS entry if automatic DNS setup is enabled
-	if err := i.updateDNS(instanceName); err != nil {
+	if err := i.updateDNS(instanceName, opts.Domains); err != nil {
 		// Don't fail installation if DNS update fails, just warn
 		color.Yellow("⚠️  Failed to add DNS entry: %v", err)
-		color.Yellow("You may need to manually add: 127.0.0.1 %s.%s", instanceName, i.domain)
+		color.Yellow("You may need to manually add: %s %s.%s", i.configMgr.GetHostsIP(), instanceName, i.domain)
 	}
 
 	return instance, nil
 }
 
+// printHostNetworkNotice explains what host networking means for an
+// instance before it's created
+func printHostNetworkNotice(instanceName string, port int, hasPortMappings bool) {
+	color.Yellow("⚠️  %s uses host networking: it shares the host's network stack instead of joining doku-network.", instanceName)
+	fmt.Println("   Traefik can't route to it, so it gets no URL or DNS entry, and other services")
+	fmt.Println("   can't reach it by name.")
+	if port > 0 {
+		fmt.Printf("   It listens directly on the host, e.g. localhost:%d.\n", port)
+	}
+	if hasPortMappings {
+		fmt.Println("   Port mappings are ignored: the container already uses the host's ports.")
+	}
+	fmt.Println("   On Docker Desktop (macOS, Windows) host networking may need to be enabled in its settings.")
+	fmt.Println()
+}
+
 // generateInstanceName generates a unique instance name
 func (i *Installer) generateInstanceName(serviceName, version string) (string, error) {
 	baseName := serviceName
@@ -426,21 +664,35 @@ func (i *Installer) envMapToSlice(env map[string]string) []string {
 	return slice
 }
 
-// generateLabels generates Traefik and management labels
-func (i *Installer) generateLabels(instanceName string, service *types.CatalogService, spec *types.ServiceSpec, internal bool) map[string]string {
-	labels := make(map[string]string)
+// extraLabels returns the user's labels for a service: the config defaults
+// overridden by labels. Doku's own labels are merged on top of them.
+func (i *Installer) extraLabels(labels map[string]string) map[string]string {
+	cfg, err := i.configMgr.Get()
+	if err != nil {
+		return docker.MergeLabels(labels)
+	}
+	return docker.MergeLabels(cfg.Preferences.Labels, labels)
+}
 
+// customHostnames returns the hostnames of an instance besides its
+// subdomain of each base domain: its own domains and those it's exposed to
+// the network at
+func customHostnames(instanceName string, opts InstallOptions) []string {
+	return append(slices.Clone(opts.Domains), domain.LANHostnames(instanceName, opts.LAN)...)
+}
+
+// generateLabels generates Traefik and management labels
+func (i *Installer) generateLabels(instanceName string, service *types.CatalogService, spec *types.ServiceSpec, internal bool, domains []string) map[string]string {
 	// Management labels (always added)
-	labels["managed-by"] = "doku"
+	labels := docker.InstanceLabels(instanceName)
 	labels["doku.service"] = service.Name
-	labels["doku.instance"] = instanceName
 	labels["doku.version"] = spec.Image
 
 	// Traefik labels for HTTP routing (only if NOT internal)
 	if !internal && (spec.Protocol == "http" || spec.Protocol == "https") {
-		routerName := fmt.Sprintf("doku-%s", instanceName)
+		routerName := docker.GenerateContainerName(instanceName)
 		labels["traefik.enable"] = "true"
-		labels[fmt.Sprintf("traefik.http.routers.%s.rule", routerName)] = fmt.Sprintf("Host(`%s.%s`)", instanceName, i.domain)
+		labels[fmt.Sprintf("traefik.http.routers.%s.rule", routerName)] = domain.HostRule(domain.Hostnames(instanceName, i.domains, domains))
 		labels[fmt.Sprintf("traefik.http.routers.%s.entrypoints", routerName)] = "web,websecure"
 		labels[fmt.Sprintf("traefik.http.services.%s.loadbalancer.server.port", routerName)] = fmt.Sprintf("%d", spec.Port)
 
@@ -503,9 +755,10 @@ func (i *Installer) createMounts(instanceName string, spec *types.ServiceSpec, c
 			volumeName := docker.GenerateVolumeName(instanceName, fmt.Sprintf("%s-%d", volumePath, idx))
 
 			mounts = append(mounts, mount.Mount{
-				Type:   mount.TypeVolume,
-				Source: volumeName,
-				Target: volumePath,
+				Type:          mount.TypeVolume,
+				Source:        volumeName,
+				Target:        volumePath,
+				VolumeOptions: &mount.VolumeOptions{Labels: docker.InstanceLabels(instanceName)},
 			})
 		}
 	}
@@ -546,7 +799,7 @@ func (i *Installer) applyResourceLimits(hostConfig *dockerTypes.HostConfig, memo
 
 // buildServiceURL builds the service access URL
 func (i *Installer) buildServiceURL(instanceName string) string {
-	return fmt.Sprintf("%s://%s.%s", i.protocol, instanceName, i.domain)
+	return config.ServiceURL(i.protocol, instanceName+"."+i.domain, i.httpPort, i.httpsPort)
 }
 
 // buildConnectionString builds a connection string for the service
@@ -589,7 +842,7 @@ func (i *Installer) createPortBindings(portMappings map[string]string) nat.PortM
 		containerPortSpec := nat.Port(fmt.Sprintf("%s/tcp", containerPortStr))
 		portMap[containerPortSpec] = []nat.PortBinding{
 			{
-				HostIP:   "0.0.0.0",
+				HostIP:   i.configMgr.GetPublishIP(),
 				HostPort: hostPortStr,
 			},
 		}
@@ -598,8 +851,9 @@ func (i *Installer) createPortBindings(portMappings map[string]string) nat.PortM
 	return portMap
 }
 
-// updateDNS adds DNS entry for the service if automatic DNS setup is enabled
-func (i *Installer) updateDNS(instanceName string) error {
+// updateDNS adds DNS entries for the service's hostnames if automatic DNS
+// setup is enabled
+func (i *Installer) updateDNS(instanceName string, domains []string) error {
 	// Get config to check DNS setup preference
 	cfg, err := i.configMgr.Get()
 	if err != nil {
@@ -612,14 +866,15 @@ func (i *Installer) updateDNS(instanceName string) error {
 	}
 
 	// Import dns package
-	dnsMgr := dns.NewManager()
+	dnsMgr := dns.NewManagerForContext(i.configMgr.GetContext()).SetIP(i.configMgr.GetHostsIP())
 
-	// Add DNS entry for this service
-	if err := dnsMgr.AddServiceDomain(instanceName, i.domain); err != nil {
-		return err
+	// Add DNS entries for this service
+	for _, host := range domain.Hostnames(instanceName, i.domains, domains) {
+		if err := dnsMgr.AddHostname(host); err != nil {
+			return err
+		}
+		fmt.Printf("✓ Added %s to %s\n", host, dnsMgr.GetHostsFilePath())
 	}
-
-	fmt.Printf("✓ Added %s.%s to /etc/hosts\n", instanceName, i.domain)
 	return nil
 }
 
@@ -638,9 +893,8 @@ func (i *Installer) checkExistingData(instanceName string) (*ExistingData, error
 		EnvVars: make(map[string]string),
 	}
 
-	// Check for existing volumes with prefix "doku-<instanceName>-"
-	volumePrefix := fmt.Sprintf("doku-%s-", instanceName)
-	volumes, err := i.dockerClient.ListVolumesByPrefix(ctx, volumePrefix)
+	// Check for existing volumes of the instance
+	volumes, err := i.dockerClient.ListInstanceVolumes(ctx, instanceName)
 	if err != nil {
 		return nil, fmt.Errorf("failed to list volumes: %w", err)
 	}
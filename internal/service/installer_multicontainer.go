@@ -8,8 +8,10 @@ import (
 	dockerTypes "github.com/docker/docker/api/types/container"
 	"github.com/docker/docker/api/types/mount"
 	"github.com/docker/docker/api/types/network"
+	"github.com/dokulabs/doku-cli/internal/catalog"
 	"github.com/dokulabs/doku-cli/internal/dependencies"
 	"github.com/dokulabs/doku-cli/internal/docker"
+	"github.com/dokulabs/doku-cli/internal/domain"
 	"github.com/dokulabs/doku-cli/internal/envfile"
 	"github.com/dokulabs/doku-cli/internal/monitoring"
 	"github.com/dokulabs/doku-cli/pkg/types"
@@ -18,8 +20,16 @@ import (
 
 // Phase 3: Multi-Container & Dependency Management Methods
 
+// resolvedDependencies are the instances providing a service's dependencies
+type resolvedDependencies struct {
+	installed []string          // Instances installed for the service
+	instances map[string]string // Dependency → installed instance reused for it, when named otherwise
+	links     []types.Link      // Connection variables of the reused instances
+	env       map[string]string // Those variables, for the service's environment
+}
+
 // resolveDependencies resolves and installs dependencies for a service
-func (i *Installer) resolveDependencies(opts InstallOptions) error {
+func (i *Installer) resolveDependencies(opts InstallOptions) (*resolvedDependencies, error) {
 	// Create dependency resolver
 	resolver := dependencies.NewResolver(i.catalogMgr, i.configMgr)
 
@@ -27,16 +37,38 @@ func (i *Installer) resolveDependencies(opts InstallOptions) error {
 	result, err := resolver.Resolve(opts.ServiceName, opts.Version)
 	if err != nil {
 		if dependencies.IsCircularDependency(err) {
-			return fmt.Errorf("circular dependency detected: %w\nPlease fix the catalog configuration", err)
+			return nil, fmt.Errorf("circular dependency detected: %w\nPlease fix the catalog configuration", err)
+		}
+		return nil, fmt.Errorf("dependency resolution failed: %w", err)
+	}
+
+	// Installed instances reused for version ranges
+	resolved := &resolvedDependencies{instances: make(map[string]string)}
+	for _, dep := range resolver.GetInstalledDependencies(result) {
+		if dep.ServiceName != opts.ServiceName && dep.Instance != "" && dep.Instance != dep.ServiceName {
+			resolved.instances[dep.ServiceName] = dep.Instance
+		}
+	}
+
+	// Get missing dependencies, and bind them to installed instances of
+	// their service where asked
+	missing, err := i.bindDependencies(opts, result, resolver.GetMissingDependencies(result), resolved)
+	if err != nil {
+		return nil, err
+	}
+	if len(resolved.instances) > 0 {
+		resolved.links, resolved.env, err = i.bindLinks(resolved.instances)
+		if err != nil {
+			return nil, err
+		}
+		for _, link := range resolved.links {
+			color.Cyan("Using installed %s, connected through %s_* variables", link.Service, link.Prefix)
 		}
-		return fmt.Errorf("dependency resolution failed: %w", err)
 	}
 
-	// Get missing dependencies
-	missing := resolver.GetMissingDependencies(result)
 	if len(missing) == 0 {
 		// All dependencies already installed
-		return nil
+		return resolved, nil
 	}
 
 	// Show dependency tree
@@ -68,22 +100,54 @@ func (i *Installer) resolveDependencies(opts InstallOptions) error {
 			depOpts := InstallOptions{
 				ServiceName:      dep.ServiceName,
 				Version:          dep.Version,
-				InstanceName:     dep.ServiceName, // Use service name as instance name
+				InstanceName:     catalog.BaseName(dep.ServiceName), // Use service name as instance name
 				Environment:      dep.Environment,
 				Internal:         true,  // Dependencies are internal by default
 				SkipDependencies: false, // Allow nested dependencies
 				AutoInstallDeps:  true,  // Auto-install nested deps
 				IsDepend:         true,  // Mark as dependency installation
+				HealthTimeout:    opts.HealthTimeout,
 			}
 
 			if _, err := i.Install(depOpts); err != nil {
-				return fmt.Errorf("failed to install dependency %s: %w", dep.ServiceName, err)
+				return nil, fmt.Errorf("failed to install dependency %s: %w", dep.ServiceName, err)
 			}
+			resolved.installed = append(resolved.installed, depOpts.InstanceName)
 
 			color.Green("✓ %s installed", dep.ServiceName)
 		}
 	}
 
+	fmt.Println()
+	return resolved, nil
+}
+
+// maxParallelPulls is how many images of a multi-container service are
+// pulled at once
+const maxParallelPulls = 4
+
+// pullImages pulls the images that aren't cached yet, several at a time
+func (i *Installer) pullImages(images []string) error {
+	var missing []string
+	for _, image := range images {
+		exists, err := i.dockerClient.ImageExists(image)
+		if err != nil {
+			return fmt.Errorf("failed to check image existence for %s: %w", image, err)
+		}
+		if exists {
+			fmt.Printf("  Using cached image %s\n", image)
+		} else {
+			missing = append(missing, image)
+		}
+	}
+	if len(missing) == 0 {
+		return nil
+	}
+
+	fmt.Printf("  Pulling %d images...\n", len(missing))
+	if err := i.dockerClient.ImagePullAll(missing, maxParallelPulls); err != nil {
+		return fmt.Errorf("failed to pull images: %w", err)
+	}
 	fmt.Println()
 	return nil
 }
@@ -113,6 +177,12 @@ func (i *Installer) installMultiContainer(
 		Dependencies:     spec.GetDependencyNames(),
 		Status:           "creating",
 		Environment:      opts.Environment,
+		Labels:           opts.Labels,
+		Logs:             opts.Logs,
+		Middlewares:      opts.Middlewares,
+		Domains:          opts.Domains,
+		TLSSANs:          opts.TLSSANs,
+		LAN:              opts.LAN,
 	}
 
 	// Find primary container
@@ -121,6 +191,11 @@ func (i *Installer) installMultiContainer(
 		return nil, fmt.Errorf("no primary container defined")
 	}
 
+	// Pull every image, init containers' included, up front
+	if err := i.pullImages(SpecImages(spec)); err != nil {
+		return nil, err
+	}
+
 	// Run init containers (migrations, setup scripts, etc.)
 	if len(spec.InitContainers) > 0 {
 		if err := i.runInitContainers(spec, instanceName); err != nil {
@@ -171,24 +246,6 @@ func (i *Installer) installMultiContainer(
 			return nil, fmt.Errorf("failed to save environment file for %s: %w", containerSpec.Name, err)
 		}
 
-		// Check if image exists locally first
-		imageExists, err := i.dockerClient.ImageExists(containerSpec.Image)
-		if err != nil {
-			i.cleanupMultiContainerInstall(instance)
-			return nil, fmt.Errorf("failed to check image existence for %s: %w", containerSpec.Image, err)
-		}
-
-		if imageExists {
-			fmt.Printf("  Using cached image %s\n", containerSpec.Image)
-		} else {
-			// Pull image if not in cache
-			fmt.Printf("  Pulling image %s...\n", containerSpec.Image)
-			if err := i.dockerClient.ImagePull(containerSpec.Image); err != nil {
-				i.cleanupMultiContainerInstall(instance)
-				return nil, fmt.Errorf("failed to pull image %s: %w", containerSpec.Image, err)
-			}
-		}
-
 		// Determine the port for this container (for Traefik routing)
 		containerPort := 0
 		if isPrimary && len(containerSpec.Ports) > 0 {
@@ -203,12 +260,37 @@ func (i *Installer) installMultiContainer(
 			containerPort = spec.Port
 		}
 
+		containerEnv, err := resolveEnvRefs(i.configMgr, env)
+		if err != nil {
+			i.cleanupMultiContainerInstall(instance)
+			return nil, err
+		}
+
+		image, err := pinnedImage(i.dockerClient, opts.ContainerDigests[containerSpec.Name], containerSpec.Image)
+		if err != nil {
+			i.cleanupMultiContainerInstall(instance)
+			return nil, fmt.Errorf("container %s: %w", containerSpec.Name, err)
+		}
+
 		// Create container configuration
 		containerConfig := &dockerTypes.Config{
-			Image:  containerSpec.Image,
-			Env:    i.envMapToSlice(env),
-			Labels: i.generateMultiContainerLabels(instanceName, opts.ServiceName, containerSpec.Name, isPrimary, opts.Internal, containerPort),
+			Image:  image,
+			Env:    i.envMapToSlice(containerEnv),
+			Labels: docker.MergeLabels(i.extraLabels(opts.Labels), i.generateMultiContainerLabels(instanceName, opts.ServiceName, containerSpec.Name, isPrimary, opts.Internal, containerPort, customHostnames(instanceName, opts))),
 		}
+		docker.ApplyMiddlewares(containerConfig.Labels, opts.Middlewares)
+
+		// Use the container healthcheck, falling back to the service one for the primary
+		healthcheck := containerSpec.Healthcheck
+		if healthcheck == nil && isPrimary {
+			healthcheck = spec.Healthcheck
+		}
+		health, err := healthConfig(healthcheck)
+		if err != nil {
+			i.cleanupMultiContainerInstall(instance)
+			return nil, fmt.Errorf("container %s: %w", containerSpec.Name, err)
+		}
+		containerConfig.Healthcheck = health
 
 		// Override command/entrypoint if specified
 		if len(containerSpec.Command) > 0 {
@@ -224,27 +306,29 @@ func (i *Installer) installMultiContainer(
 				Name: "unless-stopped",
 			},
 			Mounts:    i.createMultiContainerMounts(instanceName, containerSpec),
-			LogConfig: *monitoring.GetDockerLoggingConfig(&cfg.Monitoring),
+			LogConfig: *monitoring.GetDockerLoggingConfig(&cfg.Monitoring, monitoring.ResolveLogRotation(cfg.Preferences.Logs, opts.Logs)),
 		}
+		docker.UseEngineSocket(i.dockerClient.Engine(), hostConfig)
 
-		// Apply resource limits
+		// Apply resource limits, the defaults without any
+		var own types.ResourceConfig
 		if containerSpec.Resources != nil {
-			memLimit := containerSpec.Resources.MemoryMax
-			cpuLimit := containerSpec.Resources.CPUMax
-			if err := i.applyResourceLimits(hostConfig, memLimit, cpuLimit); err != nil {
-				i.cleanupMultiContainerInstall(instance)
-				return nil, fmt.Errorf("failed to apply resource limits: %w", err)
-			}
+			own = types.ResourceConfig{MemoryLimit: containerSpec.Resources.MemoryMax, CPULimit: containerSpec.Resources.CPUMax}
+		}
+		memLimit, cpuLimit := EffectiveLimits(cfg.Limits, own)
+		if err := i.applyResourceLimits(hostConfig, memLimit, cpuLimit); err != nil {
+			i.cleanupMultiContainerInstall(instance)
+			return nil, fmt.Errorf("failed to apply resource limits: %w", err)
 		}
 
 		// Build network aliases for this container
-		aliases := i.buildNetworkAliases(instanceName, containerSpec.Name, isPrimary)
+		aliases := buildNetworkAliases(instanceName, containerSpec.Name, isPrimary)
 
 		// Create network configuration to connect to doku-network during container creation
 		// This is more reliable than connecting after creation
 		networkConfig := &network.NetworkingConfig{
 			EndpointsConfig: map[string]*network.EndpointSettings{
-				"doku-network": {
+				docker.NetworkName(): {
 					Aliases: aliases,
 				},
 			},
@@ -274,6 +358,8 @@ func (i *Installer) installMultiContainer(
 			Status:      "created",
 			Ports:       containerSpec.Ports,
 			Image:       containerSpec.Image,
+			DependsOn:   internalDependencies(spec, containerSpec),
+			ImageDigest: installedDigest(i.dockerClient, opts.ContainerDigests[containerSpec.Name], containerSpec.Image),
 		})
 
 		color.Green("✓ Container %s created", containerSpec.Name)
@@ -289,7 +375,7 @@ func (i *Installer) installMultiContainer(
 	fmt.Println()
 
 	// Start all containers in correct order
-	if err := i.startMultiContainerService(instance, spec); err != nil {
+	if err := i.startMultiContainerService(instance, opts.HealthTimeout); err != nil {
 		i.cleanupMultiContainerInstall(instance)
 		return nil, fmt.Errorf("failed to start containers: %w", err)
 	}
@@ -301,6 +387,7 @@ func (i *Installer) installMultiContainer(
 
 	// Update instance status
 	instance.Status = types.StatusRunning
+	instance.DesiredState = types.StatusRunning
 
 	// Save instance to config
 	if err := i.configMgr.AddInstance(instance); err != nil {
@@ -309,10 +396,10 @@ func (i *Installer) installMultiContainer(
 	}
 
 	// Add DNS entry if automatic DNS setup is enabled
-	if err := i.updateDNS(instanceName); err != nil {
+	if err := i.updateDNS(instanceName, opts.Domains); err != nil {
 		// Don't fail installation if DNS update fails, just warn
 		color.Yellow("⚠️  Failed to add DNS entry: %v", err)
-		color.Yellow("You may need to manually add: 127.0.0.1 %s.%s", instanceName, i.domain)
+		color.Yellow("You may need to manually add: %s %s.%s", i.configMgr.GetHostsIP(), instanceName, i.domain)
 	}
 
 	return instance, nil
@@ -320,11 +407,11 @@ func (i *Installer) installMultiContainer(
 
 // buildMultiContainerName builds the full container name for multi-container services
 func (i *Installer) buildMultiContainerName(instanceName, containerName string) string {
-	return fmt.Sprintf("doku-%s-%s", instanceName, containerName)
+	return fmt.Sprintf("%s-%s", docker.GenerateContainerName(instanceName), containerName)
 }
 
 // buildNetworkAliases creates network aliases for a container
-func (i *Installer) buildNetworkAliases(instanceName, containerName string, isPrimary bool) []string {
+func buildNetworkAliases(instanceName, containerName string, isPrimary bool) []string {
 	// Extract base service name (remove numeric suffix if present)
 	serviceName := instanceName
 	if strings.Contains(instanceName, "-") {
@@ -336,8 +423,8 @@ func (i *Installer) buildNetworkAliases(instanceName, containerName string, isPr
 	}
 
 	aliases := []string{
-		fmt.Sprintf("doku-%s-%s", instanceName, containerName), // Full doku name
-		fmt.Sprintf("%s-%s", serviceName, containerName),       // Service-container name (e.g., signoz-query-service)
+		fmt.Sprintf("%s-%s", docker.GenerateContainerName(instanceName), containerName), // Full doku name
+		fmt.Sprintf("%s-%s", serviceName, containerName),                                // Service-container name (e.g., signoz-query-service)
 		containerName, // Short name (for intra-service communication)
 	}
 
@@ -353,19 +440,17 @@ func (i *Installer) buildNetworkAliases(instanceName, containerName string, isPr
 }
 
 // generateMultiContainerLabels generates Docker labels for multi-container services
-func (i *Installer) generateMultiContainerLabels(instanceName, serviceName, containerName string, isPrimary bool, internal bool, port int) map[string]string {
-	labels := map[string]string{
-		"doku.managed":   "true",
+func (i *Installer) generateMultiContainerLabels(instanceName, serviceName, containerName string, isPrimary bool, internal bool, port int, domains []string) map[string]string {
+	labels := docker.MergeLabels(docker.InstanceLabels(instanceName), map[string]string{
 		"doku.service":   serviceName,
-		"doku.instance":  instanceName,
 		"doku.container": containerName,
 		"doku.primary":   fmt.Sprintf("%t", isPrimary),
 		"doku.multi":     "true",
-	}
+	})
 
 	if !internal && isPrimary && port > 0 {
 		labels["traefik.enable"] = "true"
-		labels["traefik.http.routers."+instanceName+".rule"] = fmt.Sprintf("Host(`%s.%s`)", instanceName, i.domain)
+		labels["traefik.http.routers."+instanceName+".rule"] = domain.HostRule(domain.Hostnames(instanceName, i.domains, domains))
 		labels["traefik.http.routers."+instanceName+".entrypoints"] = "web,websecure"
 		labels["traefik.http.services."+instanceName+".loadbalancer.server.port"] = fmt.Sprintf("%d", port)
 
@@ -412,11 +497,12 @@ func (i *Installer) createMultiContainerMounts(instanceName string, containerSpe
 			}
 		} else {
 			// Use named volumes for simple volume paths
-			volumeName := fmt.Sprintf("doku-%s-%s-%d", instanceName, containerSpec.Name, idx)
+			volumeName := fmt.Sprintf("%s-%s-%d", docker.GenerateContainerName(instanceName), containerSpec.Name, idx)
 			mounts = append(mounts, mount.Mount{
-				Type:   mount.TypeVolume,
-				Source: volumeName,
-				Target: vol,
+				Type:          mount.TypeVolume,
+				Source:        volumeName,
+				Target:        vol,
+				VolumeOptions: &mount.VolumeOptions{Labels: docker.InstanceLabels(instanceName)},
 			})
 		}
 	}
@@ -424,108 +510,69 @@ func (i *Installer) createMultiContainerMounts(instanceName string, containerSpe
 	return mounts
 }
 
-// startMultiContainerService starts containers in dependency order
-func (i *Installer) startMultiContainerService(instance *types.Instance, spec *types.ServiceSpec) error {
-	// Build internal dependency graph for containers
-	depGraph := make(map[string][]string)
-	for _, containerSpec := range spec.Containers {
-		// Filter to only internal dependencies (same service)
-		internalDeps := make([]string, 0)
-		for _, dep := range containerSpec.DependsOn {
-			// Check if this is an internal dependency (exists in our containers)
-			isInternal := false
-			for _, c := range spec.Containers {
-				if c.Name == dep {
-					isInternal = true
-					break
-				}
-			}
-			if isInternal {
-				internalDeps = append(internalDeps, dep)
-			}
-			// External dependencies are already installed by resolveDependencies
-		}
-		depGraph[containerSpec.Name] = internalDeps
-	}
-
-	// Topological sort for startup order
-	startOrder, err := topologicalSortContainers(depGraph, spec.Containers)
+// startMultiContainerService starts containers in dependency order.
+// Containers that don't depend on each other start concurrently; before a
+// container's dependents are started it must report healthy within healthTimeout.
+func (i *Installer) startMultiContainerService(instance *types.Instance, healthTimeout time.Duration) error {
+	levels, err := containerLevels(instance.Containers)
 	if err != nil {
 		return err
 	}
 
-	// Start containers in order
-	for _, containerName := range startOrder {
-		// Find container info
-		var containerInfo *types.ContainerInfo
-		for idx := range instance.Containers {
-			if instance.Containers[idx].Name == containerName {
-				containerInfo = &instance.Containers[idx]
-				break
-			}
-		}
-
-		if containerInfo == nil {
-			continue
-		}
-
-		fmt.Printf("Starting %s...\n", containerName)
-
-		if err := i.dockerClient.ContainerStart(containerInfo.ContainerID); err != nil {
-			return fmt.Errorf("failed to start %s: %w", containerName, err)
+	// Containers that others depend on must be healthy before moving on
+	hasDependents := make(map[string]bool)
+	for _, c := range instance.Containers {
+		for _, dep := range c.DependsOn {
+			hasDependents[dep] = true
 		}
-
-		containerInfo.Status = "running"
-		color.Green("✓ %s started", containerName)
-
-		// Brief pause to let container initialize
-		time.Sleep(2 * time.Second)
 	}
 
-	return nil
-}
+	for _, level := range levels {
+		err := forEachConcurrently(level, func(idx int) error {
+			containerInfo := &instance.Containers[idx]
 
-// topologicalSortContainers sorts containers by dependencies for startup order
-func topologicalSortContainers(graph map[string][]string, containers []types.ContainerSpec) ([]string, error) {
-	var result []string
-	visited := make(map[string]bool)
-	visiting := make(map[string]bool)
+			fmt.Printf("Starting %s...\n", containerInfo.Name)
+			if err := i.dockerClient.ContainerStart(containerInfo.ContainerID); err != nil {
+				return fmt.Errorf("failed to start %s: %w", containerInfo.Name, err)
+			}
 
-	var visit func(string) error
-	visit = func(node string) error {
-		if visiting[node] {
-			return fmt.Errorf("circular dependency detected at: %s", node)
-		}
-		if visited[node] {
+			containerInfo.Status = "running"
+			color.Green("✓ %s started", containerInfo.Name)
 			return nil
+		})
+		if err != nil {
+			return err
 		}
 
-		visiting[node] = true
-
-		// Visit dependencies first
-		for _, dep := range graph[node] {
-			if err := visit(dep); err != nil {
+		// Wait one at a time so the progress spinners don't interleave
+		for _, idx := range level {
+			containerInfo := &instance.Containers[idx]
+			if !hasDependents[containerInfo.Name] {
+				continue
+			}
+			if err := i.waitForHealthy(containerInfo.ContainerID, containerInfo.Name, healthTimeout); err != nil {
 				return err
 			}
 		}
-
-		visiting[node] = false
-		visited[node] = true
-		result = append(result, node)
-
-		return nil
 	}
 
-	// Visit all containers
-	for _, container := range containers {
-		if !visited[container.Name] {
-			if err := visit(container.Name); err != nil {
-				return nil, err
+	return nil
+}
+
+// internalDependencies returns the dependencies of a container on other
+// containers of the same service. External dependencies are installed
+// separately by resolveDependencies.
+func internalDependencies(spec *types.ServiceSpec, containerSpec types.ContainerSpec) []string {
+	var deps []string
+	for _, dep := range containerSpec.DependsOn {
+		for _, c := range spec.Containers {
+			if c.Name == dep {
+				deps = append(deps, dep)
+				break
 			}
 		}
 	}
-
-	return result, nil
+	return deps
 }
 
 // runInitContainers runs init containers in dependency order
@@ -558,7 +605,7 @@ func (i *Installer) runInitContainers(spec *types.ServiceSpec, instanceName stri
 		}
 
 		// Run container with --rm flag (auto-remove after completion)
-		containerName := fmt.Sprintf("doku-%s-init-%s", instanceName, initContainer.Name)
+		containerName := fmt.Sprintf("%s-init-%s", docker.GenerateContainerName(instanceName), initContainer.Name)
 
 		// Check if image exists locally first
 		imageExists, err := i.dockerClient.ImageExists(initContainer.Image)
@@ -582,7 +629,7 @@ func (i *Installer) runInitContainers(spec *types.ServiceSpec, instanceName stri
 			containerName,
 			cmd,
 			env,
-			"doku-network",
+			docker.NetworkName(),
 			true, // auto-remove after completion
 		)
 		if err != nil {
@@ -667,7 +714,7 @@ func (i *Installer) cleanupMultiContainerInstall(instance *types.Instance) {
 			fmt.Printf("Removing %s...\n", container.Name)
 
 			// Disconnect from network
-			networkMgr.DisconnectContainer("doku-network", container.FullName, true)
+			networkMgr.DisconnectContainer(docker.NetworkName(), container.FullName, true)
 
 			// Remove container
 			if err := i.dockerClient.ContainerRemove(container.FullName, true); err != nil {
@@ -0,0 +1,30 @@
+package service
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/dokulabs/doku-cli/pkg/types"
+)
+
+// SetLAN exposes an instance to the other devices of the network, or
+// stops exposing it with a nil lan, recreating its containers with the Host
+// rules of its LAN hostnames. Stopped instances are stopped again
+// afterwards.
+func (m *Manager) SetLAN(instanceName string, lan *types.LANExposure) error {
+	instance, err := m.configMgr.GetInstance(instanceName)
+	if err != nil {
+		return fmt.Errorf("instance not found: %w", err)
+	}
+	if !IsRouted(instance) {
+		return fmt.Errorf("'%s' isn't routed through Traefik", instanceName)
+	}
+
+	// UpdateHostRules builds the rules from the saved instance
+	instance.LAN = lan
+	instance.UpdatedAt = time.Now()
+	if err := m.configMgr.UpdateInstance(instanceName, instance); err != nil {
+		return err
+	}
+	return m.UpdateHostRules(instanceName)
+}
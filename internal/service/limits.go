@@ -0,0 +1,75 @@
+package service
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/dokulabs/doku-cli/pkg/types"
+)
+
+// EffectiveLimits returns the memory and CPU limits an instance's containers
+// run with: its own, else the defaults, and none while limits are disabled
+func EffectiveLimits(limits types.LimitsConfig, own types.ResourceConfig) (memory, cpu string) {
+	if limits.Disabled {
+		return "", ""
+	}
+	memory, cpu = own.MemoryLimit, own.CPULimit
+	if memory == "" {
+		memory = limits.Memory
+	}
+	if cpu == "" {
+		cpu = limits.CPU
+	}
+	return memory, cpu
+}
+
+// SetLimits changes the memory and CPU limits of an instance, empty for the
+// defaults, and applies them to its containers without recreating them
+func (m *Manager) SetLimits(instanceName, memory, cpu string) error {
+	instance, err := m.configMgr.GetInstance(instanceName)
+	if err != nil {
+		return fmt.Errorf("instance not found: %w", err)
+	}
+
+	instance.Resources.MemoryLimit = memory
+	instance.Resources.CPULimit = cpu
+	if err := m.ApplyLimits(instance); err != nil {
+		return err
+	}
+
+	instance.UpdatedAt = time.Now()
+	return m.configMgr.UpdateInstance(instanceName, instance)
+}
+
+// ApplyLimits updates the containers of an instance, its replicas and those
+// of a multi-container service included, to the limits it should run with
+func (m *Manager) ApplyLimits(instance *types.Instance) error {
+	cfg, err := m.configMgr.Get()
+	if err != nil {
+		return fmt.Errorf("failed to get config: %w", err)
+	}
+	memory, cpu := EffectiveLimits(cfg.Limits, instance.Resources)
+
+	if instance.IsMultiContainer {
+		for _, container := range instance.Containers {
+			if err := m.dockerClient.UpdateLimits(container.ContainerID, memory, cpu); err != nil {
+				return fmt.Errorf("failed to limit container %s: %w", container.Name, err)
+			}
+		}
+		return nil
+	}
+
+	if err := m.dockerClient.UpdateLimits(instance.ContainerName, memory, cpu); err != nil {
+		return err
+	}
+	replicas, err := m.dockerClient.ListReplicas(instance.ContainerName)
+	if err != nil {
+		return err
+	}
+	for _, replica := range replicas {
+		if err := m.dockerClient.UpdateLimits(replica.ID, memory, cpu); err != nil {
+			return fmt.Errorf("failed to limit replica %s: %w", replica.ID[:12], err)
+		}
+	}
+	return nil
+}
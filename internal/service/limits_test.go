@@ -0,0 +1,32 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/dokulabs/doku-cli/pkg/types"
+)
+
+func TestEffectiveLimits(t *testing.T) {
+	defaults := types.LimitsConfig{Memory: "2g", CPU: "2"}
+	tests := []struct {
+		name       string
+		limits     types.LimitsConfig
+		own        types.ResourceConfig
+		wantMemory string
+		wantCPU    string
+	}{
+		{"own", defaults, types.ResourceConfig{MemoryLimit: "4g", CPULimit: "1"}, "4g", "1"},
+		{"defaults", defaults, types.ResourceConfig{}, "2g", "2"},
+		{"mixed", defaults, types.ResourceConfig{MemoryLimit: "512m"}, "512m", "2"},
+		{"no defaults", types.LimitsConfig{}, types.ResourceConfig{CPULimit: "0.5"}, "", "0.5"},
+		{"disabled", types.LimitsConfig{Memory: "2g", Disabled: true}, types.ResourceConfig{MemoryLimit: "4g"}, "", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			memory, cpu := EffectiveLimits(tt.limits, tt.own)
+			if memory != tt.wantMemory || cpu != tt.wantCPU {
+				t.Errorf("EffectiveLimits() = %q, %q, want %q, %q", memory, cpu, tt.wantMemory, tt.wantCPU)
+			}
+		})
+	}
+}
@@ -0,0 +1,59 @@
+package service
+
+import (
+	"fmt"
+
+	"github.com/dokulabs/doku-cli/internal/monitoring"
+	"github.com/dokulabs/doku-cli/pkg/types"
+)
+
+// logRotation returns the rotation of an instance's container logs: its
+// own, or else the preferences'
+func (m *Manager) logRotation(instance *types.Instance) types.LogRotation {
+	var preferences types.LogRotation
+	if cfg, err := m.configMgr.Get(); err == nil {
+		preferences = cfg.Preferences.Logs
+	}
+	return monitoring.ResolveLogRotation(preferences, instance.Logs)
+}
+
+// TruncateLogs empties the logs of an instance's containers, its replicas'
+// included, or of only the named container of a multi-container instance.
+// It returns the number of containers whose logs were truncated.
+func (m *Manager) TruncateLogs(instanceName, containerName string) (int, error) {
+	instance, err := m.configMgr.GetInstance(instanceName)
+	if err != nil {
+		return 0, fmt.Errorf("instance not found: %w", err)
+	}
+
+	var containers []string
+	if instance.IsMultiContainer {
+		for _, c := range instance.Containers {
+			if containerName == "" || c.Name == containerName {
+				containers = append(containers, c.FullName)
+			}
+		}
+		if len(containers) == 0 {
+			return 0, fmt.Errorf("container '%s' not found in service '%s'", containerName, instanceName)
+		}
+	} else {
+		if containerName != "" {
+			return 0, fmt.Errorf("'%s' is a single-container service: leave out --container", instanceName)
+		}
+		containers = append(containers, instance.ContainerName)
+		replicas, err := m.dockerClient.ListReplicas(instance.ContainerName)
+		if err != nil {
+			return 0, err
+		}
+		for _, replica := range replicas {
+			containers = append(containers, replica.ID)
+		}
+	}
+
+	for n, c := range containers {
+		if err := m.dockerClient.TruncateLogs(c); err != nil {
+			return n, err
+		}
+	}
+	return len(containers), nil
+}
@@ -15,6 +15,8 @@ import (
 	"github.com/dokulabs/doku-cli/internal/config"
 	"github.com/dokulabs/doku-cli/internal/docker"
 	"github.com/dokulabs/doku-cli/internal/envfile"
+	"github.com/dokulabs/doku-cli/internal/events"
+	"github.com/dokulabs/doku-cli/internal/monitoring"
 	"github.com/dokulabs/doku-cli/pkg/types"
 	"github.com/fatih/color"
 )
@@ -59,6 +61,9 @@ func (m *Manager) List() ([]*types.Instance, error) {
 			URL:           project.URL,
 			CreatedAt:     project.CreatedAt,
 			Environment:   project.Environment,
+			// Compose-based projects run several containers
+			IsMultiContainer: project.IsCompose(),
+			Containers:       project.Containers,
 			Network: types.NetworkConfig{
 				InternalPort: project.Port,
 			},
@@ -102,6 +107,9 @@ func (m *Manager) Get(instanceName string) (*types.Instance, error) {
 		URL:           project.URL,
 		CreatedAt:     project.CreatedAt,
 		Environment:   project.Environment,
+		// Compose-based projects run several containers
+		IsMultiContainer: project.IsCompose(),
+		Containers:       project.Containers,
 		Network: types.NetworkConfig{
 			InternalPort: project.Port,
 		},
@@ -122,24 +130,41 @@ func (m *Manager) Start(instanceName string) error {
 
 	// Check if already running
 	if instance.Status == types.StatusRunning {
+		if err := m.setDesiredState(instance, types.StatusRunning); err != nil {
+			return err
+		}
 		return fmt.Errorf("%w: %s", types.ErrAlreadyRunning, instanceName)
 	}
 
 	// Handle multi-container services
 	if instance.IsMultiContainer {
-		return m.startMultiContainerService(instance)
+		if err := m.startMultiContainerService(instance); err != nil {
+			return err
+		}
+		events.Emit(events.New(events.TypeStart, instanceName, "started"))
+		return nil
 	}
 
 	// Start single container
 	if err := m.dockerClient.ContainerStart(instance.ContainerName); err != nil {
 		return fmt.Errorf("failed to start container: %w", err)
 	}
+	if instance.ReplicaCount() > 1 {
+		if err := m.dockerClient.StartReplicas(instance.ContainerName); err != nil {
+			color.Yellow("⚠️  Failed to start some replicas of %s: %v", instanceName, err)
+		}
+	}
 
 	// Update status
-	instance.Status = types.StatusRunning
-	instance.UpdatedAt = time.Now()
-
-	return m.configMgr.UpdateInstance(instanceName, instance)
+	if err := m.configMgr.EditInstance(instanceName, func(instance *types.Instance) {
+		instance.Status = types.StatusRunning
+		instance.DesiredState = types.StatusRunning
+		instance.UpdatedAt = time.Now()
+	}); err != nil {
+		return err
+	}
+	events.Emit(events.New(events.TypeStart, instanceName, "started"))
+	return nil
 }
 
 // Stop stops a running service instance
@@ -149,14 +174,21 @@ func (m *Manager) Stop(instanceName string) error {
 		return fmt.Errorf("instance not found: %w", err)
 	}
 
-	// Check if already stopped
+	// Check if already stopped, e.g. after a crash; it should stay stopped
 	if instance.Status == types.StatusStopped {
+		if err := m.setDesiredState(instance, types.StatusStopped); err != nil {
+			return err
+		}
 		return fmt.Errorf("%w: %s", types.ErrAlreadyStopped, instanceName)
 	}
 
 	// Handle multi-container services
 	if instance.IsMultiContainer {
-		return m.stopMultiContainerService(instance)
+		if err := m.stopMultiContainerService(instance); err != nil {
+			return err
+		}
+		events.Emit(events.New(events.TypeStop, instanceName, "stopped"))
+		return nil
 	}
 
 	// Stop single container
@@ -164,12 +196,34 @@ func (m *Manager) Stop(instanceName string) error {
 	if err := m.dockerClient.ContainerStop(instance.ContainerName, &timeout); err != nil {
 		return fmt.Errorf("failed to stop container: %w", err)
 	}
+	if instance.ReplicaCount() > 1 {
+		if err := m.dockerClient.StopReplicas(instance.ContainerName); err != nil {
+			color.Yellow("⚠️  Failed to stop some replicas of %s: %v", instanceName, err)
+		}
+	}
 
 	// Update status
-	instance.Status = types.StatusStopped
-	instance.UpdatedAt = time.Now()
+	if err := m.configMgr.EditInstance(instanceName, func(instance *types.Instance) {
+		instance.Status = types.StatusStopped
+		instance.DesiredState = types.StatusStopped
+		instance.UpdatedAt = time.Now()
+	}); err != nil {
+		return err
+	}
+	events.Emit(events.New(events.TypeStop, instanceName, "stopped"))
+	return nil
+}
 
-	return m.configMgr.UpdateInstance(instanceName, instance)
+// setDesiredState records whether an instance should be running, if that
+// changed
+func (m *Manager) setDesiredState(instance *types.Instance, state types.ServiceStatus) error {
+	if instance.DesiredState == state {
+		return nil
+	}
+	instance.DesiredState = state
+	return m.configMgr.EditInstance(instance.Name, func(instance *types.Instance) {
+		instance.DesiredState = state
+	})
 }
 
 // Restart restarts a service instance
@@ -200,23 +254,33 @@ func (m *Manager) RestartWithInit(instanceName string, runInit bool, catalogMgr
 		return fmt.Errorf("failed to restart container: %w", err)
 	}
 
-	// Update timestamp
-	instance.UpdatedAt = time.Now()
-
-	return m.configMgr.UpdateInstance(instanceName, instance)
+	// A restarted instance should keep running
+	return m.configMgr.EditInstance(instanceName, func(instance *types.Instance) {
+		instance.Status = types.StatusRunning
+		instance.DesiredState = types.StatusRunning
+		instance.UpdatedAt = time.Now()
+	})
 }
 
 // Recreate recreates a service container to apply configuration changes (like environment variables)
-// This stops, removes, and recreates the container with environment from the env file
+// This stops, removes, and recreates the container with environment from the env file.
+// Multi-container services have each of their containers recreated.
 func (m *Manager) Recreate(instanceName string) error {
+	return m.RecreateWithLabels(instanceName, nil)
+}
+
+// RecreateWithLabels recreates a service like Recreate, letting relabel
+// modify the labels of each container first (e.g. to rewrite Traefik rules).
+// A nil relabel keeps the labels unchanged.
+func (m *Manager) RecreateWithLabels(instanceName string, relabel func(labels map[string]string)) error {
 	instance, err := m.configMgr.GetInstance(instanceName)
 	if err != nil {
 		return fmt.Errorf("instance not found: %w", err)
 	}
 
-	// Multi-container services not supported yet
+	// Handle multi-container services
 	if instance.IsMultiContainer {
-		return fmt.Errorf("recreation not supported for multi-container services yet")
+		return m.recreateMultiContainerService(instance, relabel)
 	}
 
 	// Get container info to preserve configuration
@@ -225,6 +289,20 @@ func (m *Manager) Recreate(instanceName string) error {
 		return fmt.Errorf("failed to inspect container: %w", err)
 	}
 
+	if relabel != nil && containerInfo.Config != nil {
+		if containerInfo.Config.Labels == nil {
+			containerInfo.Config.Labels = make(map[string]string)
+		}
+		relabel(containerInfo.Config.Labels)
+	}
+
+	// Keep the image the instance was installed with even if its tag moved
+	image, err := pinnedImage(m.dockerClient, instance.ImageDigest, containerInfo.Config.Image)
+	if err != nil {
+		return err
+	}
+	containerInfo.Config.Image = image
+
 	// Load environment from env file (primary source)
 	envMgr := envfile.NewManager(m.configMgr.GetDokuDir())
 	envPath := envMgr.GetServiceEnvPath(instanceName, "")
@@ -233,6 +311,10 @@ func (m *Manager) Recreate(instanceName string) error {
 		// Fall back to instance.Environment for backward compatibility
 		env = instance.Environment
 	}
+	env, err = resolveEnvRefs(m.configMgr, env)
+	if err != nil {
+		return err
+	}
 
 	// Build environment array
 	if len(env) > 0 {
@@ -251,7 +333,7 @@ func (m *Manager) Recreate(instanceName string) error {
 
 	// Disconnect from network
 	networkMgr := docker.NewNetworkManager(m.dockerClient)
-	if err := networkMgr.DisconnectContainer("doku-network", instance.ContainerName, true); err != nil {
+	if err := networkMgr.DisconnectContainer(docker.NetworkName(), instance.ContainerName, true); err != nil {
 		fmt.Printf("Warning: failed to disconnect from network: %v\n", err)
 	}
 
@@ -270,6 +352,59 @@ func (m *Manager) Recreate(instanceName string) error {
 	return m.configMgr.UpdateInstance(instanceName, instance)
 }
 
+// PendingEnvChanges returns the keys whose value in env, the service's env
+// file, differs from the running container's environment once references
+// to other instances are resolved. Those changes only take effect once the
+// container is recreated. A key missing from env counts as changed while
+// the container still has it. Multi-container services are not compared
+// and report every key.
+func (m *Manager) PendingEnvChanges(instanceName string, env map[string]string, keys []string) ([]string, error) {
+	instance, err := m.configMgr.GetInstance(instanceName)
+	if err != nil {
+		return nil, fmt.Errorf("instance not found: %w", err)
+	}
+	if instance.IsMultiContainer {
+		return keys, nil
+	}
+
+	containerInfo, err := m.dockerClient.ContainerInspect(instance.ContainerName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to inspect container: %w", err)
+	}
+
+	env, err = resolveEnvRefs(m.configMgr, env)
+	if err != nil {
+		return nil, err
+	}
+
+	var containerEnv []string
+	if containerInfo.Config != nil {
+		containerEnv = containerInfo.Config.Env
+	}
+	return envDrift(env, containerEnv, keys), nil
+}
+
+// envDrift returns the keys whose value in env differs from containerEnv,
+// a container's KEY=VALUE list
+func envDrift(env map[string]string, containerEnv []string, keys []string) []string {
+	current := make(map[string]string, len(containerEnv))
+	for _, kv := range containerEnv {
+		if key, value, ok := strings.Cut(kv, "="); ok {
+			current[key] = value
+		}
+	}
+
+	var drift []string
+	for _, key := range keys {
+		want, inFile := env[key]
+		have, inContainer := current[key]
+		if inFile != inContainer || want != have {
+			drift = append(drift, key)
+		}
+	}
+	return drift
+}
+
 // RecreateWithImage recreates a container with a new image (for upgrades)
 func (m *Manager) RecreateWithImage(instanceName string, newImage string) error {
 	instance, err := m.configMgr.GetInstance(instanceName)
@@ -288,6 +423,13 @@ func (m *Manager) RecreateWithImage(instanceName string, newImage string) error
 		return fmt.Errorf("failed to inspect container: %w", err)
 	}
 
+	// Record the current image and environment before touching the container,
+	// so a failed upgrade can be rolled back even if recreation fails midway
+	instance.Previous = m.snapshotInstance(instance, currentImage(instance, containerInfo.Config))
+	if err := m.configMgr.UpdateInstance(instanceName, instance); err != nil {
+		return fmt.Errorf("failed to record rollback snapshot: %w", err)
+	}
+
 	// Update the image
 	containerInfo.Config.Image = newImage
 
@@ -299,6 +441,10 @@ func (m *Manager) RecreateWithImage(instanceName string, newImage string) error
 		// Fall back to instance.Environment for backward compatibility
 		env = instance.Environment
 	}
+	env, err = resolveEnvRefs(m.configMgr, env)
+	if err != nil {
+		return err
+	}
 
 	// Build environment array
 	if len(env) > 0 {
@@ -318,7 +464,7 @@ func (m *Manager) RecreateWithImage(instanceName string, newImage string) error
 
 	// Disconnect from network
 	networkMgr := docker.NewNetworkManager(m.dockerClient)
-	if err := networkMgr.DisconnectContainer("doku-network", instance.ContainerName, true); err != nil {
+	if err := networkMgr.DisconnectContainer(docker.NetworkName(), instance.ContainerName, true); err != nil {
 		fmt.Printf("Warning: failed to disconnect from network: %v\n", err)
 	}
 
@@ -333,10 +479,105 @@ func (m *Manager) RecreateWithImage(instanceName string, newImage string) error
 	}
 
 	// Update config
+	instance.ImageDigest = imageDigest(m.dockerClient, newImage)
+	instance.UpdatedAt = time.Now()
+	return m.configMgr.UpdateInstance(instanceName, instance)
+}
+
+// Rollback restores the image, version and environment recorded before the
+// last image change. The replaced state becomes the new snapshot, so running
+// rollback again returns to the version that was rolled back from.
+func (m *Manager) Rollback(instanceName string) error {
+	instance, err := m.configMgr.GetInstance(instanceName)
+	if err != nil {
+		return fmt.Errorf("instance not found: %w", err)
+	}
+
+	if instance.Previous == nil {
+		return fmt.Errorf("%w for '%s'", types.ErrNoRollback, instanceName)
+	}
+
+	// Multi-container services not supported yet
+	if instance.IsMultiContainer {
+		return fmt.Errorf("rollback not supported for multi-container services yet")
+	}
+
+	previous := instance.Previous
+
+	// Get container info to preserve configuration
+	containerInfo, err := m.dockerClient.ContainerInspect(instance.ContainerName)
+	if err != nil {
+		return fmt.Errorf("failed to inspect container: %w", err)
+	}
+
+	// Capture the state being replaced before restoring the env file
+	current := m.snapshotInstance(instance, currentImage(instance, containerInfo.Config))
+
+	// Restore the previous environment to the env file (primary source)
+	if len(previous.Environment) > 0 {
+		envMgr := envfile.NewManager(m.configMgr.GetDokuDir())
+		envPath := envMgr.GetServiceEnvPath(instanceName, "")
+		if err := envMgr.Save(envPath, previous.Environment); err != nil {
+			return fmt.Errorf("failed to restore environment file: %w", err)
+		}
+		containerInfo.Config.Env = envfile.EnvMapToSlice(previous.Environment)
+	}
+
+	containerInfo.Config.Image = m.dockerClient.LocalImage(previous.Image)
+
+	// Stop the container if running
+	timeout := 10
+	if err := m.dockerClient.ContainerStop(instance.ContainerName, &timeout); err != nil {
+		// Ignore error if container is already stopped
+		fmt.Printf("Note: Container may already be stopped: %v\n", err)
+	}
+
+	// Disconnect from network
+	networkMgr := docker.NewNetworkManager(m.dockerClient)
+	if err := networkMgr.DisconnectContainer(docker.NetworkName(), instance.ContainerName, true); err != nil {
+		fmt.Printf("Warning: failed to disconnect from network: %v\n", err)
+	}
+
+	// Remove the container (but preserve volumes)
+	if err := m.dockerClient.ContainerRemove(instance.ContainerName, false); err != nil {
+		return fmt.Errorf("failed to remove container: %w", err)
+	}
+
+	// Recreate the container with the previous image
+	if err := m.recreateContainer(instance, &containerInfo); err != nil {
+		return fmt.Errorf("failed to recreate container: %w", err)
+	}
+
+	// Update config
+	instance.ImageDigest = imageDigest(m.dockerClient, containerInfo.Config.Image)
+	instance.Version = previous.Version
+	instance.Previous = current
 	instance.UpdatedAt = time.Now()
 	return m.configMgr.UpdateInstance(instanceName, instance)
 }
 
+// snapshotInstance captures the image, version and environment of an instance
+func (m *Manager) snapshotInstance(instance *types.Instance, image string) *types.InstanceSnapshot {
+	envMgr := envfile.NewManager(m.configMgr.GetDokuDir())
+	env, err := envMgr.Load(envMgr.GetServiceEnvPath(instance.Name, ""))
+	if err != nil {
+		// Fall back to instance.Environment for backward compatibility
+		env = instance.Environment
+	}
+
+	snapshotEnv := make(map[string]string, len(env))
+	for key, value := range env {
+		snapshotEnv[key] = value
+	}
+
+	return &types.InstanceSnapshot{
+		Image:       image,
+		Version:     instance.Version,
+		Environment: snapshotEnv,
+		CapturedAt:  time.Now(),
+	}
+}
+
 // RestartWithPort restarts a service instance with a new host port mapping
 // This requires recreating the container since port mappings cannot be changed on existing containers
 func (m *Manager) RestartWithPort(instanceName string, newPort int) error {
@@ -364,7 +605,7 @@ func (m *Manager) RestartWithPort(instanceName string, newPort int) error {
 
 	// Disconnect from network
 	networkMgr := docker.NewNetworkManager(m.dockerClient)
-	if err := networkMgr.DisconnectContainer("doku-network", instance.ContainerName, true); err != nil {
+	if err := networkMgr.DisconnectContainer(docker.NetworkName(), instance.ContainerName, true); err != nil {
 		fmt.Printf("Warning: failed to disconnect from network: %v\n", err)
 	}
 
@@ -399,7 +640,11 @@ func (m *Manager) Remove(instanceName string, force bool, removeVolumes bool) er
 
 	// Handle multi-container services
 	if instance.IsMultiContainer {
-		return m.removeMultiContainerService(instance, force, removeVolumes)
+		if err := m.removeMultiContainerService(instance, force, removeVolumes); err != nil {
+			return err
+		}
+		events.Emit(events.New(events.TypeRemove, instanceName, "removed"))
+		return nil
 	}
 
 	// Check if container exists
@@ -429,7 +674,7 @@ func (m *Manager) Remove(instanceName string, force bool, removeVolumes bool) er
 
 		// Disconnect from network
 		networkMgr := docker.NewNetworkManager(m.dockerClient)
-		if err := networkMgr.DisconnectContainer("doku-network", instance.ContainerName, force); err != nil {
+		if err := networkMgr.DisconnectContainer(docker.NetworkName(), instance.ContainerName, force); err != nil {
 			// Log error but continue
 			fmt.Printf("Warning: failed to disconnect from network: %v\n", err)
 		}
@@ -439,6 +684,9 @@ func (m *Manager) Remove(instanceName string, force bool, removeVolumes bool) er
 			fmt.Printf("Warning: failed to remove container: %v\n", err)
 			// Continue to clean up config even if container removal fails
 		}
+		if err := m.dockerClient.RemoveReplicas(instance.ContainerName); err != nil {
+			fmt.Printf("Warning: failed to remove replicas: %v\n", err)
+		}
 
 		// Remove associated volumes only if user agreed
 		if removeVolumes {
@@ -452,7 +700,11 @@ func (m *Manager) Remove(instanceName string, force bool, removeVolumes bool) er
 	if isCustomProject {
 		return m.configMgr.RemoveProject(instanceName)
 	}
-	return m.configMgr.RemoveInstance(instanceName)
+	if err := m.configMgr.RemoveInstance(instanceName); err != nil {
+		return err
+	}
+	events.Emit(events.New(events.TypeRemove, instanceName, "removed"))
+	return nil
 }
 
 // GetLogs retrieves logs from a service instance
@@ -557,23 +809,17 @@ func (m *Manager) RefreshStatus() error {
 	return nil
 }
 
-// removeVolumes removes volumes associated with an instance
+// removeVolumes removes the volumes of an instance, found by label so they
+// are removed even when the container is already gone
 func (m *Manager) removeVolumes(instance *types.Instance) error {
-	// Get volumes for this instance
-	containerInfo, err := m.dockerClient.ContainerInspect(instance.ContainerName)
+	volumes, err := m.dockerClient.ListInstanceVolumes(context.Background(), instance.Name)
 	if err != nil {
 		return err
 	}
 
-	// Remove named volumes
-	for _, mount := range containerInfo.Mounts {
-		if mount.Type == "volume" {
-			// Only remove volumes managed by doku (starting with "doku-")
-			if len(mount.Name) > 5 && mount.Name[:5] == "doku-" {
-				if err := m.dockerClient.VolumeRemove(mount.Name, false); err != nil {
-					fmt.Printf("Warning: failed to remove volume %s: %v\n", mount.Name, err)
-				}
-			}
+	for _, vol := range volumes {
+		if err := m.dockerClient.VolumeRemove(vol.Name, false); err != nil {
+			fmt.Printf("Warning: failed to remove volume %s: %v\n", vol.Name, err)
 		}
 	}
 
@@ -603,6 +849,18 @@ func (m *Manager) GetConnectionInfo(instanceName string) (*types.ConnectionInfo,
 		info.Env["SERVICE_PORT"] = fmt.Sprintf("%d", instance.Network.InternalPort)
 	}
 
+	// Credentials may be generated or changed later, so read the env file
+	envMgr := envfile.NewManager(m.configMgr.GetDokuDir())
+	fileEnv, _ := envMgr.Load(envMgr.GetServiceEnvPath(instanceName, ""))
+	env := envfile.MergeEnv(instance.Environment, fileEnv)
+	if user, password, ok := credentials(env); ok {
+		info.User = user
+		info.Password = password
+	}
+	if database, ok := instanceField(instance, env, "DATABASE"); ok {
+		info.Database = database
+	}
+
 	// Add service-specific connection env vars
 	for k, v := range instance.Environment {
 		// Include common connection-related env vars
@@ -628,46 +886,69 @@ func containsAny(s string, substrs []string) bool {
 
 // Multi-Container Service Methods
 
-// startMultiContainerService starts all containers in a multi-container service
+// startMultiContainerService starts all containers in a multi-container
+// service. Containers that don't depend on each other start concurrently.
 func (m *Manager) startMultiContainerService(instance *types.Instance) error {
-	for i := range instance.Containers {
-		container := &instance.Containers[i]
+	levels, err := instanceContainerLevels(instance)
+	if err != nil {
+		return err
+	}
 
-		if err := m.dockerClient.ContainerStart(container.ContainerID); err != nil {
-			return fmt.Errorf("failed to start container %s: %w", container.Name, err)
-		}
+	for _, level := range levels {
+		err := forEachConcurrently(level, func(idx int) error {
+			container := &instance.Containers[idx]
 
-		container.Status = "running"
-		fmt.Printf("Started container: %s\n", container.Name)
+			if err := m.dockerClient.ContainerStart(container.ContainerID); err != nil {
+				return fmt.Errorf("failed to start container %s: %w", container.Name, err)
+			}
 
-		// Brief pause between containers
-		time.Sleep(time.Second)
+			container.Status = "running"
+			fmt.Printf("Started container: %s\n", container.Name)
+			return nil
+		})
+		if err != nil {
+			return err
+		}
 	}
 
 	// Update overall instance status
 	instance.Status = types.StatusRunning
+	instance.DesiredState = types.StatusRunning
 	instance.UpdatedAt = time.Now()
 
 	return m.configMgr.UpdateInstance(instance.Name, instance)
 }
 
 // stopMultiContainerService stops all containers in a multi-container service
+// in reverse dependency order. Containers that don't depend on each other
+// stop concurrently.
 func (m *Manager) stopMultiContainerService(instance *types.Instance) error {
-	// Stop containers in reverse order
-	for i := len(instance.Containers) - 1; i >= 0; i-- {
-		container := &instance.Containers[i]
+	levels, err := instanceContainerLevels(instance)
+	if err != nil {
+		return err
+	}
 
-		timeout := 10
-		if err := m.dockerClient.ContainerStop(container.ContainerID, &timeout); err != nil {
-			return fmt.Errorf("failed to stop container %s: %w", container.Name, err)
-		}
+	for l := len(levels) - 1; l >= 0; l-- {
+		err := forEachConcurrently(levels[l], func(idx int) error {
+			container := &instance.Containers[idx]
+
+			timeout := 10
+			if err := m.dockerClient.ContainerStop(container.ContainerID, &timeout); err != nil {
+				return fmt.Errorf("failed to stop container %s: %w", container.Name, err)
+			}
 
-		container.Status = "stopped"
-		fmt.Printf("Stopped container: %s\n", container.Name)
+			container.Status = "stopped"
+			fmt.Printf("Stopped container: %s\n", container.Name)
+			return nil
+		})
+		if err != nil {
+			return err
+		}
 	}
 
 	// Update overall instance status
 	instance.Status = types.StatusStopped
+	instance.DesiredState = types.StatusStopped
 	instance.UpdatedAt = time.Now()
 
 	return m.configMgr.UpdateInstance(instance.Name, instance)
@@ -687,6 +968,7 @@ func (m *Manager) restartMultiContainerServiceWithInit(instance *types.Instance,
 		if err != nil {
 			return fmt.Errorf("failed to get service spec: %w", err)
 		}
+		spec = spec.ForArch(m.dockerClient.Arch())
 
 		if len(spec.InitContainers) > 0 {
 			if err := m.runInitContainers(spec, instance.Name); err != nil {
@@ -718,58 +1000,207 @@ func (m *Manager) restartMultiContainerServiceWithInit(instance *types.Instance,
 	return m.configMgr.UpdateInstance(instance.Name, instance)
 }
 
-// removeMultiContainerService removes all containers in a multi-container service
-func (m *Manager) removeMultiContainerService(instance *types.Instance, force bool, removeVolumes bool) error {
-	networkMgr := docker.NewNetworkManager(m.dockerClient)
-
-	// Remove containers in reverse order
-	for i := len(instance.Containers) - 1; i >= 0; i-- {
-		container := &instance.Containers[i]
+// recreateMultiContainerService recreates every container of a multi-container
+// service with the environment from its env file, preserving volumes, ports
+// and network aliases. relabel, if set, may modify each container's labels.
+func (m *Manager) recreateMultiContainerService(instance *types.Instance, relabel func(labels map[string]string)) error {
+	envMgr := envfile.NewManager(m.configMgr.GetDokuDir())
 
-		// Check if container exists
-		containerExists, err := m.dockerClient.ContainerExists(container.ContainerID)
+	// Inspect everything and resolve the environment up front, so a missing
+	// container or a broken reference aborts before anything is removed
+	inspected := make([]dockerTypes.ContainerJSON, len(instance.Containers))
+	envs := make([]map[string]string, len(instance.Containers))
+	for i, c := range instance.Containers {
+		info, err := m.dockerClient.ContainerInspect(c.FullName)
+		if err != nil {
+			return fmt.Errorf("failed to inspect container %s: %w", c.Name, err)
+		}
+		// Keep the image the container was installed with even if its tag moved
+		image, err := pinnedImage(m.dockerClient, c.ImageDigest, info.Config.Image)
 		if err != nil {
-			fmt.Printf("Warning: failed to check if container %s exists: %v\n", container.Name, err)
+			return fmt.Errorf("%s: %w", c.Name, err)
 		}
+		info.Config.Image = image
+		inspected[i] = info
 
-		if !containerExists {
-			fmt.Printf("⚠️  Container %s does not exist (may have been removed manually)\n", container.Name)
-			continue
+		// Load environment from env file (primary source)
+		if env, err := envMgr.Load(envMgr.GetServiceEnvPath(instance.Name, c.Name)); err == nil && len(env) > 0 {
+			if envs[i], err = resolveEnvRefs(m.configMgr, env); err != nil {
+				return err
+			}
 		}
+	}
 
-		// Stop container if running and not forcing
-		if container.Status == "running" && !force {
-			timeout := 10
-			if err := m.dockerClient.ContainerStop(container.ContainerID, &timeout); err != nil {
-				fmt.Printf("Warning: failed to stop container %s: %v\n", container.Name, err)
+	if err := m.stopMultiContainerService(instance); err != nil {
+		return err
+	}
+
+	for i := range instance.Containers {
+		c := &instance.Containers[i]
+		info := inspected[i]
+
+		if envs[i] != nil {
+			info.Config.Env = envfile.EnvMapToSlice(envs[i])
+		}
+
+		if relabel != nil {
+			if info.Config.Labels == nil {
+				info.Config.Labels = make(map[string]string)
 			}
+			relabel(info.Config.Labels)
 		}
 
-		// Disconnect from network
-		if err := networkMgr.DisconnectContainer("doku-network", container.FullName, force); err != nil {
-			fmt.Printf("Warning: failed to disconnect %s from network: %v\n", container.Name, err)
+		// Preserve network aliases, skipping the short container ID Docker adds itself
+		var aliases []string
+		if endpoint, ok := info.NetworkSettings.Networks[docker.NetworkName()]; ok && endpoint != nil {
+			for _, alias := range endpoint.Aliases {
+				if !strings.HasPrefix(info.ID, alias) {
+					aliases = append(aliases, alias)
+				}
+			}
 		}
 
-		// Remove container
-		if err := m.dockerClient.ContainerRemove(container.ContainerID, force); err != nil {
-			fmt.Printf("Warning: failed to remove container %s: %v\n", container.Name, err)
-			// Continue with other containers instead of returning error
-		} else {
-			fmt.Printf("Removed container: %s\n", container.Name)
+		if err := m.dockerClient.ContainerRemove(c.FullName, false); err != nil {
+			return fmt.Errorf("failed to remove container %s: %w", c.Name, err)
+		}
+
+		mounts := make([]mount.Mount, 0, len(info.Mounts))
+		for _, mp := range info.Mounts {
+			source := mp.Source
+			if mp.Type == mount.TypeVolume && mp.Name != "" {
+				source = mp.Name
+			}
+			mounts = append(mounts, mount.Mount{
+				Type:     mp.Type,
+				Source:   source,
+				Target:   mp.Destination,
+				ReadOnly: !mp.RW,
+			})
+		}
+
+		hostConfig := &container.HostConfig{
+			RestartPolicy: info.HostConfig.RestartPolicy,
+			Mounts:        mounts,
+			LogConfig:     monitoring.ApplyLogRotation(info.HostConfig.LogConfig, m.logRotation(instance)),
+			PortBindings:  info.HostConfig.PortBindings,
+			Resources:     info.HostConfig.Resources,
+		}
+
+		networkConfig := &network.NetworkingConfig{
+			EndpointsConfig: map[string]*network.EndpointSettings{
+				docker.NetworkName(): {
+					Aliases: aliases,
+				},
+			},
+		}
+
+		containerID, err := m.dockerClient.ContainerCreate(info.Config, hostConfig, networkConfig, c.FullName)
+		if err != nil {
+			return fmt.Errorf("failed to create container %s: %w", c.Name, err)
 		}
+
+		c.ContainerID = containerID
+		fmt.Printf("Recreated container: %s\n", c.Name)
+	}
+
+	// Record the new container IDs before starting
+	if err := m.configMgr.UpdateInstance(instance.Name, instance); err != nil {
+		return fmt.Errorf("failed to update instance: %w", err)
+	}
+
+	return m.startMultiContainerService(instance)
+}
+
+// removeMultiContainerService removes all containers in a multi-container
+// service in reverse dependency order: all containers are stopped first,
+// dependents before the containers they depend on, so none runs against a
+// missing member mid-removal; then they are removed in the same order.
+func (m *Manager) removeMultiContainerService(instance *types.Instance, force bool, removeVolumes bool) error {
+	networkMgr := docker.NewNetworkManager(m.dockerClient)
+	levels := m.removalLevels(instance)
+
+	// Stop running containers unless forcing
+	if !force {
+		for l := len(levels) - 1; l >= 0; l-- {
+			forEachConcurrently(levels[l], func(idx int) error {
+				container := &instance.Containers[idx]
+				if container.Status != "running" {
+					return nil
+				}
+				timeout := 10
+				if err := m.dockerClient.ContainerStop(container.ContainerID, &timeout); err != nil {
+					fmt.Printf("Warning: failed to stop container %s: %v\n", container.Name, err)
+				}
+				return nil
+			})
+		}
+	}
+
+	for l := len(levels) - 1; l >= 0; l-- {
+		forEachConcurrently(levels[l], func(idx int) error {
+			container := &instance.Containers[idx]
+
+			// Check if container exists
+			containerExists, err := m.dockerClient.ContainerExists(container.ContainerID)
+			if err != nil {
+				fmt.Printf("Warning: failed to check if container %s exists: %v\n", container.Name, err)
+			}
+
+			if !containerExists {
+				fmt.Printf("⚠️  Container %s does not exist (may have been removed manually)\n", container.Name)
+				return nil
+			}
+
+			// Disconnect from network
+			if err := networkMgr.DisconnectContainer(docker.NetworkName(), container.FullName, force); err != nil {
+				fmt.Printf("Warning: failed to disconnect %s from network: %v\n", container.Name, err)
+			}
+
+			// Remove container
+			if err := m.dockerClient.ContainerRemove(container.ContainerID, force); err != nil {
+				fmt.Printf("Warning: failed to remove container %s: %v\n", container.Name, err)
+				// Continue with other containers instead of returning error
+			} else {
+				fmt.Printf("Removed container: %s\n", container.Name)
+			}
+			return nil
+		})
 	}
 
 	// Remove associated volumes only if user agreed
 	if removeVolumes {
-		if err := m.removeMultiContainerVolumes(instance); err != nil {
+		if err := m.removeVolumes(instance); err != nil {
 			fmt.Printf("Warning: failed to remove some volumes: %v\n", err)
 		}
 	}
 
 	// Remove from config - always do this to clean up state
+	if instance.ServiceType == "custom-project" {
+		return m.configMgr.RemoveProject(instance.Name)
+	}
 	return m.configMgr.RemoveInstance(instance.Name)
 }
 
+// removalLevels returns the dependency levels of an instance's containers.
+// Instances installed before dependencies were recorded take them from
+// their catalog spec, or else keep their stored order.
+func (m *Manager) removalLevels(instance *types.Instance) [][]int {
+	withDeps := &types.Instance{Containers: instance.Containers}
+	catalogMgr := catalog.NewManager(m.configMgr.GetCatalogDir())
+	if !hasContainerDependencies(instance) {
+		if spec, err := catalogMgr.GetServiceVersion(instance.ServiceType, instance.Version); err == nil {
+			withDeps.Containers = withSpecDependencies(instance.Containers, spec)
+		}
+	}
+
+	levels, err := instanceContainerLevels(withDeps)
+	if err != nil {
+		// A cycle: fall back to the stored order
+		levels = storedOrderLevels(len(instance.Containers))
+	}
+	return levels
+}
+
 // getMultiContainerStatus checks the status of all containers in a multi-container service
 func (m *Manager) getMultiContainerStatus(instance *types.Instance) (types.ServiceStatus, error) {
 	runningCount := 0
@@ -820,31 +1251,6 @@ func (m *Manager) getMultiContainerStatus(instance *types.Instance) (types.Servi
 	return status, nil
 }
 
-// removeMultiContainerVolumes removes volumes for a multi-container service
-func (m *Manager) removeMultiContainerVolumes(instance *types.Instance) error {
-	for _, container := range instance.Containers {
-		// Try to inspect container to get volume info
-		containerInfo, err := m.dockerClient.ContainerInspect(container.ContainerID)
-		if err != nil {
-			continue
-		}
-
-		// Remove named volumes
-		for _, mount := range containerInfo.Mounts {
-			if mount.Type == "volume" {
-				// Only remove volumes managed by doku
-				if len(mount.Name) > 5 && mount.Name[:5] == "doku-" {
-					if err := m.dockerClient.VolumeRemove(mount.Name, false); err != nil {
-						fmt.Printf("Warning: failed to remove volume %s: %v\n", mount.Name, err)
-					}
-				}
-			}
-		}
-	}
-
-	return nil
-}
-
 // recreateContainer recreates a container with new port configuration
 func (m *Manager) recreateContainer(instance *types.Instance, oldContainerInfo *dockerTypes.ContainerJSON) error {
 	// Import nat package for port handling
@@ -861,7 +1267,7 @@ func (m *Manager) recreateContainer(instance *types.Instance, oldContainerInfo *
 		exposedPorts[containerPortSpec] = struct{}{}
 		portBindings[containerPortSpec] = []nat.PortBinding{
 			{
-				HostIP:   "0.0.0.0",
+				HostIP:   m.configMgr.GetPublishIP(),
 				HostPort: fmt.Sprintf("%d", instance.Network.HostPort),
 			},
 		}
@@ -901,10 +1307,14 @@ func (m *Manager) recreateContainer(instance *types.Instance, oldContainerInfo *
 	hostConfig := &container.HostConfig{
 		RestartPolicy: oldContainerInfo.HostConfig.RestartPolicy,
 		Mounts:        mounts,
-		LogConfig:     oldContainerInfo.HostConfig.LogConfig,
+		LogConfig:     monitoring.ApplyLogRotation(oldContainerInfo.HostConfig.LogConfig, m.logRotation(instance)),
 		PortBindings:  portBindings,
 		Resources:     oldContainerInfo.HostConfig.Resources,
 	}
+	if instance.UsesHostNetwork() {
+		hostConfig.NetworkMode = container.NetworkMode(types.NetworkModeHost)
+		hostConfig.PortBindings = nil
+	}
 
 	// Restore network aliases from labels
 	aliases := []string{instance.ServiceType}
@@ -916,11 +1326,14 @@ func (m *Manager) recreateContainer(instance *types.Instance, oldContainerInfo *
 	// This is more reliable than connecting after creation
 	networkConfig := &network.NetworkingConfig{
 		EndpointsConfig: map[string]*network.EndpointSettings{
-			"doku-network": {
+			docker.NetworkName(): {
 				Aliases: aliases,
 			},
 		},
 	}
+	if instance.UsesHostNetwork() {
+		networkConfig = nil
+	}
 
 	// Create container with network config
 	containerID, err := m.dockerClient.ContainerCreate(
@@ -942,12 +1355,19 @@ func (m *Manager) recreateContainer(instance *types.Instance, oldContainerInfo *
 	// Start container
 	if err := m.dockerClient.ContainerStart(containerID); err != nil {
 		// Cleanup on failure
-		networkMgr.DisconnectContainer("doku-network", instance.ContainerName, true)
+		networkMgr.DisconnectContainer(docker.NetworkName(), instance.ContainerName, true)
 		m.dockerClient.ContainerRemove(instance.ContainerName, true)
 		return fmt.Errorf("failed to start container: %w", err)
 	}
 
 	instance.Status = types.StatusRunning
+
+	// Replicas copy the container, so they are replaced along with it
+	if instance.ReplicaCount() > 1 {
+		if err := m.dockerClient.SyncReplicas(instance.ContainerName, instance.ReplicaCount(), true); err != nil {
+			color.Yellow("⚠️  Failed to recreate the replicas of %s: %v", instance.Name, err)
+		}
+	}
 	return nil
 }
 
@@ -1028,7 +1448,7 @@ func (m *Manager) runInitContainers(spec *types.ServiceSpec, instanceName string
 		}
 
 		// Run container with --rm flag (auto-remove after completion)
-		containerName := fmt.Sprintf("doku-%s-init-%s", instanceName, initContainer.Name)
+		containerName := fmt.Sprintf("%s-init-%s", docker.GenerateContainerName(instanceName), initContainer.Name)
 
 		// Check if image exists locally first
 		imageExists, err := m.dockerClient.ImageExists(initContainer.Image)
@@ -1052,7 +1472,7 @@ func (m *Manager) runInitContainers(spec *types.ServiceSpec, instanceName string
 			containerName,
 			cmd,
 			env,
-			"doku-network",
+			docker.NetworkName(),
 			true, // auto-remove after completion
 		)
 		if err != nil {
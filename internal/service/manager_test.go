@@ -1,6 +1,7 @@
 package service
 
 import (
+	"reflect"
 	"testing"
 )
 
@@ -86,3 +87,26 @@ func TestNewManager(t *testing.T) {
 		t.Error("configMgr should be nil when passed nil")
 	}
 }
+
+// TestEnvDrift tests comparing env file values with a container's environment
+func TestEnvDrift(t *testing.T) {
+	containerEnv := []string{
+		"PATH=/usr/local/bin:/usr/bin",
+		"POSTGRES_PASSWORD=old",
+		"POSTGRES_DB=app",
+		"DEBUG=true",
+		"EMPTY=",
+	}
+	env := map[string]string{
+		"POSTGRES_PASSWORD": "new",
+		"POSTGRES_DB":       "app",
+		"API_URL":           "http://api",
+		"EMPTY":             "",
+	}
+
+	got := envDrift(env, containerEnv, []string{"POSTGRES_PASSWORD", "POSTGRES_DB", "API_URL", "DEBUG", "EMPTY", "UNKNOWN"})
+	want := []string{"POSTGRES_PASSWORD", "API_URL", "DEBUG"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("envDrift() = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,50 @@
+package service
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/dokulabs/doku-cli/internal/docker"
+	"github.com/dokulabs/doku-cli/pkg/types"
+)
+
+// SetMiddlewares replaces the Traefik middlewares in front of an instance's
+// HTTP route, recreating its containers with the new labels. A nil or empty
+// mw removes them. Stopped instances are stopped again afterwards.
+func (m *Manager) SetMiddlewares(instanceName string, mw *types.Middlewares) error {
+	instance, err := m.configMgr.GetInstance(instanceName)
+	if err != nil {
+		return fmt.Errorf("instance not found: %w", err)
+	}
+	if instance.UsesHostNetwork() || !instance.Traefik.Enabled || instance.URL == "" {
+		return fmt.Errorf("'%s' isn't routed through Traefik", instanceName)
+	}
+	if err := docker.ValidateMiddlewares(mw); err != nil {
+		return err
+	}
+	if mw.IsEmpty() {
+		mw = nil
+	}
+
+	wasRunning := instance.Status == types.StatusRunning
+	relabel := func(labels map[string]string) {
+		docker.ApplyMiddlewares(labels, mw)
+	}
+	if err := m.RecreateWithLabels(instanceName, relabel); err != nil {
+		return err
+	}
+	if !wasRunning {
+		if err := m.Stop(instanceName); err != nil {
+			return err
+		}
+	}
+
+	// Recreating updated the instance, so save on top of that
+	instance, err = m.configMgr.GetInstance(instanceName)
+	if err != nil {
+		return err
+	}
+	instance.Middlewares = mw
+	instance.UpdatedAt = time.Now()
+	return m.configMgr.UpdateInstance(instanceName, instance)
+}
@@ -0,0 +1,104 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/dokulabs/doku-cli/pkg/types"
+)
+
+// RecreateMissing recreates an instance whose containers were removed outside
+// Doku from its record, keeping its data and pinned image and container digests.
+func (i *Installer) RecreateMissing(instanceName string) (*types.Instance, error) {
+	old, err := i.configMgr.GetInstance(instanceName)
+	if err != nil {
+		return nil, fmt.Errorf("instance '%s' not found", instanceName)
+	}
+
+	// Containers of a multi-container instance that survived would clash
+	// with the new ones
+	for _, name := range instanceContainerNames(old) {
+		exists, err := i.dockerClient.ContainerExists(name.full)
+		if err != nil {
+			return nil, err
+		}
+		if exists {
+			if err := i.dockerClient.ContainerRemove(name.full, true); err != nil {
+				return nil, fmt.Errorf("failed to remove %s: %w", name.full, err)
+			}
+		}
+	}
+
+	// Drop the record alone: RemoveInstance would also update the
+	// instances it depends on, which still do
+	if err := i.configMgr.Update(func(c *types.Config) error {
+		delete(c.Instances, instanceName)
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	instance, err := i.Install(InstallOptions{
+		ServiceName:       old.ServiceType,
+		Version:           old.Version,
+		InstanceName:      instanceName,
+		MemoryLimit:       old.Resources.MemoryLimit,
+		CPULimit:          old.Resources.CPULimit,
+		Volumes:           old.Volumes,
+		PortMappings:      old.Network.PortMappings,
+		Internal:          !old.Traefik.Enabled,
+		HostNetwork:       old.UsesHostNetwork(),
+		Labels:            old.Labels,
+		Logs:              old.Logs,
+		Middlewares:       old.Middlewares,
+		Domains:           old.Domains,
+		TLSSANs:           old.TLSSANs,
+		LAN:               old.LAN,
+		ImageDigest:       old.ImageDigest,
+		ContainerDigests:  containerDigests(old),
+		SkipDependencies:  true,
+		ReuseExistingData: true,
+	})
+	if err != nil {
+		restoreErr := i.configMgr.Update(func(c *types.Config) error {
+			c.Instances[instanceName] = old
+			return nil
+		})
+		if restoreErr != nil {
+			return nil, errors.Join(err, restoreErr)
+		}
+		return nil, err
+	}
+
+	instance.Dependencies = old.Dependencies
+	instance.DependencyOf = old.DependencyOf
+	instance.Links = old.Links
+	instance.AutoUpdate = old.AutoUpdate
+	instance.Previous = old.Previous
+	instance.CreatedAt = old.CreatedAt
+	if old.DesiredState != "" {
+		instance.DesiredState = old.DesiredState
+	}
+	if err := i.configMgr.UpdateInstance(instanceName, instance); err != nil {
+		return instance, err
+	}
+
+	if !instance.WantsRunning() {
+		if err := NewManager(i.dockerClient, i.configMgr).Stop(instanceName); err != nil && !errors.Is(err, types.ErrAlreadyStopped) {
+			return instance, fmt.Errorf("failed to stop %s: %w", instanceName, err)
+		}
+	}
+	return instance, nil
+}
+
+// containerDigests returns the digests the containers of a multi-container
+// instance are pinned to, by container name
+func containerDigests(instance *types.Instance) map[string]string {
+	digests := make(map[string]string)
+	for _, c := range instance.Containers {
+		if c.ImageDigest != "" {
+			digests[c.Name] = c.ImageDigest
+		}
+	}
+	return digests
+}
@@ -0,0 +1,152 @@
+package service
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/dokulabs/doku-cli/pkg/types"
+)
+
+// containerLevels groups the containers of a multi-container service into
+// start levels. Containers in the same level don't depend on each other and
+// can be started concurrently; every container comes in a later level than
+// the containers it depends on. Levels hold indices into containers.
+// Dependencies on names outside containers are ignored.
+func containerLevels(containers []types.ContainerInfo) ([][]int, error) {
+	names := make([]string, len(containers))
+	for idx, c := range containers {
+		names[idx] = c.Name
+	}
+	return dependencyLevels(names, func(idx int) []string {
+		return containers[idx].DependsOn
+	})
+}
+
+// dependencyLevels groups names into levels so that every name comes in a
+// later level than the names it depends on. Levels hold indices into names,
+// in their original order. Dependencies on unknown names are ignored.
+func dependencyLevels(names []string, dependsOn func(idx int) []string) ([][]int, error) {
+	index := make(map[string]int, len(names))
+	for idx, name := range names {
+		index[name] = idx
+	}
+
+	const unvisited = -1
+	level := make([]int, len(names))
+	visiting := make([]bool, len(names))
+	for idx := range level {
+		level[idx] = unvisited
+	}
+
+	var visit func(idx int) (int, error)
+	visit = func(idx int) (int, error) {
+		if level[idx] != unvisited {
+			return level[idx], nil
+		}
+		if visiting[idx] {
+			return 0, fmt.Errorf("circular dependency detected at: %s", names[idx])
+		}
+
+		visiting[idx] = true
+		current := 0
+		for _, dep := range dependsOn(idx) {
+			depIdx, ok := index[dep]
+			if !ok {
+				continue
+			}
+			depLevel, err := visit(depIdx)
+			if err != nil {
+				return 0, err
+			}
+			if depLevel+1 > current {
+				current = depLevel + 1
+			}
+		}
+		visiting[idx] = false
+		level[idx] = current
+		return current, nil
+	}
+
+	var levels [][]int
+	for idx := range names {
+		l, err := visit(idx)
+		if err != nil {
+			return nil, err
+		}
+		for len(levels) <= l {
+			levels = append(levels, nil)
+		}
+	}
+	for idx, l := range level {
+		levels[l] = append(levels[l], idx)
+	}
+
+	return levels, nil
+}
+
+// instanceContainerLevels returns the start levels of an instance. Instances
+// installed before dependencies were recorded start one container at a time
+// in their stored order, as they always have.
+func instanceContainerLevels(instance *types.Instance) ([][]int, error) {
+	if hasContainerDependencies(instance) {
+		return containerLevels(instance.Containers)
+	}
+	return storedOrderLevels(len(instance.Containers)), nil
+}
+
+// storedOrderLevels puts each of n containers in its own level, in order
+func storedOrderLevels(n int) [][]int {
+	levels := make([][]int, n)
+	for idx := range levels {
+		levels[idx] = []int{idx}
+	}
+	return levels
+}
+
+// hasContainerDependencies reports whether dependencies between an
+// instance's containers were recorded
+func hasContainerDependencies(instance *types.Instance) bool {
+	for _, c := range instance.Containers {
+		if len(c.DependsOn) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// withSpecDependencies returns a copy of containers with the dependencies
+// between them taken from spec, for instances installed before they were
+// recorded
+func withSpecDependencies(containers []types.ContainerInfo, spec *types.ServiceSpec) []types.ContainerInfo {
+	result := make([]types.ContainerInfo, len(containers))
+	for idx, c := range containers {
+		if containerSpec := spec.GetContainerByName(c.Name); containerSpec != nil {
+			c.DependsOn = internalDependencies(spec, *containerSpec)
+		}
+		result[idx] = c
+	}
+	return result
+}
+
+// forEachConcurrently runs fn for every index in parallel and returns the
+// first error in index order
+func forEachConcurrently(indices []int, fn func(idx int) error) error {
+	errs := make([]error, len(indices))
+
+	var wg sync.WaitGroup
+	for n, idx := range indices {
+		wg.Add(1)
+		go func(n, idx int) {
+			defer wg.Done()
+			errs[n] = fn(idx)
+		}(n, idx)
+	}
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
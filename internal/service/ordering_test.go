@@ -0,0 +1,82 @@
+package service
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/dokulabs/doku-cli/pkg/types"
+)
+
+func TestContainerLevels(t *testing.T) {
+	containers := []types.ContainerInfo{
+		{Name: "frontend", DependsOn: []string{"query"}},
+		{Name: "clickhouse"},
+		{Name: "query", DependsOn: []string{"clickhouse", "zookeeper"}},
+		{Name: "zookeeper"},
+		{Name: "collector", DependsOn: []string{"clickhouse", "external-service"}},
+	}
+
+	levels, err := containerLevels(containers)
+	if err != nil {
+		t.Fatalf("containerLevels() error = %v", err)
+	}
+
+	want := [][]int{{1, 3}, {2, 4}, {0}}
+	if !reflect.DeepEqual(levels, want) {
+		t.Errorf("containerLevels() = %v, want %v", levels, want)
+	}
+}
+
+func TestContainerLevelsCycle(t *testing.T) {
+	containers := []types.ContainerInfo{
+		{Name: "a", DependsOn: []string{"b"}},
+		{Name: "b", DependsOn: []string{"a"}},
+	}
+
+	if _, err := containerLevels(containers); err == nil {
+		t.Error("containerLevels() with a cycle should fail")
+	}
+}
+
+func TestInstanceContainerLevelsLegacy(t *testing.T) {
+	instance := &types.Instance{
+		Containers: []types.ContainerInfo{{Name: "a"}, {Name: "b"}, {Name: "c"}},
+	}
+
+	levels, err := instanceContainerLevels(instance)
+	if err != nil {
+		t.Fatalf("instanceContainerLevels() error = %v", err)
+	}
+
+	want := [][]int{{0}, {1}, {2}}
+	if !reflect.DeepEqual(levels, want) {
+		t.Errorf("instanceContainerLevels() = %v, want %v", levels, want)
+	}
+}
+
+func TestWithSpecDependencies(t *testing.T) {
+	spec := &types.ServiceSpec{
+		Containers: []types.ContainerSpec{
+			{Name: "db"},
+			{Name: "api", DependsOn: []string{"db"}},
+			{Name: "web", DependsOn: []string{"api"}},
+		},
+	}
+	instance := &types.Instance{
+		Containers: withSpecDependencies(
+			[]types.ContainerInfo{{Name: "web"}, {Name: "db"}, {Name: "api"}},
+			spec,
+		),
+	}
+
+	levels, err := instanceContainerLevels(instance)
+	if err != nil {
+		t.Fatalf("instanceContainerLevels() error = %v", err)
+	}
+
+	// Removal walks the levels backwards: web, then api, then db
+	want := [][]int{{1}, {2}, {0}}
+	if !reflect.DeepEqual(levels, want) {
+		t.Errorf("instanceContainerLevels() = %v, want %v", levels, want)
+	}
+}
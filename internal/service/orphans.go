@@ -0,0 +1,129 @@
+package service
+
+import (
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/dokulabs/doku-cli/pkg/types"
+)
+
+// recordDependencies points an instance's dependencies at the installed
+// instances reused for them, with links recording the variables injected
+// for them, and records it as a dependent of the ones
+// installed for it and of the ones it shares that an earlier install brought
+// in, so they are offered for removal with the last of them
+func (i *Installer) recordDependencies(instance *types.Instance, deps *resolvedDependencies) error {
+	names := make(map[string]bool, len(deps.installed))
+	for _, name := range deps.installed {
+		names[name] = true
+	}
+
+	return i.configMgr.Update(func(c *types.Config) error {
+		if stored, exists := c.Instances[instance.Name]; exists {
+			for idx, dep := range stored.Dependencies {
+				if reused, ok := deps.instances[dep]; ok {
+					stored.Dependencies[idx] = reused
+				}
+			}
+			instance.Dependencies = stored.Dependencies
+
+			stored.Links = append(stored.Links, deps.links...)
+			instance.Links = stored.Links
+		}
+
+		for _, name := range instance.Dependencies {
+			if dep, exists := c.Instances[name]; exists && len(dep.DependencyOf) > 0 {
+				names[name] = true
+			}
+		}
+		for name := range names {
+			dep, exists := c.Instances[name]
+			if !exists || name == instance.Name || slices.Contains(dep.DependencyOf, instance.Name) {
+				continue
+			}
+			dep.DependencyOf = append(dep.DependencyOf, instance.Name)
+		}
+		return nil
+	})
+}
+
+// OrphanedDependencies returns the instances installed as dependencies of
+// instanceName that nothing else needs once it is removed: no other
+// instance or project depends on or links to them. Dependencies of those
+// that become unneeded along with them are included.
+func (m *Manager) OrphanedDependencies(instanceName string) ([]string, error) {
+	cfg, err := m.configMgr.Get()
+	if err != nil {
+		return nil, err
+	}
+	return orphanedDependencies(cfg, instanceName), nil
+}
+
+// orphanedDependencies returns the dependencies left unneeded by removing
+// removed from cfg, sorted by name
+func orphanedDependencies(cfg *types.Config, removed string) []string {
+	gone := map[string]bool{removed: true}
+
+	for changed := true; changed; {
+		changed = false
+		for name, instance := range cfg.Instances {
+			if gone[name] || len(instance.DependencyOf) == 0 {
+				continue
+			}
+			if !dependentsGone(instance.DependencyOf, gone, cfg) || neededBy(cfg, name, gone) {
+				continue
+			}
+			gone[name] = true
+			changed = true
+		}
+	}
+
+	delete(gone, removed)
+	orphans := make([]string, 0, len(gone))
+	for name := range gone {
+		orphans = append(orphans, name)
+	}
+	sort.Strings(orphans)
+	return orphans
+}
+
+// dependentsGone reports whether the instances a dependency was installed
+// for are being removed, apart from ones that no longer exist
+func dependentsGone(dependents []string, gone map[string]bool, cfg *types.Config) bool {
+	removing := false
+	for _, dependent := range dependents {
+		if gone[dependent] {
+			removing = true
+		} else if _, exists := cfg.Instances[dependent]; exists {
+			return false
+		}
+	}
+	return removing
+}
+
+// neededBy reports whether an instance or project that stays depends on or
+// links to name
+func neededBy(cfg *types.Config, name string, gone map[string]bool) bool {
+	for other, instance := range cfg.Instances {
+		if gone[other] {
+			continue
+		}
+		if slices.Contains(instance.Dependencies, name) {
+			return true
+		}
+		for _, link := range instance.Links {
+			if link.Service == name {
+				return true
+			}
+		}
+	}
+	for _, project := range cfg.Projects {
+		for _, dep := range project.Dependencies {
+			if strings.SplitN(dep, ":", 2)[0] == name {
+				return true
+			}
+		}
+	}
+	return false
+}
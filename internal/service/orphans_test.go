@@ -0,0 +1,109 @@
+package service
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/dokulabs/doku-cli/pkg/types"
+)
+
+func TestOrphanedDependencies(t *testing.T) {
+	tests := []struct {
+		name      string
+		instances []*types.Instance
+		projects  []*types.Project
+		remove    string
+		expected  []string
+	}{
+		{
+			name: "dependencies installed for the instance",
+			instances: []*types.Instance{
+				{Name: "signoz", Dependencies: []string{"clickhouse", "zookeeper"}},
+				{Name: "clickhouse", Dependencies: []string{"zookeeper"}, DependencyOf: []string{"signoz"}},
+				{Name: "zookeeper", DependencyOf: []string{"signoz"}},
+			},
+			remove:   "signoz",
+			expected: []string{"clickhouse", "zookeeper"},
+		},
+		{
+			name: "dependency shared with another instance",
+			instances: []*types.Instance{
+				{Name: "signoz", Dependencies: []string{"clickhouse"}},
+				{Name: "grafana", Dependencies: []string{"clickhouse"}},
+				{Name: "clickhouse", DependencyOf: []string{"signoz", "grafana"}},
+			},
+			remove:   "signoz",
+			expected: []string{},
+		},
+		{
+			name: "dependency used by an instance it wasn't installed for",
+			instances: []*types.Instance{
+				{Name: "signoz"},
+				{Name: "api", Dependencies: []string{"clickhouse"}},
+				{Name: "clickhouse", DependencyOf: []string{"signoz"}},
+			},
+			remove:   "signoz",
+			expected: []string{},
+		},
+		{
+			name: "dependency linked to another instance",
+			instances: []*types.Instance{
+				{Name: "signoz"},
+				{Name: "api", Links: []types.Link{{Service: "clickhouse"}}},
+				{Name: "clickhouse", DependencyOf: []string{"signoz"}},
+			},
+			remove:   "signoz",
+			expected: []string{},
+		},
+		{
+			name: "dependency of a project",
+			instances: []*types.Instance{
+				{Name: "signoz"},
+				{Name: "clickhouse", DependencyOf: []string{"signoz"}},
+			},
+			projects: []*types.Project{
+				{Name: "api", Dependencies: []string{"clickhouse:24"}},
+			},
+			remove:   "signoz",
+			expected: []string{},
+		},
+		{
+			name: "services installed by themselves are kept",
+			instances: []*types.Instance{
+				{Name: "signoz", Dependencies: []string{"clickhouse"}},
+				{Name: "clickhouse"},
+			},
+			remove:   "signoz",
+			expected: []string{},
+		},
+		{
+			name: "dependencies of other instances are left alone",
+			instances: []*types.Instance{
+				{Name: "signoz"},
+				{Name: "redis"},
+				{Name: "zookeeper", DependencyOf: []string{"kafka"}},
+			},
+			remove:   "redis",
+			expected: []string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &types.Config{
+				Instances: make(map[string]*types.Instance),
+				Projects:  make(map[string]*types.Project),
+			}
+			for _, instance := range tt.instances {
+				cfg.Instances[instance.Name] = instance
+			}
+			for _, project := range tt.projects {
+				cfg.Projects[project.Name] = project
+			}
+
+			if got := orphanedDependencies(cfg, tt.remove); !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("orphanedDependencies(%q) = %v, expected %v", tt.remove, got, tt.expected)
+			}
+		})
+	}
+}
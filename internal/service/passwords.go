@@ -0,0 +1,77 @@
+package service
+
+import (
+	"crypto/rand"
+	"fmt"
+	"math/big"
+	"sort"
+	"strings"
+)
+
+// GeneratedPasswordLength is the length of generated passwords
+const GeneratedPasswordLength = 24
+
+// Letters and digits only, so generated passwords can go into connection
+// URLs and shell commands without quoting
+const passwordAlphabet = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"
+
+// GeneratePassword returns a random password from crypto/rand
+func GeneratePassword(length int) (string, error) {
+	max := big.NewInt(int64(len(passwordAlphabet)))
+	b := make([]byte, length)
+	for i := range b {
+		n, err := rand.Int(rand.Reader, max)
+		if err != nil {
+			return "", fmt.Errorf("failed to generate password: %w", err)
+		}
+		b[i] = passwordAlphabet[n.Int64()]
+	}
+	return string(b), nil
+}
+
+// IsPasswordKey reports whether an environment variable holds a password,
+// e.g. POSTGRES_PASSWORD or MYSQL_ROOT_PASSWORD. Flags about passwords
+// such as MYSQL_ALLOW_EMPTY_PASSWORD and *_FILE paths are not passwords.
+func IsPasswordKey(key string) bool {
+	upper := strings.ToUpper(key)
+	if strings.HasSuffix(upper, "_FILE") {
+		return false
+	}
+	for _, flag := range []string{"ALLOW_EMPTY", "RANDOM_ROOT", "ONETIME"} {
+		if strings.Contains(upper, flag) {
+			return false
+		}
+	}
+	return strings.Contains(upper, "PASSWORD") ||
+		strings.Contains(upper, "PASSWD") ||
+		strings.HasSuffix(upper, "_PASS")
+}
+
+// generatePasswords returns a copy of the catalog's default environment
+// with a random password in place of every default password the user did
+// not override, and the keys it replaced. Empty defaults are kept, since
+// they usually mean authentication is off.
+func generatePasswords(defaults, overrides map[string]string) (map[string]string, []string, error) {
+	env := make(map[string]string, len(defaults))
+	var generated []string
+
+	for k, v := range defaults {
+		env[k] = v
+		if v == "" || !IsPasswordKey(k) {
+			continue
+		}
+		if _, overridden := overrides[k]; overridden {
+			continue
+		}
+
+		password, err := GeneratePassword(GeneratedPasswordLength)
+		if err != nil {
+			return nil, nil, err
+		}
+		env[k] = password
+		generated = append(generated, k)
+	}
+
+	sort.Strings(generated)
+	return env, generated, nil
+}
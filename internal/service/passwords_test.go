@@ -0,0 +1,75 @@
+package service
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestGeneratePassword(t *testing.T) {
+	a, err := GeneratePassword(GeneratedPasswordLength)
+	if err != nil {
+		t.Fatalf("GeneratePassword() error = %v", err)
+	}
+	b, _ := GeneratePassword(GeneratedPasswordLength)
+
+	if len(a) != GeneratedPasswordLength {
+		t.Errorf("len = %d, want %d", len(a), GeneratedPasswordLength)
+	}
+	if a == b {
+		t.Error("expected two generated passwords to differ")
+	}
+	if strings.Trim(a, passwordAlphabet) != "" {
+		t.Errorf("password %q has characters outside the alphabet", a)
+	}
+}
+
+func TestIsPasswordKey(t *testing.T) {
+	tests := map[string]bool{
+		"POSTGRES_PASSWORD":          true,
+		"MYSQL_ROOT_PASSWORD":        true,
+		"RABBITMQ_DEFAULT_PASS":      true,
+		"redis_password":             true,
+		"POSTGRES_PASSWORD_FILE":     false,
+		"MYSQL_ALLOW_EMPTY_PASSWORD": false,
+		"MYSQL_RANDOM_ROOT_PASSWORD": false,
+		"POSTGRES_USER":              false,
+		"PASSENGER_ENV":              false,
+	}
+	for key, want := range tests {
+		if got := IsPasswordKey(key); got != want {
+			t.Errorf("IsPasswordKey(%q) = %v, want %v", key, got, want)
+		}
+	}
+}
+
+func TestGeneratePasswords(t *testing.T) {
+	defaults := map[string]string{
+		"POSTGRES_USER":       "postgres",
+		"POSTGRES_PASSWORD":   "postgres",
+		"MYSQL_ROOT_PASSWORD": "root",
+		"REDIS_PASSWORD":      "",
+	}
+	overrides := map[string]string{"MYSQL_ROOT_PASSWORD": "secret"}
+
+	env, generated, err := generatePasswords(defaults, overrides)
+	if err != nil {
+		t.Fatalf("generatePasswords() error = %v", err)
+	}
+
+	if !reflect.DeepEqual(generated, []string{"POSTGRES_PASSWORD"}) {
+		t.Errorf("generated = %v, want [POSTGRES_PASSWORD]", generated)
+	}
+	if env["POSTGRES_PASSWORD"] == "postgres" || len(env["POSTGRES_PASSWORD"]) != GeneratedPasswordLength {
+		t.Errorf("expected a generated password, got %q", env["POSTGRES_PASSWORD"])
+	}
+	if env["MYSQL_ROOT_PASSWORD"] != "root" {
+		t.Errorf("overridden default should be left for the override, got %q", env["MYSQL_ROOT_PASSWORD"])
+	}
+	if env["REDIS_PASSWORD"] != "" {
+		t.Errorf("empty default should stay empty, got %q", env["REDIS_PASSWORD"])
+	}
+	if defaults["POSTGRES_PASSWORD"] != "postgres" {
+		t.Error("catalog defaults must not be modified")
+	}
+}
@@ -0,0 +1,140 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/dokulabs/doku-cli/pkg/types"
+)
+
+// QuietHoursResult reports what RunQuietHours did
+type QuietHoursResult struct {
+	Stopped []string         // Services stopped as quiet hours began
+	Started []string         // Services started again as quiet hours ended
+	Failed  map[string]error // Services that could not be stopped or started
+}
+
+// QuietHoursDue reports whether quiet hours call for stopping services at
+// now (the quiet period began and services weren't stopped for it yet) or
+// starting them again (the period ended with services still stopped)
+func QuietHoursDue(quiet *types.QuietHoursConfig, now time.Time) (stop, start bool, err error) {
+	if quiet == nil {
+		return false, false, nil
+	}
+	inWindow, err := InWindow(quiet.Window, now)
+	if err != nil {
+		return false, false, err
+	}
+	if !inWindow {
+		return false, len(quiet.Stopped) > 0, nil
+	}
+
+	began, err := windowStart(quiet.Window, now)
+	if err != nil {
+		return false, false, err
+	}
+	return quiet.LastStop.Before(began), false, nil
+}
+
+// windowStart returns the last time a daily window began at or before now
+func windowStart(window string, now time.Time) (time.Time, error) {
+	start, _, err := ParseWindow(window)
+	if err != nil {
+		return time.Time{}, err
+	}
+	began := time.Date(now.Year(), now.Month(), now.Day(), start/60, start%60, 0, 0, now.Location())
+	if began.After(now) {
+		began = began.AddDate(0, 0, -1)
+	}
+	return began, nil
+}
+
+// RunQuietHours stops services as quiet hours begin and starts those it
+// stopped as they end, so one started by hand meanwhile keeps running
+func (m *Manager) RunQuietHours(now time.Time) (*QuietHoursResult, error) {
+	cfg, err := m.configMgr.Get()
+	if err != nil {
+		return nil, err
+	}
+	quiet := cfg.QuietHours
+
+	stop, start, err := QuietHoursDue(quiet, now)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &QuietHoursResult{Failed: make(map[string]error)}
+	switch {
+	case stop:
+		names, err := m.quietHoursTargets(quiet.Targets)
+		if err != nil {
+			return nil, err
+		}
+		for _, name := range names {
+			instance, err := m.configMgr.GetInstance(name)
+			if err != nil || instance.Status != types.StatusRunning || instance.ServiceType == "custom-project" {
+				continue
+			}
+			if err := m.Stop(name); err != nil {
+				result.Failed[name] = err
+				continue
+			}
+			result.Stopped = append(result.Stopped, name)
+		}
+		err = m.configMgr.Update(func(c *types.Config) error {
+			if c.QuietHours != nil {
+				c.QuietHours.Stopped = result.Stopped
+				c.QuietHours.LastStop = now
+			}
+			return nil
+		})
+		return result, err
+
+	case start:
+		for _, name := range quiet.Stopped {
+			instance, err := m.configMgr.GetInstance(name)
+			if err != nil || instance.WantsRunning() {
+				continue // Removed, or started by hand
+			}
+			if err := m.Start(name); err != nil && !errors.Is(err, types.ErrAlreadyRunning) {
+				result.Failed[name] = err
+				continue
+			}
+			result.Started = append(result.Started, name)
+		}
+		err := m.configMgr.Update(func(c *types.Config) error {
+			if c.QuietHours != nil {
+				c.QuietHours.Stopped = nil
+			}
+			return nil
+		})
+		return result, err
+	}
+
+	return result, nil
+}
+
+// quietHoursTargets returns the services quiet hours apply to: the given
+// services and groups, or all services
+func (m *Manager) quietHoursTargets(targets []string) ([]string, error) {
+	if len(targets) > 0 {
+		names, err := m.configMgr.ResolveNames(targets)
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve quiet hours targets: %w", err)
+		}
+		return names, nil
+	}
+
+	instances, err := m.configMgr.ListInstances()
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(instances))
+	for _, instance := range instances {
+		names = append(names, instance.Name)
+	}
+	sort.Strings(names)
+	return names, nil
+}
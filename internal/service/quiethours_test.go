@@ -0,0 +1,47 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/dokulabs/doku-cli/pkg/types"
+)
+
+func TestQuietHoursDue(t *testing.T) {
+	at := func(day, hour, minute int) time.Time {
+		return time.Date(2026, time.March, day, hour, minute, 0, 0, time.Local)
+	}
+
+	tests := []struct {
+		name      string
+		quiet     *types.QuietHoursConfig
+		now       time.Time
+		wantStop  bool
+		wantStart bool
+	}{
+		{"off", nil, at(10, 20, 0), false, false},
+		{"work hours", &types.QuietHoursConfig{Window: "19:00-09:00"}, at(10, 12, 0), false, false},
+		{"evening, not stopped yet", &types.QuietHoursConfig{Window: "19:00-09:00"}, at(10, 19, 5), true, false},
+		{"after midnight, not stopped yet", &types.QuietHoursConfig{Window: "19:00-09:00", LastStop: at(9, 8, 0)}, at(10, 2, 0), true, false},
+		{"already stopped this period", &types.QuietHoursConfig{Window: "19:00-09:00", LastStop: at(9, 19, 0)}, at(10, 2, 0), false, false},
+		{"stopped last period", &types.QuietHoursConfig{Window: "19:00-09:00", LastStop: at(9, 19, 0)}, at(10, 19, 0), true, false},
+		{"morning, services to start", &types.QuietHoursConfig{Window: "19:00-09:00", Stopped: []string{"postgres"}}, at(10, 9, 0), false, true},
+		{"same-day window", &types.QuietHoursConfig{Window: "12:00-13:00"}, at(10, 12, 30), true, false},
+	}
+	for _, tt := range tests {
+		stop, start, err := QuietHoursDue(tt.quiet, tt.now)
+		if err != nil {
+			t.Errorf("%s: error = %v", tt.name, err)
+			continue
+		}
+		if stop != tt.wantStop || start != tt.wantStart {
+			t.Errorf("%s: QuietHoursDue() = (%v, %v), want (%v, %v)", tt.name, stop, start, tt.wantStop, tt.wantStart)
+		}
+	}
+}
+
+func TestQuietHoursDueInvalidWindow(t *testing.T) {
+	if _, _, err := QuietHoursDue(&types.QuietHoursConfig{Window: "7pm-9am"}, time.Now()); err == nil {
+		t.Error("QuietHoursDue() should fail for an invalid window")
+	}
+}
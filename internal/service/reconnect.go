@@ -0,0 +1,80 @@
+package service
+
+import (
+	"fmt"
+
+	"github.com/dokulabs/doku-cli/internal/docker"
+)
+
+// Disconnected returns the containers of an instance that exist but aren't
+// connected to doku-network. Instances on the host network have none.
+func (m *Manager) Disconnected(instanceName string) ([]string, error) {
+	instance, err := m.configMgr.GetInstance(instanceName)
+	if err != nil {
+		return nil, fmt.Errorf("instance not found: %w", err)
+	}
+	if instance.UsesHostNetwork() {
+		return nil, nil
+	}
+
+	var names []string
+	if instance.IsMultiContainer {
+		for _, c := range instance.Containers {
+			names = append(names, c.FullName)
+		}
+	} else {
+		names = []string{instance.ContainerName}
+	}
+
+	var disconnected []string
+	for _, name := range names {
+		info, err := m.dockerClient.ContainerInspect(name)
+		if err != nil {
+			continue
+		}
+		if info.NetworkSettings != nil {
+			if _, ok := info.NetworkSettings.Networks[docker.NetworkName()]; ok {
+				continue
+			}
+		}
+		disconnected = append(disconnected, name)
+	}
+	return disconnected, nil
+}
+
+// Reconnect connects the containers of an instance that lost their
+// doku-network connection back to it, with the aliases they were created
+// with, so other services reach them by name again. It returns the
+// containers it reconnected.
+func (m *Manager) Reconnect(instanceName string) ([]string, error) {
+	disconnected, err := m.Disconnected(instanceName)
+	if err != nil || len(disconnected) == 0 {
+		return nil, err
+	}
+	instance, err := m.configMgr.GetInstance(instanceName)
+	if err != nil {
+		return nil, fmt.Errorf("instance not found: %w", err)
+	}
+
+	aliases := make(map[string][]string)
+	if instance.IsMultiContainer {
+		for _, c := range instance.Containers {
+			aliases[c.FullName] = buildNetworkAliases(instance.Name, c.Name, c.Primary)
+		}
+	} else {
+		aliases[instance.ContainerName] = []string{instance.ServiceType}
+		if instance.Name != instance.ServiceType {
+			aliases[instance.ContainerName] = append(aliases[instance.ContainerName], instance.Name)
+		}
+	}
+
+	networkMgr := docker.NewNetworkManager(m.dockerClient)
+	var reconnected []string
+	for _, name := range disconnected {
+		if err := networkMgr.ConnectContainerWithAliases(docker.NetworkName(), name, aliases[name]); err != nil {
+			return reconnected, fmt.Errorf("failed to connect %s to doku-network: %w", name, err)
+		}
+		reconnected = append(reconnected, name)
+	}
+	return reconnected, nil
+}
@@ -0,0 +1,525 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+
+	dockerTypes "github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/mount"
+	"github.com/docker/docker/api/types/network"
+	"github.com/dokulabs/doku-cli/internal/docker"
+	"github.com/dokulabs/doku-cli/internal/envfile"
+	"github.com/dokulabs/doku-cli/internal/monitoring"
+	"github.com/dokulabs/doku-cli/pkg/types"
+	"github.com/fatih/color"
+)
+
+// renamedContainer is a container of the instance being renamed, as
+// inspected before the rename, and the ID of its replacement
+type renamedContainer struct {
+	name containerName
+	info dockerTypes.ContainerJSON
+	id   string
+}
+
+// Rename renames an instance. Docker can't relabel containers or volumes,
+// so the containers are recreated under the new name and the data is
+// copied into new volumes; the env files, the instance record, and the
+// references, links and groups pointing at it follow. It returns the other
+// instances whose env files changed, which must be recreated to pick up
+// the new name.
+func (m *Manager) Rename(oldName, newName string) (*types.Instance, []string, error) {
+	instance, err := m.configMgr.GetInstance(oldName)
+	if err != nil {
+		return nil, nil, fmt.Errorf("instance not found: %w", err)
+	}
+	if m.configMgr.HasInstance(newName) {
+		return nil, nil, fmt.Errorf("instance '%s' already exists", newName)
+	}
+	if instance.ServiceType == "custom-project" {
+		return nil, nil, fmt.Errorf("'%s' is a custom project and can't be renamed", oldName)
+	}
+	if instance.ReplicaCount() > 1 {
+		return nil, nil, fmt.Errorf("'%s' runs %d replicas; scale it to 1 with 'doku scale %s 1' before renaming it", oldName, instance.ReplicaCount(), oldName)
+	}
+
+	containers := make([]*renamedContainer, 0, len(instanceContainerNames(instance)))
+	for _, c := range instanceContainerNames(instance) {
+		info, err := m.dockerClient.ContainerInspect(c.full)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to inspect %s: %w", c.full, err)
+		}
+		if info.Config == nil || info.HostConfig == nil {
+			return nil, nil, fmt.Errorf("failed to inspect %s: incomplete container info", c.full)
+		}
+		containers = append(containers, &renamedContainer{name: c, info: info})
+	}
+	if len(containers) == 0 {
+		return nil, nil, fmt.Errorf("'%s' has no containers to rename", oldName)
+	}
+
+	envs, err := m.renamedEnv(instance, newName)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	wasRunning := instance.Status == types.StatusRunning
+	if wasRunning {
+		fmt.Printf("Stopping %s...\n", oldName)
+		if err := m.Stop(oldName); err != nil && !errors.Is(err, types.ErrAlreadyStopped) {
+			return nil, nil, fmt.Errorf("failed to stop %s: %w", oldName, err)
+		}
+	}
+	restart := func() {
+		if !wasRunning {
+			return
+		}
+		if err := m.Start(oldName); err != nil && !errors.Is(err, types.ErrAlreadyRunning) {
+			color.Yellow("⚠️  Failed to start %s again: %v", oldName, err)
+		}
+	}
+
+	volumes, err := m.copyVolumesTo(oldName, newName)
+	if err != nil {
+		restart()
+		return nil, nil, err
+	}
+
+	if err := m.createRenamedContainers(containers, envs, volumes, oldName, newName); err != nil {
+		m.removeVolumeList(newVolumeNames(volumes))
+		restart()
+		return nil, nil, err
+	}
+
+	// From here on the new containers exist, so the rename goes ahead
+	for _, c := range containers {
+		if err := m.dockerClient.ContainerRemove(c.name.full, true); err != nil {
+			color.Yellow("⚠️  Failed to remove container %s: %v", c.name.full, err)
+		}
+	}
+
+	if err := m.moveEnvFiles(instance, newName, envs); err != nil {
+		color.Yellow("⚠️  Failed to move the env files of %s: %v", oldName, err)
+	}
+
+	var renamed *types.Instance
+	err = m.configMgr.Update(func(cfg *types.Config) error {
+		inst, ok := cfg.Instances[oldName]
+		if !ok {
+			return fmt.Errorf("instance not found: %s", oldName)
+		}
+		renameInstance(inst, oldName, newName)
+		for i, c := range containers {
+			if inst.IsMultiContainer {
+				inst.Containers[i].FullName = renameResource(c.name.full, oldName, newName)
+				inst.Containers[i].ContainerID = c.id
+			} else {
+				inst.ContainerName = renameResource(c.name.full, oldName, newName)
+				inst.ContainerID = c.id
+			}
+		}
+		delete(cfg.Instances, oldName)
+		cfg.Instances[newName] = inst
+
+		for _, other := range cfg.Instances {
+			for i, dep := range other.Dependencies {
+				if dep == oldName {
+					other.Dependencies[i] = newName
+				}
+			}
+			for i, dependent := range other.DependencyOf {
+				if dependent == oldName {
+					other.DependencyOf[i] = newName
+				}
+			}
+			for i, link := range other.Links {
+				if link.Service == oldName {
+					other.Links[i].Service = newName
+				}
+			}
+		}
+		for group, members := range cfg.Groups {
+			for i, member := range members {
+				if member == oldName {
+					cfg.Groups[group][i] = newName
+				}
+			}
+		}
+
+		renamed = inst
+		return nil
+	})
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to update config: %w", err)
+	}
+
+	dependents, err := m.renameReferences(oldName, newName)
+	if err != nil {
+		color.Yellow("⚠️  %v", err)
+	}
+
+	if wasRunning {
+		fmt.Printf("Starting %s...\n", newName)
+		if err := m.Start(newName); err != nil && !errors.Is(err, types.ErrAlreadyRunning) {
+			return renamed, dependents, fmt.Errorf("renamed, but failed to start %s: %w", newName, err)
+		}
+	}
+
+	m.removeVolumeList(oldVolumeNames(volumes))
+
+	return renamed, dependents, nil
+}
+
+// renamedEnv loads the env files of an instance, keyed by container name
+// ("" for the main file), pointing monitoring instrumentation at newName
+func (m *Manager) renamedEnv(instance *types.Instance, newName string) (map[string]map[string]string, error) {
+	envMgr := envfile.NewManager(m.configMgr.GetDokuDir())
+
+	var monitoringEnv map[string]string
+	if cfg, err := m.configMgr.Get(); err == nil {
+		monitoringEnv = monitoring.GetInstrumentationEnv(newName, &cfg.Monitoring)
+	}
+
+	envs := make(map[string]map[string]string)
+	for _, name := range append([]string{""}, containerShortNames(instance)...) {
+		path := envMgr.GetServiceEnvPath(instance.Name, name)
+		if !envMgr.Exists(path) {
+			continue
+		}
+		env, err := envMgr.Load(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", path, err)
+		}
+		envs[name] = envfile.MergeEnv(env, monitoringEnv)
+	}
+	return envs, nil
+}
+
+// copyVolumesTo copies every volume of an instance into a new volume named
+// and labelled after newName. It returns the old volume names mapped to the
+// new ones; on failure the new volumes are removed again.
+func (m *Manager) copyVolumesTo(oldName, newName string) (map[string]string, error) {
+	volumes, err := m.dockerClient.ListInstanceVolumes(context.Background(), oldName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list volumes: %w", err)
+	}
+
+	renamed := make(map[string]string, len(volumes))
+	for _, vol := range volumes {
+		target := renameResource(vol.Name, oldName, newName)
+		if target == vol.Name {
+			target = docker.GenerateContainerName(newName) + "-" + vol.Name
+		}
+		if _, err := m.dockerClient.VolumeCreate(target, docker.InstanceLabels(newName)); err != nil {
+			m.removeVolumeList(newVolumeNames(renamed))
+			return nil, fmt.Errorf("failed to create volume %s: %w", target, err)
+		}
+		renamed[vol.Name] = target
+
+		fmt.Printf("Copying volume %s → %s...\n", vol.Name, target)
+		if err := m.dockerClient.CopyVolume(vol.Name, target); err != nil {
+			m.removeVolumeList(newVolumeNames(renamed))
+			return nil, err
+		}
+	}
+	return renamed, nil
+}
+
+// createRenamedContainers creates the containers of the renamed instance,
+// with the renamed labels, aliases and volumes. They are left stopped. On
+// failure the ones already created are removed again.
+func (m *Manager) createRenamedContainers(containers []*renamedContainer, envs map[string]map[string]string, volumes map[string]string, oldName, newName string) error {
+	for _, c := range containers {
+		info := c.info
+		config := *info.Config
+
+		config.Labels = renameLabels(info.Config.Labels, oldName, newName)
+		if strings.HasPrefix(info.ID, config.Hostname) {
+			config.Hostname = ""
+		}
+		if env, ok := envs[c.name.short]; ok && len(env) > 0 {
+			resolved, err := resolveEnvRefs(m.configMgr, env)
+			if err != nil {
+				m.removeRenamedContainers(containers)
+				return err
+			}
+			config.Env = envfile.EnvMapToSlice(resolved)
+		}
+
+		mounts := make([]mount.Mount, 0, len(info.Mounts))
+		for _, mp := range info.Mounts {
+			source := mp.Source
+			if mp.Type == mount.TypeVolume && mp.Name != "" {
+				source = mp.Name
+				if target, ok := volumes[mp.Name]; ok {
+					source = target
+				}
+			}
+			mounts = append(mounts, mount.Mount{
+				Type:     mp.Type,
+				Source:   source,
+				Target:   mp.Destination,
+				ReadOnly: !mp.RW,
+			})
+		}
+
+		hostConfig := &container.HostConfig{
+			RestartPolicy: info.HostConfig.RestartPolicy,
+			Mounts:        mounts,
+			LogConfig:     info.HostConfig.LogConfig,
+			PortBindings:  info.HostConfig.PortBindings,
+			Resources:     info.HostConfig.Resources,
+		}
+
+		var networkConfig *network.NetworkingConfig
+		if info.HostConfig.NetworkMode.IsHost() {
+			hostConfig.NetworkMode = container.NetworkMode(types.NetworkModeHost)
+			hostConfig.PortBindings = nil
+		} else {
+			var aliases []string
+			if info.NetworkSettings != nil {
+				if endpoint, ok := info.NetworkSettings.Networks[docker.NetworkName()]; ok && endpoint != nil {
+					for _, alias := range endpoint.Aliases {
+						if !strings.HasPrefix(info.ID, alias) {
+							aliases = append(aliases, renameAlias(alias, oldName, newName))
+						}
+					}
+				}
+			}
+			networkConfig = &network.NetworkingConfig{
+				EndpointsConfig: map[string]*network.EndpointSettings{
+					docker.NetworkName(): {Aliases: aliases},
+				},
+			}
+		}
+
+		name := renameResource(c.name.full, oldName, newName)
+		id, err := m.dockerClient.ContainerCreate(&config, hostConfig, networkConfig, name)
+		if err != nil {
+			m.removeRenamedContainers(containers)
+			return fmt.Errorf("failed to create container %s: %w", name, err)
+		}
+		c.id = id
+	}
+	return nil
+}
+
+// removeRenamedContainers removes the replacement containers created so far
+func (m *Manager) removeRenamedContainers(containers []*renamedContainer) {
+	for _, c := range containers {
+		if c.id != "" {
+			m.dockerClient.ContainerRemove(c.id, true)
+			c.id = ""
+		}
+	}
+}
+
+// moveEnvFiles moves the env files of an instance, with their backups, to
+// newName and writes the renamed environment into them
+func (m *Manager) moveEnvFiles(instance *types.Instance, newName string, envs map[string]map[string]string) error {
+	envMgr := envfile.NewManager(m.configMgr.GetDokuDir())
+
+	for name, env := range envs {
+		newPath := envMgr.GetServiceEnvPath(newName, name)
+		if err := envMgr.Move(envMgr.GetServiceEnvPath(instance.Name, name), newPath); err != nil {
+			return err
+		}
+		if err := envMgr.Save(newPath, env); err != nil {
+			return err
+		}
+	}
+
+	// Init containers have their own env files
+	initPrefix := instance.Name + "-init-"
+	for _, path := range envMgr.FindEnvFilesByPrefix(instance.Name) {
+		base := filepath.Base(path)
+		if !strings.HasPrefix(base, initPrefix) {
+			continue
+		}
+		newPath := filepath.Join(filepath.Dir(path), newName+strings.TrimPrefix(base, instance.Name))
+		if err := envMgr.Move(path, newPath); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// renameReferences points the ${old.FIELD} references in the env files of
+// other instances at the new name. It returns the instances that changed,
+// and those linked to the renamed one, after refreshing their links.
+func (m *Manager) renameReferences(oldName, newName string) ([]string, error) {
+	instances, err := m.configMgr.ListInstances()
+	if err != nil {
+		return nil, err
+	}
+
+	envMgr := envfile.NewManager(m.configMgr.GetDokuDir())
+	var dependents []string
+	var errs []error
+	for _, inst := range instances {
+		if inst.Name == newName {
+			continue
+		}
+
+		paths := []string{m.instanceEnvPath(inst)}
+		for _, name := range containerShortNames(inst) {
+			paths = append(paths, envMgr.GetServiceEnvPath(inst.Name, name))
+		}
+
+		changed := false
+		for _, path := range paths {
+			if !envMgr.Exists(path) {
+				continue
+			}
+			env, err := envMgr.Load(path)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("failed to read %s: %w", path, err))
+				continue
+			}
+			env, renamed := envfile.RenameReferences(env, oldName, newName)
+			if !renamed {
+				continue
+			}
+			if err := envMgr.Save(path, env); err != nil {
+				errs = append(errs, fmt.Errorf("failed to update %s: %w", path, err))
+				continue
+			}
+			changed = true
+		}
+
+		for _, link := range inst.Links {
+			if link.Service == newName {
+				if _, err := m.Relink(inst.Name); err != nil {
+					errs = append(errs, fmt.Errorf("failed to refresh the links of %s: %w", inst.Name, err))
+				}
+				changed = true
+				break
+			}
+		}
+
+		if changed {
+			dependents = append(dependents, inst.Name)
+		}
+	}
+
+	return dependents, errors.Join(errs...)
+}
+
+// removeVolumeList removes volumes, warning about those that can't be
+func (m *Manager) removeVolumeList(names []string) {
+	for _, name := range names {
+		if err := m.dockerClient.VolumeRemove(name, false); err != nil {
+			color.Yellow("⚠️  Failed to remove volume %s: %v", name, err)
+		}
+	}
+}
+
+func oldVolumeNames(volumes map[string]string) []string {
+	names := make([]string, 0, len(volumes))
+	for name := range volumes {
+		names = append(names, name)
+	}
+	return names
+}
+
+func newVolumeNames(volumes map[string]string) []string {
+	names := make([]string, 0, len(volumes))
+	for _, name := range volumes {
+		names = append(names, name)
+	}
+	return names
+}
+
+// renameInstance updates the fields of an instance record that contain its
+// name. Container names and IDs are left to the caller.
+func renameInstance(instance *types.Instance, oldName, newName string) {
+	instance.Name = newName
+	instance.URL = strings.Replace(instance.URL, "://"+oldName+".", "://"+newName+".", 1)
+	instance.ConnectionString = renameConnectionString(instance.ConnectionString, oldName, newName)
+	if instance.Traefik.Subdomain == oldName {
+		instance.Traefik.Subdomain = newName
+	}
+	instance.UpdatedAt = time.Now()
+}
+
+// renameConnectionString replaces the host oldName in a connection string,
+// whether it's a URL (postgresql://user@old:5432, https://old.doku.local)
+// or a bare host:port
+func renameConnectionString(conn, oldName, newName string) string {
+	if strings.HasPrefix(conn, oldName+":") {
+		return newName + strings.TrimPrefix(conn, oldName)
+	}
+	for _, sep := range []string{"@", "://"} {
+		for _, suffix := range []string{":", ".", "/"} {
+			conn = strings.Replace(conn, sep+oldName+suffix, sep+newName+suffix, 1)
+		}
+		if strings.HasSuffix(conn, sep+oldName) {
+			conn = strings.TrimSuffix(conn, oldName) + newName
+		}
+	}
+	return conn
+}
+
+// renameLabels returns a copy of a container's labels for the renamed
+// instance: its Traefik routers and services, their Host rules, and the
+// instance and monitoring labels
+func renameLabels(labels map[string]string, oldName, newName string) map[string]string {
+	names := map[string]string{
+		oldName:                               newName,
+		docker.GenerateContainerName(oldName): docker.GenerateContainerName(newName),
+	}
+
+	renamed := make(map[string]string, len(labels))
+	for key, value := range labels {
+		parts := strings.SplitN(key, ".", 5)
+		if len(parts) == 5 && parts[0] == "traefik" && (parts[2] == "routers" || parts[2] == "services") {
+			if name, ok := names[parts[3]]; ok {
+				parts[3] = name
+				key = strings.Join(parts, ".")
+			}
+			if parts[2] == "routers" && parts[4] == "service" {
+				if name, ok := names[value]; ok {
+					value = name
+				}
+			}
+			if parts[2] == "routers" && parts[4] == "rule" {
+				value = strings.ReplaceAll(value, "`"+oldName+".", "`"+newName+".")
+			}
+		}
+
+		switch key {
+		case docker.LabelInstance, "doku.monitoring.service":
+			if value == oldName {
+				value = newName
+			}
+		}
+		renamed[key] = value
+	}
+	return renamed
+}
+
+// renameAlias returns the network alias of the renamed instance
+// corresponding to alias; aliases that don't contain its name, like the
+// service type, are kept
+func renameAlias(alias, oldName, newName string) string {
+	if alias == oldName {
+		return newName
+	}
+	return renameResource(alias, oldName, newName)
+}
+
+// renameResource returns the name of a container or volume of the renamed
+// instance: doku-old-data becomes doku-new-data. Names without the
+// instance's prefix are kept.
+func renameResource(name, oldName, newName string) string {
+	prefix := docker.GenerateContainerName(oldName)
+	if name == prefix || strings.HasPrefix(name, prefix+"-") {
+		return docker.GenerateContainerName(newName) + strings.TrimPrefix(name, prefix)
+	}
+	return name
+}
@@ -0,0 +1,121 @@
+package service
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/dokulabs/doku-cli/pkg/types"
+)
+
+func TestRenameLabels(t *testing.T) {
+	labels := map[string]string{
+		"doku.instance":                                          "pg",
+		"doku.service":                                           "postgres",
+		"doku.monitoring.service":                                "pg",
+		"traefik.enable":                                         "true",
+		"traefik.http.routers.doku-pg.rule":                      "Host(`pg.doku.local`)",
+		"traefik.http.routers.doku-pg.tls":                       "true",
+		"traefik.http.routers.doku-pg.service":                   "doku-pg",
+		"traefik.http.services.doku-pg.loadbalancer.server.port": "5432",
+		"traefik.http.routers.pgadmin.rule":                      "Host(`pgadmin.doku.local`)",
+	}
+
+	want := map[string]string{
+		"doku.instance":                                               "pg-main",
+		"doku.service":                                                "postgres",
+		"doku.monitoring.service":                                     "pg-main",
+		"traefik.enable":                                              "true",
+		"traefik.http.routers.doku-pg-main.rule":                      "Host(`pg-main.doku.local`)",
+		"traefik.http.routers.doku-pg-main.tls":                       "true",
+		"traefik.http.routers.doku-pg-main.service":                   "doku-pg-main",
+		"traefik.http.services.doku-pg-main.loadbalancer.server.port": "5432",
+		"traefik.http.routers.pgadmin.rule":                           "Host(`pgadmin.doku.local`)",
+	}
+
+	if got := renameLabels(labels, "pg", "pg-main"); !reflect.DeepEqual(got, want) {
+		t.Errorf("renameLabels() = %v, want %v", got, want)
+	}
+	if labels["doku.instance"] != "pg" {
+		t.Error("renameLabels() modified its input")
+	}
+}
+
+func TestRenameResource(t *testing.T) {
+	tests := []struct {
+		name string
+		want string
+	}{
+		{"doku-pg", "doku-db"},
+		{"doku-pg-postgresql-data-0", "doku-db-postgresql-data-0"},
+		{"doku-pgadmin", "doku-pgadmin"},
+		{"postgres", "postgres"},
+	}
+
+	for _, tt := range tests {
+		if got := renameResource(tt.name, "pg", "db"); got != tt.want {
+			t.Errorf("renameResource(%q) = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestRenameAlias(t *testing.T) {
+	tests := []struct {
+		alias string
+		want  string
+	}{
+		{"pg", "db"},
+		{"postgres", "postgres"},
+		{"doku-pg-frontend", "doku-db-frontend"},
+		{"frontend", "frontend"},
+	}
+
+	for _, tt := range tests {
+		if got := renameAlias(tt.alias, "pg", "db"); got != tt.want {
+			t.Errorf("renameAlias(%q) = %q, want %q", tt.alias, got, tt.want)
+		}
+	}
+}
+
+func TestRenameConnectionString(t *testing.T) {
+	tests := []struct {
+		conn string
+		want string
+	}{
+		{"postgresql://postgres:secret@pg:5432/postgres", "postgresql://postgres:secret@db:5432/postgres"},
+		{"redis://pg:6379", "redis://db:6379"},
+		{"https://pg.doku.local", "https://db.doku.local"},
+		{"pg:5432", "db:5432"},
+		{"localhost:5432", "localhost:5432"},
+		{"postgresql://postgres@pgadmin:5432", "postgresql://postgres@pgadmin:5432"},
+	}
+
+	for _, tt := range tests {
+		if got := renameConnectionString(tt.conn, "pg", "db"); got != tt.want {
+			t.Errorf("renameConnectionString(%q) = %q, want %q", tt.conn, got, tt.want)
+		}
+	}
+}
+
+func TestRenameInstance(t *testing.T) {
+	instance := &types.Instance{
+		Name:             "pg",
+		URL:              "https://pg.doku.local",
+		ConnectionString: "postgresql://postgres@pg:5432",
+		Traefik:          types.TraefikInstanceConfig{Subdomain: "pg"},
+	}
+
+	renameInstance(instance, "pg", "db")
+
+	if instance.Name != "db" {
+		t.Errorf("Name = %q, want db", instance.Name)
+	}
+	if instance.URL != "https://db.doku.local" {
+		t.Errorf("URL = %q, want https://db.doku.local", instance.URL)
+	}
+	if instance.ConnectionString != "postgresql://postgres@db:5432" {
+		t.Errorf("ConnectionString = %q", instance.ConnectionString)
+	}
+	if instance.Traefik.Subdomain != "db" {
+		t.Errorf("Traefik.Subdomain = %q, want db", instance.Traefik.Subdomain)
+	}
+}
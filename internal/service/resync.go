@@ -0,0 +1,215 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	dockertypes "github.com/docker/docker/api/types"
+	"github.com/dokulabs/doku-cli/internal/events"
+	"github.com/dokulabs/doku-cli/pkg/types"
+)
+
+// ResyncResult reports what Resync changed
+type ResyncResult struct {
+	Refreshed []string         // Instances whose recorded status was out of date
+	Started   []string         // Instances started because they should be running
+	Missing   []string         // Instances whose containers are gone
+	Failed    map[string]error // Instances that could not be started
+}
+
+// Changed reports whether Resync changed anything or found a problem
+func (r *ResyncResult) Changed() bool {
+	return len(r.Refreshed) > 0 || len(r.Started) > 0 || len(r.Missing) > 0 || len(r.Failed) > 0
+}
+
+// Resync brings the recorded instance statuses back in line with Docker,
+// e.g. after the Docker daemon restarted. With restart, instances that
+// should be running but aren't are started again; those stopped with
+// 'doku stop' stay stopped. Instances whose containers are gone are
+// recorded as missing. It lists the containers once, so it is cheap
+// enough to run before any command.
+func (m *Manager) Resync(ctx context.Context, restart bool) (*ResyncResult, error) {
+	containers, err := m.dockerClient.ListManagedContainers(ctx)
+	if err != nil {
+		return nil, err
+	}
+	states := containerStates(containers)
+
+	instances, err := m.configMgr.ListInstances()
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(instances, func(i, j int) bool {
+		return instances[i].Name < instances[j].Name
+	})
+
+	result := &ResyncResult{Failed: make(map[string]error)}
+	for _, instance := range instances {
+		observed, ok := observedStatus(instance, states)
+		if !ok {
+			// Recorded as missing until recreated with 'doku sync'
+			result.Missing = append(result.Missing, instance.Name)
+			observed = types.StatusMissing
+		}
+
+		// Report instances that went down since, unless Watch already did
+		if ok && instance.Status == types.StatusRunning && observed != instance.Status && instance.WantsRunning() {
+			switch observed {
+			case types.StatusFailed:
+				events.Emit(events.New(events.TypeFail, instance.Name, "crashed"))
+			case types.StatusStopped:
+				events.Emit(events.New(events.TypeFail, instance.Name, "stopped unexpectedly"))
+			}
+		}
+
+		started := false
+		if ok && restart && instance.WantsRunning() && observed != types.StatusRunning {
+			if err := m.startContainers(instance); err != nil {
+				result.Failed[instance.Name] = err
+				events.Emit(events.New(events.TypeFail, instance.Name, "could not be started: %v", err))
+			} else {
+				result.Started = append(result.Started, instance.Name)
+				observed = types.StatusRunning
+				started = true
+			}
+		}
+
+		if observed != instance.Status {
+			if ok && !started {
+				result.Refreshed = append(result.Refreshed, instance.Name)
+			}
+			if err := m.recordStatus(instance, observed); err != nil {
+				return result, err
+			}
+		}
+	}
+
+	return result, nil
+}
+
+// recordStatus records the status observed of an instance. Only the
+// status is written, over the instance as currently saved, so that it
+// doesn't undo a change made since instance was read, e.g. by the API.
+func (m *Manager) recordStatus(instance *types.Instance, status types.ServiceStatus) error {
+	return m.configMgr.EditInstance(instance.Name, func(saved *types.Instance) {
+		// Keep the intent of instances recorded before DesiredState
+		// existed, which WantsRunning reads from their old status
+		if saved.DesiredState == "" {
+			saved.DesiredState = types.StatusStopped
+			if saved.Status == types.StatusRunning {
+				saved.DesiredState = types.StatusRunning
+			}
+		}
+		saved.Status = status
+		saved.UpdatedAt = time.Now()
+	})
+}
+
+// startContainers starts an instance's containers whatever its recorded
+// status, which Start refuses to do when it is already "running"
+func (m *Manager) startContainers(instance *types.Instance) error {
+	if instance.IsMultiContainer {
+		return m.startMultiContainerService(instance)
+	}
+	if err := m.dockerClient.ContainerStart(instance.ContainerName); err != nil {
+		return fmt.Errorf("failed to start container: %w", err)
+	}
+	return nil
+}
+
+// containerStates maps container names and IDs to their state, e.g.
+// "running" or "exited"
+func containerStates(containers []dockertypes.Container) map[string]string {
+	states := make(map[string]string, len(containers)*2)
+	for _, ctr := range containers {
+		states[ctr.ID] = ctr.State
+		for _, name := range ctr.Names {
+			states[strings.TrimPrefix(name, "/")] = ctr.State
+		}
+	}
+	return states
+}
+
+// observedStatus returns an instance's status from the states of its
+// containers, or false if a container no longer exists. Like GetStatus, a
+// partially running multi-container instance counts as running.
+func observedStatus(instance *types.Instance, states map[string]string) (types.ServiceStatus, bool) {
+	var names []string
+	if instance.IsMultiContainer {
+		for _, c := range instance.Containers {
+			name := c.ContainerID
+			if _, ok := states[name]; !ok {
+				name = c.FullName
+			}
+			names = append(names, name)
+		}
+	} else {
+		names = []string{instance.ContainerName}
+	}
+	if len(names) == 0 {
+		return types.StatusUnknown, false
+	}
+
+	running, failed := 0, 0
+	for _, name := range names {
+		state, ok := states[name]
+		if !ok {
+			return types.StatusUnknown, false
+		}
+		switch state {
+		case "running", "restarting":
+			running++
+		case "dead":
+			failed++
+		}
+	}
+
+	switch {
+	case failed > 0:
+		return types.StatusFailed, true
+	case running > 0:
+		return types.StatusRunning, true
+	default:
+		return types.StatusStopped, true
+	}
+}
+
+// Restarting returns the instances with a container Docker keeps
+// restarting, i.e. that crash-loop
+func (m *Manager) Restarting(ctx context.Context) ([]string, error) {
+	containers, err := m.dockerClient.ListManagedContainers(ctx)
+	if err != nil {
+		return nil, err
+	}
+	instances, err := m.configMgr.ListInstances()
+	if err != nil {
+		return nil, err
+	}
+	return restartingInstances(instances, containerStates(containers)), nil
+}
+
+// restartingInstances returns the instances with a container in the
+// "restarting" state, sorted
+func restartingInstances(instances []*types.Instance, states map[string]string) []string {
+	var restarting []string
+	for _, instance := range instances {
+		names := []string{instance.ContainerName}
+		if instance.IsMultiContainer {
+			names = names[:0]
+			for _, c := range instance.Containers {
+				names = append(names, c.ContainerID, c.FullName)
+			}
+		}
+		for _, name := range names {
+			if states[name] == "restarting" {
+				restarting = append(restarting, instance.Name)
+				break
+			}
+		}
+	}
+	sort.Strings(restarting)
+	return restarting
+}
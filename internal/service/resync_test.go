@@ -0,0 +1,66 @@
+package service
+
+import (
+	"testing"
+
+	dockertypes "github.com/docker/docker/api/types"
+	"github.com/dokulabs/doku-cli/pkg/types"
+)
+
+func TestObservedStatus(t *testing.T) {
+	states := containerStates([]dockertypes.Container{
+		{ID: "aaa", Names: []string{"/doku-postgres"}, State: "running"},
+		{ID: "bbb", Names: []string{"/doku-redis"}, State: "exited"},
+		{ID: "ccc", Names: []string{"/doku-signoz-frontend"}, State: "running"},
+		{ID: "ddd", Names: []string{"/doku-signoz-query"}, State: "exited"},
+		{ID: "eee", Names: []string{"/doku-broken"}, State: "dead"},
+	})
+
+	tests := []struct {
+		name     string
+		instance *types.Instance
+		want     types.ServiceStatus
+		exists   bool
+	}{
+		{"running", &types.Instance{ContainerName: "doku-postgres"}, types.StatusRunning, true},
+		{"stopped", &types.Instance{ContainerName: "doku-redis"}, types.StatusStopped, true},
+		{"dead", &types.Instance{ContainerName: "doku-broken"}, types.StatusFailed, true},
+		{"missing", &types.Instance{ContainerName: "doku-gone"}, types.StatusUnknown, false},
+		{"partially running", &types.Instance{
+			IsMultiContainer: true,
+			Containers: []types.ContainerInfo{
+				{ContainerID: "ccc"},
+				{ContainerID: "stale-id", FullName: "doku-signoz-query"},
+			},
+		}, types.StatusRunning, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := observedStatus(tt.instance, states)
+			if got != tt.want || ok != tt.exists {
+				t.Errorf("observedStatus() = %v, %v; want %v, %v", got, ok, tt.want, tt.exists)
+			}
+		})
+	}
+}
+
+func TestRestartingInstances(t *testing.T) {
+	states := containerStates([]dockertypes.Container{
+		{ID: "aaa", Names: []string{"/doku-postgres"}, State: "restarting"},
+		{ID: "bbb", Names: []string{"/doku-redis"}, State: "running"},
+		{ID: "ccc", Names: []string{"/doku-signoz-query"}, State: "restarting"},
+	})
+	instances := []*types.Instance{
+		{Name: "redis", ContainerName: "doku-redis"},
+		{Name: "signoz", IsMultiContainer: true, Containers: []types.ContainerInfo{
+			{ContainerID: "stale-id", FullName: "doku-signoz-query"},
+		}},
+		{Name: "postgres", ContainerName: "doku-postgres"},
+	}
+
+	got := restartingInstances(instances, states)
+	if len(got) != 2 || got[0] != "postgres" || got[1] != "signoz" {
+		t.Errorf("restartingInstances() = %v, want [postgres signoz]", got)
+	}
+}
@@ -0,0 +1,43 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/dokulabs/doku-cli/internal/docker"
+)
+
+// Scale runs replicas copies of a single-container instance, the original
+// included. Replicas share its Traefik labels, so requests are balanced
+// across them, and its network aliases, but not its host ports. Services
+// keeping data in volumes can't be scaled, as the replicas would share it.
+func (m *Manager) Scale(instanceName string, replicas int) error {
+	instance, err := m.configMgr.GetInstance(instanceName)
+	if err != nil {
+		return fmt.Errorf("instance not found: %w", err)
+	}
+	if replicas < 1 {
+		return fmt.Errorf("replicas must be at least 1, got %d", replicas)
+	}
+	if instance.IsMultiContainer {
+		return fmt.Errorf("'%s' is a multi-container service and can't be scaled", instanceName)
+	}
+	if instance.UsesHostNetwork() {
+		return fmt.Errorf("'%s' uses the host network and can't be scaled", instanceName)
+	}
+
+	if err := m.dockerClient.SyncReplicas(instance.ContainerName, replicas, false); err != nil {
+		if errors.Is(err, docker.ErrStatefulReplicas) {
+			return fmt.Errorf("'%s' can't be scaled: %w", instanceName, err)
+		}
+		return err
+	}
+
+	instance.Replicas = 0
+	if replicas > 1 {
+		instance.Replicas = replicas
+	}
+	instance.UpdatedAt = time.Now()
+	return m.configMgr.UpdateInstance(instanceName, instance)
+}
@@ -0,0 +1,217 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/dokulabs/doku-cli/internal/docker"
+	"github.com/dokulabs/doku-cli/internal/events"
+	"github.com/dokulabs/doku-cli/pkg/types"
+)
+
+// watchRetryDelay is how long Watch waits before following Docker events
+// again after the stream broke, e.g. because the daemon restarted
+const watchRetryDelay = 5 * time.Second
+
+// failNotifyInterval keeps an instance that crash-loops from raising a
+// fail event on every crash
+const failNotifyInterval = time.Minute
+
+// StatusChange is a change of an instance's status seen by Watch
+type StatusChange struct {
+	Instance string
+	Status   types.ServiceStatus
+	Reason   string // Why it changed, e.g. "crashed (exit code 1)" or "started"
+	Health   string // Health reported by a health check, e.g. "unhealthy", if it changed
+	Time     time.Time
+}
+
+// Watch follows the Docker events of Doku's containers and records the
+// status of instances as they change, until ctx is done. onChange, if not
+// nil, is called for every change, including health check results. Crashes and OOM kills of instances that
+// should be running raise fail events. It is meant to run in the
+// background of long-running commands, instead of polling containers:
+//
+//	go serviceMgr.Watch(ctx, nil)
+func (m *Manager) Watch(ctx context.Context, onChange func(StatusChange)) {
+	lastFail := make(map[string]time.Time)
+	for {
+		_ = m.watch(ctx, onChange, lastFail)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(watchRetryDelay):
+		}
+	}
+}
+
+// watch follows Docker events until the stream breaks or ctx is done
+func (m *Manager) watch(ctx context.Context, onChange func(StatusChange), lastFail map[string]time.Time) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	// Subscribe first, so nothing happening while catching up is missed
+	stream, errs := m.dockerClient.ContainerEvents(ctx)
+
+	result, err := m.Resync(ctx, false)
+	if err != nil {
+		return err
+	}
+	if onChange != nil {
+		for _, name := range append(result.Refreshed, result.Missing...) {
+			if instance, err := m.configMgr.GetInstance(name); err == nil {
+				onChange(StatusChange{Instance: name, Status: instance.Status, Reason: "resynced", Time: time.Now()})
+			}
+		}
+	}
+	containers, err := m.dockerClient.ListManagedContainers(ctx)
+	if err != nil {
+		return err
+	}
+	live := newLiveStates(containerStates(containers))
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case err := <-errs:
+			return err
+		case e, ok := <-stream:
+			if !ok {
+				return ctx.Err()
+			}
+			reason, crashed := live.apply(e)
+			m.recordEvent(e, reason, crashed, live.states, onChange, lastFail)
+		}
+	}
+}
+
+// recordEvent records the status of the instance a container event is of
+func (m *Manager) recordEvent(e docker.ContainerEvent, reason string, crashed bool, states map[string]string, onChange func(StatusChange), lastFail map[string]time.Time) {
+	// Reload, as other doku commands may have changed the instances since
+	if _, err := m.configMgr.Load(); err != nil {
+		return
+	}
+	instances, err := m.configMgr.ListInstances()
+	if err != nil {
+		return
+	}
+	instance := instanceOfContainer(instances, e)
+	if instance == nil {
+		return
+	}
+
+	if crashed && instance.WantsRunning() && e.Time.Sub(lastFail[instance.Name]) >= failNotifyInterval {
+		lastFail[instance.Name] = e.Time
+		events.Emit(events.New(events.TypeFail, instance.Name, "%s", reason))
+	}
+
+	observed, ok := observedStatus(instance, states)
+	if !ok {
+		observed = types.StatusMissing
+	}
+	if observed == instance.Status {
+		if e.Health != "" && onChange != nil {
+			onChange(StatusChange{Instance: instance.Name, Status: observed, Reason: reason, Health: e.Health, Time: e.Time})
+		}
+		return
+	}
+	if err := m.recordStatus(instance, observed); err != nil {
+		return
+	}
+	if onChange != nil {
+		onChange(StatusChange{Instance: instance.Name, Status: observed, Reason: reason, Time: e.Time})
+	}
+}
+
+// instanceOfContainer returns the instance a container belongs to, or nil
+func instanceOfContainer(instances []*types.Instance, e docker.ContainerEvent) *types.Instance {
+	for _, instance := range instances {
+		if !instance.IsMultiContainer {
+			if instance.ContainerName == e.Name || instance.ContainerName == e.ID {
+				return instance
+			}
+			continue
+		}
+		for _, c := range instance.Containers {
+			if c.FullName == e.Name || c.ContainerID == e.ID {
+				return instance
+			}
+		}
+	}
+	return nil
+}
+
+// liveStates follows the states of containers through their events
+type liveStates struct {
+	states map[string]string // Container names and IDs to their state, as containerStates
+	killed map[string]bool   // Containers asked to stop, whose exit isn't a crash
+	oom    map[string]bool   // Containers the kernel killed for running out of memory
+}
+
+func newLiveStates(states map[string]string) *liveStates {
+	return &liveStates{
+		states: states,
+		killed: make(map[string]bool),
+		oom:    make(map[string]bool),
+	}
+}
+
+// apply records an event, returning what happened and whether the
+// container went down on its own
+func (l *liveStates) apply(e docker.ContainerEvent) (string, bool) {
+	switch e.Action {
+	case "start", "restart", "unpause":
+		delete(l.killed, e.ID)
+		delete(l.oom, e.ID)
+		l.set(e, "running")
+		if e.Action == "restart" {
+			return "restarted", false
+		}
+		return "started", false
+	case "pause":
+		l.set(e, "paused")
+		return "paused", false
+	case "kill":
+		l.killed[e.ID] = true
+		return "", false
+	case "oom":
+		l.oom[e.ID] = true
+		return "", false
+	case "die":
+		oom, killed := l.oom[e.ID], l.killed[e.ID]
+		delete(l.killed, e.ID)
+		delete(l.oom, e.ID)
+		if oom {
+			// Counted as failed, like GetStatus does for OOM kills
+			l.set(e, "dead")
+			return "was killed: out of memory", true
+		}
+		l.set(e, "exited")
+		switch {
+		case killed:
+			return "stopped", false
+		case e.ExitCode != 0:
+			return fmt.Sprintf("crashed (exit code %d)", e.ExitCode), true
+		default:
+			return "stopped unexpectedly", true
+		}
+	case "stop":
+		l.set(e, "exited")
+		return "stopped", false
+	case "health_status":
+		return e.Health, false
+	case "destroy":
+		delete(l.states, e.ID)
+		delete(l.states, e.Name)
+		return "removed", false
+	}
+	return "", false
+}
+
+func (l *liveStates) set(e docker.ContainerEvent, state string) {
+	l.states[e.ID] = state
+	l.states[e.Name] = state
+}
@@ -0,0 +1,81 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/dokulabs/doku-cli/internal/docker"
+	"github.com/dokulabs/doku-cli/pkg/types"
+)
+
+func TestLiveStatesApply(t *testing.T) {
+	live := newLiveStates(map[string]string{"id1": "running", "postgres": "running"})
+	event := func(action string, exitCode int) docker.ContainerEvent {
+		return docker.ContainerEvent{ID: "id1", Name: "postgres", Action: action, ExitCode: exitCode, Time: time.Now()}
+	}
+
+	tests := []struct {
+		action   string
+		exitCode int
+		reason   string
+		crashed  bool
+		state    string
+	}{
+		{"kill", 0, "", false, "running"},
+		{"die", 143, "stopped", false, "exited"}, // Stopped on request
+		{"start", 0, "started", false, "running"},
+		{"die", 1, "crashed (exit code 1)", true, "exited"},
+		{"restart", 0, "restarted", false, "running"},
+		{"oom", 0, "", false, "running"},
+		{"die", 137, "was killed: out of memory", true, "dead"},
+		{"start", 0, "started", false, "running"},
+		{"die", 0, "stopped unexpectedly", true, "exited"},
+	}
+	for _, tt := range tests {
+		reason, crashed := live.apply(event(tt.action, tt.exitCode))
+		if reason != tt.reason || crashed != tt.crashed {
+			t.Errorf("%s (exit %d) = %q, %v; expected %q, %v", tt.action, tt.exitCode, reason, crashed, tt.reason, tt.crashed)
+		}
+		if live.states["id1"] != tt.state || live.states["postgres"] != tt.state {
+			t.Errorf("after %s, state = %q, expected %q", tt.action, live.states["id1"], tt.state)
+		}
+	}
+
+	unhealthy := event("health_status", 0)
+	unhealthy.Health = "unhealthy"
+	if reason, crashed := live.apply(unhealthy); reason != "unhealthy" || crashed {
+		t.Errorf("health_status = %q, %v; expected \"unhealthy\", false", reason, crashed)
+	}
+
+	live.apply(event("destroy", 0))
+	if _, ok := live.states["postgres"]; ok {
+		t.Error("destroyed containers should be forgotten")
+	}
+}
+
+func TestInstanceOfContainer(t *testing.T) {
+	instances := []*types.Instance{
+		{Name: "postgres", ContainerName: "postgres"},
+		{
+			Name:             "signoz",
+			IsMultiContainer: true,
+			Containers: []types.ContainerInfo{
+				{Name: "query", FullName: "doku-signoz-query", ContainerID: "q1"},
+			},
+		},
+	}
+
+	tests := map[docker.ContainerEvent]string{
+		{ID: "x", Name: "postgres"}:          "postgres",
+		{ID: "q1", Name: "renamed"}:          "signoz",
+		{ID: "y", Name: "doku-signoz-query"}: "signoz",
+	}
+	for e, expected := range tests {
+		if got := instanceOfContainer(instances, e); got == nil || got.Name != expected {
+			t.Errorf("instanceOfContainer(%+v) = %v, expected %s", e, got, expected)
+		}
+	}
+	if got := instanceOfContainer(instances, docker.ContainerEvent{ID: "z", Name: "redis"}); got != nil {
+		t.Errorf("instanceOfContainer(redis) = %s, expected nil", got.Name)
+	}
+}
@@ -0,0 +1,157 @@
+// Package timing records where a command spends its time. Recording is off
+// by default and costs nothing until Enable is called, which --profile does.
+package timing
+
+import (
+	"net/http"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// EnvVar enables profiling when set to a true value
+const EnvVar = "DOKU_PROFILE"
+
+// Category groups spans in the report
+type Category string
+
+const (
+	Docker  Category = "docker"
+	Catalog Category = "catalog"
+	Config  Category = "config"
+	Network Category = "network"
+)
+
+// Span is one timed operation
+type Span struct {
+	Category Category
+	Name     string
+	Duration time.Duration
+}
+
+// Summary totals the spans of one category
+type Summary struct {
+	Category Category
+	Calls    int
+	Total    time.Duration
+}
+
+var (
+	enabled atomic.Bool
+
+	mu    sync.Mutex
+	spans []Span
+)
+
+// Enable turns recording on or off for the rest of the process
+func Enable(on bool) {
+	enabled.Store(on)
+}
+
+// Enabled reports whether recording is on
+func Enabled() bool {
+	return enabled.Load()
+}
+
+// EnabledByEnv reports whether the environment asks for profiling
+func EnabledByEnv() bool {
+	on, err := strconv.ParseBool(os.Getenv(EnvVar))
+	return err == nil && on
+}
+
+// Track starts timing an operation and returns the function that ends it,
+// meant to be deferred:
+//
+//	defer timing.Track(timing.Config, "load config")()
+func Track(category Category, name string) func() {
+	if !enabled.Load() {
+		return func() {}
+	}
+	start := time.Now()
+	return func() {
+		Record(category, name, time.Since(start))
+	}
+}
+
+// Record adds a finished span
+func Record(category Category, name string, d time.Duration) {
+	if !enabled.Load() {
+		return
+	}
+	mu.Lock()
+	spans = append(spans, Span{Category: category, Name: name, Duration: d})
+	mu.Unlock()
+}
+
+// Spans returns the recorded spans, slowest first
+func Spans() []Span {
+	mu.Lock()
+	result := make([]Span, len(spans))
+	copy(result, spans)
+	mu.Unlock()
+
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].Duration > result[j].Duration
+	})
+	return result
+}
+
+// Summarize totals the recorded spans per category, slowest first
+func Summarize() []Summary {
+	byCategory := make(map[Category]*Summary)
+	var order []Category
+	for _, span := range Spans() {
+		summary, ok := byCategory[span.Category]
+		if !ok {
+			summary = &Summary{Category: span.Category}
+			byCategory[span.Category] = summary
+			order = append(order, span.Category)
+		}
+		summary.Calls++
+		summary.Total += span.Duration
+	}
+
+	result := make([]Summary, 0, len(order))
+	for _, category := range order {
+		result = append(result, *byCategory[category])
+	}
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].Total > result[j].Total
+	})
+	return result
+}
+
+// Reset drops all recorded spans
+func Reset() {
+	mu.Lock()
+	spans = nil
+	mu.Unlock()
+}
+
+// apiVersion matches the version prefix of Docker API paths, e.g. /v1.47
+var apiVersion = regexp.MustCompile(`^/v[0-9.]+`)
+
+// Transport wraps an HTTP transport so every request is recorded as a span
+// of the given category, named after its method and path
+func Transport(category Category, base http.RoundTripper) http.RoundTripper {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &transport{category: category, base: base}
+}
+
+type transport struct {
+	category Category
+	base     http.RoundTripper
+}
+
+func (t *transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	start := time.Now()
+	resp, err := t.base.RoundTrip(req)
+	Record(t.category, req.Method+" "+apiVersion.ReplaceAllString(req.URL.Path, ""), time.Since(start))
+	return resp, err
+}
@@ -0,0 +1,73 @@
+package timing
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestTrackDisabled(t *testing.T) {
+	defer Reset()
+
+	Track(Docker, "GET /containers/json")()
+	Record(Config, "load config", time.Second)
+
+	if spans := Spans(); len(spans) != 0 {
+		t.Errorf("expected no spans while disabled, got %v", spans)
+	}
+}
+
+func TestSummarize(t *testing.T) {
+	Enable(true)
+	defer Enable(false)
+	defer Reset()
+
+	Record(Config, "load config", 10*time.Millisecond)
+	Record(Docker, "GET /containers/a/json", 30*time.Millisecond)
+	Record(Docker, "GET /containers/b/json", 20*time.Millisecond)
+	Track(Catalog, "load catalog")()
+
+	spans := Spans()
+	if len(spans) != 4 {
+		t.Fatalf("expected 4 spans, got %d", len(spans))
+	}
+	if spans[0].Name != "GET /containers/a/json" {
+		t.Errorf("expected slowest span first, got %s", spans[0].Name)
+	}
+
+	summary := Summarize()
+	if len(summary) != 3 {
+		t.Fatalf("expected 3 categories, got %v", summary)
+	}
+	if summary[0].Category != Docker || summary[0].Calls != 2 || summary[0].Total != 50*time.Millisecond {
+		t.Errorf("unexpected docker summary: %+v", summary[0])
+	}
+	if summary[1].Category != Config {
+		t.Errorf("expected config second, got %s", summary[1].Category)
+	}
+}
+
+func TestTransport(t *testing.T) {
+	Enable(true)
+	defer Enable(false)
+	defer Reset()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	client := &http.Client{Transport: Transport(Docker, nil)}
+	resp, err := client.Get(server.URL + "/v1.47/containers/json?all=1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+
+	spans := Spans()
+	if len(spans) != 1 {
+		t.Fatalf("expected 1 span, got %d", len(spans))
+	}
+	if spans[0].Category != Docker || spans[0].Name != "GET /containers/json" {
+		t.Errorf("unexpected span: %+v", spans[0])
+	}
+}
@@ -0,0 +1,56 @@
+package traefik
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/dokulabs/doku-cli/internal/docker"
+	"github.com/dokulabs/doku-cli/internal/readonly"
+)
+
+// accessLogContainerPath is where Traefik writes its access log, in the
+// logs directory of its configuration directory mounted in the container
+const accessLogContainerPath = "/var/log/traefik/access.log"
+
+// AccessLogPath returns the access log of the Traefik configured in
+// configDir
+func AccessLogPath(configDir string) string {
+	return filepath.Join(configDir, "logs", "access.log")
+}
+
+// AccessLogPath returns the path of Traefik's access log
+func (m *Manager) AccessLogPath() string {
+	return AccessLogPath(m.configDir)
+}
+
+// ClearAccessLog empties the access log. Traefik keeps it open, so it is
+// truncated rather than deleted; from inside the container if the file is
+// Traefik's (root's, on Linux).
+func (m *Manager) ClearAccessLog() error {
+	if err := readonly.Check("clear Traefik's access log"); err != nil {
+		return err
+	}
+
+	err := os.Truncate(m.AccessLogPath(), 0)
+	switch {
+	case err == nil, os.IsNotExist(err):
+		return nil
+	case !os.IsPermission(err):
+		return err
+	}
+	if running, _ := m.IsRunning(); !running {
+		return fmt.Errorf("failed to clear the access log: %w", err)
+	}
+	if err := m.dockerClient.Exec(context.Background(), docker.ExecOptions{
+		Container: ContainerName(),
+		Command:   []string{"sh", "-c", ": > " + accessLogContainerPath},
+		Stdout:    io.Discard,
+		Stderr:    io.Discard,
+	}); err != nil {
+		return fmt.Errorf("failed to clear the access log: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,72 @@
+package traefik
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/dokulabs/doku-cli/internal/docker"
+	"github.com/dokulabs/doku-cli/internal/readonly"
+)
+
+// dashboardUsersFile holds the users allowed into the dashboard, in
+// htpasswd format. The dynamic configuration includes them whenever it is
+// generated, so that regenerating it never opens the dashboard up.
+const dashboardUsersFile = "dashboard-users"
+
+// dashboardUsersPath returns the path of the dashboard's users file
+func (m *Manager) dashboardUsersPath() string {
+	return filepath.Join(m.configDir, dashboardUsersFile)
+}
+
+// dashboardUsers returns the htpasswd entries of the users allowed into
+// the dashboard, none if it is open
+func (m *Manager) dashboardUsers() []string {
+	data, err := os.ReadFile(m.dashboardUsersPath())
+	if err != nil {
+		return nil
+	}
+	var users []string
+	for _, line := range strings.Split(string(data), "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			users = append(users, line)
+		}
+	}
+	return users
+}
+
+// DashboardAuthEnabled reports whether the dashboard asks for a password
+func (m *Manager) DashboardAuthEnabled() bool {
+	return len(m.dashboardUsers()) > 0
+}
+
+// SetDashboardAuth protects the dashboard with basic auth, letting only
+// user in with password. Traefik picks the change up without a restart.
+func (m *Manager) SetDashboardAuth(user, password string) error {
+	if err := readonly.Check("protect the Traefik dashboard"); err != nil {
+		return err
+	}
+	entry, err := docker.BasicAuthUser(user + ":" + password)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(m.configDir, 0755); err != nil {
+		return fmt.Errorf("failed to create config directory: %w", err)
+	}
+	if err := os.WriteFile(m.dashboardUsersPath(), []byte(entry+"\n"), 0600); err != nil {
+		return fmt.Errorf("failed to write the dashboard's users: %w", err)
+	}
+	return m.GenerateDynamicConfig()
+}
+
+// RemoveDashboardAuth opens the dashboard to anyone again
+func (m *Manager) RemoveDashboardAuth() error {
+	if err := readonly.Check("open the Traefik dashboard"); err != nil {
+		return err
+	}
+	if err := os.Remove(m.dashboardUsersPath()); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to remove the dashboard's users: %w", err)
+	}
+	return m.GenerateDynamicConfig()
+}
@@ -0,0 +1,50 @@
+package traefik
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestDashboardAuth(t *testing.T) {
+	dir := t.TempDir()
+	m := NewManager(nil, dir, dir, "doku.local", "http")
+
+	if err := m.SetDashboardAuth("admin", "s3cret"); err != nil {
+		t.Fatal(err)
+	}
+	if !m.DashboardAuthEnabled() {
+		t.Error("DashboardAuthEnabled() = false after SetDashboardAuth")
+	}
+	dynamic, err := os.ReadFile(filepath.Join(dir, "dynamic.yml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"        - dashboard-auth\n", "    dashboard-auth:\n", `- "admin:$apr1$`} {
+		if !strings.Contains(string(dynamic), want) {
+			t.Errorf("dynamic config lacks %q:\n%s", want, dynamic)
+		}
+	}
+	if strings.Contains(string(dynamic), "s3cret") {
+		t.Error("dynamic config holds the password in plaintext")
+	}
+
+	// Regenerating the configuration keeps the dashboard protected
+	if err := m.GenerateDynamicConfig(); err != nil {
+		t.Fatal(err)
+	}
+	if dynamic, _ := os.ReadFile(filepath.Join(dir, "dynamic.yml")); !strings.Contains(string(dynamic), "dashboard-auth") {
+		t.Error("GenerateDynamicConfig() dropped the dashboard's auth")
+	}
+
+	if err := m.RemoveDashboardAuth(); err != nil {
+		t.Fatal(err)
+	}
+	if m.DashboardAuthEnabled() {
+		t.Error("DashboardAuthEnabled() = true after RemoveDashboardAuth")
+	}
+	if dynamic, _ := os.ReadFile(filepath.Join(dir, "dynamic.yml")); strings.Contains(string(dynamic), "dashboard-auth") {
+		t.Errorf("dynamic config still protects the dashboard:\n%s", dynamic)
+	}
+}
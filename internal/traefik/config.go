@@ -4,6 +4,10 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
+
+	"github.com/dokulabs/doku-cli/internal/readonly"
+	"github.com/dokulabs/doku-cli/internal/workspace"
 )
 
 // Config represents Traefik configuration
@@ -19,16 +23,20 @@ type Config struct {
 
 // GenerateConfig generates Traefik configuration file
 func (m *Manager) GenerateConfig() error {
-	// Ensure config directory exists
-	if err := os.MkdirAll(m.configDir, 0755); err != nil {
+	if err := readonly.Check("write the Traefik configuration"); err != nil {
+		return err
+	}
+
+	// Ensure config directory exists, with the access logs' one
+	if err := os.MkdirAll(filepath.Join(m.configDir, "logs"), 0755); err != nil {
 		return fmt.Errorf("failed to create config directory: %w", err)
 	}
 
 	config := Config{
 		Domain:           m.domain,
 		Protocol:         m.protocol,
-		HTTPPort:         80,
-		HTTPSPort:        443,
+		HTTPPort:         m.httpPort,
+		HTTPSPort:        m.httpsPort,
 		DashboardEnabled: true,
 	}
 
@@ -103,7 +111,14 @@ func (m *Manager) generateConfigContent(config Config) string {
 	content += "  docker:\n"
 	content += "    endpoint: \"unix:///var/run/docker.sock\"\n"
 	content += "    exposedByDefault: false\n"
-	content += "    network: \"doku-network\"\n"
+	content += fmt.Sprintf("    network: %q\n", workspace.NetworkName())
+	// Each workspace has its own Traefik, which only routes to its own
+	// containers
+	if name := workspace.Current(); name != "" {
+		content += fmt.Sprintf("    constraints: \"Label(`%s`, `%s`)\"\n", workspace.Label, name)
+	} else {
+		content += fmt.Sprintf("    constraints: \"!LabelRegex(`%s`, `.+`)\"\n", workspace.Label)
+	}
 	content += "    watch: true\n"
 	content += "  file:\n"
 	content += "    filename: /etc/traefik/dynamic.yml\n"
@@ -115,9 +130,11 @@ func (m *Manager) generateConfigContent(config Config) string {
 	content += "  level: INFO\n"
 	content += "\n"
 
-	// Access logs
+	// Access logs, in a file 'doku traffic' reads. Unbuffered, so requests
+	// show up as they are made.
 	content += "accessLog:\n"
-	content += "  bufferingSize: 100\n"
+	content += fmt.Sprintf("  filePath: %s\n", accessLogContainerPath)
+	content += "  format: json\n"
 	content += "\n"
 
 	return content
@@ -125,12 +142,17 @@ func (m *Manager) generateConfigContent(config Config) string {
 
 // GenerateDynamicConfig generates dynamic configuration for Traefik
 func (m *Manager) GenerateDynamicConfig() error {
+	if err := readonly.Check("write the Traefik configuration"); err != nil {
+		return err
+	}
+
 	dynamicConfigPath := filepath.Join(m.configDir, "dynamic.yml")
 
 	content := "# Traefik Dynamic Configuration\n\n"
 	content += "http:\n"
 
-	// Dashboard router
+	// Dashboard router, behind basic auth if 'doku traefik auth' set users
+	users := m.dashboardUsers()
 	if m.protocol == "https" {
 		content += "  routers:\n"
 		content += "    dashboard:\n"
@@ -147,14 +169,49 @@ func (m *Manager) GenerateDynamicConfig() error {
 		content += "      entryPoints:\n"
 		content += "        - web\n"
 	}
+	if len(users) > 0 {
+		content += "      middlewares:\n"
+		content += "        - dashboard-auth\n"
+	}
+
+	// Doku dashboard, served on the host by 'doku serve --dashboard'
+	if m.dokuDashboardPort > 0 {
+		content += "    doku-dashboard:\n"
+		content += fmt.Sprintf("      rule: \"Host(`dashboard.%s`)\"\n", m.domain)
+		content += "      service: doku-dashboard\n"
+		content += "      entryPoints:\n"
+		if m.protocol == "https" {
+			content += "        - websecure\n"
+			content += "      tls: {}\n"
+		} else {
+			content += "        - web\n"
+		}
+		content += "  services:\n"
+		content += "    doku-dashboard:\n"
+		content += "      loadBalancer:\n"
+		content += "        servers:\n"
+		content += fmt.Sprintf("          - url: \"http://host.docker.internal:%d\"\n", m.dokuDashboardPort)
+	}
+
+	if len(users) > 0 {
+		content += "  middlewares:\n"
+		content += "    dashboard-auth:\n"
+		content += "      basicAuth:\n"
+		content += "        users:\n"
+		for _, user := range users {
+			content += fmt.Sprintf("          - %q\n", user)
+		}
+	}
 
 	// TLS configuration for HTTPS
 	if m.protocol == "https" {
 		content += "\n"
 		content += "tls:\n"
 		content += "  certificates:\n"
-		content += "    - certFile: /certs/" + m.domain + ".pem\n"
-		content += "      keyFile: /certs/" + m.domain + "-key.pem\n"
+		for _, name := range m.certificateNames() {
+			content += "    - certFile: /certs/" + name + ".pem\n"
+			content += "      keyFile: /certs/" + name + "-key.pem\n"
+		}
 	}
 
 	if err := os.WriteFile(dynamicConfigPath, []byte(content), 0644); err != nil {
@@ -164,6 +221,27 @@ func (m *Manager) GenerateDynamicConfig() error {
 	return nil
 }
 
+// certificateNames returns the names of the certificates Traefik serves:
+// the domain's, then every other one in the certs directory with its key,
+// e.g. those of other base domains and of services' own domains
+func (m *Manager) certificateNames() []string {
+	names := []string{m.domain}
+	entries, err := os.ReadDir(m.certsDir)
+	if err != nil {
+		return names
+	}
+	for _, entry := range entries {
+		name, ok := strings.CutSuffix(entry.Name(), ".pem")
+		if !ok || name == m.domain || strings.HasSuffix(name, "-key") {
+			continue
+		}
+		if _, err := os.Stat(filepath.Join(m.certsDir, name+"-key.pem")); err == nil {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
 // ValidateConfig validates the Traefik configuration
 func (m *Manager) ValidateConfig() error {
 	configPath := filepath.Join(m.configDir, "traefik.yml")
@@ -210,6 +288,10 @@ func (m *Manager) GetConfig() (string, error) {
 
 // BackupConfig creates a backup of the current configuration
 func (m *Manager) BackupConfig() (string, error) {
+	if err := readonly.Check("back up the Traefik configuration"); err != nil {
+		return "", err
+	}
+
 	configPath := filepath.Join(m.configDir, "traefik.yml")
 	backupPath := configPath + ".backup"
 
@@ -227,6 +309,10 @@ func (m *Manager) BackupConfig() (string, error) {
 
 // RestoreConfig restores configuration from backup
 func (m *Manager) RestoreConfig(backupPath string) error {
+	if err := readonly.Check("restore the Traefik configuration"); err != nil {
+		return err
+	}
+
 	configPath := filepath.Join(m.configDir, "traefik.yml")
 
 	content, err := os.ReadFile(backupPath)
@@ -3,21 +3,34 @@ package traefik
 import (
 	"fmt"
 	"os"
+	"path"
 	"path/filepath"
+	"strconv"
 
 	"github.com/docker/docker/api/types/container"
 	"github.com/docker/docker/api/types/mount"
 	"github.com/docker/docker/api/types/network"
 	"github.com/docker/go-connections/nat"
+	"github.com/dokulabs/doku-cli/internal/config"
 	"github.com/dokulabs/doku-cli/internal/docker"
+	"github.com/dokulabs/doku-cli/internal/monitoring"
+	"github.com/dokulabs/doku-cli/internal/workspace"
+	"github.com/dokulabs/doku-cli/pkg/types"
 )
 
+// TraefikImage and TraefikVersion are the release Doku sets up unless
+// traefik.version pins another
 const (
-	TraefikContainerName = "doku-traefik"
-	TraefikImage         = "traefik:v2.10"
-	TraefikVersion       = "2.10"
+	TraefikImage   = "traefik:v2.10"
+	TraefikVersion = "2.10"
 )
 
+// ContainerName returns the name of the Traefik container of the selected
+// workspace, doku-traefik in the default one
+func ContainerName() string {
+	return workspace.TraefikContainerName()
+}
+
 // Manager handles Traefik setup and configuration
 type Manager struct {
 	dockerClient *docker.Client
@@ -25,6 +38,17 @@ type Manager struct {
 	certsDir     string
 	domain       string
 	protocol     string
+	image        string
+	httpPort     int // Host ports of the entrypoints
+	httpsPort    int
+	hostIP       string // Host address the entrypoints are published on
+
+	// dokuDashboardPort is the host port of the Doku dashboard served by
+	// 'doku serve --dashboard' (0 = not routed)
+	dokuDashboardPort int
+
+	// logRotation is the rotation of the container's logs
+	logRotation types.LogRotation
 }
 
 // NewManager creates a new Traefik manager
@@ -35,9 +59,54 @@ func NewManager(dockerClient *docker.Client, configDir, certsDir, domain, protoc
 		certsDir:     certsDir,
 		domain:       domain,
 		protocol:     protocol,
+		image:        TraefikImage,
+		httpPort:     config.DefaultHTTPPort,
+		httpsPort:    config.DefaultHTTPSPort,
+		hostIP:       "0.0.0.0",
 	}
 }
 
+// SetVersion sets the Traefik release of the container created from now
+// on, e.g. "2.11"; "" keeps the default one
+func (m *Manager) SetVersion(version string) *Manager {
+	m.image = Image(version)
+	return m
+}
+
+// SetPorts sets the host ports of the HTTP and HTTPS entrypoints, for
+// machines where 80 and 443 are taken or privileged
+func (m *Manager) SetPorts(httpPort, httpsPort int) *Manager {
+	m.httpPort = httpPort
+	m.httpsPort = httpsPort
+	return m
+}
+
+// SetHostIP sets the host address the entrypoints are published on, e.g.
+// the bind IP of the preferences
+func (m *Manager) SetHostIP(ip string) *Manager {
+	m.hostIP = ip
+	return m
+}
+
+// SetLogRotation sets the rotation of the logs of the container created
+// from now on, e.g. the preferences'
+func (m *Manager) SetLogRotation(rotation types.LogRotation) *Manager {
+	m.logRotation = rotation
+	return m
+}
+
+// SetDokuDashboard routes dashboard.<domain> to the Doku dashboard that
+// 'doku serve' serves on the given host port (0 = no route)
+func (m *Manager) SetDokuDashboard(port int) *Manager {
+	m.dokuDashboardPort = port
+	return m
+}
+
+// GetDokuDashboardURL returns the URL of the Doku dashboard
+func (m *Manager) GetDokuDashboardURL() string {
+	return config.ServiceURL(m.protocol, "dashboard."+m.domain, m.httpPort, m.httpsPort)
+}
+
 // Setup sets up Traefik (configuration + container)
 func (m *Manager) Setup() error {
 	// Generate static configuration file
@@ -61,7 +130,7 @@ func (m *Manager) Setup() error {
 // StartContainer starts the Traefik container
 func (m *Manager) StartContainer() error {
 	// Check if container already exists
-	exists, err := m.dockerClient.ContainerExists(TraefikContainerName)
+	exists, err := m.dockerClient.ContainerExists(ContainerName())
 	if err != nil {
 		return err
 	}
@@ -71,32 +140,37 @@ func (m *Manager) StartContainer() error {
 		return m.RestartContainer()
 	}
 
-	// Pull Traefik image
-	fmt.Printf("Pulling Traefik image %s...\n", TraefikImage)
-	if err := m.dockerClient.ImagePull(TraefikImage); err != nil {
-		return fmt.Errorf("failed to pull Traefik image: %w", err)
+	// Pull Traefik image, unless cached, e.g. loaded from an offline bundle
+	if cached, err := m.dockerClient.ImageExists(m.image); err != nil || !cached {
+		fmt.Printf("Pulling Traefik image %s...\n", m.image)
+		if err := m.dockerClient.ImagePull(m.image); err != nil {
+			return fmt.Errorf("failed to pull Traefik image: %w", err)
+		}
 	}
 
 	// Prepare container configuration
 	config := &container.Config{
-		Image: TraefikImage,
-		ExposedPorts: nat.PortSet{
-			"80/tcp":  struct{}{},
-			"443/tcp": struct{}{},
-		},
-		Labels: map[string]string{
-			"managed-by":     "doku",
-			"doku.component": "traefik",
-		},
+		Image:        m.image,
+		ExposedPorts: nat.PortSet{},
+		Labels:       docker.ComponentLabels("traefik"),
+	}
+	for port := range m.createPortBindings() {
+		config.ExposedPorts[port] = struct{}{}
 	}
 
 	// Host configuration
+	engine := m.dockerClient.Engine()
 	hostConfig := &container.HostConfig{
 		RestartPolicy: container.RestartPolicy{
 			Name: "unless-stopped",
 		},
 		Mounts:       m.createMounts(),
 		PortBindings: m.createPortBindings(),
+		// Lets Linux hosts reach processes on the host, like the Doku
+		// dashboard, as Docker Desktop does
+		ExtraHosts:  engine.ExtraHosts(),
+		SecurityOpt: engine.SocketSecurityOpt(),
+		LogConfig:   *monitoring.GetDockerLoggingConfig(nil, m.logRotation),
 	}
 
 	// Network configuration
@@ -107,7 +181,7 @@ func (m *Manager) StartContainer() error {
 		config,
 		hostConfig,
 		networkConfig,
-		TraefikContainerName,
+		ContainerName(),
 	)
 	if err != nil {
 		return fmt.Errorf("failed to create Traefik container: %w", err)
@@ -119,14 +193,14 @@ func (m *Manager) StartContainer() error {
 	}
 
 	fmt.Printf("✓ Traefik started successfully\n")
-	fmt.Printf("  Dashboard: %s://%s.%s\n", m.protocol, "traefik", m.domain)
+	fmt.Printf("  Dashboard: %s\n", m.GetDashboardURL())
 
 	return nil
 }
 
 // StopContainer stops the Traefik container
 func (m *Manager) StopContainer() error {
-	exists, err := m.dockerClient.ContainerExists(TraefikContainerName)
+	exists, err := m.dockerClient.ContainerExists(ContainerName())
 	if err != nil {
 		return err
 	}
@@ -136,18 +210,18 @@ func (m *Manager) StopContainer() error {
 	}
 
 	timeout := 10
-	return m.dockerClient.ContainerStop(TraefikContainerName, &timeout)
+	return m.dockerClient.ContainerStop(ContainerName(), &timeout)
 }
 
 // RestartContainer restarts the Traefik container
 func (m *Manager) RestartContainer() error {
 	timeout := 10
-	return m.dockerClient.ContainerRestart(TraefikContainerName, &timeout)
+	return m.dockerClient.ContainerRestart(ContainerName(), &timeout)
 }
 
 // RemoveContainer removes the Traefik container
 func (m *Manager) RemoveContainer() error {
-	exists, err := m.dockerClient.ContainerExists(TraefikContainerName)
+	exists, err := m.dockerClient.ContainerExists(ContainerName())
 	if err != nil {
 		return err
 	}
@@ -156,12 +230,12 @@ func (m *Manager) RemoveContainer() error {
 		return nil // Already removed
 	}
 
-	return m.dockerClient.ContainerRemove(TraefikContainerName, true)
+	return m.dockerClient.ContainerRemove(ContainerName(), true)
 }
 
 // IsRunning checks if Traefik container is running
 func (m *Manager) IsRunning() (bool, error) {
-	exists, err := m.dockerClient.ContainerExists(TraefikContainerName)
+	exists, err := m.dockerClient.ContainerExists(ContainerName())
 	if err != nil {
 		return false, err
 	}
@@ -170,7 +244,7 @@ func (m *Manager) IsRunning() (bool, error) {
 		return false, nil
 	}
 
-	info, err := m.dockerClient.ContainerInspect(TraefikContainerName)
+	info, err := m.dockerClient.ContainerInspect(ContainerName())
 	if err != nil {
 		return false, err
 	}
@@ -178,12 +252,14 @@ func (m *Manager) IsRunning() (bool, error) {
 	return info.State.Running, nil
 }
 
-// createMounts creates volume mounts for Traefik container
+// createMounts creates volume mounts for Traefik container. The engine's
+// socket is mounted where Traefik's Docker provider looks for it, Podman's
+// too.
 func (m *Manager) createMounts() []mount.Mount {
 	mounts := []mount.Mount{
 		{
 			Type:     mount.TypeBind,
-			Source:   "/var/run/docker.sock",
+			Source:   m.dockerClient.Engine().SocketPath(),
 			Target:   "/var/run/docker.sock",
 			ReadOnly: true,
 		},
@@ -199,6 +275,11 @@ func (m *Manager) createMounts() []mount.Mount {
 			Target:   "/etc/traefik/dynamic.yml",
 			ReadOnly: true,
 		},
+		{
+			Type:   mount.TypeBind,
+			Source: filepath.Join(m.configDir, "logs"),
+			Target: path.Dir(accessLogContainerPath),
+		},
 	}
 
 	// Add certificates mount if using HTTPS
@@ -214,15 +295,15 @@ func (m *Manager) createMounts() []mount.Mount {
 	return mounts
 }
 
-// createPortBindings creates port bindings for Traefik
+// createPortBindings creates port bindings for Traefik. The entrypoints
+// listen on the same ports inside the container as on the host, so the
+// HTTP to HTTPS redirect points at the right port.
 func (m *Manager) createPortBindings() nat.PortMap {
-	bindings := nat.PortMap{
-		"80/tcp": {
-			{HostIP: "0.0.0.0", HostPort: "80"},
-		},
-		"443/tcp": {
-			{HostIP: "0.0.0.0", HostPort: "443"},
-		},
+	bindings := nat.PortMap{}
+	for _, port := range []int{m.httpPort, m.httpsPort} {
+		bindings[nat.Port(fmt.Sprintf("%d/tcp", port))] = []nat.PortBinding{
+			{HostIP: m.hostIP, HostPort: strconv.Itoa(port)},
+		}
 	}
 
 	return bindings
@@ -235,9 +316,33 @@ func (m *Manager) createNetworkConfig() *network.NetworkingConfig {
 	}
 }
 
+// Name returns the name of the proxy
+func (m *Manager) Name() string {
+	return "Traefik"
+}
+
+// ContainerName returns the name of the Traefik container
+func (m *Manager) ContainerName() string {
+	return ContainerName()
+}
+
+// WriteConfig writes the static and dynamic configuration files
+func (m *Manager) WriteConfig() error {
+	if err := m.GenerateConfig(); err != nil {
+		return err
+	}
+	return m.GenerateDynamicConfig()
+}
+
+// SyncRoutes does nothing: Traefik reads the routes from the containers'
+// labels itself
+func (m *Manager) SyncRoutes() error {
+	return nil
+}
+
 // GetDashboardURL returns the Traefik dashboard URL
 func (m *Manager) GetDashboardURL() string {
-	return fmt.Sprintf("%s://traefik.%s", m.protocol, m.domain)
+	return config.ServiceURL(m.protocol, "traefik."+m.domain, m.httpPort, m.httpsPort)
 }
 
 // EnsureRunning ensures Traefik is running, starts it if not
@@ -251,14 +356,14 @@ func (m *Manager) EnsureRunning() error {
 		return nil
 	}
 
-	exists, err := m.dockerClient.ContainerExists(TraefikContainerName)
+	exists, err := m.dockerClient.ContainerExists(ContainerName())
 	if err != nil {
 		return err
 	}
 
 	if exists {
 		// Container exists but not running, start it
-		return m.dockerClient.ContainerStart(TraefikContainerName)
+		return m.dockerClient.ContainerStart(ContainerName())
 	}
 
 	// Container doesn't exist, set it up
@@ -267,7 +372,7 @@ func (m *Manager) EnsureRunning() error {
 
 // GetStatus returns the status of Traefik
 func (m *Manager) GetStatus() (map[string]interface{}, error) {
-	exists, err := m.dockerClient.ContainerExists(TraefikContainerName)
+	exists, err := m.dockerClient.ContainerExists(ContainerName())
 	if err != nil {
 		return nil, err
 	}
@@ -279,7 +384,7 @@ func (m *Manager) GetStatus() (map[string]interface{}, error) {
 		}, nil
 	}
 
-	info, err := m.dockerClient.ContainerInspect(TraefikContainerName)
+	info, err := m.dockerClient.ContainerInspect(ContainerName())
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,53 @@
+package traefik
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// RecommendedVersion is the newest Traefik release Doku is tested with,
+// which 'doku traefik upgrade' moves to unless told otherwise
+const RecommendedVersion = "2.11"
+
+// versionPattern matches the Traefik releases Doku can run: the middlewares
+// it labels containers with, e.g. ipwhitelist, are gone from v3
+var versionPattern = regexp.MustCompile(`^2\.\d+(\.\d+)?$`)
+
+// releasePattern matches the release of any image tag, e.g. 3.1.2
+var releasePattern = regexp.MustCompile(`^\d+\.\d+(\.\d+)?$`)
+
+// NormalizeVersion returns a Traefik release as Doku stores it, e.g. "2.11"
+// for "v2.11"
+func NormalizeVersion(version string) (string, error) {
+	version = strings.TrimPrefix(strings.TrimSpace(version), "v")
+	if versionPattern.MatchString(version) {
+		return version, nil
+	}
+	if strings.HasPrefix(version, "3") {
+		return "", fmt.Errorf("traefik %s isn't supported: Doku's routes use v2 middlewares that v3 removed", version)
+	}
+	return "", fmt.Errorf("invalid Traefik version '%s': expected a 2.x release, e.g. %s", version, RecommendedVersion)
+}
+
+// Image returns the image of a Traefik release, the default one for ""
+func Image(version string) string {
+	if version == "" {
+		return TraefikImage
+	}
+	return "traefik:v" + version
+}
+
+// VersionOf returns the release of a Traefik image, e.g. "2.10" for
+// traefik:v2.10, or "" if its tag doesn't say
+func VersionOf(image string) string {
+	i := strings.LastIndex(image, ":")
+	if i < 0 || strings.Contains(image[i:], "/") {
+		return ""
+	}
+	version := strings.TrimPrefix(image[i+1:], "v")
+	if !releasePattern.MatchString(version) {
+		return ""
+	}
+	return version
+}
@@ -0,0 +1,44 @@
+package traefik
+
+import "testing"
+
+func TestNormalizeVersion(t *testing.T) {
+	tests := map[string]string{"2.11": "2.11", "v2.11": "2.11", " 2.10.7 ": "2.10.7"}
+	for input, expected := range tests {
+		got, err := NormalizeVersion(input)
+		if err != nil || got != expected {
+			t.Errorf("NormalizeVersion(%q) = %q, %v; expected %q", input, got, err, expected)
+		}
+	}
+	for _, input := range []string{"", "latest", "3.1", "v3", "2", "2.x"} {
+		if _, err := NormalizeVersion(input); err == nil {
+			t.Errorf("NormalizeVersion(%q) accepted it", input)
+		}
+	}
+}
+
+func TestImage(t *testing.T) {
+	if got := Image(""); got != TraefikImage {
+		t.Errorf("Image(\"\") = %q", got)
+	}
+	if got := Image("2.11"); got != "traefik:v2.11" {
+		t.Errorf("Image(2.11) = %q", got)
+	}
+}
+
+func TestVersionOf(t *testing.T) {
+	tests := map[string]string{
+		"traefik:v2.10":          "2.10",
+		"traefik:2.11.3":         "2.11.3",
+		"traefik:latest":         "",
+		"traefik":                "",
+		"traefik:v3.1":           "3.1",
+		"mirror/traefik:v2.11":   "2.11",
+		"localhost:5000/traefik": "",
+	}
+	for image, expected := range tests {
+		if got := VersionOf(image); got != expected {
+			t.Errorf("VersionOf(%q) = %q, expected %q", image, got, expected)
+		}
+	}
+}
@@ -0,0 +1,196 @@
+// Package traffic reads Traefik's access log: the requests it routed, with
+// their status and latency, and summarizes them per router
+package traffic
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"math"
+	"os"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Entry is a request of the access log
+type Entry struct {
+	Time     time.Time
+	Router   string // Without its provider, e.g. doku-api rather than doku-api@docker
+	Client   string
+	Method   string
+	Host     string
+	Path     string
+	Status   int
+	Duration time.Duration
+}
+
+// rawEntry is a line of Traefik's JSON access log, with the fields used
+type rawEntry struct {
+	StartUTC         time.Time `json:"StartUTC"`
+	RouterName       string    `json:"RouterName"`
+	ClientHost       string    `json:"ClientHost"`
+	RequestMethod    string    `json:"RequestMethod"`
+	RequestHost      string    `json:"RequestHost"`
+	RequestPath      string    `json:"RequestPath"`
+	DownstreamStatus int       `json:"DownstreamStatus"`
+	Duration         int64     `json:"Duration"` // Nanoseconds
+}
+
+// ParseEntry parses a line of the access log
+func ParseEntry(line []byte) (Entry, error) {
+	var raw rawEntry
+	if err := json.Unmarshal(line, &raw); err != nil {
+		return Entry{}, err
+	}
+	if raw.RequestMethod == "" {
+		return Entry{}, errors.New("not an access log entry")
+	}
+	router, _, _ := strings.Cut(raw.RouterName, "@")
+	return Entry{
+		Time:     raw.StartUTC,
+		Router:   router,
+		Client:   raw.ClientHost,
+		Method:   raw.RequestMethod,
+		Host:     raw.RequestHost,
+		Path:     raw.RequestPath,
+		Status:   raw.DownstreamStatus,
+		Duration: time.Duration(raw.Duration),
+	}, nil
+}
+
+// Read returns the entries of the access log at path that keep accepts, or
+// all of them if keep is nil. Lines that aren't entries are skipped.
+func Read(path string, keep func(Entry) bool) ([]Entry, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var entries []Entry
+	_, err = scan(f, func(e Entry) {
+		if keep == nil || keep(e) {
+			entries = append(entries, e)
+		}
+	})
+	return entries, err
+}
+
+// Follow calls fn with the entries written to the access log at path from
+// offset on, until ctx is done. The log being cleared starts it over.
+func Follow(ctx context.Context, path string, offset int64, fn func(Entry)) error {
+	ticker := time.NewTicker(500 * time.Millisecond)
+	defer ticker.Stop()
+
+	for {
+		if info, err := os.Stat(path); err == nil && info.Size() != offset {
+			if info.Size() < offset {
+				offset = 0
+			}
+			read, err := readFrom(path, offset, fn)
+			if err != nil {
+				return err
+			}
+			offset += read
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// readFrom calls fn with the entries of the file from offset on and returns
+// how many bytes it read, up to the last complete line
+func readFrom(path string, offset int64, fn func(Entry)) (int64, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+	if _, err := f.Seek(offset, io.SeekStart); err != nil {
+		return 0, err
+	}
+	return scan(f, fn)
+}
+
+// scan calls fn with the entries of r and returns how many bytes of
+// complete lines it read. A line still being written is left for later.
+func scan(r io.Reader, fn func(Entry)) (int64, error) {
+	reader := bufio.NewReader(r)
+	var read int64
+	for {
+		line, err := reader.ReadBytes('\n')
+		if err == io.EOF {
+			return read, nil
+		}
+		if err != nil {
+			return read, err
+		}
+		read += int64(len(line))
+		if entry, err := ParseEntry(line); err == nil {
+			fn(entry)
+		}
+	}
+}
+
+// Summary is the traffic of a router
+type Summary struct {
+	Router   string
+	Requests int
+	Statuses [6]int // Requests per status class: Statuses[2] counts the 2xx
+	P50      time.Duration
+	P95      time.Duration
+	P99      time.Duration
+}
+
+// Summarize returns the traffic of each router of entries, sorted by router
+func Summarize(entries []Entry) []Summary {
+	durations := make(map[string][]time.Duration)
+	summaries := make(map[string]*Summary)
+	for _, e := range entries {
+		s, ok := summaries[e.Router]
+		if !ok {
+			s = &Summary{Router: e.Router}
+			summaries[e.Router] = s
+		}
+		s.Requests++
+		if class := e.Status / 100; class >= 1 && class <= 5 {
+			s.Statuses[class]++
+		}
+		durations[e.Router] = append(durations[e.Router], e.Duration)
+	}
+
+	result := make([]Summary, 0, len(summaries))
+	for router, s := range summaries {
+		d := durations[router]
+		sort.Slice(d, func(i, j int) bool { return d[i] < d[j] })
+		s.P50 = Percentile(d, 50)
+		s.P95 = Percentile(d, 95)
+		s.P99 = Percentile(d, 99)
+		result = append(result, *s)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Router < result[j].Router })
+	return result
+}
+
+// Percentile returns the p-th percentile of sorted durations, by the
+// nearest-rank method
+func Percentile(sorted []time.Duration, p float64) time.Duration {
+	if len(sorted) == 0 {
+		return 0
+	}
+	rank := int(math.Ceil(p / 100 * float64(len(sorted))))
+	if rank < 1 {
+		rank = 1
+	}
+	if rank > len(sorted) {
+		rank = len(sorted)
+	}
+	return sorted[rank-1]
+}
@@ -0,0 +1,134 @@
+package traffic
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+const accessLog = `{"ClientHost":"172.18.0.1","DownstreamStatus":200,"Duration":12000000,"RequestHost":"api.doku.local","RequestMethod":"POST","RequestPath":"/webhooks/github","RouterName":"doku-api@docker","StartUTC":"2026-10-18T10:00:00Z"}
+{"ClientHost":"172.18.0.1","DownstreamStatus":500,"Duration":300000000,"RequestHost":"api.doku.local","RequestMethod":"POST","RequestPath":"/webhooks/github","RouterName":"doku-api@docker","StartUTC":"2026-10-18T10:00:01Z"}
+not json
+{"level":"info","msg":"Configuration loaded"}
+{"ClientHost":"172.18.0.1","DownstreamStatus":404,"Duration":1000000,"RequestHost":"web.doku.local","RequestMethod":"GET","RequestPath":"/favicon.ico","RouterName":"web@docker","StartUTC":"2026-10-18T10:00:02Z"}
+{"ClientHost":"172.18.0.1","DownstreamStatus":201,"Duration":20000000,"RequestHost":"api.doku.local","RequestMethod":"POST","RequestPath":"/webhooks/stripe","RouterName":"doku-api@docker","StartUTC":"2026-10-18T10:00:03Z"}
+{"ClientHost":"172.18.0.1","DownstreamStatus":200,"Duration":5000000,"RequestHost":"web.doku`
+
+func writeLog(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "access.log")
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestParseEntry(t *testing.T) {
+	e, err := ParseEntry([]byte(`{"ClientHost":"172.18.0.1","DownstreamStatus":200,"Duration":12000000,"RequestHost":"api.doku.local","RequestMethod":"POST","RequestPath":"/hook","RouterName":"doku-api@docker","StartUTC":"2026-10-18T10:00:00Z"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if e.Router != "doku-api" || e.Status != 200 || e.Duration != 12*time.Millisecond || e.Method != "POST" || e.Path != "/hook" {
+		t.Errorf("ParseEntry() = %+v", e)
+	}
+	if !e.Time.Equal(time.Date(2026, 10, 18, 10, 0, 0, 0, time.UTC)) {
+		t.Errorf("ParseEntry() time = %v", e.Time)
+	}
+
+	if _, err := ParseEntry([]byte(`{"level":"info"}`)); err == nil {
+		t.Error("ParseEntry() accepted a line that isn't a request")
+	}
+}
+
+func TestRead(t *testing.T) {
+	path := writeLog(t, accessLog)
+
+	entries, err := Read(path, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The last line is still being written
+	if len(entries) != 4 {
+		t.Fatalf("Read() returned %d entries, expected 4", len(entries))
+	}
+
+	api, err := Read(path, func(e Entry) bool { return e.Router == "doku-api" })
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(api) != 3 {
+		t.Errorf("Read() kept %d entries, expected 3", len(api))
+	}
+}
+
+func TestSummarize(t *testing.T) {
+	entries, err := Read(writeLog(t, accessLog), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	summaries := Summarize(entries)
+	if len(summaries) != 2 {
+		t.Fatalf("Summarize() = %+v", summaries)
+	}
+
+	api := summaries[0]
+	if api.Router != "doku-api" || api.Requests != 3 {
+		t.Errorf("Summarize()[0] = %+v", api)
+	}
+	if api.Statuses[2] != 2 || api.Statuses[5] != 1 || api.Statuses[4] != 0 {
+		t.Errorf("statuses = %v", api.Statuses)
+	}
+	if api.P50 != 20*time.Millisecond || api.P99 != 300*time.Millisecond {
+		t.Errorf("p50 = %v, p99 = %v", api.P50, api.P99)
+	}
+
+	if web := summaries[1]; web.Router != "web" || web.Statuses[4] != 1 {
+		t.Errorf("Summarize()[1] = %+v", web)
+	}
+}
+
+func TestPercentile(t *testing.T) {
+	var d []time.Duration
+	for i := 1; i <= 100; i++ {
+		d = append(d, time.Duration(i)*time.Millisecond)
+	}
+	tests := map[float64]time.Duration{50: 50 * time.Millisecond, 95: 95 * time.Millisecond, 99: 99 * time.Millisecond, 100: 100 * time.Millisecond}
+	for p, expected := range tests {
+		if got := Percentile(d, p); got != expected {
+			t.Errorf("Percentile(%v) = %v, expected %v", p, got, expected)
+		}
+	}
+	if got := Percentile(nil, 50); got != 0 {
+		t.Errorf("Percentile(nil) = %v", got)
+	}
+}
+
+func TestReadFrom(t *testing.T) {
+	path := writeLog(t, accessLog)
+	var entries []Entry
+	read, err := readFrom(path, 0, func(e Entry) { entries = append(entries, e) })
+	if err != nil {
+		t.Fatal(err)
+	}
+	info, _ := os.Stat(path)
+	if len(entries) != 4 || read >= info.Size() {
+		t.Errorf("readFrom() read %d of %d bytes, %d entries", read, info.Size(), len(entries))
+	}
+
+	// The rest of the last line arrives
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.WriteString(`.local","RequestMethod":"GET","RequestPath":"/","RouterName":"web@docker","StartUTC":"2026-10-18T10:00:04Z"}` + "\n")
+	f.Close()
+
+	entries = nil
+	if _, err := readFrom(path, read, func(e Entry) { entries = append(entries, e) }); err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 || entries[0].Host != "web.doku.local" {
+		t.Errorf("readFrom() = %+v", entries)
+	}
+}
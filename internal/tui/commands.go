@@ -0,0 +1,125 @@
+package tui
+
+import (
+	"bytes"
+	"context"
+	"sort"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/docker/docker/pkg/stdcopy"
+	"github.com/dokulabs/doku-cli/internal/service"
+	"github.com/dokulabs/doku-cli/pkg/types"
+)
+
+// logLines is how many lines of logs are loaded
+const logLines = 200
+
+// sampleTimeout bounds loading the logs or usage of a service
+const sampleTimeout = 3 * time.Second
+
+// loadServices reloads the services. Their statuses are kept up to date
+// by Watch, so they are read from the config.
+func (m *model) loadServices() tea.Cmd {
+	return func() tea.Msg {
+		if _, err := m.configMgr.Load(); err != nil {
+			return servicesMsg{err: err}
+		}
+		instances, err := m.configMgr.ListInstances()
+		if err != nil {
+			return servicesMsg{err: err}
+		}
+		sort.Slice(instances, func(i, j int) bool {
+			return instances[i].Name < instances[j].Name
+		})
+		return servicesMsg{services: instances}
+	}
+}
+
+func (m *model) loadCatalog() tea.Cmd {
+	return func() tea.Msg {
+		services, err := m.catalogMgr.ListServices()
+		if err != nil {
+			return catalogMsg{err: err}
+		}
+		sort.Slice(services, func(i, j int) bool {
+			return services[i].Name < services[j].Name
+		})
+		return catalogMsg{services: services}
+	}
+}
+
+// loadSelected loads the logs and usage of the selected service
+func (m *model) loadSelected() tea.Cmd {
+	instance := m.current()
+	if instance == nil {
+		return nil
+	}
+	cmds := []tea.Cmd{m.loadLogs(instance)}
+	if instance.Status == types.StatusRunning {
+		cmds = append(cmds, m.loadStats(instance.Name))
+	}
+	return tea.Batch(cmds...)
+}
+
+func (m *model) loadLogs(instance *types.Instance) tea.Cmd {
+	name, container := instance.Name, logsContainer(instance)
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), sampleTimeout)
+		defer cancel()
+
+		logs, err := m.dockerClient.ContainerLogsTail(ctx, container, logLines)
+		if err != nil {
+			return logsMsg{name: name, lines: []string{err.Error()}}
+		}
+		defer logs.Close()
+
+		var buf bytes.Buffer
+		_, _ = stdcopy.StdCopy(&buf, &buf, logs)
+		return logsMsg{name: name, lines: strings.Split(strings.TrimRight(buf.String(), "\n"), "\n")}
+	}
+}
+
+func (m *model) loadStats(name string) tea.Cmd {
+	return func() tea.Msg {
+		stats, err := m.serviceMgr.GetStats(name)
+		if err != nil {
+			return statsMsg{name: name}
+		}
+		return statsMsg{name: name, stats: stats}
+	}
+}
+
+// install installs a catalog service the way 'doku serve' does: without
+// prompts, installing its dependencies and reusing data left by a previous
+// install
+func (m *model) install(name, version string) error {
+	installer, err := service.NewInstaller(m.dockerClient, m.configMgr, m.catalogMgr)
+	if err != nil {
+		return err
+	}
+	_, err = installer.Install(service.InstallOptions{
+		ServiceName:       name,
+		Version:           version,
+		AutoInstallDeps:   true,
+		ReuseExistingData: true,
+	})
+	return err
+}
+
+// logsContainer returns the container whose logs are shown: the primary
+// one of multi-container services
+func logsContainer(instance *types.Instance) string {
+	if instance.IsMultiContainer {
+		for _, c := range instance.Containers {
+			if c.Primary {
+				return c.FullName
+			}
+		}
+		if len(instance.Containers) > 0 {
+			return instance.Containers[0].FullName
+		}
+	}
+	return instance.ContainerName
+}
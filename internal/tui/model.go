@@ -0,0 +1,321 @@
+package tui
+
+import (
+	"fmt"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/dokulabs/doku-cli/internal/catalog"
+	"github.com/dokulabs/doku-cli/internal/config"
+	"github.com/dokulabs/doku-cli/internal/docker"
+	"github.com/dokulabs/doku-cli/internal/service"
+	"github.com/dokulabs/doku-cli/pkg/types"
+)
+
+// refreshInterval is how often the services, logs and usage are reloaded,
+// on top of the redraws on Docker events
+const refreshInterval = 2 * time.Second
+
+// pane is a tab of the UI
+type pane int
+
+const (
+	paneServices pane = iota
+	paneCatalog
+)
+
+// model is the state of the UI
+type model struct {
+	dockerClient *docker.Client
+	configMgr    *config.Manager
+	catalogMgr   *catalog.Manager
+	serviceMgr   *service.Manager
+
+	pane     pane
+	services []*types.Instance
+	catalog  []*types.CatalogService
+	cursor   int // Selected service
+	selected int // Selected catalog service
+
+	// versions lists the versions of the selected catalog service while
+	// one is being chosen to install
+	versions      []string
+	versionCursor int
+
+	confirm string         // Question waiting for y/n, e.g. before removing
+	onYes   func() tea.Cmd // What to do on y
+	logs    []string       // Last lines of the selected service's logs
+	stats   *docker.ContainerStatsResult
+	busy    string // Action in progress, e.g. "stopping redis"
+	status  string // Last message, e.g. the result of an action
+	failed  bool   // Whether status is an error
+	help    bool
+
+	width, height int
+}
+
+func newModel(dockerClient *docker.Client, configMgr *config.Manager, catalogMgr *catalog.Manager) *model {
+	return &model{
+		dockerClient: dockerClient,
+		configMgr:    configMgr,
+		catalogMgr:   catalogMgr,
+		serviceMgr:   service.NewManager(dockerClient, configMgr),
+	}
+}
+
+// Messages of the commands
+type (
+	servicesMsg struct {
+		services []*types.Instance
+		err      error
+	}
+	catalogMsg struct {
+		services []*types.CatalogService
+		err      error
+	}
+	logsMsg struct {
+		name  string
+		lines []string
+	}
+	statsMsg struct {
+		name  string
+		stats *docker.ContainerStatsResult
+	}
+	actionMsg struct {
+		done string // e.g. "stopped redis"
+		err  error
+	}
+	outputMsg  string // A line printed by the service manager or installer
+	changedMsg struct{}
+	tickMsg    time.Time
+)
+
+func (m *model) Init() tea.Cmd {
+	return tea.Batch(m.loadServices(), m.loadCatalog(), tick())
+}
+
+func tick() tea.Cmd {
+	return tea.Tick(refreshInterval, func(t time.Time) tea.Msg { return tickMsg(t) })
+}
+
+func (m *model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width, m.height = msg.Width, msg.Height
+		return m, nil
+
+	case tea.KeyMsg:
+		return m, m.handleKey(msg.String())
+
+	case servicesMsg:
+		if msg.err != nil {
+			m.setStatus(msg.err.Error(), true)
+			return m, nil
+		}
+		m.services = msg.services
+		m.cursor = clamp(m.cursor, len(m.services))
+		return m, m.loadSelected()
+
+	case catalogMsg:
+		if msg.err != nil {
+			m.setStatus(msg.err.Error(), true)
+			return m, nil
+		}
+		m.catalog = msg.services
+		m.selected = clamp(m.selected, len(m.catalog))
+		return m, nil
+
+	case logsMsg:
+		if instance := m.current(); instance != nil && instance.Name == msg.name {
+			m.logs = msg.lines
+		}
+		return m, nil
+
+	case statsMsg:
+		if instance := m.current(); instance != nil && instance.Name == msg.name {
+			m.stats = msg.stats
+		}
+		return m, nil
+
+	case actionMsg:
+		m.busy = ""
+		if msg.err != nil {
+			m.setStatus(msg.err.Error(), true)
+		} else {
+			m.setStatus("✓ "+msg.done, false)
+		}
+		return m, m.loadServices()
+
+	case outputMsg:
+		if m.busy != "" {
+			m.setStatus(string(msg), false)
+		}
+		return m, nil
+
+	case changedMsg:
+		return m, m.loadServices()
+
+	case tickMsg:
+		return m, tea.Batch(m.loadServices(), tick())
+	}
+
+	return m, nil
+}
+
+// handleKey runs the command bound to a key
+func (m *model) handleKey(key string) tea.Cmd {
+	if key == "ctrl+c" {
+		return tea.Quit
+	}
+
+	if m.confirm != "" {
+		onYes := m.onYes
+		m.confirm, m.onYes = "", nil
+		if key == "y" || key == "Y" {
+			return onYes()
+		}
+		return nil
+	}
+
+	if m.help {
+		m.help = false
+		return nil
+	}
+
+	if m.versions != nil {
+		return m.handleVersionKey(key)
+	}
+
+	switch key {
+	case "q":
+		return tea.Quit
+	case "?":
+		m.help = true
+		return nil
+	case "tab", "1", "2":
+		if key == "1" || (key == "tab" && m.pane == paneCatalog) {
+			m.pane = paneServices
+		} else {
+			m.pane = paneCatalog
+		}
+		return nil
+	}
+
+	if m.pane == paneCatalog {
+		return m.handleCatalogKey(key)
+	}
+	return m.handleServiceKey(key)
+}
+
+func (m *model) handleServiceKey(key string) tea.Cmd {
+	switch key {
+	case "up", "k":
+		return m.moveCursor(-1)
+	case "down", "j":
+		return m.moveCursor(1)
+	}
+
+	instance := m.current()
+	if instance == nil || m.busy != "" {
+		return nil
+	}
+	name := instance.Name
+
+	switch key {
+	case "s":
+		return m.act("starting "+name, "started "+name, func() error { return m.serviceMgr.Start(name) })
+	case "x":
+		return m.act("stopping "+name, "stopped "+name, func() error { return m.serviceMgr.Stop(name) })
+	case "r":
+		return m.act("restarting "+name, "restarted "+name, func() error { return m.serviceMgr.Restart(name) })
+	case "d":
+		m.confirm = fmt.Sprintf("Remove %s? Its data volumes are kept. (y/n)", name)
+		m.onYes = func() tea.Cmd {
+			return m.act("removing "+name, "removed "+name, func() error { return m.serviceMgr.Remove(name, false, false) })
+		}
+	}
+	return nil
+}
+
+func (m *model) handleCatalogKey(key string) tea.Cmd {
+	switch key {
+	case "up", "k":
+		m.selected = clamp(m.selected-1, len(m.catalog))
+	case "down", "j":
+		m.selected = clamp(m.selected+1, len(m.catalog))
+	case "enter", "i":
+		if m.selected < len(m.catalog) && m.busy == "" {
+			m.versions = catalog.SortedVersions(m.catalog[m.selected])
+			m.versionCursor = 0
+		}
+	}
+	return nil
+}
+
+// handleVersionKey picks the version of the catalog service to install
+func (m *model) handleVersionKey(key string) tea.Cmd {
+	switch key {
+	case "up", "k":
+		m.versionCursor = clamp(m.versionCursor-1, len(m.versions))
+	case "down", "j":
+		m.versionCursor = clamp(m.versionCursor+1, len(m.versions))
+	case "esc", "q":
+		m.versions = nil
+	case "enter":
+		if len(m.versions) == 0 {
+			m.versions = nil
+			return nil
+		}
+		name, version := m.catalog[m.selected].Name, m.versions[m.versionCursor]
+		m.versions = nil
+		m.pane = paneServices
+		return m.act("installing "+name+" "+version, "installed "+name+" "+version, func() error {
+			return m.install(name, version)
+		})
+	}
+	return nil
+}
+
+// moveCursor selects another service, clearing the logs and usage of the
+// previous one
+func (m *model) moveCursor(delta int) tea.Cmd {
+	cursor := clamp(m.cursor+delta, len(m.services))
+	if cursor == m.cursor {
+		return nil
+	}
+	m.cursor = cursor
+	m.logs, m.stats = nil, nil
+	return m.loadSelected()
+}
+
+// act runs an action in the background, one at a time
+func (m *model) act(doing, done string, fn func() error) tea.Cmd {
+	m.busy = doing
+	m.setStatus(doing+"…", false)
+	return func() tea.Msg {
+		return actionMsg{done: done, err: fn()}
+	}
+}
+
+func (m *model) setStatus(status string, failed bool) {
+	m.status, m.failed = status, failed
+}
+
+// current returns the selected service, or nil
+func (m *model) current() *types.Instance {
+	if m.cursor < len(m.services) {
+		return m.services[m.cursor]
+	}
+	return nil
+}
+
+// clamp keeps an index in [0, n)
+func clamp(i, n int) int {
+	if i >= n {
+		i = n - 1
+	}
+	if i < 0 {
+		i = 0
+	}
+	return i
+}
@@ -0,0 +1,140 @@
+package tui
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/dokulabs/doku-cli/pkg/types"
+)
+
+func newTestModel() *model {
+	m := newModel(nil, nil, nil)
+	m.width, m.height = 100, 30
+	m.services = []*types.Instance{
+		{Name: "postgres-16", ServiceType: "postgres", Version: "16", Status: types.StatusRunning},
+		{Name: "redis", ServiceType: "redis", Status: types.StatusStopped},
+	}
+	m.catalog = []*types.CatalogService{
+		{Name: "postgres", Versions: map[string]*types.ServiceSpec{"15": {}, "16": {}}},
+	}
+	return m
+}
+
+func press(m *model, key string) tea.Cmd {
+	return m.handleKey(key)
+}
+
+func TestNavigation(t *testing.T) {
+	m := newTestModel()
+
+	press(m, "down")
+	press(m, "down")
+	if m.cursor != 1 {
+		t.Errorf("cursor = %d, expected 1 (stays on the last service)", m.cursor)
+	}
+	press(m, "k")
+	if m.cursor != 0 {
+		t.Errorf("cursor = %d, expected 0", m.cursor)
+	}
+
+	press(m, "tab")
+	if m.pane != paneCatalog {
+		t.Error("tab should switch to the catalog")
+	}
+	press(m, "tab")
+	if m.pane != paneServices {
+		t.Error("tab should switch back to the services")
+	}
+
+	if cmd := press(m, "q"); cmd == nil {
+		t.Error("q should quit")
+	}
+}
+
+func TestRemoveNeedsConfirmation(t *testing.T) {
+	m := newTestModel()
+
+	if cmd := press(m, "d"); cmd != nil {
+		t.Error("d should ask before removing")
+	}
+	if !strings.Contains(m.confirm, "postgres-16") {
+		t.Errorf("confirm = %q, expected a question about postgres-16", m.confirm)
+	}
+	if cmd := press(m, "n"); cmd != nil || m.confirm != "" {
+		t.Error("n should cancel the removal")
+	}
+
+	press(m, "d")
+	if cmd := press(m, "y"); cmd == nil {
+		t.Error("y should remove the service")
+	}
+	if m.busy == "" {
+		t.Error("the removal should be in progress")
+	}
+
+	// Another action waits for the first one
+	if cmd := press(m, "s"); cmd != nil {
+		t.Error("actions should run one at a time")
+	}
+	m.Update(actionMsg{done: "removed postgres-16", err: errors.New("boom")})
+	if m.busy != "" || !m.failed || m.status != "boom" {
+		t.Errorf("after a failed action: busy = %q, status = %q, failed = %v", m.busy, m.status, m.failed)
+	}
+}
+
+func TestInstallFlow(t *testing.T) {
+	m := newTestModel()
+
+	press(m, "2")
+	press(m, "enter")
+	if len(m.versions) != 2 {
+		t.Fatalf("versions = %v, expected the versions of postgres", m.versions)
+	}
+	if !strings.Contains(m.View(), "Install postgres") {
+		t.Error("the view should show the version chooser")
+	}
+
+	press(m, "esc")
+	if m.versions != nil {
+		t.Error("esc should cancel the install")
+	}
+
+	press(m, "enter")
+	if cmd := press(m, "enter"); cmd == nil {
+		t.Error("enter should install the chosen version")
+	}
+	if m.pane != paneServices || !strings.HasPrefix(m.busy, "installing postgres") {
+		t.Errorf("pane = %v, busy = %q; expected the install in progress", m.pane, m.busy)
+	}
+}
+
+func TestView(t *testing.T) {
+	m := newTestModel()
+	m.logs = []string{"ready to accept connections"}
+
+	view := m.View()
+	for _, want := range []string{"postgres-16", "redis", "running", "ready to accept connections"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("view doesn't show %q:\n%s", want, view)
+		}
+	}
+}
+
+func TestLogsContainer(t *testing.T) {
+	instance := &types.Instance{
+		Name:             "signoz",
+		IsMultiContainer: true,
+		Containers: []types.ContainerInfo{
+			{Name: "query", FullName: "doku-signoz-query"},
+			{Name: "frontend", FullName: "doku-signoz-frontend", Primary: true},
+		},
+	}
+	if got := logsContainer(instance); got != "doku-signoz-frontend" {
+		t.Errorf("logsContainer() = %q, expected the primary container", got)
+	}
+	if got := logsContainer(&types.Instance{ContainerName: "redis"}); got != "redis" {
+		t.Errorf("logsContainer() = %q, expected redis", got)
+	}
+}
@@ -0,0 +1,62 @@
+// Package tui is the terminal UI of 'doku ui': panes listing the services
+// with the logs and usage of the selected one, and a catalog browser to
+// install new ones, all driven from the keyboard.
+package tui
+
+import (
+	"bufio"
+	"context"
+	"os"
+	"regexp"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/dokulabs/doku-cli/internal/catalog"
+	"github.com/dokulabs/doku-cli/internal/config"
+	"github.com/dokulabs/doku-cli/internal/docker"
+	"github.com/dokulabs/doku-cli/internal/service"
+	"github.com/fatih/color"
+)
+
+// ansiEscape matches the color codes of captured output
+var ansiEscape = regexp.MustCompile(`\x1b\[[0-9;]*[a-zA-Z]`)
+
+// Run shows the UI until the user quits. What the service manager and the
+// installer print is captured and shown on the status line, as it would
+// otherwise garble the screen.
+func Run(dockerClient *docker.Client, configMgr *config.Manager, catalogMgr *catalog.Manager) error {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	stdout, output := os.Stdout, color.Output
+	r, w, err := os.Pipe()
+	if err != nil {
+		return err
+	}
+	os.Stdout, color.Output = w, w
+	defer func() {
+		os.Stdout, color.Output = stdout, output
+		w.Close()
+	}()
+
+	m := newModel(dockerClient, configMgr, catalogMgr)
+	p := tea.NewProgram(m, tea.WithAltScreen(), tea.WithOutput(stdout))
+
+	go func() {
+		scanner := bufio.NewScanner(r)
+		for scanner.Scan() {
+			line := strings.TrimSpace(ansiEscape.ReplaceAllString(scanner.Text(), ""))
+			if line != "" {
+				p.Send(outputMsg(line))
+			}
+		}
+	}()
+
+	// Redraw as soon as Docker reports a service changing
+	go m.serviceMgr.Watch(ctx, func(service.StatusChange) {
+		p.Send(changedMsg{})
+	})
+
+	_, err = p.Run()
+	return err
+}
@@ -0,0 +1,255 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/docker/go-units"
+	"github.com/dokulabs/doku-cli/pkg/types"
+)
+
+var (
+	titleStyle    = lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("6"))
+	tabStyle      = lipgloss.NewStyle().Padding(0, 1)
+	activeTab     = tabStyle.Bold(true).Reverse(true)
+	headerStyle   = lipgloss.NewStyle().Bold(true)
+	selectedStyle = lipgloss.NewStyle().Reverse(true)
+	faintStyle    = lipgloss.NewStyle().Faint(true)
+	errorStyle    = lipgloss.NewStyle().Foreground(lipgloss.Color("1"))
+	confirmStyle  = lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("3"))
+
+	statusStyles = map[types.ServiceStatus]lipgloss.Style{
+		types.StatusRunning: lipgloss.NewStyle().Foreground(lipgloss.Color("2")),
+		types.StatusStopped: lipgloss.NewStyle().Foreground(lipgloss.Color("3")),
+		types.StatusFailed:  lipgloss.NewStyle().Foreground(lipgloss.Color("1")),
+		types.StatusMissing: lipgloss.NewStyle().Foreground(lipgloss.Color("1")),
+	}
+)
+
+const helpText = `Services
+  ↑/↓ j/k   Select a service
+  s         Start
+  x         Stop
+  r         Restart
+  d         Remove (keeps data volumes)
+
+Catalog
+  ↑/↓ j/k   Select a service
+  enter, i  Choose a version and install it
+
+  tab, 1/2  Switch between services and catalog
+  ?         Help
+  q         Quit
+
+Press any key to go back`
+
+func (m *model) View() string {
+	var b strings.Builder
+
+	b.WriteString(titleStyle.Render("Doku") + "  ")
+	for _, tab := range []struct {
+		pane  pane
+		title string
+	}{{paneServices, "1 Services"}, {paneCatalog, "2 Catalog"}} {
+		style := tabStyle
+		if m.pane == tab.pane {
+			style = activeTab
+		}
+		b.WriteString(style.Render(tab.title) + " ")
+	}
+	b.WriteString("\n\n")
+
+	switch {
+	case m.help:
+		b.WriteString(helpText)
+		return b.String()
+	case m.versions != nil:
+		b.WriteString(m.viewVersions())
+	case m.pane == paneCatalog:
+		b.WriteString(m.viewCatalog())
+	default:
+		b.WriteString(m.viewServices())
+	}
+
+	b.WriteString("\n")
+	b.WriteString(m.viewFooter())
+	return b.String()
+}
+
+func (m *model) viewServices() string {
+	if len(m.services) == 0 {
+		return faintStyle.Render("No services installed. Press 2 to browse the catalog.") + "\n"
+	}
+
+	var b strings.Builder
+	rows := make([][]string, 0, len(m.services))
+	for _, instance := range m.services {
+		version := "-"
+		if instance.Version != "" {
+			version = "v" + instance.Version
+		}
+		url := instance.URL
+		if url == "" {
+			url = "-"
+		}
+		rows = append(rows, []string{instance.Name, string(instance.Status), instance.ServiceType, version, url})
+	}
+	widths := columnWidths([]string{"NAME", "STATUS", "SERVICE", "VERSION", "URL"}, rows)
+
+	b.WriteString(headerStyle.Render(formatRow([]string{"NAME", "STATUS", "SERVICE", "VERSION", "URL"}, widths)) + "\n")
+	for i, row := range rows {
+		line := formatRow(row, widths)
+		if i == m.cursor {
+			line = selectedStyle.Render(line)
+		} else if style, ok := statusStyles[m.services[i].Status]; ok {
+			// Only the status column is colored
+			start := widths[0] + 2
+			line = line[:start] + style.Render(row[1]) + line[start+len(row[1]):]
+		}
+		b.WriteString(line + "\n")
+	}
+
+	instance := m.current()
+	b.WriteString("\n")
+	b.WriteString(headerStyle.Render("Usage") + "  ")
+	if m.stats != nil {
+		b.WriteString(fmt.Sprintf("CPU %.1f%%   MEM %s", m.stats.CPUPercent, units.BytesSize(float64(m.stats.MemoryUsage))))
+		if m.stats.MemoryLimit > 0 {
+			b.WriteString(" / " + units.BytesSize(float64(m.stats.MemoryLimit)))
+		}
+		b.WriteString(fmt.Sprintf("   NET ↓%s ↑%s", units.BytesSize(float64(m.stats.NetworkRx)), units.BytesSize(float64(m.stats.NetworkTx))))
+	} else {
+		b.WriteString(faintStyle.Render("-"))
+	}
+	b.WriteString("\n\n")
+
+	b.WriteString(headerStyle.Render("Logs ("+instance.Name+")") + "\n")
+	b.WriteString(m.viewLogs(len(m.services)))
+	return b.String()
+}
+
+// viewLogs renders the last logs lines that fit under a table of n rows
+func (m *model) viewLogs(n int) string {
+	if len(m.logs) == 0 {
+		return faintStyle.Render("No logs") + "\n"
+	}
+
+	// Title, tabs, table header, usage and footer take about 10 lines
+	height := m.height - n - 10
+	if height < 3 {
+		height = 3
+	}
+	lines := m.logs
+	if len(lines) > height {
+		lines = lines[len(lines)-height:]
+	}
+
+	var b strings.Builder
+	for _, line := range lines {
+		b.WriteString(truncate(line, m.width) + "\n")
+	}
+	return b.String()
+}
+
+func (m *model) viewCatalog() string {
+	if len(m.catalog) == 0 {
+		return faintStyle.Render("The catalog is empty. Run 'doku catalog update'.") + "\n"
+	}
+
+	rows := make([][]string, 0, len(m.catalog))
+	for _, svc := range m.catalog {
+		rows = append(rows, []string{svc.Name, svc.Category, svc.Description})
+	}
+	widths := columnWidths([]string{"NAME", "CATEGORY", "DESCRIPTION"}, rows)
+
+	// Keep the selected service in view
+	height := m.height - 6
+	if height < 5 {
+		height = 5
+	}
+	first := 0
+	if m.selected >= height {
+		first = m.selected - height + 1
+	}
+
+	var b strings.Builder
+	b.WriteString(headerStyle.Render(formatRow([]string{"NAME", "CATEGORY", "DESCRIPTION"}, widths)) + "\n")
+	for i := first; i < len(rows) && i < first+height; i++ {
+		line := truncate(formatRow(rows[i], widths), m.width)
+		if i == m.selected {
+			line = selectedStyle.Render(line)
+		}
+		b.WriteString(line + "\n")
+	}
+	return b.String()
+}
+
+func (m *model) viewVersions() string {
+	var b strings.Builder
+	b.WriteString(headerStyle.Render("Install "+m.catalog[m.selected].Name) + faintStyle.Render("  (enter to install, esc to cancel)") + "\n\n")
+	if len(m.versions) == 0 {
+		b.WriteString(faintStyle.Render("No versions available") + "\n")
+	}
+	for i, version := range m.versions {
+		line := "  " + version
+		if i == m.versionCursor {
+			line = selectedStyle.Render("> " + version)
+		}
+		b.WriteString(line + "\n")
+	}
+	return b.String()
+}
+
+func (m *model) viewFooter() string {
+	switch {
+	case m.confirm != "":
+		return confirmStyle.Render(m.confirm)
+	case m.status != "" && m.failed:
+		return errorStyle.Render(truncate("✗ "+m.status, m.width))
+	case m.status != "":
+		return truncate(m.status, m.width)
+	case m.pane == paneCatalog:
+		return faintStyle.Render("enter install · tab services · ? help · q quit")
+	default:
+		return faintStyle.Render("s start · x stop · r restart · d remove · tab catalog · ? help · q quit")
+	}
+}
+
+// columnWidths returns the width of each column of a table
+func columnWidths(header []string, rows [][]string) []int {
+	widths := make([]int, len(header))
+	for i, h := range header {
+		widths[i] = len(h)
+	}
+	for _, row := range rows {
+		for i, cell := range row {
+			if len(cell) > widths[i] {
+				widths[i] = len(cell)
+			}
+		}
+	}
+	return widths
+}
+
+// formatRow pads the cells of a row to the column widths
+func formatRow(cells []string, widths []int) string {
+	padded := make([]string, len(cells))
+	for i, cell := range cells {
+		if i == len(cells)-1 {
+			padded[i] = cell
+		} else {
+			padded[i] = cell + strings.Repeat(" ", widths[i]-len(cell))
+		}
+	}
+	return strings.Join(padded, "  ")
+}
+
+// truncate cuts a line to the width of the terminal (0 = unknown)
+func truncate(line string, width int) string {
+	runes := []rune(line)
+	if width <= 0 || len(runes) <= width {
+		return line
+	}
+	return string(runes[:width-1]) + "…"
+}
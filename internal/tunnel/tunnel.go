@@ -0,0 +1,174 @@
+// Package tunnel shares services on the internet through a tunnel client
+// run as a container next to Traefik
+package tunnel
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/network"
+	"github.com/docker/docker/pkg/stdcopy"
+	"github.com/dokulabs/doku-cli/internal/docker"
+)
+
+// Image is the image of the tunnel client: cloudflared, whose quick
+// tunnels need no account
+const Image = "cloudflare/cloudflared:latest"
+
+// StartTimeout is how long the client has to print the public URL
+const StartTimeout = 60 * time.Second
+
+// publicURL matches the URL of a quick tunnel in the client's output
+var publicURL = regexp.MustCompile(`https://[a-z0-9-]+\.trycloudflare\.com`)
+
+// Options describe what a tunnel forwards to
+type Options struct {
+	Name   string // Instance or project shared, naming the container
+	Host   string // Hostname Traefik routes to it, e.g. api.doku.local
+	Origin string // Traefik's entrypoint on doku-network, e.g. https://doku-traefik:443
+}
+
+// Tunnel is a running tunnel client
+type Tunnel struct {
+	dockerClient *docker.Client
+	containerID  string
+	URL          string // Public URL requests to are forwarded to the service
+}
+
+// ContainerName returns the name of the container sharing name
+func ContainerName(name string) string {
+	return docker.GenerateContainerName("share-" + name)
+}
+
+// Start runs the tunnel client and waits for its public URL. Requests to
+// it go through Traefik with the service's Host header, so its middlewares
+// apply. A container left behind by an earlier share is replaced.
+func Start(dockerClient *docker.Client, opts Options) (*Tunnel, error) {
+	if cached, err := dockerClient.ImageExists(Image); err != nil || !cached {
+		fmt.Printf("Pulling %s...\n", Image)
+		if err := dockerClient.ImagePull(Image); err != nil {
+			return nil, fmt.Errorf("failed to pull the tunnel client: %w", err)
+		}
+	}
+
+	name := ContainerName(opts.Name)
+	if exists, err := dockerClient.ContainerExists(name); err != nil {
+		return nil, err
+	} else if exists {
+		if err := dockerClient.ContainerRemove(name, true); err != nil {
+			return nil, fmt.Errorf("failed to remove the previous tunnel: %w", err)
+		}
+	}
+
+	config := &container.Config{
+		Image:  Image,
+		Cmd:    Args(opts),
+		Labels: docker.ComponentLabels("tunnel"),
+	}
+	networkConfig := &network.NetworkingConfig{
+		EndpointsConfig: map[string]*network.EndpointSettings{docker.NetworkName(): {}},
+	}
+	containerID, err := dockerClient.ContainerCreate(config, &container.HostConfig{}, networkConfig, name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create the tunnel: %w", err)
+	}
+
+	t := &Tunnel{dockerClient: dockerClient, containerID: containerID}
+	if err := dockerClient.ContainerStart(containerID); err != nil {
+		t.Close()
+		return nil, fmt.Errorf("failed to start the tunnel: %w", err)
+	}
+
+	url, err := t.waitForURL()
+	if err != nil {
+		t.Close()
+		return nil, err
+	}
+	t.URL = url
+	return t, nil
+}
+
+// Args returns the arguments of the tunnel client forwarding to opts.Origin
+// with opts.Host as the Host header and TLS server name. Traefik's
+// certificate is local, so it isn't verified.
+func Args(opts Options) []string {
+	return []string{
+		"tunnel", "--no-autoupdate",
+		"--url", opts.Origin,
+		"--http-host-header", opts.Host,
+		"--origin-server-name", opts.Host,
+		"--no-tls-verify",
+	}
+}
+
+// waitForURL follows the client's output until it prints the public URL
+func (t *Tunnel) waitForURL() (string, error) {
+	logs, err := t.dockerClient.ContainerLogs(t.containerID, true)
+	if err != nil {
+		return "", err
+	}
+	defer logs.Close()
+
+	found := make(chan string, 1)
+	go func() {
+		url, _ := FindURL(logs)
+		found <- url
+	}()
+
+	select {
+	case url := <-found:
+		if url == "" {
+			return "", fmt.Errorf("the tunnel stopped before getting a public URL: %s", t.output())
+		}
+		return url, nil
+	case <-time.After(StartTimeout):
+		return "", fmt.Errorf("the tunnel didn't get a public URL within %s: %s", StartTimeout, t.output())
+	}
+}
+
+// FindURL reads the client's output until it prints the public URL
+func FindURL(r io.Reader) (string, error) {
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		if url := publicURL.FindString(scanner.Text()); url != "" {
+			return url, nil
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return "", err
+	}
+	return "", io.EOF
+}
+
+// output returns the last lines of the client's output, to explain a
+// failure
+func (t *Tunnel) output() string {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	logs, err := t.dockerClient.ContainerLogsTail(ctx, t.containerID, 5)
+	if err != nil {
+		return err.Error()
+	}
+	defer logs.Close()
+
+	var out bytes.Buffer
+	_, _ = stdcopy.StdCopy(&out, &out, logs)
+	return strings.TrimSpace(out.String())
+}
+
+// Wait blocks until the tunnel client exits
+func (t *Tunnel) Wait() error {
+	return t.dockerClient.WaitForContainer(t.containerID)
+}
+
+// Close stops the tunnel and removes its container
+func (t *Tunnel) Close() error {
+	return t.dockerClient.ContainerRemove(t.containerID, true)
+}
@@ -0,0 +1,41 @@
+package tunnel
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestFindURL(t *testing.T) {
+	output := `2026-10-18T10:00:00Z INF Requesting new quick Tunnel on trycloudflare.com...
+2026-10-18T10:00:01Z INF +--------------------------------------------------------------------------------------------+
+2026-10-18T10:00:01Z INF |  Your quick Tunnel has been created! Visit it at (it may take some time to be reachable):  |
+2026-10-18T10:00:01Z INF |  https://quiet-river-example-1234.trycloudflare.com                                        |
+2026-10-18T10:00:01Z INF +--------------------------------------------------------------------------------------------+
+`
+	url, err := FindURL(strings.NewReader(output))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if url != "https://quiet-river-example-1234.trycloudflare.com" {
+		t.Errorf("FindURL() = %q", url)
+	}
+
+	if _, err := FindURL(strings.NewReader("ERR failed to request quick Tunnel\n")); err == nil {
+		t.Error("FindURL() found a URL in output without one")
+	}
+}
+
+func TestArgs(t *testing.T) {
+	got := Args(Options{Name: "api", Host: "api.doku.local", Origin: "https://doku-traefik:443"})
+	want := []string{
+		"tunnel", "--no-autoupdate",
+		"--url", "https://doku-traefik:443",
+		"--http-host-header", "api.doku.local",
+		"--origin-server-name", "api.doku.local",
+		"--no-tls-verify",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Args() = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,166 @@
+// Package updatecheck finds out whether newer releases of Doku and of its
+// service catalog are available. Checks are throttled to one a day and
+// their result is cached in the config.
+package updatecheck
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/dokulabs/doku-cli/internal/catalog"
+	"github.com/dokulabs/doku-cli/internal/timing"
+	"github.com/dokulabs/doku-cli/pkg/types"
+)
+
+const (
+	// Interval is how often the latest releases are checked
+	Interval = 24 * time.Hour
+
+	// EnvVar turns the check off when set to a true value
+	EnvVar = "DOKU_NO_UPDATE_CHECK"
+
+	// DefaultReleaseURL returns the latest Doku release
+	DefaultReleaseURL = "https://api.github.com/repos/dokulabs/doku-cli/releases/latest"
+
+	// DefaultCatalogURL is the metadata of the latest catalog
+	DefaultCatalogURL = "https://raw.githubusercontent.com/dokulabs/doku-catalog/main/catalog.yaml"
+)
+
+// Checker fetches the latest releases
+type Checker struct {
+	Client     *http.Client
+	ReleaseURL string
+	CatalogURL string // Empty = don't check the catalog, e.g. for a custom catalog source
+}
+
+// New creates a checker of the official releases
+func New() *Checker {
+	return &Checker{
+		Client:     http.DefaultClient,
+		ReleaseURL: DefaultReleaseURL,
+		CatalogURL: DefaultCatalogURL,
+	}
+}
+
+// DisabledByEnv reports whether the environment turns the check off
+func DisabledByEnv() bool {
+	off, err := strconv.ParseBool(os.Getenv(EnvVar))
+	return err == nil && off
+}
+
+// Due reports whether the releases should be checked again
+func Due(state *types.UpdateCheckState, now time.Time) bool {
+	return state == nil || now.Sub(state.CheckedAt) >= Interval
+}
+
+// Check fetches the latest releases. A release that can't be fetched keeps
+// its previous value, so being offline doesn't hide a known update.
+func (c *Checker) Check(ctx context.Context, previous *types.UpdateCheckState) (*types.UpdateCheckState, error) {
+	state := &types.UpdateCheckState{CheckedAt: time.Now()}
+	if previous != nil {
+		state.LatestVersion, state.LatestCatalog = previous.LatestVersion, previous.LatestCatalog
+	}
+
+	var errs []string
+	if version, err := c.latestRelease(ctx); err != nil {
+		errs = append(errs, err.Error())
+	} else {
+		state.LatestVersion = version
+	}
+	if c.CatalogURL != "" {
+		if version, err := c.latestCatalog(ctx); err != nil {
+			errs = append(errs, err.Error())
+		} else {
+			state.LatestCatalog = version
+		}
+	}
+
+	if len(errs) > 0 {
+		return state, fmt.Errorf("failed to check for updates: %s", strings.Join(errs, "; "))
+	}
+	return state, nil
+}
+
+func (c *Checker) latestRelease(ctx context.Context) (string, error) {
+	body, err := c.get(ctx, c.ReleaseURL)
+	if err != nil {
+		return "", err
+	}
+
+	var release struct {
+		TagName string `json:"tag_name"`
+	}
+	if err := json.Unmarshal(body, &release); err != nil {
+		return "", fmt.Errorf("failed to parse release info: %w", err)
+	}
+	return strings.TrimPrefix(release.TagName, "v"), nil
+}
+
+func (c *Checker) latestCatalog(ctx context.Context) (string, error) {
+	body, err := c.get(ctx, c.CatalogURL)
+	if err != nil {
+		return "", err
+	}
+
+	var metadata catalog.CatalogMetadata
+	if err := yaml.Unmarshal(body, &metadata); err != nil {
+		return "", fmt.Errorf("failed to parse catalog metadata: %w", err)
+	}
+	return metadata.Version, nil
+}
+
+func (c *Checker) get(ctx context.Context, url string) ([]byte, error) {
+	defer timing.Track(timing.Network, "check for updates")()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.Client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%s returned status: %s", url, resp.Status)
+	}
+	return io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+}
+
+// Notices returns a line for each newer release than the running Doku
+// version and the installed catalog version. Development builds and
+// unknown versions aren't compared.
+func Notices(state *types.UpdateCheckState, currentVersion, catalogVersion string) []string {
+	if state == nil {
+		return nil
+	}
+
+	var notices []string
+	if newer(state.LatestVersion, currentVersion) {
+		notices = append(notices, fmt.Sprintf("Doku %s is available (you have %s). Upgrade with: doku self upgrade",
+			state.LatestVersion, strings.TrimPrefix(currentVersion, "v")))
+	}
+	if newer(state.LatestCatalog, catalogVersion) {
+		notices = append(notices, fmt.Sprintf("Catalog %s is available (you have %s). Update with: doku catalog update",
+			state.LatestCatalog, catalogVersion))
+	}
+	return notices
+}
+
+// newer reports whether latest is a newer version than current
+func newer(latest, current string) bool {
+	if latest == "" || current == "" || current == "dev" || current == "unknown" {
+		return false
+	}
+	return catalog.CompareVersions(latest, current) > 0
+}
@@ -0,0 +1,94 @@
+package updatecheck
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dokulabs/doku-cli/pkg/types"
+)
+
+func TestDue(t *testing.T) {
+	now := time.Now()
+	if !Due(nil, now) {
+		t.Error("never checked should be due")
+	}
+	if Due(&types.UpdateCheckState{CheckedAt: now.Add(-time.Hour)}, now) {
+		t.Error("checked an hour ago shouldn't be due")
+	}
+	if !Due(&types.UpdateCheckState{CheckedAt: now.Add(-25 * time.Hour)}, now) {
+		t.Error("checked yesterday should be due")
+	}
+}
+
+func TestCheck(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/release":
+			w.Write([]byte(`{"tag_name": "v1.5.0", "name": "Doku 1.5.0"}`))
+		case "/catalog.yaml":
+			w.Write([]byte("version: \"2.1.0\"\nformat: hierarchical\n"))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	checker := &Checker{Client: server.Client(), ReleaseURL: server.URL + "/release", CatalogURL: server.URL + "/catalog.yaml"}
+	state, err := checker.Check(context.Background(), nil)
+	if err != nil {
+		t.Fatalf("Check() failed: %v", err)
+	}
+	if state.LatestVersion != "1.5.0" || state.LatestCatalog != "2.1.0" {
+		t.Errorf("Check() = %+v, expected 1.5.0 and catalog 2.1.0", state)
+	}
+	if state.CheckedAt.IsZero() {
+		t.Error("CheckedAt should be set")
+	}
+
+	// A failed check keeps what was known
+	checker.CatalogURL = server.URL + "/missing"
+	state, err = checker.Check(context.Background(), &types.UpdateCheckState{LatestCatalog: "2.0.0"})
+	if err == nil {
+		t.Error("Check() should fail when the catalog can't be fetched")
+	}
+	if state.LatestVersion != "1.5.0" || state.LatestCatalog != "2.0.0" {
+		t.Errorf("Check() = %+v, expected 1.5.0 and the previous catalog 2.0.0", state)
+	}
+}
+
+func TestNotices(t *testing.T) {
+	state := &types.UpdateCheckState{LatestVersion: "1.5.0", LatestCatalog: "2.1.0"}
+
+	tests := []struct {
+		name            string
+		current, catver string
+		want            []string
+	}{
+		{"both newer", "1.4.2", "2.0.0", []string{"Doku 1.5.0 is available (you have 1.4.2)", "Catalog 2.1.0 is available (you have 2.0.0)"}},
+		{"v prefix", "v1.4.2", "2.1.0", []string{"Doku 1.5.0 is available (you have 1.4.2)"}},
+		{"up to date", "1.5.0", "2.1.0", nil},
+		{"ahead", "1.6.0", "", nil},
+		{"development build", "dev", "2.1.0", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Notices(state, tt.current, tt.catver)
+			if len(got) != len(tt.want) {
+				t.Fatalf("Notices() = %q, expected %d notices", got, len(tt.want))
+			}
+			for i, want := range tt.want {
+				if !strings.HasPrefix(got[i], want) {
+					t.Errorf("notice %d = %q, expected it to start with %q", i, got[i], want)
+				}
+			}
+		})
+	}
+
+	if got := Notices(nil, "1.0.0", "1.0.0"); got != nil {
+		t.Errorf("Notices(nil) = %q, expected none", got)
+	}
+}
@@ -0,0 +1,298 @@
+// Package workspace selects the Doku workspace a command works in. Each
+// workspace is a separate Doku setup with its own config directory, Docker
+// network, container and volume names, domain and Traefik, so that the
+// services of different clients don't share any state. The default
+// workspace is the original ~/.doku setup.
+package workspace
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// EnvVar selects the workspace when --workspace isn't given
+	EnvVar = "DOKU_WORKSPACE"
+
+	// DirName is the directory of the workspaces, in the Doku directory
+	DirName = "workspaces"
+
+	// Label marks the Docker resources of a workspace other than the
+	// default one with its name
+	Label = "doku.workspace"
+
+	// MarkerDir and MarkerFile name the file, .doku/workspace, that selects
+	// the workspace of commands run in a project directory
+	MarkerDir  = ".doku"
+	MarkerFile = "workspace"
+
+	// manifestFileName is the manifest of 'doku apply', whose workspace:
+	// key selects a workspace too
+	manifestFileName = "doku.yaml"
+
+	// maxNameLength keeps container names, which embed the workspace name,
+	// readable
+	maxNameLength = 32
+)
+
+var nameRe = regexp.MustCompile(`^[a-z0-9]([a-z0-9-]*[a-z0-9])?$`)
+
+var (
+	mu      sync.RWMutex
+	current *string // nil = not set, use EnvVar
+)
+
+// Validate checks that a name can be used as a workspace name
+func Validate(name string) error {
+	if name == "default" {
+		return fmt.Errorf("'default' is the workspace used without --workspace")
+	}
+	if len(name) > maxNameLength {
+		return fmt.Errorf("workspace name is too long (max %d characters)", maxNameLength)
+	}
+	if !nameRe.MatchString(name) {
+		return fmt.Errorf("invalid workspace name %q: use lowercase letters, digits and dashes", name)
+	}
+	return nil
+}
+
+// Set selects the workspace for the rest of the process ("" or "default"
+// = the default workspace)
+func Set(name string) error {
+	if name == "default" {
+		name = ""
+	}
+	if name != "" {
+		if err := Validate(name); err != nil {
+			return err
+		}
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	current = &name
+	return nil
+}
+
+// Current returns the selected workspace, "" for the default one
+func Current() string {
+	mu.RLock()
+	defer mu.RUnlock()
+	if current != nil {
+		return *current
+	}
+	if name := os.Getenv(EnvVar); name != "default" && Validate(name) == nil {
+		return name
+	}
+	return ""
+}
+
+// Detect finds the workspace selected for a directory, like direnv scopes
+// environments: by a .doku/workspace file holding its name, or by the
+// workspace: key of a doku.yaml manifest, in the directory or the nearest
+// parent that has either. It returns the name ("" for the default
+// workspace) and the file naming it, or no file if none does. The Doku
+// directory itself, e.g. ~/.doku, isn't a marker.
+func Detect(dir, dokuDir string) (name, path string, err error) {
+	dir, err = filepath.Abs(dir)
+	if err != nil {
+		return "", "", err
+	}
+
+	for {
+		markerDir := filepath.Join(dir, MarkerDir)
+		if markerDir != filepath.Clean(dokuDir) {
+			path = filepath.Join(markerDir, MarkerFile)
+			if data, err := os.ReadFile(path); err == nil {
+				name, err := parseName(strings.TrimSpace(string(data)))
+				if err != nil {
+					return "", "", fmt.Errorf("%s: %w", path, err)
+				}
+				return name, path, nil
+			}
+		}
+
+		path = filepath.Join(dir, manifestFileName)
+		if data, err := os.ReadFile(path); err == nil {
+			var manifest struct {
+				Workspace string `yaml:"workspace"`
+			}
+			// An invalid manifest is reported by 'doku apply'
+			if yaml.Unmarshal(data, &manifest) == nil && manifest.Workspace != "" {
+				name, err := parseName(manifest.Workspace)
+				if err != nil {
+					return "", "", fmt.Errorf("%s: %w", path, err)
+				}
+				return name, path, nil
+			}
+		}
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", "", nil
+		}
+		dir = parent
+	}
+}
+
+// WriteMarker selects a workspace for a directory with a .doku/workspace
+// file, and returns its path
+func WriteMarker(dir, name string) (string, error) {
+	if name != "default" {
+		if err := Validate(name); err != nil {
+			return "", err
+		}
+	}
+
+	path := filepath.Join(dir, MarkerDir, MarkerFile)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return "", fmt.Errorf("failed to create %s: %w", filepath.Dir(path), err)
+	}
+	if err := os.WriteFile(path, []byte(name+"\n"), 0644); err != nil {
+		return "", fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return path, nil
+}
+
+// parseName checks a workspace name read from a file, "default" being the
+// default workspace
+func parseName(name string) (string, error) {
+	if name == "default" {
+		return "", nil
+	}
+	if err := Validate(name); err != nil {
+		return "", err
+	}
+	return name, nil
+}
+
+// DisplayName returns the name of the selected workspace, "default" for
+// the default one
+func DisplayName() string {
+	if name := Current(); name != "" {
+		return name
+	}
+	return "default"
+}
+
+// Dir returns the directory of the selected workspace, given the Doku
+// directory
+func Dir(dokuDir string) string {
+	if name := Current(); name != "" {
+		return Path(dokuDir, name)
+	}
+	return dokuDir
+}
+
+// Path returns the directory of a workspace other than the default one
+func Path(dokuDir, name string) string {
+	return filepath.Join(dokuDir, DirName, name)
+}
+
+// List returns the names of the workspaces created in the Doku directory,
+// sorted, without the default one
+func List(dokuDir string) ([]string, error) {
+	entries, err := os.ReadDir(filepath.Join(dokuDir, DirName))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read workspaces: %w", err)
+	}
+
+	var names []string
+	for _, entry := range entries {
+		if entry.IsDir() && Validate(entry.Name()) == nil {
+			names = append(names, entry.Name())
+		}
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// Prefix returns the prefix of the names of the selected workspace's
+// containers and volumes, e.g. "doku-" or "doku-work-"
+func Prefix() string {
+	if name := Current(); name != "" {
+		return "doku-" + name + "-"
+	}
+	return "doku-"
+}
+
+// NetworkName returns the name of the selected workspace's Docker network
+func NetworkName() string {
+	return Prefix() + "network"
+}
+
+// TraefikContainerName returns the name of the selected workspace's
+// Traefik container
+func TraefikContainerName() string {
+	return Prefix() + "traefik"
+}
+
+// Domain returns the default domain of the selected workspace, e.g.
+// work.doku.local, given the default domain
+func Domain(base string) string {
+	if name := Current(); name != "" {
+		return name + "." + base
+	}
+	return base
+}
+
+// Labels returns the labels marking a Docker resource as part of the
+// selected workspace: none for the default one
+func Labels() map[string]string {
+	if name := Current(); name != "" {
+		return map[string]string{Label: name}
+	}
+	return nil
+}
+
+// Owns reports whether a Docker resource with the given labels belongs to
+// the selected workspace
+func Owns(labels map[string]string) bool {
+	return labels[Label] == Current()
+}
+
+// maxSlot is the number of workspaces that get a network in 172.16.0.0/12
+// besides the default one's 172.20.0.0/16
+const maxSlot = 11
+
+// FreeSlot returns the first slot whose subnet isn't in used. A slot
+// numbers a workspace's network and default ports, so that they don't clash
+// with the other workspaces'.
+func FreeSlot(used []string) (int, error) {
+	taken := make(map[string]bool, len(used))
+	for _, subnet := range used {
+		taken[subnet] = true
+	}
+	for slot := 1; slot <= maxSlot; slot++ {
+		if subnet, _ := Subnet(slot); !taken[subnet] {
+			return slot, nil
+		}
+	}
+	return 0, fmt.Errorf("no free network for a new workspace: remove one first")
+}
+
+// Subnet returns the subnet and gateway of a slot's network
+func Subnet(slot int) (subnet, gateway string) {
+	return fmt.Sprintf("172.%d.0.0/16", 20+slot), fmt.Sprintf("172.%d.0.1", 20+slot)
+}
+
+// Ports returns the default HTTP and HTTPS ports of a slot's Traefik
+func Ports(slot int) (httpPort, httpsPort int) {
+	return 8000 + slot, 8443 + slot
+}
+
+// LoopbackIP returns the loopback alias a slot's workspace can bind to, so
+// its Traefik keeps ports 80 and 443: 127.0.0.2 for slot 1, and so on
+func LoopbackIP(slot int) string {
+	return fmt.Sprintf("127.0.0.%d", 1+slot)
+}
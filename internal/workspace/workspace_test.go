@@ -0,0 +1,163 @@
+package workspace
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestValidate(t *testing.T) {
+	for _, name := range []string{"work", "client-a", "x1"} {
+		if err := Validate(name); err != nil {
+			t.Errorf("Validate(%q) failed: %v", name, err)
+		}
+	}
+	for _, name := range []string{"", "default", "Work", "-work", "work-", "a_b", "a.b", "abcdefghijklmnopqrstuvwxyz0123456789"} {
+		if err := Validate(name); err == nil {
+			t.Errorf("Validate(%q) should fail", name)
+		}
+	}
+}
+
+func TestNames(t *testing.T) {
+	t.Cleanup(func() { Set("") })
+
+	Set("")
+	if Prefix() != "doku-" || NetworkName() != "doku-network" || TraefikContainerName() != "doku-traefik" {
+		t.Errorf("default names = %s, %s, %s", Prefix(), NetworkName(), TraefikContainerName())
+	}
+	if Domain("doku.local") != "doku.local" || Labels() != nil {
+		t.Errorf("default domain = %s, labels = %v", Domain("doku.local"), Labels())
+	}
+	if !Owns(map[string]string{}) || Owns(map[string]string{Label: "work"}) {
+		t.Error("the default workspace should only own unlabelled resources")
+	}
+
+	if err := Set("work"); err != nil {
+		t.Fatal(err)
+	}
+	if Prefix() != "doku-work-" || NetworkName() != "doku-work-network" || TraefikContainerName() != "doku-work-traefik" {
+		t.Errorf("work names = %s, %s, %s", Prefix(), NetworkName(), TraefikContainerName())
+	}
+	if Domain("doku.local") != "work.doku.local" || Labels()[Label] != "work" {
+		t.Errorf("work domain = %s, labels = %v", Domain("doku.local"), Labels())
+	}
+	if Owns(map[string]string{}) || !Owns(map[string]string{Label: "work"}) {
+		t.Error("a workspace should only own its labelled resources")
+	}
+	if Dir("/home/u/.doku") != filepath.Join("/home/u/.doku", DirName, "work") {
+		t.Errorf("Dir() = %s", Dir("/home/u/.doku"))
+	}
+
+	if err := Set("Bad Name"); err == nil {
+		t.Error("Set() should reject an invalid name")
+	}
+	if Set("default"); Current() != "" {
+		t.Errorf("Set(default) selected %q", Current())
+	}
+}
+
+func TestCurrentFromEnv(t *testing.T) {
+	mu.Lock()
+	current = nil
+	mu.Unlock()
+	t.Cleanup(func() { Set("") })
+
+	t.Setenv(EnvVar, "client")
+	if Current() != "client" {
+		t.Errorf("Current() = %q, expected client", Current())
+	}
+	Set("")
+	if Current() != "" {
+		t.Errorf("Set() should override %s, got %q", EnvVar, Current())
+	}
+}
+
+func TestList(t *testing.T) {
+	dir := t.TempDir()
+	if names, err := List(dir); err != nil || names != nil {
+		t.Fatalf("List() = %v, %v, expected none", names, err)
+	}
+
+	for _, name := range []string{"zeta", "alpha", "Not_Valid"} {
+		if err := os.MkdirAll(Path(dir, name), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	os.WriteFile(filepath.Join(dir, DirName, "file"), nil, 0644)
+
+	names, err := List(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(names) != 2 || names[0] != "alpha" || names[1] != "zeta" {
+		t.Errorf("List() = %v, expected [alpha zeta]", names)
+	}
+}
+
+func TestFreeSlot(t *testing.T) {
+	slot, err := FreeSlot([]string{"172.17.0.0/16", "172.20.0.0/16", "172.21.0.0/16"})
+	if err != nil || slot != 2 {
+		t.Fatalf("FreeSlot() = %d, %v, expected 2", slot, err)
+	}
+	if subnet, gateway := Subnet(slot); subnet != "172.22.0.0/16" || gateway != "172.22.0.1" {
+		t.Errorf("Subnet(2) = %s, %s", subnet, gateway)
+	}
+	if httpPort, httpsPort := Ports(slot); httpPort != 8002 || httpsPort != 8445 {
+		t.Errorf("Ports(2) = %d, %d", httpPort, httpsPort)
+	}
+	if ip := LoopbackIP(slot); ip != "127.0.0.3" {
+		t.Errorf("LoopbackIP(2) = %s", ip)
+	}
+
+	var used []string
+	for slot := 1; slot <= maxSlot; slot++ {
+		subnet, _ := Subnet(slot)
+		used = append(used, subnet)
+	}
+	if _, err := FreeSlot(used); err == nil {
+		t.Error("FreeSlot() should fail when every slot is used")
+	}
+}
+
+func TestDetect(t *testing.T) {
+	root := t.TempDir()
+	dokuDir := filepath.Join(root, ".doku")
+	project := filepath.Join(root, "clients", "acme")
+	sub := filepath.Join(project, "api", "src")
+	if err := os.MkdirAll(sub, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	// The Doku directory isn't a marker
+	os.MkdirAll(dokuDir, 0755)
+	os.WriteFile(filepath.Join(dokuDir, MarkerFile), []byte("ignored\n"), 0644)
+	if name, path, err := Detect(sub, dokuDir); err != nil || name != "" || path != "" {
+		t.Fatalf("Detect() = %q, %q, %v, expected nothing", name, path, err)
+	}
+
+	// A manifest's workspace: key, found from a subdirectory
+	os.WriteFile(filepath.Join(project, "doku.yaml"), []byte("workspace: acme\nservices:\n  postgres: {}\n"), 0644)
+	name, path, err := Detect(sub, dokuDir)
+	if err != nil || name != "acme" || path != filepath.Join(project, "doku.yaml") {
+		t.Errorf("Detect() = %q, %q, %v, expected acme from doku.yaml", name, path, err)
+	}
+
+	// The nearest marker wins, and "default" is the default workspace
+	marker, err := WriteMarker(filepath.Join(project, "api"), "default")
+	if err != nil {
+		t.Fatal(err)
+	}
+	name, path, err = Detect(sub, dokuDir)
+	if err != nil || name != "" || path != marker {
+		t.Errorf("Detect() = %q, %q, %v, expected the default workspace from %s", name, path, err, marker)
+	}
+
+	os.WriteFile(marker, []byte("Not Valid\n"), 0644)
+	if _, _, err := Detect(sub, dokuDir); err == nil {
+		t.Error("Detect() should fail on an invalid name")
+	}
+	if _, err := WriteMarker(project, "Not Valid"); err == nil {
+		t.Error("WriteMarker() should reject an invalid name")
+	}
+}
@@ -21,6 +21,7 @@ type CatalogService struct {
 type ServiceSpec struct {
 	// Single-container fields (backward compatible)
 	Image         string                `toml:"image" yaml:"image"`                 // Docker image with tag
+	Images        map[string]string     `toml:"images" yaml:"images"`               // Per-architecture image overrides (e.g., arm64 = "...")
 	Description   string                `toml:"description" yaml:"description"`     // Version-specific description
 	Port          int                   `toml:"port" yaml:"port"`                   // Main service port (exposed via Traefik)
 	AdminPort     int                   `toml:"admin_port" yaml:"admin_port"`       // Optional admin/management port
@@ -32,6 +33,8 @@ type ServiceSpec struct {
 	Healthcheck   *Healthcheck          `toml:"healthcheck" yaml:"healthcheck"`     // Health check configuration
 	Resources     *ResourceRequirements `toml:"resources" yaml:"resources"`         // CPU/memory requirements
 	Configuration *ServiceConfiguration `toml:"configuration" yaml:"configuration"` // Configuration options
+	HostNetwork   bool                  `toml:"host_network" yaml:"host_network"`   // Run on the host's network stack (no Traefik routing)
+	Deprecated    string                `toml:"deprecated" yaml:"deprecated"`       // Why the version shouldn't be installed anymore, e.g. "end of life, use 17"
 
 	// Multi-container support (new)
 	Containers     []ContainerSpec `toml:"containers" yaml:"containers"`           // Multiple containers for this service
@@ -45,6 +48,7 @@ type ServiceSpec struct {
 type ContainerSpec struct {
 	Name        string                `toml:"name" yaml:"name"`               // Container name (e.g., "frontend", "query-service")
 	Image       string                `toml:"image" yaml:"image"`             // Docker image with tag
+	Images      map[string]string     `toml:"images" yaml:"images"`           // Per-architecture image overrides
 	Primary     bool                  `toml:"primary" yaml:"primary"`         // Is this the primary/main container (default: first)
 	Ports       []string              `toml:"ports" yaml:"ports"`             // Port mappings (e.g., "3301:3301")
 	Environment map[string]string     `toml:"environment" yaml:"environment"` // Container-specific environment variables
@@ -61,6 +65,7 @@ type ContainerSpec struct {
 type InitContainer struct {
 	Name        string            `toml:"name" yaml:"name"`               // Init container name (e.g., "migrator-sync")
 	Image       string            `toml:"image" yaml:"image"`             // Docker image with tag
+	Images      map[string]string `toml:"images" yaml:"images"`           // Per-architecture image overrides
 	Command     []string          `toml:"command" yaml:"command"`         // Command to run
 	Environment map[string]string `toml:"environment" yaml:"environment"` // Environment variables
 	DependsOn   []string          `toml:"depends_on" yaml:"depends_on"`   // Dependencies (must complete before this runs)
@@ -83,11 +88,11 @@ type ServiceLinks struct {
 
 // Healthcheck defines health check configuration
 type Healthcheck struct {
-	Test     []string `toml:"test"`         // Health check command
-	Interval string   `toml:"interval"`     // Check interval (e.g., "30s")
-	Timeout  string   `toml:"timeout"`      // Check timeout
-	Retries  int      `toml:"retries"`      // Number of retries
-	Start    string   `toml:"start_period"` // Start period before checks begin
+	Test     []string `toml:"test" yaml:"test"`                 // Health check command
+	Interval string   `toml:"interval" yaml:"interval"`         // Check interval (e.g., "30s")
+	Timeout  string   `toml:"timeout" yaml:"timeout"`           // Check timeout
+	Retries  int      `toml:"retries" yaml:"retries"`           // Number of retries
+	Start    string   `toml:"start_period" yaml:"start_period"` // Start period before checks begin
 }
 
 // ResourceRequirements defines default resource requirements
@@ -121,6 +126,9 @@ type ConnectionInfo struct {
 	Port     int               `json:"port"`
 	URL      string            `json:"url"`
 	Protocol string            `json:"protocol"`
+	User     string            `json:"user,omitempty"`
+	Password string            `json:"password,omitempty"`
+	Database string            `json:"database,omitempty"`
 	Env      map[string]string `json:"env"` // Environment variables for connection
 }
 
@@ -163,6 +171,36 @@ func (s *ServiceSpec) GetContainerByName(name string) *ContainerSpec {
 	return nil
 }
 
+// ForArch returns a copy of the spec with the per-architecture image
+// overrides for arch (e.g., "arm64") applied. The spec itself is left
+// unchanged, since catalog specs are shared.
+func (s *ServiceSpec) ForArch(arch string) *ServiceSpec {
+	spec := *s
+	spec.Image = imageForArch(s.Image, s.Images, arch)
+
+	spec.Containers = make([]ContainerSpec, len(s.Containers))
+	for i, c := range s.Containers {
+		c.Image = imageForArch(c.Image, c.Images, arch)
+		spec.Containers[i] = c
+	}
+
+	spec.InitContainers = make([]InitContainer, len(s.InitContainers))
+	for i, c := range s.InitContainers {
+		c.Image = imageForArch(c.Image, c.Images, arch)
+		spec.InitContainers[i] = c
+	}
+
+	return &spec
+}
+
+// imageForArch returns the override for arch, or image if there is none
+func imageForArch(image string, images map[string]string, arch string) string {
+	if override, ok := images[arch]; ok && override != "" {
+		return override
+	}
+	return image
+}
+
 // HasDependencies returns true if this service has dependencies
 func (s *ServiceSpec) HasDependencies() bool {
 	return len(s.Dependencies) > 0
@@ -198,6 +236,10 @@ func (s *ServiceSpec) Validate() error {
 
 	// Multi-container validation
 	if len(s.Containers) > 0 {
+		if s.HostNetwork {
+			return &ValidationError{Field: "host_network", Message: "host networking is only supported for single-container services"}
+		}
+
 		// Check for duplicate container names
 		names := make(map[string]bool)
 		primaryCount := 0
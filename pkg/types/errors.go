@@ -10,11 +10,13 @@ var (
 	ErrAlreadyRunning    = errors.New("service is already running")
 	ErrAlreadyStopped    = errors.New("service is already stopped")
 	ErrInvalidService    = errors.New("invalid service configuration")
+	ErrNoRollback        = errors.New("no previous version recorded")
 
 	// Configuration errors
 	ErrNotInitialized = errors.New("doku is not initialized")
 	ErrConfigNotFound = errors.New("configuration not found")
 	ErrInvalidConfig  = errors.New("invalid configuration")
+	ErrReadOnly       = errors.New("doku is in read-only mode")
 
 	// Catalog errors
 	ErrCatalogNotFound = errors.New("catalog not found")
@@ -10,6 +10,7 @@ const (
 	StatusStopped ServiceStatus = "stopped"
 	StatusFailed  ServiceStatus = "failed"
 	StatusUnknown ServiceStatus = "unknown"
+	StatusMissing ServiceStatus = "missing" // Containers removed outside Doku, e.g. with docker rm
 )
 
 // Service represents a service from the catalog
@@ -102,13 +103,23 @@ type Instance struct {
 	Name         string
 	ServiceType  string
 	Version      string
-	Status       ServiceStatus
-	HealthStatus string // Health check status: healthy, unhealthy, starting, none, unknown
+	Status       ServiceStatus // Last status observed in Docker
+	HealthStatus string        // Health check status: healthy, unhealthy, starting, none, unknown
+
+	// DesiredState is what the user asked for with install, start and stop:
+	// running or stopped. Unlike Status it doesn't change when a container
+	// crashes or Docker restarts.
+	DesiredState ServiceStatus `yaml:"desired_state,omitempty"`
 
 	// Single-container fields (backward compatible)
 	ContainerName string
 	ContainerID   string // Docker container ID
 
+	// ImageDigest pins the image the container was created from, e.g.
+	// "postgres@sha256:...", so recreating it doesn't pick up a tag that
+	// has since moved. Empty for images with no repo digest.
+	ImageDigest string `yaml:"image_digest,omitempty"`
+
 	// Multi-container support (new)
 	IsMultiContainer bool            `yaml:"is_multi_container"` // Whether this is a multi-container service
 	Containers       []ContainerInfo `yaml:"containers"`         // Container information for multi-container services
@@ -116,6 +127,10 @@ type Instance struct {
 	// Dependencies
 	Dependencies []string `yaml:"dependencies"` // List of service dependencies
 
+	// DependencyOf lists the instances this one was installed for as a
+	// dependency; empty if it was installed by itself
+	DependencyOf []string `yaml:"dependency_of,omitempty"`
+
 	URL              string
 	ConnectionString string
 	CreatedAt        time.Time
@@ -125,22 +140,139 @@ type Instance struct {
 	Traefik          TraefikInstanceConfig
 	Volumes          map[string]string
 	Environment      map[string]string
+	Labels           map[string]string // Extra Docker labels given at install (--label)
+
+	// Previous holds the state replaced by the last image change (used by rollback)
+	Previous *InstanceSnapshot
+
+	// AutoUpdate is the opt-in to automatic image updates (nil = off)
+	AutoUpdate *AutoUpdateConfig
+
+	// Links are the services whose connection details were injected into
+	// this instance's env file with 'doku link'
+	Links []Link `yaml:"links,omitempty"`
+
+	// Replicas is the number of containers set with 'doku scale', the
+	// original included (0 = 1)
+	Replicas int `yaml:"replicas,omitempty"`
+
+	// Logs overrides the preferences' rotation of the containers' logs
+	// (nil = the preferences')
+	Logs *LogRotation `yaml:"logs,omitempty"`
+
+	// Middlewares are the Traefik middlewares in front of the instance's
+	// HTTP route, set with 'doku expose' (nil = none)
+	Middlewares *Middlewares `yaml:"middlewares,omitempty"`
+
+	// Domains are hostnames of the instance's own, set with
+	// 'doku install --domain', on top of its subdomain of each base domain
+	Domains []string `yaml:"domains,omitempty"`
+
+	// TLSSANs are hostnames and IP addresses the instance's own certificate
+	// covers on top of its hostname, set with 'doku install --tls-san', for
+	// clients that verify the name they connect to (nil = the domain's
+	// certificate)
+	TLSSANs []string `yaml:"tls_sans,omitempty"`
+
+	// LAN exposes the instance to the other devices of the network, set
+	// with 'doku expose --lan' (nil = this machine only)
+	LAN *LANExposure `yaml:"lan,omitempty"`
+}
+
+// LANExposure makes an instance reachable from the other devices of the
+// network through Traefik, at hostnames resolving to the machine's LAN IP
+type LANExposure struct {
+	IP   string `yaml:"ip"`             // The machine's address on the network, e.g. 192.168.1.20
+	MDNS bool   `yaml:"mdns,omitempty"` // Also reachable at <subdomain>.local, announced over mDNS
+}
+
+// Middlewares are Traefik middlewares requests to an instance go through
+type Middlewares struct {
+	BasicAuth      []string          `yaml:"basic_auth,omitempty"`      // htpasswd entries, "user:$apr1$..."
+	IPAllowList    []string          `yaml:"ip_allow_list,omitempty"`   // Client IPs or CIDR ranges let through
+	RateLimit      *RateLimit        `yaml:"rate_limit,omitempty"`      // nil = unlimited
+	Headers        map[string]string `yaml:"headers,omitempty"`         // Added to responses
+	RequestHeaders map[string]string `yaml:"request_headers,omitempty"` // Added to requests
+}
+
+// IsEmpty reports whether no middleware is set
+func (m *Middlewares) IsEmpty() bool {
+	return m == nil || (len(m.BasicAuth) == 0 && len(m.IPAllowList) == 0 && m.RateLimit == nil &&
+		len(m.Headers) == 0 && len(m.RequestHeaders) == 0)
+}
+
+// RateLimit lets Average requests per second through on average per
+// client IP, with bursts of up to Burst
+type RateLimit struct {
+	Average int `yaml:"average"`
+	Burst   int `yaml:"burst,omitempty"`
+}
+
+// LogRotation bounds the json-file logs Docker keeps of a container: up to
+// MaxFile files of MaxSize each. Empty fields take the defaults.
+type LogRotation struct {
+	MaxSize string `yaml:"maxsize,omitempty"` // e.g. "10m" or "1g"
+	MaxFile int    `yaml:"maxfile,omitempty"`
+}
+
+// Link records a service linked to an instance, the prefix of the
+// variables it injected, e.g. POSTGRES for POSTGRES_HOST, and their values
+type Link struct {
+	Service string
+	Prefix  string
+	Env     map[string]string `yaml:"env,omitempty"`
+}
+
+// InstanceSnapshot records the image, version and environment an instance ran
+// before its container was recreated with a different image
+type InstanceSnapshot struct {
+	Image       string
+	Version     string
+	Environment map[string]string
+	CapturedAt  time.Time
+}
+
+// AutoUpdateConfig holds an instance's automatic image update policy: the
+// image's tag is pulled again and the container recreated when it points
+// to a new image
+type AutoUpdateConfig struct {
+	Window    string             // Daily time window for updates, e.g. "02:00-05:00" (empty = any time)
+	Interval  time.Duration      // Minimum time between checks
+	LastCheck time.Time          // When the image was last checked
+	History   []AutoUpdateRecord // Updates applied, oldest first
+}
+
+// AutoUpdateRecord records an automatic image update
+type AutoUpdateRecord struct {
+	Image      string // Image reference, e.g. "amir20/dozzle:latest"
+	OldImageID string
+	NewImageID string
+	UpdatedAt  time.Time
 }
 
 // ContainerInfo holds information about a container in a multi-container service
 type ContainerInfo struct {
-	Name        string   `yaml:"name"`      // Container name (e.g., "frontend", "query-service")
-	ContainerID string   `yaml:"id"`        // Docker container ID
-	FullName    string   `yaml:"full_name"` // Full container name (e.g., "doku-signoz-frontend")
-	Primary     bool     `yaml:"primary"`   // Is this the primary/main container?
-	Status      string   `yaml:"status"`    // Container status (running, stopped, etc.)
-	Ports       []string `yaml:"ports"`     // Port mappings
-	Image       string   `yaml:"image"`     // Docker image used
+	Name        string   `yaml:"name"`                 // Container name (e.g., "frontend", "query-service")
+	ContainerID string   `yaml:"id"`                   // Docker container ID
+	FullName    string   `yaml:"full_name"`            // Full container name (e.g., "doku-signoz-frontend")
+	Primary     bool     `yaml:"primary"`              // Is this the primary/main container?
+	Status      string   `yaml:"status"`               // Container status (running, stopped, etc.)
+	Ports       []string `yaml:"ports"`                // Port mappings
+	Image       string   `yaml:"image"`                // Docker image used
+	DependsOn   []string `yaml:"depends_on,omitempty"` // Containers of the same instance that must start first
+
+	// ImageDigest pins Image by digest, like Instance.ImageDigest
+	ImageDigest string `yaml:"image_digest,omitempty"`
 }
 
+// NetworkModeHost is the network mode of instances that share the host's
+// network stack
+const NetworkModeHost = "host"
+
 // NetworkConfig holds network configuration for an instance
 type NetworkConfig struct {
 	Name         string
+	Mode         string `yaml:"mode,omitempty"` // NetworkModeHost, or empty for doku-network
 	InternalPort int
 	HostPort     int               // Deprecated: use PortMappings for multiple ports
 	PortMappings map[string]string // Container port -> Host port mappings (as strings for TOML compatibility)
@@ -175,6 +307,41 @@ type Project struct {
 	CreatedAt     time.Time
 	Dependencies  []string
 	Environment   map[string]string
+
+	// Build settings used by every build of the project, e.g. when it is
+	// rebuilt by 'doku project run --build'
+	BuildArgs map[string]string `yaml:"build_args,omitempty"` // Values for ARG directives
+	Target    string            `yaml:"target,omitempty"`     // Stage of a multi-stage Dockerfile
+
+	// Compose-based projects (imported from a docker-compose.yml)
+	ComposeFile string          `yaml:"compose_file"` // Compose file path, relative to Path
+	Containers  []ContainerInfo `yaml:"containers"`   // One container per compose service
+
+	// Dev mode bind-mounts source directories instead of running only the
+	// built image (nil = off)
+	Dev *DevConfig `yaml:"dev,omitempty"`
+
+	// Replicas is the number of containers set with 'doku scale', the
+	// original included (0 = 1)
+	Replicas int `yaml:"replicas,omitempty"`
+
+	// Links are named pages of the project, e.g. "docs" → its API docs,
+	// shown by 'doku info' and opened with 'doku docs'
+	Links map[string]string `yaml:"links,omitempty"`
+}
+
+// DevConfig holds a project's dev mode: source directories mounted into its
+// container and the command that replaces the image's, e.g. "npm run dev"
+type DevConfig struct {
+	Mounts  []DevMount
+	Command string
+}
+
+// DevMount is a host directory bind-mounted into a project container
+type DevMount struct {
+	Source   string // Absolute host path
+	Target   string // Path in the container
+	ReadOnly bool
 }
 
 // Config represents the main Doku configuration
@@ -184,17 +351,98 @@ type Config struct {
 	Traefik      TraefikGlobalConfig
 	Certificates CertificatesConfig
 	Monitoring   MonitoringConfig
+	Limits       LimitsConfig
 	Instances    map[string]*Instance
 	Projects     map[string]*Project
+	Groups       map[string][]string // Named sets of instances, e.g. "backend"
+
+	// QuietHours stops services outside work hours (nil = off)
+	QuietHours *QuietHoursConfig `yaml:"quiet_hours,omitempty"`
+
+	// Webhooks are notified of service events, e.g. a crashed service
+	Webhooks []WebhookConfig `yaml:"webhooks,omitempty"`
+
+	// UpdateCheck caches the last check for new releases (nil = never checked)
+	UpdateCheck *UpdateCheckState `yaml:"update_check,omitempty"`
+
+	// Registries are the logins saved with 'doku registry login'
+	Registries []RegistryLogin `yaml:"registries,omitempty"`
+}
+
+// RegistryLogin holds the credentials images are pulled from a private
+// registry with
+type RegistryLogin struct {
+	Server   string // Registry host, e.g. "ghcr.io" or "docker.io"
+	Username string
+	Password string // Password or access token
+}
+
+// UpdateCheckState is the result of the last check for new releases of
+// Doku and of the catalog
+type UpdateCheckState struct {
+	CheckedAt     time.Time
+	LatestVersion string // Latest Doku release, e.g. "1.4.0"
+	LatestCatalog string // Latest catalog version
+}
+
+// WebhookConfig is a URL that service events are POSTed to
+type WebhookConfig struct {
+	Name   string
+	URL    string
+	Format string   // "json" (the event) or "slack" (a message for an incoming webhook)
+	Events []string // Event types to send, e.g. "fail" (empty = all)
+}
+
+// LimitsConfig holds the resource limits of instances without limits of
+// their own, from the install flags, the catalog or 'doku limits set'
+type LimitsConfig struct {
+	Memory   string // Default memory limit, e.g. "2g" (empty = unlimited)
+	CPU      string // Default CPU limit in cores, e.g. "1.5" (empty = unlimited)
+	Disabled bool   // Containers run without any limits, their own included
+}
+
+// QuietHoursConfig holds a daily window in which services are stopped, e.g.
+// to save battery at night, and started again when it ends
+type QuietHoursConfig struct {
+	Window   string    // Daily quiet window, e.g. "19:00-09:00"
+	Targets  []string  // Services and @groups to stop (empty = all services)
+	Stopped  []string  // Services stopped in the current quiet period, started again when it ends
+	LastStop time.Time // When services were last stopped for a quiet period
 }
 
 // PreferencesConfig holds user preferences
 type PreferencesConfig struct {
 	Protocol       string
 	Domain         string
+	Domains        []string // Other base domains services are also reachable under, e.g. "myproject.test"
+	Proxy          string   // Reverse proxy in front of the services: "traefik" (default) or "caddy"
+	Engine         string   // Container engine: "docker" or "podman" (empty = detected)
 	CatalogVersion string
+	CatalogPin     string // Tag the catalog is pinned to, e.g. "v1.4.0"; 'doku catalog update' keeps it
 	LastUpdate     time.Time
 	DNSSetup       string
+	DNSPort        int               // Host port of the dnsmasq container with DNSSetup "dnsmasq" (0 = 53)
+	Context        string            // Names this setup's section in a shared hosts file
+	BindIP         string            // Address hosts entries point at and ports are published on, e.g. "127.0.0.2" (empty = 127.0.0.1 and every address)
+	Labels         map[string]string // Extra Docker labels added to every service
+
+	// Mirror is a pull-through mirror images of every registry are pulled
+	// from, e.g. "artifactory.acme.com/docker-remote"
+	Mirror string
+
+	// Mirrors override Mirror for a registry, keyed by its host, e.g.
+	// "ghcr.io"; "direct" pulls from the registry itself
+	Mirrors map[string]string
+
+	// Logs is the rotation of the logs of every container Doku creates,
+	// unless its instance overrides it
+	Logs LogRotation
+
+	SkipUpdateCheck bool // Don't check for new releases of Doku and the catalog
+
+	// EncryptSecrets encrypts env files and the sensitive fields of this
+	// configuration at rest, with a key from the OS keychain
+	EncryptSecrets bool
 }
 
 // NetworkGlobalConfig holds global network configuration
@@ -212,13 +460,32 @@ type TraefikGlobalConfig struct {
 	HTTPPort         int
 	HTTPSPort        int
 	DashboardURL     string
+	Version          string // Traefik release pinned with 'doku traefik upgrade', e.g. "2.11" ("" = Doku's default)
+
+	// DashboardAuth protects the dashboard with basic auth, set with
+	// 'doku traefik auth' (nil = open)
+	DashboardAuth *DashboardAuth `yaml:"dashboard_auth,omitempty"`
+}
+
+// DashboardAuth is the user allowed into the Traefik dashboard
+type DashboardAuth struct {
+	User     string
+	Password string // Encrypted at rest with preferences.encryptsecrets
 }
 
 // CertificatesConfig holds SSL certificate configuration
 type CertificatesConfig struct {
+	// Source is where the certificates come from: "mkcert" (default), "ca"
+	// to issue them from CACert and CAKey, e.g. a company's CA, or
+	// "provided" for certificates issued elsewhere and imported
+	Source   string
 	CACert   string
 	CAKey    string
 	CertsDir string
+
+	// RenewDays is how many days before they expire the certificates Doku
+	// issues are renewed: 0 means 30, and a negative number never
+	RenewDays int
 }
 
 // MonitoringConfig holds monitoring configuration
@@ -326,3 +593,43 @@ func (i *Instance) ContainerCount() int {
 	}
 	return len(i.Containers)
 }
+
+// WantsRunning reports whether the instance should be running. Instances
+// recorded before DesiredState existed fall back to their last status.
+func (i *Instance) WantsRunning() bool {
+	if i.DesiredState != "" {
+		return i.DesiredState == StatusRunning
+	}
+	return i.Status == StatusRunning
+}
+
+// UsesHostNetwork reports whether the instance shares the host's network
+// stack instead of joining doku-network
+func (i *Instance) UsesHostNetwork() bool {
+	return i.Network.Mode == NetworkModeHost
+}
+
+// ReplicaCount returns the number of containers running the instance's
+// service, the original included
+func (i *Instance) ReplicaCount() int {
+	if i.Replicas < 1 {
+		return 1
+	}
+	return i.Replicas
+}
+
+// Project helper methods
+
+// IsCompose returns true if the project was imported from a compose file
+func (p *Project) IsCompose() bool {
+	return p.ComposeFile != ""
+}
+
+// ReplicaCount returns the number of containers running the project, the
+// original included
+func (p *Project) ReplicaCount() int {
+	if p.Replicas < 1 {
+		return 1
+	}
+	return p.Replicas
+}